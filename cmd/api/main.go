@@ -13,30 +13,62 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/json"
+	"flag"
 	"log"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/array/banking-api/db/migrations"
 	"github.com/array/banking-api/internal/config"
 	"github.com/array/banking-api/internal/database"
+	"github.com/array/banking-api/internal/fieldcrypt"
 	"github.com/array/banking-api/internal/handlers"
+	"github.com/array/banking-api/internal/i18n"
+	"github.com/array/banking-api/internal/idempotency"
 	"github.com/array/banking-api/internal/integrations/northwind"
 	"github.com/array/banking-api/internal/middleware"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/ratelimit"
 	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/seed"
+	"github.com/array/banking-api/internal/selfcheck"
 	"github.com/array/banking-api/internal/services"
 	"github.com/array/banking-api/internal/validation"
 	"github.com/array/banking-api/internal/worker"
 	"github.com/labstack/echo/v4"
 	echomiddleware "github.com/labstack/echo/v4/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"gorm.io/gorm"
 )
 
 var cfg *config.Config
 
+// idempotencyKeyTTL is how long a completed Idempotency-Key response is replayed
+const idempotencyKeyTTL = 24 * time.Hour
+
+// idempotencyClaimGrace is how long an in-flight Idempotency-Key claim outlives the route
+// timeout, covering a handler that finishes after its deadline
+const idempotencyClaimGrace = time.Minute
+
+// regulatorPreflightWait bounds how long the worker waits for the regulator host to become reachable
+const regulatorPreflightWait = 30 * time.Second
+
+// startupRecoveryTimeout bounds the recovery pass that runs before the workers start
+const startupRecoveryTimeout = 30 * time.Second
+
 func main() {
+	checkOnly := flag.Bool("check", false, "validate config and external dependencies, print a JSON report and exit 0/1")
+	flag.Parse()
+
 	// Load env file based on APP_ENV: .env.example for dev, .env.production.example for production
 	if os.Getenv("APP_ENV") == "production" {
 		_ = godotenv.Load(".env.production.example")
@@ -45,21 +77,51 @@ func main() {
 	}
 	cfg = config.Load()
 
+	if *checkOnly {
+		os.Exit(runSelfCheck())
+	}
+
+	// Field-level encryption for account numbers; must be installed before any model is persisted
+	keyring, err := fieldcrypt.NewKeyringFromConfig(&cfg.Encryption)
+	if err != nil {
+		log.Fatal("Failed to load field encryption keys:", err)
+	}
+	fieldcrypt.SetDefault(keyring)
+
 	// Initialize database
 	db, err := database.Initialize(cfg)
 	if err != nil {
 		log.Fatal("Failed to initialize database:", err)
 	}
 
-	// Initialize repositories
-	userRepo := repositories.NewUserRepository(db)
-	refreshTokenRepo := repositories.NewRefreshTokenRepository(db)
-	auditLogRepo := repositories.NewAuditLogRepository(db)
-	blacklistedTokenRepo := repositories.NewBlacklistedTokenRepository(db)
-	accountRepo := repositories.NewAccountRepository(db)
-	transactionRepo := repositories.NewTransactionRepository(db)
-	transferRepo := repositories.NewTransferRepository(db)
-	processingQueueRepo := repositories.NewProcessingQueueRepository(db)
+	// Pending migrations fail readiness; outside production they can be applied here instead
+	if cfg.Database.AutoApplyMigrations && cfg.IsProduction() {
+		slog.Warn("MIGRATIONS_AUTO_APPLY is ignored in production")
+	}
+	autoApplyMigrations := cfg.Database.AutoApplyMigrations && !cfg.IsProduction()
+	if err := database.CheckMigrations(context.Background(), db, migrations.FS, autoApplyMigrations, slog.Default()); err != nil {
+		slog.Error("Database migration check failed; readiness fails until migrations are applied", "error", err)
+	}
+
+	// `api seed` loads development fixtures and exits instead of starting the server
+	if flag.Arg(0) == "seed" {
+		os.Exit(runSeed(db))
+	}
+
+	// Initialize repositories; with DB_REPOSITORY_METRICS each is wrapped to record call durations
+	// and errors
+	var repoMetrics *repositories.RepositoryMetrics
+	if cfg.Database.RepositoryMetrics {
+		repoMetrics = repositories.NewRepositoryMetrics(prometheus.DefaultRegisterer)
+	}
+	userRepo := repositories.InstrumentUserRepository(repositories.NewUserRepository(db), repoMetrics)
+	refreshTokenRepo := repositories.InstrumentRefreshTokenRepository(repositories.NewRefreshTokenRepository(db), repoMetrics)
+	auditLogRepo := repositories.InstrumentAuditLogRepository(repositories.NewAuditLogRepository(db), repoMetrics)
+	blacklistedTokenRepo := repositories.InstrumentBlacklistedTokenRepository(repositories.NewBlacklistedTokenRepository(db), repoMetrics)
+	accountRepo := repositories.InstrumentAccountRepository(repositories.NewAccountRepository(db), repoMetrics)
+	transactionRepo := repositories.InstrumentTransactionRepository(repositories.NewTransactionRepository(db), repoMetrics)
+	transferRepo := repositories.InstrumentTransferRepository(repositories.NewTransferRepository(db), repoMetrics)
+	processingQueueRepo := repositories.InstrumentProcessingQueueRepository(repositories.NewProcessingQueueRepository(db), repoMetrics)
 
 	// Initialize services
 	auditService := services.NewAuditService(auditLogRepo)
@@ -110,24 +172,127 @@ func main() {
 	accountAssociationService := services.NewAccountAssociationService(userRepo, accountRepo, auditService, slog.Default())
 	customerLogger := services.NewCustomerLogger(slog.Default())
 
+	// --- NorthWind integration setup ---
+	nwAPIKeyRequests := promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "northwind_api_key_requests_total",
+		Help: "NorthWind requests accepted, by the configured API key that authenticated them",
+	}, []string{"key"})
+	nwSchemaDrift := promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "northwind_schema_drift_total",
+		Help: "NorthWind responses carrying a field our models lack, by response and field",
+	}, []string{"resource", "field"})
+	nwRequestDuration := promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "northwind_request_duration_seconds",
+		Help:    "Duration of each NorthWind request attempt, by method, route template and status (0 when no response arrived)",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+	nwRequestRetries := promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "northwind_request_retries_total",
+		Help: "NorthWind request attempts that retried an earlier attempt, by method",
+	}, []string{"method"})
+	nwClient, err := northwind.NewClientValidated(cfg.NorthWind.BaseURL, cfg.NorthWind.APIKey,
+		northwind.WithTimeout(cfg.NorthWind.Timeout),
+		northwind.WithRetry(cfg.NorthWind.MaxRetries, cfg.NorthWind.RetryInitialBackoffMs),
+		northwind.WithMaxRetryDuration(time.Duration(cfg.NorthWind.RetryMaxDurationMs)*time.Millisecond),
+		northwind.WithFallbackAPIKey(cfg.NorthWind.APIKeySecondary),
+		northwind.WithAPIKeyHook(func(key northwind.APIKeyID) {
+			nwAPIKeyRequests.WithLabelValues(string(key)).Inc()
+		}),
+		northwind.WithStrictDecoding(cfg.NorthWind.StrictDecoding),
+		northwind.WithSchemaDriftHook(func(drift northwind.SchemaDrift) {
+			nwSchemaDrift.WithLabelValues(drift.Resource, drift.Field).Inc()
+		}),
+		northwind.WithMetricsHook(func(method, route string, status int, attempt int, duration time.Duration, err error) {
+			nwRequestDuration.WithLabelValues(method, route, strconv.Itoa(status)).Observe(duration.Seconds())
+			if attempt > 1 {
+				nwRequestRetries.WithLabelValues(method).Inc()
+			}
+		}),
+		northwind.WithLogger(slog.Default()),
+		northwind.WithTesting(cfg.IsTesting()))
+	if err != nil {
+		log.Fatal("Invalid NorthWind configuration:", err)
+	}
+
+	// NorthWind repositories
+	nwExternalAccountRepo := repositories.InstrumentNorthwindExternalAccountRepository(repositories.NewNorthwindExternalAccountRepository(db), repoMetrics)
+	nwTransferRepo := repositories.InstrumentNorthwindTransferRepository(repositories.NewNorthwindTransferRepository(db), repoMetrics)
+	regulatorNotifRepo := repositories.InstrumentRegulatorNotificationRepository(repositories.NewRegulatorNotificationRepository(db), repoMetrics)
+	regulatorAttemptRepo := repositories.InstrumentRegulatorNotificationAttemptRepository(repositories.NewRegulatorNotificationAttemptRepository(db), repoMetrics)
+
+	// Release what a pod that shut down uncleanly left claimed before anything starts processing;
+	// leases expire on their own, so a failed pass only delays that work
+	recoveryCtx, cancelRecovery := context.WithTimeout(context.Background(), startupRecoveryTimeout)
+	_, _ = services.NewStartupRecovery(regulatorNotifRepo, processingQueueRepo, nwTransferRepo,
+		prometheus.DefaultRegisterer, slog.Default()).Run(recoveryCtx)
+	cancelRecovery()
+
 	processingCtx, cancelProcessing := context.WithCancel(context.Background())
 	defer cancelProcessing()
 
 	go processingService.StartProcessing(processingCtx)
 
-	// --- NorthWind integration setup ---
-	nwClient := northwind.NewClient(cfg.NorthWind.BaseURL, cfg.NorthWind.APIKey,
-		northwind.WithRetry(cfg.NorthWind.MaxRetries, cfg.NorthWind.RetryInitialBackoffMs))
-
-	// NorthWind repositories
-	nwExternalAccountRepo := repositories.NewNorthwindExternalAccountRepository(db)
-	nwTransferRepo := repositories.NewNorthwindTransferRepository(db)
-	regulatorNotifRepo := repositories.NewRegulatorNotificationRepository(db)
-	regulatorAttemptRepo := repositories.NewRegulatorNotificationAttemptRepository(db)
-
 	// NorthWind services
 	nwAccountService := services.NewNorthwindAccountService(nwClient, nwExternalAccountRepo, slog.Default())
-	nwTransferService := services.NewNorthwindTransferService(nwClient, nwTransferRepo, slog.Default())
+	nwAccountService.SetValidationCacheTTL(cfg.NorthWind.AccountValidationCacheTTL)
+	nwAccountService.SetNameMatchThreshold(cfg.NorthWind.NameMatchThreshold)
+	nwAccountService.SetAccountImportLimits(cfg.NorthWind.AccountImportConcurrency, cfg.NorthWind.AccountImportRate)
+	nwTransferStatsService := services.NewNorthwindTransferStatsService(nwTransferRepo, nil, slog.Default())
+	nwTransferService := services.NewNorthwindTransferService(nwClient, nwTransferRepo, nwExternalAccountRepo, nwTransferStatsService, slog.Default())
+	nwTransferService.SetDuplicateWindow(time.Duration(cfg.NorthWind.DuplicateWindowSeconds) * time.Second)
+	nwTransferService.SetAuditService(auditService)
+	nwTransferService.SetCursorSigning([]byte(cfg.NorthWind.CursorSigningKey), cfg.NorthWind.CursorTTL)
+	nwTransferService.SetCancellationWindows(cfg.NorthWind.CancellationWindows)
+	if cfg.NorthWind.MaxInFlightInitiations > 0 {
+		nwTransferService.SetInitiationGate(services.NewInitiationGate(
+			cfg.NorthWind.MaxInFlightInitiations, cfg.NorthWind.InitiationQueueSize, prometheus.DefaultRegisterer))
+	}
+	sameDayPolicy, err := services.NewSameDayPolicy(cfg.NorthWind.SameDayCutoff, cfg.NorthWind.BankTimezone, cfg.NorthWind.SameDayFee)
+	if err != nil {
+		log.Fatal("Invalid same-day transfer settings:", err)
+	}
+	nwTransferService.SetSameDayPolicy(sameDayPolicy)
+	// Shared by the poller, transfer creation and the admin override endpoints
+	nwPollSchedule := services.NewNorthwindPollSchedule(cfg.NorthWind.PollingProfiles)
+	nwTransferService.SetPollSchedule(nwPollSchedule)
+	// Shared by transfer creation, the queue drain job and the admin maintenance endpoints
+	var nwMaintenanceWindow *services.MaintenanceWindow
+	if !cfg.NorthWind.MaintenanceStart.IsZero() {
+		nwMaintenanceWindow = &services.MaintenanceWindow{Start: cfg.NorthWind.MaintenanceStart, End: cfg.NorthWind.MaintenanceEnd}
+	}
+	nwMaintenance := services.NewNorthwindMaintenance(nwMaintenanceWindow)
+	nwTransferService.SetMaintenance(nwMaintenance)
+
+	featureFlagRollouts, err := services.ParseFeatureFlagConfig(cfg.FeatureFlags.Rollouts)
+	if err != nil {
+		log.Fatal("Invalid FEATURE_FLAGS:", err)
+	}
+	featureFlagOverrideRepo := repositories.InstrumentFeatureFlagOverrideRepository(repositories.NewFeatureFlagOverrideRepository(db), repoMetrics)
+	featureFlagService := services.NewFeatureFlagService(featureFlagOverrideRepo, featureFlagRollouts, slog.Default())
+	nwTransferService.SetFeatureFlags(featureFlagService)
+	riskRuleModes, err := services.ParseRiskRuleModes(cfg.Risk.RuleModes)
+	if err != nil {
+		log.Fatal("Invalid RISK_RULE_MODES:", err)
+	}
+	riskEvaluationRepo := repositories.InstrumentRiskEvaluationRepository(repositories.NewRiskEvaluationRepository(db), repoMetrics)
+	riskRuleOverrideRepo := repositories.InstrumentRiskRuleOverrideRepository(repositories.NewRiskRuleOverrideRepository(db), repoMetrics)
+	riskService := services.NewRiskService(nwTransferRepo, riskEvaluationRepo, riskRuleOverrideRepo, cfg.Risk, riskRuleModes, slog.Default())
+	nwTransferService.SetRiskService(riskService)
+	readOnlyWorkers, err := services.ParseReadOnlyWorkers(cfg.ReadOnly.Workers)
+	if err != nil {
+		log.Fatal("Invalid READ_ONLY_WORKERS:", err)
+	}
+	readOnlyService := services.NewReadOnlyService(
+		repositories.InstrumentReadOnlyModeRepository(repositories.NewReadOnlyModeRepository(db), repoMetrics),
+		cfg.ReadOnly.Enabled, readOnlyWorkers, slog.Default())
+	nwTransferService.SetReadOnly(readOnlyService)
+	nwErrorCodes, err := services.ParseNorthwindErrorCodes(cfg.NorthWind.ErrorCodeOverrides)
+	if err != nil {
+		log.Fatal("Invalid NORTHWIND_ERROR_CODES:", err)
+	}
+	nwErrorCatalog := services.NewNorthwindErrorCatalog(nwErrorCodes, prometheus.DefaultRegisterer, slog.Default())
+	nwTransferService.SetErrorCatalog(nwErrorCatalog)
+	nwReceiptService := services.NewReceiptService(nwTransferRepo, receiptSigningKey())
 
 	regulatorService := services.NewRegulatorService(
 		cfg.Regulator.WebhookURL,
@@ -138,6 +303,23 @@ func main() {
 		slog.Default(),
 		nil, // use default HTTP client
 	)
+	regulatorTransformer, err := services.PayloadTransformerForFormat(cfg.Regulator.PayloadFormat)
+	if err != nil {
+		log.Fatal("Invalid REGULATOR_PAYLOAD_FORMAT:", err)
+	}
+	regulatorService.SetPayloadTransformer(regulatorTransformer)
+	regulatorService.SetSigningSecret(cfg.Regulator.SigningSecret)
+	regulatorService.SetReadOnly(readOnlyService)
+
+	nwTransferService.SetRegulatorService(regulatorService)
+
+	// Poller, webhook receiver and on-demand syncs apply status changes through one state manager
+	nwTransferStates := services.NewTransferStateManager(nwTransferRepo, regulatorService, slog.Default())
+	nwTransferStates.SetPollSchedule(nwPollSchedule)
+	nwTransferStates.SetErrorCatalog(nwErrorCatalog)
+	notificationPreferenceService := services.NewNotificationPreferenceService(repositories.InstrumentNotificationPreferenceRepository(repositories.NewNotificationPreferenceRepository(db), repoMetrics))
+	userNotificationRepo := repositories.InstrumentUserNotificationRepository(repositories.NewUserNotificationRepository(db), repoMetrics)
+	nwTransferStates.SetNotifier(services.NewTransferNotifier(notificationPreferenceService, userNotificationRepo, userRepo, slog.Default()))
 
 	nwPollingService := services.NewNorthwindPollingService(
 		nwClient,
@@ -146,38 +328,189 @@ func main() {
 		time.Duration(cfg.NorthWind.PollIntervalSeconds)*time.Second,
 		slog.Default(),
 	)
+	nwPollingService.SetMetrics(services.NewNorthwindPollingMetrics(prometheus.DefaultRegisterer))
+	nwPollingService.SetPollSchedule(nwPollSchedule)
+	nwPollingService.SetPollPriority(cfg.NorthWind.PollPriorityShare, cfg.NorthWind.PollPriorityWindow)
+	nwPollingService.SetTransferStateManager(nwTransferStates)
+	nwTransferService.SetTransferStateManager(nwTransferStates)
+	pollAnomalyRepo := repositories.InstrumentPollAnomalyRepository(repositories.NewPollAnomalyRepository(db), repoMetrics)
+	nwPollAnomalies := services.NewPollAnomalyService(pollAnomalyRepo, nwTransferRepo, nwTransferStates, slog.Default())
+	nwPollAnomalies.SetAlertThreshold(cfg.NorthWind.PollAnomalyAlertThreshold)
+	nwPollingService.SetPollAnomalies(nwPollAnomalies)
+	nwDisputes := services.NewTransferDisputeService(repositories.InstrumentTransferDisputeRepository(repositories.NewTransferDisputeRepository(db), repoMetrics), nwTransferService, slog.Default())
+	nwDisputes.SetWindow(cfg.NorthWind.DisputeWindow)
 
 	// Unified worker: NorthWind transfer polling + regulator retries in one loop
 	workerInterval := 5 * time.Second
 	nwWorker := worker.NewScheduler(nwPollingService, regulatorService, workerInterval, slog.Default())
+	nwWorker.SetReadOnly(readOnlyService)
+	nwWorker.Register(worker.Job{
+		Name:  "expired_token_cleanup",
+		Every: cfg.Database.TokenCleanupInterval,
+		Run: func(ctx context.Context) error {
+			result, err := database.CleanupExpiredTokens(ctx, db, database.TokenCleanupOptions{
+				BatchSize:   cfg.Database.TokenCleanupBatchSize,
+				MaxDuration: cfg.Database.TokenCleanupMaxDuration,
+			})
+			if err != nil {
+				return err
+			}
+			slog.Info("Expired tokens cleaned up", "refresh_tokens", result.RefreshTokens, "blacklisted_tokens", result.BlacklistedTokens, "truncated", result.Truncated)
+			return nil
+		},
+	})
+	// Purges records past their retention period; with RETENTION_DRY_RUN it only reports counts
+	retentionService := services.NewRetentionService(nwTransferRepo, transferRepo, transactionRepo, auditLogRepo, cfg.Retention, slog.Default())
+	nwWorker.Register(worker.Job{
+		Name:  "data_retention",
+		Every: cfg.Retention.Interval,
+		Run:   retentionService.Run,
+	})
+	// Sends transfers queued during a NorthWind maintenance window once it closes
+	nwWorker.Register(worker.Job{
+		Name:           "northwind_queued_initiations",
+		Run:            nwTransferService.InitiateQueuedTransfers,
+		ReadOnlyWorker: services.ReadOnlyWorkerInitiationRetries,
+	})
+	// Sends transfers scheduled for a later date once their date arrives
+	nwWorker.Register(worker.Job{
+		Name:           "northwind_scheduled_transfers",
+		Every:          time.Minute,
+		Run:            nwTransferService.SubmitDueScheduledTransfers,
+		ReadOnlyWorker: services.ReadOnlyWorkerInitiationRetries,
+	})
+	// Balance alert rules are checked daily, and rules flagged frequent on their own shorter cycle
+	balanceAlertRuleRepo := repositories.InstrumentBalanceAlertRuleRepository(repositories.NewBalanceAlertRuleRepository(db), repoMetrics)
+	balanceAlertService := services.NewBalanceAlertService(nwClient, balanceAlertRuleRepo,
+		nwExternalAccountRepo, userNotificationRepo, userRepo, slog.Default())
+	balanceAlertService.SetCooldown(cfg.NorthWind.BalanceAlertCooldown)
+	for _, schedule := range []struct {
+		name         string
+		every        time.Duration
+		frequentOnly bool
+	}{
+		{"northwind_balance_alerts", cfg.NorthWind.BalanceAlertInterval, false},
+		{"northwind_balance_alerts_frequent", cfg.NorthWind.BalanceAlertFrequentInterval, true},
+	} {
+		nwWorker.Register(worker.Job{
+			Name:  schedule.name,
+			Every: schedule.every,
+			Run: func(ctx context.Context) error {
+				run, err := balanceAlertService.EvaluateRules(ctx, schedule.frequentOnly)
+				if err != nil {
+					return err
+				}
+				slog.Info("Balance alert rules evaluated", "frequent_only", schedule.frequentOnly, "evaluated", run.Evaluated, "alerted", run.Alerted, "failed", run.Failed)
+				return nil
+			},
+		})
+	}
+	// Processed webhook event IDs only need to outlive NorthWind's redelivery window
+	nwWebhookEvents := repositories.InstrumentProcessedWebhookEventRepository(repositories.NewProcessedWebhookEventRepository(db), repoMetrics)
+	nwWorker.Register(worker.Job{
+		Name:  "northwind_webhook_event_pruning",
+		Every: time.Hour,
+		Run: func(ctx context.Context) error {
+			pruned, err := nwWebhookEvents.PruneBefore(ctx, time.Now().Add(-cfg.NorthWind.WebhookEventRetention))
+			if err != nil {
+				return err
+			}
+			if pruned > 0 {
+				slog.Info("Processed webhook events pruned", "count", pruned)
+			}
+			return nil
+		},
+	})
+	canaryRunRepo := repositories.InstrumentCanaryRunRepository(repositories.NewCanaryRunRepository(db), repoMetrics)
+	nwCanary := services.NewCanaryService(nwClient, nwTransferService, nwTransferRepo, nwTransferStates,
+		regulatorNotifRepo, canaryRunRepo, cfg.Canary, cfg.IsProduction(), slog.Default())
+	if nwCanary.Enabled() {
+		nwWorker.Register(worker.Job{
+			Name:  "northwind_canary",
+			Every: cfg.Canary.Interval,
+			Run:   nwCanary.Trigger,
+		})
+	} else if cfg.Canary.Enabled {
+		slog.Warn("Canary not started in production; set CANARY_ALLOW_PRODUCTION to run it")
+	}
+	regulatorService.StartDeliveryWorkers(services.DefaultDeliveryWorkers, services.DefaultDeliveryQueueSize)
 	workerCtx, cancelWorker := context.WithCancel(context.Background())
 	defer cancelWorker()
-	go nwWorker.Start(workerCtx)
+	go func() {
+		// Give regulator DNS a chance to converge before the first deliveries; the outcome is
+		// logged by Preflight and the worker starts either way.
+		_ = regulatorService.Preflight(workerCtx, regulatorPreflightWait)
+		nwWorker.Start(workerCtx)
+	}()
+
+	// Backfills run off the startup path; a shutdown mid-run resumes from the last batch next time
+	if cfg.Database.BackfillOnStartup {
+		go func() {
+			if err := database.RunBackfills(workerCtx, db, cfg.Database.BackfillBatchSize, slog.Default()); err != nil {
+				slog.Error("Startup backfill failed", "error", err)
+			}
+		}()
+	}
+
+	rateLimitStore, idempotencyStore := newStateStores()
 
-	e := configureEcho()
+	if !i18n.Default().Supports(cfg.Server.DefaultLocale) {
+		log.Fatalf("Unsupported SERVER_DEFAULT_LOCALE %q; supported locales are %v", cfg.Server.DefaultLocale, i18n.Default().Locales())
+	}
+
+	validationRules := validation.NewDynamicRules(cfg, northwindDomainNames(nwClient))
+	e := configureEcho(rateLimitStore, readOnlyService, cfg.Server.RequestTimeout, cfg.Server.RedactErrorDetails,
+		validation.NewValidator(validation.WithDynamicRules(validationRules)))
 
 	authHandler := handlers.NewAuthHandler(authService)
 	adminHandler := handlers.NewAdminHandler(userRepo, auditLogRepo)
 	accountHandler := handlers.NewAccountHandler(accountService, auditLogger, prometheusMetrics)
 	transactionHandler := handlers.NewTransactionHandler(transactionRepo, accountRepo)
+	transactionHandler.SetActivityService(services.NewAccountActivityService(transactionRepo, nwTransferRepo))
 	accountSummaryHandler := handlers.NewAccountSummaryHandler(accountSummaryService, accountMetricsService, statementService)
 	devHandler := handlers.NewDevHandler(transactionRepo, accountRepo)
 	customerHandler := handlers.NewCustomerHandler(customerSearchService, customerProfileService, accountAssociationService, passwordService, auditService, customerLogger, prometheusMetrics)
 	healthCheckHandler := handlers.NewHealthCheckHandler(db)
+	healthCheckHandler.SetCanary(nwCanary)
+	healthCheckHandler.SetMigrations(migrations.FS, slog.Default())
 	docsHandler := handlers.NewDocsHandler()
 
 	// NorthWind handler
-	northwindHandler := handlers.NewNorthwindHandler(nwClient, nwAccountService, nwTransferService)
+	northwindHandler := handlers.NewNorthwindHandler(nwClient, nwAccountService, nwTransferService, nwTransferStatsService, nwReceiptService, cfg)
+	northwindHandler.SetShutdownSignal(workerCtx.Done())
+	northwindHandler.SetLegacyTransferResponse(cfg.NorthWind.LegacyTransferResponse)
+	northwindHandler.SetPollSchedule(nwPollSchedule)
+	northwindHandler.SetMaintenance(nwMaintenance)
+	northwindHandler.SetPollAnomalies(nwPollAnomalies)
+	northwindHandler.SetDisputes(nwDisputes)
+	nwDashboard := services.NewNorthwindDashboardService(nwClient, nwTransferRepo, regulatorNotifRepo, slog.Default())
+	nwDashboard.SetSchedulerStatus(nwWorker.Status)
+	northwindHandler.SetDashboard(nwDashboard)
+	regulatorHandler := handlers.NewRegulatorHandler(regulatorNotifRepo, regulatorAttemptRepo, nwTransferRepo)
+	featureFlagHandler := handlers.NewFeatureFlagHandler(featureFlagService)
+	riskHandler := handlers.NewRiskHandler(riskService)
+	retentionHandler := handlers.NewRetentionHandler(retentionService)
+	readOnlyHandler := handlers.NewReadOnlyHandler(readOnlyService)
+	notificationPreferenceHandler := handlers.NewNotificationPreferenceHandler(notificationPreferenceService)
+	accessTokenService := services.NewPersonalAccessTokenService(
+		repositories.InstrumentPersonalAccessTokenRepository(repositories.NewPersonalAccessTokenRepository(db), repoMetrics), userRepo, slog.Default())
+	accessTokenHandler := handlers.NewPersonalAccessTokenHandler(accessTokenService)
+	balanceAlertHandler := handlers.NewBalanceAlertHandler(balanceAlertService)
+	nwWebhookHandler := handlers.NewNorthwindWebhookHandler(nwTransferStates, cfg.NorthWind.WebhookSecret, slog.Default())
+	nwWebhookHandler.SetEventStore(nwWebhookEvents)
 
 	api := e.Group("/api/v1")
 	tokenSvc := tokenService.(*services.TokenService)
 	addAuthEndpoints(api, tokenSvc, blacklistedTokenRepo, authHandler)
 	addAccountEndpoints(api, tokenSvc, blacklistedTokenRepo, accountHandler, accountSummaryHandler, transactionHandler, customerHandler)
 	addCustomerEndpoints(api, tokenSvc, blacklistedTokenRepo, customerHandler, accountHandler)
+	addUserEndpoints(api, tokenSvc, blacklistedTokenRepo, notificationPreferenceHandler, accessTokenHandler)
 	addDevEndpoints(api, tokenSvc, blacklistedTokenRepo, devHandler)
-	addAdminEndpoints(api, tokenSvc, blacklistedTokenRepo, adminHandler, accountHandler)
+	addAdminEndpoints(api, tokenSvc, blacklistedTokenRepo, adminHandler, accountHandler, regulatorHandler, northwindHandler, featureFlagHandler, riskHandler, retentionHandler, readOnlyHandler)
 	addHealthCheckEndpoint(api, healthCheckHandler)
-	addNorthwindEndpoints(api, tokenSvc, blacklistedTokenRepo, northwindHandler)
+	addNorthwindEndpoints(api, tokenSvc, blacklistedTokenRepo, accessTokenService, northwindHandler, idempotencyStore)
+	addBalanceAlertEndpoints(api, tokenSvc, blacklistedTokenRepo, balanceAlertHandler)
+	addNorthwindWebhookEndpoints(api, nwWebhookHandler)
 	addDocumentationEndpoints(e, docsHandler)
 
 	go func() {
@@ -190,12 +523,15 @@ func main() {
 	signal.Notify(quit, os.Interrupt)
 	<-quit
 
-	// Graceful shutdown: cancel background workers then shut down HTTP
+	// Graceful shutdown: cancel background workers (which also releases in-flight long-polls),
+	// drain queued regulator deliveries, then shut down HTTP
 	cancelWorker()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	regulatorService.Shutdown(ctx)
+
 	if err := e.Shutdown(ctx); err != nil {
 		log.Fatal("Server forced to shut down:", err)
 	}
@@ -203,17 +539,117 @@ func main() {
 	log.Println("Server shutdown complete")
 }
 
-func configureEcho() *echo.Echo {
+// runSelfCheck probes every external dependency, prints the JSON report to stdout and returns the exit code
+func runSelfCheck() int {
+	report := selfcheck.NewChecker(cfg, database.Ping, selfcheck.DefaultTimeout).Run(context.Background())
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		log.Printf("Failed to write self-check report: %v", err)
+		return 1
+	}
+	if !report.OK() {
+		return 1
+	}
+	return 0
+}
+
+// runSeed loads the development fixture set, prints the JSON summary to stdout and returns the exit code
+func runSeed(db *gorm.DB) int {
+	summary, err := seed.NewSeeder(db, slog.Default()).Run(context.Background(), cfg.Server.Environment)
+	if err != nil {
+		log.Printf("Seeding failed: %v", err)
+		return 1
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(summary); err != nil {
+		log.Printf("Failed to write seed summary: %v", err)
+		return 1
+	}
+	log.Printf("Seeded users share the password %q", seed.Password)
+	return 0
+}
+
+// receiptSigningKey returns the configured receipt HMAC key, or a random per-process key when
+// none is set so receipts still render in development
+func receiptSigningKey() []byte {
+	if cfg.NorthWind.ReceiptSigningKey != "" {
+		return []byte(cfg.NorthWind.ReceiptSigningKey)
+	}
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	return key
+}
+
+// newStateStores selects where idempotency and rate-limit state lives: a shared Redis when
+// REDIS_ADDR is set (required for multiple pods), process memory otherwise. A nil rate-limit
+// store means the in-memory limiter.
+// northwindDomainNames lists the domains NorthWind publishes, for the transfer type validator
+func northwindDomainNames(client *northwind.Client) validation.DomainsProvider {
+	return func(ctx context.Context) ([]string, error) {
+		domains, err := client.GetDomainsCached(ctx)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, len(domains))
+		for i, domain := range domains {
+			names[i] = domain.Name
+		}
+		return names, nil
+	}
+}
+
+func newStateStores() (ratelimit.Store, idempotency.Store) {
+	if !cfg.Redis.Enabled() {
+		log.Println("REDIS_ADDR not set, keeping idempotency and rate-limit state in memory")
+		return nil, idempotency.NewMemoryStore()
+	}
+
+	client := database.NewRedisClient(&cfg.Redis)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Redis.DialTimeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		// Keep going: rate limiting fails open and idempotency fails closed until Redis is reachable
+		log.Printf("WARNING: Redis at %s is unreachable: %v", cfg.Redis.Addr, err)
+	} else {
+		log.Printf("Using Redis at %s for idempotency and rate-limit state", cfg.Redis.Addr)
+	}
+
+	rps := cfg.Security.RateLimitPerSecond
+	return ratelimit.NewRedisStore(client, rps, 2*rps, slog.Default()),
+		idempotency.NewRedisStore(client, slog.Default())
+}
+
+// readOnlyTogglePath is the admin endpoint that turns read-only mode off, so it stays writable
+const readOnlyTogglePath = "/api/v1/admin/read-only"
+
+func configureEcho(rateLimitStore ratelimit.Store, readOnly middleware.ReadOnlyChecker, requestTimeout time.Duration, redactErrorDetails bool, validator *validation.Validator) *echo.Echo {
 	e := echo.New()
 	e.HideBanner = true
 	// Use our custom validator with business rule validations
-	e.Validator = validation.EchoValidator()
+	e.Validator = validator.Echo()
 	e.HTTPErrorHandler = middleware.CustomHTTPErrorHandler
 
 	e.Use(middleware.RequestID())
+	// Every response, errors included, is in the locale the client asked for
+	e.Use(middleware.Locale(i18n.Default(), cfg.Server.DefaultLocale))
+	e.Use(middleware.ErrorDetails(redactErrorDetails))
 	e.Use(middleware.PanicRecovery())
+	// Long polls bound their own wait, so they are exempt from the per-request deadline
+	e.Use(middleware.RequestTimeout(requestTimeout, func(c echo.Context) bool {
+		return strings.HasSuffix(c.Path(), "/wait")
+	}))
 	e.Use(echomiddleware.Logger())
-	e.Use(middleware.RateLimiter())
+	if rateLimitStore != nil {
+		e.Use(middleware.RateLimiterWithStore(rateLimitStore))
+	} else {
+		e.Use(middleware.RateLimiter())
+	}
+	// During incident response writes are refused; login and lifting the freeze still work
+	e.Use(middleware.ReadOnlyGuard(readOnly, "/api/v1/auth/", readOnlyTogglePath))
 	e.Use(middleware.SecurityHeaders())
 	e.Use(echomiddleware.CORSWithConfig(echomiddleware.CORSConfig{
 		AllowOrigins: cfg.Server.CORSAllowOrigins,
@@ -241,7 +677,8 @@ func addAccountEndpoints(api *echo.Group, tokenService *services.TokenService, b
 	accountGroup.POST("/:accountId/transactions", accountHandler.PerformTransaction)
 	accountGroup.GET("/:accountId/transactions", transactionHandler.ListTransactions)
 	accountGroup.GET("/:accountId/transactions/:id", transactionHandler.GetTransaction)
-	accountGroup.POST("/:accountId/transfer", accountHandler.Transfer)
+	accountGroup.GET("/:accountId/activity", transactionHandler.ListAccountActivity)
+	accountGroup.POST("/:accountId/transfer", accountHandler.Transfer, middleware.RequireClaimScope(models.ClaimScopeTransfersCreate))
 
 	// Summary endpoints
 	accountGroup.GET("/summary", accountSummaryHandler.GetAccountSummary)
@@ -249,7 +686,7 @@ func addAccountEndpoints(api *echo.Group, tokenService *services.TokenService, b
 	accountGroup.GET("/:accountId/statements", accountSummaryHandler.GetStatement)
 
 	// Account ownership transfer endpoint (admin-only)
-	accountGroup.POST("/:accountId/transfer-ownership", customerHandler.TransferAccountOwnership, middleware.RequireAdmin())
+	accountGroup.POST("/:accountId/transfer-ownership", customerHandler.TransferAccountOwnership, middleware.RequireAdmin(), middleware.RequireClaimScope(models.ClaimScopeAdmin))
 }
 
 func addDevEndpoints(api *echo.Group, tokenService *services.TokenService, blacklistedTokenRepo repositories.BlacklistedTokenRepositoryInterface, devHandler *handlers.DevHandler) {
@@ -260,10 +697,62 @@ func addDevEndpoints(api *echo.Group, tokenService *services.TokenService, black
 	}
 }
 
-func addAdminEndpoints(api *echo.Group, tokenService *services.TokenService, blacklistedTokenRepo repositories.BlacklistedTokenRepositoryInterface, adminHandler *handlers.AdminHandler, accountHandler *handlers.AccountHandler) {
-	adminGroup := api.Group("/admin", middleware.RequireAuth(tokenService, blacklistedTokenRepo), middleware.RequireAdmin())
+func addAdminEndpoints(api *echo.Group, tokenService *services.TokenService, blacklistedTokenRepo repositories.BlacklistedTokenRepositoryInterface, adminHandler *handlers.AdminHandler, accountHandler *handlers.AccountHandler, regulatorHandler *handlers.RegulatorHandler, northwindHandler *handlers.NorthwindHandler, featureFlagHandler *handlers.FeatureFlagHandler, riskHandler *handlers.RiskHandler, retentionHandler *handlers.RetentionHandler, readOnlyHandler *handlers.ReadOnlyHandler) {
+	adminGroup := api.Group("/admin", middleware.RequireAuth(tokenService, blacklistedTokenRepo), middleware.RequireAdmin(), middleware.RequireClaimScope(models.ClaimScopeAdmin))
 	addAdminUserManagementEndpoints(adminGroup, adminHandler)
 	addAdminAccountManagementEndpoints(adminGroup, accountHandler)
+	addAdminRegulatorEndpoints(adminGroup, regulatorHandler)
+	addAdminNorthwindEndpoints(adminGroup, northwindHandler)
+	addAdminFeatureFlagEndpoints(adminGroup, featureFlagHandler)
+	addAdminRiskEndpoints(adminGroup, riskHandler)
+	adminGroup.GET("/retention/dry-run", retentionHandler.DryRun)
+	adminGroup.GET("/read-only", readOnlyHandler.GetMode)
+	adminGroup.PUT("/read-only", readOnlyHandler.SetMode)
+}
+
+func addAdminRiskEndpoints(adminGroup *echo.Group, riskHandler *handlers.RiskHandler) {
+	adminGroup.GET("/risk/rules", riskHandler.ListRules)
+	adminGroup.PUT("/risk/rules/:rule", riskHandler.SetMode)
+	adminGroup.DELETE("/risk/rules/:rule", riskHandler.ClearMode)
+	adminGroup.GET("/risk/evaluations", riskHandler.ListEvaluations)
+}
+
+func addAdminFeatureFlagEndpoints(adminGroup *echo.Group, featureFlagHandler *handlers.FeatureFlagHandler) {
+	adminGroup.GET("/feature-flags", featureFlagHandler.ListFlags)
+	adminGroup.PUT("/feature-flags/:flag", featureFlagHandler.SetRollout)
+	adminGroup.DELETE("/feature-flags/:flag", featureFlagHandler.ClearRollout)
+	adminGroup.PUT("/feature-flags/:flag/users/:userId", featureFlagHandler.SetUserOverride)
+	adminGroup.DELETE("/feature-flags/:flag/users/:userId", featureFlagHandler.ClearUserOverride)
+}
+
+func addAdminNorthwindEndpoints(adminGroup *echo.Group, northwindHandler *handlers.NorthwindHandler) {
+	adminGroup.POST("/northwind/users/:userId/transfers/cancel-all", northwindHandler.AdminCancelAllTransfers)
+	adminGroup.GET("/northwind/transfers/duration-stats", northwindHandler.AdminGetTransferDurationStats)
+	adminGroup.GET("/northwind/dashboard", northwindHandler.AdminGetDashboard)
+	adminGroup.GET("/northwind/polling-profiles", northwindHandler.AdminListPollingProfiles)
+	adminGroup.PUT("/northwind/polling-profiles/:type", northwindHandler.AdminSetPollingProfile)
+	adminGroup.DELETE("/northwind/polling-profiles/:type", northwindHandler.AdminClearPollingProfile)
+	adminGroup.GET("/northwind/transfers/upstream/:northwind_id", northwindHandler.AdminGetUpstreamTransfer)
+	adminGroup.GET("/northwind/transfers/:id", northwindHandler.AdminGetTransfer)
+	adminGroup.GET("/northwind/transfers/:id/compare", northwindHandler.AdminCompareTransfer)
+	adminGroup.PUT("/northwind/transfers/:id/internal-test", northwindHandler.AdminSetTransferInternalTest)
+	adminGroup.POST("/northwind/receipts/verify", northwindHandler.AdminVerifyReceipt)
+	adminGroup.GET("/northwind/maintenance", northwindHandler.AdminGetMaintenance)
+	adminGroup.PUT("/northwind/maintenance", northwindHandler.AdminSetMaintenance)
+	adminGroup.DELETE("/northwind/maintenance", northwindHandler.AdminClearMaintenance)
+	adminGroup.GET("/northwind/poll-anomalies", northwindHandler.AdminListPollAnomalies)
+	adminGroup.POST("/northwind/poll-anomalies/replay", northwindHandler.AdminReplayPollAnomalies)
+	adminGroup.GET("/northwind/disputes", northwindHandler.AdminListDisputes)
+	adminGroup.POST("/northwind/disputes/:id/review", northwindHandler.AdminReviewDispute)
+	adminGroup.POST("/northwind/disputes/:id/resolve", northwindHandler.AdminResolveDispute)
+}
+
+func addAdminRegulatorEndpoints(adminGroup *echo.Group, regulatorHandler *handlers.RegulatorHandler) {
+	adminGroup.GET("/regulator/notifications/:id/attempts", regulatorHandler.GetNotificationAttempts)
+	adminGroup.GET("/regulator/notifications/by-event/:event_id", regulatorHandler.GetNotificationByEvent)
+	adminGroup.GET("/regulator/attempts", regulatorHandler.ListAttempts)
+	adminGroup.GET("/regulator/evidence", regulatorHandler.ExportEvidence)
+	adminGroup.POST("/regulator/evidence", regulatorHandler.ExportEvidence)
 }
 
 func addAdminAccountManagementEndpoints(adminGroup *echo.Group, accountHandler *handlers.AccountHandler) {
@@ -281,7 +770,7 @@ func addAdminUserManagementEndpoints(adminGroup *echo.Group, adminHandler *handl
 
 func addCustomerEndpoints(api *echo.Group, tokenService *services.TokenService, blacklistedTokenRepo repositories.BlacklistedTokenRepositoryInterface, customerHandler *handlers.CustomerHandler, accountHandler *handlers.AccountHandler) {
 	// Admin-only customer management endpoints
-	adminCustomerGroup := api.Group("/customers", middleware.RequireAuth(tokenService, blacklistedTokenRepo), middleware.RequireAdmin())
+	adminCustomerGroup := api.Group("/customers", middleware.RequireAuth(tokenService, blacklistedTokenRepo), middleware.RequireAdmin(), middleware.RequireClaimScope(models.ClaimScopeAdmin))
 	adminCustomerGroup.GET("/search", customerHandler.SearchCustomers)
 	adminCustomerGroup.POST("", customerHandler.CreateCustomer)
 	adminCustomerGroup.GET("/:id", customerHandler.GetCustomerProfile)
@@ -302,36 +791,91 @@ func addCustomerEndpoints(api *echo.Group, tokenService *services.TokenService,
 	selfServiceGroup.PUT("/password", customerHandler.UpdateMyPassword)
 }
 
+// addUserEndpoints registers the caller's own settings. They need a JWT session: a personal
+// access token cannot manage tokens.
+func addUserEndpoints(api *echo.Group, tokenService *services.TokenService, blacklistedTokenRepo repositories.BlacklistedTokenRepositoryInterface, notificationPreferenceHandler *handlers.NotificationPreferenceHandler, accessTokenHandler *handlers.PersonalAccessTokenHandler) {
+	meGroup := api.Group("/users/me", middleware.RequireAuth(tokenService, blacklistedTokenRepo))
+	meGroup.GET("/notification-preferences", notificationPreferenceHandler.GetMyPreferences)
+	meGroup.PUT("/notification-preferences", notificationPreferenceHandler.UpdateMyPreferences)
+	meGroup.POST("/tokens", accessTokenHandler.CreateToken)
+	meGroup.GET("/tokens", accessTokenHandler.ListTokens)
+	meGroup.DELETE("/tokens/:id", accessTokenHandler.RevokeToken)
+}
+
 // addDocumentationEndpoints registers the health check endpoint
 func addHealthCheckEndpoint(api *echo.Group, healthCheckHandler *handlers.HealthCheckHandler) {
-	api.GET("/health", healthCheckHandler.HealthCheck)
+	api.GET("/health", healthCheckHandler.HealthCheck, middleware.RouteTimeout(cfg.Server.RouteTimeouts.Health))
+	api.GET("/health/deep", healthCheckHandler.DeepHealth, middleware.RouteTimeout(cfg.Server.RouteTimeouts.Health))
 }
 
-// addNorthwindEndpoints registers NorthWind integration routes
-func addNorthwindEndpoints(api *echo.Group, tokenService *services.TokenService, blacklistedTokenRepo repositories.BlacklistedTokenRepositoryInterface, handler *handlers.NorthwindHandler) {
-	nw := api.Group("/northwind", middleware.RequireAuth(tokenService, blacklistedTokenRepo))
+// addNorthwindEndpoints registers NorthWind integration routes. Besides a JWT session they accept a
+// personal access token with the transfers:read scope for reads and transfers:write for writes.
+// Creating, cancelling, reversing and registering also need the matching scope in the caller's claims.
+func addNorthwindEndpoints(api *echo.Group, tokenService *services.TokenService, blacklistedTokenRepo repositories.BlacklistedTokenRepositoryInterface, accessTokens middleware.AccessTokenAuthenticator, handler *handlers.NorthwindHandler, idempotencyStore idempotency.Store) {
+	nw := api.Group("/northwind", middleware.RequireAuthOrAccessToken(tokenService, blacklistedTokenRepo, accessTokens))
+	readScope := middleware.RequireScope(models.TokenScopeTransfersRead)
+	writeScope := middleware.RequireScope(models.TokenScopeTransfersWrite)
+	createScope := middleware.RequireClaimScope(models.ClaimScopeTransfersCreate)
+	cancelScope := middleware.RequireClaimScope(models.ClaimScopeTransfersCancel)
+	reverseScope := middleware.RequireClaimScope(models.ClaimScopeTransfersReverse)
+	registerScope := middleware.RequireClaimScope(models.ClaimScopeAccountsRegister)
+	// Mutating routes make several NorthWind calls and get more time than reads
+	timeouts := cfg.Server.RouteTimeouts
+	nwWrite := nw.Group("", middleware.RouteTimeout(timeouts.NorthwindWrite), writeScope)
+	nwRead := nw.Group("", middleware.RouteTimeout(timeouts.NorthwindRead), readScope)
 
 	// Bank info & domains
-	nw.GET("/bank", handler.GetBankInfo)
-	nw.GET("/domains", handler.GetDomains)
-	nw.GET("/health", handler.NorthwindHealth)
+	nwRead.GET("/bank", handler.GetBankInfo)
+	nwRead.GET("/domains", handler.GetDomains)
+	nw.GET("/health", handler.NorthwindHealth, middleware.RouteTimeout(timeouts.Health), readScope)
+	nwRead.GET("/metadata", handler.GetMetadata)
 
 	// External accounts
-	nw.POST("/external-accounts/validate-and-register", handler.ValidateAndRegister)
-	nw.GET("/external-accounts", handler.ListRegisteredAccounts)
-	nw.GET("/external-accounts/accessible", handler.ListAccessibleAccounts)
+	nwWrite.POST("/external-accounts/validate-and-register", handler.ValidateAndRegister, registerScope)
+	nwRead.GET("/external-accounts", handler.ListRegisteredAccounts)
+	nwRead.GET("/external-accounts/accessible", handler.ListAccessibleAccounts)
+	nw.POST("/accounts/import", handler.ImportExternalAccounts, middleware.RouteTimeout(timeouts.AccountImport), writeScope, registerScope)
 
 	// Transfers
-	nw.POST("/transfers", handler.CreateTransfer)
-	nw.GET("/transfers", handler.ListTransfers)
-	nw.GET("/transfers/:id", handler.GetTransfer)
-	nw.POST("/transfers/:id/cancel", handler.CancelTransfer)
-	nw.POST("/transfers/:id/reverse", handler.ReverseTransfer)
-
-	// Dev/admin only endpoints
+	claimTTL := timeouts.NorthwindWrite + idempotencyClaimGrace
+	nwWrite.POST("/transfers", handler.CreateTransfer, createScope, middleware.Idempotency(idempotencyStore, claimTTL, idempotencyKeyTTL))
+	nwWrite.POST("/transfers/batch", handler.SubmitTransferBatch, createScope, middleware.Idempotency(idempotencyStore, claimTTL, idempotencyKeyTTL))
+	nwWrite.POST("/transfers/cancel-all", handler.CancelAllTransfers, cancelScope)
+	nwRead.GET("/transfers", handler.ListTransfers)
+	nwRead.GET("/transfers/estimate", handler.EstimateTransfer)
+	nwRead.GET("/transfers/:id", handler.GetTransfer)
+	// Long polls bound their own wait and are exempt from every request deadline
+	nw.GET("/transfers/:id/wait", handler.WaitForTransfer, readScope)
+	nwRead.GET("/transfers/:id/receipt", handler.GetTransferReceipt)
+	nwWrite.POST("/transfers/:id/sync", handler.SyncTransfer)
+	nwWrite.POST("/transfers/:id/cancel", handler.CancelTransfer, cancelScope)
+	nwWrite.POST("/transfers/:id/reverse", handler.ReverseTransfer, reverseScope)
+	nwWrite.POST("/transfers/:id/disputes", handler.CreateTransferDispute)
+	nwRead.GET("/transfers/:id/disputes", handler.ListTransferDisputes)
+
+	// Dev/test only endpoints; the handler also enforces the environment check
 	if !cfg.IsProduction() {
-		nw.POST("/reset", handler.NorthwindReset)
+		nwWrite.POST("/reset", handler.NorthwindReset)
+	}
+}
+
+// addBalanceAlertEndpoints registers the caller's balance alert rules on their registered external accounts
+func addBalanceAlertEndpoints(api *echo.Group, tokenService *services.TokenService, blacklistedTokenRepo repositories.BlacklistedTokenRepositoryInterface, handler *handlers.BalanceAlertHandler) {
+	alerts := api.Group("/northwind/balance-alerts", middleware.RequireAuth(tokenService, blacklistedTokenRepo))
+	alerts.POST("", handler.CreateRule)
+	alerts.GET("", handler.ListRules)
+	alerts.GET("/:id", handler.GetRule)
+	alerts.PUT("/:id", handler.UpdateRule)
+	alerts.DELETE("/:id", handler.DeleteRule)
+}
+
+// addNorthwindWebhookEndpoints registers the NorthWind webhook receiver. It is authenticated by the
+// delivery signature rather than a user token, and only mounted when a webhook secret is set.
+func addNorthwindWebhookEndpoints(api *echo.Group, handler *handlers.NorthwindWebhookHandler) {
+	if cfg.NorthWind.WebhookSecret == "" {
+		return
 	}
+	api.POST("/northwind/webhooks/transfers", handler.ReceiveTransferWebhook)
 }
 
 // addDocumentationEndpoints registers API documentation routes
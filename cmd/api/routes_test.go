@@ -0,0 +1,107 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/config"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories/repository_mocks"
+	"github.com/array/banking-api/internal/services"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// claimScopes are every scope a route checks in the caller's claims
+var claimScopes = []string{
+	models.ClaimScopeTransfersCreate,
+	models.ClaimScopeTransfersCancel,
+	models.ClaimScopeTransfersReverse,
+	models.ClaimScopeAccountsRegister,
+	models.ClaimScopeAdmin,
+}
+
+// Test each scope-gated route refuses an admin token carrying every scope but the one it needs
+func TestRoutes_RequireClaimScope(t *testing.T) {
+	previous := cfg
+	cfg = &config.Config{Server: config.ServerConfig{Environment: "test"}}
+	t.Cleanup(func() { cfg = previous })
+
+	privateKey, publicKey, err := config.GenerateRSAKeyPair()
+	require.NoError(t, err)
+	tokenService := services.NewTokenService(&config.JWTConfig{
+		PrivateKey:           privateKey,
+		PublicKey:            publicKey,
+		Issuer:               "test-issuer",
+		AccessTokenDuration:  time.Hour,
+		RefreshTokenDuration: time.Hour,
+	}).(*services.TokenService)
+	blacklist := repository_mocks.NewMockBlacklistedTokenRepositoryInterface(gomock.NewController(t))
+	blacklist.EXPECT().GetByJTI(gomock.Any()).Return(nil, nil).AnyTimes()
+
+	// Handlers are never reached: every request is refused before them
+	e := echo.New()
+	api := e.Group("/api/v1")
+	addAccountEndpoints(api, tokenService, blacklist, nil, nil, nil, nil)
+	addAdminEndpoints(api, tokenService, blacklist, nil, nil, nil, nil, nil, nil, nil, nil)
+	addNorthwindEndpoints(api, tokenService, blacklist, nil, nil, nil)
+
+	tokenWithout := func(missing string) string {
+		var scopes models.TokenScopes
+		for _, scope := range claimScopes {
+			if scope != missing {
+				scopes = append(scopes, scope)
+			}
+		}
+		now := time.Now()
+		token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, models.CustomClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    "test-issuer",
+				ID:        uuid.New().String(),
+				IssuedAt:  jwt.NewNumericDate(now),
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+			},
+			UserID:    uuid.New().String(),
+			Role:      models.RoleAdmin,
+			TokenType: services.TokenTypeAccess,
+			Scopes:    scopes,
+		}).SignedString(privateKey)
+		require.NoError(t, err)
+		return token
+	}
+
+	id := uuid.New().String()
+	tests := []struct {
+		method string
+		path   string
+		scope  string
+	}{
+		{http.MethodPost, "/api/v1/accounts/" + id + "/transfer", models.ClaimScopeTransfersCreate},
+		{http.MethodPost, "/api/v1/accounts/" + id + "/transfer-ownership", models.ClaimScopeAdmin},
+		{http.MethodGet, "/api/v1/admin/read-only", models.ClaimScopeAdmin},
+		{http.MethodPost, "/api/v1/northwind/external-accounts/validate-and-register", models.ClaimScopeAccountsRegister},
+		{http.MethodPost, "/api/v1/northwind/accounts/import", models.ClaimScopeAccountsRegister},
+		{http.MethodPost, "/api/v1/northwind/transfers", models.ClaimScopeTransfersCreate},
+		{http.MethodPost, "/api/v1/northwind/transfers/batch", models.ClaimScopeTransfersCreate},
+		{http.MethodPost, "/api/v1/northwind/transfers/cancel-all", models.ClaimScopeTransfersCancel},
+		{http.MethodPost, "/api/v1/northwind/transfers/" + id + "/cancel", models.ClaimScopeTransfersCancel},
+		{http.MethodPost, "/api/v1/northwind/transfers/" + id + "/reverse", models.ClaimScopeTransfersReverse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+tokenWithout(tt.scope))
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusForbidden, rec.Code)
+			assert.Contains(t, rec.Body.String(), `"missing_scope":"`+tt.scope+`"`)
+		})
+	}
+}
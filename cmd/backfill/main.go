@@ -0,0 +1,65 @@
+// Command backfill runs the batched column backfills registered in internal/database, such as
+// setting version and next_poll_at on transfers created before those columns existed. Progress
+// is kept in backfill_progress, so an interrupted run picks up where it stopped and completed
+// backfills are skipped.
+//
+//	backfill                          # run every registered backfill
+//	backfill northwind_transfers.version
+//	backfill -list
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/array/banking-api/internal/config"
+	"github.com/array/banking-api/internal/database"
+	"github.com/array/banking-api/internal/fieldcrypt"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	batchSize := flag.Int("batch-size", database.DefaultBackfillBatchSize, "rows updated per batch")
+	list := flag.Bool("list", false, "print the registered backfill IDs and exit")
+	flag.Parse()
+
+	if *list {
+		for _, b := range database.RegisteredBackfills() {
+			fmt.Println(b.ID)
+		}
+		return
+	}
+
+	if os.Getenv("APP_ENV") == "production" {
+		_ = godotenv.Load(".env.production.example")
+	} else {
+		_ = godotenv.Load(".env.example")
+	}
+	cfg := config.Load()
+
+	keyring, err := fieldcrypt.NewKeyringFromConfig(&cfg.Encryption)
+	if err != nil {
+		log.Fatal("Failed to load field encryption keys:", err)
+	}
+	fieldcrypt.SetDefault(keyring)
+
+	db, err := database.Initialize(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize database:", err)
+	}
+
+	// Stopping mid-batch rolls that batch back; the next run resumes after the last committed one
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := database.RunBackfills(ctx, db, *batchSize, slog.Default(), flag.Args()...); err != nil {
+		log.Fatal("Backfill failed:", err)
+	}
+	slog.Info("Backfills complete")
+}
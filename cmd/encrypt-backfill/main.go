@@ -0,0 +1,49 @@
+// Command encrypt-backfill encrypts legacy plaintext account numbers and re-encrypts values
+// sealed with a retired key under the active FIELD_ENCRYPTION_ACTIVE_KEY_ID. Run it after
+// deploying migration 000019 and after every key rotation; it is idempotent.
+package main
+
+import (
+	"flag"
+	"log"
+	"log/slog"
+	"os"
+
+	"github.com/array/banking-api/internal/config"
+	"github.com/array/banking-api/internal/database"
+	"github.com/array/banking-api/internal/fieldcrypt"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	batchSize := flag.Int("batch-size", database.DefaultBackfillBatchSize, "rows read per batch")
+	flag.Parse()
+
+	if os.Getenv("APP_ENV") == "production" {
+		_ = godotenv.Load(".env.production.example")
+	} else {
+		_ = godotenv.Load(".env.example")
+	}
+	cfg := config.Load()
+
+	keyring, err := fieldcrypt.NewKeyringFromConfig(&cfg.Encryption)
+	if err != nil {
+		log.Fatal("Failed to load field encryption keys:", err)
+	}
+	fieldcrypt.SetDefault(keyring)
+
+	db, err := database.Initialize(cfg)
+	if err != nil {
+		log.Fatal("Failed to initialize database:", err)
+	}
+
+	slog.Info("Starting encryption backfill", "active_key_id", keyring.ActiveKeyID(), "batch_size", *batchSize)
+	results, err := database.BackfillEncryptedColumns(db, keyring, *batchSize)
+	for _, r := range results {
+		slog.Info("Backfilled column", "table", r.Table, "column", r.Column, "scanned", r.Scanned, "updated", r.Updated)
+	}
+	if err != nil {
+		log.Fatal("Encryption backfill failed:", err)
+	}
+	slog.Info("Encryption backfill complete")
+}
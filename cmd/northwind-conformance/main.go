@@ -0,0 +1,47 @@
+// Command northwind-conformance runs the NorthWind conformance test suite against a live
+// sandbox, for checking the client before a partner API upgrade and from a nightly CI job. The
+// sandbox is configured with NORTHWIND_SANDBOX_BASE_URL and NORTHWIND_SANDBOX_API_KEY; without
+// them it exits successfully without running anything unless -require is set. It must run from
+// the repository root with the Go toolchain available.
+//
+//	northwind-conformance
+//	northwind-conformance -run TransferLifecycle -require
+package main
+
+import (
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"os/exec"
+	"time"
+)
+
+const conformancePackage = "./internal/integrations/northwind/"
+
+func main() {
+	run := flag.String("run", "Conformance", "regexp selecting the conformance tests to run")
+	timeout := flag.Duration("timeout", 10*time.Minute, "timeout for the whole suite")
+	require := flag.Bool("require", false, "fail instead of skipping when sandbox credentials are not set")
+	flag.Parse()
+
+	if os.Getenv("NORTHWIND_SANDBOX_BASE_URL") == "" || os.Getenv("NORTHWIND_SANDBOX_API_KEY") == "" {
+		if *require {
+			log.Fatal("NORTHWIND_SANDBOX_BASE_URL and NORTHWIND_SANDBOX_API_KEY must be set")
+		}
+		log.Println("NorthWind sandbox credentials not set, skipping conformance suite")
+		return
+	}
+
+	cmd := exec.Command("go", "test", "-tags", "conformance", "-count=1", "-v",
+		"-run", *run, "-timeout", timeout.String(), conformancePackage)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+		log.Fatal("Failed to run conformance suite:", err)
+	}
+}
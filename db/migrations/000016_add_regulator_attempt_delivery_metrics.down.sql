@@ -0,0 +1,4 @@
+ALTER TABLE regulator_notification_attempts
+    DROP COLUMN IF EXISTS request_headers_hash,
+    DROP COLUMN IF EXISTS target_url,
+    DROP COLUMN IF EXISTS duration_ms;
@@ -0,0 +1,8 @@
+-- Record delivery latency, target URL, and a hash of the request headers for SLA disputes
+ALTER TABLE regulator_notification_attempts
+    ADD COLUMN IF NOT EXISTS duration_ms INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS target_url TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS request_headers_hash TEXT NULL;
+
+COMMENT ON COLUMN regulator_notification_attempts.duration_ms IS 'Wall-clock duration of the webhook call in milliseconds';
+COMMENT ON COLUMN regulator_notification_attempts.request_headers_hash IS 'SHA-256 of the canonicalized request headers sent to the regulator';
@@ -0,0 +1,4 @@
+ALTER TABLE northwind_transfers
+    DROP COLUMN IF EXISTS consent_method,
+    DROP COLUMN IF EXISTS consent_ip_address,
+    DROP COLUMN IF EXISTS consent_timestamp;
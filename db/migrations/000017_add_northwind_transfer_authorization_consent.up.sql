@@ -0,0 +1,8 @@
+-- Store NACHA-style debit authorization evidence for INBOUND transfers
+ALTER TABLE northwind_transfers
+    ADD COLUMN IF NOT EXISTS consent_timestamp TIMESTAMP NULL,
+    ADD COLUMN IF NOT EXISTS consent_ip_address TEXT NULL,
+    ADD COLUMN IF NOT EXISTS consent_method TEXT NULL;
+
+COMMENT ON COLUMN northwind_transfers.consent_timestamp IS 'When the account holder authorized the debit (INBOUND only)';
+COMMENT ON COLUMN northwind_transfers.consent_method IS 'How the authorization was captured: ONLINE, WRITTEN, or TELEPHONE';
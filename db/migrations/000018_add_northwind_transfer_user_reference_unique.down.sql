@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_nw_transfers_user_reference;
@@ -0,0 +1,14 @@
+-- Reference numbers must be unique per user. Existing duplicates (e.g. the "REF001" docs
+-- example reused by several integrators) keep the oldest row as-is and get a suffix.
+UPDATE northwind_transfers t
+SET reference_number = t.reference_number || '-' || LEFT(t.id::text, 8)
+FROM (
+    SELECT id,
+           ROW_NUMBER() OVER (PARTITION BY user_id, reference_number ORDER BY created_at, id) AS rn
+    FROM northwind_transfers
+    WHERE user_id IS NOT NULL
+) d
+WHERE t.id = d.id AND d.rn > 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_nw_transfers_user_reference
+    ON northwind_transfers(user_id, reference_number);
@@ -0,0 +1,10 @@
+-- Encrypted values are not decrypted here; restore plaintext before rolling back.
+DROP INDEX IF EXISTS idx_nw_ext_accounts_unique;
+
+CREATE UNIQUE INDEX idx_nw_ext_accounts_unique
+    ON northwind_external_accounts(user_id, account_number, routing_number);
+CREATE INDEX IF NOT EXISTS idx_nw_ext_accounts_account_number ON northwind_external_accounts(account_number);
+
+COMMENT ON COLUMN northwind_external_accounts.account_number IS NULL;
+
+ALTER TABLE northwind_external_accounts DROP COLUMN IF EXISTS account_number_bidx;
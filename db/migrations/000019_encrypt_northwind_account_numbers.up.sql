@@ -0,0 +1,13 @@
+-- Account numbers are encrypted at rest by the application (randomized AES-GCM), so
+-- uniqueness and exact-match lookups move to a deterministic blind index column.
+-- Existing rows keep their plaintext until `encrypt-backfill` seals them and fills the index.
+ALTER TABLE northwind_external_accounts ADD COLUMN IF NOT EXISTS account_number_bidx TEXT NULL;
+
+DROP INDEX IF EXISTS idx_nw_ext_accounts_unique;
+DROP INDEX IF EXISTS idx_nw_ext_accounts_account_number;
+
+CREATE UNIQUE INDEX idx_nw_ext_accounts_unique
+    ON northwind_external_accounts(user_id, account_number_bidx, routing_number);
+
+COMMENT ON COLUMN northwind_external_accounts.account_number IS 'Encrypted account number (enc:{keyID}:...); legacy rows may hold plaintext until backfilled';
+COMMENT ON COLUMN northwind_external_accounts.account_number_bidx IS 'HMAC-SHA256 blind index of the plaintext account number';
@@ -0,0 +1,2 @@
+-- Monotonic per-transfer version, bumped on every update, used by the long-poll wait endpoint
+ALTER TABLE northwind_transfers ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_nw_transfers_duplicate_check;
+
+ALTER TABLE northwind_transfers DROP COLUMN IF EXISTS destination_account_number_bidx;
@@ -0,0 +1,11 @@
+-- Blind index of the (encrypted) destination account number, so near-identical transfers can be
+-- matched without decrypting. Existing rows are filled by `encrypt-backfill`.
+ALTER TABLE northwind_transfers ADD COLUMN IF NOT EXISTS destination_account_number_bidx TEXT NULL;
+
+-- Covers the CreateTransfer duplicate check: equality on user and destination, a range on
+-- created_at, and the remaining compared columns carried in the index.
+CREATE INDEX IF NOT EXISTS idx_nw_transfers_duplicate_check
+    ON northwind_transfers(user_id, destination_account_number_bidx, created_at)
+    INCLUDE (amount, currency, direction, status, reference_number);
+
+COMMENT ON COLUMN northwind_transfers.destination_account_number_bidx IS 'HMAC-SHA256 blind index of the plaintext destination account number';
@@ -0,0 +1,2 @@
+-- NorthWind's initiation response kept verbatim for audit; encrypted by the application because it carries full account numbers
+ALTER TABLE northwind_transfers ADD COLUMN IF NOT EXISTS raw_response TEXT;
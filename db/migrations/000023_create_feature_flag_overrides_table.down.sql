@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_feature_flag_overrides_updated_at ON feature_flag_overrides;
+DROP TABLE IF EXISTS feature_flag_overrides;
@@ -0,0 +1,20 @@
+-- Create feature_flag_overrides table for runtime rollout and per-user overrides of feature flags
+CREATE TABLE IF NOT EXISTS feature_flag_overrides (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    flag TEXT NOT NULL,
+    user_id UUID NULL REFERENCES users(id) ON DELETE CASCADE,
+    percentage INT NOT NULL CHECK (percentage BETWEEN 0 AND 100),
+    updated_by UUID NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One rollout override per flag and one override per flag + user
+CREATE UNIQUE INDEX idx_feature_flag_overrides_flag ON feature_flag_overrides(flag) WHERE user_id IS NULL;
+CREATE UNIQUE INDEX idx_feature_flag_overrides_flag_user ON feature_flag_overrides(flag, user_id) WHERE user_id IS NOT NULL;
+
+-- Trigger to update updated_at
+CREATE TRIGGER update_feature_flag_overrides_updated_at BEFORE UPDATE ON feature_flag_overrides
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE feature_flag_overrides IS 'Runtime feature flag overrides; rows without user_id set the rollout percentage for everyone';
@@ -0,0 +1,10 @@
+-- Progress of batched data backfills (internal/database/backfill.go), so interrupted runs resume
+CREATE TABLE IF NOT EXISTS backfill_progress (
+    id TEXT PRIMARY KEY,
+    last_key TEXT NOT NULL DEFAULT '',
+    rows_processed BIGINT NOT NULL DEFAULT 0,
+    completed_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE backfill_progress IS 'Resumable progress of batched column backfills';
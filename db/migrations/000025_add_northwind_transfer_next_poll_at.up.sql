@@ -0,0 +1,3 @@
+-- When the poller should next check a transfer. Existing in-flight transfers are scheduled by
+-- the northwind_transfers.next_poll_at backfill rather than here, to keep this migration fast.
+ALTER TABLE northwind_transfers ADD COLUMN IF NOT EXISTS next_poll_at TIMESTAMP NULL;
@@ -0,0 +1,3 @@
+-- Serves keyset pagination of a user's transfers, newest first, without scanning skipped rows
+CREATE INDEX IF NOT EXISTS idx_nw_transfers_user_keyset
+    ON northwind_transfers (user_id, created_at DESC, id DESC);
@@ -0,0 +1,2 @@
+ALTER TABLE northwind_transfers DROP COLUMN IF EXISTS origin_user_agent;
+ALTER TABLE northwind_transfers DROP COLUMN IF EXISTS origin_ip;
@@ -0,0 +1,4 @@
+-- Client that initiated each transfer, for fraud investigations. 45 characters fits the longest
+-- textual IPv6 address (IPv4-mapped); User-Agent is truncated to 512 characters on write.
+ALTER TABLE northwind_transfers ADD COLUMN IF NOT EXISTS origin_ip VARCHAR(45) NULL;
+ALTER TABLE northwind_transfers ADD COLUMN IF NOT EXISTS origin_user_agent VARCHAR(512) NULL;
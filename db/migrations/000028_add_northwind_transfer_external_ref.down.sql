@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_nw_transfers_external_ref;
+ALTER TABLE northwind_transfers DROP COLUMN IF EXISTS external_ref;
@@ -0,0 +1,5 @@
+-- NorthWind's transfer ID exactly as NorthWind returned it, which may not be a UUID. Existing
+-- rows are filled from northwind_transfer_id by the northwind_transfers.external_ref backfill
+-- rather than here, to keep this migration fast.
+ALTER TABLE northwind_transfers ADD COLUMN IF NOT EXISTS external_ref TEXT NULL;
+CREATE INDEX IF NOT EXISTS idx_nw_transfers_external_ref ON northwind_transfers(external_ref);
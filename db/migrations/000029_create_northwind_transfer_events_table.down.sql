@@ -0,0 +1 @@
+DROP TABLE IF EXISTS northwind_transfer_events;
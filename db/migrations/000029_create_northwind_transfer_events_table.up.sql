@@ -0,0 +1,14 @@
+-- Status history of NorthWind transfers: one row per actual status transition, whichever of the
+-- poller or the webhook receiver observed it first
+CREATE TABLE IF NOT EXISTS northwind_transfer_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    transfer_id UUID NOT NULL REFERENCES northwind_transfers(id) ON DELETE CASCADE,
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    source TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_nw_transfer_events_transfer_id ON northwind_transfer_events(transfer_id);
+
+COMMENT ON TABLE northwind_transfer_events IS 'Status history of NorthWind transfers';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_nw_transfers_user_batch;
+ALTER TABLE northwind_transfers DROP COLUMN IF EXISTS batch_index;
+ALTER TABLE northwind_transfers DROP COLUMN IF EXISTS batch_name;
@@ -0,0 +1,6 @@
+-- Batch a transfer was submitted in and its position there. Items NorthWind rejected are kept as
+-- FAILED transfers so they can be inspected and retried.
+ALTER TABLE northwind_transfers ADD COLUMN IF NOT EXISTS batch_name TEXT NULL;
+ALTER TABLE northwind_transfers ADD COLUMN IF NOT EXISTS batch_index INT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_nw_transfers_user_batch ON northwind_transfers(user_id, batch_name) WHERE batch_name IS NOT NULL;
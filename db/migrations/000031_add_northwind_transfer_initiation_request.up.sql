@@ -0,0 +1,3 @@
+-- Request of a transfer queued while NorthWind is under maintenance, sent once the window closes.
+-- Encrypted by the application like raw_response.
+ALTER TABLE northwind_transfers ADD COLUMN IF NOT EXISTS initiation_request TEXT NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_nw_ext_accounts_needs_review;
+ALTER TABLE northwind_external_accounts DROP COLUMN IF EXISTS needs_review;
+ALTER TABLE northwind_external_accounts DROP COLUMN IF EXISTS validated_holder_name;
+ALTER TABLE northwind_external_accounts DROP COLUMN IF EXISTS name_match;
@@ -0,0 +1,7 @@
+-- How the account holder name a user typed compared with the name NorthWind has on file. On a
+-- mismatch NorthWind's name is kept and the account is flagged for review.
+ALTER TABLE northwind_external_accounts ADD COLUMN IF NOT EXISTS name_match TEXT NULL;
+ALTER TABLE northwind_external_accounts ADD COLUMN IF NOT EXISTS validated_holder_name TEXT NULL;
+ALTER TABLE northwind_external_accounts ADD COLUMN IF NOT EXISTS needs_review BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_nw_ext_accounts_needs_review ON northwind_external_accounts(created_at) WHERE needs_review;
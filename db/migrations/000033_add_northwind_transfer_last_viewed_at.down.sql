@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_nw_transfers_last_viewed_at;
+ALTER TABLE northwind_transfers DROP COLUMN IF EXISTS last_viewed_at;
@@ -0,0 +1,5 @@
+-- When a user last viewed an in-flight transfer; the poller checks recently viewed transfers
+-- ahead of the backlog.
+ALTER TABLE northwind_transfers ADD COLUMN IF NOT EXISTS last_viewed_at TIMESTAMP NULL;
+
+CREATE INDEX IF NOT EXISTS idx_nw_transfers_last_viewed_at ON northwind_transfers(last_viewed_at);
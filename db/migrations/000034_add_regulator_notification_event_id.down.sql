@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_reg_notif_event_id;
+ALTER TABLE regulator_notifications DROP COLUMN IF EXISTS event_id;
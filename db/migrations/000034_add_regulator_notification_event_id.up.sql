@@ -0,0 +1,5 @@
+-- The event_id sent in the webhook payload, so a regulator's event can be traced to its
+-- notification. Existing rows are filled from the payload by the regulator_notifications.event_id
+-- backfill rather than here, to keep this migration fast.
+ALTER TABLE regulator_notifications ADD COLUMN IF NOT EXISTS event_id TEXT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reg_notif_event_id ON regulator_notifications(event_id);
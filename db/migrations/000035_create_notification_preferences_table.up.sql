@@ -0,0 +1,39 @@
+-- Create notification_preferences table for users' per-event-type notification channels
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_type TEXT NOT NULL,
+    in_app BOOLEAN NOT NULL,
+    email BOOLEAN NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One preference per user and event type
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notification_preferences_user_event ON notification_preferences(user_id, event_type);
+
+-- Trigger to update updated_at
+CREATE TRIGGER update_notification_preferences_updated_at BEFORE UPDATE ON notification_preferences
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Create user_notifications table for in-app notifications and queued emails
+CREATE TABLE IF NOT EXISTS user_notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    transfer_id UUID NULL,
+    event_type TEXT NOT NULL,
+    channel TEXT NOT NULL CHECK (channel IN ('IN_APP', 'EMAIL')),
+    recipient TEXT NOT NULL DEFAULT '',
+    title TEXT NOT NULL,
+    message TEXT NOT NULL,
+    read_at TIMESTAMP NULL,
+    sent_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_notifications_user ON user_notifications(user_id);
+-- Emails waiting to be sent
+CREATE INDEX IF NOT EXISTS idx_user_notifications_unsent_email ON user_notifications(created_at) WHERE channel = 'EMAIL' AND sent_at IS NULL;
+
+COMMENT ON TABLE notification_preferences IS 'Per-user overrides of the default notification channels for each event type';
+COMMENT ON TABLE user_notifications IS 'In-app notifications and queued notification emails';
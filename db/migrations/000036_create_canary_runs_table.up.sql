@@ -0,0 +1,15 @@
+-- Create canary_runs table for the results of synthetic canary transfers
+CREATE TABLE IF NOT EXISTS canary_runs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    status TEXT NOT NULL CHECK (status IN ('RUNNING', 'SUCCEEDED', 'FAILED')),
+    failed_stage TEXT NULL,
+    error TEXT NULL,
+    transfer_id UUID NULL REFERENCES northwind_transfers(id) ON DELETE SET NULL,
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP NULL,
+    duration_ms BIGINT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_canary_runs_started_at ON canary_runs(started_at);
+
+COMMENT ON TABLE canary_runs IS 'Synthetic canary transfers: initiation, completion and regulator delivery with end-to-end duration';
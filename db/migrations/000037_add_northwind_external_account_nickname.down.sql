@@ -0,0 +1 @@
+ALTER TABLE northwind_external_accounts DROP COLUMN IF EXISTS nickname;
@@ -0,0 +1,2 @@
+-- A customer's label for an external account, such as the vendor it pays
+ALTER TABLE northwind_external_accounts ADD COLUMN IF NOT EXISTS nickname TEXT NULL;
@@ -0,0 +1,19 @@
+-- Create poll_anomalies table for NorthWind poll responses our status mapping could not handle
+CREATE TABLE IF NOT EXISTS poll_anomalies (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    transfer_id UUID NOT NULL REFERENCES northwind_transfers(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL CHECK (reason IN ('UNKNOWN_STATUS', 'UNDECODABLE_RESPONSE')),
+    reported_status TEXT NOT NULL DEFAULT '',
+    raw_body TEXT NOT NULL,
+    transfer_version INTEGER NOT NULL,
+    occurrences INTEGER NOT NULL DEFAULT 1,
+    first_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP NULL,
+    resolution TEXT NULL CHECK (resolution IN ('APPLIED', 'UNCHANGED', 'STALE'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_poll_anomalies_transfer_id ON poll_anomalies(transfer_id);
+CREATE INDEX IF NOT EXISTS idx_poll_anomalies_resolved_at ON poll_anomalies(resolved_at);
+
+COMMENT ON TABLE poll_anomalies IS 'Quarantined NorthWind poll responses, kept raw for replay once the status mapping is updated';
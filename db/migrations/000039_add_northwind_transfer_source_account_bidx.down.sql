@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_nw_transfers_source_keyset;
+
+ALTER TABLE northwind_transfers DROP COLUMN IF EXISTS source_account_number_bidx;
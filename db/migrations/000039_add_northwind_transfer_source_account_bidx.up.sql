@@ -0,0 +1,9 @@
+-- Blind index of the (encrypted) source account number, so an account's transfers can be listed
+-- without decrypting. Existing rows are filled by `encrypt-backfill`.
+ALTER TABLE northwind_transfers ADD COLUMN IF NOT EXISTS source_account_number_bidx TEXT NULL;
+
+-- Covers the account activity feed: equality on the source, newest first with id as tie-breaker.
+CREATE INDEX IF NOT EXISTS idx_nw_transfers_source_keyset
+    ON northwind_transfers(source_account_number_bidx, created_at DESC, id DESC);
+
+COMMENT ON COLUMN northwind_transfers.source_account_number_bidx IS 'HMAC-SHA256 blind index of the plaintext source account number';
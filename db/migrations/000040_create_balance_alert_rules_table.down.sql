@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_balance_alert_rules_updated_at ON balance_alert_rules;
+DROP TABLE IF EXISTS balance_alert_rules;
@@ -0,0 +1,26 @@
+-- Create balance_alert_rules table for users' balance threshold alerts on registered external accounts
+CREATE TABLE IF NOT EXISTS balance_alert_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    external_account_id UUID NOT NULL REFERENCES northwind_external_accounts(id) ON DELETE CASCADE,
+    threshold NUMERIC(15,2) NOT NULL CHECK (threshold > 0),
+    direction TEXT NOT NULL CHECK (direction IN ('BELOW', 'ABOVE')),
+    channel TEXT NOT NULL CHECK (channel IN ('IN_APP', 'EMAIL')),
+    frequent BOOLEAN NOT NULL DEFAULT FALSE,
+    breached BOOLEAN NOT NULL DEFAULT FALSE,
+    last_balance NUMERIC(15,2) NULL,
+    last_evaluated_at TIMESTAMP NULL,
+    last_alerted_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_balance_alert_rules_user_id ON balance_alert_rules(user_id);
+CREATE INDEX IF NOT EXISTS idx_balance_alert_rules_external_account_id ON balance_alert_rules(external_account_id);
+
+-- Trigger to update updated_at
+CREATE TRIGGER update_balance_alert_rules_updated_at BEFORE UPDATE ON balance_alert_rules
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE balance_alert_rules IS 'Balance thresholds on registered external accounts that notify their owner when crossed';
+COMMENT ON COLUMN balance_alert_rules.breached IS 'Outcome of the last evaluation; alerts fire when it turns true';
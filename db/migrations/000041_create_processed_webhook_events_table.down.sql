@@ -0,0 +1 @@
+DROP TABLE IF EXISTS processed_webhook_events;
@@ -0,0 +1,15 @@
+-- Create processed_webhook_events table so redelivered webhook events are not processed twice
+CREATE TABLE IF NOT EXISTS processed_webhook_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    event_id TEXT NOT NULL,
+    source TEXT NOT NULL,
+    received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    outcome TEXT NOT NULL CHECK (outcome IN ('PROCESSING', 'APPLIED', 'UNCHANGED', 'IGNORED'))
+);
+
+-- The receiver inserts before processing; this constraint picks the one delivery that processes an event
+CREATE UNIQUE INDEX IF NOT EXISTS idx_processed_webhook_events_event_id ON processed_webhook_events(event_id);
+-- Pruning of events past the retention period
+CREATE INDEX IF NOT EXISTS idx_processed_webhook_events_received_at ON processed_webhook_events(received_at);
+
+COMMENT ON TABLE processed_webhook_events IS 'Accepted webhook events, kept for the retention period to acknowledge redeliveries without reprocessing';
@@ -0,0 +1 @@
+ALTER TABLE northwind_transfers DROP COLUMN IF EXISTS internal_test;
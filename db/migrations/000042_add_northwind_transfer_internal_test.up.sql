@@ -0,0 +1,4 @@
+-- Canary and QA transfers flagged by an admin are never reported to the regulator
+ALTER TABLE northwind_transfers ADD COLUMN IF NOT EXISTS internal_test BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN northwind_transfers.internal_test IS 'Internal test transfer: no regulator notification is created for it';
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS update_risk_rule_overrides_updated_at ON risk_rule_overrides;
+DROP TABLE IF EXISTS risk_rule_overrides;
+DROP TABLE IF EXISTS risk_evaluations;
@@ -0,0 +1,36 @@
+-- Create risk_evaluations table recording every risk rule verdict on a transfer request
+CREATE TABLE IF NOT EXISTS risk_evaluations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    transfer_id UUID NULL REFERENCES northwind_transfers(id) ON DELETE SET NULL,
+    user_id UUID NOT NULL,
+    rule TEXT NOT NULL,
+    mode TEXT NOT NULL CHECK (mode IN ('shadow', 'enforce')),
+    verdict TEXT NOT NULL CHECK (verdict IN ('PASS', 'FLAG')),
+    blocked BOOLEAN NOT NULL DEFAULT FALSE,
+    inputs JSONB,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Analysis queries filter by rule over a time range
+CREATE INDEX IF NOT EXISTS idx_risk_evaluations_rule_created_at ON risk_evaluations(rule, created_at);
+CREATE INDEX IF NOT EXISTS idx_risk_evaluations_transfer_id ON risk_evaluations(transfer_id);
+
+COMMENT ON TABLE risk_evaluations IS 'Risk rule verdicts on transfer requests; transfer_id is NULL when the request was blocked';
+
+-- Create risk_rule_overrides table for switching a risk rule's mode without a deploy
+CREATE TABLE IF NOT EXISTS risk_rule_overrides (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    rule TEXT NOT NULL,
+    mode TEXT NOT NULL CHECK (mode IN ('off', 'shadow', 'enforce')),
+    updated_by UUID NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_risk_rule_overrides_rule ON risk_rule_overrides(rule);
+
+-- Trigger to update updated_at
+CREATE TRIGGER update_risk_rule_overrides_updated_at BEFORE UPDATE ON risk_rule_overrides
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE risk_rule_overrides IS 'Runtime risk rule modes; a row replaces the mode configured in RISK_RULE_MODES';
@@ -0,0 +1,2 @@
+ALTER TABLE northwind_transfers DROP COLUMN IF EXISTS failure_retryable;
+ALTER TABLE northwind_transfers DROP COLUMN IF EXISTS failure_category;
@@ -0,0 +1,6 @@
+-- How a failed transfer's error code was classified when it failed
+ALTER TABLE northwind_transfers ADD COLUMN IF NOT EXISTS failure_category TEXT;
+ALTER TABLE northwind_transfers ADD COLUMN IF NOT EXISTS failure_retryable BOOLEAN;
+
+COMMENT ON COLUMN northwind_transfers.failure_category IS 'Category of the error code in the NorthWind error code table when the transfer failed';
+COMMENT ON COLUMN northwind_transfers.failure_retryable IS 'Whether the error code was retryable when the transfer failed';
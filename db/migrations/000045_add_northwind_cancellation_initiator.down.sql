@@ -0,0 +1,3 @@
+ALTER TABLE northwind_transfer_events DROP COLUMN IF EXISTS initiator_id;
+ALTER TABLE northwind_transfers DROP COLUMN IF EXISTS cancellation_initiator_id;
+ALTER TABLE northwind_transfers DROP COLUMN IF EXISTS cancellation_initiator;
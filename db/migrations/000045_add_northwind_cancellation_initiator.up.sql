@@ -0,0 +1,8 @@
+-- Who had a transfer cancelled or reversed: its owner, an admin or the system
+ALTER TABLE northwind_transfers ADD COLUMN IF NOT EXISTS cancellation_initiator TEXT;
+ALTER TABLE northwind_transfers ADD COLUMN IF NOT EXISTS cancellation_initiator_id UUID;
+ALTER TABLE northwind_transfer_events ADD COLUMN IF NOT EXISTS initiator_id UUID;
+
+COMMENT ON COLUMN northwind_transfers.cancellation_initiator IS 'user, admin or system: who had the transfer cancelled or reversed';
+COMMENT ON COLUMN northwind_transfers.cancellation_initiator_id IS 'User or admin who had the transfer cancelled or reversed; NULL for the system';
+COMMENT ON COLUMN northwind_transfer_events.initiator_id IS 'User or admin who made a USER or ADMIN status change';
@@ -0,0 +1,19 @@
+-- Create personal_access_tokens table for users' server-to-server API tokens
+CREATE TABLE IF NOT EXISTS personal_access_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    scopes TEXT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    last_used_at TIMESTAMP NULL,
+    revoked_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_personal_access_tokens_user_id ON personal_access_tokens(user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_personal_access_tokens_token_hash ON personal_access_tokens(token_hash);
+
+COMMENT ON TABLE personal_access_tokens IS 'User-scoped API tokens sent as "Authorization: Token <secret>"';
+COMMENT ON COLUMN personal_access_tokens.token_hash IS 'Hex SHA-256 of the secret; the secret itself is never stored';
+COMMENT ON COLUMN personal_access_tokens.scopes IS 'Comma-separated scopes, e.g. transfers:read,transfers:write';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_reg_notif_related_id;
+ALTER TABLE regulator_notifications DROP COLUMN IF EXISTS related_notification_id;
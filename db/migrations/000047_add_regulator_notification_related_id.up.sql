@@ -0,0 +1,6 @@
+-- Link a REVERSED notification to the COMPLETED notification it follows up
+ALTER TABLE regulator_notifications ADD COLUMN IF NOT EXISTS related_notification_id UUID NULL REFERENCES regulator_notifications(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_reg_notif_related_id ON regulator_notifications(related_notification_id);
+
+COMMENT ON COLUMN regulator_notifications.related_notification_id IS 'For a REVERSED notification, the COMPLETED notification of the same transfer';
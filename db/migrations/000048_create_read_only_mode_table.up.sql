@@ -0,0 +1,10 @@
+-- Create read_only_mode table holding the read-only switch as last set by an admin
+CREATE TABLE IF NOT EXISTS read_only_mode (
+    id SMALLINT PRIMARY KEY CHECK (id = 1),
+    enabled BOOLEAN NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    updated_by UUID NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE read_only_mode IS 'Single-row read-only switch; while the row is absent READ_ONLY_MODE applies';
@@ -0,0 +1,4 @@
+-- STANDARD or SAME_DAY; existing transfers were all sent without a priority, which NorthWind treats as STANDARD
+ALTER TABLE northwind_transfers ADD COLUMN IF NOT EXISTS priority TEXT NOT NULL DEFAULT 'STANDARD';
+
+COMMENT ON COLUMN northwind_transfers.priority IS 'STANDARD, or SAME_DAY for an expedited ACH transfer';
@@ -0,0 +1 @@
+ALTER TABLE northwind_transfer_events DROP COLUMN IF EXISTS changes;
@@ -0,0 +1,4 @@
+-- Columns each update changed, for dispute resolution
+ALTER TABLE northwind_transfer_events ADD COLUMN IF NOT EXISTS changes JSONB;
+
+COMMENT ON COLUMN northwind_transfer_events.changes IS 'Columns the update changed, as {"column": {"old": ..., "new": ...}}; encrypted columns are redacted';
@@ -0,0 +1 @@
+ALTER TABLE read_only_mode DROP COLUMN IF EXISTS expected_end;
@@ -0,0 +1,4 @@
+-- When the admin who turned read-only mode on expects to lift it, sent to refused clients as a retry hint
+ALTER TABLE read_only_mode ADD COLUMN IF NOT EXISTS expected_end TIMESTAMP NULL;
+
+COMMENT ON COLUMN read_only_mode.expected_end IS 'When read-only mode is expected to be lifted; refused writes are told to retry then. It does not lift the mode';
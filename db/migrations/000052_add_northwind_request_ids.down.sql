@@ -0,0 +1,3 @@
+ALTER TABLE poll_anomalies DROP COLUMN IF EXISTS upstream_request_id;
+ALTER TABLE northwind_transfer_events DROP COLUMN IF EXISTS upstream_request_id;
+ALTER TABLE northwind_transfers DROP COLUMN IF EXISTS initiation_request_id;
@@ -0,0 +1,8 @@
+-- NorthWind's X-NW-Request-ID for the responses behind a transfer, quoted when opening a NorthWind support ticket
+ALTER TABLE northwind_transfers ADD COLUMN IF NOT EXISTS initiation_request_id TEXT;
+ALTER TABLE northwind_transfer_events ADD COLUMN IF NOT EXISTS upstream_request_id TEXT;
+ALTER TABLE poll_anomalies ADD COLUMN IF NOT EXISTS upstream_request_id TEXT;
+
+COMMENT ON COLUMN northwind_transfers.initiation_request_id IS 'NorthWind''s request ID for the call that initiated the transfer, or that rejected it';
+COMMENT ON COLUMN northwind_transfer_events.upstream_request_id IS 'NorthWind''s request ID for the response that moved the transfer, when a poll or initiation moved it';
+COMMENT ON COLUMN poll_anomalies.upstream_request_id IS 'NorthWind''s request ID for the most recent response with the anomaly';
@@ -0,0 +1,23 @@
+-- Create transfer_disputes table for customer disputes of completed NorthWind transfers
+CREATE TABLE IF NOT EXISTS transfer_disputes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    transfer_id UUID NOT NULL REFERENCES northwind_transfers(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    reason_category TEXT NOT NULL CHECK (reason_category IN ('UNAUTHORIZED', 'INCORRECT_AMOUNT', 'DUPLICATE', 'NOT_RECEIVED', 'OTHER')),
+    description TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'UNDER_REVIEW', 'RESOLVED')),
+    resolution TEXT NULL CHECK (resolution IN ('REVERSED', 'REJECTED')),
+    resolution_note TEXT NULL,
+    reviewer_id UUID NULL,
+    resolved_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_transfer_disputes_transfer_id ON transfer_disputes(transfer_id);
+CREATE INDEX IF NOT EXISTS idx_transfer_disputes_user_id ON transfer_disputes(user_id);
+CREATE INDEX IF NOT EXISTS idx_transfer_disputes_status ON transfer_disputes(status);
+-- At most one unresolved dispute per transfer
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transfer_disputes_unresolved ON transfer_disputes(transfer_id) WHERE status <> 'RESOLVED';
+
+COMMENT ON TABLE transfer_disputes IS 'Customer disputes of completed NorthWind transfers; a disputed transfer is kept past its retention period';
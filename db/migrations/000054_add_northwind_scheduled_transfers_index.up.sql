@@ -0,0 +1,3 @@
+-- Transfers scheduled for a later date are held locally as SCHEDULED until scheduled_date; the
+-- worker looks up the ones that are due
+CREATE INDEX IF NOT EXISTS idx_nw_transfers_scheduled_due ON northwind_transfers(scheduled_date) WHERE status = 'SCHEDULED';
@@ -0,0 +1,3 @@
+-- When a worker claimed a held transfer to send it to NorthWind; the NorthWind calls run outside
+-- any transaction, so the claim is what keeps other workers and cancellations off the transfer
+ALTER TABLE northwind_transfers ADD COLUMN IF NOT EXISTS initiation_claimed_at TIMESTAMP NULL;
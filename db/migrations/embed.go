@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL migrations so the server can tell which ones a database is
+// missing without reading them from disk
+package migrations
+
+import "embed"
+
+// FS holds the versioned up and down migrations, named <version>_<name>.<up|down>.sql
+//
+//go:embed *.sql
+var FS embed.FS
@@ -9,42 +9,281 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/url"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 )
 
 type Config struct {
-	Server    ServerConfig
-	Database  DatabaseConfig
-	JWT       JWTConfig
-	Security  SecurityConfig
-	NorthWind NorthWindConfig
-	Regulator RegulatorConfig
+	Server     ServerConfig
+	Database   DatabaseConfig
+	JWT        JWTConfig
+	Security   SecurityConfig
+	NorthWind  NorthWindConfig
+	Regulator  RegulatorConfig
+	Redis      RedisConfig
+	Encryption EncryptionConfig
+	// FeatureFlags sets per-environment flag rollouts; admin overrides at runtime take precedence
+	FeatureFlags FeatureFlagConfig
+	Canary       CanaryConfig
+	Risk         RiskConfig
+	Retention    RetentionConfig
+	ReadOnly     ReadOnlyConfig
 }
 
 type NorthWindConfig struct {
-	BaseURL               string
-	APIKey                string
-	PollIntervalSeconds   int
-	MaxRetries            int
-	RetryInitialBackoffMs int
+	BaseURL string
+	APIKey  string
+	// APIKeySecondary is tried when NorthWind rejects APIKey, for zero-downtime key rotation
+	APIKeySecondary        string
+	PollIntervalSeconds    int
+	Timeout                time.Duration
+	MaxRetries             int
+	RetryInitialBackoffMs  int
+	RetryMaxDurationMs     int
+	DuplicateWindowSeconds int
+	// MaxInFlightInitiations is how many transfer creations may call NorthWind at once, zero for
+	// no limit, and InitiationQueueSize how many more wait for one of them before being refused
+	MaxInFlightInitiations int
+	InitiationQueueSize    int
+	// ReceiptSigningKey is the HMAC key for transfer receipt verification hashes
+	ReceiptSigningKey string
+	// CursorSigningKey is the HMAC key for transfer list pagination cursors
+	CursorSigningKey string
+	// CursorTTL is how long a transfer list cursor stays usable
+	CursorTTL time.Duration
+	// LegacyTransferResponse makes create-transfer responses default to the deprecated shape
+	// that embeds NorthWind's raw response
+	LegacyTransferResponse bool
+	// StrictDecoding checks NorthWind responses for fields our models lack and reports them as
+	// schema drift; on by default in staging
+	StrictDecoding bool
+	// PollingProfiles sets how often the poller checks in-flight transfers, keyed by transfer type
+	PollingProfiles map[string]PollingProfile
+	// PollPriorityShare is the share of each poll batch, from 0 to 1, reserved for transfers users
+	// viewed within PollPriorityWindow
+	PollPriorityShare  float64
+	PollPriorityWindow time.Duration
+	// PollAnomalyAlertThreshold is how many quarantined poll responses awaiting a replay raise an
+	// alert
+	PollAnomalyAlertThreshold int
+	// AccountValidationCacheTTL is how long a successful account validation is reused for the
+	// same account and routing number; zero disables the cache
+	AccountValidationCacheTTL time.Duration
+	// NameMatchThreshold is the similarity, from 0 to 1, between the typed account holder name and
+	// NorthWind's below which an external account registration is rejected
+	NameMatchThreshold float64
+	// AccountImportConcurrency is how many rows of an external account CSV import are registered
+	// at once, and AccountImportRate how many registrations per second all imports may make
+	AccountImportConcurrency int
+	AccountImportRate        float64
+	// WebhookSecret is the HMAC key NorthWind signs webhook deliveries with; the webhook receiver
+	// is only mounted when it is set
+	WebhookSecret string
+	// WebhookEventRetention is how long processed webhook event IDs are kept to recognise
+	// redeliveries
+	WebhookEventRetention time.Duration
+	// MaintenanceStart and MaintenanceEnd bound an announced NorthWind maintenance window during
+	// which transfer initiations are queued; both zero means none is scheduled
+	MaintenanceStart time.Time
+	MaintenanceEnd   time.Time
+	// SupportedCurrencies limits the currencies transfers may use; empty allows any
+	SupportedCurrencies []string
+	// AdminOnlyTransferTypes are transfer types only admins may initiate
+	AdminOnlyTransferTypes []string
+	// ReferenceNumberPattern is the regular expression client-supplied reference numbers must
+	// match once normalized
+	ReferenceNumberPattern string
+	// CancellationWindows limits how long after initiation users may cancel a transfer, keyed by
+	// transfer type; types without a window may be cancelled whenever NorthWind allows it
+	CancellationWindows map[string]CancellationWindow
+	// SameDayCutoff is the time of day, as HH:MM in BankTimezone, until which SAME_DAY ACH
+	// transfers are accepted on weekdays, and SameDayFee what NorthWind charges for one
+	SameDayCutoff string
+	SameDayFee    float64
+	// BankTimezone is the IANA time zone the bank's business day, and so the cutoff, runs in
+	BankTimezone string
+	// BalanceAlertInterval is how often every balance alert rule is evaluated, and
+	// BalanceAlertFrequentInterval how often rules flagged frequent are
+	BalanceAlertInterval         time.Duration
+	BalanceAlertFrequentInterval time.Duration
+	// BalanceAlertCooldown is the minimum time between two alerts for the same rule
+	BalanceAlertCooldown time.Duration
+	// ErrorCodeOverrides replaces entries of the built-in NorthWind error code table: a
+	// semicolon-separated list of code=category|retryable|message
+	ErrorCodeOverrides string
+	// DisputeWindow is how long after a transfer completes its owner may dispute it
+	DisputeWindow time.Duration
+}
+
+// PollingProfile controls how often the poller checks a transfer of one type: first
+// InitialDelay after it is created, then at an interval that grows from MinInterval to
+// MaxInterval while its status stays the same
+type PollingProfile struct {
+	InitialDelay time.Duration
+	MinInterval  time.Duration
+	MaxInterval  time.Duration
+}
+
+// Valid reports whether the profile's durations are non-negative and MinInterval does not
+// exceed MaxInterval
+func (p PollingProfile) Valid() bool {
+	return p.InitialDelay >= 0 && p.MinInterval >= 0 && p.MinInterval <= p.MaxInterval
+}
+
+// CancellationWindow is how long a transfer of one type stays cancellable: After its initiation,
+// or until its processing date when UntilProcessingDate is set
+type CancellationWindow struct {
+	After               time.Duration
+	UntilProcessingDate bool
+}
+
+// DefaultReferenceNumberPattern is the reference number format NorthWind accepts
+const DefaultReferenceNumberPattern = `^[A-Z0-9\-]{6,40}$`
+
+// cancelWindowUntilProcessing is the NORTHWIND_CANCEL_WINDOW_* value for UntilProcessingDate
+const cancelWindowUntilProcessing = "until_processing"
+
+type FeatureFlagConfig struct {
+	// Rollouts is a comma-separated list of flag=value, where value is true, false or a rollout
+	// percentage such as 25%
+	Rollouts string
+}
+
+// RiskConfig configures the risk rules evaluated before a transfer is initiated, for users the
+// transfer_risk_rules feature flag is on for
+type RiskConfig struct {
+	// RuleModes is a comma-separated list of rule=mode, where mode is off, shadow or enforce;
+	// admin overrides at runtime take precedence
+	RuleModes string
+	// VelocityWindow is how far back the velocity rules look at a user's transfers
+	VelocityWindow time.Duration
+	// VelocityMaxTransfers is how many transfers a user may create in the window
+	VelocityMaxTransfers int
+	// VelocityMaxAmount is the total a user may transfer in one currency in the window
+	VelocityMaxAmount float64
+	// LargeTransferAmount is the amount from which a single transfer is flagged
+	LargeTransferAmount float64
+}
+
+// RetentionConfig configures how long records are kept before the retention job purges them
+type RetentionConfig struct {
+	// TransferYears applies to NorthWind and internal transfers, TransactionYears to ledger
+	// transactions and AuditLogYears to audit logs
+	TransferYears    int
+	TransactionYears int
+	AuditLogYears    int
+	// DryRun makes the job only report what it would purge
+	DryRun bool
+	// Interval is how often the job runs; BatchSize rows are deleted per statement, and one run
+	// stops after MaxDuration, leaving the rest to the next
+	Interval    time.Duration
+	BatchSize   int
+	MaxDuration time.Duration
+}
+
+// ReadOnlyConfig configures read-only mode, which rejects mutating API requests while an incident
+// is investigated
+type ReadOnlyConfig struct {
+	// Enabled is the mode until an admin sets it; the admin's setting is persisted and wins
+	Enabled bool
+	// Workers is a comma-separated list of worker=run or worker=pause saying which background
+	// work continues in read-only mode
+	Workers string
+}
+
+// CanaryConfig configures the synthetic canary transfer, a tiny transfer between two sandbox
+// accounts sent on a schedule to prove the NorthWind to regulator path works end to end
+type CanaryConfig struct {
+	Enabled bool
+	// AllowProduction lets the canary run when the environment is production
+	AllowProduction bool
+	Interval        time.Duration
+	// Timeout bounds one run, from initiation until completion or, with NotifyRegulator, until the
+	// regulator has received the webhook
+	Timeout time.Duration
+	// FailureThreshold is the number of consecutive failed runs that fails the deep health check
+	FailureThreshold int
+	TransferType     string
+	Source           CanaryAccount
+	Destination      CanaryAccount
+	// NotifyRegulator reports canary transfers to the regulator and waits for the webhook; by
+	// default they are flagged internal test transfers and a run ends at completion
+	NotifyRegulator bool
+}
+
+// CanaryAccount is one of the designated sandbox accounts the canary transfers between
+type CanaryAccount struct {
+	HolderName    string
+	AccountNumber string
+	RoutingNumber string
 }
 
 type RegulatorConfig struct {
 	WebhookURL          string
 	RetryInitialSeconds int
 	RetryMaxSeconds     int
+	// PayloadFormat is the wire shape of deliveries: "flat" sends the stored payload, "envelope"
+	// wraps it as {"data": ..., "meta": ...}
+	PayloadFormat string
+	// SigningSecret signs each delivery body with HMAC-SHA256; empty sends unsigned deliveries
+	SigningSecret string
+}
+
+// RedisConfig configures the shared Redis used for idempotency and rate-limit state.
+// An empty Addr keeps that state in process memory.
+type RedisConfig struct {
+	Addr             string
+	Password         string
+	DB               int
+	PoolSize         int
+	MinIdleConns     int
+	DialTimeout      time.Duration
+	OperationTimeout time.Duration
+}
+
+// EncryptionConfig holds the keys for field-level encryption of sensitive columns. Values are
+// plain env vars so they can be injected from a KMS or secret manager at deploy time.
+type EncryptionConfig struct {
+	// Keys is a comma-separated list of keyID:base64(32-byte key); old keys stay listed for reads
+	Keys string
+	// ActiveKeyID selects the key used for new writes
+	ActiveKeyID string
+	// BlindIndexKey is the base64 HMAC key for equality-lookup columns; it cannot be rotated in place
+	BlindIndexKey string
 }
 
 type ServerConfig struct {
-	Port             string
-	Host             string
-	Environment      string
-	ReadTimeout      time.Duration
-	WriteTimeout     time.Duration
+	Port         string
+	Host         string
+	Environment  string
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	// RequestTimeout bounds handler work, including database queries, for a single request
+	RequestTimeout time.Duration
+	// RouteTimeouts replace RequestTimeout for the route groups that need more or less time
+	RouteTimeouts    RouteTimeoutConfig
 	CORSAllowOrigins []string
+	// RedactErrorDetails withholds error details that may carry internal or upstream information
+	// from clients, logging them under the response's trace ID instead; on by default in production
+	RedactErrorDetails bool
+	// DefaultLocale is the locale of response messages for clients whose Accept-Language names no
+	// supported locale
+	DefaultLocale string
+}
+
+// RouteTimeoutConfig holds per route group request deadlines; zero keeps RequestTimeout
+type RouteTimeoutConfig struct {
+	// NorthwindWrite covers NorthWind routes that create or change transfers and accounts, which
+	// make several upstream calls
+	NorthwindWrite time.Duration
+	NorthwindRead  time.Duration
+	Health         time.Duration
+	// AccountImport covers external account CSV imports, which register up to 5000 accounts
+	AccountImport time.Duration
 }
 
 type DatabaseConfig struct {
@@ -57,6 +296,18 @@ type DatabaseConfig struct {
 	MaxConnections  int
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
+	// AutoApplyMigrations applies pending SQL migrations at startup outside production; otherwise
+	// they are only reported and fail readiness
+	AutoApplyMigrations bool
+	// BackfillOnStartup runs pending column backfills in the background after the server starts
+	BackfillOnStartup bool
+	BackfillBatchSize int
+	// TokenCleanup* control the worker job that deletes expired refresh and blacklisted tokens
+	TokenCleanupInterval    time.Duration
+	TokenCleanupBatchSize   int
+	TokenCleanupMaxDuration time.Duration
+	// RepositoryMetrics records the duration and errors of every repository call
+	RepositoryMetrics bool
 }
 
 type JWTConfig struct {
@@ -65,6 +316,11 @@ type JWTConfig struct {
 	PrivateKey           *rsa.PrivateKey
 	PublicKey            *rsa.PublicKey
 	Issuer               string
+	// RejectUnscopedTokens denies scope-checked routes to access tokens issued before tokens
+	// carried scopes; by default they get the scopes of the role in their claims
+	RejectUnscopedTokens bool
+	// RoleScopes replaces the default scopes of the roles it names, from JWT_ROLE_SCOPES_<ROLE>
+	RoleScopes map[string][]string
 }
 
 type SecurityConfig struct {
@@ -81,22 +337,36 @@ type SecurityConfig struct {
 func Load() *Config {
 	config := &Config{
 		Server: ServerConfig{
-			Port:         getEnv("SERVER_PORT", "8080"),
-			Host:         getEnv("SERVER_HOST", "localhost"),
-			Environment:  getEnv("APP_ENV", "development"),
-			ReadTimeout:  getDurationEnv("SERVER_READ_TIMEOUT", 15*time.Second),
-			WriteTimeout: getDurationEnv("SERVER_WRITE_TIMEOUT", 15*time.Second),
+			Port:           getEnv("SERVER_PORT", "8080"),
+			Host:           getEnv("SERVER_HOST", "localhost"),
+			Environment:    getEnv("APP_ENV", "development"),
+			ReadTimeout:    getDurationEnv("SERVER_READ_TIMEOUT", 15*time.Second),
+			WriteTimeout:   getDurationEnv("SERVER_WRITE_TIMEOUT", 15*time.Second),
+			RequestTimeout: getDurationEnv("SERVER_REQUEST_TIMEOUT", 10*time.Second),
+			RouteTimeouts: RouteTimeoutConfig{
+				NorthwindWrite: getDurationEnv("SERVER_TIMEOUT_NORTHWIND_WRITE", 25*time.Second),
+				NorthwindRead:  getDurationEnv("SERVER_TIMEOUT_NORTHWIND_READ", 5*time.Second),
+				Health:         getDurationEnv("SERVER_TIMEOUT_HEALTH", 2*time.Second),
+				AccountImport:  getDurationEnv("SERVER_TIMEOUT_ACCOUNT_IMPORT", 15*time.Minute),
+			},
 		},
 		Database: DatabaseConfig{
-			Host:            getEnv("DB_HOST", "localhost"),
-			Port:            getEnv("DB_PORT", "5432"),
-			User:            getEnv("DB_USER", "banking_user"),
-			Password:        getEnv("DB_PASSWORD", "banking_password"),
-			Name:            getEnv("DB_NAME", "banking_db"),
-			SSLMode:         getEnv("DB_SSL_MODE", "disable"),
-			MaxConnections:  getIntEnv("DB_MAX_CONNECTIONS", 25),
-			MaxIdleConns:    getIntEnv("DB_MAX_IDLE_CONNS", 5),
-			ConnMaxLifetime: getDurationEnv("DB_CONN_MAX_LIFETIME", time.Hour),
+			Host:                    getEnv("DB_HOST", "localhost"),
+			Port:                    getEnv("DB_PORT", "5432"),
+			User:                    getEnv("DB_USER", "banking_user"),
+			Password:                getEnv("DB_PASSWORD", "banking_password"),
+			Name:                    getEnv("DB_NAME", "banking_db"),
+			SSLMode:                 getEnv("DB_SSL_MODE", "disable"),
+			MaxConnections:          getIntEnv("DB_MAX_CONNECTIONS", 25),
+			MaxIdleConns:            getIntEnv("DB_MAX_IDLE_CONNS", 5),
+			ConnMaxLifetime:         getDurationEnv("DB_CONN_MAX_LIFETIME", time.Hour),
+			AutoApplyMigrations:     getBoolEnv("MIGRATIONS_AUTO_APPLY", false),
+			BackfillOnStartup:       getBoolEnv("BACKFILL_ON_STARTUP", false),
+			BackfillBatchSize:       getIntEnv("BACKFILL_BATCH_SIZE", 500),
+			TokenCleanupInterval:    getDurationEnv("TOKEN_CLEANUP_INTERVAL", time.Hour),
+			TokenCleanupBatchSize:   getIntEnv("TOKEN_CLEANUP_BATCH_SIZE", 5000),
+			TokenCleanupMaxDuration: getDurationEnv("TOKEN_CLEANUP_MAX_DURATION", 10*time.Second),
+			RepositoryMetrics:       getBoolEnv("DB_REPOSITORY_METRICS", false),
 		},
 		Security: SecurityConfig{
 			BCryptCost:          getIntEnv("BCRYPT_COST", 12),
@@ -112,24 +382,140 @@ func Load() *Config {
 			AccessTokenDuration:  getDurationEnv("JWT_ACCESS_TOKEN_DURATION", 24*time.Hour),
 			RefreshTokenDuration: getDurationEnv("JWT_REFRESH_TOKEN_DURATION", 7*24*time.Hour),
 			Issuer:               getEnv("JWT_ISSUER", "banking-api"),
+			RejectUnscopedTokens: getBoolEnv("JWT_REJECT_UNSCOPED_TOKENS", false),
+			RoleScopes:           getRoleScopesEnv("JWT_ROLE_SCOPES_", "customer", "admin"),
 		},
 	}
 
 	config.NorthWind = NorthWindConfig{
-		BaseURL:               getEnv("NORTHWIND_BASE_URL", "https://northwind.dev.array.io"),
-		APIKey:                getEnv("NORTHWIND_API_KEY", ""),
-		PollIntervalSeconds:   getIntEnv("NORTHWIND_POLL_INTERVAL_SECONDS", 10),
-		MaxRetries:            getIntEnv("NORTHWIND_MAX_RETRIES", 3),
-		RetryInitialBackoffMs: getIntEnv("NORTHWIND_RETRY_INITIAL_BACKOFF_MS", 500),
+		BaseURL:                getEnv("NORTHWIND_BASE_URL", "https://northwind.dev.array.io"),
+		APIKey:                 getEnv("NORTHWIND_API_KEY", ""),
+		APIKeySecondary:        getEnv("NORTHWIND_API_KEY_SECONDARY", ""),
+		PollIntervalSeconds:    getIntEnv("NORTHWIND_POLL_INTERVAL_SECONDS", 10),
+		Timeout:                getDurationEnv("NORTHWIND_TIMEOUT", 10*time.Second),
+		MaxRetries:             getIntEnv("NORTHWIND_MAX_RETRIES", 3),
+		RetryInitialBackoffMs:  getIntEnv("NORTHWIND_RETRY_INITIAL_BACKOFF_MS", 500),
+		RetryMaxDurationMs:     getIntEnv("NORTHWIND_RETRY_MAX_DURATION_MS", 30000),
+		DuplicateWindowSeconds: getIntEnv("NORTHWIND_DUPLICATE_WINDOW_SECONDS", 120),
+		MaxInFlightInitiations: getIntEnv("NORTHWIND_MAX_INFLIGHT_INITIATIONS", 50),
+		InitiationQueueSize:    getIntEnv("NORTHWIND_INITIATION_QUEUE_SIZE", 25),
+		ReceiptSigningKey:      getEnv("NORTHWIND_RECEIPT_SIGNING_KEY", ""),
+		CursorSigningKey:       getEnv("NORTHWIND_CURSOR_SIGNING_KEY", ""),
+		CursorTTL:              getDurationEnv("NORTHWIND_CURSOR_TTL", 24*time.Hour),
+		LegacyTransferResponse: getBoolEnv("NORTHWIND_LEGACY_TRANSFER_RESPONSE", false),
+		StrictDecoding:         getBoolEnv("NORTHWIND_STRICT_DECODING", config.Server.Environment == "staging"),
+		PollingProfiles: map[string]PollingProfile{
+			// RTP settles in seconds, WIRE within hours, ACH over days
+			"RTP":  getPollingProfileEnv("NORTHWIND_POLL_PROFILE_RTP", PollingProfile{InitialDelay: 5 * time.Second, MinInterval: 5 * time.Second, MaxInterval: 30 * time.Second}),
+			"WIRE": getPollingProfileEnv("NORTHWIND_POLL_PROFILE_WIRE", PollingProfile{InitialDelay: time.Minute, MinInterval: time.Minute, MaxInterval: 15 * time.Minute}),
+			"ACH":  getPollingProfileEnv("NORTHWIND_POLL_PROFILE_ACH", PollingProfile{InitialDelay: 10 * time.Minute, MinInterval: 10 * time.Minute, MaxInterval: time.Hour}),
+		},
+		PollPriorityShare:            getFloatEnv("NORTHWIND_POLL_PRIORITY_SHARE", 0.3),
+		PollPriorityWindow:           getDurationEnv("NORTHWIND_POLL_PRIORITY_WINDOW", 5*time.Minute),
+		PollAnomalyAlertThreshold:    getIntEnv("NORTHWIND_POLL_ANOMALY_ALERT_THRESHOLD", 10),
+		AccountValidationCacheTTL:    getDurationEnv("NORTHWIND_ACCOUNT_VALIDATION_CACHE_TTL", 10*time.Minute),
+		NameMatchThreshold:           getFloatEnv("NORTHWIND_NAME_MATCH_THRESHOLD", 0.8),
+		AccountImportConcurrency:     getIntEnv("NORTHWIND_ACCOUNT_IMPORT_CONCURRENCY", 4),
+		AccountImportRate:            getFloatEnv("NORTHWIND_ACCOUNT_IMPORT_RATE", 10),
+		WebhookSecret:                getEnv("NORTHWIND_WEBHOOK_SECRET", ""),
+		WebhookEventRetention:        getDurationEnv("NORTHWIND_WEBHOOK_EVENT_RETENTION", 30*24*time.Hour),
+		MaintenanceStart:             getTimeEnv("NORTHWIND_MAINTENANCE_START"),
+		MaintenanceEnd:               getTimeEnv("NORTHWIND_MAINTENANCE_END"),
+		SupportedCurrencies:          getListEnv("NORTHWIND_SUPPORTED_CURRENCIES"),
+		AdminOnlyTransferTypes:       getListEnv("NORTHWIND_ADMIN_ONLY_TRANSFER_TYPES"),
+		ReferenceNumberPattern:       getEnv("NORTHWIND_REFERENCE_NUMBER_PATTERN", DefaultReferenceNumberPattern),
+		CancellationWindows:          map[string]CancellationWindow{},
+		SameDayCutoff:                getEnv("NORTHWIND_SAME_DAY_CUTOFF", "14:45"),
+		SameDayFee:                   getFloatEnv("NORTHWIND_SAME_DAY_FEE", 5),
+		BankTimezone:                 getEnv("NORTHWIND_BANK_TIMEZONE", "America/New_York"),
+		BalanceAlertInterval:         getDurationEnv("NORTHWIND_BALANCE_ALERT_INTERVAL", 24*time.Hour),
+		BalanceAlertFrequentInterval: getDurationEnv("NORTHWIND_BALANCE_ALERT_FREQUENT_INTERVAL", 15*time.Minute),
+		BalanceAlertCooldown:         getDurationEnv("NORTHWIND_BALANCE_ALERT_COOLDOWN", 24*time.Hour),
+		ErrorCodeOverrides:           getEnv("NORTHWIND_ERROR_CODES", ""),
+		DisputeWindow:                getDurationEnv("NORTHWIND_DISPUTE_WINDOW", 60*24*time.Hour),
+	}
+	// ACH can be recalled until it is processed, wires become irrevocable within minutes, and RTP
+	// is left to NorthWind
+	for transferType, defaultValue := range map[string]string{"ACH": cancelWindowUntilProcessing, "WIRE": "15m", "RTP": ""} {
+		if window, ok := getCancellationWindowEnv("NORTHWIND_CANCEL_WINDOW_"+transferType, defaultValue); ok {
+			config.NorthWind.CancellationWindows[transferType] = window
+		}
 	}
 
 	config.Regulator = RegulatorConfig{
 		WebhookURL:          getEnv("REGULATOR_WEBHOOK_URL", "http://regulator:9000/webhook"),
 		RetryInitialSeconds: getIntEnv("REGULATOR_RETRY_INITIAL_SECONDS", 2),
 		RetryMaxSeconds:     getIntEnv("REGULATOR_RETRY_MAX_SECONDS", 60),
+		PayloadFormat:       getEnv("REGULATOR_PAYLOAD_FORMAT", "flat"),
+		SigningSecret:       getEnv("REGULATOR_SIGNING_SECRET", ""),
+	}
+
+	config.Redis = RedisConfig{
+		Addr:             getEnv("REDIS_ADDR", ""),
+		Password:         getEnv("REDIS_PASSWORD", ""),
+		DB:               getIntEnv("REDIS_DB", 0),
+		PoolSize:         getIntEnv("REDIS_POOL_SIZE", 20),
+		MinIdleConns:     getIntEnv("REDIS_MIN_IDLE_CONNS", 2),
+		DialTimeout:      getDurationEnv("REDIS_DIAL_TIMEOUT", 2*time.Second),
+		OperationTimeout: getDurationEnv("REDIS_OPERATION_TIMEOUT", 500*time.Millisecond),
+	}
+
+	config.Encryption = EncryptionConfig{
+		Keys:          getEnv("FIELD_ENCRYPTION_KEYS", ""),
+		ActiveKeyID:   getEnv("FIELD_ENCRYPTION_ACTIVE_KEY_ID", ""),
+		BlindIndexKey: getEnv("FIELD_ENCRYPTION_BLIND_INDEX_KEY", ""),
+	}
+
+	config.FeatureFlags = FeatureFlagConfig{
+		Rollouts: getEnv("FEATURE_FLAGS", ""),
+	}
+
+	config.Risk = RiskConfig{
+		RuleModes:            getEnv("RISK_RULE_MODES", ""),
+		VelocityWindow:       getDurationEnv("RISK_VELOCITY_WINDOW", time.Hour),
+		VelocityMaxTransfers: getIntEnv("RISK_VELOCITY_MAX_TRANSFERS", 10),
+		VelocityMaxAmount:    getFloatEnv("RISK_VELOCITY_MAX_AMOUNT", 50000),
+		LargeTransferAmount:  getFloatEnv("RISK_LARGE_TRANSFER_AMOUNT", 25000),
+	}
+
+	config.Retention = RetentionConfig{
+		TransferYears:    getIntEnv("RETENTION_TRANSFER_YEARS", 7),
+		TransactionYears: getIntEnv("RETENTION_TRANSACTION_YEARS", 7),
+		AuditLogYears:    getIntEnv("RETENTION_AUDIT_LOG_YEARS", 10),
+		DryRun:           getBoolEnv("RETENTION_DRY_RUN", true),
+		Interval:         getDurationEnv("RETENTION_INTERVAL", 24*time.Hour),
+		BatchSize:        getIntEnv("RETENTION_BATCH_SIZE", 1000),
+		MaxDuration:      getDurationEnv("RETENTION_MAX_DURATION", 10*time.Minute),
+	}
+
+	config.ReadOnly = ReadOnlyConfig{
+		Enabled: getBoolEnv("READ_ONLY_MODE", false),
+		Workers: getEnv("READ_ONLY_WORKERS", ""),
+	}
+
+	config.Canary = CanaryConfig{
+		Enabled:          getBoolEnv("CANARY_ENABLED", false),
+		AllowProduction:  getBoolEnv("CANARY_ALLOW_PRODUCTION", false),
+		Interval:         getDurationEnv("CANARY_INTERVAL", 6*time.Hour),
+		Timeout:          getDurationEnv("CANARY_TIMEOUT", 10*time.Minute),
+		FailureThreshold: getIntEnv("CANARY_FAILURE_THRESHOLD", 3),
+		TransferType:     getEnv("CANARY_TRANSFER_TYPE", "ACH"),
+		NotifyRegulator:  getBoolEnv("CANARY_NOTIFY_REGULATOR", false),
+		Source: CanaryAccount{
+			HolderName:    getEnv("CANARY_SOURCE_HOLDER_NAME", ""),
+			AccountNumber: getEnv("CANARY_SOURCE_ACCOUNT_NUMBER", ""),
+			RoutingNumber: getEnv("CANARY_SOURCE_ROUTING_NUMBER", ""),
+		},
+		Destination: CanaryAccount{
+			HolderName:    getEnv("CANARY_DESTINATION_HOLDER_NAME", ""),
+			AccountNumber: getEnv("CANARY_DESTINATION_ACCOUNT_NUMBER", ""),
+			RoutingNumber: getEnv("CANARY_DESTINATION_ROUTING_NUMBER", ""),
+		},
 	}
 
 	config.Server.CORSAllowOrigins = config.loadCORSAllowOrigins()
+	config.Server.RedactErrorDetails = getBoolEnv("SERVER_REDACT_ERROR_DETAILS", config.IsProduction())
+	config.Server.DefaultLocale = strings.ToLower(getEnv("SERVER_DEFAULT_LOCALE", "en"))
 
 	var loadJWTKeysErr error
 	config.JWT.PrivateKey, config.JWT.PublicKey, loadJWTKeysErr = config.loadJWTKeys()
@@ -145,16 +531,81 @@ func Load() *Config {
 		if config.Regulator.WebhookURL == "" {
 			log.Println("WARNING: REGULATOR_WEBHOOK_URL not set. Regulator notifications will not be delivered.")
 		}
+		if config.NorthWind.ReceiptSigningKey == "" {
+			log.Println("WARNING: NORTHWIND_RECEIPT_SIGNING_KEY not set. Transfer receipts will not verify after a restart.")
+		}
+		if config.NorthWind.CursorSigningKey == "" {
+			log.Println("WARNING: NORTHWIND_CURSOR_SIGNING_KEY not set. Transfer list cursors will not work across restarts or instances.")
+		}
 	}
 
 	return config
 }
 
+// Validate reports every setting that would prevent the server from talking to its
+// dependencies. Load only warns about these; Validate is used by the startup self-check.
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Server.Port == "" {
+		errs = append(errs, errors.New("SERVER_PORT is required"))
+	}
+	if c.Database.Host == "" || c.Database.Name == "" {
+		errs = append(errs, errors.New("DB_HOST and DB_NAME are required"))
+	}
+	if c.NorthWind.APIKey == "" {
+		errs = append(errs, errors.New("NORTHWIND_API_KEY is required"))
+	}
+	if err := validateHTTPURL(c.NorthWind.BaseURL); err != nil {
+		errs = append(errs, fmt.Errorf("NORTHWIND_BASE_URL: %w", err))
+	}
+	if err := validateHTTPURL(c.Regulator.WebhookURL); err != nil {
+		errs = append(errs, fmt.Errorf("REGULATOR_WEBHOOK_URL: %w", err))
+	}
+	if c.Encryption.Keys == "" || c.Encryption.ActiveKeyID == "" || c.Encryption.BlindIndexKey == "" {
+		errs = append(errs, errors.New("FIELD_ENCRYPTION_KEYS, FIELD_ENCRYPTION_ACTIVE_KEY_ID and FIELD_ENCRYPTION_BLIND_INDEX_KEY are required"))
+	}
+	if start, end := c.NorthWind.MaintenanceStart, c.NorthWind.MaintenanceEnd; (!start.IsZero() || !end.IsZero()) && !end.After(start) {
+		errs = append(errs, errors.New("NORTHWIND_MAINTENANCE_START and NORTHWIND_MAINTENANCE_END must both be set, with the end after the start"))
+	}
+	if _, err := regexp.Compile(c.NorthWind.ReferenceNumberPattern); err != nil {
+		errs = append(errs, fmt.Errorf("NORTHWIND_REFERENCE_NUMBER_PATTERN: %w", err))
+	}
+	if c.Regulator.RetryInitialSeconds <= 0 || c.Regulator.RetryMaxSeconds < c.Regulator.RetryInitialSeconds {
+		errs = append(errs, errors.New("REGULATOR_RETRY_INITIAL_SECONDS must be positive and not exceed REGULATOR_RETRY_MAX_SECONDS"))
+	}
+	if f := c.Regulator.PayloadFormat; f != "" && f != "flat" && f != "envelope" {
+		errs = append(errs, fmt.Errorf("REGULATOR_PAYLOAD_FORMAT must be flat or envelope, got %q", f))
+	}
+	if c.Canary.Enabled && (c.Canary.Source.AccountNumber == "" || c.Canary.Destination.AccountNumber == "") {
+		errs = append(errs, errors.New("CANARY_SOURCE_ACCOUNT_NUMBER and CANARY_DESTINATION_ACCOUNT_NUMBER are required when CANARY_ENABLED is set"))
+	}
+	if r := c.Retention; r.TransferYears < 1 || r.TransactionYears < 1 || r.AuditLogYears < 1 {
+		errs = append(errs, errors.New("RETENTION_TRANSFER_YEARS, RETENTION_TRANSACTION_YEARS and RETENTION_AUDIT_LOG_YEARS must be at least 1"))
+	}
+	return errors.Join(errs...)
+}
+
+func validateHTTPURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%q is not an absolute http(s) URL", raw)
+	}
+	return nil
+}
+
 func (c *DatabaseConfig) DSN() string {
 	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		c.Host, c.Port, c.User, c.Password, c.Name, c.SSLMode)
 }
 
+// Enabled reports whether a Redis address is configured
+func (c *RedisConfig) Enabled() bool {
+	return c.Addr != ""
+}
+
 func (c *Config) IsDevelopment() bool {
 	return c.Server.Environment == "development"
 }
@@ -183,6 +634,15 @@ func getIntEnv(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getFloatEnv(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatVal
+		}
+	}
+	return defaultValue
+}
+
 func getBoolEnv(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if boolVal, err := strconv.ParseBool(value); err == nil {
@@ -201,6 +661,97 @@ func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	return defaultValue
 }
 
+// getListEnv splits a comma-separated value, trimming whitespace and dropping empty entries
+func getListEnv(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getRoleScopesEnv reads a comma-separated scope list for each role from prefix plus the upper-cased
+// role, leaving out roles whose variable is unset. A variable set but empty grants no scopes.
+func getRoleScopesEnv(prefix string, roles ...string) map[string][]string {
+	scopes := make(map[string][]string)
+	for _, role := range roles {
+		key := prefix + strings.ToUpper(role)
+		if _, ok := os.LookupEnv(key); ok {
+			scopes[role] = getListEnv(key)
+		}
+	}
+	return scopes
+}
+
+// getTimeEnv parses an RFC 3339 timestamp, returning the zero time when unset or invalid
+func getTimeEnv(key string) time.Time {
+	if value := os.Getenv(key); value != "" {
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// getCancellationWindowEnv reads a cancellation window written as a duration after initiation or
+// "until_processing". It reports false for no window: an empty value or "none".
+func getCancellationWindowEnv(key, defaultValue string) (CancellationWindow, bool) {
+	window, ok, err := parseCancellationWindow(getEnv(key, defaultValue))
+	if err != nil {
+		log.Printf("WARNING: %s must be a positive duration, %q or \"none\"; using %q", key, cancelWindowUntilProcessing, defaultValue)
+		window, ok, _ = parseCancellationWindow(defaultValue)
+	}
+	return window, ok
+}
+
+func parseCancellationWindow(value string) (CancellationWindow, bool, error) {
+	switch value = strings.TrimSpace(value); value {
+	case "", "none":
+		return CancellationWindow{}, false, nil
+	case cancelWindowUntilProcessing:
+		return CancellationWindow{UntilProcessingDate: true}, true, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return CancellationWindow{}, false, err
+	}
+	if d <= 0 {
+		return CancellationWindow{}, false, fmt.Errorf("cancellation window must be positive, got %s", d)
+	}
+	return CancellationWindow{After: d}, true, nil
+}
+
+// getPollingProfileEnv parses a polling profile written as "initial_delay,min_interval,max_interval",
+// e.g. "5s,5s,30s", falling back to defaultValue when unset or invalid
+func getPollingProfileEnv(key string, defaultValue PollingProfile) PollingProfile {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parts := strings.Split(value, ",")
+	if len(parts) != 3 {
+		log.Printf("WARNING: %s must be initial_delay,min_interval,max_interval; using defaults", key)
+		return defaultValue
+	}
+	var durations [3]time.Duration
+	for i, part := range parts {
+		d, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil {
+			log.Printf("WARNING: %s: %v; using defaults", key, err)
+			return defaultValue
+		}
+		durations[i] = d
+	}
+	profile := PollingProfile{InitialDelay: durations[0], MinInterval: durations[1], MaxInterval: durations[2]}
+	if !profile.Valid() {
+		log.Printf("WARNING: %s: min_interval must not exceed max_interval; using defaults", key)
+		return defaultValue
+	}
+	return profile
+}
+
 // loadJWTKeys loads RSA keys for JWT signing and verification
 // Priority order:
 // 1. If JWT_PRIVATE_KEY and JWT_PUBLIC_KEY env vars are set, use them (works in all environments)
@@ -277,3 +277,118 @@ func Test_loadRSAPublicKey_NotRSA(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "not an RSA")
 }
+
+func TestConfig_Validate(t *testing.T) {
+	valid := func() *Config {
+		c := &Config{}
+		c.Server.Port = "8080"
+		c.Database.Host = "localhost"
+		c.Database.Name = "banking_db"
+		c.NorthWind.APIKey = "key"
+		c.NorthWind.BaseURL = "https://northwind.example.com"
+		c.Regulator.WebhookURL = "http://regulator:9000/webhook"
+		c.Regulator.RetryInitialSeconds = 2
+		c.Regulator.RetryMaxSeconds = 60
+		c.Encryption.Keys = "k1:AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE="
+		c.Encryption.ActiveKeyID = "k1"
+		c.Encryption.BlindIndexKey = "AgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgI="
+		c.Retention = RetentionConfig{TransferYears: 7, TransactionYears: 7, AuditLogYears: 10}
+		return c
+	}
+
+	require.NoError(t, valid().Validate())
+
+	tests := []struct {
+		name   string
+		mutate func(*Config)
+		substr string
+	}{
+		{"missing api key", func(c *Config) { c.NorthWind.APIKey = "" }, "NORTHWIND_API_KEY"},
+		{"relative northwind url", func(c *Config) { c.NorthWind.BaseURL = "northwind" }, "NORTHWIND_BASE_URL"},
+		{"bad regulator scheme", func(c *Config) { c.Regulator.WebhookURL = "ftp://regulator/webhook" }, "REGULATOR_WEBHOOK_URL"},
+		{"missing db host", func(c *Config) { c.Database.Host = "" }, "DB_HOST"},
+		{"inverted retry bounds", func(c *Config) { c.Regulator.RetryMaxSeconds = 1 }, "REGULATOR_RETRY"},
+		{"unknown payload format", func(c *Config) { c.Regulator.PayloadFormat = "xml" }, "REGULATOR_PAYLOAD_FORMAT"},
+		{"missing encryption keys", func(c *Config) { c.Encryption.Keys = "" }, "FIELD_ENCRYPTION_KEYS"},
+		{"zero retention", func(c *Config) { c.Retention.AuditLogYears = 0 }, "RETENTION_AUDIT_LOG_YEARS"},
+		{"invalid reference pattern", func(c *Config) { c.NorthWind.ReferenceNumberPattern = "([" }, "NORTHWIND_REFERENCE_NUMBER_PATTERN"},
+		{"maintenance without end", func(c *Config) { c.NorthWind.MaintenanceStart = time.Now() }, "NORTHWIND_MAINTENANCE_END"},
+		{"inverted maintenance window", func(c *Config) {
+			c.NorthWind.MaintenanceStart = time.Now()
+			c.NorthWind.MaintenanceEnd = c.NorthWind.MaintenanceStart.Add(-time.Hour)
+		}, "NORTHWIND_MAINTENANCE_START"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid()
+			tt.mutate(c)
+			err := c.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.substr)
+		})
+	}
+}
+
+func TestLoad_PollingProfiles(t *testing.T) {
+	t.Setenv("APP_ENV", "testing")
+	t.Setenv("NORTHWIND_POLL_PROFILE_RTP", "1s, 2s, 10s")
+	t.Setenv("NORTHWIND_POLL_PROFILE_WIRE", "1m,10m,5m")
+	t.Setenv("NORTHWIND_POLL_PROFILE_ACH", "notaprofile")
+
+	cfg := Load()
+	assert.Equal(t, PollingProfile{InitialDelay: time.Second, MinInterval: 2 * time.Second, MaxInterval: 10 * time.Second}, cfg.NorthWind.PollingProfiles["RTP"])
+	// min above max and unparseable values fall back to the defaults
+	assert.Equal(t, time.Minute, cfg.NorthWind.PollingProfiles["WIRE"].MinInterval)
+	assert.Equal(t, 10*time.Minute, cfg.NorthWind.PollingProfiles["ACH"].MinInterval)
+}
+
+func TestLoad_JWTRoleScopes(t *testing.T) {
+	t.Setenv("APP_ENV", "testing")
+	assert.Empty(t, Load().JWT.RoleScopes, "unset roles keep their default scopes")
+
+	t.Setenv("JWT_ROLE_SCOPES_CUSTOMER", " transfers:create, transfers:cancel ,")
+	t.Setenv("JWT_ROLE_SCOPES_ADMIN", "")
+	scopes := Load().JWT.RoleScopes
+	assert.Equal(t, []string{"transfers:create", "transfers:cancel"}, scopes["customer"])
+	admin, ok := scopes["admin"]
+	assert.True(t, ok)
+	assert.Empty(t, admin)
+}
+
+func TestLoad_RedactErrorDetails(t *testing.T) {
+	t.Setenv("APP_ENV", "testing")
+	assert.False(t, Load().Server.RedactErrorDetails, "details are only redacted by default in production")
+
+	t.Setenv("SERVER_REDACT_ERROR_DETAILS", "true")
+	assert.True(t, Load().Server.RedactErrorDetails)
+}
+
+func TestLoad_TransferValidationLists(t *testing.T) {
+	t.Setenv("APP_ENV", "testing")
+	t.Setenv("NORTHWIND_SUPPORTED_CURRENCIES", " USD, EUR ,,")
+
+	cfg := Load()
+	assert.Equal(t, []string{"USD", "EUR"}, cfg.NorthWind.SupportedCurrencies)
+	assert.Empty(t, cfg.NorthWind.AdminOnlyTransferTypes)
+}
+
+func TestLoad_NorthwindStrictDecoding(t *testing.T) {
+	t.Setenv("APP_ENV", "testing")
+	assert.False(t, Load().NorthWind.StrictDecoding, "strict decoding is only on by default in staging")
+
+	t.Setenv("NORTHWIND_STRICT_DECODING", "true")
+	assert.True(t, Load().NorthWind.StrictDecoding)
+}
+
+func TestLoad_CancellationWindows(t *testing.T) {
+	t.Setenv("APP_ENV", "testing")
+	t.Setenv("NORTHWIND_CANCEL_WINDOW_WIRE", "30m")
+	t.Setenv("NORTHWIND_CANCEL_WINDOW_ACH", "-5m")
+	t.Setenv("NORTHWIND_CANCEL_WINDOW_RTP", "none")
+
+	cfg := Load()
+	assert.Equal(t, CancellationWindow{After: 30 * time.Minute}, cfg.NorthWind.CancellationWindows["WIRE"])
+	// invalid values fall back to the default
+	assert.Equal(t, CancellationWindow{UntilProcessingDate: true}, cfg.NorthWind.CancellationWindows["ACH"])
+	assert.NotContains(t, cfg.NorthWind.CancellationWindows, "RTP")
+}
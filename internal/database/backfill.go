@@ -0,0 +1,163 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Backfill fills a newly added column for rows that existed before it, in batches small enough
+// to avoid long locks on big tables. Progress is recorded in backfill_progress after every
+// batch, so an interrupted run resumes after the last completed batch.
+type Backfill struct {
+	// ID keys the backfill's progress record; never reuse one for a different backfill
+	ID string
+	// Table is scanned in id order
+	Table string
+	// Pending is the SQL condition selecting rows that still need the backfill
+	Pending string
+	// Values returns the column values written to the pending rows of one batch
+	Values func() map[string]interface{}
+	// Update, when set, replaces Values for backfills whose values differ per row. It updates
+	// the rows with the given ids inside the batch's transaction.
+	Update func(tx *gorm.DB, ids []string) error
+}
+
+// BackfillProgress records how far a backfill has got. LastKey is the id of the last row in the
+// last completed batch.
+type BackfillProgress struct {
+	ID            string     `gorm:"type:text;primary_key" json:"id"`
+	LastKey       string     `gorm:"type:text;not null;default:''" json:"last_key"`
+	RowsProcessed int64      `gorm:"not null;default:0" json:"rows_processed"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+	UpdatedAt     time.Time  `gorm:"not null" json:"updated_at"`
+}
+
+// TableName returns the table name for BackfillProgress
+func (p *BackfillProgress) TableName() string {
+	return "backfill_progress"
+}
+
+var registeredBackfills = []Backfill{
+	northwindTransferVersionBackfill,
+	northwindTransferNextPollAtBackfill,
+	northwindTransferExternalRefBackfill,
+	regulatorNotificationEventIDBackfill,
+	northwindTransferRecoveredErrorBackfill,
+}
+
+// RegisterBackfill adds a backfill to those run by RunBackfills. It panics on a duplicate ID.
+func RegisterBackfill(b Backfill) {
+	for _, existing := range registeredBackfills {
+		if existing.ID == b.ID {
+			panic(fmt.Sprintf("backfill %q registered twice", b.ID))
+		}
+	}
+	registeredBackfills = append(registeredBackfills, b)
+}
+
+// RegisteredBackfills returns the registered backfills in the order they run
+func RegisteredBackfills() []Backfill {
+	return append([]Backfill(nil), registeredBackfills...)
+}
+
+// RunBackfills runs the registered backfills with the given IDs, or all of them when none are
+// given, stopping at the first failure. Completed backfills are skipped.
+func RunBackfills(ctx context.Context, db *gorm.DB, batchSize int, logger *slog.Logger, ids ...string) error {
+	selected := registeredBackfills
+	if len(ids) > 0 {
+		selected = nil
+		for _, id := range ids {
+			b, ok := findBackfill(id)
+			if !ok {
+				return fmt.Errorf("unknown backfill %q", id)
+			}
+			selected = append(selected, b)
+		}
+	}
+	for _, b := range selected {
+		if err := b.Run(ctx, db, batchSize, logger); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func findBackfill(id string) (Backfill, bool) {
+	for _, b := range registeredBackfills {
+		if b.ID == id {
+			return b, true
+		}
+	}
+	return Backfill{}, false
+}
+
+// Run applies the backfill batchSize rows at a time, resuming from its recorded progress. Each
+// batch and its progress update commit together, so a batch is never applied twice.
+func (b Backfill) Run(ctx context.Context, db *gorm.DB, batchSize int, logger *slog.Logger) error {
+	if batchSize <= 0 {
+		batchSize = DefaultBackfillBatchSize
+	}
+	db = db.WithContext(ctx)
+
+	progress := BackfillProgress{ID: b.ID}
+	if err := db.Where("id = ?", b.ID).Attrs(BackfillProgress{UpdatedAt: time.Now()}).FirstOrCreate(&progress).Error; err != nil {
+		return fmt.Errorf("backfill %s: failed to load progress: %w", b.ID, err)
+	}
+	if progress.CompletedAt != nil {
+		logger.Info("Backfill already completed", "backfill", b.ID, "rows_processed", progress.RowsProcessed)
+		return nil
+	}
+	logger.Info("Backfill starting", "backfill", b.ID, "resume_after", progress.LastKey, "rows_processed", progress.RowsProcessed)
+
+	for {
+		query := db.Table(b.Table).Where("(" + b.Pending + ")")
+		if progress.LastKey != "" {
+			query = query.Where("id > ?", progress.LastKey)
+		}
+		var ids []string
+		if err := query.Order("id").Limit(batchSize).Pluck("id", &ids).Error; err != nil {
+			return fmt.Errorf("backfill %s: failed to select batch: %w", b.ID, err)
+		}
+
+		next := progress
+		next.UpdatedAt = time.Now()
+		if len(ids) == 0 {
+			next.CompletedAt = &next.UpdatedAt
+			if err := db.Save(&next).Error; err != nil {
+				return fmt.Errorf("backfill %s: failed to record completion: %w", b.ID, err)
+			}
+			logger.Info("Backfill completed", "backfill", b.ID, "rows_processed", next.RowsProcessed)
+			return nil
+		}
+
+		next.LastKey = ids[len(ids)-1]
+		next.RowsProcessed += int64(len(ids))
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := b.apply(tx, ids); err != nil {
+				return err
+			}
+			return tx.Save(&next).Error
+		})
+		if err != nil {
+			return fmt.Errorf("backfill %s: batch after %q failed: %w", b.ID, progress.LastKey, err)
+		}
+		progress = next
+		logger.Info("Backfill batch completed", "backfill", b.ID, "batch_rows", len(ids), "rows_processed", progress.RowsProcessed)
+
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("backfill %s: %w", b.ID, err)
+		}
+	}
+}
+
+// apply writes the backfill to the rows of one batch
+func (b Backfill) apply(tx *gorm.DB, ids []string) error {
+	if b.Update != nil {
+		return b.Update(tx, ids)
+	}
+	return tx.Table(b.Table).Where("id IN ?", ids).Updates(b.Values()).Error
+}
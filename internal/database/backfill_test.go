@@ -0,0 +1,202 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+const backfillTestRows = 10000
+
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// seedBackfillRows inserts n transfers, every tenth one COMPLETED, as if they predated the
+// version and next_poll_at columns. Rows are written as maps so model hooks do not fill either.
+func seedBackfillRows(t *testing.T, db *gorm.DB, n int) {
+	t.Helper()
+	require.NoError(t, db.AutoMigrate(&models.NorthwindTransfer{}, &BackfillProgress{}))
+
+	now := time.Now()
+	rows := make([]map[string]interface{}, n)
+	for i := range rows {
+		status := models.NWTransferStatusPending
+		if i%10 == 0 {
+			status = models.NWTransferStatusCompleted
+		}
+		rows[i] = map[string]interface{}{
+			"id":                         uuid.New().String(),
+			"northwind_transfer_id":      uuid.New().String(),
+			"direction":                  models.NWTransferDirectionOutbound,
+			"transfer_type":              "ACH",
+			"amount":                     "10",
+			"currency":                   "USD",
+			"reference_number":           fmt.Sprintf("REF-%05d", i),
+			"source_account_number":      "1111111111",
+			"destination_account_number": "2222222222",
+			"status":                     status,
+			"version":                    0,
+			"created_at":                 now,
+			"updated_at":                 now,
+		}
+	}
+	require.NoError(t, db.Table("northwind_transfers").CreateInBatches(rows, 500).Error)
+}
+
+func countRows(t *testing.T, db *gorm.DB, where string) int64 {
+	t.Helper()
+	var n int64
+	require.NoError(t, db.Table("northwind_transfers").Where(where).Count(&n).Error)
+	return n
+}
+
+func loadProgress(t *testing.T, db *gorm.DB, id string) BackfillProgress {
+	t.Helper()
+	var progress BackfillProgress
+	require.NoError(t, db.Where("id = ?", id).First(&progress).Error)
+	return progress
+}
+
+func TestBackfill_RunsInBatches(t *testing.T) {
+	db := SetupTestDB(t)
+	defer CleanupTestDB(t, db)
+	seedBackfillRows(t, db.DB, backfillTestRows)
+
+	batches := 0
+	task := northwindTransferVersionBackfill
+	task.Values = func() map[string]interface{} {
+		batches++
+		return northwindTransferVersionBackfill.Values()
+	}
+	require.NoError(t, task.Run(context.Background(), db.DB, 500, discardLogger))
+
+	assert.Equal(t, backfillTestRows/500, batches)
+	assert.Zero(t, countRows(t, db.DB, "version <> 1"))
+	progress := loadProgress(t, db.DB, task.ID)
+	assert.Equal(t, int64(backfillTestRows), progress.RowsProcessed)
+	assert.NotNil(t, progress.CompletedAt)
+
+	// A completed backfill is not run again
+	batches = 0
+	require.NoError(t, task.Run(context.Background(), db.DB, 500, discardLogger))
+	assert.Zero(t, batches)
+}
+
+func TestBackfill_ResumesAfterInterruption(t *testing.T) {
+	db := SetupTestDB(t)
+	defer CleanupTestDB(t, db)
+	seedBackfillRows(t, db.DB, backfillTestRows)
+	pending := countRows(t, db.DB, "next_poll_at IS NULL AND status = 'PENDING'")
+
+	// Cancel while the fourth batch is being written, as if the process were stopped mid-run
+	ctx, cancel := context.WithCancel(context.Background())
+	batches := 0
+	interrupted := northwindTransferNextPollAtBackfill
+	interrupted.Values = func() map[string]interface{} {
+		batches++
+		if batches == 4 {
+			cancel()
+		}
+		return northwindTransferNextPollAtBackfill.Values()
+	}
+	require.Error(t, interrupted.Run(ctx, db.DB, 500, discardLogger))
+
+	progress := loadProgress(t, db.DB, interrupted.ID)
+	assert.Equal(t, int64(3*500), progress.RowsProcessed)
+	assert.Nil(t, progress.CompletedAt)
+	assert.Equal(t, int64(3*500), countRows(t, db.DB, "next_poll_at IS NOT NULL"), "rolled-back batch left rows behind")
+
+	require.NoError(t, RunBackfills(context.Background(), db.DB, 500, discardLogger, interrupted.ID))
+
+	progress = loadProgress(t, db.DB, interrupted.ID)
+	assert.Equal(t, pending, progress.RowsProcessed, "rows were processed twice or skipped")
+	assert.NotNil(t, progress.CompletedAt)
+	assert.Zero(t, countRows(t, db.DB, "next_poll_at IS NULL AND status = 'PENDING'"))
+	assert.Zero(t, countRows(t, db.DB, "next_poll_at IS NOT NULL AND status = 'COMPLETED'"))
+}
+
+func TestRunBackfills_UnknownID(t *testing.T) {
+	db := SetupTestDB(t)
+	defer CleanupTestDB(t, db)
+
+	assert.Error(t, RunBackfills(context.Background(), db.DB, 500, discardLogger, "no_such_backfill"))
+}
+
+func TestBackfill_ExternalRefCopiesNorthwindTransferID(t *testing.T) {
+	db := SetupTestDB(t)
+	defer CleanupTestDB(t, db)
+	seedBackfillRows(t, db.DB, 1200)
+
+	require.NoError(t, RunBackfills(context.Background(), db.DB, 500, discardLogger, northwindTransferExternalRefBackfill.ID))
+
+	assert.Zero(t, countRows(t, db.DB, "external_ref IS NULL"))
+	assert.Zero(t, countRows(t, db.DB, "external_ref <> CAST(northwind_transfer_id AS TEXT)"))
+}
+
+func TestBackfill_RegulatorNotificationEventIDFromPayload(t *testing.T) {
+	db := SetupTestDB(t)
+	defer CleanupTestDB(t, db)
+	require.NoError(t, db.DB.AutoMigrate(&models.RegulatorNotification{}, &BackfillProgress{}))
+
+	// Rows are written as maps so the model hook does not fill event_id
+	now := time.Now()
+	payloads := map[string]string{
+		uuid.New().String(): `{"event_id":"evt-1","status":"COMPLETED"}`,
+		uuid.New().String(): `{"event_id":"evt-2","status":"FAILED"}`,
+		uuid.New().String(): `{"status":"COMPLETED"}`,
+	}
+	for id, payload := range payloads {
+		require.NoError(t, db.DB.Table("regulator_notifications").Create(map[string]interface{}{
+			"id":              id,
+			"transfer_id":     uuid.New().String(),
+			"terminal_status": models.NWTransferStatusCompleted,
+			"payload":         []byte(payload),
+			"created_at":      now,
+			"updated_at":      now,
+		}).Error)
+	}
+
+	require.NoError(t, RunBackfills(context.Background(), db.DB, 2, discardLogger, regulatorNotificationEventIDBackfill.ID))
+
+	for id, payload := range payloads {
+		var eventID *string
+		require.NoError(t, db.DB.Table("regulator_notifications").Select("event_id").Where("id = ?", id).Row().Scan(&eventID))
+		want := models.EventIDFromPayload(json.RawMessage(payload))
+		if want == "" {
+			assert.Nil(t, eventID, "payload without event_id")
+			continue
+		}
+		require.NotNil(t, eventID)
+		assert.Equal(t, want, *eventID)
+	}
+	assert.NotNil(t, loadProgress(t, db.DB, regulatorNotificationEventIDBackfill.ID).CompletedAt)
+}
+
+func TestBackfill_RecoveredErrorClearsCompletedTransfers(t *testing.T) {
+	db := SetupTestDB(t)
+	defer CleanupTestDB(t, db)
+	seedBackfillRows(t, db.DB, 100)
+	// Every transfer carries an error; only the COMPLETED ones have recovered from it
+	require.NoError(t, db.DB.Table("northwind_transfers").Where("1 = 1").Updates(map[string]interface{}{
+		"error_code":        "R01",
+		"error_message":     "insufficient funds",
+		"failure_category":  models.NWFailureCategoryFunds,
+		"failure_retryable": false,
+	}).Error)
+
+	require.NoError(t, RunBackfills(context.Background(), db.DB, 4, discardLogger, northwindTransferRecoveredErrorBackfill.ID))
+
+	completed := fmt.Sprintf("status = '%s'", models.NWTransferStatusCompleted)
+	assert.Equal(t, int64(10), countRows(t, db.DB, completed+" AND error_code IS NULL AND error_message IS NULL AND failure_category IS NULL AND failure_retryable IS NULL"))
+	assert.Equal(t, int64(90), countRows(t, db.DB, "status <> '"+models.NWTransferStatusCompleted+"' AND error_code = 'R01'"))
+	assert.NotNil(t, loadProgress(t, db.DB, northwindTransferRecoveredErrorBackfill.ID).CompletedAt)
+}
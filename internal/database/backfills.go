@@ -0,0 +1,82 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// northwindTransferVersionBackfill gives transfers created before the version column a starting
+// version, for databases where the column was added without its default
+var northwindTransferVersionBackfill = Backfill{
+	ID:      "northwind_transfers.version",
+	Table:   "northwind_transfers",
+	Pending: "version IS NULL OR version < 1",
+	Values: func() map[string]interface{} {
+		return map[string]interface{}{"version": 1}
+	},
+}
+
+// northwindTransferNextPollAtBackfill schedules in-flight transfers created before next_poll_at
+// for an immediate poll
+var northwindTransferNextPollAtBackfill = Backfill{
+	ID:      "northwind_transfers.next_poll_at",
+	Table:   "northwind_transfers",
+	Pending: fmt.Sprintf("next_poll_at IS NULL AND status IN ('%s', '%s')", models.NWTransferStatusPending, models.NWTransferStatusProcessing),
+	Values: func() map[string]interface{} {
+		return map[string]interface{}{"next_poll_at": time.Now()}
+	},
+}
+
+// northwindTransferExternalRefBackfill copies NorthWind's transfer ID into external_ref for
+// transfers created before the column, so lookups by upstream ID find them
+var northwindTransferExternalRefBackfill = Backfill{
+	ID:      "northwind_transfers.external_ref",
+	Table:   "northwind_transfers",
+	Pending: "external_ref IS NULL",
+	Values: func() map[string]interface{} {
+		return map[string]interface{}{"external_ref": gorm.Expr("CAST(northwind_transfer_id AS TEXT)")}
+	},
+}
+
+// northwindTransferRecoveredErrorBackfill clears the error code, message and failure
+// classification of COMPLETED transfers, which kept them from an earlier failure before recovered
+// transfers had their errors cleared
+var northwindTransferRecoveredErrorBackfill = Backfill{
+	ID:    "northwind_transfers.recovered_error",
+	Table: "northwind_transfers",
+	Pending: fmt.Sprintf("status = '%s' AND (error_code IS NOT NULL OR error_message IS NOT NULL OR failure_category IS NOT NULL OR failure_retryable IS NOT NULL)",
+		models.NWTransferStatusCompleted),
+	Values: func() map[string]interface{} {
+		return map[string]interface{}{"error_code": nil, "error_message": nil, "failure_category": nil, "failure_retryable": nil}
+	},
+}
+
+// regulatorNotificationEventIDBackfill copies each payload's event_id into the event_id column for
+// notifications created before it, so they can be found by the regulator's event ID
+var regulatorNotificationEventIDBackfill = Backfill{
+	ID:      "regulator_notifications.event_id",
+	Table:   "regulator_notifications",
+	Pending: "event_id IS NULL",
+	Update: func(tx *gorm.DB, ids []string) error {
+		var rows []struct {
+			ID      string
+			Payload []byte
+		}
+		if err := tx.Table("regulator_notifications").Select("id", "payload").Where("id IN ?", ids).Find(&rows).Error; err != nil {
+			return err
+		}
+		for _, row := range rows {
+			eventID := models.EventIDFromPayload(row.Payload)
+			if eventID == "" {
+				continue
+			}
+			if err := tx.Table("regulator_notifications").Where("id = ?", row.ID).Update("event_id", eventID).Error; err != nil {
+				return fmt.Errorf("notification %s: %w", row.ID, err)
+			}
+		}
+		return nil
+	},
+}
@@ -133,20 +133,6 @@ func (db *DB) CreateIndexes() error {
 	return nil
 }
 
-func (db *DB) CleanupExpiredTokens() error {
-	now := time.Now()
-
-	if err := db.DB.Where("expires_at < ?", now).Delete(&models.RefreshToken{}).Error; err != nil {
-		return fmt.Errorf("failed to cleanup expired refresh tokens: %w", err)
-	}
-
-	if err := db.DB.Where("expires_at < ?", now).Delete(&models.BlacklistedToken{}).Error; err != nil {
-		return fmt.Errorf("failed to cleanup expired blacklisted tokens: %w", err)
-	}
-
-	return nil
-}
-
 func (db *DB) SeedAdminUser(email, password, firstName, lastName string) (*models.User, error) {
 	var existingUser models.User
 	if err := db.DB.Where("email = ?", email).First(&existingUser).Error; err == nil {
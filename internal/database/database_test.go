@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -72,8 +73,9 @@ func TestDB_CleanupExpiredTokens(t *testing.T) {
 	}
 	require.NoError(t, db.Create(bt).Error)
 
-	err := db.CleanupExpiredTokens()
+	result, err := db.CleanupExpiredTokens(context.Background(), TokenCleanupOptions{})
 	require.NoError(t, err)
+	assert.Equal(t, TokenCleanupResult{RefreshTokens: 1, BlacklistedTokens: 1}, result)
 
 	var refreshCount, blacklistCount int64
 	require.NoError(t, db.Model(&models.RefreshToken{}).Where("token_hash = ?", "expired_hash").Count(&refreshCount).Error)
@@ -0,0 +1,134 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/array/banking-api/internal/fieldcrypt"
+	"gorm.io/gorm"
+)
+
+// DefaultBackfillBatchSize is the number of rows read per batch by BackfillEncryptedColumns
+const DefaultBackfillBatchSize = 500
+
+// encryptedColumn describes a column sealed by the fieldcrypt serializer and its optional
+// blind index column
+type encryptedColumn struct {
+	table      string
+	column     string
+	bidxColumn string
+}
+
+// encryptedColumns lists every column tagged `serializer:encrypted` in the models
+var encryptedColumns = []encryptedColumn{
+	{table: "northwind_external_accounts", column: "account_number", bidxColumn: "account_number_bidx"},
+	{table: "northwind_transfers", column: "source_account_number", bidxColumn: "source_account_number_bidx"},
+	{table: "northwind_transfers", column: "destination_account_number", bidxColumn: "destination_account_number_bidx"},
+}
+
+// BackfillResult reports how many rows of one column were scanned and rewritten
+type BackfillResult struct {
+	Table   string `json:"table"`
+	Column  string `json:"column"`
+	Scanned int    `json:"scanned"`
+	Updated int    `json:"updated"`
+}
+
+type backfillRow struct {
+	ID    string
+	Value *string
+	Bidx  *string
+}
+
+// BackfillEncryptedColumns encrypts legacy plaintext values and re-encrypts values sealed with a
+// non-active key, filling blind indexes as it goes. It is idempotent and safe to run while the
+// API is serving traffic: each row is updated only if its stored value is unchanged since it
+// was read.
+func BackfillEncryptedColumns(db *gorm.DB, keyring *fieldcrypt.Keyring, batchSize int) ([]BackfillResult, error) {
+	if batchSize <= 0 {
+		batchSize = DefaultBackfillBatchSize
+	}
+
+	results := make([]BackfillResult, 0, len(encryptedColumns))
+	for _, col := range encryptedColumns {
+		result, err := backfillColumn(db, keyring, col, batchSize)
+		if err != nil {
+			return results, fmt.Errorf("backfill %s.%s: %w", col.table, col.column, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func backfillColumn(db *gorm.DB, keyring *fieldcrypt.Keyring, col encryptedColumn, batchSize int) (BackfillResult, error) {
+	result := BackfillResult{Table: col.table, Column: col.column}
+
+	selectCols := fmt.Sprintf("id, %s AS value", col.column)
+	if col.bidxColumn != "" {
+		selectCols += fmt.Sprintf(", %s AS bidx", col.bidxColumn)
+	}
+
+	cursor := ""
+	for {
+		query := db.Table(col.table).Select(selectCols).Order("id").Limit(batchSize)
+		if cursor != "" {
+			query = query.Where("id > ?", cursor)
+		}
+		var rows []backfillRow
+		if err := query.Scan(&rows).Error; err != nil {
+			return result, err
+		}
+
+		for _, row := range rows {
+			result.Scanned++
+			updated, err := backfillRowValue(db, keyring, col, row)
+			if err != nil {
+				return result, fmt.Errorf("row %s: %w", row.ID, err)
+			}
+			if updated {
+				result.Updated++
+			}
+		}
+
+		if len(rows) < batchSize {
+			return result, nil
+		}
+		cursor = rows[len(rows)-1].ID
+	}
+}
+
+func backfillRowValue(db *gorm.DB, keyring *fieldcrypt.Keyring, col encryptedColumn, row backfillRow) (bool, error) {
+	if row.Value == nil || *row.Value == "" {
+		return false, nil
+	}
+	stored := *row.Value
+	reencrypt := keyring.NeedsReencrypt(stored)
+	missingBidx := col.bidxColumn != "" && row.Bidx == nil
+	if !reencrypt && !missingBidx {
+		return false, nil
+	}
+
+	plaintext, err := keyring.Decrypt(stored)
+	if err != nil {
+		return false, err
+	}
+
+	updates := map[string]interface{}{}
+	if reencrypt {
+		ciphertext, err := keyring.Encrypt(plaintext)
+		if err != nil {
+			return false, err
+		}
+		updates[col.column] = ciphertext
+	}
+	if col.bidxColumn != "" {
+		updates[col.bidxColumn] = keyring.BlindIndex(plaintext)
+	}
+
+	res := db.Table(col.table).
+		Where(fmt.Sprintf("id = ? AND %s = ?", col.column), row.ID, stored).
+		Updates(updates)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/array/banking-api/internal/fieldcrypt"
+	"github.com/array/banking-api/internal/models"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// seedLegacyRows creates an external account and a transfer, then rewrites their account
+// numbers as pre-encryption plaintext with no blind index
+func seedLegacyRows(t *testing.T, db *gorm.DB) (*models.NorthwindExternalAccount, *models.NorthwindTransfer) {
+	t.Helper()
+	require.NoError(t, db.AutoMigrate(&models.NorthwindExternalAccount{}, &models.NorthwindTransfer{}))
+
+	account := &models.NorthwindExternalAccount{
+		AccountHolderName: "Legacy Holder",
+		AccountNumber:     "9876543210",
+		RoutingNumber:     "021000021",
+	}
+	require.NoError(t, db.Create(account).Error)
+	transfer := &models.NorthwindTransfer{
+		NorthwindTransferID:      uuid.New(),
+		Direction:                models.NWTransferDirectionOutbound,
+		TransferType:             "ACH",
+		Amount:                   decimal.NewFromInt(10),
+		Currency:                 "USD",
+		ReferenceNumber:          "REF-LEGACY",
+		SourceAccountNumber:      "1111111111",
+		DestinationAccountNumber: "2222222222",
+		Status:                   models.NWTransferStatusPending,
+	}
+	require.NoError(t, db.Create(transfer).Error)
+
+	require.NoError(t, db.Table("northwind_external_accounts").Where("id = ?", account.ID).
+		Updates(map[string]interface{}{"account_number": "9876543210", "account_number_bidx": nil}).Error)
+	require.NoError(t, db.Table("northwind_transfers").Where("id = ?", transfer.ID).
+		Updates(map[string]interface{}{"source_account_number": "1111111111", "destination_account_number": "2222222222"}).Error)
+	return account, transfer
+}
+
+func rawColumn(t *testing.T, db *gorm.DB, table, column string, id uuid.UUID) *string {
+	t.Helper()
+	var value *string
+	require.NoError(t, db.Table(table).Select(column).Where("id = ?", id).Row().Scan(&value))
+	return value
+}
+
+func TestBackfillEncryptedColumns_EncryptsPlaintext(t *testing.T) {
+	db := SetupTestDB(t)
+	defer CleanupTestDB(t, db)
+	account, transfer := seedLegacyRows(t, db.DB)
+	keyring := fieldcrypt.Default()
+
+	results, err := BackfillEncryptedColumns(db.DB, keyring, 1)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	for _, r := range results {
+		assert.Equal(t, 1, r.Scanned, "%s.%s", r.Table, r.Column)
+		assert.Equal(t, 1, r.Updated, "%s.%s", r.Table, r.Column)
+	}
+
+	stored := rawColumn(t, db.DB, "northwind_external_accounts", "account_number", account.ID)
+	require.NotNil(t, stored)
+	assert.True(t, strings.HasPrefix(*stored, "enc:test:"))
+	bidx := rawColumn(t, db.DB, "northwind_external_accounts", "account_number_bidx", account.ID)
+	require.NotNil(t, bidx)
+	assert.Equal(t, keyring.BlindIndex("9876543210"), *bidx)
+	assert.True(t, strings.HasPrefix(*rawColumn(t, db.DB, "northwind_transfers", "source_account_number", transfer.ID), "enc:test:"))
+
+	var reloaded models.NorthwindTransfer
+	require.NoError(t, db.First(&reloaded, "id = ?", transfer.ID).Error)
+	assert.Equal(t, "1111111111", reloaded.SourceAccountNumber)
+	assert.Equal(t, "2222222222", reloaded.DestinationAccountNumber)
+
+	// A second run finds nothing to do
+	results, err = BackfillEncryptedColumns(db.DB, keyring, 10)
+	require.NoError(t, err)
+	for _, r := range results {
+		assert.Zero(t, r.Updated, "%s.%s", r.Table, r.Column)
+	}
+}
+
+func TestBackfillEncryptedColumns_RotatesToActiveKey(t *testing.T) {
+	db := SetupTestDB(t)
+	defer CleanupTestDB(t, db)
+	account, _ := seedLegacyRows(t, db.DB)
+
+	oldKeyring := fieldcrypt.Default()
+	_, err := BackfillEncryptedColumns(db.DB, oldKeyring, 10)
+	require.NoError(t, err)
+
+	rotated, err := fieldcrypt.NewKeyring(map[string][]byte{
+		"test": bytes.Repeat([]byte{0x01}, 32),
+		"k2":   bytes.Repeat([]byte{0x03}, 32),
+	}, "k2", bytes.Repeat([]byte{0x02}, 32))
+	require.NoError(t, err)
+	fieldcrypt.SetDefault(rotated)
+	defer fieldcrypt.SetDefault(oldKeyring)
+
+	results, err := BackfillEncryptedColumns(db.DB, rotated, 10)
+	require.NoError(t, err)
+	for _, r := range results {
+		assert.Equal(t, 1, r.Updated, "%s.%s", r.Table, r.Column)
+	}
+
+	stored := rawColumn(t, db.DB, "northwind_external_accounts", "account_number", account.ID)
+	require.NotNil(t, stored)
+	assert.True(t, strings.HasPrefix(*stored, "enc:k2:"))
+	_, err = oldKeyring.Decrypt(*stored)
+	assert.ErrorIs(t, err, fieldcrypt.ErrUnknownKey)
+
+	var reloaded models.NorthwindExternalAccount
+	require.NoError(t, db.First(&reloaded, "id = ?", account.ID).Error)
+	assert.Equal(t, "9876543210", reloaded.AccountNumber)
+}
@@ -0,0 +1,186 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// schemaMigrationsTable is where golang-migrate records the applied version
+const schemaMigrationsTable = "schema_migrations"
+
+// Migration is one versioned migration, identified by the <version>_<name> prefix of its files
+type Migration struct {
+	Version uint
+	Name    string
+	upFile  string
+}
+
+// String returns the migration as <version>_<name>
+func (m Migration) String() string {
+	return fmt.Sprintf("%06d_%s", m.Version, m.Name)
+}
+
+// Migrations lists the up migrations in migrations ordered by version
+func Migrations(migrations fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(migrations, "*.up.sql")
+	if err != nil {
+		return nil, err
+	}
+	list := make([]Migration, 0, len(files))
+	for _, file := range files {
+		prefix, name, ok := strings.Cut(strings.TrimSuffix(path.Base(file), ".up.sql"), "_")
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if !ok || err != nil {
+			return nil, fmt.Errorf("migration file %s is not named <version>_<name>.up.sql", file)
+		}
+		list = append(list, Migration{Version: uint(version), Name: name, upFile: file})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, nil
+}
+
+// appliedMigrationVersion reads the version golang-migrate recorded and whether that migration
+// failed part way. A database without the table has none applied.
+func appliedMigrationVersion(ctx context.Context, db *gorm.DB) (uint, bool, error) {
+	db = db.WithContext(ctx)
+	if !db.Migrator().HasTable(schemaMigrationsTable) {
+		return 0, false, nil
+	}
+	var rows []struct {
+		Version int64
+		Dirty   bool
+	}
+	if err := db.Table(schemaMigrationsTable).Select("version", "dirty").Limit(1).Find(&rows).Error; err != nil {
+		return 0, false, fmt.Errorf("failed to read %s: %w", schemaMigrationsTable, err)
+	}
+	if len(rows) == 0 || rows[0].Version < 0 {
+		return 0, false, nil
+	}
+	return uint(rows[0].Version), rows[0].Dirty, nil
+}
+
+// PendingMigrations returns the migrations in migrations that the database has not applied, in
+// the order they would run. A migration that failed part way (a dirty version) is pending.
+func PendingMigrations(ctx context.Context, db *gorm.DB, migrations fs.FS) ([]Migration, error) {
+	all, err := Migrations(migrations)
+	if err != nil {
+		return nil, err
+	}
+	version, dirty, err := appliedMigrationVersion(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, m := range all {
+		if m.Version > version || (dirty && m.Version == version) {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+// MigrationsUpToDate reports whether the database has applied every migration in migrations,
+// returning the pending ones when it has not
+func MigrationsUpToDate(ctx context.Context, db *gorm.DB, migrations fs.FS) (bool, []Migration, error) {
+	pending, err := PendingMigrations(ctx, db, migrations)
+	if err != nil {
+		return false, nil, err
+	}
+	return len(pending) == 0, pending, nil
+}
+
+// ApplyPendingMigrations runs the pending up migrations in order and returns the ones applied.
+// Versions are recorded in schema_migrations the way golang-migrate records them: the version is
+// marked dirty before its migration runs and clean once it has, so a failed migration is left
+// dirty for an operator to resolve and both tools agree on what has been applied.
+func ApplyPendingMigrations(ctx context.Context, db *gorm.DB, migrations fs.FS) ([]Migration, error) {
+	pending, err := PendingMigrations(ctx, db, migrations)
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+	db = db.WithContext(ctx)
+	if err := db.Exec("CREATE TABLE IF NOT EXISTS " + schemaMigrationsTable + " (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)").Error; err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", schemaMigrationsTable, err)
+	}
+
+	var applied []Migration
+	for _, m := range pending {
+		script, err := fs.ReadFile(migrations, m.upFile)
+		if err != nil {
+			return applied, err
+		}
+		if err := setMigrationVersion(db, m.Version, true); err != nil {
+			return applied, err
+		}
+		if err := db.Exec(string(script)).Error; err != nil {
+			return applied, fmt.Errorf("migration %s failed: %w", m, err)
+		}
+		if err := setMigrationVersion(db, m.Version, false); err != nil {
+			return applied, err
+		}
+		applied = append(applied, m)
+	}
+	return applied, nil
+}
+
+// setMigrationVersion replaces the single schema_migrations row
+func setMigrationVersion(db *gorm.DB, version uint, dirty bool) error {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM " + schemaMigrationsTable).Error; err != nil {
+			return err
+		}
+		return tx.Exec("INSERT INTO "+schemaMigrationsTable+" (version, dirty) VALUES (?, ?)", version, dirty).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record migration version %d: %w", version, err)
+	}
+	return nil
+}
+
+// ErrMigrationsPending is returned by CheckMigrations when the database is behind
+var ErrMigrationsPending = errors.New("database migrations are pending")
+
+// CheckMigrations is the startup migration check. Pending migrations are applied when
+// autoApply is set; otherwise they are logged and ErrMigrationsPending is returned naming them.
+func CheckMigrations(ctx context.Context, db *gorm.DB, migrations fs.FS, autoApply bool, logger *slog.Logger) error {
+	upToDate, pending, err := MigrationsUpToDate(ctx, db, migrations)
+	if err != nil {
+		return err
+	}
+	if upToDate {
+		logger.Info("Database migrations are up to date")
+		return nil
+	}
+	if !autoApply {
+		names := MigrationNames(pending)
+		logger.Error("Database migrations are pending", "pending", names)
+		return fmt.Errorf("%w: %s", ErrMigrationsPending, strings.Join(names, ", "))
+	}
+
+	applied, err := ApplyPendingMigrations(ctx, db, migrations)
+	for _, m := range applied {
+		logger.Info("Applied database migration", "migration", m.String())
+	}
+	return err
+}
+
+// MigrationNames returns the <version>_<name> of each migration
+func MigrationNames(migrations []Migration) []string {
+	names := make([]string, len(migrations))
+	for i, m := range migrations {
+		names[i] = m.String()
+	}
+	return names
+}
@@ -0,0 +1,120 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/array/banking-api/db/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testMigrations are SQLite-compatible stand-ins for the embedded migrations
+var testMigrations = fstest.MapFS{
+	"000001_create_widgets.up.sql":         {Data: []byte("CREATE TABLE widgets (id integer PRIMARY KEY);")},
+	"000001_create_widgets.down.sql":       {Data: []byte("DROP TABLE widgets;")},
+	"000002_add_widget_name.up.sql":        {Data: []byte("ALTER TABLE widgets ADD COLUMN name text;")},
+	"000002_add_widget_name.down.sql":      {Data: []byte("ALTER TABLE widgets DROP COLUMN name;")},
+	"000010_create_gadgets.up.sql":         {Data: []byte("CREATE TABLE gadgets (id integer PRIMARY KEY); CREATE INDEX idx_gadgets_id ON gadgets (id);")},
+	"000010_create_gadgets.down.sql":       {Data: []byte("DROP TABLE gadgets;")},
+	"README.md":                            {Data: []byte("not a migration")},
+	"000003_only_down_is_ignored.down.sql": {Data: []byte("SELECT 1;")},
+}
+
+func TestMigrations_ListsUpMigrationsInOrder(t *testing.T) {
+	list, err := Migrations(testMigrations)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"000001_create_widgets", "000002_add_widget_name", "000010_create_gadgets"}, MigrationNames(list))
+
+	_, err = Migrations(fstest.MapFS{"first.up.sql": {Data: []byte("SELECT 1;")}})
+	assert.Error(t, err)
+}
+
+func TestMigrations_EmbeddedMigrationsAreWellFormed(t *testing.T) {
+	list, err := Migrations(migrations.FS)
+	require.NoError(t, err)
+	require.NotEmpty(t, list)
+	for i := 1; i < len(list); i++ {
+		assert.NotEqual(t, list[i-1].Version, list[i].Version, "duplicate migration version %d", list[i].Version)
+	}
+}
+
+func TestMigrationsUpToDate(t *testing.T) {
+	ctx := context.Background()
+	db := SetupTestDB(t).DB
+
+	upToDate, pending, err := MigrationsUpToDate(ctx, db, testMigrations)
+	require.NoError(t, err)
+	assert.False(t, upToDate, "a database without schema_migrations has nothing applied")
+	assert.Len(t, pending, 3)
+
+	applied, err := ApplyPendingMigrations(ctx, db, testMigrations)
+	require.NoError(t, err)
+	assert.Len(t, applied, 3)
+	upToDate, pending, err = MigrationsUpToDate(ctx, db, testMigrations)
+	require.NoError(t, err)
+	assert.True(t, upToDate)
+	assert.Empty(t, pending)
+
+	// A deploy ships a new migration that was not run
+	withNew := fstest.MapFS{"000011_add_gadget_name.up.sql": {Data: []byte("ALTER TABLE gadgets ADD COLUMN name text;")}}
+	for name, file := range testMigrations {
+		withNew[name] = file
+	}
+	upToDate, pending, err = MigrationsUpToDate(ctx, db, withNew)
+	require.NoError(t, err)
+	assert.False(t, upToDate)
+	assert.Equal(t, []string{"000011_add_gadget_name"}, MigrationNames(pending))
+}
+
+func TestMigrationsUpToDate_DirtyVersionIsPending(t *testing.T) {
+	ctx := context.Background()
+	db := SetupTestDB(t).DB
+	require.NoError(t, db.Exec("CREATE TABLE schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)").Error)
+	require.NoError(t, db.Exec("INSERT INTO schema_migrations (version, dirty) VALUES (2, true)").Error)
+
+	pending, err := PendingMigrations(ctx, db, testMigrations)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"000002_add_widget_name", "000010_create_gadgets"}, MigrationNames(pending))
+}
+
+func TestApplyPendingMigrations_FailureLeavesVersionDirty(t *testing.T) {
+	ctx := context.Background()
+	db := SetupTestDB(t).DB
+	broken := fstest.MapFS{
+		"000001_create_widgets.up.sql": testMigrations["000001_create_widgets.up.sql"],
+		"000002_broken.up.sql":         {Data: []byte("ALTER TABLE no_such_table ADD COLUMN name text;")},
+	}
+
+	applied, err := ApplyPendingMigrations(ctx, db, broken)
+	require.Error(t, err)
+	assert.Equal(t, []string{"000001_create_widgets"}, MigrationNames(applied))
+
+	version, dirty, err := appliedMigrationVersion(ctx, db)
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), version)
+	assert.True(t, dirty)
+}
+
+func TestCheckMigrations(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("pending without auto-apply", func(t *testing.T) {
+		db := SetupTestDB(t).DB
+		err := CheckMigrations(ctx, db, testMigrations, false, discardLogger)
+		require.True(t, errors.Is(err, ErrMigrationsPending), "got %v", err)
+		assert.Contains(t, err.Error(), "000010_create_gadgets")
+		assert.False(t, db.Migrator().HasTable("widgets"), "nothing is applied")
+	})
+
+	t.Run("auto-apply", func(t *testing.T) {
+		db := SetupTestDB(t).DB
+		require.NoError(t, CheckMigrations(ctx, db, testMigrations, true, discardLogger))
+		assert.True(t, db.Migrator().HasColumn("widgets", "name"))
+		assert.True(t, db.Migrator().HasTable("gadgets"))
+		// A restart finds nothing left to do
+		require.NoError(t, CheckMigrations(ctx, db, testMigrations, false, discardLogger))
+	})
+}
@@ -0,0 +1,25 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/array/banking-api/internal/config"
+	_ "github.com/jackc/pgx/v5/stdlib" // registers the "pgx" database/sql driver
+)
+
+// Ping opens a short-lived connection to the configured database and pings it. Unlike New it
+// honours ctx, so callers such as the startup self-check can bound how long it takes.
+func Ping(ctx context.Context, cfg *config.DatabaseConfig) error {
+	sqlDB, err := sql.Open("pgx", cfg.DSN())
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer sqlDB.Close()
+
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+	return nil
+}
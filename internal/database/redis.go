@@ -0,0 +1,21 @@
+package database
+
+import (
+	"github.com/array/banking-api/internal/config"
+	"github.com/redis/go-redis/v9"
+)
+
+// NewRedisClient creates a pooled Redis client. Connections are established lazily;
+// callers decide how to handle an unreachable server.
+func NewRedisClient(cfg *config.RedisConfig) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:         cfg.Addr,
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		PoolSize:     cfg.PoolSize,
+		MinIdleConns: cfg.MinIdleConns,
+		DialTimeout:  cfg.DialTimeout,
+		ReadTimeout:  cfg.OperationTimeout,
+		WriteTimeout: cfg.OperationTimeout,
+	})
+}
@@ -1,18 +1,39 @@
 package database
 
 import (
+	"bytes"
 	"fmt"
 	"testing"
 
 	"github.com/array/banking-api/internal/config"
+	"github.com/array/banking-api/internal/fieldcrypt"
 	"github.com/array/banking-api/internal/models"
 	"github.com/glebarez/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
+// InstallTestKeyring installs a fixed field-encryption keyring (key ID "test") unless one is
+// already set, so tests can persist models with encrypted columns
+func InstallTestKeyring(t *testing.T) {
+	t.Helper()
+	if fieldcrypt.Default() != nil {
+		return
+	}
+	keyring, err := fieldcrypt.NewKeyring(
+		map[string][]byte{"test": bytes.Repeat([]byte{0x01}, 32)},
+		"test",
+		bytes.Repeat([]byte{0x02}, 32),
+	)
+	if err != nil {
+		t.Fatalf("failed to create test keyring: %v", err)
+	}
+	fieldcrypt.SetDefault(keyring)
+}
+
 func SetupTestDB(t *testing.T) *DB {
 	t.Helper()
+	InstallTestKeyring(t)
 
 	gormConfig := &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Silent),
@@ -81,6 +102,7 @@ type TestDB struct {
 
 func NewTestDB(t *testing.T) *TestDB {
 	t.Helper()
+	InstallTestKeyring(t)
 
 	gormConfig := &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Silent),
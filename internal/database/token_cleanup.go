@@ -0,0 +1,99 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultTokenCleanupBatchSize   = 5000
+	DefaultTokenCleanupBatchPause  = 50 * time.Millisecond
+	DefaultTokenCleanupMaxDuration = 10 * time.Second
+)
+
+// TokenCleanupOptions bounds how hard one cleanup run leans on the token tables. Zero values
+// use the defaults.
+type TokenCleanupOptions struct {
+	BatchSize int
+	// BatchPause is slept between batches so token reads and writes are not starved
+	BatchPause time.Duration
+	// MaxDuration caps a run; rows left over are deleted by the next one
+	MaxDuration time.Duration
+}
+
+// TokenCleanupResult reports how many expired rows a cleanup run deleted
+type TokenCleanupResult struct {
+	RefreshTokens     int64
+	BlacklistedTokens int64
+	// Truncated is set when the run hit MaxDuration before every expired row was deleted
+	Truncated bool
+}
+
+// CleanupExpiredTokens deletes expired refresh and blacklisted tokens. See the package-level
+// CleanupExpiredTokens.
+func (db *DB) CleanupExpiredTokens(ctx context.Context, opts TokenCleanupOptions) (TokenCleanupResult, error) {
+	return CleanupExpiredTokens(ctx, db.DB, opts)
+}
+
+// CleanupExpiredTokens deletes expired refresh and blacklisted tokens opts.BatchSize rows at a
+// time. Each batch is its own short statement, so no single DELETE holds locks on a large part
+// of either table.
+func CleanupExpiredTokens(ctx context.Context, db *gorm.DB, opts TokenCleanupOptions) (TokenCleanupResult, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultTokenCleanupBatchSize
+	}
+	if opts.BatchPause <= 0 {
+		opts.BatchPause = DefaultTokenCleanupBatchPause
+	}
+	if opts.MaxDuration <= 0 {
+		opts.MaxDuration = DefaultTokenCleanupMaxDuration
+	}
+	db = db.WithContext(ctx)
+	now := time.Now()
+	deadline := now.Add(opts.MaxDuration)
+
+	var result TokenCleanupResult
+	var err error
+	result.RefreshTokens, result.Truncated, err = deleteExpiredInBatches(ctx, db, &models.RefreshToken{}, now, deadline, opts)
+	if err != nil {
+		return result, fmt.Errorf("failed to cleanup expired refresh tokens: %w", err)
+	}
+	if result.Truncated {
+		return result, nil
+	}
+	result.BlacklistedTokens, result.Truncated, err = deleteExpiredInBatches(ctx, db, &models.BlacklistedToken{}, now, deadline, opts)
+	if err != nil {
+		return result, fmt.Errorf("failed to cleanup expired blacklisted tokens: %w", err)
+	}
+	return result, nil
+}
+
+// deleteExpiredInBatches deletes rows of model that expired before now until none are left or
+// the deadline passes, and reports the rows deleted and whether it stopped at the deadline
+func deleteExpiredInBatches(ctx context.Context, db *gorm.DB, model interface{}, now, deadline time.Time, opts TokenCleanupOptions) (int64, bool, error) {
+	var deleted int64
+	for {
+		batch := db.Model(model).Select("id").Where("expires_at < ?", now).Limit(opts.BatchSize)
+		res := db.Where("id IN (?)", batch).Delete(model)
+		if res.Error != nil {
+			return deleted, false, res.Error
+		}
+		deleted += res.RowsAffected
+		if res.RowsAffected < int64(opts.BatchSize) {
+			return deleted, false, nil
+		}
+		if time.Now().Add(opts.BatchPause).After(deadline) {
+			return deleted, true, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return deleted, false, ctx.Err()
+		case <-time.After(opts.BatchPause):
+		}
+	}
+}
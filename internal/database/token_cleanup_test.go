@@ -0,0 +1,91 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+const tokenCleanupTestRows = 20000
+
+// seedTokens inserts n refresh tokens and n blacklisted tokens for userID expiring at expiresAt
+func seedTokens(t *testing.T, db *gorm.DB, userID uuid.UUID, n int, expiresAt time.Time, prefix string) {
+	t.Helper()
+	now := time.Now()
+	refresh := make([]map[string]interface{}, n)
+	blacklisted := make([]map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		refresh[i] = map[string]interface{}{
+			"id":         uuid.New().String(),
+			"user_id":    userID.String(),
+			"token_hash": fmt.Sprintf("%s-hash-%05d", prefix, i),
+			"expires_at": expiresAt,
+			"created_at": now,
+		}
+		blacklisted[i] = map[string]interface{}{
+			"id":             uuid.New().String(),
+			"jti":            fmt.Sprintf("%s-jti-%05d", prefix, i),
+			"user_id":        userID.String(),
+			"expires_at":     expiresAt,
+			"blacklisted_at": now,
+		}
+	}
+	require.NoError(t, db.Table("refresh_tokens").CreateInBatches(refresh, 500).Error)
+	require.NoError(t, db.Table("blacklisted_tokens").CreateInBatches(blacklisted, 500).Error)
+}
+
+func countTokens(t *testing.T, db *gorm.DB, model interface{}) int64 {
+	t.Helper()
+	var n int64
+	require.NoError(t, db.Model(model).Count(&n).Error)
+	return n
+}
+
+func TestCleanupExpiredTokens_DeletesInBatches(t *testing.T) {
+	db := SetupTestDB(t)
+	defer CleanupTestDB(t, db)
+
+	user := CreateTestUser(t, db, "batched-cleanup@example.com")
+	seedTokens(t, db.DB, user.ID, tokenCleanupTestRows, time.Now().Add(-time.Hour), "expired")
+	seedTokens(t, db.DB, user.ID, 10, time.Now().Add(time.Hour), "live")
+
+	var statements int
+	require.NoError(t, db.Callback().Delete().After("gorm:delete").Register("test:count_deletes", func(*gorm.DB) {
+		statements++
+	}))
+
+	result, err := db.CleanupExpiredTokens(context.Background(), TokenCleanupOptions{BatchPause: time.Millisecond, MaxDuration: time.Minute})
+	require.NoError(t, err)
+
+	assert.Equal(t, TokenCleanupResult{RefreshTokens: tokenCleanupTestRows, BlacklistedTokens: tokenCleanupTestRows}, result)
+	// Four full batches and one empty one for each table
+	assert.Equal(t, 2*(tokenCleanupTestRows/DefaultTokenCleanupBatchSize+1), statements)
+	assert.Equal(t, int64(10), countTokens(t, db.DB, &models.RefreshToken{}), "live refresh tokens were deleted")
+	assert.Equal(t, int64(10), countTokens(t, db.DB, &models.BlacklistedToken{}), "live blacklisted tokens were deleted")
+}
+
+func TestCleanupExpiredTokens_StopsAtMaxDuration(t *testing.T) {
+	db := SetupTestDB(t)
+	defer CleanupTestDB(t, db)
+
+	user := CreateTestUser(t, db, "capped-cleanup@example.com")
+	seedTokens(t, db.DB, user.ID, 50, time.Now().Add(-time.Hour), "expired")
+
+	// The pause after the first batch would overrun the cap, so the run stops there
+	opts := TokenCleanupOptions{BatchSize: 10, BatchPause: time.Second, MaxDuration: 500 * time.Millisecond}
+	result, err := CleanupExpiredTokens(context.Background(), db.DB, opts)
+	require.NoError(t, err)
+
+	assert.True(t, result.Truncated)
+	assert.Equal(t, int64(10), result.RefreshTokens)
+	assert.Zero(t, result.BlacklistedTokens)
+	assert.Equal(t, int64(40), countTokens(t, db.DB, &models.RefreshToken{}))
+	assert.Equal(t, int64(50), countTokens(t, db.DB, &models.BlacklistedToken{}))
+}
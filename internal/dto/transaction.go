@@ -3,6 +3,7 @@ package dto
 import (
 	"time"
 
+	"github.com/array/banking-api/internal/models"
 	"github.com/google/uuid"
 )
 
@@ -51,3 +52,9 @@ type ListTransactionsResponse struct {
 	Transactions []TransactionWithBalance `json:"transactions"`
 	Pagination   PaginationInfo           `json:"pagination"`
 }
+
+// ListAccountActivityResponse represents the response for an account's activity feed
+type ListAccountActivityResponse struct {
+	Activity   []models.AccountActivityItem `json:"activity"`
+	Pagination PaginationInfo               `json:"pagination"`
+}
@@ -22,6 +22,7 @@ const (
 	ValidationInvalidEmail  ErrorCode = "VALIDATION_005"
 	ValidationInvalidPhone  ErrorCode = "VALIDATION_006"
 	ValidationInvalidDate   ErrorCode = "VALIDATION_007"
+	ValidationInvalidQuery  ErrorCode = "VALIDATION_008"
 )
 
 // Customer error codes (CUSTOMER_*)
@@ -67,6 +68,7 @@ const (
 	NorthwindAccountNotFound       ErrorCode = "NORTHWIND_ACCOUNT_001"
 	NorthwindAccountValidationFail ErrorCode = "NORTHWIND_ACCOUNT_002"
 	NorthwindAccountAlreadyExists  ErrorCode = "NORTHWIND_ACCOUNT_003"
+	NorthwindAccountNameMismatch   ErrorCode = "NORTHWIND_ACCOUNT_004"
 )
 
 // NorthWind transfer error codes (NORTHWIND_TRANSFER_*)
@@ -77,12 +79,66 @@ const (
 	NorthwindTransferInsufficientBal ErrorCode = "NORTHWIND_TRANSFER_004"
 	NorthwindTransferCancelFail      ErrorCode = "NORTHWIND_TRANSFER_005"
 	NorthwindTransferReverseFail     ErrorCode = "NORTHWIND_TRANSFER_006"
+	NorthwindTransferConsentMissing  ErrorCode = "NORTHWIND_TRANSFER_007"
+	NorthwindTransferUnverifiedAcct  ErrorCode = "NORTHWIND_TRANSFER_008"
+	NorthwindTransferDuplicateRef    ErrorCode = "NORTHWIND_TRANSFER_009"
+	NorthwindTransferPossibleDup     ErrorCode = "POSSIBLE_DUPLICATE"
+	NorthwindTransferReceiptUnavail  ErrorCode = "NORTHWIND_TRANSFER_010"
+	NorthwindTransferCursorExpired   ErrorCode = "NORTHWIND_TRANSFER_011"
+	NorthwindTransferBatchExists     ErrorCode = "NORTHWIND_TRANSFER_012"
+	NorthwindTransferBatchNotFound   ErrorCode = "NORTHWIND_TRANSFER_013"
+	NorthwindTransferCancelClosed    ErrorCode = "NORTHWIND_TRANSFER_014"
+	NorthwindTransferRiskBlocked     ErrorCode = "NORTHWIND_TRANSFER_015"
+	NorthwindTransferSameDayClosed   ErrorCode = "NORTHWIND_TRANSFER_016"
+	NorthwindTransferOverloaded      ErrorCode = "NORTHWIND_TRANSFER_017"
+)
+
+// NorthWind transfer dispute error codes (NORTHWIND_DISPUTE_*)
+const (
+	NorthwindDisputeNotFound     ErrorCode = "NORTHWIND_DISPUTE_001"
+	NorthwindDisputeNotCompleted ErrorCode = "NORTHWIND_DISPUTE_002"
+	NorthwindDisputeWindowClosed ErrorCode = "NORTHWIND_DISPUTE_003"
+	NorthwindDisputeAlreadyOpen  ErrorCode = "NORTHWIND_DISPUTE_004"
+	NorthwindDisputeTransition   ErrorCode = "NORTHWIND_DISPUTE_005"
 )
 
 // NorthWind API error codes (NORTHWIND_API_*)
 const (
 	NorthwindAPIUnavailable ErrorCode = "NORTHWIND_API_001"
 	NorthwindAPIError       ErrorCode = "NORTHWIND_API_002"
+	NorthwindAPIBadRequest  ErrorCode = "NORTHWIND_API_003"
+	NorthwindAPIRejected    ErrorCode = "NORTHWIND_API_004"
+	NorthwindAPITimeout     ErrorCode = "NORTHWIND_API_005"
+)
+
+// NorthWind webhook error codes (NORTHWIND_WEBHOOK_*)
+const (
+	NorthwindWebhookInvalidSignature ErrorCode = "NORTHWIND_WEBHOOK_001"
+)
+
+// Regulator notification error codes (REGULATOR_*)
+const (
+	RegulatorNotificationNotFound ErrorCode = "REGULATOR_001"
+)
+
+// Feature flag error codes (FEATURE_FLAG_*)
+const (
+	FeatureFlagNotFound ErrorCode = "FEATURE_FLAG_001"
+)
+
+// Balance alert error codes (BALANCE_ALERT_*)
+const (
+	BalanceAlertRuleNotFound ErrorCode = "BALANCE_ALERT_001"
+)
+
+// Risk rule error codes (RISK_*)
+const (
+	RiskRuleNotFound ErrorCode = "RISK_001"
+)
+
+// Personal access token error codes (ACCESS_TOKEN_*)
+const (
+	AccessTokenNotFound ErrorCode = "ACCESS_TOKEN_001"
 )
 
 // System error codes (SYSTEM_*)
@@ -93,6 +149,11 @@ const (
 	SystemConfigurationError ErrorCode = "SYSTEM_004"
 	SystemUnexpectedError    ErrorCode = "SYSTEM_005"
 	SystemRateLimitExceeded  ErrorCode = "SYSTEM_006"
+	SystemRequestInProgress  ErrorCode = "SYSTEM_007"
+	SystemRequestTimeout     ErrorCode = "SYSTEM_008"
+	SystemGatewayTimeout     ErrorCode = "SYSTEM_009"
+	SystemNotAvailableInEnv  ErrorCode = "NOT_AVAILABLE_IN_ENV"
+	SystemReadOnlyMode       ErrorCode = "READ_ONLY_MODE"
 )
 
 // errorMessages maps error codes to their default human-readable messages
@@ -113,6 +174,7 @@ var errorMessages = map[ErrorCode]string{
 	ValidationInvalidEmail:  "Invalid email address format",
 	ValidationInvalidPhone:  "Invalid phone number format",
 	ValidationInvalidDate:   "Invalid date format or range",
+	ValidationInvalidQuery:  "Invalid query parameters",
 
 	// Customer errors
 	CustomerNotFound:      "Customer not found",
@@ -148,6 +210,7 @@ var errorMessages = map[ErrorCode]string{
 	NorthwindAccountNotFound:       "External account not found",
 	NorthwindAccountValidationFail: "External account validation failed with NorthWind",
 	NorthwindAccountAlreadyExists:  "External account already registered",
+	NorthwindAccountNameMismatch:   "Account holder name does not match the name on the account",
 
 	// NorthWind transfer errors
 	NorthwindTransferNotFound:        "NorthWind transfer not found",
@@ -156,10 +219,50 @@ var errorMessages = map[ErrorCode]string{
 	NorthwindTransferInsufficientBal: "Insufficient balance in source account",
 	NorthwindTransferCancelFail:      "Failed to cancel transfer",
 	NorthwindTransferReverseFail:     "Failed to reverse transfer",
+	NorthwindTransferConsentMissing:  "Authorization consent is required for inbound transfers",
+	NorthwindTransferUnverifiedAcct:  "External source account is not registered or not verified",
+	NorthwindTransferDuplicateRef:    "Reference number has already been used for another transfer",
+	NorthwindTransferPossibleDup:     "A near-identical transfer was created moments ago",
+	NorthwindTransferReceiptUnavail:  "Receipts are only available for completed or reversed transfers",
+	NorthwindTransferCursorExpired:   "Pagination cursor has expired; restart from the first page",
+	NorthwindTransferBatchExists:     "Batch name has already been used for another batch",
+	NorthwindTransferBatchNotFound:   "Transfer batch not found",
+	NorthwindTransferCancelClosed:    "The transfer's cancellation window has closed",
+	NorthwindTransferRiskBlocked:     "The transfer was declined by risk checks",
+	NorthwindTransferSameDayClosed:   "Same-day transfers are closed until the next cutoff",
+	NorthwindTransferOverloaded:      "Too many transfers are being initiated; retry shortly",
+
+	// NorthWind transfer dispute errors
+	NorthwindDisputeNotFound:     "Transfer dispute not found",
+	NorthwindDisputeNotCompleted: "Only completed transfers can be disputed",
+	NorthwindDisputeWindowClosed: "The transfer's dispute window has closed",
+	NorthwindDisputeAlreadyOpen:  "The transfer already has a dispute under way",
+	NorthwindDisputeTransition:   "The dispute is not in a status that allows this",
 
 	// NorthWind API errors
 	NorthwindAPIUnavailable: "NorthWind API is unavailable",
 	NorthwindAPIError:       "NorthWind API returned an error",
+	NorthwindAPIBadRequest:  "NorthWind rejected the request as malformed",
+	NorthwindAPIRejected:    "NorthWind rejected the request",
+	NorthwindAPITimeout:     "NorthWind API did not respond in time",
+
+	// NorthWind webhook errors
+	NorthwindWebhookInvalidSignature: "Webhook signature is missing or invalid",
+
+	// Regulator notification errors
+	RegulatorNotificationNotFound: "Regulator notification not found",
+
+	// Feature flag errors
+	FeatureFlagNotFound: "Feature flag not found",
+
+	// Balance alert errors
+	BalanceAlertRuleNotFound: "Balance alert rule not found",
+
+	// Risk rule errors
+	RiskRuleNotFound: "Risk rule not found",
+
+	// Personal access token errors
+	AccessTokenNotFound: "Access token not found",
 
 	// System errors
 	SystemInternalError:      "An unexpected error occurred. Please contact support with trace ID",
@@ -168,6 +271,11 @@ var errorMessages = map[ErrorCode]string{
 	SystemConfigurationError: "System configuration error",
 	SystemUnexpectedError:    "An unexpected error occurred",
 	SystemRateLimitExceeded:  "Rate limit exceeded. Please try again later",
+	SystemRequestInProgress:  "A request with this Idempotency-Key is already in progress",
+	SystemRequestTimeout:     "The request took too long to process. Please try again",
+	SystemGatewayTimeout:     "The request did not complete in time. Please try again",
+	SystemNotAvailableInEnv:  "This operation is not available in the current environment",
+	SystemReadOnlyMode:       "The service is in read-only mode; changes are temporarily disabled",
 }
 
 // GetErrorMessage returns the default message for a given error code
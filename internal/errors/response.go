@@ -3,9 +3,14 @@ package errors
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
+	"time"
 )
 
+// DefaultRetryAfter is the retry hint of a 429 or 503 whose sender did not supply one
+const DefaultRetryAfter = 5 * time.Second
+
 // ErrorResponse represents the standardized API error response structure
 type ErrorResponse struct {
 	Error ErrorDetail `json:"error"`
@@ -13,10 +18,14 @@ type ErrorResponse struct {
 
 // ErrorDetail contains the detailed error information
 type ErrorDetail struct {
-	Code    string   `json:"code"`
-	Message string   `json:"message"`
-	Details []string `json:"details,omitempty"`
-	TraceID string   `json:"trace_id"`
+	Code    string                 `json:"code"`
+	Message string                 `json:"message"`
+	Details []string               `json:"details,omitempty"`
+	TraceID string                 `json:"trace_id"`
+	Meta    map[string]interface{} `json:"meta,omitempty"`
+	// RetryAfterSeconds is how long to wait before retrying a 429 or 503, mirrored in the
+	// response's Retry-After header
+	RetryAfterSeconds int `json:"retry_after_seconds,omitempty"`
 }
 
 // ErrorOption is a functional option for configuring error responses
@@ -36,6 +45,24 @@ func WithMessage(message string) ErrorOption {
 	}
 }
 
+// WithMeta adds a key to the error's meta, for machine-readable context such as an upstream status
+func WithMeta(key string, value interface{}) ErrorOption {
+	return func(er *ErrorResponse) {
+		if er.Error.Meta == nil {
+			er.Error.Meta = map[string]interface{}{}
+		}
+		er.Error.Meta[key] = value
+	}
+}
+
+// WithRetryAfter sets how long the client should wait before retrying, rounded up to whole
+// seconds and at least one
+func WithRetryAfter(d time.Duration) ErrorOption {
+	return func(er *ErrorResponse) {
+		er.Error.RetryAfterSeconds = max(int(math.Ceil(d.Seconds())), 1)
+	}
+}
+
 // NewErrorResponse creates a standardized error response with the given error code and trace ID
 // Optional details can be added using functional options
 func NewErrorResponse(code ErrorCode, traceID string, opts ...ErrorOption) *ErrorResponse {
@@ -119,6 +146,28 @@ func (er *ErrorResponse) ToJSON() ([]byte, error) {
 	return json.Marshal(er)
 }
 
+// safeDetailCodes are the codes whose details describe the client's own input, such as
+// validation messages, and are returned even when other details are redacted
+var safeDetailCodes = map[ErrorCode]bool{
+	ValidationGeneral:       true,
+	ValidationRequiredField: true,
+	ValidationInvalidFormat: true,
+	ValidationOutOfRange:    true,
+	ValidationInvalidEmail:  true,
+	ValidationInvalidPhone:  true,
+	ValidationInvalidDate:   true,
+	ValidationInvalidQuery:  true,
+	// NorthWind's message when it rejects a request describes the client's input
+	NorthwindAPIBadRequest: true,
+	NorthwindAPIRejected:   true,
+}
+
+// DetailsSafe reports whether details of code may be returned to clients when internal error
+// details are redacted
+func DetailsSafe(code ErrorCode) bool {
+	return safeDetailCodes[code]
+}
+
 // GetHTTPStatus returns the appropriate HTTP status code for the error code
 func GetHTTPStatus(code ErrorCode) int {
 	switch code {
@@ -126,15 +175,16 @@ func GetHTTPStatus(code ErrorCode) int {
 	case ValidationGeneral, ValidationRequiredField, ValidationInvalidFormat,
 		ValidationOutOfRange, ValidationInvalidEmail, ValidationInvalidPhone,
 		ValidationInvalidDate, CustomerInvalidID, TransactionInvalidAmount,
-		TransferSameAccount, TransferInvalidAmount:
+		TransferSameAccount, TransferInvalidAmount, NorthwindAPIBadRequest:
 		return http.StatusBadRequest
 
 	// 401 Unauthorized - Authentication failures
-	case AuthInvalidCredentials, AuthMissingToken, AuthExpiredToken, AuthInvalidTokenFormat:
+	case AuthInvalidCredentials, AuthMissingToken, AuthExpiredToken, AuthInvalidTokenFormat,
+		NorthwindWebhookInvalidSignature:
 		return http.StatusUnauthorized
 
 	// 403 Forbidden - Authorization failures
-	case AuthInsufficientPermission, AuthAccountLocked:
+	case AuthInsufficientPermission, AuthAccountLocked, SystemNotAvailableInEnv:
 		return http.StatusForbidden
 
 	// 404 Not Found - Resource not found
@@ -142,22 +192,33 @@ func GetHTTPStatus(code ErrorCode) int {
 		return http.StatusNotFound
 
 	// 409 Conflict - Resource state conflict
-	case TransferPending, TransferFailed:
+	case TransferPending, TransferFailed, SystemRequestInProgress, NorthwindTransferDuplicateRef,
+		NorthwindTransferPossibleDup, NorthwindTransferReceiptUnavail, NorthwindTransferBatchExists,
+		NorthwindTransferCancelClosed, NorthwindTransferSameDayClosed, NorthwindDisputeNotCompleted,
+		NorthwindDisputeWindowClosed, NorthwindDisputeAlreadyOpen, NorthwindDisputeTransition:
 		return http.StatusConflict
 
+	// 410 Gone - Expired pagination cursors
+	case NorthwindTransferCursorExpired:
+		return http.StatusGone
+
 	// 422 Unprocessable Entity - Semantic validation failures
-	case CustomerAlreadyExists, CustomerInactive, AccountInactive,
+	case ValidationInvalidQuery, CustomerAlreadyExists, CustomerInactive, AccountInactive,
 		AccountInsufficientBalance, AccountOperationNotPermitted,
 		TransactionInsufficientFunds, TransactionDuplicate,
 		TransactionValidationFailed, TransactionInvalidType,
 		AccountInvalidNumber, CustomerNoResults,
 		TransferInsufficientFunds,
-		NorthwindAccountValidationFail, NorthwindAccountAlreadyExists,
-		NorthwindTransferValidationFail, NorthwindTransferInsufficientBal:
+		NorthwindAccountValidationFail, NorthwindAccountAlreadyExists, NorthwindAccountNameMismatch,
+		NorthwindTransferValidationFail, NorthwindTransferInsufficientBal,
+		NorthwindTransferConsentMissing, NorthwindTransferUnverifiedAcct, NorthwindAPIRejected,
+		NorthwindTransferRiskBlocked:
 		return http.StatusUnprocessableEntity
 
 	// NorthWind specific errors
-	case NorthwindAccountNotFound, NorthwindTransferNotFound:
+	case NorthwindAccountNotFound, NorthwindTransferNotFound, RegulatorNotificationNotFound,
+		FeatureFlagNotFound, NorthwindTransferBatchNotFound, BalanceAlertRuleNotFound, RiskRuleNotFound,
+		AccessTokenNotFound, NorthwindDisputeNotFound:
 		return http.StatusNotFound
 
 	case NorthwindTransferInitiateFail, NorthwindTransferCancelFail, NorthwindTransferReverseFail,
@@ -167,14 +228,21 @@ func GetHTTPStatus(code ErrorCode) int {
 	case NorthwindAPIUnavailable:
 		return http.StatusServiceUnavailable
 
+	case NorthwindAPITimeout:
+		return http.StatusGatewayTimeout
+
 	// 429 Too Many Requests - Rate limiting
 	case SystemRateLimitExceeded:
 		return http.StatusTooManyRequests
 
-	// 503 Service Unavailable - Service temporarily unavailable
-	case SystemServiceUnavailable:
+	// 503 Service Unavailable - Service temporarily unavailable or request deadline exceeded
+	case SystemServiceUnavailable, SystemRequestTimeout, SystemReadOnlyMode, NorthwindTransferOverloaded:
 		return http.StatusServiceUnavailable
 
+	// 504 Gateway Timeout - A route group's deadline passed before the handler responded
+	case SystemGatewayTimeout:
+		return http.StatusGatewayTimeout
+
 	// 500 Internal Server Error - System errors (default)
 	case SystemInternalError, SystemDatabaseError, SystemConfigurationError,
 		SystemUnexpectedError:
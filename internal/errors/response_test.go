@@ -235,6 +235,7 @@ func (s *ResponseTestSuite) TestGetHTTPStatus_AllErrorCodes() {
 		{"Auth Missing Token", AuthMissingToken, http.StatusUnauthorized},
 		{"Auth Expired Token", AuthExpiredToken, http.StatusUnauthorized},
 		{"Auth Invalid Token Format", AuthInvalidTokenFormat, http.StatusUnauthorized},
+		{"NorthWind Webhook Invalid Signature", NorthwindWebhookInvalidSignature, http.StatusUnauthorized},
 
 		// 403 Forbidden
 		{"Auth Insufficient Permission", AuthInsufficientPermission, http.StatusForbidden},
@@ -244,12 +245,34 @@ func (s *ResponseTestSuite) TestGetHTTPStatus_AllErrorCodes() {
 		{"Customer Not Found", CustomerNotFound, http.StatusNotFound},
 		{"Account Not Found", AccountNotFound, http.StatusNotFound},
 		{"Transaction Not Found", TransactionNotFound, http.StatusNotFound},
+		{"Feature Flag Not Found", FeatureFlagNotFound, http.StatusNotFound},
+		{"Balance Alert Rule Not Found", BalanceAlertRuleNotFound, http.StatusNotFound},
+		{"Risk Rule Not Found", RiskRuleNotFound, http.StatusNotFound},
+		{"Access Token Not Found", AccessTokenNotFound, http.StatusNotFound},
+		{"NorthWind Transfer Batch Not Found", NorthwindTransferBatchNotFound, http.StatusNotFound},
+		{"NorthWind Dispute Not Found", NorthwindDisputeNotFound, http.StatusNotFound},
+
+		// 410 Gone
+		{"NorthWind Transfer Cursor Expired", NorthwindTransferCursorExpired, http.StatusGone},
 
 		// 422 Unprocessable Entity
+		{"Validation Invalid Query", ValidationInvalidQuery, http.StatusUnprocessableEntity},
+		{"NorthWind Receipt Unavailable", NorthwindTransferReceiptUnavail, http.StatusConflict},
+		{"NorthWind Transfer Batch Exists", NorthwindTransferBatchExists, http.StatusConflict},
+		{"NorthWind Transfer Cancel Window Closed", NorthwindTransferCancelClosed, http.StatusConflict},
+		{"NorthWind Same-Day Transfers Closed", NorthwindTransferSameDayClosed, http.StatusConflict},
+		{"NorthWind Dispute Transfer Not Completed", NorthwindDisputeNotCompleted, http.StatusConflict},
+		{"NorthWind Dispute Window Closed", NorthwindDisputeWindowClosed, http.StatusConflict},
+		{"NorthWind Dispute Already Open", NorthwindDisputeAlreadyOpen, http.StatusConflict},
+		{"NorthWind Dispute Transition", NorthwindDisputeTransition, http.StatusConflict},
 		{"Customer Already Exists", CustomerAlreadyExists, http.StatusUnprocessableEntity},
 		{"Customer Inactive", CustomerInactive, http.StatusUnprocessableEntity},
 		{"Account Insufficient Balance", AccountInsufficientBalance, http.StatusUnprocessableEntity},
 		{"Transaction Duplicate", TransactionDuplicate, http.StatusUnprocessableEntity},
+		{"NorthWind Account Name Mismatch", NorthwindAccountNameMismatch, http.StatusUnprocessableEntity},
+		{"NorthWind Transfer Risk Blocked", NorthwindTransferRiskBlocked, http.StatusUnprocessableEntity},
+		{"NorthWind Transfer Initiations Overloaded", NorthwindTransferOverloaded, http.StatusServiceUnavailable},
+		{"NorthWind API Rejected", NorthwindAPIRejected, http.StatusUnprocessableEntity},
 
 		// 429 Too Many Requests
 		{"System Rate Limit Exceeded", SystemRateLimitExceeded, http.StatusTooManyRequests},
@@ -261,6 +284,11 @@ func (s *ResponseTestSuite) TestGetHTTPStatus_AllErrorCodes() {
 
 		// 503 Service Unavailable
 		{"System Service Unavailable", SystemServiceUnavailable, http.StatusServiceUnavailable},
+		{"System Request Timeout", SystemRequestTimeout, http.StatusServiceUnavailable},
+
+		// 504 Gateway Timeout
+		{"System Gateway Timeout", SystemGatewayTimeout, http.StatusGatewayTimeout},
+		{"NorthWind API Timeout", NorthwindAPITimeout, http.StatusGatewayTimeout},
 	}
 
 	for _, tc := range testCases {
@@ -388,3 +416,19 @@ func (s *ResponseTestSuite) TestWithMessage_MultipleInvocations() {
 
 	s.Equal("Second message", response.Error.Message)
 }
+
+// TestWithMeta_AddsKeys tests that WithMeta accumulates keys and is omitted when unused
+func (s *ResponseTestSuite) TestWithMeta_AddsKeys() {
+	response := NewErrorResponse(
+		NorthwindAPIBadRequest,
+		s.traceID,
+		WithMeta("upstream_status", 400),
+		WithMeta("upstream_trace_id", "nw-1"),
+	)
+	s.Equal(map[string]interface{}{"upstream_status": 400, "upstream_trace_id": "nw-1"}, response.Error.Meta)
+	s.Equal(http.StatusBadRequest, response.GetHTTPStatus())
+
+	jsonBytes, err := NewErrorResponse(SystemInternalError, s.traceID).ToJSON()
+	s.NoError(err)
+	s.NotContains(string(jsonBytes), `"meta"`)
+}
@@ -0,0 +1,191 @@
+// Package fieldcrypt provides application-level encryption for sensitive columns.
+//
+// Values are sealed with AES-256-GCM and stored as "enc:{keyID}:{base64(nonce|ciphertext)}",
+// so rows written under an older key stay readable after rotation. Because ciphertext is
+// randomized, equality lookups go through a deterministic blind index (HMAC-SHA256 of the
+// plaintext) stored in a separate column.
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/array/banking-api/internal/config"
+)
+
+// ciphertextPrefix marks a stored value as encrypted; anything else is legacy plaintext
+const ciphertextPrefix = "enc:"
+
+var (
+	ErrNoKeyring  = errors.New("field encryption keyring is not configured")
+	ErrUnknownKey = errors.New("value was encrypted with an unknown key")
+	ErrMalformed  = errors.New("malformed encrypted value")
+)
+
+// Keyring holds the AES keys by ID, the active key used for new writes, and the blind index key
+type Keyring struct {
+	activeID string
+	aeads    map[string]cipher.AEAD
+	blindKey []byte
+}
+
+// NewKeyring builds a keyring from 32-byte AES keys indexed by key ID
+func NewKeyring(keys map[string][]byte, activeID string, blindIndexKey []byte) (*Keyring, error) {
+	if _, ok := keys[activeID]; !ok {
+		return nil, fmt.Errorf("active key %q is not in the key set", activeID)
+	}
+	if len(blindIndexKey) < 32 {
+		return nil, errors.New("blind index key must be at least 32 bytes")
+	}
+
+	aeads := make(map[string]cipher.AEAD, len(keys))
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("invalid key ID %q", id)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("key %q must be 32 bytes for AES-256, got %d", id, len(key))
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+		aeads[id] = aead
+	}
+
+	return &Keyring{activeID: activeID, aeads: aeads, blindKey: blindIndexKey}, nil
+}
+
+// NewKeyringFromConfig builds a keyring from FIELD_ENCRYPTION_* settings
+func NewKeyringFromConfig(cfg *config.EncryptionConfig) (*Keyring, error) {
+	keys, err := ParseKeys(cfg.Keys)
+	if err != nil {
+		return nil, err
+	}
+	blindKey, err := base64.StdEncoding.DecodeString(cfg.BlindIndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("blind index key is not valid base64: %w", err)
+	}
+	return NewKeyring(keys, cfg.ActiveKeyID, blindKey)
+}
+
+// ParseKeys parses a "keyID:base64key,keyID:base64key" list
+func ParseKeys(spec string) (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("key entry %q must be keyID:base64key", entry)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key %q is not valid base64: %w", id, err)
+		}
+		keys[id] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no field encryption keys configured")
+	}
+	return keys, nil
+}
+
+// ActiveKeyID returns the ID of the key used for new writes
+func (k *Keyring) ActiveKeyID() string {
+	return k.activeID
+}
+
+// Encrypt seals plaintext with the active key
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	aead := k.aeads[k.activeID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(k.activeID))
+	return ciphertextPrefix + k.activeID + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value produced by Encrypt with whichever key it names. Values without the
+// encryption prefix are legacy plaintext (not yet backfilled) and are returned unchanged.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	id, encoded, ok := strings.Cut(strings.TrimPrefix(value, ciphertextPrefix), ":")
+	if !ok {
+		return "", ErrMalformed
+	}
+	aead, ok := k.aeads[id]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownKey, id)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", ErrMalformed
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(id))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	return string(plaintext), nil
+}
+
+// NeedsReencrypt reports whether a stored value is plaintext or sealed with a non-active key
+func (k *Keyring) NeedsReencrypt(value string) bool {
+	if !IsEncrypted(value) {
+		return true
+	}
+	id, _, _ := strings.Cut(strings.TrimPrefix(value, ciphertextPrefix), ":")
+	return id != k.activeID
+}
+
+// BlindIndex returns the hex HMAC-SHA256 of plaintext used for equality lookups
+func (k *Keyring) BlindIndex(plaintext string) string {
+	mac := hmac.New(sha256.New, k.blindKey)
+	mac.Write([]byte(plaintext))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsEncrypted reports whether value carries the encryption prefix
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, ciphertextPrefix)
+}
+
+var defaultKeyring atomic.Pointer[Keyring]
+
+// SetDefault installs the keyring used by the GORM serializer and model hooks
+func SetDefault(k *Keyring) {
+	defaultKeyring.Store(k)
+}
+
+// Default returns the installed keyring, or nil if none has been set
+func Default() *Keyring {
+	return defaultKeyring.Load()
+}
+
+// BlindIndex computes the blind index of plaintext with the default keyring
+func BlindIndex(plaintext string) (string, error) {
+	k := Default()
+	if k == nil {
+		return "", ErrNoKeyring
+	}
+	return k.BlindIndex(plaintext), nil
+}
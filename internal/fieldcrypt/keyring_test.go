@@ -0,0 +1,172 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/array/banking-api/internal/config"
+)
+
+func newTestKeyring(t *testing.T, activeID string, ids ...string) *Keyring {
+	t.Helper()
+	keys := make(map[string][]byte, len(ids))
+	for i, id := range ids {
+		keys[id] = bytes.Repeat([]byte{byte(i + 1)}, 32)
+	}
+	k, err := NewKeyring(keys, activeID, bytes.Repeat([]byte{0xAA}, 32))
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	return k
+}
+
+func TestKeyring_RoundTrip(t *testing.T) {
+	k := newTestKeyring(t, "k1", "k1")
+
+	ciphertext, err := k.Encrypt("1234567890")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if !strings.HasPrefix(ciphertext, "enc:k1:") {
+		t.Errorf("ciphertext %q does not name the active key", ciphertext)
+	}
+	if strings.Contains(ciphertext, "1234567890") {
+		t.Errorf("ciphertext leaks the plaintext")
+	}
+
+	again, _ := k.Encrypt("1234567890")
+	if again == ciphertext {
+		t.Errorf("expected randomized ciphertext, got the same value twice")
+	}
+
+	plaintext, err := k.Decrypt(ciphertext)
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if plaintext != "1234567890" {
+		t.Errorf("expected 1234567890, got %q", plaintext)
+	}
+}
+
+func TestKeyring_DecryptPlaintextPassthrough(t *testing.T) {
+	k := newTestKeyring(t, "k1", "k1")
+
+	plaintext, err := k.Decrypt("1234567890")
+	if err != nil || plaintext != "1234567890" {
+		t.Errorf("expected legacy plaintext unchanged, got %q, %v", plaintext, err)
+	}
+	if !k.NeedsReencrypt("1234567890") {
+		t.Errorf("expected plaintext to need encryption")
+	}
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	old := newTestKeyring(t, "k1", "k1")
+	rotated := newTestKeyring(t, "k2", "k1", "k2")
+
+	legacy, _ := old.Encrypt("1234567890")
+
+	plaintext, err := rotated.Decrypt(legacy)
+	if err != nil || plaintext != "1234567890" {
+		t.Fatalf("rotated keyring should read old ciphertext, got %q, %v", plaintext, err)
+	}
+	if !rotated.NeedsReencrypt(legacy) {
+		t.Errorf("expected value sealed with k1 to need re-encryption under k2")
+	}
+
+	fresh, _ := rotated.Encrypt("1234567890")
+	if !strings.HasPrefix(fresh, "enc:k2:") {
+		t.Errorf("expected new writes under k2, got %q", fresh)
+	}
+	if rotated.NeedsReencrypt(fresh) {
+		t.Errorf("value sealed with the active key should not need re-encryption")
+	}
+	if _, err := old.Decrypt(fresh); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("expected ErrUnknownKey, got %v", err)
+	}
+}
+
+func TestKeyring_DecryptRejectsTampering(t *testing.T) {
+	k := newTestKeyring(t, "k1", "k1")
+	ciphertext, _ := k.Encrypt("1234567890")
+
+	tests := map[string]string{
+		"missing key separator": "enc:k1",
+		"bad base64":            "enc:k1:!!!",
+		"truncated":             "enc:k1:AAAA",
+		"key ID swapped":        strings.Replace(ciphertext, "enc:k1:", "enc:k2:", 1),
+	}
+	k2 := newTestKeyring(t, "k1", "k1", "k2")
+	for name, value := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := k2.Decrypt(value); err == nil {
+				t.Errorf("expected error decrypting %q", value)
+			}
+		})
+	}
+}
+
+func TestKeyring_BlindIndex(t *testing.T) {
+	k := newTestKeyring(t, "k1", "k1")
+	rotated := newTestKeyring(t, "k2", "k1", "k2")
+
+	if k.BlindIndex("1234567890") != k.BlindIndex("1234567890") {
+		t.Errorf("blind index must be deterministic")
+	}
+	if k.BlindIndex("1234567890") == k.BlindIndex("1234567891") {
+		t.Errorf("different plaintexts should not share a blind index")
+	}
+	if k.BlindIndex("1234567890") != rotated.BlindIndex("1234567890") {
+		t.Errorf("rotating AES keys must not change the blind index")
+	}
+}
+
+func TestNewKeyring_Validation(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	blind := bytes.Repeat([]byte{2}, 32)
+
+	tests := []struct {
+		name     string
+		keys     map[string][]byte
+		activeID string
+		blind    []byte
+	}{
+		{"active key missing", map[string][]byte{"k1": key}, "k2", blind},
+		{"short AES key", map[string][]byte{"k1": key[:16]}, "k1", blind},
+		{"short blind key", map[string][]byte{"k1": key}, "k1", blind[:16]},
+		{"key ID with colon", map[string][]byte{"k1": key, "a:b": key}, "k1", blind},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewKeyring(tt.keys, tt.activeID, tt.blind); err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
+
+func TestNewKeyringFromConfig(t *testing.T) {
+	b64 := func(b byte) string { return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, 32)) }
+
+	k, err := NewKeyringFromConfig(&config.EncryptionConfig{
+		Keys:          "k1:" + b64(1) + ", k2:" + b64(2),
+		ActiveKeyID:   "k2",
+		BlindIndexKey: b64(3),
+	})
+	if err != nil {
+		t.Fatalf("NewKeyringFromConfig: %v", err)
+	}
+	if k.ActiveKeyID() != "k2" {
+		t.Errorf("expected active key k2, got %s", k.ActiveKeyID())
+	}
+
+	if _, err := NewKeyringFromConfig(&config.EncryptionConfig{Keys: "k1", ActiveKeyID: "k1", BlindIndexKey: b64(3)}); err == nil {
+		t.Errorf("expected error for key entry without ID separator")
+	}
+	if _, err := NewKeyringFromConfig(&config.EncryptionConfig{Keys: "", ActiveKeyID: "k1", BlindIndexKey: b64(3)}); err == nil {
+		t.Errorf("expected error for empty key set")
+	}
+}
@@ -0,0 +1,64 @@
+package fieldcrypt
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+// SerializerName is the GORM serializer tag value: `gorm:"serializer:encrypted"`
+const SerializerName = "encrypted"
+
+func init() {
+	schema.RegisterSerializer(SerializerName, EncryptedString{})
+}
+
+// EncryptedString is a GORM serializer for string fields that encrypts on write and decrypts
+// on read using the default keyring. The Go field stays a plain string.
+type EncryptedString struct{}
+
+// Scan implements schema.SerializerInterface
+func (EncryptedString) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var stored string
+	switch v := dbValue.(type) {
+	case nil:
+		return field.Set(ctx, dst, "")
+	case []byte:
+		stored = string(v)
+	case string:
+		stored = v
+	default:
+		return fmt.Errorf("unsupported type %T for encrypted field %s", dbValue, field.Name)
+	}
+
+	if !IsEncrypted(stored) {
+		return field.Set(ctx, dst, stored)
+	}
+	k := Default()
+	if k == nil {
+		return ErrNoKeyring
+	}
+	plaintext, err := k.Decrypt(stored)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt %s: %w", field.Name, err)
+	}
+	return field.Set(ctx, dst, plaintext)
+}
+
+// Value implements schema.SerializerValuerInterface
+func (EncryptedString) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	plaintext, ok := fieldValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("encrypted field %s must be a string, got %T", field.Name, fieldValue)
+	}
+	if plaintext == "" {
+		return "", nil
+	}
+	k := Default()
+	if k == nil {
+		return nil, ErrNoKeyring
+	}
+	return k.Encrypt(plaintext)
+}
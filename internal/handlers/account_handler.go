@@ -56,7 +56,7 @@ func (h *AccountHandler) CreateAccount(c echo.Context) error {
 		return SendError(c, errors.ValidationGeneral, errors.WithDetails("Invalid request body"))
 	}
 
-	if err := c.Validate(req); err != nil {
+	if err := validateRequest(c, req); err != nil {
 		return SendError(c, errors.ValidationGeneral, errors.WithDetails(err.Error()))
 	}
 
@@ -68,7 +68,7 @@ func (h *AccountHandler) CreateAccount(c echo.Context) error {
 		}
 	}
 
-	account, err := h.accountService.CreateAccount(userID, req.AccountType, initialDeposit)
+	account, err := h.accountService.CreateAccount(c.Request().Context(), userID, req.AccountType, initialDeposit)
 	if err != nil {
 		if err == services.ErrAccountAlreadyExists {
 			return SendError(c, errors.ValidationGeneral, errors.WithDetails(err.Error()))
@@ -111,7 +111,7 @@ func (h *AccountHandler) GetAccount(c echo.Context) error {
 		return SendError(c, errors.ValidationInvalidFormat, errors.WithDetails("Invalid account ID"))
 	}
 
-	account, err := h.accountService.GetAccountByID(accountID, &userID)
+	account, err := h.accountService.GetAccountByID(c.Request().Context(), accountID, &userID)
 	if err != nil {
 		if err == services.ErrAccountNotFound {
 			return SendError(c, errors.AccountNotFound)
@@ -182,11 +182,11 @@ func (h *AccountHandler) UpdateAccountStatus(c echo.Context) error {
 		return SendError(c, errors.ValidationGeneral, errors.WithDetails("Invalid request body"))
 	}
 
-	if err := c.Validate(req); err != nil {
+	if err := validateRequest(c, req); err != nil {
 		return SendError(c, errors.ValidationGeneral, errors.WithDetails(err.Error()))
 	}
 
-	account, err := h.accountService.UpdateAccountStatus(accountID, &userID, req.Status)
+	account, err := h.accountService.UpdateAccountStatus(c.Request().Context(), accountID, &userID, req.Status)
 	if err != nil {
 		if err == services.ErrAccountNotFound {
 			return SendError(c, errors.AccountNotFound)
@@ -227,7 +227,7 @@ func (h *AccountHandler) CloseAccount(c echo.Context) error {
 		return SendError(c, errors.ValidationInvalidFormat, errors.WithDetails("Invalid account ID"))
 	}
 
-	err = h.accountService.CloseAccount(accountID, userID)
+	err = h.accountService.CloseAccount(c.Request().Context(), accountID, userID)
 	if err != nil {
 		if err == services.ErrAccountNotFound {
 			return SendError(c, errors.AccountNotFound)
@@ -280,7 +280,7 @@ func (h *AccountHandler) PerformTransaction(c echo.Context) error {
 		return SendError(c, errors.ValidationGeneral, errors.WithDetails("Invalid request body"))
 	}
 
-	if err := c.Validate(req); err != nil {
+	if err := validateRequest(c, req); err != nil {
 		return err
 	}
 
@@ -289,7 +289,7 @@ func (h *AccountHandler) PerformTransaction(c echo.Context) error {
 		return SendError(c, errors.TransactionInvalidAmount, errors.WithDetails("Amount must be greater than 0"))
 	}
 
-	transaction, err := h.accountService.PerformTransaction(accountID, amount, req.Type, req.Description, &userID)
+	transaction, err := h.accountService.PerformTransaction(c.Request().Context(), accountID, amount, req.Type, req.Description, &userID)
 	if err != nil {
 		return mapTransactionErr(c, err)
 	}
@@ -341,7 +341,7 @@ func (h *AccountHandler) Transfer(c echo.Context) error {
 		return SendError(c, errors.ValidationGeneral, errors.WithDetails("Invalid request body"))
 	}
 
-	if err := c.Validate(req); err != nil {
+	if err := validateRequest(c, req); err != nil {
 		return SendError(c, errors.ValidationGeneral, errors.WithDetails(err.Error()))
 	}
 
@@ -534,7 +534,7 @@ func (h *AccountHandler) GetAccountByIDAdmin(c echo.Context) error {
 		return SendError(c, errors.ValidationInvalidFormat, errors.WithDetails("Invalid account ID"))
 	}
 
-	account, err := h.accountService.GetAccountByID(accountID, nil)
+	account, err := h.accountService.GetAccountByID(c.Request().Context(), accountID, nil)
 	if err != nil {
 		if err == services.ErrAccountNotFound {
 			return SendError(c, errors.AccountNotFound)
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -124,7 +125,7 @@ func TestAccountHandler_CreateAccount(t *testing.T) {
 
 			if tt.expectedStatus == 201 {
 				mockAccountService.EXPECT().
-					CreateAccount(gomock.Any(), "checking", gomock.Any()).
+					CreateAccount(gomock.Any(), gomock.Any(), "checking", gomock.Any()).
 					Return(&models.Account{
 						ID:            uuid.New(),
 						AccountNumber: "1012345678",
@@ -164,8 +165,8 @@ func (s *AccountHandlerSuite) TestCreateAccount_WithInitialDeposit() {
 	}
 
 	s.mockService.EXPECT().
-		CreateAccount(s.testUserID, "checking", gomock.Any()).
-		DoAndReturn(func(_ uuid.UUID, _ string, amount decimal.Decimal) (*models.Account, error) {
+		CreateAccount(gomock.Any(), s.testUserID, "checking", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, _ string, amount decimal.Decimal) (*models.Account, error) {
 			if !amount.Equal(decimal.NewFromFloat(100.00)) {
 				s.T().Errorf("expected amount 100.00, got %s", amount.String())
 			}
@@ -207,7 +208,7 @@ func (s *AccountHandlerSuite) TestCreateAccount_AccountAlreadyExists() {
 	}
 
 	s.mockService.EXPECT().
-		CreateAccount(s.testUserID, "checking", decimal.Zero).
+		CreateAccount(gomock.Any(), s.testUserID, "checking", decimal.Zero).
 		Return(nil, services.ErrAccountAlreadyExists)
 
 	c, rec := s.createContextWithAuth("POST", "/accounts", reqBody, s.testUserID, "user")
@@ -230,7 +231,7 @@ func (s *AccountHandlerSuite) TestGetAccount_Success() {
 	}
 
 	s.mockService.EXPECT().
-		GetAccountByID(accountID, &s.testUserID).
+		GetAccountByID(gomock.Any(), accountID, &s.testUserID).
 		Return(expectedAccount, nil)
 
 	c, rec := s.createContextWithAuth("GET", "/accounts/"+accountID.String(), nil, s.testUserID, "user")
@@ -251,7 +252,7 @@ func (s *AccountHandlerSuite) TestGetAccount_NotFound() {
 	accountID := uuid.New()
 
 	s.mockService.EXPECT().
-		GetAccountByID(accountID, &s.testUserID).
+		GetAccountByID(gomock.Any(), accountID, &s.testUserID).
 		Return(nil, services.ErrAccountNotFound)
 
 	c, rec := s.createContextWithAuth("GET", "/accounts/"+accountID.String(), nil, s.testUserID, "user")
@@ -267,7 +268,7 @@ func (s *AccountHandlerSuite) TestGetAccount_Unauthorized() {
 	accountID := uuid.New()
 
 	s.mockService.EXPECT().
-		GetAccountByID(accountID, &s.testUserID).
+		GetAccountByID(gomock.Any(), accountID, &s.testUserID).
 		Return(nil, services.ErrUnauthorized)
 
 	c, rec := s.createContextWithAuth("GET", "/accounts/"+accountID.String(), nil, s.testUserID, "user")
@@ -337,8 +338,8 @@ func (s *AccountHandlerSuite) TestPerformTransaction_Credit() {
 	}
 
 	s.mockService.EXPECT().
-		PerformTransaction(accountID, gomock.Any(), "credit", "Deposit", &s.testUserID).
-		DoAndReturn(func(_ uuid.UUID, amount decimal.Decimal, _ string, _ string, _ *uuid.UUID) (*models.Transaction, error) {
+		PerformTransaction(gomock.Any(), accountID, gomock.Any(), "credit", "Deposit", &s.testUserID).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, amount decimal.Decimal, _ string, _ string, _ *uuid.UUID) (*models.Transaction, error) {
 			if !amount.Equal(decimal.NewFromFloat(50.00)) {
 				s.T().Errorf("expected amount 50.00, got %s", amount.String())
 			}
@@ -369,8 +370,8 @@ func (s *AccountHandlerSuite) TestPerformTransaction_InsufficientFunds() {
 	}
 
 	s.mockService.EXPECT().
-		PerformTransaction(accountID, gomock.Any(), "debit", "Withdrawal", &s.testUserID).
-		DoAndReturn(func(_ uuid.UUID, amount decimal.Decimal, _ string, _ string, _ *uuid.UUID) (*models.Transaction, error) {
+		PerformTransaction(gomock.Any(), accountID, gomock.Any(), "debit", "Withdrawal", &s.testUserID).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, amount decimal.Decimal, _ string, _ string, _ *uuid.UUID) (*models.Transaction, error) {
 			if !amount.Equal(decimal.NewFromFloat(1000.00)) {
 				s.T().Errorf("expected amount 1000.00, got %s", amount.String())
 			}
@@ -926,7 +927,7 @@ func (s *AccountHandlerSuite) TestGetAccountByIDAdmin_Success() {
 
 	var nilUserID *uuid.UUID
 	s.mockService.EXPECT().
-		GetAccountByID(accountID, nilUserID).
+		GetAccountByID(gomock.Any(), accountID, nilUserID).
 		Return(expectedAccount, nil)
 
 	c, rec := s.createContextWithAuth("GET", "/admin/accounts/"+accountID.String(), nil, s.testAdminID, "admin")
@@ -979,7 +980,7 @@ func (s *AccountHandlerSuite) TestCloseAccount_Success() {
 	accountID := uuid.New()
 
 	s.mockService.EXPECT().
-		CloseAccount(accountID, s.testUserID).
+		CloseAccount(gomock.Any(), accountID, s.testUserID).
 		Return(nil)
 
 	c, rec := s.createContextWithAuth("DELETE", "/accounts/"+accountID.String(), nil, s.testUserID, "user")
@@ -995,7 +996,7 @@ func (s *AccountHandlerSuite) TestCloseAccount_NonZeroBalance() {
 	accountID := uuid.New()
 
 	s.mockService.EXPECT().
-		CloseAccount(accountID, s.testUserID).
+		CloseAccount(gomock.Any(), accountID, s.testUserID).
 		Return(services.ErrAccountClosureNotAllowed)
 
 	c, rec := s.createContextWithAuth("DELETE", "/accounts/"+accountID.String(), nil, s.testUserID, "user")
@@ -70,7 +70,7 @@ func (h *AccountSummaryHandler) GetAccountSummary(c echo.Context) error {
 		targetUserID = &parsedUserID
 	}
 
-	summary, err := h.summaryService.GetAccountSummary(requestorID, targetUserID, isAdmin)
+	summary, err := h.summaryService.GetAccountSummary(c.Request().Context(), requestorID, targetUserID, isAdmin)
 	if err != nil {
 		return h.handleServiceError(c, err)
 	}
@@ -151,7 +151,7 @@ func (h *AccountSummaryHandler) GetAccountMetrics(c echo.Context) error {
 		endDate = &parsed
 	}
 
-	metrics, err := h.metricsService.GetAccountMetrics(requestorID, accountID, startDate, endDate, isAdmin)
+	metrics, err := h.metricsService.GetAccountMetrics(c.Request().Context(), requestorID, accountID, startDate, endDate, isAdmin)
 	if err != nil {
 		return h.handleServiceError(c, err)
 	}
@@ -240,7 +240,7 @@ func (h *AccountSummaryHandler) GetStatement(c echo.Context) error {
 		return SendError(c, apierrors.ValidationGeneral, apierrors.WithDetails("invalid period format"))
 	}
 
-	statement, err := h.statementService.GenerateStatement(requestorID, accountID, periodType, year, period, isAdmin)
+	statement, err := h.statementService.GenerateStatement(c.Request().Context(), requestorID, accountID, periodType, year, period, isAdmin)
 	if err != nil {
 		return h.handleServiceError(c, err)
 	}
@@ -92,7 +92,7 @@ func (s *AccountSummaryHandlerTestSuite) TestGetAccountSummary_RegularUser_Succe
 	}
 
 	s.mockSummaryService.EXPECT().
-		GetAccountSummary(s.regularUserID, (*uuid.UUID)(nil), false).
+		GetAccountSummary(gomock.Any(), s.regularUserID, (*uuid.UUID)(nil), false).
 		Return(summary, nil)
 
 	err := s.handler.GetAccountSummary(c)
@@ -124,7 +124,7 @@ func (s *AccountSummaryHandlerTestSuite) TestGetAccountSummary_AdminAccessingOth
 	}
 
 	s.mockSummaryService.EXPECT().
-		GetAccountSummary(s.adminUserID, &s.otherUserID, true).
+		GetAccountSummary(gomock.Any(), s.adminUserID, &s.otherUserID, true).
 		Return(summary, nil)
 
 	err := s.handler.GetAccountSummary(c)
@@ -169,7 +169,7 @@ func (s *AccountSummaryHandlerTestSuite) TestGetAccountSummary_ServiceError_Unau
 	c.QueryParams().Add("userId", s.otherUserID.String())
 
 	s.mockSummaryService.EXPECT().
-		GetAccountSummary(s.regularUserID, &s.otherUserID, false).
+		GetAccountSummary(gomock.Any(), s.regularUserID, &s.otherUserID, false).
 		Return(nil, services.ErrUnauthorized)
 
 	err := s.handler.GetAccountSummary(c)
@@ -187,7 +187,7 @@ func (s *AccountSummaryHandlerTestSuite) TestGetAccountSummary_ServiceError_NotF
 	c.Set("is_admin", false)
 
 	s.mockSummaryService.EXPECT().
-		GetAccountSummary(s.regularUserID, (*uuid.UUID)(nil), false).
+		GetAccountSummary(gomock.Any(), s.regularUserID, (*uuid.UUID)(nil), false).
 		Return(nil, services.ErrNotFound)
 
 	err := s.handler.GetAccountSummary(c)
@@ -236,7 +236,7 @@ func (s *AccountSummaryHandlerTestSuite) TestGetAccountMetrics_WithDates_Success
 	}
 
 	s.mockMetricsService.EXPECT().
-		GetAccountMetrics(s.regularUserID, s.accountID, &parsedStart, &parsedEnd, false).
+		GetAccountMetrics(gomock.Any(), s.regularUserID, s.accountID, &parsedStart, &parsedEnd, false).
 		Return(metrics, nil)
 
 	err := s.handler.GetAccountMetrics(c)
@@ -268,7 +268,7 @@ func (s *AccountSummaryHandlerTestSuite) TestGetAccountMetrics_WithoutDates_Succ
 	}
 
 	s.mockMetricsService.EXPECT().
-		GetAccountMetrics(s.regularUserID, s.accountID, (*time.Time)(nil), (*time.Time)(nil), false).
+		GetAccountMetrics(gomock.Any(), s.regularUserID, s.accountID, (*time.Time)(nil), (*time.Time)(nil), false).
 		Return(metrics, nil)
 
 	err := s.handler.GetAccountMetrics(c)
@@ -332,7 +332,7 @@ func (s *AccountSummaryHandlerTestSuite) TestGetAccountMetrics_ServiceError_Unau
 	c.QueryParams().Add("accountId", s.accountID.String())
 
 	s.mockMetricsService.EXPECT().
-		GetAccountMetrics(s.regularUserID, s.accountID, (*time.Time)(nil), (*time.Time)(nil), false).
+		GetAccountMetrics(gomock.Any(), s.regularUserID, s.accountID, (*time.Time)(nil), (*time.Time)(nil), false).
 		Return(nil, services.ErrUnauthorized)
 
 	err := s.handler.GetAccountMetrics(c)
@@ -381,7 +381,7 @@ func (s *AccountSummaryHandlerTestSuite) TestGetStatement_Monthly_Success() {
 	}
 
 	s.mockStatementService.EXPECT().
-		GenerateStatement(s.regularUserID, s.accountID, "monthly", 2024, 1, false).
+		GenerateStatement(gomock.Any(), s.regularUserID, s.accountID, "monthly", 2024, 1, false).
 		Return(statement, nil)
 
 	err := s.handler.GetStatement(c)
@@ -430,7 +430,7 @@ func (s *AccountSummaryHandlerTestSuite) TestGetStatement_Quarterly_Success() {
 	}
 
 	s.mockStatementService.EXPECT().
-		GenerateStatement(s.regularUserID, s.accountID, "quarterly", 2024, 2, false).
+		GenerateStatement(gomock.Any(), s.regularUserID, s.accountID, "quarterly", 2024, 2, false).
 		Return(statement, nil)
 
 	err := s.handler.GetStatement(c)
@@ -568,7 +568,7 @@ func (s *AccountSummaryHandlerTestSuite) TestGetStatement_ServiceError_InvalidPe
 	c.QueryParams().Add("period", "13")
 
 	s.mockStatementService.EXPECT().
-		GenerateStatement(s.regularUserID, s.accountID, "monthly", 2024, 13, false).
+		GenerateStatement(gomock.Any(), s.regularUserID, s.accountID, "monthly", 2024, 13, false).
 		Return(nil, services.ErrInvalidMonth)
 
 	err := s.handler.GetStatement(c)
@@ -592,7 +592,7 @@ func (s *AccountSummaryHandlerTestSuite) TestGetStatement_ServiceError_Unauthori
 	c.QueryParams().Add("period", "1")
 
 	s.mockStatementService.EXPECT().
-		GenerateStatement(s.regularUserID, s.accountID, "monthly", 2024, 1, false).
+		GenerateStatement(gomock.Any(), s.regularUserID, s.accountID, "monthly", 2024, 1, false).
 		Return(nil, services.ErrUnauthorized)
 
 	err := s.handler.GetStatement(c)
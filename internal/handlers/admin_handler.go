@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"math"
 	"net/http"
 
 	"github.com/array/banking-api/internal/errors"
@@ -45,7 +46,7 @@ func (h *AdminHandler) UnlockUser(c echo.Context) error {
 		return SendError(c, errors.CustomerInvalidID, errors.WithDetails("User ID must be a valid UUID"))
 	}
 
-	user, err := h.userRepo.GetByID(userID)
+	user, err := h.userRepo.GetByID(c.Request().Context(), userID)
 	if err != nil {
 		if err == repositories.ErrUserNotFound {
 			return SendError(c, errors.CustomerNotFound)
@@ -53,7 +54,7 @@ func (h *AdminHandler) UnlockUser(c echo.Context) error {
 		return SendSystemError(c, err)
 	}
 
-	if err := h.userRepo.UnlockAccount(userID); err != nil {
+	if err := h.userRepo.UnlockAccount(c.Request().Context(), userID); err != nil {
 		return SendSystemError(c, err)
 	}
 
@@ -78,27 +79,22 @@ func (h *AdminHandler) UnlockUser(c echo.Context) error {
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page (max 100)" default(20)
 // @Success 200 {object} SuccessResponse "Users retrieved successfully with pagination metadata"
-// @Failure 400 {object} errors.ErrorResponse "VALIDATION_001 - Invalid pagination parameters"
+// @Failure 422 {object} errors.ErrorResponse "VALIDATION_008 - Invalid pagination parameters"
 // @Failure 401 {object} errors.ErrorResponse "AUTH_002 - Missing or invalid authentication"
 // @Failure 403 {object} errors.ErrorResponse "AUTH_005 - Requires admin role"
 // @Failure 500 {object} errors.ErrorResponse "SYSTEM_001 - Internal server error"
 // @Router /admin/users [get]
 func (h *AdminHandler) ListUsers(c echo.Context) error {
-	page := getIntParam(c, "page", 1)
-	limit := getIntParam(c, "limit", 20)
-
-	if page < 1 {
-		return SendError(c, errors.ValidationGeneral,
-			errors.WithDetails("page: must be greater than 0"))
-	}
-	if limit < 1 || limit > 100 {
-		return SendError(c, errors.ValidationGeneral,
-			errors.WithDetails("limit: must be between 1 and 100"))
+	q := newQueryParams(c)
+	page := q.Int("page", 1, 1, math.MaxInt32)
+	limit := q.Limit()
+	if !q.Valid() {
+		return q.SendError()
 	}
 
 	offset := (page - 1) * limit
 
-	users, total, err := h.userRepo.ListUsers(offset, limit)
+	users, total, err := h.userRepo.ListUsers(c.Request().Context(), offset, limit)
 	if err != nil {
 		return SendSystemError(c, err)
 	}
@@ -149,7 +145,7 @@ func (h *AdminHandler) GetUserByID(c echo.Context) error {
 		return SendError(c, errors.CustomerInvalidID, errors.WithDetails("User ID must be a valid UUID"))
 	}
 
-	user, err := h.userRepo.GetByID(userID)
+	user, err := h.userRepo.GetByID(c.Request().Context(), userID)
 	if err != nil {
 		if err == repositories.ErrUserNotFound {
 			return SendError(c, errors.CustomerNotFound)
@@ -200,7 +196,7 @@ func (h *AdminHandler) DeleteUser(c echo.Context) error {
 		return SendError(c, errors.ValidationGeneral, errors.WithDetails("Cannot delete your own account"))
 	}
 
-	user, err := h.userRepo.GetByID(userID)
+	user, err := h.userRepo.GetByID(c.Request().Context(), userID)
 	if err != nil {
 		if err == repositories.ErrUserNotFound {
 			return SendError(c, errors.CustomerNotFound)
@@ -208,7 +204,7 @@ func (h *AdminHandler) DeleteUser(c echo.Context) error {
 		return SendSystemError(c, err)
 	}
 
-	if err := h.userRepo.Delete(userID); err != nil {
+	if err := h.userRepo.Delete(c.Request().Context(), userID); err != nil {
 		return SendSystemError(c, err)
 	}
 
@@ -75,8 +75,8 @@ func (s *AdminHandlerSuite) TestUnlockUser() {
 			contextUserID:  adminUser.ID,
 			expectedStatus: http.StatusOK,
 			setupMocks: func() {
-				s.userRepo.EXPECT().GetByID(lockedUser.ID).Return(lockedUser, nil).Times(1)
-				s.userRepo.EXPECT().UnlockAccount(lockedUser.ID).Return(nil).Times(1)
+				s.userRepo.EXPECT().GetByID(gomock.Any(), lockedUser.ID).Return(lockedUser, nil).Times(1)
+				s.userRepo.EXPECT().UnlockAccount(gomock.Any(), lockedUser.ID).Return(nil).Times(1)
 				s.auditRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
 			},
 		},
@@ -95,7 +95,7 @@ func (s *AdminHandlerSuite) TestUnlockUser() {
 			expectedStatus: http.StatusNotFound,
 			expectedError:  "not found",
 			setupMocks: func() {
-				s.userRepo.EXPECT().GetByID(gomock.Any()).Return(nil, repositories.ErrUserNotFound).Times(1)
+				s.userRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(nil, repositories.ErrUserNotFound).Times(1)
 			},
 		},
 	}
@@ -171,7 +171,7 @@ func (s *AdminHandlerSuite) TestListUsers() {
 					s.createTestUser(models.RoleCustomer),
 					s.createTestUser(models.RoleAdmin),
 				}
-				s.userRepo.EXPECT().ListUsers(0, 20).Return(users, int64(len(users)), nil).Times(1)
+				s.userRepo.EXPECT().ListUsers(gomock.Any(), 0, 20).Return(users, int64(len(users)), nil).Times(1)
 				return users
 			},
 		},
@@ -188,7 +188,7 @@ func (s *AdminHandlerSuite) TestListUsers() {
 					s.createTestUser(models.RoleCustomer),
 					s.createTestUser(models.RoleCustomer),
 				}
-				s.userRepo.EXPECT().ListUsers(0, 3).Return(users, int64(len(users)), nil).Times(1)
+				s.userRepo.EXPECT().ListUsers(gomock.Any(), 0, 3).Return(users, int64(len(users)), nil).Times(1)
 				return users
 			},
 		},
@@ -234,6 +234,42 @@ func (s *AdminHandlerSuite) TestListUsers() {
 	}
 }
 
+func (s *AdminHandlerSuite) TestListUsers_InvalidQuery() {
+	tests := []struct {
+		name   string
+		query  string
+		detail string
+	}{
+		{name: "malformed page", query: "page=abc", detail: "page: must be an integer"},
+		{name: "zero page", query: "page=0", detail: "page: must be at least 1"},
+		{name: "malformed limit", query: "limit=ten", detail: "limit: must be an integer"},
+		{name: "limit over max", query: "limit=101", detail: "limit: must be at most 100"},
+		{name: "negative limit", query: "limit=-5", detail: "limit: must be at least 1"},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			ctrl := gomock.NewController(s.T())
+			defer ctrl.Finish()
+			s.userRepo = repository_mocks.NewMockUserRepositoryInterface(ctrl)
+			s.auditRepo = repository_mocks.NewMockAuditLogRepositoryInterface(ctrl)
+			s.handler = NewAdminHandler(s.userRepo, s.auditRepo)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/users?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := s.e.NewContext(req, rec)
+
+			s.NoError(s.handler.ListUsers(c))
+			s.Equal(http.StatusUnprocessableEntity, rec.Code)
+
+			var errorResp ErrorResponse
+			s.NoError(json.Unmarshal(rec.Body.Bytes(), &errorResp))
+			s.Equal("VALIDATION_008", errorResp.Error.Code)
+			s.Contains(errorResp.Error.Details, tt.detail)
+		})
+	}
+}
+
 func (s *AdminHandlerSuite) TestGetUserByID() {
 	// Create test user
 	testUser := s.createTestUser(models.RoleCustomer)
@@ -253,7 +289,7 @@ func (s *AdminHandlerSuite) TestGetUserByID() {
 			contextUserID:  adminUser.ID,
 			expectedStatus: http.StatusOK,
 			setupMocks: func() {
-				s.userRepo.EXPECT().GetByID(testUser.ID).Return(testUser, nil).Times(1)
+				s.userRepo.EXPECT().GetByID(gomock.Any(), testUser.ID).Return(testUser, nil).Times(1)
 			},
 		},
 		{
@@ -271,7 +307,7 @@ func (s *AdminHandlerSuite) TestGetUserByID() {
 			expectedStatus: http.StatusNotFound,
 			expectedError:  "not found",
 			setupMocks: func() {
-				s.userRepo.EXPECT().GetByID(gomock.Any()).Return(nil, repositories.ErrUserNotFound).Times(1)
+				s.userRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(nil, repositories.ErrUserNotFound).Times(1)
 			},
 		},
 	}
@@ -354,8 +390,8 @@ func (s *AdminHandlerSuite) TestDeleteUser() {
 			contextUserID:  adminUser.ID,
 			expectedStatus: http.StatusOK,
 			setupMocks: func() {
-				s.userRepo.EXPECT().GetByID(userToDelete.ID).Return(userToDelete, nil).Times(1)
-				s.userRepo.EXPECT().Delete(userToDelete.ID).Return(nil).Times(1)
+				s.userRepo.EXPECT().GetByID(gomock.Any(), userToDelete.ID).Return(userToDelete, nil).Times(1)
+				s.userRepo.EXPECT().Delete(gomock.Any(), userToDelete.ID).Return(nil).Times(1)
 				s.auditRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
 			},
 		},
@@ -374,7 +410,7 @@ func (s *AdminHandlerSuite) TestDeleteUser() {
 			expectedStatus: http.StatusNotFound,
 			expectedError:  "not found",
 			setupMocks: func() {
-				s.userRepo.EXPECT().GetByID(gomock.Any()).Return(nil, repositories.ErrUserNotFound).Times(1)
+				s.userRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(nil, repositories.ErrUserNotFound).Times(1)
 			},
 		},
 	}
@@ -41,14 +41,14 @@ func (h *AuthHandler) Register(c echo.Context) error {
 		return SendError(c, errors.ValidationGeneral, errors.WithDetails("Invalid request body"))
 	}
 
-	if err := c.Validate(req); err != nil {
+	if err := validateRequest(c, req); err != nil {
 		return err
 	}
 
 	ipAddress := getClientIP(c)
 	userAgent := c.Request().UserAgent()
 
-	user, err := h.authService.Register(&req, ipAddress, userAgent)
+	user, err := h.authService.Register(c.Request().Context(), &req, ipAddress, userAgent)
 	if err != nil {
 		if err == services.ErrUserAlreadyExists {
 			return SendError(c, errors.CustomerAlreadyExists)
@@ -91,14 +91,14 @@ func (h *AuthHandler) Login(c echo.Context) error {
 		return SendError(c, errors.ValidationGeneral, errors.WithDetails("Invalid request body"))
 	}
 
-	if err := c.Validate(req); err != nil {
+	if err := validateRequest(c, req); err != nil {
 		return err
 	}
 
 	ipAddress := getClientIP(c)
 	userAgent := c.Request().UserAgent()
 
-	tokens, err := h.authService.Login(&req, ipAddress, userAgent)
+	tokens, err := h.authService.Login(c.Request().Context(), &req, ipAddress, userAgent)
 	if err != nil {
 		if err == services.ErrAccountLocked {
 			return SendError(c, errors.AuthAccountLocked)
@@ -133,14 +133,14 @@ func (h *AuthHandler) RefreshToken(c echo.Context) error {
 		return SendError(c, errors.ValidationGeneral, errors.WithDetails("Invalid request body"))
 	}
 
-	if err := c.Validate(req); err != nil {
+	if err := validateRequest(c, req); err != nil {
 		return err
 	}
 
 	ipAddress := getClientIP(c)
 	userAgent := c.Request().UserAgent()
 
-	tokens, err := h.authService.RefreshTokens(req.RefreshToken, ipAddress, userAgent)
+	tokens, err := h.authService.RefreshTokens(c.Request().Context(), req.RefreshToken, ipAddress, userAgent)
 	if err != nil {
 		if err == services.ErrInvalidRefreshToken {
 			return SendError(c, errors.AuthInvalidTokenFormat, errors.WithDetails("Invalid or expired refresh token"))
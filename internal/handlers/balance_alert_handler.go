@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	appErrors "github.com/array/banking-api/internal/errors"
+	"github.com/array/banking-api/internal/services"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// BalanceAlertHandler lets users manage balance threshold alerts on their registered external accounts
+type BalanceAlertHandler struct {
+	alerts *services.BalanceAlertService
+}
+
+// NewBalanceAlertHandler creates a new balance alert handler
+func NewBalanceAlertHandler(alerts *services.BalanceAlertService) *BalanceAlertHandler {
+	return &BalanceAlertHandler{alerts: alerts}
+}
+
+// CreateRule creates a balance alert rule on one of the caller's registered external accounts
+func (h *BalanceAlertHandler) CreateRule(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return SendError(c, appErrors.AuthMissingToken)
+	}
+
+	var req services.CreateBalanceAlertRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid request body"))
+	}
+	if err := validateRequest(c, req); err != nil {
+		return err
+	}
+
+	rule, err := h.alerts.CreateRule(c.Request().Context(), userID, req)
+	if err != nil {
+		return sendBalanceAlertError(c, err)
+	}
+	return c.JSON(http.StatusCreated, SuccessResponse{
+		Data:    rule,
+		Message: "Balance alert rule created",
+	})
+}
+
+// ListRules lists the caller's balance alert rules with their last evaluation
+func (h *BalanceAlertHandler) ListRules(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return SendError(c, appErrors.AuthMissingToken)
+	}
+
+	q := newQueryParams(c)
+	offset := q.Offset()
+	limit := q.Limit()
+	if !q.Valid() {
+		return q.SendError()
+	}
+
+	rules, total, err := h.alerts.ListRules(c.Request().Context(), userID, offset, limit)
+	if err != nil {
+		return SendSystemError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    rules,
+		Message: "Balance alert rules retrieved",
+		Meta: map[string]interface{}{
+			"total":  total,
+			"offset": offset,
+			"limit":  limit,
+		},
+	})
+}
+
+// GetRule returns one of the caller's balance alert rules
+func (h *BalanceAlertHandler) GetRule(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return SendError(c, appErrors.AuthMissingToken)
+	}
+	ruleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid balance alert rule ID"))
+	}
+
+	rule, err := h.alerts.GetRule(c.Request().Context(), userID, ruleID)
+	if err != nil {
+		return sendBalanceAlertError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data: rule,
+	})
+}
+
+// UpdateRule replaces the threshold, direction, channel and schedule of one of the caller's rules
+func (h *BalanceAlertHandler) UpdateRule(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return SendError(c, appErrors.AuthMissingToken)
+	}
+	ruleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid balance alert rule ID"))
+	}
+
+	var req services.BalanceAlertRuleUpdate
+	if err := c.Bind(&req); err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid request body"))
+	}
+	if err := validateRequest(c, req); err != nil {
+		return err
+	}
+
+	rule, err := h.alerts.UpdateRule(c.Request().Context(), userID, ruleID, req)
+	if err != nil {
+		return sendBalanceAlertError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    rule,
+		Message: "Balance alert rule updated",
+	})
+}
+
+// DeleteRule deletes one of the caller's balance alert rules
+func (h *BalanceAlertHandler) DeleteRule(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return SendError(c, appErrors.AuthMissingToken)
+	}
+	ruleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid balance alert rule ID"))
+	}
+
+	if err := h.alerts.DeleteRule(c.Request().Context(), userID, ruleID); err != nil {
+		return sendBalanceAlertError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{Message: "Balance alert rule deleted"})
+}
+
+func sendBalanceAlertError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrBalanceAlertRuleNotFound):
+		return SendError(c, appErrors.BalanceAlertRuleNotFound)
+	case errors.Is(err, services.ErrExternalAccountNotFound):
+		return SendError(c, appErrors.NorthwindAccountNotFound)
+	case errors.Is(err, services.ErrInvalidBalanceAlertRule):
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails(err.Error()))
+	default:
+		return SendSystemError(c, err)
+	}
+}
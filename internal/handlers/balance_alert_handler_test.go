@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/repositories/repository_mocks"
+	"github.com/array/banking-api/internal/services"
+	"github.com/array/banking-api/internal/validation"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBalanceAlertHandlerTest(t *testing.T) (*BalanceAlertHandler, *repository_mocks.MockBalanceAlertRuleRepositoryInterface, *repository_mocks.MockNorthwindExternalAccountRepositoryInterface) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	rules := repository_mocks.NewMockBalanceAlertRuleRepositoryInterface(ctrl)
+	accounts := repository_mocks.NewMockNorthwindExternalAccountRepositoryInterface(ctrl)
+	svc := services.NewBalanceAlertService(nil, rules, accounts, nil, nil, slog.Default())
+	return NewBalanceAlertHandler(svc), rules, accounts
+}
+
+func balanceAlertContext(method, body string, userID uuid.UUID, ruleID string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	e.Validator = validation.EchoValidator()
+	req := httptest.NewRequest(method, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user_id", userID)
+	if ruleID != "" {
+		c.SetParamNames("id")
+		c.SetParamValues(ruleID)
+	}
+	return c, rec
+}
+
+func TestBalanceAlertHandler_CreateRule(t *testing.T) {
+	handler, rules, accounts := newBalanceAlertHandlerTest(t)
+	userID := uuid.New()
+	account := &models.NorthwindExternalAccount{ID: uuid.New(), UserID: &userID}
+	accounts.EXPECT().GetByID(gomock.Any(), account.ID).Return(account, nil)
+	rules.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, rule *models.BalanceAlertRule) error {
+		assert.Equal(t, userID, rule.UserID)
+		assert.Equal(t, "50000.00", rule.Threshold.StringFixed(2))
+		assert.Equal(t, models.BalanceAlertDirectionBelow, rule.Direction)
+		return nil
+	})
+
+	body := `{"external_account_id":"` + account.ID.String() + `","threshold":50000,"direction":"BELOW","channel":"EMAIL"}`
+	c, rec := balanceAlertContext(http.MethodPost, body, userID, "")
+	require.NoError(t, handler.CreateRule(c))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestBalanceAlertHandler_CreateRule_Validation(t *testing.T) {
+	handler, _, _ := newBalanceAlertHandlerTest(t)
+	for _, body := range []string{
+		`{"external_account_id":"` + uuid.NewString() + `","threshold":0,"direction":"BELOW","channel":"EMAIL"}`,
+		`{"external_account_id":"` + uuid.NewString() + `","threshold":10,"direction":"SIDEWAYS","channel":"EMAIL"}`,
+		`{"threshold":10,"direction":"BELOW","channel":"SMS"}`,
+	} {
+		c, _ := balanceAlertContext(http.MethodPost, body, uuid.New(), "")
+		// validation failures are rendered by the error handler middleware
+		assert.Error(t, handler.CreateRule(c), body)
+	}
+}
+
+func TestBalanceAlertHandler_GetRule_OtherUsersRuleNotFound(t *testing.T) {
+	handler, rules, _ := newBalanceAlertHandlerTest(t)
+	rule := &models.BalanceAlertRule{ID: uuid.New(), UserID: uuid.New()}
+	rules.EXPECT().GetByID(gomock.Any(), rule.ID).Return(rule, nil)
+
+	c, rec := balanceAlertContext(http.MethodGet, "", uuid.New(), rule.ID.String())
+	require.NoError(t, handler.GetRule(c))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	var body map[string]map[string]interface{}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.Equal(t, "BALANCE_ALERT_001", body["error"]["code"])
+}
+
+func TestBalanceAlertHandler_DeleteRule(t *testing.T) {
+	handler, rules, _ := newBalanceAlertHandlerTest(t)
+	userID := uuid.New()
+	rule := &models.BalanceAlertRule{ID: uuid.New(), UserID: userID}
+	rules.EXPECT().GetByID(gomock.Any(), rule.ID).Return(rule, nil)
+	rules.EXPECT().Delete(gomock.Any(), rule.ID).Return(nil)
+
+	c, rec := balanceAlertContext(http.MethodDelete, "", userID, rule.ID.String())
+	require.NoError(t, handler.DeleteRule(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rules.EXPECT().GetByID(gomock.Any(), rule.ID).Return(nil, repositories.ErrBalanceAlertRuleNotFound)
+	c, rec = balanceAlertContext(http.MethodDelete, "", userID, rule.ID.String())
+	require.NoError(t, handler.DeleteRule(c))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -89,7 +89,7 @@ func (s *CustomerHandlerTestSuite) TestSearchCustomers_SuccessfulSearchWithResul
 
 	// Service expectations
 	s.mockSearchService.EXPECT().
-		SearchCustomers("john@example.com", models.SearchTypeEmail, 0, 10).
+		SearchCustomers(gomock.Any(), "john@example.com", models.SearchTypeEmail, 0, 10).
 		Return(results, int64(1), nil)
 
 	// Metrics expectations
@@ -174,7 +174,7 @@ func (s *CustomerHandlerTestSuite) TestSearchCustomers_ServiceError() {
 
 	// Service expectations
 	s.mockSearchService.EXPECT().
-		SearchCustomers("test@example.com", models.SearchTypeEmail, 0, 10).
+		SearchCustomers(gomock.Any(), "test@example.com", models.SearchTypeEmail, 0, 10).
 		Return(nil, int64(0), errors.New("database error"))
 
 	// Metrics expectations
@@ -218,7 +218,7 @@ func (s *CustomerHandlerTestSuite) TestGetCustomerProfile_AdminRetrievesCustomer
 		Role:      models.RoleCustomer,
 	}
 	s.mockProfileService.EXPECT().
-		GetCustomerProfile(customerID).
+		GetCustomerProfile(gomock.Any(), customerID).
 		Return(user, nil)
 
 	handler := NewCustomerHandler(s.mockSearchService, s.mockProfileService, s.mockAccountService, s.mockPasswordService, s.mockAuditService, s.logger, s.mockMetrics)
@@ -257,7 +257,7 @@ func (s *CustomerHandlerTestSuite) TestGetCustomerProfile_CustomerRetrievesOwnPr
 		Role:      models.RoleCustomer,
 	}
 	s.mockProfileService.EXPECT().
-		GetCustomerProfile(customerID).
+		GetCustomerProfile(gomock.Any(), customerID).
 		Return(user, nil)
 
 	handler := NewCustomerHandler(s.mockSearchService, s.mockProfileService, s.mockAccountService, s.mockPasswordService, s.mockAuditService, s.logger, s.mockMetrics)
@@ -299,7 +299,7 @@ func (s *CustomerHandlerTestSuite) TestGetCustomerProfile_CustomerCannotAccessOt
 		Role:      models.RoleCustomer,
 	}
 	s.mockProfileService.EXPECT().
-		GetCustomerProfile(otherCustomerID).
+		GetCustomerProfile(gomock.Any(), otherCustomerID).
 		Return(expectedCustomer, nil)
 
 	handler := NewCustomerHandler(s.mockSearchService, s.mockProfileService, s.mockAccountService, s.mockPasswordService, s.mockAuditService, s.logger, s.mockMetrics)
@@ -354,7 +354,7 @@ func (s *CustomerHandlerTestSuite) TestGetCustomerProfile_CustomerNotFound() {
 
 	// Setup mock expectations
 	s.mockProfileService.EXPECT().
-		GetCustomerProfile(customerID).
+		GetCustomerProfile(gomock.Any(), customerID).
 		Return(nil, services.ErrCustomerNotFound)
 
 	handler := NewCustomerHandler(s.mockSearchService, s.mockProfileService, s.mockAccountService, s.mockPasswordService, s.mockAuditService, s.logger, s.mockMetrics)
@@ -411,7 +411,7 @@ func (s *CustomerHandlerTestSuite) TestCreateCustomer_Successful() {
 
 	// Service expectations
 	s.mockProfileService.EXPECT().
-		CreateCustomer("newcustomer@example.com", "Jane", "Smith", models.RoleCustomer).
+		CreateCustomer(gomock.Any(), "newcustomer@example.com", "Jane", "Smith", models.RoleCustomer).
 		Return(user, "TempPass123!", nil)
 
 	// Metrics and logger expectations
@@ -517,7 +517,7 @@ func (s *CustomerHandlerTestSuite) TestCreateCustomer_EmailAlreadyExists() {
 
 	// Setup mock expectations
 	s.mockProfileService.EXPECT().
-		CreateCustomer("existing@example.com", "Jane", "Smith", models.RoleCustomer).
+		CreateCustomer(gomock.Any(), "existing@example.com", "Jane", "Smith", models.RoleCustomer).
 		Return(nil, "", services.ErrEmailAlreadyExists)
 
 	handler := NewCustomerHandler(s.mockSearchService, s.mockProfileService, s.mockAccountService, s.mockPasswordService, s.mockAuditService, s.logger, s.mockMetrics)
@@ -540,7 +540,7 @@ func (s *CustomerHandlerTestSuite) TestGetMyAccounts_Success() {
 	accounts := []*models.Account{
 		{ID: uuid.New(), UserID: userID, AccountNumber: "1012345678", AccountType: "checking", Balance: decimal.NewFromFloat(500), Status: "active"},
 	}
-	s.mockAccountService.EXPECT().GetCustomerAccounts(userID).Return(accounts, nil)
+	s.mockAccountService.EXPECT().GetCustomerAccounts(gomock.Any(), userID).Return(accounts, nil)
 	s.mockMetrics.EXPECT().RecordProcessingTime(gomock.Any(), gomock.Any()).AnyTimes()
 
 	handler := NewCustomerHandler(s.mockSearchService, s.mockProfileService, s.mockAccountService, s.mockPasswordService, s.mockAuditService, s.logger, s.mockMetrics)
@@ -573,7 +573,7 @@ func (s *CustomerHandlerTestSuite) TestUpdateMyPassword_Success() {
 	c.Set("user_id", userID)
 	c.Set("user_role", models.RoleCustomer)
 
-	s.mockPasswordService.EXPECT().CustomerUpdatePassword(userID, "OldPass123!", "NewPass456!X").Return(nil)
+	s.mockPasswordService.EXPECT().CustomerUpdatePassword(gomock.Any(), userID, "OldPass123!", "NewPass456!X").Return(nil)
 	s.mockMetrics.EXPECT().RecordProcessingTime(gomock.Any(), gomock.Any()).AnyTimes()
 
 	handler := NewCustomerHandler(s.mockSearchService, s.mockProfileService, s.mockAccountService, s.mockPasswordService, s.mockAuditService, s.logger, s.mockMetrics)
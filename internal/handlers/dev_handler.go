@@ -78,20 +78,11 @@ func (h *DevHandler) GenerateTestData(c echo.Context) error {
 		return SendError(c, appErrors.AuthInsufficientPermission)
 	}
 
-	count := getIntParam(c, "count", 100)
-	if count < 1 {
-		count = 1
-	}
-	if count > 1000 {
-		count = 1000
-	}
-
-	days := getIntParam(c, "days", 30)
-	if days < 1 {
-		days = 1
-	}
-	if days > 365 {
-		days = 365
+	q := newQueryParams(c)
+	count := q.Int("count", 100, 1, 1000)
+	days := q.Int("days", 30, 1, 365)
+	if !q.Valid() {
+		return q.SendError()
 	}
 
 	endDate := time.Now()
@@ -108,7 +99,7 @@ func (h *DevHandler) GenerateTestData(c echo.Context) error {
 
 	created := 0
 	for _, txn := range transactions {
-		if err := h.transactionRepo.Create(txn); err != nil {
+		if err := h.transactionRepo.Create(c.Request().Context(), txn); err != nil {
 			continue
 		}
 		created++
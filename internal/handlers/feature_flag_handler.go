@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	appErrors "github.com/array/banking-api/internal/errors"
+	"github.com/array/banking-api/internal/services"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// FeatureFlagHandler lets admins inspect and override feature flags at runtime
+type FeatureFlagHandler struct {
+	flags *services.FeatureFlagService
+}
+
+// NewFeatureFlagHandler creates a new feature flag admin handler
+func NewFeatureFlagHandler(flags *services.FeatureFlagService) *FeatureFlagHandler {
+	return &FeatureFlagHandler{flags: flags}
+}
+
+// SetFlagRolloutRequest sets a flag's rollout; send either enabled or percentage
+type SetFlagRolloutRequest struct {
+	Enabled    *bool `json:"enabled"`
+	Percentage *int  `json:"percentage"`
+}
+
+// SetFlagUserOverrideRequest forces a flag on or off for one user
+type SetFlagUserOverrideRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// ListFlags returns every flag with its effective rollout, where it comes from, and per-user overrides
+func (h *FeatureFlagHandler) ListFlags(c echo.Context) error {
+	statuses, err := h.flags.ListFlags(c.Request().Context())
+	if err != nil {
+		return SendSystemError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    statuses,
+		Message: "Feature flags retrieved",
+	})
+}
+
+// SetRollout overrides a flag's rollout percentage for everyone without a per-user override
+func (h *FeatureFlagHandler) SetRollout(c echo.Context) error {
+	adminID, err := getUserIDFromContext(c)
+	if err != nil {
+		return SendError(c, appErrors.AuthMissingToken)
+	}
+
+	var req SetFlagRolloutRequest
+	if err := c.Bind(&req); err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid request body"))
+	}
+	if (req.Enabled == nil) == (req.Percentage == nil) {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Provide exactly one of enabled or percentage"))
+	}
+	percentage := 0
+	switch {
+	case req.Percentage != nil:
+		percentage = *req.Percentage
+	case *req.Enabled:
+		percentage = 100
+	}
+
+	if err := h.flags.SetRollout(c.Request().Context(), services.FeatureFlag(c.Param("flag")), percentage, adminID); err != nil {
+		return sendFeatureFlagError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{Message: "Feature flag rollout updated"})
+}
+
+// ClearRollout removes the runtime rollout override so config or the code default applies again
+func (h *FeatureFlagHandler) ClearRollout(c echo.Context) error {
+	if err := h.flags.ClearRollout(c.Request().Context(), services.FeatureFlag(c.Param("flag"))); err != nil {
+		return sendFeatureFlagError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{Message: "Feature flag rollout override removed"})
+}
+
+// SetUserOverride forces a flag on or off for one user
+func (h *FeatureFlagHandler) SetUserOverride(c echo.Context) error {
+	adminID, err := getUserIDFromContext(c)
+	if err != nil {
+		return SendError(c, appErrors.AuthMissingToken)
+	}
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid user ID"))
+	}
+
+	var req SetFlagUserOverrideRequest
+	if err := c.Bind(&req); err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid request body"))
+	}
+	if req.Enabled == nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("enabled is required"))
+	}
+
+	if err := h.flags.SetUserOverride(c.Request().Context(), services.FeatureFlag(c.Param("flag")), userID, *req.Enabled, adminID); err != nil {
+		return sendFeatureFlagError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{Message: "Feature flag user override updated"})
+}
+
+// ClearUserOverride returns a user to the flag's rollout
+func (h *FeatureFlagHandler) ClearUserOverride(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid user ID"))
+	}
+	if err := h.flags.ClearUserOverride(c.Request().Context(), services.FeatureFlag(c.Param("flag")), userID); err != nil {
+		return sendFeatureFlagError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{Message: "Feature flag user override removed"})
+}
+
+func sendFeatureFlagError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrUnknownFeatureFlag):
+		return SendError(c, appErrors.FeatureFlagNotFound)
+	case errors.Is(err, services.ErrInvalidFlagRollout):
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails(err.Error()))
+	default:
+		return SendSystemError(c, err)
+	}
+}
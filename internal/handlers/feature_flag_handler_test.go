@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories/repository_mocks"
+	"github.com/array/banking-api/internal/services"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFeatureFlagHandlerTest(t *testing.T, configured map[services.FeatureFlag]int) (*FeatureFlagHandler, *repository_mocks.MockFeatureFlagOverrideRepositoryInterface) {
+	t.Helper()
+	repo := repository_mocks.NewMockFeatureFlagOverrideRepositoryInterface(gomock.NewController(t))
+	return NewFeatureFlagHandler(services.NewFeatureFlagService(repo, configured, slog.Default())), repo
+}
+
+func featureFlagContext(method, body string, params map[string]string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(method, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user_id", uuid.New())
+	var names, values []string
+	for name, value := range params {
+		names = append(names, name)
+		values = append(values, value)
+	}
+	c.SetParamNames(names...)
+	c.SetParamValues(values...)
+	return c, rec
+}
+
+func TestFeatureFlagHandler_ListFlags(t *testing.T) {
+	handler, repo := newFeatureFlagHandlerTest(t, map[services.FeatureFlag]int{services.FlagTransferRiskRules: 100})
+	userID := uuid.New()
+	repo.EXPECT().List(gomock.Any()).Return([]models.FeatureFlagOverride{
+		{Flag: string(services.FlagTransferAsyncInitiation), Percentage: 20},
+		{Flag: string(services.FlagTransferAsyncInitiation), UserID: &userID, Percentage: 100},
+	}, nil)
+
+	c, rec := featureFlagContext(http.MethodGet, "", nil)
+	require.NoError(t, handler.ListFlags(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var body struct {
+		Data []services.FeatureFlagStatus `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	byFlag := make(map[services.FeatureFlag]services.FeatureFlagStatus)
+	for _, st := range body.Data {
+		byFlag[st.Flag] = st
+	}
+	assert.Equal(t, services.FlagSourceConfig, byFlag[services.FlagTransferRiskRules].Source)
+	assert.Equal(t, 100, byFlag[services.FlagTransferRiskRules].Percentage)
+	assert.Equal(t, services.FlagSourceOverride, byFlag[services.FlagTransferAsyncInitiation].Source)
+	assert.Equal(t, 20, byFlag[services.FlagTransferAsyncInitiation].Percentage)
+	assert.Equal(t, []services.FeatureFlagUserOverride{{UserID: userID, Enabled: true}}, byFlag[services.FlagTransferAsyncInitiation].UserOverrides)
+	assert.Equal(t, services.FlagSourceDefault, byFlag[services.FlagTransferApprovalWorkflow].Source)
+}
+
+func TestFeatureFlagHandler_SetRollout(t *testing.T) {
+	handler, repo := newFeatureFlagHandlerTest(t, nil)
+	repo.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, o *models.FeatureFlagOverride) error {
+		assert.Equal(t, string(services.FlagTransferRiskRules), o.Flag)
+		assert.Nil(t, o.UserID)
+		assert.Equal(t, 100, o.Percentage)
+		assert.NotNil(t, o.UpdatedBy)
+		return nil
+	})
+
+	c, rec := featureFlagContext(http.MethodPut, `{"enabled":true}`, map[string]string{"flag": string(services.FlagTransferRiskRules)})
+	require.NoError(t, handler.SetRollout(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestFeatureFlagHandler_SetRollout_Invalid(t *testing.T) {
+	handler, _ := newFeatureFlagHandlerTest(t, nil)
+
+	tests := []struct {
+		name   string
+		flag   string
+		body   string
+		status int
+	}{
+		{"unknown flag", "no_such_flag", `{"enabled":true}`, http.StatusNotFound},
+		{"both fields", string(services.FlagTransferRiskRules), `{"enabled":true,"percentage":10}`, http.StatusBadRequest},
+		{"neither field", string(services.FlagTransferRiskRules), `{}`, http.StatusBadRequest},
+		{"out of range", string(services.FlagTransferRiskRules), `{"percentage":120}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := featureFlagContext(http.MethodPut, tt.body, map[string]string{"flag": tt.flag})
+			require.NoError(t, handler.SetRollout(c))
+			assert.Equal(t, tt.status, rec.Code)
+		})
+	}
+}
+
+func TestFeatureFlagHandler_UserOverride(t *testing.T) {
+	handler, repo := newFeatureFlagHandlerTest(t, nil)
+	userID := uuid.New()
+	params := map[string]string{"flag": string(services.FlagTransferAsyncInitiation), "userId": userID.String()}
+
+	repo.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, o *models.FeatureFlagOverride) error {
+		require.NotNil(t, o.UserID)
+		assert.Equal(t, userID, *o.UserID)
+		assert.Equal(t, 0, o.Percentage)
+		return nil
+	})
+	c, rec := featureFlagContext(http.MethodPut, `{"enabled":false}`, params)
+	require.NoError(t, handler.SetUserOverride(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	repo.EXPECT().Delete(gomock.Any(), string(services.FlagTransferAsyncInitiation), &userID).Return(nil)
+	c, rec = featureFlagContext(http.MethodDelete, "", params)
+	require.NoError(t, handler.ClearUserOverride(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -1,17 +1,24 @@
 package handlers
 
 import (
+	"io/fs"
+	"log/slog"
 	"net/http"
 	"time"
 
+	"github.com/array/banking-api/internal/database"
 	"github.com/array/banking-api/internal/errors"
+	"github.com/array/banking-api/internal/services"
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 )
 
-// HealthCheckHandler handles the health check endpoint
+// HealthCheckHandler handles the health check endpoints
 type HealthCheckHandler struct {
-	db *gorm.DB
+	db         *gorm.DB
+	canary     *services.CanaryService
+	migrations fs.FS
+	logger     *slog.Logger
 }
 
 // NewHealthCheckHandler creates a new health check handler
@@ -19,6 +26,30 @@ func NewHealthCheckHandler(db *gorm.DB) *HealthCheckHandler {
 	return &HealthCheckHandler{db: db}
 }
 
+// SetCanary sets the canary whose recent runs the deep health check reports
+func (h *HealthCheckHandler) SetCanary(canary *services.CanaryService) {
+	h.canary = canary
+}
+
+// SetMigrations sets the migrations the deep health check expects the database to have applied.
+// Pending migrations are logged to logger and fail readiness.
+func (h *HealthCheckHandler) SetMigrations(migrations fs.FS, logger *slog.Logger) {
+	h.migrations = migrations
+	h.logger = logger
+}
+
+// DeepHealthReport is the deep health check response. Status is unhealthy when the database does
+// not answer, has migrations pending, or the canary has failed too many runs in a row.
+type DeepHealthReport struct {
+	Status   string `json:"status"`
+	Time     string `json:"time"`
+	Database string `json:"database"`
+	// Migrations is "ok", "pending" or "unknown" when they could not be checked
+	Migrations        string                 `json:"migrations,omitempty"`
+	PendingMigrations []string               `json:"pending_migrations,omitempty"`
+	Canary            *services.CanaryHealth `json:"canary,omitempty"`
+}
+
 // HealthCheck adds the health check endpoint
 // @Summary Health check
 // @Description Check API and database connectivity status
@@ -57,6 +88,57 @@ func (h *HealthCheckHandler) HealthCheck(c echo.Context) error {
 	})
 }
 
+// DeepHealth reports the database, its pending migrations and the latest synthetic canary run,
+// failing readiness while migrations are pending and after consecutive canary failures
+// @Summary Deep health check
+// @Description Check database connectivity, pending migrations and the synthetic canary transfer's recent runs
+// @Tags Health
+// @Produce json
+// @Success 200 {object} handlers.DeepHealthReport "Service is healthy"
+// @Failure 503 {object} handlers.DeepHealthReport "Database unavailable, migrations pending or canary failing"
+// @Router /health/deep [get]
+func (h *HealthCheckHandler) DeepHealth(c echo.Context) error {
+	report := DeepHealthReport{
+		Status:   "healthy",
+		Time:     time.Now().UTC().Format(time.RFC3339),
+		Database: "ok",
+	}
+	if sqlDB, err := h.db.DB(); err != nil || sqlDB.PingContext(c.Request().Context()) != nil {
+		report.Database = "unavailable"
+		report.Status = "unhealthy"
+	}
+	if h.migrations != nil {
+		upToDate, pending, err := database.MigrationsUpToDate(c.Request().Context(), h.db, h.migrations)
+		switch {
+		case err != nil:
+			report.Migrations = "unknown"
+			report.Status = "unhealthy"
+		case !upToDate:
+			report.Migrations = "pending"
+			report.PendingMigrations = database.MigrationNames(pending)
+			report.Status = "unhealthy"
+			h.logger.Error("Readiness failing: database migrations are pending", "pending", report.PendingMigrations)
+		default:
+			report.Migrations = "ok"
+		}
+	}
+	if h.canary != nil {
+		canary, err := h.canary.Health(c.Request().Context())
+		if err != nil {
+			return SendSystemError(c, err)
+		}
+		report.Canary = canary
+		if !canary.Healthy {
+			report.Status = "unhealthy"
+		}
+	}
+
+	if report.Status != "healthy" {
+		return c.JSON(http.StatusServiceUnavailable, report)
+	}
+	return c.JSON(http.StatusOK, report)
+}
+
 // Helper to get trace ID from context
 func getTraceIDFromContext(c echo.Context) string {
 	traceID := c.Response().Header().Get("X-Trace-ID")
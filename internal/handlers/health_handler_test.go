@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/array/banking-api/internal/database"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthCheckHandler_DeepHealth_FailsWhileMigrationsPending(t *testing.T) {
+	db := database.SetupTestDB(t).DB
+	migrations := fstest.MapFS{
+		"000001_create_widgets.up.sql":  {Data: []byte("CREATE TABLE widgets (id integer PRIMARY KEY);")},
+		"000002_add_widget_name.up.sql": {Data: []byte("ALTER TABLE widgets ADD COLUMN name text;")},
+	}
+	handler := NewHealthCheckHandler(db)
+	handler.SetMigrations(migrations, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	deepHealth := func() (int, DeepHealthReport) {
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/v1/health/deep", nil), rec)
+		require.NoError(t, handler.DeepHealth(c))
+		var report DeepHealthReport
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		return rec.Code, report
+	}
+
+	code, report := deepHealth()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "pending", report.Migrations)
+	assert.Equal(t, []string{"000001_create_widgets", "000002_add_widget_name"}, report.PendingMigrations)
+
+	_, err := database.ApplyPendingMigrations(t.Context(), db, migrations)
+	require.NoError(t, err)
+	code, report = deepHealth()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok", report.Migrations)
+	assert.Empty(t, report.PendingMigrations)
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+
+	appErrors "github.com/array/banking-api/internal/errors"
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/labstack/echo/v4"
+)
+
+// isNorthwindError reports whether err comes from a call to NorthWind: an error response, a
+// response that is not JSON, a timeout or a failed connection
+func isNorthwindError(err error) bool {
+	var apiErr *northwind.APIError
+	var urlErr *url.Error
+	return errors.As(err, &apiErr) || errors.Is(err, northwind.ErrUnexpectedContentType) || errors.As(err, &urlErr) || isTimeout(err)
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// sendNorthwindError translates a failed NorthWind call into our error response, so clients can
+// tell a request NorthWind refused from NorthWind being unavailable:
+//   - 400 from NorthWind is NorthwindAPIBadRequest (400) and other 4xx rejections are
+//     NorthwindAPIRejected (422), both with NorthWind's message as the detail
+//   - 401 and 403 mean NorthWind refused our credentials, and 5xx are its own failures: both are
+//     NorthwindAPIError (502) with a generic message
+//   - a successful response that is not JSON, such as a proxy's HTML page, is NorthwindAPIError (502)
+//   - 429 and failed connections are NorthwindAPIUnavailable (503)
+//   - timeouts, including NorthWind's own 408 and 504, are NorthwindAPITimeout (504)
+//
+// The meta carries upstream_status, NorthWind's upstream_trace_id and its upstream_request_id
+// (X-NW-Request-ID, which NorthWind support asks for) when NorthWind responded.
+// Errors that are not NorthWind's are treated as NorthWind being unavailable.
+func sendNorthwindError(c echo.Context, err error) error {
+	code := appErrors.NorthwindAPIUnavailable
+	var opts []appErrors.ErrorOption
+	var apiErr *northwind.APIError
+	switch {
+	case errors.As(err, &apiErr):
+		code = northwindStatusCode(apiErr.StatusCode)
+		opts = append(opts, appErrors.WithMeta("upstream_status", apiErr.StatusCode))
+		if apiErr.TraceID != "" {
+			opts = append(opts, appErrors.WithMeta("upstream_trace_id", apiErr.TraceID))
+		}
+		if apiErr.RequestID != "" {
+			opts = append(opts, appErrors.WithMeta("upstream_request_id", apiErr.RequestID))
+		}
+		if code == appErrors.NorthwindAPIBadRequest || code == appErrors.NorthwindAPIRejected {
+			if message := apiErr.Message(); message != "" {
+				opts = append(opts, appErrors.WithDetails(message))
+			}
+			return SendError(c, code, opts...)
+		}
+	case errors.Is(err, northwind.ErrUnexpectedContentType):
+		code = appErrors.NorthwindAPIError
+	case isTimeout(err):
+		code = appErrors.NorthwindAPITimeout
+	}
+
+	// The client gets only the generic message, so keep what went wrong for support
+	attrs := []any{
+		"trace_id", getTraceID(c),
+		"error_code", code,
+		"error", err,
+		"path", c.Request().URL.Path,
+		"method", c.Request().Method,
+	}
+	var reqErr *northwind.RequestError
+	var ctErr *northwind.ContentTypeError
+	switch {
+	case apiErr != nil:
+		attrs = append(attrs, "attempts", apiErr.Attempts, "elapsed", apiErr.Elapsed)
+	case errors.As(err, &reqErr):
+		attrs = append(attrs, "attempts", reqErr.Attempts, "elapsed", reqErr.Elapsed)
+	case errors.As(err, &ctErr):
+		attrs = append(attrs, "attempts", ctErr.Attempts, "elapsed", ctErr.Elapsed)
+	}
+	if requestID := northwind.RequestID(err); requestID != "" {
+		attrs = append(attrs, "upstream_request_id", requestID)
+	}
+	slog.WarnContext(c.Request().Context(), "NorthWind call failed", attrs...)
+	return SendError(c, code, opts...)
+}
+
+// northwindStatusCode returns the error code for an error response from NorthWind
+func northwindStatusCode(status int) appErrors.ErrorCode {
+	switch {
+	case status == http.StatusBadRequest:
+		return appErrors.NorthwindAPIBadRequest
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return appErrors.NorthwindAPIError
+	case status == http.StatusTooManyRequests:
+		return appErrors.NorthwindAPIUnavailable
+	case status == http.StatusRequestTimeout || status == http.StatusGatewayTimeout:
+		return appErrors.NorthwindAPITimeout
+	case status >= 400 && status < 500:
+		return appErrors.NorthwindAPIRejected
+	default:
+		return appErrors.NorthwindAPIError
+	}
+}
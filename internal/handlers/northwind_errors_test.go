@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/services"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/array/banking-api/internal/validation"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// upstreamFailure is how the NorthWind stub fails the call under test
+type upstreamFailure struct {
+	status int
+	body   string
+	// contentType replaces the stub's application/json
+	contentType string
+	// delay makes the stub outlast the request's deadline instead of failing
+	delay time.Duration
+}
+
+type northwindErrorCase struct {
+	failure        upstreamFailure
+	wantStatus     int
+	wantCode       string
+	wantDetail     string
+	wantUpstream   bool
+	wantNoUpstream bool
+}
+
+var northwindErrorCases = map[string]northwindErrorCase{
+	"upstream 400 is our 400 with its message": {
+		failure:      upstreamFailure{status: http.StatusBadRequest, body: `{"message":"routing_number is invalid"}`},
+		wantStatus:   http.StatusBadRequest,
+		wantCode:     "NORTHWIND_API_003",
+		wantDetail:   "routing_number is invalid",
+		wantUpstream: true,
+	},
+	"upstream 422 is our 422 with its message": {
+		failure:      upstreamFailure{status: http.StatusUnprocessableEntity, body: `{"error":"account is closed"}`},
+		wantStatus:   http.StatusUnprocessableEntity,
+		wantCode:     "NORTHWIND_API_004",
+		wantDetail:   "account is closed",
+		wantUpstream: true,
+	},
+	"upstream 5xx is a generic 502": {
+		failure:      upstreamFailure{status: http.StatusInternalServerError, body: `{"message":"db pool exhausted"}`},
+		wantStatus:   http.StatusBadGateway,
+		wantCode:     "NORTHWIND_API_002",
+		wantUpstream: true,
+	},
+	"upstream 401 is a generic 502": {
+		failure:      upstreamFailure{status: http.StatusUnauthorized, body: `{"message":"invalid api key"}`},
+		wantStatus:   http.StatusBadGateway,
+		wantCode:     "NORTHWIND_API_002",
+		wantUpstream: true,
+	},
+	"HTML page with 200 is a generic 502": {
+		failure:        upstreamFailure{status: http.StatusOK, body: `<html><body>Down for maintenance</body></html>`, contentType: "text/html"},
+		wantStatus:     http.StatusBadGateway,
+		wantCode:       "NORTHWIND_API_002",
+		wantNoUpstream: true,
+	},
+	"timeout is a 504": {
+		failure:        upstreamFailure{delay: 500 * time.Millisecond},
+		wantStatus:     http.StatusGatewayTimeout,
+		wantCode:       "NORTHWIND_API_005",
+		wantNoUpstream: true,
+	},
+}
+
+// failingNorthwind serves path with failure and every other path from next
+func failingNorthwind(t *testing.T, path string, failure upstreamFailure, next http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			next(w, r)
+			return
+		}
+		if failure.delay > 0 {
+			select {
+			case <-time.After(failure.delay):
+			case <-r.Context().Done():
+			}
+			return
+		}
+		contentType := "application/json"
+		if failure.contentType != "" {
+			contentType = failure.contentType
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("X-Trace-ID", "nw-trace-1")
+		w.Header().Set(northwind.RequestIDHeader, "nw-req-1")
+		w.WriteHeader(failure.status)
+		_, _ = w.Write([]byte(failure.body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// northwindErrorContext returns a context for req that, when failure is delayed, times out before
+// the stub answers
+func northwindErrorContext(t *testing.T, req *http.Request, failure upstreamFailure) (echo.Context, *httptest.ResponseRecorder) {
+	t.Helper()
+	if failure.delay > 0 {
+		ctx, cancel := context.WithTimeout(req.Context(), failure.delay/5)
+		t.Cleanup(cancel)
+		req = req.WithContext(ctx)
+	}
+	e := echo.New()
+	e.Validator = validation.EchoValidator()
+	rec := httptest.NewRecorder()
+	return e.NewContext(req, rec), rec
+}
+
+func assertNorthwindError(t *testing.T, tc northwindErrorCase, rec *httptest.ResponseRecorder) {
+	t.Helper()
+	require.Equal(t, tc.wantStatus, rec.Code, rec.Body.String())
+	var body ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, tc.wantCode, body.Error.Code)
+	if tc.wantDetail != "" {
+		assert.Equal(t, []string{tc.wantDetail}, body.Error.Details)
+	} else {
+		assert.NotContains(t, rec.Body.String(), "db pool exhausted")
+		assert.NotContains(t, rec.Body.String(), "invalid api key")
+		assert.NotContains(t, rec.Body.String(), "maintenance")
+	}
+	if tc.wantUpstream {
+		assert.EqualValues(t, tc.failure.status, body.Error.Meta["upstream_status"])
+		assert.Equal(t, "nw-trace-1", body.Error.Meta["upstream_trace_id"])
+		assert.Equal(t, "nw-req-1", body.Error.Meta["upstream_request_id"])
+	}
+	if tc.wantNoUpstream {
+		assert.NotContains(t, body.Error.Meta, "upstream_status")
+	}
+}
+
+func TestSendNorthwindError_GetBankInfo(t *testing.T) {
+	for name, tc := range northwindErrorCases {
+		t.Run(name, func(t *testing.T) {
+			server := failingNorthwind(t, "/bank", tc.failure, http.NotFound)
+			client := northwind.NewClient(server.URL, "test-key")
+			handler := NewNorthwindHandler(client, nil, nil, nil, nil, testEnv("testing"))
+
+			c, rec := northwindErrorContext(t, httptest.NewRequest(http.MethodGet, "/api/v1/northwind/bank", nil), tc.failure)
+			require.NoError(t, handler.GetBankInfo(c))
+			assertNorthwindError(t, tc, rec)
+		})
+	}
+}
+
+func TestSendNorthwindError_CreateTransfer(t *testing.T) {
+	for name, tc := range northwindErrorCases {
+		t.Run(name, func(t *testing.T) {
+			checks := newCreateTransferStub(t)
+			server := failingNorthwind(t, "/external/transfers/initiate", tc.failure, checks.Config.Handler.ServeHTTP)
+			db := testfactory.NewDB(t)
+			transferRepo := repositories.NewNorthwindTransferRepository(db)
+			transferSvc := services.NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), transferRepo, nil, nil, slog.Default())
+			handler := NewNorthwindHandler(nil, nil, transferSvc, nil, nil, testEnv("testing"))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/northwind/transfers", strings.NewReader(createTransferBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			c, rec := northwindErrorContext(t, req, tc.failure)
+			userID := uuid.New()
+			c.Set("user_id", userID)
+
+			require.NoError(t, handler.CreateTransfer(c))
+			assertNorthwindError(t, tc, rec)
+			transfers, err := transferRepo.GetByUserIDAndStatus(context.Background(), userID, models.NWTransferStatusPending)
+			require.NoError(t, err)
+			assert.Empty(t, transfers)
+		})
+	}
+}
+
+func TestSendNorthwindError_CancelTransfer(t *testing.T) {
+	for name, tc := range northwindErrorCases {
+		t.Run(name, func(t *testing.T) {
+			db := testfactory.NewDB(t)
+			userID := uuid.New()
+			transfer := testfactory.NWTransfer(t, db, testfactory.WithUser(userID))
+			server := failingNorthwind(t, "/external/transfers/"+transfer.NorthwindTransferID.String()+"/cancel", tc.failure, http.NotFound)
+			transferSvc := services.NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), repositories.NewNorthwindTransferRepository(db), nil, nil, slog.Default())
+			handler := NewNorthwindHandler(nil, nil, transferSvc, nil, nil, testEnv("testing"))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/northwind/transfers/"+transfer.ID.String()+"/cancel", strings.NewReader(`{"reason":"changed my mind"}`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			c, rec := northwindErrorContext(t, req, tc.failure)
+			c.Set("user_id", userID)
+			c.SetParamNames("id")
+			c.SetParamValues(transfer.ID.String())
+
+			require.NoError(t, handler.CancelTransfer(c))
+			assertNorthwindError(t, tc, rec)
+		})
+	}
+}
@@ -1,21 +1,67 @@
 package handlers
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/array/banking-api/internal/config"
 	appErrors "github.com/array/banking-api/internal/errors"
 	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
 	"github.com/array/banking-api/internal/services"
+	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
+// resetConfirmation is the value callers must send in the reset body's confirm field
+const resetConfirmation = "RESET"
+
+const (
+	// defaultTransferWaitTimeout is used when the wait endpoint is called without ?timeout
+	defaultTransferWaitTimeout = 30 * time.Second
+	// maxTransferWaitTimeout caps ?timeout so a long-poll cannot hold a connection indefinitely
+	maxTransferWaitTimeout = 60 * time.Second
+)
+
+// Create-transfer responses embedded NorthWind's raw initiation response as northwind_response
+// before the curated initiation object replaced it. Clients still relying on the old shape can
+// request it with the header below for one deprecation cycle.
+const (
+	transferResponseShapeHeader  = "X-Transfer-Response-Shape"
+	transferResponseShapeLegacy  = "legacy"
+	transferResponseShapeCurrent = "initiation"
+)
+
+// EnvironmentInfo reports which deployment environment the server is running in.
+// *config.Config satisfies this interface.
+type EnvironmentInfo interface {
+	IsDevelopment() bool
+	IsTesting() bool
+}
+
 // NorthwindHandler handles NorthWind integration endpoints
 type NorthwindHandler struct {
 	client      *northwind.Client
 	accountSvc  *services.NorthwindAccountService
 	transferSvc *services.NorthwindTransferService
+	statsSvc    *services.NorthwindTransferStatsService
+	receiptSvc  *services.ReceiptService
+	env         EnvironmentInfo
+	shutdown    <-chan struct{}
+	// legacyTransferResponse makes the deprecated create-transfer shape the default
+	legacyTransferResponse bool
+	pollSchedule           *services.NorthwindPollSchedule
+	maintenance            *services.NorthwindMaintenance
+	pollAnomalies          *services.PollAnomalyService
+	dashboard              *services.NorthwindDashboardService
+	disputes               *services.TransferDisputeService
 }
 
 // NewNorthwindHandler creates a new NorthWind handler
@@ -23,37 +69,117 @@ func NewNorthwindHandler(
 	client *northwind.Client,
 	accountSvc *services.NorthwindAccountService,
 	transferSvc *services.NorthwindTransferService,
+	statsSvc *services.NorthwindTransferStatsService,
+	receiptSvc *services.ReceiptService,
+	env EnvironmentInfo,
 ) *NorthwindHandler {
 	return &NorthwindHandler{
 		client:      client,
 		accountSvc:  accountSvc,
 		transferSvc: transferSvc,
+		statsSvc:    statsSvc,
+		receiptSvc:  receiptSvc,
+		env:         env,
 	}
 }
 
+// SetShutdownSignal registers a channel closed on server shutdown; in-flight long-polls return
+// their current state as soon as it closes
+func (h *NorthwindHandler) SetShutdownSignal(done <-chan struct{}) {
+	h.shutdown = done
+}
+
+// SetLegacyTransferResponse makes create-transfer responses default to the deprecated shape
+// that embeds NorthWind's raw response. Clients can still pick a shape per request with the
+// X-Transfer-Response-Shape header.
+func (h *NorthwindHandler) SetLegacyTransferResponse(enabled bool) {
+	h.legacyTransferResponse = enabled
+}
+
+// SetPollSchedule registers the transfer poll schedule whose per-type profiles admins can
+// override at runtime
+func (h *NorthwindHandler) SetPollSchedule(schedule *services.NorthwindPollSchedule) {
+	h.pollSchedule = schedule
+}
+
+// SetMaintenance registers the NorthWind maintenance window admins can schedule at runtime
+func (h *NorthwindHandler) SetMaintenance(maintenance *services.NorthwindMaintenance) {
+	h.maintenance = maintenance
+}
+
+// SetPollAnomalies registers the quarantined poll responses admins can list and replay
+func (h *NorthwindHandler) SetPollAnomalies(anomalies *services.PollAnomalyService) {
+	h.pollAnomalies = anomalies
+}
+
+// SetDisputes registers the service behind transfer disputes
+func (h *NorthwindHandler) SetDisputes(disputes *services.TransferDisputeService) {
+	h.disputes = disputes
+}
+
+// SetDashboard registers the service behind the admin integration dashboard
+func (h *NorthwindHandler) SetDashboard(dashboard *services.NorthwindDashboardService) {
+	h.dashboard = dashboard
+}
+
 // --- Bank Info & Domains ---
 
 // GetBankInfo retrieves NorthWind bank information
 func (h *NorthwindHandler) GetBankInfo(c echo.Context) error {
 	info, err := h.client.GetBankInfo(c.Request().Context())
 	if err != nil {
-		return SendError(c, appErrors.NorthwindAPIError, appErrors.WithDetails(err.Error()))
+		return sendNorthwindError(c, err)
 	}
 	return c.JSON(http.StatusOK, SuccessResponse{
 		Data:    info,
-		Message: "Bank info retrieved",
+		Message: localize(c, "northwind.bank_info_retrieved"),
+	})
+}
+
+// GetMetadata lists the supported transfer statuses, directions and types with display labels,
+// so clients do not need to hardcode them
+func (h *NorthwindHandler) GetMetadata(c echo.Context) error {
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    models.GetNorthwindTransferMetadata(),
+		Message: localize(c, "northwind.transfer_metadata_retrieved"),
+	})
+}
+
+// EstimateTransfer estimates a transfer of the given transfer_type and priority: the expedite fee
+// and next cutoff of a SAME_DAY transfer, and how long the type usually takes
+func (h *NorthwindHandler) EstimateTransfer(c echo.Context) error {
+	q := newQueryParams(c)
+	transferType := q.Enum("transfer_type", models.NWTransferTypeValues()...)
+	priority := q.Enum("priority", models.NWTransferPriorityValues()...)
+	if transferType == "" && c.QueryParam("transfer_type") == "" {
+		q.addError("transfer_type", "is required")
+	}
+	if !q.Valid() {
+		return q.SendError()
+	}
+
+	estimate, err := h.transferSvc.EstimateTransfer(c.Request().Context(), transferType, priority)
+	if err != nil {
+		if errors.Is(err, services.ErrNWTransferPriorityNotAllowed) {
+			return SendError(c, appErrors.ValidationInvalidQuery, appErrors.WithDetails("priority: "+err.Error()))
+		}
+		return SendSystemError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    estimate,
+		Message: localize(c, "northwind.transfer_estimate_retrieved"),
 	})
 }
 
 // GetDomains retrieves NorthWind domains
 func (h *NorthwindHandler) GetDomains(c echo.Context) error {
-	domains, err := h.client.GetDomains(c.Request().Context())
+	domains, err := h.client.GetDomainsCached(c.Request().Context())
 	if err != nil {
-		return SendError(c, appErrors.NorthwindAPIError, appErrors.WithDetails(err.Error()))
+		return sendNorthwindError(c, err)
 	}
 	return c.JSON(http.StatusOK, SuccessResponse{
 		Data:    domains,
-		Message: "Domains retrieved",
+		Message: localize(c, "northwind.domains_retrieved"),
 	})
 }
 
@@ -70,27 +196,102 @@ func (h *NorthwindHandler) ValidateAndRegister(c echo.Context) error {
 	if err := c.Bind(&req); err != nil {
 		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid request body"))
 	}
-	if err := c.Validate(req); err != nil {
+	if err := validateRequest(c, req); err != nil {
 		return err
 	}
+	if req.OverrideNameMismatch && !getIsAdminFromContext(c) {
+		return SendError(c, appErrors.AuthInsufficientPermission, appErrors.WithDetails("override_name_mismatch can only be set by an admin"))
+	}
 
 	resp, err := h.accountSvc.ValidateAndRegister(c.Request().Context(), userID, req)
 	if err != nil {
+		var textErr *services.InvalidTextError
+		if errors.As(err, &textErr) {
+			return sendInvalidTextError(c, textErr)
+		}
+		if errors.Is(err, services.ErrAccountHolderNameMismatch) {
+			return SendError(c, appErrors.NorthwindAccountNameMismatch, appErrors.WithDetails(
+				"account_holder_name is too different from the name NorthWind has on file for this account",
+			))
+		}
 		if errors.Is(err, services.ErrExternalAccountValidationFailed) {
 			return c.JSON(http.StatusUnprocessableEntity, SuccessResponse{
 				Data:    resp,
-				Message: "Account validation failed",
+				Message: localize(c, "northwind.account_validation_failed"),
 			})
 		}
+		if isNorthwindError(err) {
+			return sendNorthwindError(c, err)
+		}
 		return SendSystemError(c, err)
 	}
 
 	return c.JSON(http.StatusCreated, SuccessResponse{
 		Data:    resp,
-		Message: "External account validated and registered",
+		Message: localize(c, "northwind.account_registered"),
+	})
+}
+
+// maxAccountImportFileSize bounds an uploaded import file; 5000 rows fit comfortably
+const maxAccountImportFileSize = 5 << 20
+
+// ImportExternalAccounts validates and registers the external accounts in an uploaded CSV (form
+// field "file"), reporting the outcome of every row
+func (h *NorthwindHandler) ImportExternalAccounts(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return SendError(c, appErrors.AuthMissingToken)
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("file is required and must be a multipart CSV upload"))
+	}
+	if header.Size > maxAccountImportFileSize {
+		return SendError(c, appErrors.ValidationOutOfRange, appErrors.WithDetails("file must be at most 5 MB"))
+	}
+	file, err := header.Open()
+	if err != nil {
+		return SendSystemError(c, err)
+	}
+	defer file.Close()
+
+	// An import can outlast the server's write timeout, so the response may be written until the
+	// route's own deadline
+	if deadline, ok := c.Request().Context().Deadline(); ok {
+		_ = http.NewResponseController(c.Response()).SetWriteDeadline(deadline.Add(5 * time.Second))
+	}
+
+	report, err := h.accountSvc.ImportAccounts(c.Request().Context(), userID, file)
+	if err != nil {
+		var importErr *services.AccountImportError
+		switch {
+		case errors.As(err, &importErr):
+			return SendError(c, appErrors.ValidationInvalidFormat, appErrors.WithDetails(importErr.Error()))
+		case errors.Is(err, services.ErrAccountImportTooManyRows):
+			return SendError(c, appErrors.ValidationOutOfRange, appErrors.WithDetails(err.Error()))
+		}
+		return SendSystemError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    report,
+		Message: localize(c, "northwind.account_import_processed"),
 	})
 }
 
+// sendInvalidTextError reports a free-text field that failed sanitization, naming the field and
+// either its length limit or the characters that are not allowed
+func sendInvalidTextError(c echo.Context, err *services.InvalidTextError) error {
+	if err.MaxLength > 0 {
+		return SendError(c, appErrors.ValidationOutOfRange, appErrors.WithDetails(
+			err.Field+" must be at most "+strconv.Itoa(err.MaxLength)+" characters",
+		))
+	}
+	details := append([]string{err.Field + " contains characters that are not allowed:"}, err.Characters...)
+	return SendError(c, appErrors.ValidationInvalidFormat, appErrors.WithDetails(details...))
+}
+
 // ListRegisteredAccounts lists the user's registered external accounts
 func (h *NorthwindHandler) ListRegisteredAccounts(c echo.Context) error {
 	userID, err := getUserIDFromContext(c)
@@ -98,10 +299,11 @@ func (h *NorthwindHandler) ListRegisteredAccounts(c echo.Context) error {
 		return SendError(c, appErrors.AuthMissingToken)
 	}
 
-	offset := getIntParam(c, "offset", 0)
-	limit := getIntParam(c, "limit", 20)
-	if limit > 100 {
-		limit = 100
+	q := newQueryParams(c)
+	offset := q.Offset()
+	limit := q.Limit()
+	if !q.Valid() {
+		return q.SendError()
 	}
 
 	accounts, total, err := h.accountSvc.ListRegisteredAccounts(c.Request().Context(), userID, offset, limit)
@@ -111,7 +313,7 @@ func (h *NorthwindHandler) ListRegisteredAccounts(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, SuccessResponse{
 		Data:    accounts,
-		Message: "Registered external accounts retrieved",
+		Message: localize(c, "northwind.accounts_retrieved"),
 		Meta: map[string]interface{}{
 			"total":  total,
 			"offset": offset,
@@ -120,15 +322,31 @@ func (h *NorthwindHandler) ListRegisteredAccounts(c echo.Context) error {
 	})
 }
 
-// ListAccessibleAccounts lists accessible accounts from NorthWind API
+// ListAccessibleAccounts lists accessible accounts from NorthWind API. The total is only in the
+// meta when NorthWind reported one.
 func (h *NorthwindHandler) ListAccessibleAccounts(c echo.Context) error {
-	accounts, err := h.accountSvc.ListAccessibleAccounts(c.Request().Context())
+	q := newQueryParams(c)
+	offset := q.Offset()
+	limit := q.Int("limit", maxPageLimit, 1, maxPageLimit)
+	if !q.Valid() {
+		return q.SendError()
+	}
+
+	page, err := h.accountSvc.ListAccessibleAccounts(c.Request().Context(), offset, limit)
 	if err != nil {
-		return SendError(c, appErrors.NorthwindAPIError, appErrors.WithDetails(err.Error()))
+		return sendNorthwindError(c, err)
+	}
+	meta := map[string]interface{}{
+		"offset": offset,
+		"limit":  limit,
+	}
+	if page.TotalCount != nil {
+		meta["total"] = *page.TotalCount
 	}
 	return c.JSON(http.StatusOK, SuccessResponse{
-		Data:    accounts,
-		Message: "Accessible NorthWind accounts retrieved",
+		Data:    page.Accounts,
+		Message: localize(c, "northwind.accessible_accounts_retrieved"),
+		Meta:    meta,
 	})
 }
 
@@ -145,30 +363,229 @@ func (h *NorthwindHandler) CreateTransfer(c echo.Context) error {
 	if err := c.Bind(&req); err != nil {
 		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid request body"))
 	}
-	if err := c.Validate(req); err != nil {
+	req.NormalizeReference()
+	if err := validateRequest(c, req); err != nil {
 		return err
 	}
+	req.Metadata = services.RequestMetadata{IPAddress: c.RealIP(), UserAgent: c.Request().UserAgent()}
 
 	resp, err := h.transferSvc.CreateTransfer(c.Request().Context(), userID, req)
 	if err != nil {
-		if errors.Is(err, services.ErrNWTransferValidationFailed) {
-			return SendError(c, appErrors.NorthwindTransferValidationFail, appErrors.WithDetails(err.Error()))
+		return sendCreateTransferError(c, err)
+	}
+
+	var data interface{} = resp
+	if h.wantsLegacyTransferResponse(c) {
+		c.Response().Header().Set("Deprecation", "true")
+		data = legacyCreateTransferResponse{
+			Transfer:          resp.Transfer,
+			NorthwindResponse: json.RawMessage(resp.Transfer.RawResponse),
+			ExpectedDuration:  resp.ExpectedDuration,
 		}
-		if errors.Is(err, services.ErrNWTransferInsufficientBal) {
-			return SendError(c, appErrors.NorthwindTransferInsufficientBal, appErrors.WithDetails(err.Error()))
+	}
+	if resp.Queued != nil {
+		key := "northwind.transfer_queued"
+		if resp.Queued.Reason == services.QueuedInitiationReasonScheduled {
+			key = "northwind.transfer_scheduled"
 		}
-		if errors.Is(err, services.ErrNWTransferInitiateFailed) {
-			return SendError(c, appErrors.NorthwindTransferInitiateFail, appErrors.WithDetails(err.Error()))
+		return c.JSON(http.StatusAccepted, SuccessResponse{
+			Data:    data,
+			Message: localize(c, key, resp.Queued.InitiateAfter.UTC().Format(time.RFC3339)),
+		})
+	}
+	return c.JSON(http.StatusCreated, SuccessResponse{
+		Data:    data,
+		Message: localize(c, "northwind.transfer_initiated"),
+	})
+}
+
+// sendCreateTransferError maps an error from creating a transfer, singly or in a batch, to its response
+func sendCreateTransferError(c echo.Context, err error) error {
+	var textErr *services.InvalidTextError
+	if errors.As(err, &textErr) {
+		return sendInvalidTextError(c, textErr)
+	}
+	if errors.Is(err, services.ErrNWTransferValidationFailed) {
+		return SendError(c, appErrors.NorthwindTransferValidationFail, appErrors.WithDetails(err.Error()))
+	}
+	if errors.Is(err, services.ErrNWTransferInsufficientBal) {
+		return SendError(c, appErrors.NorthwindTransferInsufficientBal, appErrors.WithDetails(err.Error()))
+	}
+	if errors.Is(err, services.ErrNWTransferInitiateFailed) {
+		if isNorthwindError(err) {
+			return sendNorthwindError(c, err)
 		}
-		return SendSystemError(c, err)
+		return SendError(c, appErrors.NorthwindTransferInitiateFail, appErrors.WithDetails(err.Error()))
+	}
+	if errors.Is(err, services.ErrNWTransferConsentRequired) {
+		return SendError(c, appErrors.NorthwindTransferConsentMissing, appErrors.WithDetails(err.Error()))
+	}
+	if errors.Is(err, services.ErrNWTransferUnverifiedAcct) {
+		return SendError(c, appErrors.NorthwindTransferUnverifiedAcct, appErrors.WithDetails(err.Error()))
+	}
+	if errors.Is(err, services.ErrNWTransferDuplicateRef) {
+		return SendError(c, appErrors.NorthwindTransferDuplicateRef, appErrors.WithDetails(err.Error()))
+	}
+	if errors.Is(err, services.ErrNWTransferRiskBlocked) {
+		// Which rules fired is recorded for admins, not told to the client
+		return SendError(c, appErrors.NorthwindTransferRiskBlocked)
 	}
+	if errors.Is(err, services.ErrNWTransferOverloaded) {
+		return SendError(c, appErrors.NorthwindTransferOverloaded, appErrors.WithRetryAfter(services.InitiationRetryAfter))
+	}
+	if errors.Is(err, services.ErrNWTransferPriorityNotAllowed) {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails(err.Error()))
+	}
+	var cutoff *services.SameDayCutoffPassedError
+	if errors.As(err, &cutoff) {
+		return SendError(c, appErrors.NorthwindTransferSameDayClosed,
+			appErrors.WithDetails("SAME_DAY transfers can next be submitted until "+cutoff.NextCutoff.UTC().Format(time.RFC3339)),
+			appErrors.WithMeta("next_cutoff", cutoff.NextCutoff))
+	}
+	var dup *services.PossibleDuplicateError
+	if errors.As(err, &dup) {
+		return SendError(c, appErrors.NorthwindTransferPossibleDup, appErrors.WithDetails(
+			"existing_transfer_id: "+dup.ExistingTransferID.String(),
+			`resubmit with "force": true to create the transfer anyway`,
+		))
+	}
+	return SendSystemError(c, err)
+}
 
-	return c.JSON(http.StatusCreated, SuccessResponse{
-		Data:    resp,
-		Message: "Transfer initiated successfully",
+// SubmitTransferBatch submits a named batch of transfers in one NorthWind call and reports the
+// outcome of each. With retry_failed=true it instead resubmits only the items of the named batch
+// that NorthWind rejected; transfers are then taken from the stored batch, not the body. When any
+// transfer failed the response is 207, with each transfer's outcome in the body.
+func (h *NorthwindHandler) SubmitTransferBatch(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return SendError(c, appErrors.AuthMissingToken)
+	}
+
+	retryFailed := false
+	if raw := c.QueryParam("retry_failed"); raw != "" {
+		retryFailed, err = strconv.ParseBool(raw)
+		if err != nil {
+			return SendError(c, appErrors.ValidationInvalidQuery, appErrors.WithDetails("retry_failed must be true or false"))
+		}
+	}
+
+	var req services.SubmitBatchRequest
+	if err := c.Bind(&req); err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid request body"))
+	}
+	meta := services.RequestMetadata{IPAddress: c.RealIP(), UserAgent: c.Request().UserAgent()}
+
+	var result *services.BatchTransferResult
+	status := http.StatusCreated
+	if retryFailed {
+		if req.BatchName == "" {
+			return SendError(c, appErrors.ValidationRequiredField, appErrors.WithDetails("batch_name is required"))
+		}
+		if len(req.Transfers) > 0 {
+			return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("transfers must be omitted when retry_failed=true"))
+		}
+		result, err = h.transferSvc.RetryFailedBatch(c.Request().Context(), userID, req.BatchName, meta)
+		status = http.StatusOK
+	} else {
+		for i := range req.Transfers {
+			req.Transfers[i].NormalizeReference()
+		}
+		if err := validateRequest(c, req); err != nil {
+			return err
+		}
+		for i := range req.Transfers {
+			if err := validateRequest(c, req.Transfers[i]); err != nil {
+				if req.Invalid == nil {
+					req.Invalid = make(map[int]error)
+				}
+				req.Invalid[i] = err
+			}
+		}
+		req.Metadata = meta
+		result, err = h.transferSvc.SubmitBatch(c.Request().Context(), userID, req)
+	}
+	if err != nil {
+		if errors.Is(err, services.ErrNWTransferBatchExists) {
+			return SendError(c, appErrors.NorthwindTransferBatchExists, appErrors.WithDetails(
+				err.Error(), "use retry_failed=true to resubmit its failed transfers",
+			))
+		}
+		if errors.Is(err, services.ErrNWTransferBatchNotFound) {
+			return SendError(c, appErrors.NorthwindTransferBatchNotFound)
+		}
+		return sendCreateTransferError(c, err)
+	}
+
+	for i := range result.Items {
+		if item := &result.Items[i]; item.LocalErr != nil {
+			code, message := batchItemError(item.LocalErr)
+			item.ErrorCode, item.ErrorMessage = string(code), message
+		}
+	}
+	if result.FailedCount > 0 {
+		status = http.StatusMultiStatus
+	}
+	return c.JSON(status, SuccessResponse{
+		Data:    result,
+		Message: localize(c, "northwind.batch_submitted"),
 	})
 }
 
+// batchItemError returns the error code and message reported for a batch transfer rejected before
+// the NorthWind call, the code being the one a single transfer failing the same way gets
+func batchItemError(err error) (appErrors.ErrorCode, string) {
+	var fieldErrs validator.ValidationErrors
+	var textErr *services.InvalidTextError
+	var cutoff *services.SameDayCutoffPassedError
+	switch {
+	case errors.As(err, &fieldErrs):
+		messages := make([]string, len(fieldErrs))
+		for i, fieldErr := range fieldErrs {
+			// The namespace starts with the request type, which means nothing to the client
+			_, field, _ := strings.Cut(fieldErr.Namespace(), ".")
+			messages[i] = field + " failed the " + fieldErr.Tag() + " rule"
+		}
+		return appErrors.ValidationGeneral, strings.Join(messages, "; ")
+	case errors.As(err, &textErr):
+		if textErr.MaxLength > 0 {
+			return appErrors.ValidationOutOfRange, err.Error()
+		}
+		return appErrors.ValidationInvalidFormat, err.Error()
+	case errors.As(err, &cutoff):
+		return appErrors.NorthwindTransferSameDayClosed, err.Error()
+	case errors.Is(err, services.ErrNWTransferConsentRequired):
+		return appErrors.NorthwindTransferConsentMissing, err.Error()
+	case errors.Is(err, services.ErrNWTransferUnverifiedAcct):
+		return appErrors.NorthwindTransferUnverifiedAcct, err.Error()
+	case errors.Is(err, services.ErrNWTransferDuplicateRef):
+		return appErrors.NorthwindTransferDuplicateRef, err.Error()
+	default:
+		return appErrors.ValidationGeneral, err.Error()
+	}
+}
+
+// legacyCreateTransferResponse is the deprecated create-transfer shape, rebuilt from the raw
+// NorthWind response persisted on the transfer
+type legacyCreateTransferResponse struct {
+	Transfer          *models.NorthwindTransfer     `json:"transfer"`
+	NorthwindResponse json.RawMessage               `json:"northwind_response,omitempty"`
+	ExpectedDuration  *models.TransferDurationStats `json:"expected_duration,omitempty"`
+}
+
+// wantsLegacyTransferResponse reports whether the request asked for the deprecated shape, falling
+// back to the configured default when the header is absent or unrecognised
+func (h *NorthwindHandler) wantsLegacyTransferResponse(c echo.Context) bool {
+	switch c.Request().Header.Get(transferResponseShapeHeader) {
+	case transferResponseShapeLegacy:
+		return true
+	case transferResponseShapeCurrent:
+		return false
+	default:
+		return h.legacyTransferResponse
+	}
+}
+
 // GetTransfer retrieves a specific transfer
 func (h *NorthwindHandler) GetTransfer(c echo.Context) error {
 	userID, err := getUserIDFromContext(c)
@@ -181,7 +598,7 @@ func (h *NorthwindHandler) GetTransfer(c echo.Context) error {
 		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid transfer ID"))
 	}
 
-	transfer, err := h.transferSvc.GetTransfer(c.Request().Context(), userID, transferID)
+	transfer, err := h.transferSvc.ViewTransfer(c.Request().Context(), userID, transferID)
 	if err != nil {
 		if errors.Is(err, services.ErrNWTransferNotFound) {
 			return SendError(c, appErrors.NorthwindTransferNotFound)
@@ -194,21 +611,162 @@ func (h *NorthwindHandler) GetTransfer(c echo.Context) error {
 	})
 }
 
-// ListTransfers lists the user's NorthWind transfers
+// SyncTransfer asks NorthWind for the transfer's current status now rather than waiting for the
+// poller, and returns the refreshed transfer
+func (h *NorthwindHandler) SyncTransfer(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return SendError(c, appErrors.AuthMissingToken)
+	}
+
+	transferID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid transfer ID"))
+	}
+
+	transfer, err := h.transferSvc.SyncTransferStatus(c.Request().Context(), userID, transferID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNWTransferNotFound):
+			return SendError(c, appErrors.NorthwindTransferNotFound)
+		case errors.Is(err, services.ErrNWTransferUnknownStatus):
+			return SendError(c, appErrors.NorthwindAPIError, appErrors.WithDetails(err.Error()))
+		case isNorthwindError(err):
+			return sendNorthwindError(c, err)
+		}
+		return SendSystemError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    transfer,
+		Message: localize(c, "northwind.transfer_synced"),
+	})
+}
+
+// GetTransferReceipt downloads a PDF receipt for a COMPLETED or REVERSED transfer. The receipt's
+// verification hash is also returned in the X-Receipt-Verification-Hash header.
+func (h *NorthwindHandler) GetTransferReceipt(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return SendError(c, appErrors.AuthMissingToken)
+	}
+
+	transferID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid transfer ID"))
+	}
+
+	receipt, err := h.receiptSvc.TransferReceipt(c.Request().Context(), userID, transferID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNWTransferNotFound):
+			return SendError(c, appErrors.NorthwindTransferNotFound)
+		case errors.Is(err, services.ErrNWReceiptUnavailable):
+			return SendError(c, appErrors.NorthwindTransferReceiptUnavail)
+		}
+		return SendSystemError(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+receipt.Filename+`"`)
+	c.Response().Header().Set("X-Receipt-Verification-Hash", receipt.VerificationHash)
+	return c.Blob(http.StatusOK, "application/pdf", receipt.PDF)
+}
+
+// transferWaitResponse is returned by the long-poll wait endpoint
+type transferWaitResponse struct {
+	Transfer *models.NorthwindTransfer `json:"transfer"`
+	Changed  bool                      `json:"changed"`
+}
+
+// WaitForTransfer long-polls a transfer: it responds as soon as the transfer's version exceeds
+// ?since_version, or with the current state and changed=false once ?timeout (default 30s,
+// capped at 60s) elapses or the server starts shutting down
+func (h *NorthwindHandler) WaitForTransfer(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return SendError(c, appErrors.AuthMissingToken)
+	}
+
+	transferID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid transfer ID"))
+	}
+
+	timeout := defaultTransferWaitTimeout
+	if raw := c.QueryParam("timeout"); raw != "" {
+		timeout, err = time.ParseDuration(raw)
+		if err != nil || timeout <= 0 {
+			return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("timeout must be a positive duration such as 30s"))
+		}
+	}
+	if timeout > maxTransferWaitTimeout {
+		timeout = maxTransferWaitTimeout
+	}
+
+	sinceVersion := 0
+	if raw := c.QueryParam("since_version"); raw != "" {
+		sinceVersion, err = strconv.Atoi(raw)
+		if err != nil || sinceVersion < 0 {
+			return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("since_version must be a non-negative integer"))
+		}
+	}
+
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	defer cancel()
+	if h.shutdown != nil {
+		go func() {
+			select {
+			case <-h.shutdown:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	transfer, changed, err := h.transferSvc.WaitForTransferChange(ctx, userID, transferID, sinceVersion, timeout)
+	if err != nil {
+		if errors.Is(err, services.ErrNWTransferNotFound) || errors.Is(err, repositories.ErrNorthwindTransferNotFound) {
+			return SendError(c, appErrors.NorthwindTransferNotFound)
+		}
+		if errors.Is(c.Request().Context().Err(), context.Canceled) {
+			// The client disconnected; there is no one to answer and nothing went wrong here
+			return nil
+		}
+		return SendSystemError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data: transferWaitResponse{Transfer: transfer, Changed: changed},
+	})
+}
+
+// ListTransfers lists the user's NorthWind transfers. With a cursor parameter, empty for the
+// first page, it pages by keyset and returns next_cursor instead of total; otherwise it pages by
+// offset as before.
 func (h *NorthwindHandler) ListTransfers(c echo.Context) error {
 	userID, err := getUserIDFromContext(c)
 	if err != nil {
 		return SendError(c, appErrors.AuthMissingToken)
 	}
 
-	offset := getIntParam(c, "offset", 0)
-	limit := getIntParam(c, "limit", 20)
-	if limit > 100 {
-		limit = 100
+	q := newQueryParams(c)
+	offset := q.Offset()
+	limit := q.Limit()
+	status := q.Enum("status", models.NWTransferStatusValues()...)
+	direction := q.Enum("direction", models.NWTransferDirectionValues()...)
+	transferType := q.Enum("transfer_type", models.NWTransferTypeValues()...)
+	keyset := c.QueryParams().Has("cursor")
+	if keyset && c.QueryParam("offset") != "" {
+		q.addError("offset", "cannot be combined with cursor")
+	}
+	if !q.Valid() {
+		return q.SendError()
+	}
+
+	if keyset {
+		filters := models.NorthwindTransferFilters{Status: status, Direction: direction, TransferType: transferType}
+		return h.listTransfersAfter(c, userID, filters, c.QueryParam("cursor"), limit)
 	}
-	status := c.QueryParam("status")
-	direction := c.QueryParam("direction")
-	transferType := c.QueryParam("transfer_type")
 
 	transfers, total, err := h.transferSvc.ListTransfers(c.Request().Context(), userID, status, direction, transferType, offset, limit)
 	if err != nil {
@@ -217,7 +775,7 @@ func (h *NorthwindHandler) ListTransfers(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, SuccessResponse{
 		Data:    transfers,
-		Message: "Transfers retrieved",
+		Message: localize(c, "northwind.transfers_retrieved"),
 		Meta: map[string]interface{}{
 			"total":  total,
 			"offset": offset,
@@ -226,6 +784,32 @@ func (h *NorthwindHandler) ListTransfers(c echo.Context) error {
 	})
 }
 
+func (h *NorthwindHandler) listTransfersAfter(c echo.Context, userID uuid.UUID, filters models.NorthwindTransferFilters, cursor string, limit int) error {
+	transfers, next, err := h.transferSvc.ListTransfersAfter(c.Request().Context(), userID, filters, cursor, limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrTransferCursorExpired):
+			return SendError(c, appErrors.NorthwindTransferCursorExpired)
+		case errors.Is(err, services.ErrInvalidTransferCursor):
+			return SendError(c, appErrors.ValidationInvalidQuery, appErrors.WithDetails("cursor: is invalid or was issued for different filters"))
+		}
+		return SendSystemError(c, err)
+	}
+
+	var nextCursor interface{}
+	if next != "" {
+		nextCursor = next
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    transfers,
+		Message: localize(c, "northwind.transfers_retrieved"),
+		Meta: map[string]interface{}{
+			"limit":       limit,
+			"next_cursor": nextCursor,
+		},
+	})
+}
+
 // CancelTransfer cancels a pending transfer
 func (h *NorthwindHandler) CancelTransfer(c echo.Context) error {
 	userID, err := getUserIDFromContext(c)
@@ -245,74 +829,650 @@ func (h *NorthwindHandler) CancelTransfer(c echo.Context) error {
 		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid request body"))
 	}
 
-	transfer, err := h.transferSvc.CancelTransfer(c.Request().Context(), userID, transferID, req.Reason)
+	transfer, err := h.transferSvc.CancelTransfer(c.Request().Context(), userID, transferID, req.Reason, models.UserInitiator(userID))
 	if err != nil {
 		if errors.Is(err, services.ErrNWTransferNotFound) {
 			return SendError(c, appErrors.NorthwindTransferNotFound)
 		}
+		var closed *services.CancellationWindowClosedError
+		if errors.As(err, &closed) {
+			return SendError(c, appErrors.NorthwindTransferCancelClosed,
+				appErrors.WithDetails(closed.TransferType+" transfers can only be cancelled until "+closed.Deadline.UTC().Format(time.RFC3339)),
+				appErrors.WithMeta("cancellable_until", closed.Deadline))
+		}
+		if isNorthwindError(err) {
+			return sendNorthwindError(c, err)
+		}
 		return SendError(c, appErrors.NorthwindTransferCancelFail, appErrors.WithDetails(err.Error()))
 	}
 
 	return c.JSON(http.StatusOK, SuccessResponse{
 		Data:    transfer,
-		Message: "Transfer cancelled",
+		Message: localize(c, "northwind.transfer_cancelled"),
 	})
 }
 
-// ReverseTransfer reverses a completed transfer
-func (h *NorthwindHandler) ReverseTransfer(c echo.Context) error {
+// bulkCancelRequest is the body for the cancel-all endpoints
+type bulkCancelRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+// CancelAllTransfers cancels all of the caller's PENDING transfers
+func (h *NorthwindHandler) CancelAllTransfers(c echo.Context) error {
 	userID, err := getUserIDFromContext(c)
 	if err != nil {
 		return SendError(c, appErrors.AuthMissingToken)
 	}
+	return h.cancelAllTransfers(c, userID, models.UserInitiator(userID))
+}
 
-	transferID, err := uuid.Parse(c.Param("id"))
+// AdminCancelAllTransfers cancels all PENDING transfers of the user in the path, as the calling admin
+func (h *NorthwindHandler) AdminCancelAllTransfers(c echo.Context) error {
+	adminID, err := getUserIDFromContext(c)
 	if err != nil {
-		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid transfer ID"))
+		return SendError(c, appErrors.AuthMissingToken)
 	}
-
-	var req struct {
-		Reason      string `json:"reason" validate:"required"`
-		Description string `json:"description,omitempty"`
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid user ID"))
 	}
+	return h.cancelAllTransfers(c, userID, models.AdminInitiator(adminID))
+}
+
+func (h *NorthwindHandler) cancelAllTransfers(c echo.Context, userID uuid.UUID, initiator models.TransferInitiator) error {
+	var req bulkCancelRequest
 	if err := c.Bind(&req); err != nil {
 		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid request body"))
 	}
+	if req.Reason == "" {
+		return SendError(c, appErrors.ValidationRequiredField, appErrors.WithDetails("reason is required"))
+	}
 
-	transfer, err := h.transferSvc.ReverseTransfer(c.Request().Context(), userID, transferID, req.Reason, req.Description)
+	results, err := h.transferSvc.CancelAllPendingTransfers(c.Request().Context(), userID, req.Reason, initiator)
 	if err != nil {
-		if errors.Is(err, services.ErrNWTransferNotFound) {
-			return SendError(c, appErrors.NorthwindTransferNotFound)
-		}
-		return SendError(c, appErrors.NorthwindTransferReverseFail, appErrors.WithDetails(err.Error()))
+		return SendSystemError(c, err)
+	}
+
+	summary := map[string]int{
+		services.BulkCancelOutcomeCancelled:       0,
+		services.BulkCancelOutcomeAlreadyTerminal: 0,
+		services.BulkCancelOutcomeUpstreamError:   0,
+	}
+	for _, r := range results {
+		summary[r.Outcome]++
 	}
 
 	return c.JSON(http.StatusOK, SuccessResponse{
-		Data:    transfer,
-		Message: "Transfer reversed",
+		Data:    results,
+		Message: localize(c, "northwind.pending_transfers_cancelled"),
+		Meta:    summary,
 	})
 }
 
-// --- NorthWind Health ---
-
-// NorthwindHealth checks NorthWind API health
-func (h *NorthwindHandler) NorthwindHealth(c echo.Context) error {
-	health, err := h.client.Health(c.Request().Context())
+// AdminGetTransferDurationStats returns p50/p95 initiated-to-completed durations per transfer
+// type over the last 90 days (cached for an hour)
+func (h *NorthwindHandler) AdminGetTransferDurationStats(c echo.Context) error {
+	report, err := h.statsSvc.DurationStats(c.Request().Context())
 	if err != nil {
-		return SendError(c, appErrors.NorthwindAPIUnavailable, appErrors.WithDetails(err.Error()))
+		return SendSystemError(c, err)
 	}
+
 	return c.JSON(http.StatusOK, SuccessResponse{
-		Data:    health,
-		Message: "NorthWind API is healthy",
+		Data:    report,
+		Message: localize(c, "northwind.duration_stats_retrieved"),
 	})
 }
 
-// NorthwindReset resets NorthWind state (development only)
-func (h *NorthwindHandler) NorthwindReset(c echo.Context) error {
+// SetPollingProfileRequest overrides a transfer type's polling profile; durations are Go
+// duration strings such as "5s" or "10m"
+type SetPollingProfileRequest struct {
+	InitialDelay string `json:"initial_delay" validate:"required"`
+	MinInterval  string `json:"min_interval" validate:"required"`
+	MaxInterval  string `json:"max_interval" validate:"required"`
+}
+
+// AdminListPollingProfiles returns the effective polling profile of every transfer type
+func (h *NorthwindHandler) AdminListPollingProfiles(c echo.Context) error {
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    h.pollSchedule.List(),
+		Message: localize(c, "northwind.polling_profiles_retrieved"),
+	})
+}
+
+// AdminSetPollingProfile overrides a transfer type's polling profile without a restart. The
+// override applies to this instance until cleared or the process restarts.
+func (h *NorthwindHandler) AdminSetPollingProfile(c echo.Context) error {
+	var req SetPollingProfileRequest
+	if err := c.Bind(&req); err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid request body"))
+	}
+	if err := validateRequest(c, req); err != nil {
+		return err
+	}
+
+	var profile config.PollingProfile
+	var details []string
+	for _, field := range []struct {
+		name  string
+		value string
+		dst   *time.Duration
+	}{
+		{"initial_delay", req.InitialDelay, &profile.InitialDelay},
+		{"min_interval", req.MinInterval, &profile.MinInterval},
+		{"max_interval", req.MaxInterval, &profile.MaxInterval},
+	} {
+		d, err := time.ParseDuration(field.value)
+		if err != nil {
+			details = append(details, field.name+": must be a duration such as 30s or 10m")
+			continue
+		}
+		*field.dst = d
+	}
+	if len(details) > 0 {
+		return SendError(c, appErrors.ValidationInvalidFormat, appErrors.WithDetails(details...))
+	}
+
+	if err := h.pollSchedule.SetOverride(c.Param("type"), profile); err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails(err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    h.pollSchedule.List(),
+		Message: localize(c, "northwind.polling_profile_updated"),
+	})
+}
+
+// AdminClearPollingProfile removes a runtime override so the configured profile applies again
+func (h *NorthwindHandler) AdminClearPollingProfile(c echo.Context) error {
+	h.pollSchedule.ClearOverride(c.Param("type"))
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    h.pollSchedule.List(),
+		Message: localize(c, "northwind.polling_profile_removed"),
+	})
+}
+
+// maintenanceStatus is the scheduled NorthWind maintenance window and whether it is open now
+type maintenanceStatus struct {
+	Window *services.MaintenanceWindow `json:"window"`
+	Active bool                        `json:"active"`
+}
+
+func (h *NorthwindHandler) currentMaintenance() maintenanceStatus {
+	_, active := h.maintenance.Active()
+	return maintenanceStatus{Window: h.maintenance.Window(), Active: active}
+}
+
+// AdminGetMaintenance returns the scheduled NorthWind maintenance window
+func (h *NorthwindHandler) AdminGetMaintenance(c echo.Context) error {
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    h.currentMaintenance(),
+		Message: localize(c, "northwind.maintenance_retrieved"),
+	})
+}
+
+// AdminSetMaintenance schedules a NorthWind maintenance window, replacing any other. Transfers
+// created while it is open are queued and initiated after it ends.
+func (h *NorthwindHandler) AdminSetMaintenance(c echo.Context) error {
+	var req struct {
+		Start time.Time `json:"start" validate:"required"`
+		End   time.Time `json:"end" validate:"required"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid request body; start and end are RFC 3339 timestamps"))
+	}
+	if err := validateRequest(c, req); err != nil {
+		return err
+	}
+	if err := h.maintenance.Set(services.MaintenanceWindow{Start: req.Start, End: req.End}); err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails(err.Error()))
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    h.currentMaintenance(),
+		Message: localize(c, "northwind.maintenance_scheduled"),
+	})
+}
+
+// AdminClearMaintenance removes the maintenance window, ending it early if it is open; queued
+// transfers are initiated on the next worker run
+func (h *NorthwindHandler) AdminClearMaintenance(c echo.Context) error {
+	h.maintenance.Clear()
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    h.currentMaintenance(),
+		Message: localize(c, "northwind.maintenance_cleared"),
+	})
+}
+
+// AdminGetDashboard reports the health of the whole NorthWind integration for the status page.
+// It always answers 200: sections that failed or timed out carry an error marker instead of data.
+func (h *NorthwindHandler) AdminGetDashboard(c echo.Context) error {
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    h.dashboard.Dashboard(c.Request().Context()),
+		Message: localize(c, "northwind.dashboard_retrieved"),
+	})
+}
+
+// AdminListPollAnomalies lists quarantined NorthWind poll responses, unresolved ones unless
+// ?resolved=true
+func (h *NorthwindHandler) AdminListPollAnomalies(c echo.Context) error {
+	q := newQueryParams(c)
+	resolved := q.Enum("resolved", "true", "false") == "true"
+	offset := q.Offset()
+	limit := q.Limit()
+	if !q.Valid() {
+		return q.SendError()
+	}
+
+	anomalies, total, err := h.pollAnomalies.List(c.Request().Context(), resolved, offset, limit)
+	if err != nil {
+		return SendSystemError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    anomalies,
+		Message: localize(c, "northwind.poll_anomalies_retrieved"),
+		Meta: map[string]interface{}{
+			"total":  total,
+			"offset": offset,
+			"limit":  limit,
+		},
+	})
+}
+
+// AdminReplayPollAnomalies reprocesses unresolved poll anomalies, for use once the status mapping
+// has been updated to handle them
+func (h *NorthwindHandler) AdminReplayPollAnomalies(c echo.Context) error {
+	report, err := h.pollAnomalies.Replay(c.Request().Context())
+	if err != nil {
+		return SendSystemError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    report,
+		Message: localize(c, "northwind.poll_anomalies_replayed"),
+	})
+}
+
+// AdminListDisputes lists transfer disputes oldest first, optionally only those in ?status
+func (h *NorthwindHandler) AdminListDisputes(c echo.Context) error {
+	q := newQueryParams(c)
+	status := q.Enum("status", models.DisputeStatusOpen, models.DisputeStatusUnderReview, models.DisputeStatusResolved)
+	offset := q.Offset()
+	limit := q.Limit()
+	if !q.Valid() {
+		return q.SendError()
+	}
+
+	disputes, total, err := h.disputes.List(c.Request().Context(), status, offset, limit)
+	if err != nil {
+		return SendSystemError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    disputes,
+		Message: localize(c, "northwind.disputes_retrieved"),
+		Meta: map[string]interface{}{
+			"total":  total,
+			"offset": offset,
+			"limit":  limit,
+		},
+	})
+}
+
+// AdminReviewDispute takes an open dispute under review by the calling admin
+func (h *NorthwindHandler) AdminReviewDispute(c echo.Context) error {
+	adminID, err := getUserIDFromContext(c)
+	if err != nil {
+		return SendError(c, appErrors.AuthMissingToken)
+	}
+	disputeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid dispute ID"))
+	}
+
+	dispute, err := h.disputes.StartReview(c.Request().Context(), disputeID, adminID)
+	if err != nil {
+		return sendDisputeError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    dispute,
+		Message: localize(c, "northwind.dispute_under_review"),
+	})
+}
+
+// AdminResolveDispute resolves a dispute; deciding reverse reverses the transfer with NorthWind
+func (h *NorthwindHandler) AdminResolveDispute(c echo.Context) error {
+	adminID, err := getUserIDFromContext(c)
+	if err != nil {
+		return SendError(c, appErrors.AuthMissingToken)
+	}
+	disputeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid dispute ID"))
+	}
+	var req services.ResolveDisputeRequest
+	if err := c.Bind(&req); err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid request body"))
+	}
+	if err := validateRequest(c, req); err != nil {
+		return err
+	}
+
+	dispute, err := h.disputes.Resolve(c.Request().Context(), disputeID, adminID, req)
+	if err != nil {
+		return sendDisputeError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    dispute,
+		Message: localize(c, "northwind.dispute_resolved"),
+	})
+}
+
+// AdminVerifyReceipt checks a verification hash quoted from a transfer receipt
+func (h *NorthwindHandler) AdminVerifyReceipt(c echo.Context) error {
+	var req struct {
+		TransferID       uuid.UUID `json:"transfer_id" validate:"required"`
+		VerificationHash string    `json:"verification_hash" validate:"required"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid request body"))
+	}
+	if err := validateRequest(c, req); err != nil {
+		return err
+	}
+
+	result, err := h.receiptSvc.VerifyReceipt(c.Request().Context(), req.TransferID, req.VerificationHash)
+	if err != nil {
+		if errors.Is(err, services.ErrNWTransferNotFound) {
+			return SendError(c, appErrors.NorthwindTransferNotFound)
+		}
+		return SendSystemError(c, err)
+	}
+
+	message := localize(c, "northwind.receipt_verified")
+	if !result.Valid {
+		message = localize(c, "northwind.receipt_mismatch")
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    result,
+		Message: message,
+	})
+}
+
+// adminTransferResponse is a transfer as admins see it, with the client that initiated it
+type adminTransferResponse struct {
+	Transfer     *models.NorthwindTransfer      `json:"transfer"`
+	Origin       models.NorthwindTransferOrigin `json:"origin"`
+	InternalTest bool                           `json:"internal_test"`
+}
+
+// internalTestRequest flags or unflags a transfer as an internal test transfer
+type internalTestRequest struct {
+	InternalTest *bool `json:"internal_test"`
+}
+
+// AdminGetTransfer returns any user's transfer with the IP and User-Agent it was initiated from
+func (h *NorthwindHandler) AdminGetTransfer(c echo.Context) error {
+	transferID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid transfer ID"))
+	}
+
+	transfer, err := h.transferSvc.GetAnyTransfer(c.Request().Context(), transferID)
+	if err != nil {
+		if errors.Is(err, services.ErrNWTransferNotFound) {
+			return SendError(c, appErrors.NorthwindTransferNotFound)
+		}
+		return SendSystemError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    adminTransferResponse{Transfer: transfer, Origin: transfer.Origin(), InternalTest: transfer.InternalTest},
+		Message: localize(c, "northwind.transfer_retrieved"),
+	})
+}
+
+// AdminSetTransferInternalTest flags or unflags any user's transfer as an internal test transfer,
+// which is never reported to the regulator. The change is audited.
+func (h *NorthwindHandler) AdminSetTransferInternalTest(c echo.Context) error {
+	adminID, err := getUserIDFromContext(c)
+	if err != nil {
+		return SendError(c, appErrors.AuthMissingToken)
+	}
+	transferID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid transfer ID"))
+	}
+	var req internalTestRequest
+	if err := c.Bind(&req); err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid request body"))
+	}
+	if req.InternalTest == nil {
+		return SendError(c, appErrors.ValidationRequiredField, appErrors.WithDetails("internal_test is required"))
+	}
+
+	meta := services.RequestMetadata{IPAddress: c.RealIP(), UserAgent: c.Request().UserAgent()}
+	transfer, err := h.transferSvc.SetInternalTest(c.Request().Context(), transferID, *req.InternalTest, adminID, meta)
+	if err != nil {
+		if errors.Is(err, services.ErrNWTransferNotFound) {
+			return SendError(c, appErrors.NorthwindTransferNotFound)
+		}
+		return SendSystemError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    adminTransferResponse{Transfer: transfer, Origin: transfer.Origin(), InternalTest: transfer.InternalTest},
+		Message: localize(c, "northwind.internal_test_updated"),
+	})
+}
+
+// AdminCompareTransfer returns a transfer's local record next to NorthWind's live record with a
+// field-by-field diff; remote_missing is set when NorthWind does not know the transfer
+func (h *NorthwindHandler) AdminCompareTransfer(c echo.Context) error {
+	transferID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid transfer ID"))
+	}
+
+	comparison, err := h.transferSvc.CompareTransfer(c.Request().Context(), transferID)
+	if err != nil {
+		if errors.Is(err, services.ErrNWTransferNotFound) {
+			return SendError(c, appErrors.NorthwindTransferNotFound)
+		}
+		return sendNorthwindError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    comparison,
+		Message: localize(c, "northwind.comparison_retrieved"),
+	})
+}
+
+// AdminGetUpstreamTransfer reads a transfer straight from NorthWind by its NorthWind ID, for
+// transfers missing from our database. With ?adopt=true the record is also stored locally,
+// linked to the user whose unsent transfer has the same reference number.
+func (h *NorthwindHandler) AdminGetUpstreamTransfer(c echo.Context) error {
+	northwindID, err := uuid.Parse(c.Param("northwind_id"))
+	if err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid NorthWind transfer ID"))
+	}
+
+	adopt := false
+	if raw := c.QueryParam("adopt"); raw != "" {
+		adopt, err = strconv.ParseBool(raw)
+		if err != nil {
+			return SendError(c, appErrors.ValidationInvalidQuery, appErrors.WithDetails("adopt must be true or false"))
+		}
+	}
+
+	var upstream *services.UpstreamTransfer
+	if adopt {
+		upstream, err = h.transferSvc.AdoptUpstreamTransfer(c.Request().Context(), northwindID)
+	} else {
+		upstream, err = h.transferSvc.GetUpstreamTransfer(c.Request().Context(), northwindID)
+	}
+	if err != nil {
+		if errors.Is(err, services.ErrNWTransferNotFound) {
+			return SendError(c, appErrors.NorthwindTransferNotFound)
+		}
+		return sendNorthwindError(c, err)
+	}
+
+	message := localize(c, "northwind.upstream_retrieved")
+	if upstream.Adopted {
+		message = localize(c, "northwind.upstream_adopted")
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{Data: upstream, Message: message})
+}
+
+// ReverseTransfer reverses a completed transfer
+func (h *NorthwindHandler) ReverseTransfer(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return SendError(c, appErrors.AuthMissingToken)
+	}
+
+	transferID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid transfer ID"))
+	}
+
+	var req struct {
+		Reason      string `json:"reason" validate:"required"`
+		Description string `json:"description,omitempty"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid request body"))
+	}
+
+	transfer, err := h.transferSvc.ReverseTransfer(c.Request().Context(), userID, transferID, req.Reason, req.Description, models.UserInitiator(userID))
+	if err != nil {
+		if errors.Is(err, services.ErrNWTransferNotFound) {
+			return SendError(c, appErrors.NorthwindTransferNotFound)
+		}
+		if isNorthwindError(err) {
+			return sendNorthwindError(c, err)
+		}
+		return SendError(c, appErrors.NorthwindTransferReverseFail, appErrors.WithDetails(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    transfer,
+		Message: localize(c, "northwind.transfer_reversed"),
+	})
+}
+
+// CreateTransferDispute disputes one of the caller's completed transfers
+func (h *NorthwindHandler) CreateTransferDispute(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return SendError(c, appErrors.AuthMissingToken)
+	}
+	transferID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid transfer ID"))
+	}
+	var req services.OpenDisputeRequest
+	if err := c.Bind(&req); err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid request body"))
+	}
+	if err := validateRequest(c, req); err != nil {
+		return err
+	}
+
+	dispute, err := h.disputes.Open(c.Request().Context(), userID, transferID, req)
+	if err != nil {
+		return sendDisputeError(c, err)
+	}
+	return c.JSON(http.StatusCreated, SuccessResponse{
+		Data:    dispute,
+		Message: localize(c, "northwind.dispute_opened"),
+	})
+}
+
+// ListTransferDisputes lists the disputes of one of the caller's transfers, newest first
+func (h *NorthwindHandler) ListTransferDisputes(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return SendError(c, appErrors.AuthMissingToken)
+	}
+	transferID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid transfer ID"))
+	}
+
+	disputes, err := h.disputes.ListForTransfer(c.Request().Context(), userID, transferID)
+	if err != nil {
+		return sendDisputeError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    disputes,
+		Message: localize(c, "northwind.disputes_retrieved"),
+	})
+}
+
+// sendDisputeError maps dispute service errors to responses; NorthWind's own failures, such as
+// refusing the reversal of an upheld dispute, are translated like any other NorthWind call's
+func sendDisputeError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrNWTransferNotFound):
+		return SendError(c, appErrors.NorthwindTransferNotFound)
+	case errors.Is(err, services.ErrNWDisputeNotFound):
+		return SendError(c, appErrors.NorthwindDisputeNotFound)
+	case errors.Is(err, services.ErrNWDisputeNotCompleted):
+		return SendError(c, appErrors.NorthwindDisputeNotCompleted)
+	case errors.Is(err, services.ErrNWDisputeWindowClosed):
+		return SendError(c, appErrors.NorthwindDisputeWindowClosed)
+	case errors.Is(err, services.ErrNWDisputeAlreadyOpen):
+		return SendError(c, appErrors.NorthwindDisputeAlreadyOpen)
+	case errors.Is(err, services.ErrNWDisputeTransition):
+		return SendError(c, appErrors.NorthwindDisputeTransition)
+	case isNorthwindError(err):
+		return sendNorthwindError(c, err)
+	}
+	return SendSystemError(c, err)
+}
+
+// --- NorthWind Health ---
+
+// NorthwindHealth checks NorthWind API health
+func (h *NorthwindHandler) NorthwindHealth(c echo.Context) error {
+	health, err := h.client.Health(c.Request().Context())
+	if err != nil {
+		return SendError(c, appErrors.NorthwindAPIUnavailable, appErrors.WithDetails(err.Error()))
+	}
+	apiKey := h.client.ActiveAPIKey()
+	message := localize(c, "northwind.healthy")
+	if apiKey == northwind.APIKeySecondary {
+		message = localize(c, "northwind.healthy_secondary_key")
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    northwindHealthResponse{HealthResponse: health, APIKey: apiKey},
+		Message: message,
+	})
+}
+
+// northwindHealthResponse is NorthWind's health with the API key the client is authenticating with
+type northwindHealthResponse struct {
+	*northwind.HealthResponse
+	APIKey northwind.APIKeyID `json:"api_key"`
+}
+
+// NorthwindReset resets NorthWind state (development and testing only).
+// The request body must contain {"confirm": "RESET"}.
+func (h *NorthwindHandler) NorthwindReset(c echo.Context) error {
+	if h.env == nil || !(h.env.IsDevelopment() || h.env.IsTesting()) {
+		return SendError(c, appErrors.SystemNotAvailableInEnv)
+	}
+
+	var req struct {
+		Confirm string `json:"confirm"`
+	}
+	if err := c.Bind(&req); err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid request body"))
+	}
+	if req.Confirm != resetConfirmation {
+		return SendError(c, appErrors.ValidationRequiredField, appErrors.WithDetails(`confirm: must be "RESET"`))
+	}
+
 	if err := h.client.Reset(c.Request().Context()); err != nil {
-		return SendError(c, appErrors.NorthwindAPIError, appErrors.WithDetails(err.Error()))
+		return sendNorthwindError(c, err)
 	}
 	return c.JSON(http.StatusOK, SuccessResponse{
-		Message: "NorthWind state reset",
+		Message: localize(c, "northwind.state_reset"),
 	})
 }
@@ -1,19 +1,36 @@
 package handlers
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/array/banking-api/internal/config"
 	"github.com/array/banking-api/internal/database"
+	appErrors "github.com/array/banking-api/internal/errors"
+	"github.com/array/banking-api/internal/i18n"
 	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
 	"github.com/array/banking-api/internal/repositories"
 	"github.com/array/banking-api/internal/services"
+	"github.com/array/banking-api/internal/services/service_mocks"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/array/banking-api/internal/validation"
+	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 	"log/slog"
 )
 
@@ -37,8 +54,8 @@ func TestNorthwindHandler_GetBankInfo_Success(t *testing.T) {
 	nwExtRepo := repositories.NewNorthwindExternalAccountRepository(db.DB)
 	nwTransferRepo := repositories.NewNorthwindTransferRepository(db.DB)
 	accountSvc := services.NewNorthwindAccountService(client, nwExtRepo, slog.Default())
-	transferSvc := services.NewNorthwindTransferService(client, nwTransferRepo, slog.Default())
-	handler := NewNorthwindHandler(client, accountSvc, transferSvc)
+	transferSvc := services.NewNorthwindTransferService(client, nwTransferRepo, nwExtRepo, nil, slog.Default())
+	handler := NewNorthwindHandler(client, accountSvc, transferSvc, nil, nil, testEnv("testing"))
 
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/northwind/bank", nil)
@@ -72,8 +89,8 @@ func TestNorthwindHandler_GetBankInfo_APIError(t *testing.T) {
 	nwExtRepo := repositories.NewNorthwindExternalAccountRepository(db.DB)
 	nwTransferRepo := repositories.NewNorthwindTransferRepository(db.DB)
 	accountSvc := services.NewNorthwindAccountService(client, nwExtRepo, slog.Default())
-	transferSvc := services.NewNorthwindTransferService(client, nwTransferRepo, slog.Default())
-	handler := NewNorthwindHandler(client, accountSvc, transferSvc)
+	transferSvc := services.NewNorthwindTransferService(client, nwTransferRepo, nwExtRepo, nil, slog.Default())
+	handler := NewNorthwindHandler(client, accountSvc, transferSvc, nil, nil, testEnv("testing"))
 
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/northwind/bank", nil)
@@ -105,8 +122,8 @@ func TestNorthwindHandler_GetDomains_Success(t *testing.T) {
 	nwExtRepo := repositories.NewNorthwindExternalAccountRepository(db.DB)
 	nwTransferRepo := repositories.NewNorthwindTransferRepository(db.DB)
 	accountSvc := services.NewNorthwindAccountService(client, nwExtRepo, slog.Default())
-	transferSvc := services.NewNorthwindTransferService(client, nwTransferRepo, slog.Default())
-	handler := NewNorthwindHandler(client, accountSvc, transferSvc)
+	transferSvc := services.NewNorthwindTransferService(client, nwTransferRepo, nwExtRepo, nil, slog.Default())
+	handler := NewNorthwindHandler(client, accountSvc, transferSvc, nil, nil, testEnv("testing"))
 
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/northwind/domains", nil)
@@ -126,3 +143,1606 @@ func TestNorthwindHandler_GetDomains_Success(t *testing.T) {
 	assert.Equal(t, "ach", body.Data[0].Name)
 }
 
+func testEnv(env string) *config.Config {
+	return &config.Config{Server: config.ServerConfig{Environment: env}}
+}
+
+func newResetTestHandler(t *testing.T, env string, resetCalls *int) (*NorthwindHandler, func()) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/external/reset" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		*resetCalls++
+		w.WriteHeader(http.StatusOK)
+	}))
+	client := northwind.NewClient(server.URL, "test-key")
+	return NewNorthwindHandler(client, nil, nil, nil, nil, testEnv(env)), server.Close
+}
+
+func TestNorthwindHandler_NorthwindReset_Environments(t *testing.T) {
+	tests := []struct {
+		env            string
+		expectedStatus int
+		expectedCalls  int
+	}{
+		{env: "development", expectedStatus: http.StatusOK, expectedCalls: 1},
+		{env: "testing", expectedStatus: http.StatusOK, expectedCalls: 1},
+		{env: "production", expectedStatus: http.StatusForbidden, expectedCalls: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			calls := 0
+			handler, closeServer := newResetTestHandler(t, tt.env, &calls)
+			defer closeServer()
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/northwind/reset", strings.NewReader(`{"confirm":"RESET"}`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			require.NoError(t, handler.NorthwindReset(c))
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedCalls, calls)
+
+			if tt.expectedStatus == http.StatusForbidden {
+				var body ErrorResponse
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+				assert.Equal(t, "NOT_AVAILABLE_IN_ENV", body.Error.Code)
+			}
+		})
+	}
+}
+
+func TestNorthwindHandler_NorthwindReset_RequiresConfirmation(t *testing.T) {
+	bodies := []string{``, `{}`, `{"confirm":"reset"}`, `{"confirm":"yes"}`}
+
+	for _, b := range bodies {
+		t.Run(b, func(t *testing.T) {
+			calls := 0
+			handler, closeServer := newResetTestHandler(t, "development", &calls)
+			defer closeServer()
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/northwind/reset", strings.NewReader(b))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			require.NoError(t, handler.NorthwindReset(c))
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Equal(t, 0, calls)
+		})
+	}
+}
+
+func newWaitTestHandler(t *testing.T) (*NorthwindHandler, *models.NorthwindTransfer) {
+	t.Helper()
+	db := testfactory.NewDB(t)
+	transfer := testfactory.NWTransfer(t, db)
+
+	nwTransferRepo := repositories.NewNorthwindTransferRepository(db)
+	transferSvc := services.NewNorthwindTransferService(nil, nwTransferRepo, nil, nil, slog.Default())
+	return NewNorthwindHandler(nil, nil, transferSvc, nil, nil, testEnv("testing")), transfer
+}
+
+func waitRequest(handler *NorthwindHandler, userID uuid.UUID, transferID, query string) *httptest.ResponseRecorder {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/northwind/transfers/"+transferID+"/wait?"+query, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user_id", userID)
+	c.SetParamNames("id")
+	c.SetParamValues(transferID)
+	_ = handler.WaitForTransfer(c)
+	return rec
+}
+
+func TestNorthwindHandler_WaitForTransfer_ReturnsOnShutdown(t *testing.T) {
+	handler, transfer := newWaitTestHandler(t)
+	shutdown := make(chan struct{})
+	handler.SetShutdownSignal(shutdown)
+	time.AfterFunc(50*time.Millisecond, func() { close(shutdown) })
+
+	start := time.Now()
+	rec := waitRequest(handler, *transfer.UserID, transfer.ID.String(), "timeout=30s&since_version=1")
+
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var body struct {
+		Data struct {
+			Transfer models.NorthwindTransfer `json:"transfer"`
+			Changed  bool                     `json:"changed"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.False(t, body.Data.Changed)
+	assert.Equal(t, transfer.ID, body.Data.Transfer.ID)
+	assert.Equal(t, 1, body.Data.Transfer.Version)
+}
+
+func TestNorthwindHandler_WaitForTransfer_ChangedSinceVersion(t *testing.T) {
+	handler, transfer := newWaitTestHandler(t)
+
+	rec := waitRequest(handler, *transfer.UserID, transfer.ID.String(), "since_version=0")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"changed":true`)
+}
+
+func TestNorthwindHandler_WaitForTransfer_ClientDisconnected(t *testing.T) {
+	handler, transfer := newWaitTestHandler(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/northwind/transfers/"+transfer.ID.String()+"/wait", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user_id", *transfer.UserID)
+	c.SetParamNames("id")
+	c.SetParamValues(transfer.ID.String())
+
+	require.NoError(t, handler.WaitForTransfer(c))
+	assert.NotEqual(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestNorthwindHandler_WaitForTransfer_InvalidParams(t *testing.T) {
+	handler, transfer := newWaitTestHandler(t)
+
+	tests := map[string]struct {
+		userID     uuid.UUID
+		transferID string
+		query      string
+		status     int
+	}{
+		"bad timeout":           {*transfer.UserID, transfer.ID.String(), "timeout=soon", http.StatusBadRequest},
+		"negative timeout":      {*transfer.UserID, transfer.ID.String(), "timeout=-5s", http.StatusBadRequest},
+		"bad since_version":     {*transfer.UserID, transfer.ID.String(), "since_version=abc", http.StatusBadRequest},
+		"bad transfer ID":       {*transfer.UserID, "not-a-uuid", "", http.StatusBadRequest},
+		"other user's transfer": {uuid.New(), transfer.ID.String(), "since_version=0", http.StatusNotFound},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := waitRequest(handler, tt.userID, tt.transferID, tt.query)
+			assert.Equal(t, tt.status, rec.Code)
+		})
+	}
+}
+
+func newListTestHandler(t *testing.T) (*NorthwindHandler, uuid.UUID) {
+	t.Helper()
+	db := testfactory.NewDB(t)
+	userID := uuid.New()
+	testfactory.NWTransfer(t, db, testfactory.WithUser(userID))
+	testfactory.NWExternalAccount(t, db, testfactory.WithOwner(userID))
+
+	accountSvc := services.NewNorthwindAccountService(nil, repositories.NewNorthwindExternalAccountRepository(db), slog.Default())
+	transferSvc := services.NewNorthwindTransferService(nil, repositories.NewNorthwindTransferRepository(db), nil, nil, slog.Default())
+	return NewNorthwindHandler(nil, accountSvc, transferSvc, nil, nil, testEnv("testing")), userID
+}
+
+func listRequest(userID uuid.UUID, query string, handle func(echo.Context) error) *httptest.ResponseRecorder {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/northwind/list?"+query, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user_id", userID)
+	_ = handle(c)
+	return rec
+}
+
+func TestNorthwindHandler_ListEndpoints_QueryValidation(t *testing.T) {
+	handler, userID := newListTestHandler(t)
+	endpoints := map[string]func(echo.Context) error{
+		"ListTransfers":          handler.ListTransfers,
+		"ListRegisteredAccounts": handler.ListRegisteredAccounts,
+	}
+	tests := map[string]struct {
+		query     string
+		status    int
+		wantLimit int
+		detail    string
+	}{
+		"absent":           {"", http.StatusOK, 20, ""},
+		"valid":            {"offset=0&limit=5", http.StatusOK, 5, ""},
+		"malformed limit":  {"limit=abc", http.StatusUnprocessableEntity, 0, "limit: must be an integer"},
+		"negative offset":  {"offset=-1", http.StatusUnprocessableEntity, 0, "offset: must be at least 0"},
+		"limit over max":   {"limit=101", http.StatusUnprocessableEntity, 0, "limit: must be at most 100"},
+		"zero limit":       {"limit=0", http.StatusUnprocessableEntity, 0, "limit: must be at least 1"},
+		"malformed offset": {"offset=1.5", http.StatusUnprocessableEntity, 0, "offset: must be an integer"},
+	}
+	for endpoint, handle := range endpoints {
+		for name, tt := range tests {
+			t.Run(endpoint+"/"+name, func(t *testing.T) {
+				rec := listRequest(userID, tt.query, handle)
+				require.Equal(t, tt.status, rec.Code, rec.Body.String())
+				if tt.status != http.StatusOK {
+					assert.Contains(t, rec.Body.String(), tt.detail)
+					return
+				}
+				var body struct {
+					Meta map[string]interface{} `json:"meta"`
+				}
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+				assert.EqualValues(t, tt.wantLimit, body.Meta["limit"])
+				assert.EqualValues(t, 1, body.Meta["total"])
+			})
+		}
+	}
+}
+
+func TestNorthwindHandler_ListAccessibleAccounts_Meta(t *testing.T) {
+	tests := map[string]struct {
+		body      string
+		wantTotal interface{}
+	}{
+		"bare array":    {`[{"account_number":"acc1"}]`, nil},
+		"wrapped":       {`{"accounts":[{"account_number":"acc1"}],"total_count":12}`, float64(12)},
+		"empty wrapped": {`{}`, nil},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "1", r.URL.Query().Get("limit"))
+				assert.Equal(t, "3", r.URL.Query().Get("offset"))
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+			accountSvc := services.NewNorthwindAccountService(northwind.NewClient(server.URL, "test-key"), nil, slog.Default())
+			handler := NewNorthwindHandler(nil, accountSvc, nil, nil, nil, testEnv("testing"))
+
+			rec := listRequest(uuid.New(), "offset=3&limit=1", handler.ListAccessibleAccounts)
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			var body struct {
+				Data []northwind.ExternalAccount `json:"data"`
+				Meta map[string]interface{}      `json:"meta"`
+			}
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+			assert.NotNil(t, body.Data)
+			assert.EqualValues(t, 3, body.Meta["offset"])
+			assert.EqualValues(t, 1, body.Meta["limit"])
+			total, present := body.Meta["total"]
+			if tt.wantTotal == nil {
+				assert.False(t, present, "no total without one from NorthWind")
+			} else {
+				assert.Equal(t, tt.wantTotal, total)
+			}
+		})
+	}
+}
+
+func TestNorthwindHandler_ListTransfers_EnumValidation(t *testing.T) {
+	handler, userID := newListTestHandler(t)
+
+	tests := map[string]struct {
+		query  string
+		status int
+		detail string
+	}{
+		"known status":      {"status=PENDING", http.StatusOK, ""},
+		"known direction":   {"direction=OUTBOUND", http.StatusOK, ""},
+		"unknown status":    {"status=DONE", http.StatusUnprocessableEntity, "status: must be one of INITIATION_PENDING, SCHEDULED, PENDING"},
+		"unknown direction": {"direction=UP", http.StatusUnprocessableEntity, "direction: must be one of INBOUND, OUTBOUND"},
+		"lowercase status":  {"status=pending", http.StatusUnprocessableEntity, "status: must be one of"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := listRequest(userID, tt.query, handler.ListTransfers)
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+			assert.Contains(t, rec.Body.String(), tt.detail)
+		})
+	}
+}
+
+func TestNorthwindHandler_EstimateTransfer(t *testing.T) {
+	policy, err := services.NewSameDayPolicy("14:45", "America/New_York", 5)
+	require.NoError(t, err)
+	transferSvc := services.NewNorthwindTransferService(nil, nil, nil, nil, slog.Default())
+	transferSvc.SetSameDayPolicy(policy)
+	handler := NewNorthwindHandler(nil, nil, transferSvc, nil, nil, testEnv("testing"))
+
+	tests := map[string]struct {
+		query  string
+		status int
+		detail string
+	}{
+		"same day ACH":          {"transfer_type=ACH&priority=SAME_DAY", http.StatusOK, `"expedite_fee":"5"`},
+		"standard wire":         {"transfer_type=WIRE", http.StatusOK, `"priority":"STANDARD"`},
+		"missing transfer type": {"priority=SAME_DAY", http.StatusUnprocessableEntity, "transfer_type: is required"},
+		"unknown priority":      {"transfer_type=ACH&priority=URGENT", http.StatusUnprocessableEntity, "priority: must be one of STANDARD, SAME_DAY"},
+		"same day wire":         {"transfer_type=WIRE&priority=SAME_DAY", http.StatusUnprocessableEntity, "only available for ACH"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := listRequest(uuid.New(), tt.query, handler.EstimateTransfer)
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+			assert.Contains(t, rec.Body.String(), tt.detail)
+		})
+	}
+}
+
+func TestSendCreateTransferError_Priority(t *testing.T) {
+	nextCutoff := time.Date(2025, time.June, 16, 18, 45, 0, 0, time.UTC)
+	tests := map[string]struct {
+		err    error
+		status int
+		code   string
+	}{
+		"type restriction": {fmt.Errorf("%w: got WIRE", services.ErrNWTransferPriorityNotAllowed), http.StatusBadRequest, string(appErrors.ValidationGeneral)},
+		"cutoff passed":    {&services.SameDayCutoffPassedError{NextCutoff: nextCutoff}, http.StatusConflict, string(appErrors.NorthwindTransferSameDayClosed)},
+		"overloaded":       {services.ErrNWTransferOverloaded, http.StatusServiceUnavailable, `"retry_after_seconds":2`},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := echo.New()
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest(http.MethodPost, "/api/v1/northwind/transfers", nil), rec)
+			require.NoError(t, sendCreateTransferError(c, tt.err))
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+			assert.Contains(t, rec.Body.String(), tt.code)
+		})
+	}
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodPost, "/api/v1/northwind/transfers", nil), rec)
+	require.NoError(t, sendCreateTransferError(c, &services.SameDayCutoffPassedError{NextCutoff: nextCutoff}))
+	assert.Contains(t, rec.Body.String(), `"next_cutoff":"2025-06-16T18:45:00Z"`)
+}
+
+func TestNorthwindHandler_ListTransfers_Cursor(t *testing.T) {
+	db := testfactory.NewDB(t)
+	userID := uuid.New()
+	for i := 0; i < 3; i++ {
+		testfactory.NWTransfer(t, db, testfactory.WithUser(userID), testfactory.WithCreatedAt(time.Now().Add(-time.Duration(i)*time.Minute)))
+	}
+	transferSvc := services.NewNorthwindTransferService(nil, repositories.NewNorthwindTransferRepository(db), nil, nil, slog.Default())
+	handler := NewNorthwindHandler(nil, nil, transferSvc, nil, nil, testEnv("testing"))
+
+	type page struct {
+		Data []models.NorthwindTransfer `json:"data"`
+		Meta map[string]interface{}     `json:"meta"`
+	}
+	seen := make(map[uuid.UUID]bool)
+	query := "cursor=&limit=2"
+	for i := 0; ; i++ {
+		rec := listRequest(userID, query, handler.ListTransfers)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var body page
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+		assert.NotContains(t, body.Meta, "total")
+		for _, tr := range body.Data {
+			assert.False(t, seen[tr.ID], "transfer returned twice")
+			seen[tr.ID] = true
+		}
+		next, _ := body.Meta["next_cursor"].(string)
+		if next == "" {
+			break
+		}
+		require.Less(t, i, 2, "cursor never ran out")
+		query = "limit=2&cursor=" + url.QueryEscape(next)
+	}
+	assert.Len(t, seen, 3)
+
+	rec := listRequest(userID, "cursor=bogus", handler.ListTransfers)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), "cursor: is invalid")
+
+	rec = listRequest(userID, "cursor=&offset=10", handler.ListTransfers)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), "offset: cannot be combined with cursor")
+}
+
+func TestNorthwindHandler_CreateTransfer_PossibleDuplicate(t *testing.T) {
+	db := testfactory.NewDB(t)
+	userID := uuid.New()
+	existing := testfactory.NWTransfer(t, db, testfactory.WithUser(userID), testfactory.WithAmount(250))
+
+	transferSvc := services.NewNorthwindTransferService(nil, repositories.NewNorthwindTransferRepository(db), nil, nil, slog.Default())
+	handler := NewNorthwindHandler(nil, nil, transferSvc, nil, nil, testEnv("testing"))
+
+	body := `{"amount":250,"currency":"USD","direction":"OUTBOUND","transfer_type":"ACH",` +
+		`"source_account":{"account_holder_name":"Source","account_number":"1111111111"},` +
+		`"destination_account":{"account_holder_name":"Destination","account_number":"` + existing.DestinationAccountNumber + `"}}`
+	e := echo.New()
+	e.Validator = validation.EchoValidator()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/northwind/transfers", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user_id", userID)
+
+	require.NoError(t, handler.CreateTransfer(c))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"POSSIBLE_DUPLICATE"`)
+	assert.Contains(t, rec.Body.String(), "existing_transfer_id: "+existing.ID.String())
+}
+
+func TestNorthwindHandler_CreateTransfer_ValidatesWithCallerRole(t *testing.T) {
+	rules := validation.NewDynamicRules(&config.Config{NorthWind: config.NorthWindConfig{AdminOnlyTransferTypes: []string{"WIRE"}}}, nil)
+	for name, isAdmin := range map[string]bool{"user": false, "admin": true} {
+		t.Run(name, func(t *testing.T) {
+			db := testfactory.NewDB(t)
+			userID := uuid.New()
+			existing := testfactory.NWTransfer(t, db, testfactory.WithUser(userID), testfactory.WithAmount(250))
+			transferSvc := services.NewNorthwindTransferService(nil, repositories.NewNorthwindTransferRepository(db), nil, nil, slog.Default())
+			handler := NewNorthwindHandler(nil, nil, transferSvc, nil, nil, testEnv("testing"))
+
+			body := `{"amount":250,"currency":"USD","direction":"OUTBOUND","transfer_type":"WIRE",` +
+				`"source_account":{"account_holder_name":"Source","account_number":"1111111111"},` +
+				`"destination_account":{"account_holder_name":"Destination","account_number":"` + existing.DestinationAccountNumber + `"}}`
+			e := echo.New()
+			e.Validator = validation.NewValidator(validation.WithDynamicRules(rules)).Echo()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/northwind/transfers", strings.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("user_id", userID)
+			c.Set("is_admin", isAdmin)
+
+			err := handler.CreateTransfer(c)
+			if !isAdmin {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "nw_transfer_type_allowed")
+				return
+			}
+			// Past validation, the admin's request reaches the duplicate check
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusConflict, rec.Code)
+		})
+	}
+}
+
+func newReceiptTestHandler(t *testing.T) (*NorthwindHandler, *gorm.DB) {
+	t.Helper()
+	db := testfactory.NewDB(t)
+	receiptSvc := services.NewReceiptService(repositories.NewNorthwindTransferRepository(db), []byte("test-signing-key"))
+	return NewNorthwindHandler(nil, nil, nil, nil, receiptSvc, testEnv("testing")), db
+}
+
+func receiptRequest(handler *NorthwindHandler, userID uuid.UUID, transferID string) *httptest.ResponseRecorder {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/northwind/transfers/"+transferID+"/receipt", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user_id", userID)
+	c.SetParamNames("id")
+	c.SetParamValues(transferID)
+	_ = handler.GetTransferReceipt(c)
+	return rec
+}
+
+func TestNorthwindHandler_GetTransferReceipt(t *testing.T) {
+	handler, db := newReceiptTestHandler(t)
+	completed := testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusCompleted))
+
+	rec := receiptRequest(handler, *completed.UserID, completed.ID.String())
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/pdf", rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, `attachment; filename="receipt-`+completed.ReferenceNumber+`.pdf"`, rec.Header().Get(echo.HeaderContentDisposition))
+	hash := rec.Header().Get("X-Receipt-Verification-Hash")
+	require.Len(t, hash, 64)
+
+	content := rec.Body.String()
+	assert.True(t, strings.HasPrefix(content, "%PDF-"))
+	assert.Contains(t, content, "("+completed.ReferenceNumber+")")
+	assert.Contains(t, content, "("+completed.NorthwindTransferID.String()+")")
+	assert.Contains(t, content, "(100.50 USD)")
+	assert.Contains(t, content, "(****2222)")
+	assert.Contains(t, content, "("+hash+")")
+	assert.NotContains(t, content, completed.DestinationAccountNumber)
+}
+
+func TestNorthwindHandler_GetTransferReceipt_Errors(t *testing.T) {
+	handler, db := newReceiptTestHandler(t)
+	pending := testfactory.NWTransfer(t, db)
+	completed := testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusCompleted))
+
+	rec := receiptRequest(handler, *pending.UserID, pending.ID.String())
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"NORTHWIND_TRANSFER_010"`)
+
+	rec = receiptRequest(handler, uuid.New(), completed.ID.String())
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = receiptRequest(handler, *completed.UserID, "not-a-uuid")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestNorthwindHandler_AdminVerifyReceipt(t *testing.T) {
+	handler, db := newReceiptTestHandler(t)
+	completed := testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusCompleted))
+	hash := receiptRequest(handler, *completed.UserID, completed.ID.String()).Header().Get("X-Receipt-Verification-Hash")
+
+	verify := func(body string) *httptest.ResponseRecorder {
+		e := echo.New()
+		e.Validator = validation.EchoValidator()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/northwind/receipts/verify", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		if err := handler.AdminVerifyReceipt(c); err != nil {
+			e.HTTPErrorHandler(err, c)
+		}
+		return rec
+	}
+
+	var body struct {
+		Data services.ReceiptVerification `json:"data"`
+	}
+	rec := verify(`{"transfer_id":"` + completed.ID.String() + `","verification_hash":"` + hash + `"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.True(t, body.Data.Valid)
+	assert.Equal(t, completed.ReferenceNumber, body.Data.ReferenceNumber)
+
+	rec = verify(`{"transfer_id":"` + completed.ID.String() + `","verification_hash":"` + strings.Repeat("0", 64) + `"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.False(t, body.Data.Valid)
+
+	rec = verify(`{"transfer_id":"` + uuid.NewString() + `","verification_hash":"` + hash + `"}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = verify(`{"transfer_id":"` + completed.ID.String() + `"}`)
+	assert.NotEqual(t, http.StatusOK, rec.Code)
+}
+
+func TestNorthwindHandler_LocalizedMessages(t *testing.T) {
+	handler, db := newReceiptTestHandler(t)
+	completed := testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusCompleted))
+	pending := testfactory.NWTransfer(t, db)
+	catalog := i18n.Default()
+
+	request := func(acceptLanguage, method, body string, handle func(echo.Context) error, transferID string) (*httptest.ResponseRecorder, string) {
+		e := echo.New()
+		e.Validator = validation.EchoValidator()
+		req := httptest.NewRequest(method, "/", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set(LocalizerContextKey, catalog.Localizer(catalog.Negotiate(acceptLanguage, i18n.DefaultLocale)))
+		c.Set("user_id", *pending.UserID)
+		c.SetParamNames("id")
+		c.SetParamValues(transferID)
+		require.NoError(t, handle(c))
+		var parsed struct {
+			Message string `json:"message"`
+			Error   struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &parsed))
+		return rec, parsed.Message + parsed.Error.Message
+	}
+
+	verifyBody := `{"transfer_id":"` + completed.ID.String() + `","verification_hash":"` + strings.Repeat("0", 64) + `"}`
+	_, message := request("fr-CA,fr;q=0.9,en;q=0.5", http.MethodPost, verifyBody, handler.AdminVerifyReceipt, "")
+	assert.Equal(t, "Le hachage de vérification ne correspond pas à ce virement", message)
+	_, message = request("en-US", http.MethodPost, verifyBody, handler.AdminVerifyReceipt, "")
+	assert.Equal(t, "Verification hash does not match this transfer", message)
+
+	rec, message := request("fr", http.MethodGet, "", handler.GetTransferReceipt, pending.ID.String())
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "Les reçus ne sont offerts que pour les virements complétés ou contrepassés", message)
+	assert.Contains(t, rec.Body.String(), `"code":"NORTHWIND_TRANSFER_010"`, "codes are not translated")
+
+	// An error code the French catalog does not translate keeps the registry's English message
+	_, message = request("fr", http.MethodGet, "", func(c echo.Context) error {
+		return SendError(c, appErrors.CustomerNotFound)
+	}, "")
+	assert.Equal(t, "Customer not found", message)
+	// A message replaced with an upstream one is passed through as it is
+	_, message = request("fr", http.MethodGet, "", func(c echo.Context) error {
+		return SendError(c, appErrors.NorthwindAPIRejected, appErrors.WithMessage("Account frozen"))
+	}, "")
+	assert.Equal(t, "Account frozen", message)
+}
+
+// compareRequest calls the compare endpoint for a fresh PENDING transfer, with NorthWind's side
+// served by remote
+func compareRequest(t *testing.T, remote func(w http.ResponseWriter)) (*httptest.ResponseRecorder, *models.NorthwindTransfer) {
+	t.Helper()
+	db := testfactory.NewDB(t)
+	transfer := testfactory.NWTransfer(t, db)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/external/transfers/"+transfer.NorthwindTransferID.String(), r.URL.Path)
+		remote(w)
+	}))
+	t.Cleanup(server.Close)
+
+	transferSvc := services.NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), repositories.NewNorthwindTransferRepository(db), nil, nil, slog.Default())
+	handler := NewNorthwindHandler(nil, nil, transferSvc, nil, nil, testEnv("testing"))
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/northwind/transfers/"+transfer.ID.String()+"/compare", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(transfer.ID.String())
+	require.NoError(t, handler.AdminCompareTransfer(c))
+	return rec, transfer
+}
+
+func writeRemoteTransfer(resp northwind.TransferResponse) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}
+}
+
+type compareBody struct {
+	Data struct {
+		Local         models.NorthwindTransfer     `json:"local"`
+		Remote        *northwind.TransferResponse  `json:"remote"`
+		RemoteMissing bool                         `json:"remote_missing"`
+		Mismatches    int                          `json:"mismatches"`
+		Fields        []services.TransferFieldDiff `json:"fields"`
+	} `json:"data"`
+}
+
+func TestNorthwindHandler_AdminCompareTransfer_Matched(t *testing.T) {
+	rec, transfer := compareRequest(t, writeRemoteTransfer(northwind.TransferResponse{Status: "PENDING", Amount: 100.50, Currency: "USD"}))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body compareBody
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.Equal(t, transfer.ID, body.Data.Local.ID)
+	require.NotNil(t, body.Data.Remote)
+	assert.False(t, body.Data.RemoteMissing)
+	assert.Equal(t, 0, body.Data.Mismatches)
+	assert.NotEmpty(t, body.Data.Fields)
+	for _, f := range body.Data.Fields {
+		assert.True(t, f.Match, f.Field)
+	}
+}
+
+func TestNorthwindHandler_AdminCompareTransfer_Mismatched(t *testing.T) {
+	fee := 2.0
+	rec, _ := compareRequest(t, writeRemoteTransfer(northwind.TransferResponse{
+		Status: "COMPLETED", Amount: 100.50, Currency: "USD", Fee: &fee, CompletedDate: "2026-03-02T15:04:05Z",
+	}))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body compareBody
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.Equal(t, 3, body.Data.Mismatches)
+	mismatched := map[string]bool{}
+	for _, f := range body.Data.Fields {
+		if !f.Match {
+			mismatched[f.Field] = true
+		}
+	}
+	assert.Equal(t, map[string]bool{"status": true, "fee": true, "completed_date": true}, mismatched)
+}
+
+func TestNorthwindHandler_AdminCompareTransfer_MissingUpstream(t *testing.T) {
+	rec, transfer := compareRequest(t, func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(northwind.APIErrorResponse{Message: "transfer not found"})
+	})
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body compareBody
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.True(t, body.Data.RemoteMissing)
+	assert.Nil(t, body.Data.Remote)
+	assert.Empty(t, body.Data.Fields)
+	assert.Equal(t, transfer.ID, body.Data.Local.ID)
+}
+
+func TestNorthwindHandler_AdminCompareTransfer_UpstreamError(t *testing.T) {
+	rec, _ := compareRequest(t, func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"NORTHWIND_API_003"`)
+}
+
+func TestNorthwindHandler_AdminGetUpstreamTransfer(t *testing.T) {
+	northwindID := uuid.New()
+	tests := map[string]struct {
+		id          uuid.UUID
+		query       string
+		wantStatus  int
+		wantAdopted bool
+	}{
+		"view only":        {northwindID, "", http.StatusOK, false},
+		"adopt":            {northwindID, "?adopt=true", http.StatusOK, true},
+		"unknown upstream": {uuid.New(), "", http.StatusNotFound, false},
+		"invalid adopt":    {northwindID, "?adopt=maybe", http.StatusUnprocessableEntity, false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/external/transfers/"+northwindID.String() {
+					w.WriteHeader(http.StatusNotFound)
+					_ = json.NewEncoder(w).Encode(northwind.APIErrorResponse{Message: "transfer not found"})
+					return
+				}
+				writeRemoteTransfer(northwind.TransferResponse{
+					TransferID: northwindID.String(), Status: "PENDING", Amount: 100.50, Currency: "USD",
+					Direction: models.NWTransferDirectionOutbound, TransferType: models.NWTransferTypeACH, ReferenceNumber: "REF-UP-1",
+				})(w)
+			}))
+			t.Cleanup(server.Close)
+
+			db := testfactory.NewDB(t)
+			transferSvc := services.NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), repositories.NewNorthwindTransferRepository(db), nil, nil, slog.Default())
+			handler := NewNorthwindHandler(nil, nil, transferSvc, nil, nil, testEnv("testing"))
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/northwind/transfers/upstream/"+tt.id.String()+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("northwind_id")
+			c.SetParamValues(tt.id.String())
+			require.NoError(t, handler.AdminGetUpstreamTransfer(c))
+
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var body struct {
+				Data services.UpstreamTransfer `json:"data"`
+			}
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+			assert.Equal(t, services.TransferSourceUpstream, body.Data.Source)
+			assert.Equal(t, "REF-UP-1", body.Data.Transfer.ReferenceNumber)
+			assert.Equal(t, tt.wantAdopted, body.Data.Adopted)
+			assert.Equal(t, tt.wantAdopted, body.Data.LocalID != nil)
+		})
+	}
+}
+
+func TestNorthwindHandler_AdoptedOwnerlessTransferHiddenFromUsers(t *testing.T) {
+	northwindID := uuid.New()
+	var userCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			userCalls.Add(1)
+		}
+		writeRemoteTransfer(northwind.TransferResponse{
+			TransferID: northwindID.String(), Status: "PENDING", Amount: 100.50, Currency: "USD",
+			Direction: models.NWTransferDirectionOutbound, TransferType: models.NWTransferTypeACH, ReferenceNumber: "REF-UP-1",
+		})(w)
+	}))
+	defer server.Close()
+
+	db := testfactory.NewDB(t)
+	transferSvc := services.NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), repositories.NewNorthwindTransferRepository(db), nil, nil, slog.Default())
+	handler := NewNorthwindHandler(nil, nil, transferSvc, nil, nil, testEnv("testing"))
+	e := echo.New()
+
+	// No local transfer has the reference, so the adopted transfer has no user
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/northwind/transfers/upstream/"+northwindID.String()+"?adopt=true", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("northwind_id")
+	c.SetParamValues(northwindID.String())
+	require.NoError(t, handler.AdminGetUpstreamTransfer(c))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var adopted struct {
+		Data services.UpstreamTransfer `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&adopted))
+	require.Nil(t, adopted.Data.Transfer.UserID)
+	transferID := adopted.Data.Transfer.ID.String()
+
+	userID := uuid.New()
+	for name, tc := range map[string]struct {
+		method, path, body string
+		call               func(echo.Context) error
+	}{
+		"get":     {http.MethodGet, "", "", handler.GetTransfer},
+		"sync":    {http.MethodPost, "/sync", "", handler.SyncTransfer},
+		"wait":    {http.MethodGet, "/wait?since_version=0&timeout=1s", "", handler.WaitForTransfer},
+		"cancel":  {http.MethodPost, "/cancel", `{"reason":"mine now"}`, handler.CancelTransfer},
+		"reverse": {http.MethodPost, "/reverse", `{"reason":"mine now"}`, handler.ReverseTransfer},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "/api/v1/northwind/transfers/"+transferID+tc.path, strings.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("user_id", userID)
+			c.SetParamNames("id")
+			c.SetParamValues(transferID)
+			require.NoError(t, tc.call(c))
+			assert.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+		})
+	}
+	assert.Zero(t, userCalls.Load(), "expected no cancel or reverse sent to NorthWind")
+}
+
+// newCreateTransferStub serves the NorthWind calls CreateTransfer makes
+func newCreateTransferStub(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/external/transfers/validate":
+			_ = json.NewEncoder(w).Encode(northwind.TransferValidationResponse{Valid: true})
+		case strings.HasSuffix(r.URL.Path, "/balance"):
+			_ = json.NewEncoder(w).Encode(northwind.AccountBalance{AvailableBalance: 10000, Currency: "USD"})
+		case r.URL.Path == "/external/transfers/initiate":
+			fee := 0.5
+			_ = json.NewEncoder(w).Encode(northwind.TransferResponse{
+				TransferID:             uuid.New().String(),
+				Status:                 "PENDING",
+				Amount:                 250,
+				ExpectedCompletionDate: "2026-03-04T12:00:00Z",
+				Fee:                    &fee,
+				SourceAccount:          northwind.AccountDetails{AccountNumber: "1111111111"},
+				DestinationAccount:     northwind.AccountDetails{AccountNumber: "2222222222"},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+const createTransferBody = `{"amount":250,"currency":"USD","direction":"OUTBOUND","transfer_type":"ACH",` +
+	`"source_account":{"account_holder_name":"Source","account_number":"1111111111"},` +
+	`"destination_account":{"account_holder_name":"Destination","account_number":"2222222222"}}`
+
+// createTransferRequest runs CreateTransfer against a NorthWind stub and returns the decoded data
+// object of the response
+func createTransferRequest(t *testing.T, legacyDefault bool, shape string) (*httptest.ResponseRecorder, map[string]json.RawMessage) {
+	t.Helper()
+	server := newCreateTransferStub(t)
+	db := testfactory.NewDB(t)
+	transferSvc := services.NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), repositories.NewNorthwindTransferRepository(db), nil, nil, slog.Default())
+	handler := NewNorthwindHandler(nil, nil, transferSvc, nil, nil, testEnv("testing"))
+	handler.SetLegacyTransferResponse(legacyDefault)
+
+	e := echo.New()
+	e.Validator = validation.EchoValidator()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/northwind/transfers", strings.NewReader(createTransferBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if shape != "" {
+		req.Header.Set("X-Transfer-Response-Shape", shape)
+	}
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user_id", uuid.New())
+
+	require.NoError(t, handler.CreateTransfer(c))
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var resp struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return rec, resp.Data
+}
+
+func TestNorthwindHandler_CreateTransfer_InitiationShape(t *testing.T) {
+	for name, tc := range map[string]struct {
+		legacyDefault bool
+		shape         string
+	}{
+		"default":         {false, ""},
+		"header override": {true, "initiation"},
+		"unknown header":  {false, "v3"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rec, data := createTransferRequest(t, tc.legacyDefault, tc.shape)
+
+			assert.Empty(t, rec.Header().Get("Deprecation"))
+			assert.Contains(t, data, "transfer")
+			assert.NotContains(t, data, "northwind_response")
+			require.Contains(t, data, "initiation")
+
+			var initiation map[string]interface{}
+			require.NoError(t, json.Unmarshal(data["initiation"], &initiation))
+			assert.ElementsMatch(t, []string{"northwind_transfer_id", "status", "expected_completion_date", "fee"}, mapKeys(initiation))
+			assert.Equal(t, models.NWTransferStatusPending, initiation["status"])
+			assert.Equal(t, "2026-03-04T12:00:00Z", initiation["expected_completion_date"])
+			assert.Equal(t, "0.5", initiation["fee"])
+			assert.NotContains(t, string(data["transfer"]), "raw_response")
+		})
+	}
+}
+
+func TestNorthwindHandler_CreateTransfer_LegacyShape(t *testing.T) {
+	for name, tc := range map[string]struct {
+		legacyDefault bool
+		shape         string
+	}{
+		"header": {false, "legacy"},
+		"config": {true, ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rec, data := createTransferRequest(t, tc.legacyDefault, tc.shape)
+
+			assert.Equal(t, "true", rec.Header().Get("Deprecation"))
+			assert.Contains(t, data, "transfer")
+			assert.NotContains(t, data, "initiation")
+			require.Contains(t, data, "northwind_response")
+
+			var nwResp northwind.TransferResponse
+			require.NoError(t, json.Unmarshal(data["northwind_response"], &nwResp))
+			assert.Equal(t, "PENDING", nwResp.Status)
+			assert.Equal(t, 250.0, nwResp.Amount)
+			assert.Equal(t, "1111111111", nwResp.SourceAccount.AccountNumber)
+			assert.Equal(t, "2026-03-04T12:00:00Z", nwResp.ExpectedCompletionDate)
+		})
+	}
+}
+
+func TestNorthwindHandler_CreateTransfer_RecordsOrigin(t *testing.T) {
+	longUserAgent := "Mozilla/5.0 " + strings.Repeat("é", 300)
+	tests := map[string]struct {
+		forwardedFor string
+		userAgent    string
+		wantIP       string
+		wantAgent    string
+	}{
+		"ipv4":        {"203.0.113.7", "curl/8.4.0", "203.0.113.7", "curl/8.4.0"},
+		"ipv6":        {"2001:db8:85a3::8a2e:370:7334, 10.0.0.1", "banking-app/2.1 (iOS 17)", "2001:db8:85a3::8a2e:370:7334", "banking-app/2.1 (iOS 17)"},
+		"ipv4-mapped": {"::ffff:198.51.100.4", "curl/8.4.0", "198.51.100.4", "curl/8.4.0"},
+		"long agent":  {"203.0.113.7", longUserAgent, "203.0.113.7", longUserAgent[:512]},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			server := newCreateTransferStub(t)
+			db := testfactory.NewDB(t)
+
+			var audited *models.AuditLog
+			audit := service_mocks.NewMockAuditServiceInterface(ctrl)
+			audit.EXPECT().CreateAuditLog(gomock.Any()).DoAndReturn(func(log *models.AuditLog) error {
+				audited = log
+				return nil
+			})
+			transferSvc := services.NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), repositories.NewNorthwindTransferRepository(db), nil, nil, slog.Default())
+			transferSvc.SetAuditService(audit)
+			handler := NewNorthwindHandler(nil, nil, transferSvc, nil, nil, testEnv("testing"))
+
+			e := echo.New()
+			e.Validator = validation.EchoValidator()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/northwind/transfers", strings.NewReader(createTransferBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set(echo.HeaderXForwardedFor, tt.forwardedFor)
+			req.Header.Set("User-Agent", tt.userAgent)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("user_id", uuid.New())
+			require.NoError(t, handler.CreateTransfer(c))
+			require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+			// The user-facing response never carries the origin
+			assert.NotContains(t, rec.Body.String(), tt.wantIP)
+			assert.NotContains(t, rec.Body.String(), "origin")
+
+			var stored models.NorthwindTransfer
+			require.NoError(t, db.First(&stored).Error)
+			require.NotNil(t, stored.OriginIP)
+			require.NotNil(t, stored.OriginUserAgent)
+			assert.Equal(t, tt.wantIP, *stored.OriginIP)
+			assert.Equal(t, tt.wantAgent, *stored.OriginUserAgent)
+
+			require.NotNil(t, audited)
+			assert.Equal(t, models.AuditActionNorthwindTransferCreated, audited.Action)
+			assert.Equal(t, stored.ID.String(), audited.ResourceID)
+			assert.Equal(t, tt.wantIP, audited.IPAddress)
+			assert.Equal(t, tt.wantAgent, audited.UserAgent)
+
+			// Admins see it
+			adminReq := httptest.NewRequest(http.MethodGet, "/api/v1/admin/northwind/transfers/"+stored.ID.String(), nil)
+			adminRec := httptest.NewRecorder()
+			adminCtx := e.NewContext(adminReq, adminRec)
+			adminCtx.SetParamNames("id")
+			adminCtx.SetParamValues(stored.ID.String())
+			require.NoError(t, handler.AdminGetTransfer(adminCtx))
+			require.Equal(t, http.StatusOK, adminRec.Code, adminRec.Body.String())
+			var body struct {
+				Data struct {
+					Transfer map[string]interface{}         `json:"transfer"`
+					Origin   models.NorthwindTransferOrigin `json:"origin"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(adminRec.Body.Bytes(), &body))
+			assert.Equal(t, stored.ID.String(), body.Data.Transfer["id"])
+			require.NotNil(t, body.Data.Origin.IP)
+			assert.Equal(t, tt.wantIP, *body.Data.Origin.IP)
+			assert.Equal(t, tt.wantAgent, *body.Data.Origin.UserAgent)
+		})
+	}
+}
+
+func TestNorthwindHandler_AdminSetTransferInternalTest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	server := newCreateTransferStub(t)
+	db := testfactory.NewDB(t)
+
+	var audited []*models.AuditLog
+	audit := service_mocks.NewMockAuditServiceInterface(ctrl)
+	audit.EXPECT().CreateAuditLog(gomock.Any()).DoAndReturn(func(log *models.AuditLog) error {
+		audited = append(audited, log)
+		return nil
+	}).AnyTimes()
+	transferSvc := services.NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), repositories.NewNorthwindTransferRepository(db), nil, nil, slog.Default())
+	transferSvc.SetAuditService(audit)
+	handler := NewNorthwindHandler(nil, nil, transferSvc, nil, nil, testEnv("testing"))
+	e := echo.New()
+	e.Validator = validation.EchoValidator()
+
+	// Customers cannot flag their own transfers
+	body := strings.TrimSuffix(createTransferBody, "}") + `,"internal_test":true}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/northwind/transfers", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user_id", uuid.New())
+	require.NoError(t, handler.CreateTransfer(c))
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var stored models.NorthwindTransfer
+	require.NoError(t, db.First(&stored).Error)
+	assert.False(t, stored.InternalTest)
+
+	set := func(id, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/northwind/transfers/"+id+"/internal-test", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user_id", uuid.New())
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		require.NoError(t, handler.AdminSetTransferInternalTest(c))
+		return rec
+	}
+
+	audited = nil
+	rec = set(stored.ID.String(), `{"internal_test":true}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp struct {
+		Data struct {
+			InternalTest bool `json:"internal_test"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.True(t, resp.Data.InternalTest)
+	require.NoError(t, db.First(&stored, "id = ?", stored.ID).Error)
+	assert.True(t, stored.InternalTest)
+	require.Len(t, audited, 1)
+	assert.Equal(t, models.AuditActionNorthwindTransferInternalTestChanged, audited[0].Action)
+	assert.Equal(t, true, audited[0].Metadata["internal_test"])
+
+	// Setting the same value again is not audited
+	rec = set(stored.ID.String(), `{"internal_test":true}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, audited, 1)
+
+	assert.Equal(t, http.StatusBadRequest, set(stored.ID.String(), `{}`).Code)
+	assert.Equal(t, http.StatusBadRequest, set("not-a-uuid", `{"internal_test":false}`).Code)
+	assert.Equal(t, http.StatusNotFound, set(uuid.NewString(), `{"internal_test":false}`).Code)
+}
+
+func mapKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func TestNorthwindHandler_CreateTransfer_NormalizesReference(t *testing.T) {
+	// An empty wantStored means the normalized reference fails validation
+	tests := map[string]struct {
+		reference  string
+		wantStored string
+	}{
+		"normalized":             {"  inv 2024\t001 ", "INV-2024-001"},
+		"punctuation rejected":   {"ref 001/a", ""},
+		"too short once trimmed": {" ref1 ", ""},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := newCreateTransferStub(t)
+			db := testfactory.NewDB(t)
+			transferSvc := services.NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), repositories.NewNorthwindTransferRepository(db), nil, nil, slog.Default())
+			handler := NewNorthwindHandler(nil, nil, transferSvc, nil, nil, testEnv("testing"))
+
+			body, err := json.Marshal(map[string]interface{}{
+				"amount": 250, "currency": "USD", "direction": "OUTBOUND", "transfer_type": "ACH",
+				"reference_number":    tt.reference,
+				"source_account":      map[string]string{"account_holder_name": "Source", "account_number": "1111111111"},
+				"destination_account": map[string]string{"account_holder_name": "Destination", "account_number": "2222222222"},
+			})
+			require.NoError(t, err)
+
+			e := echo.New()
+			e.Validator = validation.EchoValidator()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/northwind/transfers", strings.NewReader(string(body)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("user_id", uuid.New())
+			err = handler.CreateTransfer(c)
+			if tt.wantStored == "" {
+				require.Error(t, err, "expected the reference to fail validation")
+				assert.Contains(t, err.Error(), "reference_number")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+			assert.Contains(t, rec.Body.String(), `"reference_number":"`+tt.wantStored+`"`)
+
+			var stored models.NorthwindTransfer
+			require.NoError(t, db.First(&stored).Error)
+			assert.Equal(t, tt.wantStored, stored.ReferenceNumber)
+		})
+	}
+}
+
+func TestNorthwindHandler_CreateTransfer_SanitizesText(t *testing.T) {
+	tests := map[string]struct {
+		description string
+		holderName  string
+		wantStatus  int
+		wantCode    string
+		wantDetail  string
+		wantStored  string
+	}{
+		"control characters stripped": {"Rent\x00 for\r\n\tMarch", "Destination", http.StatusCreated, "", "", "Rent for March"},
+		"emoji in holder name":        {"Rent", "Bob 🏦 Smith", http.StatusBadRequest, "VALIDATION_003", `'🏦' (U+1F3E6)`, ""},
+		"description too long":        {strings.Repeat("x", services.MaxNWDescriptionLength+1), "Destination", http.StatusBadRequest, "VALIDATION_004", "description must be at most 140 characters", ""},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := newCreateTransferStub(t)
+			db := testfactory.NewDB(t)
+			transferSvc := services.NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), repositories.NewNorthwindTransferRepository(db), nil, nil, slog.Default())
+			handler := NewNorthwindHandler(nil, nil, transferSvc, nil, nil, testEnv("testing"))
+
+			body, err := json.Marshal(map[string]interface{}{
+				"amount": 250, "currency": "USD", "direction": "OUTBOUND", "transfer_type": "ACH",
+				"description":         tt.description,
+				"source_account":      map[string]string{"account_holder_name": "Source", "account_number": "1111111111"},
+				"destination_account": map[string]string{"account_holder_name": tt.holderName, "account_number": "2222222222"},
+			})
+			require.NoError(t, err)
+
+			e := echo.New()
+			e.Validator = validation.EchoValidator()
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/northwind/transfers", strings.NewReader(string(body)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("user_id", uuid.New())
+			require.NoError(t, handler.CreateTransfer(c))
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+
+			if tt.wantCode != "" {
+				var resp struct {
+					Error struct {
+						Code    string   `json:"code"`
+						Details []string `json:"details"`
+					} `json:"error"`
+				}
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.Equal(t, tt.wantCode, resp.Error.Code)
+				assert.Contains(t, resp.Error.Details, tt.wantDetail)
+				return
+			}
+			var stored models.NorthwindTransfer
+			require.NoError(t, db.First(&stored).Error)
+			require.NotNil(t, stored.Description)
+			assert.Equal(t, tt.wantStored, *stored.Description)
+		})
+	}
+}
+
+func TestNorthwindHandler_GetMetadata_MatchesValidators(t *testing.T) {
+	handler := NewNorthwindHandler(nil, nil, nil, nil, nil, testEnv("testing"))
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/v1/northwind/metadata", nil), rec)
+	require.NoError(t, handler.GetMetadata(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp struct {
+		Data models.NorthwindTransferMetadata `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	values := func(enum []models.NorthwindEnumValue) []string {
+		var out []string
+		for _, v := range enum {
+			assert.NotEmpty(t, v.Label, v.Value)
+			out = append(out, v.Value)
+		}
+		return out
+	}
+	var statuses []string
+	for _, s := range resp.Data.Statuses {
+		assert.NotEmpty(t, s.Label, s.Value)
+		statuses = append(statuses, s.Value)
+		transfer := models.NorthwindTransfer{Status: s.Value}
+		assert.Equal(t, s.Terminal, transfer.IsTerminal(), s.Value)
+		assert.Equal(t, s.Cancellable, transfer.IsCancellable(), s.Value)
+	}
+
+	// Every listed value passes the request validators and nothing else does
+	validate := validation.NewValidator().GetValidate()
+	for tag, listed := range map[string][]string{
+		"nw_transfer_direction": values(resp.Data.Directions),
+		"nw_transfer_type":      values(resp.Data.TransferTypes),
+		"nw_transfer_priority":  values(resp.Data.Priorities),
+	} {
+		require.NotEmpty(t, listed)
+		for _, v := range listed {
+			assert.NoError(t, validate.Var(v, tag), "%s rejects listed value %s", tag, v)
+		}
+		for _, v := range []string{"", "ach", "inbound", "SEPA", "UNKNOWN"} {
+			assert.Error(t, validate.Var(v, tag), "%s accepts unlisted value %q", tag, v)
+		}
+	}
+
+	// The list filters accept exactly the listed values too
+	assert.ElementsMatch(t, models.NWTransferStatusValues(), statuses)
+	assert.ElementsMatch(t, models.NWTransferDirectionValues(), values(resp.Data.Directions))
+	assert.ElementsMatch(t, models.NWTransferTypeValues(), values(resp.Data.TransferTypes))
+	for _, query := range []string{"status=ARCHIVED", "direction=SIDEWAYS", "transfer_type=SEPA"} {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/v1/northwind/transfers?"+query, nil), rec)
+		c.Set("user_id", uuid.New())
+		require.NoError(t, handler.ListTransfers(c))
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code, query)
+	}
+}
+
+func TestNorthwindHandler_AdminPollingProfiles(t *testing.T) {
+	handler := NewNorthwindHandler(nil, nil, nil, nil, nil, testEnv("testing"))
+	handler.SetPollSchedule(services.NewNorthwindPollSchedule(map[string]config.PollingProfile{
+		models.NWTransferTypeRTP: {InitialDelay: 5 * time.Second, MinInterval: 5 * time.Second, MaxInterval: 30 * time.Second},
+	}))
+	e := echo.New()
+	e.Validator = validation.EchoValidator()
+
+	call := func(method, transferType, body string, h echo.HandlerFunc) (*httptest.ResponseRecorder, []services.PollingProfileStatus) {
+		req := httptest.NewRequest(method, "/api/v1/admin/northwind/polling-profiles/"+transferType, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("type")
+		c.SetParamValues(transferType)
+		require.NoError(t, h(c))
+		var resp struct {
+			Data []services.PollingProfileStatus `json:"data"`
+		}
+		_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+		return rec, resp.Data
+	}
+	profileOf := func(profiles []services.PollingProfileStatus, transferType string) services.PollingProfileStatus {
+		for _, p := range profiles {
+			if p.TransferType == transferType {
+				return p
+			}
+		}
+		t.Fatalf("no profile for %s in %v", transferType, profiles)
+		return services.PollingProfileStatus{}
+	}
+
+	rec, profiles := call(http.MethodGet, "", "", handler.AdminListPollingProfiles)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, profiles, len(models.NWTransferTypes))
+	assert.Equal(t, services.PollingProfileStatus{TransferType: "RTP", InitialDelay: "5s", MinInterval: "5s", MaxInterval: "30s"}, profileOf(profiles, "RTP"))
+
+	rec, profiles = call(http.MethodPut, "RTP", `{"initial_delay":"1s","min_interval":"2s","max_interval":"1m"}`, handler.AdminSetPollingProfile)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, services.PollingProfileStatus{TransferType: "RTP", InitialDelay: "1s", MinInterval: "2s", MaxInterval: "1m0s", Overridden: true}, profileOf(profiles, "RTP"))
+
+	rec, _ = call(http.MethodPut, "RTP", `{"initial_delay":"soon","min_interval":"2s","max_interval":"1m"}`, handler.AdminSetPollingProfile)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "initial_delay")
+	rec, _ = call(http.MethodPut, "RTP", `{"initial_delay":"1s","min_interval":"2m","max_interval":"1m"}`, handler.AdminSetPollingProfile)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec, _ = call(http.MethodPut, "SWIFT", `{"initial_delay":"1s","min_interval":"1s","max_interval":"1m"}`, handler.AdminSetPollingProfile)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec, profiles = call(http.MethodDelete, "RTP", "", handler.AdminClearPollingProfile)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, services.PollingProfileStatus{TransferType: "RTP", InitialDelay: "5s", MinInterval: "5s", MaxInterval: "30s"}, profileOf(profiles, "RTP"))
+}
+
+// batchRequest posts a transfer batch for userID, with query appended to the URL
+func batchRequest(t *testing.T, handler *NorthwindHandler, userID uuid.UUID, query, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	e := echo.New()
+	e.Validator = validation.EchoValidator()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/northwind/transfers/batch?"+query, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user_id", userID)
+	require.NoError(t, handler.SubmitTransferBatch(c))
+	return rec
+}
+
+func TestNorthwindHandler_SubmitTransferBatch(t *testing.T) {
+	rejectSecond := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body northwind.BatchTransferRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		var resp northwind.BatchTransferResponse
+		for i, transfer := range body.Transfers {
+			if transfer.ReferenceNumber == "REF-002" && rejectSecond {
+				resp.Errors = append(resp.Errors, northwind.BatchTransferItemError{Index: i, ErrorCode: "LIMIT_EXCEEDED", ErrorMessage: "daily limit exceeded"})
+				continue
+			}
+			resp.Transfers = append(resp.Transfers, northwind.TransferResponse{TransferID: uuid.New().String(), ReferenceNumber: transfer.ReferenceNumber, Status: "PENDING"})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	db := testfactory.NewDB(t)
+	transferSvc := services.NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), repositories.NewNorthwindTransferRepository(db), nil, nil, slog.Default())
+	handler := NewNorthwindHandler(nil, nil, transferSvc, nil, nil, testEnv("testing"))
+	userID := uuid.New()
+
+	item := func(ref string) string {
+		return `{"amount":250,"currency":"USD","direction":"OUTBOUND","transfer_type":"ACH","reference_number":"` + ref + `",` +
+			`"source_account":{"account_holder_name":"Source","account_number":"1111111111"},` +
+			`"destination_account":{"account_holder_name":"Destination","account_number":"2222222222"}}`
+	}
+	body := `{"batch_name":"march","transfers":[` + item("REF-001") + `,` + item("REF-002") + `]}`
+
+	var resp struct {
+		Data services.BatchTransferResult `json:"data"`
+	}
+	rec := batchRequest(t, handler, userID, "", body)
+	require.Equal(t, http.StatusMultiStatus, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp.Data.Items, 2)
+	assert.Equal(t, services.BatchItemOutcomeCreated, resp.Data.Items[0].Outcome)
+	assert.Equal(t, services.BatchItemOutcomeFailed, resp.Data.Items[1].Outcome)
+	assert.Equal(t, "LIMIT_EXCEEDED", resp.Data.Items[1].ErrorCode)
+	assert.Equal(t, models.NWTransferStatusFailed, resp.Data.Items[1].Transfer.Status)
+
+	rec = batchRequest(t, handler, userID, "", body)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "NORTHWIND_TRANSFER_012")
+
+	rejectSecond = false
+	rec = batchRequest(t, handler, userID, "retry_failed=true", `{"batch_name":"march"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	resp.Data = services.BatchTransferResult{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp.Data.Items, 1)
+	assert.Equal(t, 1, resp.Data.Items[0].Index)
+	assert.Equal(t, services.BatchItemOutcomeCreated, resp.Data.Items[0].Outcome)
+
+	rec = batchRequest(t, handler, userID, "retry_failed=true", `{"batch_name":"april"}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), "NORTHWIND_TRANSFER_013")
+
+	rec = batchRequest(t, handler, userID, "retry_failed=true", body)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = batchRequest(t, handler, userID, "retry_failed=maybe", `{"batch_name":"march"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	// Without a batch_name the generated one is returned, and is what a retry names
+	rejectSecond = true
+	unnamedUser := uuid.New()
+	rec = batchRequest(t, handler, unnamedUser, "", `{"transfers":[`+item("REF-001")+`,`+item("REF-002")+`]}`)
+	require.Equal(t, http.StatusMultiStatus, rec.Code, rec.Body.String())
+	resp.Data = services.BatchTransferResult{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.True(t, strings.HasPrefix(resp.Data.BatchName, "batch-"), resp.Data.BatchName)
+	assert.Equal(t, 1, resp.Data.FailedCount)
+
+	rejectSecond = false
+	rec = batchRequest(t, handler, unnamedUser, "retry_failed=true", `{"batch_name":"`+resp.Data.BatchName+`"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	resp.Data = services.BatchTransferResult{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp.Data.Items, 1)
+	assert.Equal(t, 1, resp.Data.Items[0].Index)
+	assert.Equal(t, services.BatchItemOutcomeCreated, resp.Data.Items[0].Outcome)
+
+	// An invalid transfer fails on its own and the rest of the batch goes ahead
+	invalidUser := uuid.New()
+	invalid := strings.Replace(item("REF-003"), `"amount":250,`, "", 1)
+	rec = batchRequest(t, handler, invalidUser, "", `{"batch_name":"may","transfers":[`+invalid+`,`+item("REF-001")+`,`+item("REF-001")+`]}`)
+	require.Equal(t, http.StatusMultiStatus, rec.Code, rec.Body.String())
+	resp.Data = services.BatchTransferResult{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp.Data.Items, 3)
+	assert.Equal(t, 3, resp.Data.TotalCount)
+	assert.Equal(t, 1, resp.Data.SuccessCount)
+	assert.Equal(t, services.BatchItemOutcomeFailed, resp.Data.Items[0].Outcome)
+	assert.Equal(t, "VALIDATION_001", resp.Data.Items[0].ErrorCode)
+	assert.Contains(t, resp.Data.Items[0].ErrorMessage, "amount")
+	assert.Nil(t, resp.Data.Items[0].Transfer)
+	assert.Equal(t, services.BatchItemOutcomeCreated, resp.Data.Items[1].Outcome)
+	assert.Equal(t, "NORTHWIND_TRANSFER_009", resp.Data.Items[2].ErrorCode)
+
+	// Every transfer created: 201
+	rec = batchRequest(t, handler, invalidUser, "", `{"batch_name":"june","transfers":[`+item("REF-004")+`]}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+}
+
+func TestNorthwindHandler_Maintenance(t *testing.T) {
+	server := newCreateTransferStub(t)
+	db := testfactory.NewDB(t)
+	transferSvc := services.NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), repositories.NewNorthwindTransferRepository(db), nil, nil, slog.Default())
+	maintenance := services.NewNorthwindMaintenance(nil)
+	transferSvc.SetMaintenance(maintenance)
+	handler := NewNorthwindHandler(nil, nil, transferSvc, nil, nil, testEnv("testing"))
+	handler.SetMaintenance(maintenance)
+	e := echo.New()
+	e.Validator = validation.EchoValidator()
+
+	call := func(method, path, body string, h echo.HandlerFunc) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user_id", uuid.New())
+		require.NoError(t, h(c))
+		return rec
+	}
+
+	start := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	end := start.Add(2 * time.Hour)
+	window := `{"start":"` + start.Format(time.RFC3339) + `","end":"` + end.Format(time.RFC3339) + `"}`
+	rec := call(http.MethodPut, "/api/v1/admin/northwind/maintenance", window, handler.AdminSetMaintenance)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"active":true`)
+
+	rec = call(http.MethodPut, "/api/v1/admin/northwind/maintenance", `{"start":"`+end.Format(time.RFC3339)+`","end":"`+start.Format(time.RFC3339)+`"}`, handler.AdminSetMaintenance)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// Transfers created during the window are accepted and queued
+	rec = call(http.MethodPost, "/api/v1/northwind/transfers", createTransferBody, handler.CreateTransfer)
+	require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+	var resp struct {
+		Data    map[string]json.RawMessage `json:"data"`
+		Message string                     `json:"message"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Contains(t, resp.Message, end.Format(time.RFC3339))
+	assert.Contains(t, resp.Data, "queued")
+	assert.NotContains(t, resp.Data, "initiation")
+	assert.Contains(t, string(resp.Data["transfer"]), models.NWTransferStatusInitiationPending)
+
+	rec = call(http.MethodDelete, "/api/v1/admin/northwind/maintenance", "", handler.AdminClearMaintenance)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"window":null`)
+
+	rec = call(http.MethodPost, "/api/v1/northwind/transfers", createTransferBody, handler.CreateTransfer)
+	assert.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+}
+
+func TestNorthwindHandler_NorthwindHealth_ReportsAPIKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer new-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(northwind.HealthResponse{Status: "healthy"})
+	}))
+	defer server.Close()
+
+	client := northwind.NewClient(server.URL, "old-key", northwind.WithFallbackAPIKey("new-key"))
+	handler := NewNorthwindHandler(client, nil, nil, nil, nil, testEnv("testing"))
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/v1/northwind/health", nil), rec)
+	require.NoError(t, handler.NorthwindHealth(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body struct {
+		Data struct {
+			Status string `json:"status"`
+			APIKey string `json:"api_key"`
+		} `json:"data"`
+		Message string `json:"message"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "healthy", body.Data.Status)
+	assert.Equal(t, string(northwind.APIKeySecondary), body.Data.APIKey)
+	assert.Contains(t, body.Message, "secondary API key")
+}
+
+func TestNorthwindHandler_ValidateAndRegister_HolderNameMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(northwind.AccountValidationResponse{Valid: true, AccountHolderName: "Robert Jones"})
+	}))
+	defer server.Close()
+	db := testfactory.NewDB(t)
+	accountSvc := services.NewNorthwindAccountService(northwind.NewClient(server.URL, "test-key"), repositories.NewNorthwindExternalAccountRepository(db), slog.Default())
+	handler := NewNorthwindHandler(nil, accountSvc, nil, nil, nil, testEnv("testing"))
+	e := echo.New()
+	e.Validator = validation.EchoValidator()
+
+	register := func(override, admin bool) *httptest.ResponseRecorder {
+		body := `{"account_holder_name":"J. Smith","account_number":"1234567890","routing_number":"021000021"}`
+		if override {
+			body = strings.Replace(body, "}", `,"override_name_mismatch":true}`, 1)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/northwind/external-accounts/validate-and-register", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user_id", uuid.New())
+		c.Set("is_admin", admin)
+		require.NoError(t, handler.ValidateAndRegister(c))
+		return rec
+	}
+
+	rec := register(false, false)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), "NORTHWIND_ACCOUNT_004")
+	assert.NotContains(t, rec.Body.String(), "Robert Jones")
+
+	rec = register(true, false)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = register(true, true)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"needs_review":true`)
+}
+
+func newImportRequest(t *testing.T, field, content string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	part, err := w.CreateFormFile(field, "vendors.csv")
+	require.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/northwind/accounts/import", &body)
+	req.Header.Set(echo.HeaderContentType, w.FormDataContentType())
+	return req
+}
+
+func TestNorthwindHandler_ImportExternalAccounts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req northwind.AccountValidationRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(northwind.AccountValidationResponse{Valid: true, AccountNumber: req.AccountNumber, RoutingNumber: req.RoutingNumber})
+	}))
+	defer server.Close()
+	accountSvc := services.NewNorthwindAccountService(northwind.NewClient(server.URL, "test-key"),
+		repositories.NewNorthwindExternalAccountRepository(testfactory.NewDB(t)), slog.Default())
+	handler := NewNorthwindHandler(nil, accountSvc, nil, nil, nil, testEnv("testing"))
+
+	tests := map[string]struct {
+		field    string
+		content  string
+		wantCode int
+		wantBody string
+	}{
+		"imported":       {"file", "account_holder_name,account_number,routing_number,nickname\nGlobex,1111111111,021000021,Office", http.StatusOK, `"registered":1`},
+		"malformed csv":  {"file", "account_holder_name,account_number\nGlobex,1111111111", http.StatusBadRequest, "missing column routing_number"},
+		"missing upload": {"upload", "account_holder_name,account_number,routing_number", http.StatusBadRequest, "file is required"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			e := echo.New()
+			rec := httptest.NewRecorder()
+			c := e.NewContext(newImportRequest(t, tt.field, tt.content), rec)
+			c.Set("user_id", uuid.New())
+
+			require.NoError(t, handler.ImportExternalAccounts(c))
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantBody)
+		})
+	}
+}
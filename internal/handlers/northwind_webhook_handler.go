@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	appErrors "github.com/array/banking-api/internal/errors"
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/services"
+	"github.com/labstack/echo/v4"
+)
+
+// maxWebhookBodyBytes bounds how much of a webhook delivery is read before verifying it
+const maxWebhookBodyBytes = 1 << 20
+
+// NorthwindWebhookHandler receives transfer status webhooks from NorthWind. Deliveries are
+// authenticated by an HMAC signature instead of a user token, and status changes go through the
+// same TransferStateManager as the poller, so a transition both report is only applied once.
+type NorthwindWebhookHandler struct {
+	states *services.TransferStateManager
+	events repositories.ProcessedWebhookEventRepositoryInterface
+	secret []byte
+	logger *slog.Logger
+}
+
+// NewNorthwindWebhookHandler creates a new NorthWind webhook handler verifying deliveries with secret
+func NewNorthwindWebhookHandler(states *services.TransferStateManager, secret string, logger *slog.Logger) *NorthwindWebhookHandler {
+	return &NorthwindWebhookHandler{
+		states: states,
+		secret: []byte(secret),
+		logger: logger,
+	}
+}
+
+// SetEventStore sets where accepted event IDs are recorded, so a redelivered event is acknowledged
+// without being processed again. Without one only the transfer's status guards against replays.
+func (h *NorthwindWebhookHandler) SetEventStore(events repositories.ProcessedWebhookEventRepositoryInterface) {
+	h.events = events
+}
+
+// ReceiveTransferWebhook applies a NorthWind transfer status webhook. An event ID already in the
+// event store is acknowledged as a replay without reprocessing, redeliveries of a status the
+// transfer already has are acknowledged without changing anything, and event types other than
+// transfer status changes are acknowledged and ignored so NorthWind does not retry them.
+func (h *NorthwindWebhookHandler) ReceiveTransferWebhook(c echo.Context) error {
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxWebhookBodyBytes))
+	if err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Failed to read request body"))
+	}
+	if !northwind.VerifyWebhookSignature(h.secret, body, c.Request().Header.Get(northwind.WebhookSignatureHeader)) {
+		return SendError(c, appErrors.NorthwindWebhookInvalidSignature)
+	}
+
+	var event northwind.WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return SendError(c, appErrors.ValidationInvalidFormat, appErrors.WithDetails("Invalid webhook payload"))
+	}
+
+	ctx := c.Request().Context()
+	replay, err := h.recordEvent(ctx, event.EventID)
+	if err != nil {
+		return SendSystemError(c, err)
+	}
+	if replay {
+		h.logger.Info("Acknowledging replayed NorthWind webhook event", "event_id", event.EventID, "event_type", event.EventType)
+		return c.JSON(http.StatusOK, SuccessResponse{
+			Data:    map[string]interface{}{"event_id": event.EventID, "replay": true},
+			Message: localize(c, "northwind.webhook_replayed"),
+		})
+	}
+
+	if event.EventType != northwind.WebhookEventTransferStatusChanged {
+		h.logger.Info("Ignoring NorthWind webhook event", "event_id", event.EventID, "event_type", event.EventType)
+		h.setOutcome(ctx, event.EventID, models.WebhookEventOutcomeIgnored)
+		return c.JSON(http.StatusOK, SuccessResponse{Message: localize(c, "northwind.webhook_ignored")})
+	}
+	if event.Data.TransferID == "" || event.Data.Status == "" {
+		h.forgetEvent(ctx, event.EventID)
+		return SendError(c, appErrors.ValidationRequiredField, appErrors.WithDetails("data.transfer_id and data.status are required"))
+	}
+
+	result, err := h.states.ApplyRemote(ctx, models.NWTransferEventSourceWebhook, &event.Data)
+	if err != nil {
+		h.forgetEvent(ctx, event.EventID)
+		if errors.Is(err, services.ErrNWTransferNotFound) {
+			return SendError(c, appErrors.NorthwindTransferNotFound)
+		}
+		return SendSystemError(c, err)
+	}
+	outcome := models.WebhookEventOutcomeUnchanged
+	if result.Applied() {
+		outcome = models.WebhookEventOutcomeApplied
+	}
+	h.setOutcome(ctx, event.EventID, outcome)
+
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data: map[string]interface{}{
+			"event_id":    event.EventID,
+			"transfer_id": result.Transfer.ID,
+			"status":      result.Transfer.Status,
+			"applied":     result.Applied(),
+		},
+		Message: localize(c, "northwind.webhook_processed"),
+	})
+}
+
+// recordEvent records the event ID before the event is processed and reports whether it had been
+// recorded already. Events without an ID, or without an event store, are never replays.
+func (h *NorthwindWebhookHandler) recordEvent(ctx context.Context, eventID string) (bool, error) {
+	if h.events == nil || eventID == "" {
+		return false, nil
+	}
+	err := h.events.Record(ctx, &models.ProcessedWebhookEvent{EventID: eventID, Source: models.WebhookSourceNorthwind})
+	if errors.Is(err, repositories.ErrWebhookEventAlreadyProcessed) {
+		return true, nil
+	}
+	return false, err
+}
+
+// setOutcome records how a recorded event was processed. A failure only loses the outcome; the
+// event stays recorded, so it is logged rather than returned.
+func (h *NorthwindWebhookHandler) setOutcome(ctx context.Context, eventID, outcome string) {
+	if h.events == nil || eventID == "" {
+		return
+	}
+	if err := h.events.SetOutcome(ctx, eventID, outcome); err != nil {
+		h.logger.Error("Failed to record NorthWind webhook event outcome", "event_id", eventID, "outcome", outcome, "error", err)
+	}
+}
+
+// forgetEvent removes a recorded event that could not be processed, so NorthWind's retry of it is
+// processed rather than acknowledged as a replay
+func (h *NorthwindWebhookHandler) forgetEvent(ctx context.Context, eventID string) {
+	if h.events == nil || eventID == "" {
+		return
+	}
+	if err := h.events.Delete(ctx, eventID); err != nil {
+		h.logger.Error("Failed to forget unprocessed NorthWind webhook event", "event_id", eventID, "error", err)
+	}
+}
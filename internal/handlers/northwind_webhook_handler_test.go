@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/services"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+const testWebhookSecret = "test-webhook-secret"
+
+type webhookTestEnv struct {
+	db             *gorm.DB
+	transferRepo   repositories.NorthwindTransferRepositoryInterface
+	regulatorSvc   *services.RegulatorService
+	regulatorCalls *atomic.Int32
+	states         *services.TransferStateManager
+	handler        *NorthwindWebhookHandler
+}
+
+func newWebhookTestEnv(t *testing.T) *webhookTestEnv {
+	t.Helper()
+	db := testfactory.NewDB(t)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	var regulatorCalls atomic.Int32
+	regulatorServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		regulatorCalls.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(regulatorServer.Close)
+
+	transferRepo := repositories.NewNorthwindTransferRepository(db)
+	regulatorSvc := services.NewRegulatorService(regulatorServer.URL, 2, 60,
+		repositories.NewRegulatorNotificationRepository(db),
+		repositories.NewRegulatorNotificationAttemptRepository(db),
+		slog.Default(), regulatorServer.Client())
+	regulatorSvc.StartDeliveryWorkers(1, 10)
+	t.Cleanup(func() { regulatorSvc.Shutdown(context.Background()) })
+
+	states := services.NewTransferStateManager(transferRepo, regulatorSvc, slog.Default())
+	handler := NewNorthwindWebhookHandler(states, testWebhookSecret, slog.Default())
+	handler.SetEventStore(repositories.NewProcessedWebhookEventRepository(db))
+	return &webhookTestEnv{
+		db:             db,
+		transferRepo:   transferRepo,
+		regulatorSvc:   regulatorSvc,
+		regulatorCalls: &regulatorCalls,
+		states:         states,
+		handler:        handler,
+	}
+}
+
+// deliver posts a webhook body with the given signature header and returns the recorded response
+func (env *webhookTestEnv) deliver(t *testing.T, body []byte, signature string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/northwind/webhooks/transfers", strings.NewReader(string(body)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if signature != "" {
+		req.Header.Set(northwind.WebhookSignatureHeader, signature)
+	}
+	rec := httptest.NewRecorder()
+	require.NoError(t, env.handler.ReceiveTransferWebhook(echo.New().NewContext(req, rec)))
+	return rec
+}
+
+func statusWebhook(t *testing.T, transferID, status string) []byte {
+	t.Helper()
+	body, err := json.Marshal(northwind.WebhookEvent{
+		EventID:   "evt-" + status,
+		EventType: northwind.WebhookEventTransferStatusChanged,
+		Data:      northwind.TransferResponse{TransferID: transferID, Status: status},
+	})
+	require.NoError(t, err)
+	return body
+}
+
+func signed(body []byte) string {
+	return "sha256=" + northwind.SignWebhook([]byte(testWebhookSecret), body)
+}
+
+func TestNorthwindWebhookHandler_RejectsBadSignatures(t *testing.T) {
+	env := newWebhookTestEnv(t)
+	transfer := testfactory.NWTransfer(t, env.db)
+	body := statusWebhook(t, *transfer.ExternalRef, "COMPLETED")
+
+	for name, signature := range map[string]string{
+		"missing":    "",
+		"wrong key":  "sha256=" + northwind.SignWebhook([]byte("other-secret"), body),
+		"not hex":    "sha256=zz",
+		"other body": signed([]byte(`{}`)),
+	} {
+		rec := env.deliver(t, body, signature)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, name)
+		assert.Contains(t, rec.Body.String(), "NORTHWIND_WEBHOOK_001", name)
+	}
+
+	got, err := env.transferRepo.GetByID(context.Background(), transfer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.NWTransferStatusPending, got.Status)
+}
+
+func TestNorthwindWebhookHandler_AppliesStatusChangeOnce(t *testing.T) {
+	env := newWebhookTestEnv(t)
+	transfer := testfactory.NWTransfer(t, env.db, testfactory.WithStatus(models.NWTransferStatusProcessing))
+	body := statusWebhook(t, *transfer.ExternalRef, "COMPLETED")
+
+	var resp struct {
+		Data struct {
+			Status  string `json:"status"`
+			Applied bool   `json:"applied"`
+			Replay  bool   `json:"replay"`
+		} `json:"data"`
+	}
+	rec := env.deliver(t, body, signed(body))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, models.NWTransferStatusCompleted, resp.Data.Status)
+	assert.True(t, resp.Data.Applied)
+
+	// NorthWind redelivers: acknowledged as a replay, nothing reprocessed
+	rec = env.deliver(t, body, signed(body))
+	require.Equal(t, http.StatusOK, rec.Code)
+	resp.Data.Applied = false
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.False(t, resp.Data.Applied)
+	assert.True(t, resp.Data.Replay)
+	assert.Equal(t, models.WebhookEventOutcomeApplied, webhookEventOutcome(t, env.db, "evt-COMPLETED"))
+
+	env.regulatorSvc.Shutdown(context.Background())
+	events, err := env.transferRepo.ListEvents(context.Background(), transfer.ID)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, models.NWTransferEventSourceWebhook, events[0].Source)
+	assert.Equal(t, int32(1), env.regulatorCalls.Load())
+}
+
+func TestNorthwindWebhookHandler_RejectsUnusableDeliveries(t *testing.T) {
+	env := newWebhookTestEnv(t)
+
+	unknown := statusWebhook(t, "no-such-transfer", "COMPLETED")
+	rec := env.deliver(t, unknown, signed(unknown))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), "NORTHWIND_TRANSFER_001")
+
+	malformed := []byte(`{"event_type":`)
+	rec = env.deliver(t, malformed, signed(malformed))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	incomplete := statusWebhook(t, "", "COMPLETED")
+	rec = env.deliver(t, incomplete, signed(incomplete))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	other := []byte(`{"event_id":"evt-1","event_type":"account.updated","data":{}}`)
+	rec = env.deliver(t, other, signed(other))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Webhook event ignored")
+}
+
+func TestNorthwindWebhookHandler_ConcurrentWithPollerAppliesOnce(t *testing.T) {
+	env := newWebhookTestEnv(t)
+	transfer := testfactory.NWTransfer(t, env.db, testfactory.WithStatus(models.NWTransferStatusProcessing))
+	body := statusWebhook(t, *transfer.ExternalRef, "COMPLETED")
+
+	nwServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(northwind.TransferStatusResponse{TransferID: *transfer.ExternalRef, Status: "COMPLETED"})
+	}))
+	defer nwServer.Close()
+	poller := services.NewNorthwindPollingService(northwind.NewClient(nwServer.URL, "test-key"), env.transferRepo, env.regulatorSvc, 0, slog.Default())
+	poller.SetTransferStateManager(env.states)
+
+	const rounds = 5
+	start := make(chan struct{})
+	codes := make(chan int, rounds)
+	var wg sync.WaitGroup
+	for i := 0; i < rounds; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			<-start
+			poller.PollOnce(context.Background())
+		}()
+		go func() {
+			defer wg.Done()
+			<-start
+			codes <- env.deliver(t, body, signed(body)).Code
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(codes)
+	for code := range codes {
+		assert.Equal(t, http.StatusOK, code)
+	}
+
+	env.regulatorSvc.Shutdown(context.Background())
+	events, err := env.transferRepo.ListEvents(context.Background(), transfer.ID)
+	require.NoError(t, err)
+	assert.Len(t, events, 1, "one transition, one status event")
+	var notifications int64
+	require.NoError(t, env.db.Model(&models.RegulatorNotification{}).Where("transfer_id = ?", transfer.ID).Count(&notifications).Error)
+	assert.Equal(t, int64(1), notifications)
+	assert.Equal(t, int32(1), env.regulatorCalls.Load())
+}
+
+// webhookEventOutcome returns the recorded outcome of a webhook event, or "" when it is not recorded
+func webhookEventOutcome(t *testing.T, db *gorm.DB, eventID string) string {
+	t.Helper()
+	var events []models.ProcessedWebhookEvent
+	require.NoError(t, db.Where("event_id = ?", eventID).Find(&events).Error)
+	if len(events) == 0 {
+		return ""
+	}
+	return events[0].Outcome
+}
+
+func TestNorthwindWebhookHandler_ConcurrentDuplicateDeliveriesProcessOnce(t *testing.T) {
+	env := newWebhookTestEnv(t)
+	transfer := testfactory.NWTransfer(t, env.db, testfactory.WithStatus(models.NWTransferStatusProcessing))
+	body := statusWebhook(t, *transfer.ExternalRef, "COMPLETED")
+
+	const deliveries = 10
+	start := make(chan struct{})
+	responses := make(chan *httptest.ResponseRecorder, deliveries)
+	var wg sync.WaitGroup
+	for i := 0; i < deliveries; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			responses <- env.deliver(t, body, signed(body))
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(responses)
+
+	processed, replays := 0, 0
+	for rec := range responses {
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		if strings.Contains(rec.Body.String(), "Webhook event already processed") {
+			replays++
+		} else {
+			processed++
+		}
+	}
+	assert.Equal(t, 1, processed, "exactly one delivery is processed")
+	assert.Equal(t, deliveries-1, replays)
+
+	env.regulatorSvc.Shutdown(context.Background())
+	events, err := env.transferRepo.ListEvents(context.Background(), transfer.ID)
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, int32(1), env.regulatorCalls.Load())
+}
+
+func TestNorthwindWebhookHandler_FailedEventIsProcessedOnRetry(t *testing.T) {
+	env := newWebhookTestEnv(t)
+	body, err := json.Marshal(northwind.WebhookEvent{
+		EventID:   "evt-early",
+		EventType: northwind.WebhookEventTransferStatusChanged,
+		Data:      northwind.TransferResponse{TransferID: "nw-not-yet-stored", Status: "COMPLETED"},
+	})
+	require.NoError(t, err)
+
+	// The transfer is not stored yet: the event is not kept, so NorthWind's retry is processed
+	rec := env.deliver(t, body, signed(body))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, webhookEventOutcome(t, env.db, "evt-early"))
+
+	transfer := testfactory.NWTransfer(t, env.db, testfactory.WithStatus(models.NWTransferStatusProcessing))
+	require.NoError(t, env.db.Model(transfer).Update("external_ref", "nw-not-yet-stored").Error)
+	rec = env.deliver(t, body, signed(body))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"applied":true`)
+	assert.Equal(t, models.WebhookEventOutcomeApplied, webhookEventOutcome(t, env.db, "evt-early"))
+
+	other := []byte(`{"event_id":"evt-other","event_type":"account.updated","data":{}}`)
+	env.deliver(t, other, signed(other))
+	assert.Equal(t, models.WebhookEventOutcomeIgnored, webhookEventOutcome(t, env.db, "evt-other"))
+}
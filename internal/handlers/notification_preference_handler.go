@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	appErrors "github.com/array/banking-api/internal/errors"
+	"github.com/array/banking-api/internal/services"
+	"github.com/labstack/echo/v4"
+)
+
+// NotificationPreferenceHandler lets users choose how they are notified about transfer events
+type NotificationPreferenceHandler struct {
+	preferences *services.NotificationPreferenceService
+}
+
+// NewNotificationPreferenceHandler creates a new notification preference handler
+func NewNotificationPreferenceHandler(preferences *services.NotificationPreferenceService) *NotificationPreferenceHandler {
+	return &NotificationPreferenceHandler{preferences: preferences}
+}
+
+// UpdateNotificationPreferencesRequest changes the caller's channels for one or more event types
+type UpdateNotificationPreferencesRequest struct {
+	Preferences []services.NotificationPreferenceUpdate `json:"preferences" validate:"required,min=1,dive"`
+}
+
+// GetMyPreferences returns the caller's channels for every event type and whether each is the default
+func (h *NotificationPreferenceHandler) GetMyPreferences(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return SendError(c, appErrors.AuthMissingToken)
+	}
+
+	statuses, err := h.preferences.GetPreferences(c.Request().Context(), userID)
+	if err != nil {
+		return SendSystemError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    statuses,
+		Message: "Notification preferences retrieved",
+	})
+}
+
+// UpdateMyPreferences changes the caller's channels for the listed event types
+func (h *NotificationPreferenceHandler) UpdateMyPreferences(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return SendError(c, appErrors.AuthMissingToken)
+	}
+
+	var req UpdateNotificationPreferencesRequest
+	if err := c.Bind(&req); err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid request body"))
+	}
+	if err := validateRequest(c, req); err != nil {
+		return err
+	}
+
+	statuses, err := h.preferences.UpdatePreferences(c.Request().Context(), userID, req.Preferences)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownNotificationEvent) {
+			return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails(err.Error()))
+		}
+		return SendSystemError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    statuses,
+		Message: "Notification preferences updated",
+	})
+}
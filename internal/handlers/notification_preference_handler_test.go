@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/repositories/repository_mocks"
+	"github.com/array/banking-api/internal/services"
+	"github.com/array/banking-api/internal/validation"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newNotificationPreferenceHandlerTest(t *testing.T) (*NotificationPreferenceHandler, *repository_mocks.MockNotificationPreferenceRepositoryInterface) {
+	t.Helper()
+	repo := repository_mocks.NewMockNotificationPreferenceRepositoryInterface(gomock.NewController(t))
+	return NewNotificationPreferenceHandler(services.NewNotificationPreferenceService(repo)), repo
+}
+
+func notificationPreferenceContext(method, body string, userID uuid.UUID) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	e.Validator = validation.EchoValidator()
+	req := httptest.NewRequest(method, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user_id", userID)
+	return c, rec
+}
+
+func TestNotificationPreferenceHandler_GetMyPreferences(t *testing.T) {
+	handler, repo := newNotificationPreferenceHandlerTest(t)
+	userID := uuid.New()
+	repo.EXPECT().ListByUser(gomock.Any(), userID).Return([]models.NotificationPreference{
+		{UserID: userID, EventType: services.NotificationEventTransferCompleted, InApp: true, Email: false},
+	}, nil)
+
+	c, rec := notificationPreferenceContext(http.MethodGet, "", userID)
+	require.NoError(t, handler.GetMyPreferences(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var body struct {
+		Data []services.NotificationPreferenceStatus `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	byEvent := make(map[string]services.NotificationPreferenceStatus)
+	for _, status := range body.Data {
+		byEvent[status.EventType] = status
+	}
+	completed := byEvent[services.NotificationEventTransferCompleted]
+	assert.Equal(t, services.NotificationPreferenceSourceUser, completed.Source)
+	assert.False(t, completed.Email)
+	failed := byEvent[services.NotificationEventTransferFailed]
+	assert.Equal(t, services.NotificationPreferenceSourceDefault, failed.Source)
+	assert.True(t, failed.Email)
+}
+
+func TestNotificationPreferenceHandler_UpdateMyPreferences(t *testing.T) {
+	userID := uuid.New()
+	tests := []struct {
+		name       string
+		body       string
+		expect     func(repo *repository_mocks.MockNotificationPreferenceRepositoryInterface)
+		wantStatus int
+		// wantErr is set for validation failures, which the error handler middleware renders
+		wantErr bool
+	}{
+		{
+			name: "updates email for failures",
+			body: `{"preferences":[{"event_type":"TRANSFER_FAILED","email":false}]}`,
+			expect: func(repo *repository_mocks.MockNotificationPreferenceRepositoryInterface) {
+				repo.EXPECT().Get(gomock.Any(), userID, services.NotificationEventTransferFailed).Return(nil, repositories.ErrNotificationPreferenceNotFound)
+				repo.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(func(_ interface{}, p *models.NotificationPreference) error {
+					assert.True(t, p.InApp)
+					assert.False(t, p.Email)
+					return nil
+				})
+				repo.EXPECT().ListByUser(gomock.Any(), userID).Return(nil, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "unknown event type",
+			body:       `{"preferences":[{"event_type":"NOT_AN_EVENT","email":true}]}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "no preferences",
+			body:    `{"preferences":[]}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, repo := newNotificationPreferenceHandlerTest(t)
+			if tt.expect != nil {
+				tt.expect(repo)
+			}
+			c, rec := notificationPreferenceContext(http.MethodPut, tt.body, userID)
+			err := handler.UpdateMyPreferences(c)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	appErrors "github.com/array/banking-api/internal/errors"
+	"github.com/array/banking-api/internal/services"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// PersonalAccessTokenHandler lets users manage the API tokens they use instead of a JWT login
+type PersonalAccessTokenHandler struct {
+	tokens *services.PersonalAccessTokenService
+}
+
+// NewPersonalAccessTokenHandler creates a new personal access token handler
+func NewPersonalAccessTokenHandler(tokens *services.PersonalAccessTokenService) *PersonalAccessTokenHandler {
+	return &PersonalAccessTokenHandler{tokens: tokens}
+}
+
+// CreateToken creates a token for the caller. The response is the only time its secret is shown.
+func (h *PersonalAccessTokenHandler) CreateToken(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return SendError(c, appErrors.AuthMissingToken)
+	}
+
+	var req services.CreatePersonalAccessTokenRequest
+	if err := c.Bind(&req); err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid request body"))
+	}
+	if err := validateRequest(c, req); err != nil {
+		return err
+	}
+
+	token, err := h.tokens.CreateToken(c.Request().Context(), userID, req)
+	if err != nil {
+		return SendSystemError(c, err)
+	}
+	return c.JSON(http.StatusCreated, SuccessResponse{
+		Data:    token,
+		Message: "Access token created; store the token now, it will not be shown again",
+	})
+}
+
+// ListTokens lists the caller's tokens, without their secrets
+func (h *PersonalAccessTokenHandler) ListTokens(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return SendError(c, appErrors.AuthMissingToken)
+	}
+
+	q := newQueryParams(c)
+	offset := q.Offset()
+	limit := q.Limit()
+	if !q.Valid() {
+		return q.SendError()
+	}
+
+	tokens, total, err := h.tokens.ListTokens(c.Request().Context(), userID, offset, limit)
+	if err != nil {
+		return SendSystemError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    tokens,
+		Message: "Access tokens retrieved",
+		Meta: map[string]interface{}{
+			"total":  total,
+			"offset": offset,
+			"limit":  limit,
+		},
+	})
+}
+
+// RevokeToken revokes one of the caller's tokens; requests made with it are rejected from then on
+func (h *PersonalAccessTokenHandler) RevokeToken(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return SendError(c, appErrors.AuthMissingToken)
+	}
+	tokenID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid access token ID"))
+	}
+
+	if err := h.tokens.RevokeToken(c.Request().Context(), userID, tokenID); err != nil {
+		if errors.Is(err, services.ErrPersonalAccessTokenNotFound) {
+			return SendError(c, appErrors.AccessTokenNotFound)
+		}
+		return SendSystemError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{Message: "Access token revoked"})
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/array/banking-api/internal/database"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/services"
+	"github.com/array/banking-api/internal/validation"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAccessTokenHandlerTest(t *testing.T) (*PersonalAccessTokenHandler, *models.User) {
+	t.Helper()
+	db := database.SetupTestDB(t)
+	require.NoError(t, db.DB.AutoMigrate(&models.PersonalAccessToken{}))
+	user := database.CreateTestUser(t, db, "tokens@example.com")
+	svc := services.NewPersonalAccessTokenService(repositories.NewPersonalAccessTokenRepository(db.DB), repositories.NewUserRepository(db.DB), slog.Default())
+	return NewPersonalAccessTokenHandler(svc), user
+}
+
+func accessTokenContext(method, body string, userID uuid.UUID, tokenID string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	e.Validator = validation.EchoValidator()
+	req := httptest.NewRequest(method, "/api/v1/users/me/tokens", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user_id", userID)
+	if tokenID != "" {
+		c.SetParamNames("id")
+		c.SetParamValues(tokenID)
+	}
+	return c, rec
+}
+
+func TestPersonalAccessTokenHandler_SecretIsOnlyShownOnCreate(t *testing.T) {
+	handler, user := newAccessTokenHandlerTest(t)
+
+	c, rec := accessTokenContext(http.MethodPost, `{"name":"erp","scopes":["transfers:read"],"expires_in_days":30}`, user.ID, "")
+	require.NoError(t, handler.CreateToken(c))
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	secret, _ := created.Data["token"].(string)
+	assert.True(t, strings.HasPrefix(secret, "pat_"), "expected the secret in the create response, got %v", created.Data)
+	assert.NotContains(t, created.Data, "token_hash")
+
+	c, rec = accessTokenContext(http.MethodGet, "", user.ID, "")
+	require.NoError(t, handler.ListTokens(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), secret)
+	var listed struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listed))
+	require.Len(t, listed.Data, 1)
+	assert.NotContains(t, listed.Data[0], "token")
+	assert.Equal(t, []interface{}{"transfers:read"}, listed.Data[0]["scopes"])
+}
+
+func TestPersonalAccessTokenHandler_CreateToken_Validation(t *testing.T) {
+	handler, user := newAccessTokenHandlerTest(t)
+	for _, body := range []string{
+		`{"scopes":["transfers:read"]}`,
+		`{"name":"erp","scopes":[]}`,
+		`{"name":"erp","scopes":["accounts:admin"]}`,
+		`{"name":"erp","scopes":["transfers:read"],"expires_in_days":400}`,
+	} {
+		c, _ := accessTokenContext(http.MethodPost, body, user.ID, "")
+		// validation failures are rendered by the error handler middleware
+		assert.Error(t, handler.CreateToken(c), body)
+	}
+}
+
+func TestPersonalAccessTokenHandler_RevokeToken(t *testing.T) {
+	handler, user := newAccessTokenHandlerTest(t)
+	c, rec := accessTokenContext(http.MethodPost, `{"name":"erp","scopes":["transfers:write"]}`, user.ID, "")
+	require.NoError(t, handler.CreateToken(c))
+	var created struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+
+	c, rec = accessTokenContext(http.MethodDelete, "", uuid.New(), created.Data.ID)
+	require.NoError(t, handler.RevokeToken(c))
+	assert.Equal(t, http.StatusNotFound, rec.Code, "another user's token is not found")
+
+	c, rec = accessTokenContext(http.MethodDelete, "", user.ID, created.Data.ID)
+	require.NoError(t, handler.RevokeToken(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	appErrors "github.com/array/banking-api/internal/errors"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// queryParams binds query parameters for list endpoints. Absent parameters take their
+// defaults; values that are present but unparsable, out of range, or not one of the known
+// values are recorded as field-level errors and reported together by SendError.
+type queryParams struct {
+	c      echo.Context
+	errors []string
+}
+
+// newQueryParams creates a query parameter binder for the request
+func newQueryParams(c echo.Context) *queryParams {
+	return &queryParams{c: c}
+}
+
+// Int returns the named parameter as an integer within [min, max], or defaultValue if absent
+func (q *queryParams) Int(name string, defaultValue, min, max int) int {
+	raw := q.c.QueryParam(name)
+	if raw == "" {
+		return defaultValue
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		q.addError(name, "must be an integer")
+		return defaultValue
+	}
+	if value < min {
+		q.addError(name, fmt.Sprintf("must be at least %d", min))
+		return defaultValue
+	}
+	if value > max {
+		q.addError(name, fmt.Sprintf("must be at most %d", max))
+		return defaultValue
+	}
+	return value
+}
+
+// Offset returns the non-negative "offset" parameter, defaulting to 0
+func (q *queryParams) Offset() int {
+	return q.Int("offset", 0, 0, math.MaxInt32)
+}
+
+// Limit returns the "limit" parameter within [1, maxPageLimit], defaulting to defaultPageLimit
+func (q *queryParams) Limit() int {
+	return q.Int("limit", defaultPageLimit, 1, maxPageLimit)
+}
+
+// Enum returns the named parameter if it is one of allowed, or "" if absent
+func (q *queryParams) Enum(name string, allowed ...string) string {
+	raw := q.c.QueryParam(name)
+	if raw == "" {
+		return ""
+	}
+
+	for _, value := range allowed {
+		if raw == value {
+			return raw
+		}
+	}
+	q.addError(name, "must be one of "+strings.Join(allowed, ", "))
+	return ""
+}
+
+// Bool returns the named parameter as a boolean, or nil if absent
+func (q *queryParams) Bool(name string) *bool {
+	raw := q.c.QueryParam(name)
+	if raw == "" {
+		return nil
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		q.addError(name, "must be true or false")
+		return nil
+	}
+	return &value
+}
+
+// UUID returns the named parameter as a UUID, or nil if absent
+func (q *queryParams) UUID(name string) *uuid.UUID {
+	raw := q.c.QueryParam(name)
+	if raw == "" {
+		return nil
+	}
+
+	value, err := uuid.Parse(raw)
+	if err != nil {
+		q.addError(name, "must be a UUID")
+		return nil
+	}
+	return &value
+}
+
+// Time returns the named parameter as an RFC 3339 timestamp, or nil if absent
+func (q *queryParams) Time(name string) *time.Time {
+	raw := q.c.QueryParam(name)
+	if raw == "" {
+		return nil
+	}
+
+	value, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		q.addError(name, "must be an RFC 3339 timestamp")
+		return nil
+	}
+	return &value
+}
+
+// Valid reports whether every parameter bound so far was accepted
+func (q *queryParams) Valid() bool {
+	return len(q.errors) == 0
+}
+
+// SendError responds with a 422 listing every rejected parameter
+func (q *queryParams) SendError() error {
+	return SendError(q.c, appErrors.ValidationInvalidQuery, appErrors.WithDetails(q.errors...))
+}
+
+func (q *queryParams) addError(name, message string) {
+	q.errors = append(q.errors, fmt.Sprintf("%s: %s", name, message))
+}
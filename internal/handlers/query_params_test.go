@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newQueryParamsFor(query string) *queryParams {
+	req := httptest.NewRequest(http.MethodGet, "/?"+query, nil)
+	return newQueryParams(echo.New().NewContext(req, httptest.NewRecorder()))
+}
+
+func TestQueryParams_Int(t *testing.T) {
+	tests := map[string]struct {
+		query string
+		want  int
+		valid bool
+	}{
+		"absent uses default": {"", 20, true},
+		"valid":               {"limit=50", 50, true},
+		"lower bound":         {"limit=1", 1, true},
+		"upper bound":         {"limit=100", 100, true},
+		"malformed":           {"limit=abc", 20, false},
+		"trailing garbage":    {"limit=10abc", 20, false},
+		"below range":         {"limit=0", 20, false},
+		"above range":         {"limit=101", 20, false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			q := newQueryParamsFor(tt.query)
+			assert.Equal(t, tt.want, q.Limit())
+			assert.Equal(t, tt.valid, q.Valid())
+		})
+	}
+}
+
+func TestQueryParams_Enum(t *testing.T) {
+	tests := map[string]struct {
+		query string
+		want  string
+		valid bool
+	}{
+		"absent":        {"", "", true},
+		"known value":   {"direction=INBOUND", "INBOUND", true},
+		"unknown value": {"direction=SIDEWAYS", "", false},
+		"wrong case":    {"direction=inbound", "", false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			q := newQueryParamsFor(tt.query)
+			assert.Equal(t, tt.want, q.Enum("direction", "INBOUND", "OUTBOUND"))
+			assert.Equal(t, tt.valid, q.Valid())
+		})
+	}
+}
+
+func TestQueryParams_SendErrorListsEveryField(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?offset=-1&limit=abc&status=NOPE", nil)
+	rec := httptest.NewRecorder()
+	q := newQueryParams(echo.New().NewContext(req, rec))
+
+	q.Offset()
+	q.Limit()
+	q.Enum("status", "PENDING")
+	assert.False(t, q.Valid())
+	assert.NoError(t, q.SendError())
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, "VALIDATION_008")
+	assert.Contains(t, body, "offset: must be at least 0")
+	assert.Contains(t, body, "limit: must be an integer")
+	assert.Contains(t, body, "status: must be one of PENDING")
+}
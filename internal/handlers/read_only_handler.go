@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	appErrors "github.com/array/banking-api/internal/errors"
+	"github.com/array/banking-api/internal/services"
+	"github.com/labstack/echo/v4"
+)
+
+// ReadOnlyHandler lets admins freeze writes during incident response and lift the freeze again
+type ReadOnlyHandler struct {
+	readOnly *services.ReadOnlyService
+}
+
+// NewReadOnlyHandler creates a new read-only mode admin handler
+func NewReadOnlyHandler(readOnly *services.ReadOnlyService) *ReadOnlyHandler {
+	return &ReadOnlyHandler{readOnly: readOnly}
+}
+
+// SetReadOnlyModeRequest turns read-only mode on or off; a reason is required to turn it on.
+// ExpectedEnd, optional, is when the mode is expected to be lifted, and tells refused clients when
+// to retry.
+type SetReadOnlyModeRequest struct {
+	Enabled     *bool      `json:"enabled"`
+	Reason      string     `json:"reason"`
+	ExpectedEnd *time.Time `json:"expected_end"`
+}
+
+// GetMode returns whether read-only mode is on, where that comes from, and which background work
+// runs while it is on
+func (h *ReadOnlyHandler) GetMode(c echo.Context) error {
+	status, err := h.readOnly.Status(c.Request().Context())
+	if err != nil {
+		return SendSystemError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    status,
+		Message: "Read-only mode retrieved",
+	})
+}
+
+// SetMode turns read-only mode on or off. The setting is persisted, so it survives restarts, and
+// every instance applies it within seconds.
+func (h *ReadOnlyHandler) SetMode(c echo.Context) error {
+	adminID, err := getUserIDFromContext(c)
+	if err != nil {
+		return SendError(c, appErrors.AuthMissingToken)
+	}
+
+	var req SetReadOnlyModeRequest
+	if err := c.Bind(&req); err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid request body"))
+	}
+	if req.Enabled == nil {
+		return SendError(c, appErrors.ValidationRequiredField, appErrors.WithDetails("enabled is required"))
+	}
+	if *req.Enabled && req.Reason == "" {
+		return SendError(c, appErrors.ValidationRequiredField, appErrors.WithDetails("reason is required to turn read-only mode on"))
+	}
+	if *req.Enabled && req.ExpectedEnd != nil && !req.ExpectedEnd.After(time.Now()) {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("expected_end must be in the future"))
+	}
+
+	ctx := c.Request().Context()
+	if err := h.readOnly.Set(ctx, *req.Enabled, req.Reason, req.ExpectedEnd, adminID); err != nil {
+		return SendSystemError(c, err)
+	}
+	status, err := h.readOnly.Status(ctx)
+	if err != nil {
+		return SendSystemError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    status,
+		Message: "Read-only mode updated",
+	})
+}
@@ -0,0 +1,318 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	appErrors "github.com/array/banking-api/internal/errors"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/services"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// Formats of the notification attempt listings
+const (
+	attemptFormatJSON = "json"
+	attemptFormatCSV  = "csv"
+)
+
+// attemptCSVFlushRows is how many CSV rows are buffered before they are flushed to the client
+const attemptCSVFlushRows = 500
+
+// attemptCSVHeader is the header row of the notification attempt CSV export
+var attemptCSVHeader = []string{
+	"attempt_id", "notification_id", "attempted_at", "outcome", "http_status", "duration_ms",
+	"target_url", "error", "request_headers_hash", "response_body",
+}
+
+// RegulatorHandler exposes regulator notification audit data to admins
+type RegulatorHandler struct {
+	notifRepo   repositories.RegulatorNotificationRepositoryInterface
+	attemptRepo repositories.RegulatorNotificationAttemptRepositoryInterface
+	evidence    *services.RegulatorEvidenceExporter
+}
+
+// NewRegulatorHandler creates a new regulator admin handler
+func NewRegulatorHandler(
+	notifRepo repositories.RegulatorNotificationRepositoryInterface,
+	attemptRepo repositories.RegulatorNotificationAttemptRepositoryInterface,
+	transferRepo repositories.NorthwindTransferRepositoryInterface,
+) *RegulatorHandler {
+	return &RegulatorHandler{
+		notifRepo:   notifRepo,
+		attemptRepo: attemptRepo,
+		evidence:    services.NewRegulatorEvidenceExporter(notifRepo, attemptRepo, transferRepo),
+	}
+}
+
+// GetNotificationAttempts returns a notification together with a page of its delivery attempts,
+// including duration, target URL, and request header hash for SLA disputes. The attempts can be
+// filtered by outcome, status_class and from/to on the attempt time, and ?format=csv exports
+// every matching attempt instead of a page.
+func (h *RegulatorHandler) GetNotificationAttempts(c echo.Context) error {
+	notificationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid notification ID"))
+	}
+
+	notification, err := h.notifRepo.GetByID(c.Request().Context(), notificationID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRegulatorNotificationNotFound) {
+			return SendError(c, appErrors.RegulatorNotificationNotFound)
+		}
+		return SendSystemError(c, err)
+	}
+
+	return h.sendNotificationAttempts(c, notification)
+}
+
+// GetNotificationByEvent returns the notification that sent a webhook event, with every delivery
+// attempt, so an event ID quoted by the regulator can be traced to its transfer
+func (h *RegulatorHandler) GetNotificationByEvent(c echo.Context) error {
+	eventID := c.Param("event_id")
+	if eventID == "" {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid event ID"))
+	}
+
+	notification, err := h.notifRepo.GetByEventID(c.Request().Context(), eventID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRegulatorNotificationNotFound) {
+			return SendError(c, appErrors.RegulatorNotificationNotFound)
+		}
+		return SendSystemError(c, err)
+	}
+
+	return h.sendNotificationAttempts(c, notification)
+}
+
+// sendNotificationAttempts responds with the notification, a page of its attempts and the
+// notification paired with it: the COMPLETED one a REVERSED notification follows up, or the
+// reversal of a completion
+func (h *RegulatorHandler) sendNotificationAttempts(c echo.Context, notification *models.RegulatorNotification) error {
+	q := newQueryParams(c)
+	offset := q.Offset()
+	limit := q.Limit()
+	format := q.Enum("format", attemptFormatJSON, attemptFormatCSV)
+	filters := bindAttemptFilters(q)
+	filters.NotificationID = &notification.ID
+	if !q.Valid() {
+		return q.SendError()
+	}
+	if format == attemptFormatCSV {
+		return h.streamAttemptsCSV(c, filters, "regulator-attempts-"+notification.ID.String()+".csv")
+	}
+
+	ctx := c.Request().Context()
+	attempts, total, err := h.attemptRepo.ListFiltered(ctx, filters, offset, limit)
+	if err != nil {
+		return SendSystemError(c, err)
+	}
+
+	var related *models.RegulatorNotification
+	if notification.RelatedNotificationID != nil {
+		related, err = h.notifRepo.GetByID(ctx, *notification.RelatedNotificationID)
+	} else if notification.TerminalStatus == models.NWTransferStatusCompleted {
+		related, err = h.notifRepo.GetByTransferAndStatus(ctx, notification.TransferID, models.NWTransferStatusReversed)
+	}
+	if errors.Is(err, repositories.ErrRegulatorNotificationNotFound) {
+		related, err = nil, nil
+	}
+	if err != nil {
+		return SendSystemError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data: map[string]interface{}{
+			"notification":         notification,
+			"attempts":             attempts,
+			"related_notification": related,
+		},
+		Message: "Regulator notification attempts retrieved",
+		Meta: map[string]interface{}{
+			"total":  total,
+			"offset": offset,
+			"limit":  limit,
+		},
+	})
+}
+
+// ListAttempts returns the delivery attempts of every notification made between from and to,
+// oldest first, with the same filters as GetNotificationAttempts. Both bounds are required so an
+// audit export names its period; ?format=csv streams every matching attempt.
+func (h *RegulatorHandler) ListAttempts(c echo.Context) error {
+	q := newQueryParams(c)
+	offset := q.Offset()
+	limit := q.Limit()
+	format := q.Enum("format", attemptFormatJSON, attemptFormatCSV)
+	filters := bindAttemptFilters(q)
+	if c.QueryParam("from") == "" {
+		q.addError("from", "is required")
+	}
+	if c.QueryParam("to") == "" {
+		q.addError("to", "is required")
+	}
+	if !q.Valid() {
+		return q.SendError()
+	}
+	if format == attemptFormatCSV {
+		filename := fmt.Sprintf("regulator-attempts-%s-%s.csv",
+			filters.From.UTC().Format("20060102T150405Z"), filters.To.UTC().Format("20060102T150405Z"))
+		return h.streamAttemptsCSV(c, filters, filename)
+	}
+
+	attempts, total, err := h.attemptRepo.ListFiltered(c.Request().Context(), filters, offset, limit)
+	if err != nil {
+		return SendSystemError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    attempts,
+		Message: "Regulator notification attempts retrieved",
+		Meta: map[string]interface{}{
+			"total":  total,
+			"offset": offset,
+			"limit":  limit,
+		},
+	})
+}
+
+// bindAttemptFilters reads the outcome, status_class, from and to filters of an attempt listing
+func bindAttemptFilters(q *queryParams) models.RegulatorNotificationAttemptFilters {
+	filters := models.RegulatorNotificationAttemptFilters{
+		Outcome:     q.Enum("outcome", models.RegulatorAttemptOutcomeSuccess, models.RegulatorAttemptOutcomeFailure),
+		StatusClass: q.Enum("status_class", models.RegulatorAttemptStatusClasses...),
+		From:        q.Time("from"),
+		To:          q.Time("to"),
+	}
+	if filters.From != nil && filters.To != nil && filters.To.Before(*filters.From) {
+		q.addError("to", "must not be before from")
+	}
+	return filters
+}
+
+// streamAttemptsCSV writes the attempts matching filters as a CSV download, flushing every
+// attemptCSVFlushRows rows so a long export reaches the client as it is read. A failure before
+// any row is sent is a normal error response; after that the client sees a truncated file.
+func (h *RegulatorHandler) streamAttemptsCSV(c echo.Context, filters models.RegulatorNotificationAttemptFilters, filename string) error {
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+
+	w := csv.NewWriter(res)
+	err := w.Write(attemptCSVHeader)
+	rows := 0
+	if err == nil {
+		err = h.attemptRepo.StreamFiltered(c.Request().Context(), filters, func(attempt *models.RegulatorNotificationAttempt) error {
+			if err := w.Write(attemptCSVRow(attempt)); err != nil {
+				return err
+			}
+			rows++
+			if rows%attemptCSVFlushRows == 0 {
+				w.Flush()
+				res.Flush()
+				return w.Error()
+			}
+			return nil
+		})
+	}
+	if err != nil && !res.Committed {
+		res.Header().Del(echo.HeaderContentDisposition)
+		return SendSystemError(c, err)
+	}
+	w.Flush()
+	if err == nil {
+		err = w.Error()
+	}
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "Failed to stream regulator notification attempts", "rows", rows, "error", err)
+	}
+	return nil
+}
+
+// attemptCSVRow formats an attempt in the order of attemptCSVHeader
+func attemptCSVRow(attempt *models.RegulatorNotificationAttempt) []string {
+	row := []string{
+		attempt.ID.String(),
+		attempt.NotificationID.String(),
+		attempt.AttemptedAt.UTC().Format(time.RFC3339Nano),
+		attempt.Outcome(),
+		"",
+		strconv.Itoa(attempt.DurationMs),
+		attempt.TargetURL,
+		"",
+		attempt.RequestHeadersHash,
+		"",
+	}
+	if attempt.HTTPStatus != nil {
+		row[4] = strconv.Itoa(*attempt.HTTPStatus)
+	}
+	if attempt.Error != nil {
+		row[7] = *attempt.Error
+	}
+	if attempt.ResponseBody != nil {
+		row[9] = *attempt.ResponseBody
+	}
+	return row
+}
+
+// evidenceRequest is the body of the POST form of the evidence export
+type evidenceRequest struct {
+	TransferIDs []string `json:"transfer_ids"`
+}
+
+// ExportEvidence streams a ZIP with the proof of regulator notification for a sample of
+// transfers: one JSON file per transfer holding its notifications, every attempt and the
+// delivery confirmation, plus a manifest with each file's SHA-256. Transfers are given as
+// ?transfer_ids=a,b,c or, with POST, as {"transfer_ids": [...]}; those with no notification are
+// listed in the manifest instead of failing the export.
+func (h *RegulatorHandler) ExportEvidence(c echo.Context) error {
+	var raw []string
+	if c.Request().Method == http.MethodPost {
+		var req evidenceRequest
+		if err := c.Bind(&req); err != nil {
+			return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid request body"))
+		}
+		raw = req.TransferIDs
+	} else if param := c.QueryParam("transfer_ids"); param != "" {
+		raw = strings.Split(param, ",")
+	}
+	if len(raw) == 0 {
+		return SendError(c, appErrors.ValidationRequiredField, appErrors.WithDetails("transfer_ids is required"))
+	}
+
+	transferIDs := make([]uuid.UUID, 0, len(raw))
+	for _, value := range raw {
+		id, err := uuid.Parse(strings.TrimSpace(value))
+		if err != nil {
+			return SendError(c, appErrors.ValidationInvalidFormat, appErrors.WithDetails(fmt.Sprintf("transfer_ids: %q is not a valid transfer ID", value)))
+		}
+		transferIDs = append(transferIDs, id)
+	}
+
+	bundle, err := h.evidence.Collect(c.Request().Context(), transferIDs)
+	if err != nil {
+		if errors.Is(err, services.ErrEvidenceTooManyTransfers) {
+			return SendError(c, appErrors.ValidationOutOfRange, appErrors.WithDetails(
+				fmt.Sprintf("transfer_ids: at most %d transfers per export", services.MaxEvidenceTransfers),
+			))
+		}
+		return SendSystemError(c, err)
+	}
+
+	filename := "regulator-evidence-" + time.Now().UTC().Format("20060102T150405Z") + ".zip"
+	c.Response().Header().Set(echo.HeaderContentType, "application/zip")
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+	c.Response().WriteHeader(http.StatusOK)
+	if err := bundle.WriteZip(c.Response()); err != nil {
+		// The status is already sent; the client sees a truncated archive
+		slog.ErrorContext(c.Request().Context(), "Failed to stream regulator evidence export", "error", err)
+	}
+	return nil
+}
@@ -0,0 +1,413 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/repositories/repository_mocks"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newRegulatorHandlerTest(t *testing.T) (*RegulatorHandler, *repository_mocks.MockRegulatorNotificationRepositoryInterface, *repository_mocks.MockRegulatorNotificationAttemptRepositoryInterface, *repository_mocks.MockNorthwindTransferRepositoryInterface) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	notifRepo := repository_mocks.NewMockRegulatorNotificationRepositoryInterface(ctrl)
+	attemptRepo := repository_mocks.NewMockRegulatorNotificationAttemptRepositoryInterface(ctrl)
+	transferRepo := repository_mocks.NewMockNorthwindTransferRepositoryInterface(ctrl)
+	return NewRegulatorHandler(notifRepo, attemptRepo, transferRepo), notifRepo, attemptRepo, transferRepo
+}
+
+func TestRegulatorHandler_GetNotificationAttempts_Success(t *testing.T) {
+	handler, notifRepo, attemptRepo, _ := newRegulatorHandlerTest(t)
+
+	notificationID := uuid.New()
+	status := http.StatusOK
+	notifRepo.EXPECT().GetByID(gomock.Any(), notificationID).Return(&models.RegulatorNotification{ID: notificationID, Delivered: true}, nil)
+	attemptRepo.EXPECT().ListFiltered(gomock.Any(), models.RegulatorNotificationAttemptFilters{NotificationID: &notificationID}, 0, defaultPageLimit).Return([]models.RegulatorNotificationAttempt{
+		{
+			ID:                 uuid.New(),
+			NotificationID:     notificationID,
+			HTTPStatus:         &status,
+			DurationMs:         42,
+			TargetURL:          "http://regulator:9000/webhook",
+			RequestHeadersHash: "abc123",
+		},
+	}, int64(1), nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(notificationID.String())
+
+	require.NoError(t, handler.GetNotificationAttempts(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var body struct {
+		Data struct {
+			Attempts []models.RegulatorNotificationAttempt `json:"attempts"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	require.Len(t, body.Data.Attempts, 1)
+	assert.Equal(t, 42, body.Data.Attempts[0].DurationMs)
+	assert.Equal(t, "http://regulator:9000/webhook", body.Data.Attempts[0].TargetURL)
+	assert.Equal(t, "abc123", body.Data.Attempts[0].RequestHeadersHash)
+}
+
+func TestRegulatorHandler_GetNotificationAttempts_NotFound(t *testing.T) {
+	handler, notifRepo, _, _ := newRegulatorHandlerTest(t)
+
+	notificationID := uuid.New()
+	notifRepo.EXPECT().GetByID(gomock.Any(), notificationID).Return(nil, repositories.ErrRegulatorNotificationNotFound)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(notificationID.String())
+
+	require.NoError(t, handler.GetNotificationAttempts(c))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestRegulatorHandler_GetNotificationAttempts_InvalidID(t *testing.T) {
+	handler, _, _, _ := newRegulatorHandlerTest(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("not-a-uuid")
+
+	require.NoError(t, handler.GetNotificationAttempts(c))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestRegulatorHandler_GetNotificationByEvent(t *testing.T) {
+	handler, notifRepo, attemptRepo, _ := newRegulatorHandlerTest(t)
+
+	notificationID := uuid.New()
+	transferID := uuid.New()
+	eventID := uuid.NewString()
+	notifRepo.EXPECT().GetByEventID(gomock.Any(), eventID).Return(&models.RegulatorNotification{ID: notificationID, TransferID: transferID, EventID: &eventID}, nil)
+	attemptRepo.EXPECT().ListFiltered(gomock.Any(), gomock.Any(), 0, defaultPageLimit).Return([]models.RegulatorNotificationAttempt{}, int64(0), nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("event_id")
+	c.SetParamValues(eventID)
+
+	require.NoError(t, handler.GetNotificationByEvent(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var body struct {
+		Data struct {
+			Notification models.RegulatorNotification `json:"notification"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.Equal(t, transferID, body.Data.Notification.TransferID)
+	require.NotNil(t, body.Data.Notification.EventID)
+	assert.Equal(t, eventID, *body.Data.Notification.EventID)
+}
+
+func TestRegulatorHandler_GetNotificationAttempts_ShowsLinkedPair(t *testing.T) {
+	handler, notifRepo, attemptRepo, _ := newRegulatorHandlerTest(t)
+
+	transferID := uuid.New()
+	completion := &models.RegulatorNotification{ID: uuid.New(), TransferID: transferID, TerminalStatus: models.NWTransferStatusCompleted}
+	reversal := &models.RegulatorNotification{ID: uuid.New(), TransferID: transferID, TerminalStatus: models.NWTransferStatusReversed, RelatedNotificationID: &completion.ID}
+	notifRepo.EXPECT().GetByID(gomock.Any(), completion.ID).Return(completion, nil).Times(2)
+	notifRepo.EXPECT().GetByID(gomock.Any(), reversal.ID).Return(reversal, nil)
+	notifRepo.EXPECT().GetByTransferAndStatus(gomock.Any(), transferID, models.NWTransferStatusReversed).Return(reversal, nil)
+	attemptRepo.EXPECT().ListFiltered(gomock.Any(), gomock.Any(), 0, defaultPageLimit).Return([]models.RegulatorNotificationAttempt{}, int64(0), nil).Times(2)
+
+	for notification, want := range map[*models.RegulatorNotification]uuid.UUID{reversal: completion.ID, completion: reversal.ID} {
+		e := echo.New()
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+		c.SetParamNames("id")
+		c.SetParamValues(notification.ID.String())
+
+		require.NoError(t, handler.GetNotificationAttempts(c))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var body struct {
+			Data struct {
+				Related *models.RegulatorNotification `json:"related_notification"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+		require.NotNil(t, body.Data.Related, notification.TerminalStatus)
+		assert.Equal(t, want, body.Data.Related.ID)
+	}
+}
+
+func TestRegulatorHandler_GetNotificationByEvent_NotFound(t *testing.T) {
+	handler, notifRepo, _, _ := newRegulatorHandlerTest(t)
+
+	notifRepo.EXPECT().GetByEventID(gomock.Any(), "evt-unknown").Return(nil, repositories.ErrRegulatorNotificationNotFound)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("event_id")
+	c.SetParamValues("evt-unknown")
+
+	require.NoError(t, handler.GetNotificationByEvent(c))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestRegulatorHandler_ExportEvidence(t *testing.T) {
+	notifiedID, missingID := uuid.New(), uuid.New()
+	notificationID := uuid.New()
+
+	for name, newRequest := range map[string]func() *http.Request{
+		"query": func() *http.Request {
+			return httptest.NewRequest(http.MethodGet, "/?transfer_ids="+notifiedID.String()+","+missingID.String(), nil)
+		},
+		"body": func() *http.Request {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"transfer_ids":["`+notifiedID.String()+`","`+missingID.String()+`"]}`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			return req
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			handler, notifRepo, attemptRepo, transferRepo := newRegulatorHandlerTest(t)
+			notifRepo.EXPECT().ListByTransferIDs(gomock.Any(), []uuid.UUID{notifiedID, missingID}).Return([]models.RegulatorNotification{
+				{ID: notificationID, TransferID: notifiedID, Delivered: true, Payload: json.RawMessage(`{"event_id":"evt-1"}`)},
+			}, nil)
+			attemptRepo.EXPECT().ListByNotificationIDs(gomock.Any(), []uuid.UUID{notificationID}).Return(nil, nil)
+			transferRepo.EXPECT().GetByIDs(gomock.Any(), []uuid.UUID{notifiedID, missingID}).Return(map[uuid.UUID]models.NorthwindTransfer{
+				notifiedID: {ID: notifiedID, Status: models.NWTransferStatusCompleted},
+			}, nil)
+
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(newRequest(), rec)
+			require.NoError(t, handler.ExportEvidence(c))
+
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			assert.Equal(t, "application/zip", rec.Header().Get(echo.HeaderContentType))
+			assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "regulator-evidence-")
+			archive, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+			require.NoError(t, err)
+			var names []string
+			for _, f := range archive.File {
+				names = append(names, f.Name)
+			}
+			assert.Equal(t, []string{notifiedID.String() + ".json", "manifest.json"}, names)
+		})
+	}
+}
+
+func TestRegulatorHandler_ExportEvidence_ConstantQueries(t *testing.T) {
+	db := testfactory.NewDB(t)
+	handler := NewRegulatorHandler(repositories.NewRegulatorNotificationRepository(db),
+		repositories.NewRegulatorNotificationAttemptRepository(db), repositories.NewNorthwindTransferRepository(db))
+
+	var queries int
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:count_queries", func(*gorm.DB) {
+		queries++
+	}))
+	export := func(n int) int {
+		ids := make([]string, n)
+		for i := range ids {
+			transfer := testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusCompleted))
+			testfactory.RegulatorNotification(t, db, testfactory.WithTransfer(transfer))
+			ids[i] = transfer.ID.String()
+		}
+		queries = 0
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/?transfer_ids="+strings.Join(ids, ","), nil), rec)
+		require.NoError(t, handler.ExportEvidence(c))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		return queries
+	}
+
+	few, many := export(2), export(20)
+	assert.Positive(t, few)
+	assert.Equal(t, few, many, "expected the same number of queries however many transfers are exported")
+}
+
+func TestRegulatorHandler_ExportEvidence_InvalidInput(t *testing.T) {
+	for name, tc := range map[string]struct {
+		target     string
+		wantStatus int
+	}{
+		"missing transfer_ids": {"/", http.StatusBadRequest},
+		"invalid transfer ID":  {"/?transfer_ids=" + uuid.NewString() + ",not-a-uuid", http.StatusBadRequest},
+	} {
+		t.Run(name, func(t *testing.T) {
+			handler, _, _, _ := newRegulatorHandlerTest(t)
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, tc.target, nil), rec)
+
+			require.NoError(t, handler.ExportEvidence(c))
+			assert.Equal(t, tc.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestRegulatorHandler_GetNotificationAttempts_Filters(t *testing.T) {
+	handler, notifRepo, attemptRepo, _ := newRegulatorHandlerTest(t)
+
+	notificationID := uuid.New()
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	notifRepo.EXPECT().GetByID(gomock.Any(), notificationID).Return(&models.RegulatorNotification{ID: notificationID}, nil)
+	attemptRepo.EXPECT().ListFiltered(gomock.Any(), models.RegulatorNotificationAttemptFilters{
+		NotificationID: &notificationID,
+		Outcome:        models.RegulatorAttemptOutcomeFailure,
+		StatusClass:    "5xx",
+		From:           &from,
+		To:             &to,
+	}, 20, 10).Return([]models.RegulatorNotificationAttempt{}, int64(42), nil)
+
+	target := "/?outcome=failure&status_class=5xx&from=2026-03-01T00:00:00Z&to=2026-03-02T00:00:00Z&offset=20&limit=10"
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, target, nil), rec)
+	c.SetParamNames("id")
+	c.SetParamValues(notificationID.String())
+
+	require.NoError(t, handler.GetNotificationAttempts(c))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var body struct {
+		Meta map[string]interface{} `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.EqualValues(t, 42, body.Meta["total"])
+	assert.EqualValues(t, 20, body.Meta["offset"])
+	assert.EqualValues(t, 10, body.Meta["limit"])
+}
+
+func TestRegulatorHandler_ListAttempts_InvalidQuery(t *testing.T) {
+	for name, target := range map[string]string{
+		"missing range":      "/",
+		"missing to":         "/?from=2026-03-01T00:00:00Z",
+		"reversed range":     "/?from=2026-03-02T00:00:00Z&to=2026-03-01T00:00:00Z",
+		"unknown outcome":    "/?from=2026-03-01T00:00:00Z&to=2026-03-02T00:00:00Z&outcome=maybe",
+		"unknown class":      "/?from=2026-03-01T00:00:00Z&to=2026-03-02T00:00:00Z&status_class=6xx",
+		"unknown format":     "/?from=2026-03-01T00:00:00Z&to=2026-03-02T00:00:00Z&format=xml",
+		"limit out of range": "/?from=2026-03-01T00:00:00Z&to=2026-03-02T00:00:00Z&limit=1000",
+	} {
+		t.Run(name, func(t *testing.T) {
+			handler, _, _, _ := newRegulatorHandlerTest(t)
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, target, nil), rec)
+
+			require.NoError(t, handler.ListAttempts(c))
+			assert.Equal(t, http.StatusUnprocessableEntity, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestRegulatorHandler_ListAttempts_CSV(t *testing.T) {
+	handler, _, attemptRepo, _ := newRegulatorHandlerTest(t)
+
+	status := http.StatusServiceUnavailable
+	errMsg := "webhook returned HTTP 503"
+	responseBody := "line one\nline \"two\""
+	attempt := models.RegulatorNotificationAttempt{
+		ID:                 uuid.New(),
+		NotificationID:     uuid.New(),
+		AttemptedAt:        time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC),
+		HTTPStatus:         &status,
+		Error:              &errMsg,
+		ResponseBody:       &responseBody,
+		DurationMs:         120,
+		TargetURL:          "http://regulator:9000/webhook",
+		RequestHeadersHash: "abc123",
+	}
+	attemptRepo.EXPECT().StreamFiltered(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, filters models.RegulatorNotificationAttemptFilters, fn func(*models.RegulatorNotificationAttempt) error) error {
+			assert.Nil(t, filters.NotificationID)
+			assert.Equal(t, models.RegulatorAttemptOutcomeFailure, filters.Outcome)
+			return fn(&attempt)
+		})
+
+	rec := httptest.NewRecorder()
+	target := "/?from=2026-03-01T00:00:00Z&to=2026-03-02T00:00:00Z&outcome=failure&format=csv"
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, target, nil), rec)
+	require.NoError(t, handler.ListAttempts(c))
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, `attachment; filename="regulator-attempts-20260301T000000Z-20260302T000000Z.csv"`, rec.Header().Get(echo.HeaderContentDisposition))
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, []string{
+		"attempt_id", "notification_id", "attempted_at", "outcome", "http_status", "duration_ms",
+		"target_url", "error", "request_headers_hash", "response_body",
+	}, records[0])
+	assert.Equal(t, []string{
+		attempt.ID.String(), attempt.NotificationID.String(), "2026-03-01T09:30:00Z", "failure", "503", "120",
+		"http://regulator:9000/webhook", errMsg, "abc123", responseBody,
+	}, records[1])
+}
+
+func TestRegulatorHandler_GetNotificationAttempts_CSVStreamsLargeExports(t *testing.T) {
+	handler, notifRepo, attemptRepo, _ := newRegulatorHandlerTest(t)
+
+	notificationID := uuid.New()
+	const rows = 3*attemptCSVFlushRows + 7
+	notifRepo.EXPECT().GetByID(gomock.Any(), notificationID).Return(&models.RegulatorNotification{ID: notificationID}, nil)
+	attemptRepo.EXPECT().StreamFiltered(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, filters models.RegulatorNotificationAttemptFilters, fn func(*models.RegulatorNotificationAttempt) error) error {
+			assert.Equal(t, &notificationID, filters.NotificationID)
+			for i := 0; i < rows; i++ {
+				if err := fn(&models.RegulatorNotificationAttempt{ID: uuid.New(), NotificationID: notificationID}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/?format=csv", nil), rec)
+	c.SetParamNames("id")
+	c.SetParamValues(notificationID.String())
+	require.NoError(t, handler.GetNotificationAttempts(c))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, rec.Flushed, "expected rows to be flushed while streaming")
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	require.NoError(t, err)
+	assert.Len(t, records, rows+1)
+}
+
+func TestRegulatorHandler_ListAttempts_CSVFailureBeforeFirstRow(t *testing.T) {
+	handler, _, attemptRepo, _ := newRegulatorHandlerTest(t)
+	attemptRepo.EXPECT().StreamFiltered(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("connection refused"))
+
+	rec := httptest.NewRecorder()
+	target := "/?from=2026-03-01T00:00:00Z&to=2026-03-02T00:00:00Z&format=csv"
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, target, nil), rec)
+	require.NoError(t, handler.ListAttempts(c))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Empty(t, rec.Header().Get(echo.HeaderContentDisposition))
+}
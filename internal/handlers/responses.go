@@ -1,9 +1,16 @@
 package handlers
 
 import (
+	"context"
+	stderrors "errors"
+	"log/slog"
 	"net/http"
+	"strconv"
 
 	"github.com/array/banking-api/internal/errors"
+	"github.com/array/banking-api/internal/i18n"
+	"github.com/array/banking-api/internal/services"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
@@ -18,6 +25,9 @@ import (
 //    - Authorization errors: SendError(c, errors.AuthInsufficientPermission)
 //    - Not found errors: SendError(c, errors.CustomerNotFound)
 //    - Business rule violations: SendError(c, errors.AccountInsufficientBalance)
+//    - Throttling or unavailability: SendError(c, errors.SystemRateLimitExceeded, errors.WithRetryAfter(wait))
+//      Every 429 and 503 carries a Retry-After header and retry_after_seconds; without a hint
+//      from the sender they default to errors.DefaultRetryAfter
 //
 // 2. SendSystemError - For system/internal errors (500 responses)
 //    Use cases:
@@ -59,16 +69,111 @@ func getTraceID(c echo.Context) string {
 	return traceID
 }
 
-// SendError sends a standardized error response with trace ID from context
+// LocalizerContextKey is the echo context key under which the locale middleware stores the
+// request's *i18n.Localizer
+const LocalizerContextKey = "localizer"
+
+// localizer returns the request's localizer, or English when the locale middleware did not run
+func localizer(c echo.Context) *i18n.Localizer {
+	if l, ok := c.Get(LocalizerContextKey).(*i18n.Localizer); ok {
+		return l
+	}
+	return i18n.Default().Localizer(i18n.DefaultLocale)
+}
+
+// localize returns the message for key, formatted with args, in the request's locale
+func localize(c echo.Context, key string, args ...interface{}) string {
+	return localizer(c).Message(key, args...)
+}
+
+// LocalizeError translates an error response's message into the request's locale, unless the
+// message was replaced with one that is not the registry's, such as an upstream message
+func LocalizeError(c echo.Context, errorResponse *errors.ErrorResponse) {
+	code := errors.ErrorCode(errorResponse.Error.Code)
+	if errorResponse.Error.Message != errors.GetErrorMessage(code) {
+		return
+	}
+	errorResponse.Error.Message = localizer(c).ErrorMessage(errorResponse.Error.Code, errorResponse.Error.Message)
+}
+
+// RedactErrorDetailsKey is the echo context key under which the error detail middleware records
+// whether error details outside errors.DetailsSafe are withheld from the client
+const RedactErrorDetailsKey = "redact_error_details"
+
+// SendError sends a standardized error response with trace ID from context. When details are
+// redacted, details of codes not in errors.DetailsSafe are logged under the response's trace ID
+// and the client gets that ID in their place.
 func SendError(c echo.Context, code errors.ErrorCode, opts ...errors.ErrorOption) error {
 	traceID := getTraceID(c)
 	errorResponse := errors.NewErrorResponse(code, traceID, opts...)
-	return c.JSON(errorResponse.GetHTTPStatus(), errorResponse)
+	LocalizeError(c, errorResponse)
+	if redact, _ := c.Get(RedactErrorDetailsKey).(bool); redact && len(errorResponse.Error.Details) > 0 && !errors.DetailsSafe(code) {
+		redactDetails(c, errorResponse)
+	}
+	status := errorResponse.GetHTTPStatus()
+	SetRetryAfter(c, status, errorResponse)
+	return c.JSON(status, errorResponse)
 }
 
-// SendSystemError wraps a system error with generic message and logs the internal error
+// SetRetryAfter gives a 429 or 503 error response a retry hint, errors.DefaultRetryAfter unless
+// its sender supplied one with errors.WithRetryAfter, and sets the Retry-After header from that
+// same hint so the header and the body agree
+func SetRetryAfter(c echo.Context, status int, errorResponse *errors.ErrorResponse) {
+	if status != http.StatusTooManyRequests && status != http.StatusServiceUnavailable {
+		return
+	}
+	if errorResponse.Error.RetryAfterSeconds <= 0 {
+		errors.WithRetryAfter(errors.DefaultRetryAfter)(errorResponse)
+	}
+	c.Response().Header().Set("Retry-After", strconv.Itoa(errorResponse.Error.RetryAfterSeconds))
+}
+
+// redactDetails logs the response's details and replaces them with the trace ID, generating one
+// when the request has none
+func redactDetails(c echo.Context, errorResponse *errors.ErrorResponse) {
+	if errorResponse.Error.TraceID == "" {
+		errorResponse.Error.TraceID = uuid.NewString()
+	}
+	slog.WarnContext(c.Request().Context(), "Error details withheld from client",
+		"trace_id", errorResponse.Error.TraceID,
+		"error_code", errorResponse.Error.Code,
+		"details", errorResponse.Error.Details,
+		"path", c.Request().URL.Path,
+		"method", c.Request().Method,
+	)
+	errorResponse.Error.Details = []string{"Details withheld, reference trace ID " + errorResponse.Error.TraceID}
+}
+
+// TimeoutCodeKey is the echo context key under which a timeout middleware records the error code
+// for requests that outlive its deadline; SendSystemError falls back to SystemRequestTimeout
+const TimeoutCodeKey = "timeout_error_code"
+
+// SendSystemError wraps a system error with generic message and logs the internal error. When the
+// request's deadline has passed the error is most likely a cancelled query, so the client gets
+// the timeout error recorded under TimeoutCodeKey instead. Work refused by an open circuit breaker
+// is a 503 telling the client to retry once the breaker goes half-open.
 func SendSystemError(c echo.Context, err error) error {
+	var breakerErr *services.CircuitBreakerOpenError
+	if stderrors.As(err, &breakerErr) {
+		return SendError(c, errors.SystemServiceUnavailable, errors.WithRetryAfter(breakerErr.RetryAfter))
+	}
+	if c.Request().Context().Err() == context.DeadlineExceeded {
+		if code, ok := c.Get(TimeoutCodeKey).(errors.ErrorCode); ok {
+			return SendError(c, code)
+		}
+		return SendError(c, errors.SystemRequestTimeout)
+	}
 	traceID := getTraceID(c)
-	errorResponse, _ := errors.WrapSystemError(err, traceID)
+	if traceID == "" {
+		traceID = uuid.NewString()
+	}
+	errorResponse, internalErr := errors.WrapSystemError(err, traceID)
+	LocalizeError(c, errorResponse)
+	slog.ErrorContext(c.Request().Context(), "Internal error",
+		"trace_id", traceID,
+		"error", internalErr,
+		"path", c.Request().URL.Path,
+		"method", c.Request().Method,
+	)
 	return c.JSON(http.StatusInternalServerError, errorResponse)
 }
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	appErrors "github.com/array/banking-api/internal/errors"
+	"github.com/array/banking-api/internal/services"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureLogs sends the default logger's records to the returned buffer, one JSON object per line,
+// until the test ends
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+// errorContext returns a context for a request carrying traceID, with details redacted when redact is set
+func errorContext(traceID string, redact bool) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/v1/northwind/transfers", nil), rec)
+	if traceID != "" {
+		c.Set(TraceIDContextKey, traceID)
+	}
+	c.Set(RedactErrorDetailsKey, redact)
+	return c, rec
+}
+
+func TestSendError_DetailRedaction(t *testing.T) {
+	upstreamDetail := `northwind API error (status 502): {"message":"upstream http://10.0.0.5:8081/internal failed"}`
+	tests := map[string]struct {
+		redact     bool
+		code       appErrors.ErrorCode
+		detail     string
+		wantDetail string
+		wantLogged bool
+		traceID    string
+	}{
+		"production withholds upstream detail":      {true, appErrors.NorthwindAPIError, upstreamDetail, "Details withheld, reference trace ID trace-123", true, "trace-123"},
+		"production without trace ID generates one": {true, appErrors.NorthwindAPIError, upstreamDetail, "", true, ""},
+		"production passes validation detail":       {true, appErrors.ValidationGeneral, "amount: must be positive", "amount: must be positive", false, "trace-123"},
+		"development passes upstream detail":        {false, appErrors.NorthwindAPIError, upstreamDetail, upstreamDetail, false, "trace-123"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			logs := captureLogs(t)
+			c, rec := errorContext(tt.traceID, tt.redact)
+
+			require.NoError(t, SendError(c, tt.code, appErrors.WithDetails(tt.detail)))
+
+			var body ErrorResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+			require.Len(t, body.Error.Details, 1)
+			assert.NotEmpty(t, body.Error.TraceID)
+			if tt.wantDetail != "" {
+				assert.Equal(t, tt.wantDetail, body.Error.Details[0])
+			} else {
+				assert.Equal(t, "Details withheld, reference trace ID "+body.Error.TraceID, body.Error.Details[0])
+			}
+
+			if !tt.wantLogged {
+				assert.Empty(t, logs.String())
+				return
+			}
+			var record struct {
+				Msg     string   `json:"msg"`
+				TraceID string   `json:"trace_id"`
+				Details []string `json:"details"`
+			}
+			require.NoError(t, json.Unmarshal(logs.Bytes(), &record))
+			assert.Equal(t, "Error details withheld from client", record.Msg)
+			assert.Equal(t, body.Error.TraceID, record.TraceID, "the response must point at the log record")
+			assert.Equal(t, []string{tt.detail}, record.Details)
+		})
+	}
+}
+
+func TestSendSystemError_LogsUnderTraceID(t *testing.T) {
+	logs := captureLogs(t)
+	c, rec := errorContext("", true)
+
+	require.NoError(t, SendSystemError(c, errors.New("pq: connection refused to db-primary:5432")))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "db-primary")
+	var body ErrorResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	require.NotEmpty(t, body.Error.TraceID)
+
+	var record struct {
+		TraceID string `json:"trace_id"`
+		Error   string `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &record))
+	assert.Equal(t, body.Error.TraceID, record.TraceID)
+	assert.Contains(t, record.Error, "db-primary")
+}
+
+func TestSendSystemError_CircuitBreakerOpen(t *testing.T) {
+	c, rec := errorContext("trace-123", true)
+
+	err := fmt.Errorf("process queue item: %w", &services.CircuitBreakerOpenError{RetryAfter: 12300 * time.Millisecond})
+	require.NoError(t, SendSystemError(c, err))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	var body ErrorResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.Equal(t, string(appErrors.SystemServiceUnavailable), body.Error.Code)
+	assert.Equal(t, 13, body.Error.RetryAfterSeconds, "the hint is rounded up to whole seconds")
+	assert.Equal(t, "13", rec.Header().Get("Retry-After"), "header and body must agree")
+}
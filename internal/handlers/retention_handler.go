@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/array/banking-api/internal/services"
+	"github.com/labstack/echo/v4"
+)
+
+// RetentionHandler lets admins see what the data retention job would purge
+type RetentionHandler struct {
+	retention *services.RetentionService
+}
+
+// NewRetentionHandler creates a new retention admin handler
+func NewRetentionHandler(retention *services.RetentionService) *RetentionHandler {
+	return &RetentionHandler{retention: retention}
+}
+
+// DryRun reports, per entity, the rows past their retention period and how many of those are
+// held by open work, without purging anything
+func (h *RetentionHandler) DryRun(c echo.Context) error {
+	reports, err := h.retention.DryRun(c.Request().Context())
+	if err != nil {
+		return SendSystemError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    reports,
+		Message: "Retention dry run completed",
+	})
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/config"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories/repository_mocks"
+	"github.com/array/banking-api/internal/services"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetentionHandler_DryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	nwTransfers := repository_mocks.NewMockNorthwindTransferRepositoryInterface(ctrl)
+	transfers := repository_mocks.NewMockTransferRepositoryInterface(ctrl)
+	transactions := repository_mocks.NewMockTransactionRepositoryInterface(ctrl)
+	auditLogs := repository_mocks.NewMockAuditLogRepositoryInterface(ctrl)
+
+	nwTransfers.EXPECT().CountRetentionExpired(gomock.Any(), gomock.Any()).Return(models.RetentionCount{Expired: 3, Held: 1}, nil)
+	transfers.EXPECT().CountRetentionExpired(gomock.Any(), gomock.Any()).Return(models.RetentionCount{}, nil)
+	transactions.EXPECT().CountRetentionExpired(gomock.Any(), gomock.Any()).Return(models.RetentionCount{Expired: 5}, nil)
+	auditLogs.EXPECT().CountRetentionExpired(gomock.Any(), gomock.Any()).Return(models.RetentionCount{}, nil)
+
+	retention := services.NewRetentionService(nwTransfers, transfers, transactions, auditLogs,
+		config.RetentionConfig{TransferYears: 7, TransactionYears: 7, AuditLogYears: 10}, slog.Default())
+	handler := NewRetentionHandler(retention)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/retention/dry-run", nil)
+	rec := httptest.NewRecorder()
+	require.NoError(t, handler.DryRun(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var body struct {
+		Data []struct {
+			Entity         string    `json:"entity"`
+			RetentionYears int       `json:"retention_years"`
+			Cutoff         time.Time `json:"cutoff"`
+			Expired        int64     `json:"expired"`
+			Held           int64     `json:"held"`
+			Purged         int64     `json:"purged"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Len(t, body.Data, 4)
+	assert.Equal(t, services.RetentionEntityNorthwindTransfers, body.Data[0].Entity)
+	assert.Equal(t, int64(3), body.Data[0].Expired)
+	assert.Equal(t, int64(1), body.Data[0].Held)
+	assert.Equal(t, 10, body.Data[3].RetentionYears)
+	assert.Zero(t, body.Data[2].Purged)
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	appErrors "github.com/array/banking-api/internal/errors"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/services"
+	"github.com/labstack/echo/v4"
+)
+
+// RiskHandler lets admins switch risk rules between off, shadow and enforce at runtime and
+// analyze the verdicts they recorded
+type RiskHandler struct {
+	risk *services.RiskService
+}
+
+// NewRiskHandler creates a new risk rule admin handler
+func NewRiskHandler(risk *services.RiskService) *RiskHandler {
+	return &RiskHandler{risk: risk}
+}
+
+// SetRiskRuleModeRequest sets a risk rule's mode
+type SetRiskRuleModeRequest struct {
+	Mode string `json:"mode"`
+}
+
+// ListRules returns every risk rule with its effective mode and where it comes from
+func (h *RiskHandler) ListRules(c echo.Context) error {
+	rules, err := h.risk.ListRules(c.Request().Context())
+	if err != nil {
+		return SendSystemError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    rules,
+		Message: "Risk rules retrieved",
+	})
+}
+
+// SetMode overrides a risk rule's mode; every instance applies it from the next transfer request
+func (h *RiskHandler) SetMode(c echo.Context) error {
+	adminID, err := getUserIDFromContext(c)
+	if err != nil {
+		return SendError(c, appErrors.AuthMissingToken)
+	}
+
+	var req SetRiskRuleModeRequest
+	if err := c.Bind(&req); err != nil {
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails("Invalid request body"))
+	}
+	if req.Mode == "" {
+		return SendError(c, appErrors.ValidationRequiredField, appErrors.WithDetails("mode is required"))
+	}
+
+	if err := h.risk.SetMode(c.Request().Context(), services.RiskRule(c.Param("rule")), req.Mode, adminID); err != nil {
+		return sendRiskError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{Message: "Risk rule mode updated"})
+}
+
+// ClearMode removes the runtime override so the configured or default mode applies again
+func (h *RiskHandler) ClearMode(c echo.Context) error {
+	if err := h.risk.ClearMode(c.Request().Context(), services.RiskRule(c.Param("rule"))); err != nil {
+		return sendRiskError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{Message: "Risk rule mode override removed"})
+}
+
+// ListEvaluations returns recorded risk verdicts, newest first, with per-rule counts of the
+// verdicts matching the same filters in the meta
+func (h *RiskHandler) ListEvaluations(c echo.Context) error {
+	q := newQueryParams(c)
+	offset := q.Offset()
+	limit := q.Limit()
+	filters := models.RiskEvaluationFilters{
+		Rule:           c.QueryParam("rule"),
+		Mode:           q.Enum("mode", models.RiskRuleModeShadow, models.RiskRuleModeEnforce),
+		Verdict:        q.Enum("verdict", models.RiskVerdictPass, models.RiskVerdictFlag),
+		Blocked:        q.Bool("blocked"),
+		UserID:         q.UUID("user_id"),
+		TransferID:     q.UUID("transfer_id"),
+		TransferStatus: q.Enum("transfer_status", models.NWTransferStatusValues()...),
+		From:           q.Time("from"),
+		To:             q.Time("to"),
+	}
+	if !q.Valid() {
+		return q.SendError()
+	}
+
+	ctx := c.Request().Context()
+	evaluations, total, err := h.risk.ListEvaluations(ctx, filters, offset, limit)
+	if err != nil {
+		return SendSystemError(c, err)
+	}
+	summary, err := h.risk.SummarizeEvaluations(ctx, filters)
+	if err != nil {
+		return SendSystemError(c, err)
+	}
+	return c.JSON(http.StatusOK, SuccessResponse{
+		Data:    evaluations,
+		Message: "Risk evaluations retrieved",
+		Meta: map[string]interface{}{
+			"total":   total,
+			"offset":  offset,
+			"limit":   limit,
+			"summary": summary,
+		},
+	})
+}
+
+func sendRiskError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrUnknownRiskRule):
+		return SendError(c, appErrors.RiskRuleNotFound)
+	case errors.Is(err, services.ErrInvalidRiskRuleMode):
+		return SendError(c, appErrors.ValidationGeneral, appErrors.WithDetails(err.Error()))
+	default:
+		return SendSystemError(c, err)
+	}
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/config"
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/services"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/array/banking-api/internal/validation"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func riskContext(method, target, body string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	e.Validator = validation.EchoValidator()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user_id", uuid.New())
+	return c, rec
+}
+
+func TestRiskHandler_EnforceBlocksAndShadowRecords(t *testing.T) {
+	server := newCreateTransferStub(t)
+	db := testfactory.NewDB(t)
+	transferRepo := repositories.NewNorthwindTransferRepository(db)
+	risk := services.NewRiskService(transferRepo, repositories.NewRiskEvaluationRepository(db), repositories.NewRiskRuleOverrideRepository(db),
+		config.RiskConfig{VelocityWindow: time.Hour, VelocityMaxTransfers: 10, VelocityMaxAmount: 50000, LargeTransferAmount: 100}, nil, slog.Default())
+	transferSvc := services.NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), transferRepo, nil, nil, slog.Default())
+	transferSvc.SetFeatureFlags(services.NewFeatureFlagService(repositories.NewFeatureFlagOverrideRepository(db),
+		map[services.FeatureFlag]int{services.FlagTransferRiskRules: 100}, slog.Default()))
+	transferSvc.SetRiskService(risk)
+	northwindHandler := NewNorthwindHandler(nil, nil, transferSvc, nil, nil, testEnv("testing"))
+	handler := NewRiskHandler(risk)
+
+	// Every rule starts in shadow mode: the 250 transfer is flagged as large but created
+	c, rec := riskContext(http.MethodPost, "/api/v1/northwind/transfers", createTransferBody)
+	require.NoError(t, northwindHandler.CreateTransfer(c))
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	c, rec = riskContext(http.MethodPut, "/api/v1/admin/risk/rules/large_transfer", `{"mode":"enforce"}`)
+	c.SetParamNames("rule")
+	c.SetParamValues("large_transfer")
+	require.NoError(t, handler.SetMode(c))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	c, rec = riskContext(http.MethodPost, "/api/v1/northwind/transfers", strings.Replace(createTransferBody, `"amount":250`, `"amount":251`, 1))
+	require.NoError(t, northwindHandler.CreateTransfer(c))
+	require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), "NORTHWIND_TRANSFER_015")
+	assert.NotContains(t, rec.Body.String(), "large_transfer")
+
+	c, rec = riskContext(http.MethodGet, "/api/v1/admin/risk/evaluations?rule=large_transfer&verdict=FLAG", "")
+	require.NoError(t, handler.ListEvaluations(c))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var body struct {
+		Data []models.RiskEvaluation `json:"data"`
+		Meta struct {
+			Total   int64                    `json:"total"`
+			Summary []models.RiskRuleSummary `json:"summary"`
+		} `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.EqualValues(t, 2, body.Meta.Total)
+	require.Len(t, body.Data, 2)
+	assert.True(t, body.Data[0].Blocked)
+	assert.Nil(t, body.Data[0].TransferID)
+	assert.False(t, body.Data[1].Blocked)
+	assert.NotNil(t, body.Data[1].TransferID)
+	assert.Equal(t, []models.RiskRuleSummary{{Rule: "large_transfer", Evaluated: 2, Flagged: 2, Blocked: 1}}, body.Meta.Summary)
+}
+
+func TestRiskHandler_Errors(t *testing.T) {
+	db := testfactory.NewDB(t)
+	risk := services.NewRiskService(repositories.NewNorthwindTransferRepository(db), repositories.NewRiskEvaluationRepository(db),
+		repositories.NewRiskRuleOverrideRepository(db), config.RiskConfig{}, nil, slog.Default())
+	handler := NewRiskHandler(risk)
+
+	c, rec := riskContext(http.MethodGet, "/api/v1/admin/risk/evaluations?blocked=maybe&user_id=42&from=yesterday", "")
+	require.NoError(t, handler.ListEvaluations(c))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	for _, param := range []string{"blocked", "user_id", "from"} {
+		assert.Contains(t, rec.Body.String(), param+":")
+	}
+
+	for _, tt := range []struct {
+		rule, body string
+		want       int
+	}{
+		{"unknown_rule", `{"mode":"enforce"}`, http.StatusNotFound},
+		{"large_transfer", `{"mode":"block"}`, http.StatusBadRequest},
+		{"large_transfer", `{}`, http.StatusBadRequest},
+	} {
+		c, rec := riskContext(http.MethodPut, "/", tt.body)
+		c.SetParamNames("rule")
+		c.SetParamValues(tt.rule)
+		require.NoError(t, handler.SetMode(c))
+		assert.Equal(t, tt.want, rec.Code, tt.rule+" "+tt.body)
+	}
+}
@@ -5,13 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/array/banking-api/internal/dto"
 	"github.com/array/banking-api/internal/errors"
 	"github.com/array/banking-api/internal/models"
 	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/services"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/shopspring/decimal"
@@ -27,6 +27,7 @@ const (
 type TransactionHandler struct {
 	transactionRepo repositories.TransactionRepositoryInterface
 	accountRepo     repositories.AccountRepositoryInterface
+	activity        *services.AccountActivityService
 }
 
 // NewTransactionHandler creates a new transaction handler
@@ -40,6 +41,11 @@ func NewTransactionHandler(
 	}
 }
 
+// SetActivityService enables the account activity feed
+func (h *TransactionHandler) SetActivityService(activity *services.AccountActivityService) {
+	h.activity = activity
+}
+
 // cursorData represents the data encoded in a pagination cursor
 type cursorData struct {
 	Timestamp     time.Time `json:"timestamp"`
@@ -102,6 +108,7 @@ func decodeCursor(cursor string) (time.Time, uuid.UUID, error) {
 // @Failure 401 {object} errors.ErrorResponse "AUTH_002 - Missing or invalid authentication"
 // @Failure 403 {object} errors.ErrorResponse "AUTH_005 - Account belongs to another user"
 // @Failure 404 {object} errors.ErrorResponse "ACCOUNT_001 - Account not found"
+// @Failure 422 {object} errors.ErrorResponse "VALIDATION_008 - Malformed or out-of-range limit, type, or status"
 // @Failure 500 {object} errors.ErrorResponse "SYSTEM_001 - Internal server error"
 // @Router /accounts/{accountId}/transactions [get]
 func (h *TransactionHandler) ListTransactions(c echo.Context) error {
@@ -128,15 +135,16 @@ func (h *TransactionHandler) ListTransactions(c echo.Context) error {
 		return SendError(c, errors.AuthInsufficientPermission)
 	}
 
-	filters, err := parseTransactionFilters(c)
+	q := newQueryParams(c)
+	filters, err := parseTransactionFilters(c, q)
 	if err != nil {
 		return SendError(c, errors.ValidationGeneral, errors.WithDetails(err.Error()))
 	}
 	filters.AccountID = accountID
 
-	pagination, err := parsePaginationParams(c)
-	if err != nil {
-		return SendError(c, errors.ValidationGeneral, errors.WithDetails(err.Error()))
+	pagination := parsePaginationParams(c, q)
+	if !q.Valid() {
+		return q.SendError()
 	}
 
 	if pagination.Cursor != "" {
@@ -148,7 +156,7 @@ func (h *TransactionHandler) ListTransactions(c echo.Context) error {
 		storeCursorTransactionIdForExclusion(c, cursorID)
 	}
 
-	transactions, total, err := h.transactionRepo.GetWithFilters(filters)
+	transactions, total, err := h.transactionRepo.GetWithFilters(c.Request().Context(), filters)
 	if err != nil {
 		return SendSystemError(c, err)
 	}
@@ -185,12 +193,87 @@ func (h *TransactionHandler) ListTransactions(c echo.Context) error {
 	return c.JSON(http.StatusOK, response)
 }
 
+// ListAccountActivity retrieves the account's activity feed
+// @Summary List account activity
+// @Description Retrieve the account's internal transactions and the NorthWind transfers sent from it as one newest-first feed with cursor-based pagination. Each item's type is transaction or northwind_transfer.
+// @Tags Transactions
+// @Security BearerAuth
+// @Produce json
+// @Param accountId path string true "Account ID (UUID)"
+// @Param cursor query string false "Pagination cursor for next page"
+// @Param limit query int false "Number of results per page (max 100)" default(20)
+// @Success 200 {object} dto.ListAccountActivityResponse "Account activity with pagination"
+// @Failure 400 {object} errors.ErrorResponse "VALIDATION_003 - Invalid account ID or cursor"
+// @Failure 401 {object} errors.ErrorResponse "AUTH_002 - Missing or invalid authentication"
+// @Failure 403 {object} errors.ErrorResponse "AUTH_005 - Account belongs to another user"
+// @Failure 404 {object} errors.ErrorResponse "ACCOUNT_001 - Account not found"
+// @Failure 422 {object} errors.ErrorResponse "VALIDATION_008 - Malformed or out-of-range limit"
+// @Failure 500 {object} errors.ErrorResponse "SYSTEM_001 - Internal server error"
+// @Router /accounts/{accountId}/activity [get]
+func (h *TransactionHandler) ListAccountActivity(c echo.Context) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return SendError(c, errors.AuthMissingToken)
+	}
+
+	accountID, err := uuid.Parse(c.Param("accountId"))
+	if err != nil {
+		return SendError(c, errors.ValidationInvalidFormat, errors.WithDetails("Invalid account ID"))
+	}
+
+	account, err := h.accountRepo.GetByID(accountID)
+	if err != nil {
+		if err == repositories.ErrAccountNotFound {
+			return SendError(c, errors.AccountNotFound)
+		}
+		return SendSystemError(c, err)
+	}
+
+	if account.UserID != userID {
+		return SendError(c, errors.AuthInsufficientPermission)
+	}
+
+	q := newQueryParams(c)
+	pagination := parsePaginationParams(c, q)
+	if !q.Valid() {
+		return q.SendError()
+	}
+
+	var after *models.AccountActivityKeyset
+	if pagination.Cursor != "" {
+		cursorTime, cursorID, err := decodeCursor(pagination.Cursor)
+		if err != nil {
+			return SendError(c, errors.ValidationInvalidFormat, errors.WithDetails("Invalid cursor"))
+		}
+		after = &models.AccountActivityKeyset{CreatedAt: cursorTime, ID: cursorID}
+	}
+
+	page, err := h.activity.GetActivity(c.Request().Context(), account, after, pagination.Limit)
+	if err != nil {
+		return SendSystemError(c, err)
+	}
+
+	response := dto.ListAccountActivityResponse{
+		Activity: page.Items,
+		Pagination: dto.PaginationInfo{
+			HasMore: page.Next != nil,
+			Limit:   pagination.Limit,
+		},
+	}
+	if page.Next != nil {
+		response.Pagination.NextCursor = encodeCursor(page.Next.CreatedAt, page.Next.ID)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
 func storeCursorTransactionIdForExclusion(c echo.Context, cursorID uuid.UUID) {
 	c.Set("cursorTransactionID", cursorID)
 }
 
-// parseTransactionFilters parses and validates transaction filter parameters
-func parseTransactionFilters(c echo.Context) (models.TransactionFilters, error) {
+// parseTransactionFilters parses and validates transaction filter parameters. The type and
+// status enums are bound through q; other malformed filters are returned as an error.
+func parseTransactionFilters(c echo.Context, q *queryParams) (models.TransactionFilters, error) {
 	filters := models.TransactionFilters{
 		Offset: 0,
 		Limit:  defaultPageLimit + 1, // Fetch one extra to determine if there's more
@@ -214,25 +297,10 @@ func parseTransactionFilters(c echo.Context) (models.TransactionFilters, error)
 		filters.EndDate = &endOfDay
 	}
 
-	if txnType := c.QueryParam("type"); txnType != "" {
-		if txnType != models.TransactionTypeCredit && txnType != models.TransactionTypeDebit {
-			return filters, fmt.Errorf("invalid type, must be 'credit' or 'debit'")
-		}
-		filters.Type = txnType
-	}
-
-	if status := c.QueryParam("status"); status != "" {
-		validStatuses := map[string]bool{
-			models.TransactionStatusPending:   true,
-			models.TransactionStatusCompleted: true,
-			models.TransactionStatusFailed:    true,
-			models.TransactionStatusReversed:  true,
-		}
-		if !validStatuses[status] {
-			return filters, fmt.Errorf("invalid status")
-		}
-		filters.Status = status
-	}
+	filters.Type = q.Enum("type", models.TransactionTypeCredit, models.TransactionTypeDebit)
+	filters.Status = q.Enum("status",
+		models.TransactionStatusPending, models.TransactionStatusCompleted,
+		models.TransactionStatusFailed, models.TransactionStatusReversed)
 
 	if category := c.QueryParam("category"); category != "" {
 		if !models.IsValidCategory(category) {
@@ -264,34 +332,12 @@ func parseTransactionFilters(c echo.Context) (models.TransactionFilters, error)
 	return filters, nil
 }
 
-// parsePaginationParams parses pagination parameters from query string
-func parsePaginationParams(c echo.Context) (dto.PaginationParams, error) {
-	params := dto.PaginationParams{
-		Limit: defaultPageLimit,
-	}
-
-	if cursor := c.QueryParam("cursor"); cursor != "" {
-		params.Cursor = cursor
-	}
-
-	if limitStr := c.QueryParam("limit"); limitStr != "" {
-		limit, err := strconv.Atoi(limitStr)
-		if err != nil {
-			return params, fmt.Errorf("invalid limit parameter")
-		}
-
-		if limit < 1 {
-			return params, fmt.Errorf("limit must be at least 1")
-		}
-
-		if limit > maxPageLimit {
-			limit = maxPageLimit
-		}
-
-		params.Limit = limit
+// parsePaginationParams parses pagination parameters from query string, binding limit through q
+func parsePaginationParams(c echo.Context, q *queryParams) dto.PaginationParams {
+	return dto.PaginationParams{
+		Cursor: c.QueryParam("cursor"),
+		Limit:  q.Limit(),
 	}
-
-	return params, nil
 }
 
 // convertToTransactionWithBalance converts transaction models to DTOs with running balance
@@ -365,7 +411,7 @@ func (h *TransactionHandler) GetTransaction(c echo.Context) error {
 		return SendError(c, errors.ValidationGeneral, errors.WithDetails("Transaction ID must be a valid UUID"))
 	}
 
-	transaction, err := h.transactionRepo.GetByID(transactionID)
+	transaction, err := h.transactionRepo.GetByID(c.Request().Context(), transactionID)
 	if err != nil {
 		if err == repositories.ErrTransactionNotFound {
 			return SendError(c, errors.TransactionNotFound)
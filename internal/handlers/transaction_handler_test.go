@@ -13,6 +13,7 @@ import (
 	"github.com/array/banking-api/internal/models"
 	"github.com/array/banking-api/internal/repositories"
 	"github.com/array/banking-api/internal/repositories/repository_mocks"
+	"github.com/array/banking-api/internal/services"
 	"github.com/brianvoe/gofakeit/v7"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
@@ -128,7 +129,7 @@ func (s *TransactionHandlerTestSuite) TestListTransactions_FirstPage() {
 		Return(account, nil)
 
 	s.mockTransactionRepo.EXPECT().
-		GetWithFilters(gomock.Any()).
+		GetWithFilters(gomock.Any(), gomock.Any()).
 		Return(transactions, int64(21), nil)
 
 	// Create request
@@ -206,7 +207,7 @@ func (s *TransactionHandlerTestSuite) TestListTransactions_WithCursor() {
 		Return(account, nil)
 
 	s.mockTransactionRepo.EXPECT().
-		GetWithFilters(gomock.Any()).
+		GetWithFilters(gomock.Any(), gomock.Any()).
 		Return(transactions, int64(30), nil)
 
 	// Create cursor
@@ -262,7 +263,7 @@ func (s *TransactionHandlerTestSuite) TestListTransactions_EmptyResults() {
 		Return(account, nil)
 
 	s.mockTransactionRepo.EXPECT().
-		GetWithFilters(gomock.Any()).
+		GetWithFilters(gomock.Any(), gomock.Any()).
 		Return([]models.Transaction{}, int64(0), nil)
 
 	// Create request
@@ -674,7 +675,7 @@ func (s *TransactionHandlerTestSuite) TestGetTransaction_Success() {
 		BalanceAfter:    decimal.NewFromFloat(950),
 	}
 	s.mockAccountRepo.EXPECT().GetByID(s.accountID).Return(account, nil)
-	s.mockTransactionRepo.EXPECT().GetByID(transactionID).Return(transaction, nil)
+	s.mockTransactionRepo.EXPECT().GetByID(gomock.Any(), transactionID).Return(transaction, nil)
 
 	handler := NewTransactionHandler(s.mockTransactionRepo, s.mockAccountRepo)
 	url := fmt.Sprintf("/api/v1/accounts/%s/transactions/%s", s.accountID, transactionID)
@@ -753,7 +754,7 @@ func (s *TransactionHandlerTestSuite) TestGetTransaction_TransactionNotFound() {
 	}
 	txID := uuid.New()
 	s.mockAccountRepo.EXPECT().GetByID(s.accountID).Return(account, nil)
-	s.mockTransactionRepo.EXPECT().GetByID(txID).Return(nil, repositories.ErrTransactionNotFound)
+	s.mockTransactionRepo.EXPECT().GetByID(gomock.Any(), txID).Return(nil, repositories.ErrTransactionNotFound)
 
 	handler := NewTransactionHandler(s.mockTransactionRepo, s.mockAccountRepo)
 	url := fmt.Sprintf("/api/v1/accounts/%s/transactions/%s", s.accountID, txID)
@@ -792,3 +793,155 @@ func (s *TransactionHandlerTestSuite) TestGetTransaction_ForbiddenAccount() {
 	s.NoError(err)
 	s.Equal(http.StatusForbidden, rec.Code)
 }
+
+// Query Validation Tests
+
+func (s *TransactionHandlerTestSuite) listTransactionsWithQuery(query string) *httptest.ResponseRecorder {
+	handler := NewTransactionHandler(s.mockTransactionRepo, s.mockAccountRepo)
+	s.mockAccountRepo.EXPECT().GetByID(s.accountID).
+		Return(&models.Account{ID: s.accountID, UserID: s.userID}, nil)
+
+	url := fmt.Sprintf("/api/v1/accounts/%s/transactions?%s", s.accountID, query)
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	rec := httptest.NewRecorder()
+	c := s.echo.NewContext(req, rec)
+	c.SetParamNames("accountId")
+	c.SetParamValues(s.accountID.String())
+	c.Set("user_id", s.userID)
+
+	s.NoError(handler.ListTransactions(c))
+	return rec
+}
+
+func (s *TransactionHandlerTestSuite) TestListTransactions_QueryValidation() {
+	testCases := []struct {
+		name      string
+		query     string
+		status    int
+		wantLimit int
+		detail    string
+	}{
+		{"absent", "", http.StatusOK, 20, ""},
+		{"valid limit", "limit=50", http.StatusOK, 50, ""},
+		{"valid enums", "type=credit&status=completed", http.StatusOK, 20, ""},
+		{"malformed limit", "limit=abc", http.StatusUnprocessableEntity, 0, "limit: must be an integer"},
+		{"limit over max", "limit=200", http.StatusUnprocessableEntity, 0, "limit: must be at most 100"},
+		{"zero limit", "limit=0", http.StatusUnprocessableEntity, 0, "limit: must be at least 1"},
+		{"unknown type", "type=invalid", http.StatusUnprocessableEntity, 0, "type: must be one of credit, debit"},
+		{"unknown status", "status=done", http.StatusUnprocessableEntity, 0, "status: must be one of pending"},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			if tc.status == http.StatusOK {
+				s.mockTransactionRepo.EXPECT().GetWithFilters(gomock.Any(), gomock.Any()).
+					Return([]models.Transaction{}, int64(0), nil)
+			}
+
+			rec := s.listTransactionsWithQuery(tc.query)
+
+			s.Equal(tc.status, rec.Code, rec.Body.String())
+			if tc.status != http.StatusOK {
+				s.Contains(rec.Body.String(), tc.detail)
+				return
+			}
+			var response dto.ListTransactionsResponse
+			s.NoError(json.Unmarshal(rec.Body.Bytes(), &response))
+			s.Equal(tc.wantLimit, response.Pagination.Limit)
+		})
+	}
+}
+
+// Account Activity Tests
+
+func (s *TransactionHandlerTestSuite) listActivity(handler *TransactionHandler, query string) *httptest.ResponseRecorder {
+	url := fmt.Sprintf("/api/v1/accounts/%s/activity?%s", s.accountID, query)
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	rec := httptest.NewRecorder()
+	c := s.echo.NewContext(req, rec)
+	c.SetParamNames("accountId")
+	c.SetParamValues(s.accountID.String())
+	c.Set("user_id", s.userID)
+
+	s.NoError(handler.ListAccountActivity(c))
+	return rec
+}
+
+func (s *TransactionHandlerTestSuite) activityHandler(transferRepo repositories.NorthwindTransferRepositoryInterface) *TransactionHandler {
+	handler := NewTransactionHandler(s.mockTransactionRepo, s.mockAccountRepo)
+	handler.SetActivityService(services.NewAccountActivityService(s.mockTransactionRepo, transferRepo))
+	return handler
+}
+
+func (s *TransactionHandlerTestSuite) TestListAccountActivity_MergesAndResumesFromCursor() {
+	mockTransferRepo := repository_mocks.NewMockNorthwindTransferRepositoryInterface(s.ctrl)
+	handler := s.activityHandler(mockTransferRepo)
+	account := &models.Account{ID: s.accountID, UserID: s.userID, AccountNumber: "1234567890"}
+	s.mockAccountRepo.EXPECT().GetByID(s.accountID).Return(account, nil).Times(2)
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	transactions := []models.Transaction{
+		{ID: uuid.New(), AccountID: s.accountID, CreatedAt: now},
+		{ID: uuid.New(), AccountID: s.accountID, CreatedAt: now.Add(-2 * time.Minute)},
+	}
+	newest, oldest := &transactions[0], &transactions[1]
+	middle := models.NorthwindTransfer{ID: uuid.New(), UserID: &s.userID, SourceAccountNumber: account.AccountNumber, CreatedAt: now.Add(-time.Minute)}
+
+	s.mockTransactionRepo.EXPECT().GetByAccountIDKeyset(gomock.Any(), s.accountID, (*models.AccountActivityKeyset)(nil), 3).
+		Return(transactions, nil)
+	mockTransferRepo.EXPECT().GetBySourceAccountKeyset(gomock.Any(), s.userID, account.AccountNumber, (*models.AccountActivityKeyset)(nil), 3).
+		Return([]models.NorthwindTransfer{middle}, nil)
+
+	rec := s.listActivity(handler, "limit=2")
+	s.Equal(http.StatusOK, rec.Code, rec.Body.String())
+	var first dto.ListAccountActivityResponse
+	s.NoError(json.Unmarshal(rec.Body.Bytes(), &first))
+	s.Require().Len(first.Activity, 2)
+	s.Equal(models.AccountActivityTypeTransaction, first.Activity[0].Type)
+	s.Equal(newest.ID, first.Activity[0].ID)
+	s.Equal(models.AccountActivityTypeNorthwindTransfer, first.Activity[1].Type)
+	s.Equal(middle.ID, first.Activity[1].ID)
+	s.True(first.Pagination.HasMore)
+	s.Require().NotEmpty(first.Pagination.NextCursor)
+
+	// The second page resumes both sources from the last item of the first
+	resume := &models.AccountActivityKeyset{CreatedAt: middle.CreatedAt, ID: middle.ID}
+	s.mockTransactionRepo.EXPECT().GetByAccountIDKeyset(gomock.Any(), s.accountID, gomock.Eq(resume), 3).
+		Return(transactions[1:], nil)
+	mockTransferRepo.EXPECT().GetBySourceAccountKeyset(gomock.Any(), s.userID, account.AccountNumber, gomock.Eq(resume), 3).
+		Return([]models.NorthwindTransfer{}, nil)
+
+	rec = s.listActivity(handler, "limit=2&cursor="+first.Pagination.NextCursor)
+	s.Equal(http.StatusOK, rec.Code, rec.Body.String())
+	var second dto.ListAccountActivityResponse
+	s.NoError(json.Unmarshal(rec.Body.Bytes(), &second))
+	s.Require().Len(second.Activity, 1)
+	s.Equal(oldest.ID, second.Activity[0].ID)
+	s.False(second.Pagination.HasMore)
+	s.Empty(second.Pagination.NextCursor)
+}
+
+func (s *TransactionHandlerTestSuite) TestListAccountActivity_ForbiddenAccount() {
+	mockTransferRepo := repository_mocks.NewMockNorthwindTransferRepositoryInterface(s.ctrl)
+	s.mockAccountRepo.EXPECT().GetByID(s.accountID).
+		Return(&models.Account{ID: s.accountID, UserID: uuid.New(), AccountNumber: "1234567890"}, nil)
+
+	rec := s.listActivity(s.activityHandler(mockTransferRepo), "")
+	s.Equal(http.StatusForbidden, rec.Code)
+	s.Contains(rec.Body.String(), "AUTH_005")
+}
+
+func (s *TransactionHandlerTestSuite) TestListAccountActivity_AccountNotFound() {
+	s.mockAccountRepo.EXPECT().GetByID(s.accountID).Return(nil, repositories.ErrAccountNotFound)
+
+	rec := s.listActivity(s.activityHandler(repository_mocks.NewMockNorthwindTransferRepositoryInterface(s.ctrl)), "")
+	s.Equal(http.StatusNotFound, rec.Code)
+}
+
+func (s *TransactionHandlerTestSuite) TestListAccountActivity_InvalidCursor() {
+	s.mockAccountRepo.EXPECT().GetByID(s.accountID).Return(&models.Account{ID: s.accountID, UserID: s.userID}, nil)
+
+	rec := s.listActivity(s.activityHandler(repository_mocks.NewMockNorthwindTransferRepositoryInterface(s.ctrl)), "cursor=not-base64!!!")
+	s.Equal(http.StatusBadRequest, rec.Code)
+	s.Contains(rec.Body.String(), "Invalid cursor")
+}
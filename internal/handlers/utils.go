@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/validation"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
@@ -44,6 +45,16 @@ func getIsAdminFromContext(c echo.Context) bool {
 	return isAdmin
 }
 
+// validateRequest validates req with the request's context, carrying whether the caller is an
+// admin, so context-aware rules apply. Validators that do not take a context validate it plainly.
+func validateRequest(c echo.Context, req interface{}) error {
+	if v, ok := c.Echo().Validator.(validation.ContextValidator); ok {
+		ctx := validation.WithRequester(c.Request().Context(), validation.Requester{IsAdmin: getIsAdminFromContext(c)})
+		return v.ValidateCtx(ctx, req)
+	}
+	return c.Validate(req)
+}
+
 func (h *AdminHandler) createAuditLog(adminID uuid.UUID, action, targetUserID string, c echo.Context) {
 	m := models.JSONBMap{
 		"target_user_id": targetUserID,
@@ -64,20 +75,6 @@ func (h *AdminHandler) createAuditLog(adminID uuid.UUID, action, targetUserID st
 	}
 }
 
-func getIntParam(c echo.Context, name string, defaultValue int) int {
-	param := c.QueryParam(name)
-	if param == "" {
-		return defaultValue
-	}
-
-	var value int
-	if _, err := fmt.Sscanf(param, "%d", &value); err != nil {
-		return defaultValue
-	}
-
-	return value
-}
-
 func getClientIP(c echo.Context) string {
 	xff := c.Request().Header.Get("X-Forwarded-For")
 	if xff != "" {
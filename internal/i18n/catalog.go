@@ -0,0 +1,193 @@
+// Package i18n translates the messages the API returns to clients.
+//
+// Handlers refer to messages by key; the text for each locale comes from a JSON catalog embedded
+// from locales/{locale}.json. A key missing from a locale's catalog falls back to English, so a
+// new message only has to be added to en.json to ship. Error messages are looked up under
+// "errors.{code}" and fall back to the error registry's English message.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is the locale every catalog falls back to
+const DefaultLocale = "en"
+
+// errorKeyPrefix namespaces error codes in the catalogs
+const errorKeyPrefix = "errors."
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// Catalog holds the messages of every supported locale
+type Catalog struct {
+	messages map[string]map[string]string
+}
+
+var (
+	defaultCatalog     *Catalog
+	defaultCatalogErr  error
+	defaultCatalogOnce sync.Once
+)
+
+// Default returns the catalog built from the embedded locale files. The files are part of the
+// binary, so a malformed one is a build defect and panics.
+func Default() *Catalog {
+	defaultCatalogOnce.Do(func() {
+		defaultCatalog, defaultCatalogErr = Load()
+	})
+	if defaultCatalogErr != nil {
+		panic(defaultCatalogErr)
+	}
+	return defaultCatalog
+}
+
+// Load parses the embedded locale files into a catalog
+func Load() (*Catalog, error) {
+	entries, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read locale catalogs: %w", err)
+	}
+	catalog := &Catalog{messages: make(map[string]map[string]string, len(entries))}
+	for _, entry := range entries {
+		raw, err := localeFiles.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read locale catalog %s: %w", entry.Name(), err)
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(raw, &messages); err != nil {
+			return nil, fmt.Errorf("invalid locale catalog %s: %w", entry.Name(), err)
+		}
+		catalog.messages[strings.TrimSuffix(entry.Name(), path.Ext(entry.Name()))] = messages
+	}
+	if _, ok := catalog.messages[DefaultLocale]; !ok {
+		return nil, fmt.Errorf("locale catalog %s.json is missing", DefaultLocale)
+	}
+	return catalog, nil
+}
+
+// Locales returns the supported locales in sorted order
+func (c *Catalog) Locales() []string {
+	locales := make([]string, 0, len(c.messages))
+	for locale := range c.messages {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Supports reports whether the catalog has messages for locale
+func (c *Catalog) Supports(locale string) bool {
+	_, ok := c.messages[strings.ToLower(locale)]
+	return ok
+}
+
+// Negotiate picks the supported locale the Accept-Language header prefers, matching a regional
+// tag such as fr-CA to its language when the region has no catalog of its own. It returns
+// fallback when the header names no supported locale.
+func (c *Catalog) Negotiate(acceptLanguage, fallback string) string {
+	best, bestQ := fallback, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, q := parseLanguageRange(part)
+		if tag == "" || q <= bestQ {
+			continue
+		}
+		if locale, ok := c.match(tag); ok {
+			best, bestQ = locale, q
+		}
+	}
+	return best
+}
+
+// match finds the catalog for a language tag, trying the full tag and then its language
+func (c *Catalog) match(tag string) (string, bool) {
+	if c.Supports(tag) {
+		return tag, true
+	}
+	if language, _, ok := strings.Cut(tag, "-"); ok && c.Supports(language) {
+		return language, true
+	}
+	return "", false
+}
+
+// parseLanguageRange parses one Accept-Language entry such as "fr-CA;q=0.8" into a lowercase
+// tag and its quality; a wildcard or malformed entry has an empty tag
+func parseLanguageRange(part string) (string, float64) {
+	tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+	tag = strings.ToLower(strings.TrimSpace(strings.ReplaceAll(tag, "_", "-")))
+	if tag == "" || tag == "*" {
+		return "", 0
+	}
+	q := 1.0
+	for _, param := range strings.Split(params, ";") {
+		name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || strings.TrimSpace(name) != "q" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || parsed < 0 || parsed > 1 {
+			return "", 0
+		}
+		q = parsed
+	}
+	return tag, q
+}
+
+// Localizer returns the messages of locale, falling back to English; an unsupported locale gets
+// English throughout
+func (c *Catalog) Localizer(locale string) *Localizer {
+	locale = strings.ToLower(locale)
+	if !c.Supports(locale) {
+		locale = DefaultLocale
+	}
+	return &Localizer{catalog: c, locale: locale}
+}
+
+// Localizer translates messages into one locale
+type Localizer struct {
+	catalog *Catalog
+	locale  string
+}
+
+// Locale returns the locale messages are translated into
+func (l *Localizer) Locale() string {
+	return l.locale
+}
+
+// Message returns the message for key formatted with args, in the localizer's locale or else
+// in English. A key in no catalog is returned as it is, so a missing message shows up in review
+// rather than as an empty string.
+func (l *Localizer) Message(key string, args ...interface{}) string {
+	message, ok := l.lookup(key)
+	if !ok {
+		return key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
+
+// ErrorMessage returns the message for an error code, or fallback, the registry's English
+// message, when no catalog translates the code
+func (l *Localizer) ErrorMessage(code, fallback string) string {
+	if message, ok := l.lookup(errorKeyPrefix + code); ok {
+		return message
+	}
+	return fallback
+}
+
+func (l *Localizer) lookup(key string) (string, bool) {
+	if message, ok := l.catalog.messages[l.locale][key]; ok {
+		return message, true
+	}
+	message, ok := l.catalog.messages[DefaultLocale][key]
+	return message, ok
+}
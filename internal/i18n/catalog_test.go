@@ -0,0 +1,82 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDefault_CatalogsAreConsistent(t *testing.T) {
+	catalog := Default()
+	if got := catalog.Locales(); strings.Join(got, ",") != "en,fr" {
+		t.Fatalf("expected locales en,fr, got %v", got)
+	}
+	english := catalog.messages[DefaultLocale]
+	for _, locale := range catalog.Locales() {
+		for key, message := range catalog.messages[locale] {
+			if strings.HasPrefix(key, errorKeyPrefix) {
+				continue
+			}
+			source, ok := english[key]
+			if !ok {
+				t.Errorf("%s: key %q has no English message to fall back to", locale, key)
+				continue
+			}
+			if strings.Count(message, "%") != strings.Count(source, "%") {
+				t.Errorf("%s: %q takes different arguments than the English message", locale, key)
+			}
+		}
+	}
+}
+
+func TestCatalog_Negotiate(t *testing.T) {
+	catalog := Default()
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", "en"},
+		{"fr", "fr"},
+		{"fr-CA", "fr"},
+		{"FR_ca", "fr"},
+		{"de-DE,fr-CA;q=0.8,en;q=0.5", "fr"},
+		{"en;q=0.9,fr;q=0.8", "en"},
+		{"fr;q=0.2,en-US;q=0.7", "en"},
+		{"de,*;q=0.5", "en"},
+		{"fr;q=abc", "en"},
+	}
+	for _, tt := range tests {
+		if got := catalog.Negotiate(tt.header, DefaultLocale); got != tt.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+	if got := catalog.Negotiate("de", "fr"); got != "fr" {
+		t.Errorf("expected the configured default for an unsupported language, got %q", got)
+	}
+}
+
+func TestLocalizer_FallsBackToEnglish(t *testing.T) {
+	catalog := &Catalog{messages: map[string]map[string]string{
+		"en": {"greeting": "Hello %s", "farewell": "Goodbye"},
+		"fr": {"greeting": "Bonjour %s", "errors.TEST_001": "Erreur de test"},
+	}}
+	fr := catalog.Localizer("fr")
+
+	if got := fr.Message("greeting", "Chloé"); got != "Bonjour Chloé" {
+		t.Errorf("expected the French message, got %q", got)
+	}
+	if got := fr.Message("farewell"); got != "Goodbye" {
+		t.Errorf("expected an untranslated key to fall back to English, got %q", got)
+	}
+	if got := fr.Message("missing.key"); got != "missing.key" {
+		t.Errorf("expected an unknown key to be returned as it is, got %q", got)
+	}
+	if got := fr.ErrorMessage("TEST_001", "Test error"); got != "Erreur de test" {
+		t.Errorf("expected the French error message, got %q", got)
+	}
+	if got := fr.ErrorMessage("TEST_002", "Other error"); got != "Other error" {
+		t.Errorf("expected the registry message for an untranslated code, got %q", got)
+	}
+	if got := catalog.Localizer("de").Locale(); got != DefaultLocale {
+		t.Errorf("expected an unsupported locale to use English, got %q", got)
+	}
+}
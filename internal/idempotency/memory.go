@@ -0,0 +1,79 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	response  []byte
+	completed bool
+	expiresAt time.Time
+}
+
+// MemoryStore is a process-local Store. It is only correct with a single API instance.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+	now     func() time.Time
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]*memoryEntry),
+		now:     time.Now,
+	}
+}
+
+// Claim reserves key for ttl unless a live entry already exists
+func (s *MemoryStore) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if e, ok := s.entries[key]; ok && now.Before(e.expiresAt) {
+		return false, nil
+	}
+	s.entries[key] = &memoryEntry{expiresAt: now.Add(ttl)}
+	return true, nil
+}
+
+// Complete stores the response for key
+func (s *MemoryStore) Complete(ctx context.Context, key string, response []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = &memoryEntry{
+		response:  append([]byte(nil), response...),
+		completed: true,
+		expiresAt: s.now().Add(ttl),
+	}
+	return nil
+}
+
+// Get returns the completed response for key, if any
+func (s *MemoryStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok || !s.now().Before(e.expiresAt) {
+		delete(s.entries, key)
+		return nil, false, nil
+	}
+	if !e.completed {
+		return nil, false, nil
+	}
+	return append([]byte(nil), e.response...), true, nil
+}
+
+// Release removes key
+func (s *MemoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
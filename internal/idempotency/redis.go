@@ -0,0 +1,78 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisKeyPrefix = "idempotency:"
+
+	// Stored values are prefixed so an in-flight claim is distinguishable from an empty response
+	pendingMarker   = "P"
+	completedMarker = "D"
+)
+
+// RedisStore is a Store shared by every API instance through Redis
+type RedisStore struct {
+	client *redis.Client
+	logger *slog.Logger
+}
+
+// NewRedisStore creates a Redis-backed idempotency store
+func NewRedisStore(client *redis.Client, logger *slog.Logger) *RedisStore {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &RedisStore{client: client, logger: logger}
+}
+
+// Claim reserves key with SET NX so that exactly one caller wins
+func (s *RedisStore) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	ok, err := s.client.SetNX(ctx, redisKeyPrefix+key, pendingMarker, ttl).Result()
+	if err != nil {
+		return false, s.unavailable("claim", key, err)
+	}
+	return ok, nil
+}
+
+// Complete replaces the claim with the stored response
+func (s *RedisStore) Complete(ctx context.Context, key string, response []byte, ttl time.Duration) error {
+	if err := s.client.Set(ctx, redisKeyPrefix+key, completedMarker+string(response), ttl).Err(); err != nil {
+		return s.unavailable("complete", key, err)
+	}
+	return nil
+}
+
+// Get returns the completed response for key, if any
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	val, err := s.client.Get(ctx, redisKeyPrefix+key).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, s.unavailable("get", key, err)
+	}
+	if len(val) == 0 || val[:1] != completedMarker {
+		return nil, false, nil
+	}
+	return []byte(val[1:]), true, nil
+}
+
+// Release deletes key
+func (s *RedisStore) Release(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, redisKeyPrefix+key).Err(); err != nil {
+		return s.unavailable("release", key, err)
+	}
+	return nil
+}
+
+func (s *RedisStore) unavailable(op, key string, err error) error {
+	s.logger.Error("Idempotency store unavailable, rejecting request", "op", op, "key", key, "error", err)
+	return fmt.Errorf("%w: %s: %v", ErrStoreUnavailable, op, err)
+}
@@ -0,0 +1,138 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedisStore(t *testing.T) (*RedisStore, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewRedisStore(client, nil), mr
+}
+
+// storeContract runs the behaviour every Store implementation must share
+func storeContract(t *testing.T, store Store) {
+	ctx := context.Background()
+
+	claimed, err := store.Claim(ctx, "key-1", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+
+	claimed, err = store.Claim(ctx, "key-1", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, claimed, "second claim must lose")
+
+	_, found, err := store.Get(ctx, "key-1")
+	require.NoError(t, err)
+	assert.False(t, found, "in-flight key has no response")
+
+	require.NoError(t, store.Complete(ctx, "key-1", []byte(`{"ok":true}`), time.Minute))
+	resp, found, err := store.Get(ctx, "key-1")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, `{"ok":true}`, string(resp))
+
+	claimed, err = store.Claim(ctx, "key-2", time.Minute)
+	require.NoError(t, err)
+	require.True(t, claimed)
+	require.NoError(t, store.Release(ctx, "key-2"))
+	claimed, err = store.Claim(ctx, "key-2", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, claimed, "released key can be claimed again")
+}
+
+func TestMemoryStore_Contract(t *testing.T) {
+	storeContract(t, NewMemoryStore())
+}
+
+func TestRedisStore_Contract(t *testing.T) {
+	store, _ := newTestRedisStore(t)
+	storeContract(t, store)
+}
+
+func TestRedisStore_ClaimIsAtomic(t *testing.T) {
+	store, _ := newTestRedisStore(t)
+
+	var winners int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			claimed, err := store.Claim(context.Background(), "contended", time.Minute)
+			if err == nil && claimed {
+				atomic.AddInt32(&winners, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), winners, "exactly one concurrent claim may win")
+}
+
+func TestRedisStore_ClaimExpiresAfterTTL(t *testing.T) {
+	store, mr := newTestRedisStore(t)
+	ctx := context.Background()
+
+	claimed, err := store.Claim(ctx, "key", 30*time.Second)
+	require.NoError(t, err)
+	require.True(t, claimed)
+
+	mr.FastForward(31 * time.Second)
+
+	claimed, err = store.Claim(ctx, "key", 30*time.Second)
+	require.NoError(t, err)
+	assert.True(t, claimed, "expired claim can be taken again")
+}
+
+func TestRedisStore_CompletedResponseExpiresAfterTTL(t *testing.T) {
+	store, mr := newTestRedisStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.Complete(ctx, "key", []byte("resp"), time.Minute))
+	mr.FastForward(time.Minute + time.Second)
+
+	_, found, err := store.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestRedisStore_FailsClosedWhenRedisUnavailable(t *testing.T) {
+	store, mr := newTestRedisStore(t)
+	mr.Close()
+	ctx := context.Background()
+
+	claimed, err := store.Claim(ctx, "key", time.Minute)
+	assert.False(t, claimed, "idempotency must fail closed")
+	assert.True(t, errors.Is(err, ErrStoreUnavailable))
+
+	_, found, err := store.Get(ctx, "key")
+	assert.False(t, found)
+	assert.True(t, errors.Is(err, ErrStoreUnavailable))
+}
+
+func TestMemoryStore_ClaimExpiresAfterTTL(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	claimed, _ := store.Claim(ctx, "key", 30*time.Second)
+	require.True(t, claimed)
+
+	now = now.Add(31 * time.Second)
+	claimed, _ = store.Claim(ctx, "key", 30*time.Second)
+	assert.True(t, claimed)
+}
@@ -0,0 +1,26 @@
+// Package idempotency defines the storage contract for idempotency keys so that a retried
+// request is recognised no matter which API instance receives it.
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrStoreUnavailable is returned when the backing store cannot be reached. Idempotency
+// fails closed: callers must reject the request rather than risk executing it twice.
+var ErrStoreUnavailable = errors.New("idempotency store unavailable")
+
+// Store records which idempotency keys are in flight or completed.
+type Store interface {
+	// Claim atomically reserves key for ttl. It returns false if the key is already
+	// claimed or completed.
+	Claim(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Complete stores the response for a claimed key, keeping it for ttl.
+	Complete(ctx context.Context, key string, response []byte, ttl time.Duration) error
+	// Get returns the stored response. found is false when the key is unknown or still in flight.
+	Get(ctx context.Context, key string) (response []byte, found bool, err error)
+	// Release drops a claim so that the operation can be retried.
+	Release(ctx context.Context, key string) error
+}
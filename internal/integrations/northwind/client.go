@@ -4,27 +4,114 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"mime"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultTimeout bounds each request attempt unless WithHTTPClient or WithTimeout says otherwise
+	DefaultTimeout = 10 * time.Second
+	// DefaultMaxRetryDuration caps the total time doRequest spends retrying one call
+	DefaultMaxRetryDuration = 30 * time.Second
+	// minRetryBackoff replaces a non-positive initial backoff so retries never fire back-to-back
+	minRetryBackoff = 100 * time.Millisecond
+	// maxRetryBackoff caps the delay before any single retry
+	maxRetryBackoff = 10 * time.Second
+	// contentTypeSnippetLen caps how much of a non-JSON response body a ContentTypeError keeps
+	contentTypeSnippetLen = 200
+)
+
+var (
+	// ErrUnexpectedContentType is wrapped by ContentTypeError, for a successful response whose
+	// body is not JSON, such as a proxy's HTML maintenance page
+	ErrUnexpectedContentType = errors.New("northwind response is not JSON")
+	// ErrEmptyResponse is returned when a response that should carry a JSON body has none
+	ErrEmptyResponse = errors.New("northwind response has no body")
+)
+
+// APIKeyID identifies which configured API key authenticated a request
+type APIKeyID string
+
+const (
+	APIKeyPrimary   APIKeyID = "primary"
+	APIKeySecondary APIKeyID = "secondary"
 )
 
 // Client is the NorthWind Bank API client
 type Client struct {
 	baseURL             string
 	apiKey              string
+	fallbackAPIKey      string
+	apiKeyHook          func(APIKeyID)
+	activeAPIKey        atomic.Value // APIKeyID of the last accepted request
 	httpClient          *http.Client
+	timeout             time.Duration
 	maxRetries          int
 	retryInitialBackoff time.Duration
+	retryJitter         func(seconds float64) float64
+	maxRetryDuration    time.Duration
+	logger              *slog.Logger
+	testing             bool
+	strictDecoding      bool
+	schemaDriftHook     func(SchemaDrift)
+	idempotencyKeyFunc  IdempotencyKeyFunc
+	metricsHook         MetricsHook
+	quota               atomic.Pointer[Quota]
+	stats               statsRecorder
+	maxListPages        int
+
+	domainsMu    sync.Mutex
+	domainsCache domainsCacheEntry
+}
+
+// domainsCacheEntry holds the last /domains payload with its HTTP validators
+type domainsCacheEntry struct {
+	domains      []Domain
+	etag         string
+	lastModified string
 }
 
+// ErrInvalidBaseURL is returned by NewClientValidated when the base URL is not an absolute
+// http(s) URL
+var ErrInvalidBaseURL = errors.New("northwind base URL must be an absolute http or https URL")
+
 // ClientOption configures the NorthWind client
 type ClientOption func(*Client)
 
-// WithRetry enables retries with exponential backoff
+// WithHTTPClient sends requests with client, e.g. one whose transport presents a TLS client
+// certificate. As with the regulator service, a nil client keeps the default.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(c *Client) {
+		if client != nil {
+			c.httpClient = client
+		}
+	}
+}
+
+// WithTimeout bounds each request attempt, overriding the timeout of the default client or of
+// the one given with WithHTTPClient, which is copied rather than changed. Zero or negative keeps
+// the client's own timeout.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithRetry enables retries with exponential backoff. A negative maxRetries disables retries and
+// a non-positive initialBackoffMs is raised to 100ms; both are logged when the client is created.
 func WithRetry(maxRetries int, initialBackoffMs int) ClientOption {
 	return func(c *Client) {
 		c.maxRetries = maxRetries
@@ -32,128 +119,501 @@ func WithRetry(maxRetries int, initialBackoffMs int) ClientOption {
 	}
 }
 
-// NewClient creates a new NorthWind API client
+// WithRetryJitter spreads each retry backoff with jitter, which takes a backoff in seconds and
+// returns the jittered backoff. The result is still capped at the maximum backoff. Backoffs are
+// not jittered by default.
+func WithRetryJitter(jitter func(seconds float64) float64) ClientOption {
+	return func(c *Client) {
+		c.retryJitter = jitter
+	}
+}
+
+// WithMaxRetryDuration caps the total time spent on one call, including backoff; no retry is
+// started that would end past it. Non-positive values keep DefaultMaxRetryDuration.
+func WithMaxRetryDuration(d time.Duration) ClientOption {
+	return func(c *Client) {
+		if d > 0 {
+			c.maxRetryDuration = d
+		}
+	}
+}
+
+// WithLogger sets the logger used for configuration warnings; slog.Default() otherwise
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		if logger != nil {
+			c.logger = logger
+		}
+	}
+}
+
+// WithFallbackAPIKey sets a secondary API key for rotation. Requests use the primary key; one
+// rejected with 401 is sent once more with the secondary key. An empty key disables the fallback.
+func WithFallbackAPIKey(apiKey string) ClientOption {
+	return func(c *Client) {
+		c.fallbackAPIKey = apiKey
+	}
+}
+
+// WithAPIKeyHook registers hook, called after every request NorthWind accepts with the key that
+// authenticated it, for per-key metrics
+func WithAPIKeyHook(hook func(APIKeyID)) ClientOption {
+	return func(c *Client) {
+		c.apiKeyHook = hook
+	}
+}
+
+// WithStrictDecoding makes the client check every response for fields our models lack. Results
+// are still decoded leniently, so NorthWind adding or renaming a field never fails a call; the
+// drift is logged and passed to the WithSchemaDriftHook hook.
+func WithStrictDecoding(strict bool) ClientOption {
+	return func(c *Client) {
+		c.strictDecoding = strict
+	}
+}
+
+// WithSchemaDriftHook registers hook, called with each response field strict decoding finds
+// our models lack, for alerting
+func WithSchemaDriftHook(hook func(SchemaDrift)) ClientOption {
+	return func(c *Client) {
+		c.schemaDriftHook = hook
+	}
+}
+
+// IdempotencyKeyHeader carries the key NorthWind recognises a repeated transfer operation by
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotencyKeyFunc returns the Idempotency-Key of one call that creates or changes a transfer,
+// given the request path and the request body. An empty key falls back to a random one.
+type IdempotencyKeyFunc func(path string, body interface{}) string
+
+// WithIdempotencyKeyFunc sets how the Idempotency-Key of a transfer operation is chosen, for
+// example from the TransferRequest's ReferenceNumber so a retry in a later call reuses it. By
+// default every call gets a random key. A key set with WithIdempotencyKey takes precedence.
+func WithIdempotencyKeyFunc(fn IdempotencyKeyFunc) ClientOption {
+	return func(c *Client) {
+		c.idempotencyKeyFunc = fn
+	}
+}
+
+// WithMetricsHook registers hook, called once per request attempt, for exporting latency, status
+// codes and retries. Client.Stats keeps a summary whether or not a hook is set.
+func WithMetricsHook(hook MetricsHook) ClientOption {
+	return func(c *Client) {
+		c.metricsHook = hook
+	}
+}
+
+// WithTesting marks the client as running in tests, where a missing API key is expected and
+// not worth a warning
+func WithTesting(testing bool) ClientOption {
+	return func(c *Client) {
+		c.testing = testing
+	}
+}
+
+// NewClient creates a new NorthWind API client. Trailing slashes are trimmed from baseURL so
+// request paths join cleanly; use NewClientValidated to reject an unusable baseURL up front.
 func NewClient(baseURL, apiKey string, opts ...ClientOption) *Client {
 	c := &Client{
-		baseURL: baseURL,
+		baseURL: strings.TrimRight(baseURL, "/"),
 		apiKey:  apiKey,
 		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout: DefaultTimeout,
 		},
+		maxRetryDuration: DefaultMaxRetryDuration,
+		maxListPages:     DefaultMaxListPages,
+		logger:           slog.Default(),
 	}
+	c.activeAPIKey.Store(APIKeyPrimary)
 	for _, opt := range opts {
 		opt(c)
 	}
+	if c.timeout > 0 {
+		httpClient := *c.httpClient
+		httpClient.Timeout = c.timeout
+		c.httpClient = &httpClient
+	}
+	c.normalizeRetry()
 	return c
 }
 
+// ActiveAPIKey returns the key that authenticated the last request NorthWind accepted, so health
+// checks can report a client running on the secondary key. It is APIKeyPrimary until then.
+func (c *Client) ActiveAPIKey() APIKeyID {
+	return c.activeAPIKey.Load().(APIKeyID)
+}
+
+// recordAPIKey notes the key a request was accepted with, logging when it differs from the last
+func (c *Client) recordAPIKey(key APIKeyID) {
+	if previous := c.activeAPIKey.Swap(key); previous != key {
+		c.logger.Info("NorthWind requests are now authenticated with a different API key", "api_key", key, "previous_api_key", previous)
+	}
+	if c.apiKeyHook != nil {
+		c.apiKeyHook(key)
+	}
+}
+
+// NewClientValidated is NewClient for configured values: it returns ErrInvalidBaseURL for an
+// empty, relative or non-http(s) baseURL, and warns about an empty apiKey unless WithTesting
+// is set, rather than letting either surface as a failure on the first call.
+func NewClientValidated(baseURL, apiKey string, opts ...ClientOption) (*Client, error) {
+	if err := validateBaseURL(baseURL); err != nil {
+		return nil, err
+	}
+	c := NewClient(baseURL, apiKey, opts...)
+	if apiKey == "" && !c.testing {
+		c.logger.Warn("NorthWind API key is empty; requests will be sent unauthenticated", "base_url", c.baseURL)
+	}
+	return c, nil
+}
+
+func validateBaseURL(raw string) error {
+	if strings.TrimSpace(raw) == "" {
+		return fmt.Errorf("%w: base URL is empty", ErrInvalidBaseURL)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBaseURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: got %q", ErrInvalidBaseURL, raw)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("%w: %q has no host", ErrInvalidBaseURL, raw)
+	}
+	return nil
+}
+
+// normalizeRetry corrects retry settings that would disable retries by accident or retry
+// without any delay
+func (c *Client) normalizeRetry() {
+	if c.maxRetries < 0 {
+		c.logger.Warn("NorthWind client maxRetries is negative, disabling retries", "max_retries", c.maxRetries)
+		c.maxRetries = 0
+	}
+	if c.maxRetries > 0 && c.retryInitialBackoff <= 0 {
+		c.logger.Warn("NorthWind client retry backoff is not positive, using floor",
+			"initial_backoff", c.retryInitialBackoff,
+			"floor", minRetryBackoff,
+		)
+		c.retryInitialBackoff = minRetryBackoff
+	}
+}
+
 // APIError represents an error returned by the NorthWind API
 type APIError struct {
 	StatusCode int
 	Body       string
 	Parsed     *APIErrorResponse
+	// TraceID is NorthWind's trace ID for the failed call, from its X-Trace-ID response header,
+	// and RequestID its X-NW-Request-ID, which NorthWind support asks for
+	TraceID   string
+	RequestID string
+	// Attempts is how many requests the call made, retries included, and Elapsed the time from
+	// the first request to giving up
+	Attempts int
+	Elapsed  time.Duration
 }
 
 func (e *APIError) Error() string {
+	return fmt.Sprintf("northwind api error (HTTP %d): %s", e.StatusCode, e.Message())
+}
+
+// Message returns NorthWind's explanation of the error, falling back to the raw response body
+func (e *APIError) Message() string {
 	if e.Parsed != nil {
-		msg := e.Parsed.Message
-		if msg == "" {
-			msg = e.Parsed.Error
+		if e.Parsed.Message != "" {
+			return e.Parsed.Message
+		}
+		if e.Parsed.Error != "" {
+			return e.Parsed.Error
 		}
-		if msg != "" {
-			return fmt.Sprintf("northwind api error (HTTP %d): %s", e.StatusCode, msg)
+	}
+	return e.Body
+}
+
+// RequestError is a call to NorthWind that got no usable response: the request could not be sent
+// or its response could not be read. Attempts and Elapsed are as on APIError.
+type RequestError struct {
+	Err      error
+	Attempts int
+	Elapsed  time.Duration
+}
+
+func (e *RequestError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// ContentTypeError is a 2xx response from NorthWind whose body is not JSON. It usually comes from
+// something in front of NorthWind, such as a load balancer serving an HTML error page with 200,
+// so it is retried like a 5xx. Snippet is the start of the body, for the logs.
+type ContentTypeError struct {
+	StatusCode  int
+	ContentType string
+	Snippet     string
+	RequestID   string
+	Attempts    int
+	Elapsed     time.Duration
+}
+
+func (e *ContentTypeError) Error() string {
+	return fmt.Sprintf("%s (HTTP %d, Content-Type %q): %s", ErrUnexpectedContentType, e.StatusCode, e.ContentType, e.Snippet)
+}
+
+func (e *ContentTypeError) Unwrap() error {
+	return ErrUnexpectedContentType
+}
+
+// endpoint is a NorthWind API path together with the route template it was built from. Metrics are
+// labelled with the route, which leaves out the IDs and query strings of individual requests.
+type endpoint struct {
+	route string
+	path  string
+}
+
+// apiPath returns the endpoint of route with each {placeholder}, in order, replaced by the matching
+// path-escaped arg
+func apiPath(route string, args ...string) endpoint {
+	var path strings.Builder
+	rest := route
+	for _, arg := range args {
+		open, end := strings.IndexByte(rest, '{'), strings.IndexByte(rest, '}')
+		if open < 0 || end < open {
+			break
 		}
+		path.WriteString(rest[:open])
+		path.WriteString(url.PathEscape(arg))
+		rest = rest[end+1:]
 	}
-	return fmt.Sprintf("northwind api error (HTTP %d): %s", e.StatusCode, e.Body)
+	path.WriteString(rest)
+	return endpoint{route: route, path: path.String()}
+}
+
+// withQuery returns e with params added to its path as a query string; the route is unchanged
+func (e endpoint) withQuery(params url.Values) endpoint {
+	if len(params) > 0 {
+		e.path += "?" + params.Encode()
+	}
+	return e
 }
 
 // doRequest executes an HTTP request to the NorthWind API with optional retries.
-// Retries on network errors and 5xx responses; does not retry on 4xx.
-func (c *Client) doRequest(ctx context.Context, method, path string, body interface{}) ([]byte, int, error) {
-	fullURL := c.baseURL + path
+// Retries on network errors, 429 and 5xx responses and 2xx responses that are not JSON; does not
+// retry on other 4xx.
+func (c *Client) doRequest(ctx context.Context, method string, ep endpoint, body interface{}) ([]byte, int, error) {
+	respBody, _, status, err := c.doRequestWithHeaders(ctx, method, ep, body, nil)
+	return respBody, status, err
+}
+
+// doIdempotentPost is doRequest for a POST that creates or changes a transfer. NorthWind may have
+// acted on a request whose response was lost to a reset connection or a 5xx, so the request
+// carries an Idempotency-Key, chosen once per call, that every retry repeats.
+func (c *Client) doIdempotentPost(ctx context.Context, ep endpoint, body interface{}) ([]byte, error) {
+	key, _ := ctx.Value(idempotencyKeyKey).(string)
+	if key == "" && c.idempotencyKeyFunc != nil {
+		key = c.idempotencyKeyFunc(ep.path, body)
+	}
+	if key == "" {
+		key = uuid.NewString()
+	}
+	respBody, _, _, err := c.doRequestWithHeaders(ctx, http.MethodPost, ep, body, http.Header{IdempotencyKeyHeader: {key}})
+	return respBody, err
+}
 
-	var reqBody io.Reader
+// doRequestWithHeaders is doRequest with extra request headers, also returning the response
+// headers. A 304 Not Modified is a successful response: it is returned without error or retry.
+// A 401 with the primary key is retried once with the fallback key, when one is set.
+func (c *Client) doRequestWithHeaders(ctx context.Context, method string, ep endpoint, body interface{}, headers http.Header) ([]byte, http.Header, int, error) {
+	return c.send(ctx, method, ep, body, headers, c.maxRetries)
+}
+
+// send encodes body and sends the request, retrying network errors, 429 and 5xx up to maxRetries times
+func (c *Client) send(ctx context.Context, method string, ep endpoint, body interface{}, headers http.Header, maxRetries int) ([]byte, http.Header, int, error) {
+	var jsonBody []byte
 	if body != nil {
-		jsonBody, err := json.Marshal(body)
-		if err != nil {
-			return nil, 0, fmt.Errorf("failed to marshal request body: %w", err)
+		var err error
+		if jsonBody, err = json.Marshal(body); err != nil {
+			return nil, nil, 0, fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		reqBody = bytes.NewBuffer(jsonBody)
 	}
 
+	key := APIKeyPrimary
+	respBody, respHeaders, status, err := c.doRequestWithKey(ctx, method, ep, jsonBody, headers, c.apiKey, maxRetries)
+	if status == http.StatusUnauthorized && c.fallbackAPIKey != "" {
+		c.logger.Warn("NorthWind rejected the primary API key, retrying with the secondary",
+			"method", method,
+			"path", ep.path,
+		)
+		key = APIKeySecondary
+		respBody, respHeaders, status, err = c.doRequestWithKey(ctx, method, ep, jsonBody, headers, c.fallbackAPIKey, maxRetries)
+	}
+	if err == nil {
+		c.recordAPIKey(key)
+	}
+	return respBody, respHeaders, status, err
+}
+
+// doRequestWithKey sends one request authenticated with apiKey, retrying network errors, 429 and
+// 5xx up to maxRetries times. A failed response's Retry-After header, in seconds or as an HTTP
+// date, replaces the computed backoff before the next retry. A retry is only started when its
+// backoff ends before both the retry budget and the context's deadline; otherwise the last failure
+// is returned at once rather than after a wasted sleep. When the delay NorthWind asked for is what
+// outlasts the deadline, the error wraps context.DeadlineExceeded as well as the last failure, and a
+// context cancelled during a backoff likewise wraps its error and the last failure.
+func (c *Client) doRequestWithKey(ctx context.Context, method string, ep endpoint, jsonBody []byte, headers http.Header, apiKey string, maxRetries int) ([]byte, http.Header, int, error) {
+	fullURL := c.baseURL + ep.path
+
 	var lastErr error
 	var lastStatus int
+	var retryAfter time.Duration
+	var hasRetryAfter bool
+	attempts := 0
+	start := time.Now()
 
-	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
+			backoff := c.retryBackoff(attempt)
+			if hasRetryAfter {
+				backoff = retryAfter
+			}
+			if time.Since(start)+backoff > c.maxRetryDuration {
+				break // retry budget exhausted; report the last failure
+			}
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= backoff {
+				if hasRetryAfter {
+					c.setAttempts(lastErr, attempts, time.Since(start))
+					return nil, nil, lastStatus, fmt.Errorf("%w: northwind asked to retry after %v: %w", context.DeadlineExceeded, retryAfter, lastErr)
+				}
+				break // the caller's deadline would pass before the retry is sent
+			}
 			select {
 			case <-ctx.Done():
-				return nil, 0, ctx.Err()
-			case <-time.After(c.retryBackoff(attempt)):
+				c.setAttempts(lastErr, attempts, time.Since(start))
+				return nil, nil, lastStatus, fmt.Errorf("%w while waiting to retry: %w", ctx.Err(), lastErr)
+			case <-time.After(backoff):
 				// proceed to retry
 			}
 		}
 
+		// Each attempt gets a fresh reader; a consumed one would send an empty body
+		var reqBody io.Reader
+		if jsonBody != nil {
+			reqBody = bytes.NewReader(jsonBody)
+		}
 		req, err := http.NewRequestWithContext(ctx, method, fullURL, reqBody)
 		if err != nil {
-			return nil, 0, fmt.Errorf("failed to create request: %w", err)
+			return nil, nil, 0, fmt.Errorf("failed to create request: %w", err)
 		}
 
-		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+		req.Header.Set("Authorization", "Bearer "+apiKey)
 		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("Accept", "application/json")
 		if traceID, ok := ctx.Value(traceIDKey).(string); ok && traceID != "" {
 			req.Header.Set("X-Trace-ID", traceID)
 		}
+		for key, values := range headers {
+			for _, v := range values {
+				req.Header.Add(key, v)
+			}
+		}
 
+		attempts++
+		hasRetryAfter = false
+		attemptStart := time.Now()
 		resp, err := c.httpClient.Do(req)
 		if err != nil {
-			lastErr = fmt.Errorf("failed to execute request: %w", err)
+			lastErr = &RequestError{Err: fmt.Errorf("failed to execute request: %w", err)}
+			c.observe(method, ep.route, 0, attempts, attemptStart, lastErr)
 			continue
 		}
 
+		c.recordQuota(resp.Header)
+		recordResponse(ctx, resp)
 		respBody, err := io.ReadAll(resp.Body)
 		resp.Body.Close()
 		if err != nil {
-			lastErr = fmt.Errorf("failed to read response body: %w", err)
+			lastErr = &RequestError{Err: fmt.Errorf("failed to read response body: %w", err)}
 			lastStatus = resp.StatusCode
+			c.observe(method, ep.route, resp.StatusCode, attempts, attemptStart, lastErr)
 			continue
 		}
 
 		if resp.StatusCode >= 400 {
-			apiErr := &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
+			apiErr := &APIError{
+				StatusCode: resp.StatusCode,
+				Body:       string(respBody),
+				TraceID:    resp.Header.Get("X-Trace-ID"),
+				RequestID:  resp.Header.Get(RequestIDHeader),
+			}
 			var parsed APIErrorResponse
 			if json.Unmarshal(respBody, &parsed) == nil {
 				apiErr.Parsed = &parsed
 			}
-			// Do not retry 4xx
-			if resp.StatusCode < 500 {
-				return nil, resp.StatusCode, apiErr
+			c.observe(method, ep.route, resp.StatusCode, attempts, attemptStart, apiErr)
+			// Do not retry 4xx other than 429
+			if resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+				apiErr.Attempts, apiErr.Elapsed = attempts, time.Since(start)
+				return nil, resp.Header, resp.StatusCode, apiErr
 			}
+			retryAfter, hasRetryAfter = parseRetryAfter(resp.Header, time.Now())
 			lastErr = apiErr
 			lastStatus = resp.StatusCode
 			continue
 		}
 
-		return respBody, resp.StatusCode, nil
+		if ctErr := c.checkContentType(method, ep.path, resp, respBody); ctErr != nil {
+			lastErr = ctErr
+			lastStatus = resp.StatusCode
+			c.observe(method, ep.route, resp.StatusCode, attempts, attemptStart, ctErr)
+			continue
+		}
+
+		c.observe(method, ep.route, resp.StatusCode, attempts, attemptStart, nil)
+		return respBody, resp.Header, resp.StatusCode, nil
 	}
 
-	if apiErr, ok := lastErr.(*APIError); ok {
-		return nil, lastStatus, apiErr
+	c.setAttempts(lastErr, attempts, time.Since(start))
+	return nil, nil, lastStatus, lastErr
+}
+
+// setAttempts records on a failed call's error how many attempts it made and how long they took
+func (c *Client) setAttempts(err error, attempts int, elapsed time.Duration) {
+	switch e := err.(type) {
+	case *APIError:
+		e.Attempts, e.Elapsed = attempts, elapsed
+	case *RequestError:
+		e.Attempts, e.Elapsed = attempts, elapsed
+	case *ContentTypeError:
+		e.Attempts, e.Elapsed = attempts, elapsed
 	}
-	return nil, lastStatus, lastErr
 }
 
+// retryBackoff returns the delay before retry number attempt (1-based): initial * 2^(attempt-1),
+// jittered when WithRetryJitter is set, then capped at maxRetryBackoff. Attempts below 1 are
+// treated as the first retry.
 func (c *Client) retryBackoff(attempt int) time.Duration {
 	if c.retryInitialBackoff <= 0 {
 		return 0
 	}
-	// Exponential: initial * 2^attempt
-	d := c.retryInitialBackoff * time.Duration(1<<uint(attempt-1))
-	if d > 10*time.Second {
-		return 10 * time.Second
+	if attempt < 1 {
+		attempt = 1
+	}
+	d := c.retryInitialBackoff
+	for i := 1; i < attempt && d < maxRetryBackoff; i++ {
+		d *= 2
+	}
+	if c.retryJitter != nil {
+		d = time.Duration(c.retryJitter(d.Seconds()) * float64(time.Second))
 	}
-	return d
+	if d > maxRetryBackoff {
+		return maxRetryBackoff
+	}
+	return max(d, 0)
 }
 
 type contextKey string
@@ -165,36 +625,177 @@ func WithTraceID(ctx context.Context, traceID string) context.Context {
 	return context.WithValue(ctx, traceIDKey, traceID)
 }
 
+const idempotencyKeyKey contextKey = "idempotency_key"
+
+// WithIdempotencyKey returns a context whose transfer operations send key as their
+// Idempotency-Key. A caller that may repeat an operation in a later call, after one whose outcome
+// it never learned, passes the same key each time so NorthWind acts on it only once.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyKey, key)
+}
+
+// SchemaDrift is a field in a NorthWind response that our models do not have
+type SchemaDrift struct {
+	// Resource names the response, such as "transfer status"
+	Resource string
+	Field    string
+}
+
+// decode unmarshals a response body into v. With strict decoding the body is decoded a second
+// time into a scratch value with unknown fields disallowed, and the first field our models lack
+// is reported as drift; v is the lenient result either way.
+func (c *Client) decode(resource string, body []byte, v any) error {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return fmt.Errorf("%s: %w", resource, ErrEmptyResponse)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return err
+	}
+	if c.strictDecoding {
+		c.checkSchemaDrift(resource, body, v)
+	}
+	return nil
+}
+
+// checkContentType returns a ContentTypeError unless a successful response is JSON. Responses
+// without a body, such as 204 No Content and 304 Not Modified, are not checked. A body with no
+// Content-Type is accepted with a warning when it parses as JSON.
+func (c *Client) checkContentType(method, path string, resp *http.Response, body []byte) *ContentTypeError {
+	if resp.StatusCode == http.StatusNoContent || resp.StatusCode == http.StatusNotModified || len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" && json.Valid(body) {
+		c.logger.Warn("NorthWind response has no Content-Type; accepting it as JSON", "method", method, "path", path, "status", resp.StatusCode)
+		return nil
+	}
+	if isJSONContentType(contentType) {
+		return nil
+	}
+	snippet := strings.ToValidUTF8(string(body), "")
+	if len(snippet) > contentTypeSnippetLen {
+		snippet = strings.ToValidUTF8(snippet[:contentTypeSnippetLen], "")
+	}
+	return &ContentTypeError{
+		StatusCode:  resp.StatusCode,
+		ContentType: contentType,
+		Snippet:     strings.TrimSpace(snippet),
+		RequestID:   resp.Header.Get(RequestIDHeader),
+	}
+}
+
+// isJSONContentType reports whether a Content-Type header names JSON: application/json or a
+// structured +json type such as application/problem+json
+func isJSONContentType(header string) bool {
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func (c *Client) checkSchemaDrift(resource string, body []byte, v any) {
+	strict := json.NewDecoder(bytes.NewReader(body))
+	strict.DisallowUnknownFields()
+	err := strict.Decode(reflect.New(reflect.TypeOf(v).Elem()).Interface())
+	if err == nil {
+		return
+	}
+	// encoding/json has no typed error for this; its message is `json: unknown field "name"`
+	quoted, ok := strings.CutPrefix(err.Error(), "json: unknown field ")
+	if !ok {
+		return
+	}
+	field, unquoteErr := strconv.Unquote(quoted)
+	if unquoteErr != nil {
+		field = quoted
+	}
+
+	c.logger.Warn("NorthWind response has a field our models lack",
+		"resource", resource,
+		"field", field,
+	)
+	if c.schemaDriftHook != nil {
+		c.schemaDriftHook(SchemaDrift{Resource: resource, Field: field})
+	}
+}
+
 // --- API Methods ---
 
 // GetBankInfo retrieves NorthWind bank information
 func (c *Client) GetBankInfo(ctx context.Context) (*BankInfo, error) {
-	body, _, err := c.doRequest(ctx, http.MethodGet, "/bank", nil)
+	body, _, err := c.doRequest(ctx, http.MethodGet, apiPath("/bank"), nil)
 	if err != nil {
 		return nil, err
 	}
 	var result BankInfo
-	if err := json.Unmarshal(body, &result); err != nil {
+	if err := c.decode("bank info", body, &result); err != nil {
 		return nil, fmt.Errorf("failed to decode bank info: %w", err)
 	}
 	return &result, nil
 }
 
-// GetDomains retrieves NorthWind domains
+// GetDomains retrieves NorthWind domains, always downloading a fresh copy. The response's
+// ETag/Last-Modified validators are remembered for GetDomainsCached.
 func (c *Client) GetDomains(ctx context.Context) ([]Domain, error) {
-	body, _, err := c.doRequest(ctx, http.MethodGet, "/domains", nil)
+	return c.getDomains(ctx, false)
+}
+
+// GetDomainsCached retrieves NorthWind domains with a conditional request: when a validator
+// from an earlier response is stored it is sent as If-None-Match/If-Modified-Since, and a
+// 304 Not Modified returns the cached domains. Without a stored validator it behaves like GetDomains.
+func (c *Client) GetDomainsCached(ctx context.Context) ([]Domain, error) {
+	return c.getDomains(ctx, true)
+}
+
+func (c *Client) getDomains(ctx context.Context, conditional bool) ([]Domain, error) {
+	c.domainsMu.Lock()
+	cached := c.domainsCache
+	c.domainsMu.Unlock()
+
+	headers := http.Header{}
+	if conditional && cached.domains != nil {
+		if cached.etag != "" {
+			headers.Set("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			headers.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+
+	body, respHeaders, status, err := c.doRequestWithHeaders(ctx, http.MethodGet, apiPath("/domains"), nil, headers)
 	if err != nil {
 		return nil, err
 	}
+	if status == http.StatusNotModified {
+		if len(headers) == 0 {
+			return nil, fmt.Errorf("unexpected 304 from /domains without a conditional request")
+		}
+		return append([]Domain(nil), cached.domains...), nil
+	}
+
 	var result []Domain
-	if err := json.Unmarshal(body, &result); err != nil {
+	if err := c.decode("domains", body, &result); err != nil {
 		return nil, fmt.Errorf("failed to decode domains: %w", err)
 	}
+	if result == nil {
+		result = []Domain{}
+	}
+
+	c.domainsMu.Lock()
+	c.domainsCache = domainsCacheEntry{
+		domains:      append([]Domain(nil), result...),
+		etag:         respHeaders.Get("ETag"),
+		lastModified: respHeaders.Get("Last-Modified"),
+	}
+	c.domainsMu.Unlock()
+
 	return result, nil
 }
 
-// ListAccounts lists external accounts from NorthWind
-func (c *Client) ListAccounts(ctx context.Context, limit, offset int, accountType, status string) ([]ExternalAccount, error) {
+// ListAccounts lists external accounts from NorthWind. NorthWind answers with either a bare array
+// or an {"accounts": [...], "total_count": n} envelope; TotalCount is nil unless it sent one.
+func (c *Client) ListAccounts(ctx context.Context, limit, offset int, accountType, status string) (*AccountListResponse, error) {
 	params := url.Values{}
 	if limit > 0 {
 		params.Set("limit", strconv.Itoa(limit))
@@ -209,30 +810,33 @@ func (c *Client) ListAccounts(ctx context.Context, limit, offset int, accountTyp
 		params.Set("status", status)
 	}
 
-	path := "/external/accounts"
-	if len(params) > 0 {
-		path += "?" + params.Encode()
-	}
-
-	body, _, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	body, _, err := c.doRequest(ctx, http.MethodGet, apiPath("/external/accounts").withQuery(params), nil)
 	if err != nil {
 		return nil, err
 	}
-	var result []ExternalAccount
-	if err := json.Unmarshal(body, &result); err != nil {
+	var result AccountListResponse
+	if isBareArray(body) {
+		err = c.decode("accounts", body, &result.Accounts)
+	} else {
+		err = c.decode("accounts", body, &result)
+	}
+	if err != nil {
 		return nil, fmt.Errorf("failed to decode accounts: %w", err)
 	}
-	return result, nil
+	if result.Accounts == nil {
+		result.Accounts = []ExternalAccount{}
+	}
+	return &result, nil
 }
 
 // ValidateAccount validates an external account with NorthWind
 func (c *Client) ValidateAccount(ctx context.Context, req AccountValidationRequest) (*AccountValidationResponse, error) {
-	body, _, err := c.doRequest(ctx, http.MethodPost, "/external/accounts/validate", req)
+	body, _, err := c.doRequest(ctx, http.MethodPost, apiPath("/external/accounts/validate"), req)
 	if err != nil {
 		return nil, err
 	}
 	var result AccountValidationResponse
-	if err := json.Unmarshal(body, &result); err != nil {
+	if err := c.decode("validation response", body, &result); err != nil {
 		return nil, fmt.Errorf("failed to decode validation response: %w", err)
 	}
 	return &result, nil
@@ -240,20 +844,20 @@ func (c *Client) ValidateAccount(ctx context.Context, req AccountValidationReque
 
 // GetAccountBalance retrieves balance for an external account
 func (c *Client) GetAccountBalance(ctx context.Context, accountNumber string) (*AccountBalance, error) {
-	path := fmt.Sprintf("/external/accounts/%s/balance", url.PathEscape(accountNumber))
-	body, _, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	body, _, err := c.doRequest(ctx, http.MethodGet, apiPath("/external/accounts/{account_number}/balance", accountNumber), nil)
 	if err != nil {
 		return nil, err
 	}
 	var result AccountBalance
-	if err := json.Unmarshal(body, &result); err != nil {
+	if err := c.decode("account balance", body, &result); err != nil {
 		return nil, fmt.Errorf("failed to decode account balance: %w", err)
 	}
 	return &result, nil
 }
 
-// ListTransfers lists external transfers from NorthWind
-func (c *Client) ListTransfers(ctx context.Context, filters TransferListFilters) ([]TransferResponse, error) {
+// ListTransfers lists external transfers from NorthWind, accepting the same two response shapes
+// as ListAccounts
+func (c *Client) ListTransfers(ctx context.Context, filters TransferListFilters) (*TransferListResponse, error) {
 	params := url.Values{}
 	if filters.Status != "" {
 		params.Set("status", filters.Status)
@@ -271,43 +875,53 @@ func (c *Client) ListTransfers(ctx context.Context, filters TransferListFilters)
 		params.Set("offset", strconv.Itoa(filters.Offset))
 	}
 
-	path := "/external/transfers"
-	if len(params) > 0 {
-		path += "?" + params.Encode()
-	}
-
-	body, _, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	body, _, err := c.doRequest(ctx, http.MethodGet, apiPath("/external/transfers").withQuery(params), nil)
 	if err != nil {
 		return nil, err
 	}
-	var result []TransferResponse
-	if err := json.Unmarshal(body, &result); err != nil {
+	var result TransferListResponse
+	if isBareArray(body) {
+		err = c.decode("transfers", body, &result.Transfers)
+	} else {
+		err = c.decode("transfers", body, &result)
+	}
+	if err != nil {
 		return nil, fmt.Errorf("failed to decode transfers: %w", err)
 	}
-	return result, nil
+	if result.Transfers == nil {
+		result.Transfers = []TransferResponse{}
+	}
+	return &result, nil
+}
+
+// isBareArray reports whether a list response is a JSON array rather than a wrapping object
+func isBareArray(body []byte) bool {
+	trimmed := bytes.TrimLeft(body, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '['
 }
 
 // ValidateTransfer validates a transfer request with NorthWind
 func (c *Client) ValidateTransfer(ctx context.Context, req TransferRequest) (*TransferValidationResponse, error) {
-	body, _, err := c.doRequest(ctx, http.MethodPost, "/external/transfers/validate", req)
+	body, _, err := c.doRequest(ctx, http.MethodPost, apiPath("/external/transfers/validate"), req)
 	if err != nil {
 		return nil, err
 	}
 	var result TransferValidationResponse
-	if err := json.Unmarshal(body, &result); err != nil {
+	if err := c.decode("transfer validation", body, &result); err != nil {
 		return nil, fmt.Errorf("failed to decode transfer validation: %w", err)
 	}
 	return &result, nil
 }
 
-// InitiateTransfer initiates a transfer via NorthWind
+// InitiateTransfer initiates a transfer via NorthWind. Retries repeat the call's Idempotency-Key,
+// so a retry after a lost response returns the transfer NorthWind already initiated.
 func (c *Client) InitiateTransfer(ctx context.Context, req TransferRequest) (*TransferResponse, error) {
-	body, _, err := c.doRequest(ctx, http.MethodPost, "/external/transfers/initiate", req)
+	body, err := c.doIdempotentPost(ctx, apiPath("/external/transfers/initiate"), req)
 	if err != nil {
 		return nil, err
 	}
 	var result TransferResponse
-	if err := json.Unmarshal(body, &result); err != nil {
+	if err := c.decode("transfer response", body, &result); err != nil {
 		return nil, fmt.Errorf("failed to decode transfer response: %w", err)
 	}
 	return &result, nil
@@ -315,12 +929,12 @@ func (c *Client) InitiateTransfer(ctx context.Context, req TransferRequest) (*Tr
 
 // BatchTransfers submits a batch of transfers
 func (c *Client) BatchTransfers(ctx context.Context, req BatchTransferRequest) (*BatchTransferResponse, error) {
-	body, _, err := c.doRequest(ctx, http.MethodPost, "/external/transfers/batch", req)
+	body, err := c.doIdempotentPost(ctx, apiPath("/external/transfers/batch"), req)
 	if err != nil {
 		return nil, err
 	}
 	var result BatchTransferResponse
-	if err := json.Unmarshal(body, &result); err != nil {
+	if err := c.decode("batch transfer response", body, &result); err != nil {
 		return nil, fmt.Errorf("failed to decode batch transfer response: %w", err)
 	}
 	return &result, nil
@@ -328,27 +942,32 @@ func (c *Client) BatchTransfers(ctx context.Context, req BatchTransferRequest) (
 
 // GetTransferStatus retrieves the status of a transfer
 func (c *Client) GetTransferStatus(ctx context.Context, transferID string) (*TransferStatusResponse, error) {
-	path := fmt.Sprintf("/external/transfers/%s", url.PathEscape(transferID))
-	body, _, err := c.doRequest(ctx, http.MethodGet, path, nil)
+	result, _, err := c.GetTransferStatusRaw(ctx, transferID)
+	return result, err
+}
+
+// GetTransferStatusRaw gets a transfer's status along with the response body as NorthWind sent it.
+// When the body cannot be decoded it is returned with the error; on any other error it is nil.
+func (c *Client) GetTransferStatusRaw(ctx context.Context, transferID string) (*TransferStatusResponse, []byte, error) {
+	body, _, err := c.doRequest(ctx, http.MethodGet, apiPath("/external/transfers/{id}", transferID), nil)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	var result TransferStatusResponse
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("failed to decode transfer status: %w", err)
+	if err := c.decode("transfer status", body, &result); err != nil {
+		return nil, body, fmt.Errorf("failed to decode transfer status: %w", err)
 	}
-	return &result, nil
+	return &result, body, nil
 }
 
 // CancelTransfer cancels a pending transfer
 func (c *Client) CancelTransfer(ctx context.Context, transferID, reason string) (*TransferResponse, error) {
-	path := fmt.Sprintf("/external/transfers/%s/cancel", url.PathEscape(transferID))
-	body, _, err := c.doRequest(ctx, http.MethodPost, path, CancelRequest{Reason: reason})
+	body, err := c.doIdempotentPost(ctx, apiPath("/external/transfers/{id}/cancel", transferID), CancelRequest{Reason: reason})
 	if err != nil {
 		return nil, err
 	}
 	var result TransferResponse
-	if err := json.Unmarshal(body, &result); err != nil {
+	if err := c.decode("cancel response", body, &result); err != nil {
 		return nil, fmt.Errorf("failed to decode cancel response: %w", err)
 	}
 	return &result, nil
@@ -356,8 +975,7 @@ func (c *Client) CancelTransfer(ctx context.Context, transferID, reason string)
 
 // ReverseTransfer reverses a completed transfer
 func (c *Client) ReverseTransfer(ctx context.Context, transferID, reason, description string) (*TransferResponse, error) {
-	path := fmt.Sprintf("/external/transfers/%s/reverse", url.PathEscape(transferID))
-	body, _, err := c.doRequest(ctx, http.MethodPost, path, ReverseRequest{
+	body, err := c.doIdempotentPost(ctx, apiPath("/external/transfers/{id}/reverse", transferID), ReverseRequest{
 		Reason:      reason,
 		Description: description,
 	})
@@ -365,7 +983,7 @@ func (c *Client) ReverseTransfer(ctx context.Context, transferID, reason, descri
 		return nil, err
 	}
 	var result TransferResponse
-	if err := json.Unmarshal(body, &result); err != nil {
+	if err := c.decode("reverse response", body, &result); err != nil {
 		return nil, fmt.Errorf("failed to decode reverse response: %w", err)
 	}
 	return &result, nil
@@ -373,18 +991,19 @@ func (c *Client) ReverseTransfer(ctx context.Context, transferID, reason, descri
 
 // Reset resets NorthWind state (development only)
 func (c *Client) Reset(ctx context.Context) error {
-	_, _, err := c.doRequest(ctx, http.MethodPost, "/external/reset", nil)
+	// NorthWind answers 204 No Content, so there is no body to decode
+	_, _, err := c.doRequest(ctx, http.MethodPost, apiPath("/external/reset"), nil)
 	return err
 }
 
 // Health checks NorthWind API health
 func (c *Client) Health(ctx context.Context) (*HealthResponse, error) {
-	body, _, err := c.doRequest(ctx, http.MethodGet, "/health", nil)
+	body, _, err := c.doRequest(ctx, http.MethodGet, apiPath("/health"), nil)
 	if err != nil {
 		return nil, err
 	}
 	var result HealthResponse
-	if err := json.Unmarshal(body, &result); err != nil {
+	if err := c.decode("health response", body, &result); err != nil {
 		return nil, fmt.Errorf("failed to decode health response: %w", err)
 	}
 	return &result, nil
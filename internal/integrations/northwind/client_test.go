@@ -1,11 +1,19 @@
 package northwind
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewClient(t *testing.T) {
@@ -18,6 +26,78 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+// countingTransport counts the requests it carries to the wrapped transport
+type countingTransport struct {
+	next  http.RoundTripper
+	calls int
+}
+
+func (t *countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	t.calls++
+	return t.next.RoundTrip(r)
+}
+
+func TestNewClient_DefaultHTTPClient(t *testing.T) {
+	for name, c := range map[string]*Client{
+		"no options":     NewClient("https://example.com", "test-key"),
+		"nil client":     NewClient("https://example.com", "test-key", WithHTTPClient(nil)),
+		"no timeout set": NewClient("https://example.com", "test-key", WithTimeout(0)),
+	} {
+		if c.httpClient == nil || c.httpClient.Timeout != DefaultTimeout {
+			t.Errorf("%s: expected the default client with a %v timeout, got %+v", name, DefaultTimeout, c.httpClient)
+		}
+	}
+}
+
+func TestClient_WithHTTPClient_UsesTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	transport := &countingTransport{next: server.Client().Transport}
+	client := NewClient(server.URL, "test-key", WithHTTPClient(&http.Client{Transport: transport}))
+	for i := 0; i < 2; i++ {
+		if _, err := client.Health(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if transport.calls != 2 {
+		t.Errorf("expected both requests through the custom transport, got %d", transport.calls)
+	}
+}
+
+func TestClient_WithTimeout_ShortensSlowRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(2 * time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	custom := &http.Client{Transport: server.Client().Transport, Timeout: time.Minute}
+	clients := map[string]*Client{
+		"default client": NewClient(server.URL, "test-key", WithRetry(-1, 0), WithTimeout(50*time.Millisecond)),
+		"custom client":  NewClient(server.URL, "test-key", WithRetry(-1, 0), WithTimeout(50*time.Millisecond), WithHTTPClient(custom)),
+	}
+	for name, client := range clients {
+		start := time.Now()
+		_, err := client.Health(context.Background())
+		var reqErr *RequestError
+		if !errors.As(err, &reqErr) {
+			t.Fatalf("%s: expected the request to time out as a *RequestError, got %v", name, err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("%s: expected the 50ms timeout to cut the request short, took %v", name, elapsed)
+		}
+	}
+	if custom.Timeout != time.Minute {
+		t.Errorf("expected the supplied client to be left unchanged, its timeout is now %v", custom.Timeout)
+	}
+}
+
 func TestClient_GetBankInfo_Success(t *testing.T) {
 	expected := BankInfo{
 		Name:          "NorthWind Bank",
@@ -257,6 +337,65 @@ func TestClient_TraceIDPropagation(t *testing.T) {
 	}
 }
 
+func TestClient_ResponseMetadata_RecordsRequestID(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set(RequestIDHeader, "nw-req-"+strconv.Itoa(hits))
+		if hits == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(HealthResponse{Status: "ok"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", WithRetry(2, 1))
+	ctx, meta := WithResponseMetadata(context.Background())
+	if _, err := client.Health(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if meta.RequestID != "nw-req-2" || meta.StatusCode != http.StatusOK {
+		t.Errorf("expected the last attempt's request ID and status, got %+v", meta)
+	}
+	if ResponseMetadataFrom(context.Background()) != nil {
+		t.Error("expected no metadata on a plain context")
+	}
+}
+
+func TestClient_RequestIDOnErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(RequestIDHeader, "nw-req-"+r.URL.Query().Get("case"))
+		if r.URL.Query().Get("case") == "html" {
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<html></html>"))
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(APIErrorResponse{Message: "bad request"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", WithRetry(0, 1))
+	_, _, err := client.doRequest(context.Background(), http.MethodGet, apiPath("/health").withQuery(url.Values{"case": {"api"}}), nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RequestID != "nw-req-api" {
+		t.Errorf("expected *APIError with the request ID, got %#v", err)
+	}
+	_, _, err = client.doRequest(context.Background(), http.MethodGet, apiPath("/health").withQuery(url.Values{"case": {"html"}}), nil)
+	var ctErr *ContentTypeError
+	if !errors.As(err, &ctErr) || ctErr.RequestID != "nw-req-html" {
+		t.Errorf("expected *ContentTypeError with the request ID, got %#v", err)
+	}
+	if got := RequestID(err); got != "nw-req-html" {
+		t.Errorf("expected RequestID to find the ID in a wrapped error, got %q", got)
+	}
+	if got := RequestID(errors.New("connection refused")); got != "" {
+		t.Errorf("expected no request ID without a response, got %q", got)
+	}
+}
+
 func TestClient_NonJSONErrorBody(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadGateway)
@@ -399,8 +538,43 @@ func TestClient_ListAccounts_Success(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(result) != 1 || result[0].AccountNumber != "acc1" {
-		t.Errorf("expected one account acc1, got %+v", result)
+	if len(result.Accounts) != 1 || result.Accounts[0].AccountNumber != "acc1" || result.TotalCount != nil {
+		t.Errorf("expected one account acc1 and no total, got %+v", result)
+	}
+}
+
+func TestClient_ListAccounts_ResponseShapes(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		accounts int
+		total    *int
+	}{
+		{"bare array", ` [{"account_number":"acc1"},{"account_number":"acc2"}]`, 2, nil},
+		{"wrapped", `{"accounts":[{"account_number":"acc1"}],"total_count":42}`, 1, intPtr(42)},
+		{"wrapped without total", `{"accounts":[{"account_number":"acc1"}]}`, 1, nil},
+		{"empty wrapped object", `{}`, 0, nil},
+		{"empty bare array", `[]`, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			result, err := NewClient(server.URL, "test-key").ListAccounts(context.Background(), 10, 0, "", "")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Accounts == nil || len(result.Accounts) != tt.accounts {
+				t.Errorf("expected %d accounts, got %+v", tt.accounts, result.Accounts)
+			}
+			if !sameIntPtr(result.TotalCount, tt.total) {
+				t.Errorf("expected total %v, got %v", tt.total, result.TotalCount)
+			}
+		})
 	}
 }
 
@@ -484,11 +658,53 @@ func TestClient_ListTransfers_Success(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(result) != 1 || result[0].TransferID != "t1" || result[0].Status != "COMPLETED" {
+	if len(result.Transfers) != 1 || result.Transfers[0].TransferID != "t1" || result.Transfers[0].Status != "COMPLETED" {
 		t.Errorf("expected one transfer t1 COMPLETED, got %+v", result)
 	}
 }
 
+func TestClient_ListTransfers_ResponseShapes(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		transfers int
+		total     *int
+	}{
+		{"bare array", "\n[{\"transfer_id\":\"t1\"}]", 1, nil},
+		{"wrapped", `{"transfers":[{"transfer_id":"t1"},{"transfer_id":"t2"}],"total_count":7}`, 2, intPtr(7)},
+		{"empty wrapped object", `{}`, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			result, err := NewClient(server.URL, "test-key").ListTransfers(context.Background(), TransferListFilters{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Transfers == nil || len(result.Transfers) != tt.transfers {
+				t.Errorf("expected %d transfers, got %+v", tt.transfers, result.Transfers)
+			}
+			if !sameIntPtr(result.TotalCount, tt.total) {
+				t.Errorf("expected total %v, got %v", tt.total, result.TotalCount)
+			}
+		})
+	}
+}
+
+func intPtr(n int) *int { return &n }
+
+func sameIntPtr(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
 func TestClient_DoRequest_4xxNoRetry(t *testing.T) {
 	attempts := 0
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -507,3 +723,1163 @@ func TestClient_DoRequest_4xxNoRetry(t *testing.T) {
 		t.Errorf("expected no retry on 4xx, got %d attempts", attempts)
 	}
 }
+
+func TestClient_GetDomainsCached_ETagRoundTrip(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch requests {
+		case 1:
+			if r.Header.Get("If-None-Match") != "" || r.Header.Get("If-Modified-Since") != "" {
+				t.Errorf("first request should not be conditional, got %v", r.Header)
+			}
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Last-Modified", "Wed, 14 Oct 2026 10:00:00 GMT")
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode([]Domain{{Name: "dom1"}})
+		case 2:
+			if got := r.Header.Get("If-None-Match"); got != `"v1"` {
+				t.Errorf("expected If-None-Match \"v1\", got %q", got)
+			}
+			if got := r.Header.Get("If-Modified-Since"); got != "Wed, 14 Oct 2026 10:00:00 GMT" {
+				t.Errorf("expected If-Modified-Since to echo Last-Modified, got %q", got)
+			}
+			w.Header().Set("ETag", `"v2"`)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode([]Domain{{Name: "dom1"}, {Name: "dom2"}})
+		default:
+			if got := r.Header.Get("If-None-Match"); got != `"v2"` {
+				t.Errorf("expected updated If-None-Match \"v2\", got %q", got)
+			}
+			w.WriteHeader(http.StatusNotModified)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key")
+	ctx := context.Background()
+	first, err := client.GetDomainsCached(ctx)
+	if err != nil || len(first) != 1 {
+		t.Fatalf("first fetch: got %+v, err %v", first, err)
+	}
+	second, err := client.GetDomainsCached(ctx)
+	if err != nil || len(second) != 2 {
+		t.Fatalf("second fetch: got %+v, err %v", second, err)
+	}
+	third, err := client.GetDomainsCached(ctx)
+	if err != nil {
+		t.Fatalf("third fetch: unexpected error: %v", err)
+	}
+	if len(third) != 2 || third[1].Name != "dom2" {
+		t.Errorf("expected cached domains on 304, got %+v", third)
+	}
+}
+
+func TestClient_GetDomainsCached_NotModifiedIsNotRetried(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]Domain{{Name: "dom1", Description: "First"}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", WithRetry(3, 1))
+	if _, err := client.GetDomainsCached(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := client.GetDomainsCached(context.Background())
+	if err != nil {
+		t.Fatalf("304 should not be an error, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests (no retry on 304), got %d", requests)
+	}
+	if len(result) != 1 || result[0].Description != "First" {
+		t.Errorf("expected cached domains, got %+v", result)
+	}
+}
+
+func TestClient_GetDomainsCached_NoValidatorBypassesCache(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") != "" || r.Header.Get("If-Modified-Since") != "" {
+			t.Errorf("request %d should not be conditional without a stored validator", requests)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]Domain{{Name: "dom" + strconv.Itoa(requests)}})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key")
+	for i := 1; i <= 2; i++ {
+		result, err := client.GetDomainsCached(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := "dom" + strconv.Itoa(i); len(result) != 1 || result[0].Name != want {
+			t.Errorf("fetch %d: expected %s, got %+v", i, want, result)
+		}
+	}
+}
+
+func TestNewClient_RetryBackoffFloor(t *testing.T) {
+	var logs bytes.Buffer
+	client := NewClient("https://example.com", "test-key", WithRetry(3, 0), WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+
+	if client.retryInitialBackoff != minRetryBackoff {
+		t.Errorf("expected backoff floor %v, got %v", minRetryBackoff, client.retryInitialBackoff)
+	}
+	if !strings.Contains(logs.String(), "retry backoff is not positive") {
+		t.Errorf("expected a warning about the backoff, got %q", logs.String())
+	}
+
+	// Without retries the backoff is irrelevant and left alone
+	if c := NewClient("https://example.com", "test-key", WithRetry(0, 0)); c.retryInitialBackoff != 0 {
+		t.Errorf("expected no floor without retries, got %v", c.retryInitialBackoff)
+	}
+}
+
+func TestNewClient_NegativeMaxRetries(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	client := NewClient(server.URL, "test-key", WithRetry(-2, 1), WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	if client.maxRetries != 0 {
+		t.Errorf("expected maxRetries 0, got %d", client.maxRetries)
+	}
+	if _, err := client.Health(context.Background()); err == nil {
+		t.Fatal("expected an error from the failing server")
+	}
+	if attempts != 1 {
+		t.Errorf("expected a single attempt, got %d", attempts)
+	}
+	if !strings.Contains(logs.String(), "maxRetries is negative") {
+		t.Errorf("expected a warning about maxRetries, got %q", logs.String())
+	}
+}
+
+func TestClient_DoRequest_MaxRetryDurationCeiling(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	// Retries wait 50ms then 100ms: the second retry would end past the 120ms ceiling
+	client := NewClient(server.URL, "test-key", WithRetry(5, 50), WithMaxRetryDuration(120*time.Millisecond))
+	start := time.Now()
+	_, err := client.Health(context.Background())
+	elapsed := time.Since(start)
+
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected the last 503 to be returned, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts within the ceiling, got %d", attempts)
+	}
+	if elapsed > time.Second {
+		t.Errorf("retry loop ran past the ceiling: %v", elapsed)
+	}
+}
+
+func TestClient_DoRequest_ReportsAttemptsOnExhaustedRetries(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", WithRetry(2, 1))
+	_, err := client.Health(context.Background())
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %v", err)
+	}
+	if hits != 3 || apiErr.Attempts != 3 {
+		t.Errorf("expected 3 attempts reported, got %d (server saw %d)", apiErr.Attempts, hits)
+	}
+	if apiErr.Elapsed <= 0 {
+		t.Errorf("expected the elapsed time to be reported, got %v", apiErr.Elapsed)
+	}
+}
+
+func TestClient_DoRequest_HTMLWithOKIsRetriedAndTyped(t *testing.T) {
+	hits := 0
+	page := "<html><body>Down for maintenance" + strings.Repeat(".", 500) + "</body></html>"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(page))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", WithRetry(2, 1))
+	_, err := client.Health(context.Background())
+
+	if !errors.Is(err, ErrUnexpectedContentType) {
+		t.Fatalf("expected ErrUnexpectedContentType, got %v", err)
+	}
+	var ctErr *ContentTypeError
+	if !errors.As(err, &ctErr) {
+		t.Fatalf("expected *ContentTypeError, got %T", err)
+	}
+	if hits != 3 || ctErr.Attempts != 3 {
+		t.Errorf("expected the page to be retried like a 5xx, got %d attempts (server saw %d)", ctErr.Attempts, hits)
+	}
+	if ctErr.StatusCode != http.StatusOK || ctErr.ContentType != "text/html; charset=utf-8" {
+		t.Errorf("unexpected status or content type: %+v", ctErr)
+	}
+	if !strings.HasPrefix(ctErr.Snippet, "<html><body>Down for maintenance") || len(ctErr.Snippet) > contentTypeSnippetLen {
+		t.Errorf("expected a short snippet of the page, got %q", ctErr.Snippet)
+	}
+}
+
+func TestClient_Reset_NoContent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	if err := NewClient(server.URL, "test-key").Reset(context.Background()); err != nil {
+		t.Errorf("expected 204 to succeed, got %v", err)
+	}
+}
+
+func TestClient_DoRequest_EmptyBodyForDecodedCall(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "test-key").Health(context.Background())
+	if !errors.Is(err, ErrEmptyResponse) {
+		t.Errorf("expected ErrEmptyResponse, got %v", err)
+	}
+}
+
+func TestClient_DoRequest_MissingContentTypeWithJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Setting the header to nil stops net/http from sniffing one
+		w.Header()["Content-Type"] = nil
+		_, _ = w.Write([]byte(`{"status":"healthy"}`))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	client := NewClient(server.URL, "test-key", WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+	health, err := client.Health(context.Background())
+	if err != nil || health.Status != "healthy" {
+		t.Fatalf("expected the JSON body to be accepted, got %+v, err %v", health, err)
+	}
+	if !strings.Contains(logs.String(), "no Content-Type") {
+		t.Errorf("expected a warning about the missing Content-Type, got %q", logs.String())
+	}
+}
+
+func TestClient_InitiateTransfer_RetryResendsFullBody(t *testing.T) {
+	req := TransferRequest{
+		Amount:          250.75,
+		Currency:        "USD",
+		Direction:       "OUTBOUND",
+		TransferType:    "ACH",
+		ReferenceNumber: "REF-RETRY",
+		SourceAccount:   AccountDetails{AccountHolderName: "Source", AccountNumber: "1111111111"},
+	}
+	want, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var bodies [][]byte
+	var keys []string
+	var hits []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/external/transfers/initiate" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, body)
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		hits = append(hits, time.Now())
+		if len(bodies) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(TransferResponse{TransferID: "TXN-RETRY", Status: TransferStatusPending})
+	}))
+	defer server.Close()
+
+	// The computed backoff would be 1ms; NorthWind asks for a second
+	client := NewClient(server.URL, "test-key", WithRetry(2, 1))
+	resp, err := client.InitiateTransfer(context.Background(), req)
+	if err != nil {
+		t.Fatalf("expected the 503 to be retried, got %v", err)
+	}
+	if resp.TransferID != "TXN-RETRY" {
+		t.Errorf("expected the retry's response, got %+v", resp)
+	}
+	if len(bodies) != 2 {
+		t.Fatalf("expected 2 attempts, got %d", len(bodies))
+	}
+	for i, body := range bodies {
+		if !bytes.Equal(body, want) {
+			t.Errorf("attempt %d sent %q, want the full body %q", i+1, body, want)
+		}
+	}
+	if keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("expected one Idempotency-Key on both attempts, got %q", keys)
+	}
+	if wait := hits[1].Sub(hits[0]); wait < 900*time.Millisecond {
+		t.Errorf("expected the retry to wait for Retry-After, waited %v", wait)
+	}
+}
+
+func TestClient_IdempotencyKey_ConstantAcrossRetries(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(c *Client) error
+	}{
+		{"initiate", func(c *Client) error {
+			_, err := c.InitiateTransfer(context.Background(), TransferRequest{ReferenceNumber: "REF001"})
+			return err
+		}},
+		{"batch", func(c *Client) error {
+			_, err := c.BatchTransfers(context.Background(), BatchTransferRequest{Transfers: []TransferRequest{{ReferenceNumber: "REF001"}}})
+			return err
+		}},
+		{"cancel", func(c *Client) error {
+			_, err := c.CancelTransfer(context.Background(), "TXN-1", "customer request")
+			return err
+		}},
+		{"reverse", func(c *Client) error {
+			_, err := c.ReverseTransfer(context.Background(), "TXN-1", "duplicate", "sent twice")
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var keys []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+				// Each call's first attempt is lost after NorthWind may have acted on it
+				if len(keys)%2 == 1 {
+					w.WriteHeader(http.StatusBadGateway)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "test-key", WithRetry(2, 1))
+			for i := 0; i < 2; i++ {
+				if err := tt.call(client); err != nil {
+					t.Fatalf("unexpected error after retry: %v", err)
+				}
+			}
+			if len(keys) != 4 {
+				t.Fatalf("expected 2 attempts per call, got %d requests", len(keys))
+			}
+			if keys[0] == "" || keys[0] != keys[1] {
+				t.Errorf("expected attempt 1 and attempt 2 to carry the same key, got %q and %q", keys[0], keys[1])
+			}
+			if keys[2] != keys[3] || keys[2] == keys[0] {
+				t.Errorf("expected each call to get its own key, got %v", keys)
+			}
+		})
+	}
+}
+
+func TestClient_WithIdempotencyKeyFunc(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", WithIdempotencyKeyFunc(func(path string, body interface{}) string {
+		if req, ok := body.(TransferRequest); ok {
+			return req.ReferenceNumber
+		}
+		return ""
+	}))
+	if _, err := client.InitiateTransfer(context.Background(), TransferRequest{ReferenceNumber: "REF001"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.CancelTransfer(context.Background(), "TXN-1", "customer request"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if keys[0] != "REF001" {
+		t.Errorf("expected the reference number as key, got %q", keys[0])
+	}
+	if keys[1] == "" {
+		t.Error("expected a random key when the func returns none")
+	}
+}
+
+func TestClient_WithIdempotencyKey(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", WithIdempotencyKeyFunc(func(string, interface{}) string {
+		return "from-func"
+	}))
+	ctx := WithIdempotencyKey(context.Background(), "initiate-1")
+	for i := 0; i < 2; i++ {
+		if _, err := client.InitiateTransfer(ctx, TransferRequest{ReferenceNumber: "REF001"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, err := client.InitiateTransfer(context.Background(), TransferRequest{ReferenceNumber: "REF001"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if keys[0] != "initiate-1" || keys[1] != "initiate-1" {
+		t.Errorf("expected the context's key on both calls, got %v", keys[:2])
+	}
+	if keys[2] != "from-func" {
+		t.Errorf("expected the key func without a context key, got %q", keys[2])
+	}
+}
+
+func TestClient_DoRequest_NetworkFailureIsRequestError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	client := NewClient(server.URL, "test-key", WithRetry(2, 1))
+	_, err := client.Health(context.Background())
+
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) {
+		t.Fatalf("expected *RequestError, got %T: %v", err, err)
+	}
+	if reqErr.Attempts != 3 {
+		t.Errorf("expected 3 attempts reported, got %d", reqErr.Attempts)
+	}
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		t.Errorf("expected the transport error to stay reachable, got %v", err)
+	}
+}
+
+func TestClient_DoRequest_NoBackoffPastContextDeadline(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	// The first retry would wait 2s, but the caller only has 300ms
+	client := NewClient(server.URL, "test-key", WithRetry(3, 2000))
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := client.Health(ctx)
+	elapsed := time.Since(start)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected the 503 rather than a context error, got %v", err)
+	}
+	if hits != 1 || apiErr.Attempts != 1 {
+		t.Errorf("expected a single attempt, got %d (server saw %d)", apiErr.Attempts, hits)
+	}
+	if elapsed >= 300*time.Millisecond {
+		t.Errorf("expected to fail without sleeping, took %v", elapsed)
+	}
+}
+
+func TestClient_DoRequest_HonorsRetryAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter func() string
+	}{
+		{"seconds", func() string { return "1" }},
+		{"http date", func() string { return time.Now().Add(2 * time.Second).UTC().Format(http.TimeFormat) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hits []time.Time
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hits = append(hits, time.Now())
+				if len(hits) == 1 {
+					w.Header().Set("Retry-After", tt.retryAfter())
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"status":"ok"}`))
+			}))
+			defer server.Close()
+
+			// The computed backoff would be 1ms; NorthWind asks for at least a second
+			client := NewClient(server.URL, "test-key", WithRetry(2, 1))
+			if _, err := client.Health(context.Background()); err != nil {
+				t.Fatalf("expected the 429 to be retried, got %v", err)
+			}
+			if len(hits) != 2 {
+				t.Fatalf("expected 2 attempts, got %d", len(hits))
+			}
+			// HTTP dates have whole-second precision, so the wait may be just under the 2s asked for
+			if wait := hits[1].Sub(hits[0]); wait < 900*time.Millisecond {
+				t.Errorf("expected the retry to wait for Retry-After, waited %v", wait)
+			}
+		})
+	}
+}
+
+func TestClient_DoRequest_RetryAfterPastContextDeadline(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("Retry-After", "3")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", WithRetry(3, 1))
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := client.Health(ctx)
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a deadline error, got %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests || apiErr.Attempts != 1 {
+		t.Errorf("expected the 429 after one attempt to stay reachable, got %v", err)
+	}
+	if hits != 1 {
+		t.Errorf("expected a single attempt, server saw %d", hits)
+	}
+	if elapsed >= 300*time.Millisecond {
+		t.Errorf("expected to fail without sleeping, took %v", elapsed)
+	}
+}
+
+func TestClient_DoRequest_CancelledDuringBackoff(t *testing.T) {
+	hit := make(chan struct{}, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit <- struct{}{}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", WithRetry(3, 5000))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-hit
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	start := time.Now()
+	_, status, err := client.doRequest(ctx, http.MethodGet, apiPath("/health"), nil)
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a cancellation error, got %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable || apiErr.Attempts != 1 {
+		t.Errorf("expected the 503 after one attempt to stay reachable, got %v", err)
+	}
+	if status != http.StatusServiceUnavailable {
+		t.Errorf("expected the last status 503, got %d", status)
+	}
+	if elapsed >= time.Second {
+		t.Errorf("expected cancellation to end the backoff, took %v", elapsed)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, time.June, 11, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"seconds", "3", 3 * time.Second, true},
+		{"http date", now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second, true},
+		{"past date", now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"missing", "", 0, false},
+		{"negative", "-1", 0, false},
+		{"malformed", "soon", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.value != "" {
+				header.Set("Retry-After", tt.value)
+			}
+			got, ok := parseRetryAfter(header, now)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("expected %v, %v; got %v, %v", tt.want, tt.wantOK, got, ok)
+			}
+		})
+	}
+}
+
+func TestClient_MetricsHook_FiresOncePerAttempt(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if hits < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	type call struct {
+		method, path string
+		status       int
+		attempt      int
+		err          error
+	}
+	var calls []call
+	hook := func(method, path string, status int, attempt int, duration time.Duration, err error) {
+		if duration <= 0 {
+			t.Errorf("attempt %d: expected a positive duration, got %v", attempt, duration)
+		}
+		calls = append(calls, call{method, path, status, attempt, err})
+	}
+	client := NewClient(server.URL, "test-key", WithRetry(3, 1), WithMetricsHook(hook))
+	if _, err := client.Health(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(calls) != 3 {
+		t.Fatalf("expected the hook to fire once for each of 3 attempts, got %d calls", len(calls))
+	}
+	for i, c := range calls {
+		wantStatus := http.StatusBadGateway
+		if i == 2 {
+			wantStatus = http.StatusOK
+		}
+		if c.method != http.MethodGet || c.path != "/health" || c.attempt != i+1 || c.status != wantStatus {
+			t.Errorf("call %d: got %s %s status %d attempt %d; want GET /health status %d attempt %d",
+				i, c.method, c.path, c.status, c.attempt, wantStatus, i+1)
+		}
+		var apiErr *APIError
+		if i < 2 && !errors.As(c.err, &apiErr) {
+			t.Errorf("call %d: expected the attempt's *APIError, got %v", i, c.err)
+		}
+		if i == 2 && c.err != nil {
+			t.Errorf("call %d: expected no error for the successful attempt, got %v", i, c.err)
+		}
+	}
+}
+
+func TestClient_MetricsHook_ReportsRouteTemplate(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.RequestURI())
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/external/transfers":
+			_, _ = w.Write([]byte(`[]`))
+		case "/external/accounts/acct%2F1/balance":
+			_, _ = w.Write([]byte(`{"account_number":"acct/1"}`))
+		default:
+			_, _ = w.Write([]byte(`{"transfer_id":"nw-123","status":"PENDING"}`))
+		}
+	}))
+	defer server.Close()
+
+	var routes []string
+	client := NewClient(server.URL, "test-key", WithRetry(0, 1), WithMetricsHook(
+		func(method, route string, status int, attempt int, duration time.Duration, err error) {
+			routes = append(routes, method+" "+route)
+		}))
+	ctx := context.Background()
+	if _, err := client.GetTransferStatus(ctx, "nw-123"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.CancelTransfer(ctx, "nw-456", "duplicate"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.ListTransfers(ctx, TransferListFilters{Status: "PENDING", Limit: 10}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.GetAccountBalance(ctx, "acct/1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantPaths := []string{
+		"/external/transfers/nw-123",
+		"/external/transfers/nw-456/cancel",
+		"/external/transfers?limit=10&status=PENDING",
+		"/external/accounts/acct%2F1/balance",
+	}
+	wantRoutes := []string{
+		"GET /external/transfers/{id}",
+		"POST /external/transfers/{id}/cancel",
+		"GET /external/transfers",
+		"GET /external/accounts/{account_number}/balance",
+	}
+	if strings.Join(paths, " ") != strings.Join(wantPaths, " ") {
+		t.Errorf("expected requests to %v, got %v", wantPaths, paths)
+	}
+	if strings.Join(routes, ", ") != strings.Join(wantRoutes, ", ") {
+		t.Errorf("expected the hook to get routes %v, got %v", wantRoutes, routes)
+	}
+}
+
+func TestClient_MetricsHook_NetworkFailureHasNoStatus(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	var statuses, attempts []int
+	client := NewClient(server.URL, "test-key", WithRetry(1, 1), WithMetricsHook(
+		func(method, path string, status int, attempt int, duration time.Duration, err error) {
+			statuses = append(statuses, status)
+			attempts = append(attempts, attempt)
+		}))
+	_, _ = client.Health(context.Background())
+
+	if len(statuses) != 2 || statuses[0] != 0 || statuses[1] != 0 {
+		t.Errorf("expected status 0 for both attempts, got %v", statuses)
+	}
+	if len(attempts) != 2 || attempts[0] != 1 || attempts[1] != 2 {
+		t.Errorf("expected attempts 1 and 2, got %v", attempts)
+	}
+}
+
+func TestClient_Stats(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		switch {
+		case r.URL.Path == "/domains":
+			w.WriteHeader(http.StatusNotFound)
+		case hits == 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case hits == 2:
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<html>maintenance</html>"))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status":"ok"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", WithRetry(2, 1))
+	if stats := client.Stats(); stats.Requests != 0 || len(stats.ErrorsByClass) != 0 || stats.P95Latency != 0 {
+		t.Errorf("expected empty stats before any request, got %+v", stats)
+	}
+	if _, err := client.Health(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.GetDomains(context.Background()); err == nil {
+		t.Fatal("expected the 404 to fail")
+	}
+
+	stats := client.Stats()
+	if stats.Requests != 4 {
+		t.Errorf("expected 4 attempts counted, got %d", stats.Requests)
+	}
+	want := map[string]int64{ErrorClassServer: 1, ErrorClassContentType: 1, ErrorClassClient: 1}
+	if len(stats.ErrorsByClass) != len(want) {
+		t.Errorf("expected errors %v, got %v", want, stats.ErrorsByClass)
+	}
+	for class, n := range want {
+		if stats.ErrorsByClass[class] != n {
+			t.Errorf("expected %d %s errors, got %d", n, class, stats.ErrorsByClass[class])
+		}
+	}
+	if stats.P95Latency <= 0 {
+		t.Errorf("expected a p95 latency, got %v", stats.P95Latency)
+	}
+}
+
+func TestStatsRecorder_P95OverRecentAttempts(t *testing.T) {
+	var r statsRecorder
+	for i := 1; i <= 100; i++ {
+		r.record(time.Duration(i)*time.Millisecond, nil)
+	}
+	if p95 := r.snapshot().P95Latency; p95 != 95*time.Millisecond {
+		t.Errorf("expected p95 of 1..100ms to be 95ms, got %v", p95)
+	}
+
+	// A full window of fast attempts pushes the slow ones out of the ring buffer
+	for i := 0; i < statsLatencyWindow; i++ {
+		r.record(time.Millisecond, nil)
+	}
+	stats := r.snapshot()
+	if stats.P95Latency != time.Millisecond {
+		t.Errorf("expected p95 over the latest window to be 1ms, got %v", stats.P95Latency)
+	}
+	if stats.Requests != int64(100+statsLatencyWindow) {
+		t.Errorf("expected every attempt counted, got %d", stats.Requests)
+	}
+}
+
+func TestClient_RetryBackoff(t *testing.T) {
+	client := NewClient("https://example.com", "test-key", WithRetry(3, 100))
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{-1, 100 * time.Millisecond},
+		{0, 100 * time.Millisecond},
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{8, maxRetryBackoff},
+		{100, maxRetryBackoff},
+	}
+	for _, tt := range tests {
+		if got := client.retryBackoff(tt.attempt); got != tt.want {
+			t.Errorf("retryBackoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestClient_RetryBackoff_JitterCappedAfterwards(t *testing.T) {
+	grow := func(seconds float64) float64 { return seconds * 1.5 }
+	client := NewClient("https://example.com", "test-key", WithRetry(3, 100), WithRetryJitter(grow))
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 150 * time.Millisecond},
+		{3, 600 * time.Millisecond},
+		{7, 9600 * time.Millisecond}, // 6.4s jittered stays under the cap
+		{8, maxRetryBackoff},         // 10s is jittered to 15s, then capped
+	}
+	for _, tt := range tests {
+		if got := client.retryBackoff(tt.attempt); got != tt.want {
+			t.Errorf("retryBackoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestNewClientValidated_TrimsTrailingSlash(t *testing.T) {
+	for _, raw := range []string{"https://example.com/", "https://example.com//", "https://example.com/api/"} {
+		c, err := NewClientValidated(raw, "test-key")
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", raw, err)
+		}
+		if want := strings.TrimRight(raw, "/"); c.baseURL != want {
+			t.Errorf("%q: expected baseURL %s, got %s", raw, want, c.baseURL)
+		}
+	}
+}
+
+func TestNewClientValidated_RejectsInvalidBaseURL(t *testing.T) {
+	cases := map[string]string{
+		"empty":     "",
+		"blank":     "   ",
+		"relative":  "/northwind",
+		"no scheme": "example.com",
+		"ftp":       "ftp://example.com",
+		"no host":   "https://",
+		"malformed": "http://[::1",
+	}
+	for name, raw := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewClientValidated(raw, "test-key")
+			if !errors.Is(err, ErrInvalidBaseURL) {
+				t.Errorf("%q: expected ErrInvalidBaseURL, got %v", raw, err)
+			}
+			if c != nil {
+				t.Errorf("%q: expected no client", raw)
+			}
+		})
+	}
+}
+
+func TestNewClientValidated_WarnsOnEmptyAPIKey(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	if _, err := NewClientValidated("https://example.com", "", WithLogger(logger)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(logs.String(), "API key is empty") {
+		t.Errorf("expected empty API key warning, got %q", logs.String())
+	}
+
+	logs.Reset()
+	if _, err := NewClientValidated("https://example.com", "", WithLogger(logger), WithTesting(true)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("expected no warning in testing, got %q", logs.String())
+	}
+}
+
+// A configured base URL with a trailing slash used to produce requests to //bank
+func TestNewClientValidated_TrailingSlashRequestPath(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bank" {
+			t.Errorf("expected /bank, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(BankInfo{Name: "NorthWind Bank"})
+	}))
+	defer server.Close()
+
+	client, err := NewClientValidated(server.URL+"/", "test-key")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.GetBankInfo(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestClient_BatchTransfers_PerItemErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/external/transfers/batch" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"transfers": [{"transfer_id": "nw-1", "status": "PENDING", "reference_number": "REF001"}],
+			"errors": [{"index": 1, "reference_number": "REF002", "error_code": "INVALID_ACCOUNT", "error_message": "Destination account is closed"}],
+			"total_count": 2, "success_count": 1, "failed_count": 1
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key")
+	result, err := client.BatchTransfers(context.Background(), BatchTransferRequest{
+		Transfers: []TransferRequest{{ReferenceNumber: "REF001"}, {ReferenceNumber: "REF002"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Transfers) != 1 || result.Transfers[0].TransferID != "nw-1" {
+		t.Errorf("unexpected accepted transfers: %+v", result.Transfers)
+	}
+	if len(result.Errors) != 1 {
+		t.Fatalf("expected 1 item error, got %d", len(result.Errors))
+	}
+	itemErr := result.Errors[0]
+	if itemErr.Index != 1 || itemErr.ErrorCode != "INVALID_ACCOUNT" || itemErr.ErrorMessage != "Destination account is closed" {
+		t.Errorf("unexpected item error: %+v", itemErr)
+	}
+	if result.FailedCount != 1 || result.SuccessCount != 1 {
+		t.Errorf("unexpected counts: success=%d failed=%d", result.SuccessCount, result.FailedCount)
+	}
+}
+
+func TestClient_FallbackAPIKey(t *testing.T) {
+	tests := map[string]struct {
+		accepted   map[string]bool
+		wantKey    APIKeyID
+		wantStatus int
+		wantAuths  []string
+	}{
+		"primary ok":               {map[string]bool{"primary-key": true}, APIKeyPrimary, 0, []string{"primary-key"}},
+		"primary 401 secondary ok": {map[string]bool{"secondary-key": true}, APIKeySecondary, 0, []string{"primary-key", "secondary-key"}},
+		"both rejected":            {map[string]bool{}, "", http.StatusUnauthorized, []string{"primary-key", "secondary-key"}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var auths []string
+			var bodies []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				key := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+				body, _ := io.ReadAll(r.Body)
+				auths = append(auths, key)
+				bodies = append(bodies, string(body))
+				if !tt.accepted[key] {
+					w.WriteHeader(http.StatusUnauthorized)
+					_, _ = w.Write([]byte(`{"error":"invalid api key"}`))
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(TransferValidationResponse{Valid: true})
+			}))
+			defer server.Close()
+
+			var hooked []APIKeyID
+			client := NewClient(server.URL, "primary-key",
+				WithFallbackAPIKey("secondary-key"),
+				WithAPIKeyHook(func(key APIKeyID) { hooked = append(hooked, key) }))
+			_, err := client.ValidateTransfer(context.Background(), TransferRequest{ReferenceNumber: "REF-1"})
+
+			if strings.Join(auths, ",") != strings.Join(tt.wantAuths, ",") {
+				t.Errorf("expected keys %v to be tried, got %v", tt.wantAuths, auths)
+			}
+			for i, body := range bodies {
+				if !strings.Contains(body, "REF-1") {
+					t.Errorf("request %d was sent without its body: %q", i, body)
+				}
+			}
+			if tt.wantStatus != 0 {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantStatus {
+					t.Fatalf("expected HTTP %d, got %v", tt.wantStatus, err)
+				}
+				if len(hooked) != 0 || client.ActiveAPIKey() != APIKeyPrimary {
+					t.Errorf("expected no key recorded for a failed request, got %v and %s", hooked, client.ActiveAPIKey())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if client.ActiveAPIKey() != tt.wantKey {
+				t.Errorf("expected active key %s, got %s", tt.wantKey, client.ActiveAPIKey())
+			}
+			if len(hooked) != 1 || hooked[0] != tt.wantKey {
+				t.Errorf("expected the hook to report %s once, got %v", tt.wantKey, hooked)
+			}
+		})
+	}
+}
+
+func TestClient_NoFallbackAPIKey(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "primary-key")
+	if _, err := client.GetBankInfo(context.Background()); err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != 1 {
+		t.Errorf("expected a single request without a fallback key, got %d", calls)
+	}
+}
+
+func TestClient_StrictDecoding_ReportsDriftAndKeepsResult(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"transfer_id":"nw-1","status":"PROCESSING","settlement_rail":"FEDNOW"}`))
+	}))
+	defer server.Close()
+
+	var drifts []SchemaDrift
+	var logs bytes.Buffer
+	client := NewClient(server.URL, "test-key",
+		WithStrictDecoding(true),
+		WithSchemaDriftHook(func(drift SchemaDrift) { drifts = append(drifts, drift) }),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+
+	result, err := client.GetTransferStatus(context.Background(), "nw-1")
+	if err != nil {
+		t.Fatalf("expected drift not to fail the call, got %v", err)
+	}
+	if result.TransferID != "nw-1" || result.Status != "PROCESSING" {
+		t.Errorf("expected the parsed result, got %+v", result)
+	}
+	if len(drifts) != 1 || drifts[0] != (SchemaDrift{Resource: "transfer status", Field: "settlement_rail"}) {
+		t.Errorf("expected settlement_rail reported as drift, got %+v", drifts)
+	}
+	if !strings.Contains(logs.String(), "settlement_rail") {
+		t.Errorf("expected the drift logged, got %q", logs.String())
+	}
+}
+
+func TestClient_StrictDecoding_QuietWhenSchemaMatches(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"transfer_id":"nw-1","status":"PROCESSING"}`))
+	}))
+	defer server.Close()
+
+	called := false
+	hook := WithSchemaDriftHook(func(SchemaDrift) { called = true })
+	for _, strict := range []bool{true, false} {
+		client := NewClient(server.URL, "test-key", WithStrictDecoding(strict), hook)
+		if _, err := client.GetTransferStatus(context.Background(), "nw-1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if called {
+		t.Error("expected no drift for a matching response")
+	}
+
+	// Without strict decoding unknown fields go unnoticed
+	extra := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"transfer_id":"nw-1","status":"PROCESSING","settlement_rail":"FEDNOW"}`))
+	}))
+	defer extra.Close()
+	if _, err := NewClient(extra.URL, "test-key", hook).GetTransferStatus(context.Background(), "nw-1"); err != nil || called {
+		t.Errorf("expected lenient decoding without drift checks, got err=%v called=%v", err, called)
+	}
+}
+
+func TestClient_Quota_TracksRateLimitHeaders(t *testing.T) {
+	reset := time.Now().Add(time.Minute).Unix()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/health" {
+			w.Header().Set("RateLimit-Limit", "100")
+			w.Header().Set("RateLimit-Remaining", "99")
+			w.Header().Set("RateLimit-Reset", "30")
+		} else {
+			w.Header().Set("X-RateLimit-Limit", "100")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key")
+	if client.Quota() != nil {
+		t.Fatal("expected no quota before any response")
+	}
+
+	if _, err := client.Health(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	quota := client.Quota()
+	if quota == nil || quota.Limit != 100 || quota.Remaining != 99 || quota.ResetAt == nil ||
+		quota.ResetAt.Sub(quota.ObservedAt) != 30*time.Second {
+		t.Fatalf("expected the standard headers with a relative reset, got %+v", quota)
+	}
+
+	// Rejected responses report the quota too, here with an absolute reset
+	if _, err := client.GetBankInfo(context.Background()); err == nil {
+		t.Fatal("expected the 429 to fail the call")
+	}
+	quota = client.Quota()
+	if quota == nil || quota.Remaining != 0 || quota.ResetAt == nil || quota.ResetAt.Unix() != reset {
+		t.Errorf("expected the X-RateLimit headers with an epoch reset, got %+v", quota)
+	}
+}
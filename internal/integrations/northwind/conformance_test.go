@@ -0,0 +1,387 @@
+//go:build conformance
+
+// Conformance tests run the client against a live NorthWind sandbox before a partner API
+// upgrade. They are excluded from normal builds; run them with
+//
+//	NORTHWIND_SANDBOX_BASE_URL=... NORTHWIND_SANDBOX_API_KEY=... go test -tags conformance -run Conformance -v ./internal/integrations/northwind/
+//
+// or through cmd/northwind-conformance. Sandbox state is reset before and after each test.
+package northwind
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	sandboxBaseURLEnv = "NORTHWIND_SANDBOX_BASE_URL"
+	sandboxAPIKeyEnv  = "NORTHWIND_SANDBOX_API_KEY"
+	// sandboxBadAccountEnv optionally overrides the account NorthWind documents as failing
+	// validation, as account:routing
+	sandboxBadAccountEnv = "NORTHWIND_SANDBOX_BAD_ACCOUNT"
+
+	conformanceTimeout      = 2 * time.Minute
+	conformancePollInterval = 2 * time.Second
+)
+
+// defaultBadAccount is an account number and routing number that fail validation: the routing
+// number's ABA checksum is wrong and no sandbox account uses it
+var defaultBadAccount = AccountValidationRequest{AccountNumber: "0000000000", RoutingNumber: "123456789", AccountType: "CHECKING"}
+
+// exchange is one request/response pair seen on the wire
+type exchange struct {
+	method       string
+	url          string
+	requestBody  []byte
+	status       int
+	header       http.Header
+	responseBody []byte
+	err          error
+}
+
+func (e exchange) String() string {
+	if e.err != nil {
+		return fmt.Sprintf("%s %s\n  request: %s\n  error: %v", e.method, e.url, e.requestBody, e.err)
+	}
+	return fmt.Sprintf("%s %s\n  request: %s\n  response: HTTP %d %s", e.method, e.url, e.requestBody, e.status, e.responseBody)
+}
+
+// recordingTransport keeps every exchange so a failing test can show what was sent and received
+type recordingTransport struct {
+	next      http.RoundTripper
+	mu        sync.Mutex
+	exchanges []exchange
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ex := exchange{method: req.Method, url: req.URL.String()}
+	if req.Body != nil {
+		ex.requestBody, _ = io.ReadAll(req.Body)
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(ex.requestBody))
+	}
+
+	resp, err := rt.next.RoundTrip(req)
+	if err != nil {
+		ex.err = err
+	} else {
+		ex.status = resp.StatusCode
+		ex.header = resp.Header.Clone()
+		ex.responseBody, _ = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(ex.responseBody))
+	}
+
+	rt.mu.Lock()
+	rt.exchanges = append(rt.exchanges, ex)
+	rt.mu.Unlock()
+	return resp, err
+}
+
+func (rt *recordingTransport) last() exchange {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if len(rt.exchanges) == 0 {
+		return exchange{}
+	}
+	return rt.exchanges[len(rt.exchanges)-1]
+}
+
+// newConformanceClient returns a sandbox client without retries, so every call is exactly one
+// recorded exchange. The sandbox is reset around the test and the exchanges are logged if it fails.
+func newConformanceClient(t *testing.T, apiKey string) (*Client, *recordingTransport) {
+	t.Helper()
+	baseURL := os.Getenv(sandboxBaseURLEnv)
+	if baseURL == "" || os.Getenv(sandboxAPIKeyEnv) == "" {
+		t.Skipf("%s and %s must be set to run conformance tests", sandboxBaseURLEnv, sandboxAPIKeyEnv)
+	}
+	if apiKey == "" {
+		apiKey = os.Getenv(sandboxAPIKeyEnv)
+	}
+
+	c, err := NewClientValidated(baseURL, apiKey)
+	if err != nil {
+		t.Fatalf("invalid sandbox configuration: %v", err)
+	}
+	recorder := &recordingTransport{next: http.DefaultTransport}
+	c.httpClient.Transport = recorder
+
+	t.Cleanup(func() {
+		if !t.Failed() {
+			return
+		}
+		recorder.mu.Lock()
+		defer recorder.mu.Unlock()
+		for i, ex := range recorder.exchanges {
+			t.Logf("exchange %d: %s", i+1, ex)
+		}
+	})
+	if apiKey == os.Getenv(sandboxAPIKeyEnv) {
+		resetSandbox(t, c)
+		t.Cleanup(func() { resetSandbox(t, c) })
+	}
+	return c, recorder
+}
+
+func resetSandbox(t *testing.T, c *Client) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := c.Reset(ctx); err != nil {
+		t.Errorf("sandbox reset failed: %v", err)
+	}
+}
+
+func conformanceContext(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), conformanceTimeout)
+	t.Cleanup(cancel)
+	return WithTraceID(ctx, "conformance-"+uuid.NewString())
+}
+
+// sandboxAccounts returns the sandbox's external accounts, failing the test if there are fewer than n
+func sandboxAccounts(t *testing.T, ctx context.Context, c *Client, n int) []ExternalAccount {
+	t.Helper()
+	page, err := c.ListAccounts(ctx, 10, 0, "", "")
+	if err != nil {
+		t.Fatalf("ListAccounts: %v", err)
+	}
+	if len(page.Accounts) < n {
+		t.Fatalf("sandbox has %d accounts, need at least %d", len(page.Accounts), n)
+	}
+	return page.Accounts
+}
+
+// requireAPIError asserts err is a NorthWind API error with the given status and a parseable
+// error body carrying a code or message
+func requireAPIError(t *testing.T, err error, status int) {
+	t.Helper()
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected NorthWind API error with HTTP %d, got %v", status, err)
+	}
+	if apiErr.StatusCode != status {
+		t.Errorf("expected HTTP %d, got %d", status, apiErr.StatusCode)
+	}
+	if apiErr.Parsed == nil {
+		t.Fatalf("error body is not JSON: %s", apiErr.Body)
+	}
+	if apiErr.Parsed.Error == "" && apiErr.Parsed.Message == "" && apiErr.Parsed.Code == "" {
+		t.Errorf("error body has none of error, message or code: %s", apiErr.Body)
+	}
+}
+
+func TestConformance_ReadEndpoints(t *testing.T) {
+	c, _ := newConformanceClient(t, "")
+	ctx := conformanceContext(t)
+
+	health, err := c.Health(ctx)
+	if err != nil {
+		t.Fatalf("Health: %v", err)
+	}
+	if health.Status == "" {
+		t.Error("Health: empty status")
+	}
+
+	info, err := c.GetBankInfo(ctx)
+	if err != nil {
+		t.Fatalf("GetBankInfo: %v", err)
+	}
+	if info.Name == "" || info.RoutingNumber == "" {
+		t.Errorf("GetBankInfo: missing name or routing number: %+v", info)
+	}
+
+	if _, err := c.GetDomains(ctx); err != nil {
+		t.Errorf("GetDomains: %v", err)
+	}
+	if _, err := c.ListTransfers(ctx, TransferListFilters{Limit: 5}); err != nil {
+		t.Errorf("ListTransfers: %v", err)
+	}
+
+	account := sandboxAccounts(t, ctx, c, 1)[0]
+	balance, err := c.GetAccountBalance(ctx, account.AccountNumber)
+	if err != nil {
+		t.Fatalf("GetAccountBalance: %v", err)
+	}
+	if balance.AccountNumber != account.AccountNumber || balance.Currency == "" {
+		t.Errorf("GetAccountBalance: unexpected balance %+v", balance)
+	}
+}
+
+func TestConformance_AccountValidation(t *testing.T) {
+	c, _ := newConformanceClient(t, "")
+	ctx := conformanceContext(t)
+
+	good := sandboxAccounts(t, ctx, c, 1)[0]
+	bad := defaultBadAccount
+	if raw := os.Getenv(sandboxBadAccountEnv); raw != "" {
+		account, routing, ok := strings.Cut(raw, ":")
+		if !ok {
+			t.Fatalf("%s must be account:routing, got %q", sandboxBadAccountEnv, raw)
+		}
+		bad = AccountValidationRequest{AccountNumber: account, RoutingNumber: routing, AccountType: "CHECKING"}
+	}
+
+	t.Run("known good", func(t *testing.T) {
+		result, err := c.ValidateAccount(ctx, AccountValidationRequest{
+			AccountNumber: good.AccountNumber,
+			RoutingNumber: good.RoutingNumber,
+			AccountType:   good.AccountType,
+		})
+		if err != nil {
+			t.Fatalf("ValidateAccount: %v", err)
+		}
+		if !result.Valid {
+			t.Errorf("expected %s/%s to validate: %+v", good.AccountNumber, good.RoutingNumber, result)
+		}
+	})
+
+	t.Run("known bad", func(t *testing.T) {
+		result, err := c.ValidateAccount(ctx, bad)
+		var apiErr *APIError
+		switch {
+		case errors.As(err, &apiErr):
+			// Rejecting the request outright is as good as answering valid: false
+			if apiErr.StatusCode != http.StatusBadRequest && apiErr.StatusCode != http.StatusUnprocessableEntity {
+				t.Errorf("expected HTTP 400 or 422 for an invalid account, got %d", apiErr.StatusCode)
+			}
+		case err != nil:
+			t.Fatalf("ValidateAccount: %v", err)
+		case result.Valid:
+			t.Errorf("expected %s/%s to fail validation", bad.AccountNumber, bad.RoutingNumber)
+		}
+	})
+}
+
+func TestConformance_TransferLifecycle(t *testing.T) {
+	c, _ := newConformanceClient(t, "")
+	ctx := conformanceContext(t)
+
+	accounts := sandboxAccounts(t, ctx, c, 2)
+	req := TransferRequest{
+		Amount:          1.23,
+		Currency:        "USD",
+		Description:     "conformance lifecycle",
+		Direction:       "OUTBOUND",
+		TransferType:    "ACH",
+		ReferenceNumber: "CONF-" + strings.ToUpper(uuid.NewString()[:8]),
+		SourceAccount: AccountDetails{
+			AccountHolderName: accounts[0].AccountHolderName,
+			AccountNumber:     accounts[0].AccountNumber,
+			RoutingNumber:     accounts[0].RoutingNumber,
+		},
+		DestinationAccount: AccountDetails{
+			AccountHolderName: accounts[1].AccountHolderName,
+			AccountNumber:     accounts[1].AccountNumber,
+			RoutingNumber:     accounts[1].RoutingNumber,
+		},
+	}
+
+	validation, err := c.ValidateTransfer(ctx, req)
+	if err != nil {
+		t.Fatalf("ValidateTransfer: %v", err)
+	}
+	if !validation.Valid {
+		t.Fatalf("ValidateTransfer: transfer rejected: %+v", validation.Issues)
+	}
+
+	created, err := c.InitiateTransfer(ctx, req)
+	if err != nil {
+		t.Fatalf("InitiateTransfer: %v", err)
+	}
+	if created.TransferID == "" || created.ReferenceNumber != req.ReferenceNumber {
+		t.Fatalf("InitiateTransfer: unexpected response %+v", created)
+	}
+
+	// Poll until NorthWind reports the transfer back with a known status
+	var status *TransferStatusResponse
+	for status == nil {
+		current, err := c.GetTransferStatus(ctx, created.TransferID)
+		if err != nil {
+			t.Fatalf("GetTransferStatus: %v", err)
+		}
+		switch current.Status {
+		case "PENDING", "PROCESSING", "COMPLETED", "FAILED", "CANCELLED", "REVERSED":
+			status = current
+		default:
+			select {
+			case <-ctx.Done():
+				t.Fatalf("GetTransferStatus: status %q never settled", current.Status)
+			case <-time.After(conformancePollInterval):
+			}
+		}
+	}
+	if status.TransferID != created.TransferID || status.Amount != req.Amount {
+		t.Errorf("GetTransferStatus: does not match the created transfer: %+v", status)
+	}
+	if status.Status != "PENDING" && status.Status != "PROCESSING" {
+		t.Skipf("transfer was %s before it could be cancelled; cancel not exercised", status.Status)
+	}
+
+	cancelled, err := c.CancelTransfer(ctx, created.TransferID, "conformance test")
+	if err != nil {
+		t.Fatalf("CancelTransfer: %v", err)
+	}
+	if cancelled.Status != "CANCELLED" {
+		t.Errorf("CancelTransfer: expected CANCELLED, got %s", cancelled.Status)
+	}
+	after, err := c.GetTransferStatus(ctx, created.TransferID)
+	if err != nil {
+		t.Fatalf("GetTransferStatus after cancel: %v", err)
+	}
+	if after.Status != "CANCELLED" {
+		t.Errorf("GetTransferStatus after cancel: expected CANCELLED, got %s", after.Status)
+	}
+}
+
+func TestConformance_ErrorShapes(t *testing.T) {
+	t.Run("400 invalid transfer", func(t *testing.T) {
+		c, _ := newConformanceClient(t, "")
+		_, err := c.InitiateTransfer(conformanceContext(t), TransferRequest{})
+		requireAPIError(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("401 bad API key", func(t *testing.T) {
+		c, _ := newConformanceClient(t, "conformance-invalid-key")
+		_, err := c.ListAccounts(conformanceContext(t), 1, 0, "", "")
+		requireAPIError(t, err, http.StatusUnauthorized)
+	})
+
+	t.Run("404 unknown transfer", func(t *testing.T) {
+		c, _ := newConformanceClient(t, "")
+		_, err := c.GetTransferStatus(conformanceContext(t), uuid.NewString())
+		requireAPIError(t, err, http.StatusNotFound)
+	})
+}
+
+func TestConformance_RateLimitHeaders(t *testing.T) {
+	c, recorder := newConformanceClient(t, "")
+	if _, err := c.GetBankInfo(conformanceContext(t)); err != nil {
+		t.Fatalf("GetBankInfo: %v", err)
+	}
+
+	header := recorder.last().header
+	for _, names := range [][]string{
+		{"X-RateLimit-Limit", "RateLimit-Limit"},
+		{"X-RateLimit-Remaining", "RateLimit-Remaining"},
+	} {
+		found := false
+		for _, name := range names {
+			found = found || header.Get(name) != ""
+		}
+		if !found {
+			t.Errorf("response has none of %v", names)
+		}
+	}
+}
@@ -1,26 +1,30 @@
 package northwind
 
 import (
+	"strings"
 	"time"
+)
 
-	"github.com/array/banking-api/internal/models"
+// Transfer statuses NorthWind reports, as MapStatus returns them
+const (
+	TransferStatusPending    = "PENDING"
+	TransferStatusProcessing = "PROCESSING"
+	TransferStatusCompleted  = "COMPLETED"
+	TransferStatusFailed     = "FAILED"
+	TransferStatusCancelled  = "CANCELLED"
+	TransferStatusReversed   = "REVERSED"
 )
 
-// MapStatus maps NorthWind API status strings to our local status constants
-func MapStatus(apiStatus string) string {
-	switch apiStatus {
-	case "COMPLETED", "completed":
-		return models.NWTransferStatusCompleted
-	case "FAILED", "failed":
-		return models.NWTransferStatusFailed
-	case "CANCELLED", "cancelled":
-		return models.NWTransferStatusCancelled
-	case "REVERSED", "reversed":
-		return models.NWTransferStatusReversed
-	case "PROCESSING", "processing":
-		return models.NWTransferStatusProcessing
+// MapStatus maps a NorthWind API status string to its TransferStatus constant, ignoring case. ok
+// is false for a status we do not recognise, so a status NorthWind adds is never mistaken for one
+// we know.
+func MapStatus(apiStatus string) (status string, ok bool) {
+	switch status := strings.ToUpper(apiStatus); status {
+	case TransferStatusPending, TransferStatusProcessing, TransferStatusCompleted,
+		TransferStatusFailed, TransferStatusCancelled, TransferStatusReversed:
+		return status, true
 	default:
-		return models.NWTransferStatusPending
+		return "", false
 	}
 }
 
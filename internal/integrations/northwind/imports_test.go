@@ -0,0 +1,39 @@
+package northwind
+
+import (
+	"go/build"
+	"strings"
+	"testing"
+)
+
+// TestPackageImports_StayOutsideTheAPI keeps the client free of the banking API's own packages, so
+// it can be extracted into a module of its own. Tests are held to the same rule.
+func TestPackageImports_StayOutsideTheAPI(t *testing.T) {
+	const apiModule = "github.com/array/banking-api/"
+
+	pkg, err := build.ImportDir(".", 0)
+	if err != nil {
+		t.Fatalf("failed to read package: %v", err)
+	}
+	for _, imports := range [][]string{pkg.Imports, pkg.TestImports, pkg.XTestImports} {
+		for _, path := range imports {
+			if strings.HasPrefix(path, apiModule) {
+				t.Errorf("northwind imports %s; the client must not depend on the banking API's packages", path)
+			}
+		}
+	}
+}
+
+func TestMapStatus(t *testing.T) {
+	for _, raw := range []string{"PENDING", "processing", "Completed", "FAILED", "CANCELLED", "REVERSED"} {
+		status, ok := MapStatus(raw)
+		if !ok || status != strings.ToUpper(raw) {
+			t.Errorf("MapStatus(%q) = %q, %v; want %q, true", raw, status, ok, strings.ToUpper(raw))
+		}
+	}
+	for _, raw := range []string{"", "ON_HOLD", "INITIATION_PENDING", "SCHEDULED"} {
+		if status, ok := MapStatus(raw); ok {
+			t.Errorf("MapStatus(%q) = %q; want it unrecognised", raw, status)
+		}
+	}
+}
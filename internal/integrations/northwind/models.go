@@ -18,6 +18,7 @@ type TransferRequest struct {
 	Description        string         `json:"description,omitempty"`
 	Direction          string         `json:"direction"`
 	TransferType       string         `json:"transfer_type"`
+	Priority           string         `json:"priority,omitempty"`
 	ReferenceNumber    string         `json:"reference_number"`
 	ScheduledDate      string         `json:"scheduled_date,omitempty"`
 	SourceAccount      AccountDetails `json:"source_account"`
@@ -114,6 +115,7 @@ type TransferResponse struct {
 	Currency               string         `json:"currency"`
 	Direction              string         `json:"direction"`
 	TransferType           string         `json:"transfer_type"`
+	Priority               string         `json:"priority,omitempty"`
 	ReferenceNumber        string         `json:"reference_number"`
 	Description            string         `json:"description,omitempty"`
 	ScheduledDate          string         `json:"scheduled_date,omitempty"`
@@ -144,12 +146,23 @@ type TransferValidationIssue struct {
 	Severity string `json:"severity"` // "error" or "warning"
 }
 
-// BatchTransferResponse represents a batch transfer response
+// BatchTransferResponse represents a batch transfer response. Transfers lists the accepted
+// transfers; Errors says why each rejected one failed, by its position in the request.
 type BatchTransferResponse struct {
-	Transfers    []TransferResponse `json:"transfers"`
-	TotalCount   int                `json:"total_count"`
-	SuccessCount int                `json:"success_count"`
-	FailedCount  int                `json:"failed_count"`
+	Transfers    []TransferResponse       `json:"transfers"`
+	Errors       []BatchTransferItemError `json:"errors,omitempty"`
+	TotalCount   int                      `json:"total_count"`
+	SuccessCount int                      `json:"success_count"`
+	FailedCount  int                      `json:"failed_count"`
+}
+
+// BatchTransferItemError is the rejection of one transfer in a batch. Index is the transfer's
+// zero-based position in the BatchTransferRequest.
+type BatchTransferItemError struct {
+	Index           int    `json:"index"`
+	ReferenceNumber string `json:"reference_number,omitempty"`
+	ErrorCode       string `json:"error_code"`
+	ErrorMessage    string `json:"error_message"`
 }
 
 // TransferStatusResponse represents a transfer status response from NorthWind
@@ -177,14 +190,14 @@ type PaginatedResponse struct {
 	Offset     int         `json:"offset,omitempty"`
 }
 
-// AccountListResponse for listing accounts
+// AccountListResponse for listing accounts. TotalCount is nil when NorthWind did not report one.
 type AccountListResponse struct {
 	Accounts   []ExternalAccount `json:"accounts"`
-	TotalCount int               `json:"total_count,omitempty"`
+	TotalCount *int              `json:"total_count,omitempty"`
 }
 
-// TransferListResponse for listing transfers
+// TransferListResponse for listing transfers. TotalCount is nil when NorthWind did not report one.
 type TransferListResponse struct {
 	Transfers  []TransferResponse `json:"transfers"`
-	TotalCount int                `json:"total_count,omitempty"`
+	TotalCount *int               `json:"total_count,omitempty"`
 }
@@ -0,0 +1,96 @@
+package northwind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+const (
+	// DefaultListPageSize is the page size ListAllTransfers and ListAllAccounts ask for when the
+	// caller gives none
+	DefaultListPageSize = 100
+	// DefaultMaxListPages caps how many pages one ListAll call fetches unless WithMaxListPages
+	// says otherwise
+	DefaultMaxListPages = 1000
+)
+
+// ErrTooManyPages is returned by ListAllTransfers and ListAllAccounts when NorthWind still had
+// more after the client's page cap, so the walk stopped short of the end of the list
+var ErrTooManyPages = errors.New("northwind list has more pages than the client fetches in one walk")
+
+// WithMaxListPages caps how many pages one ListAllTransfers or ListAllAccounts call fetches, so a
+// NorthWind that never returns a short page cannot keep a walk going forever. Non-positive values
+// keep DefaultMaxListPages.
+func WithMaxListPages(n int) ClientOption {
+	return func(c *Client) {
+		if n > 0 {
+			c.maxListPages = n
+		}
+	}
+}
+
+// ListAllTransfers walks every transfer matching filters, calling fn with each non-empty page in
+// order. It starts at filters.Offset and advances by filters.Limit (DefaultListPageSize when not
+// positive) until NorthWind returns a short page or, when it reports one, the total count is
+// reached. An error from fn stops the walk and is returned as is.
+func (c *Client) ListAllTransfers(ctx context.Context, filters TransferListFilters, fn func([]TransferResponse) error) error {
+	limit := listPageSize(filters.Limit)
+	return c.listAll(filters.Offset, limit, func(offset int) (int, *int, error) {
+		page := filters
+		page.Limit, page.Offset = limit, offset
+		resp, err := c.ListTransfers(ctx, page)
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to list transfers at offset %d: %w", offset, err)
+		}
+		if len(resp.Transfers) > 0 {
+			if err := fn(resp.Transfers); err != nil {
+				return 0, nil, err
+			}
+		}
+		return len(resp.Transfers), resp.TotalCount, nil
+	})
+}
+
+// ListAllAccounts walks every external account of accountType and status, either of which may
+// be empty, like ListAllTransfers, asking for limit accounts a page
+func (c *Client) ListAllAccounts(ctx context.Context, limit int, accountType, status string, fn func([]ExternalAccount) error) error {
+	limit = listPageSize(limit)
+	return c.listAll(0, limit, func(offset int) (int, *int, error) {
+		resp, err := c.ListAccounts(ctx, limit, offset, accountType, status)
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to list accounts at offset %d: %w", offset, err)
+		}
+		if len(resp.Accounts) > 0 {
+			if err := fn(resp.Accounts); err != nil {
+				return 0, nil, err
+			}
+		}
+		return len(resp.Accounts), resp.TotalCount, nil
+	})
+}
+
+// listAll fetches pages of limit items from offset on. fetchPage handles the page at an offset
+// and returns its size and NorthWind's total count, if it sent one.
+func (c *Client) listAll(offset, limit int, fetchPage func(offset int) (int, *int, error)) error {
+	for pages := 0; ; pages++ {
+		if pages == c.maxListPages {
+			return fmt.Errorf("%w: stopped after %d pages at offset %d", ErrTooManyPages, pages, offset)
+		}
+		n, total, err := fetchPage(offset)
+		if err != nil {
+			return err
+		}
+		offset += limit
+		if n < limit || (total != nil && offset >= *total) {
+			return nil
+		}
+	}
+}
+
+func listPageSize(limit int) int {
+	if limit <= 0 {
+		return DefaultListPageSize
+	}
+	return limit
+}
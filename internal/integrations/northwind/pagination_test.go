@@ -0,0 +1,184 @@
+package northwind
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// pagedServer serves total numbered items from path, honouring limit and offset like NorthWind,
+// and records the offsets asked for
+type pagedServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	offsets []int
+}
+
+func newPagedServer(t *testing.T, path string, total int, encode func(ids []string) interface{}) *pagedServer {
+	t.Helper()
+	s := &pagedServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			t.Errorf("expected %s, got %s", path, r.URL.Path)
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		s.mu.Lock()
+		s.offsets = append(s.offsets, offset)
+		s.mu.Unlock()
+
+		var ids []string
+		for i := offset; i < total && i < offset+limit; i++ {
+			ids = append(ids, "item-"+strconv.Itoa(i))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(encode(ids))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func transferPage(ids []string) interface{} {
+	transfers := make([]TransferResponse, len(ids))
+	for i, id := range ids {
+		transfers[i] = TransferResponse{TransferID: id}
+	}
+	return transfers
+}
+
+func TestClient_ListAllTransfers_VisitsPagesInOrder(t *testing.T) {
+	server := newPagedServer(t, "/external/transfers", 25, transferPage)
+	client := NewClient(server.URL, "test-key")
+
+	var seen []string
+	var pageSizes []int
+	err := client.ListAllTransfers(context.Background(), TransferListFilters{Status: "PENDING", Limit: 10}, func(page []TransferResponse) error {
+		pageSizes = append(pageSizes, len(page))
+		for _, tr := range page {
+			seen = append(seen, tr.TransferID)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pageSizes) != 3 || pageSizes[0] != 10 || pageSizes[1] != 10 || pageSizes[2] != 5 {
+		t.Errorf("expected pages of 10, 10 and 5, got %v", pageSizes)
+	}
+	for i, id := range seen {
+		if id != "item-"+strconv.Itoa(i) {
+			t.Fatalf("expected items in order, got %v", seen)
+		}
+	}
+	if len(seen) != 25 {
+		t.Errorf("expected 25 transfers, got %d", len(seen))
+	}
+	if len(server.offsets) != 3 || server.offsets[1] != 10 || server.offsets[2] != 20 {
+		t.Errorf("expected offsets 0, 10, 20, got %v", server.offsets)
+	}
+}
+
+func TestClient_ListAllTransfers_StopsAtTotalCount(t *testing.T) {
+	server := newPagedServer(t, "/external/transfers", 20, func(ids []string) interface{} {
+		total := 20
+		return TransferListResponse{Transfers: transferPage(ids).([]TransferResponse), TotalCount: &total}
+	})
+	client := NewClient(server.URL, "test-key")
+
+	pages := 0
+	err := client.ListAllTransfers(context.Background(), TransferListFilters{Limit: 10}, func([]TransferResponse) error {
+		pages++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pages != 2 || len(server.offsets) != 2 {
+		t.Errorf("expected two full pages and no request past the total, got %d pages from %d requests", pages, len(server.offsets))
+	}
+}
+
+func TestClient_ListAllTransfers_CallbackErrorStops(t *testing.T) {
+	server := newPagedServer(t, "/external/transfers", 25, transferPage)
+	client := NewClient(server.URL, "test-key")
+
+	errStop := errors.New("stop")
+	pages := 0
+	err := client.ListAllTransfers(context.Background(), TransferListFilters{Limit: 10}, func([]TransferResponse) error {
+		pages++
+		return errStop
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("expected the callback's error, got %v", err)
+	}
+	if pages != 1 || len(server.offsets) != 1 {
+		t.Errorf("expected the walk to stop after the first page, got %d pages from %d requests", pages, len(server.offsets))
+	}
+}
+
+func TestClient_ListAllTransfers_PageCap(t *testing.T) {
+	server := newPagedServer(t, "/external/transfers", 100, transferPage)
+	client := NewClient(server.URL, "test-key", WithMaxListPages(2))
+
+	pages := 0
+	err := client.ListAllTransfers(context.Background(), TransferListFilters{Limit: 10}, func([]TransferResponse) error {
+		pages++
+		return nil
+	})
+	if !errors.Is(err, ErrTooManyPages) {
+		t.Fatalf("expected ErrTooManyPages, got %v", err)
+	}
+	if pages != 2 || len(server.offsets) != 2 {
+		t.Errorf("expected to stop at the cap of 2 pages, got %d pages from %d requests", pages, len(server.offsets))
+	}
+}
+
+func TestClient_ListAllTransfers_RequestErrorStops(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(APIErrorResponse{Message: "bad filter"})
+	}))
+	defer server.Close()
+
+	err := NewClient(server.URL, "test-key").ListAllTransfers(context.Background(), TransferListFilters{}, func([]TransferResponse) error {
+		t.Error("callback called for a failed page")
+		return nil
+	})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected NorthWind's error, got %v", err)
+	}
+}
+
+func TestClient_ListAllAccounts_VisitsPagesInOrder(t *testing.T) {
+	server := newPagedServer(t, "/external/accounts", 7, func(ids []string) interface{} {
+		accounts := make([]ExternalAccount, len(ids))
+		for i, id := range ids {
+			accounts[i] = ExternalAccount{AccountNumber: id}
+		}
+		return AccountListResponse{Accounts: accounts}
+	})
+	client := NewClient(server.URL, "test-key")
+
+	var seen []string
+	err := client.ListAllAccounts(context.Background(), 3, "", "", func(page []ExternalAccount) error {
+		for _, account := range page {
+			seen = append(seen, account.AccountNumber)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(seen) != 7 || seen[0] != "item-0" || seen[6] != "item-6" {
+		t.Errorf("expected accounts item-0 to item-6 in order, got %v", seen)
+	}
+	if len(server.offsets) != 3 {
+		t.Errorf("expected three pages, got offsets %v", server.offsets)
+	}
+}
@@ -0,0 +1,87 @@
+package northwind
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Quota is NorthWind's rate limit as reported on its most recent response. ResetAt is nil when
+// the response did not say when the window resets.
+type Quota struct {
+	Limit      int        `json:"limit"`
+	Remaining  int        `json:"remaining"`
+	ResetAt    *time.Time `json:"reset_at,omitempty"`
+	ObservedAt time.Time  `json:"observed_at"`
+}
+
+// epochResetThreshold separates reset headers holding a Unix time from ones holding seconds
+// until the reset: no window is longer than this many seconds
+const epochResetThreshold = 1_000_000_000
+
+// parseQuota reads the rate-limit headers of a response received at now, accepting both the
+// X-RateLimit-* and the standard RateLimit-* names. It reports false when the limit or the
+// remaining count is missing.
+func parseQuota(header http.Header, now time.Time) (*Quota, bool) {
+	limit, ok := rateLimitHeader(header, "Limit")
+	if !ok {
+		return nil, false
+	}
+	remaining, ok := rateLimitHeader(header, "Remaining")
+	if !ok {
+		return nil, false
+	}
+	quota := &Quota{Limit: limit, Remaining: remaining, ObservedAt: now}
+	if reset, ok := rateLimitHeader(header, "Reset"); ok {
+		resetAt := now.Add(time.Duration(reset) * time.Second)
+		if reset >= epochResetThreshold {
+			resetAt = time.Unix(int64(reset), 0)
+		}
+		quota.ResetAt = &resetAt
+	}
+	return quota, true
+}
+
+func rateLimitHeader(header http.Header, name string) (int, bool) {
+	for _, key := range []string{"X-RateLimit-" + name, "RateLimit-" + name} {
+		if value := header.Get(key); value != "" {
+			n, err := strconv.Atoi(value)
+			return n, err == nil
+		}
+	}
+	return 0, false
+}
+
+// parseRetryAfter reads the Retry-After header of a response received at now, given either as
+// seconds or as an HTTP date. A date already past is no delay. It reports false when the header
+// is missing or malformed.
+func parseRetryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	at, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	return max(at.Sub(now), 0), true
+}
+
+// recordQuota keeps the quota reported on a response, if it carried one
+func (c *Client) recordQuota(header http.Header) {
+	if quota, ok := parseQuota(header, time.Now()); ok {
+		c.quota.Store(quota)
+	}
+}
+
+// Quota returns NorthWind's rate limit as of the last response that reported it, or nil when no
+// response has yet
+func (c *Client) Quota() *Quota {
+	return c.quota.Load()
+}
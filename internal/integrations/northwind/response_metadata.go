@@ -0,0 +1,57 @@
+package northwind
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// RequestIDHeader carries NorthWind's ID for a response, which its support team asks for when a
+// ticket is opened about a call
+const RequestIDHeader = "X-NW-Request-ID"
+
+const responseMetadataKey contextKey = "response_metadata"
+
+// ResponseMetadata describes the last NorthWind response to calls made with a context from
+// WithResponseMetadata, retries included. The client fills it in, so one ResponseMetadata must
+// not be shared by concurrent calls.
+type ResponseMetadata struct {
+	// RequestID is the response's X-NW-Request-ID, empty when NorthWind sent none
+	RequestID  string
+	StatusCode int
+}
+
+// WithResponseMetadata returns a context whose NorthWind calls record their last response in
+// the returned ResponseMetadata, for calls that succeed as well as calls that fail
+func WithResponseMetadata(ctx context.Context) (context.Context, *ResponseMetadata) {
+	meta := &ResponseMetadata{}
+	return context.WithValue(ctx, responseMetadataKey, meta), meta
+}
+
+// ResponseMetadataFrom returns the ResponseMetadata ctx records responses in, or nil
+func ResponseMetadataFrom(ctx context.Context) *ResponseMetadata {
+	meta, _ := ctx.Value(responseMetadataKey).(*ResponseMetadata)
+	return meta
+}
+
+// RequestID returns NorthWind's request ID for a failed call, or "" when the call got no
+// response or NorthWind sent no ID
+func RequestID(err error) string {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.RequestID
+	}
+	var ctErr *ContentTypeError
+	if errors.As(err, &ctErr) {
+		return ctErr.RequestID
+	}
+	return ""
+}
+
+// recordResponse notes resp in the ResponseMetadata ctx carries, if any
+func recordResponse(ctx context.Context, resp *http.Response) {
+	if meta := ResponseMetadataFrom(ctx); meta != nil {
+		meta.RequestID = resp.Header.Get(RequestIDHeader)
+		meta.StatusCode = resp.StatusCode
+	}
+}
@@ -0,0 +1,58 @@
+package northwind
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TransferSnapshot is NorthWind's view of a transfer with every value parsed. A nil field is one
+// the response did not carry, or carried in a form that could not be parsed; Status is empty
+// when NorthWind reported a status we do not recognise, which RawStatus keeps.
+type TransferSnapshot struct {
+	Status                 string
+	RawStatus              string
+	ScheduledDate          *time.Time
+	InitiatedDate          *time.Time
+	ProcessingDate         *time.Time
+	ExpectedCompletionDate *time.Time
+	CompletedDate          *time.Time
+	Fee                    *decimal.Decimal
+	ExchangeRate           *decimal.Decimal
+	ErrorCode              *string
+	ErrorMessage           *string
+}
+
+// ToSnapshot parses a transfer response. A nil response is an empty snapshot.
+func ToSnapshot(resp *TransferResponse) TransferSnapshot {
+	if resp == nil {
+		return TransferSnapshot{}
+	}
+	status, _ := MapStatus(resp.Status)
+	snap := TransferSnapshot{
+		Status:                 status,
+		RawStatus:              resp.Status,
+		ScheduledDate:          ParseRFC3339Optional(resp.ScheduledDate),
+		InitiatedDate:          ParseRFC3339Optional(resp.InitiatedDate),
+		ProcessingDate:         ParseRFC3339Optional(resp.ProcessingDate),
+		ExpectedCompletionDate: ParseRFC3339Optional(resp.ExpectedCompletionDate),
+		CompletedDate:          ParseRFC3339Optional(resp.CompletedDate),
+	}
+	if resp.Fee != nil {
+		fee := decimal.NewFromFloat(*resp.Fee)
+		snap.Fee = &fee
+	}
+	if resp.ExchangeRate != nil {
+		rate := decimal.NewFromFloat(*resp.ExchangeRate)
+		snap.ExchangeRate = &rate
+	}
+	if resp.ErrorCode != "" {
+		code := resp.ErrorCode
+		snap.ErrorCode = &code
+	}
+	if resp.ErrorMessage != "" {
+		message := resp.ErrorMessage
+		snap.ErrorMessage = &message
+	}
+	return snap
+}
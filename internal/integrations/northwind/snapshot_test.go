@@ -0,0 +1,88 @@
+package northwind
+
+import (
+	"testing"
+	"time"
+)
+
+func TestToSnapshot_Empty(t *testing.T) {
+	for name, resp := range map[string]*TransferResponse{"nil": nil, "empty": {}} {
+		snap := ToSnapshot(resp)
+		if snap != (TransferSnapshot{}) {
+			t.Errorf("%s: expected an empty snapshot, got %+v", name, snap)
+		}
+	}
+}
+
+func TestToSnapshot_Partial(t *testing.T) {
+	snap := ToSnapshot(&TransferResponse{
+		Status:         "settling",
+		ProcessingDate: "2025-03-01T10:00:00Z",
+		CompletedDate:  "yesterday",
+		ErrorMessage:   "held for review",
+	})
+
+	if snap.Status != "" || snap.RawStatus != "settling" {
+		t.Errorf("expected an unknown status kept raw only, got %q / %q", snap.Status, snap.RawStatus)
+	}
+	if snap.ProcessingDate == nil || !snap.ProcessingDate.Equal(time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the processing date parsed, got %v", snap.ProcessingDate)
+	}
+	if snap.CompletedDate != nil || snap.InitiatedDate != nil || snap.ExpectedCompletionDate != nil || snap.ScheduledDate != nil {
+		t.Errorf("expected absent and unparseable dates to be nil, got %+v", snap)
+	}
+	if snap.Fee != nil || snap.ExchangeRate != nil || snap.ErrorCode != nil {
+		t.Errorf("expected absent values to be nil, got %+v", snap)
+	}
+	if snap.ErrorMessage == nil || *snap.ErrorMessage != "held for review" {
+		t.Errorf("expected the error message, got %v", snap.ErrorMessage)
+	}
+}
+
+func TestToSnapshot_Full(t *testing.T) {
+	fee, rate := 1.25, 0.9175
+	resp := &TransferResponse{
+		Status:                 "COMPLETED",
+		ScheduledDate:          "2025-03-01T00:00:00Z",
+		InitiatedDate:          "2025-03-01T09:00:00Z",
+		ProcessingDate:         "2025-03-01T10:00:00+02:00",
+		ExpectedCompletionDate: "2025-03-03T00:00:00Z",
+		CompletedDate:          "2025-03-02T16:30:00Z",
+		Fee:                    &fee,
+		ExchangeRate:           &rate,
+		ErrorCode:              "NONE",
+		ErrorMessage:           "none",
+	}
+	snap := ToSnapshot(resp)
+
+	if snap.Status != TransferStatusCompleted {
+		t.Errorf("expected COMPLETED, got %q", snap.Status)
+	}
+	dates := map[string]*time.Time{
+		"scheduled":           snap.ScheduledDate,
+		"initiated":           snap.InitiatedDate,
+		"processing":          snap.ProcessingDate,
+		"expected completion": snap.ExpectedCompletionDate,
+		"completed":           snap.CompletedDate,
+	}
+	for name, date := range dates {
+		if date == nil {
+			t.Errorf("expected the %s date parsed", name)
+		}
+	}
+	if snap.ProcessingDate != nil && !snap.ProcessingDate.Equal(time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the offset honoured, got %v", snap.ProcessingDate)
+	}
+	if snap.Fee == nil || snap.Fee.String() != "1.25" || snap.ExchangeRate == nil || snap.ExchangeRate.String() != "0.9175" {
+		t.Errorf("expected fee 1.25 and rate 0.9175, got %v and %v", snap.Fee, snap.ExchangeRate)
+	}
+	if snap.ErrorCode == nil || *snap.ErrorCode != "NONE" || snap.ErrorMessage == nil || *snap.ErrorMessage != "none" {
+		t.Errorf("expected the error code and message, got %v and %v", snap.ErrorCode, snap.ErrorMessage)
+	}
+
+	// The snapshot does not alias the response
+	resp.ErrorCode = "CHANGED"
+	if *snap.ErrorCode != "NONE" {
+		t.Errorf("expected the snapshot to own its error code, got %q", *snap.ErrorCode)
+	}
+}
@@ -0,0 +1,112 @@
+package northwind
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// statsLatencyWindow is how many of the most recent attempts ClientStats.P95Latency covers
+const statsLatencyWindow = 512
+
+// Error classes counted in ClientStats.ErrorsByClass
+const (
+	ErrorClassNetwork     = "network"
+	ErrorClassClient      = "4xx"
+	ErrorClassServer      = "5xx"
+	ErrorClassContentType = "content_type"
+)
+
+// MetricsHook is called once per request attempt, retries included, with the request's method
+// and route template, such as /external/transfers/{id}, the response status (0 when no response arrived), the attempt number counted from 1,
+// how long the attempt took and its error, nil when it succeeded. A retry with the fallback API
+// key starts counting attempts again.
+type MetricsHook func(method, route string, status int, attempt int, duration time.Duration, err error)
+
+// ClientStats summarises the request attempts a client has sent since it was created, retries
+// included. P95Latency covers only the most recent attempts.
+type ClientStats struct {
+	Requests      int64            `json:"requests"`
+	ErrorsByClass map[string]int64 `json:"errors_by_class"`
+	P95Latency    time.Duration    `json:"p95_latency"`
+}
+
+// statsRecorder aggregates attempts into ClientStats, keeping recent latencies in a ring buffer
+type statsRecorder struct {
+	mu        sync.Mutex
+	requests  int64
+	errors    map[string]int64
+	latencies [statsLatencyWindow]time.Duration
+	next      int
+	filled    bool
+}
+
+func (r *statsRecorder) record(duration time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests++
+	if err != nil {
+		if r.errors == nil {
+			r.errors = make(map[string]int64)
+		}
+		r.errors[errorClass(err)]++
+	}
+	r.latencies[r.next] = duration
+	r.next = (r.next + 1) % statsLatencyWindow
+	if r.next == 0 {
+		r.filled = true
+	}
+}
+
+func (r *statsRecorder) snapshot() ClientStats {
+	r.mu.Lock()
+	stats := ClientStats{Requests: r.requests, ErrorsByClass: make(map[string]int64, len(r.errors))}
+	for class, n := range r.errors {
+		stats.ErrorsByClass[class] = n
+	}
+	n := r.next
+	if r.filled {
+		n = statsLatencyWindow
+	}
+	recent := slices.Clone(r.latencies[:n])
+	r.mu.Unlock()
+
+	if len(recent) > 0 {
+		slices.Sort(recent)
+		stats.P95Latency = recent[int(math.Ceil(0.95*float64(len(recent))))-1]
+	}
+	return stats
+}
+
+// errorClass returns the ErrorsByClass key of a failed attempt's error
+func errorClass(err error) string {
+	var apiErr *APIError
+	var ctErr *ContentTypeError
+	switch {
+	case errors.As(err, &apiErr) && apiErr.StatusCode >= 500:
+		return ErrorClassServer
+	case apiErr != nil:
+		return ErrorClassClient
+	case errors.As(err, &ctErr):
+		return ErrorClassContentType
+	default:
+		return ErrorClassNetwork
+	}
+}
+
+// Stats returns the request counts, errors and latency the client has seen so far
+func (c *Client) Stats() ClientStats {
+	return c.stats.snapshot()
+}
+
+// observe records one attempt started at start in the client's stats and passes it to the
+// metrics hook, if one is set
+func (c *Client) observe(method, route string, status, attempt int, start time.Time, err error) {
+	duration := time.Since(start)
+	c.stats.record(duration, err)
+	if c.metricsHook != nil {
+		c.metricsHook(method, route, status, attempt, duration, err)
+	}
+}
@@ -0,0 +1,45 @@
+package northwind
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// WebhookSignatureHeader carries the hex HMAC-SHA256 of a webhook's raw body, keyed with the
+// shared webhook secret and optionally prefixed with "sha256="
+const WebhookSignatureHeader = "X-NorthWind-Signature"
+
+// WebhookEventTransferStatusChanged is sent when a transfer's status changes
+const WebhookEventTransferStatusChanged = "transfer.status_changed"
+
+// WebhookEvent is a webhook delivery from NorthWind. Data is the transfer as it is after the
+// event, in the same shape as a transfer status response.
+type WebhookEvent struct {
+	EventID    string           `json:"event_id"`
+	EventType  string           `json:"event_type"`
+	OccurredAt string           `json:"occurred_at,omitempty"`
+	Data       TransferResponse `json:"data"`
+}
+
+// SignWebhook returns the signature NorthWind sends for body, without the "sha256=" prefix
+func SignWebhook(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature reports whether signature is a valid signature of body under secret.
+// The comparison is constant-time; an empty secret never verifies.
+func VerifyWebhookSignature(secret, body []byte, signature string) bool {
+	if len(secret) == 0 {
+		return false
+	}
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return false
+	}
+	want, _ := hex.DecodeString(SignWebhook(secret, body))
+	return hmac.Equal(got, want)
+}
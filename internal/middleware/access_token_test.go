@@ -0,0 +1,250 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/config"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories/repository_mocks"
+	"github.com/array/banking-api/internal/services"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAccessTokens resolves one secret, or fails every lookup with err
+type fakeAccessTokens struct {
+	secret string
+	token  *models.PersonalAccessToken
+	user   *models.User
+	err    error
+}
+
+func (f *fakeAccessTokens) Authenticate(_ context.Context, secret string) (*models.PersonalAccessToken, *models.User, error) {
+	if f.err != nil {
+		return nil, nil, f.err
+	}
+	if secret != f.secret {
+		return nil, nil, services.ErrPersonalAccessTokenInvalid
+	}
+	return f.token, f.user, nil
+}
+
+func newTestTokenService(t *testing.T) services.TokenServiceInterface {
+	t.Helper()
+	privateKey, publicKey, err := config.GenerateRSAKeyPair()
+	require.NoError(t, err)
+	return services.NewTokenService(&config.JWTConfig{
+		PrivateKey:           privateKey,
+		PublicKey:            publicKey,
+		Issuer:               "test-issuer",
+		AccessTokenDuration:  time.Hour,
+		RefreshTokenDuration: time.Hour,
+	})
+}
+
+// accessTokenRequest runs authorization through RequireAuthOrAccessToken and RequireScope(scope)
+// and returns the response and the user the handler saw
+func accessTokenRequest(t *testing.T, tokenService services.TokenServiceInterface, accessTokens AccessTokenAuthenticator, authorization, scope string) (*httptest.ResponseRecorder, interface{}) {
+	t.Helper()
+	blacklist := repository_mocks.NewMockBlacklistedTokenRepositoryInterface(gomock.NewController(t))
+	blacklist.EXPECT().GetByJTI(gomock.Any()).Return(nil, nil).AnyTimes()
+
+	var seenUser interface{}
+	handler := RequireAuthOrAccessToken(tokenService, blacklist, accessTokens)(RequireScope(scope)(func(c echo.Context) error {
+		seenUser = c.Get("user_id")
+		return c.NoContent(http.StatusOK)
+	}))
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/northwind/transfers", nil)
+	req.Header.Set("Authorization", authorization)
+	rec := httptest.NewRecorder()
+	require.NoError(t, handler(e.NewContext(req, rec)))
+	return rec, seenUser
+}
+
+func TestRequireAuthOrAccessToken_EnforcesScopes(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "erp@example.com", Role: models.RoleCustomer}
+	accessTokens := &fakeAccessTokens{
+		secret: "pat_read",
+		token:  &models.PersonalAccessToken{ID: uuid.New(), UserID: user.ID, Scopes: models.TokenScopes{models.TokenScopeTransfersRead}},
+		user:   user,
+	}
+	tokenService := newTestTokenService(t)
+
+	rec, seenUser := accessTokenRequest(t, tokenService, accessTokens, "Token pat_read", models.TokenScopeTransfersRead)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, user.ID, seenUser)
+
+	rec, seenUser = accessTokenRequest(t, tokenService, accessTokens, "token pat_read", models.TokenScopeTransfersWrite)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "transfers:write")
+	assert.Nil(t, seenUser)
+}
+
+func TestRequireAuthOrAccessToken_JWTSessionsAreNotScoped(t *testing.T) {
+	tokenService := newTestTokenService(t)
+	user := &models.User{ID: uuid.New(), Email: "user@example.com", Role: models.RoleCustomer}
+	jwt, _, err := tokenService.GenerateAccessToken(user)
+	require.NoError(t, err)
+
+	rec, seenUser := accessTokenRequest(t, tokenService, &fakeAccessTokens{}, "Bearer "+jwt, models.TokenScopeTransfersWrite)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, user.ID, seenUser)
+}
+
+func TestRequireAuthOrAccessToken_FailsClosed(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		err           error
+		status        int
+	}{
+		{"unknown token", "Token pat_unknown", nil, http.StatusUnauthorized},
+		{"empty token", "Token ", nil, http.StatusUnauthorized},
+		{"expired", "Token pat_read", services.ErrPersonalAccessTokenExpired, http.StatusUnauthorized},
+		{"revoked", "Token pat_read", services.ErrPersonalAccessTokenRevoked, http.StatusUnauthorized},
+		{"locked user", "Token pat_read", services.ErrAccountLocked, http.StatusForbidden},
+		{"lookup failure", "Token pat_read", context.DeadlineExceeded, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accessTokens := &fakeAccessTokens{secret: "pat_read", err: tt.err}
+			rec, seenUser := accessTokenRequest(t, newTestTokenService(t), accessTokens, tt.authorization, models.TokenScopeTransfersRead)
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Nil(t, seenUser)
+		})
+	}
+}
+
+// claimScopeRequest runs authorization through RequireAuthOrAccessToken and RequireClaimScope(scope)
+func claimScopeRequest(t *testing.T, tokenService services.TokenServiceInterface, accessTokens AccessTokenAuthenticator, authorization, scope string) *httptest.ResponseRecorder {
+	t.Helper()
+	blacklist := repository_mocks.NewMockBlacklistedTokenRepositoryInterface(gomock.NewController(t))
+	blacklist.EXPECT().GetByJTI(gomock.Any()).Return(nil, nil).AnyTimes()
+
+	handler := RequireAuthOrAccessToken(tokenService, blacklist, accessTokens)(RequireClaimScope(scope)(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}))
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/northwind/transfers", nil)
+	req.Header.Set("Authorization", authorization)
+	rec := httptest.NewRecorder()
+	require.NoError(t, handler(e.NewContext(req, rec)))
+	return rec
+}
+
+func TestRequireClaimScope(t *testing.T) {
+	tokenService := newTestTokenService(t)
+	customer := &models.User{ID: uuid.New(), Email: "user@example.com", Role: models.RoleCustomer}
+	admin := &models.User{ID: uuid.New(), Email: "admin@example.com", Role: models.RoleAdmin}
+	customerJWT, _, err := tokenService.GenerateAccessToken(customer)
+	require.NoError(t, err)
+	adminJWT, _, err := tokenService.GenerateAccessToken(admin)
+	require.NoError(t, err)
+	accessTokens := &fakeAccessTokens{
+		secret: "pat_write",
+		token:  &models.PersonalAccessToken{ID: uuid.New(), UserID: customer.ID, Scopes: models.TokenScopes{models.TokenScopeTransfersWrite}},
+		user:   customer,
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		scope         string
+		status        int
+	}{
+		{"customer creates transfers", "Bearer " + customerJWT, models.ClaimScopeTransfersCreate, http.StatusOK},
+		{"customer cancels transfers", "Bearer " + customerJWT, models.ClaimScopeTransfersCancel, http.StatusOK},
+		{"customer reverses transfers", "Bearer " + customerJWT, models.ClaimScopeTransfersReverse, http.StatusOK},
+		{"customer registers accounts", "Bearer " + customerJWT, models.ClaimScopeAccountsRegister, http.StatusOK},
+		{"customer is not an admin", "Bearer " + customerJWT, models.ClaimScopeAdmin, http.StatusForbidden},
+		{"admin wildcard", "Bearer " + adminJWT, models.ClaimScopeAdmin, http.StatusOK},
+		{"access token acts with its owner's role", "Token pat_write", models.ClaimScopeTransfersCancel, http.StatusOK},
+		{"access token owner is not an admin", "Token pat_write", models.ClaimScopeAdmin, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := claimScopeRequest(t, tokenService, accessTokens, tt.authorization, tt.scope)
+			assert.Equal(t, tt.status, rec.Code)
+			if tt.status == http.StatusForbidden {
+				assert.Contains(t, rec.Body.String(), `"missing_scope":"`+tt.scope+`"`)
+			}
+		})
+	}
+}
+
+func TestRequireClaimScope_LegacyUnscopedTokens(t *testing.T) {
+	privateKey, publicKey, err := config.GenerateRSAKeyPair()
+	require.NoError(t, err)
+	now := time.Now()
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodRS256, models.CustomClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "test-issuer",
+			ID:        uuid.New().String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+		UserID:    uuid.New().String(),
+		Role:      models.RoleCustomer,
+		TokenType: services.TokenTypeAccess,
+	}).SignedString(privateKey)
+	require.NoError(t, err)
+
+	for _, reject := range []bool{false, true} {
+		tokenService := services.NewTokenService(&config.JWTConfig{
+			PrivateKey:           privateKey,
+			PublicKey:            publicKey,
+			Issuer:               "test-issuer",
+			AccessTokenDuration:  time.Hour,
+			RefreshTokenDuration: time.Hour,
+			RejectUnscopedTokens: reject,
+		})
+		rec := claimScopeRequest(t, tokenService, &fakeAccessTokens{}, "Bearer "+legacy, models.ClaimScopeTransfersCreate)
+		if reject {
+			assert.Equal(t, http.StatusForbidden, rec.Code)
+			assert.Contains(t, rec.Body.String(), models.ClaimScopeTransfersCreate)
+		} else {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	}
+}
+
+func TestRequireClaimScope_ConfiguredRoleScopes(t *testing.T) {
+	privateKey, publicKey, err := config.GenerateRSAKeyPair()
+	require.NoError(t, err)
+	tokenService := services.NewTokenService(&config.JWTConfig{
+		PrivateKey:           privateKey,
+		PublicKey:            publicKey,
+		Issuer:               "test-issuer",
+		AccessTokenDuration:  time.Hour,
+		RefreshTokenDuration: time.Hour,
+		RoleScopes:           map[string][]string{models.RoleCustomer: {models.ClaimScopeTransfersCreate}},
+	})
+	customer := &models.User{ID: uuid.New(), Email: "user@example.com", Role: models.RoleCustomer}
+	customerJWT, _, err := tokenService.GenerateAccessToken(customer)
+	require.NoError(t, err)
+	accessTokens := &fakeAccessTokens{
+		secret: "pat_write",
+		token:  &models.PersonalAccessToken{ID: uuid.New(), UserID: customer.ID, Scopes: models.TokenScopes{models.TokenScopeTransfersWrite}},
+		user:   customer,
+	}
+
+	for _, authorization := range []string{"Bearer " + customerJWT, "Token pat_write"} {
+		assert.Equal(t, http.StatusOK, claimScopeRequest(t, tokenService, accessTokens, authorization, models.ClaimScopeTransfersCreate).Code)
+		for _, scope := range []string{models.ClaimScopeTransfersCancel, models.ClaimScopeTransfersReverse, models.ClaimScopeAccountsRegister} {
+			rec := claimScopeRequest(t, tokenService, accessTokens, authorization, scope)
+			assert.Equal(t, http.StatusForbidden, rec.Code, scope)
+			assert.Contains(t, rec.Body.String(), `"missing_scope":"`+scope+`"`)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package middleware
 
 import (
+	"context"
+	stderrors "errors"
+	"strings"
+
 	"github.com/array/banking-api/internal/errors"
 	"github.com/array/banking-api/internal/handlers"
 	"github.com/array/banking-api/internal/models"
@@ -47,6 +51,7 @@ func RequireAuth(tokenService services.TokenServiceInterface, blacklistedTokenRe
 			c.Set("user_email", claims.Email)
 			c.Set("user_role", claims.Role)
 			c.Set("token_jti", claims.ID)
+			c.Set("claim_scopes", claims.Scopes)
 			c.Set("is_admin", claims.Role == models.RoleAdmin)
 
 			user := map[string]interface{}{
@@ -61,6 +66,96 @@ func RequireAuth(tokenService services.TokenServiceInterface, blacklistedTokenRe
 	}
 }
 
+// accessTokenScheme is the Authorization scheme of personal access tokens
+const accessTokenScheme = "Token "
+
+// AccessTokenAuthenticator resolves a personal access token secret to its token and user
+type AccessTokenAuthenticator interface {
+	Authenticate(ctx context.Context, secret string) (*models.PersonalAccessToken, *models.User, error)
+}
+
+// RequireAuthOrAccessToken is RequireAuth that also accepts `Authorization: Token <secret>` with a
+// personal access token. Token requests carry the token's scopes for RequireScope; a token that
+// cannot be resolved, for whatever reason, is rejected.
+func RequireAuthOrAccessToken(tokenService services.TokenServiceInterface, blacklistedTokenRepo repositories.BlacklistedTokenRepositoryInterface, accessTokens AccessTokenAuthenticator) echo.MiddlewareFunc {
+	requireJWT := RequireAuth(tokenService, blacklistedTokenRepo)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		jwtNext := requireJWT(next)
+		return func(c echo.Context) error {
+			authHeader := c.Request().Header.Get("Authorization")
+			if len(authHeader) < len(accessTokenScheme) || !strings.EqualFold(authHeader[:len(accessTokenScheme)], accessTokenScheme) {
+				return jwtNext(c)
+			}
+
+			secret := strings.TrimSpace(authHeader[len(accessTokenScheme):])
+			if secret == "" {
+				return handlers.SendError(c, errors.AuthInvalidTokenFormat)
+			}
+			token, user, err := accessTokens.Authenticate(c.Request().Context(), secret)
+			switch {
+			case err == nil:
+			case stderrors.Is(err, services.ErrPersonalAccessTokenExpired):
+				return handlers.SendError(c, errors.AuthExpiredToken)
+			case stderrors.Is(err, services.ErrPersonalAccessTokenRevoked):
+				return handlers.SendError(c, errors.AuthInvalidTokenFormat, errors.WithDetails("Token has been revoked"))
+			case stderrors.Is(err, services.ErrPersonalAccessTokenInvalid):
+				return handlers.SendError(c, errors.AuthInvalidTokenFormat)
+			case stderrors.Is(err, services.ErrAccountLocked):
+				return handlers.SendError(c, errors.AuthAccountLocked)
+			default:
+				return handlers.SendSystemError(c, err)
+			}
+
+			c.Set("user_id", user.ID)
+			c.Set("user_email", user.Email)
+			c.Set("user_role", user.Role)
+			c.Set("access_token_id", token.ID)
+			c.Set("token_scopes", token.Scopes)
+			// Access tokens act with the scopes their owner's role grants today
+			c.Set("claim_scopes", tokenService.ScopesForRole(user.Role))
+			c.Set("is_admin", user.Role == models.RoleAdmin)
+			c.Set("user", map[string]interface{}{
+				"id":    user.ID,
+				"email": user.Email,
+				"role":  user.Role,
+			})
+
+			return next(c)
+		}
+	}
+}
+
+// RequireScope creates a middleware that requires requests made with a personal access token to
+// have scope. JWT sessions are not scoped and always pass.
+func RequireScope(scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			scopes, ok := c.Get("token_scopes").(models.TokenScopes)
+			if ok && !scopes.Has(scope) {
+				return handlers.SendError(c, errors.AuthInsufficientPermission, errors.WithDetails("Access token lacks the "+scope+" scope"))
+			}
+			return next(c)
+		}
+	}
+}
+
+// RequireClaimScope creates a middleware that requires the caller's token to grant scope, refusing
+// with 403 and the missing scope named otherwise. JWT sessions are checked against the scopes in
+// their claims, personal access tokens against those of their owner's role.
+func RequireClaimScope(scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			scopes, _ := c.Get("claim_scopes").(models.TokenScopes)
+			if !scopes.Grants(scope) {
+				return handlers.SendError(c, errors.AuthInsufficientPermission,
+					errors.WithDetails("Token lacks the "+scope+" scope"),
+					errors.WithMeta("missing_scope", scope))
+			}
+			return next(c)
+		}
+	}
+}
+
 // RequireRole creates a middleware that requires a specific role
 func RequireRole(requiredRoles ...string) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
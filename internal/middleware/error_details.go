@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"github.com/array/banking-api/internal/handlers"
+	"github.com/labstack/echo/v4"
+)
+
+// ErrorDetails records on each request whether error details outside errors.DetailsSafe are
+// withheld from the client; handlers.SendError then logs them under the trace ID instead
+func ErrorDetails(redact bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(handlers.RedactErrorDetailsKey, redact)
+			return next(c)
+		}
+	}
+}
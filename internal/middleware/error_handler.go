@@ -7,6 +7,7 @@ import (
 	"reflect"
 
 	"github.com/array/banking-api/internal/errors"
+	"github.com/array/banking-api/internal/handlers"
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 	"github.com/prometheus/client_golang/prometheus"
@@ -83,6 +84,8 @@ func CustomHTTPErrorHandler(err error, c echo.Context) {
 		fmt.Sprintf("%d", httpStatus),
 	).Inc()
 
+	handlers.LocalizeError(c, errorResponse)
+	handlers.SetRetryAfter(c, httpStatus, errorResponse)
 	if sendErr := c.JSON(httpStatus, errorResponse); sendErr != nil {
 		slog.Error("Failed to send error response",
 			"trace_id", traceID,
@@ -180,6 +183,12 @@ func formatValidationError(fe validator.FieldError) string {
 		return "must be a valid account type (checking, savings, credit)"
 	case "transaction_type":
 		return "must be a valid transaction type (deposit, withdrawal, transfer)"
+	case "supported_currency":
+		return "must be a supported currency"
+	case "nw_transfer_type_allowed":
+		return "is not a transfer type you can initiate"
+	case "reference_number":
+		return "must be a valid reference number"
 	default:
 		return fmt.Sprintf("failed validation for '%s'", fe.Tag())
 	}
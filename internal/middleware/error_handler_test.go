@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	appErrors "github.com/array/banking-api/internal/errors"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/suite"
 )
@@ -42,6 +43,23 @@ func (s *ErrorHandlerTestSuite) TestCustomHTTPErrorHandler_EchoHTTPError() {
 	s.Contains(rec.Body.String(), "Resource not found")
 }
 
+// TestCustomHTTPErrorHandler_RetryAfter tests that 429 and 503 errors carry the default retry hint
+func (s *ErrorHandlerTestSuite) TestCustomHTTPErrorHandler_RetryAfter() {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusServiceUnavailable} {
+		rec := httptest.NewRecorder()
+		c := s.echo.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+		CustomHTTPErrorHandler(echo.NewHTTPError(status), c)
+
+		s.Equal(status, rec.Code)
+		assertRetryAfter(s.T(), rec, int(appErrors.DefaultRetryAfter.Seconds()))
+	}
+
+	rec := httptest.NewRecorder()
+	CustomHTTPErrorHandler(echo.NewHTTPError(http.StatusNotFound), s.echo.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec))
+	s.Empty(rec.Header().Get("Retry-After"), "only 429 and 503 carry a retry hint")
+}
+
 // TestCustomHTTPErrorHandler_GenericError tests handling of generic errors
 func (s *ErrorHandlerTestSuite) TestCustomHTTPErrorHandler_GenericError() {
 	req := httptest.NewRequest(http.MethodGet, "/", nil)
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/array/banking-api/internal/errors"
+	"github.com/array/banking-api/internal/handlers"
+	"github.com/array/banking-api/internal/idempotency"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the client's idempotency key
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayHeader is set on responses replayed from the idempotency store
+	IdempotentReplayHeader = "Idempotent-Replayed"
+)
+
+// storedResponse is the response snapshot kept for a completed idempotency key
+type storedResponse struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+// bodyRecorder tees the response body so it can be stored after the handler returns
+type bodyRecorder struct {
+	http.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// Idempotency makes requests carrying an Idempotency-Key header safe to retry: the first
+// request executes, concurrent duplicates get 409, and later duplicates replay the stored
+// 2xx response for ttl. Requests without the header pass through. If the store is unavailable
+// the request is rejected with 503 (fail-closed) rather than risk executing it twice. The
+// in-flight claim lasts claimTTL, which must outlive the request, so a key whose instance died
+// mid-request is only locked that long.
+func Idempotency(store idempotency.Store, claimTTL, ttl time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			clientKey := c.Request().Header.Get(IdempotencyKeyHeader)
+			if clientKey == "" {
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+			key := idempotencyScope(c, clientKey)
+
+			claimed, err := store.Claim(ctx, key, claimTTL)
+			if err != nil {
+				return handlers.SendError(c, errors.SystemServiceUnavailable, errors.WithDetails("Idempotency store unavailable"))
+			}
+
+			if !claimed {
+				raw, found, err := store.Get(ctx, key)
+				if err != nil {
+					return handlers.SendError(c, errors.SystemServiceUnavailable, errors.WithDetails("Idempotency store unavailable"))
+				}
+				if !found {
+					return handlers.SendError(c, errors.SystemRequestInProgress)
+				}
+				var snapshot storedResponse
+				if err := json.Unmarshal(raw, &snapshot); err != nil {
+					return handlers.SendSystemError(c, err)
+				}
+				c.Response().Header().Set(IdempotentReplayHeader, "true")
+				return c.Blob(snapshot.Status, snapshot.ContentType, snapshot.Body)
+			}
+
+			recorder := &bodyRecorder{ResponseWriter: c.Response().Writer, body: new(bytes.Buffer)}
+			c.Response().Writer = recorder
+
+			handlerErr := next(c)
+
+			// The outcome must be recorded even when the client has gone or the route timed out,
+			// or the key would stay claimed and every retry get 409
+			storeCtx := context.WithoutCancel(ctx)
+
+			status := c.Response().Status
+			if handlerErr == nil && status >= 200 && status < 300 {
+				snapshot, _ := json.Marshal(storedResponse{
+					Status:      status,
+					ContentType: c.Response().Header().Get(echo.HeaderContentType),
+					Body:        recorder.body.Bytes(),
+				})
+				if err := store.Complete(storeCtx, key, snapshot, ttl); err != nil {
+					slog.Error("Failed to store idempotent response", "key", key, "error", err)
+				}
+			} else if err := store.Release(storeCtx, key); err != nil {
+				slog.Error("Failed to release idempotency key", "key", key, "error", err)
+			}
+
+			return handlerErr
+		}
+	}
+}
+
+// idempotencyScope namespaces the client key by user and route so keys cannot collide across callers
+func idempotencyScope(c echo.Context, clientKey string) string {
+	return fmt.Sprintf("%v:%s:%s:%s", c.Get("user_id"), c.Request().Method, c.Path(), clientKey)
+}
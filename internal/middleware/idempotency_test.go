@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/array/banking-api/internal/idempotency"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unavailableStore simulates a backend outage on every call
+type unavailableStore struct{}
+
+func (unavailableStore) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return false, idempotency.ErrStoreUnavailable
+}
+func (unavailableStore) Complete(ctx context.Context, key string, response []byte, ttl time.Duration) error {
+	return idempotency.ErrStoreUnavailable
+}
+func (unavailableStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return nil, false, idempotency.ErrStoreUnavailable
+}
+func (unavailableStore) Release(ctx context.Context, key string) error {
+	return idempotency.ErrStoreUnavailable
+}
+
+func serveIdempotent(handler echo.HandlerFunc, key string) *httptest.ResponseRecorder {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/transfers", nil)
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	_ = handler(c)
+	return rec
+}
+
+func TestIdempotency_ReplaysCompletedResponse(t *testing.T) {
+	calls := 0
+	handler := Idempotency(idempotency.NewMemoryStore(), time.Minute, time.Hour)(func(c echo.Context) error {
+		calls++
+		return c.JSON(http.StatusCreated, map[string]int{"call": calls})
+	})
+
+	first := serveIdempotent(handler, "abc")
+	second := serveIdempotent(handler, "abc")
+
+	assert.Equal(t, 1, calls, "handler must run once per key")
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "true", second.Header().Get(IdempotentReplayHeader))
+}
+
+func TestIdempotency_FailedRequestCanBeRetried(t *testing.T) {
+	calls := 0
+	handler := Idempotency(idempotency.NewMemoryStore(), time.Minute, time.Hour)(func(c echo.Context) error {
+		calls++
+		if calls == 1 {
+			return c.JSON(http.StatusBadGateway, map[string]string{"error": "upstream"})
+		}
+		return c.JSON(http.StatusCreated, map[string]string{"status": "ok"})
+	})
+
+	assert.Equal(t, http.StatusBadGateway, serveIdempotent(handler, "abc").Code)
+	assert.Equal(t, http.StatusCreated, serveIdempotent(handler, "abc").Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotency_InFlightDuplicateConflicts(t *testing.T) {
+	var handler echo.HandlerFunc
+	duplicateCode := 0
+	handler = Idempotency(idempotency.NewMemoryStore(), time.Minute, time.Hour)(func(c echo.Context) error {
+		if duplicateCode == 0 {
+			// A retry arriving while the original is still executing
+			duplicateCode = serveIdempotent(handler, "abc").Code
+		}
+		return c.NoContent(http.StatusOK)
+	})
+
+	rec := serveIdempotent(handler, "abc")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, http.StatusConflict, duplicateCode)
+}
+
+func TestIdempotency_FailsClosedWhenStoreUnavailable(t *testing.T) {
+	calls := 0
+	handler := Idempotency(unavailableStore{}, time.Minute, time.Hour)(func(c echo.Context) error {
+		calls++
+		return c.NoContent(http.StatusOK)
+	})
+
+	rec := serveIdempotent(handler, "abc")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, 0, calls, "handler must not run when idempotency cannot be guaranteed")
+}
+
+func TestIdempotency_NoHeaderPassesThrough(t *testing.T) {
+	calls := 0
+	handler := Idempotency(unavailableStore{}, time.Minute, time.Hour)(func(c echo.Context) error {
+		calls++
+		return c.NoContent(http.StatusOK)
+	})
+
+	rec := serveIdempotent(handler, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotency_RecordsOutcomeAfterClientCancels(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	calls := 0
+	var claimTTL time.Duration
+	handler := Idempotency(idempotency.NewRedisStore(client, nil), time.Minute, time.Hour)(func(c echo.Context) error {
+		calls++
+		claimTTL = mr.TTL("idempotency:" + idempotencyScope(c, "abc"))
+		// The client disconnects, or the route times out, before the handler returns
+		cancel := c.Get("cancel").(context.CancelFunc)
+		cancel()
+		if calls == 2 {
+			return c.JSON(http.StatusBadGateway, map[string]string{"error": "upstream"})
+		}
+		return c.JSON(http.StatusCreated, map[string]int{"call": calls})
+	})
+	serve := func(key string) *httptest.ResponseRecorder {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		req := httptest.NewRequest(http.MethodPost, "/transfers", nil).WithContext(ctx)
+		req.Header.Set(IdempotencyKeyHeader, key)
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+		c.Set("cancel", cancel)
+		_ = handler(c)
+		return rec
+	}
+
+	first := serve("abc")
+	require.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, time.Minute, claimTTL, "in-flight claim uses the claim TTL")
+	replay := serve("abc")
+	assert.Equal(t, http.StatusCreated, replay.Code, replay.Body.String())
+	assert.Equal(t, first.Body.String(), replay.Body.String())
+	assert.Equal(t, "true", replay.Header().Get(IdempotentReplayHeader))
+	assert.Equal(t, 1, calls)
+
+	// A failed request's claim is released despite the cancellation, so the key can be retried
+	assert.Equal(t, http.StatusBadGateway, serve("def").Code)
+	assert.Equal(t, http.StatusCreated, serve("def").Code)
+	assert.Equal(t, 3, calls)
+}
+
+func TestIdempotency_ClaimOfDeadRequestExpires(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	store := idempotency.NewRedisStore(client, nil)
+
+	calls := 0
+	handler := Idempotency(store, time.Minute, time.Hour)(func(c echo.Context) error {
+		calls++
+		return c.NoContent(http.StatusCreated)
+	})
+	// An instance claimed the key and died before recording the outcome
+	key := idempotencyScope(echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/transfers", nil), nil), "abc")
+	claimed, err := store.Claim(context.Background(), key, time.Minute)
+	require.NoError(t, err)
+	require.True(t, claimed)
+
+	assert.Equal(t, http.StatusConflict, serveIdempotent(handler, "abc").Code)
+	mr.FastForward(time.Minute)
+	assert.Equal(t, http.StatusCreated, serveIdempotent(handler, "abc").Code)
+	assert.Equal(t, 1, calls)
+}
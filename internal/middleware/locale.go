@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"github.com/array/banking-api/internal/handlers"
+	"github.com/array/banking-api/internal/i18n"
+	"github.com/labstack/echo/v4"
+)
+
+// Locale negotiates each request's locale from its Accept-Language header, using defaultLocale
+// when the header names no supported locale, and stores the request's localizer for handlers.
+// The response's Content-Language header says which locale its messages are in.
+func Locale(catalog *i18n.Catalog, defaultLocale string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			localizer := catalog.Localizer(catalog.Negotiate(c.Request().Header.Get("Accept-Language"), defaultLocale))
+			c.Set(handlers.LocalizerContextKey, localizer)
+			header := c.Response().Header()
+			header.Set("Content-Language", localizer.Locale())
+			header.Add(echo.HeaderVary, "Accept-Language")
+			return next(c)
+		}
+	}
+}
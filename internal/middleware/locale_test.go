@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/array/banking-api/internal/i18n"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocale_NegotiatesErrorMessages(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		defaultLocale  string
+		wantLocale     string
+		wantMessage    string
+	}{
+		{"french", "fr-CA,fr;q=0.9,en;q=0.8", "en", "fr", "Le service est en mode lecture seule; les modifications sont temporairement désactivées"},
+		{"english", "en-US", "fr", "en", "The service is in read-only mode; changes are temporarily disabled"},
+		{"configured default", "de-DE", "fr", "fr", "Le service est en mode lecture seule; les modifications sont temporairement désactivées"},
+		{"no header", "", "en", "en", "The service is in read-only mode; changes are temporarily disabled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guard := ReadOnlyGuard(fixedReadOnly(true))(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+			handler := Locale(i18n.Default(), tt.defaultLocale)(guard)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/northwind/transfers", nil)
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+			rec := httptest.NewRecorder()
+			require.NoError(t, handler(echo.New().NewContext(req, rec)))
+
+			assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+			assert.Equal(t, tt.wantLocale, rec.Header().Get("Content-Language"))
+			assert.Equal(t, "Accept-Language", rec.Header().Get(echo.HeaderVary))
+			assert.Contains(t, rec.Body.String(), `"message":"`+tt.wantMessage+`"`)
+		})
+	}
+}
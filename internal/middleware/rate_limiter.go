@@ -1,11 +1,13 @@
 package middleware
 
 import (
+	"context"
 	"sync"
 	"time"
 
 	"github.com/array/banking-api/internal/errors"
 	"github.com/array/banking-api/internal/handlers"
+	"github.com/array/banking-api/internal/ratelimit"
 	"github.com/labstack/echo/v4"
 	"golang.org/x/time/rate"
 )
@@ -24,17 +26,40 @@ var (
 	burstSize         = 10
 )
 
-// RateLimiter creates a middleware for rate limiting requests per IP
+// visitorStore is the process-local ratelimit.Store backed by the visitors map
+type visitorStore struct{}
+
+func (visitorStore) Allow(ctx context.Context, key string) (bool, time.Duration) {
+	limiter := getVisitor(key)
+	now := time.Now()
+	reservation := limiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		return false, 0
+	}
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		// Give the token back: a rejected request must not push later ones further out
+		reservation.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// RateLimiter creates a middleware for rate limiting requests per IP using in-memory state
 func RateLimiter() echo.MiddlewareFunc {
 	go cleanupVisitors()
 
+	return RateLimiterWithStore(visitorStore{})
+}
+
+// RateLimiterWithStore creates a middleware for rate limiting requests per IP against the given
+// store. Rejected requests are told how long until the IP's budget refills.
+func RateLimiterWithStore(store ratelimit.Store) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			ip := getIP(c)
 
-			limiter := getVisitor(ip)
-			if !limiter.Allow() {
-				return handlers.SendError(c, errors.SystemRateLimitExceeded)
+			if allowed, retryAfter := store.Allow(c.Request().Context(), ip); !allowed {
+				return handlers.SendError(c, errors.SystemRateLimitExceeded, errors.WithRetryAfter(retryAfter))
 			}
 
 			return next(c)
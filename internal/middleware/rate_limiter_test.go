@@ -1,14 +1,19 @@
 package middleware
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/array/banking-api/internal/errors"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRateLimiter(t *testing.T) {
@@ -282,3 +287,57 @@ func TestRateLimiterConcurrency(t *testing.T) {
 	assert.Greater(t, rateLimitCount, 0, "Some requests should be rate limited")
 	assert.Equal(t, 20, successCount+rateLimitCount, "All requests should be accounted for")
 }
+
+// fixedStore rejects every request, asking the client to wait retryAfter
+type fixedStore struct {
+	retryAfter time.Duration
+}
+
+func (s fixedStore) Allow(context.Context, string) (bool, time.Duration) {
+	return false, s.retryAfter
+}
+
+// assertRetryAfter checks that a response carries the retry hint want, in seconds, in both the
+// Retry-After header and the error body
+func assertRetryAfter(t *testing.T, rec *httptest.ResponseRecorder, want int) {
+	t.Helper()
+	var body errors.ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, want, body.Error.RetryAfterSeconds)
+	assert.Equal(t, strconv.Itoa(body.Error.RetryAfterSeconds), rec.Header().Get("Retry-After"), "header and body must agree")
+}
+
+func TestRateLimiterWithStore_RetryAfter(t *testing.T) {
+	handler := RateLimiterWithStore(fixedStore{retryAfter: 1500 * time.Millisecond})(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	require.NoError(t, handler(echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/test", nil), rec)))
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assertRetryAfter(t, rec, 2)
+}
+
+func TestVisitorStore_ReportsRefillWait(t *testing.T) {
+	mu.Lock()
+	visitors = make(map[string]*visitor)
+	requestsPerSecond = 1
+	burstSize = 1
+	mu.Unlock()
+	t.Cleanup(func() {
+		mu.Lock()
+		requestsPerSecond, burstSize = 5, 10
+		mu.Unlock()
+	})
+
+	store := visitorStore{}
+	allowed, wait := store.Allow(context.Background(), "10.0.0.9")
+	require.True(t, allowed)
+	assert.Zero(t, wait)
+
+	for i := 0; i < 2; i++ {
+		allowed, wait = store.Allow(context.Background(), "10.0.0.9")
+		assert.False(t, allowed)
+		assert.InDelta(t, time.Second, wait, float64(50*time.Millisecond), "one token refills in a second at 1 rps")
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/array/banking-api/internal/errors"
+	"github.com/array/banking-api/internal/handlers"
+	"github.com/labstack/echo/v4"
+)
+
+// ReadOnlyChecker reports whether read-only mode is on and, when known, when it is expected to be
+// lifted
+type ReadOnlyChecker interface {
+	Enabled(ctx context.Context) bool
+	ExpectedEnd(ctx context.Context) (time.Time, bool)
+}
+
+// ReadOnlyGuard rejects POST, PUT, PATCH and DELETE requests with 503 READ_ONLY_MODE while
+// read-only mode is on; reads are always served. Refused clients are told to retry when the mode
+// is expected to be lifted, if the admin gave a time. Requests whose path starts with one of
+// exemptPrefixes, such as login and the read-only toggle itself, are let through.
+func ReadOnlyGuard(checker ReadOnlyChecker, exemptPrefixes ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if !isMutatingMethod(req.Method) {
+				return next(c)
+			}
+			for _, prefix := range exemptPrefixes {
+				if strings.HasPrefix(req.URL.Path, prefix) {
+					return next(c)
+				}
+			}
+			if checker.Enabled(req.Context()) {
+				var opts []errors.ErrorOption
+				if end, ok := checker.ExpectedEnd(req.Context()); ok {
+					opts = append(opts, errors.WithRetryAfter(time.Until(end)))
+				}
+				return handlers.SendError(c, errors.SystemReadOnlyMode, opts...)
+			}
+			return next(c)
+		}
+	}
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/errors"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedReadOnly bool
+
+func (f fixedReadOnly) Enabled(context.Context) bool { return bool(f) }
+
+func (f fixedReadOnly) ExpectedEnd(context.Context) (time.Time, bool) { return time.Time{}, false }
+
+// readOnlyUntil is read-only mode turned on with an expected end
+type readOnlyUntil time.Time
+
+func (readOnlyUntil) Enabled(context.Context) bool { return true }
+
+func (r readOnlyUntil) ExpectedEnd(context.Context) (time.Time, bool) { return time.Time(r), true }
+
+func TestReadOnlyGuard(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		method   string
+		path     string
+		wantCode int
+	}{
+		{"reads are served", true, http.MethodGet, "/api/v1/northwind/transfers", http.StatusOK},
+		{"post is refused", true, http.MethodPost, "/api/v1/northwind/transfers", http.StatusServiceUnavailable},
+		{"put is refused", true, http.MethodPut, "/api/v1/users/me/notification-preferences", http.StatusServiceUnavailable},
+		{"patch is refused", true, http.MethodPatch, "/api/v1/accounts/1/status", http.StatusServiceUnavailable},
+		{"delete is refused", true, http.MethodDelete, "/api/v1/accounts/1", http.StatusServiceUnavailable},
+		{"login is exempt", true, http.MethodPost, "/api/v1/auth/login", http.StatusOK},
+		{"toggle is exempt", true, http.MethodPut, "/api/v1/admin/read-only", http.StatusOK},
+		{"writes pass when off", false, http.MethodPost, "/api/v1/northwind/transfers", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := ReadOnlyGuard(fixedReadOnly(tt.enabled), "/api/v1/auth/", "/api/v1/admin/read-only")(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+
+			rec := httptest.NewRecorder()
+			require.NoError(t, handler(echo.New().NewContext(httptest.NewRequest(tt.method, tt.path, nil), rec)))
+			assert.Equal(t, tt.wantCode, rec.Code)
+			if tt.wantCode == http.StatusServiceUnavailable {
+				assert.Contains(t, rec.Body.String(), "READ_ONLY_MODE")
+			}
+		})
+	}
+}
+
+func TestReadOnlyGuard_RetryAfter(t *testing.T) {
+	tests := []struct {
+		name    string
+		checker ReadOnlyChecker
+		want    int
+	}{
+		{"expected end", readOnlyUntil(time.Now().Add(90 * time.Second)), 90},
+		{"no expected end", fixedReadOnly(true), int(errors.DefaultRetryAfter.Seconds())},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := ReadOnlyGuard(tt.checker)(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+
+			rec := httptest.NewRecorder()
+			require.NoError(t, handler(echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/api/v1/northwind/transfers", nil), rec)))
+			require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+			assertRetryAfter(t, rec, tt.want)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/array/banking-api/internal/errors"
+	"github.com/array/banking-api/internal/handlers"
+	"github.com/labstack/echo/v4"
+)
+
+// RequestTimeout bounds each request's context by timeout so repository queries and outbound
+// calls made with it are cancelled once the deadline passes. Requests for which skip returns
+// true, such as long polls that enforce their own deadline, keep the unbounded context. A
+// non-positive timeout disables the middleware.
+func RequestTimeout(timeout time.Duration, skip func(c echo.Context) bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if timeout <= 0 || (skip != nil && skip(c)) {
+				return next(c)
+			}
+
+			ctx, cancel := context.WithTimeout(c.Request().Context(), timeout)
+			defer cancel()
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			err := next(c)
+			// A RouteTimeout further down may have replaced this deadline, so check the context
+			// the handler actually ran with
+			if c.Request().Context().Err() == context.DeadlineExceeded && !c.Response().Committed {
+				return handlers.SendError(c, errors.SystemRequestTimeout)
+			}
+			return err
+		}
+	}
+}
+
+// RouteTimeout bounds the requests of a route group by timeout in place of the server-wide
+// RequestTimeout deadline, so a group can be given more or less time than the default. At the
+// deadline the request's context is cancelled, aborting repository queries and NorthWind calls
+// made with it, and the client gets 504 SystemGatewayTimeout unless the handler has already
+// responded. A client disconnecting still cancels the request. A non-positive timeout leaves the
+// server-wide deadline in place.
+func RouteTimeout(timeout time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if timeout <= 0 {
+				return next(c)
+			}
+
+			parent := c.Request().Context()
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(parent), timeout)
+			defer cancel()
+			// Only the server-wide deadline is dropped; any other cancellation is passed on
+			stop := context.AfterFunc(parent, func() {
+				if parent.Err() != context.DeadlineExceeded {
+					cancel()
+				}
+			})
+			defer stop()
+			c.SetRequest(c.Request().WithContext(ctx))
+			c.Set(handlers.TimeoutCodeKey, errors.SystemGatewayTimeout)
+
+			err := next(c)
+			if ctx.Err() == context.DeadlineExceeded && !c.Response().Committed {
+				return handlers.SendError(c, errors.SystemGatewayTimeout)
+			}
+			return err
+		}
+	}
+}
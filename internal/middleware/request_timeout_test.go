@@ -0,0 +1,191 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/errors"
+	"github.com/array/banking-api/internal/handlers"
+	"github.com/glebarez/sqlite"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// slowQueryHandler runs query with the request's context and reports failures the way handlers do
+func slowQueryHandler(db *gorm.DB, query string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if err := db.WithContext(c.Request().Context()).Exec(query).Error; err != nil {
+			return handlers.SendSystemError(c, err)
+		}
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+func serveWithTimeout(t *testing.T, timeout time.Duration, skip func(echo.Context) bool, handler echo.HandlerFunc) (*httptest.ResponseRecorder, time.Duration) {
+	t.Helper()
+	e := echo.New()
+	e.Use(RequestTimeout(timeout, skip))
+	e.GET("/slow", handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+	rec := httptest.NewRecorder()
+	start := time.Now()
+	e.ServeHTTP(rec, req)
+	return rec, time.Since(start)
+}
+
+func assertRequestTimeout(t *testing.T, rec *httptest.ResponseRecorder) {
+	t.Helper()
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	var body handlers.ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, string(errors.SystemRequestTimeout), body.Error.Code)
+}
+
+func TestRequestTimeout_CancelsLongRunningQuery(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+
+	// Counting to a billion takes far longer than the deadline unless the query is interrupted
+	const query = "WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < 1000000000) SELECT count(*) FROM n"
+	rec, elapsed := serveWithTimeout(t, 100*time.Millisecond, nil, slowQueryHandler(db, query))
+
+	assertRequestTimeout(t, rec)
+	assert.Less(t, elapsed, 5*time.Second, "query was not cancelled at the deadline")
+}
+
+// TestRequestTimeout_CancelsPgSleep runs against a real PostgreSQL server when TEST_POSTGRES_DSN is set
+func TestRequestTimeout_CancelsPgSleep(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+
+	rec, elapsed := serveWithTimeout(t, 200*time.Millisecond, nil, slowQueryHandler(db, "SELECT 1 FROM pg_sleep(10)"))
+
+	assertRequestTimeout(t, rec)
+	assert.Less(t, elapsed, 5*time.Second, "pg_sleep was not cancelled at the deadline")
+}
+
+func TestRequestTimeout_UnhandledDeadline(t *testing.T) {
+	rec, _ := serveWithTimeout(t, 10*time.Millisecond, nil, func(c echo.Context) error {
+		<-c.Request().Context().Done()
+		return c.Request().Context().Err()
+	})
+
+	assertRequestTimeout(t, rec)
+}
+
+func TestRequestTimeout_Skipped(t *testing.T) {
+	skip := func(c echo.Context) bool { return c.Path() == "/slow" }
+	rec, _ := serveWithTimeout(t, 10*time.Millisecond, skip, func(c echo.Context) error {
+		if _, ok := c.Request().Context().Deadline(); ok {
+			return c.NoContent(http.StatusInternalServerError)
+		}
+		return c.NoContent(http.StatusOK)
+	})
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+// delayHandler responds after delay unless its context ends first, reporting how it ended on cancelled
+func delayHandler(delay time.Duration, cancelled chan<- error) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		select {
+		case <-time.After(delay):
+			return c.NoContent(http.StatusOK)
+		case <-c.Request().Context().Done():
+			cancelled <- c.Request().Context().Err()
+			return handlers.SendSystemError(c, c.Request().Context().Err())
+		}
+	}
+}
+
+// newRouteTimeoutServer registers delayHandler under route groups laid out like the API's, with
+// a server-wide deadline shorter than the write group's
+func newRouteTimeoutServer(delay time.Duration, cancelled chan<- error) *echo.Echo {
+	e := echo.New()
+	e.Use(RequestTimeout(50*time.Millisecond, nil))
+	api := e.Group("/api/v1")
+	api.GET("/health", delayHandler(delay, cancelled), RouteTimeout(20*time.Millisecond))
+	nw := api.Group("/northwind")
+	nw.Group("", RouteTimeout(300*time.Millisecond)).POST("/transfers", delayHandler(delay, cancelled))
+	nw.Group("", RouteTimeout(40*time.Millisecond)).GET("/transfers", delayHandler(delay, cancelled))
+	return e
+}
+
+func TestRouteTimeout_Groups(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		// delay outlives the group's deadline but not the next longer one
+		delay time.Duration
+	}{
+		{"health", http.MethodGet, "/api/v1/health", 35 * time.Millisecond},
+		{"northwind read", http.MethodGet, "/api/v1/northwind/transfers", 150 * time.Millisecond},
+		{"northwind write", http.MethodPost, "/api/v1/northwind/transfers", 2 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cancelled := make(chan error, 1)
+			e := newRouteTimeoutServer(tt.delay, cancelled)
+
+			rec := httptest.NewRecorder()
+			start := time.Now()
+			e.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+			var body handlers.ErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, string(errors.SystemGatewayTimeout), body.Error.Code)
+			assert.Less(t, time.Since(start), tt.delay, "handler was not cut off at the deadline")
+			select {
+			case err := <-cancelled:
+				assert.Equal(t, context.DeadlineExceeded, err)
+			default:
+				t.Error("handler context was not cancelled")
+			}
+		})
+	}
+}
+
+func TestRouteTimeout_ReplacesServerDeadline(t *testing.T) {
+	// The write group allows 300ms, well past the server-wide 50ms
+	cancelled := make(chan error, 1)
+	e := newRouteTimeoutServer(100*time.Millisecond, cancelled)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/northwind/transfers", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Empty(t, cancelled)
+}
+
+func TestRouteTimeout_ClientGoneCancels(t *testing.T) {
+	cancelled := make(chan error, 1)
+	e := newRouteTimeoutServer(time.Second, cancelled)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/northwind/transfers", nil).WithContext(ctx)
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	select {
+	case err := <-cancelled:
+		assert.Equal(t, context.Canceled, err)
+	case <-time.After(time.Second):
+		t.Error("handler context was not cancelled when the client went away")
+	}
+}
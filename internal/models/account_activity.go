@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Account activity item types
+const (
+	AccountActivityTypeTransaction       = "transaction"
+	AccountActivityTypeNorthwindTransfer = "northwind_transfer"
+)
+
+// AccountActivityKeyset is the position of an item in an account's newest-first activity feed.
+// Transactions and NorthWind transfers are both ordered by created_at with ties broken by id, so
+// one position applies to both sources.
+type AccountActivityKeyset struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// AccountActivityItem is one entry of an account's activity feed: an internal transaction on the
+// account or a NorthWind transfer sent from it, told apart by Type
+type AccountActivityItem struct {
+	Type              string             `json:"type"`
+	ID                uuid.UUID          `json:"id"`
+	CreatedAt         time.Time          `json:"created_at"`
+	Transaction       *Transaction       `json:"transaction,omitempty"`
+	NorthwindTransfer *NorthwindTransfer `json:"northwind_transfer,omitempty"`
+}
+
+// Keyset returns the item's position in the feed
+func (i AccountActivityItem) Keyset() AccountActivityKeyset {
+	return AccountActivityKeyset{CreatedAt: i.CreatedAt, ID: i.ID}
+}
+
+// Before reports whether i comes before other in the newest-first feed
+func (i AccountActivityItem) Before(other AccountActivityItem) bool {
+	if !i.CreatedAt.Equal(other.CreatedAt) {
+		return i.CreatedAt.After(other.CreatedAt)
+	}
+	return i.ID.String() > other.ID.String()
+}
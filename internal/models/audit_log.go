@@ -31,6 +31,11 @@ const (
 	AuditActionAccountTransferred = "account_transferred"
 	AuditActionCustomerViewed     = "customer_viewed"
 	AuditActionActivityViewed     = "activity_viewed"
+
+	AuditActionNorthwindTransferCreated             = "northwind_transfer_created"
+	AuditActionNorthwindTransferInternalTestChanged = "northwind_transfer_internal_test_changed"
+	AuditActionNorthwindTransferCancelled           = "northwind_transfer_cancelled"
+	AuditActionNorthwindTransferReversed            = "northwind_transfer_reversed"
 )
 
 type AuditLog struct {
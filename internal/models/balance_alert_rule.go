@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// Which side of its threshold breaches a balance alert rule
+const (
+	BalanceAlertDirectionBelow = "BELOW"
+	BalanceAlertDirectionAbove = "ABOVE"
+)
+
+// BalanceAlertRule alerts a user when the available balance of one of their registered external
+// accounts crosses a threshold. Breached records the outcome of the last evaluation, so an alert
+// is only raised when the rule moves from satisfied to breached.
+type BalanceAlertRule struct {
+	ID                uuid.UUID       `gorm:"type:uuid;primary_key" json:"id"`
+	UserID            uuid.UUID       `gorm:"type:uuid;not null;index:idx_balance_alert_rules_user_id" json:"user_id"`
+	ExternalAccountID uuid.UUID       `gorm:"type:uuid;not null;index:idx_balance_alert_rules_external_account_id" json:"external_account_id"`
+	Threshold         decimal.Decimal `gorm:"type:numeric(15,2);not null" json:"threshold"`
+	Direction         string          `gorm:"type:text;not null" json:"direction"`
+	// Channel is where alerts are delivered: NotificationChannelInApp or NotificationChannelEmail
+	Channel string `gorm:"type:text;not null" json:"channel"`
+	// Frequent rules are evaluated on the frequent balance check as well as the daily one
+	Frequent        bool             `gorm:"not null;default:false" json:"frequent"`
+	Breached        bool             `gorm:"not null;default:false" json:"breached"`
+	LastBalance     *decimal.Decimal `gorm:"type:numeric(15,2)" json:"last_balance,omitempty"`
+	LastEvaluatedAt *time.Time       `json:"last_evaluated_at,omitempty"`
+	LastAlertedAt   *time.Time       `json:"last_alerted_at,omitempty"`
+	CreatedAt       time.Time        `gorm:"not null" json:"created_at"`
+	UpdatedAt       time.Time        `gorm:"not null" json:"updated_at"`
+}
+
+// TableName returns the table name for BalanceAlertRule
+func (r *BalanceAlertRule) TableName() string {
+	return "balance_alert_rules"
+}
+
+// BeforeCreate hook for BalanceAlertRule
+func (r *BalanceAlertRule) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	now := time.Now()
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = now
+	}
+	if r.UpdatedAt.IsZero() {
+		r.UpdatedAt = now
+	}
+	return nil
+}
+
+// BeforeUpdate hook for BalanceAlertRule
+func (r *BalanceAlertRule) BeforeUpdate(tx *gorm.DB) error {
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// BreachedBy reports whether balance is on the alerting side of the threshold. A balance equal to
+// the threshold does not breach it.
+func (r *BalanceAlertRule) BreachedBy(balance decimal.Decimal) bool {
+	if r.Direction == BalanceAlertDirectionAbove {
+		return balance.GreaterThan(r.Threshold)
+	}
+	return balance.LessThan(r.Threshold)
+}
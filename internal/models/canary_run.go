@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Canary run statuses
+const (
+	CanaryStatusRunning   = "RUNNING"
+	CanaryStatusSucceeded = "SUCCEEDED"
+	CanaryStatusFailed    = "FAILED"
+)
+
+// Stages of a canary run, recorded as the stage a failed run stopped at
+const (
+	CanaryStageInitiate = "INITIATE"
+	CanaryStageComplete = "COMPLETE"
+	CanaryStageWebhook  = "WEBHOOK"
+)
+
+// CanaryRun records one synthetic canary transfer: initiated with NorthWind, polled to completion
+// and confirmed delivered to the regulator
+type CanaryRun struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	Status      string     `gorm:"type:text;not null" json:"status"`
+	FailedStage *string    `gorm:"type:text" json:"failed_stage,omitempty"`
+	Error       *string    `gorm:"type:text" json:"error,omitempty"`
+	TransferID  *uuid.UUID `gorm:"type:uuid" json:"transfer_id,omitempty"`
+	StartedAt   time.Time  `gorm:"not null;index:idx_canary_runs_started_at" json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	// DurationMs is the end-to-end time from initiation until the regulator received the webhook
+	DurationMs *int64 `json:"duration_ms,omitempty"`
+}
+
+// TableName returns the table name for CanaryRun
+func (r *CanaryRun) TableName() string {
+	return "canary_runs"
+}
+
+// BeforeCreate hook for CanaryRun
+func (r *CanaryRun) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	if r.StartedAt.IsZero() {
+		r.StartedAt = time.Now()
+	}
+	return nil
+}
@@ -1,6 +1,19 @@
 package models
 
-import "github.com/golang-jwt/jwt/v5"
+import (
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Scopes carried in JWT access tokens. A scope ending in ":*" grants every scope with its prefix.
+const (
+	ClaimScopeTransfersCreate  = "transfers:create"
+	ClaimScopeTransfersCancel  = "transfers:cancel"
+	ClaimScopeTransfersReverse = "transfers:reverse"
+	ClaimScopeAccountsRegister = "accounts:register"
+	ClaimScopeAdmin            = "admin:*"
+)
 
 // CustomClaims represents the custom claims in our JWT tokens
 type CustomClaims struct {
@@ -9,4 +22,32 @@ type CustomClaims struct {
 	Email     string `json:"email,omitempty"`
 	Role      string `json:"role,omitempty"`
 	TokenType string `json:"token_type"`
+	// Scopes are granted at issuance from the user's role. Tokens issued before scopes existed
+	// have none, which leaves Scopes nil.
+	Scopes TokenScopes `json:"scopes,omitempty"`
+}
+
+// ScopesForRole returns the scopes an access token issued to a user with role carries by default.
+// A role it does not know gets none. Deployments can override a role's scopes in JWTConfig.RoleScopes.
+func ScopesForRole(role string) TokenScopes {
+	switch role {
+	case RoleCustomer:
+		return TokenScopes{ClaimScopeTransfersCreate, ClaimScopeTransfersCancel, ClaimScopeTransfersReverse, ClaimScopeAccountsRegister}
+	case RoleAdmin:
+		return TokenScopes{ClaimScopeTransfersCreate, ClaimScopeTransfersCancel, ClaimScopeTransfersReverse, ClaimScopeAccountsRegister, ClaimScopeAdmin}
+	}
+	return nil
+}
+
+// Grants reports whether the set grants scope, directly or through a wildcard such as admin:*
+func (s TokenScopes) Grants(scope string) bool {
+	for _, granted := range s {
+		if granted == scope {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(granted, "*"); ok && strings.HasSuffix(prefix, ":") && strings.HasPrefix(scope, prefix) {
+			return true
+		}
+	}
+	return false
 }
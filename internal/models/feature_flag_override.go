@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// FeatureFlagOverride changes a feature flag at runtime. Without a UserID it replaces the
+// rollout percentage for everyone; with one it forces the flag on (100) or off (0) for that user.
+type FeatureFlagOverride struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	Flag       string     `gorm:"type:text;not null" json:"flag"`
+	UserID     *uuid.UUID `gorm:"type:uuid" json:"user_id,omitempty"`
+	Percentage int        `gorm:"not null" json:"percentage"`
+	UpdatedBy  *uuid.UUID `gorm:"type:uuid" json:"updated_by,omitempty"`
+	CreatedAt  time.Time  `gorm:"not null" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"not null" json:"updated_at"`
+}
+
+// TableName returns the table name for FeatureFlagOverride
+func (f *FeatureFlagOverride) TableName() string {
+	return "feature_flag_overrides"
+}
+
+// BeforeCreate hook for FeatureFlagOverride
+func (f *FeatureFlagOverride) BeforeCreate(tx *gorm.DB) error {
+	if f.ID == uuid.Nil {
+		f.ID = uuid.New()
+	}
+	now := time.Now()
+	if f.CreatedAt.IsZero() {
+		f.CreatedAt = now
+	}
+	if f.UpdatedAt.IsZero() {
+		f.UpdatedAt = now
+	}
+	return nil
+}
+
+// BeforeUpdate hook for FeatureFlagOverride
+func (f *FeatureFlagOverride) BeforeUpdate(tx *gorm.DB) error {
+	f.UpdatedAt = time.Now()
+	return nil
+}
@@ -3,21 +3,33 @@ package models
 import (
 	"time"
 
+	"github.com/array/banking-api/internal/fieldcrypt"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
-// NorthwindExternalAccount represents a registered external bank account validated via NorthWind
+// NorthwindExternalAccount represents a registered external bank account validated via NorthWind.
+// AccountNumber is encrypted at rest; AccountNumberBidx is its blind index for exact-match lookups.
 type NorthwindExternalAccount struct {
 	ID                uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
-	UserID            *uuid.UUID `gorm:"type:uuid;index:idx_nw_ext_accounts_user_id" json:"user_id,omitempty"`
+	UserID            *uuid.UUID `gorm:"type:uuid;index:idx_nw_ext_accounts_user_id;uniqueIndex:idx_nw_ext_accounts_unique" json:"user_id,omitempty"`
 	AccountHolderName string     `gorm:"type:text;not null" json:"account_holder_name"`
-	AccountNumber     string     `gorm:"type:text;not null" json:"account_number"`
-	RoutingNumber     string     `gorm:"type:text;not null" json:"routing_number"`
+	AccountNumber     string     `gorm:"type:text;not null;serializer:encrypted" json:"account_number"`
+	AccountNumberBidx *string    `gorm:"type:text;uniqueIndex:idx_nw_ext_accounts_unique" json:"-"`
+	RoutingNumber     string     `gorm:"type:text;not null;uniqueIndex:idx_nw_ext_accounts_unique" json:"routing_number"`
 	InstitutionName   *string    `gorm:"type:text" json:"institution_name,omitempty"`
+	Nickname          *string    `gorm:"type:text" json:"nickname,omitempty"`
 	Validated         bool       `gorm:"not null;default:false" json:"validated"`
 	ValidationTime    *time.Time `json:"validation_time,omitempty"`
-	CreatedAt         time.Time  `gorm:"not null" json:"created_at"`
+	// NameMatch is how AccountHolderName compared with the name NorthWind has on file; empty when
+	// NorthWind returned no name
+	NameMatch string `gorm:"type:text" json:"name_match,omitempty"`
+	// ValidatedHolderName is NorthWind's name for the account, kept when it differs from
+	// AccountHolderName
+	ValidatedHolderName *string `gorm:"type:text" json:"validated_holder_name,omitempty"`
+	// NeedsReview flags an account registered despite a holder name mismatch
+	NeedsReview bool      `gorm:"not null;default:false" json:"needs_review"`
+	CreatedAt   time.Time `gorm:"not null" json:"created_at"`
 }
 
 // TableName returns the table name for NorthwindExternalAccount
@@ -35,3 +47,13 @@ func (n *NorthwindExternalAccount) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// BeforeSave keeps the account number blind index in sync with the plaintext
+func (n *NorthwindExternalAccount) BeforeSave(tx *gorm.DB) error {
+	bidx, err := fieldcrypt.BlindIndex(n.AccountNumber)
+	if err != nil {
+		return err
+	}
+	n.AccountNumberBidx = &bidx
+	return nil
+}
@@ -1,52 +1,197 @@
 package models
 
 import (
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
 	"time"
 
+	"github.com/array/banking-api/internal/fieldcrypt"
+	"github.com/array/banking-api/internal/integrations/northwind"
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 )
 
-// NorthWind transfer status constants
+// NorthWind transfer status constants. The statuses NorthWind reports are the northwind client's;
+// INITIATION_PENDING and SCHEDULED are ours, for transfers not yet sent to NorthWind.
 const (
-	NWTransferStatusPending    = "PENDING"
-	NWTransferStatusProcessing = "PROCESSING"
-	NWTransferStatusCompleted  = "COMPLETED"
-	NWTransferStatusFailed     = "FAILED"
-	NWTransferStatusCancelled  = "CANCELLED"
-	NWTransferStatusReversed   = "REVERSED"
+	NWTransferStatusInitiationPending = "INITIATION_PENDING"
+	NWTransferStatusScheduled         = "SCHEDULED"
+	NWTransferStatusPending           = northwind.TransferStatusPending
+	NWTransferStatusProcessing        = northwind.TransferStatusProcessing
+	NWTransferStatusCompleted         = northwind.TransferStatusCompleted
+	NWTransferStatusFailed            = northwind.TransferStatusFailed
+	NWTransferStatusCancelled         = northwind.TransferStatusCancelled
+	NWTransferStatusReversed          = northwind.TransferStatusReversed
 )
 
-// NorthwindTransfer represents an external transfer tracked via NorthWind
+// NorthWind transfer direction constants
+const (
+	NWTransferDirectionInbound  = "INBOUND"
+	NWTransferDirectionOutbound = "OUTBOUND"
+)
+
+// Authorization consent method constants for INBOUND (debit) transfers
+const (
+	ConsentMethodOnline    = "ONLINE"
+	ConsentMethodWritten   = "WRITTEN"
+	ConsentMethodTelephone = "TELEPHONE"
+)
+
+// transferReferenceEncoding is RFC 4648 base32 (A-Z, 2-7) without padding
+var transferReferenceEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTransferReference returns a new reference number of the form NW-{yyyymmdd}-{10 base32 chars}.
+// The random part carries 50 bits of entropy; per-user uniqueness is still enforced by the repository.
+func GenerateTransferReference() string {
+	return generateTransferReference(time.Now())
+}
+
+func generateTransferReference(now time.Time) string {
+	buf := make([]byte, 7) // 56 bits, enough for 10 base32 characters
+	_, _ = rand.Read(buf)  // never returns an error; crashes the program instead
+	return "NW-" + now.UTC().Format("20060102") + "-" + transferReferenceEncoding.EncodeToString(buf)[:10]
+}
+
+// NormalizeTransferReference returns reference as it is stored and sent to NorthWind: trimmed,
+// uppercased, with each run of internal whitespace replaced by a hyphen. "ref 001/a" becomes
+// "REF-001/A"; characters outside the reference format are left for validation to reject.
+func NormalizeTransferReference(reference string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(reference), "-"))
+}
+
+// TransferReferenceForms returns the forms a reference lookup matches: reference as given and,
+// when it differs, its normalized form. References stored before normalization keep their
+// original spelling and are still found by it.
+func TransferReferenceForms(reference string) []string {
+	if normalized := NormalizeTransferReference(reference); normalized != reference {
+		return []string{reference, normalized}
+	}
+	return []string{reference}
+}
+
+// AuthorizationConsent captures the account holder's NACHA-style authorization to debit an external account
+type AuthorizationConsent struct {
+	Timestamp time.Time `json:"timestamp"`
+	IPAddress string    `json:"ip_address"`
+	Method    string    `json:"method"`
+}
+
+// NorthwindTransfer represents an external transfer tracked via NorthWind.
+// Source and destination account numbers are encrypted at rest; DestinationAccountNumberBidx is
+// the destination's blind index, used to spot near-identical submissions, and
+// SourceAccountNumberBidx the source's, used to list an account's transfers. RawResponse keeps
+// NorthWind's initiation response verbatim for audit; it carries full account numbers, so it is
+// encrypted too and never serialized to clients. OriginIP and OriginUserAgent record the client
+// that initiated the transfer for fraud investigations; they are only shown on admin views.
+// ExternalRef is NorthWind's transfer ID exactly as returned, which is what lookups by upstream ID
+// use: NorthwindTransferID only holds it when it is a UUID.
+// BatchName and BatchIndex tie a transfer submitted through a batch to the batch and its position
+// in it. Batch items NorthWind rejected are stored as FAILED transfers without an ExternalRef;
+// their NorthwindTransferID is only a placeholder.
+// A transfer created during NorthWind maintenance is queued as INITIATION_PENDING, likewise with a
+// placeholder NorthwindTransferID, and InitiationRequest keeps the encrypted request to send once
+// the window closes. A transfer scheduled for a later date is held the same way as SCHEDULED
+// until its ScheduledDate.
+// InitiationRequestID is NorthWind's X-NW-Request-ID for the call that initiated the transfer,
+// which NorthWind support asks for in tickets; it is shown on admin views only.
 type NorthwindTransfer struct {
-	ID                           uuid.UUID        `gorm:"type:uuid;primary_key" json:"id"`
-	UserID                       *uuid.UUID       `gorm:"type:uuid;index:idx_nw_transfers_user_id" json:"user_id,omitempty"`
+	ID                           uuid.UUID        `gorm:"type:uuid;primary_key;index:idx_nw_transfers_user_keyset,priority:3,sort:desc;index:idx_nw_transfers_source_keyset,priority:3,sort:desc" json:"id"`
+	UserID                       *uuid.UUID       `gorm:"type:uuid;index:idx_nw_transfers_user_id;uniqueIndex:idx_nw_transfers_user_reference;index:idx_nw_transfers_duplicate_check,priority:1;index:idx_nw_transfers_user_keyset,priority:1;index:idx_nw_transfers_user_batch,priority:1" json:"user_id,omitempty"`
 	NorthwindTransferID          uuid.UUID        `gorm:"type:uuid;not null;uniqueIndex:idx_nw_transfers_nw_id" json:"northwind_transfer_id"`
+	ExternalRef                  *string          `gorm:"type:text;index:idx_nw_transfers_external_ref" json:"external_ref,omitempty"`
 	Direction                    string           `gorm:"type:text;not null" json:"direction"`
 	TransferType                 string           `gorm:"type:text;not null" json:"transfer_type"`
+	Priority                     string           `gorm:"type:text;not null;default:'STANDARD'" json:"priority"`
 	Amount                       decimal.Decimal  `gorm:"type:numeric(15,2);not null" json:"amount"`
 	Currency                     string           `gorm:"type:text;not null;default:'USD'" json:"currency"`
 	Description                  *string          `gorm:"type:text" json:"description,omitempty"`
-	ReferenceNumber              string           `gorm:"type:text;not null" json:"reference_number"`
+	ReferenceNumber              string           `gorm:"type:text;not null;uniqueIndex:idx_nw_transfers_user_reference" json:"reference_number"`
 	ScheduledDate                *time.Time       `json:"scheduled_date,omitempty"`
-	SourceAccountNumber          string           `gorm:"type:text;not null" json:"source_account_number"`
+	SourceAccountNumber          string           `gorm:"type:text;not null;serializer:encrypted" json:"source_account_number"`
+	SourceAccountNumberBidx      *string          `gorm:"type:text;index:idx_nw_transfers_source_keyset,priority:1" json:"-"`
 	SourceRoutingNumber          *string          `gorm:"type:text" json:"source_routing_number,omitempty"`
 	SourceAccountHolderName      *string          `gorm:"type:text" json:"source_account_holder_name,omitempty"`
-	DestinationAccountNumber     string           `gorm:"type:text;not null" json:"destination_account_number"`
+	DestinationAccountNumber     string           `gorm:"type:text;not null;serializer:encrypted" json:"destination_account_number"`
+	DestinationAccountNumberBidx *string          `gorm:"type:text;index:idx_nw_transfers_duplicate_check,priority:2" json:"-"`
 	DestinationRoutingNumber     *string          `gorm:"type:text" json:"destination_routing_number,omitempty"`
 	DestinationAccountHolderName *string          `gorm:"type:text" json:"destination_account_holder_name,omitempty"`
 	Status                       string           `gorm:"type:text;not null;default:'PENDING';index:idx_nw_transfers_status" json:"status"`
 	ErrorCode                    *string          `gorm:"type:text" json:"error_code,omitempty"`
 	ErrorMessage                 *string          `gorm:"type:text" json:"error_message,omitempty"`
+	FailureCategory              *string          `gorm:"type:text" json:"-"`
+	FailureRetryable             *bool            `json:"-"`
 	InitiatedDate                *time.Time       `json:"initiated_date,omitempty"`
 	ProcessingDate               *time.Time       `json:"processing_date,omitempty"`
 	ExpectedCompletionDate       *time.Time       `json:"expected_completion_date,omitempty"`
 	CompletedDate                *time.Time       `json:"completed_date,omitempty"`
 	Fee                          *decimal.Decimal `gorm:"type:numeric(15,4)" json:"fee,omitempty"`
 	ExchangeRate                 *decimal.Decimal `gorm:"type:numeric(15,6)" json:"exchange_rate,omitempty"`
-	CreatedAt                    time.Time        `gorm:"not null;index:idx_nw_transfers_created_at" json:"created_at"`
-	UpdatedAt                    time.Time        `gorm:"not null" json:"updated_at"`
+	ConsentTimestamp             *time.Time       `json:"consent_timestamp,omitempty"`
+	ConsentIPAddress             *string          `gorm:"type:text" json:"consent_ip_address,omitempty"`
+	ConsentMethod                *string          `gorm:"type:text" json:"consent_method,omitempty"`
+	OriginIP                     *string          `gorm:"type:varchar(45)" json:"-"`
+	OriginUserAgent              *string          `gorm:"type:varchar(512)" json:"-"`
+	RawResponse                  string           `gorm:"type:text;serializer:encrypted" json:"-"`
+	Version                      int              `gorm:"not null;default:1" json:"version"`
+	NextPollAt                   *time.Time       `json:"next_poll_at,omitempty"`
+	LastViewedAt                 *time.Time       `gorm:"index:idx_nw_transfers_last_viewed_at" json:"-"`
+	BatchName                    *string          `gorm:"type:text;index:idx_nw_transfers_user_batch,priority:2" json:"batch_name,omitempty"`
+	BatchIndex                   *int             `json:"batch_index,omitempty"`
+	InitiationRequest            string           `gorm:"type:text;serializer:encrypted" json:"-"`
+	InitiationRequestID          *string          `gorm:"type:text" json:"-"`
+	InitiationClaimedAt          *time.Time       `json:"-"`
+	// CancellationInitiator and CancellationInitiatorID record who last had NorthWind cancel or
+	// reverse the transfer, for the regulator
+	CancellationInitiator   *string    `gorm:"type:text" json:"-"`
+	CancellationInitiatorID *uuid.UUID `gorm:"type:uuid" json:"-"`
+	// InternalTest marks canary and QA transfers that must not be reported to the regulator. Only
+	// admins and the canary set it; it is shown in admin views, not to users.
+	InternalTest bool      `gorm:"not null;default:false" json:"-"`
+	CreatedAt    time.Time `gorm:"not null;index:idx_nw_transfers_created_at;index:idx_nw_transfers_duplicate_check,priority:3;index:idx_nw_transfers_user_keyset,priority:2,sort:desc;index:idx_nw_transfers_source_keyset,priority:2,sort:desc" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"not null" json:"updated_at"`
+	// CancellableUntil is when the transfer's cancellation window closes. It is not stored: the
+	// transfer service computes it from the transfer type's window, and it is nil for terminal
+	// transfers and types without a window.
+	CancellableUntil *time.Time `gorm:"-" json:"cancellable_until,omitempty"`
+	// Failure explains a FAILED transfer's error code to the user. It is not stored: the transfer
+	// service looks the code up in the NorthWind error code table. FailureCategory and
+	// FailureRetryable keep the classification the transfer got when it failed, for reporting.
+	Failure *NorthwindTransferFailure `gorm:"-" json:"failure,omitempty"`
+}
+
+// Failure categories of NorthWind error codes
+const (
+	NWFailureCategoryFunds         = "funds"
+	NWFailureCategoryAccount       = "account"
+	NWFailureCategoryAuthorization = "authorization"
+	NWFailureCategoryValidation    = "validation"
+	NWFailureCategoryRejected      = "rejected"
+	NWFailureCategoryTemporary     = "temporary"
+	NWFailureCategoryUnknown       = "unknown"
+)
+
+// NWFailureCategoryValues returns every failure category
+func NWFailureCategoryValues() []string {
+	return []string{
+		NWFailureCategoryFunds,
+		NWFailureCategoryAccount,
+		NWFailureCategoryAuthorization,
+		NWFailureCategoryValidation,
+		NWFailureCategoryRejected,
+		NWFailureCategoryTemporary,
+		NWFailureCategoryUnknown,
+	}
+}
+
+// NorthwindTransferFailure is what a failed transfer's error code means for the user: a message
+// to show instead of NorthWind's, a category, and whether trying the transfer again can succeed
+type NorthwindTransferFailure struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Category  string `json:"category"`
+	Retryable bool   `json:"retryable"`
 }
 
 // TableName returns the table name for NorthwindTransfer
@@ -69,19 +214,88 @@ func (n *NorthwindTransfer) BeforeCreate(tx *gorm.DB) error {
 	if n.Status == "" {
 		n.Status = NWTransferStatusPending
 	}
+	if n.Version == 0 {
+		n.Version = 1
+	}
+	if n.NextPollAt == nil && !n.IsTerminal() {
+		n.NextPollAt = &now
+	}
+	if n.ExternalRef == nil && n.NorthwindTransferID != uuid.Nil && n.BatchName == nil && !n.AwaitingInitiation() {
+		ref := n.NorthwindTransferID.String()
+		n.ExternalRef = &ref
+	}
 	return nil
 }
 
-// BeforeUpdate hook for NorthwindTransfer
+// BeforeUpdate hook for NorthwindTransfer. Every saved change bumps Version so long-polling
+// clients can detect it. UpdatedAt comes from the session's clock, which is what GORM writes.
 func (n *NorthwindTransfer) BeforeUpdate(tx *gorm.DB) error {
-	n.UpdatedAt = time.Now()
+	n.UpdatedAt = tx.NowFunc()
+	n.Version++
+	return nil
+}
+
+// BeforeSave keeps the account number blind indexes in sync with the plaintexts
+func (n *NorthwindTransfer) BeforeSave(tx *gorm.DB) error {
+	bidx, err := fieldcrypt.BlindIndex(n.DestinationAccountNumber)
+	if err != nil {
+		return err
+	}
+	n.DestinationAccountNumberBidx = &bidx
+	sourceBidx, err := fieldcrypt.BlindIndex(n.SourceAccountNumber)
+	if err != nil {
+		return err
+	}
+	n.SourceAccountNumberBidx = &sourceBidx
 	return nil
 }
 
 // IsTerminal returns true if the transfer is in a terminal state
 func (n *NorthwindTransfer) IsTerminal() bool {
-	return n.Status == NWTransferStatusCompleted ||
-		n.Status == NWTransferStatusFailed ||
-		n.Status == NWTransferStatusCancelled ||
-		n.Status == NWTransferStatusReversed
+	status, _ := nwTransferStatus(n.Status)
+	return status.Terminal
+}
+
+// AwaitingInitiation reports whether the transfer is held here and not yet sent to NorthWind:
+// queued during maintenance or scheduled for a later date
+func (n *NorthwindTransfer) AwaitingInitiation() bool {
+	return n.Status == NWTransferStatusInitiationPending || n.Status == NWTransferStatusScheduled
+}
+
+// IsCancellable returns true if the transfer can still be cancelled
+func (n *NorthwindTransfer) IsCancellable() bool {
+	status, _ := nwTransferStatus(n.Status)
+	return status.Cancellable
+}
+
+// IsInbound returns true if the transfer pulls funds from an external account
+func (n *NorthwindTransfer) IsInbound() bool {
+	return n.Direction == NWTransferDirectionInbound
+}
+
+// NorthwindTransferOrigin is the client that initiated a transfer, as shown to admins
+type NorthwindTransferOrigin struct {
+	IP        *string `json:"ip"`
+	UserAgent *string `json:"user_agent"`
+}
+
+// Origin returns the IP and User-Agent the transfer was initiated from; either is nil when it
+// was not captured, as for transfers created before they were recorded
+func (n *NorthwindTransfer) Origin() NorthwindTransferOrigin {
+	return NorthwindTransferOrigin{IP: n.OriginIP, UserAgent: n.OriginUserAgent}
+}
+
+// AuthorizationConsent returns the stored debit authorization, or nil if none was captured
+func (n *NorthwindTransfer) AuthorizationConsent() *AuthorizationConsent {
+	if n.ConsentTimestamp == nil {
+		return nil
+	}
+	consent := &AuthorizationConsent{Timestamp: *n.ConsentTimestamp}
+	if n.ConsentIPAddress != nil {
+		consent.IPAddress = *n.ConsentIPAddress
+	}
+	if n.ConsentMethod != nil {
+		consent.Method = *n.ConsentMethod
+	}
+	return consent
 }
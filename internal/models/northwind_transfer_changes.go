@@ -0,0 +1,131 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm/schema"
+)
+
+// RedactedChangeValue stands in for the old and new values of an encrypted column, so a diff
+// records that an account number changed without storing it in the clear
+const RedactedChangeValue = "[redacted]"
+
+// nwTransferDiffSkipped are columns left out of transfer diffs: UpdatedAt and Version change on
+// every save, and the blind indexes follow the account numbers
+var nwTransferDiffSkipped = map[string]bool{
+	"updated_at":                      true,
+	"version":                         true,
+	"source_account_number_bidx":      true,
+	"destination_account_number_bidx": true,
+}
+
+var (
+	nwTransferSchema     *schema.Schema
+	nwTransferSchemaErr  error
+	nwTransferSchemaOnce sync.Once
+)
+
+// NorthwindFieldChange is one column's value before and after an update; nil is NULL
+type NorthwindFieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// NorthwindTransferChanges maps each column an update changed to its old and new value. It is
+// stored on the status-history event of the update for dispute resolution.
+type NorthwindTransferChanges map[string]NorthwindFieldChange
+
+// Value implements driver.Valuer interface
+func (c NorthwindTransferChanges) Value() (driver.Value, error) {
+	if len(c) == 0 {
+		return nil, nil
+	}
+	bytes, err := json.Marshal(map[string]NorthwindFieldChange(c))
+	if err != nil {
+		return nil, err
+	}
+	// Return string for SQLite compatibility
+	return string(bytes), nil
+}
+
+// Scan implements sql.Scanner interface
+func (c *NorthwindTransferChanges) Scan(value interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into NorthwindTransferChanges", value)
+	}
+	if len(bytes) == 0 {
+		*c = nil
+		return nil
+	}
+	return json.Unmarshal(bytes, (*map[string]NorthwindFieldChange)(c))
+}
+
+// DiffNorthwindTransfers returns the columns whose values differ between before and after, the
+// same transfer before and after an update. Pointers are compared by what they point to, decimals
+// by value and times by instant, so 100 and 100.00 or one instant in two time zones are no
+// change. Encrypted columns are reported with RedactedChangeValue.
+func DiffNorthwindTransfers(before, after *NorthwindTransfer) (NorthwindTransferChanges, error) {
+	nwTransferSchemaOnce.Do(func() {
+		nwTransferSchema, nwTransferSchemaErr = schema.Parse(&NorthwindTransfer{}, &sync.Map{}, schema.NamingStrategy{})
+	})
+	if nwTransferSchemaErr != nil {
+		return nil, fmt.Errorf("failed to parse northwind transfer schema: %w", nwTransferSchemaErr)
+	}
+
+	beforeValue := reflect.ValueOf(before).Elem()
+	afterValue := reflect.ValueOf(after).Elem()
+	changes := make(NorthwindTransferChanges)
+	for _, field := range nwTransferSchema.Fields {
+		if field.DBName == "" || nwTransferDiffSkipped[field.DBName] {
+			continue
+		}
+		oldValue := diffValue(beforeValue.FieldByIndex(field.StructField.Index))
+		newValue := diffValue(afterValue.FieldByIndex(field.StructField.Index))
+		if sameDiffValue(oldValue, newValue) {
+			continue
+		}
+		if field.Serializer != nil {
+			oldValue, newValue = RedactedChangeValue, RedactedChangeValue
+		}
+		changes[field.DBName] = NorthwindFieldChange{Old: oldValue, New: newValue}
+	}
+	return changes, nil
+}
+
+// diffValue returns the value of a field, dereferencing pointers; a nil pointer is nil
+func diffValue(v reflect.Value) interface{} {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	return v.Interface()
+}
+
+func sameDiffValue(a, b interface{}) bool {
+	switch x := a.(type) {
+	case decimal.Decimal:
+		y, ok := b.(decimal.Decimal)
+		return ok && x.Equal(y)
+	case time.Time:
+		y, ok := b.(time.Time)
+		return ok && x.Equal(y)
+	}
+	return reflect.DeepEqual(a, b)
+}
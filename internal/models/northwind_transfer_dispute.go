@@ -0,0 +1,84 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Transfer dispute statuses. A dispute is opened by the transfer's owner, taken under review by
+// an admin and resolved; a resolved dispute is final.
+const (
+	DisputeStatusOpen        = "OPEN"
+	DisputeStatusUnderReview = "UNDER_REVIEW"
+	DisputeStatusResolved    = "RESOLVED"
+)
+
+// Why a customer disputes a transfer
+const (
+	DisputeReasonUnauthorized    = "UNAUTHORIZED"
+	DisputeReasonIncorrectAmount = "INCORRECT_AMOUNT"
+	DisputeReasonDuplicate       = "DUPLICATE"
+	DisputeReasonNotReceived     = "NOT_RECEIVED"
+	DisputeReasonOther           = "OTHER"
+)
+
+// How a dispute was resolved
+const (
+	// DisputeResolutionReversed means the dispute was upheld and the transfer reversed with NorthWind
+	DisputeResolutionReversed = "REVERSED"
+	// DisputeResolutionRejected means the dispute was reviewed and the transfer left as it is
+	DisputeResolutionRejected = "REJECTED"
+)
+
+// DisputeReasonValues returns the reasons a transfer can be disputed for
+func DisputeReasonValues() []string {
+	return []string{DisputeReasonUnauthorized, DisputeReasonIncorrectAmount, DisputeReasonDuplicate, DisputeReasonNotReceived, DisputeReasonOther}
+}
+
+// TransferDispute is a customer's claim that a completed transfer is wrong, such as one they did
+// not authorize. A transfer has at most one unresolved dispute. ReviewerID is the admin who took
+// it under review or resolved it; Resolution and ResolutionNote record their decision.
+type TransferDispute struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	TransferID     uuid.UUID  `gorm:"type:uuid;not null;index:idx_transfer_disputes_transfer_id" json:"transfer_id"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null;index:idx_transfer_disputes_user_id" json:"user_id"`
+	ReasonCategory string     `gorm:"type:text;not null" json:"reason_category"`
+	Description    string     `gorm:"type:text;not null;default:''" json:"description"`
+	Status         string     `gorm:"type:text;not null;default:'OPEN';index:idx_transfer_disputes_status" json:"status"`
+	Resolution     *string    `gorm:"type:text" json:"resolution,omitempty"`
+	ResolutionNote *string    `gorm:"type:text" json:"resolution_note,omitempty"`
+	ReviewerID     *uuid.UUID `gorm:"type:uuid" json:"reviewer_id,omitempty"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt      time.Time  `gorm:"not null" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"not null" json:"updated_at"`
+}
+
+// TableName returns the table name for TransferDispute
+func (d *TransferDispute) TableName() string {
+	return "transfer_disputes"
+}
+
+// BeforeCreate hook for TransferDispute
+func (d *TransferDispute) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	if d.Status == "" {
+		d.Status = DisputeStatusOpen
+	}
+	now := time.Now()
+	if d.CreatedAt.IsZero() {
+		d.CreatedAt = now
+	}
+	if d.UpdatedAt.IsZero() {
+		d.UpdatedAt = now
+	}
+	return nil
+}
+
+// IsResolved reports whether the dispute has been resolved
+func (d *TransferDispute) IsResolved() bool {
+	return d.Status == DisputeStatusResolved
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// NorthWind transfer event sources: what observed or made the status change
+const (
+	NWTransferEventSourcePoller  = "POLLER"
+	NWTransferEventSourceWebhook = "WEBHOOK"
+	// NWTransferEventSourceQueue is the worker initiating transfers queued during maintenance
+	NWTransferEventSourceQueue = "QUEUE"
+	// NWTransferEventSourceSchedule is the worker initiating scheduled transfers once they are due
+	NWTransferEventSourceSchedule = "SCHEDULE"
+	// NWTransferEventSourceUser is the transfer's owner cancelling or reversing it
+	NWTransferEventSourceUser = "USER"
+	// NWTransferEventSourceAdmin is an admin cancelling a user's transfers
+	NWTransferEventSourceAdmin = "ADMIN"
+	// NWTransferEventSourceSystem is a cancellation or reversal the system made on its own
+	NWTransferEventSourceSystem = "SYSTEM"
+	// NWTransferEventSourceAdoption is an admin adopting NorthWind's record of a transfer missing locally
+	NWTransferEventSourceAdoption = "ADOPTION"
+	// NWTransferEventSourceCanary is the synthetic canary polling its own transfer to completion
+	NWTransferEventSourceCanary = "CANARY"
+	// NWTransferEventSourceReplay is an admin replaying a quarantined poll response
+	NWTransferEventSourceReplay = "REPLAY"
+	// NWTransferEventSourceUpdate is a transfer saved outside a status transition, such as a batch
+	// retry storing NorthWind's new answer
+	NWTransferEventSourceUpdate = "UPDATE"
+	// NWTransferEventSourceSync is a user or support agent asking for a transfer's status on demand
+	NWTransferEventSourceSync = "SYNC"
+)
+
+// NorthwindTransferEvent is one entry in a transfer's status history, recorded once per actual
+// status transition and once per other update that changed the transfer. Changes records the
+// columns the update changed with their old and new values.
+type NorthwindTransferEvent struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	TransferID uuid.UUID `gorm:"type:uuid;not null;index:idx_nw_transfer_events_transfer_id" json:"transfer_id"`
+	FromStatus string    `gorm:"type:text;not null" json:"from_status"`
+	ToStatus   string    `gorm:"type:text;not null" json:"to_status"`
+	Source     string    `gorm:"type:text;not null" json:"source"`
+	// InitiatorID is the user or admin who made a USER or ADMIN change
+	InitiatorID *uuid.UUID               `gorm:"type:uuid" json:"initiator_id,omitempty"`
+	Changes     NorthwindTransferChanges `gorm:"type:text" json:"changes,omitempty"`
+	// UpstreamRequestID is NorthWind's X-NW-Request-ID for the response that reported the change,
+	// when it came from a call we made such as a poll
+	UpstreamRequestID *string   `gorm:"type:text" json:"upstream_request_id,omitempty"`
+	CreatedAt         time.Time `gorm:"not null" json:"created_at"`
+}
+
+// Initiators of a cancellation or reversal
+const (
+	NWInitiatorUser   = "user"
+	NWInitiatorAdmin  = "admin"
+	NWInitiatorSystem = "system"
+)
+
+// TransferInitiator is who asked for a transfer to be cancelled or reversed: its owner, an admin,
+// or the system itself, such as a bulk cancel after a compromised-account report. InitiatorID is
+// the user or admin and is nil for the system.
+type TransferInitiator struct {
+	Initiator   string
+	InitiatorID *uuid.UUID
+}
+
+// UserInitiator is the transfer's owner acting on their own transfer
+func UserInitiator(userID uuid.UUID) TransferInitiator {
+	return TransferInitiator{Initiator: NWInitiatorUser, InitiatorID: &userID}
+}
+
+// AdminInitiator is an admin acting on a user's transfer
+func AdminInitiator(adminID uuid.UUID) TransferInitiator {
+	return TransferInitiator{Initiator: NWInitiatorAdmin, InitiatorID: &adminID}
+}
+
+// SystemInitiator is the system acting without a user's or admin's request
+func SystemInitiator() TransferInitiator {
+	return TransferInitiator{Initiator: NWInitiatorSystem}
+}
+
+// Event returns the status-history event for a change the initiator made
+func (i TransferInitiator) Event(fromStatus, toStatus string) *NorthwindTransferEvent {
+	source := NWTransferEventSourceSystem
+	switch i.Initiator {
+	case NWInitiatorUser:
+		source = NWTransferEventSourceUser
+	case NWInitiatorAdmin:
+		source = NWTransferEventSourceAdmin
+	}
+	return &NorthwindTransferEvent{FromStatus: fromStatus, ToStatus: toStatus, Source: source, InitiatorID: i.InitiatorID}
+}
+
+// TableName returns the table name for NorthwindTransferEvent
+func (e *NorthwindTransferEvent) TableName() string {
+	return "northwind_transfer_events"
+}
+
+// BeforeCreate hook for NorthwindTransferEvent
+func (e *NorthwindTransferEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+	return nil
+}
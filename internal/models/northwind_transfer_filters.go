@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NorthwindTransferFilters narrows a user's NorthWind transfer list; empty fields match any value
+type NorthwindTransferFilters struct {
+	Status       string
+	Direction    string
+	TransferType string
+}
+
+// NorthwindTransferKeyset is the position of a row in the newest-first transfer list, used to
+// fetch the rows after it without an offset scan
+type NorthwindTransferKeyset struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// NorthwindPollPriority reserves part of a poll batch for transfers users are watching. Up to
+// Slots due transfers viewed at or after ViewedSince are polled first, most recently viewed
+// first; the zero value polls oldest first only.
+type NorthwindPollPriority struct {
+	ViewedSince time.Time
+	Slots       int
+}
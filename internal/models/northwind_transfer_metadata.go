@@ -0,0 +1,179 @@
+package models
+
+// NorthWind transfer type constants
+const (
+	NWTransferTypeACH  = "ACH"
+	NWTransferTypeWire = "WIRE"
+	NWTransferTypeRTP  = "RTP"
+)
+
+// NorthWind transfer priority constants. SAME_DAY expedites an ACH transfer for a fee when it is
+// submitted before the daily cutoff.
+const (
+	NWTransferPriorityStandard = "STANDARD"
+	NWTransferPrioritySameDay  = "SAME_DAY"
+)
+
+// NorthwindEnumValue is one allowed value of a NorthWind transfer field and its display label
+type NorthwindEnumValue struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+}
+
+// NorthwindStatusValue is one transfer status, its display label and what can still happen to
+// a transfer in it. Pollable statuses are the ones NorthWind knows the transfer in, so the poller
+// asks NorthWind about it; a status that exists only here, before the transfer is sent, is not.
+// KeepsError statuses are the outcomes NorthWind explains with an error code and message; a
+// transfer in any other status carries neither.
+type NorthwindStatusValue struct {
+	Value       string `json:"value"`
+	Label       string `json:"label"`
+	Terminal    bool   `json:"terminal"`
+	Cancellable bool   `json:"cancellable"`
+	Pollable    bool   `json:"pollable"`
+	KeepsError  bool   `json:"-"`
+}
+
+// NWTransferStatuses, NWTransferDirections, NWTransferTypes and NWTransferPriorities are the single source of truth
+// for the values of the corresponding transfer fields. Request validation, list filters, the
+// metadata endpoint and the poller all read them, so adding a value here is all it takes to support it.
+var (
+	NWTransferStatuses = []NorthwindStatusValue{
+		{Value: NWTransferStatusInitiationPending, Label: "Queued for initiation", Cancellable: true},
+		{Value: NWTransferStatusScheduled, Label: "Scheduled", Cancellable: true},
+		{Value: NWTransferStatusPending, Label: "Pending", Cancellable: true, Pollable: true},
+		{Value: NWTransferStatusProcessing, Label: "Processing", Pollable: true},
+		{Value: NWTransferStatusCompleted, Label: "Completed", Terminal: true},
+		{Value: NWTransferStatusFailed, Label: "Failed", Terminal: true, KeepsError: true},
+		{Value: NWTransferStatusCancelled, Label: "Cancelled", Terminal: true, KeepsError: true},
+		{Value: NWTransferStatusReversed, Label: "Reversed", Terminal: true, KeepsError: true},
+	}
+	NWTransferDirections = []NorthwindEnumValue{
+		{Value: NWTransferDirectionInbound, Label: "Inbound"},
+		{Value: NWTransferDirectionOutbound, Label: "Outbound"},
+	}
+	NWTransferTypes = []NorthwindEnumValue{
+		{Value: NWTransferTypeACH, Label: "ACH"},
+		{Value: NWTransferTypeWire, Label: "Wire"},
+		{Value: NWTransferTypeRTP, Label: "Real-Time Payment"},
+	}
+	NWTransferPriorities = []NorthwindEnumValue{
+		{Value: NWTransferPriorityStandard, Label: "Standard"},
+		{Value: NWTransferPrioritySameDay, Label: "Same day (ACH only)"},
+	}
+)
+
+// NorthwindTransferMetadata lists the supported values of every enumerated transfer field
+type NorthwindTransferMetadata struct {
+	Statuses      []NorthwindStatusValue `json:"statuses"`
+	Directions    []NorthwindEnumValue   `json:"directions"`
+	TransferTypes []NorthwindEnumValue   `json:"transfer_types"`
+	Priorities    []NorthwindEnumValue   `json:"priorities"`
+}
+
+// GetNorthwindTransferMetadata returns the supported transfer statuses, directions, types and
+// priorities
+func GetNorthwindTransferMetadata() NorthwindTransferMetadata {
+	return NorthwindTransferMetadata{
+		Statuses:      NWTransferStatuses,
+		Directions:    NWTransferDirections,
+		TransferTypes: NWTransferTypes,
+		Priorities:    NWTransferPriorities,
+	}
+}
+
+// NWTransferStatusValues returns the supported transfer statuses
+func NWTransferStatusValues() []string {
+	values := make([]string, len(NWTransferStatuses))
+	for i, s := range NWTransferStatuses {
+		values[i] = s.Value
+	}
+	return values
+}
+
+// NWTransferTerminalStatusValues returns the statuses a transfer never leaves, other than a
+// completed transfer being reversed
+func NWTransferTerminalStatusValues() []string {
+	var values []string
+	for _, s := range NWTransferStatuses {
+		if s.Terminal {
+			values = append(values, s.Value)
+		}
+	}
+	return values
+}
+
+// NWTransferPollableStatusValues returns the statuses of transfers the poller asks NorthWind about
+func NWTransferPollableStatusValues() []string {
+	var values []string
+	for _, s := range NWTransferStatuses {
+		if s.Pollable {
+			values = append(values, s.Value)
+		}
+	}
+	return values
+}
+
+// NWTransferStatusKeepsError reports whether a transfer in status keeps NorthWind's error code
+// and message
+func NWTransferStatusKeepsError(status string) bool {
+	s, _ := nwTransferStatus(status)
+	return s.KeepsError
+}
+
+// NWTransferDirectionValues returns the supported transfer directions
+func NWTransferDirectionValues() []string {
+	return enumValues(NWTransferDirections)
+}
+
+// NWTransferTypeValues returns the supported transfer types
+func NWTransferTypeValues() []string {
+	return enumValues(NWTransferTypes)
+}
+
+// NWTransferPriorityValues returns the supported transfer priorities
+func NWTransferPriorityValues() []string {
+	return enumValues(NWTransferPriorities)
+}
+
+// IsNWTransferDirection reports whether direction is a supported transfer direction
+func IsNWTransferDirection(direction string) bool {
+	return containsValue(NWTransferDirectionValues(), direction)
+}
+
+// IsNWTransferType reports whether transferType is a supported transfer type
+func IsNWTransferType(transferType string) bool {
+	return containsValue(NWTransferTypeValues(), transferType)
+}
+
+// IsNWTransferPriority reports whether priority is a supported transfer priority
+func IsNWTransferPriority(priority string) bool {
+	return containsValue(NWTransferPriorityValues(), priority)
+}
+
+// nwTransferStatus looks up a status; ok is false for an unknown one
+func nwTransferStatus(status string) (NorthwindStatusValue, bool) {
+	for _, s := range NWTransferStatuses {
+		if s.Value == status {
+			return s, true
+		}
+	}
+	return NorthwindStatusValue{}, false
+}
+
+func enumValues(enum []NorthwindEnumValue) []string {
+	values := make([]string, len(enum))
+	for i, e := range enum {
+		values[i] = e.Value
+	}
+	return values
+}
+
+func containsValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// NorthwindInitiationOutcomes counts transfer initiations over a window. Initiated transfers
+// were accepted by NorthWind; rejected ones were stored FAILED without a NorthWind ID, as batch
+// items NorthWind refused and queued initiations that failed are.
+type NorthwindInitiationOutcomes struct {
+	Initiated int64 `json:"initiated"`
+	Rejected  int64 `json:"rejected"`
+}
+
+// NorthwindPollingBacklog describes the in-flight transfers the poller tracks. Due is how many
+// of them are waiting for a poll now; OldestCreatedAt is the creation time of the oldest
+// in-flight transfer, nil when there is none.
+type NorthwindPollingBacklog struct {
+	InFlight        int64      `json:"in_flight"`
+	Due             int64      `json:"due"`
+	OldestCreatedAt *time.Time `json:"oldest_created_at,omitempty"`
+}
+
+// NorthwindTransferVelocity counts a user's transfers in one currency created since a point in
+// time, and their total amount. Failed and cancelled transfers are not counted.
+type NorthwindTransferVelocity struct {
+	Count  int64           `json:"count"`
+	Amount decimal.Decimal `json:"amount"`
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var transferReferencePattern = regexp.MustCompile(`^NW-\d{8}-[A-Z2-7]{10}$`)
+
+func TestGenerateTransferReference_Format(t *testing.T) {
+	ref := GenerateTransferReference()
+	assert.Regexp(t, transferReferencePattern, ref)
+
+	at := time.Date(2024, 1, 15, 23, 30, 0, 0, time.FixedZone("EST", -5*3600))
+	ref = generateTransferReference(at)
+	assert.Regexp(t, transferReferencePattern, ref)
+	assert.Equal(t, "NW-20240116-", ref[:12], "date part should be in UTC")
+}
+
+func TestGenerateTransferReference_UniqueUnderConcurrency(t *testing.T) {
+	const workers, perWorker = 16, 500
+
+	var mu sync.Mutex
+	seen := make(map[string]struct{}, workers*perWorker)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			refs := make([]string, perWorker)
+			for i := range refs {
+				refs[i] = GenerateTransferReference()
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, ref := range refs {
+				seen[ref] = struct{}{}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, seen, workers*perWorker, "expected every generated reference to be distinct")
+}
+
+func TestNormalizeTransferReference(t *testing.T) {
+	tests := map[string]string{
+		"INV-2024-001":     "INV-2024-001",
+		"  inv-2024-001\n": "INV-2024-001",
+		"ref 001/a":        "REF-001/A",
+		"ref \t  001   a":  "REF-001-A",
+		"":                 "",
+		"   ":              "",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, NormalizeTransferReference(in), "%q", in)
+	}
+}
+
+func TestTransferReferenceForms(t *testing.T) {
+	assert.Equal(t, []string{"REF-001"}, TransferReferenceForms("REF-001"))
+	assert.Equal(t, []string{"ref 001", "REF-001"}, TransferReferenceForms("ref 001"))
+}
+
+func TestDiffNorthwindTransfers(t *testing.T) {
+	fee := decimal.RequireFromString("1.50")
+	completed := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	before := &NorthwindTransfer{
+		ID:                       uuid.New(),
+		Status:                   NWTransferStatusPending,
+		Amount:                   decimal.RequireFromString("100"),
+		Fee:                      &fee,
+		ProcessingDate:           &completed,
+		SourceAccountNumber:      "1111111111",
+		DestinationAccountNumber: "2222222222",
+		Version:                  1,
+		UpdatedAt:                completed,
+	}
+	after := *before
+	newFee := decimal.RequireFromString("2.25")
+	sameInstant := completed.In(time.FixedZone("EST", -5*3600))
+	errorCode := "R01"
+	after.Status = NWTransferStatusFailed
+	after.Amount = decimal.RequireFromString("100.00")
+	after.Fee = &newFee
+	after.ProcessingDate = &sameInstant
+	after.CompletedDate = &completed
+	after.ErrorCode = &errorCode
+	after.DestinationAccountNumber = "3333333333"
+	after.Version = 2
+	after.UpdatedAt = completed.Add(time.Hour)
+
+	changes, err := DiffNorthwindTransfers(before, &after)
+	require.NoError(t, err)
+	assert.Equal(t, NorthwindTransferChanges{
+		"status":                     {Old: NWTransferStatusPending, New: NWTransferStatusFailed},
+		"fee":                        {Old: fee, New: newFee},
+		"completed_date":             {Old: nil, New: completed},
+		"error_code":                 {Old: nil, New: errorCode},
+		"destination_account_number": {Old: RedactedChangeValue, New: RedactedChangeValue},
+	}, changes, "rescaled amounts, one instant in two zones, version and updated_at are not changes")
+
+	unchanged, err := DiffNorthwindTransfers(before, before)
+	require.NoError(t, err)
+	assert.Empty(t, unchanged)
+}
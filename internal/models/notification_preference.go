@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Channels a user notification is delivered on
+const (
+	NotificationChannelInApp = "IN_APP"
+	NotificationChannelEmail = "EMAIL"
+)
+
+// NotificationPreference overrides the default channels a user is notified on for one event type
+type NotificationPreference struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_notification_preferences_user_event" json:"user_id"`
+	EventType string    `gorm:"type:text;not null;uniqueIndex:idx_notification_preferences_user_event" json:"event_type"`
+	InApp     bool      `gorm:"not null" json:"in_app"`
+	Email     bool      `gorm:"not null" json:"email"`
+	CreatedAt time.Time `gorm:"not null" json:"created_at"`
+	UpdatedAt time.Time `gorm:"not null" json:"updated_at"`
+}
+
+// TableName returns the table name for NotificationPreference
+func (p *NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
+
+// BeforeCreate hook for NotificationPreference
+func (p *NotificationPreference) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	now := time.Now()
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = now
+	}
+	if p.UpdatedAt.IsZero() {
+		p.UpdatedAt = now
+	}
+	return nil
+}
+
+// BeforeUpdate hook for NotificationPreference
+func (p *NotificationPreference) BeforeUpdate(tx *gorm.DB) error {
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// UserNotification is a notification for a user on one channel. In-app notifications are shown
+// until read; email notifications are queued until SentAt is set.
+type UserNotification struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index:idx_user_notifications_user" json:"user_id"`
+	TransferID *uuid.UUID `gorm:"type:uuid" json:"transfer_id,omitempty"`
+	EventType  string     `gorm:"type:text;not null" json:"event_type"`
+	Channel    string     `gorm:"type:text;not null" json:"channel"`
+	Recipient  string     `gorm:"type:text;not null;default:''" json:"recipient,omitempty"`
+	Title      string     `gorm:"type:text;not null" json:"title"`
+	Message    string     `gorm:"type:text;not null" json:"message"`
+	ReadAt     *time.Time `json:"read_at,omitempty"`
+	SentAt     *time.Time `json:"sent_at,omitempty"`
+	CreatedAt  time.Time  `gorm:"not null" json:"created_at"`
+}
+
+// TableName returns the table name for UserNotification
+func (n *UserNotification) TableName() string {
+	return "user_notifications"
+}
+
+// BeforeCreate hook for UserNotification
+func (n *UserNotification) BeforeCreate(tx *gorm.DB) error {
+	if n.ID == uuid.Nil {
+		n.ID = uuid.New()
+	}
+	if n.CreatedAt.IsZero() {
+		n.CreatedAt = time.Now()
+	}
+	return nil
+}
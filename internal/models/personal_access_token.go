@@ -0,0 +1,105 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Scopes a personal access token can be granted
+const (
+	TokenScopeTransfersRead  = "transfers:read"
+	TokenScopeTransfersWrite = "transfers:write"
+)
+
+// TokenScopeValues returns every scope a personal access token can be granted
+func TokenScopeValues() []string {
+	return []string{TokenScopeTransfersRead, TokenScopeTransfersWrite}
+}
+
+// TokenScopes is a set of token scopes, stored as comma-separated text
+// swaggertype: array,string
+type TokenScopes []string
+
+// Value implements driver.Valuer interface
+func (s TokenScopes) Value() (driver.Value, error) {
+	return strings.Join(s, ","), nil
+}
+
+// Scan implements sql.Scanner interface
+func (s *TokenScopes) Scan(value interface{}) error {
+	var raw string
+	switch v := value.(type) {
+	case nil:
+	case []byte:
+		raw = string(v)
+	case string:
+		raw = v
+	default:
+		return fmt.Errorf("cannot scan %T into TokenScopes", value)
+	}
+
+	*s = TokenScopes{}
+	for _, scope := range strings.Split(raw, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			*s = append(*s, scope)
+		}
+	}
+	return nil
+}
+
+// Has reports whether scope is in the set
+func (s TokenScopes) Has(scope string) bool {
+	for _, granted := range s {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// PersonalAccessToken lets a user call the API server-to-server with `Authorization: Token <secret>`
+// instead of an interactive JWT login. Only the SHA-256 hash of the secret is stored; the secret
+// itself is shown once, when the token is created.
+type PersonalAccessToken struct {
+	ID         uuid.UUID   `gorm:"type:uuid;primary_key" json:"id"`
+	UserID     uuid.UUID   `gorm:"type:uuid;not null;index:idx_personal_access_tokens_user_id" json:"user_id"`
+	Name       string      `gorm:"type:varchar(100);not null" json:"name"`
+	TokenHash  string      `gorm:"type:varchar(64);not null;uniqueIndex:idx_personal_access_tokens_token_hash" json:"-"`
+	Scopes     TokenScopes `gorm:"type:text;not null" json:"scopes"`
+	ExpiresAt  time.Time   `gorm:"not null" json:"expires_at"`
+	LastUsedAt *time.Time  `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time  `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time   `gorm:"not null" json:"created_at"`
+}
+
+// TableName returns the table name for PersonalAccessToken
+func (t *PersonalAccessToken) TableName() string {
+	return "personal_access_tokens"
+}
+
+// BeforeCreate hook for PersonalAccessToken
+func (t *PersonalAccessToken) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = time.Now()
+	}
+	return nil
+}
+
+// IsRevoked reports whether the token has been revoked
+func (t *PersonalAccessToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+// IsExpiredAt reports whether the token has expired by now. A token is expired from its
+// ExpiresAt on.
+func (t *PersonalAccessToken) IsExpiredAt(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
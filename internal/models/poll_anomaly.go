@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Reasons a NorthWind poll response is quarantined
+const (
+	// PollAnomalyReasonUnknownStatus is a status MapStatus does not recognise
+	PollAnomalyReasonUnknownStatus = "UNKNOWN_STATUS"
+	// PollAnomalyReasonUndecodable is a body that is not a transfer status response
+	PollAnomalyReasonUndecodable = "UNDECODABLE_RESPONSE"
+)
+
+// How a replayed anomaly was resolved
+const (
+	// PollAnomalyResolutionApplied means the replayed response changed the transfer's status
+	PollAnomalyResolutionApplied = "APPLIED"
+	// PollAnomalyResolutionUnchanged means the replayed response matched the transfer's status or
+	// would have moved it out of a terminal status
+	PollAnomalyResolutionUnchanged = "UNCHANGED"
+	// PollAnomalyResolutionStale means the transfer changed after the response was quarantined,
+	// so the response was not applied
+	PollAnomalyResolutionStale = "STALE"
+)
+
+// PollAnomaly is a NorthWind poll response our mapping could not handle, kept as NorthWind sent it
+// so it can be replayed once the mapping is updated. Repeats of an unresolved anomaly for the
+// same transfer, reason and status are counted on one row.
+type PollAnomaly struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	TransferID     uuid.UUID `gorm:"type:uuid;not null;index:idx_poll_anomalies_transfer_id" json:"transfer_id"`
+	Reason         string    `gorm:"type:text;not null" json:"reason"`
+	ReportedStatus string    `gorm:"type:text;not null;default:''" json:"reported_status"`
+	RawBody        string    `gorm:"type:text;not null" json:"raw_body"`
+	// UpstreamRequestID is NorthWind's X-NW-Request-ID for the response last seen
+	UpstreamRequestID *string `gorm:"type:text" json:"upstream_request_id,omitempty"`
+	// TransferVersion is the transfer's version when the response was last seen; replay skips
+	// the response once the transfer has moved on
+	TransferVersion int        `gorm:"not null" json:"transfer_version"`
+	Occurrences     int        `gorm:"not null;default:1" json:"occurrences"`
+	FirstSeenAt     time.Time  `gorm:"not null" json:"first_seen_at"`
+	LastSeenAt      time.Time  `gorm:"not null" json:"last_seen_at"`
+	ResolvedAt      *time.Time `gorm:"index:idx_poll_anomalies_resolved_at" json:"resolved_at,omitempty"`
+	Resolution      *string    `gorm:"type:text" json:"resolution,omitempty"`
+}
+
+// TableName returns the table name for PollAnomaly
+func (a *PollAnomaly) TableName() string {
+	return "poll_anomalies"
+}
+
+// BeforeCreate hook for PollAnomaly
+func (a *PollAnomaly) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	now := time.Now()
+	if a.FirstSeenAt.IsZero() {
+		a.FirstSeenAt = now
+	}
+	if a.LastSeenAt.IsZero() {
+		a.LastSeenAt = a.FirstSeenAt
+	}
+	if a.Occurrences == 0 {
+		a.Occurrences = 1
+	}
+	return nil
+}
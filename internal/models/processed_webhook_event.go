@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Senders of webhooks whose deliveries are deduplicated
+const (
+	WebhookSourceNorthwind = "NORTHWIND"
+)
+
+// Outcomes of a processed webhook event
+const (
+	// WebhookEventOutcomeProcessing marks an event whose first delivery is still being handled
+	WebhookEventOutcomeProcessing = "PROCESSING"
+	// WebhookEventOutcomeApplied means the event changed a transfer's status
+	WebhookEventOutcomeApplied = "APPLIED"
+	// WebhookEventOutcomeUnchanged means the transfer already had the event's status
+	WebhookEventOutcomeUnchanged = "UNCHANGED"
+	// WebhookEventOutcomeIgnored means the event type is not one we act on
+	WebhookEventOutcomeIgnored = "IGNORED"
+)
+
+// ProcessedWebhookEvent records a webhook event we have accepted, so redeliveries of it are
+// acknowledged without being processed again. The row is inserted before the event is processed
+// and the unique event ID decides which of several concurrent deliveries processes it.
+type ProcessedWebhookEvent struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	EventID    string    `gorm:"type:text;not null;uniqueIndex:idx_processed_webhook_events_event_id" json:"event_id"`
+	Source     string    `gorm:"type:text;not null" json:"source"`
+	ReceivedAt time.Time `gorm:"not null;index:idx_processed_webhook_events_received_at" json:"received_at"`
+	Outcome    string    `gorm:"type:text;not null" json:"outcome"`
+}
+
+// TableName returns the table name for ProcessedWebhookEvent
+func (e *ProcessedWebhookEvent) TableName() string {
+	return "processed_webhook_events"
+}
+
+// BeforeCreate hook for ProcessedWebhookEvent
+func (e *ProcessedWebhookEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	if e.ReceivedAt.IsZero() {
+		e.ReceivedAt = time.Now()
+	}
+	if e.Outcome == "" {
+		e.Outcome = WebhookEventOutcomeProcessing
+	}
+	return nil
+}
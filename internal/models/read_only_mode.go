@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ReadOnlyModeID is the primary key of the single read_only_mode row
+const ReadOnlyModeID = 1
+
+// ReadOnlyMode is the read-only switch as last set by an admin. There is at most one row; until
+// it exists the configured default applies. ExpectedEnd is when the admin expects to lift the
+// mode, a hint for refused clients that does not lift it by itself.
+type ReadOnlyMode struct {
+	ID          int        `gorm:"primaryKey;autoIncrement:false" json:"-"`
+	Enabled     bool       `gorm:"not null" json:"enabled"`
+	Reason      string     `gorm:"type:text;not null;default:''" json:"reason,omitempty"`
+	ExpectedEnd *time.Time `json:"expected_end,omitempty"`
+	UpdatedBy   *uuid.UUID `gorm:"type:uuid" json:"updated_by,omitempty"`
+	UpdatedAt   time.Time  `gorm:"not null" json:"updated_at"`
+}
+
+// TableName returns the table name for ReadOnlyMode
+func (m *ReadOnlyMode) TableName() string {
+	return "read_only_mode"
+}
+
+// BeforeSave hook for ReadOnlyMode
+func (m *ReadOnlyMode) BeforeSave(tx *gorm.DB) error {
+	m.ID = ReadOnlyModeID
+	m.UpdatedAt = time.Now()
+	return nil
+}
@@ -21,8 +21,11 @@ type RegulatorNotification struct {
 	LastHTTPStatus *int            `json:"last_http_status,omitempty"`
 	LastError      *string         `json:"last_error,omitempty"`
 	Payload        json.RawMessage `gorm:"type:jsonb;not null" json:"payload"`
-	CreatedAt      time.Time       `gorm:"not null" json:"created_at"`
-	UpdatedAt      time.Time       `gorm:"not null" json:"updated_at"`
+	EventID        *string         `gorm:"type:text;uniqueIndex:idx_reg_notif_event_id" json:"event_id,omitempty"`
+	// RelatedNotificationID links a REVERSED notification to the COMPLETED notification it follows up
+	RelatedNotificationID *uuid.UUID `gorm:"type:uuid;index:idx_reg_notif_related_id" json:"related_notification_id,omitempty"`
+	CreatedAt             time.Time  `gorm:"not null" json:"created_at"`
+	UpdatedAt             time.Time  `gorm:"not null" json:"updated_at"`
 }
 
 // TableName returns the table name for RegulatorNotification
@@ -35,6 +38,11 @@ func (r *RegulatorNotification) BeforeCreate(tx *gorm.DB) error {
 	if r.ID == uuid.Nil {
 		r.ID = uuid.New()
 	}
+	if r.EventID == nil {
+		if eventID := EventIDFromPayload(r.Payload); eventID != "" {
+			r.EventID = &eventID
+		}
+	}
 	now := time.Now()
 	if r.CreatedAt.IsZero() {
 		r.CreatedAt = now
@@ -45,6 +53,18 @@ func (r *RegulatorNotification) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// EventIDFromPayload returns the event_id of a stored webhook payload, or "" when the payload
+// has none or is not valid JSON
+func EventIDFromPayload(payload json.RawMessage) string {
+	var fields struct {
+		EventID string `json:"event_id"`
+	}
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return ""
+	}
+	return fields.EventID
+}
+
 // BeforeUpdate hook for RegulatorNotification
 func (r *RegulatorNotification) BeforeUpdate(tx *gorm.DB) error {
 	r.UpdatedAt = time.Now()
@@ -53,12 +73,15 @@ func (r *RegulatorNotification) BeforeUpdate(tx *gorm.DB) error {
 
 // RegulatorNotificationAttempt records a single delivery attempt for audit proof
 type RegulatorNotificationAttempt struct {
-	ID             uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
-	NotificationID uuid.UUID `gorm:"type:uuid;not null" json:"notification_id"`
-	AttemptedAt    time.Time `gorm:"not null" json:"attempted_at"`
-	HTTPStatus     *int      `json:"http_status,omitempty"`
-	Error          *string   `json:"error,omitempty"`
-	ResponseBody   *string   `gorm:"type:text" json:"response_body,omitempty"`
+	ID                 uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	NotificationID     uuid.UUID `gorm:"type:uuid;not null" json:"notification_id"`
+	AttemptedAt        time.Time `gorm:"not null" json:"attempted_at"`
+	HTTPStatus         *int      `json:"http_status,omitempty"`
+	Error              *string   `json:"error,omitempty"`
+	ResponseBody       *string   `gorm:"type:text" json:"response_body,omitempty"`
+	DurationMs         int       `gorm:"not null;default:0" json:"duration_ms"`
+	TargetURL          string    `gorm:"type:text;not null;default:''" json:"target_url"`
+	RequestHeadersHash string    `gorm:"type:text" json:"request_headers_hash,omitempty"`
 }
 
 // TableName returns the table name for RegulatorNotificationAttempt
@@ -79,13 +102,60 @@ func (r *RegulatorNotificationAttempt) BeforeCreate(tx *gorm.DB) error {
 
 // RegulatorWebhookPayload is the payload sent to the regulator webhook
 type RegulatorWebhookPayload struct {
-	EventID             string  `json:"event_id"`
-	TransferID          string  `json:"transfer_id"`
-	NorthwindTransferID string  `json:"northwind_transfer_id"`
-	Status              string  `json:"status"`
-	Amount              float64 `json:"amount"`
-	Currency            string  `json:"currency"`
-	Direction           string  `json:"direction"`
-	TransferType        string  `json:"transfer_type"`
-	Timestamp           string  `json:"timestamp"`
+	EventID              string                `json:"event_id"`
+	TransferID           string                `json:"transfer_id"`
+	NorthwindTransferID  string                `json:"northwind_transfer_id"`
+	Status               string                `json:"status"`
+	Amount               float64               `json:"amount"`
+	Currency             string                `json:"currency"`
+	Direction            string                `json:"direction"`
+	TransferType         string                `json:"transfer_type"`
+	Timestamp            string                `json:"timestamp"`
+	AuthorizationConsent *AuthorizationConsent `json:"authorization_consent,omitempty"`
+	// CancellationOrigin is user, admin or system when a cancellation or reversal of the transfer
+	// was requested, so the regulator can tell a customer's request from our own
+	CancellationOrigin string `json:"cancellation_origin,omitempty"`
+	// OriginalEventID is, on a REVERSED notification, the event_id of the COMPLETED notification
+	// sent for the transfer
+	OriginalEventID string `json:"original_event_id,omitempty"`
+}
+
+// RegulatorDeliveryStats summarizes regulator notification delivery. Pending notifications are
+// not delivered yet and Abandoned is the subset that has been retrying past the abandonment
+// age. Of the SLAEligible notifications, created long enough ago to have been due, SLAMet were
+// delivered within the SLA.
+type RegulatorDeliveryStats struct {
+	Pending     int64 `json:"pending"`
+	Abandoned   int64 `json:"abandoned"`
+	SLAEligible int64 `json:"sla_eligible"`
+	SLAMet      int64 `json:"sla_met"`
+}
+
+// Outcomes of a regulator notification attempt: a 2xx response is a success, anything else,
+// including no response at all, a failure
+const (
+	RegulatorAttemptOutcomeSuccess = "success"
+	RegulatorAttemptOutcomeFailure = "failure"
+)
+
+// RegulatorAttemptStatusClasses are the HTTP status classes attempts can be filtered by
+var RegulatorAttemptStatusClasses = []string{"2xx", "3xx", "4xx", "5xx"}
+
+// Outcome returns whether the attempt succeeded or failed
+func (r *RegulatorNotificationAttempt) Outcome() string {
+	if r.HTTPStatus != nil && *r.HTTPStatus >= 200 && *r.HTTPStatus < 300 {
+		return RegulatorAttemptOutcomeSuccess
+	}
+	return RegulatorAttemptOutcomeFailure
+}
+
+// RegulatorNotificationAttemptFilters narrows a listing of notification attempts. A nil
+// NotificationID lists attempts across notifications; StatusClass is one of
+// RegulatorAttemptStatusClasses; From and To bound attempted_at, both inclusive.
+type RegulatorNotificationAttemptFilters struct {
+	NotificationID *uuid.UUID
+	Outcome        string
+	StatusClass    string
+	From           *time.Time
+	To             *time.Time
 }
@@ -0,0 +1,9 @@
+package models
+
+// RetentionCount is how many rows of one entity are past their retention period: Expired can be
+// purged, Held are kept because something still references them, such as an open poll anomaly
+// or a regulator notification not delivered yet
+type RetentionCount struct {
+	Expired int64 `json:"expired"`
+	Held    int64 `json:"held"`
+}
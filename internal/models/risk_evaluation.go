@@ -0,0 +1,113 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Modes of a risk rule
+const (
+	// RiskRuleModeOff skips the rule
+	RiskRuleModeOff = "off"
+	// RiskRuleModeShadow evaluates the rule and records its verdict without blocking
+	RiskRuleModeShadow = "shadow"
+	// RiskRuleModeEnforce blocks transfers the rule flags
+	RiskRuleModeEnforce = "enforce"
+)
+
+// Verdicts of a risk rule evaluation
+const (
+	RiskVerdictPass = "PASS"
+	RiskVerdictFlag = "FLAG"
+)
+
+// RiskEvaluation records one risk rule's verdict on a transfer request together with the inputs
+// it was reached from. TransferID is empty when the request was blocked and no transfer exists.
+type RiskEvaluation struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	TransferID *uuid.UUID `gorm:"type:uuid;index:idx_risk_evaluations_transfer_id" json:"transfer_id,omitempty"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null" json:"user_id"`
+	Rule       string     `gorm:"type:text;not null;index:idx_risk_evaluations_rule_created_at,priority:1" json:"rule"`
+	Mode       string     `gorm:"type:text;not null" json:"mode"`
+	Verdict    string     `gorm:"type:text;not null" json:"verdict"`
+	Blocked    bool       `gorm:"not null;default:false" json:"blocked"`
+	Inputs     JSONBMap   `gorm:"type:jsonb" json:"inputs"`
+	CreatedAt  time.Time  `gorm:"not null;index:idx_risk_evaluations_rule_created_at,priority:2" json:"created_at"`
+}
+
+// TableName returns the table name for RiskEvaluation
+func (e *RiskEvaluation) TableName() string {
+	return "risk_evaluations"
+}
+
+// BeforeCreate hook for RiskEvaluation
+func (e *RiskEvaluation) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+	return nil
+}
+
+// RiskEvaluationFilters narrows the risk evaluations listed for analysis. TransferStatus matches
+// the current status of the evaluated transfer, so flagged transfers that went on to complete
+// can be told apart from those that failed or were reversed.
+type RiskEvaluationFilters struct {
+	Rule           string
+	Mode           string
+	Verdict        string
+	Blocked        *bool
+	UserID         *uuid.UUID
+	TransferID     *uuid.UUID
+	TransferStatus string
+	From           *time.Time
+	To             *time.Time
+}
+
+// RiskRuleSummary counts the evaluations of one rule matching a filter
+type RiskRuleSummary struct {
+	Rule      string `json:"rule"`
+	Evaluated int64  `json:"evaluated"`
+	Flagged   int64  `json:"flagged"`
+	Blocked   int64  `json:"blocked"`
+}
+
+// RiskRuleOverride switches a risk rule's mode at runtime, replacing the configured mode
+type RiskRuleOverride struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	Rule      string     `gorm:"type:text;not null;uniqueIndex:idx_risk_rule_overrides_rule" json:"rule"`
+	Mode      string     `gorm:"type:text;not null" json:"mode"`
+	UpdatedBy *uuid.UUID `gorm:"type:uuid" json:"updated_by,omitempty"`
+	CreatedAt time.Time  `gorm:"not null" json:"created_at"`
+	UpdatedAt time.Time  `gorm:"not null" json:"updated_at"`
+}
+
+// TableName returns the table name for RiskRuleOverride
+func (o *RiskRuleOverride) TableName() string {
+	return "risk_rule_overrides"
+}
+
+// BeforeCreate hook for RiskRuleOverride
+func (o *RiskRuleOverride) BeforeCreate(tx *gorm.DB) error {
+	if o.ID == uuid.Nil {
+		o.ID = uuid.New()
+	}
+	now := time.Now()
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = now
+	}
+	if o.UpdatedAt.IsZero() {
+		o.UpdatedAt = now
+	}
+	return nil
+}
+
+// BeforeUpdate hook for RiskRuleOverride
+func (o *RiskRuleOverride) BeforeUpdate(tx *gorm.DB) error {
+	o.UpdatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,9 @@
+package models
+
+// TransferDurationStats contains initiated-to-completed duration percentiles for one transfer type
+type TransferDurationStats struct {
+	TransferType string  `json:"transfer_type"`
+	SampleCount  int64   `json:"sample_count"`
+	P50Seconds   float64 `json:"p50_seconds"`
+	P95Seconds   float64 `json:"p95_seconds"`
+}
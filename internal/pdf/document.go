@@ -0,0 +1,154 @@
+// Package pdf writes small text-only PDF documents using the PDF standard Type 1 fonts, which
+// readers supply themselves, so no font data is embedded. Content streams are left uncompressed.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Font is one of the standard Type 1 fonts every PDF reader provides
+type Font int
+
+// Supported fonts
+const (
+	Helvetica Font = iota
+	HelveticaBold
+	Courier
+)
+
+var fontNames = []string{"Helvetica", "Helvetica-Bold", "Courier"}
+
+// US Letter page size in points
+const (
+	PageWidth  = 612
+	PageHeight = 792
+)
+
+// Document is a PDF document being assembled in memory
+type Document struct {
+	pages []*Page
+}
+
+// Page is a single page; coordinates are in points from the bottom-left corner
+type Page struct {
+	content bytes.Buffer
+}
+
+// New creates an empty document
+func New() *Document {
+	return &Document{}
+}
+
+// AddPage appends a blank page and returns it
+func (d *Document) AddPage() *Page {
+	p := &Page{}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// Text draws a single line of text with its baseline starting at (x, y). Characters outside
+// Latin-1 are replaced with '?'.
+func (p *Page) Text(font Font, size, x, y float64, text string) {
+	fmt.Fprintf(&p.content, "BT /F%d %s Tf %s %s Td (%s) Tj ET\n",
+		int(font)+1, formatNumber(size), formatNumber(x), formatNumber(y), escapeText(text))
+}
+
+// Line draws a straight line of the given width from (x1, y1) to (x2, y2)
+func (p *Page) Line(width, x1, y1, x2, y2 float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s m %s %s l S\n",
+		formatNumber(width), formatNumber(x1), formatNumber(y1), formatNumber(x2), formatNumber(y2))
+}
+
+// Bytes renders the document
+func (d *Document) Bytes() []byte {
+	var buf bytes.Buffer
+	_, _ = d.WriteTo(&buf)
+	return buf.Bytes()
+}
+
+// WriteTo renders the document to w
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	pages := d.pages
+	if len(pages) == 0 {
+		pages = []*Page{{}}
+	}
+
+	// Object layout: 1 catalog, 2 page tree, then one object per font, then a page object and
+	// its content stream for every page
+	const catalogObj, pagesObj = 1, 2
+	firstFontObj := 3
+	firstPageObj := firstFontObj + len(fontNames)
+
+	var objects [][]byte
+	objects = append(objects, []byte(fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesObj)))
+
+	var kids bytes.Buffer
+	for i := range pages {
+		fmt.Fprintf(&kids, "%d 0 R ", firstPageObj+2*i)
+	}
+	objects = append(objects, []byte(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", bytes.TrimSpace(kids.Bytes()), len(pages))))
+
+	var fonts bytes.Buffer
+	for i, name := range fontNames {
+		objects = append(objects, []byte(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name)))
+		fmt.Fprintf(&fonts, "/F%d %d 0 R ", i+1, firstFontObj+i)
+	}
+
+	for i, p := range pages {
+		contentObj := firstPageObj + 2*i + 1
+		objects = append(objects, []byte(fmt.Sprintf(
+			"<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %d %d] /Resources << /Font << %s>> >> /Contents %d 0 R >>",
+			pagesObj, PageWidth, PageHeight, fonts.String(), contentObj)))
+		var stream bytes.Buffer
+		fmt.Fprintf(&stream, "<< /Length %d >>\nstream\n", p.content.Len())
+		stream.Write(p.content.Bytes())
+		stream.WriteString("\nendstream")
+		objects = append(objects, stream.Bytes())
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n", i+1)
+		buf.Write(obj)
+		buf.WriteString("\nendobj\n")
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, catalogObj, xref)
+
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// escapeText encodes s as the body of a PDF literal string in WinAnsi (Latin-1) encoding
+func escapeText(s string) string {
+	var b bytes.Buffer
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || (r >= 0x7f && r < 0xa0) || r > 0xff:
+			b.WriteByte('?')
+		case r < 0x80:
+			b.WriteByte(byte(r))
+		default:
+			fmt.Fprintf(&b, "\\%03o", r)
+		}
+	}
+	return b.String()
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,83 @@
+package pdf
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDocument_Bytes(t *testing.T) {
+	doc := New()
+	page := doc.AddPage()
+	page.Text(HelveticaBold, 18, 72, 720, "Transfer Receipt")
+	page.Line(0.5, 72, 710, 540, 710)
+	doc.AddPage().Text(Courier, 9, 72, 700, "second page")
+
+	out := doc.Bytes()
+
+	if !bytes.HasPrefix(out, []byte("%PDF-1.4\n")) {
+		t.Fatalf("missing PDF header: %q", out[:16])
+	}
+	if !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+		t.Errorf("missing EOF marker")
+	}
+	for _, want := range []string{
+		"BT /F2 18 Tf 72 720 Td (Transfer Receipt) Tj ET",
+		"0.5 w 72 710 m 540 710 l S",
+		"BT /F3 9 Tf 72 700 Td (second page) Tj ET",
+		"/Count 2",
+		"/BaseFont /Helvetica-Bold",
+	} {
+		if !bytes.Contains(out, []byte(want)) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+
+	// Every xref entry must point at the start of its object
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	if startxref == nil {
+		t.Fatal("missing startxref")
+	}
+	xrefOffset, _ := strconv.Atoi(string(startxref[1]))
+	if !bytes.HasPrefix(out[xrefOffset:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point at the xref table", xrefOffset)
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[xrefOffset:], -1)
+	if len(entries) != 9 {
+		t.Fatalf("expected 9 objects, got %d", len(entries))
+	}
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		want := strconv.Itoa(i+1) + " 0 obj\n"
+		if !bytes.HasPrefix(out[offset:], []byte(want)) {
+			t.Errorf("xref entry %d points at %q, want %q", i+1, out[offset:offset+len(want)], want)
+		}
+	}
+}
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"plain", "plain"},
+		{`a (b) \c`, `a \(b\) \\c`},
+		{"café", `caf\351`},
+		{"line\nbreak", "line?break"},
+		{"€5", "?5"},
+	}
+	for _, tt := range tests {
+		if got := escapeText(tt.in); got != tt.want {
+			t.Errorf("escapeText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDocument_EmptyHasOnePage(t *testing.T) {
+	out := string(New().Bytes())
+	if !strings.Contains(out, "/Count 1") {
+		t.Errorf("expected an empty document to render a single blank page")
+	}
+}
@@ -0,0 +1,100 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const redisKeyPrefix = "ratelimit:"
+
+// tokenBucketScript refills the bucket for the elapsed time, takes one token if available,
+// and keeps the key alive only as long as it takes to refill completely. It returns whether the
+// token was taken and, when it was not, the milliseconds until one is available.
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local ttl = tonumber(ARGV[4])
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+
+local elapsed = math.max(0, now - ts)
+tokens = math.min(burst, tokens + (elapsed * rate / 1000))
+
+local allowed = 0
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+elseif rate > 0 then
+	wait = math.ceil((1 - tokens) * 1000 / rate)
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", tostring(now))
+redis.call("PEXPIRE", KEYS[1], ttl)
+return {allowed, wait}
+`)
+
+// RedisStore is a token-bucket Store shared by every API instance through Redis
+type RedisStore struct {
+	client            *redis.Client
+	requestsPerSecond int
+	burst             int
+	logger            *slog.Logger
+	now               func() time.Time
+}
+
+// NewRedisStore creates a Redis-backed token bucket allowing requestsPerSecond with the given burst
+func NewRedisStore(client *redis.Client, requestsPerSecond, burst int, logger *slog.Logger) *RedisStore {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &RedisStore{
+		client:            client,
+		requestsPerSecond: requestsPerSecond,
+		burst:             burst,
+		logger:            logger,
+		now:               time.Now,
+	}
+}
+
+// Allow consumes one token for key. Redis failures fail open.
+func (s *RedisStore) Allow(ctx context.Context, key string) (bool, time.Duration) {
+	result, err := tokenBucketScript.Run(ctx, s.client, []string{redisKeyPrefix + key},
+		s.requestsPerSecond,
+		s.burst,
+		s.now().UnixMilli(),
+		s.refillTTL().Milliseconds(),
+	).Int64Slice()
+	if err == nil && len(result) != 2 {
+		err = fmt.Errorf("unexpected token bucket result %v", result)
+	}
+	if err != nil {
+		s.logger.Warn("Rate limit store unavailable, allowing request", "key", key, "error", err)
+		return true, 0
+	}
+	if result[0] == 1 {
+		return true, 0
+	}
+	return false, time.Duration(result[1]) * time.Millisecond
+}
+
+// refillTTL is how long an idle bucket needs to refill completely, plus a second of slack
+func (s *RedisStore) refillTTL() time.Duration {
+	if s.requestsPerSecond <= 0 {
+		return time.Minute
+	}
+	seconds := math.Ceil(float64(s.burst) / float64(s.requestsPerSecond))
+	return time.Duration(seconds+1) * time.Second
+}
@@ -0,0 +1,92 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedisStore(t *testing.T, rps, burst int) (*RedisStore, *miniredis.Miniredis, *time.Time) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	store := NewRedisStore(client, rps, burst, nil)
+	store.now = func() time.Time { return now }
+	return store, mr, &now
+}
+
+// allowed reports whether the store let one request for key through
+func allowed(ctx context.Context, store *RedisStore, key string) bool {
+	ok, _ := store.Allow(ctx, key)
+	return ok
+}
+
+func TestRedisStore_AllowsBurstThenLimits(t *testing.T) {
+	store, _, _ := newTestRedisStore(t, 2, 4)
+	ctx := context.Background()
+
+	for i := 0; i < 4; i++ {
+		assert.True(t, allowed(ctx, store, "10.0.0.1"), "request %d within burst should be allowed", i+1)
+	}
+	assert.False(t, allowed(ctx, store, "10.0.0.1"), "request beyond burst should be limited")
+	assert.True(t, allowed(ctx, store, "10.0.0.2"), "other keys have an independent budget")
+}
+
+func TestRedisStore_RefillsOverTime(t *testing.T) {
+	store, _, now := newTestRedisStore(t, 2, 2)
+	ctx := context.Background()
+
+	require.True(t, allowed(ctx, store, "k"))
+	require.True(t, allowed(ctx, store, "k"))
+	require.False(t, allowed(ctx, store, "k"))
+
+	*now = now.Add(500 * time.Millisecond) // one token at 2 rps
+	assert.True(t, allowed(ctx, store, "k"))
+	assert.False(t, allowed(ctx, store, "k"))
+}
+
+func TestRedisStore_IdleBucketExpires(t *testing.T) {
+	store, mr, _ := newTestRedisStore(t, 2, 4)
+
+	require.True(t, allowed(context.Background(), store, "k"))
+	require.True(t, mr.Exists(redisKeyPrefix+"k"))
+	assert.Equal(t, 3*time.Second, mr.TTL(redisKeyPrefix+"k"))
+
+	mr.FastForward(3 * time.Second)
+	assert.False(t, mr.Exists(redisKeyPrefix+"k"), "idle bucket should expire once fully refilled")
+}
+
+func TestRedisStore_FailsOpenWhenRedisUnavailable(t *testing.T) {
+	store, mr, _ := newTestRedisStore(t, 1, 1)
+	mr.Close()
+
+	for i := 0; i < 5; i++ {
+		assert.True(t, allowed(context.Background(), store, "k"), "rate limiting must fail open")
+	}
+}
+
+func TestRedisStore_ReportsRefillWait(t *testing.T) {
+	store, _, now := newTestRedisStore(t, 2, 1)
+	ctx := context.Background()
+
+	ok, wait := store.Allow(ctx, "k")
+	require.True(t, ok)
+	assert.Zero(t, wait, "allowed requests need no wait")
+
+	ok, wait = store.Allow(ctx, "k")
+	assert.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, wait, "one token refills in 500ms at 2 rps")
+
+	*now = now.Add(200 * time.Millisecond)
+	ok, wait = store.Allow(ctx, "k")
+	assert.False(t, ok)
+	assert.Equal(t, 300*time.Millisecond, wait, "the wait shrinks as the bucket refills")
+}
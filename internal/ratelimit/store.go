@@ -0,0 +1,18 @@
+// Package ratelimit defines the storage contract for request rate-limit state so that
+// limits can be enforced consistently across multiple API instances.
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Store tracks per-key request budgets.
+//
+// Implementations own their failure policy: rate limiting fails open, so a store whose
+// backend is unavailable must log the failure and allow the request.
+type Store interface {
+	// Allow consumes one request from key's budget and reports whether it was available. When it
+	// was not, retryAfter is how long until the budget has refilled enough for one request.
+	Allow(ctx context.Context, key string) (allowed bool, retryAfter time.Duration)
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -220,3 +221,21 @@ func (r *AuditLogRepository) DeleteOlderThan(duration time.Duration) (int64, err
 
 	return result.RowsAffected, nil
 }
+
+// CountRetentionExpired counts the audit logs created before cutoff; none are held
+func (r *AuditLogRepository) CountRetentionExpired(ctx context.Context, cutoff time.Time) (models.RetentionCount, error) {
+	count, err := countRetention(ctx, r.db, "audit_logs", cutoff, retentionHold{})
+	if err != nil {
+		return count, fmt.Errorf("failed to count expired audit logs: %w", err)
+	}
+	return count, nil
+}
+
+// PurgeRetentionExpired hard-deletes up to limit audit logs created before cutoff
+func (r *AuditLogRepository) PurgeRetentionExpired(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	deleted, err := purgeRetention(ctx, r.db, &models.AuditLog{}, "audit_logs", cutoff, retentionHold{}, limit)
+	if err != nil {
+		return deleted, fmt.Errorf("failed to purge expired audit logs: %w", err)
+	}
+	return deleted, nil
+}
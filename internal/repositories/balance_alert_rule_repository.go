@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrBalanceAlertRuleNotFound = errors.New("balance alert rule not found")
+)
+
+type balanceAlertRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewBalanceAlertRuleRepository creates a new balance alert rule repository
+func NewBalanceAlertRuleRepository(db *gorm.DB) BalanceAlertRuleRepositoryInterface {
+	return &balanceAlertRuleRepository{db: db}
+}
+
+func (r *balanceAlertRuleRepository) Create(ctx context.Context, rule *models.BalanceAlertRule) error {
+	if rule == nil {
+		return errors.New("rule cannot be nil")
+	}
+	if err := r.db.WithContext(ctx).Create(rule).Error; err != nil {
+		return fmt.Errorf("failed to create balance alert rule: %w", err)
+	}
+	return nil
+}
+
+func (r *balanceAlertRuleRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.BalanceAlertRule, error) {
+	var rule models.BalanceAlertRule
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&rule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBalanceAlertRuleNotFound
+		}
+		return nil, fmt.Errorf("failed to get balance alert rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// ListByUser returns a page of the user's rules, newest first, and the total number of them
+func (r *balanceAlertRuleRepository) ListByUser(ctx context.Context, userID uuid.UUID, offset, limit int) ([]models.BalanceAlertRule, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.BalanceAlertRule{}).Where("user_id = ?", userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count balance alert rules: %w", err)
+	}
+	var rules []models.BalanceAlertRule
+	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&rules).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list balance alert rules: %w", err)
+	}
+	return rules, total, nil
+}
+
+// ListForEvaluation returns every rule, or only the frequent ones, grouped by external account
+func (r *balanceAlertRuleRepository) ListForEvaluation(ctx context.Context, frequentOnly bool) ([]models.BalanceAlertRule, error) {
+	query := r.db.WithContext(ctx)
+	if frequentOnly {
+		query = query.Where("frequent = ?", true)
+	}
+	var rules []models.BalanceAlertRule
+	if err := query.Order("external_account_id ASC, created_at ASC").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to list balance alert rules: %w", err)
+	}
+	return rules, nil
+}
+
+func (r *balanceAlertRuleRepository) Update(ctx context.Context, rule *models.BalanceAlertRule) error {
+	if rule == nil {
+		return errors.New("rule cannot be nil")
+	}
+	if err := r.db.WithContext(ctx).Save(rule).Error; err != nil {
+		return fmt.Errorf("failed to update balance alert rule: %w", err)
+	}
+	return nil
+}
+
+func (r *balanceAlertRuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.BalanceAlertRule{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete balance alert rule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrBalanceAlertRuleNotFound
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/array/banking-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type canaryRunRepository struct {
+	db *gorm.DB
+}
+
+// NewCanaryRunRepository creates a new canary run repository
+func NewCanaryRunRepository(db *gorm.DB) CanaryRunRepositoryInterface {
+	return &canaryRunRepository{db: db}
+}
+
+func (r *canaryRunRepository) Create(ctx context.Context, run *models.CanaryRun) error {
+	if run == nil {
+		return errors.New("run cannot be nil")
+	}
+	if err := r.db.WithContext(ctx).Create(run).Error; err != nil {
+		return fmt.Errorf("failed to create canary run: %w", err)
+	}
+	return nil
+}
+
+func (r *canaryRunRepository) Update(ctx context.Context, run *models.CanaryRun) error {
+	if run == nil {
+		return errors.New("run cannot be nil")
+	}
+	if err := r.db.WithContext(ctx).Save(run).Error; err != nil {
+		return fmt.Errorf("failed to update canary run: %w", err)
+	}
+	return nil
+}
+
+// ListFinished returns up to limit finished runs, most recent first
+func (r *canaryRunRepository) ListFinished(ctx context.Context, limit int) ([]models.CanaryRun, error) {
+	var runs []models.CanaryRun
+	if err := r.db.WithContext(ctx).Where("status <> ?", models.CanaryStatusRunning).
+		Order("started_at DESC").
+		Limit(limit).
+		Find(&runs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list canary runs: %w", err)
+	}
+	return runs, nil
+}
@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type featureFlagOverrideRepository struct {
+	db *gorm.DB
+}
+
+// NewFeatureFlagOverrideRepository creates a new feature flag override repository
+func NewFeatureFlagOverrideRepository(db *gorm.DB) FeatureFlagOverrideRepositoryInterface {
+	return &featureFlagOverrideRepository{db: db}
+}
+
+// Upsert creates the override or replaces the percentage of the existing one for the same flag and user
+func (r *featureFlagOverrideRepository) Upsert(ctx context.Context, override *models.FeatureFlagOverride) error {
+	if override == nil {
+		return errors.New("override cannot be nil")
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing models.FeatureFlagOverride
+		err := scopeOverride(tx, override.Flag, override.UserID).First(&existing).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if err := tx.Create(override).Error; err != nil {
+				return fmt.Errorf("failed to create feature flag override: %w", err)
+			}
+			return nil
+		case err != nil:
+			return fmt.Errorf("failed to get feature flag override: %w", err)
+		}
+
+		existing.Percentage = override.Percentage
+		existing.UpdatedBy = override.UpdatedBy
+		if err := tx.Save(&existing).Error; err != nil {
+			return fmt.Errorf("failed to update feature flag override: %w", err)
+		}
+		*override = existing
+		return nil
+	})
+}
+
+func (r *featureFlagOverrideRepository) Delete(ctx context.Context, flag string, userID *uuid.UUID) error {
+	if err := scopeOverride(r.db.WithContext(ctx), flag, userID).Delete(&models.FeatureFlagOverride{}).Error; err != nil {
+		return fmt.Errorf("failed to delete feature flag override: %w", err)
+	}
+	return nil
+}
+
+// GetApplicable returns the flag-wide override and the user's override, whichever exist
+func (r *featureFlagOverrideRepository) GetApplicable(ctx context.Context, flag string, userID uuid.UUID) ([]models.FeatureFlagOverride, error) {
+	var overrides []models.FeatureFlagOverride
+	if err := r.db.WithContext(ctx).
+		Where("flag = ? AND (user_id IS NULL OR user_id = ?)", flag, userID).
+		Find(&overrides).Error; err != nil {
+		return nil, fmt.Errorf("failed to get feature flag overrides: %w", err)
+	}
+	return overrides, nil
+}
+
+func (r *featureFlagOverrideRepository) List(ctx context.Context) ([]models.FeatureFlagOverride, error) {
+	var overrides []models.FeatureFlagOverride
+	if err := r.db.WithContext(ctx).Order("flag ASC, created_at ASC").Find(&overrides).Error; err != nil {
+		return nil, fmt.Errorf("failed to list feature flag overrides: %w", err)
+	}
+	return overrides, nil
+}
+
+func scopeOverride(db *gorm.DB, flag string, userID *uuid.UUID) *gorm.DB {
+	if userID == nil {
+		return db.Where("flag = ? AND user_id IS NULL", flag)
+	}
+	return db.Where("flag = ? AND user_id = ?", flag, *userID)
+}
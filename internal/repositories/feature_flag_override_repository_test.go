@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/array/banking-api/internal/database"
+	"github.com/array/banking-api/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+// FeatureFlagOverrideRepositorySuite defines the test suite for FeatureFlagOverrideRepository
+type FeatureFlagOverrideRepositorySuite struct {
+	suite.Suite
+	db   *database.DB
+	repo FeatureFlagOverrideRepositoryInterface
+}
+
+// SetupTest runs before each test in the suite
+func (s *FeatureFlagOverrideRepositorySuite) SetupTest() {
+	s.db = database.SetupTestDB(s.T())
+	s.Require().NoError(s.db.DB.AutoMigrate(&models.FeatureFlagOverride{}))
+	s.repo = NewFeatureFlagOverrideRepository(s.db.DB)
+}
+
+// TearDownTest runs after each test in the suite
+func (s *FeatureFlagOverrideRepositorySuite) TearDownTest() {
+	database.CleanupTestDB(s.T(), s.db)
+}
+
+// TestFeatureFlagOverrideRepositorySuite runs the test suite
+func TestFeatureFlagOverrideRepositorySuite(t *testing.T) {
+	suite.Run(t, new(FeatureFlagOverrideRepositorySuite))
+}
+
+func (s *FeatureFlagOverrideRepositorySuite) TestUpsert_ReplacesExisting() {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	s.Require().NoError(s.repo.Upsert(ctx, &models.FeatureFlagOverride{Flag: "risk", Percentage: 10}))
+	s.Require().NoError(s.repo.Upsert(ctx, &models.FeatureFlagOverride{Flag: "risk", UserID: &userID, Percentage: 100}))
+	s.Require().NoError(s.repo.Upsert(ctx, &models.FeatureFlagOverride{Flag: "risk", Percentage: 40}))
+	s.Require().NoError(s.repo.Upsert(ctx, &models.FeatureFlagOverride{Flag: "risk", UserID: &userID, Percentage: 0}))
+
+	overrides, err := s.repo.List(ctx)
+	s.Require().NoError(err)
+	s.Require().Len(overrides, 2)
+	for _, o := range overrides {
+		if o.UserID == nil {
+			s.Equal(40, o.Percentage)
+		} else {
+			s.Equal(0, o.Percentage)
+		}
+	}
+}
+
+func (s *FeatureFlagOverrideRepositorySuite) TestGetApplicable_FlagWideAndOwnUserOnly() {
+	ctx := context.Background()
+	userID, otherUserID := uuid.New(), uuid.New()
+
+	s.Require().NoError(s.repo.Upsert(ctx, &models.FeatureFlagOverride{Flag: "risk", Percentage: 25}))
+	s.Require().NoError(s.repo.Upsert(ctx, &models.FeatureFlagOverride{Flag: "risk", UserID: &userID, Percentage: 100}))
+	s.Require().NoError(s.repo.Upsert(ctx, &models.FeatureFlagOverride{Flag: "risk", UserID: &otherUserID, Percentage: 0}))
+	s.Require().NoError(s.repo.Upsert(ctx, &models.FeatureFlagOverride{Flag: "async", UserID: &userID, Percentage: 100}))
+
+	overrides, err := s.repo.GetApplicable(ctx, "risk", userID)
+	s.Require().NoError(err)
+	s.Require().Len(overrides, 2)
+	for _, o := range overrides {
+		s.Equal("risk", o.Flag)
+		s.True(o.UserID == nil || *o.UserID == userID)
+	}
+}
+
+func (s *FeatureFlagOverrideRepositorySuite) TestDelete_ScopedToUser() {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	s.Require().NoError(s.repo.Upsert(ctx, &models.FeatureFlagOverride{Flag: "risk", Percentage: 25}))
+	s.Require().NoError(s.repo.Upsert(ctx, &models.FeatureFlagOverride{Flag: "risk", UserID: &userID, Percentage: 100}))
+
+	s.Require().NoError(s.repo.Delete(ctx, "risk", &userID))
+	overrides, err := s.repo.List(ctx)
+	s.Require().NoError(err)
+	s.Require().Len(overrides, 1)
+	s.Nil(overrides[0].UserID)
+
+	s.Require().NoError(s.repo.Delete(ctx, "risk", nil))
+	overrides, err = s.repo.List(ctx)
+	s.Require().NoError(err)
+	s.Empty(overrides)
+}
@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+//go:generate go run ./instrumentgen
+
+// RepositoryMetrics holds the Prometheus collectors the Instrument*Repository decorators record
+// into. Labels are limited to repository and method names, so cardinality is fixed by the
+// interfaces.
+type RepositoryMetrics struct {
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+}
+
+// NewRepositoryMetrics creates the repository collectors and registers them with reg
+func NewRepositoryMetrics(reg prometheus.Registerer) *RepositoryMetrics {
+	factory := promauto.With(reg)
+	return &RepositoryMetrics{
+		duration: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "repository_call_duration_seconds",
+				Help:    "Duration of repository calls in seconds, by repository and method",
+				Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+			},
+			[]string{"repository", "method"},
+		),
+		errors: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "repository_call_errors_total",
+				Help: "Total number of repository calls that returned an error, by repository and method",
+			},
+			[]string{"repository", "method"},
+		),
+	}
+}
+
+// observe records one call that started at start. The histogram's count is the number of calls.
+func (m *RepositoryMetrics) observe(repository, method string, start time.Time, err error) {
+	m.duration.WithLabelValues(repository, method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		m.errors.WithLabelValues(repository, method).Inc()
+	}
+}
@@ -0,0 +1,1779 @@
+// Code generated by instrumentgen from interfaces.go. DO NOT EDIT.
+
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// instrumentedAccountRepository records the duration and errors of every AccountRepositoryInterface call
+type instrumentedAccountRepository struct {
+	next    AccountRepositoryInterface
+	metrics *RepositoryMetrics
+}
+
+// InstrumentAccountRepository wraps repo so its calls are recorded in metrics. With nil metrics
+// it returns repo itself.
+func InstrumentAccountRepository(repo AccountRepositoryInterface, metrics *RepositoryMetrics) AccountRepositoryInterface {
+	if metrics == nil {
+		return repo
+	}
+	return &instrumentedAccountRepository{next: repo, metrics: metrics}
+}
+
+func (w *instrumentedAccountRepository) Create(account *models.Account) error {
+	start := time.Now()
+	err := w.next.Create(account)
+	w.metrics.observe("account", "Create", start, err)
+	return err
+}
+
+func (w *instrumentedAccountRepository) GetByID(id uuid.UUID) (*models.Account, error) {
+	start := time.Now()
+	r0, err := w.next.GetByID(id)
+	w.metrics.observe("account", "GetByID", start, err)
+	return r0, err
+}
+
+func (w *instrumentedAccountRepository) GetByAccountNumber(accountNumber string) (*models.Account, error) {
+	start := time.Now()
+	r0, err := w.next.GetByAccountNumber(accountNumber)
+	w.metrics.observe("account", "GetByAccountNumber", start, err)
+	return r0, err
+}
+
+func (w *instrumentedAccountRepository) GetByUserID(userID uuid.UUID) ([]models.Account, error) {
+	start := time.Now()
+	r0, err := w.next.GetByUserID(userID)
+	w.metrics.observe("account", "GetByUserID", start, err)
+	return r0, err
+}
+
+func (w *instrumentedAccountRepository) GetByUserIDAndType(userID uuid.UUID, accountType string) ([]models.Account, error) {
+	start := time.Now()
+	r0, err := w.next.GetByUserIDAndType(userID, accountType)
+	w.metrics.observe("account", "GetByUserIDAndType", start, err)
+	return r0, err
+}
+
+func (w *instrumentedAccountRepository) GetByUserIDExcludingStatus(userID uuid.UUID, excludeStatus string) ([]*models.Account, error) {
+	start := time.Now()
+	r0, err := w.next.GetByUserIDExcludingStatus(userID, excludeStatus)
+	w.metrics.observe("account", "GetByUserIDExcludingStatus", start, err)
+	return r0, err
+}
+
+func (w *instrumentedAccountRepository) GetAll(offset int, limit int) ([]models.Account, int64, error) {
+	start := time.Now()
+	r0, r1, err := w.next.GetAll(offset, limit)
+	w.metrics.observe("account", "GetAll", start, err)
+	return r0, r1, err
+}
+
+func (w *instrumentedAccountRepository) GetAllWithFilters(filters models.AccountFilters, offset int, limit int) ([]models.Account, int64, error) {
+	start := time.Now()
+	r0, r1, err := w.next.GetAllWithFilters(filters, offset, limit)
+	w.metrics.observe("account", "GetAllWithFilters", start, err)
+	return r0, r1, err
+}
+
+func (w *instrumentedAccountRepository) Update(account *models.Account) error {
+	start := time.Now()
+	err := w.next.Update(account)
+	w.metrics.observe("account", "Update", start, err)
+	return err
+}
+
+func (w *instrumentedAccountRepository) UpdateOwnership(accountID uuid.UUID, newUserID uuid.UUID) error {
+	start := time.Now()
+	err := w.next.UpdateOwnership(accountID, newUserID)
+	w.metrics.observe("account", "UpdateOwnership", start, err)
+	return err
+}
+
+func (w *instrumentedAccountRepository) Delete(id uuid.UUID) error {
+	start := time.Now()
+	err := w.next.Delete(id)
+	w.metrics.observe("account", "Delete", start, err)
+	return err
+}
+
+func (w *instrumentedAccountRepository) SoftDeleteByUserID(userID uuid.UUID) error {
+	start := time.Now()
+	err := w.next.SoftDeleteByUserID(userID)
+	w.metrics.observe("account", "SoftDeleteByUserID", start, err)
+	return err
+}
+
+func (w *instrumentedAccountRepository) CheckAccountNumberExists(accountNumber string) (bool, error) {
+	start := time.Now()
+	r0, err := w.next.CheckAccountNumberExists(accountNumber)
+	w.metrics.observe("account", "CheckAccountNumberExists", start, err)
+	return r0, err
+}
+
+func (w *instrumentedAccountRepository) GenerateUniqueAccountNumber(accountType string) (string, error) {
+	start := time.Now()
+	r0, err := w.next.GenerateUniqueAccountNumber(accountType)
+	w.metrics.observe("account", "GenerateUniqueAccountNumber", start, err)
+	return r0, err
+}
+
+func (w *instrumentedAccountRepository) CreateWithTransaction(account *models.Account, transactions []*models.Transaction) error {
+	start := time.Now()
+	err := w.next.CreateWithTransaction(account, transactions)
+	w.metrics.observe("account", "CreateWithTransaction", start, err)
+	return err
+}
+
+func (w *instrumentedAccountRepository) UpdateBalance(accountID uuid.UUID, amount decimal.Decimal, transactionType string) error {
+	start := time.Now()
+	err := w.next.UpdateBalance(accountID, amount, transactionType)
+	w.metrics.observe("account", "UpdateBalance", start, err)
+	return err
+}
+
+func (w *instrumentedAccountRepository) GetAccountsByStatus(status string, offset int, limit int) ([]models.Account, error) {
+	start := time.Now()
+	r0, err := w.next.GetAccountsByStatus(status, offset, limit)
+	w.metrics.observe("account", "GetAccountsByStatus", start, err)
+	return r0, err
+}
+
+func (w *instrumentedAccountRepository) GetTotalBalanceByUserID(userID uuid.UUID) (decimal.Decimal, error) {
+	start := time.Now()
+	r0, err := w.next.GetTotalBalanceByUserID(userID)
+	w.metrics.observe("account", "GetTotalBalanceByUserID", start, err)
+	return r0, err
+}
+
+func (w *instrumentedAccountRepository) ExistsForUser(userID uuid.UUID, accountType string) (bool, error) {
+	start := time.Now()
+	r0, err := w.next.ExistsForUser(userID, accountType)
+	w.metrics.observe("account", "ExistsForUser", start, err)
+	return r0, err
+}
+
+func (w *instrumentedAccountRepository) ExecuteAtomicTransfer(fromAccountID uuid.UUID, toAccountID uuid.UUID, amount decimal.Decimal, fromDescription string, toDescription string) (uuid.UUID, uuid.UUID, error) {
+	start := time.Now()
+	r0, r1, err := w.next.ExecuteAtomicTransfer(fromAccountID, toAccountID, amount, fromDescription, toDescription)
+	w.metrics.observe("account", "ExecuteAtomicTransfer", start, err)
+	return r0, r1, err
+}
+
+// instrumentedTransactionRepository records the duration and errors of every TransactionRepositoryInterface call
+type instrumentedTransactionRepository struct {
+	next    TransactionRepositoryInterface
+	metrics *RepositoryMetrics
+}
+
+// InstrumentTransactionRepository wraps repo so its calls are recorded in metrics. With nil metrics
+// it returns repo itself.
+func InstrumentTransactionRepository(repo TransactionRepositoryInterface, metrics *RepositoryMetrics) TransactionRepositoryInterface {
+	if metrics == nil {
+		return repo
+	}
+	return &instrumentedTransactionRepository{next: repo, metrics: metrics}
+}
+
+func (w *instrumentedTransactionRepository) Create(ctx context.Context, transaction *models.Transaction) error {
+	start := time.Now()
+	err := w.next.Create(ctx, transaction)
+	w.metrics.observe("transaction", "Create", start, err)
+	return err
+}
+
+func (w *instrumentedTransactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
+	start := time.Now()
+	r0, err := w.next.GetByID(ctx, id)
+	w.metrics.observe("transaction", "GetByID", start, err)
+	return r0, err
+}
+
+func (w *instrumentedTransactionRepository) GetByAccountID(ctx context.Context, accountID uuid.UUID, offset int, limit int) ([]models.Transaction, int64, error) {
+	start := time.Now()
+	r0, r1, err := w.next.GetByAccountID(ctx, accountID, offset, limit)
+	w.metrics.observe("transaction", "GetByAccountID", start, err)
+	return r0, r1, err
+}
+
+func (w *instrumentedTransactionRepository) GetByReference(ctx context.Context, reference string) (*models.Transaction, error) {
+	start := time.Now()
+	r0, err := w.next.GetByReference(ctx, reference)
+	w.metrics.observe("transaction", "GetByReference", start, err)
+	return r0, err
+}
+
+func (w *instrumentedTransactionRepository) GetRecentByAccountID(ctx context.Context, accountID uuid.UUID, limit int) ([]models.Transaction, error) {
+	start := time.Now()
+	r0, err := w.next.GetRecentByAccountID(ctx, accountID, limit)
+	w.metrics.observe("transaction", "GetRecentByAccountID", start, err)
+	return r0, err
+}
+
+func (w *instrumentedTransactionRepository) GetByAccountIDKeyset(ctx context.Context, accountID uuid.UUID, after *models.AccountActivityKeyset, limit int) ([]models.Transaction, error) {
+	start := time.Now()
+	r0, err := w.next.GetByAccountIDKeyset(ctx, accountID, after, limit)
+	w.metrics.observe("transaction", "GetByAccountIDKeyset", start, err)
+	return r0, err
+}
+
+func (w *instrumentedTransactionRepository) GetByDateRange(ctx context.Context, accountID uuid.UUID, startDate time.Time, endDate time.Time) ([]models.Transaction, error) {
+	start := time.Now()
+	r0, err := w.next.GetByDateRange(ctx, accountID, startDate, endDate)
+	w.metrics.observe("transaction", "GetByDateRange", start, err)
+	return r0, err
+}
+
+func (w *instrumentedTransactionRepository) CreateBatch(ctx context.Context, transactions []models.Transaction) error {
+	start := time.Now()
+	err := w.next.CreateBatch(ctx, transactions)
+	w.metrics.observe("transaction", "CreateBatch", start, err)
+	return err
+}
+
+func (w *instrumentedTransactionRepository) GetPendingTransactions(ctx context.Context, offset int, limit int) ([]models.Transaction, error) {
+	start := time.Now()
+	r0, err := w.next.GetPendingTransactions(ctx, offset, limit)
+	w.metrics.observe("transaction", "GetPendingTransactions", start, err)
+	return r0, err
+}
+
+func (w *instrumentedTransactionRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status string) error {
+	start := time.Now()
+	err := w.next.UpdateStatus(ctx, id, status)
+	w.metrics.observe("transaction", "UpdateStatus", start, err)
+	return err
+}
+
+func (w *instrumentedTransactionRepository) GetTotalsByAccountID(ctx context.Context, accountID uuid.UUID) (int64, int64, string, string, error) {
+	start := time.Now()
+	r0, r1, r2, r3, err := w.next.GetTotalsByAccountID(ctx, accountID)
+	w.metrics.observe("transaction", "GetTotalsByAccountID", start, err)
+	return r0, r1, r2, r3, err
+}
+
+func (w *instrumentedTransactionRepository) GetByCategory(ctx context.Context, accountID uuid.UUID, category string, offset int, limit int) ([]models.Transaction, int64, error) {
+	start := time.Now()
+	r0, r1, err := w.next.GetByCategory(ctx, accountID, category, offset, limit)
+	w.metrics.observe("transaction", "GetByCategory", start, err)
+	return r0, r1, err
+}
+
+func (w *instrumentedTransactionRepository) GetWithFilters(ctx context.Context, filters models.TransactionFilters) ([]models.Transaction, int64, error) {
+	start := time.Now()
+	r0, r1, err := w.next.GetWithFilters(ctx, filters)
+	w.metrics.observe("transaction", "GetWithFilters", start, err)
+	return r0, r1, err
+}
+
+func (w *instrumentedTransactionRepository) UpdateWithOptimisticLock(ctx context.Context, transaction *models.Transaction, expectedVersion int) error {
+	start := time.Now()
+	err := w.next.UpdateWithOptimisticLock(ctx, transaction, expectedVersion)
+	w.metrics.observe("transaction", "UpdateWithOptimisticLock", start, err)
+	return err
+}
+
+func (w *instrumentedTransactionRepository) GetExpiredPendingTransactions(ctx context.Context, limit int) ([]models.Transaction, error) {
+	start := time.Now()
+	r0, err := w.next.GetExpiredPendingTransactions(ctx, limit)
+	w.metrics.observe("transaction", "GetExpiredPendingTransactions", start, err)
+	return r0, err
+}
+
+func (w *instrumentedTransactionRepository) GetCategorySummary(ctx context.Context, accountID uuid.UUID, startDate time.Time, endDate time.Time) ([]models.CategorySummary, error) {
+	start := time.Now()
+	r0, err := w.next.GetCategorySummary(ctx, accountID, startDate, endDate)
+	w.metrics.observe("transaction", "GetCategorySummary", start, err)
+	return r0, err
+}
+
+func (w *instrumentedTransactionRepository) CountRetentionExpired(ctx context.Context, cutoff time.Time) (models.RetentionCount, error) {
+	start := time.Now()
+	r0, err := w.next.CountRetentionExpired(ctx, cutoff)
+	w.metrics.observe("transaction", "CountRetentionExpired", start, err)
+	return r0, err
+}
+
+func (w *instrumentedTransactionRepository) PurgeRetentionExpired(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	start := time.Now()
+	r0, err := w.next.PurgeRetentionExpired(ctx, cutoff, limit)
+	w.metrics.observe("transaction", "PurgeRetentionExpired", start, err)
+	return r0, err
+}
+
+// instrumentedUserRepository records the duration and errors of every UserRepositoryInterface call
+type instrumentedUserRepository struct {
+	next    UserRepositoryInterface
+	metrics *RepositoryMetrics
+}
+
+// InstrumentUserRepository wraps repo so its calls are recorded in metrics. With nil metrics
+// it returns repo itself.
+func InstrumentUserRepository(repo UserRepositoryInterface, metrics *RepositoryMetrics) UserRepositoryInterface {
+	if metrics == nil {
+		return repo
+	}
+	return &instrumentedUserRepository{next: repo, metrics: metrics}
+}
+
+func (w *instrumentedUserRepository) Create(ctx context.Context, user *models.User) error {
+	start := time.Now()
+	err := w.next.Create(ctx, user)
+	w.metrics.observe("user", "Create", start, err)
+	return err
+}
+
+func (w *instrumentedUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	start := time.Now()
+	r0, err := w.next.GetByID(ctx, id)
+	w.metrics.observe("user", "GetByID", start, err)
+	return r0, err
+}
+
+func (w *instrumentedUserRepository) GetByIDActive(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	start := time.Now()
+	r0, err := w.next.GetByIDActive(ctx, id)
+	w.metrics.observe("user", "GetByIDActive", start, err)
+	return r0, err
+}
+
+func (w *instrumentedUserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	start := time.Now()
+	r0, err := w.next.GetByEmail(ctx, email)
+	w.metrics.observe("user", "GetByEmail", start, err)
+	return r0, err
+}
+
+func (w *instrumentedUserRepository) GetByEmailExcluding(ctx context.Context, email string, excludeUserID uuid.UUID) (*models.User, error) {
+	start := time.Now()
+	r0, err := w.next.GetByEmailExcluding(ctx, email, excludeUserID)
+	w.metrics.observe("user", "GetByEmailExcluding", start, err)
+	return r0, err
+}
+
+func (w *instrumentedUserRepository) SearchUsers(ctx context.Context, criteria UserSearchCriteria, offset int, limit int) ([]*models.User, int64, error) {
+	start := time.Now()
+	r0, r1, err := w.next.SearchUsers(ctx, criteria, offset, limit)
+	w.metrics.observe("user", "SearchUsers", start, err)
+	return r0, r1, err
+}
+
+func (w *instrumentedUserRepository) Update(ctx context.Context, user *models.User) error {
+	start := time.Now()
+	err := w.next.Update(ctx, user)
+	w.metrics.observe("user", "Update", start, err)
+	return err
+}
+
+func (w *instrumentedUserRepository) UpdateFields(ctx context.Context, userID uuid.UUID, fields map[string]interface{}) error {
+	start := time.Now()
+	err := w.next.UpdateFields(ctx, userID, fields)
+	w.metrics.observe("user", "UpdateFields", start, err)
+	return err
+}
+
+func (w *instrumentedUserRepository) UpdateEmail(ctx context.Context, userID uuid.UUID, newEmail string) error {
+	start := time.Now()
+	err := w.next.UpdateEmail(ctx, userID, newEmail)
+	w.metrics.observe("user", "UpdateEmail", start, err)
+	return err
+}
+
+func (w *instrumentedUserRepository) UpdatePasswordHash(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	start := time.Now()
+	err := w.next.UpdatePasswordHash(ctx, userID, passwordHash)
+	w.metrics.observe("user", "UpdatePasswordHash", start, err)
+	return err
+}
+
+func (w *instrumentedUserRepository) UpdateFailedLoginAttempts(ctx context.Context, user *models.User) error {
+	start := time.Now()
+	err := w.next.UpdateFailedLoginAttempts(ctx, user)
+	w.metrics.observe("user", "UpdateFailedLoginAttempts", start, err)
+	return err
+}
+
+func (w *instrumentedUserRepository) ResetFailedLoginAttempts(ctx context.Context, userID uuid.UUID) error {
+	start := time.Now()
+	err := w.next.ResetFailedLoginAttempts(ctx, userID)
+	w.metrics.observe("user", "ResetFailedLoginAttempts", start, err)
+	return err
+}
+
+func (w *instrumentedUserRepository) UnlockAccount(ctx context.Context, userID uuid.UUID) error {
+	start := time.Now()
+	err := w.next.UnlockAccount(ctx, userID)
+	w.metrics.observe("user", "UnlockAccount", start, err)
+	return err
+}
+
+func (w *instrumentedUserRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	start := time.Now()
+	err := w.next.Delete(ctx, userID)
+	w.metrics.observe("user", "Delete", start, err)
+	return err
+}
+
+func (w *instrumentedUserRepository) ListUsers(ctx context.Context, offset int, limit int) ([]*models.User, int64, error) {
+	start := time.Now()
+	r0, r1, err := w.next.ListUsers(ctx, offset, limit)
+	w.metrics.observe("user", "ListUsers", start, err)
+	return r0, r1, err
+}
+
+func (w *instrumentedUserRepository) CountAccountsByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	start := time.Now()
+	r0, err := w.next.CountAccountsByUserID(ctx, userID)
+	w.metrics.observe("user", "CountAccountsByUserID", start, err)
+	return r0, err
+}
+
+// instrumentedAuditLogRepository records the duration and errors of every AuditLogRepositoryInterface call
+type instrumentedAuditLogRepository struct {
+	next    AuditLogRepositoryInterface
+	metrics *RepositoryMetrics
+}
+
+// InstrumentAuditLogRepository wraps repo so its calls are recorded in metrics. With nil metrics
+// it returns repo itself.
+func InstrumentAuditLogRepository(repo AuditLogRepositoryInterface, metrics *RepositoryMetrics) AuditLogRepositoryInterface {
+	if metrics == nil {
+		return repo
+	}
+	return &instrumentedAuditLogRepository{next: repo, metrics: metrics}
+}
+
+func (w *instrumentedAuditLogRepository) Create(log *models.AuditLog) error {
+	start := time.Now()
+	err := w.next.Create(log)
+	w.metrics.observe("audit_log", "Create", start, err)
+	return err
+}
+
+func (w *instrumentedAuditLogRepository) GetByID(id uuid.UUID) (*models.AuditLog, error) {
+	start := time.Now()
+	r0, err := w.next.GetByID(id)
+	w.metrics.observe("audit_log", "GetByID", start, err)
+	return r0, err
+}
+
+func (w *instrumentedAuditLogRepository) GetByUserID(userID uuid.UUID, offset int, limit int) ([]*models.AuditLog, int64, error) {
+	start := time.Now()
+	r0, r1, err := w.next.GetByUserID(userID, offset, limit)
+	w.metrics.observe("audit_log", "GetByUserID", start, err)
+	return r0, r1, err
+}
+
+func (w *instrumentedAuditLogRepository) GetByAction(action string, offset int, limit int) ([]*models.AuditLog, int64, error) {
+	start := time.Now()
+	r0, r1, err := w.next.GetByAction(action, offset, limit)
+	w.metrics.observe("audit_log", "GetByAction", start, err)
+	return r0, r1, err
+}
+
+func (w *instrumentedAuditLogRepository) GetByResource(resource string, resourceID string, offset int, limit int) ([]*models.AuditLog, int64, error) {
+	start := time.Now()
+	r0, r1, err := w.next.GetByResource(resource, resourceID, offset, limit)
+	w.metrics.observe("audit_log", "GetByResource", start, err)
+	return r0, r1, err
+}
+
+func (w *instrumentedAuditLogRepository) GetByIPAddress(ipAddress string, offset int, limit int) ([]*models.AuditLog, int64, error) {
+	start := time.Now()
+	r0, r1, err := w.next.GetByIPAddress(ipAddress, offset, limit)
+	w.metrics.observe("audit_log", "GetByIPAddress", start, err)
+	return r0, r1, err
+}
+
+func (w *instrumentedAuditLogRepository) GetByTimeRange(startTime time.Time, endTime time.Time, offset int, limit int) ([]*models.AuditLog, int64, error) {
+	start := time.Now()
+	r0, r1, err := w.next.GetByTimeRange(startTime, endTime, offset, limit)
+	w.metrics.observe("audit_log", "GetByTimeRange", start, err)
+	return r0, r1, err
+}
+
+func (w *instrumentedAuditLogRepository) GetCustomerActivity(userID uuid.UUID, startDate *time.Time, endDate *time.Time, offset int, limit int) ([]*models.AuditLog, int64, error) {
+	start := time.Now()
+	r0, r1, err := w.next.GetCustomerActivity(userID, startDate, endDate, offset, limit)
+	w.metrics.observe("audit_log", "GetCustomerActivity", start, err)
+	return r0, r1, err
+}
+
+func (w *instrumentedAuditLogRepository) GetFailedLoginAttempts(email string, since time.Time) (int64, error) {
+	start := time.Now()
+	r0, err := w.next.GetFailedLoginAttempts(email, since)
+	w.metrics.observe("audit_log", "GetFailedLoginAttempts", start, err)
+	return r0, err
+}
+
+func (w *instrumentedAuditLogRepository) DeleteOlderThan(duration time.Duration) (int64, error) {
+	start := time.Now()
+	r0, err := w.next.DeleteOlderThan(duration)
+	w.metrics.observe("audit_log", "DeleteOlderThan", start, err)
+	return r0, err
+}
+
+func (w *instrumentedAuditLogRepository) CountRetentionExpired(ctx context.Context, cutoff time.Time) (models.RetentionCount, error) {
+	start := time.Now()
+	r0, err := w.next.CountRetentionExpired(ctx, cutoff)
+	w.metrics.observe("audit_log", "CountRetentionExpired", start, err)
+	return r0, err
+}
+
+func (w *instrumentedAuditLogRepository) PurgeRetentionExpired(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	start := time.Now()
+	r0, err := w.next.PurgeRetentionExpired(ctx, cutoff, limit)
+	w.metrics.observe("audit_log", "PurgeRetentionExpired", start, err)
+	return r0, err
+}
+
+// instrumentedProcessingQueueRepository records the duration and errors of every ProcessingQueueRepositoryInterface call
+type instrumentedProcessingQueueRepository struct {
+	next    ProcessingQueueRepositoryInterface
+	metrics *RepositoryMetrics
+}
+
+// InstrumentProcessingQueueRepository wraps repo so its calls are recorded in metrics. With nil metrics
+// it returns repo itself.
+func InstrumentProcessingQueueRepository(repo ProcessingQueueRepositoryInterface, metrics *RepositoryMetrics) ProcessingQueueRepositoryInterface {
+	if metrics == nil {
+		return repo
+	}
+	return &instrumentedProcessingQueueRepository{next: repo, metrics: metrics}
+}
+
+func (w *instrumentedProcessingQueueRepository) Enqueue(transactionID uuid.UUID, operation string, priority int) error {
+	start := time.Now()
+	err := w.next.Enqueue(transactionID, operation, priority)
+	w.metrics.observe("processing_queue", "Enqueue", start, err)
+	return err
+}
+
+func (w *instrumentedProcessingQueueRepository) FetchPending(limit int) ([]*models.ProcessingQueueItem, error) {
+	start := time.Now()
+	r0, err := w.next.FetchPending(limit)
+	w.metrics.observe("processing_queue", "FetchPending", start, err)
+	return r0, err
+}
+
+func (w *instrumentedProcessingQueueRepository) MarkProcessing(queueItemID uuid.UUID) error {
+	start := time.Now()
+	err := w.next.MarkProcessing(queueItemID)
+	w.metrics.observe("processing_queue", "MarkProcessing", start, err)
+	return err
+}
+
+func (w *instrumentedProcessingQueueRepository) MarkCompleted(queueItemID uuid.UUID) error {
+	start := time.Now()
+	err := w.next.MarkCompleted(queueItemID)
+	w.metrics.observe("processing_queue", "MarkCompleted", start, err)
+	return err
+}
+
+func (w *instrumentedProcessingQueueRepository) MarkFailed(queueItemID uuid.UUID, errorMessage string) error {
+	start := time.Now()
+	err := w.next.MarkFailed(queueItemID, errorMessage)
+	w.metrics.observe("processing_queue", "MarkFailed", start, err)
+	return err
+}
+
+func (w *instrumentedProcessingQueueRepository) IncrementRetry(queueItemID uuid.UUID) error {
+	start := time.Now()
+	err := w.next.IncrementRetry(queueItemID)
+	w.metrics.observe("processing_queue", "IncrementRetry", start, err)
+	return err
+}
+
+func (w *instrumentedProcessingQueueRepository) ResetStaleProcessing(staleBefore time.Time) (int64, error) {
+	start := time.Now()
+	r0, err := w.next.ResetStaleProcessing(staleBefore)
+	w.metrics.observe("processing_queue", "ResetStaleProcessing", start, err)
+	return r0, err
+}
+
+func (w *instrumentedProcessingQueueRepository) GetPendingCount() (int64, error) {
+	start := time.Now()
+	r0, err := w.next.GetPendingCount()
+	w.metrics.observe("processing_queue", "GetPendingCount", start, err)
+	return r0, err
+}
+
+func (w *instrumentedProcessingQueueRepository) GetProcessingCount() (int64, error) {
+	start := time.Now()
+	r0, err := w.next.GetProcessingCount()
+	w.metrics.observe("processing_queue", "GetProcessingCount", start, err)
+	return r0, err
+}
+
+func (w *instrumentedProcessingQueueRepository) GetFailedCount() (int64, error) {
+	start := time.Now()
+	r0, err := w.next.GetFailedCount()
+	w.metrics.observe("processing_queue", "GetFailedCount", start, err)
+	return r0, err
+}
+
+func (w *instrumentedProcessingQueueRepository) GetCompletedCount() (int64, error) {
+	start := time.Now()
+	r0, err := w.next.GetCompletedCount()
+	w.metrics.observe("processing_queue", "GetCompletedCount", start, err)
+	return r0, err
+}
+
+func (w *instrumentedProcessingQueueRepository) GetAverageProcessingTime() (float64, error) {
+	start := time.Now()
+	r0, err := w.next.GetAverageProcessingTime()
+	w.metrics.observe("processing_queue", "GetAverageProcessingTime", start, err)
+	return r0, err
+}
+
+func (w *instrumentedProcessingQueueRepository) GetOldestPendingAge() (*string, error) {
+	start := time.Now()
+	r0, err := w.next.GetOldestPendingAge()
+	w.metrics.observe("processing_queue", "GetOldestPendingAge", start, err)
+	return r0, err
+}
+
+func (w *instrumentedProcessingQueueRepository) CleanupCompleted(olderThan time.Duration) (int64, error) {
+	start := time.Now()
+	r0, err := w.next.CleanupCompleted(olderThan)
+	w.metrics.observe("processing_queue", "CleanupCompleted", start, err)
+	return r0, err
+}
+
+// instrumentedTransferRepository records the duration and errors of every TransferRepositoryInterface call
+type instrumentedTransferRepository struct {
+	next    TransferRepositoryInterface
+	metrics *RepositoryMetrics
+}
+
+// InstrumentTransferRepository wraps repo so its calls are recorded in metrics. With nil metrics
+// it returns repo itself.
+func InstrumentTransferRepository(repo TransferRepositoryInterface, metrics *RepositoryMetrics) TransferRepositoryInterface {
+	if metrics == nil {
+		return repo
+	}
+	return &instrumentedTransferRepository{next: repo, metrics: metrics}
+}
+
+func (w *instrumentedTransferRepository) Create(transfer *models.Transfer) error {
+	start := time.Now()
+	err := w.next.Create(transfer)
+	w.metrics.observe("transfer", "Create", start, err)
+	return err
+}
+
+func (w *instrumentedTransferRepository) Update(transfer *models.Transfer) error {
+	start := time.Now()
+	err := w.next.Update(transfer)
+	w.metrics.observe("transfer", "Update", start, err)
+	return err
+}
+
+func (w *instrumentedTransferRepository) FindByID(id uuid.UUID) (*models.Transfer, error) {
+	start := time.Now()
+	r0, err := w.next.FindByID(id)
+	w.metrics.observe("transfer", "FindByID", start, err)
+	return r0, err
+}
+
+func (w *instrumentedTransferRepository) FindByIdempotencyKey(key string) (*models.Transfer, error) {
+	start := time.Now()
+	r0, err := w.next.FindByIdempotencyKey(key)
+	w.metrics.observe("transfer", "FindByIdempotencyKey", start, err)
+	return r0, err
+}
+
+func (w *instrumentedTransferRepository) FindByUserAccounts(accountIDs []uuid.UUID, offset int, limit int) ([]models.Transfer, int64, error) {
+	start := time.Now()
+	r0, r1, err := w.next.FindByUserAccounts(accountIDs, offset, limit)
+	w.metrics.observe("transfer", "FindByUserAccounts", start, err)
+	return r0, r1, err
+}
+
+func (w *instrumentedTransferRepository) FindByUserAccountsWithFilters(accountIDs []uuid.UUID, filters models.TransferFilters, offset int, limit int) ([]models.Transfer, int64, error) {
+	start := time.Now()
+	r0, r1, err := w.next.FindByUserAccountsWithFilters(accountIDs, filters, offset, limit)
+	w.metrics.observe("transfer", "FindByUserAccountsWithFilters", start, err)
+	return r0, r1, err
+}
+
+func (w *instrumentedTransferRepository) CountByUserAccounts(accountIDs []uuid.UUID) (int64, error) {
+	start := time.Now()
+	r0, err := w.next.CountByUserAccounts(accountIDs)
+	w.metrics.observe("transfer", "CountByUserAccounts", start, err)
+	return r0, err
+}
+
+func (w *instrumentedTransferRepository) CountRetentionExpired(ctx context.Context, cutoff time.Time) (models.RetentionCount, error) {
+	start := time.Now()
+	r0, err := w.next.CountRetentionExpired(ctx, cutoff)
+	w.metrics.observe("transfer", "CountRetentionExpired", start, err)
+	return r0, err
+}
+
+func (w *instrumentedTransferRepository) PurgeRetentionExpired(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	start := time.Now()
+	r0, err := w.next.PurgeRetentionExpired(ctx, cutoff, limit)
+	w.metrics.observe("transfer", "PurgeRetentionExpired", start, err)
+	return r0, err
+}
+
+// instrumentedRefreshTokenRepository records the duration and errors of every RefreshTokenRepositoryInterface call
+type instrumentedRefreshTokenRepository struct {
+	next    RefreshTokenRepositoryInterface
+	metrics *RepositoryMetrics
+}
+
+// InstrumentRefreshTokenRepository wraps repo so its calls are recorded in metrics. With nil metrics
+// it returns repo itself.
+func InstrumentRefreshTokenRepository(repo RefreshTokenRepositoryInterface, metrics *RepositoryMetrics) RefreshTokenRepositoryInterface {
+	if metrics == nil {
+		return repo
+	}
+	return &instrumentedRefreshTokenRepository{next: repo, metrics: metrics}
+}
+
+func (w *instrumentedRefreshTokenRepository) Create(token *models.RefreshToken) error {
+	start := time.Now()
+	err := w.next.Create(token)
+	w.metrics.observe("refresh_token", "Create", start, err)
+	return err
+}
+
+func (w *instrumentedRefreshTokenRepository) GetByID(id uuid.UUID) (*models.RefreshToken, error) {
+	start := time.Now()
+	r0, err := w.next.GetByID(id)
+	w.metrics.observe("refresh_token", "GetByID", start, err)
+	return r0, err
+}
+
+func (w *instrumentedRefreshTokenRepository) GetByTokenHash(tokenHash string) (*models.RefreshToken, error) {
+	start := time.Now()
+	r0, err := w.next.GetByTokenHash(tokenHash)
+	w.metrics.observe("refresh_token", "GetByTokenHash", start, err)
+	return r0, err
+}
+
+func (w *instrumentedRefreshTokenRepository) GetActiveByUserID(userID uuid.UUID) ([]*models.RefreshToken, error) {
+	start := time.Now()
+	r0, err := w.next.GetActiveByUserID(userID)
+	w.metrics.observe("refresh_token", "GetActiveByUserID", start, err)
+	return r0, err
+}
+
+func (w *instrumentedRefreshTokenRepository) Update(token *models.RefreshToken) error {
+	start := time.Now()
+	err := w.next.Update(token)
+	w.metrics.observe("refresh_token", "Update", start, err)
+	return err
+}
+
+func (w *instrumentedRefreshTokenRepository) Revoke(tokenID uuid.UUID) error {
+	start := time.Now()
+	err := w.next.Revoke(tokenID)
+	w.metrics.observe("refresh_token", "Revoke", start, err)
+	return err
+}
+
+func (w *instrumentedRefreshTokenRepository) RevokeAllForUser(userID uuid.UUID) error {
+	start := time.Now()
+	err := w.next.RevokeAllForUser(userID)
+	w.metrics.observe("refresh_token", "RevokeAllForUser", start, err)
+	return err
+}
+
+func (w *instrumentedRefreshTokenRepository) DeleteExpired() (int64, error) {
+	start := time.Now()
+	r0, err := w.next.DeleteExpired()
+	w.metrics.observe("refresh_token", "DeleteExpired", start, err)
+	return r0, err
+}
+
+func (w *instrumentedRefreshTokenRepository) DeleteRevokedOlderThan(duration time.Duration) (int64, error) {
+	start := time.Now()
+	r0, err := w.next.DeleteRevokedOlderThan(duration)
+	w.metrics.observe("refresh_token", "DeleteRevokedOlderThan", start, err)
+	return r0, err
+}
+
+// instrumentedBlacklistedTokenRepository records the duration and errors of every BlacklistedTokenRepositoryInterface call
+type instrumentedBlacklistedTokenRepository struct {
+	next    BlacklistedTokenRepositoryInterface
+	metrics *RepositoryMetrics
+}
+
+// InstrumentBlacklistedTokenRepository wraps repo so its calls are recorded in metrics. With nil metrics
+// it returns repo itself.
+func InstrumentBlacklistedTokenRepository(repo BlacklistedTokenRepositoryInterface, metrics *RepositoryMetrics) BlacklistedTokenRepositoryInterface {
+	if metrics == nil {
+		return repo
+	}
+	return &instrumentedBlacklistedTokenRepository{next: repo, metrics: metrics}
+}
+
+func (w *instrumentedBlacklistedTokenRepository) Create(token *models.BlacklistedToken) error {
+	start := time.Now()
+	err := w.next.Create(token)
+	w.metrics.observe("blacklisted_token", "Create", start, err)
+	return err
+}
+
+func (w *instrumentedBlacklistedTokenRepository) GetByJTI(jti string) (*models.BlacklistedToken, error) {
+	start := time.Now()
+	r0, err := w.next.GetByJTI(jti)
+	w.metrics.observe("blacklisted_token", "GetByJTI", start, err)
+	return r0, err
+}
+
+func (w *instrumentedBlacklistedTokenRepository) DeleteExpired() (int64, error) {
+	start := time.Now()
+	r0, err := w.next.DeleteExpired()
+	w.metrics.observe("blacklisted_token", "DeleteExpired", start, err)
+	return r0, err
+}
+
+// instrumentedNorthwindExternalAccountRepository records the duration and errors of every NorthwindExternalAccountRepositoryInterface call
+type instrumentedNorthwindExternalAccountRepository struct {
+	next    NorthwindExternalAccountRepositoryInterface
+	metrics *RepositoryMetrics
+}
+
+// InstrumentNorthwindExternalAccountRepository wraps repo so its calls are recorded in metrics. With nil metrics
+// it returns repo itself.
+func InstrumentNorthwindExternalAccountRepository(repo NorthwindExternalAccountRepositoryInterface, metrics *RepositoryMetrics) NorthwindExternalAccountRepositoryInterface {
+	if metrics == nil {
+		return repo
+	}
+	return &instrumentedNorthwindExternalAccountRepository{next: repo, metrics: metrics}
+}
+
+func (w *instrumentedNorthwindExternalAccountRepository) Create(ctx context.Context, account *models.NorthwindExternalAccount) error {
+	start := time.Now()
+	err := w.next.Create(ctx, account)
+	w.metrics.observe("northwind_external_account", "Create", start, err)
+	return err
+}
+
+func (w *instrumentedNorthwindExternalAccountRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.NorthwindExternalAccount, error) {
+	start := time.Now()
+	r0, err := w.next.GetByID(ctx, id)
+	w.metrics.observe("northwind_external_account", "GetByID", start, err)
+	return r0, err
+}
+
+func (w *instrumentedNorthwindExternalAccountRepository) GetByUserID(ctx context.Context, userID uuid.UUID, offset int, limit int) ([]models.NorthwindExternalAccount, int64, error) {
+	start := time.Now()
+	r0, r1, err := w.next.GetByUserID(ctx, userID, offset, limit)
+	w.metrics.observe("northwind_external_account", "GetByUserID", start, err)
+	return r0, r1, err
+}
+
+func (w *instrumentedNorthwindExternalAccountRepository) FindByAccountAndRouting(ctx context.Context, userID uuid.UUID, accountNumber string, routingNumber string) (*models.NorthwindExternalAccount, error) {
+	start := time.Now()
+	r0, err := w.next.FindByAccountAndRouting(ctx, userID, accountNumber, routingNumber)
+	w.metrics.observe("northwind_external_account", "FindByAccountAndRouting", start, err)
+	return r0, err
+}
+
+func (w *instrumentedNorthwindExternalAccountRepository) Update(ctx context.Context, account *models.NorthwindExternalAccount) error {
+	start := time.Now()
+	err := w.next.Update(ctx, account)
+	w.metrics.observe("northwind_external_account", "Update", start, err)
+	return err
+}
+
+// instrumentedNorthwindTransferRepository records the duration and errors of every NorthwindTransferRepositoryInterface call
+type instrumentedNorthwindTransferRepository struct {
+	next    NorthwindTransferRepositoryInterface
+	metrics *RepositoryMetrics
+}
+
+// InstrumentNorthwindTransferRepository wraps repo so its calls are recorded in metrics. With nil metrics
+// it returns repo itself.
+func InstrumentNorthwindTransferRepository(repo NorthwindTransferRepositoryInterface, metrics *RepositoryMetrics) NorthwindTransferRepositoryInterface {
+	if metrics == nil {
+		return repo
+	}
+	return &instrumentedNorthwindTransferRepository{next: repo, metrics: metrics}
+}
+
+func (w *instrumentedNorthwindTransferRepository) Create(ctx context.Context, transfer *models.NorthwindTransfer) error {
+	start := time.Now()
+	err := w.next.Create(ctx, transfer)
+	w.metrics.observe("northwind_transfer", "Create", start, err)
+	return err
+}
+
+func (w *instrumentedNorthwindTransferRepository) Update(ctx context.Context, transfer *models.NorthwindTransfer) error {
+	start := time.Now()
+	err := w.next.Update(ctx, transfer)
+	w.metrics.observe("northwind_transfer", "Update", start, err)
+	return err
+}
+
+func (w *instrumentedNorthwindTransferRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.NorthwindTransfer, error) {
+	start := time.Now()
+	r0, err := w.next.GetByID(ctx, id)
+	w.metrics.observe("northwind_transfer", "GetByID", start, err)
+	return r0, err
+}
+
+func (w *instrumentedNorthwindTransferRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]models.NorthwindTransfer, error) {
+	start := time.Now()
+	r0, err := w.next.GetByIDs(ctx, ids)
+	w.metrics.observe("northwind_transfer", "GetByIDs", start, err)
+	return r0, err
+}
+
+func (w *instrumentedNorthwindTransferRepository) GetByNorthwindTransferID(ctx context.Context, nwID uuid.UUID) (*models.NorthwindTransfer, error) {
+	start := time.Now()
+	r0, err := w.next.GetByNorthwindTransferID(ctx, nwID)
+	w.metrics.observe("northwind_transfer", "GetByNorthwindTransferID", start, err)
+	return r0, err
+}
+
+func (w *instrumentedNorthwindTransferRepository) GetByExternalRef(ctx context.Context, ref string) (*models.NorthwindTransfer, error) {
+	start := time.Now()
+	r0, err := w.next.GetByExternalRef(ctx, ref)
+	w.metrics.observe("northwind_transfer", "GetByExternalRef", start, err)
+	return r0, err
+}
+
+func (w *instrumentedNorthwindTransferRepository) GetByUserID(ctx context.Context, userID uuid.UUID, offset int, limit int) ([]models.NorthwindTransfer, int64, error) {
+	start := time.Now()
+	r0, r1, err := w.next.GetByUserID(ctx, userID, offset, limit)
+	w.metrics.observe("northwind_transfer", "GetByUserID", start, err)
+	return r0, r1, err
+}
+
+func (w *instrumentedNorthwindTransferRepository) GetByUserIDWithFilters(ctx context.Context, userID uuid.UUID, status string, direction string, transferType string, offset int, limit int) ([]models.NorthwindTransfer, int64, error) {
+	start := time.Now()
+	r0, r1, err := w.next.GetByUserIDWithFilters(ctx, userID, status, direction, transferType, offset, limit)
+	w.metrics.observe("northwind_transfer", "GetByUserIDWithFilters", start, err)
+	return r0, r1, err
+}
+
+func (w *instrumentedNorthwindTransferRepository) GetByUserIDKeyset(ctx context.Context, userID uuid.UUID, filters models.NorthwindTransferFilters, after *models.NorthwindTransferKeyset, limit int) ([]models.NorthwindTransfer, error) {
+	start := time.Now()
+	r0, err := w.next.GetByUserIDKeyset(ctx, userID, filters, after, limit)
+	w.metrics.observe("northwind_transfer", "GetByUserIDKeyset", start, err)
+	return r0, err
+}
+
+func (w *instrumentedNorthwindTransferRepository) GetBySourceAccountKeyset(ctx context.Context, userID uuid.UUID, sourceAccountNumber string, after *models.AccountActivityKeyset, limit int) ([]models.NorthwindTransfer, error) {
+	start := time.Now()
+	r0, err := w.next.GetBySourceAccountKeyset(ctx, userID, sourceAccountNumber, after, limit)
+	w.metrics.observe("northwind_transfer", "GetBySourceAccountKeyset", start, err)
+	return r0, err
+}
+
+func (w *instrumentedNorthwindTransferRepository) GetPendingTransfers(ctx context.Context, statuses []string, limit int, priority models.NorthwindPollPriority) ([]models.NorthwindTransfer, error) {
+	start := time.Now()
+	r0, err := w.next.GetPendingTransfers(ctx, statuses, limit, priority)
+	w.metrics.observe("northwind_transfer", "GetPendingTransfers", start, err)
+	return r0, err
+}
+
+func (w *instrumentedNorthwindTransferRepository) SetNextPollAt(ctx context.Context, id uuid.UUID, at *time.Time) error {
+	start := time.Now()
+	err := w.next.SetNextPollAt(ctx, id, at)
+	w.metrics.observe("northwind_transfer", "SetNextPollAt", start, err)
+	return err
+}
+
+func (w *instrumentedNorthwindTransferRepository) SetInternalTest(ctx context.Context, id uuid.UUID, internalTest bool) error {
+	start := time.Now()
+	err := w.next.SetInternalTest(ctx, id, internalTest)
+	w.metrics.observe("northwind_transfer", "SetInternalTest", start, err)
+	return err
+}
+
+func (w *instrumentedNorthwindTransferRepository) TouchLastViewedAt(ctx context.Context, id uuid.UUID, at time.Time, minInterval time.Duration) (bool, error) {
+	start := time.Now()
+	r0, err := w.next.TouchLastViewedAt(ctx, id, at, minInterval)
+	w.metrics.observe("northwind_transfer", "TouchLastViewedAt", start, err)
+	return r0, err
+}
+
+func (w *instrumentedNorthwindTransferRepository) ClaimForInitiation(ctx context.Context, id uuid.UUID, status string, at time.Time, staleAfter time.Duration) (bool, error) {
+	start := time.Now()
+	r0, err := w.next.ClaimForInitiation(ctx, id, status, at, staleAfter)
+	w.metrics.observe("northwind_transfer", "ClaimForInitiation", start, err)
+	return r0, err
+}
+
+func (w *instrumentedNorthwindTransferRepository) ReleaseInitiationClaim(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	err := w.next.ReleaseInitiationClaim(ctx, id)
+	w.metrics.observe("northwind_transfer", "ReleaseInitiationClaim", start, err)
+	return err
+}
+
+func (w *instrumentedNorthwindTransferRepository) ApplyTransition(ctx context.Context, id uuid.UUID, transition func(*models.NorthwindTransfer) *models.NorthwindTransferEvent) (*models.NorthwindTransfer, *models.NorthwindTransferEvent, error) {
+	start := time.Now()
+	r0, r1, err := w.next.ApplyTransition(ctx, id, transition)
+	w.metrics.observe("northwind_transfer", "ApplyTransition", start, err)
+	return r0, r1, err
+}
+
+func (w *instrumentedNorthwindTransferRepository) ListEvents(ctx context.Context, transferID uuid.UUID) ([]models.NorthwindTransferEvent, error) {
+	start := time.Now()
+	r0, err := w.next.ListEvents(ctx, transferID)
+	w.metrics.observe("northwind_transfer", "ListEvents", start, err)
+	return r0, err
+}
+
+func (w *instrumentedNorthwindTransferRepository) GetByUserIDAndStatus(ctx context.Context, userID uuid.UUID, status string) ([]models.NorthwindTransfer, error) {
+	start := time.Now()
+	r0, err := w.next.GetByUserIDAndStatus(ctx, userID, status)
+	w.metrics.observe("northwind_transfer", "GetByUserIDAndStatus", start, err)
+	return r0, err
+}
+
+func (w *instrumentedNorthwindTransferRepository) GetByStatus(ctx context.Context, status string, limit int) ([]models.NorthwindTransfer, error) {
+	start := time.Now()
+	r0, err := w.next.GetByStatus(ctx, status, limit)
+	w.metrics.observe("northwind_transfer", "GetByStatus", start, err)
+	return r0, err
+}
+
+func (w *instrumentedNorthwindTransferRepository) GetScheduledTransfersDue(ctx context.Context, before time.Time, limit int) ([]models.NorthwindTransfer, error) {
+	start := time.Now()
+	r0, err := w.next.GetScheduledTransfersDue(ctx, before, limit)
+	w.metrics.observe("northwind_transfer", "GetScheduledTransfersDue", start, err)
+	return r0, err
+}
+
+func (w *instrumentedNorthwindTransferRepository) GetByUserIDAndBatch(ctx context.Context, userID uuid.UUID, batchName string) ([]models.NorthwindTransfer, error) {
+	start := time.Now()
+	r0, err := w.next.GetByUserIDAndBatch(ctx, userID, batchName)
+	w.metrics.observe("northwind_transfer", "GetByUserIDAndBatch", start, err)
+	return r0, err
+}
+
+func (w *instrumentedNorthwindTransferRepository) ReferenceExists(ctx context.Context, userID uuid.UUID, referenceNumber string) (bool, error) {
+	start := time.Now()
+	r0, err := w.next.ReferenceExists(ctx, userID, referenceNumber)
+	w.metrics.observe("northwind_transfer", "ReferenceExists", start, err)
+	return r0, err
+}
+
+func (w *instrumentedNorthwindTransferRepository) GetUnlinkedByReference(ctx context.Context, referenceNumber string) ([]models.NorthwindTransfer, error) {
+	start := time.Now()
+	r0, err := w.next.GetUnlinkedByReference(ctx, referenceNumber)
+	w.metrics.observe("northwind_transfer", "GetUnlinkedByReference", start, err)
+	return r0, err
+}
+
+func (w *instrumentedNorthwindTransferRepository) CountByStatus(ctx context.Context, statuses ...string) (map[string]int64, error) {
+	start := time.Now()
+	r0, err := w.next.CountByStatus(ctx, statuses...)
+	w.metrics.observe("northwind_transfer", "CountByStatus", start, err)
+	return r0, err
+}
+
+func (w *instrumentedNorthwindTransferRepository) CountByStatusSince(ctx context.Context, since time.Time) (map[string]int64, error) {
+	start := time.Now()
+	r0, err := w.next.CountByStatusSince(ctx, since)
+	w.metrics.observe("northwind_transfer", "CountByStatusSince", start, err)
+	return r0, err
+}
+
+func (w *instrumentedNorthwindTransferRepository) GetInitiationOutcomes(ctx context.Context, since time.Time) (*models.NorthwindInitiationOutcomes, error) {
+	start := time.Now()
+	r0, err := w.next.GetInitiationOutcomes(ctx, since)
+	w.metrics.observe("northwind_transfer", "GetInitiationOutcomes", start, err)
+	return r0, err
+}
+
+func (w *instrumentedNorthwindTransferRepository) GetPollingBacklog(ctx context.Context) (*models.NorthwindPollingBacklog, error) {
+	start := time.Now()
+	r0, err := w.next.GetPollingBacklog(ctx)
+	w.metrics.observe("northwind_transfer", "GetPollingBacklog", start, err)
+	return r0, err
+}
+
+func (w *instrumentedNorthwindTransferRepository) FindRecentDuplicate(ctx context.Context, userID uuid.UUID, amount decimal.Decimal, currency string, direction string, destinationAccountNumber string, since time.Time) (*models.NorthwindTransfer, error) {
+	start := time.Now()
+	r0, err := w.next.FindRecentDuplicate(ctx, userID, amount, currency, direction, destinationAccountNumber, since)
+	w.metrics.observe("northwind_transfer", "FindRecentDuplicate", start, err)
+	return r0, err
+}
+
+func (w *instrumentedNorthwindTransferRepository) GetUserVelocity(ctx context.Context, userID uuid.UUID, currency string, since time.Time) (*models.NorthwindTransferVelocity, error) {
+	start := time.Now()
+	r0, err := w.next.GetUserVelocity(ctx, userID, currency, since)
+	w.metrics.observe("northwind_transfer", "GetUserVelocity", start, err)
+	return r0, err
+}
+
+func (w *instrumentedNorthwindTransferRepository) GetCompletionDurationStats(ctx context.Context, from time.Time, to time.Time) ([]models.TransferDurationStats, error) {
+	start := time.Now()
+	r0, err := w.next.GetCompletionDurationStats(ctx, from, to)
+	w.metrics.observe("northwind_transfer", "GetCompletionDurationStats", start, err)
+	return r0, err
+}
+
+func (w *instrumentedNorthwindTransferRepository) CountRetentionExpired(ctx context.Context, cutoff time.Time) (models.RetentionCount, error) {
+	start := time.Now()
+	r0, err := w.next.CountRetentionExpired(ctx, cutoff)
+	w.metrics.observe("northwind_transfer", "CountRetentionExpired", start, err)
+	return r0, err
+}
+
+func (w *instrumentedNorthwindTransferRepository) PurgeRetentionExpired(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	start := time.Now()
+	r0, err := w.next.PurgeRetentionExpired(ctx, cutoff, limit)
+	w.metrics.observe("northwind_transfer", "PurgeRetentionExpired", start, err)
+	return r0, err
+}
+
+// instrumentedRegulatorNotificationRepository records the duration and errors of every RegulatorNotificationRepositoryInterface call
+type instrumentedRegulatorNotificationRepository struct {
+	next    RegulatorNotificationRepositoryInterface
+	metrics *RepositoryMetrics
+}
+
+// InstrumentRegulatorNotificationRepository wraps repo so its calls are recorded in metrics. With nil metrics
+// it returns repo itself.
+func InstrumentRegulatorNotificationRepository(repo RegulatorNotificationRepositoryInterface, metrics *RepositoryMetrics) RegulatorNotificationRepositoryInterface {
+	if metrics == nil {
+		return repo
+	}
+	return &instrumentedRegulatorNotificationRepository{next: repo, metrics: metrics}
+}
+
+func (w *instrumentedRegulatorNotificationRepository) Create(ctx context.Context, notification *models.RegulatorNotification) error {
+	start := time.Now()
+	err := w.next.Create(ctx, notification)
+	w.metrics.observe("regulator_notification", "Create", start, err)
+	return err
+}
+
+func (w *instrumentedRegulatorNotificationRepository) Update(ctx context.Context, notification *models.RegulatorNotification) error {
+	start := time.Now()
+	err := w.next.Update(ctx, notification)
+	w.metrics.observe("regulator_notification", "Update", start, err)
+	return err
+}
+
+func (w *instrumentedRegulatorNotificationRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.RegulatorNotification, error) {
+	start := time.Now()
+	r0, err := w.next.GetByID(ctx, id)
+	w.metrics.observe("regulator_notification", "GetByID", start, err)
+	return r0, err
+}
+
+func (w *instrumentedRegulatorNotificationRepository) GetByEventID(ctx context.Context, eventID string) (*models.RegulatorNotification, error) {
+	start := time.Now()
+	r0, err := w.next.GetByEventID(ctx, eventID)
+	w.metrics.observe("regulator_notification", "GetByEventID", start, err)
+	return r0, err
+}
+
+func (w *instrumentedRegulatorNotificationRepository) GetByTransferAndStatus(ctx context.Context, transferID uuid.UUID, terminalStatus string) (*models.RegulatorNotification, error) {
+	start := time.Now()
+	r0, err := w.next.GetByTransferAndStatus(ctx, transferID, terminalStatus)
+	w.metrics.observe("regulator_notification", "GetByTransferAndStatus", start, err)
+	return r0, err
+}
+
+func (w *instrumentedRegulatorNotificationRepository) GetPendingNotifications(ctx context.Context, limit int) ([]models.RegulatorNotification, error) {
+	start := time.Now()
+	r0, err := w.next.GetPendingNotifications(ctx, limit)
+	w.metrics.observe("regulator_notification", "GetPendingNotifications", start, err)
+	return r0, err
+}
+
+func (w *instrumentedRegulatorNotificationRepository) ExistsForTransferAndStatus(ctx context.Context, transferID uuid.UUID, terminalStatus string) (bool, error) {
+	start := time.Now()
+	r0, err := w.next.ExistsForTransferAndStatus(ctx, transferID, terminalStatus)
+	w.metrics.observe("regulator_notification", "ExistsForTransferAndStatus", start, err)
+	return r0, err
+}
+
+func (w *instrumentedRegulatorNotificationRepository) ListByTransferIDs(ctx context.Context, transferIDs []uuid.UUID) ([]models.RegulatorNotification, error) {
+	start := time.Now()
+	r0, err := w.next.ListByTransferIDs(ctx, transferIDs)
+	w.metrics.observe("regulator_notification", "ListByTransferIDs", start, err)
+	return r0, err
+}
+
+func (w *instrumentedRegulatorNotificationRepository) GetDeliveryStats(ctx context.Context, abandonedBefore time.Time, slaFrom time.Time, sla time.Duration) (*models.RegulatorDeliveryStats, error) {
+	start := time.Now()
+	r0, err := w.next.GetDeliveryStats(ctx, abandonedBefore, slaFrom, sla)
+	w.metrics.observe("regulator_notification", "GetDeliveryStats", start, err)
+	return r0, err
+}
+
+func (w *instrumentedRegulatorNotificationRepository) ReleaseStaleLeases(ctx context.Context, leasedBefore time.Time, now time.Time) (int64, error) {
+	start := time.Now()
+	r0, err := w.next.ReleaseStaleLeases(ctx, leasedBefore, now)
+	w.metrics.observe("regulator_notification", "ReleaseStaleLeases", start, err)
+	return r0, err
+}
+
+// instrumentedRegulatorNotificationAttemptRepository records the duration and errors of every RegulatorNotificationAttemptRepositoryInterface call
+type instrumentedRegulatorNotificationAttemptRepository struct {
+	next    RegulatorNotificationAttemptRepositoryInterface
+	metrics *RepositoryMetrics
+}
+
+// InstrumentRegulatorNotificationAttemptRepository wraps repo so its calls are recorded in metrics. With nil metrics
+// it returns repo itself.
+func InstrumentRegulatorNotificationAttemptRepository(repo RegulatorNotificationAttemptRepositoryInterface, metrics *RepositoryMetrics) RegulatorNotificationAttemptRepositoryInterface {
+	if metrics == nil {
+		return repo
+	}
+	return &instrumentedRegulatorNotificationAttemptRepository{next: repo, metrics: metrics}
+}
+
+func (w *instrumentedRegulatorNotificationAttemptRepository) Create(ctx context.Context, attempt *models.RegulatorNotificationAttempt) error {
+	start := time.Now()
+	err := w.next.Create(ctx, attempt)
+	w.metrics.observe("regulator_notification_attempt", "Create", start, err)
+	return err
+}
+
+func (w *instrumentedRegulatorNotificationAttemptRepository) GetByNotificationID(ctx context.Context, notificationID uuid.UUID) ([]models.RegulatorNotificationAttempt, error) {
+	start := time.Now()
+	r0, err := w.next.GetByNotificationID(ctx, notificationID)
+	w.metrics.observe("regulator_notification_attempt", "GetByNotificationID", start, err)
+	return r0, err
+}
+
+func (w *instrumentedRegulatorNotificationAttemptRepository) ListByNotificationIDs(ctx context.Context, notificationIDs []uuid.UUID) ([]models.RegulatorNotificationAttempt, error) {
+	start := time.Now()
+	r0, err := w.next.ListByNotificationIDs(ctx, notificationIDs)
+	w.metrics.observe("regulator_notification_attempt", "ListByNotificationIDs", start, err)
+	return r0, err
+}
+
+func (w *instrumentedRegulatorNotificationAttemptRepository) ListFiltered(ctx context.Context, filters models.RegulatorNotificationAttemptFilters, offset int, limit int) ([]models.RegulatorNotificationAttempt, int64, error) {
+	start := time.Now()
+	r0, r1, err := w.next.ListFiltered(ctx, filters, offset, limit)
+	w.metrics.observe("regulator_notification_attempt", "ListFiltered", start, err)
+	return r0, r1, err
+}
+
+func (w *instrumentedRegulatorNotificationAttemptRepository) StreamFiltered(ctx context.Context, filters models.RegulatorNotificationAttemptFilters, fn func(*models.RegulatorNotificationAttempt) error) error {
+	start := time.Now()
+	err := w.next.StreamFiltered(ctx, filters, fn)
+	w.metrics.observe("regulator_notification_attempt", "StreamFiltered", start, err)
+	return err
+}
+
+// instrumentedFeatureFlagOverrideRepository records the duration and errors of every FeatureFlagOverrideRepositoryInterface call
+type instrumentedFeatureFlagOverrideRepository struct {
+	next    FeatureFlagOverrideRepositoryInterface
+	metrics *RepositoryMetrics
+}
+
+// InstrumentFeatureFlagOverrideRepository wraps repo so its calls are recorded in metrics. With nil metrics
+// it returns repo itself.
+func InstrumentFeatureFlagOverrideRepository(repo FeatureFlagOverrideRepositoryInterface, metrics *RepositoryMetrics) FeatureFlagOverrideRepositoryInterface {
+	if metrics == nil {
+		return repo
+	}
+	return &instrumentedFeatureFlagOverrideRepository{next: repo, metrics: metrics}
+}
+
+func (w *instrumentedFeatureFlagOverrideRepository) Upsert(ctx context.Context, override *models.FeatureFlagOverride) error {
+	start := time.Now()
+	err := w.next.Upsert(ctx, override)
+	w.metrics.observe("feature_flag_override", "Upsert", start, err)
+	return err
+}
+
+func (w *instrumentedFeatureFlagOverrideRepository) Delete(ctx context.Context, flag string, userID *uuid.UUID) error {
+	start := time.Now()
+	err := w.next.Delete(ctx, flag, userID)
+	w.metrics.observe("feature_flag_override", "Delete", start, err)
+	return err
+}
+
+func (w *instrumentedFeatureFlagOverrideRepository) GetApplicable(ctx context.Context, flag string, userID uuid.UUID) ([]models.FeatureFlagOverride, error) {
+	start := time.Now()
+	r0, err := w.next.GetApplicable(ctx, flag, userID)
+	w.metrics.observe("feature_flag_override", "GetApplicable", start, err)
+	return r0, err
+}
+
+func (w *instrumentedFeatureFlagOverrideRepository) List(ctx context.Context) ([]models.FeatureFlagOverride, error) {
+	start := time.Now()
+	r0, err := w.next.List(ctx)
+	w.metrics.observe("feature_flag_override", "List", start, err)
+	return r0, err
+}
+
+// instrumentedRiskEvaluationRepository records the duration and errors of every RiskEvaluationRepositoryInterface call
+type instrumentedRiskEvaluationRepository struct {
+	next    RiskEvaluationRepositoryInterface
+	metrics *RepositoryMetrics
+}
+
+// InstrumentRiskEvaluationRepository wraps repo so its calls are recorded in metrics. With nil metrics
+// it returns repo itself.
+func InstrumentRiskEvaluationRepository(repo RiskEvaluationRepositoryInterface, metrics *RepositoryMetrics) RiskEvaluationRepositoryInterface {
+	if metrics == nil {
+		return repo
+	}
+	return &instrumentedRiskEvaluationRepository{next: repo, metrics: metrics}
+}
+
+func (w *instrumentedRiskEvaluationRepository) CreateBatch(ctx context.Context, evaluations []models.RiskEvaluation) error {
+	start := time.Now()
+	err := w.next.CreateBatch(ctx, evaluations)
+	w.metrics.observe("risk_evaluation", "CreateBatch", start, err)
+	return err
+}
+
+func (w *instrumentedRiskEvaluationRepository) List(ctx context.Context, filters models.RiskEvaluationFilters, offset int, limit int) ([]models.RiskEvaluation, int64, error) {
+	start := time.Now()
+	r0, r1, err := w.next.List(ctx, filters, offset, limit)
+	w.metrics.observe("risk_evaluation", "List", start, err)
+	return r0, r1, err
+}
+
+func (w *instrumentedRiskEvaluationRepository) SummarizeByRule(ctx context.Context, filters models.RiskEvaluationFilters) ([]models.RiskRuleSummary, error) {
+	start := time.Now()
+	r0, err := w.next.SummarizeByRule(ctx, filters)
+	w.metrics.observe("risk_evaluation", "SummarizeByRule", start, err)
+	return r0, err
+}
+
+// instrumentedRiskRuleOverrideRepository records the duration and errors of every RiskRuleOverrideRepositoryInterface call
+type instrumentedRiskRuleOverrideRepository struct {
+	next    RiskRuleOverrideRepositoryInterface
+	metrics *RepositoryMetrics
+}
+
+// InstrumentRiskRuleOverrideRepository wraps repo so its calls are recorded in metrics. With nil metrics
+// it returns repo itself.
+func InstrumentRiskRuleOverrideRepository(repo RiskRuleOverrideRepositoryInterface, metrics *RepositoryMetrics) RiskRuleOverrideRepositoryInterface {
+	if metrics == nil {
+		return repo
+	}
+	return &instrumentedRiskRuleOverrideRepository{next: repo, metrics: metrics}
+}
+
+func (w *instrumentedRiskRuleOverrideRepository) Upsert(ctx context.Context, override *models.RiskRuleOverride) error {
+	start := time.Now()
+	err := w.next.Upsert(ctx, override)
+	w.metrics.observe("risk_rule_override", "Upsert", start, err)
+	return err
+}
+
+func (w *instrumentedRiskRuleOverrideRepository) Delete(ctx context.Context, rule string) error {
+	start := time.Now()
+	err := w.next.Delete(ctx, rule)
+	w.metrics.observe("risk_rule_override", "Delete", start, err)
+	return err
+}
+
+func (w *instrumentedRiskRuleOverrideRepository) List(ctx context.Context) ([]models.RiskRuleOverride, error) {
+	start := time.Now()
+	r0, err := w.next.List(ctx)
+	w.metrics.observe("risk_rule_override", "List", start, err)
+	return r0, err
+}
+
+// instrumentedNotificationPreferenceRepository records the duration and errors of every NotificationPreferenceRepositoryInterface call
+type instrumentedNotificationPreferenceRepository struct {
+	next    NotificationPreferenceRepositoryInterface
+	metrics *RepositoryMetrics
+}
+
+// InstrumentNotificationPreferenceRepository wraps repo so its calls are recorded in metrics. With nil metrics
+// it returns repo itself.
+func InstrumentNotificationPreferenceRepository(repo NotificationPreferenceRepositoryInterface, metrics *RepositoryMetrics) NotificationPreferenceRepositoryInterface {
+	if metrics == nil {
+		return repo
+	}
+	return &instrumentedNotificationPreferenceRepository{next: repo, metrics: metrics}
+}
+
+func (w *instrumentedNotificationPreferenceRepository) Upsert(ctx context.Context, preference *models.NotificationPreference) error {
+	start := time.Now()
+	err := w.next.Upsert(ctx, preference)
+	w.metrics.observe("notification_preference", "Upsert", start, err)
+	return err
+}
+
+func (w *instrumentedNotificationPreferenceRepository) Get(ctx context.Context, userID uuid.UUID, eventType string) (*models.NotificationPreference, error) {
+	start := time.Now()
+	r0, err := w.next.Get(ctx, userID, eventType)
+	w.metrics.observe("notification_preference", "Get", start, err)
+	return r0, err
+}
+
+func (w *instrumentedNotificationPreferenceRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.NotificationPreference, error) {
+	start := time.Now()
+	r0, err := w.next.ListByUser(ctx, userID)
+	w.metrics.observe("notification_preference", "ListByUser", start, err)
+	return r0, err
+}
+
+// instrumentedUserNotificationRepository records the duration and errors of every UserNotificationRepositoryInterface call
+type instrumentedUserNotificationRepository struct {
+	next    UserNotificationRepositoryInterface
+	metrics *RepositoryMetrics
+}
+
+// InstrumentUserNotificationRepository wraps repo so its calls are recorded in metrics. With nil metrics
+// it returns repo itself.
+func InstrumentUserNotificationRepository(repo UserNotificationRepositoryInterface, metrics *RepositoryMetrics) UserNotificationRepositoryInterface {
+	if metrics == nil {
+		return repo
+	}
+	return &instrumentedUserNotificationRepository{next: repo, metrics: metrics}
+}
+
+func (w *instrumentedUserNotificationRepository) Create(ctx context.Context, notification *models.UserNotification) error {
+	start := time.Now()
+	err := w.next.Create(ctx, notification)
+	w.metrics.observe("user_notification", "Create", start, err)
+	return err
+}
+
+func (w *instrumentedUserNotificationRepository) ListByUser(ctx context.Context, userID uuid.UUID, channel string) ([]models.UserNotification, error) {
+	start := time.Now()
+	r0, err := w.next.ListByUser(ctx, userID, channel)
+	w.metrics.observe("user_notification", "ListByUser", start, err)
+	return r0, err
+}
+
+// instrumentedCanaryRunRepository records the duration and errors of every CanaryRunRepositoryInterface call
+type instrumentedCanaryRunRepository struct {
+	next    CanaryRunRepositoryInterface
+	metrics *RepositoryMetrics
+}
+
+// InstrumentCanaryRunRepository wraps repo so its calls are recorded in metrics. With nil metrics
+// it returns repo itself.
+func InstrumentCanaryRunRepository(repo CanaryRunRepositoryInterface, metrics *RepositoryMetrics) CanaryRunRepositoryInterface {
+	if metrics == nil {
+		return repo
+	}
+	return &instrumentedCanaryRunRepository{next: repo, metrics: metrics}
+}
+
+func (w *instrumentedCanaryRunRepository) Create(ctx context.Context, run *models.CanaryRun) error {
+	start := time.Now()
+	err := w.next.Create(ctx, run)
+	w.metrics.observe("canary_run", "Create", start, err)
+	return err
+}
+
+func (w *instrumentedCanaryRunRepository) Update(ctx context.Context, run *models.CanaryRun) error {
+	start := time.Now()
+	err := w.next.Update(ctx, run)
+	w.metrics.observe("canary_run", "Update", start, err)
+	return err
+}
+
+func (w *instrumentedCanaryRunRepository) ListFinished(ctx context.Context, limit int) ([]models.CanaryRun, error) {
+	start := time.Now()
+	r0, err := w.next.ListFinished(ctx, limit)
+	w.metrics.observe("canary_run", "ListFinished", start, err)
+	return r0, err
+}
+
+// instrumentedPollAnomalyRepository records the duration and errors of every PollAnomalyRepositoryInterface call
+type instrumentedPollAnomalyRepository struct {
+	next    PollAnomalyRepositoryInterface
+	metrics *RepositoryMetrics
+}
+
+// InstrumentPollAnomalyRepository wraps repo so its calls are recorded in metrics. With nil metrics
+// it returns repo itself.
+func InstrumentPollAnomalyRepository(repo PollAnomalyRepositoryInterface, metrics *RepositoryMetrics) PollAnomalyRepositoryInterface {
+	if metrics == nil {
+		return repo
+	}
+	return &instrumentedPollAnomalyRepository{next: repo, metrics: metrics}
+}
+
+func (w *instrumentedPollAnomalyRepository) Record(ctx context.Context, anomaly *models.PollAnomaly) error {
+	start := time.Now()
+	err := w.next.Record(ctx, anomaly)
+	w.metrics.observe("poll_anomaly", "Record", start, err)
+	return err
+}
+
+func (w *instrumentedPollAnomalyRepository) CountUnresolved(ctx context.Context) (int64, error) {
+	start := time.Now()
+	r0, err := w.next.CountUnresolved(ctx)
+	w.metrics.observe("poll_anomaly", "CountUnresolved", start, err)
+	return r0, err
+}
+
+func (w *instrumentedPollAnomalyRepository) ListUnresolved(ctx context.Context, limit int) ([]models.PollAnomaly, error) {
+	start := time.Now()
+	r0, err := w.next.ListUnresolved(ctx, limit)
+	w.metrics.observe("poll_anomaly", "ListUnresolved", start, err)
+	return r0, err
+}
+
+func (w *instrumentedPollAnomalyRepository) List(ctx context.Context, resolved bool, offset int, limit int) ([]models.PollAnomaly, int64, error) {
+	start := time.Now()
+	r0, r1, err := w.next.List(ctx, resolved, offset, limit)
+	w.metrics.observe("poll_anomaly", "List", start, err)
+	return r0, r1, err
+}
+
+func (w *instrumentedPollAnomalyRepository) Resolve(ctx context.Context, id uuid.UUID, resolution string) error {
+	start := time.Now()
+	err := w.next.Resolve(ctx, id, resolution)
+	w.metrics.observe("poll_anomaly", "Resolve", start, err)
+	return err
+}
+
+// instrumentedTransferDisputeRepository records the duration and errors of every TransferDisputeRepositoryInterface call
+type instrumentedTransferDisputeRepository struct {
+	next    TransferDisputeRepositoryInterface
+	metrics *RepositoryMetrics
+}
+
+// InstrumentTransferDisputeRepository wraps repo so its calls are recorded in metrics. With nil metrics
+// it returns repo itself.
+func InstrumentTransferDisputeRepository(repo TransferDisputeRepositoryInterface, metrics *RepositoryMetrics) TransferDisputeRepositoryInterface {
+	if metrics == nil {
+		return repo
+	}
+	return &instrumentedTransferDisputeRepository{next: repo, metrics: metrics}
+}
+
+func (w *instrumentedTransferDisputeRepository) Create(ctx context.Context, dispute *models.TransferDispute) error {
+	start := time.Now()
+	err := w.next.Create(ctx, dispute)
+	w.metrics.observe("transfer_dispute", "Create", start, err)
+	return err
+}
+
+func (w *instrumentedTransferDisputeRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.TransferDispute, error) {
+	start := time.Now()
+	r0, err := w.next.GetByID(ctx, id)
+	w.metrics.observe("transfer_dispute", "GetByID", start, err)
+	return r0, err
+}
+
+func (w *instrumentedTransferDisputeRepository) GetUnresolvedByTransfer(ctx context.Context, transferID uuid.UUID) (*models.TransferDispute, error) {
+	start := time.Now()
+	r0, err := w.next.GetUnresolvedByTransfer(ctx, transferID)
+	w.metrics.observe("transfer_dispute", "GetUnresolvedByTransfer", start, err)
+	return r0, err
+}
+
+func (w *instrumentedTransferDisputeRepository) ListByTransfer(ctx context.Context, transferID uuid.UUID) ([]models.TransferDispute, error) {
+	start := time.Now()
+	r0, err := w.next.ListByTransfer(ctx, transferID)
+	w.metrics.observe("transfer_dispute", "ListByTransfer", start, err)
+	return r0, err
+}
+
+func (w *instrumentedTransferDisputeRepository) List(ctx context.Context, status string, offset int, limit int) ([]models.TransferDispute, int64, error) {
+	start := time.Now()
+	r0, r1, err := w.next.List(ctx, status, offset, limit)
+	w.metrics.observe("transfer_dispute", "List", start, err)
+	return r0, r1, err
+}
+
+func (w *instrumentedTransferDisputeRepository) Transition(ctx context.Context, dispute *models.TransferDispute, fromStatuses []string) error {
+	start := time.Now()
+	err := w.next.Transition(ctx, dispute, fromStatuses)
+	w.metrics.observe("transfer_dispute", "Transition", start, err)
+	return err
+}
+
+// instrumentedBalanceAlertRuleRepository records the duration and errors of every BalanceAlertRuleRepositoryInterface call
+type instrumentedBalanceAlertRuleRepository struct {
+	next    BalanceAlertRuleRepositoryInterface
+	metrics *RepositoryMetrics
+}
+
+// InstrumentBalanceAlertRuleRepository wraps repo so its calls are recorded in metrics. With nil metrics
+// it returns repo itself.
+func InstrumentBalanceAlertRuleRepository(repo BalanceAlertRuleRepositoryInterface, metrics *RepositoryMetrics) BalanceAlertRuleRepositoryInterface {
+	if metrics == nil {
+		return repo
+	}
+	return &instrumentedBalanceAlertRuleRepository{next: repo, metrics: metrics}
+}
+
+func (w *instrumentedBalanceAlertRuleRepository) Create(ctx context.Context, rule *models.BalanceAlertRule) error {
+	start := time.Now()
+	err := w.next.Create(ctx, rule)
+	w.metrics.observe("balance_alert_rule", "Create", start, err)
+	return err
+}
+
+func (w *instrumentedBalanceAlertRuleRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.BalanceAlertRule, error) {
+	start := time.Now()
+	r0, err := w.next.GetByID(ctx, id)
+	w.metrics.observe("balance_alert_rule", "GetByID", start, err)
+	return r0, err
+}
+
+func (w *instrumentedBalanceAlertRuleRepository) ListByUser(ctx context.Context, userID uuid.UUID, offset int, limit int) ([]models.BalanceAlertRule, int64, error) {
+	start := time.Now()
+	r0, r1, err := w.next.ListByUser(ctx, userID, offset, limit)
+	w.metrics.observe("balance_alert_rule", "ListByUser", start, err)
+	return r0, r1, err
+}
+
+func (w *instrumentedBalanceAlertRuleRepository) ListForEvaluation(ctx context.Context, frequentOnly bool) ([]models.BalanceAlertRule, error) {
+	start := time.Now()
+	r0, err := w.next.ListForEvaluation(ctx, frequentOnly)
+	w.metrics.observe("balance_alert_rule", "ListForEvaluation", start, err)
+	return r0, err
+}
+
+func (w *instrumentedBalanceAlertRuleRepository) Update(ctx context.Context, rule *models.BalanceAlertRule) error {
+	start := time.Now()
+	err := w.next.Update(ctx, rule)
+	w.metrics.observe("balance_alert_rule", "Update", start, err)
+	return err
+}
+
+func (w *instrumentedBalanceAlertRuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	err := w.next.Delete(ctx, id)
+	w.metrics.observe("balance_alert_rule", "Delete", start, err)
+	return err
+}
+
+// instrumentedPersonalAccessTokenRepository records the duration and errors of every PersonalAccessTokenRepositoryInterface call
+type instrumentedPersonalAccessTokenRepository struct {
+	next    PersonalAccessTokenRepositoryInterface
+	metrics *RepositoryMetrics
+}
+
+// InstrumentPersonalAccessTokenRepository wraps repo so its calls are recorded in metrics. With nil metrics
+// it returns repo itself.
+func InstrumentPersonalAccessTokenRepository(repo PersonalAccessTokenRepositoryInterface, metrics *RepositoryMetrics) PersonalAccessTokenRepositoryInterface {
+	if metrics == nil {
+		return repo
+	}
+	return &instrumentedPersonalAccessTokenRepository{next: repo, metrics: metrics}
+}
+
+func (w *instrumentedPersonalAccessTokenRepository) Create(ctx context.Context, token *models.PersonalAccessToken) error {
+	start := time.Now()
+	err := w.next.Create(ctx, token)
+	w.metrics.observe("personal_access_token", "Create", start, err)
+	return err
+}
+
+func (w *instrumentedPersonalAccessTokenRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.PersonalAccessToken, error) {
+	start := time.Now()
+	r0, err := w.next.GetByID(ctx, id)
+	w.metrics.observe("personal_access_token", "GetByID", start, err)
+	return r0, err
+}
+
+func (w *instrumentedPersonalAccessTokenRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.PersonalAccessToken, error) {
+	start := time.Now()
+	r0, err := w.next.GetByTokenHash(ctx, tokenHash)
+	w.metrics.observe("personal_access_token", "GetByTokenHash", start, err)
+	return r0, err
+}
+
+func (w *instrumentedPersonalAccessTokenRepository) ListByUser(ctx context.Context, userID uuid.UUID, offset int, limit int) ([]models.PersonalAccessToken, int64, error) {
+	start := time.Now()
+	r0, r1, err := w.next.ListByUser(ctx, userID, offset, limit)
+	w.metrics.observe("personal_access_token", "ListByUser", start, err)
+	return r0, r1, err
+}
+
+func (w *instrumentedPersonalAccessTokenRepository) Revoke(ctx context.Context, id uuid.UUID, revokedAt time.Time) error {
+	start := time.Now()
+	err := w.next.Revoke(ctx, id, revokedAt)
+	w.metrics.observe("personal_access_token", "Revoke", start, err)
+	return err
+}
+
+func (w *instrumentedPersonalAccessTokenRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	start := time.Now()
+	err := w.next.TouchLastUsed(ctx, id, usedAt)
+	w.metrics.observe("personal_access_token", "TouchLastUsed", start, err)
+	return err
+}
+
+// instrumentedProcessedWebhookEventRepository records the duration and errors of every ProcessedWebhookEventRepositoryInterface call
+type instrumentedProcessedWebhookEventRepository struct {
+	next    ProcessedWebhookEventRepositoryInterface
+	metrics *RepositoryMetrics
+}
+
+// InstrumentProcessedWebhookEventRepository wraps repo so its calls are recorded in metrics. With nil metrics
+// it returns repo itself.
+func InstrumentProcessedWebhookEventRepository(repo ProcessedWebhookEventRepositoryInterface, metrics *RepositoryMetrics) ProcessedWebhookEventRepositoryInterface {
+	if metrics == nil {
+		return repo
+	}
+	return &instrumentedProcessedWebhookEventRepository{next: repo, metrics: metrics}
+}
+
+func (w *instrumentedProcessedWebhookEventRepository) Record(ctx context.Context, event *models.ProcessedWebhookEvent) error {
+	start := time.Now()
+	err := w.next.Record(ctx, event)
+	w.metrics.observe("processed_webhook_event", "Record", start, err)
+	return err
+}
+
+func (w *instrumentedProcessedWebhookEventRepository) SetOutcome(ctx context.Context, eventID string, outcome string) error {
+	start := time.Now()
+	err := w.next.SetOutcome(ctx, eventID, outcome)
+	w.metrics.observe("processed_webhook_event", "SetOutcome", start, err)
+	return err
+}
+
+func (w *instrumentedProcessedWebhookEventRepository) Delete(ctx context.Context, eventID string) error {
+	start := time.Now()
+	err := w.next.Delete(ctx, eventID)
+	w.metrics.observe("processed_webhook_event", "Delete", start, err)
+	return err
+}
+
+func (w *instrumentedProcessedWebhookEventRepository) PruneBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	start := time.Now()
+	r0, err := w.next.PruneBefore(ctx, cutoff)
+	w.metrics.observe("processed_webhook_event", "PruneBefore", start, err)
+	return r0, err
+}
+
+// instrumentedReadOnlyModeRepository records the duration and errors of every ReadOnlyModeRepositoryInterface call
+type instrumentedReadOnlyModeRepository struct {
+	next    ReadOnlyModeRepositoryInterface
+	metrics *RepositoryMetrics
+}
+
+// InstrumentReadOnlyModeRepository wraps repo so its calls are recorded in metrics. With nil metrics
+// it returns repo itself.
+func InstrumentReadOnlyModeRepository(repo ReadOnlyModeRepositoryInterface, metrics *RepositoryMetrics) ReadOnlyModeRepositoryInterface {
+	if metrics == nil {
+		return repo
+	}
+	return &instrumentedReadOnlyModeRepository{next: repo, metrics: metrics}
+}
+
+func (w *instrumentedReadOnlyModeRepository) Get(ctx context.Context) (*models.ReadOnlyMode, error) {
+	start := time.Now()
+	r0, err := w.next.Get(ctx)
+	w.metrics.observe("read_only_mode", "Get", start, err)
+	return r0, err
+}
+
+func (w *instrumentedReadOnlyModeRepository) Save(ctx context.Context, mode *models.ReadOnlyMode) error {
+	start := time.Now()
+	err := w.next.Save(ctx, mode)
+	w.metrics.observe("read_only_mode", "Save", start, err)
+	return err
+}
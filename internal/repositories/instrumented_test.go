@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/array/banking-api/internal/database"
+	"github.com/array/banking-api/internal/models"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/shopspring/decimal"
+)
+
+func newInstrumentedTransferRepo(t *testing.T) (NorthwindTransferRepositoryInterface, *RepositoryMetrics) {
+	t.Helper()
+	db := database.SetupTestDB(t)
+	t.Cleanup(func() { database.CleanupTestDB(t, db) })
+	if err := db.DB.AutoMigrate(&models.NorthwindTransfer{}, &models.NorthwindTransferEvent{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	metrics := NewRepositoryMetrics(prometheus.NewRegistry())
+	return InstrumentNorthwindTransferRepository(NewNorthwindTransferRepository(db.DB), metrics), metrics
+}
+
+func callCount(t *testing.T, metrics *RepositoryMetrics, repository, method string) uint64 {
+	t.Helper()
+	var metric dto.Metric
+	if err := metrics.duration.WithLabelValues(repository, method).(prometheus.Histogram).Write(&metric); err != nil {
+		t.Fatalf("failed to read histogram: %v", err)
+	}
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func TestInstrumentedRepository_RecordsCalls(t *testing.T) {
+	repo, metrics := newInstrumentedTransferRepo(t)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	transfer := &models.NorthwindTransfer{
+		UserID:                   &userID,
+		NorthwindTransferID:      uuid.New(),
+		Direction:                models.NWTransferDirectionOutbound,
+		TransferType:             "ACH",
+		Amount:                   decimal.NewFromFloat(100),
+		Currency:                 "USD",
+		ReferenceNumber:          "REF-1",
+		SourceAccountNumber:      "1111111111",
+		DestinationAccountNumber: "2222222222",
+	}
+	if err := repo.Create(ctx, transfer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := repo.GetByID(ctx, transfer.ID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	counts, err := repo.CountByStatus(ctx, models.NWTransferStatusPending)
+	if err != nil || counts[models.NWTransferStatusPending] != 1 {
+		t.Fatalf("expected the variadic statuses passed through, got %v, %v", counts, err)
+	}
+
+	for method, want := range map[string]uint64{"Create": 1, "GetByID": 2, "CountByStatus": 1} {
+		if got := callCount(t, metrics, "northwind_transfer", method); got != want {
+			t.Errorf("%s: expected %d recorded calls, got %d", method, want, got)
+		}
+	}
+	if got := testutil.CollectAndCount(metrics.errors); got != 0 {
+		t.Errorf("expected no errors recorded, got %d series", got)
+	}
+}
+
+func TestInstrumentedRepository_PassesErrorsThrough(t *testing.T) {
+	repo, metrics := newInstrumentedTransferRepo(t)
+
+	transfer, err := repo.GetByID(context.Background(), uuid.New())
+	if transfer != nil || !errors.Is(err, ErrNorthwindTransferNotFound) {
+		t.Fatalf("expected ErrNorthwindTransferNotFound unchanged, got %v, %v", transfer, err)
+	}
+	if err != ErrNorthwindTransferNotFound {
+		t.Errorf("expected the very same error value, got %#v", err)
+	}
+	if got := testutil.ToFloat64(metrics.errors.WithLabelValues("northwind_transfer", "GetByID")); got != 1 {
+		t.Errorf("expected 1 recorded error, got %v", got)
+	}
+	if got := callCount(t, metrics, "northwind_transfer", "GetByID"); got != 1 {
+		t.Errorf("expected the failed call timed, got %d calls", got)
+	}
+}
+
+func TestInstrumentedRepository_DisabledReturnsRepository(t *testing.T) {
+	repo := NewNorthwindTransferRepository(nil)
+	if got := InstrumentNorthwindTransferRepository(repo, nil); got != repo {
+		t.Errorf("expected the repository itself without metrics, got %T", got)
+	}
+}
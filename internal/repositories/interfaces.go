@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"time"
 
 	"github.com/array/banking-api/internal/models"
@@ -34,23 +35,26 @@ type AccountRepositoryInterface interface {
 
 // TransactionRepositoryInterface defines the contract for transaction repository operations
 type TransactionRepositoryInterface interface {
-	Create(transaction *models.Transaction) error
-	GetByID(id uuid.UUID) (*models.Transaction, error)
-	GetByAccountID(accountID uuid.UUID, offset, limit int) ([]models.Transaction, int64, error)
-	GetByReference(reference string) (*models.Transaction, error)
-	GetRecentByAccountID(accountID uuid.UUID, limit int) ([]models.Transaction, error)
-	GetByDateRange(accountID uuid.UUID, startDate, endDate time.Time) ([]models.Transaction, error)
-	CreateBatch(transactions []models.Transaction) error
-	GetPendingTransactions(offset, limit int) ([]models.Transaction, error)
-	UpdateStatus(id uuid.UUID, status string) error
-	GetTotalsByAccountID(accountID uuid.UUID) (credits, debits int64, creditAmount, debitAmount string, err error)
+	Create(ctx context.Context, transaction *models.Transaction) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Transaction, error)
+	GetByAccountID(ctx context.Context, accountID uuid.UUID, offset, limit int) ([]models.Transaction, int64, error)
+	GetByReference(ctx context.Context, reference string) (*models.Transaction, error)
+	GetRecentByAccountID(ctx context.Context, accountID uuid.UUID, limit int) ([]models.Transaction, error)
+	GetByAccountIDKeyset(ctx context.Context, accountID uuid.UUID, after *models.AccountActivityKeyset, limit int) ([]models.Transaction, error)
+	GetByDateRange(ctx context.Context, accountID uuid.UUID, startDate, endDate time.Time) ([]models.Transaction, error)
+	CreateBatch(ctx context.Context, transactions []models.Transaction) error
+	GetPendingTransactions(ctx context.Context, offset, limit int) ([]models.Transaction, error)
+	UpdateStatus(ctx context.Context, id uuid.UUID, status string) error
+	GetTotalsByAccountID(ctx context.Context, accountID uuid.UUID) (credits, debits int64, creditAmount, debitAmount string, err error)
 
 	// Enhanced methods for category and filtering
-	GetByCategory(accountID uuid.UUID, category string, offset, limit int) ([]models.Transaction, int64, error)
-	GetWithFilters(filters models.TransactionFilters) ([]models.Transaction, int64, error)
-	UpdateWithOptimisticLock(transaction *models.Transaction, expectedVersion int) error
-	GetExpiredPendingTransactions(limit int) ([]models.Transaction, error)
-	GetCategorySummary(accountID uuid.UUID, startDate, endDate time.Time) ([]models.CategorySummary, error)
+	GetByCategory(ctx context.Context, accountID uuid.UUID, category string, offset, limit int) ([]models.Transaction, int64, error)
+	GetWithFilters(ctx context.Context, filters models.TransactionFilters) ([]models.Transaction, int64, error)
+	UpdateWithOptimisticLock(ctx context.Context, transaction *models.Transaction, expectedVersion int) error
+	GetExpiredPendingTransactions(ctx context.Context, limit int) ([]models.Transaction, error)
+	GetCategorySummary(ctx context.Context, accountID uuid.UUID, startDate, endDate time.Time) ([]models.CategorySummary, error)
+	CountRetentionExpired(ctx context.Context, cutoff time.Time) (models.RetentionCount, error)
+	PurgeRetentionExpired(ctx context.Context, cutoff time.Time, limit int) (int64, error)
 }
 
 // UserSearchCriteria defines search criteria for users
@@ -61,22 +65,22 @@ type UserSearchCriteria struct {
 
 // UserRepositoryInterface defines the contract for user repository operations
 type UserRepositoryInterface interface {
-	Create(user *models.User) error
-	GetByID(id uuid.UUID) (*models.User, error)
-	GetByIDActive(id uuid.UUID) (*models.User, error)
-	GetByEmail(email string) (*models.User, error)
-	GetByEmailExcluding(email string, excludeUserID uuid.UUID) (*models.User, error)
-	SearchUsers(criteria UserSearchCriteria, offset, limit int) ([]*models.User, int64, error)
-	Update(user *models.User) error
-	UpdateFields(userID uuid.UUID, fields map[string]interface{}) error
-	UpdateEmail(userID uuid.UUID, newEmail string) error
-	UpdatePasswordHash(userID uuid.UUID, passwordHash string) error
-	UpdateFailedLoginAttempts(user *models.User) error
-	ResetFailedLoginAttempts(userID uuid.UUID) error
-	UnlockAccount(userID uuid.UUID) error
-	Delete(userID uuid.UUID) error
-	ListUsers(offset, limit int) ([]*models.User, int64, error)
-	CountAccountsByUserID(userID uuid.UUID) (int64, error)
+	Create(ctx context.Context, user *models.User) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	GetByIDActive(ctx context.Context, id uuid.UUID) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	GetByEmailExcluding(ctx context.Context, email string, excludeUserID uuid.UUID) (*models.User, error)
+	SearchUsers(ctx context.Context, criteria UserSearchCriteria, offset, limit int) ([]*models.User, int64, error)
+	Update(ctx context.Context, user *models.User) error
+	UpdateFields(ctx context.Context, userID uuid.UUID, fields map[string]interface{}) error
+	UpdateEmail(ctx context.Context, userID uuid.UUID, newEmail string) error
+	UpdatePasswordHash(ctx context.Context, userID uuid.UUID, passwordHash string) error
+	UpdateFailedLoginAttempts(ctx context.Context, user *models.User) error
+	ResetFailedLoginAttempts(ctx context.Context, userID uuid.UUID) error
+	UnlockAccount(ctx context.Context, userID uuid.UUID) error
+	Delete(ctx context.Context, userID uuid.UUID) error
+	ListUsers(ctx context.Context, offset, limit int) ([]*models.User, int64, error)
+	CountAccountsByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
 }
 
 // AuditLogRepositoryInterface defines the contract for audit log repository operations
@@ -91,6 +95,8 @@ type AuditLogRepositoryInterface interface {
 	GetCustomerActivity(userID uuid.UUID, startDate, endDate *time.Time, offset, limit int) ([]*models.AuditLog, int64, error)
 	GetFailedLoginAttempts(email string, since time.Time) (int64, error)
 	DeleteOlderThan(duration time.Duration) (int64, error)
+	CountRetentionExpired(ctx context.Context, cutoff time.Time) (models.RetentionCount, error)
+	PurgeRetentionExpired(ctx context.Context, cutoff time.Time, limit int) (int64, error)
 }
 
 // ProcessingQueueRepositoryInterface defines the contract for transaction processing queue operations
@@ -101,6 +107,7 @@ type ProcessingQueueRepositoryInterface interface {
 	MarkCompleted(queueItemID uuid.UUID) error
 	MarkFailed(queueItemID uuid.UUID, errorMessage string) error
 	IncrementRetry(queueItemID uuid.UUID) error
+	ResetStaleProcessing(staleBefore time.Time) (int64, error)
 	GetPendingCount() (int64, error)
 	GetProcessingCount() (int64, error)
 	GetFailedCount() (int64, error)
@@ -119,6 +126,8 @@ type TransferRepositoryInterface interface {
 	FindByUserAccounts(accountIDs []uuid.UUID, offset, limit int) ([]models.Transfer, int64, error)
 	FindByUserAccountsWithFilters(accountIDs []uuid.UUID, filters models.TransferFilters, offset, limit int) ([]models.Transfer, int64, error)
 	CountByUserAccounts(accountIDs []uuid.UUID) (int64, error)
+	CountRetentionExpired(ctx context.Context, cutoff time.Time) (models.RetentionCount, error)
+	PurgeRetentionExpired(ctx context.Context, cutoff time.Time, limit int) (int64, error)
 }
 
 type RefreshTokenRepositoryInterface interface {
@@ -142,35 +151,166 @@ type BlacklistedTokenRepositoryInterface interface {
 
 // NorthwindExternalAccountRepositoryInterface defines the contract for NorthWind external account operations
 type NorthwindExternalAccountRepositoryInterface interface {
-	Create(account *models.NorthwindExternalAccount) error
-	GetByID(id uuid.UUID) (*models.NorthwindExternalAccount, error)
-	GetByUserID(userID uuid.UUID, offset, limit int) ([]models.NorthwindExternalAccount, int64, error)
-	FindByAccountAndRouting(userID uuid.UUID, accountNumber, routingNumber string) (*models.NorthwindExternalAccount, error)
-	Update(account *models.NorthwindExternalAccount) error
+	Create(ctx context.Context, account *models.NorthwindExternalAccount) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.NorthwindExternalAccount, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]models.NorthwindExternalAccount, int64, error)
+	FindByAccountAndRouting(ctx context.Context, userID uuid.UUID, accountNumber, routingNumber string) (*models.NorthwindExternalAccount, error)
+	Update(ctx context.Context, account *models.NorthwindExternalAccount) error
 }
 
 // NorthwindTransferRepositoryInterface defines the contract for NorthWind transfer operations
 type NorthwindTransferRepositoryInterface interface {
-	Create(transfer *models.NorthwindTransfer) error
-	Update(transfer *models.NorthwindTransfer) error
-	GetByID(id uuid.UUID) (*models.NorthwindTransfer, error)
-	GetByNorthwindTransferID(nwID uuid.UUID) (*models.NorthwindTransfer, error)
-	GetByUserID(userID uuid.UUID, offset, limit int) ([]models.NorthwindTransfer, int64, error)
-	GetByUserIDWithFilters(userID uuid.UUID, status, direction, transferType string, offset, limit int) ([]models.NorthwindTransfer, int64, error)
-	GetPendingTransfers(limit int) ([]models.NorthwindTransfer, error)
+	Create(ctx context.Context, transfer *models.NorthwindTransfer) error
+	Update(ctx context.Context, transfer *models.NorthwindTransfer) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.NorthwindTransfer, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]models.NorthwindTransfer, error)
+	GetByNorthwindTransferID(ctx context.Context, nwID uuid.UUID) (*models.NorthwindTransfer, error)
+	GetByExternalRef(ctx context.Context, ref string) (*models.NorthwindTransfer, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]models.NorthwindTransfer, int64, error)
+	GetByUserIDWithFilters(ctx context.Context, userID uuid.UUID, status, direction, transferType string, offset, limit int) ([]models.NorthwindTransfer, int64, error)
+	GetByUserIDKeyset(ctx context.Context, userID uuid.UUID, filters models.NorthwindTransferFilters, after *models.NorthwindTransferKeyset, limit int) ([]models.NorthwindTransfer, error)
+	GetBySourceAccountKeyset(ctx context.Context, userID uuid.UUID, sourceAccountNumber string, after *models.AccountActivityKeyset, limit int) ([]models.NorthwindTransfer, error)
+	GetPendingTransfers(ctx context.Context, statuses []string, limit int, priority models.NorthwindPollPriority) ([]models.NorthwindTransfer, error)
+	SetNextPollAt(ctx context.Context, id uuid.UUID, at *time.Time) error
+	SetInternalTest(ctx context.Context, id uuid.UUID, internalTest bool) error
+	TouchLastViewedAt(ctx context.Context, id uuid.UUID, at time.Time, minInterval time.Duration) (bool, error)
+	ClaimForInitiation(ctx context.Context, id uuid.UUID, status string, at time.Time, staleAfter time.Duration) (bool, error)
+	ReleaseInitiationClaim(ctx context.Context, id uuid.UUID) error
+	ApplyTransition(ctx context.Context, id uuid.UUID, transition func(*models.NorthwindTransfer) *models.NorthwindTransferEvent) (*models.NorthwindTransfer, *models.NorthwindTransferEvent, error)
+	ListEvents(ctx context.Context, transferID uuid.UUID) ([]models.NorthwindTransferEvent, error)
+	GetByUserIDAndStatus(ctx context.Context, userID uuid.UUID, status string) ([]models.NorthwindTransfer, error)
+	GetByStatus(ctx context.Context, status string, limit int) ([]models.NorthwindTransfer, error)
+	GetScheduledTransfersDue(ctx context.Context, before time.Time, limit int) ([]models.NorthwindTransfer, error)
+	GetByUserIDAndBatch(ctx context.Context, userID uuid.UUID, batchName string) ([]models.NorthwindTransfer, error)
+	ReferenceExists(ctx context.Context, userID uuid.UUID, referenceNumber string) (bool, error)
+	GetUnlinkedByReference(ctx context.Context, referenceNumber string) ([]models.NorthwindTransfer, error)
+	CountByStatus(ctx context.Context, statuses ...string) (map[string]int64, error)
+	CountByStatusSince(ctx context.Context, since time.Time) (map[string]int64, error)
+	GetInitiationOutcomes(ctx context.Context, since time.Time) (*models.NorthwindInitiationOutcomes, error)
+	GetPollingBacklog(ctx context.Context) (*models.NorthwindPollingBacklog, error)
+	FindRecentDuplicate(ctx context.Context, userID uuid.UUID, amount decimal.Decimal, currency, direction, destinationAccountNumber string, since time.Time) (*models.NorthwindTransfer, error)
+	GetUserVelocity(ctx context.Context, userID uuid.UUID, currency string, since time.Time) (*models.NorthwindTransferVelocity, error)
+	GetCompletionDurationStats(ctx context.Context, from, to time.Time) ([]models.TransferDurationStats, error)
+	CountRetentionExpired(ctx context.Context, cutoff time.Time) (models.RetentionCount, error)
+	PurgeRetentionExpired(ctx context.Context, cutoff time.Time, limit int) (int64, error)
 }
 
 // RegulatorNotificationRepositoryInterface defines the contract for regulator notification operations
 type RegulatorNotificationRepositoryInterface interface {
-	Create(notification *models.RegulatorNotification) error
-	Update(notification *models.RegulatorNotification) error
-	GetByID(id uuid.UUID) (*models.RegulatorNotification, error)
-	GetPendingNotifications(limit int) ([]models.RegulatorNotification, error)
-	ExistsForTransferAndStatus(transferID uuid.UUID, terminalStatus string) (bool, error)
+	Create(ctx context.Context, notification *models.RegulatorNotification) error
+	Update(ctx context.Context, notification *models.RegulatorNotification) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.RegulatorNotification, error)
+	GetByEventID(ctx context.Context, eventID string) (*models.RegulatorNotification, error)
+	GetByTransferAndStatus(ctx context.Context, transferID uuid.UUID, terminalStatus string) (*models.RegulatorNotification, error)
+	GetPendingNotifications(ctx context.Context, limit int) ([]models.RegulatorNotification, error)
+	ExistsForTransferAndStatus(ctx context.Context, transferID uuid.UUID, terminalStatus string) (bool, error)
+	ListByTransferIDs(ctx context.Context, transferIDs []uuid.UUID) ([]models.RegulatorNotification, error)
+	GetDeliveryStats(ctx context.Context, abandonedBefore, slaFrom time.Time, sla time.Duration) (*models.RegulatorDeliveryStats, error)
+	ReleaseStaleLeases(ctx context.Context, leasedBefore, now time.Time) (int64, error)
 }
 
 // RegulatorNotificationAttemptRepositoryInterface defines the contract for notification attempt audit records
 type RegulatorNotificationAttemptRepositoryInterface interface {
-	Create(attempt *models.RegulatorNotificationAttempt) error
-	GetByNotificationID(notificationID uuid.UUID) ([]models.RegulatorNotificationAttempt, error)
+	Create(ctx context.Context, attempt *models.RegulatorNotificationAttempt) error
+	GetByNotificationID(ctx context.Context, notificationID uuid.UUID) ([]models.RegulatorNotificationAttempt, error)
+	ListByNotificationIDs(ctx context.Context, notificationIDs []uuid.UUID) ([]models.RegulatorNotificationAttempt, error)
+	ListFiltered(ctx context.Context, filters models.RegulatorNotificationAttemptFilters, offset, limit int) ([]models.RegulatorNotificationAttempt, int64, error)
+	StreamFiltered(ctx context.Context, filters models.RegulatorNotificationAttemptFilters, fn func(*models.RegulatorNotificationAttempt) error) error
+}
+
+// FeatureFlagOverrideRepositoryInterface defines the contract for runtime feature flag overrides.
+// A nil userID addresses the flag-wide rollout override.
+type FeatureFlagOverrideRepositoryInterface interface {
+	Upsert(ctx context.Context, override *models.FeatureFlagOverride) error
+	Delete(ctx context.Context, flag string, userID *uuid.UUID) error
+	GetApplicable(ctx context.Context, flag string, userID uuid.UUID) ([]models.FeatureFlagOverride, error)
+	List(ctx context.Context) ([]models.FeatureFlagOverride, error)
+}
+
+// RiskEvaluationRepositoryInterface defines the contract for recorded risk rule verdicts
+type RiskEvaluationRepositoryInterface interface {
+	CreateBatch(ctx context.Context, evaluations []models.RiskEvaluation) error
+	List(ctx context.Context, filters models.RiskEvaluationFilters, offset, limit int) ([]models.RiskEvaluation, int64, error)
+	SummarizeByRule(ctx context.Context, filters models.RiskEvaluationFilters) ([]models.RiskRuleSummary, error)
+}
+
+// RiskRuleOverrideRepositoryInterface defines the contract for runtime risk rule modes, one per rule
+type RiskRuleOverrideRepositoryInterface interface {
+	Upsert(ctx context.Context, override *models.RiskRuleOverride) error
+	Delete(ctx context.Context, rule string) error
+	List(ctx context.Context) ([]models.RiskRuleOverride, error)
+}
+
+// NotificationPreferenceRepositoryInterface defines the contract for users' notification channel
+// overrides, one per user and event type
+type NotificationPreferenceRepositoryInterface interface {
+	Upsert(ctx context.Context, preference *models.NotificationPreference) error
+	Get(ctx context.Context, userID uuid.UUID, eventType string) (*models.NotificationPreference, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]models.NotificationPreference, error)
+}
+
+// UserNotificationRepositoryInterface defines the contract for in-app notifications and queued emails
+type UserNotificationRepositoryInterface interface {
+	Create(ctx context.Context, notification *models.UserNotification) error
+	ListByUser(ctx context.Context, userID uuid.UUID, channel string) ([]models.UserNotification, error)
+}
+
+// CanaryRunRepositoryInterface defines the contract for synthetic canary transfer results
+type CanaryRunRepositoryInterface interface {
+	Create(ctx context.Context, run *models.CanaryRun) error
+	Update(ctx context.Context, run *models.CanaryRun) error
+	ListFinished(ctx context.Context, limit int) ([]models.CanaryRun, error)
+}
+
+// PollAnomalyRepositoryInterface defines the contract for quarantined NorthWind poll responses
+type PollAnomalyRepositoryInterface interface {
+	Record(ctx context.Context, anomaly *models.PollAnomaly) error
+	CountUnresolved(ctx context.Context) (int64, error)
+	ListUnresolved(ctx context.Context, limit int) ([]models.PollAnomaly, error)
+	List(ctx context.Context, resolved bool, offset, limit int) ([]models.PollAnomaly, int64, error)
+	Resolve(ctx context.Context, id uuid.UUID, resolution string) error
+}
+
+// TransferDisputeRepositoryInterface defines the contract for customer disputes of transfers
+type TransferDisputeRepositoryInterface interface {
+	Create(ctx context.Context, dispute *models.TransferDispute) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.TransferDispute, error)
+	GetUnresolvedByTransfer(ctx context.Context, transferID uuid.UUID) (*models.TransferDispute, error)
+	ListByTransfer(ctx context.Context, transferID uuid.UUID) ([]models.TransferDispute, error)
+	List(ctx context.Context, status string, offset, limit int) ([]models.TransferDispute, int64, error)
+	Transition(ctx context.Context, dispute *models.TransferDispute, fromStatuses []string) error
+}
+
+// BalanceAlertRuleRepositoryInterface defines the contract for balance threshold alert rules
+type BalanceAlertRuleRepositoryInterface interface {
+	Create(ctx context.Context, rule *models.BalanceAlertRule) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.BalanceAlertRule, error)
+	ListByUser(ctx context.Context, userID uuid.UUID, offset, limit int) ([]models.BalanceAlertRule, int64, error)
+	ListForEvaluation(ctx context.Context, frequentOnly bool) ([]models.BalanceAlertRule, error)
+	Update(ctx context.Context, rule *models.BalanceAlertRule) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// PersonalAccessTokenRepositoryInterface defines the contract for users' API tokens
+type PersonalAccessTokenRepositoryInterface interface {
+	Create(ctx context.Context, token *models.PersonalAccessToken) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.PersonalAccessToken, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*models.PersonalAccessToken, error)
+	ListByUser(ctx context.Context, userID uuid.UUID, offset, limit int) ([]models.PersonalAccessToken, int64, error)
+	Revoke(ctx context.Context, id uuid.UUID, revokedAt time.Time) error
+	TouchLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+}
+
+// ProcessedWebhookEventRepositoryInterface defines the contract for the webhook replay guard
+type ProcessedWebhookEventRepositoryInterface interface {
+	Record(ctx context.Context, event *models.ProcessedWebhookEvent) error
+	SetOutcome(ctx context.Context, eventID, outcome string) error
+	Delete(ctx context.Context, eventID string) error
+	PruneBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// ReadOnlyModeRepositoryInterface defines the contract for the persisted read-only switch
+type ReadOnlyModeRepositoryInterface interface {
+	Get(ctx context.Context) (*models.ReadOnlyMode, error)
+	Save(ctx context.Context, mode *models.ReadOnlyMode) error
 }
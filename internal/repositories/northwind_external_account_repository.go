@@ -1,9 +1,11 @@
 package repositories
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
+	"github.com/array/banking-api/internal/fieldcrypt"
 	"github.com/array/banking-api/internal/models"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -22,11 +24,11 @@ func NewNorthwindExternalAccountRepository(db *gorm.DB) NorthwindExternalAccount
 	return &northwindExternalAccountRepository{db: db}
 }
 
-func (r *northwindExternalAccountRepository) Create(account *models.NorthwindExternalAccount) error {
+func (r *northwindExternalAccountRepository) Create(ctx context.Context, account *models.NorthwindExternalAccount) error {
 	if account == nil {
 		return errors.New("account cannot be nil")
 	}
-	if err := r.db.Create(account).Error; err != nil {
+	if err := r.db.WithContext(ctx).Create(account).Error; err != nil {
 		if isDuplicateKeyError(err) {
 			return fmt.Errorf("external account already registered: %w", err)
 		}
@@ -35,9 +37,9 @@ func (r *northwindExternalAccountRepository) Create(account *models.NorthwindExt
 	return nil
 }
 
-func (r *northwindExternalAccountRepository) GetByID(id uuid.UUID) (*models.NorthwindExternalAccount, error) {
+func (r *northwindExternalAccountRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.NorthwindExternalAccount, error) {
 	var account models.NorthwindExternalAccount
-	if err := r.db.Where("id = ?", id).First(&account).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&account).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrNorthwindExternalAccountNotFound
 		}
@@ -46,11 +48,11 @@ func (r *northwindExternalAccountRepository) GetByID(id uuid.UUID) (*models.Nort
 	return &account, nil
 }
 
-func (r *northwindExternalAccountRepository) GetByUserID(userID uuid.UUID, offset, limit int) ([]models.NorthwindExternalAccount, int64, error) {
+func (r *northwindExternalAccountRepository) GetByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]models.NorthwindExternalAccount, int64, error) {
 	var accounts []models.NorthwindExternalAccount
 	var total int64
 
-	query := r.db.Model(&models.NorthwindExternalAccount{}).Where("user_id = ?", userID)
+	query := r.db.WithContext(ctx).Model(&models.NorthwindExternalAccount{}).Where("user_id = ?", userID)
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to count northwind external accounts: %w", err)
@@ -63,9 +65,19 @@ func (r *northwindExternalAccountRepository) GetByUserID(userID uuid.UUID, offse
 	return accounts, total, nil
 }
 
-func (r *northwindExternalAccountRepository) FindByAccountAndRouting(userID uuid.UUID, accountNumber, routingNumber string) (*models.NorthwindExternalAccount, error) {
+// FindByAccountAndRouting matches the account number through its blind index. Rows not yet
+// backfilled (no blind index, plaintext account number) are still matched directly.
+func (r *northwindExternalAccountRepository) FindByAccountAndRouting(ctx context.Context, userID uuid.UUID, accountNumber, routingNumber string) (*models.NorthwindExternalAccount, error) {
+	bidx, err := fieldcrypt.BlindIndex(accountNumber)
+	if err != nil {
+		return nil, err
+	}
+
 	var account models.NorthwindExternalAccount
-	if err := r.db.Where("user_id = ? AND account_number = ? AND routing_number = ?", userID, accountNumber, routingNumber).First(&account).Error; err != nil {
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND routing_number = ?", userID, routingNumber).
+		Where("account_number_bidx = ? OR (account_number_bidx IS NULL AND account_number = ?)", bidx, accountNumber).
+		First(&account).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrNorthwindExternalAccountNotFound
 		}
@@ -74,11 +86,11 @@ func (r *northwindExternalAccountRepository) FindByAccountAndRouting(userID uuid
 	return &account, nil
 }
 
-func (r *northwindExternalAccountRepository) Update(account *models.NorthwindExternalAccount) error {
+func (r *northwindExternalAccountRepository) Update(ctx context.Context, account *models.NorthwindExternalAccount) error {
 	if account == nil {
 		return errors.New("account cannot be nil")
 	}
-	if err := r.db.Save(account).Error; err != nil {
+	if err := r.db.WithContext(ctx).Save(account).Error; err != nil {
 		return fmt.Errorf("failed to update northwind external account: %w", err)
 	}
 	return nil
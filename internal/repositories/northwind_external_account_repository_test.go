@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/array/banking-api/internal/database"
+	"github.com/array/banking-api/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+// NorthwindExternalAccountRepositorySuite defines the test suite for NorthwindExternalAccountRepository
+type NorthwindExternalAccountRepositorySuite struct {
+	suite.Suite
+	db   *database.DB
+	repo NorthwindExternalAccountRepositoryInterface
+}
+
+// SetupTest runs before each test in the suite
+func (s *NorthwindExternalAccountRepositorySuite) SetupTest() {
+	s.db = database.SetupTestDB(s.T())
+	s.Require().NoError(s.db.DB.AutoMigrate(&models.NorthwindExternalAccount{}))
+	s.repo = NewNorthwindExternalAccountRepository(s.db.DB)
+}
+
+// TearDownTest runs after each test in the suite
+func (s *NorthwindExternalAccountRepositorySuite) TearDownTest() {
+	database.CleanupTestDB(s.T(), s.db)
+}
+
+// TestNorthwindExternalAccountRepositorySuite runs the test suite
+func TestNorthwindExternalAccountRepositorySuite(t *testing.T) {
+	suite.Run(t, new(NorthwindExternalAccountRepositorySuite))
+}
+
+func (s *NorthwindExternalAccountRepositorySuite) newAccount(userID uuid.UUID, accountNumber string) *models.NorthwindExternalAccount {
+	return &models.NorthwindExternalAccount{
+		UserID:            &userID,
+		AccountHolderName: "Test Holder",
+		AccountNumber:     accountNumber,
+		RoutingNumber:     "021000021",
+	}
+}
+
+func (s *NorthwindExternalAccountRepositorySuite) rawAccountNumber(id uuid.UUID) string {
+	var stored string
+	s.Require().NoError(s.db.DB.Table("northwind_external_accounts").Select("account_number").Where("id = ?", id).Row().Scan(&stored))
+	return stored
+}
+
+func (s *NorthwindExternalAccountRepositorySuite) TestCreate_EncryptsAccountNumber() {
+	account := s.newAccount(uuid.New(), "1234567890")
+	s.Require().NoError(s.repo.Create(context.Background(), account))
+
+	stored := s.rawAccountNumber(account.ID)
+	s.True(strings.HasPrefix(stored, "enc:test:"))
+	s.NotContains(stored, "1234567890")
+
+	found, err := s.repo.GetByID(context.Background(), account.ID)
+	s.Require().NoError(err)
+	s.Equal("1234567890", found.AccountNumber)
+}
+
+func (s *NorthwindExternalAccountRepositorySuite) TestFindByAccountAndRouting_UsesBlindIndex() {
+	userID := uuid.New()
+	account := s.newAccount(userID, "1234567890")
+	s.Require().NoError(s.repo.Create(context.Background(), account))
+
+	found, err := s.repo.FindByAccountAndRouting(context.Background(), userID, "1234567890", "021000021")
+	s.Require().NoError(err)
+	s.Equal(account.ID, found.ID)
+	s.Equal("1234567890", found.AccountNumber)
+
+	_, err = s.repo.FindByAccountAndRouting(context.Background(), userID, "0000000000", "021000021")
+	s.ErrorIs(err, ErrNorthwindExternalAccountNotFound)
+	_, err = s.repo.FindByAccountAndRouting(context.Background(), uuid.New(), "1234567890", "021000021")
+	s.ErrorIs(err, ErrNorthwindExternalAccountNotFound)
+}
+
+func (s *NorthwindExternalAccountRepositorySuite) TestFindByAccountAndRouting_LegacyPlaintextRow() {
+	userID := uuid.New()
+	account := s.newAccount(userID, "1234567890")
+	s.Require().NoError(s.repo.Create(context.Background(), account))
+	s.Require().NoError(s.db.DB.Table("northwind_external_accounts").Where("id = ?", account.ID).
+		Updates(map[string]interface{}{"account_number": "1234567890", "account_number_bidx": nil}).Error)
+
+	found, err := s.repo.FindByAccountAndRouting(context.Background(), userID, "1234567890", "021000021")
+	s.Require().NoError(err)
+	s.Equal(account.ID, found.ID)
+}
+
+func (s *NorthwindExternalAccountRepositorySuite) TestCreate_DuplicateRejectedByBlindIndex() {
+	userID := uuid.New()
+	s.Require().NoError(s.repo.Create(context.Background(), s.newAccount(userID, "1234567890")))
+
+	s.Error(s.repo.Create(context.Background(), s.newAccount(userID, "1234567890")))
+	s.NoError(s.repo.Create(context.Background(), s.newAccount(uuid.New(), "1234567890")), "other users may register the same account")
+}
@@ -1,16 +1,28 @@
 package repositories
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/array/banking-api/internal/fieldcrypt"
 	"github.com/array/banking-api/internal/models"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var (
-	ErrNorthwindTransferNotFound = errors.New("northwind transfer not found")
+	ErrNorthwindTransferNotFound           = errors.New("northwind transfer not found")
+	ErrNorthwindTransferDuplicateReference = errors.New("reference number already used by this user")
+	// ErrNorthwindTransferUpdatedAtBackwards rejects an update whose clock is behind the transfer's
+	// last update, as with clock skew between pods, so updated_at never moves backwards
+	ErrNorthwindTransferUpdatedAtBackwards = errors.New("update would move the transfer's updated_at backwards")
 )
 
 type northwindTransferRepository struct {
@@ -22,29 +34,70 @@ func NewNorthwindTransferRepository(db *gorm.DB) NorthwindTransferRepositoryInte
 	return &northwindTransferRepository{db: db}
 }
 
-func (r *northwindTransferRepository) Create(transfer *models.NorthwindTransfer) error {
+func (r *northwindTransferRepository) Create(ctx context.Context, transfer *models.NorthwindTransfer) error {
 	if transfer == nil {
 		return errors.New("transfer cannot be nil")
 	}
-	if err := r.db.Create(transfer).Error; err != nil {
+	if err := r.db.WithContext(ctx).Create(transfer).Error; err != nil {
+		if isDuplicateKeyError(err) && strings.Contains(err.Error(), "reference") {
+			return ErrNorthwindTransferDuplicateReference
+		}
 		return fmt.Errorf("failed to create northwind transfer: %w", err)
 	}
 	return nil
 }
 
-func (r *northwindTransferRepository) Update(transfer *models.NorthwindTransfer) error {
+// Update saves the transfer over its row. When that changes anything, an UPDATE event recording
+// the changed columns is added to the transfer's status history in the same database transaction.
+func (r *northwindTransferRepository) Update(ctx context.Context, transfer *models.NorthwindTransfer) error {
 	if transfer == nil {
 		return errors.New("transfer cannot be nil")
 	}
-	if err := r.db.Save(transfer).Error; err != nil {
-		return fmt.Errorf("failed to update northwind transfer: %w", err)
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var stored models.NorthwindTransfer
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", transfer.ID).First(&stored).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNorthwindTransferNotFound
+			}
+			return fmt.Errorf("failed to lock northwind transfer: %w", err)
+		}
+		changes, err := saveTransfer(tx, &stored, transfer)
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		event := &models.NorthwindTransferEvent{
+			TransferID: transfer.ID,
+			FromStatus: stored.Status,
+			ToStatus:   transfer.Status,
+			Source:     models.NWTransferEventSourceUpdate,
+			Changes:    changes,
+		}
+		if err := tx.Create(event).Error; err != nil {
+			return fmt.Errorf("failed to record northwind transfer event: %w", err)
+		}
+		return nil
+	})
+}
+
+// saveTransfer saves transfer over stored, its locked row, and returns the columns that changed.
+// The update is rejected when the session's clock is behind stored's UpdatedAt.
+func saveTransfer(tx *gorm.DB, stored, transfer *models.NorthwindTransfer) (models.NorthwindTransferChanges, error) {
+	if now := tx.NowFunc(); now.Before(stored.UpdatedAt) {
+		return nil, fmt.Errorf("%w: last updated at %s, now %s", ErrNorthwindTransferUpdatedAtBackwards,
+			stored.UpdatedAt.UTC().Format(time.RFC3339Nano), now.UTC().Format(time.RFC3339Nano))
+	}
+	if err := tx.Save(transfer).Error; err != nil {
+		return nil, fmt.Errorf("failed to update northwind transfer: %w", err)
 	}
-	return nil
+	return models.DiffNorthwindTransfers(stored, transfer)
 }
 
-func (r *northwindTransferRepository) GetByID(id uuid.UUID) (*models.NorthwindTransfer, error) {
+func (r *northwindTransferRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.NorthwindTransfer, error) {
 	var transfer models.NorthwindTransfer
-	if err := r.db.Where("id = ?", id).First(&transfer).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&transfer).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrNorthwindTransferNotFound
 		}
@@ -53,36 +106,58 @@ func (r *northwindTransferRepository) GetByID(id uuid.UUID) (*models.NorthwindTr
 	return &transfer, nil
 }
 
-func (r *northwindTransferRepository) GetByNorthwindTransferID(nwID uuid.UUID) (*models.NorthwindTransfer, error) {
+// transferIDsChunkSize bounds how many IDs GetByIDs puts in one IN list
+const transferIDsChunkSize = 500
+
+// GetByIDs loads the transfers with the given IDs in one query per transferIDsChunkSize IDs,
+// for listings that show a transfer per row. IDs with no transfer are absent from the map.
+func (r *northwindTransferRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]models.NorthwindTransfer, error) {
+	transfers := make(map[uuid.UUID]models.NorthwindTransfer, len(ids))
+	for start := 0; start < len(ids); start += transferIDsChunkSize {
+		end := min(start+transferIDsChunkSize, len(ids))
+		var chunk []models.NorthwindTransfer
+		if err := r.db.WithContext(ctx).Where("id IN ?", ids[start:end]).Find(&chunk).Error; err != nil {
+			return nil, fmt.Errorf("failed to get northwind transfers: %w", err)
+		}
+		for _, transfer := range chunk {
+			transfers[transfer.ID] = transfer
+		}
+	}
+	return transfers, nil
+}
+
+// GetByNorthwindTransferID finds a transfer by a UUID-shaped NorthWind transfer ID. It is kept for
+// callers that hold a UUID and delegates to GetByExternalRef.
+func (r *northwindTransferRepository) GetByNorthwindTransferID(ctx context.Context, nwID uuid.UUID) (*models.NorthwindTransfer, error) {
+	return r.GetByExternalRef(ctx, nwID.String())
+}
+
+// GetByExternalRef finds a transfer by NorthWind's transfer ID as NorthWind returned it. The match
+// is exact and case-sensitive.
+func (r *northwindTransferRepository) GetByExternalRef(ctx context.Context, ref string) (*models.NorthwindTransfer, error) {
 	var transfer models.NorthwindTransfer
-	if err := r.db.Where("northwind_transfer_id = ?", nwID).First(&transfer).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("external_ref = ?", ref).First(&transfer).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrNorthwindTransferNotFound
 		}
-		return nil, fmt.Errorf("failed to get northwind transfer by nw id: %w", err)
+		return nil, fmt.Errorf("failed to get northwind transfer by external ref: %w", err)
 	}
 	return &transfer, nil
 }
 
-func (r *northwindTransferRepository) GetByUserID(userID uuid.UUID, offset, limit int) ([]models.NorthwindTransfer, int64, error) {
-	return r.GetByUserIDWithFilters(userID, "", "", "", offset, limit)
+func (r *northwindTransferRepository) GetByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]models.NorthwindTransfer, int64, error) {
+	return r.GetByUserIDWithFilters(ctx, userID, "", "", "", offset, limit)
 }
 
-func (r *northwindTransferRepository) GetByUserIDWithFilters(userID uuid.UUID, status, direction, transferType string, offset, limit int) ([]models.NorthwindTransfer, int64, error) {
+func (r *northwindTransferRepository) GetByUserIDWithFilters(ctx context.Context, userID uuid.UUID, status, direction, transferType string, offset, limit int) ([]models.NorthwindTransfer, int64, error) {
 	var transfers []models.NorthwindTransfer
 	var total int64
 
-	query := r.db.Model(&models.NorthwindTransfer{}).Where("user_id = ?", userID)
-
-	if status != "" {
-		query = query.Where("status = ?", status)
-	}
-	if direction != "" {
-		query = query.Where("direction = ?", direction)
-	}
-	if transferType != "" {
-		query = query.Where("transfer_type = ?", transferType)
-	}
+	query := r.userTransfersQuery(ctx, userID, models.NorthwindTransferFilters{
+		Status:       status,
+		Direction:    direction,
+		TransferType: transferType,
+	})
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to count northwind transfers: %w", err)
@@ -95,13 +170,502 @@ func (r *northwindTransferRepository) GetByUserIDWithFilters(userID uuid.UUID, s
 	return transfers, total, nil
 }
 
-func (r *northwindTransferRepository) GetPendingTransfers(limit int) ([]models.NorthwindTransfer, error) {
+// GetByUserIDKeyset returns up to limit of the user's transfers, newest first, that come after
+// the given position, or from the newest when after is nil. Ties on created_at are broken by id
+// so rows inserted while a client pages through are never repeated or skipped.
+func (r *northwindTransferRepository) GetByUserIDKeyset(ctx context.Context, userID uuid.UUID, filters models.NorthwindTransferFilters, after *models.NorthwindTransferKeyset, limit int) ([]models.NorthwindTransfer, error) {
+	var transfers []models.NorthwindTransfer
+
+	query := r.userTransfersQuery(ctx, userID, filters)
+	if after != nil {
+		query = query.Where("created_at < ? OR (created_at = ? AND id < ?)", after.CreatedAt, after.CreatedAt, after.ID)
+	}
+
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Find(&transfers).Error; err != nil {
+		return nil, fmt.Errorf("failed to list northwind transfers: %w", err)
+	}
+	return transfers, nil
+}
+
+// GetBySourceAccountKeyset returns up to limit of the user's transfers sent from the given
+// account number, newest first, that come after the given position, or from the newest when
+// after is nil. The account number is matched through its blind index.
+func (r *northwindTransferRepository) GetBySourceAccountKeyset(ctx context.Context, userID uuid.UUID, sourceAccountNumber string, after *models.AccountActivityKeyset, limit int) ([]models.NorthwindTransfer, error) {
+	bidx, err := fieldcrypt.BlindIndex(sourceAccountNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	var transfers []models.NorthwindTransfer
+	query := r.db.WithContext(ctx).Where("user_id = ? AND source_account_number_bidx = ?", userID, bidx)
+	if after != nil {
+		query = query.Where("created_at < ? OR (created_at = ? AND id < ?)", after.CreatedAt, after.CreatedAt, after.ID)
+	}
+
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Find(&transfers).Error; err != nil {
+		return nil, fmt.Errorf("failed to list northwind transfers: %w", err)
+	}
+	return transfers, nil
+}
+
+func (r *northwindTransferRepository) userTransfersQuery(ctx context.Context, userID uuid.UUID, filters models.NorthwindTransferFilters) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&models.NorthwindTransfer{}).Where("user_id = ?", userID)
+
+	if filters.Status != "" {
+		query = query.Where("status = ?", filters.Status)
+	}
+	if filters.Direction != "" {
+		query = query.Where("direction = ?", filters.Direction)
+	}
+	if filters.TransferType != "" {
+		query = query.Where("transfer_type = ?", filters.TransferType)
+	}
+	return query
+}
+
+// GetPendingTransfers returns transfers in one of statuses whose next poll is due. Transfers that
+// were never scheduled are always due. Up to priority.Slots recently viewed transfers come first, most
+// recently viewed first; the rest of the batch is filled oldest first.
+func (r *northwindTransferRepository) GetPendingTransfers(ctx context.Context, statuses []string, limit int, priority models.NorthwindPollPriority) ([]models.NorthwindTransfer, error) {
+	if len(statuses) == 0 {
+		return nil, nil
+	}
+	var transfers []models.NorthwindTransfer
+	if slots := min(priority.Slots, limit); slots > 0 {
+		if err := r.duePending(ctx, statuses).Where("last_viewed_at >= ?", priority.ViewedSince).
+			Order("last_viewed_at DESC").
+			Limit(slots).
+			Find(&transfers).Error; err != nil {
+			return nil, fmt.Errorf("failed to get viewed pending northwind transfers: %w", err)
+		}
+	}
+	if len(transfers) >= limit {
+		return transfers, nil
+	}
+
+	query := r.duePending(ctx, statuses)
+	if len(transfers) > 0 {
+		ids := make([]uuid.UUID, len(transfers))
+		for i := range transfers {
+			ids[i] = transfers[i].ID
+		}
+		query = query.Where("id NOT IN ?", ids)
+	}
+	var backlog []models.NorthwindTransfer
+	if err := query.Order("created_at ASC").
+		Limit(limit - len(transfers)).
+		Find(&backlog).Error; err != nil {
+		return nil, fmt.Errorf("failed to get pending northwind transfers: %w", err)
+	}
+	return append(transfers, backlog...), nil
+}
+
+// duePending selects transfers in one of statuses whose next poll is due
+func (r *northwindTransferRepository) duePending(ctx context.Context, statuses []string) *gorm.DB {
+	return r.db.WithContext(ctx).Where("status IN ?", statuses).
+		Where("next_poll_at IS NULL OR next_poll_at <= ?", time.Now())
+}
+
+// TouchLastViewedAt records that the transfer was viewed at, unless it was already recorded as
+// viewed within minInterval. Like SetNextPollAt it skips hooks so the version is not bumped. It
+// reports whether the view was recorded.
+func (r *northwindTransferRepository) TouchLastViewedAt(ctx context.Context, id uuid.UUID, at time.Time, minInterval time.Duration) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.NorthwindTransfer{}).
+		Where("id = ? AND (last_viewed_at IS NULL OR last_viewed_at <= ?)", id, at.Add(-minInterval)).
+		UpdateColumn("last_viewed_at", at)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to record northwind transfer view: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ClaimForInitiation marks a transfer held in status as being sent to NorthWind at, unless
+// another worker claimed it less than staleAfter ago. Like TouchLastViewedAt it skips hooks, so
+// the claim is not a change long-polling clients see. It reports whether the claim was taken.
+func (r *northwindTransferRepository) ClaimForInitiation(ctx context.Context, id uuid.UUID, status string, at time.Time, staleAfter time.Duration) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.NorthwindTransfer{}).
+		Where("id = ? AND status = ?", id, status).
+		Where("initiation_claimed_at IS NULL OR initiation_claimed_at <= ?", at.Add(-staleAfter)).
+		UpdateColumn("initiation_claimed_at", at)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to claim northwind transfer for initiation: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ReleaseInitiationClaim clears the claim of a transfer that could not be sent, skipping hooks
+// like ClaimForInitiation
+func (r *northwindTransferRepository) ReleaseInitiationClaim(ctx context.Context, id uuid.UUID) error {
+	if err := r.db.WithContext(ctx).Model(&models.NorthwindTransfer{}).Where("id = ?", id).
+		UpdateColumn("initiation_claimed_at", nil).Error; err != nil {
+		return fmt.Errorf("failed to release northwind transfer initiation claim: %w", err)
+	}
+	return nil
+}
+
+// SetNextPollAt updates only next_poll_at, skipping hooks so a reschedule does not bump the
+// version that long-polling clients watch
+func (r *northwindTransferRepository) SetNextPollAt(ctx context.Context, id uuid.UUID, at *time.Time) error {
+	if err := r.db.WithContext(ctx).Model(&models.NorthwindTransfer{}).Where("id = ?", id).
+		UpdateColumn("next_poll_at", at).Error; err != nil {
+		return fmt.Errorf("failed to schedule northwind transfer poll: %w", err)
+	}
+	return nil
+}
+
+// SetInternalTest updates only internal_test, skipping hooks like SetNextPollAt
+func (r *northwindTransferRepository) SetInternalTest(ctx context.Context, id uuid.UUID, internalTest bool) error {
+	result := r.db.WithContext(ctx).Model(&models.NorthwindTransfer{}).Where("id = ?", id).
+		UpdateColumn("internal_test", internalTest)
+	if result.Error != nil {
+		return fmt.Errorf("failed to flag northwind transfer: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNorthwindTransferNotFound
+	}
+	return nil
+}
+
+// ApplyTransition locks the transfer row and hands it to transition inside one database
+// transaction. When transition returns an event, the changed transfer and the event are saved
+// together, with the columns transition changed recorded on the event; a nil event means there
+// was nothing to apply and the row is left untouched. Holding the row lock across the read, check
+// and update serializes concurrent writers, so a transition observed twice is only applied once.
+func (r *northwindTransferRepository) ApplyTransition(ctx context.Context, id uuid.UUID, transition func(*models.NorthwindTransfer) *models.NorthwindTransferEvent) (*models.NorthwindTransfer, *models.NorthwindTransferEvent, error) {
+	var transfer models.NorthwindTransfer
+	var event *models.NorthwindTransferEvent
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&transfer).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNorthwindTransferNotFound
+			}
+			return fmt.Errorf("failed to lock northwind transfer: %w", err)
+		}
+
+		stored := transfer
+		event = transition(&transfer)
+		if event == nil {
+			return nil
+		}
+		changes, err := saveTransfer(tx, &stored, &transfer)
+		if err != nil {
+			return err
+		}
+		event.TransferID = transfer.ID
+		event.Changes = changes
+		if err := tx.Create(event).Error; err != nil {
+			return fmt.Errorf("failed to record northwind transfer event: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &transfer, event, nil
+}
+
+// ListEvents returns a transfer's status history, oldest first
+func (r *northwindTransferRepository) ListEvents(ctx context.Context, transferID uuid.UUID) ([]models.NorthwindTransferEvent, error) {
+	var events []models.NorthwindTransferEvent
+	if err := r.db.WithContext(ctx).Where("transfer_id = ?", transferID).
+		Order("created_at ASC").
+		Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to list northwind transfer events: %w", err)
+	}
+	return events, nil
+}
+
+func (r *northwindTransferRepository) GetByUserIDAndStatus(ctx context.Context, userID uuid.UUID, status string) ([]models.NorthwindTransfer, error) {
+	var transfers []models.NorthwindTransfer
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND status = ?", userID, status).
+		Order("created_at ASC").
+		Find(&transfers).Error; err != nil {
+		return nil, fmt.Errorf("failed to get northwind transfers by status: %w", err)
+	}
+	return transfers, nil
+}
+
+// GetByStatus returns up to limit transfers in the given status, oldest first
+func (r *northwindTransferRepository) GetByStatus(ctx context.Context, status string, limit int) ([]models.NorthwindTransfer, error) {
 	var transfers []models.NorthwindTransfer
-	if err := r.db.Where("status IN ?", []string{models.NWTransferStatusPending, models.NWTransferStatusProcessing}).
+	if err := r.db.WithContext(ctx).Where("status = ?", status).
 		Order("created_at ASC").
 		Limit(limit).
 		Find(&transfers).Error; err != nil {
-		return nil, fmt.Errorf("failed to get pending northwind transfers: %w", err)
+		return nil, fmt.Errorf("failed to get northwind transfers by status: %w", err)
+	}
+	return transfers, nil
+}
+
+// GetScheduledTransfersDue returns up to limit SCHEDULED transfers whose scheduled date is not
+// after before, earliest first
+func (r *northwindTransferRepository) GetScheduledTransfersDue(ctx context.Context, before time.Time, limit int) ([]models.NorthwindTransfer, error) {
+	var transfers []models.NorthwindTransfer
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND scheduled_date <= ?", models.NWTransferStatusScheduled, before).
+		Order("scheduled_date ASC, created_at ASC").
+		Limit(limit).
+		Find(&transfers).Error; err != nil {
+		return nil, fmt.Errorf("failed to get due scheduled northwind transfers: %w", err)
+	}
+	return transfers, nil
+}
+
+// GetByUserIDAndBatch returns the user's transfers submitted in the named batch, in batch order
+func (r *northwindTransferRepository) GetByUserIDAndBatch(ctx context.Context, userID uuid.UUID, batchName string) ([]models.NorthwindTransfer, error) {
+	var transfers []models.NorthwindTransfer
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND batch_name = ?", userID, batchName).
+		Order("batch_index ASC").
+		Find(&transfers).Error; err != nil {
+		return nil, fmt.Errorf("failed to get northwind transfers by batch: %w", err)
+	}
+	return transfers, nil
+}
+
+// ReferenceExists reports whether the user has a transfer with referenceNumber, as given or
+// normalized
+func (r *northwindTransferRepository) ReferenceExists(ctx context.Context, userID uuid.UUID, referenceNumber string) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.NorthwindTransfer{}).
+		Where("user_id = ? AND reference_number IN ?", userID, models.TransferReferenceForms(referenceNumber)).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check northwind transfer reference: %w", err)
+	}
+	return count > 0, nil
+}
+
+// GetUnlinkedByReference returns every user's transfers with referenceNumber, as given or
+// normalized, that were never linked to a NorthWind transfer, such as ones queued during
+// maintenance or rejected in a batch
+func (r *northwindTransferRepository) GetUnlinkedByReference(ctx context.Context, referenceNumber string) ([]models.NorthwindTransfer, error) {
+	var transfers []models.NorthwindTransfer
+	if err := r.db.WithContext(ctx).Where("reference_number IN ? AND external_ref IS NULL", models.TransferReferenceForms(referenceNumber)).
+		Order("created_at ASC").
+		Find(&transfers).Error; err != nil {
+		return nil, fmt.Errorf("failed to get unlinked northwind transfers by reference: %w", err)
 	}
 	return transfers, nil
 }
+
+// CountByStatus returns the number of transfers in each of statuses. Statuses with no transfers
+// are absent from the map.
+func (r *northwindTransferRepository) CountByStatus(ctx context.Context, statuses ...string) (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	if err := r.db.WithContext(ctx).Model(&models.NorthwindTransfer{}).
+		Select("status, COUNT(*) AS count").
+		Where("status IN ?", statuses).
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count northwind transfers by status: %w", err)
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// CountByStatusSince returns the number of transfers created at or after since in each status.
+// Statuses with no transfers are absent from the map.
+func (r *northwindTransferRepository) CountByStatusSince(ctx context.Context, since time.Time) (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	if err := r.db.WithContext(ctx).Model(&models.NorthwindTransfer{}).
+		Select("status, COUNT(*) AS count").
+		Where("created_at >= ?", since).
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count recent northwind transfers by status: %w", err)
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// GetInitiationOutcomes counts the transfers created at or after since that NorthWind accepted
+// and those it rejected. Transfers still queued for initiation are in neither count.
+func (r *northwindTransferRepository) GetInitiationOutcomes(ctx context.Context, since time.Time) (*models.NorthwindInitiationOutcomes, error) {
+	var outcomes models.NorthwindInitiationOutcomes
+	if err := r.db.WithContext(ctx).Model(&models.NorthwindTransfer{}).
+		Select(`COUNT(CASE WHEN external_ref IS NOT NULL THEN 1 END) AS initiated,
+			COUNT(CASE WHEN external_ref IS NULL AND status = ? THEN 1 END) AS rejected`, models.NWTransferStatusFailed).
+		Where("created_at >= ?", since).
+		Scan(&outcomes).Error; err != nil {
+		return nil, fmt.Errorf("failed to count northwind transfer initiations: %w", err)
+	}
+	return &outcomes, nil
+}
+
+// GetPollingBacklog counts the in-flight transfers and those due for a poll, with the creation
+// time of the oldest in-flight transfer
+func (r *northwindTransferRepository) GetPollingBacklog(ctx context.Context) (*models.NorthwindPollingBacklog, error) {
+	inFlight := models.NWTransferPollableStatusValues()
+	var backlog models.NorthwindPollingBacklog
+	if err := r.db.WithContext(ctx).Model(&models.NorthwindTransfer{}).
+		Where("status IN ?", inFlight).
+		Count(&backlog.InFlight).Error; err != nil {
+		return nil, fmt.Errorf("failed to count in-flight northwind transfers: %w", err)
+	}
+	if backlog.InFlight == 0 {
+		return &backlog, nil
+	}
+	if err := r.duePending(ctx, inFlight).Model(&models.NorthwindTransfer{}).Count(&backlog.Due).Error; err != nil {
+		return nil, fmt.Errorf("failed to count northwind transfers due for polling: %w", err)
+	}
+
+	var oldest models.NorthwindTransfer
+	err := r.db.WithContext(ctx).Select("id", "created_at").
+		Where("status IN ?", inFlight).
+		Order("created_at ASC").
+		First(&oldest).Error
+	switch {
+	case err == nil:
+		backlog.OldestCreatedAt = &oldest.CreatedAt
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, fmt.Errorf("failed to find the oldest in-flight northwind transfer: %w", err)
+	}
+	return &backlog, nil
+}
+
+// FindRecentDuplicate returns the latest transfer by userID created at or after since with the same
+// amount, currency, direction, and destination account that has not FAILED or been CANCELLED.
+// The destination is matched through its blind index; rows not yet backfilled are matched on the
+// plaintext column. Only the ID, reference number, status, and creation time are loaded.
+func (r *northwindTransferRepository) FindRecentDuplicate(ctx context.Context, userID uuid.UUID, amount decimal.Decimal, currency, direction, destinationAccountNumber string, since time.Time) (*models.NorthwindTransfer, error) {
+	bidx, err := fieldcrypt.BlindIndex(destinationAccountNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	var transfer models.NorthwindTransfer
+	if err := r.db.WithContext(ctx).Select("id", "reference_number", "status", "created_at").
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Where("destination_account_number_bidx = ? OR (destination_account_number_bidx IS NULL AND destination_account_number = ?)", bidx, destinationAccountNumber).
+		Where("amount = ? AND currency = ? AND direction = ?", amount.Round(2), currency, direction).
+		Where("status NOT IN ?", []string{models.NWTransferStatusFailed, models.NWTransferStatusCancelled}).
+		Order("created_at DESC").
+		First(&transfer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNorthwindTransferNotFound
+		}
+		return nil, fmt.Errorf("failed to check for duplicate northwind transfer: %w", err)
+	}
+	return &transfer, nil
+}
+
+// GetUserVelocity counts the user's transfers in currency created since since and sums their
+// amounts, leaving out failed and cancelled transfers
+func (r *northwindTransferRepository) GetUserVelocity(ctx context.Context, userID uuid.UUID, currency string, since time.Time) (*models.NorthwindTransferVelocity, error) {
+	var velocity models.NorthwindTransferVelocity
+	if err := r.db.WithContext(ctx).Model(&models.NorthwindTransfer{}).
+		Select("COUNT(*) AS count, COALESCE(SUM(amount), 0) AS amount").
+		Where("user_id = ? AND currency = ? AND created_at >= ?", userID, currency, since).
+		Where("status NOT IN ?", []string{models.NWTransferStatusFailed, models.NWTransferStatusCancelled}).
+		Scan(&velocity).Error; err != nil {
+		return nil, fmt.Errorf("failed to compute northwind transfer velocity: %w", err)
+	}
+	return &velocity, nil
+}
+
+// GetCompletionDurationStats returns p50/p95 initiated-to-completed durations per transfer type
+// for COMPLETED transfers initiated in [from, to). Transfers missing either timestamp are
+// excluded. Postgres computes the percentiles in SQL; other dialects (sqlite in tests) load the
+// durations and interpolate in Go with the same percentile_cont semantics.
+func (r *northwindTransferRepository) GetCompletionDurationStats(ctx context.Context, from, to time.Time) ([]models.TransferDurationStats, error) {
+	query := r.db.WithContext(ctx).Model(&models.NorthwindTransfer{}).
+		Where("status = ?", models.NWTransferStatusCompleted).
+		Where("initiated_date IS NOT NULL AND completed_date IS NOT NULL").
+		Where("initiated_date >= ? AND initiated_date < ?", from, to)
+
+	if r.db.WithContext(ctx).Dialector.Name() == "postgres" {
+		var stats []models.TransferDurationStats
+		if err := query.
+			Select(`transfer_type,
+				COUNT(*) AS sample_count,
+				percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (completed_date - initiated_date))) AS p50_seconds,
+				percentile_cont(0.95) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (completed_date - initiated_date))) AS p95_seconds`).
+			Group("transfer_type").
+			Order("transfer_type").
+			Scan(&stats).Error; err != nil {
+			return nil, fmt.Errorf("failed to compute northwind transfer duration stats: %w", err)
+		}
+		return stats, nil
+	}
+
+	var rows []struct {
+		TransferType  string
+		InitiatedDate time.Time
+		CompletedDate time.Time
+	}
+	if err := query.Select("transfer_type, initiated_date, completed_date").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load northwind transfer durations: %w", err)
+	}
+
+	durations := make(map[string][]float64)
+	for _, row := range rows {
+		durations[row.TransferType] = append(durations[row.TransferType], row.CompletedDate.Sub(row.InitiatedDate).Seconds())
+	}
+	stats := make([]models.TransferDurationStats, 0, len(durations))
+	for transferType, values := range durations {
+		sort.Float64s(values)
+		stats = append(stats, models.TransferDurationStats{
+			TransferType: transferType,
+			SampleCount:  int64(len(values)),
+			P50Seconds:   percentileCont(values, 0.5),
+			P95Seconds:   percentileCont(values, 0.95),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].TransferType < stats[j].TransferType })
+	return stats, nil
+}
+
+// percentileCont interpolates the p-th percentile of sorted values like Postgres percentile_cont
+func percentileCont(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (rank-float64(lower))*(sorted[upper]-sorted[lower])
+}
+
+// northwindTransferRetentionHold keeps transfers that are still in flight, have an unresolved
+// poll anomaly, have a regulator notification not delivered yet, or have ever been disputed
+func northwindTransferRetentionHold() retentionHold {
+	return retentionHold{
+		query: "status NOT IN ? OR EXISTS (SELECT 1 FROM poll_anomalies a WHERE a.transfer_id = northwind_transfers.id AND a.resolved_at IS NULL) " +
+			"OR EXISTS (SELECT 1 FROM regulator_notifications n WHERE n.transfer_id = northwind_transfers.id AND n.delivered = ?) " +
+			"OR EXISTS (SELECT 1 FROM transfer_disputes d WHERE d.transfer_id = northwind_transfers.id)",
+		args: []interface{}{models.NWTransferTerminalStatusValues(), false},
+	}
+}
+
+// CountRetentionExpired counts the transfers created before cutoff, split into those that can be
+// purged and those still held
+func (r *northwindTransferRepository) CountRetentionExpired(ctx context.Context, cutoff time.Time) (models.RetentionCount, error) {
+	count, err := countRetention(ctx, r.db, "northwind_transfers", cutoff, northwindTransferRetentionHold())
+	if err != nil {
+		return count, fmt.Errorf("failed to count expired northwind transfers: %w", err)
+	}
+	return count, nil
+}
+
+// PurgeRetentionExpired hard-deletes up to limit purgeable transfers created before cutoff. Their
+// events, regulator notifications and resolved anomalies go with them.
+func (r *northwindTransferRepository) PurgeRetentionExpired(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	deleted, err := purgeRetention(ctx, r.db, &models.NorthwindTransfer{}, "northwind_transfers", cutoff, northwindTransferRetentionHold(), limit)
+	if err != nil {
+		return deleted, fmt.Errorf("failed to purge expired northwind transfers: %w", err)
+	}
+	return deleted, nil
+}
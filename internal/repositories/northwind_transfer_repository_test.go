@@ -0,0 +1,667 @@
+package repositories
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/database"
+	"github.com/array/banking-api/internal/models"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+// NorthwindTransferRepositorySuite defines the test suite for NorthwindTransferRepository
+type NorthwindTransferRepositorySuite struct {
+	suite.Suite
+	db   *database.DB
+	repo NorthwindTransferRepositoryInterface
+}
+
+// SetupTest runs before each test in the suite
+func (s *NorthwindTransferRepositorySuite) SetupTest() {
+	s.db = database.SetupTestDB(s.T())
+	s.Require().NoError(s.db.DB.AutoMigrate(&models.NorthwindTransfer{}, &models.NorthwindTransferEvent{}))
+	s.repo = NewNorthwindTransferRepository(s.db.DB)
+}
+
+// TearDownTest runs after each test in the suite
+func (s *NorthwindTransferRepositorySuite) TearDownTest() {
+	database.CleanupTestDB(s.T(), s.db)
+}
+
+// TestNorthwindTransferRepositorySuite runs the test suite
+func TestNorthwindTransferRepositorySuite(t *testing.T) {
+	suite.Run(t, new(NorthwindTransferRepositorySuite))
+}
+
+func (s *NorthwindTransferRepositorySuite) newTransfer(userID uuid.UUID, reference string) *models.NorthwindTransfer {
+	return &models.NorthwindTransfer{
+		UserID:                   &userID,
+		NorthwindTransferID:      uuid.New(),
+		Direction:                models.NWTransferDirectionOutbound,
+		TransferType:             "ACH",
+		Amount:                   decimal.NewFromFloat(100),
+		Currency:                 "USD",
+		ReferenceNumber:          reference,
+		SourceAccountNumber:      "1111111111",
+		DestinationAccountNumber: "2222222222",
+	}
+}
+
+func (s *NorthwindTransferRepositorySuite) TestCreate_ReferenceUniquePerUser() {
+	alice, bob := uuid.New(), uuid.New()
+
+	s.NoError(s.repo.Create(context.Background(), s.newTransfer(alice, "REF001")))
+	s.NoError(s.repo.Create(context.Background(), s.newTransfer(bob, "REF001")), "other users may reuse a reference")
+
+	err := s.repo.Create(context.Background(), s.newTransfer(alice, "REF001"))
+	s.ErrorIs(err, ErrNorthwindTransferDuplicateReference)
+}
+
+func (s *NorthwindTransferRepositorySuite) TestGetByIDs() {
+	ctx := context.Background()
+	userID := uuid.New()
+	first, second := s.newTransfer(userID, "REF001"), s.newTransfer(userID, "REF002")
+	s.Require().NoError(s.repo.Create(ctx, first))
+	s.Require().NoError(s.repo.Create(ctx, second))
+
+	var queries int
+	s.Require().NoError(s.db.Callback().Query().After("gorm:query").Register("test:count_queries", func(*gorm.DB) {
+		queries++
+	}))
+	defer func() { _ = s.db.Callback().Query().Remove("test:count_queries") }()
+
+	// The second transfer sits past the first chunk; every other ID has no transfer
+	ids := []uuid.UUID{first.ID}
+	for len(ids) < transferIDsChunkSize+1 {
+		ids = append(ids, uuid.New())
+	}
+	ids = append(ids, second.ID)
+
+	transfers, err := s.repo.GetByIDs(ctx, ids)
+	s.Require().NoError(err)
+	s.Equal(2, queries, "expected one query per chunk of IDs")
+	s.Len(transfers, 2, "missing IDs are absent rather than an error")
+	s.Equal("REF001", transfers[first.ID].ReferenceNumber)
+	s.Equal("REF002", transfers[second.ID].ReferenceNumber)
+
+	empty, err := s.repo.GetByIDs(ctx, nil)
+	s.Require().NoError(err)
+	s.Empty(empty)
+	s.Equal(2, queries, "expected no query without IDs")
+}
+
+func (s *NorthwindTransferRepositorySuite) TestGetScheduledTransfersDue() {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	create := func(reference, status string, scheduled time.Time) *models.NorthwindTransfer {
+		transfer := s.newTransfer(uuid.New(), reference)
+		transfer.Status = status
+		transfer.ScheduledDate = &scheduled
+		s.Require().NoError(s.repo.Create(ctx, transfer))
+		return transfer
+	}
+	later := create("REF-LATER", models.NWTransferStatusScheduled, now.Add(-time.Hour))
+	earlier := create("REF-EARLIER", models.NWTransferStatusScheduled, now.Add(-2*time.Hour))
+	create("REF-FUTURE", models.NWTransferStatusScheduled, now.Add(time.Hour))
+	create("REF-SENT", models.NWTransferStatusPending, now.Add(-3*time.Hour))
+
+	due, err := s.repo.GetScheduledTransfersDue(ctx, now, 10)
+	s.Require().NoError(err)
+	s.Require().Len(due, 2, "only SCHEDULED transfers that are due")
+	s.Equal(earlier.ID, due[0].ID, "earliest scheduled date first")
+	s.Equal(later.ID, due[1].ID)
+
+	due, err = s.repo.GetScheduledTransfersDue(ctx, now, 1)
+	s.Require().NoError(err)
+	s.Len(due, 1)
+}
+
+func (s *NorthwindTransferRepositorySuite) TestReferenceExists() {
+	userID := uuid.New()
+	s.NoError(s.repo.Create(context.Background(), s.newTransfer(userID, "REF001")))
+
+	exists, err := s.repo.ReferenceExists(context.Background(), userID, "REF001")
+	s.NoError(err)
+	s.True(exists)
+
+	exists, err = s.repo.ReferenceExists(context.Background(), userID, "REF002")
+	s.NoError(err)
+	s.False(exists)
+
+	exists, err = s.repo.ReferenceExists(context.Background(), uuid.New(), "REF001")
+	s.NoError(err)
+	s.False(exists)
+}
+
+func (s *NorthwindTransferRepositorySuite) TestReferenceLookupsMatchRawAndNormalized() {
+	ctx := context.Background()
+	userID := uuid.New()
+	// Queued, so never linked to NorthWind; the first was stored before references were normalized
+	legacy := s.newTransfer(userID, "ref 001")
+	legacy.Status = models.NWTransferStatusInitiationPending
+	s.Require().NoError(s.repo.Create(ctx, legacy))
+	normalized := s.newTransfer(uuid.New(), "REF-002")
+	normalized.Status = models.NWTransferStatusInitiationPending
+	s.Require().NoError(s.repo.Create(ctx, normalized))
+
+	exists, err := s.repo.ReferenceExists(ctx, userID, "ref 001")
+	s.NoError(err)
+	s.True(exists, "the raw form finds the legacy reference")
+
+	exists, err = s.repo.ReferenceExists(ctx, *normalized.UserID, "ref 002")
+	s.NoError(err)
+	s.True(exists, "the normalized form finds a normalized reference")
+
+	for reference, want := range map[string]uuid.UUID{"ref 001": legacy.ID, "ref 002": normalized.ID} {
+		unlinked, err := s.repo.GetUnlinkedByReference(ctx, reference)
+		s.Require().NoError(err)
+		s.Require().Len(unlinked, 1, reference)
+		s.Equal(want, unlinked[0].ID, reference)
+	}
+}
+
+func (s *NorthwindTransferRepositorySuite) createCompleted(transferType string, initiated time.Time, duration time.Duration) {
+	tr := s.newTransfer(uuid.New(), "REF-"+uuid.NewString()[:8])
+	tr.TransferType = transferType
+	tr.Status = models.NWTransferStatusCompleted
+	completed := initiated.Add(duration)
+	tr.InitiatedDate = &initiated
+	tr.CompletedDate = &completed
+	s.Require().NoError(s.repo.Create(context.Background(), tr))
+}
+
+func (s *NorthwindTransferRepositorySuite) TestGetCompletionDurationStats() {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// ACH: 1h..5h -> p50 3h, p95 4.8h
+	for i := 1; i <= 5; i++ {
+		s.createCompleted("ACH", base.Add(time.Duration(i)*time.Minute), time.Duration(i)*time.Hour)
+	}
+	// WIRE: 10m, 30m -> p50 20m, p95 29m
+	s.createCompleted("WIRE", base, 10*time.Minute)
+	s.createCompleted("WIRE", base, 30*time.Minute)
+
+	// Excluded: outside the window, missing a timestamp, not COMPLETED
+	s.createCompleted("ACH", base.AddDate(0, -2, 0), 100*time.Hour)
+	missing := s.newTransfer(uuid.New(), "REF-MISSING")
+	missing.Status = models.NWTransferStatusCompleted
+	missing.InitiatedDate = &base
+	s.Require().NoError(s.repo.Create(context.Background(), missing))
+	failed := s.newTransfer(uuid.New(), "REF-FAILED")
+	failed.Status = models.NWTransferStatusFailed
+	failedDone := base.Add(200 * time.Hour)
+	failed.InitiatedDate, failed.CompletedDate = &base, &failedDone
+	s.Require().NoError(s.repo.Create(context.Background(), failed))
+
+	stats, err := s.repo.GetCompletionDurationStats(context.Background(), base.AddDate(0, 0, -7), base.AddDate(0, 0, 7))
+	s.Require().NoError(err)
+	s.Require().Len(stats, 2)
+
+	s.Equal("ACH", stats[0].TransferType)
+	s.Equal(int64(5), stats[0].SampleCount)
+	s.InDelta(3*3600, stats[0].P50Seconds, 0.001)
+	s.InDelta(4.8*3600, stats[0].P95Seconds, 0.001)
+
+	s.Equal("WIRE", stats[1].TransferType)
+	s.Equal(int64(2), stats[1].SampleCount)
+	s.InDelta(20*60, stats[1].P50Seconds, 0.001)
+	s.InDelta(29*60, stats[1].P95Seconds, 0.001)
+}
+
+func (s *NorthwindTransferRepositorySuite) TestFindRecentDuplicate() {
+	userID := uuid.New()
+	now := time.Now().UTC().Truncate(time.Second)
+	since := now.Add(-2 * time.Minute)
+	amount := decimal.NewFromFloat(100)
+
+	create := func(reference string, createdAt time.Time, mutate func(*models.NorthwindTransfer)) *models.NorthwindTransfer {
+		tr := s.newTransfer(userID, reference)
+		tr.CreatedAt = createdAt
+		if mutate != nil {
+			mutate(tr)
+		}
+		s.Require().NoError(s.repo.Create(context.Background(), tr))
+		return tr
+	}
+
+	// Just outside the window, and in-window rows that differ or have failed
+	create("REF-OLD", since.Add(-time.Second), nil)
+	create("REF-AMOUNT", now, func(tr *models.NorthwindTransfer) { tr.Amount = decimal.NewFromFloat(100.01) })
+	create("REF-DEST", now, func(tr *models.NorthwindTransfer) { tr.DestinationAccountNumber = "3333333333" })
+	create("REF-DIR", now, func(tr *models.NorthwindTransfer) { tr.Direction = models.NWTransferDirectionInbound })
+	create("REF-FAILED", now, func(tr *models.NorthwindTransfer) { tr.Status = models.NWTransferStatusFailed })
+	create("REF-CANCELLED", now, func(tr *models.NorthwindTransfer) { tr.Status = models.NWTransferStatusCancelled })
+
+	_, err := s.repo.FindRecentDuplicate(context.Background(), userID, amount, "USD", models.NWTransferDirectionOutbound, "2222222222", since)
+	s.ErrorIs(err, ErrNorthwindTransferNotFound)
+
+	// Exactly on the window boundary counts as a duplicate
+	onBoundary := create("REF-BOUNDARY", since, nil)
+	found, err := s.repo.FindRecentDuplicate(context.Background(), userID, amount, "USD", models.NWTransferDirectionOutbound, "2222222222", since)
+	s.Require().NoError(err)
+	s.Equal(onBoundary.ID, found.ID)
+	s.Equal("REF-BOUNDARY", found.ReferenceNumber)
+
+	// The latest match wins, and other users' transfers are never matched
+	latest := create("REF-LATEST", now, func(tr *models.NorthwindTransfer) { tr.Status = models.NWTransferStatusCompleted })
+	found, err = s.repo.FindRecentDuplicate(context.Background(), userID, amount, "USD", models.NWTransferDirectionOutbound, "2222222222", since)
+	s.Require().NoError(err)
+	s.Equal(latest.ID, found.ID)
+
+	_, err = s.repo.FindRecentDuplicate(context.Background(), uuid.New(), amount, "USD", models.NWTransferDirectionOutbound, "2222222222", since)
+	s.ErrorIs(err, ErrNorthwindTransferNotFound)
+}
+
+func (s *NorthwindTransferRepositorySuite) TestCountByStatus() {
+	userID := uuid.New()
+	statuses := []string{
+		models.NWTransferStatusPending,
+		models.NWTransferStatusPending,
+		models.NWTransferStatusProcessing,
+		models.NWTransferStatusCompleted,
+	}
+	for i, status := range statuses {
+		tr := s.newTransfer(userID, "REF-COUNT-"+strconv.Itoa(i))
+		tr.Status = status
+		s.Require().NoError(s.repo.Create(context.Background(), tr))
+	}
+
+	counts, err := s.repo.CountByStatus(context.Background(), models.NWTransferStatusPending, models.NWTransferStatusProcessing, models.NWTransferStatusFailed)
+	s.Require().NoError(err)
+	s.Equal(map[string]int64{
+		models.NWTransferStatusPending:    2,
+		models.NWTransferStatusProcessing: 1,
+	}, counts)
+}
+
+func (s *NorthwindTransferRepositorySuite) TestGetByUserIDKeyset_StableAcrossInserts() {
+	ctx := context.Background()
+	userID := uuid.New()
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	// Pairs of transfers share a created_at so pages must break ties on id
+	for i := 0; i < 9; i++ {
+		tr := s.newTransfer(userID, "REF-KEYSET-"+strconv.Itoa(i))
+		tr.CreatedAt = base.Add(time.Duration(i/2) * time.Minute)
+		s.Require().NoError(s.repo.Create(ctx, tr))
+	}
+	s.Require().NoError(s.repo.Create(ctx, s.newTransfer(uuid.New(), "REF-OTHER-USER")))
+	expected, _, err := s.repo.GetByUserID(ctx, userID, 0, 100)
+	s.Require().NoError(err)
+
+	seen := make(map[uuid.UUID]bool)
+	var after *models.NorthwindTransferKeyset
+	for page := 0; page < 3; page++ {
+		transfers, err := s.repo.GetByUserIDKeyset(ctx, userID, models.NorthwindTransferFilters{}, after, 3)
+		s.Require().NoError(err)
+		s.Require().Len(transfers, 3, "page %d", page)
+		for i, tr := range transfers {
+			s.False(seen[tr.ID], "transfer %s returned twice", tr.ReferenceNumber)
+			seen[tr.ID] = true
+			if i > 0 {
+				prev := transfers[i-1]
+				s.True(prev.CreatedAt.After(tr.CreatedAt) || (prev.CreatedAt.Equal(tr.CreatedAt) && prev.ID.String() > tr.ID.String()),
+					"page %d is not in keyset order", page)
+			}
+		}
+		last := transfers[len(transfers)-1]
+		after = &models.NorthwindTransferKeyset{CreatedAt: last.CreatedAt, ID: last.ID}
+
+		// Newer transfers arriving mid-iteration land before the cursor and do not shift pages
+		s.Require().NoError(s.repo.Create(ctx, s.newTransfer(userID, "REF-KEYSET-NEW-"+strconv.Itoa(page))))
+	}
+
+	s.Len(seen, len(expected))
+	for _, tr := range expected {
+		s.True(seen[tr.ID], "transfer %s was skipped", tr.ReferenceNumber)
+	}
+
+	rest, err := s.repo.GetByUserIDKeyset(ctx, userID, models.NorthwindTransferFilters{}, after, 3)
+	s.Require().NoError(err)
+	s.Empty(rest)
+}
+
+func (s *NorthwindTransferRepositorySuite) TestGetByUserIDKeyset_Filters() {
+	ctx := context.Background()
+	userID := uuid.New()
+	for i, status := range []string{models.NWTransferStatusPending, models.NWTransferStatusCompleted, models.NWTransferStatusCompleted} {
+		tr := s.newTransfer(userID, "REF-KEYSET-FILTER-"+strconv.Itoa(i))
+		tr.Status = status
+		s.Require().NoError(s.repo.Create(ctx, tr))
+	}
+
+	transfers, err := s.repo.GetByUserIDKeyset(ctx, userID, models.NorthwindTransferFilters{Status: models.NWTransferStatusCompleted}, nil, 10)
+	s.Require().NoError(err)
+	s.Len(transfers, 2)
+	for _, tr := range transfers {
+		s.Equal(models.NWTransferStatusCompleted, tr.Status)
+	}
+}
+
+func (s *NorthwindTransferRepositorySuite) TestGetByExternalRef() {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	uuidShaped := s.newTransfer(userID, "REF-UUID")
+	s.Require().NoError(s.repo.Create(ctx, uuidShaped))
+	opaqueRef := "nw_TR-00042/a"
+	opaque := s.newTransfer(userID, "REF-OPAQUE")
+	opaque.ExternalRef = &opaqueRef
+	s.Require().NoError(s.repo.Create(ctx, opaque))
+
+	found, err := s.repo.GetByExternalRef(ctx, uuidShaped.NorthwindTransferID.String())
+	s.Require().NoError(err)
+	s.Equal(uuidShaped.ID, found.ID, "UUID-shaped ref defaults to the NorthWind transfer ID")
+
+	found, err = s.repo.GetByNorthwindTransferID(ctx, uuidShaped.NorthwindTransferID)
+	s.Require().NoError(err)
+	s.Equal(uuidShaped.ID, found.ID)
+
+	found, err = s.repo.GetByExternalRef(ctx, opaqueRef)
+	s.Require().NoError(err)
+	s.Equal(opaque.ID, found.ID)
+
+	for _, ref := range []string{
+		"NW_tr-00042/A",
+		"nw_TR-00042",
+		"nw_TR-00042/a ",
+		strings.ToUpper(uuidShaped.NorthwindTransferID.String()),
+		"",
+	} {
+		_, err := s.repo.GetByExternalRef(ctx, ref)
+		s.ErrorIs(err, ErrNorthwindTransferNotFound, "ref %q", ref)
+	}
+	_, err = s.repo.GetByNorthwindTransferID(ctx, uuid.New())
+	s.ErrorIs(err, ErrNorthwindTransferNotFound)
+}
+
+func (s *NorthwindTransferRepositorySuite) TestGetPendingTransfers_OnlyDue() {
+	ctx := context.Background()
+	userID := uuid.New()
+	past, future := time.Now().Add(-time.Minute), time.Now().Add(time.Hour)
+
+	due := s.newTransfer(userID, "REF-DUE")
+	due.NextPollAt = &past
+	unscheduled := s.newTransfer(userID, "REF-UNSCHEDULED")
+	notDue := s.newTransfer(userID, "REF-NOT-DUE")
+	notDue.NextPollAt = &future
+	completed := s.newTransfer(userID, "REF-COMPLETED")
+	completed.Status = models.NWTransferStatusCompleted
+	completed.NextPollAt = &past
+	// Queued locally and unknown to NorthWind
+	queued := s.newTransfer(userID, "REF-QUEUED")
+	queued.Status = models.NWTransferStatusInitiationPending
+	queued.NextPollAt = &past
+	for _, tr := range []*models.NorthwindTransfer{due, unscheduled, notDue, completed, queued} {
+		s.Require().NoError(s.repo.Create(ctx, tr))
+	}
+
+	pending, err := s.repo.GetPendingTransfers(ctx, models.NWTransferPollableStatusValues(), 10, models.NorthwindPollPriority{})
+	s.Require().NoError(err)
+	ids := make([]uuid.UUID, len(pending))
+	for i, tr := range pending {
+		ids[i] = tr.ID
+	}
+	s.ElementsMatch([]uuid.UUID{due.ID, unscheduled.ID}, ids)
+
+	s.Require().NoError(s.repo.SetNextPollAt(ctx, notDue.ID, &past))
+	pending, err = s.repo.GetPendingTransfers(ctx, models.NWTransferPollableStatusValues(), 10, models.NorthwindPollPriority{})
+	s.Require().NoError(err)
+	s.Len(pending, 3)
+
+	got, err := s.repo.GetByID(ctx, notDue.ID)
+	s.Require().NoError(err)
+	s.Equal(notDue.Version, got.Version, "rescheduling must not bump the version")
+
+	pending, err = s.repo.GetPendingTransfers(ctx, []string{models.NWTransferStatusProcessing}, 10, models.NorthwindPollPriority{})
+	s.Require().NoError(err)
+	s.Empty(pending, "only transfers in the given statuses are returned")
+	pending, err = s.repo.GetPendingTransfers(ctx, nil, 10, models.NorthwindPollPriority{})
+	s.Require().NoError(err)
+	s.Empty(pending)
+}
+
+func (s *NorthwindTransferRepositorySuite) TestGetPendingTransfers_ViewedFirst() {
+	ctx := context.Background()
+	userID := uuid.New()
+	now := time.Now()
+
+	// Created oldest first: backlog-1 .. backlog-3, then the viewed transfers
+	var backlog []*models.NorthwindTransfer
+	for i := 1; i <= 3; i++ {
+		tr := s.newTransfer(userID, "REF-BACKLOG-"+strconv.Itoa(i))
+		tr.CreatedAt = now.Add(time.Duration(i-10) * time.Minute)
+		s.Require().NoError(s.repo.Create(ctx, tr))
+		backlog = append(backlog, tr)
+	}
+	viewedAt := map[string]time.Time{
+		"REF-VIEWED-OLD":    now.Add(-3 * time.Minute),
+		"REF-VIEWED-NEW":    now.Add(-time.Minute),
+		"REF-VIEWED-STALE":  now.Add(-time.Hour),
+		"REF-VIEWED-MIDDLE": now.Add(-2 * time.Minute),
+	}
+	viewed := map[string]*models.NorthwindTransfer{}
+	for ref, at := range viewedAt {
+		tr := s.newTransfer(userID, ref)
+		tr.CreatedAt = now.Add(-time.Minute)
+		s.Require().NoError(s.repo.Create(ctx, tr))
+		_, err := s.repo.TouchLastViewedAt(ctx, tr.ID, at, time.Minute)
+		s.Require().NoError(err)
+		viewed[ref] = tr
+	}
+
+	priority := models.NorthwindPollPriority{ViewedSince: now.Add(-5 * time.Minute), Slots: 2}
+	pending, err := s.repo.GetPendingTransfers(ctx, models.NWTransferPollableStatusValues(), 5, priority)
+	s.Require().NoError(err)
+	refs := make([]string, len(pending))
+	for i, tr := range pending {
+		refs[i] = tr.ReferenceNumber
+	}
+	// Two priority slots for the most recently viewed, then oldest first; a view outside the
+	// window earns no priority
+	s.Equal([]string{"REF-VIEWED-NEW", "REF-VIEWED-MIDDLE", "REF-BACKLOG-1", "REF-BACKLOG-2", "REF-BACKLOG-3"}, refs)
+
+	pending, err = s.repo.GetPendingTransfers(ctx, models.NWTransferPollableStatusValues(), 5, models.NorthwindPollPriority{})
+	s.Require().NoError(err)
+	s.Equal(backlog[0].ID, pending[0].ID, "without priority slots the oldest transfer comes first")
+
+	pending, err = s.repo.GetPendingTransfers(ctx, models.NWTransferPollableStatusValues(), 1, priority)
+	s.Require().NoError(err)
+	s.Require().Len(pending, 1)
+	s.Equal(viewed["REF-VIEWED-NEW"].ID, pending[0].ID)
+}
+
+func (s *NorthwindTransferRepositorySuite) TestTouchLastViewedAt_Throttled() {
+	ctx := context.Background()
+	tr := s.newTransfer(uuid.New(), "REF-TOUCH")
+	s.Require().NoError(s.repo.Create(ctx, tr))
+	first := time.Now().UTC().Truncate(time.Second)
+
+	touched, err := s.repo.TouchLastViewedAt(ctx, tr.ID, first, time.Minute)
+	s.Require().NoError(err)
+	s.True(touched, "the first view is recorded")
+
+	touched, err = s.repo.TouchLastViewedAt(ctx, tr.ID, first.Add(30*time.Second), time.Minute)
+	s.Require().NoError(err)
+	s.False(touched, "a second view within the interval is not written")
+
+	got, err := s.repo.GetByID(ctx, tr.ID)
+	s.Require().NoError(err)
+	s.Require().NotNil(got.LastViewedAt)
+	s.True(got.LastViewedAt.Equal(first), "expected %v, got %v", first, got.LastViewedAt)
+	s.Equal(tr.Version, got.Version, "recording a view must not bump the version")
+
+	touched, err = s.repo.TouchLastViewedAt(ctx, tr.ID, first.Add(time.Minute), time.Minute)
+	s.Require().NoError(err)
+	s.True(touched, "a view once the interval has passed is recorded")
+}
+
+func (s *NorthwindTransferRepositorySuite) TestClaimForInitiation() {
+	ctx := context.Background()
+	tr := s.newTransfer(uuid.New(), "REF-CLAIM")
+	tr.Status = models.NWTransferStatusInitiationPending
+	s.Require().NoError(s.repo.Create(ctx, tr))
+	first := time.Now().UTC().Truncate(time.Second)
+
+	claimed, err := s.repo.ClaimForInitiation(ctx, tr.ID, models.NWTransferStatusScheduled, first, time.Minute)
+	s.Require().NoError(err)
+	s.False(claimed, "a transfer not in the expected status is not claimed")
+
+	claimed, err = s.repo.ClaimForInitiation(ctx, tr.ID, models.NWTransferStatusInitiationPending, first, time.Minute)
+	s.Require().NoError(err)
+	s.True(claimed, "the first claim is taken")
+
+	claimed, err = s.repo.ClaimForInitiation(ctx, tr.ID, models.NWTransferStatusInitiationPending, first.Add(30*time.Second), time.Minute)
+	s.Require().NoError(err)
+	s.False(claimed, "a fresh claim keeps other workers off the transfer")
+
+	got, err := s.repo.GetByID(ctx, tr.ID)
+	s.Require().NoError(err)
+	s.Require().NotNil(got.InitiationClaimedAt)
+	s.True(got.InitiationClaimedAt.Equal(first), "expected %v, got %v", first, got.InitiationClaimedAt)
+	s.Equal(tr.Version, got.Version, "claiming must not bump the version")
+
+	claimed, err = s.repo.ClaimForInitiation(ctx, tr.ID, models.NWTransferStatusInitiationPending, first.Add(time.Minute), time.Minute)
+	s.Require().NoError(err)
+	s.True(claimed, "a stale claim is taken over")
+
+	s.Require().NoError(s.repo.ReleaseInitiationClaim(ctx, tr.ID))
+	got, err = s.repo.GetByID(ctx, tr.ID)
+	s.Require().NoError(err)
+	s.Nil(got.InitiationClaimedAt)
+	s.Equal(tr.Version, got.Version, "releasing must not bump the version")
+}
+
+func (s *NorthwindTransferRepositorySuite) TestApplyTransition() {
+	ctx := context.Background()
+	transfer := s.newTransfer(uuid.New(), "REF-TRANSITION")
+	s.Require().NoError(s.repo.Create(ctx, transfer))
+
+	toCompleted := func(tr *models.NorthwindTransfer) *models.NorthwindTransferEvent {
+		if tr.Status == models.NWTransferStatusCompleted {
+			return nil
+		}
+		event := &models.NorthwindTransferEvent{FromStatus: tr.Status, ToStatus: models.NWTransferStatusCompleted, Source: models.NWTransferEventSourcePoller}
+		tr.Status = models.NWTransferStatusCompleted
+		return event
+	}
+
+	updated, event, err := s.repo.ApplyTransition(ctx, transfer.ID, toCompleted)
+	s.Require().NoError(err)
+	s.Require().NotNil(event)
+	s.Equal(models.NWTransferStatusCompleted, updated.Status)
+	s.Equal(transfer.ID, event.TransferID)
+	s.Equal(transfer.Version+1, updated.Version)
+
+	// Re-applying the same status saves nothing
+	again, event, err := s.repo.ApplyTransition(ctx, transfer.ID, toCompleted)
+	s.Require().NoError(err)
+	s.Nil(event)
+	s.Equal(updated.Version, again.Version)
+
+	events, err := s.repo.ListEvents(ctx, transfer.ID)
+	s.Require().NoError(err)
+	s.Require().Len(events, 1)
+	s.Equal(models.NWTransferStatusPending, events[0].FromStatus)
+	s.Equal(models.NWTransferStatusCompleted, events[0].ToStatus)
+
+	_, _, err = s.repo.ApplyTransition(ctx, uuid.New(), toCompleted)
+	s.ErrorIs(err, ErrNorthwindTransferNotFound)
+}
+
+func (s *NorthwindTransferRepositorySuite) TestApplyTransition_RecordsChanges() {
+	ctx := context.Background()
+	transfer := s.newTransfer(uuid.New(), "REF-CHANGES")
+	s.Require().NoError(s.repo.Create(ctx, transfer))
+
+	fee := decimal.RequireFromString("1.25")
+	completed := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	_, event, err := s.repo.ApplyTransition(ctx, transfer.ID, func(tr *models.NorthwindTransfer) *models.NorthwindTransferEvent {
+		event := &models.NorthwindTransferEvent{FromStatus: tr.Status, ToStatus: models.NWTransferStatusCompleted, Source: models.NWTransferEventSourcePoller}
+		tr.Status = models.NWTransferStatusCompleted
+		tr.Fee = &fee
+		tr.CompletedDate = &completed
+		tr.Amount = decimal.RequireFromString("100.00")
+		return event
+	})
+	s.Require().NoError(err)
+	s.Equal(models.NorthwindTransferChanges{
+		"status":         {Old: models.NWTransferStatusPending, New: models.NWTransferStatusCompleted},
+		"fee":            {Old: nil, New: fee},
+		"completed_date": {Old: nil, New: completed},
+	}, event.Changes)
+
+	// The stored diff reads back as JSON values
+	events, err := s.repo.ListEvents(ctx, transfer.ID)
+	s.Require().NoError(err)
+	s.Require().Len(events, 1)
+	s.Equal(models.NorthwindTransferChanges{
+		"status":         {Old: models.NWTransferStatusPending, New: models.NWTransferStatusCompleted},
+		"fee":            {Old: nil, New: "1.25"},
+		"completed_date": {Old: nil, New: "2024-01-15T10:30:00Z"},
+	}, events[0].Changes)
+}
+
+func (s *NorthwindTransferRepositorySuite) TestUpdate_RecordsChanges() {
+	ctx := context.Background()
+	transfer := s.newTransfer(uuid.New(), "REF-UPDATE")
+	s.Require().NoError(s.repo.Create(ctx, transfer))
+
+	// Saving the transfer as it is records nothing
+	s.Require().NoError(s.repo.Update(ctx, transfer))
+	events, err := s.repo.ListEvents(ctx, transfer.ID)
+	s.Require().NoError(err)
+	s.Empty(events)
+
+	errorCode := "R01"
+	transfer.Status = models.NWTransferStatusFailed
+	transfer.ErrorCode = &errorCode
+	transfer.DestinationAccountNumber = "3333333333"
+	s.Require().NoError(s.repo.Update(ctx, transfer))
+
+	events, err = s.repo.ListEvents(ctx, transfer.ID)
+	s.Require().NoError(err)
+	s.Require().Len(events, 1)
+	s.Equal(models.NWTransferEventSourceUpdate, events[0].Source)
+	s.Equal(models.NWTransferStatusPending, events[0].FromStatus)
+	s.Equal(models.NWTransferStatusFailed, events[0].ToStatus)
+	s.Equal(models.NorthwindFieldChange{Old: nil, New: "R01"}, events[0].Changes["error_code"])
+	s.Equal(models.NorthwindFieldChange{Old: models.RedactedChangeValue, New: models.RedactedChangeValue}, events[0].Changes["destination_account_number"],
+		"account numbers are not stored in the clear")
+	s.NotContains(events[0].Changes, "updated_at")
+	s.NotContains(events[0].Changes, "version")
+
+	s.ErrorIs(s.repo.Update(ctx, s.newTransfer(uuid.New(), "REF-MISSING")), ErrNorthwindTransferNotFound)
+}
+
+func (s *NorthwindTransferRepositorySuite) TestUpdate_RejectsUpdatedAtGoingBackwards() {
+	ctx := context.Background()
+	transfer := s.newTransfer(uuid.New(), "REF-SKEW")
+	s.Require().NoError(s.repo.Create(ctx, transfer))
+
+	// A pod whose clock runs a minute behind the one that last updated the transfer
+	behind := func() time.Time { return transfer.UpdatedAt.Add(-time.Minute) }
+	skewed := NewNorthwindTransferRepository(s.db.DB.Session(&gorm.Session{NowFunc: behind}))
+
+	transfer.Status = models.NWTransferStatusProcessing
+	s.ErrorIs(skewed.Update(ctx, transfer), ErrNorthwindTransferUpdatedAtBackwards)
+	_, _, err := skewed.ApplyTransition(ctx, transfer.ID, func(tr *models.NorthwindTransfer) *models.NorthwindTransferEvent {
+		tr.Status = models.NWTransferStatusProcessing
+		return &models.NorthwindTransferEvent{FromStatus: models.NWTransferStatusPending, ToStatus: tr.Status, Source: models.NWTransferEventSourcePoller}
+	})
+	s.ErrorIs(err, ErrNorthwindTransferUpdatedAtBackwards)
+
+	stored, err := s.repo.GetByID(ctx, transfer.ID)
+	s.Require().NoError(err)
+	s.Equal(models.NWTransferStatusPending, stored.Status, "a rejected update changes nothing")
+	s.Equal(1, stored.Version)
+
+	// A clock level with the last update is accepted
+	level := NewNorthwindTransferRepository(s.db.DB.Session(&gorm.Session{NowFunc: func() time.Time { return stored.UpdatedAt }}))
+	s.NoError(level.Update(ctx, transfer))
+}
@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNotificationPreferenceNotFound = errors.New("notification preference not found")
+)
+
+type notificationPreferenceRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationPreferenceRepository creates a new notification preference repository
+func NewNotificationPreferenceRepository(db *gorm.DB) NotificationPreferenceRepositoryInterface {
+	return &notificationPreferenceRepository{db: db}
+}
+
+// Upsert creates the preference or replaces the channels of the user's existing one for the same event type
+func (r *notificationPreferenceRepository) Upsert(ctx context.Context, preference *models.NotificationPreference) error {
+	if preference == nil {
+		return errors.New("preference cannot be nil")
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing models.NotificationPreference
+		err := tx.Where("user_id = ? AND event_type = ?", preference.UserID, preference.EventType).First(&existing).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if err := tx.Create(preference).Error; err != nil {
+				return fmt.Errorf("failed to create notification preference: %w", err)
+			}
+			return nil
+		case err != nil:
+			return fmt.Errorf("failed to get notification preference: %w", err)
+		}
+
+		existing.InApp = preference.InApp
+		existing.Email = preference.Email
+		if err := tx.Save(&existing).Error; err != nil {
+			return fmt.Errorf("failed to update notification preference: %w", err)
+		}
+		*preference = existing
+		return nil
+	})
+}
+
+func (r *notificationPreferenceRepository) Get(ctx context.Context, userID uuid.UUID, eventType string) (*models.NotificationPreference, error) {
+	var preference models.NotificationPreference
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND event_type = ?", userID, eventType).First(&preference).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotificationPreferenceNotFound
+		}
+		return nil, fmt.Errorf("failed to get notification preference: %w", err)
+	}
+	return &preference, nil
+}
+
+func (r *notificationPreferenceRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.NotificationPreference, error) {
+	var preferences []models.NotificationPreference
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("event_type ASC").Find(&preferences).Error; err != nil {
+		return nil, fmt.Errorf("failed to list notification preferences: %w", err)
+	}
+	return preferences, nil
+}
+
+// --- User Notification Repository ---
+
+type userNotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewUserNotificationRepository creates a new user notification repository
+func NewUserNotificationRepository(db *gorm.DB) UserNotificationRepositoryInterface {
+	return &userNotificationRepository{db: db}
+}
+
+func (r *userNotificationRepository) Create(ctx context.Context, notification *models.UserNotification) error {
+	if notification == nil {
+		return errors.New("notification cannot be nil")
+	}
+	if err := r.db.WithContext(ctx).Create(notification).Error; err != nil {
+		return fmt.Errorf("failed to create user notification: %w", err)
+	}
+	return nil
+}
+
+func (r *userNotificationRepository) ListByUser(ctx context.Context, userID uuid.UUID, channel string) ([]models.UserNotification, error) {
+	var notifications []models.UserNotification
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND channel = ?", userID, channel).
+		Order("created_at DESC").
+		Find(&notifications).Error; err != nil {
+		return nil, fmt.Errorf("failed to list user notifications: %w", err)
+	}
+	return notifications, nil
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPersonalAccessTokenNotFound = errors.New("personal access token not found")
+)
+
+type personalAccessTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewPersonalAccessTokenRepository creates a new personal access token repository
+func NewPersonalAccessTokenRepository(db *gorm.DB) PersonalAccessTokenRepositoryInterface {
+	return &personalAccessTokenRepository{db: db}
+}
+
+func (r *personalAccessTokenRepository) Create(ctx context.Context, token *models.PersonalAccessToken) error {
+	if token == nil {
+		return errors.New("personal access token cannot be nil")
+	}
+	if err := r.db.WithContext(ctx).Create(token).Error; err != nil {
+		return fmt.Errorf("failed to create personal access token: %w", err)
+	}
+	return nil
+}
+
+func (r *personalAccessTokenRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.PersonalAccessToken, error) {
+	var token models.PersonalAccessToken
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPersonalAccessTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to get personal access token: %w", err)
+	}
+	return &token, nil
+}
+
+// GetByTokenHash returns the token whose secret hashes to tokenHash, revoked and expired included
+func (r *personalAccessTokenRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.PersonalAccessToken, error) {
+	var token models.PersonalAccessToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPersonalAccessTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to get personal access token by hash: %w", err)
+	}
+	return &token, nil
+}
+
+// ListByUser returns a page of the user's tokens, newest first, and the total number of them
+func (r *personalAccessTokenRepository) ListByUser(ctx context.Context, userID uuid.UUID, offset, limit int) ([]models.PersonalAccessToken, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.PersonalAccessToken{}).Where("user_id = ?", userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count personal access tokens: %w", err)
+	}
+	var tokens []models.PersonalAccessToken
+	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&tokens).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list personal access tokens: %w", err)
+	}
+	return tokens, total, nil
+}
+
+// Revoke marks a token revoked at revokedAt. Revoking an already revoked token keeps its first
+// revocation time.
+func (r *personalAccessTokenRepository) Revoke(ctx context.Context, id uuid.UUID, revokedAt time.Time) error {
+	result := r.db.WithContext(ctx).Model(&models.PersonalAccessToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", revokedAt)
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke personal access token: %w", result.Error)
+	}
+	return nil
+}
+
+// TouchLastUsed records that the token was used at usedAt
+func (r *personalAccessTokenRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	if err := r.db.WithContext(ctx).Model(&models.PersonalAccessToken{}).
+		Where("id = ?", id).
+		Update("last_used_at", usedAt).Error; err != nil {
+		return fmt.Errorf("failed to record personal access token use: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type pollAnomalyRepository struct {
+	db *gorm.DB
+}
+
+// NewPollAnomalyRepository creates a new poll anomaly repository
+func NewPollAnomalyRepository(db *gorm.DB) PollAnomalyRepositoryInterface {
+	return &pollAnomalyRepository{db: db}
+}
+
+// Record stores an anomaly, or counts it on the unresolved anomaly already recorded for the same
+// transfer, reason and reported status, keeping the latest body, request ID and transfer version. anomaly is
+// updated to the stored row.
+func (r *pollAnomalyRepository) Record(ctx context.Context, anomaly *models.PollAnomaly) error {
+	if anomaly == nil {
+		return errors.New("anomaly cannot be nil")
+	}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing models.PollAnomaly
+		err := tx.Where("transfer_id = ? AND reason = ? AND reported_status = ? AND resolved_at IS NULL",
+			anomaly.TransferID, anomaly.Reason, anomaly.ReportedStatus).
+			First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Create(anomaly).Error
+		}
+		if err != nil {
+			return err
+		}
+		existing.RawBody = anomaly.RawBody
+		existing.UpstreamRequestID = anomaly.UpstreamRequestID
+		existing.TransferVersion = anomaly.TransferVersion
+		existing.Occurrences++
+		existing.LastSeenAt = time.Now()
+		if err := tx.Save(&existing).Error; err != nil {
+			return err
+		}
+		*anomaly = existing
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record poll anomaly: %w", err)
+	}
+	return nil
+}
+
+// CountUnresolved returns how many anomalies have not been resolved by a replay
+func (r *pollAnomalyRepository) CountUnresolved(ctx context.Context) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.PollAnomaly{}).Where("resolved_at IS NULL").Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count poll anomalies: %w", err)
+	}
+	return count, nil
+}
+
+// ListUnresolved returns up to limit unresolved anomalies, oldest first
+func (r *pollAnomalyRepository) ListUnresolved(ctx context.Context, limit int) ([]models.PollAnomaly, error) {
+	var anomalies []models.PollAnomaly
+	if err := r.db.WithContext(ctx).Where("resolved_at IS NULL").
+		Order("first_seen_at ASC").
+		Limit(limit).
+		Find(&anomalies).Error; err != nil {
+		return nil, fmt.Errorf("failed to list poll anomalies: %w", err)
+	}
+	return anomalies, nil
+}
+
+// List returns a page of resolved or unresolved anomalies, most recently seen first, and the
+// total number of them
+func (r *pollAnomalyRepository) List(ctx context.Context, resolved bool, offset, limit int) ([]models.PollAnomaly, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.PollAnomaly{})
+	if resolved {
+		query = query.Where("resolved_at IS NOT NULL")
+	} else {
+		query = query.Where("resolved_at IS NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count poll anomalies: %w", err)
+	}
+	var anomalies []models.PollAnomaly
+	if err := query.Order("last_seen_at DESC").Offset(offset).Limit(limit).Find(&anomalies).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list poll anomalies: %w", err)
+	}
+	return anomalies, total, nil
+}
+
+// Resolve marks an anomaly resolved with the outcome of its replay
+func (r *pollAnomalyRepository) Resolve(ctx context.Context, id uuid.UUID, resolution string) error {
+	now := time.Now()
+	if err := r.db.WithContext(ctx).Model(&models.PollAnomaly{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"resolved_at": &now, "resolution": resolution}).Error; err != nil {
+		return fmt.Errorf("failed to resolve poll anomaly: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrWebhookEventAlreadyProcessed = errors.New("webhook event already processed")
+)
+
+type processedWebhookEventRepository struct {
+	db *gorm.DB
+}
+
+// NewProcessedWebhookEventRepository creates a new processed webhook event repository
+func NewProcessedWebhookEventRepository(db *gorm.DB) ProcessedWebhookEventRepositoryInterface {
+	return &processedWebhookEventRepository{db: db}
+}
+
+// Record inserts the event, returning ErrWebhookEventAlreadyProcessed when its event ID has been
+// recorded before. The unique constraint makes the check atomic, so of several concurrent
+// deliveries of one event exactly one records it.
+func (r *processedWebhookEventRepository) Record(ctx context.Context, event *models.ProcessedWebhookEvent) error {
+	if event == nil {
+		return errors.New("event cannot be nil")
+	}
+	if err := r.db.WithContext(ctx).Create(event).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) || isDuplicateKeyError(err) {
+			return ErrWebhookEventAlreadyProcessed
+		}
+		return fmt.Errorf("failed to record webhook event: %w", err)
+	}
+	return nil
+}
+
+// SetOutcome records how a recorded event was processed
+func (r *processedWebhookEventRepository) SetOutcome(ctx context.Context, eventID, outcome string) error {
+	if err := r.db.WithContext(ctx).Model(&models.ProcessedWebhookEvent{}).
+		Where("event_id = ?", eventID).
+		Update("outcome", outcome).Error; err != nil {
+		return fmt.Errorf("failed to set webhook event outcome: %w", err)
+	}
+	return nil
+}
+
+// Delete forgets an event, so its next delivery is processed again
+func (r *processedWebhookEventRepository) Delete(ctx context.Context, eventID string) error {
+	if err := r.db.WithContext(ctx).Where("event_id = ?", eventID).Delete(&models.ProcessedWebhookEvent{}).Error; err != nil {
+		return fmt.Errorf("failed to delete webhook event: %w", err)
+	}
+	return nil
+}
+
+// PruneBefore deletes events received before cutoff and returns how many were deleted
+func (r *processedWebhookEventRepository) PruneBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("received_at < ?", cutoff).Delete(&models.ProcessedWebhookEvent{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to prune webhook events: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
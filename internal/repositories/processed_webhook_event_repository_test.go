@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/database"
+	"github.com/array/banking-api/internal/models"
+	"github.com/stretchr/testify/suite"
+)
+
+// ProcessedWebhookEventRepositorySuite defines the test suite for ProcessedWebhookEventRepository
+type ProcessedWebhookEventRepositorySuite struct {
+	suite.Suite
+	db   *database.DB
+	repo ProcessedWebhookEventRepositoryInterface
+}
+
+// SetupTest runs before each test in the suite
+func (s *ProcessedWebhookEventRepositorySuite) SetupTest() {
+	s.db = database.SetupTestDB(s.T())
+	s.Require().NoError(s.db.DB.AutoMigrate(&models.ProcessedWebhookEvent{}))
+	// One connection, so concurrent callers share the in-memory database
+	sqlDB, err := s.db.DB.DB()
+	s.Require().NoError(err)
+	sqlDB.SetMaxOpenConns(1)
+	s.repo = NewProcessedWebhookEventRepository(s.db.DB)
+}
+
+// TearDownTest runs after each test in the suite
+func (s *ProcessedWebhookEventRepositorySuite) TearDownTest() {
+	database.CleanupTestDB(s.T(), s.db)
+}
+
+// TestProcessedWebhookEventRepositorySuite runs the test suite
+func TestProcessedWebhookEventRepositorySuite(t *testing.T) {
+	suite.Run(t, new(ProcessedWebhookEventRepositorySuite))
+}
+
+func (s *ProcessedWebhookEventRepositorySuite) TestRecord_ConcurrentDuplicatesRecordOnce() {
+	const deliveries = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, deliveries)
+	start := make(chan struct{})
+	for i := 0; i < deliveries; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			errs <- s.repo.Record(context.Background(), &models.ProcessedWebhookEvent{EventID: "evt-1", Source: models.WebhookSourceNorthwind})
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	recorded := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			recorded++
+		case !errors.Is(err, ErrWebhookEventAlreadyProcessed):
+			s.Failf("unexpected error", "%v", err)
+		}
+	}
+	s.Equal(1, recorded)
+
+	var stored []models.ProcessedWebhookEvent
+	s.Require().NoError(s.db.DB.Find(&stored).Error)
+	s.Require().Len(stored, 1)
+	s.Equal(models.WebhookEventOutcomeProcessing, stored[0].Outcome)
+}
+
+func (s *ProcessedWebhookEventRepositorySuite) TestSetOutcomeAndDelete() {
+	ctx := context.Background()
+	s.Require().NoError(s.repo.Record(ctx, &models.ProcessedWebhookEvent{EventID: "evt-1", Source: models.WebhookSourceNorthwind}))
+	s.Require().NoError(s.repo.SetOutcome(ctx, "evt-1", models.WebhookEventOutcomeApplied))
+
+	var stored models.ProcessedWebhookEvent
+	s.Require().NoError(s.db.DB.Where("event_id = ?", "evt-1").First(&stored).Error)
+	s.Equal(models.WebhookEventOutcomeApplied, stored.Outcome)
+
+	s.Require().NoError(s.repo.Delete(ctx, "evt-1"))
+	s.NoError(s.repo.Record(ctx, &models.ProcessedWebhookEvent{EventID: "evt-1", Source: models.WebhookSourceNorthwind}),
+		"a forgotten event can be recorded again")
+}
+
+func (s *ProcessedWebhookEventRepositorySuite) TestPruneBefore() {
+	ctx := context.Background()
+	now := time.Now()
+	s.Require().NoError(s.repo.Record(ctx, &models.ProcessedWebhookEvent{EventID: "evt-old", Source: models.WebhookSourceNorthwind, ReceivedAt: now.Add(-31 * 24 * time.Hour)}))
+	s.Require().NoError(s.repo.Record(ctx, &models.ProcessedWebhookEvent{EventID: "evt-new", Source: models.WebhookSourceNorthwind, ReceivedAt: now.Add(-time.Hour)}))
+
+	pruned, err := s.repo.PruneBefore(ctx, now.Add(-30*24*time.Hour))
+	s.Require().NoError(err)
+	s.Equal(int64(1), pruned)
+
+	s.NoError(s.repo.Record(ctx, &models.ProcessedWebhookEvent{EventID: "evt-old", Source: models.WebhookSourceNorthwind}))
+	s.ErrorIs(s.repo.Record(ctx, &models.ProcessedWebhookEvent{EventID: "evt-new", Source: models.WebhookSourceNorthwind}), ErrWebhookEventAlreadyProcessed)
+}
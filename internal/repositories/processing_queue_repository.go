@@ -68,6 +68,24 @@ func (r *processingQueueRepository) MarkProcessing(queueItemID uuid.UUID) error
 	return nil
 }
 
+// ResetStaleProcessing returns items marked processing at or before staleBefore to pending, due
+// now. A reset item no longer matches, so concurrent callers reset each item once; it returns the
+// number reset.
+func (r *processingQueueRepository) ResetStaleProcessing(staleBefore time.Time) (int64, error) {
+	result := r.db.Model(&models.ProcessingQueueItem{}).
+		Where("status = ? AND updated_at <= ?", models.QueueStatusProcessing, staleBefore).
+		Updates(map[string]interface{}{
+			"status":       models.QueueStatusPending,
+			"scheduled_at": time.Now(),
+		})
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to reset stale processing items: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
 func (r *processingQueueRepository) MarkCompleted(queueItemID uuid.UUID) error {
 	now := time.Now()
 	result := r.db.Model(&models.ProcessingQueueItem{ID: queueItemID}).
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/array/banking-api/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrReadOnlyModeNotFound = errors.New("read-only mode has not been set")
+)
+
+type readOnlyModeRepository struct {
+	db *gorm.DB
+}
+
+// NewReadOnlyModeRepository creates a new read-only mode repository
+func NewReadOnlyModeRepository(db *gorm.DB) ReadOnlyModeRepositoryInterface {
+	return &readOnlyModeRepository{db: db}
+}
+
+// Get returns the stored switch, or ErrReadOnlyModeNotFound if it was never set
+func (r *readOnlyModeRepository) Get(ctx context.Context) (*models.ReadOnlyMode, error) {
+	var mode models.ReadOnlyMode
+	if err := r.db.WithContext(ctx).First(&mode, "id = ?", models.ReadOnlyModeID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReadOnlyModeNotFound
+		}
+		return nil, fmt.Errorf("failed to get read-only mode: %w", err)
+	}
+	return &mode, nil
+}
+
+// Save stores the switch, replacing the previous one
+func (r *readOnlyModeRepository) Save(ctx context.Context, mode *models.ReadOnlyMode) error {
+	if mode == nil {
+		return errors.New("mode cannot be nil")
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing models.ReadOnlyMode
+		err := tx.First(&existing, "id = ?", models.ReadOnlyModeID).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if err := tx.Create(mode).Error; err != nil {
+				return fmt.Errorf("failed to create read-only mode: %w", err)
+			}
+			return nil
+		case err != nil:
+			return fmt.Errorf("failed to get read-only mode: %w", err)
+		}
+
+		existing.Enabled = mode.Enabled
+		existing.Reason = mode.Reason
+		existing.ExpectedEnd = mode.ExpectedEnd
+		existing.UpdatedBy = mode.UpdatedBy
+		if err := tx.Save(&existing).Error; err != nil {
+			return fmt.Errorf("failed to update read-only mode: %w", err)
+		}
+		*mode = existing
+		return nil
+	})
+}
@@ -1,8 +1,10 @@
 package repositories
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/array/banking-api/internal/models"
@@ -23,32 +25,32 @@ func NewRegulatorNotificationRepository(db *gorm.DB) RegulatorNotificationReposi
 	return &regulatorNotificationRepository{db: db}
 }
 
-func (r *regulatorNotificationRepository) Create(notification *models.RegulatorNotification) error {
+func (r *regulatorNotificationRepository) Create(ctx context.Context, notification *models.RegulatorNotification) error {
 	if notification == nil {
 		return errors.New("notification cannot be nil")
 	}
-	if err := r.db.Create(notification).Error; err != nil {
+	if err := r.db.WithContext(ctx).Create(notification).Error; err != nil {
 		if isDuplicateKeyError(err) {
-			return fmt.Errorf("notification already exists for this transfer and status: %w", err)
+			return fmt.Errorf("notification already exists for this transfer and status or event: %w", err)
 		}
 		return fmt.Errorf("failed to create regulator notification: %w", err)
 	}
 	return nil
 }
 
-func (r *regulatorNotificationRepository) Update(notification *models.RegulatorNotification) error {
+func (r *regulatorNotificationRepository) Update(ctx context.Context, notification *models.RegulatorNotification) error {
 	if notification == nil {
 		return errors.New("notification cannot be nil")
 	}
-	if err := r.db.Save(notification).Error; err != nil {
+	if err := r.db.WithContext(ctx).Save(notification).Error; err != nil {
 		return fmt.Errorf("failed to update regulator notification: %w", err)
 	}
 	return nil
 }
 
-func (r *regulatorNotificationRepository) GetByID(id uuid.UUID) (*models.RegulatorNotification, error) {
+func (r *regulatorNotificationRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.RegulatorNotification, error) {
 	var notification models.RegulatorNotification
-	if err := r.db.Where("id = ?", id).First(&notification).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&notification).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrRegulatorNotificationNotFound
 		}
@@ -57,10 +59,32 @@ func (r *regulatorNotificationRepository) GetByID(id uuid.UUID) (*models.Regulat
 	return &notification, nil
 }
 
-func (r *regulatorNotificationRepository) GetPendingNotifications(limit int) ([]models.RegulatorNotification, error) {
+func (r *regulatorNotificationRepository) GetByEventID(ctx context.Context, eventID string) (*models.RegulatorNotification, error) {
+	var notification models.RegulatorNotification
+	if err := r.db.WithContext(ctx).Where("event_id = ?", eventID).First(&notification).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRegulatorNotificationNotFound
+		}
+		return nil, fmt.Errorf("failed to get regulator notification by event ID: %w", err)
+	}
+	return &notification, nil
+}
+
+func (r *regulatorNotificationRepository) GetByTransferAndStatus(ctx context.Context, transferID uuid.UUID, terminalStatus string) (*models.RegulatorNotification, error) {
+	var notification models.RegulatorNotification
+	if err := r.db.WithContext(ctx).Where("transfer_id = ? AND terminal_status = ?", transferID, terminalStatus).First(&notification).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRegulatorNotificationNotFound
+		}
+		return nil, fmt.Errorf("failed to get regulator notification: %w", err)
+	}
+	return &notification, nil
+}
+
+func (r *regulatorNotificationRepository) GetPendingNotifications(ctx context.Context, limit int) ([]models.RegulatorNotification, error) {
 	var notifications []models.RegulatorNotification
 	now := time.Now()
-	if err := r.db.Where("delivered = ? AND (next_attempt_at IS NULL OR next_attempt_at <= ?)", false, now).
+	if err := r.db.WithContext(ctx).Where("delivered = ? AND (next_attempt_at IS NULL OR next_attempt_at <= ?)", false, now).
 		Order("created_at ASC").
 		Limit(limit).
 		Find(&notifications).Error; err != nil {
@@ -69,9 +93,25 @@ func (r *regulatorNotificationRepository) GetPendingNotifications(limit int) ([]
 	return notifications, nil
 }
 
-func (r *regulatorNotificationRepository) ExistsForTransferAndStatus(transferID uuid.UUID, terminalStatus string) (bool, error) {
+// ReleaseStaleLeases makes due now every undelivered, never-attempted notification whose queue
+// lease, taken when it was created at or before leasedBefore, still holds it out of the retry
+// loop. A released row is stamped updated_at now and no longer matches, so concurrent callers
+// release each notification once even when their clocks differ by less than the lease; it
+// returns the number released.
+func (r *regulatorNotificationRepository) ReleaseStaleLeases(ctx context.Context, leasedBefore, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.RegulatorNotification{}).
+		Where("delivered = ? AND attempt_count = 0 AND next_attempt_at > ? AND created_at <= ? AND updated_at <= ?",
+			false, now, leasedBefore, leasedBefore).
+		Updates(map[string]interface{}{"next_attempt_at": now, "updated_at": now})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to release stale regulator notification leases: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *regulatorNotificationRepository) ExistsForTransferAndStatus(ctx context.Context, transferID uuid.UUID, terminalStatus string) (bool, error) {
 	var count int64
-	if err := r.db.Model(&models.RegulatorNotification{}).
+	if err := r.db.WithContext(ctx).Model(&models.RegulatorNotification{}).
 		Where("transfer_id = ? AND terminal_status = ?", transferID, terminalStatus).
 		Count(&count).Error; err != nil {
 		return false, fmt.Errorf("failed to check regulator notification existence: %w", err)
@@ -79,6 +119,53 @@ func (r *regulatorNotificationRepository) ExistsForTransferAndStatus(transferID
 	return count > 0, nil
 }
 
+// ListByTransferIDs returns the notifications of the given transfers, oldest first
+func (r *regulatorNotificationRepository) ListByTransferIDs(ctx context.Context, transferIDs []uuid.UUID) ([]models.RegulatorNotification, error) {
+	var notifications []models.RegulatorNotification
+	if len(transferIDs) == 0 {
+		return notifications, nil
+	}
+	if err := r.db.WithContext(ctx).Where("transfer_id IN ?", transferIDs).
+		Order("created_at ASC").
+		Find(&notifications).Error; err != nil {
+		return nil, fmt.Errorf("failed to list regulator notifications by transfer: %w", err)
+	}
+	return notifications, nil
+}
+
+// GetDeliveryStats counts undelivered notifications, and those among them created before
+// abandonedBefore, and measures the delivery SLA over notifications created between slaFrom and
+// sla ago: each counts as met when it was delivered within sla of its creation. A delivered
+// notification's last attempt is the one that delivered it.
+func (r *regulatorNotificationRepository) GetDeliveryStats(ctx context.Context, abandonedBefore, slaFrom time.Time, sla time.Duration) (*models.RegulatorDeliveryStats, error) {
+	var stats models.RegulatorDeliveryStats
+	if err := r.db.WithContext(ctx).Model(&models.RegulatorNotification{}).
+		Select("COUNT(*) AS pending, COUNT(CASE WHEN created_at < ? THEN 1 END) AS abandoned", abandonedBefore).
+		Where("delivered = ?", false).
+		Scan(&stats).Error; err != nil {
+		return nil, fmt.Errorf("failed to count undelivered regulator notifications: %w", err)
+	}
+
+	var rows []struct {
+		Delivered     bool
+		CreatedAt     time.Time
+		LastAttemptAt *time.Time
+	}
+	if err := r.db.WithContext(ctx).Model(&models.RegulatorNotification{}).
+		Select("delivered, created_at, last_attempt_at").
+		Where("created_at >= ? AND created_at <= ?", slaFrom, time.Now().Add(-sla)).
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load regulator notifications for the delivery SLA: %w", err)
+	}
+	stats.SLAEligible = int64(len(rows))
+	for _, row := range rows {
+		if row.Delivered && row.LastAttemptAt != nil && row.LastAttemptAt.Sub(row.CreatedAt) <= sla {
+			stats.SLAMet++
+		}
+	}
+	return &stats, nil
+}
+
 // --- Notification Attempt Repository ---
 
 type regulatorNotificationAttemptRepository struct {
@@ -90,22 +177,104 @@ func NewRegulatorNotificationAttemptRepository(db *gorm.DB) RegulatorNotificatio
 	return &regulatorNotificationAttemptRepository{db: db}
 }
 
-func (r *regulatorNotificationAttemptRepository) Create(attempt *models.RegulatorNotificationAttempt) error {
+func (r *regulatorNotificationAttemptRepository) Create(ctx context.Context, attempt *models.RegulatorNotificationAttempt) error {
 	if attempt == nil {
 		return errors.New("attempt cannot be nil")
 	}
-	if err := r.db.Create(attempt).Error; err != nil {
+	if err := r.db.WithContext(ctx).Create(attempt).Error; err != nil {
 		return fmt.Errorf("failed to create notification attempt: %w", err)
 	}
 	return nil
 }
 
-func (r *regulatorNotificationAttemptRepository) GetByNotificationID(notificationID uuid.UUID) ([]models.RegulatorNotificationAttempt, error) {
+func (r *regulatorNotificationAttemptRepository) GetByNotificationID(ctx context.Context, notificationID uuid.UUID) ([]models.RegulatorNotificationAttempt, error) {
 	var attempts []models.RegulatorNotificationAttempt
-	if err := r.db.Where("notification_id = ?", notificationID).
+	if err := r.db.WithContext(ctx).Where("notification_id = ?", notificationID).
 		Order("attempted_at ASC").
 		Find(&attempts).Error; err != nil {
 		return nil, fmt.Errorf("failed to get notification attempts: %w", err)
 	}
 	return attempts, nil
 }
+
+// ListByNotificationIDs returns the attempts of the given notifications, oldest first
+func (r *regulatorNotificationAttemptRepository) ListByNotificationIDs(ctx context.Context, notificationIDs []uuid.UUID) ([]models.RegulatorNotificationAttempt, error) {
+	var attempts []models.RegulatorNotificationAttempt
+	if len(notificationIDs) == 0 {
+		return attempts, nil
+	}
+	if err := r.db.WithContext(ctx).Where("notification_id IN ?", notificationIDs).
+		Order("attempted_at ASC").
+		Find(&attempts).Error; err != nil {
+		return nil, fmt.Errorf("failed to list notification attempts: %w", err)
+	}
+	return attempts, nil
+}
+
+// ListFiltered returns a page of the attempts matching filters, oldest first, and how many match
+// in total
+func (r *regulatorNotificationAttemptRepository) ListFiltered(ctx context.Context, filters models.RegulatorNotificationAttemptFilters, offset, limit int) ([]models.RegulatorNotificationAttempt, int64, error) {
+	query := r.filtered(ctx, filters)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count notification attempts: %w", err)
+	}
+
+	var attempts []models.RegulatorNotificationAttempt
+	if err := query.Order("attempted_at ASC, id ASC").Offset(offset).Limit(limit).Find(&attempts).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list notification attempts: %w", err)
+	}
+	return attempts, total, nil
+}
+
+// StreamFiltered calls fn with each attempt matching filters, oldest first, reading the rows one
+// at a time so an export of a long date range does not load it whole. An error from fn stops the
+// stream and is returned.
+func (r *regulatorNotificationAttemptRepository) StreamFiltered(ctx context.Context, filters models.RegulatorNotificationAttemptFilters, fn func(*models.RegulatorNotificationAttempt) error) error {
+	query := r.filtered(ctx, filters).Order("attempted_at ASC, id ASC")
+	rows, err := query.Rows()
+	if err != nil {
+		return fmt.Errorf("failed to stream notification attempts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var attempt models.RegulatorNotificationAttempt
+		if err := query.ScanRows(rows, &attempt); err != nil {
+			return fmt.Errorf("failed to read notification attempt: %w", err)
+		}
+		if err := fn(&attempt); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to stream notification attempts: %w", err)
+	}
+	return nil
+}
+
+func (r *regulatorNotificationAttemptRepository) filtered(ctx context.Context, filters models.RegulatorNotificationAttemptFilters) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&models.RegulatorNotificationAttempt{})
+	if filters.NotificationID != nil {
+		query = query.Where("notification_id = ?", *filters.NotificationID)
+	}
+	switch filters.Outcome {
+	case models.RegulatorAttemptOutcomeSuccess:
+		query = query.Where("http_status >= 200 AND http_status < 300")
+	case models.RegulatorAttemptOutcomeFailure:
+		query = query.Where("http_status IS NULL OR http_status < 200 OR http_status >= 300")
+	}
+	if len(filters.StatusClass) == 3 && filters.StatusClass[1:] == "xx" {
+		if class, err := strconv.Atoi(filters.StatusClass[:1]); err == nil {
+			query = query.Where("http_status >= ? AND http_status < ?", class*100, (class+1)*100)
+		}
+	}
+	if filters.From != nil {
+		query = query.Where("attempted_at >= ?", *filters.From)
+	}
+	if filters.To != nil {
+		query = query.Where("attempted_at <= ?", *filters.To)
+	}
+	return query
+}
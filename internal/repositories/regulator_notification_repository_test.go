@@ -0,0 +1,201 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/database"
+	"github.com/array/banking-api/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+// RegulatorNotificationRepositorySuite defines the test suite for RegulatorNotificationRepository
+type RegulatorNotificationRepositorySuite struct {
+	suite.Suite
+	db       *database.DB
+	repo     RegulatorNotificationRepositoryInterface
+	attempts RegulatorNotificationAttemptRepositoryInterface
+}
+
+// SetupTest runs before each test in the suite
+func (s *RegulatorNotificationRepositorySuite) SetupTest() {
+	s.db = database.SetupTestDB(s.T())
+	s.Require().NoError(s.db.DB.AutoMigrate(&models.RegulatorNotification{}, &models.RegulatorNotificationAttempt{}))
+	s.repo = NewRegulatorNotificationRepository(s.db.DB)
+	s.attempts = NewRegulatorNotificationAttemptRepository(s.db.DB)
+}
+
+// TearDownTest runs after each test in the suite
+func (s *RegulatorNotificationRepositorySuite) TearDownTest() {
+	database.CleanupTestDB(s.T(), s.db)
+}
+
+// TestRegulatorNotificationRepositorySuite runs the test suite
+func TestRegulatorNotificationRepositorySuite(t *testing.T) {
+	suite.Run(t, new(RegulatorNotificationRepositorySuite))
+}
+
+func (s *RegulatorNotificationRepositorySuite) newNotification(eventID, status string) *models.RegulatorNotification {
+	payload, err := json.Marshal(models.RegulatorWebhookPayload{EventID: eventID, Status: status})
+	s.Require().NoError(err)
+	return &models.RegulatorNotification{
+		TransferID:     uuid.New(),
+		TerminalStatus: status,
+		Payload:        payload,
+	}
+}
+
+func (s *RegulatorNotificationRepositorySuite) TestCreate_StoresPayloadEventID() {
+	notification := s.newNotification("evt-1", models.NWTransferStatusCompleted)
+	s.Require().NoError(s.repo.Create(context.Background(), notification))
+
+	s.Require().NotNil(notification.EventID)
+	s.Equal("evt-1", *notification.EventID)
+}
+
+func (s *RegulatorNotificationRepositorySuite) TestGetByEventID() {
+	ctx := context.Background()
+	notification := s.newNotification("evt-1", models.NWTransferStatusCompleted)
+	s.Require().NoError(s.repo.Create(ctx, notification))
+	s.Require().NoError(s.repo.Create(ctx, s.newNotification("evt-2", models.NWTransferStatusFailed)))
+
+	found, err := s.repo.GetByEventID(ctx, "evt-1")
+	s.Require().NoError(err)
+	s.Equal(notification.ID, found.ID)
+	s.Equal(notification.TransferID, found.TransferID)
+
+	_, err = s.repo.GetByEventID(ctx, "evt-unknown")
+	s.ErrorIs(err, ErrRegulatorNotificationNotFound)
+}
+
+func (s *RegulatorNotificationRepositorySuite) TestCreate_DuplicateEventIDRejected() {
+	ctx := context.Background()
+	s.Require().NoError(s.repo.Create(ctx, s.newNotification("evt-1", models.NWTransferStatusCompleted)))
+
+	err := s.repo.Create(ctx, s.newNotification("evt-1", models.NWTransferStatusFailed))
+	s.Require().Error(err)
+	s.Contains(err.Error(), "already exists")
+}
+
+// addAttempt stores an attempt of notificationID at attemptedAt; a zero status records an
+// attempt that got no response
+func (s *RegulatorNotificationRepositorySuite) addAttempt(notificationID uuid.UUID, attemptedAt time.Time, status int) *models.RegulatorNotificationAttempt {
+	attempt := &models.RegulatorNotificationAttempt{NotificationID: notificationID, AttemptedAt: attemptedAt, TargetURL: "http://regulator/webhook"}
+	if status != 0 {
+		attempt.HTTPStatus = &status
+	}
+	s.Require().NoError(s.attempts.Create(context.Background(), attempt))
+	return attempt
+}
+
+func (s *RegulatorNotificationRepositorySuite) TestAttemptListFiltered() {
+	ctx := context.Background()
+	notificationID, other := uuid.New(), uuid.New()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, status := range []int{0, 500, 503, 429, 302, 200} {
+		s.addAttempt(notificationID, base.Add(time.Duration(i)*time.Minute), status)
+	}
+	s.addAttempt(other, base, 200)
+
+	count := func(filters models.RegulatorNotificationAttemptFilters) int64 {
+		_, total, err := s.attempts.ListFiltered(ctx, filters, 0, 100)
+		s.Require().NoError(err)
+		return total
+	}
+	s.Equal(int64(7), count(models.RegulatorNotificationAttemptFilters{}))
+	s.Equal(int64(6), count(models.RegulatorNotificationAttemptFilters{NotificationID: &notificationID}))
+	s.Equal(int64(5), count(models.RegulatorNotificationAttemptFilters{NotificationID: &notificationID, Outcome: models.RegulatorAttemptOutcomeFailure}), "no response is a failure")
+	s.Equal(int64(2), count(models.RegulatorNotificationAttemptFilters{Outcome: models.RegulatorAttemptOutcomeSuccess}))
+	s.Equal(int64(2), count(models.RegulatorNotificationAttemptFilters{StatusClass: "5xx"}))
+	s.Equal(int64(1), count(models.RegulatorNotificationAttemptFilters{StatusClass: "3xx"}))
+
+	from, to := base.Add(time.Minute), base.Add(3*time.Minute)
+	page, total, err := s.attempts.ListFiltered(ctx, models.RegulatorNotificationAttemptFilters{NotificationID: &notificationID, From: &from, To: &to}, 1, 1)
+	s.Require().NoError(err)
+	s.Equal(int64(3), total, "both bounds are inclusive")
+	s.Require().Len(page, 1)
+	s.Require().NotNil(page[0].HTTPStatus)
+	s.Equal(503, *page[0].HTTPStatus, "pages are oldest first")
+}
+
+func (s *RegulatorNotificationRepositorySuite) TestAttemptStreamFiltered_LargeRange() {
+	ctx := context.Background()
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	const stored = 1500
+	attempts := make([]models.RegulatorNotificationAttempt, stored)
+	for i := range attempts {
+		status := 200
+		attempts[i] = models.RegulatorNotificationAttempt{ID: uuid.New(), NotificationID: uuid.New(), AttemptedAt: base.Add(time.Duration(i) * time.Second), HTTPStatus: &status}
+	}
+	s.Require().NoError(s.db.DB.CreateInBatches(attempts, 500).Error)
+
+	from, to := base, base.Add(stored*time.Second)
+	var streamed int
+	last := time.Time{}
+	err := s.attempts.StreamFiltered(ctx, models.RegulatorNotificationAttemptFilters{From: &from, To: &to}, func(attempt *models.RegulatorNotificationAttempt) error {
+		s.False(attempt.AttemptedAt.Before(last), "attempts stream oldest first")
+		last = attempt.AttemptedAt
+		streamed++
+		return nil
+	})
+	s.Require().NoError(err)
+	s.Equal(stored, streamed)
+
+	stop := errors.New("stop")
+	streamed = 0
+	err = s.attempts.StreamFiltered(ctx, models.RegulatorNotificationAttemptFilters{}, func(*models.RegulatorNotificationAttempt) error {
+		streamed++
+		if streamed == 10 {
+			return stop
+		}
+		return nil
+	})
+	s.ErrorIs(err, stop)
+	s.Equal(10, streamed)
+}
+
+func (s *RegulatorNotificationRepositorySuite) TestReleaseStaleLeases() {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	at := func(offset time.Duration) *time.Time {
+		t := now.Add(offset)
+		return &t
+	}
+	create := func(eventID string, createdAt time.Time, edit func(*models.RegulatorNotification)) *models.RegulatorNotification {
+		notification := s.newNotification(eventID, models.NWTransferStatusCompleted)
+		notification.CreatedAt, notification.UpdatedAt = createdAt, createdAt
+		edit(notification)
+		s.Require().NoError(s.repo.Create(ctx, notification))
+		return notification
+	}
+
+	stale := create("evt-stale", now.Add(-2*time.Minute), func(n *models.RegulatorNotification) { n.NextAttemptAt = at(time.Hour) })
+	fresh := create("evt-fresh", now.Add(-5*time.Second), func(n *models.RegulatorNotification) { n.NextAttemptAt = at(25 * time.Second) })
+	backoff := create("evt-backoff", now.Add(-2*time.Minute), func(n *models.RegulatorNotification) {
+		n.AttemptCount = 1
+		n.NextAttemptAt = at(time.Hour)
+	})
+	due := create("evt-due", now.Add(-2*time.Minute), func(n *models.RegulatorNotification) { n.NextAttemptAt = at(-time.Minute) })
+
+	released, err := s.repo.ReleaseStaleLeases(ctx, now.Add(-30*time.Second), now)
+	s.Require().NoError(err)
+	s.Equal(int64(1), released)
+
+	got, err := s.repo.GetByID(ctx, stale.ID)
+	s.Require().NoError(err)
+	s.Require().NotNil(got.NextAttemptAt)
+	s.True(got.NextAttemptAt.Equal(now), "a released lease is due now")
+	for _, kept := range []*models.RegulatorNotification{fresh, backoff, due} {
+		got, err := s.repo.GetByID(ctx, kept.ID)
+		s.Require().NoError(err)
+		s.True(got.NextAttemptAt.Equal(*kept.NextAttemptAt), "notification %s keeps its schedule", *kept.EventID)
+	}
+
+	released, err = s.repo.ReleaseStaleLeases(ctx, now.Add(-30*time.Second), now)
+	s.Require().NoError(err)
+	s.Zero(released, "a released lease is not released again")
+}
@@ -5,6 +5,7 @@
 package repository_mocks
 
 import (
+	context "context"
 	reflect "reflect"
 	time "time"
 
@@ -357,38 +358,53 @@ func (m *MockTransactionRepositoryInterface) EXPECT() *MockTransactionRepository
 	return m.recorder
 }
 
+// CountRetentionExpired mocks base method.
+func (m *MockTransactionRepositoryInterface) CountRetentionExpired(ctx context.Context, cutoff time.Time) (models.RetentionCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRetentionExpired", ctx, cutoff)
+	ret0, _ := ret[0].(models.RetentionCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRetentionExpired indicates an expected call of CountRetentionExpired.
+func (mr *MockTransactionRepositoryInterfaceMockRecorder) CountRetentionExpired(ctx, cutoff interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRetentionExpired", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).CountRetentionExpired), ctx, cutoff)
+}
+
 // Create mocks base method.
-func (m *MockTransactionRepositoryInterface) Create(transaction *models.Transaction) error {
+func (m *MockTransactionRepositoryInterface) Create(ctx context.Context, transaction *models.Transaction) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", transaction)
+	ret := m.ctrl.Call(m, "Create", ctx, transaction)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockTransactionRepositoryInterfaceMockRecorder) Create(transaction interface{}) *gomock.Call {
+func (mr *MockTransactionRepositoryInterfaceMockRecorder) Create(ctx, transaction interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).Create), transaction)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).Create), ctx, transaction)
 }
 
 // CreateBatch mocks base method.
-func (m *MockTransactionRepositoryInterface) CreateBatch(transactions []models.Transaction) error {
+func (m *MockTransactionRepositoryInterface) CreateBatch(ctx context.Context, transactions []models.Transaction) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateBatch", transactions)
+	ret := m.ctrl.Call(m, "CreateBatch", ctx, transactions)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateBatch indicates an expected call of CreateBatch.
-func (mr *MockTransactionRepositoryInterfaceMockRecorder) CreateBatch(transactions interface{}) *gomock.Call {
+func (mr *MockTransactionRepositoryInterfaceMockRecorder) CreateBatch(ctx, transactions interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).CreateBatch), transactions)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).CreateBatch), ctx, transactions)
 }
 
 // GetByAccountID mocks base method.
-func (m *MockTransactionRepositoryInterface) GetByAccountID(accountID uuid.UUID, offset, limit int) ([]models.Transaction, int64, error) {
+func (m *MockTransactionRepositoryInterface) GetByAccountID(ctx context.Context, accountID uuid.UUID, offset, limit int) ([]models.Transaction, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByAccountID", accountID, offset, limit)
+	ret := m.ctrl.Call(m, "GetByAccountID", ctx, accountID, offset, limit)
 	ret0, _ := ret[0].([]models.Transaction)
 	ret1, _ := ret[1].(int64)
 	ret2, _ := ret[2].(error)
@@ -396,15 +412,30 @@ func (m *MockTransactionRepositoryInterface) GetByAccountID(accountID uuid.UUID,
 }
 
 // GetByAccountID indicates an expected call of GetByAccountID.
-func (mr *MockTransactionRepositoryInterfaceMockRecorder) GetByAccountID(accountID, offset, limit interface{}) *gomock.Call {
+func (mr *MockTransactionRepositoryInterfaceMockRecorder) GetByAccountID(ctx, accountID, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAccountID", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).GetByAccountID), ctx, accountID, offset, limit)
+}
+
+// GetByAccountIDKeyset mocks base method.
+func (m *MockTransactionRepositoryInterface) GetByAccountIDKeyset(ctx context.Context, accountID uuid.UUID, after *models.AccountActivityKeyset, limit int) ([]models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByAccountIDKeyset", ctx, accountID, after, limit)
+	ret0, _ := ret[0].([]models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByAccountIDKeyset indicates an expected call of GetByAccountIDKeyset.
+func (mr *MockTransactionRepositoryInterfaceMockRecorder) GetByAccountIDKeyset(ctx, accountID, after, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAccountID", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).GetByAccountID), accountID, offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAccountIDKeyset", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).GetByAccountIDKeyset), ctx, accountID, after, limit)
 }
 
 // GetByCategory mocks base method.
-func (m *MockTransactionRepositoryInterface) GetByCategory(accountID uuid.UUID, category string, offset, limit int) ([]models.Transaction, int64, error) {
+func (m *MockTransactionRepositoryInterface) GetByCategory(ctx context.Context, accountID uuid.UUID, category string, offset, limit int) ([]models.Transaction, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByCategory", accountID, category, offset, limit)
+	ret := m.ctrl.Call(m, "GetByCategory", ctx, accountID, category, offset, limit)
 	ret0, _ := ret[0].([]models.Transaction)
 	ret1, _ := ret[1].(int64)
 	ret2, _ := ret[2].(error)
@@ -412,120 +443,120 @@ func (m *MockTransactionRepositoryInterface) GetByCategory(accountID uuid.UUID,
 }
 
 // GetByCategory indicates an expected call of GetByCategory.
-func (mr *MockTransactionRepositoryInterfaceMockRecorder) GetByCategory(accountID, category, offset, limit interface{}) *gomock.Call {
+func (mr *MockTransactionRepositoryInterfaceMockRecorder) GetByCategory(ctx, accountID, category, offset, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCategory", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).GetByCategory), accountID, category, offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCategory", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).GetByCategory), ctx, accountID, category, offset, limit)
 }
 
 // GetByDateRange mocks base method.
-func (m *MockTransactionRepositoryInterface) GetByDateRange(accountID uuid.UUID, startDate, endDate time.Time) ([]models.Transaction, error) {
+func (m *MockTransactionRepositoryInterface) GetByDateRange(ctx context.Context, accountID uuid.UUID, startDate, endDate time.Time) ([]models.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByDateRange", accountID, startDate, endDate)
+	ret := m.ctrl.Call(m, "GetByDateRange", ctx, accountID, startDate, endDate)
 	ret0, _ := ret[0].([]models.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByDateRange indicates an expected call of GetByDateRange.
-func (mr *MockTransactionRepositoryInterfaceMockRecorder) GetByDateRange(accountID, startDate, endDate interface{}) *gomock.Call {
+func (mr *MockTransactionRepositoryInterfaceMockRecorder) GetByDateRange(ctx, accountID, startDate, endDate interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByDateRange", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).GetByDateRange), accountID, startDate, endDate)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByDateRange", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).GetByDateRange), ctx, accountID, startDate, endDate)
 }
 
 // GetByID mocks base method.
-func (m *MockTransactionRepositoryInterface) GetByID(id uuid.UUID) (*models.Transaction, error) {
+func (m *MockTransactionRepositoryInterface) GetByID(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", id)
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
 	ret0, _ := ret[0].(*models.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockTransactionRepositoryInterfaceMockRecorder) GetByID(id interface{}) *gomock.Call {
+func (mr *MockTransactionRepositoryInterfaceMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).GetByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).GetByID), ctx, id)
 }
 
 // GetByReference mocks base method.
-func (m *MockTransactionRepositoryInterface) GetByReference(reference string) (*models.Transaction, error) {
+func (m *MockTransactionRepositoryInterface) GetByReference(ctx context.Context, reference string) (*models.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByReference", reference)
+	ret := m.ctrl.Call(m, "GetByReference", ctx, reference)
 	ret0, _ := ret[0].(*models.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByReference indicates an expected call of GetByReference.
-func (mr *MockTransactionRepositoryInterfaceMockRecorder) GetByReference(reference interface{}) *gomock.Call {
+func (mr *MockTransactionRepositoryInterfaceMockRecorder) GetByReference(ctx, reference interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByReference", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).GetByReference), reference)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByReference", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).GetByReference), ctx, reference)
 }
 
 // GetCategorySummary mocks base method.
-func (m *MockTransactionRepositoryInterface) GetCategorySummary(accountID uuid.UUID, startDate, endDate time.Time) ([]models.CategorySummary, error) {
+func (m *MockTransactionRepositoryInterface) GetCategorySummary(ctx context.Context, accountID uuid.UUID, startDate, endDate time.Time) ([]models.CategorySummary, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCategorySummary", accountID, startDate, endDate)
+	ret := m.ctrl.Call(m, "GetCategorySummary", ctx, accountID, startDate, endDate)
 	ret0, _ := ret[0].([]models.CategorySummary)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCategorySummary indicates an expected call of GetCategorySummary.
-func (mr *MockTransactionRepositoryInterfaceMockRecorder) GetCategorySummary(accountID, startDate, endDate interface{}) *gomock.Call {
+func (mr *MockTransactionRepositoryInterfaceMockRecorder) GetCategorySummary(ctx, accountID, startDate, endDate interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategorySummary", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).GetCategorySummary), accountID, startDate, endDate)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategorySummary", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).GetCategorySummary), ctx, accountID, startDate, endDate)
 }
 
 // GetExpiredPendingTransactions mocks base method.
-func (m *MockTransactionRepositoryInterface) GetExpiredPendingTransactions(limit int) ([]models.Transaction, error) {
+func (m *MockTransactionRepositoryInterface) GetExpiredPendingTransactions(ctx context.Context, limit int) ([]models.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetExpiredPendingTransactions", limit)
+	ret := m.ctrl.Call(m, "GetExpiredPendingTransactions", ctx, limit)
 	ret0, _ := ret[0].([]models.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetExpiredPendingTransactions indicates an expected call of GetExpiredPendingTransactions.
-func (mr *MockTransactionRepositoryInterfaceMockRecorder) GetExpiredPendingTransactions(limit interface{}) *gomock.Call {
+func (mr *MockTransactionRepositoryInterfaceMockRecorder) GetExpiredPendingTransactions(ctx, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredPendingTransactions", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).GetExpiredPendingTransactions), limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredPendingTransactions", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).GetExpiredPendingTransactions), ctx, limit)
 }
 
 // GetPendingTransactions mocks base method.
-func (m *MockTransactionRepositoryInterface) GetPendingTransactions(offset, limit int) ([]models.Transaction, error) {
+func (m *MockTransactionRepositoryInterface) GetPendingTransactions(ctx context.Context, offset, limit int) ([]models.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPendingTransactions", offset, limit)
+	ret := m.ctrl.Call(m, "GetPendingTransactions", ctx, offset, limit)
 	ret0, _ := ret[0].([]models.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetPendingTransactions indicates an expected call of GetPendingTransactions.
-func (mr *MockTransactionRepositoryInterfaceMockRecorder) GetPendingTransactions(offset, limit interface{}) *gomock.Call {
+func (mr *MockTransactionRepositoryInterfaceMockRecorder) GetPendingTransactions(ctx, offset, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingTransactions", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).GetPendingTransactions), offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingTransactions", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).GetPendingTransactions), ctx, offset, limit)
 }
 
 // GetRecentByAccountID mocks base method.
-func (m *MockTransactionRepositoryInterface) GetRecentByAccountID(accountID uuid.UUID, limit int) ([]models.Transaction, error) {
+func (m *MockTransactionRepositoryInterface) GetRecentByAccountID(ctx context.Context, accountID uuid.UUID, limit int) ([]models.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRecentByAccountID", accountID, limit)
+	ret := m.ctrl.Call(m, "GetRecentByAccountID", ctx, accountID, limit)
 	ret0, _ := ret[0].([]models.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetRecentByAccountID indicates an expected call of GetRecentByAccountID.
-func (mr *MockTransactionRepositoryInterfaceMockRecorder) GetRecentByAccountID(accountID, limit interface{}) *gomock.Call {
+func (mr *MockTransactionRepositoryInterfaceMockRecorder) GetRecentByAccountID(ctx, accountID, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecentByAccountID", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).GetRecentByAccountID), accountID, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecentByAccountID", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).GetRecentByAccountID), ctx, accountID, limit)
 }
 
 // GetTotalsByAccountID mocks base method.
-func (m *MockTransactionRepositoryInterface) GetTotalsByAccountID(accountID uuid.UUID) (int64, int64, string, string, error) {
+func (m *MockTransactionRepositoryInterface) GetTotalsByAccountID(ctx context.Context, accountID uuid.UUID) (int64, int64, string, string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTotalsByAccountID", accountID)
+	ret := m.ctrl.Call(m, "GetTotalsByAccountID", ctx, accountID)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(int64)
 	ret2, _ := ret[2].(string)
@@ -535,15 +566,15 @@ func (m *MockTransactionRepositoryInterface) GetTotalsByAccountID(accountID uuid
 }
 
 // GetTotalsByAccountID indicates an expected call of GetTotalsByAccountID.
-func (mr *MockTransactionRepositoryInterfaceMockRecorder) GetTotalsByAccountID(accountID interface{}) *gomock.Call {
+func (mr *MockTransactionRepositoryInterfaceMockRecorder) GetTotalsByAccountID(ctx, accountID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotalsByAccountID", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).GetTotalsByAccountID), accountID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTotalsByAccountID", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).GetTotalsByAccountID), ctx, accountID)
 }
 
 // GetWithFilters mocks base method.
-func (m *MockTransactionRepositoryInterface) GetWithFilters(filters models.TransactionFilters) ([]models.Transaction, int64, error) {
+func (m *MockTransactionRepositoryInterface) GetWithFilters(ctx context.Context, filters models.TransactionFilters) ([]models.Transaction, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetWithFilters", filters)
+	ret := m.ctrl.Call(m, "GetWithFilters", ctx, filters)
 	ret0, _ := ret[0].([]models.Transaction)
 	ret1, _ := ret[1].(int64)
 	ret2, _ := ret[2].(error)
@@ -551,37 +582,52 @@ func (m *MockTransactionRepositoryInterface) GetWithFilters(filters models.Trans
 }
 
 // GetWithFilters indicates an expected call of GetWithFilters.
-func (mr *MockTransactionRepositoryInterfaceMockRecorder) GetWithFilters(filters interface{}) *gomock.Call {
+func (mr *MockTransactionRepositoryInterfaceMockRecorder) GetWithFilters(ctx, filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWithFilters", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).GetWithFilters), ctx, filters)
+}
+
+// PurgeRetentionExpired mocks base method.
+func (m *MockTransactionRepositoryInterface) PurgeRetentionExpired(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeRetentionExpired", ctx, cutoff, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeRetentionExpired indicates an expected call of PurgeRetentionExpired.
+func (mr *MockTransactionRepositoryInterfaceMockRecorder) PurgeRetentionExpired(ctx, cutoff, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWithFilters", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).GetWithFilters), filters)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeRetentionExpired", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).PurgeRetentionExpired), ctx, cutoff, limit)
 }
 
 // UpdateStatus mocks base method.
-func (m *MockTransactionRepositoryInterface) UpdateStatus(id uuid.UUID, status string) error {
+func (m *MockTransactionRepositoryInterface) UpdateStatus(ctx context.Context, id uuid.UUID, status string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateStatus", id, status)
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, id, status)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateStatus indicates an expected call of UpdateStatus.
-func (mr *MockTransactionRepositoryInterfaceMockRecorder) UpdateStatus(id, status interface{}) *gomock.Call {
+func (mr *MockTransactionRepositoryInterfaceMockRecorder) UpdateStatus(ctx, id, status interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).UpdateStatus), id, status)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).UpdateStatus), ctx, id, status)
 }
 
 // UpdateWithOptimisticLock mocks base method.
-func (m *MockTransactionRepositoryInterface) UpdateWithOptimisticLock(transaction *models.Transaction, expectedVersion int) error {
+func (m *MockTransactionRepositoryInterface) UpdateWithOptimisticLock(ctx context.Context, transaction *models.Transaction, expectedVersion int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateWithOptimisticLock", transaction, expectedVersion)
+	ret := m.ctrl.Call(m, "UpdateWithOptimisticLock", ctx, transaction, expectedVersion)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateWithOptimisticLock indicates an expected call of UpdateWithOptimisticLock.
-func (mr *MockTransactionRepositoryInterfaceMockRecorder) UpdateWithOptimisticLock(transaction, expectedVersion interface{}) *gomock.Call {
+func (mr *MockTransactionRepositoryInterfaceMockRecorder) UpdateWithOptimisticLock(ctx, transaction, expectedVersion interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWithOptimisticLock", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).UpdateWithOptimisticLock), transaction, expectedVersion)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWithOptimisticLock", reflect.TypeOf((*MockTransactionRepositoryInterface)(nil).UpdateWithOptimisticLock), ctx, transaction, expectedVersion)
 }
 
 // MockUserRepositoryInterface is a mock of UserRepositoryInterface interface.
@@ -608,112 +654,112 @@ func (m *MockUserRepositoryInterface) EXPECT() *MockUserRepositoryInterfaceMockR
 }
 
 // CountAccountsByUserID mocks base method.
-func (m *MockUserRepositoryInterface) CountAccountsByUserID(userID uuid.UUID) (int64, error) {
+func (m *MockUserRepositoryInterface) CountAccountsByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CountAccountsByUserID", userID)
+	ret := m.ctrl.Call(m, "CountAccountsByUserID", ctx, userID)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CountAccountsByUserID indicates an expected call of CountAccountsByUserID.
-func (mr *MockUserRepositoryInterfaceMockRecorder) CountAccountsByUserID(userID interface{}) *gomock.Call {
+func (mr *MockUserRepositoryInterfaceMockRecorder) CountAccountsByUserID(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAccountsByUserID", reflect.TypeOf((*MockUserRepositoryInterface)(nil).CountAccountsByUserID), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAccountsByUserID", reflect.TypeOf((*MockUserRepositoryInterface)(nil).CountAccountsByUserID), ctx, userID)
 }
 
 // Create mocks base method.
-func (m *MockUserRepositoryInterface) Create(user *models.User) error {
+func (m *MockUserRepositoryInterface) Create(ctx context.Context, user *models.User) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", user)
+	ret := m.ctrl.Call(m, "Create", ctx, user)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockUserRepositoryInterfaceMockRecorder) Create(user interface{}) *gomock.Call {
+func (mr *MockUserRepositoryInterfaceMockRecorder) Create(ctx, user interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserRepositoryInterface)(nil).Create), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserRepositoryInterface)(nil).Create), ctx, user)
 }
 
 // Delete mocks base method.
-func (m *MockUserRepositoryInterface) Delete(userID uuid.UUID) error {
+func (m *MockUserRepositoryInterface) Delete(ctx context.Context, userID uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", userID)
+	ret := m.ctrl.Call(m, "Delete", ctx, userID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockUserRepositoryInterfaceMockRecorder) Delete(userID interface{}) *gomock.Call {
+func (mr *MockUserRepositoryInterfaceMockRecorder) Delete(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserRepositoryInterface)(nil).Delete), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserRepositoryInterface)(nil).Delete), ctx, userID)
 }
 
 // GetByEmail mocks base method.
-func (m *MockUserRepositoryInterface) GetByEmail(email string) (*models.User, error) {
+func (m *MockUserRepositoryInterface) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByEmail", email)
+	ret := m.ctrl.Call(m, "GetByEmail", ctx, email)
 	ret0, _ := ret[0].(*models.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByEmail indicates an expected call of GetByEmail.
-func (mr *MockUserRepositoryInterfaceMockRecorder) GetByEmail(email interface{}) *gomock.Call {
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetByEmail(ctx, email interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmail", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetByEmail), email)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmail", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetByEmail), ctx, email)
 }
 
 // GetByEmailExcluding mocks base method.
-func (m *MockUserRepositoryInterface) GetByEmailExcluding(email string, excludeUserID uuid.UUID) (*models.User, error) {
+func (m *MockUserRepositoryInterface) GetByEmailExcluding(ctx context.Context, email string, excludeUserID uuid.UUID) (*models.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByEmailExcluding", email, excludeUserID)
+	ret := m.ctrl.Call(m, "GetByEmailExcluding", ctx, email, excludeUserID)
 	ret0, _ := ret[0].(*models.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByEmailExcluding indicates an expected call of GetByEmailExcluding.
-func (mr *MockUserRepositoryInterfaceMockRecorder) GetByEmailExcluding(email, excludeUserID interface{}) *gomock.Call {
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetByEmailExcluding(ctx, email, excludeUserID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmailExcluding", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetByEmailExcluding), email, excludeUserID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmailExcluding", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetByEmailExcluding), ctx, email, excludeUserID)
 }
 
 // GetByID mocks base method.
-func (m *MockUserRepositoryInterface) GetByID(id uuid.UUID) (*models.User, error) {
+func (m *MockUserRepositoryInterface) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", id)
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
 	ret0, _ := ret[0].(*models.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockUserRepositoryInterfaceMockRecorder) GetByID(id interface{}) *gomock.Call {
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetByID), ctx, id)
 }
 
 // GetByIDActive mocks base method.
-func (m *MockUserRepositoryInterface) GetByIDActive(id uuid.UUID) (*models.User, error) {
+func (m *MockUserRepositoryInterface) GetByIDActive(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByIDActive", id)
+	ret := m.ctrl.Call(m, "GetByIDActive", ctx, id)
 	ret0, _ := ret[0].(*models.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByIDActive indicates an expected call of GetByIDActive.
-func (mr *MockUserRepositoryInterfaceMockRecorder) GetByIDActive(id interface{}) *gomock.Call {
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetByIDActive(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDActive", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetByIDActive), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDActive", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetByIDActive), ctx, id)
 }
 
 // ListUsers mocks base method.
-func (m *MockUserRepositoryInterface) ListUsers(offset, limit int) ([]*models.User, int64, error) {
+func (m *MockUserRepositoryInterface) ListUsers(ctx context.Context, offset, limit int) ([]*models.User, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListUsers", offset, limit)
+	ret := m.ctrl.Call(m, "ListUsers", ctx, offset, limit)
 	ret0, _ := ret[0].([]*models.User)
 	ret1, _ := ret[1].(int64)
 	ret2, _ := ret[2].(error)
@@ -721,29 +767,29 @@ func (m *MockUserRepositoryInterface) ListUsers(offset, limit int) ([]*models.Us
 }
 
 // ListUsers indicates an expected call of ListUsers.
-func (mr *MockUserRepositoryInterfaceMockRecorder) ListUsers(offset, limit interface{}) *gomock.Call {
+func (mr *MockUserRepositoryInterfaceMockRecorder) ListUsers(ctx, offset, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockUserRepositoryInterface)(nil).ListUsers), offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockUserRepositoryInterface)(nil).ListUsers), ctx, offset, limit)
 }
 
 // ResetFailedLoginAttempts mocks base method.
-func (m *MockUserRepositoryInterface) ResetFailedLoginAttempts(userID uuid.UUID) error {
+func (m *MockUserRepositoryInterface) ResetFailedLoginAttempts(ctx context.Context, userID uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ResetFailedLoginAttempts", userID)
+	ret := m.ctrl.Call(m, "ResetFailedLoginAttempts", ctx, userID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // ResetFailedLoginAttempts indicates an expected call of ResetFailedLoginAttempts.
-func (mr *MockUserRepositoryInterfaceMockRecorder) ResetFailedLoginAttempts(userID interface{}) *gomock.Call {
+func (mr *MockUserRepositoryInterfaceMockRecorder) ResetFailedLoginAttempts(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetFailedLoginAttempts", reflect.TypeOf((*MockUserRepositoryInterface)(nil).ResetFailedLoginAttempts), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetFailedLoginAttempts", reflect.TypeOf((*MockUserRepositoryInterface)(nil).ResetFailedLoginAttempts), ctx, userID)
 }
 
 // SearchUsers mocks base method.
-func (m *MockUserRepositoryInterface) SearchUsers(criteria repositories.UserSearchCriteria, offset, limit int) ([]*models.User, int64, error) {
+func (m *MockUserRepositoryInterface) SearchUsers(ctx context.Context, criteria repositories.UserSearchCriteria, offset, limit int) ([]*models.User, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SearchUsers", criteria, offset, limit)
+	ret := m.ctrl.Call(m, "SearchUsers", ctx, criteria, offset, limit)
 	ret0, _ := ret[0].([]*models.User)
 	ret1, _ := ret[1].(int64)
 	ret2, _ := ret[2].(error)
@@ -751,93 +797,93 @@ func (m *MockUserRepositoryInterface) SearchUsers(criteria repositories.UserSear
 }
 
 // SearchUsers indicates an expected call of SearchUsers.
-func (mr *MockUserRepositoryInterfaceMockRecorder) SearchUsers(criteria, offset, limit interface{}) *gomock.Call {
+func (mr *MockUserRepositoryInterfaceMockRecorder) SearchUsers(ctx, criteria, offset, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchUsers", reflect.TypeOf((*MockUserRepositoryInterface)(nil).SearchUsers), criteria, offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchUsers", reflect.TypeOf((*MockUserRepositoryInterface)(nil).SearchUsers), ctx, criteria, offset, limit)
 }
 
 // UnlockAccount mocks base method.
-func (m *MockUserRepositoryInterface) UnlockAccount(userID uuid.UUID) error {
+func (m *MockUserRepositoryInterface) UnlockAccount(ctx context.Context, userID uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UnlockAccount", userID)
+	ret := m.ctrl.Call(m, "UnlockAccount", ctx, userID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UnlockAccount indicates an expected call of UnlockAccount.
-func (mr *MockUserRepositoryInterfaceMockRecorder) UnlockAccount(userID interface{}) *gomock.Call {
+func (mr *MockUserRepositoryInterfaceMockRecorder) UnlockAccount(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockAccount", reflect.TypeOf((*MockUserRepositoryInterface)(nil).UnlockAccount), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockAccount", reflect.TypeOf((*MockUserRepositoryInterface)(nil).UnlockAccount), ctx, userID)
 }
 
 // Update mocks base method.
-func (m *MockUserRepositoryInterface) Update(user *models.User) error {
+func (m *MockUserRepositoryInterface) Update(ctx context.Context, user *models.User) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", user)
+	ret := m.ctrl.Call(m, "Update", ctx, user)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockUserRepositoryInterfaceMockRecorder) Update(user interface{}) *gomock.Call {
+func (mr *MockUserRepositoryInterfaceMockRecorder) Update(ctx, user interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserRepositoryInterface)(nil).Update), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockUserRepositoryInterface)(nil).Update), ctx, user)
 }
 
 // UpdateEmail mocks base method.
-func (m *MockUserRepositoryInterface) UpdateEmail(userID uuid.UUID, newEmail string) error {
+func (m *MockUserRepositoryInterface) UpdateEmail(ctx context.Context, userID uuid.UUID, newEmail string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateEmail", userID, newEmail)
+	ret := m.ctrl.Call(m, "UpdateEmail", ctx, userID, newEmail)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateEmail indicates an expected call of UpdateEmail.
-func (mr *MockUserRepositoryInterfaceMockRecorder) UpdateEmail(userID, newEmail interface{}) *gomock.Call {
+func (mr *MockUserRepositoryInterfaceMockRecorder) UpdateEmail(ctx, userID, newEmail interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEmail", reflect.TypeOf((*MockUserRepositoryInterface)(nil).UpdateEmail), userID, newEmail)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEmail", reflect.TypeOf((*MockUserRepositoryInterface)(nil).UpdateEmail), ctx, userID, newEmail)
 }
 
 // UpdateFailedLoginAttempts mocks base method.
-func (m *MockUserRepositoryInterface) UpdateFailedLoginAttempts(user *models.User) error {
+func (m *MockUserRepositoryInterface) UpdateFailedLoginAttempts(ctx context.Context, user *models.User) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateFailedLoginAttempts", user)
+	ret := m.ctrl.Call(m, "UpdateFailedLoginAttempts", ctx, user)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateFailedLoginAttempts indicates an expected call of UpdateFailedLoginAttempts.
-func (mr *MockUserRepositoryInterfaceMockRecorder) UpdateFailedLoginAttempts(user interface{}) *gomock.Call {
+func (mr *MockUserRepositoryInterfaceMockRecorder) UpdateFailedLoginAttempts(ctx, user interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFailedLoginAttempts", reflect.TypeOf((*MockUserRepositoryInterface)(nil).UpdateFailedLoginAttempts), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFailedLoginAttempts", reflect.TypeOf((*MockUserRepositoryInterface)(nil).UpdateFailedLoginAttempts), ctx, user)
 }
 
 // UpdateFields mocks base method.
-func (m *MockUserRepositoryInterface) UpdateFields(userID uuid.UUID, fields map[string]interface{}) error {
+func (m *MockUserRepositoryInterface) UpdateFields(ctx context.Context, userID uuid.UUID, fields map[string]interface{}) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateFields", userID, fields)
+	ret := m.ctrl.Call(m, "UpdateFields", ctx, userID, fields)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateFields indicates an expected call of UpdateFields.
-func (mr *MockUserRepositoryInterfaceMockRecorder) UpdateFields(userID, fields interface{}) *gomock.Call {
+func (mr *MockUserRepositoryInterfaceMockRecorder) UpdateFields(ctx, userID, fields interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFields", reflect.TypeOf((*MockUserRepositoryInterface)(nil).UpdateFields), userID, fields)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFields", reflect.TypeOf((*MockUserRepositoryInterface)(nil).UpdateFields), ctx, userID, fields)
 }
 
 // UpdatePasswordHash mocks base method.
-func (m *MockUserRepositoryInterface) UpdatePasswordHash(userID uuid.UUID, passwordHash string) error {
+func (m *MockUserRepositoryInterface) UpdatePasswordHash(ctx context.Context, userID uuid.UUID, passwordHash string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdatePasswordHash", userID, passwordHash)
+	ret := m.ctrl.Call(m, "UpdatePasswordHash", ctx, userID, passwordHash)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdatePasswordHash indicates an expected call of UpdatePasswordHash.
-func (mr *MockUserRepositoryInterfaceMockRecorder) UpdatePasswordHash(userID, passwordHash interface{}) *gomock.Call {
+func (mr *MockUserRepositoryInterfaceMockRecorder) UpdatePasswordHash(ctx, userID, passwordHash interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePasswordHash", reflect.TypeOf((*MockUserRepositoryInterface)(nil).UpdatePasswordHash), userID, passwordHash)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePasswordHash", reflect.TypeOf((*MockUserRepositoryInterface)(nil).UpdatePasswordHash), ctx, userID, passwordHash)
 }
 
 // MockAuditLogRepositoryInterface is a mock of AuditLogRepositoryInterface interface.
@@ -863,6 +909,21 @@ func (m *MockAuditLogRepositoryInterface) EXPECT() *MockAuditLogRepositoryInterf
 	return m.recorder
 }
 
+// CountRetentionExpired mocks base method.
+func (m *MockAuditLogRepositoryInterface) CountRetentionExpired(ctx context.Context, cutoff time.Time) (models.RetentionCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRetentionExpired", ctx, cutoff)
+	ret0, _ := ret[0].(models.RetentionCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRetentionExpired indicates an expected call of CountRetentionExpired.
+func (mr *MockAuditLogRepositoryInterfaceMockRecorder) CountRetentionExpired(ctx, cutoff interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRetentionExpired", reflect.TypeOf((*MockAuditLogRepositoryInterface)(nil).CountRetentionExpired), ctx, cutoff)
+}
+
 // Create mocks base method.
 func (m *MockAuditLogRepositoryInterface) Create(log *models.AuditLog) error {
 	m.ctrl.T.Helper()
@@ -1018,6 +1079,21 @@ func (mr *MockAuditLogRepositoryInterfaceMockRecorder) GetFailedLoginAttempts(em
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFailedLoginAttempts", reflect.TypeOf((*MockAuditLogRepositoryInterface)(nil).GetFailedLoginAttempts), email, since)
 }
 
+// PurgeRetentionExpired mocks base method.
+func (m *MockAuditLogRepositoryInterface) PurgeRetentionExpired(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeRetentionExpired", ctx, cutoff, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeRetentionExpired indicates an expected call of PurgeRetentionExpired.
+func (mr *MockAuditLogRepositoryInterfaceMockRecorder) PurgeRetentionExpired(ctx, cutoff, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeRetentionExpired", reflect.TypeOf((*MockAuditLogRepositoryInterface)(nil).PurgeRetentionExpired), ctx, cutoff, limit)
+}
+
 // MockProcessingQueueRepositoryInterface is a mock of ProcessingQueueRepositoryInterface interface.
 type MockProcessingQueueRepositoryInterface struct {
 	ctrl     *gomock.Controller
@@ -1231,6 +1307,21 @@ func (mr *MockProcessingQueueRepositoryInterfaceMockRecorder) MarkProcessing(que
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkProcessing", reflect.TypeOf((*MockProcessingQueueRepositoryInterface)(nil).MarkProcessing), queueItemID)
 }
 
+// ResetStaleProcessing mocks base method.
+func (m *MockProcessingQueueRepositoryInterface) ResetStaleProcessing(staleBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetStaleProcessing", staleBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetStaleProcessing indicates an expected call of ResetStaleProcessing.
+func (mr *MockProcessingQueueRepositoryInterfaceMockRecorder) ResetStaleProcessing(staleBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetStaleProcessing", reflect.TypeOf((*MockProcessingQueueRepositoryInterface)(nil).ResetStaleProcessing), staleBefore)
+}
+
 // MockTransferRepositoryInterface is a mock of TransferRepositoryInterface interface.
 type MockTransferRepositoryInterface struct {
 	ctrl     *gomock.Controller
@@ -1269,6 +1360,21 @@ func (mr *MockTransferRepositoryInterfaceMockRecorder) CountByUserAccounts(accou
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByUserAccounts", reflect.TypeOf((*MockTransferRepositoryInterface)(nil).CountByUserAccounts), accountIDs)
 }
 
+// CountRetentionExpired mocks base method.
+func (m *MockTransferRepositoryInterface) CountRetentionExpired(ctx context.Context, cutoff time.Time) (models.RetentionCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRetentionExpired", ctx, cutoff)
+	ret0, _ := ret[0].(models.RetentionCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRetentionExpired indicates an expected call of CountRetentionExpired.
+func (mr *MockTransferRepositoryInterfaceMockRecorder) CountRetentionExpired(ctx, cutoff interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRetentionExpired", reflect.TypeOf((*MockTransferRepositoryInterface)(nil).CountRetentionExpired), ctx, cutoff)
+}
+
 // Create mocks base method.
 func (m *MockTransferRepositoryInterface) Create(transfer *models.Transfer) error {
 	m.ctrl.T.Helper()
@@ -1345,6 +1451,21 @@ func (mr *MockTransferRepositoryInterfaceMockRecorder) FindByUserAccountsWithFil
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserAccountsWithFilters", reflect.TypeOf((*MockTransferRepositoryInterface)(nil).FindByUserAccountsWithFilters), accountIDs, filters, offset, limit)
 }
 
+// PurgeRetentionExpired mocks base method.
+func (m *MockTransferRepositoryInterface) PurgeRetentionExpired(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeRetentionExpired", ctx, cutoff, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeRetentionExpired indicates an expected call of PurgeRetentionExpired.
+func (mr *MockTransferRepositoryInterfaceMockRecorder) PurgeRetentionExpired(ctx, cutoff, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeRetentionExpired", reflect.TypeOf((*MockTransferRepositoryInterface)(nil).PurgeRetentionExpired), ctx, cutoff, limit)
+}
+
 // Update mocks base method.
 func (m *MockTransferRepositoryInterface) Update(transfer *models.Transfer) error {
 	m.ctrl.T.Helper()
@@ -1604,53 +1725,53 @@ func (m *MockNorthwindExternalAccountRepositoryInterface) EXPECT() *MockNorthwin
 }
 
 // Create mocks base method.
-func (m *MockNorthwindExternalAccountRepositoryInterface) Create(account *models.NorthwindExternalAccount) error {
+func (m *MockNorthwindExternalAccountRepositoryInterface) Create(ctx context.Context, account *models.NorthwindExternalAccount) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", account)
+	ret := m.ctrl.Call(m, "Create", ctx, account)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockNorthwindExternalAccountRepositoryInterfaceMockRecorder) Create(account interface{}) *gomock.Call {
+func (mr *MockNorthwindExternalAccountRepositoryInterfaceMockRecorder) Create(ctx, account interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockNorthwindExternalAccountRepositoryInterface)(nil).Create), account)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockNorthwindExternalAccountRepositoryInterface)(nil).Create), ctx, account)
 }
 
 // FindByAccountAndRouting mocks base method.
-func (m *MockNorthwindExternalAccountRepositoryInterface) FindByAccountAndRouting(userID uuid.UUID, accountNumber, routingNumber string) (*models.NorthwindExternalAccount, error) {
+func (m *MockNorthwindExternalAccountRepositoryInterface) FindByAccountAndRouting(ctx context.Context, userID uuid.UUID, accountNumber, routingNumber string) (*models.NorthwindExternalAccount, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByAccountAndRouting", userID, accountNumber, routingNumber)
+	ret := m.ctrl.Call(m, "FindByAccountAndRouting", ctx, userID, accountNumber, routingNumber)
 	ret0, _ := ret[0].(*models.NorthwindExternalAccount)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindByAccountAndRouting indicates an expected call of FindByAccountAndRouting.
-func (mr *MockNorthwindExternalAccountRepositoryInterfaceMockRecorder) FindByAccountAndRouting(userID, accountNumber, routingNumber interface{}) *gomock.Call {
+func (mr *MockNorthwindExternalAccountRepositoryInterfaceMockRecorder) FindByAccountAndRouting(ctx, userID, accountNumber, routingNumber interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByAccountAndRouting", reflect.TypeOf((*MockNorthwindExternalAccountRepositoryInterface)(nil).FindByAccountAndRouting), userID, accountNumber, routingNumber)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByAccountAndRouting", reflect.TypeOf((*MockNorthwindExternalAccountRepositoryInterface)(nil).FindByAccountAndRouting), ctx, userID, accountNumber, routingNumber)
 }
 
 // GetByID mocks base method.
-func (m *MockNorthwindExternalAccountRepositoryInterface) GetByID(id uuid.UUID) (*models.NorthwindExternalAccount, error) {
+func (m *MockNorthwindExternalAccountRepositoryInterface) GetByID(ctx context.Context, id uuid.UUID) (*models.NorthwindExternalAccount, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", id)
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
 	ret0, _ := ret[0].(*models.NorthwindExternalAccount)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockNorthwindExternalAccountRepositoryInterfaceMockRecorder) GetByID(id interface{}) *gomock.Call {
+func (mr *MockNorthwindExternalAccountRepositoryInterfaceMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockNorthwindExternalAccountRepositoryInterface)(nil).GetByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockNorthwindExternalAccountRepositoryInterface)(nil).GetByID), ctx, id)
 }
 
 // GetByUserID mocks base method.
-func (m *MockNorthwindExternalAccountRepositoryInterface) GetByUserID(userID uuid.UUID, offset, limit int) ([]models.NorthwindExternalAccount, int64, error) {
+func (m *MockNorthwindExternalAccountRepositoryInterface) GetByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]models.NorthwindExternalAccount, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByUserID", userID, offset, limit)
+	ret := m.ctrl.Call(m, "GetByUserID", ctx, userID, offset, limit)
 	ret0, _ := ret[0].([]models.NorthwindExternalAccount)
 	ret1, _ := ret[1].(int64)
 	ret2, _ := ret[2].(error)
@@ -1658,23 +1779,23 @@ func (m *MockNorthwindExternalAccountRepositoryInterface) GetByUserID(userID uui
 }
 
 // GetByUserID indicates an expected call of GetByUserID.
-func (mr *MockNorthwindExternalAccountRepositoryInterfaceMockRecorder) GetByUserID(userID, offset, limit interface{}) *gomock.Call {
+func (mr *MockNorthwindExternalAccountRepositoryInterfaceMockRecorder) GetByUserID(ctx, userID, offset, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockNorthwindExternalAccountRepositoryInterface)(nil).GetByUserID), userID, offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockNorthwindExternalAccountRepositoryInterface)(nil).GetByUserID), ctx, userID, offset, limit)
 }
 
 // Update mocks base method.
-func (m *MockNorthwindExternalAccountRepositoryInterface) Update(account *models.NorthwindExternalAccount) error {
+func (m *MockNorthwindExternalAccountRepositoryInterface) Update(ctx context.Context, account *models.NorthwindExternalAccount) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", account)
+	ret := m.ctrl.Call(m, "Update", ctx, account)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockNorthwindExternalAccountRepositoryInterfaceMockRecorder) Update(account interface{}) *gomock.Call {
+func (mr *MockNorthwindExternalAccountRepositoryInterfaceMockRecorder) Update(ctx, account interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockNorthwindExternalAccountRepositoryInterface)(nil).Update), account)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockNorthwindExternalAccountRepositoryInterface)(nil).Update), ctx, account)
 }
 
 // MockNorthwindTransferRepositoryInterface is a mock of NorthwindTransferRepositoryInterface interface.
@@ -1700,255 +1821,1731 @@ func (m *MockNorthwindTransferRepositoryInterface) EXPECT() *MockNorthwindTransf
 	return m.recorder
 }
 
+// ApplyTransition mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) ApplyTransition(ctx context.Context, id uuid.UUID, transition func(*models.NorthwindTransfer) *models.NorthwindTransferEvent) (*models.NorthwindTransfer, *models.NorthwindTransferEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyTransition", ctx, id, transition)
+	ret0, _ := ret[0].(*models.NorthwindTransfer)
+	ret1, _ := ret[1].(*models.NorthwindTransferEvent)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ApplyTransition indicates an expected call of ApplyTransition.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) ApplyTransition(ctx, id, transition interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyTransition", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).ApplyTransition), ctx, id, transition)
+}
+
+// ClaimForInitiation mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) ClaimForInitiation(ctx context.Context, id uuid.UUID, status string, at time.Time, staleAfter time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimForInitiation", ctx, id, status, at, staleAfter)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimForInitiation indicates an expected call of ClaimForInitiation.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) ClaimForInitiation(ctx, id, status, at, staleAfter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimForInitiation", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).ClaimForInitiation), ctx, id, status, at, staleAfter)
+}
+
+// CountByStatus mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) CountByStatus(ctx context.Context, statuses ...string) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range statuses {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CountByStatus", varargs...)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByStatus indicates an expected call of CountByStatus.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) CountByStatus(ctx interface{}, statuses ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, statuses...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByStatus", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).CountByStatus), varargs...)
+}
+
+// CountByStatusSince mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) CountByStatusSince(ctx context.Context, since time.Time) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByStatusSince", ctx, since)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByStatusSince indicates an expected call of CountByStatusSince.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) CountByStatusSince(ctx, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByStatusSince", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).CountByStatusSince), ctx, since)
+}
+
+// CountRetentionExpired mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) CountRetentionExpired(ctx context.Context, cutoff time.Time) (models.RetentionCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRetentionExpired", ctx, cutoff)
+	ret0, _ := ret[0].(models.RetentionCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRetentionExpired indicates an expected call of CountRetentionExpired.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) CountRetentionExpired(ctx, cutoff interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRetentionExpired", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).CountRetentionExpired), ctx, cutoff)
+}
+
 // Create mocks base method.
-func (m *MockNorthwindTransferRepositoryInterface) Create(transfer *models.NorthwindTransfer) error {
+func (m *MockNorthwindTransferRepositoryInterface) Create(ctx context.Context, transfer *models.NorthwindTransfer) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", transfer)
+	ret := m.ctrl.Call(m, "Create", ctx, transfer)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) Create(transfer interface{}) *gomock.Call {
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) Create(ctx, transfer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).Create), ctx, transfer)
+}
+
+// FindRecentDuplicate mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) FindRecentDuplicate(ctx context.Context, userID uuid.UUID, amount decimal.Decimal, currency, direction, destinationAccountNumber string, since time.Time) (*models.NorthwindTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRecentDuplicate", ctx, userID, amount, currency, direction, destinationAccountNumber, since)
+	ret0, _ := ret[0].(*models.NorthwindTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRecentDuplicate indicates an expected call of FindRecentDuplicate.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) FindRecentDuplicate(ctx, userID, amount, currency, direction, destinationAccountNumber, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRecentDuplicate", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).FindRecentDuplicate), ctx, userID, amount, currency, direction, destinationAccountNumber, since)
+}
+
+// GetByExternalRef mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) GetByExternalRef(ctx context.Context, ref string) (*models.NorthwindTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByExternalRef", ctx, ref)
+	ret0, _ := ret[0].(*models.NorthwindTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByExternalRef indicates an expected call of GetByExternalRef.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) GetByExternalRef(ctx, ref interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).Create), transfer)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByExternalRef", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).GetByExternalRef), ctx, ref)
 }
 
 // GetByID mocks base method.
-func (m *MockNorthwindTransferRepositoryInterface) GetByID(id uuid.UUID) (*models.NorthwindTransfer, error) {
+func (m *MockNorthwindTransferRepositoryInterface) GetByID(ctx context.Context, id uuid.UUID) (*models.NorthwindTransfer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", id)
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
 	ret0, _ := ret[0].(*models.NorthwindTransfer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) GetByID(id interface{}) *gomock.Call {
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).GetByID), ctx, id)
+}
+
+// GetByIDs mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]models.NorthwindTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDs", ctx, ids)
+	ret0, _ := ret[0].(map[uuid.UUID]models.NorthwindTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDs indicates an expected call of GetByIDs.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) GetByIDs(ctx, ids interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).GetByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).GetByIDs), ctx, ids)
 }
 
 // GetByNorthwindTransferID mocks base method.
-func (m *MockNorthwindTransferRepositoryInterface) GetByNorthwindTransferID(nwID uuid.UUID) (*models.NorthwindTransfer, error) {
+func (m *MockNorthwindTransferRepositoryInterface) GetByNorthwindTransferID(ctx context.Context, nwID uuid.UUID) (*models.NorthwindTransfer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByNorthwindTransferID", nwID)
+	ret := m.ctrl.Call(m, "GetByNorthwindTransferID", ctx, nwID)
 	ret0, _ := ret[0].(*models.NorthwindTransfer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByNorthwindTransferID indicates an expected call of GetByNorthwindTransferID.
-func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) GetByNorthwindTransferID(nwID interface{}) *gomock.Call {
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) GetByNorthwindTransferID(ctx, nwID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByNorthwindTransferID", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).GetByNorthwindTransferID), nwID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByNorthwindTransferID", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).GetByNorthwindTransferID), ctx, nwID)
 }
 
-// GetByUserID mocks base method.
-func (m *MockNorthwindTransferRepositoryInterface) GetByUserID(userID uuid.UUID, offset, limit int) ([]models.NorthwindTransfer, int64, error) {
+// GetBySourceAccountKeyset mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) GetBySourceAccountKeyset(ctx context.Context, userID uuid.UUID, sourceAccountNumber string, after *models.AccountActivityKeyset, limit int) ([]models.NorthwindTransfer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByUserID", userID, offset, limit)
+	ret := m.ctrl.Call(m, "GetBySourceAccountKeyset", ctx, userID, sourceAccountNumber, after, limit)
 	ret0, _ := ret[0].([]models.NorthwindTransfer)
-	ret1, _ := ret[1].(int64)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// GetByUserID indicates an expected call of GetByUserID.
-func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) GetByUserID(userID, offset, limit interface{}) *gomock.Call {
+// GetBySourceAccountKeyset indicates an expected call of GetBySourceAccountKeyset.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) GetBySourceAccountKeyset(ctx, userID, sourceAccountNumber, after, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).GetByUserID), userID, offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySourceAccountKeyset", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).GetBySourceAccountKeyset), ctx, userID, sourceAccountNumber, after, limit)
 }
 
-// GetByUserIDWithFilters mocks base method.
-func (m *MockNorthwindTransferRepositoryInterface) GetByUserIDWithFilters(userID uuid.UUID, status, direction, transferType string, offset, limit int) ([]models.NorthwindTransfer, int64, error) {
+// GetByStatus mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) GetByStatus(ctx context.Context, status string, limit int) ([]models.NorthwindTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByStatus", ctx, status, limit)
+	ret0, _ := ret[0].([]models.NorthwindTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByStatus indicates an expected call of GetByStatus.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) GetByStatus(ctx, status, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByStatus", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).GetByStatus), ctx, status, limit)
+}
+
+// GetByUserID mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) GetByUserID(ctx context.Context, userID uuid.UUID, offset, limit int) ([]models.NorthwindTransfer, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByUserIDWithFilters", userID, status, direction, transferType, offset, limit)
+	ret := m.ctrl.Call(m, "GetByUserID", ctx, userID, offset, limit)
 	ret0, _ := ret[0].([]models.NorthwindTransfer)
 	ret1, _ := ret[1].(int64)
 	ret2, _ := ret[2].(error)
 	return ret0, ret1, ret2
 }
 
-// GetByUserIDWithFilters indicates an expected call of GetByUserIDWithFilters.
-func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) GetByUserIDWithFilters(userID, status, direction, transferType, offset, limit interface{}) *gomock.Call {
+// GetByUserID indicates an expected call of GetByUserID.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) GetByUserID(ctx, userID, offset, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserIDWithFilters", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).GetByUserIDWithFilters), userID, status, direction, transferType, offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).GetByUserID), ctx, userID, offset, limit)
 }
 
-// GetPendingTransfers mocks base method.
-func (m *MockNorthwindTransferRepositoryInterface) GetPendingTransfers(limit int) ([]models.NorthwindTransfer, error) {
+// GetByUserIDAndBatch mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) GetByUserIDAndBatch(ctx context.Context, userID uuid.UUID, batchName string) ([]models.NorthwindTransfer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPendingTransfers", limit)
+	ret := m.ctrl.Call(m, "GetByUserIDAndBatch", ctx, userID, batchName)
 	ret0, _ := ret[0].([]models.NorthwindTransfer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPendingTransfers indicates an expected call of GetPendingTransfers.
-func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) GetPendingTransfers(limit interface{}) *gomock.Call {
+// GetByUserIDAndBatch indicates an expected call of GetByUserIDAndBatch.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) GetByUserIDAndBatch(ctx, userID, batchName interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingTransfers", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).GetPendingTransfers), limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserIDAndBatch", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).GetByUserIDAndBatch), ctx, userID, batchName)
 }
 
-// Update mocks base method.
-func (m *MockNorthwindTransferRepositoryInterface) Update(transfer *models.NorthwindTransfer) error {
+// GetByUserIDAndStatus mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) GetByUserIDAndStatus(ctx context.Context, userID uuid.UUID, status string) ([]models.NorthwindTransfer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", transfer)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "GetByUserIDAndStatus", ctx, userID, status)
+	ret0, _ := ret[0].([]models.NorthwindTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Update indicates an expected call of Update.
-func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) Update(transfer interface{}) *gomock.Call {
+// GetByUserIDAndStatus indicates an expected call of GetByUserIDAndStatus.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) GetByUserIDAndStatus(ctx, userID, status interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).Update), transfer)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserIDAndStatus", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).GetByUserIDAndStatus), ctx, userID, status)
 }
 
-// MockRegulatorNotificationRepositoryInterface is a mock of RegulatorNotificationRepositoryInterface interface.
-type MockRegulatorNotificationRepositoryInterface struct {
-	ctrl     *gomock.Controller
-	recorder *MockRegulatorNotificationRepositoryInterfaceMockRecorder
+// GetByUserIDKeyset mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) GetByUserIDKeyset(ctx context.Context, userID uuid.UUID, filters models.NorthwindTransferFilters, after *models.NorthwindTransferKeyset, limit int) ([]models.NorthwindTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserIDKeyset", ctx, userID, filters, after, limit)
+	ret0, _ := ret[0].([]models.NorthwindTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// MockRegulatorNotificationRepositoryInterfaceMockRecorder is the mock recorder for MockRegulatorNotificationRepositoryInterface.
-type MockRegulatorNotificationRepositoryInterfaceMockRecorder struct {
-	mock *MockRegulatorNotificationRepositoryInterface
+// GetByUserIDKeyset indicates an expected call of GetByUserIDKeyset.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) GetByUserIDKeyset(ctx, userID, filters, after, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserIDKeyset", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).GetByUserIDKeyset), ctx, userID, filters, after, limit)
 }
 
-// NewMockRegulatorNotificationRepositoryInterface creates a new mock instance.
-func NewMockRegulatorNotificationRepositoryInterface(ctrl *gomock.Controller) *MockRegulatorNotificationRepositoryInterface {
-	mock := &MockRegulatorNotificationRepositoryInterface{ctrl: ctrl}
-	mock.recorder = &MockRegulatorNotificationRepositoryInterfaceMockRecorder{mock}
-	return mock
+// GetByUserIDWithFilters mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) GetByUserIDWithFilters(ctx context.Context, userID uuid.UUID, status, direction, transferType string, offset, limit int) ([]models.NorthwindTransfer, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserIDWithFilters", ctx, userID, status, direction, transferType, offset, limit)
+	ret0, _ := ret[0].([]models.NorthwindTransfer)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockRegulatorNotificationRepositoryInterface) EXPECT() *MockRegulatorNotificationRepositoryInterfaceMockRecorder {
-	return m.recorder
+// GetByUserIDWithFilters indicates an expected call of GetByUserIDWithFilters.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) GetByUserIDWithFilters(ctx, userID, status, direction, transferType, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserIDWithFilters", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).GetByUserIDWithFilters), ctx, userID, status, direction, transferType, offset, limit)
 }
 
-// Create mocks base method.
-func (m *MockRegulatorNotificationRepositoryInterface) Create(notification *models.RegulatorNotification) error {
+// GetCompletionDurationStats mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) GetCompletionDurationStats(ctx context.Context, from, to time.Time) ([]models.TransferDurationStats, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", notification)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "GetCompletionDurationStats", ctx, from, to)
+	ret0, _ := ret[0].([]models.TransferDurationStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Create indicates an expected call of Create.
-func (mr *MockRegulatorNotificationRepositoryInterfaceMockRecorder) Create(notification interface{}) *gomock.Call {
+// GetCompletionDurationStats indicates an expected call of GetCompletionDurationStats.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) GetCompletionDurationStats(ctx, from, to interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRegulatorNotificationRepositoryInterface)(nil).Create), notification)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCompletionDurationStats", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).GetCompletionDurationStats), ctx, from, to)
 }
 
-// ExistsForTransferAndStatus mocks base method.
-func (m *MockRegulatorNotificationRepositoryInterface) ExistsForTransferAndStatus(transferID uuid.UUID, terminalStatus string) (bool, error) {
+// GetInitiationOutcomes mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) GetInitiationOutcomes(ctx context.Context, since time.Time) (*models.NorthwindInitiationOutcomes, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ExistsForTransferAndStatus", transferID, terminalStatus)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "GetInitiationOutcomes", ctx, since)
+	ret0, _ := ret[0].(*models.NorthwindInitiationOutcomes)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ExistsForTransferAndStatus indicates an expected call of ExistsForTransferAndStatus.
-func (mr *MockRegulatorNotificationRepositoryInterfaceMockRecorder) ExistsForTransferAndStatus(transferID, terminalStatus interface{}) *gomock.Call {
+// GetInitiationOutcomes indicates an expected call of GetInitiationOutcomes.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) GetInitiationOutcomes(ctx, since interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsForTransferAndStatus", reflect.TypeOf((*MockRegulatorNotificationRepositoryInterface)(nil).ExistsForTransferAndStatus), transferID, terminalStatus)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInitiationOutcomes", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).GetInitiationOutcomes), ctx, since)
 }
 
-// GetByID mocks base method.
-func (m *MockRegulatorNotificationRepositoryInterface) GetByID(id uuid.UUID) (*models.RegulatorNotification, error) {
+// GetPendingTransfers mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) GetPendingTransfers(ctx context.Context, statuses []string, limit int, priority models.NorthwindPollPriority) ([]models.NorthwindTransfer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", id)
-	ret0, _ := ret[0].(*models.RegulatorNotification)
+	ret := m.ctrl.Call(m, "GetPendingTransfers", ctx, statuses, limit, priority)
+	ret0, _ := ret[0].([]models.NorthwindTransfer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetByID indicates an expected call of GetByID.
-func (mr *MockRegulatorNotificationRepositoryInterfaceMockRecorder) GetByID(id interface{}) *gomock.Call {
+// GetPendingTransfers indicates an expected call of GetPendingTransfers.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) GetPendingTransfers(ctx, statuses, limit, priority interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockRegulatorNotificationRepositoryInterface)(nil).GetByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingTransfers", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).GetPendingTransfers), ctx, statuses, limit, priority)
 }
 
-// GetPendingNotifications mocks base method.
-func (m *MockRegulatorNotificationRepositoryInterface) GetPendingNotifications(limit int) ([]models.RegulatorNotification, error) {
+// GetPollingBacklog mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) GetPollingBacklog(ctx context.Context) (*models.NorthwindPollingBacklog, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPendingNotifications", limit)
-	ret0, _ := ret[0].([]models.RegulatorNotification)
+	ret := m.ctrl.Call(m, "GetPollingBacklog", ctx)
+	ret0, _ := ret[0].(*models.NorthwindPollingBacklog)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPendingNotifications indicates an expected call of GetPendingNotifications.
-func (mr *MockRegulatorNotificationRepositoryInterfaceMockRecorder) GetPendingNotifications(limit interface{}) *gomock.Call {
+// GetPollingBacklog indicates an expected call of GetPollingBacklog.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) GetPollingBacklog(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingNotifications", reflect.TypeOf((*MockRegulatorNotificationRepositoryInterface)(nil).GetPendingNotifications), limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPollingBacklog", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).GetPollingBacklog), ctx)
 }
 
-// Update mocks base method.
-func (m *MockRegulatorNotificationRepositoryInterface) Update(notification *models.RegulatorNotification) error {
+// GetScheduledTransfersDue mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) GetScheduledTransfersDue(ctx context.Context, before time.Time, limit int) ([]models.NorthwindTransfer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", notification)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "GetScheduledTransfersDue", ctx, before, limit)
+	ret0, _ := ret[0].([]models.NorthwindTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Update indicates an expected call of Update.
-func (mr *MockRegulatorNotificationRepositoryInterfaceMockRecorder) Update(notification interface{}) *gomock.Call {
+// GetScheduledTransfersDue indicates an expected call of GetScheduledTransfersDue.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) GetScheduledTransfersDue(ctx, before, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRegulatorNotificationRepositoryInterface)(nil).Update), notification)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduledTransfersDue", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).GetScheduledTransfersDue), ctx, before, limit)
 }
 
-// MockRegulatorNotificationAttemptRepositoryInterface is a mock of RegulatorNotificationAttemptRepositoryInterface interface.
-type MockRegulatorNotificationAttemptRepositoryInterface struct {
-	ctrl     *gomock.Controller
-	recorder *MockRegulatorNotificationAttemptRepositoryInterfaceMockRecorder
+// GetUnlinkedByReference mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) GetUnlinkedByReference(ctx context.Context, referenceNumber string) ([]models.NorthwindTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnlinkedByReference", ctx, referenceNumber)
+	ret0, _ := ret[0].([]models.NorthwindTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// MockRegulatorNotificationAttemptRepositoryInterfaceMockRecorder is the mock recorder for MockRegulatorNotificationAttemptRepositoryInterface.
-type MockRegulatorNotificationAttemptRepositoryInterfaceMockRecorder struct {
-	mock *MockRegulatorNotificationAttemptRepositoryInterface
+// GetUnlinkedByReference indicates an expected call of GetUnlinkedByReference.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) GetUnlinkedByReference(ctx, referenceNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnlinkedByReference", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).GetUnlinkedByReference), ctx, referenceNumber)
 }
 
-// NewMockRegulatorNotificationAttemptRepositoryInterface creates a new mock instance.
-func NewMockRegulatorNotificationAttemptRepositoryInterface(ctrl *gomock.Controller) *MockRegulatorNotificationAttemptRepositoryInterface {
-	mock := &MockRegulatorNotificationAttemptRepositoryInterface{ctrl: ctrl}
-	mock.recorder = &MockRegulatorNotificationAttemptRepositoryInterfaceMockRecorder{mock}
-	return mock
+// GetUserVelocity mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) GetUserVelocity(ctx context.Context, userID uuid.UUID, currency string, since time.Time) (*models.NorthwindTransferVelocity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserVelocity", ctx, userID, currency, since)
+	ret0, _ := ret[0].(*models.NorthwindTransferVelocity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockRegulatorNotificationAttemptRepositoryInterface) EXPECT() *MockRegulatorNotificationAttemptRepositoryInterfaceMockRecorder {
-	return m.recorder
+// GetUserVelocity indicates an expected call of GetUserVelocity.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) GetUserVelocity(ctx, userID, currency, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserVelocity", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).GetUserVelocity), ctx, userID, currency, since)
 }
 
-// Create mocks base method.
-func (m *MockRegulatorNotificationAttemptRepositoryInterface) Create(attempt *models.RegulatorNotificationAttempt) error {
+// ListEvents mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) ListEvents(ctx context.Context, transferID uuid.UUID) ([]models.NorthwindTransferEvent, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", attempt)
-	ret0, _ := ret[0].(error)
+	ret := m.ctrl.Call(m, "ListEvents", ctx, transferID)
+	ret0, _ := ret[0].([]models.NorthwindTransferEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEvents indicates an expected call of ListEvents.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) ListEvents(ctx, transferID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEvents", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).ListEvents), ctx, transferID)
+}
+
+// PurgeRetentionExpired mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) PurgeRetentionExpired(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeRetentionExpired", ctx, cutoff, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeRetentionExpired indicates an expected call of PurgeRetentionExpired.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) PurgeRetentionExpired(ctx, cutoff, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeRetentionExpired", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).PurgeRetentionExpired), ctx, cutoff, limit)
+}
+
+// ReferenceExists mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) ReferenceExists(ctx context.Context, userID uuid.UUID, referenceNumber string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReferenceExists", ctx, userID, referenceNumber)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReferenceExists indicates an expected call of ReferenceExists.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) ReferenceExists(ctx, userID, referenceNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReferenceExists", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).ReferenceExists), ctx, userID, referenceNumber)
+}
+
+// ReleaseInitiationClaim mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) ReleaseInitiationClaim(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseInitiationClaim", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseInitiationClaim indicates an expected call of ReleaseInitiationClaim.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) ReleaseInitiationClaim(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseInitiationClaim", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).ReleaseInitiationClaim), ctx, id)
+}
+
+// SetInternalTest mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) SetInternalTest(ctx context.Context, id uuid.UUID, internalTest bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetInternalTest", ctx, id, internalTest)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetInternalTest indicates an expected call of SetInternalTest.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) SetInternalTest(ctx, id, internalTest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInternalTest", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).SetInternalTest), ctx, id, internalTest)
+}
+
+// SetNextPollAt mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) SetNextPollAt(ctx context.Context, id uuid.UUID, at *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNextPollAt", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetNextPollAt indicates an expected call of SetNextPollAt.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) SetNextPollAt(ctx, id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNextPollAt", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).SetNextPollAt), ctx, id, at)
+}
+
+// TouchLastViewedAt mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) TouchLastViewedAt(ctx context.Context, id uuid.UUID, at time.Time, minInterval time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchLastViewedAt", ctx, id, at, minInterval)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TouchLastViewedAt indicates an expected call of TouchLastViewedAt.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) TouchLastViewedAt(ctx, id, at, minInterval interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchLastViewedAt", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).TouchLastViewedAt), ctx, id, at, minInterval)
+}
+
+// Update mocks base method.
+func (m *MockNorthwindTransferRepositoryInterface) Update(ctx context.Context, transfer *models.NorthwindTransfer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, transfer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockNorthwindTransferRepositoryInterfaceMockRecorder) Update(ctx, transfer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockNorthwindTransferRepositoryInterface)(nil).Update), ctx, transfer)
+}
+
+// MockRegulatorNotificationRepositoryInterface is a mock of RegulatorNotificationRepositoryInterface interface.
+type MockRegulatorNotificationRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRegulatorNotificationRepositoryInterfaceMockRecorder
+}
+
+// MockRegulatorNotificationRepositoryInterfaceMockRecorder is the mock recorder for MockRegulatorNotificationRepositoryInterface.
+type MockRegulatorNotificationRepositoryInterfaceMockRecorder struct {
+	mock *MockRegulatorNotificationRepositoryInterface
+}
+
+// NewMockRegulatorNotificationRepositoryInterface creates a new mock instance.
+func NewMockRegulatorNotificationRepositoryInterface(ctrl *gomock.Controller) *MockRegulatorNotificationRepositoryInterface {
+	mock := &MockRegulatorNotificationRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRegulatorNotificationRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRegulatorNotificationRepositoryInterface) EXPECT() *MockRegulatorNotificationRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRegulatorNotificationRepositoryInterface) Create(ctx context.Context, notification *models.RegulatorNotification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, notification)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockRegulatorNotificationRepositoryInterfaceMockRecorder) Create(ctx, notification interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRegulatorNotificationRepositoryInterface)(nil).Create), ctx, notification)
+}
+
+// ExistsForTransferAndStatus mocks base method.
+func (m *MockRegulatorNotificationRepositoryInterface) ExistsForTransferAndStatus(ctx context.Context, transferID uuid.UUID, terminalStatus string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistsForTransferAndStatus", ctx, transferID, terminalStatus)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistsForTransferAndStatus indicates an expected call of ExistsForTransferAndStatus.
+func (mr *MockRegulatorNotificationRepositoryInterfaceMockRecorder) ExistsForTransferAndStatus(ctx, transferID, terminalStatus interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsForTransferAndStatus", reflect.TypeOf((*MockRegulatorNotificationRepositoryInterface)(nil).ExistsForTransferAndStatus), ctx, transferID, terminalStatus)
+}
+
+// GetByEventID mocks base method.
+func (m *MockRegulatorNotificationRepositoryInterface) GetByEventID(ctx context.Context, eventID string) (*models.RegulatorNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByEventID", ctx, eventID)
+	ret0, _ := ret[0].(*models.RegulatorNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByEventID indicates an expected call of GetByEventID.
+func (mr *MockRegulatorNotificationRepositoryInterfaceMockRecorder) GetByEventID(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEventID", reflect.TypeOf((*MockRegulatorNotificationRepositoryInterface)(nil).GetByEventID), ctx, eventID)
+}
+
+// GetByID mocks base method.
+func (m *MockRegulatorNotificationRepositoryInterface) GetByID(ctx context.Context, id uuid.UUID) (*models.RegulatorNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*models.RegulatorNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockRegulatorNotificationRepositoryInterfaceMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockRegulatorNotificationRepositoryInterface)(nil).GetByID), ctx, id)
+}
+
+// GetByTransferAndStatus mocks base method.
+func (m *MockRegulatorNotificationRepositoryInterface) GetByTransferAndStatus(ctx context.Context, transferID uuid.UUID, terminalStatus string) (*models.RegulatorNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByTransferAndStatus", ctx, transferID, terminalStatus)
+	ret0, _ := ret[0].(*models.RegulatorNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByTransferAndStatus indicates an expected call of GetByTransferAndStatus.
+func (mr *MockRegulatorNotificationRepositoryInterfaceMockRecorder) GetByTransferAndStatus(ctx, transferID, terminalStatus interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByTransferAndStatus", reflect.TypeOf((*MockRegulatorNotificationRepositoryInterface)(nil).GetByTransferAndStatus), ctx, transferID, terminalStatus)
+}
+
+// GetDeliveryStats mocks base method.
+func (m *MockRegulatorNotificationRepositoryInterface) GetDeliveryStats(ctx context.Context, abandonedBefore, slaFrom time.Time, sla time.Duration) (*models.RegulatorDeliveryStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveryStats", ctx, abandonedBefore, slaFrom, sla)
+	ret0, _ := ret[0].(*models.RegulatorDeliveryStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveryStats indicates an expected call of GetDeliveryStats.
+func (mr *MockRegulatorNotificationRepositoryInterfaceMockRecorder) GetDeliveryStats(ctx, abandonedBefore, slaFrom, sla interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveryStats", reflect.TypeOf((*MockRegulatorNotificationRepositoryInterface)(nil).GetDeliveryStats), ctx, abandonedBefore, slaFrom, sla)
+}
+
+// GetPendingNotifications mocks base method.
+func (m *MockRegulatorNotificationRepositoryInterface) GetPendingNotifications(ctx context.Context, limit int) ([]models.RegulatorNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingNotifications", ctx, limit)
+	ret0, _ := ret[0].([]models.RegulatorNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingNotifications indicates an expected call of GetPendingNotifications.
+func (mr *MockRegulatorNotificationRepositoryInterfaceMockRecorder) GetPendingNotifications(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingNotifications", reflect.TypeOf((*MockRegulatorNotificationRepositoryInterface)(nil).GetPendingNotifications), ctx, limit)
+}
+
+// ListByTransferIDs mocks base method.
+func (m *MockRegulatorNotificationRepositoryInterface) ListByTransferIDs(ctx context.Context, transferIDs []uuid.UUID) ([]models.RegulatorNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByTransferIDs", ctx, transferIDs)
+	ret0, _ := ret[0].([]models.RegulatorNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByTransferIDs indicates an expected call of ListByTransferIDs.
+func (mr *MockRegulatorNotificationRepositoryInterfaceMockRecorder) ListByTransferIDs(ctx, transferIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByTransferIDs", reflect.TypeOf((*MockRegulatorNotificationRepositoryInterface)(nil).ListByTransferIDs), ctx, transferIDs)
+}
+
+// ReleaseStaleLeases mocks base method.
+func (m *MockRegulatorNotificationRepositoryInterface) ReleaseStaleLeases(ctx context.Context, leasedBefore, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseStaleLeases", ctx, leasedBefore, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseStaleLeases indicates an expected call of ReleaseStaleLeases.
+func (mr *MockRegulatorNotificationRepositoryInterfaceMockRecorder) ReleaseStaleLeases(ctx, leasedBefore, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseStaleLeases", reflect.TypeOf((*MockRegulatorNotificationRepositoryInterface)(nil).ReleaseStaleLeases), ctx, leasedBefore, now)
+}
+
+// Update mocks base method.
+func (m *MockRegulatorNotificationRepositoryInterface) Update(ctx context.Context, notification *models.RegulatorNotification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, notification)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRegulatorNotificationRepositoryInterfaceMockRecorder) Update(ctx, notification interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRegulatorNotificationRepositoryInterface)(nil).Update), ctx, notification)
+}
+
+// MockRegulatorNotificationAttemptRepositoryInterface is a mock of RegulatorNotificationAttemptRepositoryInterface interface.
+type MockRegulatorNotificationAttemptRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRegulatorNotificationAttemptRepositoryInterfaceMockRecorder
+}
+
+// MockRegulatorNotificationAttemptRepositoryInterfaceMockRecorder is the mock recorder for MockRegulatorNotificationAttemptRepositoryInterface.
+type MockRegulatorNotificationAttemptRepositoryInterfaceMockRecorder struct {
+	mock *MockRegulatorNotificationAttemptRepositoryInterface
+}
+
+// NewMockRegulatorNotificationAttemptRepositoryInterface creates a new mock instance.
+func NewMockRegulatorNotificationAttemptRepositoryInterface(ctrl *gomock.Controller) *MockRegulatorNotificationAttemptRepositoryInterface {
+	mock := &MockRegulatorNotificationAttemptRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRegulatorNotificationAttemptRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRegulatorNotificationAttemptRepositoryInterface) EXPECT() *MockRegulatorNotificationAttemptRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockRegulatorNotificationAttemptRepositoryInterface) Create(ctx context.Context, attempt *models.RegulatorNotificationAttempt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, attempt)
+	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockRegulatorNotificationAttemptRepositoryInterfaceMockRecorder) Create(attempt interface{}) *gomock.Call {
+func (mr *MockRegulatorNotificationAttemptRepositoryInterfaceMockRecorder) Create(ctx, attempt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRegulatorNotificationAttemptRepositoryInterface)(nil).Create), attempt)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRegulatorNotificationAttemptRepositoryInterface)(nil).Create), ctx, attempt)
 }
 
 // GetByNotificationID mocks base method.
-func (m *MockRegulatorNotificationAttemptRepositoryInterface) GetByNotificationID(notificationID uuid.UUID) ([]models.RegulatorNotificationAttempt, error) {
+func (m *MockRegulatorNotificationAttemptRepositoryInterface) GetByNotificationID(ctx context.Context, notificationID uuid.UUID) ([]models.RegulatorNotificationAttempt, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByNotificationID", notificationID)
+	ret := m.ctrl.Call(m, "GetByNotificationID", ctx, notificationID)
 	ret0, _ := ret[0].([]models.RegulatorNotificationAttempt)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByNotificationID indicates an expected call of GetByNotificationID.
-func (mr *MockRegulatorNotificationAttemptRepositoryInterfaceMockRecorder) GetByNotificationID(notificationID interface{}) *gomock.Call {
+func (mr *MockRegulatorNotificationAttemptRepositoryInterfaceMockRecorder) GetByNotificationID(ctx, notificationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByNotificationID", reflect.TypeOf((*MockRegulatorNotificationAttemptRepositoryInterface)(nil).GetByNotificationID), ctx, notificationID)
+}
+
+// ListByNotificationIDs mocks base method.
+func (m *MockRegulatorNotificationAttemptRepositoryInterface) ListByNotificationIDs(ctx context.Context, notificationIDs []uuid.UUID) ([]models.RegulatorNotificationAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByNotificationIDs", ctx, notificationIDs)
+	ret0, _ := ret[0].([]models.RegulatorNotificationAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByNotificationIDs indicates an expected call of ListByNotificationIDs.
+func (mr *MockRegulatorNotificationAttemptRepositoryInterfaceMockRecorder) ListByNotificationIDs(ctx, notificationIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByNotificationIDs", reflect.TypeOf((*MockRegulatorNotificationAttemptRepositoryInterface)(nil).ListByNotificationIDs), ctx, notificationIDs)
+}
+
+// ListFiltered mocks base method.
+func (m *MockRegulatorNotificationAttemptRepositoryInterface) ListFiltered(ctx context.Context, filters models.RegulatorNotificationAttemptFilters, offset, limit int) ([]models.RegulatorNotificationAttempt, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFiltered", ctx, filters, offset, limit)
+	ret0, _ := ret[0].([]models.RegulatorNotificationAttempt)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListFiltered indicates an expected call of ListFiltered.
+func (mr *MockRegulatorNotificationAttemptRepositoryInterfaceMockRecorder) ListFiltered(ctx, filters, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFiltered", reflect.TypeOf((*MockRegulatorNotificationAttemptRepositoryInterface)(nil).ListFiltered), ctx, filters, offset, limit)
+}
+
+// StreamFiltered mocks base method.
+func (m *MockRegulatorNotificationAttemptRepositoryInterface) StreamFiltered(ctx context.Context, filters models.RegulatorNotificationAttemptFilters, fn func(*models.RegulatorNotificationAttempt) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamFiltered", ctx, filters, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamFiltered indicates an expected call of StreamFiltered.
+func (mr *MockRegulatorNotificationAttemptRepositoryInterfaceMockRecorder) StreamFiltered(ctx, filters, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamFiltered", reflect.TypeOf((*MockRegulatorNotificationAttemptRepositoryInterface)(nil).StreamFiltered), ctx, filters, fn)
+}
+
+// MockFeatureFlagOverrideRepositoryInterface is a mock of FeatureFlagOverrideRepositoryInterface interface.
+type MockFeatureFlagOverrideRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockFeatureFlagOverrideRepositoryInterfaceMockRecorder
+}
+
+// MockFeatureFlagOverrideRepositoryInterfaceMockRecorder is the mock recorder for MockFeatureFlagOverrideRepositoryInterface.
+type MockFeatureFlagOverrideRepositoryInterfaceMockRecorder struct {
+	mock *MockFeatureFlagOverrideRepositoryInterface
+}
+
+// NewMockFeatureFlagOverrideRepositoryInterface creates a new mock instance.
+func NewMockFeatureFlagOverrideRepositoryInterface(ctrl *gomock.Controller) *MockFeatureFlagOverrideRepositoryInterface {
+	mock := &MockFeatureFlagOverrideRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockFeatureFlagOverrideRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFeatureFlagOverrideRepositoryInterface) EXPECT() *MockFeatureFlagOverrideRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockFeatureFlagOverrideRepositoryInterface) Delete(ctx context.Context, flag string, userID *uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, flag, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockFeatureFlagOverrideRepositoryInterfaceMockRecorder) Delete(ctx, flag, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockFeatureFlagOverrideRepositoryInterface)(nil).Delete), ctx, flag, userID)
+}
+
+// GetApplicable mocks base method.
+func (m *MockFeatureFlagOverrideRepositoryInterface) GetApplicable(ctx context.Context, flag string, userID uuid.UUID) ([]models.FeatureFlagOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApplicable", ctx, flag, userID)
+	ret0, _ := ret[0].([]models.FeatureFlagOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApplicable indicates an expected call of GetApplicable.
+func (mr *MockFeatureFlagOverrideRepositoryInterfaceMockRecorder) GetApplicable(ctx, flag, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplicable", reflect.TypeOf((*MockFeatureFlagOverrideRepositoryInterface)(nil).GetApplicable), ctx, flag, userID)
+}
+
+// List mocks base method.
+func (m *MockFeatureFlagOverrideRepositoryInterface) List(ctx context.Context) ([]models.FeatureFlagOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]models.FeatureFlagOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockFeatureFlagOverrideRepositoryInterfaceMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockFeatureFlagOverrideRepositoryInterface)(nil).List), ctx)
+}
+
+// Upsert mocks base method.
+func (m *MockFeatureFlagOverrideRepositoryInterface) Upsert(ctx context.Context, override *models.FeatureFlagOverride) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, override)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockFeatureFlagOverrideRepositoryInterfaceMockRecorder) Upsert(ctx, override interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockFeatureFlagOverrideRepositoryInterface)(nil).Upsert), ctx, override)
+}
+
+// MockRiskEvaluationRepositoryInterface is a mock of RiskEvaluationRepositoryInterface interface.
+type MockRiskEvaluationRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRiskEvaluationRepositoryInterfaceMockRecorder
+}
+
+// MockRiskEvaluationRepositoryInterfaceMockRecorder is the mock recorder for MockRiskEvaluationRepositoryInterface.
+type MockRiskEvaluationRepositoryInterfaceMockRecorder struct {
+	mock *MockRiskEvaluationRepositoryInterface
+}
+
+// NewMockRiskEvaluationRepositoryInterface creates a new mock instance.
+func NewMockRiskEvaluationRepositoryInterface(ctrl *gomock.Controller) *MockRiskEvaluationRepositoryInterface {
+	mock := &MockRiskEvaluationRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRiskEvaluationRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRiskEvaluationRepositoryInterface) EXPECT() *MockRiskEvaluationRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateBatch mocks base method.
+func (m *MockRiskEvaluationRepositoryInterface) CreateBatch(ctx context.Context, evaluations []models.RiskEvaluation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", ctx, evaluations)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockRiskEvaluationRepositoryInterfaceMockRecorder) CreateBatch(ctx, evaluations interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockRiskEvaluationRepositoryInterface)(nil).CreateBatch), ctx, evaluations)
+}
+
+// List mocks base method.
+func (m *MockRiskEvaluationRepositoryInterface) List(ctx context.Context, filters models.RiskEvaluationFilters, offset, limit int) ([]models.RiskEvaluation, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filters, offset, limit)
+	ret0, _ := ret[0].([]models.RiskEvaluation)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockRiskEvaluationRepositoryInterfaceMockRecorder) List(ctx, filters, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRiskEvaluationRepositoryInterface)(nil).List), ctx, filters, offset, limit)
+}
+
+// SummarizeByRule mocks base method.
+func (m *MockRiskEvaluationRepositoryInterface) SummarizeByRule(ctx context.Context, filters models.RiskEvaluationFilters) ([]models.RiskRuleSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SummarizeByRule", ctx, filters)
+	ret0, _ := ret[0].([]models.RiskRuleSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SummarizeByRule indicates an expected call of SummarizeByRule.
+func (mr *MockRiskEvaluationRepositoryInterfaceMockRecorder) SummarizeByRule(ctx, filters interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SummarizeByRule", reflect.TypeOf((*MockRiskEvaluationRepositoryInterface)(nil).SummarizeByRule), ctx, filters)
+}
+
+// MockRiskRuleOverrideRepositoryInterface is a mock of RiskRuleOverrideRepositoryInterface interface.
+type MockRiskRuleOverrideRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRiskRuleOverrideRepositoryInterfaceMockRecorder
+}
+
+// MockRiskRuleOverrideRepositoryInterfaceMockRecorder is the mock recorder for MockRiskRuleOverrideRepositoryInterface.
+type MockRiskRuleOverrideRepositoryInterfaceMockRecorder struct {
+	mock *MockRiskRuleOverrideRepositoryInterface
+}
+
+// NewMockRiskRuleOverrideRepositoryInterface creates a new mock instance.
+func NewMockRiskRuleOverrideRepositoryInterface(ctrl *gomock.Controller) *MockRiskRuleOverrideRepositoryInterface {
+	mock := &MockRiskRuleOverrideRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRiskRuleOverrideRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRiskRuleOverrideRepositoryInterface) EXPECT() *MockRiskRuleOverrideRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockRiskRuleOverrideRepositoryInterface) Delete(ctx context.Context, rule string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRiskRuleOverrideRepositoryInterfaceMockRecorder) Delete(ctx, rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRiskRuleOverrideRepositoryInterface)(nil).Delete), ctx, rule)
+}
+
+// List mocks base method.
+func (m *MockRiskRuleOverrideRepositoryInterface) List(ctx context.Context) ([]models.RiskRuleOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]models.RiskRuleOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRiskRuleOverrideRepositoryInterfaceMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRiskRuleOverrideRepositoryInterface)(nil).List), ctx)
+}
+
+// Upsert mocks base method.
+func (m *MockRiskRuleOverrideRepositoryInterface) Upsert(ctx context.Context, override *models.RiskRuleOverride) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, override)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockRiskRuleOverrideRepositoryInterfaceMockRecorder) Upsert(ctx, override interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockRiskRuleOverrideRepositoryInterface)(nil).Upsert), ctx, override)
+}
+
+// MockNotificationPreferenceRepositoryInterface is a mock of NotificationPreferenceRepositoryInterface interface.
+type MockNotificationPreferenceRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationPreferenceRepositoryInterfaceMockRecorder
+}
+
+// MockNotificationPreferenceRepositoryInterfaceMockRecorder is the mock recorder for MockNotificationPreferenceRepositoryInterface.
+type MockNotificationPreferenceRepositoryInterfaceMockRecorder struct {
+	mock *MockNotificationPreferenceRepositoryInterface
+}
+
+// NewMockNotificationPreferenceRepositoryInterface creates a new mock instance.
+func NewMockNotificationPreferenceRepositoryInterface(ctrl *gomock.Controller) *MockNotificationPreferenceRepositoryInterface {
+	mock := &MockNotificationPreferenceRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockNotificationPreferenceRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationPreferenceRepositoryInterface) EXPECT() *MockNotificationPreferenceRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockNotificationPreferenceRepositoryInterface) Get(ctx context.Context, userID uuid.UUID, eventType string) (*models.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID, eventType)
+	ret0, _ := ret[0].(*models.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockNotificationPreferenceRepositoryInterfaceMockRecorder) Get(ctx, userID, eventType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockNotificationPreferenceRepositoryInterface)(nil).Get), ctx, userID, eventType)
+}
+
+// ListByUser mocks base method.
+func (m *MockNotificationPreferenceRepositoryInterface) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID)
+	ret0, _ := ret[0].([]models.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockNotificationPreferenceRepositoryInterfaceMockRecorder) ListByUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockNotificationPreferenceRepositoryInterface)(nil).ListByUser), ctx, userID)
+}
+
+// Upsert mocks base method.
+func (m *MockNotificationPreferenceRepositoryInterface) Upsert(ctx context.Context, preference *models.NotificationPreference) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, preference)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockNotificationPreferenceRepositoryInterfaceMockRecorder) Upsert(ctx, preference interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockNotificationPreferenceRepositoryInterface)(nil).Upsert), ctx, preference)
+}
+
+// MockUserNotificationRepositoryInterface is a mock of UserNotificationRepositoryInterface interface.
+type MockUserNotificationRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserNotificationRepositoryInterfaceMockRecorder
+}
+
+// MockUserNotificationRepositoryInterfaceMockRecorder is the mock recorder for MockUserNotificationRepositoryInterface.
+type MockUserNotificationRepositoryInterfaceMockRecorder struct {
+	mock *MockUserNotificationRepositoryInterface
+}
+
+// NewMockUserNotificationRepositoryInterface creates a new mock instance.
+func NewMockUserNotificationRepositoryInterface(ctrl *gomock.Controller) *MockUserNotificationRepositoryInterface {
+	mock := &MockUserNotificationRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockUserNotificationRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserNotificationRepositoryInterface) EXPECT() *MockUserNotificationRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockUserNotificationRepositoryInterface) Create(ctx context.Context, notification *models.UserNotification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, notification)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockUserNotificationRepositoryInterfaceMockRecorder) Create(ctx, notification interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserNotificationRepositoryInterface)(nil).Create), ctx, notification)
+}
+
+// ListByUser mocks base method.
+func (m *MockUserNotificationRepositoryInterface) ListByUser(ctx context.Context, userID uuid.UUID, channel string) ([]models.UserNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID, channel)
+	ret0, _ := ret[0].([]models.UserNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockUserNotificationRepositoryInterfaceMockRecorder) ListByUser(ctx, userID, channel interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockUserNotificationRepositoryInterface)(nil).ListByUser), ctx, userID, channel)
+}
+
+// MockCanaryRunRepositoryInterface is a mock of CanaryRunRepositoryInterface interface.
+type MockCanaryRunRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCanaryRunRepositoryInterfaceMockRecorder
+}
+
+// MockCanaryRunRepositoryInterfaceMockRecorder is the mock recorder for MockCanaryRunRepositoryInterface.
+type MockCanaryRunRepositoryInterfaceMockRecorder struct {
+	mock *MockCanaryRunRepositoryInterface
+}
+
+// NewMockCanaryRunRepositoryInterface creates a new mock instance.
+func NewMockCanaryRunRepositoryInterface(ctrl *gomock.Controller) *MockCanaryRunRepositoryInterface {
+	mock := &MockCanaryRunRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockCanaryRunRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCanaryRunRepositoryInterface) EXPECT() *MockCanaryRunRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCanaryRunRepositoryInterface) Create(ctx context.Context, run *models.CanaryRun) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, run)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCanaryRunRepositoryInterfaceMockRecorder) Create(ctx, run interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCanaryRunRepositoryInterface)(nil).Create), ctx, run)
+}
+
+// ListFinished mocks base method.
+func (m *MockCanaryRunRepositoryInterface) ListFinished(ctx context.Context, limit int) ([]models.CanaryRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFinished", ctx, limit)
+	ret0, _ := ret[0].([]models.CanaryRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFinished indicates an expected call of ListFinished.
+func (mr *MockCanaryRunRepositoryInterfaceMockRecorder) ListFinished(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFinished", reflect.TypeOf((*MockCanaryRunRepositoryInterface)(nil).ListFinished), ctx, limit)
+}
+
+// Update mocks base method.
+func (m *MockCanaryRunRepositoryInterface) Update(ctx context.Context, run *models.CanaryRun) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, run)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCanaryRunRepositoryInterfaceMockRecorder) Update(ctx, run interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCanaryRunRepositoryInterface)(nil).Update), ctx, run)
+}
+
+// MockPollAnomalyRepositoryInterface is a mock of PollAnomalyRepositoryInterface interface.
+type MockPollAnomalyRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockPollAnomalyRepositoryInterfaceMockRecorder
+}
+
+// MockPollAnomalyRepositoryInterfaceMockRecorder is the mock recorder for MockPollAnomalyRepositoryInterface.
+type MockPollAnomalyRepositoryInterfaceMockRecorder struct {
+	mock *MockPollAnomalyRepositoryInterface
+}
+
+// NewMockPollAnomalyRepositoryInterface creates a new mock instance.
+func NewMockPollAnomalyRepositoryInterface(ctrl *gomock.Controller) *MockPollAnomalyRepositoryInterface {
+	mock := &MockPollAnomalyRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockPollAnomalyRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPollAnomalyRepositoryInterface) EXPECT() *MockPollAnomalyRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CountUnresolved mocks base method.
+func (m *MockPollAnomalyRepositoryInterface) CountUnresolved(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnresolved", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnresolved indicates an expected call of CountUnresolved.
+func (mr *MockPollAnomalyRepositoryInterfaceMockRecorder) CountUnresolved(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnresolved", reflect.TypeOf((*MockPollAnomalyRepositoryInterface)(nil).CountUnresolved), ctx)
+}
+
+// List mocks base method.
+func (m *MockPollAnomalyRepositoryInterface) List(ctx context.Context, resolved bool, offset, limit int) ([]models.PollAnomaly, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, resolved, offset, limit)
+	ret0, _ := ret[0].([]models.PollAnomaly)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockPollAnomalyRepositoryInterfaceMockRecorder) List(ctx, resolved, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPollAnomalyRepositoryInterface)(nil).List), ctx, resolved, offset, limit)
+}
+
+// ListUnresolved mocks base method.
+func (m *MockPollAnomalyRepositoryInterface) ListUnresolved(ctx context.Context, limit int) ([]models.PollAnomaly, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnresolved", ctx, limit)
+	ret0, _ := ret[0].([]models.PollAnomaly)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnresolved indicates an expected call of ListUnresolved.
+func (mr *MockPollAnomalyRepositoryInterfaceMockRecorder) ListUnresolved(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnresolved", reflect.TypeOf((*MockPollAnomalyRepositoryInterface)(nil).ListUnresolved), ctx, limit)
+}
+
+// Record mocks base method.
+func (m *MockPollAnomalyRepositoryInterface) Record(ctx context.Context, anomaly *models.PollAnomaly) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", ctx, anomaly)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockPollAnomalyRepositoryInterfaceMockRecorder) Record(ctx, anomaly interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockPollAnomalyRepositoryInterface)(nil).Record), ctx, anomaly)
+}
+
+// Resolve mocks base method.
+func (m *MockPollAnomalyRepositoryInterface) Resolve(ctx context.Context, id uuid.UUID, resolution string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", ctx, id, resolution)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockPollAnomalyRepositoryInterfaceMockRecorder) Resolve(ctx, id, resolution interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockPollAnomalyRepositoryInterface)(nil).Resolve), ctx, id, resolution)
+}
+
+// MockTransferDisputeRepositoryInterface is a mock of TransferDisputeRepositoryInterface interface.
+type MockTransferDisputeRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransferDisputeRepositoryInterfaceMockRecorder
+}
+
+// MockTransferDisputeRepositoryInterfaceMockRecorder is the mock recorder for MockTransferDisputeRepositoryInterface.
+type MockTransferDisputeRepositoryInterfaceMockRecorder struct {
+	mock *MockTransferDisputeRepositoryInterface
+}
+
+// NewMockTransferDisputeRepositoryInterface creates a new mock instance.
+func NewMockTransferDisputeRepositoryInterface(ctrl *gomock.Controller) *MockTransferDisputeRepositoryInterface {
+	mock := &MockTransferDisputeRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockTransferDisputeRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransferDisputeRepositoryInterface) EXPECT() *MockTransferDisputeRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTransferDisputeRepositoryInterface) Create(ctx context.Context, dispute *models.TransferDispute) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, dispute)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTransferDisputeRepositoryInterfaceMockRecorder) Create(ctx, dispute interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTransferDisputeRepositoryInterface)(nil).Create), ctx, dispute)
+}
+
+// GetByID mocks base method.
+func (m *MockTransferDisputeRepositoryInterface) GetByID(ctx context.Context, id uuid.UUID) (*models.TransferDispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*models.TransferDispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockTransferDisputeRepositoryInterfaceMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTransferDisputeRepositoryInterface)(nil).GetByID), ctx, id)
+}
+
+// GetUnresolvedByTransfer mocks base method.
+func (m *MockTransferDisputeRepositoryInterface) GetUnresolvedByTransfer(ctx context.Context, transferID uuid.UUID) (*models.TransferDispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnresolvedByTransfer", ctx, transferID)
+	ret0, _ := ret[0].(*models.TransferDispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnresolvedByTransfer indicates an expected call of GetUnresolvedByTransfer.
+func (mr *MockTransferDisputeRepositoryInterfaceMockRecorder) GetUnresolvedByTransfer(ctx, transferID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnresolvedByTransfer", reflect.TypeOf((*MockTransferDisputeRepositoryInterface)(nil).GetUnresolvedByTransfer), ctx, transferID)
+}
+
+// List mocks base method.
+func (m *MockTransferDisputeRepositoryInterface) List(ctx context.Context, status string, offset, limit int) ([]models.TransferDispute, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, status, offset, limit)
+	ret0, _ := ret[0].([]models.TransferDispute)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockTransferDisputeRepositoryInterfaceMockRecorder) List(ctx, status, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockTransferDisputeRepositoryInterface)(nil).List), ctx, status, offset, limit)
+}
+
+// ListByTransfer mocks base method.
+func (m *MockTransferDisputeRepositoryInterface) ListByTransfer(ctx context.Context, transferID uuid.UUID) ([]models.TransferDispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByTransfer", ctx, transferID)
+	ret0, _ := ret[0].([]models.TransferDispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByTransfer indicates an expected call of ListByTransfer.
+func (mr *MockTransferDisputeRepositoryInterfaceMockRecorder) ListByTransfer(ctx, transferID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByTransfer", reflect.TypeOf((*MockTransferDisputeRepositoryInterface)(nil).ListByTransfer), ctx, transferID)
+}
+
+// Transition mocks base method.
+func (m *MockTransferDisputeRepositoryInterface) Transition(ctx context.Context, dispute *models.TransferDispute, fromStatuses []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transition", ctx, dispute, fromStatuses)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transition indicates an expected call of Transition.
+func (mr *MockTransferDisputeRepositoryInterfaceMockRecorder) Transition(ctx, dispute, fromStatuses interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transition", reflect.TypeOf((*MockTransferDisputeRepositoryInterface)(nil).Transition), ctx, dispute, fromStatuses)
+}
+
+// MockBalanceAlertRuleRepositoryInterface is a mock of BalanceAlertRuleRepositoryInterface interface.
+type MockBalanceAlertRuleRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockBalanceAlertRuleRepositoryInterfaceMockRecorder
+}
+
+// MockBalanceAlertRuleRepositoryInterfaceMockRecorder is the mock recorder for MockBalanceAlertRuleRepositoryInterface.
+type MockBalanceAlertRuleRepositoryInterfaceMockRecorder struct {
+	mock *MockBalanceAlertRuleRepositoryInterface
+}
+
+// NewMockBalanceAlertRuleRepositoryInterface creates a new mock instance.
+func NewMockBalanceAlertRuleRepositoryInterface(ctrl *gomock.Controller) *MockBalanceAlertRuleRepositoryInterface {
+	mock := &MockBalanceAlertRuleRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockBalanceAlertRuleRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBalanceAlertRuleRepositoryInterface) EXPECT() *MockBalanceAlertRuleRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockBalanceAlertRuleRepositoryInterface) Create(ctx context.Context, rule *models.BalanceAlertRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockBalanceAlertRuleRepositoryInterfaceMockRecorder) Create(ctx, rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBalanceAlertRuleRepositoryInterface)(nil).Create), ctx, rule)
+}
+
+// Delete mocks base method.
+func (m *MockBalanceAlertRuleRepositoryInterface) Delete(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockBalanceAlertRuleRepositoryInterfaceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBalanceAlertRuleRepositoryInterface)(nil).Delete), ctx, id)
+}
+
+// GetByID mocks base method.
+func (m *MockBalanceAlertRuleRepositoryInterface) GetByID(ctx context.Context, id uuid.UUID) (*models.BalanceAlertRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*models.BalanceAlertRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockBalanceAlertRuleRepositoryInterfaceMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockBalanceAlertRuleRepositoryInterface)(nil).GetByID), ctx, id)
+}
+
+// ListByUser mocks base method.
+func (m *MockBalanceAlertRuleRepositoryInterface) ListByUser(ctx context.Context, userID uuid.UUID, offset, limit int) ([]models.BalanceAlertRule, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID, offset, limit)
+	ret0, _ := ret[0].([]models.BalanceAlertRule)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockBalanceAlertRuleRepositoryInterfaceMockRecorder) ListByUser(ctx, userID, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockBalanceAlertRuleRepositoryInterface)(nil).ListByUser), ctx, userID, offset, limit)
+}
+
+// ListForEvaluation mocks base method.
+func (m *MockBalanceAlertRuleRepositoryInterface) ListForEvaluation(ctx context.Context, frequentOnly bool) ([]models.BalanceAlertRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListForEvaluation", ctx, frequentOnly)
+	ret0, _ := ret[0].([]models.BalanceAlertRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListForEvaluation indicates an expected call of ListForEvaluation.
+func (mr *MockBalanceAlertRuleRepositoryInterfaceMockRecorder) ListForEvaluation(ctx, frequentOnly interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListForEvaluation", reflect.TypeOf((*MockBalanceAlertRuleRepositoryInterface)(nil).ListForEvaluation), ctx, frequentOnly)
+}
+
+// Update mocks base method.
+func (m *MockBalanceAlertRuleRepositoryInterface) Update(ctx context.Context, rule *models.BalanceAlertRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockBalanceAlertRuleRepositoryInterfaceMockRecorder) Update(ctx, rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBalanceAlertRuleRepositoryInterface)(nil).Update), ctx, rule)
+}
+
+// MockPersonalAccessTokenRepositoryInterface is a mock of PersonalAccessTokenRepositoryInterface interface.
+type MockPersonalAccessTokenRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockPersonalAccessTokenRepositoryInterfaceMockRecorder
+}
+
+// MockPersonalAccessTokenRepositoryInterfaceMockRecorder is the mock recorder for MockPersonalAccessTokenRepositoryInterface.
+type MockPersonalAccessTokenRepositoryInterfaceMockRecorder struct {
+	mock *MockPersonalAccessTokenRepositoryInterface
+}
+
+// NewMockPersonalAccessTokenRepositoryInterface creates a new mock instance.
+func NewMockPersonalAccessTokenRepositoryInterface(ctrl *gomock.Controller) *MockPersonalAccessTokenRepositoryInterface {
+	mock := &MockPersonalAccessTokenRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockPersonalAccessTokenRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPersonalAccessTokenRepositoryInterface) EXPECT() *MockPersonalAccessTokenRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPersonalAccessTokenRepositoryInterface) Create(ctx context.Context, token *models.PersonalAccessToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPersonalAccessTokenRepositoryInterfaceMockRecorder) Create(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPersonalAccessTokenRepositoryInterface)(nil).Create), ctx, token)
+}
+
+// GetByID mocks base method.
+func (m *MockPersonalAccessTokenRepositoryInterface) GetByID(ctx context.Context, id uuid.UUID) (*models.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*models.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockPersonalAccessTokenRepositoryInterfaceMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockPersonalAccessTokenRepositoryInterface)(nil).GetByID), ctx, id)
+}
+
+// GetByTokenHash mocks base method.
+func (m *MockPersonalAccessTokenRepositoryInterface) GetByTokenHash(ctx context.Context, tokenHash string) (*models.PersonalAccessToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByTokenHash", ctx, tokenHash)
+	ret0, _ := ret[0].(*models.PersonalAccessToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByTokenHash indicates an expected call of GetByTokenHash.
+func (mr *MockPersonalAccessTokenRepositoryInterfaceMockRecorder) GetByTokenHash(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByTokenHash", reflect.TypeOf((*MockPersonalAccessTokenRepositoryInterface)(nil).GetByTokenHash), ctx, tokenHash)
+}
+
+// ListByUser mocks base method.
+func (m *MockPersonalAccessTokenRepositoryInterface) ListByUser(ctx context.Context, userID uuid.UUID, offset, limit int) ([]models.PersonalAccessToken, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID, offset, limit)
+	ret0, _ := ret[0].([]models.PersonalAccessToken)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockPersonalAccessTokenRepositoryInterfaceMockRecorder) ListByUser(ctx, userID, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockPersonalAccessTokenRepositoryInterface)(nil).ListByUser), ctx, userID, offset, limit)
+}
+
+// Revoke mocks base method.
+func (m *MockPersonalAccessTokenRepositoryInterface) Revoke(ctx context.Context, id uuid.UUID, revokedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, id, revokedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockPersonalAccessTokenRepositoryInterfaceMockRecorder) Revoke(ctx, id, revokedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockPersonalAccessTokenRepositoryInterface)(nil).Revoke), ctx, id, revokedAt)
+}
+
+// TouchLastUsed mocks base method.
+func (m *MockPersonalAccessTokenRepositoryInterface) TouchLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchLastUsed", ctx, id, usedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchLastUsed indicates an expected call of TouchLastUsed.
+func (mr *MockPersonalAccessTokenRepositoryInterfaceMockRecorder) TouchLastUsed(ctx, id, usedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchLastUsed", reflect.TypeOf((*MockPersonalAccessTokenRepositoryInterface)(nil).TouchLastUsed), ctx, id, usedAt)
+}
+
+// MockProcessedWebhookEventRepositoryInterface is a mock of ProcessedWebhookEventRepositoryInterface interface.
+type MockProcessedWebhookEventRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockProcessedWebhookEventRepositoryInterfaceMockRecorder
+}
+
+// MockProcessedWebhookEventRepositoryInterfaceMockRecorder is the mock recorder for MockProcessedWebhookEventRepositoryInterface.
+type MockProcessedWebhookEventRepositoryInterfaceMockRecorder struct {
+	mock *MockProcessedWebhookEventRepositoryInterface
+}
+
+// NewMockProcessedWebhookEventRepositoryInterface creates a new mock instance.
+func NewMockProcessedWebhookEventRepositoryInterface(ctrl *gomock.Controller) *MockProcessedWebhookEventRepositoryInterface {
+	mock := &MockProcessedWebhookEventRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockProcessedWebhookEventRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProcessedWebhookEventRepositoryInterface) EXPECT() *MockProcessedWebhookEventRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockProcessedWebhookEventRepositoryInterface) Delete(ctx context.Context, eventID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, eventID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockProcessedWebhookEventRepositoryInterfaceMockRecorder) Delete(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProcessedWebhookEventRepositoryInterface)(nil).Delete), ctx, eventID)
+}
+
+// PruneBefore mocks base method.
+func (m *MockProcessedWebhookEventRepositoryInterface) PruneBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneBefore", ctx, cutoff)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneBefore indicates an expected call of PruneBefore.
+func (mr *MockProcessedWebhookEventRepositoryInterfaceMockRecorder) PruneBefore(ctx, cutoff interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneBefore", reflect.TypeOf((*MockProcessedWebhookEventRepositoryInterface)(nil).PruneBefore), ctx, cutoff)
+}
+
+// Record mocks base method.
+func (m *MockProcessedWebhookEventRepositoryInterface) Record(ctx context.Context, event *models.ProcessedWebhookEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockProcessedWebhookEventRepositoryInterfaceMockRecorder) Record(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockProcessedWebhookEventRepositoryInterface)(nil).Record), ctx, event)
+}
+
+// SetOutcome mocks base method.
+func (m *MockProcessedWebhookEventRepositoryInterface) SetOutcome(ctx context.Context, eventID, outcome string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOutcome", ctx, eventID, outcome)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOutcome indicates an expected call of SetOutcome.
+func (mr *MockProcessedWebhookEventRepositoryInterfaceMockRecorder) SetOutcome(ctx, eventID, outcome interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOutcome", reflect.TypeOf((*MockProcessedWebhookEventRepositoryInterface)(nil).SetOutcome), ctx, eventID, outcome)
+}
+
+// MockReadOnlyModeRepositoryInterface is a mock of ReadOnlyModeRepositoryInterface interface.
+type MockReadOnlyModeRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockReadOnlyModeRepositoryInterfaceMockRecorder
+}
+
+// MockReadOnlyModeRepositoryInterfaceMockRecorder is the mock recorder for MockReadOnlyModeRepositoryInterface.
+type MockReadOnlyModeRepositoryInterfaceMockRecorder struct {
+	mock *MockReadOnlyModeRepositoryInterface
+}
+
+// NewMockReadOnlyModeRepositoryInterface creates a new mock instance.
+func NewMockReadOnlyModeRepositoryInterface(ctrl *gomock.Controller) *MockReadOnlyModeRepositoryInterface {
+	mock := &MockReadOnlyModeRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockReadOnlyModeRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReadOnlyModeRepositoryInterface) EXPECT() *MockReadOnlyModeRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockReadOnlyModeRepositoryInterface) Get(ctx context.Context) (*models.ReadOnlyMode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx)
+	ret0, _ := ret[0].(*models.ReadOnlyMode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockReadOnlyModeRepositoryInterfaceMockRecorder) Get(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockReadOnlyModeRepositoryInterface)(nil).Get), ctx)
+}
+
+// Save mocks base method.
+func (m *MockReadOnlyModeRepositoryInterface) Save(ctx context.Context, mode *models.ReadOnlyMode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, mode)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockReadOnlyModeRepositoryInterfaceMockRecorder) Save(ctx, mode interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByNotificationID", reflect.TypeOf((*MockRegulatorNotificationAttemptRepositoryInterface)(nil).GetByNotificationID), notificationID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockReadOnlyModeRepositoryInterface)(nil).Save), ctx, mode)
 }
@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// retentionHold is the SQL condition that keeps a row past its retention period, such as it
+// being referenced by open work. The zero value holds nothing.
+type retentionHold struct {
+	query string
+	args  []interface{}
+}
+
+// countRetention counts the rows of table created before cutoff, split into those hold keeps
+// and the rest
+func countRetention(ctx context.Context, db *gorm.DB, table string, cutoff time.Time, hold retentionHold) (models.RetentionCount, error) {
+	pastRetention := func() *gorm.DB {
+		return db.WithContext(ctx).Table(table).Where("created_at < ?", cutoff)
+	}
+	var count models.RetentionCount
+	expired := pastRetention()
+	if hold.query != "" {
+		if err := pastRetention().Where(hold.query, hold.args...).Count(&count.Held).Error; err != nil {
+			return count, err
+		}
+		expired = expired.Where("NOT ("+hold.query+")", hold.args...)
+	}
+	err := expired.Count(&count.Expired).Error
+	return count, err
+}
+
+// purgeRetention hard-deletes up to limit rows of model created before cutoff that hold does not
+// keep. Each call is one short statement, so callers delete in batches.
+func purgeRetention(ctx context.Context, db *gorm.DB, model interface{}, table string, cutoff time.Time, hold retentionHold, limit int) (int64, error) {
+	batch := db.Table(table).Select("id").Where("created_at < ?", cutoff)
+	if hold.query != "" {
+		batch = batch.Where("NOT ("+hold.query+")", hold.args...)
+	}
+	res := db.WithContext(ctx).Where("id IN (?)", batch.Limit(limit)).Delete(model)
+	return res.RowsAffected, res.Error
+}
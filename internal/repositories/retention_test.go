@@ -0,0 +1,219 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/database"
+	"github.com/array/banking-api/internal/models"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/suite"
+)
+
+// RetentionSuite tests the retention counts and purges of the repositories
+type RetentionSuite struct {
+	suite.Suite
+	db      *database.DB
+	account *models.Account
+	cutoff  time.Time
+}
+
+// SetupTest runs before each test in the suite
+func (s *RetentionSuite) SetupTest() {
+	s.db = database.SetupTestDB(s.T())
+	s.Require().NoError(s.db.DB.AutoMigrate(
+		&models.NorthwindTransfer{},
+		&models.NorthwindTransferEvent{},
+		&models.RegulatorNotification{},
+		&models.PollAnomaly{},
+		&models.TransferDispute{},
+	))
+
+	user := database.CreateTestUser(s.T(), s.db, "retention@example.com")
+	s.account = &models.Account{
+		UserID:        user.ID,
+		AccountNumber: "1012345679",
+		AccountType:   models.AccountTypeChecking,
+		Balance:       decimal.NewFromFloat(1000),
+		Status:        models.AccountStatusActive,
+		Currency:      "USD",
+	}
+	s.Require().NoError(s.db.Create(s.account).Error)
+	s.cutoff = time.Now().UTC().AddDate(-7, 0, 0).Truncate(time.Second)
+}
+
+// TearDownTest runs after each test in the suite
+func (s *RetentionSuite) TearDownTest() {
+	database.CleanupTestDB(s.T(), s.db)
+}
+
+// TestRetentionSuite runs the test suite
+func TestRetentionSuite(t *testing.T) {
+	suite.Run(t, new(RetentionSuite))
+}
+
+func (s *RetentionSuite) transaction(createdAt time.Time, status string) *models.Transaction {
+	tx := &models.Transaction{
+		AccountID:       s.account.ID,
+		TransactionType: models.TransactionTypeCredit,
+		Amount:          decimal.NewFromFloat(10),
+		BalanceBefore:   decimal.NewFromFloat(1000),
+		BalanceAfter:    decimal.NewFromFloat(1010),
+		Description:     "deposit",
+		Status:          status,
+		CreatedAt:       createdAt,
+		UpdatedAt:       createdAt,
+	}
+	s.Require().NoError(s.db.Create(tx).Error)
+	return tx
+}
+
+func (s *RetentionSuite) northwindTransfer(createdAt time.Time, status string) *models.NorthwindTransfer {
+	userID := uuid.New()
+	transfer := &models.NorthwindTransfer{
+		UserID:                   &userID,
+		NorthwindTransferID:      uuid.New(),
+		Direction:                models.NWTransferDirectionOutbound,
+		TransferType:             models.NWTransferTypeACH,
+		Amount:                   decimal.NewFromFloat(100),
+		Currency:                 "USD",
+		ReferenceNumber:          "REF-" + uuid.NewString()[:8],
+		SourceAccountNumber:      "1111111111",
+		DestinationAccountNumber: "2222222222",
+		Status:                   status,
+		CreatedAt:                createdAt,
+		UpdatedAt:                createdAt,
+	}
+	s.Require().NoError(NewNorthwindTransferRepository(s.db.DB).Create(context.Background(), transfer))
+	return transfer
+}
+
+func (s *RetentionSuite) exists(model interface{}, id uuid.UUID) bool {
+	var count int64
+	s.Require().NoError(s.db.DB.Model(model).Where("id = ?", id).Count(&count).Error)
+	return count > 0
+}
+
+func (s *RetentionSuite) TestTransactions_CutoffIsExclusive() {
+	repo := NewTransactionRepository(s.db.DB)
+	expired := s.transaction(s.cutoff.Add(-time.Second), models.TransactionStatusCompleted)
+	atCutoff := s.transaction(s.cutoff, models.TransactionStatusCompleted)
+	recent := s.transaction(time.Now().UTC(), models.TransactionStatusCompleted)
+
+	count, err := repo.CountRetentionExpired(context.Background(), s.cutoff)
+	s.Require().NoError(err)
+	s.Equal(models.RetentionCount{Expired: 1}, count)
+
+	deleted, err := repo.PurgeRetentionExpired(context.Background(), s.cutoff, 100)
+	s.Require().NoError(err)
+	s.Equal(int64(1), deleted)
+	s.False(s.exists(&models.Transaction{}, expired.ID))
+	s.True(s.exists(&models.Transaction{}, atCutoff.ID), "a row exactly at the cutoff is still within retention")
+	s.True(s.exists(&models.Transaction{}, recent.ID))
+}
+
+func (s *RetentionSuite) TestTransactions_HoldsReferencedAndPendingRows() {
+	repo := NewTransactionRepository(s.db.DB)
+	old := s.cutoff.AddDate(0, -1, 0)
+	referenced := s.transaction(old, models.TransactionStatusCompleted)
+	pending := s.transaction(old, models.TransactionStatusPending)
+	queued := s.transaction(old, models.TransactionStatusCompleted)
+	free := s.transaction(old, models.TransactionStatusCompleted)
+
+	// The transfer is within its own retention, so its debit transaction must stay
+	s.Require().NoError(s.db.Create(&models.Transfer{
+		FromAccountID:      s.account.ID,
+		ToAccountID:        uuid.New(),
+		Amount:             decimal.NewFromFloat(10),
+		Description:        "transfer",
+		IdempotencyKey:     uuid.NewString(),
+		Status:             models.TransferStatusCompleted,
+		DebitTransactionID: &referenced.ID,
+	}).Error)
+	s.Require().NoError(s.db.Create(&models.ProcessingQueueItem{
+		TransactionID: queued.ID,
+		Operation:     "settle",
+		Status:        models.QueueStatusProcessing,
+		ScheduledAt:   time.Now(),
+	}).Error)
+
+	count, err := repo.CountRetentionExpired(context.Background(), s.cutoff)
+	s.Require().NoError(err)
+	s.Equal(models.RetentionCount{Expired: 1, Held: 3}, count)
+
+	deleted, err := repo.PurgeRetentionExpired(context.Background(), s.cutoff, 100)
+	s.Require().NoError(err)
+	s.Equal(int64(1), deleted)
+	s.False(s.exists(&models.Transaction{}, free.ID))
+	for _, held := range []*models.Transaction{referenced, pending, queued} {
+		s.True(s.exists(&models.Transaction{}, held.ID))
+	}
+}
+
+func (s *RetentionSuite) TestPurge_RespectsLimit() {
+	repo := NewTransactionRepository(s.db.DB)
+	for i := 0; i < 5; i++ {
+		s.transaction(s.cutoff.Add(-time.Hour), models.TransactionStatusCompleted)
+	}
+
+	deleted, err := repo.PurgeRetentionExpired(context.Background(), s.cutoff, 2)
+	s.Require().NoError(err)
+	s.Equal(int64(2), deleted)
+
+	count, err := repo.CountRetentionExpired(context.Background(), s.cutoff)
+	s.Require().NoError(err)
+	s.Equal(int64(3), count.Expired)
+}
+
+func (s *RetentionSuite) TestNorthwindTransfers_HoldsOpenWork() {
+	repo := NewNorthwindTransferRepository(s.db.DB)
+	old := s.cutoff.AddDate(-1, 0, 0)
+	inFlight := s.northwindTransfer(old, models.NWTransferStatusProcessing)
+	anomalous := s.northwindTransfer(old, models.NWTransferStatusCompleted)
+	undelivered := s.northwindTransfer(old, models.NWTransferStatusFailed)
+	disputed := s.northwindTransfer(old, models.NWTransferStatusCompleted)
+	free := s.northwindTransfer(old, models.NWTransferStatusCompleted)
+
+	s.Require().NoError(s.db.Create(&models.PollAnomaly{
+		TransferID:  anomalous.ID,
+		Reason:      "unknown_status",
+		RawBody:     "{}",
+		FirstSeenAt: old,
+		LastSeenAt:  old,
+	}).Error)
+	s.Require().NoError(s.db.Create(&models.RegulatorNotification{
+		TransferID:     undelivered.ID,
+		TerminalStatus: models.NWTransferStatusFailed,
+		Payload:        []byte("{}"),
+	}).Error)
+	// A dispute holds its transfer even once resolved
+	resolved := models.DisputeResolutionRejected
+	s.Require().NoError(s.db.Create(&models.TransferDispute{
+		TransferID:     disputed.ID,
+		UserID:         s.account.UserID,
+		ReasonCategory: models.DisputeReasonUnauthorized,
+		Status:         models.DisputeStatusResolved,
+		Resolution:     &resolved,
+	}).Error)
+	// A delivered notification does not hold its transfer
+	s.Require().NoError(s.db.Create(&models.RegulatorNotification{
+		TransferID:     free.ID,
+		TerminalStatus: models.NWTransferStatusCompleted,
+		Delivered:      true,
+		Payload:        []byte("{}"),
+	}).Error)
+
+	count, err := repo.CountRetentionExpired(context.Background(), s.cutoff)
+	s.Require().NoError(err)
+	s.Equal(models.RetentionCount{Expired: 1, Held: 4}, count)
+
+	deleted, err := repo.PurgeRetentionExpired(context.Background(), s.cutoff, 100)
+	s.Require().NoError(err)
+	s.Equal(int64(1), deleted)
+	s.False(s.exists(&models.NorthwindTransfer{}, free.ID))
+	for _, held := range []*models.NorthwindTransfer{inFlight, anomalous, undelivered, disputed} {
+		s.True(s.exists(&models.NorthwindTransfer{}, held.ID))
+	}
+}
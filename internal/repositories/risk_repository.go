@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/array/banking-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type riskEvaluationRepository struct {
+	db *gorm.DB
+}
+
+// NewRiskEvaluationRepository creates a new risk evaluation repository
+func NewRiskEvaluationRepository(db *gorm.DB) RiskEvaluationRepositoryInterface {
+	return &riskEvaluationRepository{db: db}
+}
+
+// CreateBatch stores the verdicts of one assessment together
+func (r *riskEvaluationRepository) CreateBatch(ctx context.Context, evaluations []models.RiskEvaluation) error {
+	if len(evaluations) == 0 {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).Create(&evaluations).Error; err != nil {
+		return fmt.Errorf("failed to create risk evaluations: %w", err)
+	}
+	return nil
+}
+
+// List returns a page of the evaluations matching filters, newest first, and how many match
+func (r *riskEvaluationRepository) List(ctx context.Context, filters models.RiskEvaluationFilters, offset, limit int) ([]models.RiskEvaluation, int64, error) {
+	query := r.filtered(ctx, filters)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count risk evaluations: %w", err)
+	}
+
+	var evaluations []models.RiskEvaluation
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&evaluations).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list risk evaluations: %w", err)
+	}
+	return evaluations, total, nil
+}
+
+// SummarizeByRule counts the evaluations matching filters per rule, ordered by rule
+func (r *riskEvaluationRepository) SummarizeByRule(ctx context.Context, filters models.RiskEvaluationFilters) ([]models.RiskRuleSummary, error) {
+	var summaries []models.RiskRuleSummary
+	if err := r.filtered(ctx, filters).
+		Select(`rule, COUNT(*) AS evaluated,
+			COUNT(CASE WHEN verdict = ? THEN 1 END) AS flagged,
+			COUNT(CASE WHEN blocked THEN 1 END) AS blocked`, models.RiskVerdictFlag).
+		Group("rule").
+		Order("rule ASC").
+		Scan(&summaries).Error; err != nil {
+		return nil, fmt.Errorf("failed to summarize risk evaluations: %w", err)
+	}
+	return summaries, nil
+}
+
+func (r *riskEvaluationRepository) filtered(ctx context.Context, filters models.RiskEvaluationFilters) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&models.RiskEvaluation{})
+	if filters.Rule != "" {
+		query = query.Where("rule = ?", filters.Rule)
+	}
+	if filters.Mode != "" {
+		query = query.Where("mode = ?", filters.Mode)
+	}
+	if filters.Verdict != "" {
+		query = query.Where("verdict = ?", filters.Verdict)
+	}
+	if filters.Blocked != nil {
+		query = query.Where("blocked = ?", *filters.Blocked)
+	}
+	if filters.UserID != nil {
+		query = query.Where("user_id = ?", *filters.UserID)
+	}
+	if filters.TransferID != nil {
+		query = query.Where("transfer_id = ?", *filters.TransferID)
+	}
+	if filters.TransferStatus != "" {
+		query = query.Where("transfer_id IN (?)", r.db.Model(&models.NorthwindTransfer{}).Select("id").Where("status = ?", filters.TransferStatus))
+	}
+	if filters.From != nil {
+		query = query.Where("created_at >= ?", *filters.From)
+	}
+	if filters.To != nil {
+		query = query.Where("created_at < ?", *filters.To)
+	}
+	return query
+}
+
+type riskRuleOverrideRepository struct {
+	db *gorm.DB
+}
+
+// NewRiskRuleOverrideRepository creates a new risk rule override repository
+func NewRiskRuleOverrideRepository(db *gorm.DB) RiskRuleOverrideRepositoryInterface {
+	return &riskRuleOverrideRepository{db: db}
+}
+
+// Upsert creates the rule's override or replaces the mode of the existing one
+func (r *riskRuleOverrideRepository) Upsert(ctx context.Context, override *models.RiskRuleOverride) error {
+	if override == nil {
+		return errors.New("override cannot be nil")
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing models.RiskRuleOverride
+		err := tx.Where("rule = ?", override.Rule).First(&existing).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if err := tx.Create(override).Error; err != nil {
+				return fmt.Errorf("failed to create risk rule override: %w", err)
+			}
+			return nil
+		case err != nil:
+			return fmt.Errorf("failed to get risk rule override: %w", err)
+		}
+
+		existing.Mode = override.Mode
+		existing.UpdatedBy = override.UpdatedBy
+		if err := tx.Save(&existing).Error; err != nil {
+			return fmt.Errorf("failed to update risk rule override: %w", err)
+		}
+		*override = existing
+		return nil
+	})
+}
+
+func (r *riskRuleOverrideRepository) Delete(ctx context.Context, rule string) error {
+	if err := r.db.WithContext(ctx).Where("rule = ?", rule).Delete(&models.RiskRuleOverride{}).Error; err != nil {
+		return fmt.Errorf("failed to delete risk rule override: %w", err)
+	}
+	return nil
+}
+
+func (r *riskRuleOverrideRepository) List(ctx context.Context) ([]models.RiskRuleOverride, error) {
+	var overrides []models.RiskRuleOverride
+	if err := r.db.WithContext(ctx).Order("rule ASC").Find(&overrides).Error; err != nil {
+		return nil, fmt.Errorf("failed to list risk rule overrides: %w", err)
+	}
+	return overrides, nil
+}
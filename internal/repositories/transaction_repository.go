@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -27,17 +28,17 @@ func NewTransactionRepository(db *gorm.DB) TransactionRepositoryInterface {
 }
 
 // Create creates a new transaction
-func (r *transactionRepository) Create(transaction *models.Transaction) error {
-	if err := r.db.Create(transaction).Error; err != nil {
+func (r *transactionRepository) Create(ctx context.Context, transaction *models.Transaction) error {
+	if err := r.db.WithContext(ctx).Create(transaction).Error; err != nil {
 		return fmt.Errorf("failed to create transaction: %w", err)
 	}
 	return nil
 }
 
 // GetByID retrieves a transaction by ID
-func (r *transactionRepository) GetByID(id uuid.UUID) (*models.Transaction, error) {
+func (r *transactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
 	transaction := &models.Transaction{ID: id}
-	if err := r.db.First(transaction).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(transaction).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrTransactionNotFound
 		}
@@ -47,17 +48,17 @@ func (r *transactionRepository) GetByID(id uuid.UUID) (*models.Transaction, erro
 }
 
 // GetByAccountID retrieves transactions for an account with pagination
-func (r *transactionRepository) GetByAccountID(accountID uuid.UUID, offset, limit int) ([]models.Transaction, int64, error) {
+func (r *transactionRepository) GetByAccountID(ctx context.Context, accountID uuid.UUID, offset, limit int) ([]models.Transaction, int64, error) {
 	var transactions []models.Transaction
 	var total int64
 
-	if err := r.db.Model(&models.Transaction{}).
+	if err := r.db.WithContext(ctx).Model(&models.Transaction{}).
 		Where("account_id = ?", accountID).
 		Count(&total).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to count transactions: %w", err)
 	}
 
-	if err := r.db.Where("account_id = ?", accountID).
+	if err := r.db.WithContext(ctx).Where("account_id = ?", accountID).
 		Offset(offset).Limit(limit).
 		Order("created_at DESC").
 		Find(&transactions).Error; err != nil {
@@ -67,10 +68,26 @@ func (r *transactionRepository) GetByAccountID(accountID uuid.UUID, offset, limi
 	return transactions, total, nil
 }
 
+// GetByAccountIDKeyset returns up to limit of the account's transactions, newest first, that come
+// after the given position, or from the newest when after is nil
+func (r *transactionRepository) GetByAccountIDKeyset(ctx context.Context, accountID uuid.UUID, after *models.AccountActivityKeyset, limit int) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+
+	query := r.db.WithContext(ctx).Where("account_id = ?", accountID)
+	if after != nil {
+		query = query.Where("created_at < ? OR (created_at = ? AND id < ?)", after.CreatedAt, after.CreatedAt, after.ID)
+	}
+
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Find(&transactions).Error; err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+	return transactions, nil
+}
+
 // GetByReference retrieves a transaction by reference
-func (r *transactionRepository) GetByReference(reference string) (*models.Transaction, error) {
+func (r *transactionRepository) GetByReference(ctx context.Context, reference string) (*models.Transaction, error) {
 	var transaction models.Transaction
-	if err := r.db.Where("reference = ?", reference).First(&transaction).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("reference = ?", reference).First(&transaction).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrTransactionNotFound
 		}
@@ -80,9 +97,9 @@ func (r *transactionRepository) GetByReference(reference string) (*models.Transa
 }
 
 // GetRecentByAccountID retrieves recent transactions for an account
-func (r *transactionRepository) GetRecentByAccountID(accountID uuid.UUID, limit int) ([]models.Transaction, error) {
+func (r *transactionRepository) GetRecentByAccountID(ctx context.Context, accountID uuid.UUID, limit int) ([]models.Transaction, error) {
 	var transactions []models.Transaction
-	if err := r.db.Where("account_id = ?", accountID).
+	if err := r.db.WithContext(ctx).Where("account_id = ?", accountID).
 		Order("created_at DESC").
 		Limit(limit).
 		Find(&transactions).Error; err != nil {
@@ -92,9 +109,9 @@ func (r *transactionRepository) GetRecentByAccountID(accountID uuid.UUID, limit
 }
 
 // GetByDateRange retrieves transactions within a date range
-func (r *transactionRepository) GetByDateRange(accountID uuid.UUID, startDate, endDate time.Time) ([]models.Transaction, error) {
+func (r *transactionRepository) GetByDateRange(ctx context.Context, accountID uuid.UUID, startDate, endDate time.Time) ([]models.Transaction, error) {
 	var transactions []models.Transaction
-	if err := r.db.Where("account_id = ? AND created_at BETWEEN ? AND ?", accountID, startDate, endDate).
+	if err := r.db.WithContext(ctx).Where("account_id = ? AND created_at BETWEEN ? AND ?", accountID, startDate, endDate).
 		Order("created_at DESC").
 		Find(&transactions).Error; err != nil {
 		return nil, fmt.Errorf("failed to get transactions by date range: %w", err)
@@ -103,12 +120,12 @@ func (r *transactionRepository) GetByDateRange(accountID uuid.UUID, startDate, e
 }
 
 // CreateBatch creates multiple transactions in a single database transaction
-func (r *transactionRepository) CreateBatch(transactions []models.Transaction) error {
+func (r *transactionRepository) CreateBatch(ctx context.Context, transactions []models.Transaction) error {
 	if len(transactions) == 0 {
 		return nil
 	}
 
-	return r.db.Transaction(func(tx *gorm.DB) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Create(&transactions).Error; err != nil {
 			return fmt.Errorf("failed to create batch transactions: %w", err)
 		}
@@ -117,9 +134,9 @@ func (r *transactionRepository) CreateBatch(transactions []models.Transaction) e
 }
 
 // GetPendingTransactions retrieves all pending transactions
-func (r *transactionRepository) GetPendingTransactions(offset, limit int) ([]models.Transaction, error) {
+func (r *transactionRepository) GetPendingTransactions(ctx context.Context, offset, limit int) ([]models.Transaction, error) {
 	var transactions []models.Transaction
-	if err := r.db.Where("status = ?", models.TransactionStatusPending).
+	if err := r.db.WithContext(ctx).Where("status = ?", models.TransactionStatusPending).
 		Offset(offset).Limit(limit).
 		Order("created_at ASC").
 		Find(&transactions).Error; err != nil {
@@ -129,9 +146,9 @@ func (r *transactionRepository) GetPendingTransactions(offset, limit int) ([]mod
 }
 
 // UpdateStatus updates the status of a transaction
-func (r *transactionRepository) UpdateStatus(id uuid.UUID, status string) error {
+func (r *transactionRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status string) error {
 	now := time.Now()
-	result := r.db.Model(&models.Transaction{ID: id}).
+	result := r.db.WithContext(ctx).Model(&models.Transaction{ID: id}).
 		Updates(map[string]interface{}{
 			"status":       status,
 			"processed_at": now,
@@ -148,12 +165,12 @@ func (r *transactionRepository) UpdateStatus(id uuid.UUID, status string) error
 }
 
 // GetTotalsByAccountID calculates transaction totals for an account
-func (r *transactionRepository) GetTotalsByAccountID(accountID uuid.UUID) (credits, debits int64, creditAmount, debitAmount string, err error) {
+func (r *transactionRepository) GetTotalsByAccountID(ctx context.Context, accountID uuid.UUID) (credits, debits int64, creditAmount, debitAmount string, err error) {
 	var creditResult struct {
 		Count  int64
 		Amount string
 	}
-	if err := r.db.Model(&models.Transaction{}).
+	if err := r.db.WithContext(ctx).Model(&models.Transaction{}).
 		Select("COUNT(*) as count, COALESCE(SUM(amount), 0) as amount").
 		Where("account_id = ? AND transaction_type = ? AND status = ?",
 			accountID, models.TransactionTypeCredit, models.TransactionStatusCompleted).
@@ -165,7 +182,7 @@ func (r *transactionRepository) GetTotalsByAccountID(accountID uuid.UUID) (credi
 		Count  int64
 		Amount string
 	}
-	if err := r.db.Model(&models.Transaction{}).
+	if err := r.db.WithContext(ctx).Model(&models.Transaction{}).
 		Select("COUNT(*) as count, COALESCE(SUM(amount), 0) as amount").
 		Where("account_id = ? AND transaction_type = ? AND status = ?",
 			accountID, models.TransactionTypeDebit, models.TransactionStatusCompleted).
@@ -177,11 +194,11 @@ func (r *transactionRepository) GetTotalsByAccountID(accountID uuid.UUID) (credi
 }
 
 // GetByCategory retrieves transactions by category
-func (r *transactionRepository) GetByCategory(accountID uuid.UUID, category string, offset, limit int) ([]models.Transaction, int64, error) {
+func (r *transactionRepository) GetByCategory(ctx context.Context, accountID uuid.UUID, category string, offset, limit int) ([]models.Transaction, int64, error) {
 	var transactions []models.Transaction
 	var total int64
 
-	query := r.db.Model(&models.Transaction{}).
+	query := r.db.WithContext(ctx).Model(&models.Transaction{}).
 		Where("account_id = ? AND category = ?", accountID, category)
 
 	if err := query.Count(&total).Error; err != nil {
@@ -198,11 +215,11 @@ func (r *transactionRepository) GetByCategory(accountID uuid.UUID, category stri
 }
 
 // GetWithFilters retrieves transactions with multiple filters
-func (r *transactionRepository) GetWithFilters(filters models.TransactionFilters) ([]models.Transaction, int64, error) {
+func (r *transactionRepository) GetWithFilters(ctx context.Context, filters models.TransactionFilters) ([]models.Transaction, int64, error) {
 	var transactions []models.Transaction
 	var total int64
 
-	query := r.db.Model(&models.Transaction{})
+	query := r.db.WithContext(ctx).Model(&models.Transaction{})
 
 	if filters.AccountID != uuid.Nil {
 		query = query.Where("account_id = ?", filters.AccountID)
@@ -246,9 +263,9 @@ func (r *transactionRepository) GetWithFilters(filters models.TransactionFilters
 }
 
 // UpdateWithOptimisticLock updates a transaction with optimistic locking
-func (r *transactionRepository) UpdateWithOptimisticLock(transaction *models.Transaction, expectedVersion int) error {
+func (r *transactionRepository) UpdateWithOptimisticLock(ctx context.Context, transaction *models.Transaction, expectedVersion int) error {
 	// Use transaction as model so BeforeUpdate callback receives the full struct (including AccountID)
-	result := r.db.Model(transaction).
+	result := r.db.WithContext(ctx).Model(transaction).
 		Where("version = ?", expectedVersion).
 		Updates(transaction)
 
@@ -263,11 +280,11 @@ func (r *transactionRepository) UpdateWithOptimisticLock(transaction *models.Tra
 }
 
 // GetExpiredPendingTransactions retrieves pending transactions that have expired
-func (r *transactionRepository) GetExpiredPendingTransactions(limit int) ([]models.Transaction, error) {
+func (r *transactionRepository) GetExpiredPendingTransactions(ctx context.Context, limit int) ([]models.Transaction, error) {
 	var transactions []models.Transaction
 	now := time.Now()
 
-	if err := r.db.Where("status = ? AND pending_until IS NOT NULL AND pending_until < ?",
+	if err := r.db.WithContext(ctx).Where("status = ? AND pending_until IS NOT NULL AND pending_until < ?",
 		models.TransactionStatusPending, now).
 		Limit(limit).
 		Order("pending_until ASC").
@@ -279,7 +296,7 @@ func (r *transactionRepository) GetExpiredPendingTransactions(limit int) ([]mode
 }
 
 // GetCategorySummary retrieves transaction summary grouped by category
-func (r *transactionRepository) GetCategorySummary(accountID uuid.UUID, startDate, endDate time.Time) ([]models.CategorySummary, error) {
+func (r *transactionRepository) GetCategorySummary(ctx context.Context, accountID uuid.UUID, startDate, endDate time.Time) ([]models.CategorySummary, error) {
 	var summaries []models.CategorySummary
 
 	query := `
@@ -297,10 +314,40 @@ func (r *transactionRepository) GetCategorySummary(accountID uuid.UUID, startDat
 		ORDER BY total_amount DESC
 	`
 
-	if err := r.db.Raw(query, accountID, startDate, endDate, models.TransactionStatusCompleted).
+	if err := r.db.WithContext(ctx).Raw(query, accountID, startDate, endDate, models.TransactionStatusCompleted).
 		Scan(&summaries).Error; err != nil {
 		return nil, fmt.Errorf("failed to get category summary: %w", err)
 	}
 
 	return summaries, nil
 }
+
+// transactionRetentionHold keeps transactions that are still pending, are the debit or credit of
+// a transfer that has not been purged, or have queue work not finished yet
+func transactionRetentionHold() retentionHold {
+	return retentionHold{
+		query: "status = ? OR EXISTS (SELECT 1 FROM transfers t WHERE t.debit_transaction_id = transactions.id OR t.credit_transaction_id = transactions.id) " +
+			"OR EXISTS (SELECT 1 FROM transaction_processing_queue q WHERE q.transaction_id = transactions.id AND q.status IN ?)",
+		args: []interface{}{models.TransactionStatusPending, []string{models.QueueStatusPending, models.QueueStatusProcessing}},
+	}
+}
+
+// CountRetentionExpired counts the transactions created before cutoff, split into those that can
+// be purged and those still held
+func (r *transactionRepository) CountRetentionExpired(ctx context.Context, cutoff time.Time) (models.RetentionCount, error) {
+	count, err := countRetention(ctx, r.db, "transactions", cutoff, transactionRetentionHold())
+	if err != nil {
+		return count, fmt.Errorf("failed to count expired transactions: %w", err)
+	}
+	return count, nil
+}
+
+// PurgeRetentionExpired hard-deletes up to limit purgeable transactions created before cutoff.
+// Their finished queue items go with them.
+func (r *transactionRepository) PurgeRetentionExpired(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	deleted, err := purgeRetention(ctx, r.db, &models.Transaction{}, "transactions", cutoff, transactionRetentionHold(), limit)
+	if err != nil {
+		return deleted, fmt.Errorf("failed to purge expired transactions: %w", err)
+	}
+	return deleted, nil
+}
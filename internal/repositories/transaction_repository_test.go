@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -54,7 +55,7 @@ func (s *TransactionRepositorySuite) TestCreate() {
 		Reference:       models.GenerateTransactionReference(),
 		Status:          models.TransactionStatusCompleted,
 	}
-	err := s.repo.Create(tx)
+	err := s.repo.Create(context.Background(), tx)
 	s.NoError(err)
 	s.NotEqual(uuid.Nil, tx.ID)
 	s.NotZero(tx.CreatedAt)
@@ -71,9 +72,9 @@ func (s *TransactionRepositorySuite) TestGetByID_Success() {
 		Reference:       models.GenerateTransactionReference(),
 		Status:          models.TransactionStatusCompleted,
 	}
-	s.NoError(s.repo.Create(tx))
+	s.NoError(s.repo.Create(context.Background(), tx))
 
-	found, err := s.repo.GetByID(tx.ID)
+	found, err := s.repo.GetByID(context.Background(), tx.ID)
 	s.NoError(err)
 	s.Equal(tx.ID, found.ID)
 	s.Equal(tx.AccountID, found.AccountID)
@@ -81,7 +82,7 @@ func (s *TransactionRepositorySuite) TestGetByID_Success() {
 }
 
 func (s *TransactionRepositorySuite) TestGetByID_NotFound() {
-	_, err := s.repo.GetByID(uuid.New())
+	_, err := s.repo.GetByID(context.Background(), uuid.New())
 	s.Error(err)
 	s.Equal(ErrTransactionNotFound, err)
 }
@@ -99,15 +100,15 @@ func (s *TransactionRepositorySuite) TestGetByAccountID_Pagination() {
 			Reference:       models.GenerateTransactionReference(),
 			Status:          models.TransactionStatusCompleted,
 		}
-		s.NoError(s.repo.Create(tx))
+		s.NoError(s.repo.Create(context.Background(), tx))
 	}
 
-	list, total, err := s.repo.GetByAccountID(s.testAcct.ID, 0, 2)
+	list, total, err := s.repo.GetByAccountID(context.Background(), s.testAcct.ID, 0, 2)
 	s.NoError(err)
 	s.Equal(int64(5), total)
 	s.Len(list, 2)
 
-	list2, total2, err := s.repo.GetByAccountID(s.testAcct.ID, 2, 2)
+	list2, total2, err := s.repo.GetByAccountID(context.Background(), s.testAcct.ID, 2, 2)
 	s.NoError(err)
 	s.Equal(int64(5), total2)
 	s.Len(list2, 2)
@@ -125,16 +126,16 @@ func (s *TransactionRepositorySuite) TestGetByReference() {
 		Reference:       ref,
 		Status:          models.TransactionStatusCompleted,
 	}
-	s.NoError(s.repo.Create(tx))
+	s.NoError(s.repo.Create(context.Background(), tx))
 
-	found, err := s.repo.GetByReference(ref)
+	found, err := s.repo.GetByReference(context.Background(), ref)
 	s.NoError(err)
 	s.Equal(ref, found.Reference)
 	s.Equal(tx.ID, found.ID)
 }
 
 func (s *TransactionRepositorySuite) TestGetByReference_NotFound() {
-	_, err := s.repo.GetByReference("nonexistent-ref")
+	_, err := s.repo.GetByReference(context.Background(), "nonexistent-ref")
 	s.Error(err)
 	s.Equal(ErrTransactionNotFound, err)
 }
@@ -151,16 +152,16 @@ func (s *TransactionRepositorySuite) TestUpdateWithOptimisticLock() {
 		Status:          models.TransactionStatusPending,
 		Version:         1,
 	}
-	s.NoError(s.repo.Create(tx))
+	s.NoError(s.repo.Create(context.Background(), tx))
 
 	tx.Status = models.TransactionStatusCompleted
 	now := time.Now()
 	tx.ProcessedAt = &now
 	// Keep AccountID set so BeforeUpdate validation sees it when repo calls Updates(tx)
-	err := s.repo.UpdateWithOptimisticLock(tx, 1)
+	err := s.repo.UpdateWithOptimisticLock(context.Background(), tx, 1)
 	s.NoError(err)
 
-	updated, err := s.repo.GetByID(tx.ID)
+	updated, err := s.repo.GetByID(context.Background(), tx.ID)
 	s.NoError(err)
 	s.Equal(models.TransactionStatusCompleted, updated.Status)
 }
@@ -177,10 +178,10 @@ func (s *TransactionRepositorySuite) TestUpdateWithOptimisticLock_Conflict() {
 		Status:          models.TransactionStatusCompleted,
 		Version:         1,
 	}
-	s.NoError(s.repo.Create(tx))
+	s.NoError(s.repo.Create(context.Background(), tx))
 
 	tx.Version = 2
-	err := s.repo.UpdateWithOptimisticLock(tx, 99) // wrong expected version; AccountID already set from create
+	err := s.repo.UpdateWithOptimisticLock(context.Background(), tx, 99) // wrong expected version; AccountID already set from create
 	s.Error(err)
 	s.Equal(models.ErrOptimisticLockConflict, err)
 }
@@ -196,7 +197,7 @@ func (s *TransactionRepositorySuite) TestGetPendingTransactions() {
 		Reference:       models.GenerateTransactionReference(),
 		Status:          models.TransactionStatusPending,
 	}
-	s.NoError(s.repo.Create(tx1))
+	s.NoError(s.repo.Create(context.Background(), tx1))
 
 	tx2 := &models.Transaction{
 		AccountID:       s.testAcct.ID,
@@ -208,9 +209,9 @@ func (s *TransactionRepositorySuite) TestGetPendingTransactions() {
 		Reference:       models.GenerateTransactionReference(),
 		Status:          models.TransactionStatusCompleted,
 	}
-	s.NoError(s.repo.Create(tx2))
+	s.NoError(s.repo.Create(context.Background(), tx2))
 
-	pending, err := s.repo.GetPendingTransactions(0, 10)
+	pending, err := s.repo.GetPendingTransactions(context.Background(), 0, 10)
 	s.NoError(err)
 	s.GreaterOrEqual(len(pending), 1)
 	for _, p := range pending {
@@ -231,10 +232,10 @@ func (s *TransactionRepositorySuite) TestGetRecentByAccountID() {
 			Reference:       models.GenerateTransactionReference(),
 			Status:          models.TransactionStatusCompleted,
 		}
-		s.NoError(s.repo.Create(tx))
+		s.NoError(s.repo.Create(context.Background(), tx))
 	}
 
-	recent, err := s.repo.GetRecentByAccountID(s.testAcct.ID, 2)
+	recent, err := s.repo.GetRecentByAccountID(context.Background(), s.testAcct.ID, 2)
 	s.NoError(err)
 	s.LessOrEqual(len(recent), 2)
 }
@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTransferDisputeNotFound = errors.New("transfer dispute not found")
+	// ErrTransferDisputeAlreadyOpen is returned when the transfer already has an unresolved dispute
+	ErrTransferDisputeAlreadyOpen = errors.New("transfer already has an unresolved dispute")
+	// ErrTransferDisputeStatusChanged is returned by Transition when the dispute is no longer in
+	// one of the statuses it may move from, as when another admin resolved it first
+	ErrTransferDisputeStatusChanged = errors.New("transfer dispute status changed")
+)
+
+type transferDisputeRepository struct {
+	db *gorm.DB
+}
+
+// NewTransferDisputeRepository creates a new transfer dispute repository
+func NewTransferDisputeRepository(db *gorm.DB) TransferDisputeRepositoryInterface {
+	return &transferDisputeRepository{db: db}
+}
+
+// Create stores a new dispute. The unique index on unresolved disputes turns a second one for the
+// same transfer into ErrTransferDisputeAlreadyOpen.
+func (r *transferDisputeRepository) Create(ctx context.Context, dispute *models.TransferDispute) error {
+	if dispute == nil {
+		return errors.New("dispute cannot be nil")
+	}
+	if err := r.db.WithContext(ctx).Create(dispute).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) || isDuplicateKeyError(err) {
+			return ErrTransferDisputeAlreadyOpen
+		}
+		return fmt.Errorf("failed to create transfer dispute: %w", err)
+	}
+	return nil
+}
+
+func (r *transferDisputeRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.TransferDispute, error) {
+	var dispute models.TransferDispute
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&dispute).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTransferDisputeNotFound
+		}
+		return nil, fmt.Errorf("failed to get transfer dispute: %w", err)
+	}
+	return &dispute, nil
+}
+
+// GetUnresolvedByTransfer returns the transfer's unresolved dispute, or ErrTransferDisputeNotFound
+func (r *transferDisputeRepository) GetUnresolvedByTransfer(ctx context.Context, transferID uuid.UUID) (*models.TransferDispute, error) {
+	var dispute models.TransferDispute
+	if err := r.db.WithContext(ctx).
+		Where("transfer_id = ? AND status <> ?", transferID, models.DisputeStatusResolved).
+		First(&dispute).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTransferDisputeNotFound
+		}
+		return nil, fmt.Errorf("failed to get unresolved transfer dispute: %w", err)
+	}
+	return &dispute, nil
+}
+
+// ListByTransfer returns every dispute of the transfer, newest first
+func (r *transferDisputeRepository) ListByTransfer(ctx context.Context, transferID uuid.UUID) ([]models.TransferDispute, error) {
+	var disputes []models.TransferDispute
+	if err := r.db.WithContext(ctx).Where("transfer_id = ?", transferID).
+		Order("created_at DESC").
+		Find(&disputes).Error; err != nil {
+		return nil, fmt.Errorf("failed to list transfer disputes: %w", err)
+	}
+	return disputes, nil
+}
+
+// List returns a page of disputes in status, or in any status when it is empty, oldest first so
+// reviewers work through them in order, and the total number of them
+func (r *transferDisputeRepository) List(ctx context.Context, status string, offset, limit int) ([]models.TransferDispute, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.TransferDispute{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count transfer disputes: %w", err)
+	}
+	var disputes []models.TransferDispute
+	if err := query.Order("created_at ASC").Offset(offset).Limit(limit).Find(&disputes).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list transfer disputes: %w", err)
+	}
+	return disputes, total, nil
+}
+
+// Transition stores the dispute's status, resolution and reviewer, provided the stored dispute is
+// still in one of fromStatuses; otherwise it returns ErrTransferDisputeStatusChanged
+func (r *transferDisputeRepository) Transition(ctx context.Context, dispute *models.TransferDispute, fromStatuses []string) error {
+	dispute.UpdatedAt = time.Now()
+	res := r.db.WithContext(ctx).Model(&models.TransferDispute{}).
+		Where("id = ? AND status IN ?", dispute.ID, fromStatuses).
+		Updates(map[string]interface{}{
+			"status":          dispute.Status,
+			"resolution":      dispute.Resolution,
+			"resolution_note": dispute.ResolutionNote,
+			"reviewer_id":     dispute.ReviewerID,
+			"resolved_at":     dispute.ResolvedAt,
+			"updated_at":      dispute.UpdatedAt,
+		})
+	if res.Error != nil {
+		return fmt.Errorf("failed to update transfer dispute: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrTransferDisputeStatusChanged
+	}
+	return nil
+}
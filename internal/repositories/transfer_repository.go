@@ -1,8 +1,10 @@
 package repositories
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/array/banking-api/internal/models"
 	"github.com/google/uuid"
@@ -149,3 +151,27 @@ func (r *transferRepository) CountByUserAccounts(accountIDs []uuid.UUID) (int64,
 
 	return count, nil
 }
+
+// transferRetentionHold keeps transfers that are still pending
+func transferRetentionHold() retentionHold {
+	return retentionHold{query: "status = ?", args: []interface{}{models.TransferStatusPending}}
+}
+
+// CountRetentionExpired counts the transfers created before cutoff, split into those that can be
+// purged and those still held
+func (r *transferRepository) CountRetentionExpired(ctx context.Context, cutoff time.Time) (models.RetentionCount, error) {
+	count, err := countRetention(ctx, r.db, "transfers", cutoff, transferRetentionHold())
+	if err != nil {
+		return count, fmt.Errorf("failed to count expired transfers: %w", err)
+	}
+	return count, nil
+}
+
+// PurgeRetentionExpired hard-deletes up to limit purgeable transfers created before cutoff
+func (r *transferRepository) PurgeRetentionExpired(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	deleted, err := purgeRetention(ctx, r.db, &models.Transfer{}, "transfers", cutoff, transferRetentionHold(), limit)
+	if err != nil {
+		return deleted, fmt.Errorf("failed to purge expired transfers: %w", err)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,425 @@
+// Package seed creates a deterministic development fixture set: users with known credentials,
+// internal accounts with balances, registered external accounts, NorthWind transfers in every
+// status, and regulator notifications in each delivery state.
+//
+// Records are created through the services and repositories used by the API, so model hooks,
+// validation and field encryption all apply. Seeding is idempotent: users are matched by email,
+// accounts by type, external accounts by account/routing number and transfers by reference.
+package seed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/services"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// Password is the password of every seeded user
+const Password = "DevPassword123!"
+
+// ErrEnvironmentNotAllowed is returned when seeding is attempted outside development/testing
+var ErrEnvironmentNotAllowed = errors.New("seeding is only allowed in development and testing environments")
+
+// nwTransferNamespace derives stable NorthWind transfer IDs from seed references
+var nwTransferNamespace = uuid.MustParse("5d3f7a52-6a8e-4c1b-9f0e-3b2c1d4e5f60")
+
+type userFixture struct {
+	email     string
+	firstName string
+	lastName  string
+	role      string
+	accounts  map[string]int64 // account type -> initial deposit
+}
+
+type externalAccountFixture struct {
+	owner         string
+	holderName    string
+	accountNumber string
+	routingNumber string
+	institution   string
+}
+
+// notificationState is the regulator delivery state seeded for a terminal transfer
+type notificationState int
+
+const (
+	notificationNone notificationState = iota
+	notificationPending
+	notificationRetrying
+	notificationDelivered
+)
+
+type transferFixture struct {
+	owner        string
+	reference    string
+	direction    string
+	status       string
+	amount       int64
+	notification notificationState
+}
+
+var users = []userFixture{
+	{email: "dev.admin@example.com", firstName: "Dev", lastName: "Admin", role: models.RoleAdmin},
+	{email: "dev.alice@example.com", firstName: "Alice", lastName: "Developer", role: models.RoleCustomer,
+		accounts: map[string]int64{models.AccountTypeChecking: 5000, models.AccountTypeSavings: 12000}},
+	{email: "dev.bob@example.com", firstName: "Bob", lastName: "Developer", role: models.RoleCustomer,
+		accounts: map[string]int64{models.AccountTypeChecking: 2500}},
+}
+
+var externalAccounts = []externalAccountFixture{
+	{owner: "dev.alice@example.com", holderName: "Alice Developer", accountNumber: "4000000001", routingNumber: "021000021", institution: "JPMorgan Chase"},
+	{owner: "dev.bob@example.com", holderName: "Bob Developer", accountNumber: "4000000002", routingNumber: "011000138", institution: "Bank of America"},
+}
+
+var transfers = []transferFixture{
+	{owner: "dev.alice@example.com", reference: "SEED-ALICE-PENDING", direction: models.NWTransferDirectionOutbound, status: models.NWTransferStatusPending, amount: 100},
+	{owner: "dev.alice@example.com", reference: "SEED-ALICE-PROCESSING", direction: models.NWTransferDirectionOutbound, status: models.NWTransferStatusProcessing, amount: 200},
+	{owner: "dev.alice@example.com", reference: "SEED-ALICE-COMPLETED", direction: models.NWTransferDirectionOutbound, status: models.NWTransferStatusCompleted, amount: 300, notification: notificationDelivered},
+	{owner: "dev.alice@example.com", reference: "SEED-ALICE-FAILED", direction: models.NWTransferDirectionOutbound, status: models.NWTransferStatusFailed, amount: 400, notification: notificationRetrying},
+	{owner: "dev.alice@example.com", reference: "SEED-ALICE-CANCELLED", direction: models.NWTransferDirectionOutbound, status: models.NWTransferStatusCancelled, amount: 500},
+	{owner: "dev.alice@example.com", reference: "SEED-ALICE-REVERSED", direction: models.NWTransferDirectionOutbound, status: models.NWTransferStatusReversed, amount: 600, notification: notificationPending},
+	{owner: "dev.bob@example.com", reference: "SEED-BOB-INBOUND", direction: models.NWTransferDirectionInbound, status: models.NWTransferStatusCompleted, amount: 750, notification: notificationDelivered},
+}
+
+// Counts tallies created and skipped (already present) records of one kind
+type Counts struct {
+	Created int `json:"created"`
+	Skipped int `json:"skipped"`
+}
+
+// Summary reports what a seeding run did
+type Summary struct {
+	Users            Counts `json:"users"`
+	Accounts         Counts `json:"accounts"`
+	ExternalAccounts Counts `json:"external_accounts"`
+	Transfers        Counts `json:"transfers"`
+	Notifications    Counts `json:"notifications"`
+}
+
+// Seeder creates the development fixture set
+type Seeder struct {
+	userRepo        repositories.UserRepositoryInterface
+	accountRepo     repositories.AccountRepositoryInterface
+	extAccountRepo  repositories.NorthwindExternalAccountRepositoryInterface
+	transferRepo    repositories.NorthwindTransferRepositoryInterface
+	notifRepo       repositories.RegulatorNotificationRepositoryInterface
+	attemptRepo     repositories.RegulatorNotificationAttemptRepositoryInterface
+	passwordService services.PasswordServiceInterface
+	accountService  services.AccountServiceInterface
+	logger          *slog.Logger
+}
+
+// NewSeeder wires a seeder to db using the same repositories and services as the API
+func NewSeeder(db *gorm.DB, logger *slog.Logger) *Seeder {
+	userRepo := repositories.NewUserRepository(db)
+	accountRepo := repositories.NewAccountRepository(db)
+	auditLogRepo := repositories.NewAuditLogRepository(db)
+	auditService := services.NewAuditService(auditLogRepo)
+
+	return &Seeder{
+		userRepo:        userRepo,
+		accountRepo:     accountRepo,
+		extAccountRepo:  repositories.NewNorthwindExternalAccountRepository(db),
+		transferRepo:    repositories.NewNorthwindTransferRepository(db),
+		notifRepo:       repositories.NewRegulatorNotificationRepository(db),
+		attemptRepo:     repositories.NewRegulatorNotificationAttemptRepository(db),
+		passwordService: services.NewPasswordService(userRepo, auditService),
+		accountService: services.NewAccountService(
+			accountRepo,
+			repositories.NewTransactionRepository(db),
+			repositories.NewTransferRepository(db),
+			userRepo,
+			auditLogRepo,
+			logger,
+		),
+		logger: logger,
+	}
+}
+
+// Run seeds the fixture set. environment is the APP_ENV value; anything other than
+// development or testing is refused.
+func (s *Seeder) Run(ctx context.Context, environment string) (*Summary, error) {
+	if environment != "development" && environment != "testing" {
+		return nil, ErrEnvironmentNotAllowed
+	}
+
+	summary := &Summary{}
+	userIDs := make(map[string]uuid.UUID, len(users))
+	for _, fixture := range users {
+		user, err := s.seedUser(ctx, fixture, &summary.Users)
+		if err != nil {
+			return summary, fmt.Errorf("seed user %s: %w", fixture.email, err)
+		}
+		userIDs[fixture.email] = user.ID
+		for accountType, deposit := range fixture.accounts {
+			if err := s.seedAccount(ctx, user.ID, accountType, deposit, &summary.Accounts); err != nil {
+				return summary, fmt.Errorf("seed %s account for %s: %w", accountType, fixture.email, err)
+			}
+		}
+	}
+
+	extByOwner := make(map[string]externalAccountFixture, len(externalAccounts))
+	for _, fixture := range externalAccounts {
+		if err := s.seedExternalAccount(ctx, userIDs[fixture.owner], fixture, &summary.ExternalAccounts); err != nil {
+			return summary, fmt.Errorf("seed external account for %s: %w", fixture.owner, err)
+		}
+		extByOwner[fixture.owner] = fixture
+	}
+
+	for _, fixture := range transfers {
+		userID := userIDs[fixture.owner]
+		internal, err := s.primaryAccountNumber(userID)
+		if err != nil {
+			return summary, fmt.Errorf("seed transfer %s: %w", fixture.reference, err)
+		}
+		transfer, err := s.seedTransfer(ctx, userID, internal, extByOwner[fixture.owner], fixture, &summary.Transfers)
+		if err != nil {
+			return summary, fmt.Errorf("seed transfer %s: %w", fixture.reference, err)
+		}
+		if fixture.notification != notificationNone {
+			if err := s.seedNotification(ctx, transfer, fixture.notification, &summary.Notifications); err != nil {
+				return summary, fmt.Errorf("seed notification for %s: %w", fixture.reference, err)
+			}
+		}
+	}
+
+	s.logger.Info("Development fixtures seeded",
+		"users_created", summary.Users.Created,
+		"accounts_created", summary.Accounts.Created,
+		"external_accounts_created", summary.ExternalAccounts.Created,
+		"transfers_created", summary.Transfers.Created,
+		"notifications_created", summary.Notifications.Created,
+	)
+	return summary, nil
+}
+
+func (s *Seeder) seedUser(ctx context.Context, fixture userFixture, counts *Counts) (*models.User, error) {
+	existing, err := s.userRepo.GetByEmail(ctx, fixture.email)
+	if err == nil {
+		counts.Skipped++
+		return existing, nil
+	}
+	if !errors.Is(err, repositories.ErrUserNotFound) {
+		return nil, err
+	}
+
+	hash, err := s.passwordService.HashPassword(Password)
+	if err != nil {
+		return nil, err
+	}
+	user := &models.User{
+		Email:        fixture.email,
+		PasswordHash: hash,
+		FirstName:    fixture.firstName,
+		LastName:     fixture.lastName,
+		Role:         fixture.role,
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	counts.Created++
+	return user, nil
+}
+
+func (s *Seeder) seedAccount(ctx context.Context, userID uuid.UUID, accountType string, deposit int64, counts *Counts) error {
+	_, err := s.accountService.CreateAccount(ctx, userID, accountType, decimal.NewFromInt(deposit))
+	switch {
+	case errors.Is(err, services.ErrAccountAlreadyExists):
+		counts.Skipped++
+		return nil
+	case err != nil:
+		return err
+	}
+	counts.Created++
+	return nil
+}
+
+// primaryAccountNumber returns the user's checking account number, used as the internal side
+// of seeded transfers
+func (s *Seeder) primaryAccountNumber(userID uuid.UUID) (string, error) {
+	accounts, err := s.accountRepo.GetByUserIDAndType(userID, models.AccountTypeChecking)
+	if err != nil {
+		return "", err
+	}
+	if len(accounts) == 0 {
+		return "", fmt.Errorf("user %s has no checking account", userID)
+	}
+	return accounts[0].AccountNumber, nil
+}
+
+func (s *Seeder) seedExternalAccount(ctx context.Context, userID uuid.UUID, fixture externalAccountFixture, counts *Counts) error {
+	_, err := s.extAccountRepo.FindByAccountAndRouting(ctx, userID, fixture.accountNumber, fixture.routingNumber)
+	if err == nil {
+		counts.Skipped++
+		return nil
+	}
+	if !errors.Is(err, repositories.ErrNorthwindExternalAccountNotFound) {
+		return err
+	}
+
+	now := time.Now().UTC()
+	institution := fixture.institution
+	if err := s.extAccountRepo.Create(ctx, &models.NorthwindExternalAccount{
+		UserID:            &userID,
+		AccountHolderName: fixture.holderName,
+		AccountNumber:     fixture.accountNumber,
+		RoutingNumber:     fixture.routingNumber,
+		InstitutionName:   &institution,
+		Validated:         true,
+		ValidationTime:    &now,
+	}); err != nil {
+		return err
+	}
+	counts.Created++
+	return nil
+}
+
+func (s *Seeder) seedTransfer(ctx context.Context, userID uuid.UUID, internalAccount string, external externalAccountFixture, fixture transferFixture, counts *Counts) (*models.NorthwindTransfer, error) {
+	nwID := uuid.NewSHA1(nwTransferNamespace, []byte(fixture.reference))
+
+	exists, err := s.transferRepo.ReferenceExists(ctx, userID, fixture.reference)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		counts.Skipped++
+		return s.transferRepo.GetByNorthwindTransferID(ctx, nwID)
+	}
+
+	now := time.Now().UTC()
+	description := "Seeded " + fixture.status + " transfer"
+	transfer := &models.NorthwindTransfer{
+		UserID:                   &userID,
+		NorthwindTransferID:      nwID,
+		Direction:                fixture.direction,
+		TransferType:             "ACH",
+		Amount:                   decimal.NewFromInt(fixture.amount),
+		Currency:                 "USD",
+		Description:              &description,
+		ReferenceNumber:          fixture.reference,
+		SourceAccountNumber:      internalAccount,
+		DestinationAccountNumber: external.accountNumber,
+		DestinationRoutingNumber: &external.routingNumber,
+		Status:                   fixture.status,
+		InitiatedDate:            &now,
+	}
+	if fixture.direction == models.NWTransferDirectionInbound {
+		transfer.SourceAccountNumber = external.accountNumber
+		transfer.SourceRoutingNumber = &external.routingNumber
+		transfer.DestinationAccountNumber = internalAccount
+		transfer.DestinationRoutingNumber = nil
+		consentIP, consentMethod := "127.0.0.1", "seed"
+		transfer.ConsentTimestamp = &now
+		transfer.ConsentIPAddress = &consentIP
+		transfer.ConsentMethod = &consentMethod
+	}
+	switch fixture.status {
+	case models.NWTransferStatusProcessing:
+		transfer.ProcessingDate = &now
+	case models.NWTransferStatusCompleted, models.NWTransferStatusReversed:
+		transfer.ProcessingDate = &now
+		transfer.CompletedDate = &now
+	case models.NWTransferStatusFailed:
+		code, message := "INSUFFICIENT_FUNDS", "Seeded failure: insufficient funds"
+		transfer.ErrorCode = &code
+		transfer.ErrorMessage = &message
+	}
+
+	if err := s.transferRepo.Create(ctx, transfer); err != nil {
+		return nil, err
+	}
+	counts.Created++
+	return transfer, nil
+}
+
+func (s *Seeder) seedNotification(ctx context.Context, transfer *models.NorthwindTransfer, state notificationState, counts *Counts) error {
+	exists, err := s.notifRepo.ExistsForTransferAndStatus(ctx, transfer.ID, transfer.Status)
+	if err != nil {
+		return err
+	}
+	if exists {
+		counts.Skipped++
+		return nil
+	}
+
+	amount, _ := transfer.Amount.Float64()
+	payload := models.RegulatorWebhookPayload{
+		EventID:             uuid.New().String(),
+		TransferID:          transfer.ID.String(),
+		NorthwindTransferID: transfer.NorthwindTransferID.String(),
+		Status:              transfer.Status,
+		Amount:              amount,
+		Currency:            transfer.Currency,
+		Direction:           transfer.Direction,
+		TransferType:        transfer.TransferType,
+		Timestamp:           time.Now().UTC().Format(time.RFC3339),
+	}
+	if transfer.IsInbound() {
+		payload.AuthorizationConsent = transfer.AuthorizationConsent()
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	notification := &models.RegulatorNotification{
+		TransferID:     transfer.ID,
+		TerminalStatus: transfer.Status,
+		NextAttemptAt:  &now,
+		Payload:        payloadBytes,
+	}
+
+	var attempts []models.RegulatorNotificationAttempt
+	switch state {
+	case notificationDelivered:
+		ok := 200
+		notification.Delivered = true
+		notification.NextAttemptAt = nil
+		notification.LastHTTPStatus = &ok
+		attempts = append(attempts, models.RegulatorNotificationAttempt{AttemptedAt: now, HTTPStatus: &ok, DurationMs: 35})
+	case notificationRetrying:
+		unavailable := 503
+		lastErr := "regulator returned 503"
+		next := now.Add(time.Minute)
+		notification.NextAttemptAt = &next
+		notification.LastHTTPStatus = &unavailable
+		notification.LastError = &lastErr
+		for i := 2; i > 0; i-- {
+			attempts = append(attempts, models.RegulatorNotificationAttempt{
+				AttemptedAt: now.Add(-time.Duration(i) * time.Minute),
+				HTTPStatus:  &unavailable,
+				Error:       &lastErr,
+				DurationMs:  120,
+			})
+		}
+	}
+	if len(attempts) > 0 {
+		first, last := attempts[0].AttemptedAt, attempts[len(attempts)-1].AttemptedAt
+		notification.AttemptCount = len(attempts)
+		notification.FirstAttemptAt = &first
+		notification.LastAttemptAt = &last
+	}
+
+	if err := s.notifRepo.Create(ctx, notification); err != nil {
+		return err
+	}
+	for i := range attempts {
+		attempts[i].NotificationID = notification.ID
+		attempts[i].TargetURL = "http://regulator:9000/webhook"
+		if err := s.attemptRepo.Create(ctx, &attempts[i]); err != nil {
+			return err
+		}
+	}
+	counts.Created++
+	return nil
+}
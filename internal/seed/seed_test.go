@@ -0,0 +1,105 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/testfactory"
+	"gorm.io/gorm"
+)
+
+func countRows(t *testing.T, db *gorm.DB) map[string]int64 {
+	t.Helper()
+	counts := make(map[string]int64)
+	for name, model := range map[string]interface{}{
+		"users":             &models.User{},
+		"accounts":          &models.Account{},
+		"external_accounts": &models.NorthwindExternalAccount{},
+		"transfers":         &models.NorthwindTransfer{},
+		"notifications":     &models.RegulatorNotification{},
+		"attempts":          &models.RegulatorNotificationAttempt{},
+	} {
+		var n int64
+		if err := db.Model(model).Count(&n).Error; err != nil {
+			t.Fatalf("count %s: %v", name, err)
+		}
+		counts[name] = n
+	}
+	return counts
+}
+
+func TestSeeder_RunIsIdempotent(t *testing.T) {
+	db := testfactory.NewDB(t)
+	seeder := NewSeeder(db, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	first, err := seeder.Run(context.Background(), "development")
+	if err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	if first.Users.Created != len(users) || first.Transfers.Created != len(transfers) {
+		t.Errorf("unexpected first run summary: %+v", first)
+	}
+	afterFirst := countRows(t, db)
+
+	second, err := seeder.Run(context.Background(), "development")
+	if err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	afterSecond := countRows(t, db)
+
+	for name, n := range afterFirst {
+		if afterSecond[name] != n {
+			t.Errorf("%s: count changed from %d to %d on re-run", name, n, afterSecond[name])
+		}
+	}
+	for name, c := range map[string]Counts{
+		"users":             second.Users,
+		"accounts":          second.Accounts,
+		"external_accounts": second.ExternalAccounts,
+		"transfers":         second.Transfers,
+		"notifications":     second.Notifications,
+	} {
+		if c.Created != 0 {
+			t.Errorf("%s: expected nothing created on re-run, got %d", name, c.Created)
+		}
+	}
+
+	statuses := make(map[string]bool)
+	var seeded []models.NorthwindTransfer
+	if err := db.Find(&seeded).Error; err != nil {
+		t.Fatalf("load transfers: %v", err)
+	}
+	for _, tr := range seeded {
+		statuses[tr.Status] = true
+	}
+	for _, status := range []string{
+		models.NWTransferStatusPending, models.NWTransferStatusProcessing, models.NWTransferStatusCompleted,
+		models.NWTransferStatusFailed, models.NWTransferStatusCancelled, models.NWTransferStatusReversed,
+	} {
+		if !statuses[status] {
+			t.Errorf("no seeded transfer in status %s", status)
+		}
+	}
+
+	var delivered int64
+	db.Model(&models.RegulatorNotification{}).Where("delivered = ?", true).Count(&delivered)
+	if delivered == 0 || delivered == afterSecond["notifications"] {
+		t.Errorf("expected a mix of delivered and undelivered notifications, got %d of %d delivered", delivered, afterSecond["notifications"])
+	}
+}
+
+func TestSeeder_RefusesProduction(t *testing.T) {
+	db := testfactory.NewDB(t)
+	seeder := NewSeeder(db, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	if _, err := seeder.Run(context.Background(), "production"); !errors.Is(err, ErrEnvironmentNotAllowed) {
+		t.Fatalf("expected ErrEnvironmentNotAllowed, got %v", err)
+	}
+	if counts := countRows(t, db); counts["users"] != 0 {
+		t.Errorf("expected no users created, got %d", counts["users"])
+	}
+}
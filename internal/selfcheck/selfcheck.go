@@ -0,0 +1,149 @@
+// Package selfcheck validates configuration and probes external dependencies so deploy
+// pipelines can verify an environment without starting the server.
+package selfcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/array/banking-api/internal/config"
+	"github.com/array/banking-api/internal/fieldcrypt"
+	"github.com/array/banking-api/internal/integrations/northwind"
+)
+
+// Dependency names used in the report
+const (
+	DependencyConfig    = "config"
+	DependencyDatabase  = "database"
+	DependencyNorthwind = "northwind"
+	DependencyRegulator = "regulator"
+)
+
+// Check statuses
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// DefaultTimeout bounds each individual dependency probe
+const DefaultTimeout = 5 * time.Second
+
+// Result is the outcome of probing one dependency
+type Result struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Report is the full self-check outcome; Status is ok only if every check passed
+type Report struct {
+	Status string   `json:"status"`
+	Checks []Result `json:"checks"`
+}
+
+// OK reports whether every dependency check passed
+func (r Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+// DBPinger connects to the database described by cfg and returns once it answers a ping
+type DBPinger func(ctx context.Context, cfg *config.DatabaseConfig) error
+
+// Checker runs the startup self-check against a loaded configuration
+type Checker struct {
+	cfg        *config.Config
+	pingDB     DBPinger
+	httpClient *http.Client
+	timeout    time.Duration
+}
+
+// NewChecker creates a self-check for cfg. pingDB is normally database.Ping; timeout bounds each probe.
+func NewChecker(cfg *config.Config, pingDB DBPinger, timeout time.Duration) *Checker {
+	return &Checker{
+		cfg:        cfg,
+		pingDB:     pingDB,
+		httpClient: &http.Client{Timeout: timeout},
+		timeout:    timeout,
+	}
+}
+
+// Run validates the configuration and probes every dependency. All checks run even when an
+// earlier one fails so the report shows the complete picture.
+func (c *Checker) Run(ctx context.Context) Report {
+	checks := []struct {
+		name string
+		fn   func(context.Context) error
+	}{
+		{DependencyConfig, c.checkConfig},
+		{DependencyDatabase, func(ctx context.Context) error { return c.pingDB(ctx, &c.cfg.Database) }},
+		{DependencyNorthwind, c.checkNorthwind},
+		{DependencyRegulator, c.checkRegulator},
+	}
+
+	report := Report{Status: StatusOK}
+	for _, check := range checks {
+		result := c.run(ctx, check.name, check.fn)
+		if result.Status != StatusOK {
+			report.Status = StatusFail
+		}
+		report.Checks = append(report.Checks, result)
+	}
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, name string, fn func(context.Context) error) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := fn(ctx)
+	result := Result{
+		Name:      name,
+		Status:    StatusOK,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// checkConfig validates settings and that the field encryption keys actually form a keyring
+func (c *Checker) checkConfig(context.Context) error {
+	if err := c.cfg.Validate(); err != nil {
+		return err
+	}
+	if _, err := fieldcrypt.NewKeyringFromConfig(&c.cfg.Encryption); err != nil {
+		return fmt.Errorf("field encryption: %w", err)
+	}
+	return nil
+}
+
+// checkNorthwind calls NorthWind /health with the configured API key, without retries
+func (c *Checker) checkNorthwind(ctx context.Context) error {
+	client := northwind.NewClient(c.cfg.NorthWind.BaseURL, c.cfg.NorthWind.APIKey, northwind.WithTimeout(c.cfg.NorthWind.Timeout))
+	_, err := client.Health(ctx)
+	return err
+}
+
+// checkRegulator HEADs the webhook URL. Any HTTP response proves the endpoint is reachable;
+// only 5xx is treated as a failure since many webhook receivers reject HEAD with 4xx.
+func (c *Checker) checkRegulator(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, c.cfg.Regulator.WebhookURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("regulator webhook returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package selfcheck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/config"
+	"github.com/array/banking-api/internal/integrations/northwind"
+)
+
+// fakeDependencies serves NorthWind /health and the regulator webhook; each can be made to fail
+type fakeDependencies struct {
+	northwindStatus int
+	regulatorStatus int
+}
+
+func (f *fakeDependencies) northwind(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			t.Errorf("unexpected NorthWind path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(f.northwindStatus)
+		_ = json.NewEncoder(w).Encode(northwind.HealthResponse{Status: "healthy"})
+	}))
+}
+
+func (f *fakeDependencies) regulator(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("expected HEAD to regulator, got %s", r.Method)
+		}
+		w.WriteHeader(f.regulatorStatus)
+	}))
+}
+
+func newTestConfig(northwindURL, regulatorURL string) *config.Config {
+	cfg := &config.Config{}
+	cfg.Server.Port = "8080"
+	cfg.Database.Host = "localhost"
+	cfg.Database.Name = "banking_db"
+	cfg.NorthWind.BaseURL = northwindURL
+	cfg.NorthWind.APIKey = "test-key"
+	cfg.Regulator.WebhookURL = regulatorURL + "/webhook"
+	cfg.Regulator.RetryInitialSeconds = 2
+	cfg.Regulator.RetryMaxSeconds = 60
+	cfg.Retention.TransferYears = 7
+	cfg.Retention.TransactionYears = 7
+	cfg.Retention.AuditLogYears = 10
+	cfg.Encryption.Keys = "k1:AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE="
+	cfg.Encryption.ActiveKeyID = "k1"
+	cfg.Encryption.BlindIndexKey = "AgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgI="
+	return cfg
+}
+
+func healthyDB(context.Context, *config.DatabaseConfig) error { return nil }
+
+func TestChecker_Run(t *testing.T) {
+	tests := []struct {
+		name       string
+		northwind  int
+		regulator  int
+		pingDB     DBPinger
+		mutate     func(*config.Config)
+		failing    string
+		errMessage string
+	}{
+		{name: "all healthy", northwind: http.StatusOK, regulator: http.StatusOK, pingDB: healthyDB},
+		{
+			name: "invalid config", northwind: http.StatusOK, regulator: http.StatusOK, pingDB: healthyDB,
+			mutate:  func(c *config.Config) { c.Regulator.RetryMaxSeconds = 0 },
+			failing: DependencyConfig,
+		},
+		{
+			name: "unknown active encryption key", northwind: http.StatusOK, regulator: http.StatusOK, pingDB: healthyDB,
+			mutate:  func(c *config.Config) { c.Encryption.ActiveKeyID = "k2" },
+			failing: DependencyConfig,
+		},
+		{
+			name: "database down", northwind: http.StatusOK, regulator: http.StatusOK,
+			pingDB: func(context.Context, *config.DatabaseConfig) error {
+				return errors.New("connection refused")
+			},
+			failing:    DependencyDatabase,
+			errMessage: "connection refused",
+		},
+		{name: "northwind unhealthy", northwind: http.StatusServiceUnavailable, regulator: http.StatusOK, pingDB: healthyDB, failing: DependencyNorthwind},
+		{
+			name: "northwind rejects key", northwind: http.StatusOK, regulator: http.StatusOK, pingDB: healthyDB,
+			mutate:  func(c *config.Config) { c.NorthWind.APIKey = "wrong-key" },
+			failing: DependencyNorthwind,
+		},
+		{name: "regulator erroring", northwind: http.StatusOK, regulator: http.StatusBadGateway, pingDB: healthyDB, failing: DependencyRegulator},
+		{name: "regulator rejects HEAD", northwind: http.StatusOK, regulator: http.StatusMethodNotAllowed, pingDB: healthyDB},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := &fakeDependencies{northwindStatus: tt.northwind, regulatorStatus: tt.regulator}
+			nwServer := deps.northwind(t)
+			defer nwServer.Close()
+			regServer := deps.regulator(t)
+			defer regServer.Close()
+
+			cfg := newTestConfig(nwServer.URL, regServer.URL)
+			if tt.mutate != nil {
+				tt.mutate(cfg)
+			}
+
+			report := NewChecker(cfg, tt.pingDB, time.Second).Run(context.Background())
+
+			if len(report.Checks) != 4 {
+				t.Fatalf("expected 4 checks, got %d", len(report.Checks))
+			}
+			if report.OK() != (tt.failing == "") {
+				t.Errorf("expected OK=%v, got report %+v", tt.failing == "", report)
+			}
+			for _, check := range report.Checks {
+				shouldFail := check.Name == tt.failing
+				if (check.Status == StatusFail) != shouldFail {
+					t.Errorf("%s: expected failing=%v, got status %s (%s)", check.Name, shouldFail, check.Status, check.Error)
+				}
+				if shouldFail && check.Error == "" {
+					t.Errorf("%s: expected an error message", check.Name)
+				}
+			}
+			if tt.errMessage != "" {
+				for _, check := range report.Checks {
+					if check.Name == tt.failing && check.Error != tt.errMessage {
+						t.Errorf("expected error %q, got %q", tt.errMessage, check.Error)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestChecker_Run_ProbeTimeout(t *testing.T) {
+	regServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+	}))
+	defer regServer.Close()
+	nwServer := (&fakeDependencies{northwindStatus: http.StatusOK}).northwind(t)
+	defer nwServer.Close()
+
+	start := time.Now()
+	report := NewChecker(newTestConfig(nwServer.URL, regServer.URL), healthyDB, 100*time.Millisecond).Run(context.Background())
+	if elapsed := time.Since(start); elapsed > 400*time.Millisecond {
+		t.Errorf("expected probes bounded by the timeout, took %v", elapsed)
+	}
+	if report.OK() {
+		t.Error("expected slow regulator to fail the check")
+	}
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+)
+
+// AccountActivityPage is one page of an account's activity feed. Next is the position to resume
+// from, nil on the last page.
+type AccountActivityPage struct {
+	Items []models.AccountActivityItem
+	Next  *models.AccountActivityKeyset
+}
+
+// AccountActivityService builds an account's activity feed from its internal transactions and
+// the NorthWind transfers sent from it
+type AccountActivityService struct {
+	transactionRepo repositories.TransactionRepositoryInterface
+	transferRepo    repositories.NorthwindTransferRepositoryInterface
+}
+
+// NewAccountActivityService creates an account activity service
+func NewAccountActivityService(
+	transactionRepo repositories.TransactionRepositoryInterface,
+	transferRepo repositories.NorthwindTransferRepositoryInterface,
+) *AccountActivityService {
+	return &AccountActivityService{transactionRepo: transactionRepo, transferRepo: transferRepo}
+}
+
+// GetActivity returns up to limit items of the account's activity, newest first, after the given
+// position. Each source is read by keyset from the same position, one row past the limit, and the
+// two are merged by created_at, so a page costs two bounded queries however deep it is. Transfers
+// are matched to the account by source account number and to the account's owner.
+func (s *AccountActivityService) GetActivity(ctx context.Context, account *models.Account, after *models.AccountActivityKeyset, limit int) (*AccountActivityPage, error) {
+	transactions, err := s.transactionRepo.GetByAccountIDKeyset(ctx, account.ID, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	transfers, err := s.transferRepo.GetBySourceAccountKeyset(ctx, account.UserID, account.AccountNumber, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]models.AccountActivityItem, 0, limit+1)
+	i, j := 0, 0
+	for len(items) <= limit && (i < len(transactions) || j < len(transfers)) {
+		var transaction, transfer *models.AccountActivityItem
+		if i < len(transactions) {
+			transaction = transactionActivity(&transactions[i])
+		}
+		if j < len(transfers) {
+			transfer = transferActivity(&transfers[j])
+		}
+		if transfer == nil || (transaction != nil && transaction.Before(*transfer)) {
+			items = append(items, *transaction)
+			i++
+		} else {
+			items = append(items, *transfer)
+			j++
+		}
+	}
+
+	page := &AccountActivityPage{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		next := page.Items[limit-1].Keyset()
+		page.Next = &next
+	}
+	return page, nil
+}
+
+func transactionActivity(transaction *models.Transaction) *models.AccountActivityItem {
+	return &models.AccountActivityItem{
+		Type:        models.AccountActivityTypeTransaction,
+		ID:          transaction.ID,
+		CreatedAt:   transaction.CreatedAt,
+		Transaction: transaction,
+	}
+}
+
+func transferActivity(transfer *models.NorthwindTransfer) *models.AccountActivityItem {
+	return &models.AccountActivityItem{
+		Type:              models.AccountActivityTypeNorthwindTransfer,
+		ID:                transfer.ID,
+		CreatedAt:         transfer.CreatedAt,
+		NorthwindTransfer: transfer,
+	}
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestAccountActivityService_MergesAndPages(t *testing.T) {
+	db := testfactory.NewDB(t)
+	ctx := context.Background()
+	transactionRepo := repositories.NewTransactionRepository(db)
+	service := NewAccountActivityService(transactionRepo, repositories.NewNorthwindTransferRepository(db))
+
+	account := &models.Account{ID: uuid.New(), UserID: uuid.New(), AccountNumber: "1234567890"}
+	base := time.Now().UTC().Truncate(time.Microsecond)
+	transaction := func(ago time.Duration) uuid.UUID {
+		tx := &models.Transaction{
+			AccountID:       account.ID,
+			TransactionType: models.TransactionTypeDebit,
+			Amount:          decimal.NewFromInt(10),
+			BalanceBefore:   decimal.NewFromInt(100),
+			BalanceAfter:    decimal.NewFromInt(90),
+			Description:     "Card payment",
+			CreatedAt:       base.Add(-ago),
+		}
+		if err := transactionRepo.Create(ctx, tx); err != nil {
+			t.Fatalf("failed to create transaction: %v", err)
+		}
+		return tx.ID
+	}
+	transfer := func(ago time.Duration) uuid.UUID {
+		return testfactory.NWTransfer(t, db, testfactory.WithUser(account.UserID), testfactory.WithSourceAccount(account.AccountNumber),
+			testfactory.WithCreatedAt(base.Add(-ago))).ID
+	}
+
+	// Neither the owner's transfers from another account nor another user's from the same number belong in the feed
+	testfactory.NWTransfer(t, db, testfactory.WithUser(account.UserID), testfactory.WithSourceAccount("9999999999"), testfactory.WithCreatedAt(base))
+	testfactory.NWTransfer(t, db, testfactory.WithSourceAccount(account.AccountNumber), testfactory.WithCreatedAt(base))
+
+	want := []uuid.UUID{
+		transaction(1 * time.Minute),
+		transfer(2 * time.Minute),
+		transfer(3 * time.Minute),
+		transaction(4 * time.Minute),
+		transfer(5 * time.Minute),
+	}
+	// A transaction and a transfer created at the same instant are ordered by id
+	tiedTransaction, tiedTransfer := transaction(6*time.Minute), transfer(6*time.Minute)
+	if tiedTransaction.String() > tiedTransfer.String() {
+		want = append(want, tiedTransaction, tiedTransfer)
+	} else {
+		want = append(want, tiedTransfer, tiedTransaction)
+	}
+	want = append(want, transaction(7*time.Minute))
+
+	var got []uuid.UUID
+	var after *models.AccountActivityKeyset
+	for pages := 0; ; pages++ {
+		if pages > len(want) {
+			t.Fatalf("paging did not terminate, got %v", got)
+		}
+		page, err := service.GetActivity(ctx, account, after, 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, item := range page.Items {
+			got = append(got, item.ID)
+			if (item.Type == models.AccountActivityTypeTransaction) != (item.Transaction != nil) ||
+				(item.Type == models.AccountActivityTypeNorthwindTransfer) != (item.NorthwindTransfer != nil) {
+				t.Errorf("item %s of type %s carries the wrong payload", item.ID, item.Type)
+			}
+		}
+		if page.Next == nil {
+			break
+		}
+		after = page.Next
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("expected %d items, got %d: %v", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("item %d: expected %s, got %s", i, want[i], got[i])
+		}
+	}
+}
+
+func TestAccountActivityService_LastPageHasNoNext(t *testing.T) {
+	db := testfactory.NewDB(t)
+	service := NewAccountActivityService(repositories.NewTransactionRepository(db), repositories.NewNorthwindTransferRepository(db))
+	account := &models.Account{ID: uuid.New(), UserID: uuid.New(), AccountNumber: "1234567890"}
+	testfactory.NWTransfer(t, db, testfactory.WithUser(account.UserID), testfactory.WithSourceAccount(account.AccountNumber))
+	testfactory.NWTransfer(t, db, testfactory.WithUser(account.UserID), testfactory.WithSourceAccount(account.AccountNumber))
+
+	page, err := service.GetActivity(context.Background(), account, nil, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Items) != 2 || page.Next != nil {
+		t.Errorf("expected both transfers and no next page, got %d items and next %v", len(page.Items), page.Next)
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -37,12 +38,12 @@ var (
 )
 
 // GetCustomerAccounts retrieves all accounts for a customer (excludes closed and deleted accounts)
-func (s *AccountAssociationService) GetCustomerAccounts(customerID uuid.UUID) ([]*models.Account, error) {
+func (s *AccountAssociationService) GetCustomerAccounts(ctx context.Context, customerID uuid.UUID) ([]*models.Account, error) {
 	if customerID == uuid.Nil {
 		return nil, ErrInvalidCustomerID
 	}
 
-	_, err := s.userRepo.GetByIDActive(customerID)
+	_, err := s.userRepo.GetByIDActive(ctx, customerID)
 	if err != nil {
 		if errors.Is(err, repositories.ErrUserNotFound) {
 			return nil, ErrCustomerNotFound
@@ -59,7 +60,7 @@ func (s *AccountAssociationService) GetCustomerAccounts(customerID uuid.UUID) ([
 }
 
 // CreateAccountForCustomer creates a new account for a customer (admin operation)
-func (s *AccountAssociationService) CreateAccountForCustomer(customerID, performedBy uuid.UUID, accountType, ipAddress, userAgent string) (*models.Account, error) {
+func (s *AccountAssociationService) CreateAccountForCustomer(ctx context.Context, customerID, performedBy uuid.UUID, accountType, ipAddress, userAgent string) (*models.Account, error) {
 	if customerID == uuid.Nil {
 		return nil, ErrInvalidCustomerID
 	}
@@ -72,7 +73,7 @@ func (s *AccountAssociationService) CreateAccountForCustomer(customerID, perform
 		return nil, models.ErrInvalidAccountType
 	}
 
-	_, err := s.userRepo.GetByIDActive(customerID)
+	_, err := s.userRepo.GetByIDActive(ctx, customerID)
 	if err != nil {
 		if errors.Is(err, repositories.ErrUserNotFound) {
 			return nil, ErrCustomerNotFound
@@ -113,7 +114,7 @@ func (s *AccountAssociationService) CreateAccountForCustomer(customerID, perform
 }
 
 // TransferAccountOwnership transfers an account from one customer to another (atomic transaction)
-func (s *AccountAssociationService) TransferAccountOwnership(accountID, fromCustomerID, toCustomerID, performedBy uuid.UUID, ipAddress, userAgent string) error {
+func (s *AccountAssociationService) TransferAccountOwnership(ctx context.Context, accountID, fromCustomerID, toCustomerID, performedBy uuid.UUID, ipAddress, userAgent string) error {
 	if accountID == uuid.Nil {
 		return ErrInvalidAccountID
 	}
@@ -142,7 +143,7 @@ func (s *AccountAssociationService) TransferAccountOwnership(accountID, fromCust
 		return ErrFromCustomerMismatch
 	}
 
-	_, err = s.userRepo.GetByIDActive(fromCustomerID)
+	_, err = s.userRepo.GetByIDActive(ctx, fromCustomerID)
 	if err != nil {
 		if errors.Is(err, repositories.ErrUserNotFound) {
 			return ErrCustomerNotFound
@@ -150,7 +151,7 @@ func (s *AccountAssociationService) TransferAccountOwnership(accountID, fromCust
 		return fmt.Errorf("failed to verify from customer: %w", err)
 	}
 
-	_, err = s.userRepo.GetByIDActive(toCustomerID)
+	_, err = s.userRepo.GetByIDActive(ctx, toCustomerID)
 	if err != nil {
 		if errors.Is(err, repositories.ErrUserNotFound) {
 			return ErrCustomerNotFound
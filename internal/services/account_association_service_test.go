@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"log/slog"
 	"testing"
@@ -58,10 +59,10 @@ func (s *AccountAssociationServiceTestSuite) TestGetCustomerAccounts_Success() {
 		{ID: uuid.New(), UserID: customerID, AccountNumber: "SAV0987654321", Status: models.AccountStatusActive},
 	}
 
-	s.mockUserRepo.EXPECT().GetByIDActive(customerID).Return(user, nil)
+	s.mockUserRepo.EXPECT().GetByIDActive(gomock.Any(), customerID).Return(user, nil)
 	s.mockAccountRepo.EXPECT().GetByUserIDExcludingStatus(customerID, models.AccountStatusClosed).Return(accounts, nil)
 
-	result, err := s.service.GetCustomerAccounts(customerID)
+	result, err := s.service.GetCustomerAccounts(context.Background(), customerID)
 
 	s.NoError(err)
 	s.Len(result, 2)
@@ -69,7 +70,7 @@ func (s *AccountAssociationServiceTestSuite) TestGetCustomerAccounts_Success() {
 
 // TestGetCustomerAccounts_NilCustomerID tests with nil customer ID
 func (s *AccountAssociationServiceTestSuite) TestGetCustomerAccounts_NilCustomerID() {
-	_, err := s.service.GetCustomerAccounts(uuid.Nil)
+	_, err := s.service.GetCustomerAccounts(context.Background(), uuid.Nil)
 
 	s.Error(err)
 	s.ErrorIs(err, ErrInvalidCustomerID)
@@ -79,9 +80,9 @@ func (s *AccountAssociationServiceTestSuite) TestGetCustomerAccounts_NilCustomer
 func (s *AccountAssociationServiceTestSuite) TestGetCustomerAccounts_CustomerNotFound() {
 	customerID := uuid.New()
 
-	s.mockUserRepo.EXPECT().GetByIDActive(customerID).Return(nil, repositories.ErrUserNotFound)
+	s.mockUserRepo.EXPECT().GetByIDActive(gomock.Any(), customerID).Return(nil, repositories.ErrUserNotFound)
 
-	_, err := s.service.GetCustomerAccounts(customerID)
+	_, err := s.service.GetCustomerAccounts(context.Background(), customerID)
 
 	s.Error(err)
 	s.ErrorIs(err, ErrCustomerNotFound)
@@ -92,10 +93,10 @@ func (s *AccountAssociationServiceTestSuite) TestGetCustomerAccounts_RepositoryE
 	customerID := uuid.New()
 	user := &models.User{ID: customerID}
 
-	s.mockUserRepo.EXPECT().GetByIDActive(customerID).Return(user, nil)
+	s.mockUserRepo.EXPECT().GetByIDActive(gomock.Any(), customerID).Return(user, nil)
 	s.mockAccountRepo.EXPECT().GetByUserIDExcludingStatus(customerID, models.AccountStatusClosed).Return(nil, errors.New("database error"))
 
-	_, err := s.service.GetCustomerAccounts(customerID)
+	_, err := s.service.GetCustomerAccounts(context.Background(), customerID)
 
 	s.Error(err)
 }
@@ -107,7 +108,7 @@ func (s *AccountAssociationServiceTestSuite) TestCreateAccountForCustomer_Succes
 	accountType := models.AccountTypeChecking
 	user := &models.User{ID: customerID, Email: gofakeit.Email()}
 
-	s.mockUserRepo.EXPECT().GetByIDActive(customerID).Return(user, nil)
+	s.mockUserRepo.EXPECT().GetByIDActive(gomock.Any(), customerID).Return(user, nil)
 	s.mockAccountRepo.EXPECT().GenerateUniqueAccountNumber(accountType).Return("CHK1234567890", nil)
 	s.mockAccountRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(account *models.Account) error {
 		// Simulate setting the ID that would happen in the database
@@ -118,7 +119,7 @@ func (s *AccountAssociationServiceTestSuite) TestCreateAccountForCustomer_Succes
 	})
 	s.auditService.EXPECT().LogAccountCreated(customerID, performedBy, gomock.Any(), accountType, "127.0.0.1", "test-agent").Return(nil)
 
-	account, err := s.service.CreateAccountForCustomer(customerID, performedBy, accountType, "127.0.0.1", "test-agent")
+	account, err := s.service.CreateAccountForCustomer(context.Background(), customerID, performedBy, accountType, "127.0.0.1", "test-agent")
 
 	s.NoError(err)
 	s.NotNil(account)
@@ -133,7 +134,7 @@ func (s *AccountAssociationServiceTestSuite) TestCreateAccountForCustomer_NilCus
 	performedBy := uuid.New()
 	accountType := models.AccountTypeChecking
 
-	account, err := s.service.CreateAccountForCustomer(uuid.Nil, performedBy, accountType, "127.0.0.1", "test-agent")
+	account, err := s.service.CreateAccountForCustomer(context.Background(), uuid.Nil, performedBy, accountType, "127.0.0.1", "test-agent")
 
 	s.Error(err)
 	s.ErrorIs(err, ErrInvalidCustomerID)
@@ -145,7 +146,7 @@ func (s *AccountAssociationServiceTestSuite) TestCreateAccountForCustomer_NilPer
 	customerID := uuid.New()
 	accountType := models.AccountTypeChecking
 
-	account, err := s.service.CreateAccountForCustomer(customerID, uuid.Nil, accountType, "127.0.0.1", "test-agent")
+	account, err := s.service.CreateAccountForCustomer(context.Background(), customerID, uuid.Nil, accountType, "127.0.0.1", "test-agent")
 
 	s.Error(err)
 	s.ErrorIs(err, ErrInvalidPerformedBy)
@@ -157,7 +158,7 @@ func (s *AccountAssociationServiceTestSuite) TestCreateAccountForCustomer_Invali
 	customerID := uuid.New()
 	performedBy := uuid.New()
 
-	account, err := s.service.CreateAccountForCustomer(customerID, performedBy, "INVALID", "127.0.0.1", "test-agent")
+	account, err := s.service.CreateAccountForCustomer(context.Background(), customerID, performedBy, "INVALID", "127.0.0.1", "test-agent")
 
 	s.Error(err)
 	s.ErrorIs(err, models.ErrInvalidAccountType)
@@ -170,9 +171,9 @@ func (s *AccountAssociationServiceTestSuite) TestCreateAccountForCustomer_Custom
 	performedBy := uuid.New()
 	accountType := models.AccountTypeSavings
 
-	s.mockUserRepo.EXPECT().GetByIDActive(customerID).Return(nil, repositories.ErrUserNotFound)
+	s.mockUserRepo.EXPECT().GetByIDActive(gomock.Any(), customerID).Return(nil, repositories.ErrUserNotFound)
 
-	account, err := s.service.CreateAccountForCustomer(customerID, performedBy, accountType, "127.0.0.1", "test-agent")
+	account, err := s.service.CreateAccountForCustomer(context.Background(), customerID, performedBy, accountType, "127.0.0.1", "test-agent")
 
 	s.Error(err)
 	s.ErrorIs(err, ErrCustomerNotFound)
@@ -186,10 +187,10 @@ func (s *AccountAssociationServiceTestSuite) TestCreateAccountForCustomer_Accoun
 	accountType := models.AccountTypeChecking
 	user := &models.User{ID: customerID}
 
-	s.mockUserRepo.EXPECT().GetByIDActive(customerID).Return(user, nil)
+	s.mockUserRepo.EXPECT().GetByIDActive(gomock.Any(), customerID).Return(user, nil)
 	s.mockAccountRepo.EXPECT().GenerateUniqueAccountNumber(accountType).Return("", errors.New("generation failed"))
 
-	account, err := s.service.CreateAccountForCustomer(customerID, performedBy, accountType, "127.0.0.1", "test-agent")
+	account, err := s.service.CreateAccountForCustomer(context.Background(), customerID, performedBy, accountType, "127.0.0.1", "test-agent")
 
 	s.Error(err)
 	s.Nil(account)
@@ -202,11 +203,11 @@ func (s *AccountAssociationServiceTestSuite) TestCreateAccountForCustomer_Accoun
 	accountType := models.AccountTypeChecking
 	user := &models.User{ID: customerID}
 
-	s.mockUserRepo.EXPECT().GetByIDActive(customerID).Return(user, nil)
+	s.mockUserRepo.EXPECT().GetByIDActive(gomock.Any(), customerID).Return(user, nil)
 	s.mockAccountRepo.EXPECT().GenerateUniqueAccountNumber(accountType).Return("CHK1234567890", nil)
 	s.mockAccountRepo.EXPECT().Create(gomock.Any()).Return(errors.New("database error"))
 
-	account, err := s.service.CreateAccountForCustomer(customerID, performedBy, accountType, "127.0.0.1", "test-agent")
+	account, err := s.service.CreateAccountForCustomer(context.Background(), customerID, performedBy, accountType, "127.0.0.1", "test-agent")
 
 	s.Error(err)
 	s.Nil(account)
@@ -227,12 +228,12 @@ func (s *AccountAssociationServiceTestSuite) TestTransferAccountOwnership_Succes
 	toUser := &models.User{ID: toCustomerID}
 
 	s.mockAccountRepo.EXPECT().GetByID(accountID).Return(account, nil)
-	s.mockUserRepo.EXPECT().GetByIDActive(fromCustomerID).Return(fromUser, nil)
-	s.mockUserRepo.EXPECT().GetByIDActive(toCustomerID).Return(toUser, nil)
+	s.mockUserRepo.EXPECT().GetByIDActive(gomock.Any(), fromCustomerID).Return(fromUser, nil)
+	s.mockUserRepo.EXPECT().GetByIDActive(gomock.Any(), toCustomerID).Return(toUser, nil)
 	s.mockAccountRepo.EXPECT().UpdateOwnership(accountID, toCustomerID).Return(nil)
 	s.auditService.EXPECT().LogAccountTransferred(fromCustomerID, toCustomerID, performedBy, accountID, "127.0.0.1", "test-agent").Return(nil)
 
-	err := s.service.TransferAccountOwnership(accountID, fromCustomerID, toCustomerID, performedBy, "127.0.0.1", "test-agent")
+	err := s.service.TransferAccountOwnership(context.Background(), accountID, fromCustomerID, toCustomerID, performedBy, "127.0.0.1", "test-agent")
 
 	s.NoError(err)
 }
@@ -243,7 +244,7 @@ func (s *AccountAssociationServiceTestSuite) TestTransferAccountOwnership_NilAcc
 	toCustomerID := uuid.New()
 	performedBy := uuid.New()
 
-	err := s.service.TransferAccountOwnership(uuid.Nil, fromCustomerID, toCustomerID, performedBy, "127.0.0.1", "test-agent")
+	err := s.service.TransferAccountOwnership(context.Background(), uuid.Nil, fromCustomerID, toCustomerID, performedBy, "127.0.0.1", "test-agent")
 
 	s.Error(err)
 	s.ErrorIs(err, ErrInvalidAccountID)
@@ -255,7 +256,7 @@ func (s *AccountAssociationServiceTestSuite) TestTransferAccountOwnership_NilFro
 	toCustomerID := uuid.New()
 	performedBy := uuid.New()
 
-	err := s.service.TransferAccountOwnership(accountID, uuid.Nil, toCustomerID, performedBy, "127.0.0.1", "test-agent")
+	err := s.service.TransferAccountOwnership(context.Background(), accountID, uuid.Nil, toCustomerID, performedBy, "127.0.0.1", "test-agent")
 
 	s.Error(err)
 	s.ErrorIs(err, ErrInvalidCustomerID)
@@ -267,7 +268,7 @@ func (s *AccountAssociationServiceTestSuite) TestTransferAccountOwnership_NilToC
 	fromCustomerID := uuid.New()
 	performedBy := uuid.New()
 
-	err := s.service.TransferAccountOwnership(accountID, fromCustomerID, uuid.Nil, performedBy, "127.0.0.1", "test-agent")
+	err := s.service.TransferAccountOwnership(context.Background(), accountID, fromCustomerID, uuid.Nil, performedBy, "127.0.0.1", "test-agent")
 
 	s.Error(err)
 	s.ErrorIs(err, ErrInvalidCustomerID)
@@ -279,7 +280,7 @@ func (s *AccountAssociationServiceTestSuite) TestTransferAccountOwnership_NilPer
 	fromCustomerID := uuid.New()
 	toCustomerID := uuid.New()
 
-	err := s.service.TransferAccountOwnership(accountID, fromCustomerID, toCustomerID, uuid.Nil, "127.0.0.1", "test-agent")
+	err := s.service.TransferAccountOwnership(context.Background(), accountID, fromCustomerID, toCustomerID, uuid.Nil, "127.0.0.1", "test-agent")
 
 	s.Error(err)
 	s.ErrorIs(err, ErrInvalidPerformedBy)
@@ -291,7 +292,7 @@ func (s *AccountAssociationServiceTestSuite) TestTransferAccountOwnership_SameCu
 	sameCustomerID := uuid.New()
 	performedBy := uuid.New()
 
-	err := s.service.TransferAccountOwnership(accountID, sameCustomerID, sameCustomerID, performedBy, "127.0.0.1", "test-agent")
+	err := s.service.TransferAccountOwnership(context.Background(), accountID, sameCustomerID, sameCustomerID, performedBy, "127.0.0.1", "test-agent")
 
 	s.Error(err)
 	s.ErrorIs(err, ErrSameCustomer)
@@ -306,7 +307,7 @@ func (s *AccountAssociationServiceTestSuite) TestTransferAccountOwnership_Accoun
 
 	s.mockAccountRepo.EXPECT().GetByID(accountID).Return(nil, repositories.ErrAccountNotFound)
 
-	err := s.service.TransferAccountOwnership(accountID, fromCustomerID, toCustomerID, performedBy, "127.0.0.1", "test-agent")
+	err := s.service.TransferAccountOwnership(context.Background(), accountID, fromCustomerID, toCustomerID, performedBy, "127.0.0.1", "test-agent")
 
 	s.Error(err)
 	s.ErrorIs(err, ErrAccountNotFound)
@@ -327,7 +328,7 @@ func (s *AccountAssociationServiceTestSuite) TestTransferAccountOwnership_Accoun
 
 	s.mockAccountRepo.EXPECT().GetByID(accountID).Return(account, nil)
 
-	err := s.service.TransferAccountOwnership(accountID, fromCustomerID, toCustomerID, performedBy, "127.0.0.1", "test-agent")
+	err := s.service.TransferAccountOwnership(context.Background(), accountID, fromCustomerID, toCustomerID, performedBy, "127.0.0.1", "test-agent")
 
 	s.Error(err)
 	s.ErrorIs(err, ErrFromCustomerMismatch)
@@ -346,9 +347,9 @@ func (s *AccountAssociationServiceTestSuite) TestTransferAccountOwnership_FromCu
 	}
 
 	s.mockAccountRepo.EXPECT().GetByID(accountID).Return(account, nil)
-	s.mockUserRepo.EXPECT().GetByIDActive(fromCustomerID).Return(nil, repositories.ErrUserNotFound)
+	s.mockUserRepo.EXPECT().GetByIDActive(gomock.Any(), fromCustomerID).Return(nil, repositories.ErrUserNotFound)
 
-	err := s.service.TransferAccountOwnership(accountID, fromCustomerID, toCustomerID, performedBy, "127.0.0.1", "test-agent")
+	err := s.service.TransferAccountOwnership(context.Background(), accountID, fromCustomerID, toCustomerID, performedBy, "127.0.0.1", "test-agent")
 
 	s.Error(err)
 	s.ErrorIs(err, ErrCustomerNotFound)
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -56,8 +57,8 @@ func NewAccountService(
 }
 
 // CreateAccount creates a new account for a user
-func (s *accountService) CreateAccount(userID uuid.UUID, accountType string, initialDeposit decimal.Decimal) (*models.Account, error) {
-	user, err := s.userRepo.GetByID(userID)
+func (s *accountService) CreateAccount(ctx context.Context, userID uuid.UUID, accountType string, initialDeposit decimal.Decimal) (*models.Account, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		if errors.Is(err, repositories.ErrUserNotFound) {
 			return nil, ErrUserNotFound
@@ -272,7 +273,7 @@ func (s *accountService) generateRandomBalance(min, max int) decimal.Decimal {
 }
 
 // GetAccountByID retrieves an account by ID with optional user verification
-func (s *accountService) GetAccountByID(accountID uuid.UUID, userID *uuid.UUID) (*models.Account, error) {
+func (s *accountService) GetAccountByID(ctx context.Context, accountID uuid.UUID, userID *uuid.UUID) (*models.Account, error) {
 	account, err := s.accountRepo.GetByID(accountID)
 	if err != nil {
 		if errors.Is(err, repositories.ErrAccountNotFound) {
@@ -283,7 +284,7 @@ func (s *accountService) GetAccountByID(accountID uuid.UUID, userID *uuid.UUID)
 
 	// Authorization: Non-admin users can only access their own accounts
 	if userID != nil && account.UserID != *userID {
-		user, err := s.userRepo.GetByID(*userID)
+		user, err := s.userRepo.GetByID(ctx, *userID)
 		if err != nil || !user.IsAdmin() {
 			return nil, ErrUnauthorized
 		}
@@ -323,8 +324,8 @@ func (s *accountService) GetAllAccounts(filters models.AccountFilters, offset, l
 }
 
 // UpdateAccountStatus updates the status of an account
-func (s *accountService) UpdateAccountStatus(accountID uuid.UUID, userID *uuid.UUID, status string) (*models.Account, error) {
-	account, err := s.GetAccountByID(accountID, userID)
+func (s *accountService) UpdateAccountStatus(ctx context.Context, accountID uuid.UUID, userID *uuid.UUID, status string) (*models.Account, error) {
+	account, err := s.GetAccountByID(ctx, accountID, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -368,8 +369,8 @@ func (s *accountService) UpdateAccountStatus(accountID uuid.UUID, userID *uuid.U
 }
 
 // CloseAccount closes an account
-func (s *accountService) CloseAccount(accountID uuid.UUID, userID uuid.UUID) error {
-	account, err := s.GetAccountByID(accountID, &userID)
+func (s *accountService) CloseAccount(ctx context.Context, accountID uuid.UUID, userID uuid.UUID) error {
+	account, err := s.GetAccountByID(ctx, accountID, &userID)
 	if err != nil {
 		return err
 	}
@@ -405,12 +406,12 @@ func (s *accountService) CloseAccount(accountID uuid.UUID, userID uuid.UUID) err
 }
 
 // PerformTransaction creates a transaction on an account
-func (s *accountService) PerformTransaction(accountID uuid.UUID, amount decimal.Decimal, transactionType, description string, userID *uuid.UUID) (*models.Transaction, error) {
+func (s *accountService) PerformTransaction(ctx context.Context, accountID uuid.UUID, amount decimal.Decimal, transactionType, description string, userID *uuid.UUID) (*models.Transaction, error) {
 	if amount.LessThanOrEqual(decimal.Zero) {
 		return nil, ErrInvalidAmount
 	}
 
-	account, err := s.GetAccountByID(accountID, userID)
+	account, err := s.GetAccountByID(ctx, accountID, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -446,7 +447,7 @@ func (s *accountService) PerformTransaction(accountID uuid.UUID, amount decimal.
 		Reference:       models.GenerateTransactionReference(),
 	}
 
-	if err := s.transactionRepo.Create(transaction); err != nil {
+	if err := s.transactionRepo.Create(ctx, transaction); err != nil {
 		return nil, fmt.Errorf("failed to create transaction record: %w", err)
 	}
 
@@ -686,13 +687,13 @@ func (s *accountService) handleTransferSuccess(
 }
 
 // GetAccountTransactions retrieves transactions for an account
-func (s *accountService) GetAccountTransactions(accountID uuid.UUID, userID *uuid.UUID, offset, limit int) ([]models.Transaction, int64, error) {
-	_, err := s.GetAccountByID(accountID, userID)
+func (s *accountService) GetAccountTransactions(ctx context.Context, accountID uuid.UUID, userID *uuid.UUID, offset, limit int) ([]models.Transaction, int64, error) {
+	_, err := s.GetAccountByID(ctx, accountID, userID)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	transactions, total, err := s.transactionRepo.GetByAccountID(accountID, offset, limit)
+	transactions, total, err := s.transactionRepo.GetByAccountID(ctx, accountID, offset, limit)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to get transactions: %w", err)
 	}
@@ -701,13 +702,13 @@ func (s *accountService) GetAccountTransactions(accountID uuid.UUID, userID *uui
 }
 
 // GetRecentTransactions retrieves recent transactions for an account
-func (s *accountService) GetRecentTransactions(accountID uuid.UUID, userID *uuid.UUID, limit int) ([]models.Transaction, error) {
-	_, err := s.GetAccountByID(accountID, userID)
+func (s *accountService) GetRecentTransactions(ctx context.Context, accountID uuid.UUID, userID *uuid.UUID, limit int) ([]models.Transaction, error) {
+	_, err := s.GetAccountByID(ctx, accountID, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	transactions, err := s.transactionRepo.GetRecentByAccountID(accountID, limit)
+	transactions, err := s.transactionRepo.GetRecentByAccountID(ctx, accountID, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get recent transactions: %w", err)
 	}
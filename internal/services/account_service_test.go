@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"testing"
@@ -72,7 +73,7 @@ func TestAccountServiceSuite(t *testing.T) {
 // Test CreateAccount functionality
 func (s *AccountServiceSuite) TestCreateAccount_WithInitialDeposit() {
 	// Setup expectations
-	s.userRepo.EXPECT().GetByID(s.testUserID).Return(s.testUser, nil)
+	s.userRepo.EXPECT().GetByID(gomock.Any(), s.testUserID).Return(s.testUser, nil)
 	s.accountRepo.EXPECT().ExistsForUser(s.testUserID, "checking").Return(false, nil)
 	s.accountRepo.EXPECT().GenerateUniqueAccountNumber("checking").Return("1012345678", nil)
 	s.accountRepo.EXPECT().CreateWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
@@ -90,7 +91,7 @@ func (s *AccountServiceSuite) TestCreateAccount_WithInitialDeposit() {
 		})
 	s.auditRepo.EXPECT().Create(gomock.Any()).Return(nil)
 
-	account, err := s.service.CreateAccount(s.testUserID, "checking", decimal.NewFromFloat(100))
+	account, err := s.service.CreateAccount(context.Background(), s.testUserID, "checking", decimal.NewFromFloat(100))
 	s.NoError(err)
 	s.NotNil(account)
 	s.Equal(s.testUserID, account.UserID)
@@ -101,7 +102,7 @@ func (s *AccountServiceSuite) TestCreateAccount_WithInitialDeposit() {
 }
 
 func (s *AccountServiceSuite) TestCreateAccount_WithoutInitialDeposit() {
-	s.userRepo.EXPECT().GetByID(s.testUserID).Return(s.testUser, nil)
+	s.userRepo.EXPECT().GetByID(gomock.Any(), s.testUserID).Return(s.testUser, nil)
 	s.accountRepo.EXPECT().ExistsForUser(s.testUserID, "savings").Return(false, nil)
 	s.accountRepo.EXPECT().GenerateUniqueAccountNumber("savings").Return("2012345679", nil)
 	s.accountRepo.EXPECT().CreateWithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
@@ -113,36 +114,36 @@ func (s *AccountServiceSuite) TestCreateAccount_WithoutInitialDeposit() {
 		})
 	s.auditRepo.EXPECT().Create(gomock.Any()).Return(nil)
 
-	account, err := s.service.CreateAccount(s.testUserID, "savings", decimal.Zero)
+	account, err := s.service.CreateAccount(context.Background(), s.testUserID, "savings", decimal.Zero)
 	s.NoError(err)
 	s.NotNil(account)
 	s.Equal(decimal.Zero, account.Balance)
 }
 
 func (s *AccountServiceSuite) TestCreateAccount_UserNotFound() {
-	s.userRepo.EXPECT().GetByID(s.testUserID).Return(nil, repositories.ErrUserNotFound)
+	s.userRepo.EXPECT().GetByID(gomock.Any(), s.testUserID).Return(nil, repositories.ErrUserNotFound)
 
-	account, err := s.service.CreateAccount(s.testUserID, "checking", decimal.Zero)
+	account, err := s.service.CreateAccount(context.Background(), s.testUserID, "checking", decimal.Zero)
 	s.Error(err)
 	s.Nil(account)
 	s.Equal(ErrUserNotFound, err)
 }
 
 func (s *AccountServiceSuite) TestCreateAccount_NegativeInitialDeposit() {
-	s.userRepo.EXPECT().GetByID(s.testUserID).Return(s.testUser, nil)
+	s.userRepo.EXPECT().GetByID(gomock.Any(), s.testUserID).Return(s.testUser, nil)
 	s.accountRepo.EXPECT().ExistsForUser(s.testUserID, "checking").Return(false, nil)
 
-	account, err := s.service.CreateAccount(s.testUserID, "checking", decimal.NewFromFloat(-100))
+	account, err := s.service.CreateAccount(context.Background(), s.testUserID, "checking", decimal.NewFromFloat(-100))
 	s.Error(err)
 	s.Nil(account)
 	s.Equal(ErrInvalidAmount, err)
 }
 
 func (s *AccountServiceSuite) TestCreateAccount_AccountAlreadyExists() {
-	s.userRepo.EXPECT().GetByID(s.testUserID).Return(s.testUser, nil)
+	s.userRepo.EXPECT().GetByID(gomock.Any(), s.testUserID).Return(s.testUser, nil)
 	s.accountRepo.EXPECT().ExistsForUser(s.testUserID, "checking").Return(true, nil)
 
-	account, err := s.service.CreateAccount(s.testUserID, "checking", decimal.Zero)
+	account, err := s.service.CreateAccount(context.Background(), s.testUserID, "checking", decimal.Zero)
 	s.Error(err)
 	s.Nil(account)
 	s.Equal(ErrAccountAlreadyExists, err)
@@ -161,7 +162,7 @@ func (s *AccountServiceSuite) TestGetAccountByID_WithoutUserVerification() {
 
 	s.accountRepo.EXPECT().GetByID(s.testAccountID).Return(account, nil)
 
-	result, err := s.service.GetAccountByID(s.testAccountID, nil)
+	result, err := s.service.GetAccountByID(context.Background(), s.testAccountID, nil)
 	s.NoError(err)
 	s.Equal(account, result)
 }
@@ -179,7 +180,7 @@ func (s *AccountServiceSuite) TestGetAccountByID_WithOwnerVerification() {
 	s.accountRepo.EXPECT().GetByID(s.testAccountID).Return(account, nil)
 
 	// Owner can access their own account
-	result, err := s.service.GetAccountByID(s.testAccountID, &s.testUserID)
+	result, err := s.service.GetAccountByID(context.Background(), s.testAccountID, &s.testUserID)
 	s.NoError(err)
 	s.Equal(account, result)
 }
@@ -202,10 +203,10 @@ func (s *AccountServiceSuite) TestGetAccountByID_AdminAccess() {
 	}
 
 	s.accountRepo.EXPECT().GetByID(s.testAccountID).Return(account, nil)
-	s.userRepo.EXPECT().GetByID(adminID).Return(adminUser, nil)
+	s.userRepo.EXPECT().GetByID(gomock.Any(), adminID).Return(adminUser, nil)
 
 	// Admin can access any account
-	result, err := s.service.GetAccountByID(s.testAccountID, &adminID)
+	result, err := s.service.GetAccountByID(context.Background(), s.testAccountID, &adminID)
 	s.NoError(err)
 	s.Equal(account, result)
 }
@@ -227,9 +228,9 @@ func (s *AccountServiceSuite) TestGetAccountByID_UnauthorizedAccess() {
 	}
 
 	s.accountRepo.EXPECT().GetByID(s.testAccountID).Return(account, nil)
-	s.userRepo.EXPECT().GetByID(otherUserID).Return(otherUser, nil)
+	s.userRepo.EXPECT().GetByID(gomock.Any(), otherUserID).Return(otherUser, nil)
 
-	result, err := s.service.GetAccountByID(s.testAccountID, &otherUserID)
+	result, err := s.service.GetAccountByID(context.Background(), s.testAccountID, &otherUserID)
 	s.Error(err)
 	s.Nil(result)
 	s.Equal(ErrUnauthorized, err)
@@ -238,7 +239,7 @@ func (s *AccountServiceSuite) TestGetAccountByID_UnauthorizedAccess() {
 func (s *AccountServiceSuite) TestGetAccountByID_NotFound() {
 	s.accountRepo.EXPECT().GetByID(s.testAccountID).Return(nil, repositories.ErrAccountNotFound)
 
-	result, err := s.service.GetAccountByID(s.testAccountID, nil)
+	result, err := s.service.GetAccountByID(context.Background(), s.testAccountID, nil)
 	s.Error(err)
 	s.Nil(result)
 	s.Equal(ErrAccountNotFound, err)
@@ -257,8 +258,8 @@ func (s *AccountServiceSuite) TestPerformTransaction_Credit() {
 
 	s.accountRepo.EXPECT().GetByID(s.testAccountID).Return(account, nil)
 	s.accountRepo.EXPECT().UpdateBalance(s.testAccountID, decimal.NewFromFloat(50), "credit").Return(nil)
-	s.transactionRepo.EXPECT().Create(gomock.Any()).DoAndReturn(
-		func(t *models.Transaction) error {
+	s.transactionRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, t *models.Transaction) error {
 			t.ID = uuid.New()
 			t.CreatedAt = s.testTime
 			t.UpdatedAt = s.testTime
@@ -266,7 +267,7 @@ func (s *AccountServiceSuite) TestPerformTransaction_Credit() {
 		})
 	s.auditRepo.EXPECT().Create(gomock.Any()).Return(nil)
 
-	transaction, err := s.service.PerformTransaction(s.testAccountID, decimal.NewFromFloat(50), "credit", "Deposit", &s.testUserID)
+	transaction, err := s.service.PerformTransaction(context.Background(), s.testAccountID, decimal.NewFromFloat(50), "credit", "Deposit", &s.testUserID)
 	s.NoError(err)
 	s.NotNil(transaction)
 	s.Equal(decimal.NewFromFloat(50), transaction.Amount)
@@ -285,8 +286,8 @@ func (s *AccountServiceSuite) TestPerformTransaction_Debit() {
 
 	s.accountRepo.EXPECT().GetByID(s.testAccountID).Return(account, nil)
 	s.accountRepo.EXPECT().UpdateBalance(s.testAccountID, decimal.NewFromFloat(100), "debit").Return(nil)
-	s.transactionRepo.EXPECT().Create(gomock.Any()).DoAndReturn(
-		func(t *models.Transaction) error {
+	s.transactionRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, t *models.Transaction) error {
 			t.ID = uuid.New()
 			t.CreatedAt = s.testTime
 			t.UpdatedAt = s.testTime
@@ -294,7 +295,7 @@ func (s *AccountServiceSuite) TestPerformTransaction_Debit() {
 		})
 	s.auditRepo.EXPECT().Create(gomock.Any()).Return(nil)
 
-	transaction, err := s.service.PerformTransaction(s.testAccountID, decimal.NewFromFloat(100), "debit", "Withdrawal", &s.testUserID)
+	transaction, err := s.service.PerformTransaction(context.Background(), s.testAccountID, decimal.NewFromFloat(100), "debit", "Withdrawal", &s.testUserID)
 	s.NoError(err)
 	s.NotNil(transaction)
 	s.Equal(decimal.NewFromFloat(100), transaction.Amount)
@@ -314,7 +315,7 @@ func (s *AccountServiceSuite) TestPerformTransaction_InsufficientFunds() {
 	s.accountRepo.EXPECT().GetByID(s.testAccountID).Return(account, nil)
 	s.accountRepo.EXPECT().UpdateBalance(s.testAccountID, decimal.NewFromFloat(1000), "debit").Return(repositories.ErrInsufficientFunds)
 
-	transaction, err := s.service.PerformTransaction(s.testAccountID, decimal.NewFromFloat(1000), "debit", "Large withdrawal", &s.testUserID)
+	transaction, err := s.service.PerformTransaction(context.Background(), s.testAccountID, decimal.NewFromFloat(1000), "debit", "Large withdrawal", &s.testUserID)
 	s.Error(err)
 	s.Nil(transaction)
 	s.Equal(ErrInsufficientFunds, err)
@@ -332,14 +333,14 @@ func (s *AccountServiceSuite) TestPerformTransaction_InactiveAccount() {
 
 	s.accountRepo.EXPECT().GetByID(s.testAccountID).Return(inactiveAccount, nil)
 
-	transaction, err := s.service.PerformTransaction(s.testAccountID, decimal.NewFromFloat(50), "credit", "Deposit", &s.testUserID)
+	transaction, err := s.service.PerformTransaction(context.Background(), s.testAccountID, decimal.NewFromFloat(50), "credit", "Deposit", &s.testUserID)
 	s.Error(err)
 	s.Nil(transaction)
 	s.Equal(ErrAccountNotActive, err)
 }
 
 func (s *AccountServiceSuite) TestPerformTransaction_InvalidAmount() {
-	transaction, err := s.service.PerformTransaction(s.testAccountID, decimal.NewFromFloat(-50), "credit", "Invalid", nil)
+	transaction, err := s.service.PerformTransaction(context.Background(), s.testAccountID, decimal.NewFromFloat(-50), "credit", "Invalid", nil)
 	s.Error(err)
 	s.Nil(transaction)
 	s.Equal(ErrInvalidAmount, err)
@@ -480,7 +481,7 @@ func (s *AccountServiceSuite) TestCloseAccount_Success() {
 		})
 	s.auditRepo.EXPECT().Create(gomock.Any()).Return(nil)
 
-	err := s.service.CloseAccount(s.testAccountID, s.testUserID)
+	err := s.service.CloseAccount(context.Background(), s.testAccountID, s.testUserID)
 	s.NoError(err)
 }
 
@@ -496,7 +497,7 @@ func (s *AccountServiceSuite) TestCloseAccount_NonZeroBalance() {
 
 	s.accountRepo.EXPECT().GetByID(s.testAccountID).Return(account, nil)
 
-	err := s.service.CloseAccount(s.testAccountID, s.testUserID)
+	err := s.service.CloseAccount(context.Background(), s.testAccountID, s.testUserID)
 	s.Error(err)
 	s.Equal(ErrAccountClosureNotAllowed, err)
 }
@@ -519,9 +520,9 @@ func (s *AccountServiceSuite) TestCloseAccount_Unauthorized() {
 		ID:   s.testUserID,
 		Role: models.RoleCustomer, // Not admin
 	}
-	s.userRepo.EXPECT().GetByID(s.testUserID).Return(user, nil)
+	s.userRepo.EXPECT().GetByID(gomock.Any(), s.testUserID).Return(user, nil)
 
-	err := s.service.CloseAccount(s.testAccountID, s.testUserID)
+	err := s.service.CloseAccount(context.Background(), s.testAccountID, s.testUserID)
 	s.Error(err)
 	s.Equal(ErrUnauthorized, err)
 }
@@ -540,7 +541,7 @@ func (s *AccountServiceSuite) TestUpdateAccountStatus_Active() {
 	s.accountRepo.EXPECT().Update(gomock.Any()).Return(nil)
 	s.auditRepo.EXPECT().Create(gomock.Any()).Return(nil)
 
-	result, err := s.service.UpdateAccountStatus(s.testAccountID, &s.testUserID, models.AccountStatusActive)
+	result, err := s.service.UpdateAccountStatus(context.Background(), s.testAccountID, &s.testUserID, models.AccountStatusActive)
 	s.NoError(err)
 	s.NotNil(result)
 	s.Equal(models.AccountStatusActive, result.Status)
@@ -559,7 +560,7 @@ func (s *AccountServiceSuite) TestUpdateAccountStatus_Inactive() {
 	s.accountRepo.EXPECT().Update(gomock.Any()).Return(nil)
 	s.auditRepo.EXPECT().Create(gomock.Any()).Return(nil)
 
-	result, err := s.service.UpdateAccountStatus(s.testAccountID, &s.testUserID, models.AccountStatusInactive)
+	result, err := s.service.UpdateAccountStatus(context.Background(), s.testAccountID, &s.testUserID, models.AccountStatusInactive)
 	s.NoError(err)
 	s.NotNil(result)
 	s.Equal(models.AccountStatusInactive, result.Status)
@@ -568,7 +569,7 @@ func (s *AccountServiceSuite) TestUpdateAccountStatus_Inactive() {
 func (s *AccountServiceSuite) TestUpdateAccountStatus_NotFound() {
 	s.accountRepo.EXPECT().GetByID(s.testAccountID).Return(nil, repositories.ErrAccountNotFound)
 
-	result, err := s.service.UpdateAccountStatus(s.testAccountID, &s.testUserID, models.AccountStatusActive)
+	result, err := s.service.UpdateAccountStatus(context.Background(), s.testAccountID, &s.testUserID, models.AccountStatusActive)
 	s.Error(err)
 	s.Nil(result)
 	s.Equal(ErrAccountNotFound, err)
@@ -585,7 +586,7 @@ func (s *AccountServiceSuite) TestUpdateAccountStatus_InvalidStatus() {
 	}
 	s.accountRepo.EXPECT().GetByID(s.testAccountID).Return(account, nil)
 
-	result, err := s.service.UpdateAccountStatus(s.testAccountID, &s.testUserID, "invalid_status")
+	result, err := s.service.UpdateAccountStatus(context.Background(), s.testAccountID, &s.testUserID, "invalid_status")
 	s.Error(err)
 	s.Nil(result)
 }
@@ -602,7 +603,7 @@ func (s *AccountServiceSuite) TestUpdateAccountStatus_RepoUpdateError() {
 	s.accountRepo.EXPECT().GetByID(s.testAccountID).Return(account, nil)
 	s.accountRepo.EXPECT().Update(gomock.Any()).Return(fmt.Errorf("db error"))
 
-	result, err := s.service.UpdateAccountStatus(s.testAccountID, &s.testUserID, models.AccountStatusActive)
+	result, err := s.service.UpdateAccountStatus(context.Background(), s.testAccountID, &s.testUserID, models.AccountStatusActive)
 	s.Error(err)
 	s.Nil(result)
 }
@@ -711,9 +712,9 @@ func (s *AccountServiceSuite) TestGetAccountTransactions_Success() {
 		},
 	}
 	s.accountRepo.EXPECT().GetByID(s.testAccountID).Return(account, nil)
-	s.transactionRepo.EXPECT().GetByAccountID(s.testAccountID, 0, 10).Return(transactions, int64(1), nil)
+	s.transactionRepo.EXPECT().GetByAccountID(gomock.Any(), s.testAccountID, 0, 10).Return(transactions, int64(1), nil)
 
-	result, total, err := s.service.GetAccountTransactions(s.testAccountID, &s.testUserID, 0, 10)
+	result, total, err := s.service.GetAccountTransactions(context.Background(), s.testAccountID, &s.testUserID, 0, 10)
 	s.NoError(err)
 	s.Len(result, 1)
 	s.Equal(int64(1), total)
@@ -730,9 +731,9 @@ func (s *AccountServiceSuite) TestGetAccountTransactions_Unauthorized() {
 		Status:        "active",
 	}
 	s.accountRepo.EXPECT().GetByID(s.testAccountID).Return(account, nil)
-	s.userRepo.EXPECT().GetByID(otherUserID).Return(&models.User{ID: otherUserID, Role: "user"}, nil)
+	s.userRepo.EXPECT().GetByID(gomock.Any(), otherUserID).Return(&models.User{ID: otherUserID, Role: "user"}, nil)
 
-	result, total, err := s.service.GetAccountTransactions(s.testAccountID, &otherUserID, 0, 10)
+	result, total, err := s.service.GetAccountTransactions(context.Background(), s.testAccountID, &otherUserID, 0, 10)
 	s.Error(err)
 	s.Nil(result)
 	s.Equal(int64(0), total)
@@ -749,9 +750,9 @@ func (s *AccountServiceSuite) TestGetAccountTransactions_RepoError() {
 		Status:        "active",
 	}
 	s.accountRepo.EXPECT().GetByID(s.testAccountID).Return(account, nil)
-	s.transactionRepo.EXPECT().GetByAccountID(s.testAccountID, 0, 10).Return(nil, int64(0), fmt.Errorf("db error"))
+	s.transactionRepo.EXPECT().GetByAccountID(gomock.Any(), s.testAccountID, 0, 10).Return(nil, int64(0), fmt.Errorf("db error"))
 
-	result, total, err := s.service.GetAccountTransactions(s.testAccountID, &s.testUserID, 0, 10)
+	result, total, err := s.service.GetAccountTransactions(context.Background(), s.testAccountID, &s.testUserID, 0, 10)
 	s.Error(err)
 	s.Nil(result)
 	s.Equal(int64(0), total)
@@ -777,9 +778,9 @@ func (s *AccountServiceSuite) TestGetRecentTransactions_Success() {
 		},
 	}
 	s.accountRepo.EXPECT().GetByID(s.testAccountID).Return(account, nil)
-	s.transactionRepo.EXPECT().GetRecentByAccountID(s.testAccountID, 5).Return(transactions, nil)
+	s.transactionRepo.EXPECT().GetRecentByAccountID(gomock.Any(), s.testAccountID, 5).Return(transactions, nil)
 
-	result, err := s.service.GetRecentTransactions(s.testAccountID, &s.testUserID, 5)
+	result, err := s.service.GetRecentTransactions(context.Background(), s.testAccountID, &s.testUserID, 5)
 	s.NoError(err)
 	s.Len(result, 1)
 }
@@ -794,9 +795,9 @@ func (s *AccountServiceSuite) TestGetRecentTransactions_RepoError() {
 		Status:        "active",
 	}
 	s.accountRepo.EXPECT().GetByID(s.testAccountID).Return(account, nil)
-	s.transactionRepo.EXPECT().GetRecentByAccountID(s.testAccountID, 5).Return(nil, fmt.Errorf("db error"))
+	s.transactionRepo.EXPECT().GetRecentByAccountID(gomock.Any(), s.testAccountID, 5).Return(nil, fmt.Errorf("db error"))
 
-	result, err := s.service.GetRecentTransactions(s.testAccountID, &s.testUserID, 5)
+	result, err := s.service.GetRecentTransactions(context.Background(), s.testAccountID, &s.testUserID, 5)
 	s.Error(err)
 	s.Nil(result)
 }
@@ -848,12 +849,12 @@ func (s *AccountServiceSuite) TestGetUserTransfers_RepoError() {
 
 // Test CreateAccount repo failure
 func (s *AccountServiceSuite) TestCreateAccount_CreateWithTransactionFails() {
-	s.userRepo.EXPECT().GetByID(s.testUserID).Return(s.testUser, nil)
+	s.userRepo.EXPECT().GetByID(gomock.Any(), s.testUserID).Return(s.testUser, nil)
 	s.accountRepo.EXPECT().ExistsForUser(s.testUserID, "checking").Return(false, nil)
 	s.accountRepo.EXPECT().GenerateUniqueAccountNumber("checking").Return("1012345678", nil)
 	s.accountRepo.EXPECT().CreateWithTransaction(gomock.Any(), gomock.Any()).Return(fmt.Errorf("db error"))
 
-	account, err := s.service.CreateAccount(s.testUserID, "checking", decimal.Zero)
+	account, err := s.service.CreateAccount(context.Background(), s.testUserID, "checking", decimal.Zero)
 	s.Error(err)
 	s.Nil(account)
 }
@@ -871,7 +872,7 @@ func (s *AccountServiceSuite) TestPerformTransaction_UpdateBalanceError() {
 	s.accountRepo.EXPECT().GetByID(s.testAccountID).Return(account, nil)
 	s.accountRepo.EXPECT().UpdateBalance(s.testAccountID, decimal.NewFromFloat(50), "credit").Return(fmt.Errorf("db error"))
 
-	transaction, err := s.service.PerformTransaction(s.testAccountID, decimal.NewFromFloat(50), "credit", "Deposit", &s.testUserID)
+	transaction, err := s.service.PerformTransaction(context.Background(), s.testAccountID, decimal.NewFromFloat(50), "credit", "Deposit", &s.testUserID)
 	s.Error(err)
 	s.Nil(transaction)
 }
@@ -887,9 +888,9 @@ func (s *AccountServiceSuite) TestPerformTransaction_CreateTransactionError() {
 	}
 	s.accountRepo.EXPECT().GetByID(s.testAccountID).Return(account, nil)
 	s.accountRepo.EXPECT().UpdateBalance(s.testAccountID, decimal.NewFromFloat(50), "credit").Return(nil)
-	s.transactionRepo.EXPECT().Create(gomock.Any()).Return(fmt.Errorf("db error"))
+	s.transactionRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(fmt.Errorf("db error"))
 
-	transaction, err := s.service.PerformTransaction(s.testAccountID, decimal.NewFromFloat(50), "credit", "Deposit", &s.testUserID)
+	transaction, err := s.service.PerformTransaction(context.Background(), s.testAccountID, decimal.NewFromFloat(50), "credit", "Deposit", &s.testUserID)
 	s.Error(err)
 	s.Nil(transaction)
 }
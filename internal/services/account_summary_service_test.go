@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -83,10 +84,10 @@ func (s *AccountSummaryServiceSuite) TestGetAccountSummary_RegularUser_Success()
 		},
 	}
 
-	s.userRepo.EXPECT().GetByID(s.testUserID).Return(testUser, nil)
+	s.userRepo.EXPECT().GetByID(gomock.Any(), s.testUserID).Return(testUser, nil)
 	s.accountRepo.EXPECT().GetByUserID(s.testUserID).Return(accounts, nil)
 
-	summary, err := s.service.GetAccountSummary(s.testUserID, &s.testUserID, false)
+	summary, err := s.service.GetAccountSummary(context.Background(), s.testUserID, &s.testUserID, false)
 	s.NoError(err)
 	s.NotNil(summary)
 	s.Equal(s.testUserID, summary.UserID)
@@ -135,11 +136,11 @@ func (s *AccountSummaryServiceSuite) TestGetAccountSummary_AdminUser_Success() {
 		},
 	}
 
-	s.userRepo.EXPECT().GetByID(s.testAdminID).Return(adminUser, nil)
-	s.userRepo.EXPECT().GetByID(s.testUserID).Return(targetUser, nil)
+	s.userRepo.EXPECT().GetByID(gomock.Any(), s.testAdminID).Return(adminUser, nil)
+	s.userRepo.EXPECT().GetByID(gomock.Any(), s.testUserID).Return(targetUser, nil)
 	s.accountRepo.EXPECT().GetByUserID(s.testUserID).Return(accounts, nil)
 
-	summary, err := s.service.GetAccountSummary(s.testAdminID, &s.testUserID, true)
+	summary, err := s.service.GetAccountSummary(context.Background(), s.testAdminID, &s.testUserID, true)
 	s.NoError(err)
 	s.NotNil(summary)
 	s.Equal(s.testUserID, summary.UserID)
@@ -159,9 +160,9 @@ func (s *AccountSummaryServiceSuite) TestGetAccountSummary_UnauthorizedAccess()
 
 	otherUserID := uuid.New()
 
-	s.userRepo.EXPECT().GetByID(s.testUserID).Return(testUser, nil)
+	s.userRepo.EXPECT().GetByID(gomock.Any(), s.testUserID).Return(testUser, nil)
 
-	summary, err := s.service.GetAccountSummary(s.testUserID, &otherUserID, false)
+	summary, err := s.service.GetAccountSummary(context.Background(), s.testUserID, &otherUserID, false)
 	s.Error(err)
 	s.Equal(ErrUnauthorized, err)
 	s.Nil(summary)
@@ -177,10 +178,10 @@ func (s *AccountSummaryServiceSuite) TestGetAccountSummary_NoAccounts() {
 		Role:      models.RoleCustomer,
 	}
 
-	s.userRepo.EXPECT().GetByID(s.testUserID).Return(testUser, nil)
+	s.userRepo.EXPECT().GetByID(gomock.Any(), s.testUserID).Return(testUser, nil)
 	s.accountRepo.EXPECT().GetByUserID(s.testUserID).Return([]models.Account{}, nil)
 
-	summary, err := s.service.GetAccountSummary(s.testUserID, &s.testUserID, false)
+	summary, err := s.service.GetAccountSummary(context.Background(), s.testUserID, &s.testUserID, false)
 	s.NoError(err)
 	s.NotNil(summary)
 	s.Equal(s.testUserID, summary.UserID)
@@ -191,9 +192,9 @@ func (s *AccountSummaryServiceSuite) TestGetAccountSummary_NoAccounts() {
 
 // Test GetAccountSummary with user not found
 func (s *AccountSummaryServiceSuite) TestGetAccountSummary_UserNotFound() {
-	s.userRepo.EXPECT().GetByID(s.testUserID).Return(nil, repositories.ErrUserNotFound)
+	s.userRepo.EXPECT().GetByID(gomock.Any(), s.testUserID).Return(nil, repositories.ErrUserNotFound)
 
-	summary, err := s.service.GetAccountSummary(s.testUserID, &s.testUserID, false)
+	summary, err := s.service.GetAccountSummary(context.Background(), s.testUserID, &s.testUserID, false)
 	s.Error(err)
 	s.Equal(ErrNotFound, err)
 	s.Nil(summary)
@@ -209,10 +210,10 @@ func (s *AccountSummaryServiceSuite) TestGetAccountSummary_TargetUserNotFound()
 		Role:      models.RoleAdmin,
 	}
 
-	s.userRepo.EXPECT().GetByID(s.testAdminID).Return(adminUser, nil)
-	s.userRepo.EXPECT().GetByID(s.testUserID).Return(nil, repositories.ErrUserNotFound)
+	s.userRepo.EXPECT().GetByID(gomock.Any(), s.testAdminID).Return(adminUser, nil)
+	s.userRepo.EXPECT().GetByID(gomock.Any(), s.testUserID).Return(nil, repositories.ErrUserNotFound)
 
-	summary, err := s.service.GetAccountSummary(s.testAdminID, &s.testUserID, true)
+	summary, err := s.service.GetAccountSummary(context.Background(), s.testAdminID, &s.testUserID, true)
 	s.Error(err)
 	s.Equal(ErrNotFound, err)
 	s.Nil(summary)
@@ -264,10 +265,10 @@ func (s *AccountSummaryServiceSuite) TestGetAccountSummary_MultipleAccountTypes(
 		},
 	}
 
-	s.userRepo.EXPECT().GetByID(s.testUserID).Return(testUser, nil)
+	s.userRepo.EXPECT().GetByID(gomock.Any(), s.testUserID).Return(testUser, nil)
 	s.accountRepo.EXPECT().GetByUserID(s.testUserID).Return(accounts, nil)
 
-	summary, err := s.service.GetAccountSummary(s.testUserID, &s.testUserID, false)
+	summary, err := s.service.GetAccountSummary(context.Background(), s.testUserID, &s.testUserID, false)
 	s.NoError(err)
 	s.NotNil(summary)
 	s.Equal(3, summary.AccountCount)
@@ -310,10 +311,10 @@ func (s *AccountSummaryServiceSuite) TestGetAccountSummary_WithInactiveAccounts(
 		},
 	}
 
-	s.userRepo.EXPECT().GetByID(s.testUserID).Return(testUser, nil)
+	s.userRepo.EXPECT().GetByID(gomock.Any(), s.testUserID).Return(testUser, nil)
 	s.accountRepo.EXPECT().GetByUserID(s.testUserID).Return(accounts, nil)
 
-	summary, err := s.service.GetAccountSummary(s.testUserID, &s.testUserID, false)
+	summary, err := s.service.GetAccountSummary(context.Background(), s.testUserID, &s.testUserID, false)
 	s.NoError(err)
 	s.NotNil(summary)
 	s.Equal(2, summary.AccountCount)
@@ -356,10 +357,10 @@ func (s *AccountSummaryServiceSuite) TestGetAccountSummary_AccountNumberMasking(
 		},
 	}
 
-	s.userRepo.EXPECT().GetByID(s.testUserID).Return(testUser, nil)
+	s.userRepo.EXPECT().GetByID(gomock.Any(), s.testUserID).Return(testUser, nil)
 	s.accountRepo.EXPECT().GetByUserID(s.testUserID).Return(accounts, nil)
 
-	summary, err := s.service.GetAccountSummary(s.testUserID, &s.testUserID, false)
+	summary, err := s.service.GetAccountSummary(context.Background(), s.testUserID, &s.testUserID, false)
 	s.NoError(err)
 	s.NotNil(summary)
 
@@ -51,6 +51,11 @@ func ValidateActivityType(action string) error {
 		models.AuditActionAccountTransferred: true,
 		models.AuditActionCustomerViewed:     true,
 		models.AuditActionActivityViewed:     true,
+
+		models.AuditActionNorthwindTransferCreated:             true,
+		models.AuditActionNorthwindTransferInternalTestChanged: true,
+		models.AuditActionNorthwindTransferCancelled:           true,
+		models.AuditActionNorthwindTransferReversed:            true,
 	}
 
 	if !validActions[action] {
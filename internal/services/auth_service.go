@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"crypto/sha256"
 	"errors"
 	"fmt"
@@ -56,8 +57,8 @@ func NewAuthService(
 }
 
 // Register creates a new user account
-func (s *AuthService) Register(req *dto.RegisterRequest, ipAddress, userAgent string) (*models.User, error) {
-	existingUser, err := s.userRepo.GetByEmail(req.Email)
+func (s *AuthService) Register(ctx context.Context, req *dto.RegisterRequest, ipAddress, userAgent string) (*models.User, error) {
+	existingUser, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil && !errors.Is(err, repositories.ErrUserNotFound) {
 		return nil, fmt.Errorf("failed to check existing user: %w", err)
 	}
@@ -80,7 +81,7 @@ func (s *AuthService) Register(req *dto.RegisterRequest, ipAddress, userAgent st
 		Role:         models.RoleCustomer,
 	}
 
-	if err := s.userRepo.Create(user); err != nil {
+	if err := s.userRepo.Create(ctx, user); err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
@@ -97,8 +98,8 @@ func (s *AuthService) Register(req *dto.RegisterRequest, ipAddress, userAgent st
 }
 
 // Login authenticates a user and returns tokens
-func (s *AuthService) Login(req *dto.LoginRequest, ipAddress, userAgent string) (*dto.TokenResponse, error) {
-	user, err := s.userRepo.GetByEmail(req.Email)
+func (s *AuthService) Login(ctx context.Context, req *dto.LoginRequest, ipAddress, userAgent string) (*dto.TokenResponse, error) {
+	user, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
 		if errors.Is(err, repositories.ErrUserNotFound) {
 			s.auditFailedLogin(req.Email, ipAddress, userAgent, "user_not_found")
@@ -114,7 +115,7 @@ func (s *AuthService) Login(req *dto.LoginRequest, ipAddress, userAgent string)
 
 	if !s.passwordService.ComparePassword(req.Password, user.PasswordHash) {
 		user.IncrementFailedAttempts()
-		if err := s.userRepo.UpdateFailedLoginAttempts(user); err != nil {
+		if err := s.userRepo.UpdateFailedLoginAttempts(ctx, user); err != nil {
 			// Security: Never reveal user existence via error messages
 			s.logger.Error("failed to update login attempts",
 				"error", err,
@@ -131,7 +132,7 @@ func (s *AuthService) Login(req *dto.LoginRequest, ipAddress, userAgent string)
 	}
 
 	user.ResetFailedAttempts()
-	if err := s.userRepo.UpdateFailedLoginAttempts(user); err != nil {
+	if err := s.userRepo.UpdateFailedLoginAttempts(ctx, user); err != nil {
 		// Non-critical: Audit logging failure shouldn't block login
 		s.logger.Warn("failed to reset login attempts",
 			"error", err,
@@ -150,7 +151,7 @@ func (s *AuthService) Login(req *dto.LoginRequest, ipAddress, userAgent string)
 }
 
 // RefreshTokens generates new tokens using a refresh token
-func (s *AuthService) RefreshTokens(refreshToken, ipAddress, userAgent string) (*dto.TokenResponse, error) {
+func (s *AuthService) RefreshTokens(ctx context.Context, refreshToken, ipAddress, userAgent string) (*dto.TokenResponse, error) {
 	claims, err := s.tokenService.ValidateRefreshToken(refreshToken)
 	if err != nil {
 		s.auditFailedTokenRefresh("", ipAddress, userAgent, "invalid_token")
@@ -173,7 +174,7 @@ func (s *AuthService) RefreshTokens(refreshToken, ipAddress, userAgent string) (
 		return nil, ErrInvalidRefreshToken
 	}
 
-	user, err := s.userRepo.GetByID(userID)
+	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
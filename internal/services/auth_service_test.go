@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"log/slog"
 	"testing"
@@ -59,13 +60,13 @@ func (s *AuthServiceTestSuite) TestRegister_SuccessfulRegistration() {
 	}
 
 	// Setup mock expectations
-	s.userRepo.EXPECT().GetByEmail(req.Email).Return(nil, repositories.ErrUserNotFound).Times(1)
+	s.userRepo.EXPECT().GetByEmail(gomock.Any(), req.Email).Return(nil, repositories.ErrUserNotFound).Times(1)
 	s.passwordService.EXPECT().HashPassword(req.Password).Return("hashed_password", nil).Times(1)
-	s.userRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+	s.userRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 	s.accountService.EXPECT().CreateAccountsForNewUser(gomock.Any()).Return(nil).Times(1)
 	s.auditRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(2) // account creation + registration audit logs
 
-	user, err := s.authService.Register(req, "192.168.1.1", "Mozilla/5.0")
+	user, err := s.authService.Register(context.Background(), req, "192.168.1.1", "Mozilla/5.0")
 
 	s.NoError(err)
 	s.NotNil(user)
@@ -92,11 +93,11 @@ func (s *AuthServiceTestSuite) TestRegister_UserAlreadyExists() {
 	}
 
 	// Setup mock expectations - user already exists
-	s.userRepo.EXPECT().GetByEmail(req.Email).Return(existingUser, nil).Times(1)
+	s.userRepo.EXPECT().GetByEmail(gomock.Any(), req.Email).Return(existingUser, nil).Times(1)
 	s.auditRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1) // failed registration audit log
 
 	// Attempt registration with existing email
-	user, err := s.authService.Register(req, "192.168.1.1", "Mozilla/5.0")
+	user, err := s.authService.Register(context.Background(), req, "192.168.1.1", "Mozilla/5.0")
 	s.Equal(ErrUserAlreadyExists, err)
 	s.Nil(user)
 }
@@ -110,11 +111,11 @@ func (s *AuthServiceTestSuite) TestRegister_WeakPasswordValidation() {
 	}
 
 	// Setup mock expectations
-	s.userRepo.EXPECT().GetByEmail(req.Email).Return(nil, repositories.ErrUserNotFound).Times(1)
+	s.userRepo.EXPECT().GetByEmail(gomock.Any(), req.Email).Return(nil, repositories.ErrUserNotFound).Times(1)
 	s.passwordService.EXPECT().HashPassword(req.Password).Return("", errors.New("password must be at least 12 characters")).Times(1)
 
 	// The password service enforces minimum length requirement
-	user, err := s.authService.Register(req, "192.168.1.1", "Mozilla/5.0")
+	user, err := s.authService.Register(context.Background(), req, "192.168.1.1", "Mozilla/5.0")
 	s.Error(err)
 	s.Contains(err.Error(), "password must be at least 12 characters")
 	s.Nil(user)
@@ -144,15 +145,15 @@ func (s *AuthServiceTestSuite) TestLogin_SuccessfulLogin() {
 	expiresAt := time.Now().Add(15 * time.Minute)
 
 	// Setup mock expectations
-	s.userRepo.EXPECT().GetByEmail(email).Return(user, nil).Times(1)
+	s.userRepo.EXPECT().GetByEmail(gomock.Any(), email).Return(user, nil).Times(1)
 	s.passwordService.EXPECT().ComparePassword(password, user.PasswordHash).Return(true).Times(1)
-	s.userRepo.EXPECT().UpdateFailedLoginAttempts(gomock.Any()).Return(nil).Times(1)
+	s.userRepo.EXPECT().UpdateFailedLoginAttempts(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 	s.tokenService.EXPECT().GenerateAccessToken(user).Return("access_token", expiresAt, nil).Times(1)
 	s.tokenService.EXPECT().GenerateRefreshToken(userID).Return("refresh_token", time.Now().Add(7*24*time.Hour), nil).Times(1)
 	s.refreshTokenRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
 	s.auditRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1) // successful login audit log
 
-	tokens, err := s.authService.Login(req, "192.168.1.1", "Mozilla/5.0")
+	tokens, err := s.authService.Login(context.Background(), req, "192.168.1.1", "Mozilla/5.0")
 
 	s.NoError(err)
 	s.NotNil(tokens)
@@ -183,12 +184,12 @@ func (s *AuthServiceTestSuite) TestLogin_InvalidPassword() {
 	}
 
 	// Setup mock expectations
-	s.userRepo.EXPECT().GetByEmail(email).Return(user, nil).Times(1)
+	s.userRepo.EXPECT().GetByEmail(gomock.Any(), email).Return(user, nil).Times(1)
 	s.passwordService.EXPECT().ComparePassword("WrongPassword", user.PasswordHash).Return(false).Times(1)
-	s.userRepo.EXPECT().UpdateFailedLoginAttempts(gomock.Any()).Return(nil).Times(1)
+	s.userRepo.EXPECT().UpdateFailedLoginAttempts(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 	s.auditRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1) // failed login audit log
 
-	tokens, err := s.authService.Login(req, "192.168.1.1", "Mozilla/5.0")
+	tokens, err := s.authService.Login(context.Background(), req, "192.168.1.1", "Mozilla/5.0")
 
 	s.Equal(ErrInvalidCredentials, err)
 	s.Nil(tokens)
@@ -201,10 +202,10 @@ func (s *AuthServiceTestSuite) TestLogin_NonExistentUser() {
 	}
 
 	// Setup mock expectations
-	s.userRepo.EXPECT().GetByEmail(req.Email).Return(nil, repositories.ErrUserNotFound).Times(1)
+	s.userRepo.EXPECT().GetByEmail(gomock.Any(), req.Email).Return(nil, repositories.ErrUserNotFound).Times(1)
 	s.auditRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1) // failed login audit log
 
-	tokens, err := s.authService.Login(req, "192.168.1.1", "Mozilla/5.0")
+	tokens, err := s.authService.Login(context.Background(), req, "192.168.1.1", "Mozilla/5.0")
 
 	s.Equal(ErrInvalidCredentials, err)
 	s.Nil(tokens)
@@ -233,12 +234,12 @@ func (s *AuthServiceTestSuite) TestLogin_AccountLockoutAfterFailedAttempts() {
 	}
 
 	// Third failed attempt - this should lock the account
-	s.userRepo.EXPECT().GetByEmail(lockoutEmail).Return(user, nil).Times(1)
+	s.userRepo.EXPECT().GetByEmail(gomock.Any(), lockoutEmail).Return(user, nil).Times(1)
 	s.passwordService.EXPECT().ComparePassword("WrongPassword", user.PasswordHash).Return(false).Times(1)
-	s.userRepo.EXPECT().UpdateFailedLoginAttempts(gomock.Any()).Return(nil).Times(1)
+	s.userRepo.EXPECT().UpdateFailedLoginAttempts(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 	s.auditRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(2) // account locked + failed login audit logs
 
-	_, err := s.authService.Login(wrongReq, "192.168.1.1", "Mozilla/5.0")
+	_, err := s.authService.Login(context.Background(), wrongReq, "192.168.1.1", "Mozilla/5.0")
 	s.Equal(ErrInvalidCredentials, err)
 
 	// Now try with correct password - should be locked
@@ -259,10 +260,10 @@ func (s *AuthServiceTestSuite) TestLogin_AccountLockoutAfterFailedAttempts() {
 		Password: lockoutPassword,
 	}
 
-	s.userRepo.EXPECT().GetByEmail(lockoutEmail).Return(lockedUser, nil).Times(1)
+	s.userRepo.EXPECT().GetByEmail(gomock.Any(), lockoutEmail).Return(lockedUser, nil).Times(1)
 	s.auditRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1) // account locked audit log
 
-	tokens, err := s.authService.Login(correctReq, "192.168.1.1", "Mozilla/5.0")
+	tokens, err := s.authService.Login(context.Background(), correctReq, "192.168.1.1", "Mozilla/5.0")
 	s.Equal(ErrAccountLocked, err)
 	s.Nil(tokens)
 }
@@ -295,14 +296,14 @@ func (s *AuthServiceTestSuite) TestRefreshTokens_SuccessfulTokenRefresh() {
 	// Setup mock expectations
 	s.tokenService.EXPECT().ValidateRefreshToken(refreshToken).Return(claims, nil).Times(1)
 	s.refreshTokenRepo.EXPECT().GetByTokenHash(gomock.Any()).Return(storedToken, nil).Times(1)
-	s.userRepo.EXPECT().GetByID(userID).Return(user, nil).Times(1)
+	s.userRepo.EXPECT().GetByID(gomock.Any(), userID).Return(user, nil).Times(1)
 	s.refreshTokenRepo.EXPECT().Update(gomock.Any()).Return(nil).Times(1) // Revoke old token
 	s.tokenService.EXPECT().GenerateAccessToken(user).Return("new_access_token", expiresAt, nil).Times(1)
 	s.tokenService.EXPECT().GenerateRefreshToken(userID).Return("new_refresh_token", time.Now().Add(7*24*time.Hour), nil).Times(1)
 	s.refreshTokenRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
 	s.auditRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1) // successful token refresh audit log
 
-	newTokens, err := s.authService.RefreshTokens(refreshToken, "192.168.1.1", "Mozilla/5.0")
+	newTokens, err := s.authService.RefreshTokens(context.Background(), refreshToken, "192.168.1.1", "Mozilla/5.0")
 
 	s.NoError(err)
 	s.NotNil(newTokens)
@@ -315,7 +316,7 @@ func (s *AuthServiceTestSuite) TestRefreshTokens_InvalidRefreshToken() {
 	s.tokenService.EXPECT().ValidateRefreshToken("invalid.refresh.token").Return(nil, errors.New("invalid token")).Times(1)
 	s.auditRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1) // failed token refresh audit log
 
-	tokens, err := s.authService.RefreshTokens("invalid.refresh.token", "192.168.1.1", "Mozilla/5.0")
+	tokens, err := s.authService.RefreshTokens(context.Background(), "invalid.refresh.token", "192.168.1.1", "Mozilla/5.0")
 
 	s.Equal(ErrInvalidRefreshToken, err)
 	s.Nil(tokens)
@@ -352,14 +353,14 @@ func (s *AuthServiceTestSuite) TestRefreshTokens_UsingRevokedRefreshToken() {
 	// First refresh expectations
 	s.tokenService.EXPECT().ValidateRefreshToken(refreshToken).Return(claims, nil).Times(1)
 	s.refreshTokenRepo.EXPECT().GetByTokenHash(gomock.Any()).Return(storedToken, nil).Times(1)
-	s.userRepo.EXPECT().GetByID(userID).Return(user, nil).Times(1)
+	s.userRepo.EXPECT().GetByID(gomock.Any(), userID).Return(user, nil).Times(1)
 	s.refreshTokenRepo.EXPECT().Update(gomock.Any()).Return(nil).Times(1) // Revoke old token
 	s.tokenService.EXPECT().GenerateAccessToken(user).Return("new_access_token", expiresAt, nil).Times(1)
 	s.tokenService.EXPECT().GenerateRefreshToken(userID).Return("new_refresh_token", now.Add(7*24*time.Hour), nil).Times(1)
 	s.refreshTokenRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
 	s.auditRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
 
-	newTokens, err := s.authService.RefreshTokens(refreshToken, "192.168.1.1", "Mozilla/5.0")
+	newTokens, err := s.authService.RefreshTokens(context.Background(), refreshToken, "192.168.1.1", "Mozilla/5.0")
 	s.NoError(err)
 	s.NotNil(newTokens)
 
@@ -376,7 +377,7 @@ func (s *AuthServiceTestSuite) TestRefreshTokens_UsingRevokedRefreshToken() {
 	s.refreshTokenRepo.EXPECT().GetByTokenHash(gomock.Any()).Return(revokedToken, nil).Times(1)
 	s.auditRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1) // failed token refresh audit log
 
-	tokens2, err := s.authService.RefreshTokens(refreshToken, "192.168.1.1", "Mozilla/5.0")
+	tokens2, err := s.authService.RefreshTokens(context.Background(), refreshToken, "192.168.1.1", "Mozilla/5.0")
 	s.Equal(ErrInvalidRefreshToken, err)
 	s.Nil(tokens2)
 }
@@ -408,7 +409,7 @@ func (s *AuthServiceTestSuite) TestLogout_SuccessfulLogout() {
 	s.refreshTokenRepo.EXPECT().GetByTokenHash(gomock.Any()).Return(nil, repositories.ErrRefreshTokenNotFound).Times(1)
 	s.auditRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1) // failed token refresh audit log
 
-	newTokens, err := s.authService.RefreshTokens(refreshToken, "192.168.1.1", "Mozilla/5.0")
+	newTokens, err := s.authService.RefreshTokens(context.Background(), refreshToken, "192.168.1.1", "Mozilla/5.0")
 	s.Equal(ErrInvalidRefreshToken, err)
 	s.Nil(newTokens)
 }
@@ -473,23 +474,23 @@ func (s *AuthServiceTestSuite) TestPasswordHashing_PasswordsAreHashedDifferently
 	}
 
 	// First registration
-	s.userRepo.EXPECT().GetByEmail(req1.Email).Return(nil, repositories.ErrUserNotFound).Times(1)
+	s.userRepo.EXPECT().GetByEmail(gomock.Any(), req1.Email).Return(nil, repositories.ErrUserNotFound).Times(1)
 	s.passwordService.EXPECT().HashPassword(password).Return("hashed_password_1", nil).Times(1)
-	s.userRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+	s.userRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 	s.accountService.EXPECT().CreateAccountsForNewUser(gomock.Any()).Return(nil).Times(1)
 	s.auditRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(2) // account creation + registration audit logs
 
-	user1, err := s.authService.Register(req1, "192.168.1.1", "Mozilla/5.0")
+	user1, err := s.authService.Register(context.Background(), req1, "192.168.1.1", "Mozilla/5.0")
 	s.Require().NoError(err)
 
 	// Second registration with different hash (due to salt)
-	s.userRepo.EXPECT().GetByEmail(req2.Email).Return(nil, repositories.ErrUserNotFound).Times(1)
+	s.userRepo.EXPECT().GetByEmail(gomock.Any(), req2.Email).Return(nil, repositories.ErrUserNotFound).Times(1)
 	s.passwordService.EXPECT().HashPassword(password).Return("hashed_password_2", nil).Times(1)
-	s.userRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+	s.userRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 	s.accountService.EXPECT().CreateAccountsForNewUser(gomock.Any()).Return(nil).Times(1)
 	s.auditRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(2) // account creation + registration audit logs
 
-	user2, err := s.authService.Register(req2, "192.168.1.1", "Mozilla/5.0")
+	user2, err := s.authService.Register(context.Background(), req2, "192.168.1.1", "Mozilla/5.0")
 	s.Require().NoError(err)
 
 	// Same password should result in different hashes (due to salt)
@@ -523,28 +524,28 @@ func (s *AuthServiceTestSuite) TestPasswordHashing_PasswordsAreHashedDifferently
 	expiresAt := time.Now().Add(15 * time.Minute)
 
 	// First login
-	s.userRepo.EXPECT().GetByEmail(req1.Email).Return(user1Model, nil).Times(1)
+	s.userRepo.EXPECT().GetByEmail(gomock.Any(), req1.Email).Return(user1Model, nil).Times(1)
 	s.passwordService.EXPECT().ComparePassword(password, "hashed_password_1").Return(true).Times(1)
-	s.userRepo.EXPECT().UpdateFailedLoginAttempts(gomock.Any()).Return(nil).Times(1)
+	s.userRepo.EXPECT().UpdateFailedLoginAttempts(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 	s.tokenService.EXPECT().GenerateAccessToken(user1Model).Return("access_token_1", expiresAt, nil).Times(1)
 	s.tokenService.EXPECT().GenerateRefreshToken(userID1).Return("refresh_token_1", time.Now().Add(7*24*time.Hour), nil).Times(1)
 	s.refreshTokenRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
 	s.auditRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
 
-	tokens1, err := s.authService.Login(loginReq1, "192.168.1.1", "Mozilla/5.0")
+	tokens1, err := s.authService.Login(context.Background(), loginReq1, "192.168.1.1", "Mozilla/5.0")
 	s.NoError(err)
 	s.NotNil(tokens1)
 
 	// Second login
-	s.userRepo.EXPECT().GetByEmail(req2.Email).Return(user2Model, nil).Times(1)
+	s.userRepo.EXPECT().GetByEmail(gomock.Any(), req2.Email).Return(user2Model, nil).Times(1)
 	s.passwordService.EXPECT().ComparePassword(password, "hashed_password_2").Return(true).Times(1)
-	s.userRepo.EXPECT().UpdateFailedLoginAttempts(gomock.Any()).Return(nil).Times(1)
+	s.userRepo.EXPECT().UpdateFailedLoginAttempts(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 	s.tokenService.EXPECT().GenerateAccessToken(user2Model).Return("access_token_2", expiresAt, nil).Times(1)
 	s.tokenService.EXPECT().GenerateRefreshToken(userID2).Return("refresh_token_2", time.Now().Add(7*24*time.Hour), nil).Times(1)
 	s.refreshTokenRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
 	s.auditRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
 
-	tokens2, err := s.authService.Login(loginReq2, "192.168.1.1", "Mozilla/5.0")
+	tokens2, err := s.authService.Login(context.Background(), loginReq2, "192.168.1.1", "Mozilla/5.0")
 	s.NoError(err)
 	s.NotNil(tokens2)
 }
@@ -558,9 +559,9 @@ func (s *AuthServiceTestSuite) TestAuditLogging_RegistrationCreatesAuditLog() {
 	}
 
 	// Setup mock expectations - the audit log create should be called
-	s.userRepo.EXPECT().GetByEmail(req.Email).Return(nil, repositories.ErrUserNotFound).Times(1)
+	s.userRepo.EXPECT().GetByEmail(gomock.Any(), req.Email).Return(nil, repositories.ErrUserNotFound).Times(1)
 	s.passwordService.EXPECT().HashPassword(req.Password).Return("hashed_password", nil).Times(1)
-	s.userRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+	s.userRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 	s.accountService.EXPECT().CreateAccountsForNewUser(gomock.Any()).Return(nil).Times(1)
 
 	// Capture the audit log to verify it was created
@@ -572,7 +573,7 @@ func (s *AuthServiceTestSuite) TestAuditLogging_RegistrationCreatesAuditLog() {
 		return nil
 	}).Times(2) // account creation + registration audit logs
 
-	_, err := s.authService.Register(req, "192.168.1.1", "TestAgent")
+	_, err := s.authService.Register(context.Background(), req, "192.168.1.1", "TestAgent")
 	s.Require().NoError(err)
 
 	// Verify that registration audit log was created
@@ -589,7 +590,7 @@ func (s *AuthServiceTestSuite) TestAuditLogging_FailedLoginCreatesAuditLog() {
 	}
 
 	// Setup mock expectations
-	s.userRepo.EXPECT().GetByEmail(req.Email).Return(nil, repositories.ErrUserNotFound).Times(1)
+	s.userRepo.EXPECT().GetByEmail(gomock.Any(), req.Email).Return(nil, repositories.ErrUserNotFound).Times(1)
 
 	// Capture the audit log to verify it was created
 	var capturedAuditLog *models.AuditLog
@@ -600,7 +601,7 @@ func (s *AuthServiceTestSuite) TestAuditLogging_FailedLoginCreatesAuditLog() {
 		return nil
 	}).Times(1) // failed login audit log
 
-	_, err := s.authService.Login(req, "192.168.1.2", "TestAgent2")
+	_, err := s.authService.Login(context.Background(), req, "192.168.1.2", "TestAgent2")
 	s.Error(err)
 
 	// Verify that failed login audit log was created
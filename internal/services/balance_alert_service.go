@@ -0,0 +1,306 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// NotificationEventBalanceAlert is the event type of notifications raised by balance alert rules
+const NotificationEventBalanceAlert = "BALANCE_ALERT"
+
+// DefaultBalanceAlertCooldown is the minimum time between two alerts for the same rule
+const DefaultBalanceAlertCooldown = 24 * time.Hour
+
+var (
+	ErrBalanceAlertRuleNotFound = errors.New("balance alert rule not found")
+	ErrInvalidBalanceAlertRule  = errors.New("invalid balance alert rule")
+)
+
+// BalanceAlertRuleUpdate sets a rule's threshold, direction, channel and schedule
+type BalanceAlertRuleUpdate struct {
+	Threshold float64 `json:"threshold" validate:"required,gt=0"`
+	Direction string  `json:"direction" validate:"required,oneof=BELOW ABOVE"`
+	Channel   string  `json:"channel" validate:"required,oneof=IN_APP EMAIL"`
+	Frequent  bool    `json:"frequent"`
+}
+
+// CreateBalanceAlertRuleRequest creates a rule on one of the caller's registered external accounts
+type CreateBalanceAlertRuleRequest struct {
+	ExternalAccountID uuid.UUID `json:"external_account_id" validate:"required"`
+	BalanceAlertRuleUpdate
+}
+
+// BalanceAlertRun summarizes one evaluation of the balance alert rules
+type BalanceAlertRun struct {
+	Evaluated int `json:"evaluated"`
+	Alerted   int `json:"alerted"`
+	// Failed counts rules whose account balance could not be read; they keep their last state
+	Failed int `json:"failed"`
+}
+
+// BalanceAlertService manages users' balance threshold alerts on their registered external
+// accounts and evaluates them against the available balances NorthWind reports. A rule alerts its
+// owner when it moves from satisfied to breached, at most once per cooldown.
+type BalanceAlertService struct {
+	client        *northwind.Client
+	repo          repositories.BalanceAlertRuleRepositoryInterface
+	accounts      repositories.NorthwindExternalAccountRepositoryInterface
+	notifications repositories.UserNotificationRepositoryInterface
+	userRepo      repositories.UserRepositoryInterface
+	logger        *slog.Logger
+	cooldown      time.Duration
+	now           func() time.Time
+}
+
+// NewBalanceAlertService creates a new balance alert service
+func NewBalanceAlertService(
+	client *northwind.Client,
+	repo repositories.BalanceAlertRuleRepositoryInterface,
+	accounts repositories.NorthwindExternalAccountRepositoryInterface,
+	notifications repositories.UserNotificationRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	logger *slog.Logger,
+) *BalanceAlertService {
+	return &BalanceAlertService{
+		client:        client,
+		repo:          repo,
+		accounts:      accounts,
+		notifications: notifications,
+		userRepo:      userRepo,
+		logger:        logger,
+		cooldown:      DefaultBalanceAlertCooldown,
+		now:           time.Now,
+	}
+}
+
+// SetCooldown sets the minimum time between two alerts for the same rule. A rule that recovers
+// and breaches again within the cooldown records the breach without alerting.
+func (s *BalanceAlertService) SetCooldown(cooldown time.Duration) {
+	s.cooldown = cooldown
+}
+
+// CreateRule creates a rule on one of the user's registered external accounts
+func (s *BalanceAlertService) CreateRule(ctx context.Context, userID uuid.UUID, req CreateBalanceAlertRuleRequest) (*models.BalanceAlertRule, error) {
+	account, err := s.accounts.GetByID(ctx, req.ExternalAccountID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNorthwindExternalAccountNotFound) {
+			return nil, ErrExternalAccountNotFound
+		}
+		return nil, err
+	}
+	if account.UserID == nil || *account.UserID != userID {
+		return nil, ErrExternalAccountNotFound
+	}
+
+	rule := &models.BalanceAlertRule{UserID: userID, ExternalAccountID: account.ID}
+	if err := applyBalanceAlertRuleUpdate(rule, req.BalanceAlertRuleUpdate); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// GetRule returns one of the user's rules. Rules of other users are reported as not found.
+func (s *BalanceAlertService) GetRule(ctx context.Context, userID, ruleID uuid.UUID) (*models.BalanceAlertRule, error) {
+	rule, err := s.repo.GetByID(ctx, ruleID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrBalanceAlertRuleNotFound) {
+			return nil, ErrBalanceAlertRuleNotFound
+		}
+		return nil, err
+	}
+	if rule.UserID != userID {
+		return nil, ErrBalanceAlertRuleNotFound
+	}
+	return rule, nil
+}
+
+// ListRules returns a page of the user's rules and the total number of them
+func (s *BalanceAlertService) ListRules(ctx context.Context, userID uuid.UUID, offset, limit int) ([]models.BalanceAlertRule, int64, error) {
+	return s.repo.ListByUser(ctx, userID, offset, limit)
+}
+
+// UpdateRule changes one of the user's rules. The rule starts over as satisfied, so a changed
+// rule that is already breached alerts on its next evaluation, subject to the cooldown.
+func (s *BalanceAlertService) UpdateRule(ctx context.Context, userID, ruleID uuid.UUID, update BalanceAlertRuleUpdate) (*models.BalanceAlertRule, error) {
+	rule, err := s.GetRule(ctx, userID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyBalanceAlertRuleUpdate(rule, update); err != nil {
+		return nil, err
+	}
+	rule.Breached = false
+	if err := s.repo.Update(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// DeleteRule deletes one of the user's rules
+func (s *BalanceAlertService) DeleteRule(ctx context.Context, userID, ruleID uuid.UUID) error {
+	if _, err := s.GetRule(ctx, userID, ruleID); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, ruleID); err != nil {
+		if errors.Is(err, repositories.ErrBalanceAlertRuleNotFound) {
+			return ErrBalanceAlertRuleNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func applyBalanceAlertRuleUpdate(rule *models.BalanceAlertRule, update BalanceAlertRuleUpdate) error {
+	if update.Threshold <= 0 {
+		return fmt.Errorf("%w: threshold must be positive", ErrInvalidBalanceAlertRule)
+	}
+	switch update.Direction {
+	case models.BalanceAlertDirectionBelow, models.BalanceAlertDirectionAbove:
+	default:
+		return fmt.Errorf("%w: direction must be %s or %s", ErrInvalidBalanceAlertRule, models.BalanceAlertDirectionBelow, models.BalanceAlertDirectionAbove)
+	}
+	switch update.Channel {
+	case models.NotificationChannelInApp, models.NotificationChannelEmail:
+	default:
+		return fmt.Errorf("%w: channel must be %s or %s", ErrInvalidBalanceAlertRule, models.NotificationChannelInApp, models.NotificationChannelEmail)
+	}
+	rule.Threshold = decimal.NewFromFloat(update.Threshold).Round(2)
+	rule.Direction = update.Direction
+	rule.Channel = update.Channel
+	rule.Frequent = update.Frequent
+	return nil
+}
+
+// EvaluateRules checks every rule, or only the frequent ones, against its account's available
+// balance, reading each account's balance once. A rule whose balance cannot be read is skipped
+// and keeps its last state; the run carries on with the other accounts.
+func (s *BalanceAlertService) EvaluateRules(ctx context.Context, frequentOnly bool) (*BalanceAlertRun, error) {
+	rules, err := s.repo.ListForEvaluation(ctx, frequentOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &BalanceAlertRun{}
+	balances := make(map[uuid.UUID]decimal.Decimal)
+	unreadable := make(map[uuid.UUID]bool)
+	for i := range rules {
+		rule := &rules[i]
+		if unreadable[rule.ExternalAccountID] {
+			run.Failed++
+			continue
+		}
+		balance, ok := balances[rule.ExternalAccountID]
+		if !ok {
+			balance, err = s.availableBalance(ctx, rule.ExternalAccountID)
+			if err != nil {
+				if ctx.Err() != nil {
+					return run, ctx.Err()
+				}
+				s.logger.Warn("Balance alert account balance unavailable", "external_account_id", rule.ExternalAccountID, "error", err)
+				unreadable[rule.ExternalAccountID] = true
+				run.Failed++
+				continue
+			}
+			balances[rule.ExternalAccountID] = balance
+		}
+
+		alerted, err := s.evaluateRule(ctx, rule, balance)
+		if err != nil {
+			return run, err
+		}
+		run.Evaluated++
+		if alerted {
+			run.Alerted++
+		}
+	}
+	return run, nil
+}
+
+// availableBalance reads the available balance NorthWind reports for a registered external account
+func (s *BalanceAlertService) availableBalance(ctx context.Context, externalAccountID uuid.UUID) (decimal.Decimal, error) {
+	account, err := s.accounts.GetByID(ctx, externalAccountID)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	balance, err := s.client.GetAccountBalance(ctx, account.AccountNumber)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return decimal.NewFromFloat(balance.AvailableBalance), nil
+}
+
+// evaluateRule records the rule's state for balance and alerts when it has just been breached
+// and the cooldown since its last alert has passed. It reports whether an alert was raised.
+func (s *BalanceAlertService) evaluateRule(ctx context.Context, rule *models.BalanceAlertRule, balance decimal.Decimal) (bool, error) {
+	now := s.now()
+	breached := rule.BreachedBy(balance)
+	alert := breached && !rule.Breached &&
+		(rule.LastAlertedAt == nil || now.Sub(*rule.LastAlertedAt) >= s.cooldown)
+
+	if alert {
+		if err := s.notify(ctx, rule, balance); err != nil {
+			return false, err
+		}
+		rule.LastAlertedAt = &now
+	}
+	rule.Breached = breached
+	rule.LastBalance = &balance
+	rule.LastEvaluatedAt = &now
+	if err := s.repo.Update(ctx, rule); err != nil {
+		return false, err
+	}
+	return alert, nil
+}
+
+// notify delivers an alert for rule on its channel
+func (s *BalanceAlertService) notify(ctx context.Context, rule *models.BalanceAlertRule, balance decimal.Decimal) error {
+	title, message := balanceAlertText(rule, balance)
+	notification := &models.UserNotification{
+		UserID:    rule.UserID,
+		EventType: NotificationEventBalanceAlert,
+		Channel:   rule.Channel,
+		Title:     title,
+		Message:   message,
+	}
+	if rule.Channel == models.NotificationChannelEmail {
+		user, err := s.userRepo.GetByID(ctx, rule.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to load notification recipient: %w", err)
+		}
+		notification.Recipient = user.Email
+	}
+	if err := s.notifications.Create(ctx, notification); err != nil {
+		return fmt.Errorf("failed to create balance alert notification: %w", err)
+	}
+
+	s.logger.Info("Balance alert raised",
+		"rule_id", rule.ID,
+		"external_account_id", rule.ExternalAccountID,
+		"direction", rule.Direction,
+		"channel", rule.Channel,
+	)
+	return nil
+}
+
+func balanceAlertText(rule *models.BalanceAlertRule, balance decimal.Decimal) (string, string) {
+	side := "below"
+	if rule.Direction == models.BalanceAlertDirectionAbove {
+		side = "above"
+	}
+	title := fmt.Sprintf("Balance %s %s", side, rule.Threshold.StringFixed(2))
+	message := fmt.Sprintf("The available balance of your external account is %s, %s your alert threshold of %s.",
+		balance.StringFixed(2), side, rule.Threshold.StringFixed(2))
+	return title, message
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type balanceAlertTestEnv struct {
+	svc           *BalanceAlertService
+	db            *gorm.DB
+	notifications repositories.UserNotificationRepositoryInterface
+	user          *models.User
+	account       *models.NorthwindExternalAccount
+	now           time.Time
+
+	mu       sync.Mutex
+	balances map[string]float64
+}
+
+func newBalanceAlertTestEnv(t *testing.T) *balanceAlertTestEnv {
+	t.Helper()
+	env := &balanceAlertTestEnv{
+		db:       testfactory.NewDB(t),
+		now:      time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+		balances: map[string]float64{},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accountNumber := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/external/accounts/"), "/balance")
+		env.mu.Lock()
+		balance, ok := env.balances[accountNumber]
+		env.mu.Unlock()
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(northwind.AccountBalance{AccountNumber: accountNumber, AvailableBalance: balance, Currency: "USD"})
+	}))
+	t.Cleanup(server.Close)
+
+	env.user = &models.User{Email: "treasury@example.com", FirstName: "Tara", LastName: "Reyes", PasswordHash: "x", Role: models.RoleCustomer}
+	if err := env.db.Create(env.user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	env.account = testfactory.NWExternalAccount(t, env.db, testfactory.WithOwner(env.user.ID))
+	env.setBalance(env.account.AccountNumber, 80000)
+
+	env.notifications = repositories.NewUserNotificationRepository(env.db)
+	env.svc = NewBalanceAlertService(northwind.NewClient(server.URL, "test-key"), repositories.NewBalanceAlertRuleRepository(env.db),
+		repositories.NewNorthwindExternalAccountRepository(env.db), env.notifications, repositories.NewUserRepository(env.db), slog.Default())
+	env.svc.now = func() time.Time { return env.now }
+	return env
+}
+
+func (env *balanceAlertTestEnv) setBalance(accountNumber string, balance float64) {
+	env.mu.Lock()
+	defer env.mu.Unlock()
+	env.balances[accountNumber] = balance
+}
+
+func (env *balanceAlertTestEnv) createRule(t *testing.T, update BalanceAlertRuleUpdate) *models.BalanceAlertRule {
+	t.Helper()
+	rule, err := env.svc.CreateRule(context.Background(), env.user.ID, CreateBalanceAlertRuleRequest{
+		ExternalAccountID:      env.account.ID,
+		BalanceAlertRuleUpdate: update,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return rule
+}
+
+// evaluate runs every rule after advancing the clock by elapsed and returns the run
+func (env *balanceAlertTestEnv) evaluate(t *testing.T, elapsed time.Duration, balance float64) *BalanceAlertRun {
+	t.Helper()
+	env.now = env.now.Add(elapsed)
+	env.setBalance(env.account.AccountNumber, balance)
+	run, err := env.svc.EvaluateRules(context.Background(), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return run
+}
+
+func (env *balanceAlertTestEnv) alerts(t *testing.T, channel string) []models.UserNotification {
+	t.Helper()
+	notifications, err := env.notifications.ListByUser(context.Background(), env.user.ID, channel)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return notifications
+}
+
+func TestBalanceAlertService_EvaluateRules_AlertsOnTransitionToBreached(t *testing.T) {
+	env := newBalanceAlertTestEnv(t)
+	rule := env.createRule(t, BalanceAlertRuleUpdate{Threshold: 50000, Direction: models.BalanceAlertDirectionBelow, Channel: models.NotificationChannelInApp})
+
+	steps := []struct {
+		name       string
+		balance    float64
+		wantAlerts int
+	}{
+		{"satisfied", 80000, 0},
+		{"at the threshold is not a breach", 50000, 0},
+		{"breached", 42000.50, 1},
+		{"still breached", 30000, 1},
+	}
+	for _, step := range steps {
+		run := env.evaluate(t, time.Hour, step.balance)
+		if run.Evaluated != 1 || run.Failed != 0 {
+			t.Fatalf("%s: expected the rule evaluated, got %+v", step.name, run)
+		}
+		if got := len(env.alerts(t, models.NotificationChannelInApp)); got != step.wantAlerts {
+			t.Fatalf("%s: expected %d alerts, got %d", step.name, step.wantAlerts, got)
+		}
+	}
+
+	alert := env.alerts(t, models.NotificationChannelInApp)[0]
+	if alert.EventType != NotificationEventBalanceAlert || !strings.Contains(alert.Message, "42000.50") {
+		t.Errorf("expected a balance alert naming the balance, got %+v", alert)
+	}
+	stored, err := env.svc.GetRule(context.Background(), env.user.ID, rule.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !stored.Breached || stored.LastBalance == nil || stored.LastBalance.StringFixed(2) != "30000.00" || stored.LastAlertedAt == nil {
+		t.Errorf("expected the breached state recorded, got %+v", stored)
+	}
+}
+
+func TestBalanceAlertService_EvaluateRules_Cooldown(t *testing.T) {
+	env := newBalanceAlertTestEnv(t)
+	env.svc.SetCooldown(24 * time.Hour)
+	env.createRule(t, BalanceAlertRuleUpdate{Threshold: 100000, Direction: models.BalanceAlertDirectionAbove, Channel: models.NotificationChannelEmail})
+
+	env.evaluate(t, 0, 120000)
+	env.evaluate(t, time.Hour, 90000)
+	// Breached again within the cooldown: recorded but not alerted
+	if run := env.evaluate(t, time.Hour, 130000); run.Alerted != 0 {
+		t.Fatalf("expected no alert within the cooldown, got %+v", run)
+	}
+	env.evaluate(t, time.Hour, 90000)
+	// Breached again once the cooldown has passed
+	if run := env.evaluate(t, 24*time.Hour, 125000); run.Alerted != 1 {
+		t.Fatalf("expected an alert after the cooldown, got %+v", run)
+	}
+
+	emails := env.alerts(t, models.NotificationChannelEmail)
+	if len(emails) != 2 || emails[0].Recipient != env.user.Email {
+		t.Errorf("expected two emails to %s, got %+v", env.user.Email, emails)
+	}
+	if inApp := env.alerts(t, models.NotificationChannelInApp); len(inApp) != 0 {
+		t.Errorf("expected alerts only on the rule's channel, got %+v", inApp)
+	}
+}
+
+func TestBalanceAlertService_EvaluateRules_FrequentOnlyAndUnreadableBalances(t *testing.T) {
+	env := newBalanceAlertTestEnv(t)
+	daily := env.createRule(t, BalanceAlertRuleUpdate{Threshold: 50000, Direction: models.BalanceAlertDirectionBelow, Channel: models.NotificationChannelInApp})
+	env.createRule(t, BalanceAlertRuleUpdate{Threshold: 60000, Direction: models.BalanceAlertDirectionBelow, Channel: models.NotificationChannelInApp, Frequent: true})
+	unreadable := testfactory.NWExternalAccount(t, env.db, testfactory.WithOwner(env.user.ID), testfactory.WithAccountNumber("4444444444", "021000021"))
+	if _, err := env.svc.CreateRule(context.Background(), env.user.ID, CreateBalanceAlertRuleRequest{
+		ExternalAccountID:      unreadable.ID,
+		BalanceAlertRuleUpdate: BalanceAlertRuleUpdate{Threshold: 10, Direction: models.BalanceAlertDirectionBelow, Channel: models.NotificationChannelInApp, Frequent: true},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	env.setBalance(env.account.AccountNumber, 40000)
+	run, err := env.svc.EvaluateRules(context.Background(), true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if run.Evaluated != 1 || run.Alerted != 1 || run.Failed != 1 {
+		t.Fatalf("expected only the frequent rules checked, one failing, got %+v", run)
+	}
+	stored, _ := env.svc.GetRule(context.Background(), env.user.ID, daily.ID)
+	if stored.LastEvaluatedAt != nil {
+		t.Errorf("expected the daily rule left for the daily run, got %+v", stored)
+	}
+}
+
+func TestBalanceAlertService_RuleOwnership(t *testing.T) {
+	env := newBalanceAlertTestEnv(t)
+	ctx := context.Background()
+	rule := env.createRule(t, BalanceAlertRuleUpdate{Threshold: 50000, Direction: models.BalanceAlertDirectionBelow, Channel: models.NotificationChannelInApp})
+	other := uuid.New()
+
+	if _, err := env.svc.CreateRule(ctx, other, CreateBalanceAlertRuleRequest{
+		ExternalAccountID:      env.account.ID,
+		BalanceAlertRuleUpdate: BalanceAlertRuleUpdate{Threshold: 1, Direction: models.BalanceAlertDirectionBelow, Channel: models.NotificationChannelInApp},
+	}); !errors.Is(err, ErrExternalAccountNotFound) {
+		t.Errorf("expected another user's account to be not found, got %v", err)
+	}
+	if _, err := env.svc.GetRule(ctx, other, rule.ID); !errors.Is(err, ErrBalanceAlertRuleNotFound) {
+		t.Errorf("expected another user's rule to be not found, got %v", err)
+	}
+	if _, err := env.svc.UpdateRule(ctx, other, rule.ID, BalanceAlertRuleUpdate{Threshold: 1, Direction: models.BalanceAlertDirectionBelow, Channel: models.NotificationChannelInApp}); !errors.Is(err, ErrBalanceAlertRuleNotFound) {
+		t.Errorf("expected another user's rule not to be updated, got %v", err)
+	}
+	if err := env.svc.DeleteRule(ctx, other, rule.ID); !errors.Is(err, ErrBalanceAlertRuleNotFound) {
+		t.Errorf("expected another user's rule not to be deleted, got %v", err)
+	}
+	if rules, total, _ := env.svc.ListRules(ctx, other, 0, 20); total != 0 || len(rules) != 0 {
+		t.Errorf("expected no rules for another user, got %d", total)
+	}
+
+	updated, err := env.svc.UpdateRule(ctx, env.user.ID, rule.ID, BalanceAlertRuleUpdate{Threshold: 75000.129, Direction: models.BalanceAlertDirectionAbove, Channel: models.NotificationChannelEmail, Frequent: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Threshold.StringFixed(2) != "75000.13" || updated.Direction != models.BalanceAlertDirectionAbove || !updated.Frequent {
+		t.Errorf("expected the rule updated, got %+v", updated)
+	}
+	if _, err := env.svc.UpdateRule(ctx, env.user.ID, rule.ID, BalanceAlertRuleUpdate{Threshold: 1, Direction: "SIDEWAYS", Channel: models.NotificationChannelEmail}); !errors.Is(err, ErrInvalidBalanceAlertRule) {
+		t.Errorf("expected an invalid direction to be rejected, got %v", err)
+	}
+	if err := env.svc.DeleteRule(ctx, env.user.ID, rule.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := env.svc.GetRule(ctx, env.user.ID, rule.ID); !errors.Is(err, ErrBalanceAlertRuleNotFound) {
+		t.Errorf("expected the deleted rule to be gone, got %v", err)
+	}
+}
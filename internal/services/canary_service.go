@@ -0,0 +1,301 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/array/banking-api/internal/config"
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// canaryAmount is the amount of every canary transfer
+const canaryAmount = 0.01
+
+// DefaultCanaryPollInterval is how often a canary run checks its transfer and the regulator delivery
+const DefaultCanaryPollInterval = 5 * time.Second
+
+var (
+	ErrCanaryDisabled          = errors.New("canary is disabled")
+	ErrCanaryProductionRefused = errors.New("canary refuses to run in production unless CANARY_ALLOW_PRODUCTION is set")
+	ErrCanaryRunning           = errors.New("a canary run is already in progress")
+)
+
+// CanaryHealth summarizes recent canary runs for the deep health check. The canary is unhealthy
+// only once FailureThreshold runs in a row have failed, so one flaky run does not fail readiness.
+type CanaryHealth struct {
+	Enabled             bool              `json:"enabled"`
+	Healthy             bool              `json:"healthy"`
+	ConsecutiveFailures int               `json:"consecutive_failures"`
+	FailureThreshold    int               `json:"failure_threshold"`
+	LatestRun           *models.CanaryRun `json:"latest_run,omitempty"`
+}
+
+// CanaryService sends a tiny transfer between two designated sandbox accounts, polls it to
+// completion and, when configured to notify the regulator, waits for the regulator to receive its
+// webhook, recording the outcome and the end-to-end duration of each run in canary_runs. The
+// transfer has no user and goes through the same state manager as any other. Unless
+// NotifyRegulator is set it is flagged as an internal test transfer, so no regulator
+// notification is created for it.
+type CanaryService struct {
+	client       *northwind.Client
+	transfers    *NorthwindTransferService
+	transferRepo repositories.NorthwindTransferRepositoryInterface
+	states       *TransferStateManager
+	notifRepo    repositories.RegulatorNotificationRepositoryInterface
+	runRepo      repositories.CanaryRunRepositoryInterface
+	cfg          config.CanaryConfig
+	production   bool
+	pollInterval time.Duration
+	running      atomic.Bool
+	logger       *slog.Logger
+}
+
+// NewCanaryService creates a canary configured by cfg. production is whether the environment is
+// production, where the canary only runs when cfg.AllowProduction is set.
+func NewCanaryService(
+	client *northwind.Client,
+	transfers *NorthwindTransferService,
+	transferRepo repositories.NorthwindTransferRepositoryInterface,
+	states *TransferStateManager,
+	notifRepo repositories.RegulatorNotificationRepositoryInterface,
+	runRepo repositories.CanaryRunRepositoryInterface,
+	cfg config.CanaryConfig,
+	production bool,
+	logger *slog.Logger,
+) *CanaryService {
+	if cfg.FailureThreshold < 1 {
+		cfg.FailureThreshold = 1
+	}
+	return &CanaryService{
+		client:       client,
+		transfers:    transfers,
+		transferRepo: transferRepo,
+		states:       states,
+		notifRepo:    notifRepo,
+		runRepo:      runRepo,
+		cfg:          cfg,
+		production:   production,
+		pollInterval: DefaultCanaryPollInterval,
+		logger:       logger,
+	}
+}
+
+// SetPollInterval sets how often a run checks its transfer and the regulator delivery
+func (s *CanaryService) SetPollInterval(interval time.Duration) {
+	if interval > 0 {
+		s.pollInterval = interval
+	}
+}
+
+// Enabled reports whether the canary is configured to run in this environment
+func (s *CanaryService) Enabled() bool {
+	return s.allowed() == nil
+}
+
+func (s *CanaryService) allowed() error {
+	if !s.cfg.Enabled {
+		return ErrCanaryDisabled
+	}
+	if s.production && !s.cfg.AllowProduction {
+		return ErrCanaryProductionRefused
+	}
+	return nil
+}
+
+// Trigger starts a run in the background unless one is still in progress. It is the canary's
+// scheduler job, so a run never holds up polling.
+func (s *CanaryService) Trigger(ctx context.Context) error {
+	if err := s.allowed(); err != nil {
+		return err
+	}
+	go func() {
+		if _, err := s.RunOnce(ctx); err != nil && !errors.Is(err, ErrCanaryRunning) {
+			s.logger.Error("Canary run could not start", "error", err)
+		}
+	}()
+	return nil
+}
+
+// RunOnce performs one canary run, bounded by the configured timeout, and returns its recorded
+// result. A failed run is not an error; errors are for runs that could not start or be recorded.
+func (s *CanaryService) RunOnce(ctx context.Context) (*models.CanaryRun, error) {
+	if err := s.allowed(); err != nil {
+		return nil, err
+	}
+	if !s.running.CompareAndSwap(false, true) {
+		return nil, ErrCanaryRunning
+	}
+	defer s.running.Store(false)
+
+	run := &models.CanaryRun{Status: models.CanaryStatusRunning, StartedAt: time.Now()}
+	if err := s.runRepo.Create(ctx, run); err != nil {
+		return nil, err
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+	stage, err := s.execute(runCtx, run)
+
+	finished := time.Now()
+	run.FinishedAt = &finished
+	if err != nil {
+		run.Status = models.CanaryStatusFailed
+		run.FailedStage = &stage
+		message := err.Error()
+		run.Error = &message
+		s.logger.Error("Canary run failed", "run_id", run.ID, "stage", stage, "transfer_id", run.TransferID, "error", err)
+	} else {
+		run.Status = models.CanaryStatusSucceeded
+		duration := finished.Sub(run.StartedAt).Milliseconds()
+		run.DurationMs = &duration
+		s.logger.Info("Canary run succeeded", "run_id", run.ID, "transfer_id", run.TransferID, "duration_ms", duration)
+	}
+	if err := s.runRepo.Update(context.WithoutCancel(ctx), run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// execute initiates the canary transfer, polls it to completion and, when the canary notifies
+// the regulator, waits for the regulator delivery, returning the stage that failed
+func (s *CanaryService) execute(ctx context.Context, run *models.CanaryRun) (string, error) {
+	req := s.transferRequest(run.StartedAt)
+	nwResp, err := s.client.InitiateTransfer(ctx, toNWTransferRequest(req))
+	if err != nil {
+		return models.CanaryStageInitiate, fmt.Errorf("northwind rejected the canary transfer: %w", err)
+	}
+	transfer := s.transfers.newLocalTransfer(uuid.Nil, req, nwResp)
+	transfer.UserID = nil
+	transfer.InternalTest = !s.cfg.NotifyRegulator
+	if err := s.transferRepo.Create(ctx, transfer); err != nil {
+		return models.CanaryStageInitiate, err
+	}
+	s.transfers.auditTransferCreated(transfer)
+	run.TransferID = &transfer.ID
+
+	status, err := s.awaitTerminal(ctx, transfer)
+	if err != nil {
+		return models.CanaryStageComplete, err
+	}
+	if status != models.NWTransferStatusCompleted {
+		return models.CanaryStageComplete, fmt.Errorf("canary transfer ended %s", status)
+	}
+
+	if transfer.InternalTest {
+		return "", nil
+	}
+	if err := s.awaitWebhook(ctx, transfer.ID, status); err != nil {
+		return models.CanaryStageWebhook, err
+	}
+	return "", nil
+}
+
+// awaitTerminal polls NorthWind for the transfer's status and applies it until the transfer
+// reaches a terminal status
+func (s *CanaryService) awaitTerminal(ctx context.Context, transfer *models.NorthwindTransfer) (string, error) {
+	upstreamID, err := upstreamTransferID(transfer)
+	if err != nil {
+		return "", err
+	}
+	var lastErr error
+	for {
+		remote, err := s.client.GetTransferStatus(ctx, upstreamID)
+		if err == nil {
+			var result *TransferTransition
+			result, err = s.states.Apply(ctx, transfer.ID, models.NWTransferEventSourceCanary, remote)
+			if err == nil && result.Transfer.IsTerminal() {
+				return result.Transfer.Status, nil
+			}
+		}
+		if err != nil {
+			lastErr = err
+		}
+		if err := s.wait(ctx); err != nil {
+			if lastErr != nil {
+				return "", fmt.Errorf("canary transfer did not complete: %w", lastErr)
+			}
+			return "", fmt.Errorf("canary transfer did not complete: %w", err)
+		}
+	}
+}
+
+// awaitWebhook waits until the regulator has acknowledged the transfer's notification
+func (s *CanaryService) awaitWebhook(ctx context.Context, transferID uuid.UUID, status string) error {
+	for {
+		notification, err := s.notifRepo.GetByTransferAndStatus(ctx, transferID, status)
+		switch {
+		case err == nil && notification.Delivered:
+			return nil
+		case err != nil && !errors.Is(err, repositories.ErrRegulatorNotificationNotFound):
+			return err
+		}
+		if err := s.wait(ctx); err != nil {
+			if notification != nil && notification.LastError != nil {
+				return fmt.Errorf("regulator did not receive the webhook after %d attempts: %s", notification.AttemptCount, *notification.LastError)
+			}
+			return fmt.Errorf("regulator did not receive the webhook: %w", err)
+		}
+	}
+}
+
+func (s *CanaryService) wait(ctx context.Context) error {
+	timer := time.NewTimer(s.pollInterval)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (s *CanaryService) transferRequest(startedAt time.Time) CreateTransferRequest {
+	return CreateTransferRequest{
+		Amount:             canaryAmount,
+		Currency:           "USD",
+		Description:        "Synthetic canary transfer",
+		Direction:          models.NWTransferDirectionOutbound,
+		TransferType:       s.cfg.TransferType,
+		ReferenceNumber:    "CANARY-" + startedAt.UTC().Format("20060102T150405.000"),
+		SourceAccount:      canaryAccountDetails(s.cfg.Source),
+		DestinationAccount: canaryAccountDetails(s.cfg.Destination),
+	}
+}
+
+func canaryAccountDetails(account config.CanaryAccount) CreateTransferAccountDetails {
+	return CreateTransferAccountDetails{
+		AccountHolderName: account.HolderName,
+		AccountNumber:     account.AccountNumber,
+		RoutingNumber:     account.RoutingNumber,
+	}
+}
+
+// Health summarizes the most recent finished runs
+func (s *CanaryService) Health(ctx context.Context) (*CanaryHealth, error) {
+	health := &CanaryHealth{Enabled: s.Enabled(), Healthy: true, FailureThreshold: s.cfg.FailureThreshold}
+	if !health.Enabled {
+		return health, nil
+	}
+	runs, err := s.runRepo.ListFinished(ctx, s.cfg.FailureThreshold)
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) > 0 {
+		health.LatestRun = &runs[0]
+	}
+	for _, run := range runs {
+		if run.Status != models.CanaryStatusFailed {
+			break
+		}
+		health.ConsecutiveFailures++
+	}
+	health.Healthy = health.ConsecutiveFailures < s.cfg.FailureThreshold
+	return health, nil
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/config"
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/google/uuid"
+)
+
+// canaryFake configures the fake NorthWind and regulator a canary test runs against
+type canaryFake struct {
+	initiateStatus  int
+	regulatorStatus int
+}
+
+func testCanaryConfig() config.CanaryConfig {
+	return config.CanaryConfig{
+		Enabled:          true,
+		Timeout:          500 * time.Millisecond,
+		FailureThreshold: 2,
+		TransferType:     models.NWTransferTypeACH,
+		Source:           config.CanaryAccount{HolderName: "Canary Source", AccountNumber: "1111111111", RoutingNumber: "021000021"},
+		Destination:      config.CanaryAccount{HolderName: "Canary Destination", AccountNumber: "2222222222", RoutingNumber: "021000021"},
+	}
+}
+
+// newCanaryTestService returns a canary whose fake NorthWind completes every transfer on its
+// first status check, reporting its outcomes to a fake regulator
+func newCanaryTestService(t *testing.T, fake canaryFake, cfg config.CanaryConfig, production bool) (*CanaryService, repositories.CanaryRunRepositoryInterface) {
+	t.Helper()
+	northwindID := uuid.New()
+	nwServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/external/transfers/initiate":
+			if fake.initiateStatus != http.StatusOK {
+				w.WriteHeader(fake.initiateStatus)
+				_ = json.NewEncoder(w).Encode(northwind.APIErrorResponse{Message: "upstream unavailable"})
+				return
+			}
+			var req northwind.TransferRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			_ = json.NewEncoder(w).Encode(northwind.TransferResponse{TransferID: northwindID.String(), Status: "PENDING", Amount: req.Amount, ReferenceNumber: req.ReferenceNumber})
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/external/transfers/"):
+			_ = json.NewEncoder(w).Encode(northwind.TransferResponse{TransferID: northwindID.String(), Status: "COMPLETED"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(nwServer.Close)
+	regulatorServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(fake.regulatorStatus)
+	}))
+	t.Cleanup(regulatorServer.Close)
+
+	db := testfactory.NewDB(t)
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	client := northwind.NewClient(nwServer.URL, "test-key")
+	transferRepo := repositories.NewNorthwindTransferRepository(db)
+	notifRepo := repositories.NewRegulatorNotificationRepository(db)
+	regulatorSvc := NewRegulatorService(regulatorServer.URL, 60, 120, notifRepo,
+		repositories.NewRegulatorNotificationAttemptRepository(db), slog.Default(), regulatorServer.Client())
+	regulatorSvc.StartDeliveryWorkers(1, 10)
+	t.Cleanup(func() { regulatorSvc.Shutdown(context.Background()) })
+
+	runRepo := repositories.NewCanaryRunRepository(db)
+	canary := NewCanaryService(client,
+		NewNorthwindTransferService(client, transferRepo, nil, nil, slog.Default()),
+		transferRepo,
+		NewTransferStateManager(transferRepo, regulatorSvc, slog.Default()),
+		notifRepo, runRepo, cfg, production, slog.Default())
+	canary.SetPollInterval(10 * time.Millisecond)
+	return canary, runRepo
+}
+
+func TestCanaryService_RunOnce(t *testing.T) {
+	tests := []struct {
+		name         string
+		fake         canaryFake
+		notify       bool
+		wantStatus   string
+		wantStage    string
+		wantTransfer bool
+	}{
+		{"success", canaryFake{initiateStatus: http.StatusOK, regulatorStatus: http.StatusOK}, true, models.CanaryStatusSucceeded, "", true},
+		{"upstream failure", canaryFake{initiateStatus: http.StatusBadRequest, regulatorStatus: http.StatusOK}, true, models.CanaryStatusFailed, models.CanaryStageInitiate, false},
+		{"webhook miss", canaryFake{initiateStatus: http.StatusOK, regulatorStatus: http.StatusInternalServerError}, true, models.CanaryStatusFailed, models.CanaryStageWebhook, true},
+		{"internal test transfer skips the regulator", canaryFake{initiateStatus: http.StatusOK, regulatorStatus: http.StatusInternalServerError}, false, models.CanaryStatusSucceeded, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testCanaryConfig()
+			cfg.NotifyRegulator = tt.notify
+			canary, runRepo := newCanaryTestService(t, tt.fake, cfg, false)
+
+			run, err := canary.RunOnce(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if run.Status != tt.wantStatus {
+				t.Fatalf("expected status %s, got %s (error %v)", tt.wantStatus, run.Status, run.Error)
+			}
+			if tt.wantStage != "" && (run.FailedStage == nil || *run.FailedStage != tt.wantStage) {
+				t.Errorf("expected failure at %s, got %v", tt.wantStage, run.FailedStage)
+			}
+			if (run.TransferID != nil) != tt.wantTransfer {
+				t.Errorf("expected transfer recorded=%v, got %v", tt.wantTransfer, run.TransferID)
+			}
+			if (run.DurationMs != nil) != (tt.wantStatus == models.CanaryStatusSucceeded) {
+				t.Errorf("expected a duration only for a successful run, got %v", run.DurationMs)
+			}
+
+			stored, err := runRepo.ListFinished(context.Background(), 10)
+			if err != nil || len(stored) != 1 || stored[0].ID != run.ID || stored[0].Status != tt.wantStatus {
+				t.Errorf("expected the run to be recorded, got %+v (%v)", stored, err)
+			}
+			if run.TransferID != nil {
+				transfer, err := canary.transferRepo.GetByID(context.Background(), *run.TransferID)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if transfer.InternalTest == tt.notify {
+					t.Errorf("expected internal_test=%v on the canary transfer", !tt.notify)
+				}
+				_, err = canary.notifRepo.GetByTransferAndStatus(context.Background(), transfer.ID, models.NWTransferStatusCompleted)
+				if notified := err == nil; notified != tt.notify {
+					t.Errorf("expected a regulator notification=%v, got %v", tt.notify, err)
+				}
+			}
+		})
+	}
+}
+
+func TestCanaryService_RefusesProductionUnlessAllowed(t *testing.T) {
+	fake := canaryFake{initiateStatus: http.StatusOK, regulatorStatus: http.StatusOK}
+	canary, _ := newCanaryTestService(t, fake, testCanaryConfig(), true)
+	if _, err := canary.RunOnce(context.Background()); !errors.Is(err, ErrCanaryProductionRefused) {
+		t.Errorf("expected ErrCanaryProductionRefused, got %v", err)
+	}
+	if canary.Enabled() {
+		t.Error("expected the canary to report itself disabled in production")
+	}
+
+	cfg := testCanaryConfig()
+	cfg.AllowProduction = true
+	allowed, _ := newCanaryTestService(t, fake, cfg, true)
+	if !allowed.Enabled() {
+		t.Error("expected CANARY_ALLOW_PRODUCTION to enable the canary in production")
+	}
+
+	disabled, _ := newCanaryTestService(t, fake, config.CanaryConfig{}, false)
+	if _, err := disabled.RunOnce(context.Background()); !errors.Is(err, ErrCanaryDisabled) {
+		t.Errorf("expected ErrCanaryDisabled by default, got %v", err)
+	}
+}
+
+func TestCanaryService_HealthFailsAfterConsecutiveFailures(t *testing.T) {
+	canary, runRepo := newCanaryTestService(t, canaryFake{initiateStatus: http.StatusBadRequest, regulatorStatus: http.StatusOK}, testCanaryConfig(), false)
+	ctx := context.Background()
+	record := func(status string, startedAt time.Time) {
+		finished := startedAt.Add(time.Second)
+		if err := runRepo.Create(ctx, &models.CanaryRun{Status: status, StartedAt: startedAt, FinishedAt: &finished}); err != nil {
+			t.Fatalf("failed to record run: %v", err)
+		}
+	}
+	now := time.Now()
+	record(models.CanaryStatusSucceeded, now.Add(-3*time.Hour))
+	record(models.CanaryStatusFailed, now.Add(-2*time.Hour))
+
+	health, err := canary.Health(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !health.Healthy || health.ConsecutiveFailures != 1 {
+		t.Errorf("expected one failure below the threshold to stay healthy, got %+v", health)
+	}
+
+	record(models.CanaryStatusFailed, now.Add(-time.Hour))
+	health, _ = canary.Health(ctx)
+	if health.Healthy || health.ConsecutiveFailures != 2 || health.LatestRun == nil || health.LatestRun.Status != models.CanaryStatusFailed {
+		t.Errorf("expected two consecutive failures to be unhealthy, got %+v", health)
+	}
+}
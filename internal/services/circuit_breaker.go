@@ -2,6 +2,7 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"github.com/array/banking-api/internal/models"
 	"sync"
 	"time"
@@ -11,6 +12,20 @@ var (
 	ErrCircuitBreakerOpen = errors.New("circuit breaker is open")
 )
 
+// CircuitBreakerOpenError is returned for work refused by an open circuit breaker. RetryAfter is
+// how long until the breaker goes half-open. It wraps ErrCircuitBreakerOpen.
+type CircuitBreakerOpenError struct {
+	RetryAfter time.Duration
+}
+
+func (e *CircuitBreakerOpenError) Error() string {
+	return fmt.Sprintf("%s: half-open in %s", ErrCircuitBreakerOpen, e.RetryAfter)
+}
+
+func (e *CircuitBreakerOpenError) Unwrap() error {
+	return ErrCircuitBreakerOpen
+}
+
 type CircuitBreakerConfig struct {
 	MaxFailures     int
 	ResetTimeout    time.Duration
@@ -107,6 +122,16 @@ func (cb *CircuitBreaker) transitionToOpen() {
 	cb.halfOpenSuccesses = 0
 }
 
+func (cb *CircuitBreaker) RetryAfter() time.Duration {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+
+	if cb.state != StateOpen {
+		return 0
+	}
+	return max(cb.config.ResetTimeout-time.Since(cb.lastFailureTime), 0)
+}
+
 func (cb *CircuitBreaker) GetState() models.CircuitBreakerState {
 	cb.mu.RLock()
 	defer cb.mu.RUnlock()
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker_RetryAfter(t *testing.T) {
+	cb := NewCircuitBreaker(CircuitBreakerConfig{MaxFailures: 2, ResetTimeout: time.Minute, HalfOpenMaxSucc: 1})
+
+	cb.RecordFailure()
+	if got := cb.RetryAfter(); got != 0 {
+		t.Errorf("expected no wait while closed, got %v", got)
+	}
+
+	cb.RecordFailure()
+	if !cb.IsOpen() {
+		t.Fatal("expected the breaker open after MaxFailures")
+	}
+	if got := cb.RetryAfter(); got <= 55*time.Second || got > time.Minute {
+		t.Errorf("expected close to the reset timeout until half-open, got %v", got)
+	}
+
+	cb.(*CircuitBreaker).lastFailureTime = time.Now().Add(-2 * time.Minute)
+	if got := cb.RetryAfter(); got != 0 {
+		t.Errorf("expected no wait once the reset timeout has passed, got %v", got)
+	}
+}
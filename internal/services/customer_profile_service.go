@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"crypto/rand"
 	"errors"
 	"fmt"
@@ -43,12 +44,12 @@ var (
 )
 
 // GetCustomerProfile retrieves a customer profile by ID
-func (s *CustomerProfileService) GetCustomerProfile(customerID uuid.UUID) (*models.User, error) {
+func (s *CustomerProfileService) GetCustomerProfile(ctx context.Context, customerID uuid.UUID) (*models.User, error) {
 	if customerID == uuid.Nil {
 		return nil, ErrInvalidCustomerID
 	}
 
-	user, err := s.userRepo.GetByIDActive(customerID)
+	user, err := s.userRepo.GetByIDActive(ctx, customerID)
 	if err != nil {
 		if errors.Is(err, repositories.ErrUserNotFound) {
 			return nil, ErrCustomerNotFound
@@ -60,7 +61,7 @@ func (s *CustomerProfileService) GetCustomerProfile(customerID uuid.UUID) (*mode
 }
 
 // CreateCustomer creates a new customer with a temporary password
-func (s *CustomerProfileService) CreateCustomer(email, firstName, lastName string, role string) (*models.User, string, error) {
+func (s *CustomerProfileService) CreateCustomer(ctx context.Context, email, firstName, lastName string, role string) (*models.User, string, error) {
 	if email == "" {
 		return nil, "", ErrInvalidEmail
 	}
@@ -69,7 +70,7 @@ func (s *CustomerProfileService) CreateCustomer(email, firstName, lastName strin
 		return nil, "", ErrInvalidRole
 	}
 
-	existingUser, err := s.userRepo.GetByEmail(email)
+	existingUser, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil && !errors.Is(err, repositories.ErrUserNotFound) {
 		return nil, "", fmt.Errorf("failed to check email uniqueness: %w", err)
 	}
@@ -95,7 +96,7 @@ func (s *CustomerProfileService) CreateCustomer(email, firstName, lastName strin
 		PasswordHash: string(hashedPassword),
 	}
 
-	if err := s.userRepo.Create(user); err != nil {
+	if err := s.userRepo.Create(ctx, user); err != nil {
 		if errors.Is(err, repositories.ErrEmailAlreadyExists) {
 			return nil, "", ErrEmailAlreadyExists
 		}
@@ -106,7 +107,7 @@ func (s *CustomerProfileService) CreateCustomer(email, firstName, lastName strin
 }
 
 // UpdateCustomerProfile updates customer profile fields
-func (s *CustomerProfileService) UpdateCustomerProfile(customerID uuid.UUID, updates map[string]interface{}) error {
+func (s *CustomerProfileService) UpdateCustomerProfile(ctx context.Context, customerID uuid.UUID, updates map[string]interface{}) error {
 	if customerID == uuid.Nil {
 		return ErrInvalidCustomerID
 	}
@@ -115,7 +116,7 @@ func (s *CustomerProfileService) UpdateCustomerProfile(customerID uuid.UUID, upd
 		return errors.New("no updates provided")
 	}
 
-	_, err := s.userRepo.GetByIDActive(customerID)
+	_, err := s.userRepo.GetByIDActive(ctx, customerID)
 	if err != nil {
 		if errors.Is(err, repositories.ErrUserNotFound) {
 			return ErrCustomerNotFound
@@ -125,7 +126,7 @@ func (s *CustomerProfileService) UpdateCustomerProfile(customerID uuid.UUID, upd
 
 	preventUpdatingSensitiveAndNonApplicableFields(updates)
 
-	if err := s.userRepo.UpdateFields(customerID, updates); err != nil {
+	if err := s.userRepo.UpdateFields(ctx, customerID, updates); err != nil {
 		if errors.Is(err, repositories.ErrUserNotFound) {
 			return ErrCustomerNotFound
 		}
@@ -136,7 +137,7 @@ func (s *CustomerProfileService) UpdateCustomerProfile(customerID uuid.UUID, upd
 }
 
 // UpdateCustomerEmail updates a customer's email address with uniqueness validation
-func (s *CustomerProfileService) UpdateCustomerEmail(customerID uuid.UUID, newEmail string) error {
+func (s *CustomerProfileService) UpdateCustomerEmail(ctx context.Context, customerID uuid.UUID, newEmail string) error {
 	if customerID == uuid.Nil {
 		return ErrInvalidCustomerID
 	}
@@ -145,7 +146,7 @@ func (s *CustomerProfileService) UpdateCustomerEmail(customerID uuid.UUID, newEm
 		return ErrInvalidEmail
 	}
 
-	existingUser, err := s.userRepo.GetByEmailExcluding(newEmail, customerID)
+	existingUser, err := s.userRepo.GetByEmailExcluding(ctx, newEmail, customerID)
 	if err != nil && !errors.Is(err, repositories.ErrUserNotFound) {
 		return fmt.Errorf("failed to check email uniqueness: %w", err)
 	}
@@ -153,7 +154,7 @@ func (s *CustomerProfileService) UpdateCustomerEmail(customerID uuid.UUID, newEm
 		return ErrEmailAlreadyExists
 	}
 
-	_, err = s.userRepo.GetByIDActive(customerID)
+	_, err = s.userRepo.GetByIDActive(ctx, customerID)
 	if err != nil {
 		if errors.Is(err, repositories.ErrUserNotFound) {
 			return ErrCustomerNotFound
@@ -161,7 +162,7 @@ func (s *CustomerProfileService) UpdateCustomerEmail(customerID uuid.UUID, newEm
 		return fmt.Errorf("failed to find customer: %w", err)
 	}
 
-	if err := s.userRepo.UpdateEmail(customerID, newEmail); err != nil {
+	if err := s.userRepo.UpdateEmail(ctx, customerID, newEmail); err != nil {
 		if errors.Is(err, repositories.ErrUserNotFound) {
 			return ErrCustomerNotFound
 		}
@@ -175,12 +176,12 @@ func (s *CustomerProfileService) UpdateCustomerEmail(customerID uuid.UUID, newEm
 }
 
 // DeleteCustomer soft deletes a customer with balance validation and cascade account deactivation
-func (s *CustomerProfileService) DeleteCustomer(customerID uuid.UUID, reason string) error {
+func (s *CustomerProfileService) DeleteCustomer(ctx context.Context, customerID uuid.UUID, reason string) error {
 	if customerID == uuid.Nil {
 		return ErrInvalidCustomerID
 	}
 
-	_, err := s.userRepo.GetByIDActive(customerID)
+	_, err := s.userRepo.GetByIDActive(ctx, customerID)
 	if err != nil {
 		if errors.Is(err, repositories.ErrUserNotFound) {
 			return ErrCustomerNotFound
@@ -196,7 +197,7 @@ func (s *CustomerProfileService) DeleteCustomer(customerID uuid.UUID, reason str
 		return ErrCustomerHasBalance
 	}
 
-	if err := s.userRepo.Delete(customerID); err != nil {
+	if err := s.userRepo.Delete(ctx, customerID); err != nil {
 		if errors.Is(err, repositories.ErrUserNotFound) {
 			return ErrCustomerNotFound
 		}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"testing"
 
 	"github.com/array/banking-api/internal/models"
@@ -86,9 +87,9 @@ func (s *CustomerProfileServiceTestSuite) TestGetCustomerProfile_ValidCustomerID
 	}
 
 	// Setup mock expectations
-	s.userRepo.EXPECT().GetByIDActive(user.ID).Return(user, nil).Times(1)
+	s.userRepo.EXPECT().GetByIDActive(gomock.Any(), user.ID).Return(user, nil).Times(1)
 
-	result, err := s.service.GetCustomerProfile(user.ID)
+	result, err := s.service.GetCustomerProfile(context.Background(), user.ID)
 	s.Require().NoError(err)
 	s.NotNil(result)
 	s.Equal(user.ID, result.ID)
@@ -96,7 +97,7 @@ func (s *CustomerProfileServiceTestSuite) TestGetCustomerProfile_ValidCustomerID
 }
 
 func (s *CustomerProfileServiceTestSuite) TestGetCustomerProfile_NilCustomerID() {
-	_, err := s.service.GetCustomerProfile(uuid.Nil)
+	_, err := s.service.GetCustomerProfile(context.Background(), uuid.Nil)
 	s.Error(err)
 	s.ErrorIs(err, ErrInvalidCustomerID)
 }
@@ -105,23 +106,23 @@ func (s *CustomerProfileServiceTestSuite) TestGetCustomerProfile_NonExistentCust
 	customerID := uuid.New()
 
 	// Setup mock expectations
-	s.userRepo.EXPECT().GetByIDActive(customerID).Return(nil, repositories.ErrUserNotFound).Times(1)
+	s.userRepo.EXPECT().GetByIDActive(gomock.Any(), customerID).Return(nil, repositories.ErrUserNotFound).Times(1)
 
-	_, err := s.service.GetCustomerProfile(customerID)
+	_, err := s.service.GetCustomerProfile(context.Background(), customerID)
 	s.Error(err)
 	s.ErrorIs(err, ErrCustomerNotFound)
 }
 
 func (s *CustomerProfileServiceTestSuite) TestCreateCustomer_ValidCustomerCreation() {
 	// Setup mock expectations
-	s.userRepo.EXPECT().GetByEmail("new@example.com").Return(nil, repositories.ErrUserNotFound).Times(1)
-	s.userRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(user *models.User) error {
+	s.userRepo.EXPECT().GetByEmail(gomock.Any(), "new@example.com").Return(nil, repositories.ErrUserNotFound).Times(1)
+	s.userRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, user *models.User) error {
 		// Simulate ID generation
 		user.ID = uuid.New()
 		return nil
 	}).Times(1)
 
-	user, tempPassword, err := s.service.CreateCustomer("new@example.com", "Jane", "Smith", models.RoleCustomer)
+	user, tempPassword, err := s.service.CreateCustomer(context.Background(), "new@example.com", "Jane", "Smith", models.RoleCustomer)
 
 	s.Require().NoError(err)
 	s.NotNil(user)
@@ -142,14 +143,14 @@ func (s *CustomerProfileServiceTestSuite) TestCreateCustomer_ValidCustomerCreati
 
 func (s *CustomerProfileServiceTestSuite) TestCreateCustomer_ValidAdminCreation() {
 	// Setup mock expectations
-	s.userRepo.EXPECT().GetByEmail("admin@example.com").Return(nil, repositories.ErrUserNotFound).Times(1)
-	s.userRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(user *models.User) error {
+	s.userRepo.EXPECT().GetByEmail(gomock.Any(), "admin@example.com").Return(nil, repositories.ErrUserNotFound).Times(1)
+	s.userRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, user *models.User) error {
 		// Simulate ID generation
 		user.ID = uuid.New()
 		return nil
 	}).Times(1)
 
-	user, tempPassword, err := s.service.CreateCustomer("admin@example.com", "Admin", "User", models.RoleAdmin)
+	user, tempPassword, err := s.service.CreateCustomer(context.Background(), "admin@example.com", "Admin", "User", models.RoleAdmin)
 
 	s.Require().NoError(err)
 	s.NotNil(user)
@@ -169,14 +170,14 @@ func (s *CustomerProfileServiceTestSuite) TestCreateCustomer_ValidAdminCreation(
 }
 
 func (s *CustomerProfileServiceTestSuite) TestCreateCustomer_EmptyEmail() {
-	user, _, err := s.service.CreateCustomer("", "John", "Doe", models.RoleCustomer)
+	user, _, err := s.service.CreateCustomer(context.Background(), "", "John", "Doe", models.RoleCustomer)
 	s.Error(err)
 	s.ErrorIs(err, ErrInvalidEmail)
 	s.Nil(user)
 }
 
 func (s *CustomerProfileServiceTestSuite) TestCreateCustomer_InvalidRole() {
-	user, _, err := s.service.CreateCustomer("invalid@example.com", "John", "Doe", "superuser")
+	user, _, err := s.service.CreateCustomer(context.Background(), "invalid@example.com", "John", "Doe", "superuser")
 	s.Error(err)
 	s.ErrorIs(err, ErrInvalidRole)
 	s.Nil(user)
@@ -189,22 +190,22 @@ func (s *CustomerProfileServiceTestSuite) TestCreateCustomer_DuplicateEmail() {
 	}
 
 	// Setup mock expectations - first call succeeds
-	s.userRepo.EXPECT().GetByEmail("duplicate@example.com").Return(nil, repositories.ErrUserNotFound).Times(1)
-	s.userRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(user *models.User) error {
+	s.userRepo.EXPECT().GetByEmail(gomock.Any(), "duplicate@example.com").Return(nil, repositories.ErrUserNotFound).Times(1)
+	s.userRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, user *models.User) error {
 		user.ID = uuid.New()
 		return nil
 	}).Times(1)
 
 	// Create first user
-	user1, _, err := s.service.CreateCustomer("duplicate@example.com", "John", "Doe", models.RoleCustomer)
+	user1, _, err := s.service.CreateCustomer(context.Background(), "duplicate@example.com", "John", "Doe", models.RoleCustomer)
 	s.Require().NoError(err)
 	s.Require().NotNil(user1)
 
 	// Setup mock expectations - second call finds existing user
-	s.userRepo.EXPECT().GetByEmail("duplicate@example.com").Return(existingUser, nil).Times(1)
+	s.userRepo.EXPECT().GetByEmail(gomock.Any(), "duplicate@example.com").Return(existingUser, nil).Times(1)
 
 	// Try to create second user with same email
-	user2, _, err := s.service.CreateCustomer("duplicate@example.com", "Jane", "Smith", models.RoleCustomer)
+	user2, _, err := s.service.CreateCustomer(context.Background(), "duplicate@example.com", "Jane", "Smith", models.RoleCustomer)
 	s.Error(err)
 	s.ErrorIs(err, ErrEmailAlreadyExists)
 	s.Nil(user2)
@@ -238,8 +239,8 @@ func (s *CustomerProfileServiceTestSuite) TestUpdateCustomerProfile() {
 			},
 			wantErr: false,
 			setupMocks: func() {
-				s.userRepo.EXPECT().GetByIDActive(user.ID).Return(user, nil).Times(1)
-				s.userRepo.EXPECT().UpdateFields(user.ID, gomock.Any()).Return(nil).Times(1)
+				s.userRepo.EXPECT().GetByIDActive(gomock.Any(), user.ID).Return(user, nil).Times(1)
+				s.userRepo.EXPECT().UpdateFields(gomock.Any(), user.ID, gomock.Any()).Return(nil).Times(1)
 			},
 			validate: func(userID uuid.UUID) {
 				// Validation happens through mock expectations
@@ -264,7 +265,7 @@ func (s *CustomerProfileServiceTestSuite) TestUpdateCustomerProfile() {
 			wantErr: true,
 			errType: ErrCustomerNotFound,
 			setupMocks: func() {
-				s.userRepo.EXPECT().GetByIDActive(gomock.Any()).Return(nil, repositories.ErrUserNotFound).Times(1)
+				s.userRepo.EXPECT().GetByIDActive(gomock.Any(), gomock.Any()).Return(nil, repositories.ErrUserNotFound).Times(1)
 			},
 		},
 		{
@@ -285,8 +286,8 @@ func (s *CustomerProfileServiceTestSuite) TestUpdateCustomerProfile() {
 			},
 			wantErr: false,
 			setupMocks: func() {
-				s.userRepo.EXPECT().GetByIDActive(user.ID).Return(user, nil).Times(1)
-				s.userRepo.EXPECT().UpdateFields(user.ID, gomock.Any()).Return(nil).Times(1)
+				s.userRepo.EXPECT().GetByIDActive(gomock.Any(), user.ID).Return(user, nil).Times(1)
+				s.userRepo.EXPECT().UpdateFields(gomock.Any(), user.ID, gomock.Any()).Return(nil).Times(1)
 			},
 			validate: func(userID uuid.UUID) {
 				// Validation happens through mock expectations - sensitive fields filtered by service
@@ -306,7 +307,7 @@ func (s *CustomerProfileServiceTestSuite) TestUpdateCustomerProfile() {
 
 			tt.setupMocks()
 
-			err := s.service.UpdateCustomerProfile(tt.customerID, tt.updates)
+			err := s.service.UpdateCustomerProfile(context.Background(), tt.customerID, tt.updates)
 
 			if tt.wantErr {
 				s.Error(err)
@@ -334,11 +335,11 @@ func (s *CustomerProfileServiceTestSuite) TestUpdateCustomerEmail_ValidEmailUpda
 	}
 
 	// Setup mock expectations
-	s.userRepo.EXPECT().GetByEmailExcluding("newemail@example.com", user.ID).Return(nil, repositories.ErrUserNotFound).Times(1)
-	s.userRepo.EXPECT().GetByIDActive(user.ID).Return(user, nil).Times(1)
-	s.userRepo.EXPECT().UpdateEmail(user.ID, "newemail@example.com").Return(nil).Times(1)
+	s.userRepo.EXPECT().GetByEmailExcluding(gomock.Any(), "newemail@example.com", user.ID).Return(nil, repositories.ErrUserNotFound).Times(1)
+	s.userRepo.EXPECT().GetByIDActive(gomock.Any(), user.ID).Return(user, nil).Times(1)
+	s.userRepo.EXPECT().UpdateEmail(gomock.Any(), user.ID, "newemail@example.com").Return(nil).Times(1)
 
-	err := s.service.UpdateCustomerEmail(user.ID, "newemail@example.com")
+	err := s.service.UpdateCustomerEmail(context.Background(), user.ID, "newemail@example.com")
 	s.Require().NoError(err)
 }
 
@@ -361,9 +362,9 @@ func (s *CustomerProfileServiceTestSuite) TestUpdateCustomerEmail_DuplicateEmail
 	}
 
 	// Setup mock expectations - email already exists
-	s.userRepo.EXPECT().GetByEmailExcluding(user2.Email, user1.ID).Return(user2, nil).Times(1)
+	s.userRepo.EXPECT().GetByEmailExcluding(gomock.Any(), user2.Email, user1.ID).Return(user2, nil).Times(1)
 
-	err := s.service.UpdateCustomerEmail(user1.ID, user2.Email)
+	err := s.service.UpdateCustomerEmail(context.Background(), user1.ID, user2.Email)
 	s.Error(err)
 	s.ErrorIs(err, ErrEmailAlreadyExists)
 }
@@ -377,13 +378,13 @@ func (s *CustomerProfileServiceTestSuite) TestUpdateCustomerEmail_EmptyEmail() {
 		Role:      models.RoleCustomer,
 	}
 
-	err := s.service.UpdateCustomerEmail(user.ID, "")
+	err := s.service.UpdateCustomerEmail(context.Background(), user.ID, "")
 	s.Error(err)
 	s.ErrorIs(err, ErrInvalidEmail)
 }
 
 func (s *CustomerProfileServiceTestSuite) TestUpdateCustomerEmail_NilCustomerID() {
-	err := s.service.UpdateCustomerEmail(uuid.Nil, "test@example.com")
+	err := s.service.UpdateCustomerEmail(context.Background(), uuid.Nil, "test@example.com")
 	s.Error(err)
 	s.ErrorIs(err, ErrInvalidCustomerID)
 }
@@ -422,9 +423,9 @@ func (s *CustomerProfileServiceTestSuite) TestDeleteCustomer() {
 			reason:     "Account closure requested",
 			wantErr:    false,
 			setupMocks: func() {
-				s.userRepo.EXPECT().GetByIDActive(userWithZeroBalance.ID).Return(userWithZeroBalance, nil).Times(1)
+				s.userRepo.EXPECT().GetByIDActive(gomock.Any(), userWithZeroBalance.ID).Return(userWithZeroBalance, nil).Times(1)
 				s.accountRepo.EXPECT().GetTotalBalanceByUserID(userWithZeroBalance.ID).Return(decimal.Zero, nil).Times(1)
-				s.userRepo.EXPECT().Delete(userWithZeroBalance.ID).Return(nil).Times(1)
+				s.userRepo.EXPECT().Delete(gomock.Any(), userWithZeroBalance.ID).Return(nil).Times(1)
 				s.accountRepo.EXPECT().SoftDeleteByUserID(userWithZeroBalance.ID).Return(nil).Times(1)
 			},
 			validate: func() {
@@ -438,7 +439,7 @@ func (s *CustomerProfileServiceTestSuite) TestDeleteCustomer() {
 			wantErr:    true,
 			errType:    ErrCustomerHasBalance,
 			setupMocks: func() {
-				s.userRepo.EXPECT().GetByIDActive(userWithBalance.ID).Return(userWithBalance, nil).Times(1)
+				s.userRepo.EXPECT().GetByIDActive(gomock.Any(), userWithBalance.ID).Return(userWithBalance, nil).Times(1)
 				s.accountRepo.EXPECT().GetTotalBalanceByUserID(userWithBalance.ID).Return(decimal.NewFromFloat(100.50), nil).Times(1)
 			},
 		},
@@ -457,7 +458,7 @@ func (s *CustomerProfileServiceTestSuite) TestDeleteCustomer() {
 			wantErr:    true,
 			errType:    ErrCustomerNotFound,
 			setupMocks: func() {
-				s.userRepo.EXPECT().GetByIDActive(gomock.Any()).Return(nil, repositories.ErrUserNotFound).Times(1)
+				s.userRepo.EXPECT().GetByIDActive(gomock.Any(), gomock.Any()).Return(nil, repositories.ErrUserNotFound).Times(1)
 			},
 		},
 	}
@@ -474,7 +475,7 @@ func (s *CustomerProfileServiceTestSuite) TestDeleteCustomer() {
 
 			tt.setupMocks()
 
-			err := s.service.DeleteCustomer(tt.customerID, tt.reason)
+			err := s.service.DeleteCustomer(context.Background(), tt.customerID, tt.reason)
 
 			if tt.wantErr {
 				s.Error(err)
@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// FeatureFlag names a feature that can be rolled out to a share of users before it is enabled
+// for everyone
+type FeatureFlag string
+
+// Transfer features behind a flag
+const (
+	FlagTransferApprovalWorkflow FeatureFlag = "transfer_approval_workflow"
+	FlagTransferRiskRules        FeatureFlag = "transfer_risk_rules"
+	FlagTransferAsyncInitiation  FeatureFlag = "transfer_async_initiation"
+)
+
+// Sources of a flag's effective rollout percentage, from lowest to highest precedence
+const (
+	FlagSourceDefault  = "default"
+	FlagSourceConfig   = "config"
+	FlagSourceOverride = "override"
+)
+
+var (
+	ErrUnknownFeatureFlag      = errors.New("unknown feature flag")
+	ErrInvalidFlagRollout      = errors.New("rollout percentage must be between 0 and 100")
+	errFeatureFlagConfigFormat = errors.New("expected flag=true, flag=false or flag=<percentage>")
+)
+
+// FeatureFlagDefinition declares a flag in code. DefaultPercentage is the share of users who get
+// the feature when neither config nor an override says otherwise.
+type FeatureFlagDefinition struct {
+	Name              FeatureFlag
+	Description       string
+	DefaultPercentage int
+}
+
+var featureFlagDefinitions = []FeatureFlagDefinition{
+	{Name: FlagTransferApprovalWorkflow, Description: "Hold large transfers for approval before they are sent to NorthWind"},
+	{Name: FlagTransferRiskRules, Description: "Evaluate risk rules before initiating a transfer"},
+	{Name: FlagTransferAsyncInitiation, Description: "Initiate transfers with NorthWind in the background instead of in the request"},
+}
+
+// FeatureFlagStatus describes a flag as admins see it. Percentage and Source give the rollout
+// that applies to users without their own override.
+type FeatureFlagStatus struct {
+	Flag              FeatureFlag               `json:"flag"`
+	Description       string                    `json:"description"`
+	DefaultPercentage int                       `json:"default_percentage"`
+	Percentage        int                       `json:"percentage"`
+	Source            string                    `json:"source"`
+	UserOverrides     []FeatureFlagUserOverride `json:"user_overrides"`
+}
+
+// FeatureFlagUserOverride forces a flag on or off for one user
+type FeatureFlagUserOverride struct {
+	UserID  uuid.UUID `json:"user_id"`
+	Enabled bool      `json:"enabled"`
+}
+
+// FeatureFlagService evaluates feature flags. A flag's rollout percentage comes from, in order
+// of precedence, a runtime override stored in the database, the FEATURE_FLAGS config and the
+// code default; a per-user override beats all of them. Users are bucketed by a hash of the flag
+// name and user ID, so evaluation is deterministic and each flag picks an independent cohort.
+type FeatureFlagService struct {
+	repo        repositories.FeatureFlagOverrideRepositoryInterface
+	definitions map[FeatureFlag]FeatureFlagDefinition
+	configured  map[FeatureFlag]int
+	logger      *slog.Logger
+}
+
+// NewFeatureFlagService creates a feature flag service. configured holds the rollout percentages
+// from config, typically produced by ParseFeatureFlagConfig; it may be nil.
+func NewFeatureFlagService(repo repositories.FeatureFlagOverrideRepositoryInterface, configured map[FeatureFlag]int, logger *slog.Logger) *FeatureFlagService {
+	definitions := make(map[FeatureFlag]FeatureFlagDefinition, len(featureFlagDefinitions))
+	for _, def := range featureFlagDefinitions {
+		definitions[def.Name] = def
+	}
+	return &FeatureFlagService{
+		repo:        repo,
+		definitions: definitions,
+		configured:  configured,
+		logger:      logger,
+	}
+}
+
+// ParseFeatureFlagConfig parses a comma-separated list of flag=value pairs, where value is true,
+// false or a rollout percentage such as 25 or 25%. Unknown flags are rejected so a typo in the
+// environment does not silently leave a feature off.
+func ParseFeatureFlagConfig(raw string) (map[FeatureFlag]int, error) {
+	known := make(map[FeatureFlag]bool, len(featureFlagDefinitions))
+	for _, def := range featureFlagDefinitions {
+		known[def.Name] = true
+	}
+
+	configured := make(map[FeatureFlag]int)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q: %w", entry, errFeatureFlagConfigFormat)
+		}
+		flag := FeatureFlag(strings.TrimSpace(name))
+		if !known[flag] {
+			return nil, fmt.Errorf("%q: %w", flag, ErrUnknownFeatureFlag)
+		}
+		percentage, err := parseFlagRollout(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", entry, err)
+		}
+		configured[flag] = percentage
+	}
+	return configured, nil
+}
+
+func parseFlagRollout(value string) (int, error) {
+	switch strings.ToLower(value) {
+	case "true", "on":
+		return 100, nil
+	case "false", "off":
+		return 0, nil
+	}
+	percentage, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+	if err != nil {
+		return 0, errFeatureFlagConfigFormat
+	}
+	if percentage < 0 || percentage > 100 {
+		return 0, ErrInvalidFlagRollout
+	}
+	return percentage, nil
+}
+
+// IsEnabled reports whether the flag is on for the user. Unknown flags are off. If overrides
+// cannot be loaded the flag is evaluated from config and defaults alone.
+func (s *FeatureFlagService) IsEnabled(ctx context.Context, flag FeatureFlag, userID uuid.UUID) bool {
+	def, ok := s.definitions[flag]
+	if !ok {
+		return false
+	}
+
+	percentage, _ := s.basePercentage(def)
+	overrides, err := s.repo.GetApplicable(ctx, string(flag), userID)
+	if err != nil {
+		s.logger.Warn("Failed to load feature flag overrides; using configured rollout",
+			"flag", flag, "user_id", userID, "error", err)
+		overrides = nil
+	}
+	for _, o := range overrides {
+		if o.UserID != nil {
+			return o.Percentage >= 100
+		}
+		percentage = o.Percentage
+	}
+	return flagBucket(flag, userID) < percentage
+}
+
+// ListFlags returns every defined flag with its effective rollout and per-user overrides
+func (s *FeatureFlagService) ListFlags(ctx context.Context) ([]FeatureFlagStatus, error) {
+	overrides, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byFlag := make(map[FeatureFlag][]models.FeatureFlagOverride)
+	for _, o := range overrides {
+		byFlag[FeatureFlag(o.Flag)] = append(byFlag[FeatureFlag(o.Flag)], o)
+	}
+
+	statuses := make([]FeatureFlagStatus, 0, len(featureFlagDefinitions))
+	for _, def := range featureFlagDefinitions {
+		status := FeatureFlagStatus{
+			Flag:              def.Name,
+			Description:       def.Description,
+			DefaultPercentage: def.DefaultPercentage,
+			UserOverrides:     []FeatureFlagUserOverride{},
+		}
+		status.Percentage, status.Source = s.basePercentage(def)
+		for _, o := range byFlag[def.Name] {
+			if o.UserID == nil {
+				status.Percentage, status.Source = o.Percentage, FlagSourceOverride
+				continue
+			}
+			status.UserOverrides = append(status.UserOverrides, FeatureFlagUserOverride{UserID: *o.UserID, Enabled: o.Percentage >= 100})
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// SetRollout overrides the flag's rollout percentage for everyone without a per-user override
+func (s *FeatureFlagService) SetRollout(ctx context.Context, flag FeatureFlag, percentage int, adminID uuid.UUID) error {
+	if _, ok := s.definitions[flag]; !ok {
+		return ErrUnknownFeatureFlag
+	}
+	if percentage < 0 || percentage > 100 {
+		return ErrInvalidFlagRollout
+	}
+	return s.repo.Upsert(ctx, &models.FeatureFlagOverride{Flag: string(flag), Percentage: percentage, UpdatedBy: &adminID})
+}
+
+// ClearRollout removes the runtime rollout override so config or the default applies again
+func (s *FeatureFlagService) ClearRollout(ctx context.Context, flag FeatureFlag) error {
+	if _, ok := s.definitions[flag]; !ok {
+		return ErrUnknownFeatureFlag
+	}
+	return s.repo.Delete(ctx, string(flag), nil)
+}
+
+// SetUserOverride forces the flag on or off for one user regardless of the rollout
+func (s *FeatureFlagService) SetUserOverride(ctx context.Context, flag FeatureFlag, userID uuid.UUID, enabled bool, adminID uuid.UUID) error {
+	if _, ok := s.definitions[flag]; !ok {
+		return ErrUnknownFeatureFlag
+	}
+	percentage := 0
+	if enabled {
+		percentage = 100
+	}
+	return s.repo.Upsert(ctx, &models.FeatureFlagOverride{Flag: string(flag), UserID: &userID, Percentage: percentage, UpdatedBy: &adminID})
+}
+
+// ClearUserOverride returns the user to the flag's rollout
+func (s *FeatureFlagService) ClearUserOverride(ctx context.Context, flag FeatureFlag, userID uuid.UUID) error {
+	if _, ok := s.definitions[flag]; !ok {
+		return ErrUnknownFeatureFlag
+	}
+	return s.repo.Delete(ctx, string(flag), &userID)
+}
+
+// basePercentage is the rollout before runtime overrides: config if set, otherwise the default
+func (s *FeatureFlagService) basePercentage(def FeatureFlagDefinition) (int, string) {
+	if percentage, ok := s.configured[def.Name]; ok {
+		return percentage, FlagSourceConfig
+	}
+	return def.DefaultPercentage, FlagSourceDefault
+}
+
+// flagBucket places the user in one of 100 buckets for the flag; the user is in the rollout
+// when the bucket is below the percentage, so raising the percentage only ever adds users
+func flagBucket(flag FeatureFlag, userID uuid.UUID) int {
+	h := fnv.New32a()
+	h.Write([]byte(flag))
+	h.Write([]byte{':'})
+	h.Write(userID[:])
+	return int(h.Sum32() % 100)
+}
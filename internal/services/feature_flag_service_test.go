@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/google/uuid"
+)
+
+func newFeatureFlagService(t *testing.T, configured map[FeatureFlag]int) *FeatureFlagService {
+	t.Helper()
+	repo := repositories.NewFeatureFlagOverrideRepository(testfactory.NewDB(t))
+	return NewFeatureFlagService(repo, configured, slog.Default())
+}
+
+// enabledShare counts how many of users have the flag on
+func enabledShare(svc *FeatureFlagService, flag FeatureFlag, users []uuid.UUID) int {
+	enabled := 0
+	for _, u := range users {
+		if svc.IsEnabled(context.Background(), flag, u) {
+			enabled++
+		}
+	}
+	return enabled
+}
+
+func newUsers(n int) []uuid.UUID {
+	users := make([]uuid.UUID, n)
+	for i := range users {
+		users[i] = uuid.New()
+	}
+	return users
+}
+
+func TestFeatureFlagService_Default(t *testing.T) {
+	svc := newFeatureFlagService(t, nil)
+	users := newUsers(50)
+
+	if n := enabledShare(svc, FlagTransferRiskRules, users); n != 0 {
+		t.Errorf("expected flag off by default, enabled for %d users", n)
+	}
+	if svc.IsEnabled(context.Background(), FeatureFlag("no_such_flag"), users[0]) {
+		t.Error("unknown flag evaluated as enabled")
+	}
+}
+
+func TestFeatureFlagService_ConfigOverride(t *testing.T) {
+	configured, err := ParseFeatureFlagConfig("transfer_risk_rules=true, transfer_async_initiation=30%")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	svc := newFeatureFlagService(t, configured)
+	users := newUsers(1000)
+
+	if n := enabledShare(svc, FlagTransferRiskRules, users); n != len(users) {
+		t.Errorf("expected flag on for everyone, enabled for %d of %d", n, len(users))
+	}
+	if n := enabledShare(svc, FlagTransferAsyncInitiation, users); n < 200 || n > 400 {
+		t.Errorf("expected roughly 30%% rollout, enabled for %d of %d", n, len(users))
+	}
+	if n := enabledShare(svc, FlagTransferApprovalWorkflow, users); n != 0 {
+		t.Errorf("unconfigured flag enabled for %d users", n)
+	}
+
+	for _, raw := range []string{"transfer_risk_rules", "transfer_risk_rules=maybe", "transfer_risk_rules=101", "no_such_flag=true"} {
+		if _, err := ParseFeatureFlagConfig(raw); err == nil {
+			t.Errorf("expected %q to be rejected", raw)
+		}
+	}
+}
+
+func TestFeatureFlagService_RuntimeAndUserOverrides(t *testing.T) {
+	ctx := context.Background()
+	svc := newFeatureFlagService(t, map[FeatureFlag]int{FlagTransferRiskRules: 100})
+	adminID := uuid.New()
+	optedIn, optedOut := uuid.New(), uuid.New()
+
+	if err := svc.SetRollout(ctx, FlagTransferRiskRules, 0, adminID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := svc.SetUserOverride(ctx, FlagTransferRiskRules, optedIn, true, adminID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if svc.IsEnabled(ctx, FlagTransferRiskRules, optedOut) {
+		t.Error("runtime override did not take precedence over config")
+	}
+	if !svc.IsEnabled(ctx, FlagTransferRiskRules, optedIn) {
+		t.Error("per-user override did not take precedence over the rollout")
+	}
+
+	if err := svc.ClearRollout(ctx, FlagTransferRiskRules); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := svc.SetUserOverride(ctx, FlagTransferRiskRules, optedOut, false, adminID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if svc.IsEnabled(ctx, FlagTransferRiskRules, optedOut) {
+		t.Error("per-user opt-out ignored")
+	}
+
+	statuses, err := svc.ListFlags(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, st := range statuses {
+		if st.Flag != FlagTransferRiskRules {
+			continue
+		}
+		if st.Percentage != 100 || st.Source != FlagSourceConfig || len(st.UserOverrides) != 2 {
+			t.Errorf("unexpected status %+v", st)
+		}
+	}
+
+	if err := svc.SetRollout(ctx, FeatureFlag("no_such_flag"), 10, adminID); !errors.Is(err, ErrUnknownFeatureFlag) {
+		t.Errorf("expected ErrUnknownFeatureFlag, got %v", err)
+	}
+	if err := svc.SetRollout(ctx, FlagTransferRiskRules, 150, adminID); !errors.Is(err, ErrInvalidFlagRollout) {
+		t.Errorf("expected ErrInvalidFlagRollout, got %v", err)
+	}
+}
+
+func TestFeatureFlagService_BucketStability(t *testing.T) {
+	ctx := context.Background()
+	svc := newFeatureFlagService(t, nil)
+	adminID := uuid.New()
+	users := newUsers(500)
+
+	if err := svc.SetRollout(ctx, FlagTransferAsyncInitiation, 25, adminID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first := make(map[uuid.UUID]bool, len(users))
+	for _, u := range users {
+		first[u] = svc.IsEnabled(ctx, FlagTransferAsyncInitiation, u)
+	}
+	for _, u := range users {
+		if svc.IsEnabled(ctx, FlagTransferAsyncInitiation, u) != first[u] {
+			t.Fatalf("evaluation for user %s changed between calls", u)
+		}
+	}
+
+	// Widening the rollout keeps everyone who already had the feature
+	if err := svc.SetRollout(ctx, FlagTransferAsyncInitiation, 60, adminID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, u := range users {
+		if first[u] && !svc.IsEnabled(ctx, FlagTransferAsyncInitiation, u) {
+			t.Fatalf("user %s lost the feature when the rollout widened", u)
+		}
+	}
+
+	// Buckets are pinned so a change to the hash, which would reshuffle every rollout, is caught
+	fixed := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	if got := flagBucket(FlagTransferRiskRules, fixed); got != 61 {
+		t.Errorf("expected bucket 61, got %d", got)
+	}
+	if got := flagBucket(FlagTransferAsyncInitiation, fixed); got != 25 {
+		t.Errorf("expected bucket 25, got %d", got)
+	}
+}
@@ -12,30 +12,30 @@ import (
 
 // AccountAssociationServiceInterface defines the contract for account association operations
 type AccountAssociationServiceInterface interface {
-	GetCustomerAccounts(customerID uuid.UUID) ([]*models.Account, error)
-	CreateAccountForCustomer(customerID, performedBy uuid.UUID, accountType, ipAddress, userAgent string) (*models.Account, error)
-	TransferAccountOwnership(accountID, fromCustomerID, toCustomerID, performedBy uuid.UUID, ipAddress, userAgent string) error
+	GetCustomerAccounts(ctx context.Context, customerID uuid.UUID) ([]*models.Account, error)
+	CreateAccountForCustomer(ctx context.Context, customerID, performedBy uuid.UUID, accountType, ipAddress, userAgent string) (*models.Account, error)
+	TransferAccountOwnership(ctx context.Context, accountID, fromCustomerID, toCustomerID, performedBy uuid.UUID, ipAddress, userAgent string) error
 }
 
 // AccountServiceInterface defines account-related business operations
 type AccountServiceInterface interface {
-	CreateAccount(userID uuid.UUID, accountType string, initialDeposit decimal.Decimal) (*models.Account, error)
+	CreateAccount(ctx context.Context, userID uuid.UUID, accountType string, initialDeposit decimal.Decimal) (*models.Account, error)
 	CreateAccountsForNewUser(userID uuid.UUID) error
-	GetAccountByID(accountID uuid.UUID, userID *uuid.UUID) (*models.Account, error)
+	GetAccountByID(ctx context.Context, accountID uuid.UUID, userID *uuid.UUID) (*models.Account, error)
 	GetAccountByNumber(accountNumber string) (*models.Account, error)
 	GetUserAccounts(userID uuid.UUID) ([]models.Account, error)
 	GetAllAccounts(filters models.AccountFilters, offset, limit int) ([]models.Account, int64, error)
-	UpdateAccountStatus(accountID uuid.UUID, userID *uuid.UUID, status string) (*models.Account, error)
-	CloseAccount(accountID uuid.UUID, userID uuid.UUID) error
-	PerformTransaction(accountID uuid.UUID, amount decimal.Decimal, transactionType, description string, userID *uuid.UUID) (*models.Transaction, error)
+	UpdateAccountStatus(ctx context.Context, accountID uuid.UUID, userID *uuid.UUID, status string) (*models.Account, error)
+	CloseAccount(ctx context.Context, accountID uuid.UUID, userID uuid.UUID) error
+	PerformTransaction(ctx context.Context, accountID uuid.UUID, amount decimal.Decimal, transactionType, description string, userID *uuid.UUID) (*models.Transaction, error)
 	TransferBetweenAccounts(fromAccountID, toAccountID uuid.UUID, amount decimal.Decimal, description, idempotencyKey string, userID uuid.UUID) (*models.Transfer, error)
-	GetAccountTransactions(accountID uuid.UUID, userID *uuid.UUID, offset, limit int) ([]models.Transaction, int64, error)
-	GetRecentTransactions(accountID uuid.UUID, userID *uuid.UUID, limit int) ([]models.Transaction, error)
+	GetAccountTransactions(ctx context.Context, accountID uuid.UUID, userID *uuid.UUID, offset, limit int) ([]models.Transaction, int64, error)
+	GetRecentTransactions(ctx context.Context, accountID uuid.UUID, userID *uuid.UUID, limit int) ([]models.Transaction, error)
 	GetUserTransfers(userID uuid.UUID, filters models.TransferFilters, offset, limit int) ([]models.Transfer, int64, error)
 }
 
 type AccountSummaryServiceInterface interface {
-	GetAccountSummary(requestorID uuid.UUID, targetUserID *uuid.UUID, isAdmin bool) (*models.UserAccountSummary, error)
+	GetAccountSummary(ctx context.Context, requestorID uuid.UUID, targetUserID *uuid.UUID, isAdmin bool) (*models.UserAccountSummary, error)
 }
 
 // AuditServiceInterface defines the contract for audit logging operations
@@ -80,25 +80,25 @@ type CategoryServiceInterface interface {
 
 // CustomerProfileServiceInterface defines the contract for customer profile operations
 type CustomerProfileServiceInterface interface {
-	GetCustomerProfile(customerID uuid.UUID) (*models.User, error)
-	CreateCustomer(email, firstName, lastName string, role string) (*models.User, string, error)
-	UpdateCustomerProfile(customerID uuid.UUID, updates map[string]interface{}) error
-	UpdateCustomerEmail(customerID uuid.UUID, newEmail string) error
-	DeleteCustomer(customerID uuid.UUID, reason string) error
+	GetCustomerProfile(ctx context.Context, customerID uuid.UUID) (*models.User, error)
+	CreateCustomer(ctx context.Context, email, firstName, lastName string, role string) (*models.User, string, error)
+	UpdateCustomerProfile(ctx context.Context, customerID uuid.UUID, updates map[string]interface{}) error
+	UpdateCustomerEmail(ctx context.Context, customerID uuid.UUID, newEmail string) error
+	DeleteCustomer(ctx context.Context, customerID uuid.UUID, reason string) error
 }
 
 // CustomerSearchServiceInterface defines the contract for customer search operations
 type CustomerSearchServiceInterface interface {
-	SearchCustomers(query string, searchType models.SearchType, offset, limit int) ([]*models.CustomerSearchResult, int64, error)
+	SearchCustomers(ctx context.Context, query string, searchType models.SearchType, offset, limit int) ([]*models.CustomerSearchResult, int64, error)
 }
 
 // AccountMetricsServiceInterface provides performance metrics and analytics for accounts
 type AccountMetricsServiceInterface interface {
 	// GetAccountMetrics calculates performance metrics for a single account over a date range
-	GetAccountMetrics(requestorID, accountID uuid.UUID, startDate, endDate *time.Time, isAdmin bool) (*models.AccountMetrics, error)
+	GetAccountMetrics(ctx context.Context, requestorID, accountID uuid.UUID, startDate, endDate *time.Time, isAdmin bool) (*models.AccountMetrics, error)
 
 	// GetUserAggregateMetrics calculates aggregate metrics across all accounts for a user
-	GetUserAggregateMetrics(requestorID, targetUserID uuid.UUID, startDate, endDate *time.Time, isAdmin bool) (*models.UserAggregateMetrics, error)
+	GetUserAggregateMetrics(ctx context.Context, requestorID, targetUserID uuid.UUID, startDate, endDate *time.Time, isAdmin bool) (*models.UserAggregateMetrics, error)
 }
 
 type MetricsRecorderInterface interface {
@@ -110,7 +110,7 @@ type MetricsRecorderInterface interface {
 // StatementServiceInterface provides account statement generation
 type StatementServiceInterface interface {
 	// GenerateStatement generates a monthly or quarterly account statement
-	GenerateStatement(requestorID, accountID uuid.UUID, periodType string, year, period int, isAdmin bool) (*models.AccountStatement, error)
+	GenerateStatement(ctx context.Context, requestorID, accountID uuid.UUID, periodType string, year, period int, isAdmin bool) (*models.AccountStatement, error)
 }
 
 // TransactionGeneratorInterface generates realistic transaction data for testing
@@ -128,9 +128,9 @@ type TransactionGeneratorInterface interface {
 }
 
 type AuthServiceInterface interface {
-	Register(req *dto.RegisterRequest, ipAddress, userAgent string) (*models.User, error)
-	Login(req *dto.LoginRequest, ipAddress, userAgent string) (*dto.TokenResponse, error)
-	RefreshTokens(refreshToken, ipAddress, userAgent string) (*dto.TokenResponse, error)
+	Register(ctx context.Context, req *dto.RegisterRequest, ipAddress, userAgent string) (*models.User, error)
+	Login(ctx context.Context, req *dto.LoginRequest, ipAddress, userAgent string) (*dto.TokenResponse, error)
+	RefreshTokens(ctx context.Context, refreshToken, ipAddress, userAgent string) (*dto.TokenResponse, error)
 	Logout(accessToken, ipAddress, userAgent string) error
 }
 
@@ -142,6 +142,7 @@ type TokenServiceInterface interface {
 	ExtractTokenFromHeader(authHeader string) (string, error)
 	GetJTI(tokenString string) (string, error)
 	GetTokenExpiry(tokenString string) (time.Time, error)
+	ScopesForRole(role string) models.TokenScopes
 }
 
 type PasswordServiceInterface interface {
@@ -152,8 +153,8 @@ type PasswordServiceInterface interface {
 	GenerateSecurePassword() (string, error)
 	GenerateSecurePasswordWithLength(length int) (string, error)
 	PasswordStrength(password string) int
-	AdminResetPassword(customerID, adminID uuid.UUID) (string, error)
-	CustomerUpdatePassword(customerID uuid.UUID, currentPassword, newPassword string) error
+	AdminResetPassword(ctx context.Context, customerID, adminID uuid.UUID) (string, error)
+	CustomerUpdatePassword(ctx context.Context, customerID uuid.UUID, currentPassword, newPassword string) error
 }
 
 type AuditLoggerInterface interface {
@@ -180,6 +181,9 @@ type CircuitBreakerInterface interface {
 	GetState() models.CircuitBreakerState
 	Reset()
 	GetFailureCount() int
+	// RetryAfter is how long until an open breaker goes half-open and lets a trial call
+	// through; zero unless it is open
+	RetryAfter() time.Duration
 }
 
 type CustomerLoggerInterface interface {
@@ -199,7 +203,7 @@ type CustomerLoggerInterface interface {
 }
 
 type TransactionProcessingServiceInterface interface {
-	EnqueueTransaction(transactionID uuid.UUID, operation string, priority int) error
+	EnqueueTransaction(ctx context.Context, transactionID uuid.UUID, operation string, priority int) error
 	StartProcessing(ctx context.Context)
 	ProcessQueueItem(ctx context.Context, queueItem *models.ProcessingQueueItem) error
 	GetQueueMetrics() (*dto.QueueMetrics, error)
@@ -0,0 +1,329 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"golang.org/x/time/rate"
+)
+
+// MaxAccountImportRows is the most data rows one external account import may hold
+const MaxAccountImportRows = 5000
+
+// Default bounds on how fast imports call NorthWind
+const (
+	DefaultAccountImportConcurrency = 4
+	DefaultAccountImportRate        = 10 // registrations per second
+)
+
+// Outcomes of one imported row
+const (
+	AccountImportRegistered       = "REGISTERED"
+	AccountImportAlreadyExists    = "ALREADY_EXISTS"
+	AccountImportValidationFailed = "VALIDATION_FAILED"
+	AccountImportUpstreamError    = "UPSTREAM_ERROR"
+	AccountImportDuplicate        = "DUPLICATE"
+)
+
+// Columns of an import file; extra columns, such as the error column of a failure CSV, are ignored
+const (
+	accountImportColumnHolderName    = "account_holder_name"
+	accountImportColumnAccountNumber = "account_number"
+	accountImportColumnRoutingNumber = "routing_number"
+	accountImportColumnNickname      = "nickname"
+	accountImportColumnError         = "error"
+)
+
+var (
+	ErrAccountImportMalformed   = errors.New("malformed account import file")
+	ErrAccountImportTooManyRows = fmt.Errorf("account import files are limited to %d rows", MaxAccountImportRows)
+)
+
+// AccountImportError reports an import file that is not a readable CSV or lacks a required
+// column. It wraps ErrAccountImportMalformed.
+type AccountImportError struct {
+	Line   int
+	Reason string
+}
+
+func (e *AccountImportError) Error() string {
+	return fmt.Sprintf("%s: line %d: %s", ErrAccountImportMalformed, e.Line, e.Reason)
+}
+
+func (e *AccountImportError) Unwrap() error {
+	return ErrAccountImportMalformed
+}
+
+// AccountImportRowResult is the outcome of one data row, numbered from 1 after the header
+type AccountImportRowResult struct {
+	Row               int        `json:"row"`
+	AccountHolderName string     `json:"account_holder_name"`
+	AccountNumber     string     `json:"account_number"`
+	RoutingNumber     string     `json:"routing_number"`
+	Nickname          string     `json:"nickname,omitempty"`
+	Outcome           string     `json:"outcome"`
+	Reason            string     `json:"reason,omitempty"`
+	AccountID         *uuid.UUID `json:"account_id,omitempty"`
+	// DuplicateOf is the earlier row with the same account and routing number
+	DuplicateOf int `json:"duplicate_of,omitempty"`
+}
+
+// AccountImportReport is the per-row result of an import. FailuresCSV holds the rows that failed
+// validation or could not reach NorthWind with an error column, ready to fix and upload again.
+type AccountImportReport struct {
+	Total            int                      `json:"total"`
+	Registered       int                      `json:"registered"`
+	AlreadyExists    int                      `json:"already_exists"`
+	ValidationFailed int                      `json:"validation_failed"`
+	UpstreamErrors   int                      `json:"upstream_errors"`
+	Duplicates       int                      `json:"duplicates"`
+	Rows             []AccountImportRowResult `json:"rows"`
+	FailuresCSV      string                   `json:"failures_csv,omitempty"`
+}
+
+// accountImportRow is one parsed data row with the account number as uploaded
+type accountImportRow struct {
+	req           ValidateAndRegisterRequest
+	accountNumber string
+}
+
+// SetAccountImportLimits sets how many rows an import registers at once and how many
+// registrations per second all imports together may make. Non-positive values keep the defaults.
+func (s *NorthwindAccountService) SetAccountImportLimits(concurrency int, ratePerSecond float64) {
+	if concurrency <= 0 {
+		concurrency = DefaultAccountImportConcurrency
+	}
+	if ratePerSecond <= 0 {
+		ratePerSecond = DefaultAccountImportRate
+	}
+	s.importConcurrency = concurrency
+	s.importLimiter = rate.NewLimiter(rate.Limit(ratePerSecond), concurrency)
+}
+
+// ImportAccounts validates and registers every external account in a CSV with the columns
+// account_holder_name, account_number, routing_number and, optionally, nickname. The whole file
+// is read before any row is registered, so a malformed file or one over MaxAccountImportRows
+// registers nothing. Rows repeating an earlier account and routing number are reported as
+// duplicates and not registered again. A row that fails is reported rather than failing the
+// import.
+func (s *NorthwindAccountService) ImportAccounts(ctx context.Context, userID uuid.UUID, r io.Reader) (*AccountImportReport, error) {
+	rows, err := parseAccountImport(r)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]AccountImportRowResult, len(rows))
+	firstRow := make(map[accountValidationKey]int, len(rows))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(s.importConcurrency, len(rows)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				s.importRow(ctx, userID, rows[i], &results[i])
+			}
+		}()
+	}
+	for i, row := range rows {
+		results[i] = AccountImportRowResult{
+			Row:               i + 1,
+			AccountHolderName: row.req.AccountHolderName,
+			AccountNumber:     maskAccountNumber(row.accountNumber),
+			RoutingNumber:     row.req.RoutingNumber,
+			Nickname:          row.req.Nickname,
+		}
+		key := accountValidationKey{accountNumber: row.req.AccountNumber, routingNumber: row.req.RoutingNumber}
+		if first, ok := firstRow[key]; ok {
+			results[i].Outcome = AccountImportDuplicate
+			results[i].DuplicateOf = first
+			continue
+		}
+		firstRow[key] = i + 1
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	report := &AccountImportReport{Total: len(rows), Rows: results}
+	var failed []int
+	for i, result := range results {
+		switch result.Outcome {
+		case AccountImportRegistered:
+			report.Registered++
+		case AccountImportAlreadyExists:
+			report.AlreadyExists++
+		case AccountImportValidationFailed:
+			report.ValidationFailed++
+			failed = append(failed, i)
+		case AccountImportUpstreamError:
+			report.UpstreamErrors++
+			failed = append(failed, i)
+		case AccountImportDuplicate:
+			report.Duplicates++
+		}
+	}
+	if len(failed) > 0 {
+		if report.FailuresCSV, err = accountImportFailuresCSV(rows, results, failed); err != nil {
+			return nil, err
+		}
+	}
+
+	s.logger.Info("External account import finished",
+		"user_id", userID,
+		"rows", report.Total,
+		"registered", report.Registered,
+		"already_exists", report.AlreadyExists,
+		"validation_failed", report.ValidationFailed,
+		"upstream_errors", report.UpstreamErrors,
+		"duplicates", report.Duplicates,
+	)
+	return report, nil
+}
+
+// importRow registers one row under the import limits and records its outcome
+func (s *NorthwindAccountService) importRow(ctx context.Context, userID uuid.UUID, row accountImportRow, result *AccountImportRowResult) {
+	for _, field := range []struct{ name, value string }{
+		{accountImportColumnHolderName, row.req.AccountHolderName},
+		{accountImportColumnAccountNumber, row.req.AccountNumber},
+		{accountImportColumnRoutingNumber, row.req.RoutingNumber},
+	} {
+		if field.value == "" {
+			result.Outcome = AccountImportValidationFailed
+			result.Reason = field.name + " is required"
+			return
+		}
+	}
+	if err := s.importLimiter.Wait(ctx); err != nil {
+		result.Outcome = AccountImportUpstreamError
+		result.Reason = "The import was stopped before this row was registered"
+		return
+	}
+
+	resp, err := s.ValidateAndRegister(ctx, userID, row.req)
+	var textErr *InvalidTextError
+	switch {
+	case err == nil && resp.AlreadyRegistered:
+		result.Outcome = AccountImportAlreadyExists
+		result.AccountID = &resp.Account.ID
+	case err == nil:
+		result.Outcome = AccountImportRegistered
+		result.AccountID = &resp.Account.ID
+	case errors.As(err, &textErr):
+		result.Outcome = AccountImportValidationFailed
+		result.Reason = strings.TrimPrefix(textErr.Error(), ErrNWInvalidText.Error()+": ")
+	case errors.Is(err, ErrAccountHolderNameMismatch):
+		result.Outcome = AccountImportValidationFailed
+		result.Reason = "account_holder_name is too different from the name NorthWind has on file for this account"
+	case errors.Is(err, ErrExternalAccountValidationFailed):
+		result.Outcome = AccountImportValidationFailed
+		result.Reason = "NorthWind could not validate the account"
+		if resp != nil && resp.Validation != nil && resp.Validation.Message != "" {
+			result.Reason = resp.Validation.Message
+		}
+	default:
+		s.logger.Error("External account import row failed", "user_id", userID, "row", result.Row, "error", err)
+		result.Outcome = AccountImportUpstreamError
+		result.Reason = "The account could not be validated with NorthWind; retry it later"
+	}
+}
+
+// parseAccountImport reads every data row of an import file
+func parseAccountImport(r io.Reader) ([]accountImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, &AccountImportError{Line: 1, Reason: "the file is empty"}
+	}
+	if err != nil {
+		return nil, csvImportError(err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			// Spreadsheet exports often start with a byte order mark
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{accountImportColumnHolderName, accountImportColumnAccountNumber, accountImportColumnRoutingNumber} {
+		if _, ok := columns[name]; !ok {
+			return nil, &AccountImportError{Line: 1, Reason: "missing column " + name}
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var rows []accountImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, csvImportError(err)
+		}
+		if len(rows) == MaxAccountImportRows {
+			return nil, ErrAccountImportTooManyRows
+		}
+		accountNumber := field(record, accountImportColumnAccountNumber)
+		rows = append(rows, accountImportRow{
+			req: ValidateAndRegisterRequest{
+				AccountHolderName: field(record, accountImportColumnHolderName),
+				AccountNumber:     strings.ReplaceAll(accountNumber, " ", ""),
+				RoutingNumber:     field(record, accountImportColumnRoutingNumber),
+				Nickname:          field(record, accountImportColumnNickname),
+			},
+			accountNumber: accountNumber,
+		})
+	}
+	return rows, nil
+}
+
+func csvImportError(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return &AccountImportError{Line: parseErr.Line, Reason: parseErr.Err.Error()}
+	}
+	return fmt.Errorf("failed to read account import file: %w", err)
+}
+
+// accountImportFailuresCSV writes the failed rows as uploaded, with the reason each failed
+func accountImportFailuresCSV(rows []accountImportRow, results []AccountImportRowResult, failed []int) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{
+		accountImportColumnHolderName,
+		accountImportColumnAccountNumber,
+		accountImportColumnRoutingNumber,
+		accountImportColumnNickname,
+		accountImportColumnError,
+	})
+	for _, i := range failed {
+		_ = w.Write([]string{
+			rows[i].req.AccountHolderName,
+			rows[i].accountNumber,
+			rows[i].req.RoutingNumber,
+			rows[i].req.Nickname,
+			results[i].Reason,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", fmt.Errorf("failed to write import failures: %w", err)
+	}
+	return buf.String(), nil
+}
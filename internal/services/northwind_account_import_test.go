@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/google/uuid"
+)
+
+// fakeImportValidationAPI validates every account except those starting with 9, and fails with a
+// 500 for those starting with 5. It tracks the most validations it saw in flight at once, holding
+// each for delay.
+type fakeImportValidationAPI struct {
+	calls       atomic.Int32
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+	delay       time.Duration
+}
+
+func (f *fakeImportValidationAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.calls.Add(1)
+	current := f.inFlight.Add(1)
+	defer f.inFlight.Add(-1)
+	for {
+		seen := f.maxInFlight.Load()
+		if current <= seen || f.maxInFlight.CompareAndSwap(seen, current) {
+			break
+		}
+	}
+	time.Sleep(f.delay)
+
+	var req northwind.AccountValidationRequest
+	_ = json.NewDecoder(r.Body).Decode(&req)
+	w.Header().Set("Content-Type", "application/json")
+	if strings.HasPrefix(req.AccountNumber, "5") {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(northwind.APIErrorResponse{Message: "upstream unavailable"})
+		return
+	}
+	valid := !strings.HasPrefix(req.AccountNumber, "9")
+	resp := northwind.AccountValidationResponse{Valid: valid, AccountNumber: req.AccountNumber, RoutingNumber: req.RoutingNumber}
+	if !valid {
+		resp.Message = "Account not found"
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func newImportTestService(t *testing.T, api *fakeImportValidationAPI) (*NorthwindAccountService, repositories.NorthwindExternalAccountRepositoryInterface) {
+	t.Helper()
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	repo := repositories.NewNorthwindExternalAccountRepository(testfactory.NewDB(t))
+	svc := NewNorthwindAccountService(northwind.NewClient(server.URL, "test-key"), repo, slog.Default())
+	svc.SetAccountImportLimits(2, 1000)
+	return svc, repo
+}
+
+func TestNorthwindAccountService_ImportAccounts_MixedOutcomes(t *testing.T) {
+	api := &fakeImportValidationAPI{}
+	svc, repo := newImportTestService(t, api)
+	ctx := context.Background()
+	userID := uuid.New()
+	existing := &models.NorthwindExternalAccount{UserID: &userID, AccountHolderName: "Acme Supplies", AccountNumber: "3333333333", RoutingNumber: "021000021", Validated: true}
+	if err := repo.Create(ctx, existing); err != nil {
+		t.Fatalf("failed to create existing account: %v", err)
+	}
+
+	file := strings.Join([]string{
+		"account_holder_name,account_number,routing_number,nickname",
+		"Globex Corp,1111111111,021000021,Office supplies",
+		"Acme Supplies,3333333333,021000021,Acme",
+		"Initech,9999999999,021000021,Printers",
+		"Umbrella Ltd,5555555555,021000021,",
+		"Globex Corp,1111 111 111,021000021,Same vendor again",
+		"Hooli,2222222222,,Missing routing",
+	}, "\n")
+
+	report, err := svc.ImportAccounts(ctx, userID, strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []struct {
+		outcome string
+		reason  string
+	}{
+		{AccountImportRegistered, ""},
+		{AccountImportAlreadyExists, ""},
+		{AccountImportValidationFailed, "Account not found"},
+		{AccountImportUpstreamError, "retry it later"},
+		{AccountImportDuplicate, ""},
+		{AccountImportValidationFailed, "routing_number is required"},
+	}
+	if len(report.Rows) != len(want) {
+		t.Fatalf("expected %d rows, got %d", len(want), len(report.Rows))
+	}
+	for i, w := range want {
+		row := report.Rows[i]
+		if row.Row != i+1 || row.Outcome != w.outcome || !strings.Contains(row.Reason, w.reason) {
+			t.Errorf("row %d: expected %s %q, got %+v", i+1, w.outcome, w.reason, row)
+		}
+	}
+	if report.Rows[0].AccountID == nil || report.Rows[1].AccountID == nil || *report.Rows[1].AccountID != existing.ID {
+		t.Errorf("expected registered and existing rows to name their accounts, got %v and %v", report.Rows[0].AccountID, report.Rows[1].AccountID)
+	}
+	if report.Rows[4].DuplicateOf != 1 {
+		t.Errorf("expected row 5 to duplicate row 1, got %d", report.Rows[4].DuplicateOf)
+	}
+	if report.Rows[0].AccountNumber != "****1111" {
+		t.Errorf("expected the report to mask account numbers, got %q", report.Rows[0].AccountNumber)
+	}
+	if report.Total != 6 || report.Registered != 1 || report.AlreadyExists != 1 || report.ValidationFailed != 2 || report.UpstreamErrors != 1 || report.Duplicates != 1 {
+		t.Errorf("unexpected totals: %+v", report)
+	}
+	if calls := api.calls.Load(); calls != 3 {
+		t.Errorf("expected NorthWind to validate only the 3 new, complete rows, got %d calls", calls)
+	}
+
+	stored, _, _ := repo.GetByUserID(ctx, userID, 0, 10)
+	if len(stored) != 2 {
+		t.Fatalf("expected the existing and the new account, got %d", len(stored))
+	}
+	for _, account := range stored {
+		if account.AccountNumber == "1111111111" && (account.Nickname == nil || *account.Nickname != "Office supplies") {
+			t.Errorf("expected the imported nickname to be stored, got %v", account.Nickname)
+		}
+	}
+
+	// The failure CSV holds the failed rows as uploaded and can be imported again
+	failures, err := parseAccountImport(strings.NewReader(report.FailuresCSV))
+	if err != nil {
+		t.Fatalf("expected the failure CSV to be importable: %v", err)
+	}
+	if len(failures) != 3 || failures[0].req.AccountNumber != "9999999999" || failures[1].req.AccountNumber != "5555555555" || failures[2].req.AccountHolderName != "Hooli" {
+		t.Errorf("expected the three failed rows, got %+v", failures)
+	}
+	if !strings.Contains(report.FailuresCSV, "routing_number is required") {
+		t.Errorf("expected the failure CSV to carry the reasons, got %q", report.FailuresCSV)
+	}
+}
+
+func TestNorthwindAccountService_ImportAccounts_BoundsConcurrency(t *testing.T) {
+	api := &fakeImportValidationAPI{delay: 20 * time.Millisecond}
+	svc, _ := newImportTestService(t, api)
+
+	lines := []string{"account_holder_name,account_number,routing_number"}
+	for i := range 8 {
+		lines = append(lines, "Vendor,"+strings.Repeat(string(rune('1'+i%4)), 9)+string(rune('0'+i))+",021000021")
+	}
+	report, err := svc.ImportAccounts(context.Background(), uuid.New(), strings.NewReader(strings.Join(lines, "\n")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Registered != 8 {
+		t.Fatalf("expected all 8 rows registered, got %+v", report)
+	}
+	if got := api.maxInFlight.Load(); got != 2 {
+		t.Errorf("expected at most 2 validations in flight and the bound reached, got %d", got)
+	}
+}
+
+func TestNorthwindAccountService_ImportAccounts_Malformed(t *testing.T) {
+	tests := map[string]struct {
+		file    string
+		wantErr error
+		line    int
+	}{
+		"empty file":        {"", ErrAccountImportMalformed, 1},
+		"missing column":    {"account_holder_name,account_number\nGlobex,1111111111", ErrAccountImportMalformed, 1},
+		"wrong field count": {"account_holder_name,account_number,routing_number\nGlobex,1111111111,021000021\nInitech,2222222222", ErrAccountImportMalformed, 3},
+		"bare quote":        {"account_holder_name,account_number,routing_number\nGlo\"bex,1111111111,021000021", ErrAccountImportMalformed, 2},
+		"too many rows": {
+			"account_holder_name,account_number,routing_number\n" + strings.Repeat("Globex,1111111111,021000021\n", MaxAccountImportRows+1),
+			ErrAccountImportTooManyRows, 0,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			api := &fakeImportValidationAPI{}
+			svc, _ := newImportTestService(t, api)
+
+			_, err := svc.ImportAccounts(context.Background(), uuid.New(), strings.NewReader(tt.file))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			var importErr *AccountImportError
+			if tt.line > 0 && (!errors.As(err, &importErr) || importErr.Line != tt.line) {
+				t.Errorf("expected the error on line %d, got %v", tt.line, err)
+			}
+			if calls := api.calls.Load(); calls != 0 {
+				t.Errorf("expected nothing registered from a rejected file, got %d NorthWind calls", calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Outcomes of comparing the account holder name a user typed with the name NorthWind has on file
+const (
+	NameMatchMatch         = "match"
+	NameMatchMinorMismatch = "minor_mismatch"
+	NameMatchMajorMismatch = "major_mismatch"
+)
+
+// DefaultNameMatchThreshold is the name similarity, from 0 to 1, below which a mismatch is major
+const DefaultNameMatchThreshold = 0.8
+
+// ErrAccountHolderNameMismatch is returned when the typed account holder name is too different from
+// the name NorthWind has on file to register the account without an admin override
+var ErrAccountHolderNameMismatch = errors.New("account holder name does not match the name on the account")
+
+// nameAffixes are titles and suffixes ignored when comparing names
+var nameAffixes = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "miss": true, "dr": true,
+	"jr": true, "sr": true, "ii": true, "iii": true,
+}
+
+// SetNameMatchThreshold sets the similarity below which a holder name mismatch rejects the
+// registration. Values outside (0, 1] keep DefaultNameMatchThreshold.
+func (s *NorthwindAccountService) SetNameMatchThreshold(threshold float64) {
+	if threshold <= 0 || threshold > 1 {
+		threshold = DefaultNameMatchThreshold
+	}
+	s.nameMatchThreshold = threshold
+}
+
+// compareHolderNames classifies how well typed matches onFile. Names made of the same words, in
+// any order and ignoring case, punctuation and titles, match. Otherwise each word of the shorter
+// name is paired with its closest unpaired word in the longer one: an initial pairs fully with a
+// word it starts, other words score by edit distance. A mean score of at least threshold is a
+// minor mismatch, such as "J. Smith" for "John Smith"; anything lower is major.
+func compareHolderNames(typed, onFile string, threshold float64) (string, float64) {
+	a, b := holderNameTokens(typed), holderNameTokens(onFile)
+	if len(a) == 0 || len(b) == 0 {
+		return NameMatchMajorMismatch, 0
+	}
+	if strings.Join(a, " ") == strings.Join(b, " ") {
+		return NameMatchMatch, 1
+	}
+
+	shorter, longer := a, b
+	if len(b) < len(a) {
+		shorter, longer = b, a
+	}
+	paired := make([]bool, len(longer))
+	var total float64
+	for _, word := range shorter {
+		best, bestIdx := 0.0, -1
+		for i, other := range longer {
+			if paired[i] {
+				continue
+			}
+			if score := nameTokenSimilarity(word, other); score > best {
+				best, bestIdx = score, i
+			}
+		}
+		if bestIdx >= 0 {
+			paired[bestIdx] = true
+		}
+		total += best
+	}
+
+	score := total / float64(len(shorter))
+	if score >= threshold {
+		return NameMatchMinorMismatch, score
+	}
+	return NameMatchMajorMismatch, score
+}
+
+// holderNameTokens lowercases name and splits it into sorted words, dropping punctuation and
+// nameAffixes
+func holderNameTokens(name string) []string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := words[:0]
+	for _, word := range words {
+		if !nameAffixes[word] {
+			tokens = append(tokens, word)
+		}
+	}
+	sort.Strings(tokens)
+	return tokens
+}
+
+// nameTokenSimilarity scores two words from 0 to 1; a single letter is an initial that fully
+// matches any word starting with it
+func nameTokenSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	if utf8.RuneCountInString(a) == 1 || utf8.RuneCountInString(b) == 1 {
+		ra, _ := utf8.DecodeRuneInString(a)
+		rb, _ := utf8.DecodeRuneInString(b)
+		if ra == rb {
+			return 1
+		}
+		return 0
+	}
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein returns the number of single-rune edits turning a into b
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
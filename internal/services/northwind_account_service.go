@@ -11,6 +11,7 @@ import (
 	"github.com/array/banking-api/internal/models"
 	"github.com/array/banking-api/internal/repositories"
 	"github.com/google/uuid"
+	"golang.org/x/time/rate"
 )
 
 var (
@@ -21,9 +22,16 @@ var (
 
 // NorthwindAccountService handles external account registration and validation
 type NorthwindAccountService struct {
-	client *northwind.Client
-	repo   repositories.NorthwindExternalAccountRepositoryInterface
-	logger *slog.Logger
+	client      *northwind.Client
+	repo        repositories.NorthwindExternalAccountRepositoryInterface
+	logger      *slog.Logger
+	validations *accountValidationCache
+	// nameMatchThreshold is the holder name similarity below which registration is rejected
+	nameMatchThreshold float64
+	// importConcurrency and importLimiter bound how fast CSV imports register accounts; the
+	// limiter is shared by every import so concurrent uploads do not multiply NorthWind load
+	importConcurrency int
+	importLimiter     *rate.Limiter
 }
 
 // NewNorthwindAccountService creates a new NorthWind account service
@@ -33,9 +41,14 @@ func NewNorthwindAccountService(
 	logger *slog.Logger,
 ) *NorthwindAccountService {
 	return &NorthwindAccountService{
-		client: client,
-		repo:   repo,
-		logger: logger,
+		client:      client,
+		repo:        repo,
+		logger:      logger,
+		validations: newAccountValidationCache(DefaultAccountValidationCacheTTL),
+
+		nameMatchThreshold: DefaultNameMatchThreshold,
+		importConcurrency:  DefaultAccountImportConcurrency,
+		importLimiter:      rate.NewLimiter(DefaultAccountImportRate, DefaultAccountImportConcurrency),
 	}
 }
 
@@ -45,18 +58,36 @@ type ValidateAndRegisterRequest struct {
 	AccountNumber     string `json:"account_number" validate:"required"`
 	RoutingNumber     string `json:"routing_number" validate:"required"`
 	InstitutionName   string `json:"institution_name,omitempty"`
+	Nickname          string `json:"nickname,omitempty" validate:"omitempty,max=50"`
+	// OverrideNameMismatch registers the account even when the holder name is far from the name
+	// NorthWind has on file; only admins may set it
+	OverrideNameMismatch bool `json:"override_name_mismatch,omitempty"`
 }
 
 // ValidateAndRegisterResponse represents the response from validation and registration
 type ValidateAndRegisterResponse struct {
 	Account    *models.NorthwindExternalAccount     `json:"account"`
 	Validation *northwind.AccountValidationResponse `json:"validation"`
+	// NameMatch is how the holder name compared with NorthWind's; see models.NorthwindExternalAccount
+	NameMatch string `json:"name_match,omitempty"`
+	// AlreadyRegistered is set when the account was already registered and validated, in which
+	// case it is returned unchanged
+	AlreadyRegistered bool `json:"already_registered,omitempty"`
 }
 
 // ValidateAndRegister validates an external account with NorthWind and stores it locally
 func (s *NorthwindAccountService) ValidateAndRegister(ctx context.Context, userID uuid.UUID, req ValidateAndRegisterRequest) (*ValidateAndRegisterResponse, error) {
+	holderName, err := sanitizeAccountHolderName("account_holder_name", req.AccountHolderName)
+	if err != nil {
+		return nil, err
+	}
+	req.AccountHolderName = holderName
+	if req.Nickname, err = sanitizeNickname("nickname", req.Nickname); err != nil {
+		return nil, err
+	}
+
 	// Check if already registered
-	existing, err := s.repo.FindByAccountAndRouting(userID, req.AccountNumber, req.RoutingNumber)
+	existing, err := s.repo.FindByAccountAndRouting(ctx, userID, req.AccountNumber, req.RoutingNumber)
 	if err == nil && existing != nil {
 		if existing.Validated {
 			return &ValidateAndRegisterResponse{
@@ -67,15 +98,13 @@ func (s *NorthwindAccountService) ValidateAndRegister(ctx context.Context, userI
 					RoutingNumber: existing.RoutingNumber,
 					Message:       "Account already registered and validated",
 				},
+				AlreadyRegistered: true,
 			}, nil
 		}
 	}
 
 	// Call NorthWind to validate
-	validationResp, err := s.client.ValidateAccount(ctx, northwind.AccountValidationRequest{
-		AccountNumber: req.AccountNumber,
-		RoutingNumber: req.RoutingNumber,
-	})
+	validationResp, err := s.validateAccount(ctx, req.AccountNumber, req.RoutingNumber)
 	if err != nil {
 		s.logger.Error("NorthWind account validation failed", "error", err, "account_number", req.AccountNumber)
 		return nil, fmt.Errorf("northwind validation error: %w", err)
@@ -87,6 +116,12 @@ func (s *NorthwindAccountService) ValidateAndRegister(ctx context.Context, userI
 		}, ErrExternalAccountValidationFailed
 	}
 
+	// The name NorthWind has on file is not echoed back when the names are too far apart
+	nameMatch, err := s.checkHolderName(userID, req, validationResp)
+	if err != nil {
+		return &ValidateAndRegisterResponse{NameMatch: nameMatch}, err
+	}
+
 	// Upsert: if we found an existing unvalidated record, update it
 	now := time.Now()
 	if existing != nil {
@@ -99,12 +134,17 @@ func (s *NorthwindAccountService) ValidateAndRegister(ctx context.Context, userI
 		if validationResp.InstitutionName != "" {
 			existing.InstitutionName = &validationResp.InstitutionName
 		}
-		if err := s.repo.Update(existing); err != nil {
+		if req.Nickname != "" {
+			existing.Nickname = &req.Nickname
+		}
+		markNameMatch(existing, nameMatch, validationResp.AccountHolderName)
+		if err := s.repo.Update(ctx, existing); err != nil {
 			return nil, fmt.Errorf("failed to update external account: %w", err)
 		}
 		return &ValidateAndRegisterResponse{
 			Account:    existing,
 			Validation: validationResp,
+			NameMatch:  nameMatch,
 		}, nil
 	}
 
@@ -117,6 +157,10 @@ func (s *NorthwindAccountService) ValidateAndRegister(ctx context.Context, userI
 	if institutionName != "" {
 		instPtr = &institutionName
 	}
+	var nickname *string
+	if req.Nickname != "" {
+		nickname = &req.Nickname
+	}
 
 	account := &models.NorthwindExternalAccount{
 		UserID:            &userID,
@@ -124,11 +168,13 @@ func (s *NorthwindAccountService) ValidateAndRegister(ctx context.Context, userI
 		AccountNumber:     req.AccountNumber,
 		RoutingNumber:     req.RoutingNumber,
 		InstitutionName:   instPtr,
+		Nickname:          nickname,
 		Validated:         true,
 		ValidationTime:    &now,
 	}
+	markNameMatch(account, nameMatch, validationResp.AccountHolderName)
 
-	if err := s.repo.Create(account); err != nil {
+	if err := s.repo.Create(ctx, account); err != nil {
 		return nil, fmt.Errorf("failed to create external account: %w", err)
 	}
 
@@ -137,15 +183,57 @@ func (s *NorthwindAccountService) ValidateAndRegister(ctx context.Context, userI
 	return &ValidateAndRegisterResponse{
 		Account:    account,
 		Validation: validationResp,
+		NameMatch:  nameMatch,
 	}, nil
 }
 
+// checkHolderName compares the typed holder name with the one NorthWind returned, returning
+// ErrAccountHolderNameMismatch for a major mismatch without an override. The outcome is empty
+// when NorthWind returned no name.
+func (s *NorthwindAccountService) checkHolderName(userID uuid.UUID, req ValidateAndRegisterRequest, validation *northwind.AccountValidationResponse) (string, error) {
+	if validation.AccountHolderName == "" {
+		return "", nil
+	}
+	outcome, score := compareHolderNames(req.AccountHolderName, validation.AccountHolderName, s.nameMatchThreshold)
+	switch {
+	case outcome == NameMatchMajorMismatch && !req.OverrideNameMismatch:
+		s.logger.Warn("External account rejected: holder name does not match NorthWind",
+			"user_id", userID,
+			"similarity", score,
+		)
+		return outcome, ErrAccountHolderNameMismatch
+	case outcome == NameMatchMajorMismatch:
+		s.logger.Warn("External account holder name mismatch overridden by an admin; flagged for review",
+			"user_id", userID,
+			"similarity", score,
+		)
+	case outcome == NameMatchMinorMismatch:
+		s.logger.Info("External account holder name differs slightly from NorthWind; flagged for review",
+			"user_id", userID,
+			"similarity", score,
+		)
+	}
+	return outcome, nil
+}
+
+// markNameMatch records the holder name comparison on account, keeping NorthWind's name and
+// flagging the account for review on any mismatch
+func markNameMatch(account *models.NorthwindExternalAccount, outcome, onFile string) {
+	account.NameMatch = outcome
+	account.NeedsReview = outcome == NameMatchMinorMismatch || outcome == NameMatchMajorMismatch
+	account.ValidatedHolderName = nil
+	if account.NeedsReview {
+		account.ValidatedHolderName = &onFile
+	}
+}
+
 // ListRegisteredAccounts returns the user's registered external accounts
 func (s *NorthwindAccountService) ListRegisteredAccounts(ctx context.Context, userID uuid.UUID, offset, limit int) ([]models.NorthwindExternalAccount, int64, error) {
-	return s.repo.GetByUserID(userID, offset, limit)
+	return s.repo.GetByUserID(ctx, userID, offset, limit)
 }
 
-// ListAccessibleAccounts returns accessible accounts from NorthWind API (passthrough)
-func (s *NorthwindAccountService) ListAccessibleAccounts(ctx context.Context) ([]northwind.ExternalAccount, error) {
-	return s.client.ListAccounts(ctx, 100, 0, "", "")
+// ListAccessibleAccounts returns a page of accessible accounts from NorthWind API (passthrough),
+// with NorthWind's total count when it reports one
+func (s *NorthwindAccountService) ListAccessibleAccounts(ctx context.Context, offset, limit int) (*northwind.AccountListResponse, error) {
+	return s.client.ListAccounts(ctx, limit, offset, "", "")
 }
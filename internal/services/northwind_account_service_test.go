@@ -0,0 +1,278 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/google/uuid"
+)
+
+// fakeAccountValidationAPI answers NorthWind account validations with the current valid flag,
+// counting calls.
+// When gate is set every call waits for it to close; holderName is returned as the name on file.
+type fakeAccountValidationAPI struct {
+	calls      atomic.Int32
+	valid      atomic.Bool
+	gate       chan struct{}
+	holderName string
+}
+
+func (f *fakeAccountValidationAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.calls.Add(1)
+	if f.gate != nil {
+		<-f.gate
+	}
+	var req northwind.AccountValidationRequest
+	_ = json.NewDecoder(r.Body).Decode(&req)
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(northwind.AccountValidationResponse{
+		Valid:             f.valid.Load(),
+		AccountNumber:     req.AccountNumber,
+		RoutingNumber:     req.RoutingNumber,
+		AccountHolderName: f.holderName,
+	})
+}
+
+func newValidationCacheTestService(t *testing.T, api *fakeAccountValidationAPI, logs *bytes.Buffer) *NorthwindAccountService {
+	t.Helper()
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	logger := slog.Default()
+	if logs != nil {
+		logger = slog.New(slog.NewTextHandler(logs, nil))
+	}
+	db := testfactory.NewDB(t)
+	return NewNorthwindAccountService(northwind.NewClient(server.URL, "test-key"), repositories.NewNorthwindExternalAccountRepository(db), logger)
+}
+
+func validationRequest(accountNumber string) ValidateAndRegisterRequest {
+	return ValidateAndRegisterRequest{AccountHolderName: "Jane Doe", AccountNumber: accountNumber, RoutingNumber: "021000021"}
+}
+
+func TestNorthwindAccountService_ValidationCache_Hit(t *testing.T) {
+	api := &fakeAccountValidationAPI{}
+	api.valid.Store(true)
+	var logs bytes.Buffer
+	svc := newValidationCacheTestService(t, api, &logs)
+
+	for i := 0; i < 3; i++ {
+		resp, err := svc.ValidateAndRegister(context.Background(), uuid.New(), validationRequest("123456789"))
+		if err != nil {
+			t.Fatalf("registration %d: unexpected error: %v", i, err)
+		}
+		if !resp.Validation.Valid || resp.Account == nil {
+			t.Fatalf("registration %d: expected a registered account, got %+v", i, resp)
+		}
+	}
+	if got := api.calls.Load(); got != 1 {
+		t.Errorf("expected 1 NorthWind validation, got %d", got)
+	}
+	if hits := strings.Count(logs.String(), "served from cache"); hits != 2 {
+		t.Errorf("expected 2 logged cache hits, got %d:\n%s", hits, logs.String())
+	}
+	if strings.Contains(logs.String(), "123456789") {
+		t.Error("cache hit log contains the full account number")
+	}
+}
+
+func TestNorthwindAccountService_ValidationCache_Miss(t *testing.T) {
+	api := &fakeAccountValidationAPI{}
+	api.valid.Store(true)
+	svc := newValidationCacheTestService(t, api, nil)
+	now := time.Now()
+	svc.validations.now = func() time.Time { return now }
+
+	register := func(accountNumber string) {
+		t.Helper()
+		if _, err := svc.ValidateAndRegister(context.Background(), uuid.New(), validationRequest(accountNumber)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	register("1111111111")
+	register("2222222222")
+	if got := api.calls.Load(); got != 2 {
+		t.Errorf("different accounts: expected 2 validations, got %d", got)
+	}
+
+	now = now.Add(DefaultAccountValidationCacheTTL)
+	register("1111111111")
+	if got := api.calls.Load(); got != 3 {
+		t.Errorf("expired entry: expected 3 validations, got %d", got)
+	}
+
+	svc.SetValidationCacheTTL(0)
+	register("1111111111")
+	register("1111111111")
+	if got := api.calls.Load(); got != 5 {
+		t.Errorf("cache disabled: expected 5 validations, got %d", got)
+	}
+}
+
+func TestNorthwindAccountService_ValidationCache_InvalidResultsAreRechecked(t *testing.T) {
+	api := &fakeAccountValidationAPI{}
+	svc := newValidationCacheTestService(t, api, nil)
+
+	for i := 0; i < 2; i++ {
+		_, err := svc.ValidateAndRegister(context.Background(), uuid.New(), validationRequest("3333333333"))
+		if !errors.Is(err, ErrExternalAccountValidationFailed) {
+			t.Fatalf("attempt %d: expected ErrExternalAccountValidationFailed, got %v", i, err)
+		}
+	}
+	if got := api.calls.Load(); got != 2 {
+		t.Errorf("expected every invalid result to be re-checked, got %d validations", got)
+	}
+
+	// Once NorthWind accepts the account the valid result is cached
+	api.valid.Store(true)
+	for i := 0; i < 2; i++ {
+		if _, err := svc.ValidateAndRegister(context.Background(), uuid.New(), validationRequest("3333333333")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got := api.calls.Load(); got != 3 {
+		t.Errorf("expected 3 validations, got %d", got)
+	}
+}
+
+func TestNorthwindAccountService_ValidationCache_CollapsesConcurrentValidations(t *testing.T) {
+	api := &fakeAccountValidationAPI{gate: make(chan struct{})}
+	api.valid.Store(true)
+	svc := newValidationCacheTestService(t, api, nil)
+	// Without the cache only the in-flight collapse can keep this to one call
+	svc.SetValidationCacheTTL(0)
+
+	const callers = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := svc.validateAccount(context.Background(), "4444444444", "021000021")
+			if err == nil && !resp.Valid {
+				err = errors.New("expected a valid result")
+			}
+			errs <- err
+		}()
+	}
+	// Let every caller join the in-flight validation before NorthWind answers
+	time.Sleep(100 * time.Millisecond)
+	close(api.gate)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if got := api.calls.Load(); got != 1 {
+		t.Errorf("expected concurrent validations to collapse into 1 call, got %d", got)
+	}
+}
+
+func TestNorthwindAccountService_ValidationCache_CallerCancellationDoesNotFailOthers(t *testing.T) {
+	api := &fakeAccountValidationAPI{gate: make(chan struct{})}
+	api.valid.Store(true)
+	svc := newValidationCacheTestService(t, api, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := svc.validateAccount(ctx, "5555555555", "021000021")
+		first <- err
+	}()
+	for api.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	second := make(chan error, 1)
+	go func() {
+		_, err := svc.validateAccount(context.Background(), "5555555555", "021000021")
+		second <- err
+	}()
+
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled caller: expected context.Canceled, got %v", err)
+	}
+	close(api.gate)
+	if err := <-second; err != nil {
+		t.Errorf("other caller: unexpected error: %v", err)
+	}
+	if got := api.calls.Load(); got != 1 {
+		t.Errorf("expected 1 validation, got %d", got)
+	}
+}
+
+func TestNorthwindAccountService_ValidateAndRegister_HolderNameCheck(t *testing.T) {
+	tests := map[string]struct {
+		typed       string
+		onFile      string
+		override    bool
+		wantOutcome string
+		wantErr     error
+	}{
+		"exact":                     {"Jane Doe", "DOE, Jane", false, NameMatchMatch, nil},
+		"initials vs full":          {"J. Smith", "John Smith", false, NameMatchMinorMismatch, nil},
+		"typo":                      {"Jane Doe", "Jane Dow", false, NameMatchMinorMismatch, nil},
+		"completely different":      {"J. Smith", "Robert Jones", false, NameMatchMajorMismatch, ErrAccountHolderNameMismatch},
+		"different, admin override": {"J. Smith", "Robert Jones", true, NameMatchMajorMismatch, nil},
+		"no name from northwind":    {"Jane Doe", "", false, "", nil},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			api := &fakeAccountValidationAPI{holderName: tt.onFile}
+			api.valid.Store(true)
+			svc := newValidationCacheTestService(t, api, nil)
+			userID := uuid.New()
+			req := validationRequest("5555555555")
+			req.AccountHolderName = tt.typed
+			req.OverrideNameMismatch = tt.override
+
+			resp, err := svc.ValidateAndRegister(context.Background(), userID, req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if resp.NameMatch != tt.wantOutcome {
+				t.Errorf("expected outcome %q, got %q", tt.wantOutcome, resp.NameMatch)
+			}
+
+			stored, _, _ := svc.ListRegisteredAccounts(context.Background(), userID, 0, 10)
+			if tt.wantErr != nil {
+				if resp.Validation != nil || len(stored) != 0 {
+					t.Errorf("expected nothing stored or echoed for a rejected name, got %d accounts", len(stored))
+				}
+				return
+			}
+			if len(stored) != 1 {
+				t.Fatalf("expected the account to be stored, got %d", len(stored))
+			}
+			account := stored[0]
+			mismatch := tt.wantOutcome == NameMatchMinorMismatch || tt.wantOutcome == NameMatchMajorMismatch
+			if account.AccountHolderName != tt.typed || account.NameMatch != tt.wantOutcome || account.NeedsReview != mismatch {
+				t.Errorf("expected %q stored with outcome %q and review %v, got %q %q %v",
+					tt.typed, tt.wantOutcome, mismatch, account.AccountHolderName, account.NameMatch, account.NeedsReview)
+			}
+			if mismatch && (account.ValidatedHolderName == nil || *account.ValidatedHolderName != tt.onFile) {
+				t.Errorf("expected NorthWind's name %q kept on a mismatch, got %v", tt.onFile, account.ValidatedHolderName)
+			}
+			if !mismatch && account.ValidatedHolderName != nil {
+				t.Errorf("expected no second name without a mismatch, got %q", *account.ValidatedHolderName)
+			}
+		})
+	}
+}
@@ -0,0 +1,338 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/repositories"
+)
+
+const (
+	// DefaultDashboardTimeout bounds how long the dashboard waits for its slowest section
+	DefaultDashboardTimeout = 3 * time.Second
+	// dashboardConcurrency is how many sections are computed at once
+	dashboardConcurrency = 3
+	// dashboardHealthTTL is how long a NorthWind health check is reused across dashboard loads
+	dashboardHealthTTL = 15 * time.Second
+	// dashboardInitiationWindow is the window of the initiation success rate
+	dashboardInitiationWindow = time.Hour
+	// DashboardRegulatorSLA is how soon after creation a regulator notification should be delivered
+	DashboardRegulatorSLA = 5 * time.Minute
+	// dashboardRegulatorSLAWindow is how far back the delivery SLA is measured
+	dashboardRegulatorSLAWindow = 24 * time.Hour
+	// DashboardRegulatorAbandonAfter is the age past which an undelivered notification is
+	// reported as abandoned: retries go on, but it needs someone to look at it
+	DashboardRegulatorAbandonAfter = 24 * time.Hour
+)
+
+// Error markers of a dashboard section that could not be computed
+const (
+	DashboardSectionTimeout     = "TIMEOUT"
+	DashboardSectionUnavailable = "UNAVAILABLE"
+)
+
+var errSchedulerNotRunning = errors.New("the worker scheduler is not running")
+
+// ScheduledJobStatus is a background job run by the worker scheduler with the outcome of its
+// last run. The run times are nil until the job first runs.
+type ScheduledJobStatus struct {
+	Name           string     `json:"name"`
+	EverySeconds   float64    `json:"every_seconds"`
+	LastRunAt      *time.Time `json:"last_run_at,omitempty"`
+	NextRunAt      *time.Time `json:"next_run_at,omitempty"`
+	LastDurationMs int64      `json:"last_duration_ms"`
+	LastError      string     `json:"last_error,omitempty"`
+	// Paused is set while read-only mode holds the job back
+	Paused bool `json:"paused,omitempty"`
+}
+
+// DashboardSection is one part of the dashboard: its data, or the marker of why it is missing
+type DashboardSection[T any] struct {
+	Data  *T     `json:"data,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// DashboardTransfers counts today's transfers by status and the initiation success rate over
+// the last hour. SuccessRate is nil when nothing was initiated in the window.
+type DashboardTransfers struct {
+	Since         time.Time        `json:"since"`
+	CountsToday   map[string]int64 `json:"counts_today"`
+	WindowSeconds float64          `json:"initiation_window_seconds"`
+	Initiated     int64            `json:"initiated"`
+	Rejected      int64            `json:"rejected"`
+	SuccessRate   *float64         `json:"initiation_success_rate,omitempty"`
+}
+
+// DashboardPolling is the poller's backlog with the age of its oldest transfer
+type DashboardPolling struct {
+	InFlight                int64    `json:"in_flight"`
+	Due                     int64    `json:"due"`
+	OldestPendingAgeSeconds *float64 `json:"oldest_pending_age_seconds,omitempty"`
+}
+
+// DashboardRegulator is regulator notification delivery. SLAPercent is nil when no
+// notification was due in the SLA window.
+type DashboardRegulator struct {
+	Pending             int64    `json:"pending"`
+	Abandoned           int64    `json:"abandoned"`
+	AbandonAfterSeconds float64  `json:"abandon_after_seconds"`
+	SLASeconds          float64  `json:"sla_seconds"`
+	SLAEligible         int64    `json:"sla_eligible"`
+	SLAMet              int64    `json:"sla_met"`
+	SLAPercent          *float64 `json:"sla_percent,omitempty"`
+}
+
+// DashboardNorthwind is NorthWind's health as of CheckedAt and its rate-limit quota. Quota is
+// nil until a NorthWind response has reported it.
+type DashboardNorthwind struct {
+	Healthy   bool                      `json:"healthy"`
+	Health    *northwind.HealthResponse `json:"health,omitempty"`
+	APIKey    northwind.APIKeyID        `json:"api_key"`
+	CheckedAt time.Time                 `json:"checked_at"`
+	Quota     *northwind.Quota          `json:"quota,omitempty"`
+}
+
+// DashboardScheduler lists the worker scheduler's jobs
+type DashboardScheduler struct {
+	Jobs []ScheduledJobStatus `json:"jobs"`
+}
+
+// NorthwindDashboard is the system-wide health of the NorthWind integration
+type NorthwindDashboard struct {
+	GeneratedAt time.Time                            `json:"generated_at"`
+	Transfers   DashboardSection[DashboardTransfers] `json:"transfers"`
+	Polling     DashboardSection[DashboardPolling]   `json:"polling"`
+	Regulator   DashboardSection[DashboardRegulator] `json:"regulator"`
+	Northwind   DashboardSection[DashboardNorthwind] `json:"northwind"`
+	Scheduler   DashboardSection[DashboardScheduler] `json:"scheduler"`
+}
+
+// dashboardJob computes one section. load returns how to store the result, which only the
+// goroutine assembling the dashboard applies, so a section finishing after the deadline never
+// touches a dashboard already returned.
+type dashboardJob struct {
+	name    string
+	load    func(ctx context.Context) func(*NorthwindDashboard)
+	timeout func(*NorthwindDashboard)
+}
+
+// NorthwindDashboardService composes the NorthWind dashboard from the repository aggregates,
+// NorthWind's health and quota and the worker scheduler
+type NorthwindDashboardService struct {
+	client          *northwind.Client
+	transferRepo    repositories.NorthwindTransferRepositoryInterface
+	notifRepo       repositories.RegulatorNotificationRepositoryInterface
+	schedulerStatus func() []ScheduledJobStatus
+	timeout         time.Duration
+	logger          *slog.Logger
+
+	healthMu     sync.Mutex
+	cachedHealth *DashboardNorthwind
+}
+
+// NewNorthwindDashboardService creates a dashboard service
+func NewNorthwindDashboardService(
+	client *northwind.Client,
+	transferRepo repositories.NorthwindTransferRepositoryInterface,
+	notifRepo repositories.RegulatorNotificationRepositoryInterface,
+	logger *slog.Logger,
+) *NorthwindDashboardService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &NorthwindDashboardService{
+		client:       client,
+		transferRepo: transferRepo,
+		notifRepo:    notifRepo,
+		timeout:      DefaultDashboardTimeout,
+		logger:       logger,
+	}
+}
+
+// SetSchedulerStatus sets where the scheduler section reads job statuses from
+func (s *NorthwindDashboardService) SetSchedulerStatus(status func() []ScheduledJobStatus) {
+	s.schedulerStatus = status
+}
+
+// SetTimeout sets how long the dashboard waits for its slowest section
+func (s *NorthwindDashboardService) SetTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		s.timeout = timeout
+	}
+}
+
+// Dashboard computes every section, a few at a time, within the dashboard timeout. A section
+// that fails or is still running at the deadline carries an error marker instead of data; the
+// others are returned as usual.
+func (s *NorthwindDashboardService) Dashboard(ctx context.Context) *NorthwindDashboard {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	now := time.Now()
+	jobs := []dashboardJob{
+		newDashboardJob(s, "transfers", func(d *NorthwindDashboard) *DashboardSection[DashboardTransfers] { return &d.Transfers },
+			func(ctx context.Context) (*DashboardTransfers, error) { return s.transfers(ctx, now) }),
+		newDashboardJob(s, "polling", func(d *NorthwindDashboard) *DashboardSection[DashboardPolling] { return &d.Polling },
+			func(ctx context.Context) (*DashboardPolling, error) { return s.polling(ctx, now) }),
+		newDashboardJob(s, "regulator", func(d *NorthwindDashboard) *DashboardSection[DashboardRegulator] { return &d.Regulator },
+			func(ctx context.Context) (*DashboardRegulator, error) { return s.regulator(ctx, now) }),
+		newDashboardJob(s, "northwind", func(d *NorthwindDashboard) *DashboardSection[DashboardNorthwind] { return &d.Northwind },
+			s.northwind),
+		newDashboardJob(s, "scheduler", func(d *NorthwindDashboard) *DashboardSection[DashboardScheduler] { return &d.Scheduler },
+			s.scheduler),
+	}
+
+	type result struct {
+		index int
+		apply func(*NorthwindDashboard)
+	}
+	results := make(chan result, len(jobs))
+	slots := make(chan struct{}, dashboardConcurrency)
+	for i, job := range jobs {
+		go func() {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-slots }()
+			results <- result{index: i, apply: job.load(ctx)}
+		}()
+	}
+
+	dashboard := &NorthwindDashboard{GeneratedAt: now}
+	done := make([]bool, len(jobs))
+	for range jobs {
+		select {
+		case r := <-results:
+			r.apply(dashboard)
+			done[r.index] = true
+		case <-ctx.Done():
+			for i, job := range jobs {
+				if !done[i] {
+					s.logger.Warn("Dashboard section timed out", "section", job.name)
+					job.timeout(dashboard)
+				}
+			}
+			return dashboard
+		}
+	}
+	return dashboard
+}
+
+func newDashboardJob[T any](s *NorthwindDashboardService, name string, section func(*NorthwindDashboard) *DashboardSection[T], load func(context.Context) (*T, error)) dashboardJob {
+	return dashboardJob{
+		name: name,
+		load: func(ctx context.Context) func(*NorthwindDashboard) {
+			data, err := load(ctx)
+			marker := ""
+			switch {
+			case err != nil && ctx.Err() != nil:
+				marker = DashboardSectionTimeout
+			case err != nil:
+				marker = DashboardSectionUnavailable
+				s.logger.Error("Dashboard section failed", "section", name, "error", err)
+			}
+			return func(d *NorthwindDashboard) {
+				if marker != "" {
+					section(d).Error = marker
+					return
+				}
+				section(d).Data = data
+			}
+		},
+		timeout: func(d *NorthwindDashboard) { section(d).Error = DashboardSectionTimeout },
+	}
+}
+
+func (s *NorthwindDashboardService) transfers(ctx context.Context, now time.Time) (*DashboardTransfers, error) {
+	today := now.UTC().Truncate(24 * time.Hour)
+	counts, err := s.transferRepo.CountByStatusSince(ctx, today)
+	if err != nil {
+		return nil, err
+	}
+	outcomes, err := s.transferRepo.GetInitiationOutcomes(ctx, now.Add(-dashboardInitiationWindow))
+	if err != nil {
+		return nil, err
+	}
+	section := &DashboardTransfers{
+		Since:         today,
+		CountsToday:   counts,
+		WindowSeconds: dashboardInitiationWindow.Seconds(),
+		Initiated:     outcomes.Initiated,
+		Rejected:      outcomes.Rejected,
+	}
+	if attempted := outcomes.Initiated + outcomes.Rejected; attempted > 0 {
+		rate := float64(outcomes.Initiated) / float64(attempted)
+		section.SuccessRate = &rate
+	}
+	return section, nil
+}
+
+func (s *NorthwindDashboardService) polling(ctx context.Context, now time.Time) (*DashboardPolling, error) {
+	backlog, err := s.transferRepo.GetPollingBacklog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	section := &DashboardPolling{InFlight: backlog.InFlight, Due: backlog.Due}
+	if backlog.OldestCreatedAt != nil {
+		age := now.Sub(*backlog.OldestCreatedAt).Seconds()
+		section.OldestPendingAgeSeconds = &age
+	}
+	return section, nil
+}
+
+func (s *NorthwindDashboardService) regulator(ctx context.Context, now time.Time) (*DashboardRegulator, error) {
+	stats, err := s.notifRepo.GetDeliveryStats(ctx, now.Add(-DashboardRegulatorAbandonAfter), now.Add(-dashboardRegulatorSLAWindow), DashboardRegulatorSLA)
+	if err != nil {
+		return nil, err
+	}
+	section := &DashboardRegulator{
+		Pending:             stats.Pending,
+		Abandoned:           stats.Abandoned,
+		AbandonAfterSeconds: DashboardRegulatorAbandonAfter.Seconds(),
+		SLASeconds:          DashboardRegulatorSLA.Seconds(),
+		SLAEligible:         stats.SLAEligible,
+		SLAMet:              stats.SLAMet,
+	}
+	if stats.SLAEligible > 0 {
+		percent := 100 * float64(stats.SLAMet) / float64(stats.SLAEligible)
+		section.SLAPercent = &percent
+	}
+	return section, nil
+}
+
+// northwind reports NorthWind's health, checking it at most once per dashboardHealthTTL. An
+// unhealthy NorthWind is data, not a failed section; only a check cut short by the dashboard's
+// deadline is not cached.
+func (s *NorthwindDashboardService) northwind(ctx context.Context) (*DashboardNorthwind, error) {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+
+	if s.cachedHealth == nil || time.Since(s.cachedHealth.CheckedAt) >= dashboardHealthTTL {
+		health, err := s.client.Health(ctx)
+		if err != nil && ctx.Err() != nil {
+			return nil, err
+		}
+		if err != nil {
+			s.logger.Warn("NorthWind health check failed", "error", err)
+		}
+		s.cachedHealth = &DashboardNorthwind{Healthy: err == nil, Health: health, CheckedAt: time.Now()}
+	}
+
+	section := *s.cachedHealth
+	section.APIKey = s.client.ActiveAPIKey()
+	section.Quota = s.client.Quota()
+	return &section, nil
+}
+
+func (s *NorthwindDashboardService) scheduler(context.Context) (*DashboardScheduler, error) {
+	if s.schedulerStatus == nil {
+		return nil, errSchedulerNotRunning
+	}
+	return &DashboardScheduler{Jobs: s.schedulerStatus()}, nil
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+)
+
+// failingDeliveryStats is a regulator notification repository whose delivery stats query fails
+type failingDeliveryStats struct {
+	repositories.RegulatorNotificationRepositoryInterface
+}
+
+func (failingDeliveryStats) GetDeliveryStats(context.Context, time.Time, time.Time, time.Duration) (*models.RegulatorDeliveryStats, error) {
+	return nil, errors.New("connection reset")
+}
+
+// dashboardNorthwind serves /health, counting the checks, after delay
+func dashboardNorthwind(t *testing.T, delay time.Duration, checks *atomic.Int32) *northwind.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		checks.Add(1)
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"healthy"}`))
+	}))
+	t.Cleanup(server.Close)
+	return northwind.NewClient(server.URL, "test-key")
+}
+
+func createdAgo(age time.Duration) testfactory.NotificationOption {
+	return func(n *models.RegulatorNotification) {
+		n.CreatedAt = time.Now().Add(-age)
+		n.UpdatedAt = n.CreatedAt
+	}
+}
+
+func TestNorthwindDashboard_ComposesSections(t *testing.T) {
+	db := testfactory.NewDB(t)
+	testfactory.NWTransfer(t, db)
+	testfactory.NWTransfer(t, db)
+	testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusCompleted))
+	// A batch item NorthWind refused is stored FAILED without a NorthWind ID
+	rejected := testfactory.NewNWTransfer(testfactory.WithStatus(models.NWTransferStatusFailed))
+	batch, index := "payroll", 0
+	rejected.ExternalRef, rejected.BatchName, rejected.BatchIndex = nil, &batch, &index
+	if err := repositories.NewNorthwindTransferRepository(db).Create(context.Background(), rejected); err != nil {
+		t.Fatalf("failed to create transfer: %v", err)
+	}
+	testfactory.NWTransfer(t, db, testfactory.WithCreatedAt(time.Now().Add(-48*time.Hour)))
+
+	delivered := testfactory.WithDelivered(1)
+	testfactory.RegulatorNotification(t, db, delivered, createdAgo(time.Hour), func(n *models.RegulatorNotification) {
+		deliveredAt := n.CreatedAt.Add(time.Minute)
+		n.LastAttemptAt = &deliveredAt
+	})
+	testfactory.RegulatorNotification(t, db, createdAgo(2*time.Hour))
+	testfactory.RegulatorNotification(t, db, createdAgo(30*time.Hour))
+
+	var checks atomic.Int32
+	svc := NewNorthwindDashboardService(dashboardNorthwind(t, 0, &checks), repositories.NewNorthwindTransferRepository(db),
+		repositories.NewRegulatorNotificationRepository(db), nil)
+	svc.SetSchedulerStatus(func() []ScheduledJobStatus {
+		return []ScheduledJobStatus{{Name: "northwind_polling", EverySeconds: 5}}
+	})
+
+	dashboard := svc.Dashboard(context.Background())
+
+	transfers := dashboard.Transfers.Data
+	if dashboard.Transfers.Error != "" || transfers == nil {
+		t.Fatalf("expected the transfers section, got error %q", dashboard.Transfers.Error)
+	}
+	wantCounts := map[string]int64{models.NWTransferStatusPending: 2, models.NWTransferStatusCompleted: 1, models.NWTransferStatusFailed: 1}
+	if len(transfers.CountsToday) != len(wantCounts) {
+		t.Errorf("expected today's counts %v, got %v", wantCounts, transfers.CountsToday)
+	}
+	for status, count := range wantCounts {
+		if transfers.CountsToday[status] != count {
+			t.Errorf("expected %d %s transfers today, got %d", count, status, transfers.CountsToday[status])
+		}
+	}
+	if transfers.Initiated != 3 || transfers.Rejected != 1 || transfers.SuccessRate == nil || *transfers.SuccessRate != 0.75 {
+		t.Errorf("expected 3 of 4 initiations accepted, got %+v", transfers)
+	}
+
+	polling := dashboard.Polling.Data
+	if polling == nil || polling.InFlight != 3 || polling.Due != 3 || polling.OldestPendingAgeSeconds == nil ||
+		math.Abs(*polling.OldestPendingAgeSeconds-(48*time.Hour).Seconds()) > 60 {
+		t.Errorf("expected 3 in flight, the oldest 48h old, got %+v", polling)
+	}
+
+	regulator := dashboard.Regulator.Data
+	if regulator == nil || regulator.Pending != 2 || regulator.Abandoned != 1 || regulator.SLAEligible != 2 ||
+		regulator.SLAMet != 1 || regulator.SLAPercent == nil || *regulator.SLAPercent != 50 {
+		t.Errorf("expected 2 pending, 1 abandoned and half delivered within the SLA, got %+v", regulator)
+	}
+
+	nw := dashboard.Northwind.Data
+	if nw == nil || !nw.Healthy || nw.Health.Status != "healthy" || nw.APIKey != northwind.APIKeyPrimary ||
+		nw.Quota == nil || nw.Quota.Remaining != 42 {
+		t.Errorf("expected a healthy NorthWind with its quota, got %+v", nw)
+	}
+
+	if jobs := dashboard.Scheduler.Data; jobs == nil || len(jobs.Jobs) != 1 || jobs.Jobs[0].Name != "northwind_polling" {
+		t.Errorf("expected the scheduler's jobs, got %+v", dashboard.Scheduler)
+	}
+
+	svc.Dashboard(context.Background())
+	if checks.Load() != 1 {
+		t.Errorf("expected the health check to be cached, got %d checks", checks.Load())
+	}
+}
+
+func TestNorthwindDashboard_FailingSectionsCarryMarkers(t *testing.T) {
+	db := testfactory.NewDB(t)
+	testfactory.NWTransfer(t, db)
+
+	var checks atomic.Int32
+	svc := NewNorthwindDashboardService(dashboardNorthwind(t, 5*time.Second, &checks), repositories.NewNorthwindTransferRepository(db),
+		failingDeliveryStats{repositories.NewRegulatorNotificationRepository(db)}, nil)
+	svc.SetTimeout(200 * time.Millisecond)
+
+	start := time.Now()
+	dashboard := svc.Dashboard(context.Background())
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected the slow health check to be cut off at the timeout, took %v", elapsed)
+	}
+
+	if dashboard.Regulator.Error != DashboardSectionUnavailable || dashboard.Regulator.Data != nil {
+		t.Errorf("expected the failing regulator section marked unavailable, got %+v", dashboard.Regulator)
+	}
+	if dashboard.Northwind.Error != DashboardSectionTimeout || dashboard.Northwind.Data != nil {
+		t.Errorf("expected the slow NorthWind section marked timed out, got %+v", dashboard.Northwind)
+	}
+	if dashboard.Scheduler.Error != DashboardSectionUnavailable {
+		t.Errorf("expected the scheduler section unavailable without a scheduler, got %+v", dashboard.Scheduler)
+	}
+	if dashboard.Transfers.Error != "" || dashboard.Transfers.Data == nil || dashboard.Transfers.Data.CountsToday[models.NWTransferStatusPending] != 1 {
+		t.Errorf("expected the transfers section despite the failures, got %+v", dashboard.Transfers)
+	}
+	if dashboard.Polling.Error != "" || dashboard.Polling.Data == nil || dashboard.Polling.Data.InFlight != 1 {
+		t.Errorf("expected the polling section despite the failures, got %+v", dashboard.Polling)
+	}
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// northwindErrorCodes is the built-in table of the error codes NorthWind sets on failed
+// transfers, which are ACH return codes, and of the codes we set on transfers that failed
+// before NorthWind accepted them
+var northwindErrorCodes = map[string]models.NorthwindTransferFailure{
+	"R01": {Message: "There were not enough funds in the account. Add funds and try again.", Category: models.NWFailureCategoryFunds, Retryable: true},
+	"R02": {Message: "The account has been closed.", Category: models.NWFailureCategoryAccount},
+	"R03": {Message: "No account was found with the details provided.", Category: models.NWFailureCategoryAccount},
+	"R04": {Message: "The account number is not valid.", Category: models.NWFailureCategoryAccount},
+	"R07": {Message: "The account holder revoked their authorization for this transfer.", Category: models.NWFailureCategoryAuthorization},
+	"R08": {Message: "The account holder stopped this payment.", Category: models.NWFailureCategoryAuthorization},
+	"R09": {Message: "The funds in the account were not yet available. Try again later.", Category: models.NWFailureCategoryFunds, Retryable: true},
+	"R10": {Message: "The account holder did not authorize this transfer.", Category: models.NWFailureCategoryAuthorization},
+	"R16": {Message: "The account is frozen.", Category: models.NWFailureCategoryAccount},
+	"R20": {Message: "The account does not accept transfers.", Category: models.NWFailureCategoryAccount},
+	"R29": {Message: "The business account holder did not authorize this transfer.", Category: models.NWFailureCategoryAuthorization},
+
+	queuedFailureValidation:   {Message: "The transfer details were not valid.", Category: models.NWFailureCategoryValidation},
+	queuedFailureInsufficient: {Message: "There were not enough funds in the account. Add funds and try again.", Category: models.NWFailureCategoryFunds, Retryable: true},
+	queuedFailureRejected:     {Message: "The transfer was rejected.", Category: models.NWFailureCategoryRejected},
+	batchItemMissingCode:      {Message: "The transfer could not be submitted. Try again.", Category: models.NWFailureCategoryTemporary, Retryable: true},
+}
+
+// unknownNorthwindFailure is what an error code missing from the table means
+var unknownNorthwindFailure = models.NorthwindTransferFailure{
+	Message:  "The transfer could not be completed.",
+	Category: models.NWFailureCategoryUnknown,
+}
+
+// NorthwindErrorCatalog maps the error codes of failed transfers to what they mean for the
+// user: the built-in table with the configured overrides applied. Codes it does not know get a
+// generic entry and are counted, so new NorthWind codes get noticed and added.
+type NorthwindErrorCatalog struct {
+	entries map[string]models.NorthwindTransferFailure
+	unknown *prometheus.CounterVec
+	logger  *slog.Logger
+}
+
+// NewNorthwindErrorCatalog creates a catalog of the built-in table with overrides, as produced
+// by ParseNorthwindErrorCodes, replacing its entries. Unknown codes are counted in reg; a nil
+// reg counts nothing.
+func NewNorthwindErrorCatalog(overrides map[string]models.NorthwindTransferFailure, reg prometheus.Registerer, logger *slog.Logger) *NorthwindErrorCatalog {
+	entries := make(map[string]models.NorthwindTransferFailure, len(northwindErrorCodes)+len(overrides))
+	for code, failure := range northwindErrorCodes {
+		entries[code] = failure
+	}
+	for code, failure := range overrides {
+		entries[code] = failure
+	}
+
+	catalog := &NorthwindErrorCatalog{entries: entries, logger: logger}
+	if reg != nil {
+		catalog.unknown = promauto.With(reg).NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "northwind_unknown_error_codes_total",
+				Help: "Total number of failed NorthWind transfers whose error code is not in the error code table, by code",
+			},
+			[]string{"code"},
+		)
+	}
+	return catalog
+}
+
+// ParseNorthwindErrorCodes parses a semicolon-separated list of code=category|retryable|message
+// entries, e.g. "R01=funds|false|Your bank declined the transfer". An empty message keeps the
+// built-in one, or the generic one for a code the table does not have.
+func ParseNorthwindErrorCodes(raw string) (map[string]models.NorthwindTransferFailure, error) {
+	overrides := make(map[string]models.NorthwindTransferFailure)
+	for _, entry := range strings.Split(raw, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		code, value, ok := strings.Cut(entry, "=")
+		code = strings.TrimSpace(code)
+		parts := strings.SplitN(value, "|", 3)
+		if !ok || code == "" || len(parts) != 3 {
+			return nil, fmt.Errorf("invalid error code entry %q: expected code=category|retryable|message", entry)
+		}
+
+		category := strings.TrimSpace(parts[0])
+		if !slices.Contains(models.NWFailureCategoryValues(), category) {
+			return nil, fmt.Errorf("invalid category %q for error code %s", category, code)
+		}
+		retryable, err := strconv.ParseBool(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid retryable value %q for error code %s", parts[1], code)
+		}
+		message := strings.TrimSpace(parts[2])
+		if message == "" {
+			message = unknownNorthwindFailure.Message
+			if builtIn, ok := northwindErrorCodes[code]; ok {
+				message = builtIn.Message
+			}
+		}
+		overrides[code] = models.NorthwindTransferFailure{Message: message, Category: category, Retryable: retryable}
+	}
+	return overrides, nil
+}
+
+// Lookup returns what code means, and whether the table has it
+func (c *NorthwindErrorCatalog) Lookup(code string) (models.NorthwindTransferFailure, bool) {
+	failure, ok := c.entries[code]
+	if !ok {
+		failure = unknownNorthwindFailure
+	}
+	failure.Code = code
+	return failure, ok
+}
+
+// Describe returns what a FAILED transfer's error code means, for rendering. It is nil for
+// transfers that have not failed or have no error code.
+func (c *NorthwindErrorCatalog) Describe(transfer *models.NorthwindTransfer) *models.NorthwindTransferFailure {
+	if transfer.Status != models.NWTransferStatusFailed || transfer.ErrorCode == nil {
+		return nil
+	}
+	failure, _ := c.Lookup(*transfer.ErrorCode)
+	return &failure
+}
+
+// Classify records on a transfer about to be stored how its error code is classified, once it
+// has failed. A transfer already classified is left alone, so each unknown code is counted
+// once per transfer.
+func (c *NorthwindErrorCatalog) Classify(transfer *models.NorthwindTransfer) {
+	if transfer.Status != models.NWTransferStatusFailed || transfer.ErrorCode == nil || transfer.FailureCategory != nil {
+		return
+	}
+	failure, known := c.Lookup(*transfer.ErrorCode)
+	if !known {
+		c.logger.Warn("NorthWind error code not in the error code table",
+			"transfer_id", transfer.ID,
+			"error_code", failure.Code,
+		)
+		if c.unknown != nil {
+			c.unknown.WithLabelValues(failure.Code).Inc()
+		}
+	}
+	transfer.FailureCategory = &failure.Category
+	transfer.FailureRetryable = &failure.Retryable
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestNorthwindErrorCatalog_KnownCodes(t *testing.T) {
+	catalog := NewNorthwindErrorCatalog(nil, nil, slog.Default())
+
+	tests := []struct {
+		code      string
+		category  string
+		retryable bool
+	}{
+		{"R01", models.NWFailureCategoryFunds, true},
+		{"R03", models.NWFailureCategoryAccount, false},
+		{"R16", models.NWFailureCategoryAccount, false},
+		{"R10", models.NWFailureCategoryAuthorization, false},
+		{queuedFailureValidation, models.NWFailureCategoryValidation, false},
+		{batchItemMissingCode, models.NWFailureCategoryTemporary, true},
+	}
+	for _, tt := range tests {
+		failure, known := catalog.Lookup(tt.code)
+		if !known {
+			t.Errorf("%s: expected a known code", tt.code)
+		}
+		if failure.Code != tt.code || failure.Category != tt.category || failure.Retryable != tt.retryable || failure.Message == "" {
+			t.Errorf("%s: unexpected entry %+v", tt.code, failure)
+		}
+	}
+}
+
+func TestParseNorthwindErrorCodes(t *testing.T) {
+	overrides, err := ParseNorthwindErrorCodes(" R01=funds|false|Your bank declined the transfer ; R99=temporary|true| ;R16=account|false|")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]models.NorthwindTransferFailure{
+		"R01": {Message: "Your bank declined the transfer", Category: models.NWFailureCategoryFunds},
+		"R99": {Message: unknownNorthwindFailure.Message, Category: models.NWFailureCategoryTemporary, Retryable: true},
+		"R16": {Message: northwindErrorCodes["R16"].Message, Category: models.NWFailureCategoryAccount},
+	}
+	if len(overrides) != len(want) {
+		t.Fatalf("expected %d overrides, got %+v", len(want), overrides)
+	}
+	for code, failure := range want {
+		if overrides[code] != failure {
+			t.Errorf("%s: expected %+v, got %+v", code, failure, overrides[code])
+		}
+	}
+
+	if overrides, err := ParseNorthwindErrorCodes(""); err != nil || len(overrides) != 0 {
+		t.Errorf("expected no overrides for an empty value, got %+v, %v", overrides, err)
+	}
+	for _, raw := range []string{"R01", "R01=funds|true", "=funds|true|x", "R01=money|true|x", "R01=funds|maybe|x"} {
+		if _, err := ParseNorthwindErrorCodes(raw); err == nil {
+			t.Errorf("%q: expected an error", raw)
+		}
+	}
+}
+
+func TestNorthwindErrorCatalog_OverridesReplaceBuiltIn(t *testing.T) {
+	overrides, err := ParseNorthwindErrorCodes("R01=funds|false|Your bank declined the transfer;R99=temporary|true|Try again later")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	catalog := NewNorthwindErrorCatalog(overrides, nil, slog.Default())
+
+	failure, known := catalog.Lookup("R01")
+	if !known || failure.Retryable || failure.Message != "Your bank declined the transfer" {
+		t.Errorf("expected the override for R01, got %+v", failure)
+	}
+	failure, known = catalog.Lookup("R99")
+	if !known || !failure.Retryable || failure.Category != models.NWFailureCategoryTemporary {
+		t.Errorf("expected the added R99 entry, got %+v", failure)
+	}
+	if failure, _ := catalog.Lookup("R03"); failure != (models.NorthwindTransferFailure{Code: "R03", Message: northwindErrorCodes["R03"].Message, Category: models.NWFailureCategoryAccount}) {
+		t.Errorf("expected the built-in R03 entry, got %+v", failure)
+	}
+}
+
+func TestNorthwindErrorCatalog_UnknownCodeIsGenericAndCounted(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	catalog := NewNorthwindErrorCatalog(nil, reg, slog.Default())
+
+	failure, known := catalog.Lookup("X42")
+	if known || failure.Category != models.NWFailureCategoryUnknown || failure.Retryable || failure.Message != unknownNorthwindFailure.Message || failure.Code != "X42" {
+		t.Errorf("expected the generic entry, got %+v", failure)
+	}
+	if got := testutil.ToFloat64(catalog.unknown.WithLabelValues("X42")); got != 0 {
+		t.Errorf("expected a lookup alone not to be counted, got %v", got)
+	}
+
+	code := "X42"
+	transfer := &models.NorthwindTransfer{Status: models.NWTransferStatusFailed, ErrorCode: &code}
+	catalog.Classify(transfer)
+	catalog.Classify(transfer)
+	if transfer.FailureCategory == nil || *transfer.FailureCategory != models.NWFailureCategoryUnknown || transfer.FailureRetryable == nil || *transfer.FailureRetryable {
+		t.Errorf("expected the transfer classified unknown and not retryable, got %v %v", transfer.FailureCategory, transfer.FailureRetryable)
+	}
+	if got := testutil.ToFloat64(catalog.unknown.WithLabelValues("X42")); got != 1 {
+		t.Errorf("expected the unknown code counted once, got %v", got)
+	}
+
+	r01 := "R01"
+	catalog.Classify(&models.NorthwindTransfer{Status: models.NWTransferStatusFailed, ErrorCode: &r01})
+	if got := testutil.CollectAndCount(catalog.unknown); got != 1 {
+		t.Errorf("expected only the unknown code counted, got %d series", got)
+	}
+}
+
+func TestNorthwindErrorCatalog_DescribeOnlyFailedTransfers(t *testing.T) {
+	catalog := NewNorthwindErrorCatalog(nil, nil, slog.Default())
+	code := "R01"
+
+	if failure := catalog.Describe(&models.NorthwindTransfer{Status: models.NWTransferStatusCompleted, ErrorCode: &code}); failure != nil {
+		t.Errorf("expected no failure for a completed transfer, got %+v", failure)
+	}
+	if failure := catalog.Describe(&models.NorthwindTransfer{Status: models.NWTransferStatusFailed}); failure != nil {
+		t.Errorf("expected no failure without an error code, got %+v", failure)
+	}
+	failure := catalog.Describe(&models.NorthwindTransfer{Status: models.NWTransferStatusFailed, ErrorCode: &code})
+	if failure == nil || failure.Code != "R01" || !failure.Retryable {
+		t.Errorf("expected the R01 entry, got %+v", failure)
+	}
+}
+
+func TestNorthwindErrorCatalog_AppliedOnFailureAndRendering(t *testing.T) {
+	env := newStateTestEnv(t)
+	defer env.regulatorSvc.Shutdown(context.Background())
+	ctx := context.Background()
+	transfer := testfactory.NWTransfer(t, env.db, testfactory.WithStatus(models.NWTransferStatusProcessing))
+
+	if _, err := env.states.Apply(ctx, transfer.ID, models.NWTransferEventSourceWebhook, &northwind.TransferResponse{Status: "FAILED", ErrorCode: "R16"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stored, err := env.transferRepo.GetByID(ctx, transfer.ID)
+	if err != nil {
+		t.Fatalf("failed to load transfer: %v", err)
+	}
+	if stored.FailureCategory == nil || *stored.FailureCategory != models.NWFailureCategoryAccount || stored.FailureRetryable == nil || *stored.FailureRetryable {
+		t.Errorf("expected the failure stored as a non-retryable account failure, got %v %v", stored.FailureCategory, stored.FailureRetryable)
+	}
+
+	svc := NewNorthwindTransferService(nil, repositories.NewNorthwindTransferRepository(env.db), nil, nil, slog.Default())
+	got, err := svc.GetTransfer(ctx, *transfer.UserID, transfer.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Failure == nil || got.Failure.Code != "R16" || got.Failure.Category != models.NWFailureCategoryAccount || got.Failure.Message != northwindErrorCodes["R16"].Message {
+		t.Errorf("expected the R16 failure rendered, got %+v", got.Failure)
+	}
+}
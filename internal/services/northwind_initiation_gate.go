@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// ErrNWTransferOverloaded is returned for a transfer refused because the initiation gate's
+// queue is full
+var ErrNWTransferOverloaded = errors.New("too many transfer initiations in progress")
+
+// InitiationRetryAfter is how long a client refused with ErrNWTransferOverloaded is told to wait.
+// Slots free up as fast as NorthWind answers, so the wait is short.
+const InitiationRetryAfter = 2 * time.Second
+
+// Initiation gate states, used as the northwind_initiation_gate label
+const (
+	InitiationGateInFlight = "in_flight"
+	InitiationGateQueued   = "queued"
+)
+
+// InitiationGate bounds how many transfer initiations call NorthWind at once. When every slot is
+// taken up to queueSize more initiations wait for one; beyond that they are refused with
+// ErrNWTransferOverloaded, so a slow NorthWind cannot pile up goroutines and connections. A nil
+// gate admits everything.
+type InitiationGate struct {
+	slots chan struct{}
+	queue chan struct{}
+	gauge *prometheus.GaugeVec
+}
+
+// NewInitiationGate creates a gate admitting maxInFlight initiations at once with queueSize
+// waiting. Its in-flight and queued counts are the northwind_initiation_gate gauge, registered
+// with reg.
+func NewInitiationGate(maxInFlight, queueSize int, reg prometheus.Registerer) *InitiationGate {
+	if maxInFlight < 1 {
+		maxInFlight = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+	return &InitiationGate{
+		slots: make(chan struct{}, maxInFlight),
+		queue: make(chan struct{}, queueSize),
+		gauge: promauto.With(reg).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "northwind_initiation_gate",
+				Help: "Transfer initiations calling NorthWind and waiting to, by state",
+			},
+			[]string{"state"},
+		),
+	}
+}
+
+// Acquire takes a slot, waiting in the queue while every slot is taken, and returns the function
+// that gives it back. It fails with ErrNWTransferOverloaded when the queue is full too, or with
+// the context's error when ctx ends while waiting.
+func (g *InitiationGate) Acquire(ctx context.Context) (release func(), err error) {
+	if g == nil {
+		return func() {}, nil
+	}
+	select {
+	case g.slots <- struct{}{}:
+		return g.admit(), nil
+	default:
+	}
+
+	select {
+	case g.queue <- struct{}{}:
+	default:
+		return nil, ErrNWTransferOverloaded
+	}
+	queued := g.gauge.WithLabelValues(InitiationGateQueued)
+	queued.Inc()
+	defer func() {
+		<-g.queue
+		queued.Dec()
+	}()
+
+	select {
+	case g.slots <- struct{}{}:
+		return g.admit(), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// admit counts a taken slot and returns its release function
+func (g *InitiationGate) admit() func() {
+	inFlight := g.gauge.WithLabelValues(InitiationGateInFlight)
+	inFlight.Inc()
+	return func() {
+		inFlight.Dec()
+		<-g.slots
+	}
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func gateCount(gate *InitiationGate, state string) int {
+	return int(testutil.ToFloat64(gate.gauge.WithLabelValues(state)))
+}
+
+// waitForGate waits until the gate has inFlight initiations running and queued waiting
+func waitForGate(t *testing.T, gate *InitiationGate, inFlight, queued int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for gateCount(gate, InitiationGateInFlight) != inFlight || gateCount(gate, InitiationGateQueued) != queued {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d in flight and %d queued, got %d and %d", inFlight, queued,
+				gateCount(gate, InitiationGateInFlight), gateCount(gate, InitiationGateQueued))
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestInitiationGate_ShedsBeyondQueue(t *testing.T) {
+	gate := NewInitiationGate(2, 1, prometheus.NewRegistry())
+	ctx := context.Background()
+
+	var releases []func()
+	for i := 0; i < 2; i++ {
+		release, err := gate.Acquire(ctx)
+		if err != nil {
+			t.Fatalf("expected slot %d, got %v", i, err)
+		}
+		releases = append(releases, release)
+	}
+
+	admitted := make(chan func())
+	go func() {
+		release, err := gate.Acquire(ctx)
+		if err != nil {
+			t.Errorf("expected the queued acquire to get a slot, got %v", err)
+		}
+		admitted <- release
+	}()
+	waitForGate(t, gate, 2, 1)
+
+	if _, err := gate.Acquire(ctx); !errors.Is(err, ErrNWTransferOverloaded) {
+		t.Fatalf("expected ErrNWTransferOverloaded with the queue full, got %v", err)
+	}
+
+	releases[0]()
+	releases[0] = <-admitted
+	waitForGate(t, gate, 2, 0)
+
+	for _, release := range releases {
+		release()
+	}
+	waitForGate(t, gate, 0, 0)
+	release, err := gate.Acquire(ctx)
+	if err != nil {
+		t.Fatalf("expected a slot once load dropped, got %v", err)
+	}
+	release()
+}
+
+func TestInitiationGate_QueuedCallerGivesUp(t *testing.T) {
+	gate := NewInitiationGate(1, 1, prometheus.NewRegistry())
+	release, err := gate.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := gate.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the context's error, got %v", err)
+	}
+	waitForGate(t, gate, 1, 0)
+}
+
+func TestInitiationGate_NilAdmitsEverything(t *testing.T) {
+	var gate *InitiationGate
+	release, err := gate.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	release()
+}
+
+// TestNorthwindTransferService_CreateTransfer_ShedsLoad sends a burst of transfers at a NorthWind
+// that holds every initiation until released, then transfers one at a time once it has drained
+func TestNorthwindTransferService_CreateTransfer_ShedsLoad(t *testing.T) {
+	const maxInFlight, queueSize, burst = 3, 2, 10
+
+	db := testfactory.NewDB(t)
+	// Concurrent creations must share the one in-memory database
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	api := &fakeNorthwindTransferAPI{}
+	hold := make(chan struct{})
+	var concurrent, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fakeEndpoint(r) == fakeEndpointValidate {
+			n := concurrent.Add(1)
+			defer concurrent.Add(-1)
+			for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+			}
+			<-hold
+		}
+		api.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	gate := NewInitiationGate(maxInFlight, queueSize, prometheus.NewRegistry())
+	svc := NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"),
+		repositories.NewNorthwindTransferRepository(db), nil, nil, slog.Default())
+	svc.SetDuplicateWindow(0)
+	svc.SetInitiationGate(gate)
+	userID := uuid.New()
+
+	errs := make([]error, burst)
+	var wg sync.WaitGroup
+	for i := 0; i < burst; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req := newTestTransferRequest(models.NWTransferDirectionOutbound)
+			req.ReferenceNumber = fmt.Sprintf("LOAD-%d", i)
+			_, errs[i] = svc.CreateTransfer(context.Background(), userID, req)
+		}(i)
+	}
+	waitForGate(t, gate, maxInFlight, queueSize)
+	close(hold)
+	wg.Wait()
+
+	shed, created := 0, 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case errors.Is(err, ErrNWTransferOverloaded):
+			shed++
+		default:
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if created != maxInFlight+queueSize || shed != burst-maxInFlight-queueSize {
+		t.Errorf("expected %d created and %d shed, got %d and %d", maxInFlight+queueSize, burst-maxInFlight-queueSize, created, shed)
+	}
+	if p := peak.Load(); p > maxInFlight {
+		t.Errorf("expected at most %d initiations at NorthWind at once, got %d", maxInFlight, p)
+	}
+	if n := api.requests(fakeEndpointInitiate); n != created {
+		t.Errorf("expected shed transfers never to reach NorthWind, got %d initiations for %d created", n, created)
+	}
+	waitForGate(t, gate, 0, 0)
+
+	// Once the burst has drained, transfers are accepted again
+	for i := 0; i < burst; i++ {
+		req := newTestTransferRequest(models.NWTransferDirectionOutbound)
+		req.ReferenceNumber = fmt.Sprintf("AFTER-%d", i)
+		if _, err := svc.CreateTransfer(context.Background(), userID, req); err != nil {
+			t.Errorf("transfer %d after the burst: unexpected error: %v", i, err)
+		}
+	}
+}
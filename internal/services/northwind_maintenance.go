@@ -0,0 +1,74 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrInvalidMaintenanceWindow is returned when a maintenance window does not end after it starts
+var ErrInvalidMaintenanceWindow = errors.New("maintenance window must end after it starts")
+
+// MaintenanceWindow is an announced period during which NorthWind does not accept transfers
+type MaintenanceWindow struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Contains reports whether t falls within the window; the end is exclusive
+func (w MaintenanceWindow) Contains(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End)
+}
+
+// NorthwindMaintenance holds the announced NorthWind maintenance window. While it is open new
+// transfers are queued instead of being sent to NorthWind. The window starts out as configured
+// and can be replaced or cleared at runtime; changes are held in memory and last until the
+// process restarts.
+type NorthwindMaintenance struct {
+	mu     sync.RWMutex
+	window *MaintenanceWindow
+	now    func() time.Time
+}
+
+// NewNorthwindMaintenance creates the maintenance state; nil schedules no window
+func NewNorthwindMaintenance(window *MaintenanceWindow) *NorthwindMaintenance {
+	return &NorthwindMaintenance{window: window, now: time.Now}
+}
+
+// Window returns the scheduled window, open or not, or nil when there is none
+func (m *NorthwindMaintenance) Window() *MaintenanceWindow {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.window == nil {
+		return nil
+	}
+	window := *m.window
+	return &window
+}
+
+// Active returns the window when it is open now
+func (m *NorthwindMaintenance) Active() (MaintenanceWindow, bool) {
+	window := m.Window()
+	if window == nil || !window.Contains(m.now()) {
+		return MaintenanceWindow{}, false
+	}
+	return *window, true
+}
+
+// Set schedules window, replacing any previous one
+func (m *NorthwindMaintenance) Set(window MaintenanceWindow) error {
+	if !window.End.After(window.Start) {
+		return ErrInvalidMaintenanceWindow
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.window = &window
+	return nil
+}
+
+// Clear removes the scheduled window, ending it early if it is open
+func (m *NorthwindMaintenance) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.window = nil
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+)
+
+// DefaultPollAnomalyAlertThreshold is how many unresolved poll anomalies raise an alert
+const DefaultPollAnomalyAlertThreshold = 10
+
+// pollAnomalyReplayBatch bounds how many anomalies one replay reprocesses
+const pollAnomalyReplayBatch = 500
+
+var errPollAnomalyUndecodable = errors.New("quarantined body is still not a transfer status response")
+
+// PollAnomalyReplayReport counts the outcomes of one replay. StillUnresolved anomalies failed the
+// mapping again and stay quarantined; Remaining is how many are unresolved after the replay.
+type PollAnomalyReplayReport struct {
+	Replayed        int   `json:"replayed"`
+	Applied         int   `json:"applied"`
+	Unchanged       int   `json:"unchanged"`
+	Stale           int   `json:"stale"`
+	StillUnresolved int   `json:"still_unresolved"`
+	Remaining       int64 `json:"remaining"`
+}
+
+// PollAnomalyService quarantines NorthWind poll responses our status mapping cannot handle and
+// replays them once it can. An error is logged when the number of unresolved anomalies reaches
+// the alert threshold, and again only after it has dropped back below it.
+type PollAnomalyService struct {
+	repo           repositories.PollAnomalyRepositoryInterface
+	transferRepo   repositories.NorthwindTransferRepositoryInterface
+	states         *TransferStateManager
+	alertThreshold int64
+	alerted        atomic.Bool
+	logger         *slog.Logger
+}
+
+// NewPollAnomalyService creates a poll anomaly service that replays through states
+func NewPollAnomalyService(
+	repo repositories.PollAnomalyRepositoryInterface,
+	transferRepo repositories.NorthwindTransferRepositoryInterface,
+	states *TransferStateManager,
+	logger *slog.Logger,
+) *PollAnomalyService {
+	return &PollAnomalyService{
+		repo:           repo,
+		transferRepo:   transferRepo,
+		states:         states,
+		alertThreshold: DefaultPollAnomalyAlertThreshold,
+		logger:         logger,
+	}
+}
+
+// SetAlertThreshold sets how many unresolved anomalies raise an alert; non-positive values keep
+// DefaultPollAnomalyAlertThreshold
+func (s *PollAnomalyService) SetAlertThreshold(threshold int) {
+	if threshold > 0 {
+		s.alertThreshold = int64(threshold)
+	}
+}
+
+// Quarantine records a poll response for transfer that could not be applied, with NorthWind's
+// request ID when the poll was made with a context from northwind.WithResponseMetadata
+func (s *PollAnomalyService) Quarantine(ctx context.Context, transfer *models.NorthwindTransfer, reason, reportedStatus string, raw []byte) error {
+	anomaly := &models.PollAnomaly{
+		TransferID:        transfer.ID,
+		Reason:            reason,
+		ReportedStatus:    reportedStatus,
+		RawBody:           string(raw),
+		UpstreamRequestID: upstreamRequestID(ctx),
+		TransferVersion:   transfer.Version,
+	}
+	if err := s.repo.Record(ctx, anomaly); err != nil {
+		return err
+	}
+	unresolved, err := s.CountUnresolved(ctx)
+	if err != nil {
+		return err
+	}
+	if unresolved >= s.alertThreshold && s.alerted.CompareAndSwap(false, true) {
+		s.logger.Error("NorthWind poll anomalies reached the alert threshold; check for upstream status changes and replay once the mapping is updated",
+			"unresolved", unresolved,
+			"threshold", s.alertThreshold,
+			"latest_reason", reason,
+			"latest_status", reportedStatus,
+		)
+	}
+	return nil
+}
+
+// CountUnresolved returns how many anomalies are waiting for a replay, re-arming the alert once
+// they drop below the threshold
+func (s *PollAnomalyService) CountUnresolved(ctx context.Context) (int64, error) {
+	unresolved, err := s.repo.CountUnresolved(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if unresolved < s.alertThreshold {
+		s.alerted.Store(false)
+	}
+	return unresolved, nil
+}
+
+// List returns a page of resolved or unresolved anomalies and the total number of them
+func (s *PollAnomalyService) List(ctx context.Context, resolved bool, offset, limit int) ([]models.PollAnomaly, int64, error) {
+	return s.repo.List(ctx, resolved, offset, limit)
+}
+
+// Replay reprocesses up to 500 unresolved anomalies, oldest first, through the transfer state
+// manager. An anomaly the mapping still cannot handle stays quarantined. One whose transfer has
+// changed since it was quarantined is resolved as stale without being applied, so an old status
+// cannot move a transfer backwards.
+func (s *PollAnomalyService) Replay(ctx context.Context) (*PollAnomalyReplayReport, error) {
+	anomalies, err := s.repo.ListUnresolved(ctx, pollAnomalyReplayBatch)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &PollAnomalyReplayReport{}
+	for i := range anomalies {
+		anomaly := &anomalies[i]
+		report.Replayed++
+		resolution, err := s.replay(ctx, anomaly)
+		if errors.Is(err, ErrNWTransferUnknownStatus) || errors.Is(err, errPollAnomalyUndecodable) {
+			report.StillUnresolved++
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to replay poll anomaly %s: %w", anomaly.ID, err)
+		}
+		if err := s.repo.Resolve(ctx, anomaly.ID, resolution); err != nil {
+			return nil, err
+		}
+		switch resolution {
+		case models.PollAnomalyResolutionApplied:
+			report.Applied++
+		case models.PollAnomalyResolutionUnchanged:
+			report.Unchanged++
+		case models.PollAnomalyResolutionStale:
+			report.Stale++
+		}
+	}
+
+	if report.Remaining, err = s.CountUnresolved(ctx); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Replayed NorthWind poll anomalies",
+		"replayed", report.Replayed,
+		"applied", report.Applied,
+		"unchanged", report.Unchanged,
+		"stale", report.Stale,
+		"still_unresolved", report.StillUnresolved,
+	)
+	return report, nil
+}
+
+// replay applies one quarantined response and returns how the anomaly is resolved
+func (s *PollAnomalyService) replay(ctx context.Context, anomaly *models.PollAnomaly) (string, error) {
+	var remote northwind.TransferStatusResponse
+	if err := json.Unmarshal([]byte(anomaly.RawBody), &remote); err != nil {
+		return "", errPollAnomalyUndecodable
+	}
+	if _, ok := northwind.MapStatus(remote.Status); !ok {
+		return "", ErrNWTransferUnknownStatus
+	}
+
+	transfer, err := s.transferRepo.GetByID(ctx, anomaly.TransferID)
+	if errors.Is(err, repositories.ErrNorthwindTransferNotFound) {
+		return models.PollAnomalyResolutionStale, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if transfer.Version != anomaly.TransferVersion {
+		return models.PollAnomalyResolutionStale, nil
+	}
+
+	result, err := s.states.Apply(ctx, anomaly.TransferID, models.NWTransferEventSourceReplay, &remote)
+	if err != nil {
+		return "", err
+	}
+	if result.Applied() {
+		return models.PollAnomalyResolutionApplied, nil
+	}
+	return models.PollAnomalyResolutionUnchanged, nil
+}
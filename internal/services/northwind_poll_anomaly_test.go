@@ -0,0 +1,185 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+)
+
+func newPollAnomalyTestService(t *testing.T, db *gorm.DB, logger *slog.Logger) (*PollAnomalyService, repositories.NorthwindTransferRepositoryInterface) {
+	t.Helper()
+	transferRepo := repositories.NewNorthwindTransferRepository(db)
+	states := NewTransferStateManager(transferRepo, nil, logger)
+	return NewPollAnomalyService(repositories.NewPollAnomalyRepository(db), transferRepo, states, logger), transferRepo
+}
+
+func TestNorthwindPollingService_PollOnce_QuarantinesUnknownStatus(t *testing.T) {
+	db := testfactory.NewDB(t)
+	processing := testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusProcessing))
+	garbled := testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusProcessing))
+
+	nwServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(northwind.RequestIDHeader, "nw-req-"+strings.TrimPrefix(r.URL.Path, "/external/transfers/"))
+		if strings.HasSuffix(r.URL.Path, garbled.NorthwindTransferID.String()) {
+			_, _ = w.Write([]byte(`{"status": "COMPLETED", "amount": "twelve"`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"transfer_id":"` + processing.NorthwindTransferID.String() + `","status":"SETTLING"}`))
+	}))
+	defer nwServer.Close()
+
+	anomalies, transferRepo := newPollAnomalyTestService(t, db, slog.Default())
+	reg := prometheus.NewRegistry()
+	svc := NewNorthwindPollingService(northwind.NewClient(nwServer.URL, "test-key"), transferRepo, nil, 0, slog.Default())
+	svc.SetMetrics(NewNorthwindPollingMetrics(reg))
+	svc.SetPollAnomalies(anomalies)
+
+	svc.PollOnce(context.Background())
+
+	for _, transfer := range []*models.NorthwindTransfer{processing, garbled} {
+		got, err := transferRepo.GetByID(context.Background(), transfer.ID)
+		if err != nil {
+			t.Fatalf("failed to reload transfer: %v", err)
+		}
+		if got.Status != models.NWTransferStatusProcessing || got.Version != transfer.Version {
+			t.Errorf("expected transfer %s to stay PROCESSING at version %d, got %s at %d", transfer.ID, transfer.Version, got.Status, got.Version)
+		}
+		if events, _ := transferRepo.ListEvents(context.Background(), transfer.ID); len(events) != 0 {
+			t.Errorf("expected no status events for a quarantined response, got %+v", events)
+		}
+	}
+
+	stored, total, err := anomalies.List(context.Background(), false, 0, 10)
+	if err != nil || total != 2 {
+		t.Fatalf("expected 2 unresolved anomalies, got %d (%v)", total, err)
+	}
+	byTransfer := map[string]models.PollAnomaly{}
+	for _, anomaly := range stored {
+		byTransfer[anomaly.TransferID.String()] = anomaly
+	}
+	unknown := byTransfer[processing.ID.String()]
+	if unknown.Reason != models.PollAnomalyReasonUnknownStatus || unknown.ReportedStatus != "SETTLING" || !strings.Contains(unknown.RawBody, `"status":"SETTLING"`) {
+		t.Errorf("expected the SETTLING response quarantined as sent, got %+v", unknown)
+	}
+	if unknown.UpstreamRequestID == nil || *unknown.UpstreamRequestID != "nw-req-"+processing.NorthwindTransferID.String() {
+		t.Errorf("expected the poll's NorthWind request ID on the anomaly, got %v", unknown.UpstreamRequestID)
+	}
+	if undecodable := byTransfer[garbled.ID.String()]; undecodable.Reason != models.PollAnomalyReasonUndecodable || !strings.Contains(undecodable.RawBody, "twelve") {
+		t.Errorf("expected the undecodable response quarantined as sent, got %+v", undecodable)
+	}
+
+	if m := gatheredMetric(t, reg, "northwind_poll_anomalies_total", map[string]string{"reason": models.PollAnomalyReasonUnknownStatus}); m == nil || m.GetCounter().GetValue() != 1 {
+		t.Errorf("expected one UNKNOWN_STATUS anomaly counted, got %v", m)
+	}
+	if m := gatheredMetric(t, reg, "northwind_poll_anomalies_unresolved", nil); m == nil || m.GetGauge().GetValue() != 2 {
+		t.Errorf("expected 2 unresolved anomalies reported, got %v", m)
+	}
+}
+
+func TestPollAnomalyService_Quarantine_CountsRepeatsAndAlertsOnce(t *testing.T) {
+	db := testfactory.NewDB(t)
+	var logs bytes.Buffer
+	anomalies, _ := newPollAnomalyTestService(t, db, slog.New(slog.NewTextHandler(&logs, nil)))
+	anomalies.SetAlertThreshold(2)
+	ctx := context.Background()
+	first := testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusProcessing))
+	second := testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusProcessing))
+	raw := []byte(`{"status":"SETTLING"}`)
+
+	for range 2 {
+		if err := anomalies.Quarantine(ctx, first, models.PollAnomalyReasonUnknownStatus, "SETTLING", raw); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	stored, total, _ := anomalies.List(ctx, false, 0, 10)
+	if total != 1 || stored[0].Occurrences != 2 {
+		t.Fatalf("expected the repeat counted on one anomaly, got %d anomalies %+v", total, stored)
+	}
+	if strings.Contains(logs.String(), "alert threshold") {
+		t.Error("expected no alert below the threshold")
+	}
+
+	for range 2 {
+		if err := anomalies.Quarantine(ctx, second, models.PollAnomalyReasonUnknownStatus, "SETTLING", raw); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if alerts := strings.Count(logs.String(), "alert threshold"); alerts != 1 {
+		t.Errorf("expected one alert on reaching the threshold, got %d", alerts)
+	}
+}
+
+func TestPollAnomalyService_Replay(t *testing.T) {
+	db := testfactory.NewDB(t)
+	anomalies, transferRepo := newPollAnomalyTestService(t, db, slog.Default())
+	anomalyRepo := repositories.NewPollAnomalyRepository(db)
+	ctx := context.Background()
+
+	quarantine := func(transfer *models.NorthwindTransfer, status string, version int) {
+		t.Helper()
+		anomaly := &models.PollAnomaly{
+			TransferID:      transfer.ID,
+			Reason:          models.PollAnomalyReasonUnknownStatus,
+			ReportedStatus:  status,
+			RawBody:         `{"status":"` + status + `"}`,
+			TransferVersion: version,
+		}
+		if err := anomalyRepo.Record(ctx, anomaly); err != nil {
+			t.Fatalf("failed to record anomaly: %v", err)
+		}
+	}
+	// Statuses the mapping now knows, as if it had been updated since they were quarantined
+	applied := testfactory.NWTransfer(t, db)
+	quarantine(applied, "processing", applied.Version)
+	unchanged := testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusProcessing))
+	quarantine(unchanged, "PROCESSING", unchanged.Version)
+	// The transfer moved on after this response was quarantined, so it must not move it back
+	stale := testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusProcessing))
+	quarantine(stale, "PENDING", stale.Version-1)
+	unknown := testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusProcessing))
+	quarantine(unknown, "SETTLING", unknown.Version)
+
+	report, err := anomalies.Replay(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := PollAnomalyReplayReport{Replayed: 4, Applied: 1, Unchanged: 1, Stale: 1, StillUnresolved: 1, Remaining: 1}
+	if *report != want {
+		t.Errorf("expected %+v, got %+v", want, *report)
+	}
+
+	got, _ := transferRepo.GetByID(ctx, applied.ID)
+	events, _ := transferRepo.ListEvents(ctx, applied.ID)
+	if got.Status != models.NWTransferStatusProcessing || len(events) != 1 || events[0].Source != models.NWTransferEventSourceReplay {
+		t.Errorf("expected the replay to move the transfer to PROCESSING with a REPLAY event, got %s %+v", got.Status, events)
+	}
+	if got, _ := transferRepo.GetByID(ctx, stale.ID); got.Status != models.NWTransferStatusProcessing {
+		t.Errorf("expected the stale response not to be applied, got %s", got.Status)
+	}
+
+	resolved, total, _ := anomalies.List(ctx, true, 0, 10)
+	if total != 3 {
+		t.Fatalf("expected 3 resolved anomalies, got %d", total)
+	}
+	resolutions := map[string]string{}
+	for _, anomaly := range resolved {
+		resolutions[anomaly.TransferID.String()] = *anomaly.Resolution
+	}
+	if resolutions[applied.ID.String()] != models.PollAnomalyResolutionApplied ||
+		resolutions[unchanged.ID.String()] != models.PollAnomalyResolutionUnchanged ||
+		resolutions[stale.ID.String()] != models.PollAnomalyResolutionStale {
+		t.Errorf("unexpected resolutions: %v", resolutions)
+	}
+}
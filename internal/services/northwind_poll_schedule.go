@@ -0,0 +1,126 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/array/banking-api/internal/config"
+	"github.com/array/banking-api/internal/models"
+)
+
+// pollBackoffDivisor sets how an unchanged transfer's poll interval grows with its age: the
+// interval is its age divided by this, clamped to the profile's min and max interval
+const pollBackoffDivisor = 4
+
+// ErrInvalidPollingProfile is returned when a polling profile override is rejected
+var ErrInvalidPollingProfile = errors.New("invalid polling profile")
+
+// PollingProfileStatus is the effective polling profile for a transfer type, with durations as
+// Go duration strings, and whether it is a runtime override
+type PollingProfileStatus struct {
+	TransferType string `json:"transfer_type"`
+	InitialDelay string `json:"initial_delay"`
+	MinInterval  string `json:"min_interval"`
+	MaxInterval  string `json:"max_interval"`
+	Overridden   bool   `json:"overridden"`
+}
+
+// NorthwindPollSchedule decides when the poller next checks each transfer, from a polling
+// profile per transfer type. Types without a profile are checked on every poll cycle. Runtime
+// overrides take precedence over the configured profiles until cleared; they are held in memory
+// and last until the process restarts.
+type NorthwindPollSchedule struct {
+	mu         sync.RWMutex
+	configured map[string]config.PollingProfile
+	overrides  map[string]config.PollingProfile
+}
+
+// NewNorthwindPollSchedule creates a schedule from the configured profiles; nil polls every
+// transfer on every cycle
+func NewNorthwindPollSchedule(configured map[string]config.PollingProfile) *NorthwindPollSchedule {
+	profiles := make(map[string]config.PollingProfile, len(configured))
+	for transferType, profile := range configured {
+		profiles[transferType] = profile
+	}
+	return &NorthwindPollSchedule{
+		configured: profiles,
+		overrides:  make(map[string]config.PollingProfile),
+	}
+}
+
+// Profile returns the effective polling profile for a transfer type
+func (s *NorthwindPollSchedule) Profile(transferType string) config.PollingProfile {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if profile, ok := s.overrides[transferType]; ok {
+		return profile
+	}
+	return s.configured[transferType]
+}
+
+// InitialPollAt is when a transfer created at now is first checked
+func (s *NorthwindPollSchedule) InitialPollAt(transferType string, now time.Time) time.Time {
+	return now.Add(s.Profile(transferType).InitialDelay)
+}
+
+// NextPollAt is when to check a transfer again after a poll at now. A status change resets the
+// interval to the minimum; otherwise it grows with the transfer's age up to the maximum.
+func (s *NorthwindPollSchedule) NextPollAt(transfer *models.NorthwindTransfer, statusChanged bool, now time.Time) time.Time {
+	profile := s.Profile(transfer.TransferType)
+	if statusChanged {
+		return now.Add(profile.MinInterval)
+	}
+	interval := now.Sub(transfer.CreatedAt) / pollBackoffDivisor
+	if interval < profile.MinInterval {
+		interval = profile.MinInterval
+	}
+	if interval > profile.MaxInterval {
+		interval = profile.MaxInterval
+	}
+	return now.Add(interval)
+}
+
+// SetOverride replaces the polling profile of a transfer type at runtime
+func (s *NorthwindPollSchedule) SetOverride(transferType string, profile config.PollingProfile) error {
+	if !models.IsNWTransferType(transferType) {
+		return fmt.Errorf("%w: unknown transfer type %q", ErrInvalidPollingProfile, transferType)
+	}
+	if !profile.Valid() {
+		return fmt.Errorf("%w: durations must not be negative and min_interval must not exceed max_interval", ErrInvalidPollingProfile)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.overrides[transferType] = profile
+	return nil
+}
+
+// ClearOverride removes a runtime override so the configured profile applies again
+func (s *NorthwindPollSchedule) ClearOverride(transferType string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.overrides, transferType)
+}
+
+// List returns the effective profile of every supported transfer type
+func (s *NorthwindPollSchedule) List() []PollingProfileStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	types := models.NWTransferTypeValues()
+	statuses := make([]PollingProfileStatus, 0, len(types))
+	for _, transferType := range types {
+		profile, overridden := s.overrides[transferType]
+		if !overridden {
+			profile = s.configured[transferType]
+		}
+		statuses = append(statuses, PollingProfileStatus{
+			TransferType: transferType,
+			InitialDelay: profile.InitialDelay.String(),
+			MinInterval:  profile.MinInterval.String(),
+			MaxInterval:  profile.MaxInterval.String(),
+			Overridden:   overridden,
+		})
+	}
+	return statuses
+}
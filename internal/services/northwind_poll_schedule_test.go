@@ -0,0 +1,60 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/config"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/testfactory"
+)
+
+func TestNorthwindPollSchedule_NextPollAt(t *testing.T) {
+	schedule := testPollSchedule()
+	now := time.Now()
+
+	young := testfactory.NewNWTransfer(testfactory.WithTransferType(models.NWTransferTypeACH), testfactory.WithCreatedAt(now.Add(-time.Minute)))
+	if got := schedule.NextPollAt(young, false, now).Sub(now); got != 10*time.Minute {
+		t.Errorf("young ACH transfer: got %v, want the 10m minimum", got)
+	}
+	middle := testfactory.NewNWTransfer(testfactory.WithTransferType(models.NWTransferTypeACH), testfactory.WithCreatedAt(now.Add(-2*time.Hour)))
+	if got := schedule.NextPollAt(middle, false, now).Sub(now); got != 30*time.Minute {
+		t.Errorf("2h old ACH transfer: got %v, want a quarter of its age", got)
+	}
+	old := testfactory.NewNWTransfer(testfactory.WithTransferType(models.NWTransferTypeACH), testfactory.WithCreatedAt(now.Add(-72*time.Hour)))
+	if got := schedule.NextPollAt(old, false, now).Sub(now); got != time.Hour {
+		t.Errorf("old ACH transfer: got %v, want the 1h maximum", got)
+	}
+	if got := schedule.NextPollAt(old, true, now).Sub(now); got != 10*time.Minute {
+		t.Errorf("status change: got %v, want the 10m minimum", got)
+	}
+
+	if err := schedule.SetOverride(models.NWTransferTypeACH, config.PollingProfile{MinInterval: time.Minute, MaxInterval: 2 * time.Minute}); err != nil {
+		t.Fatalf("unexpected override error: %v", err)
+	}
+	if got := schedule.NextPollAt(old, false, now).Sub(now); got != 2*time.Minute {
+		t.Errorf("overridden ACH profile: got %v, want 2m", got)
+	}
+	schedule.ClearOverride(models.NWTransferTypeACH)
+	if got := schedule.NextPollAt(old, false, now).Sub(now); got != time.Hour {
+		t.Errorf("cleared override: got %v, want the configured 1h", got)
+	}
+
+	unknown := testfactory.NewNWTransfer(testfactory.WithTransferType("SWIFT"))
+	if got := schedule.NextPollAt(unknown, false, now); !got.Equal(now) {
+		t.Errorf("type without a profile: got %v, want due immediately", got)
+	}
+	for _, bad := range []struct {
+		transferType string
+		profile      config.PollingProfile
+	}{
+		{"SWIFT", config.PollingProfile{}},
+		{models.NWTransferTypeRTP, config.PollingProfile{MinInterval: time.Minute, MaxInterval: time.Second}},
+		{models.NWTransferTypeRTP, config.PollingProfile{InitialDelay: -time.Second}},
+	} {
+		if err := schedule.SetOverride(bad.transferType, bad.profile); !errors.Is(err, ErrInvalidPollingProfile) {
+			t.Errorf("SetOverride(%s, %+v): expected ErrInvalidPollingProfile, got %v", bad.transferType, bad.profile, err)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// pollBacklogStatuses are the statuses the poller works through
+var pollBacklogStatuses = models.NWTransferPollableStatusValues()
+
+// NorthwindPollingMetrics holds the Prometheus collectors for the NorthWind transfer poller.
+// Labels are limited to transfer statuses and HTTP status codes to keep cardinality bounded.
+// A nil *NorthwindPollingMetrics records nothing.
+type NorthwindPollingMetrics struct {
+	backlog       *prometheus.GaugeVec
+	transitions   *prometheus.CounterVec
+	pollErrors    *prometheus.CounterVec
+	cycleDuration prometheus.Histogram
+	anomalies     *prometheus.CounterVec
+	unresolved    prometheus.Gauge
+}
+
+// NewNorthwindPollingMetrics creates the poller collectors and registers them with reg
+func NewNorthwindPollingMetrics(reg prometheus.Registerer) *NorthwindPollingMetrics {
+	factory := promauto.With(reg)
+	return &NorthwindPollingMetrics{
+		backlog: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "northwind_poll_backlog_transfers",
+				Help: "Number of NorthWind transfers awaiting a terminal status, by status",
+			},
+			[]string{"status"},
+		),
+		transitions: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "northwind_transfer_status_transitions_total",
+				Help: "Total number of NorthWind transfer status changes observed by the poller",
+			},
+			[]string{"from", "to"},
+		),
+		pollErrors: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "northwind_poll_errors_total",
+				Help: "Total number of failed NorthWind transfer status requests, by HTTP status code (\"network\" when no response, \"content_type\" when it is not JSON)",
+			},
+			[]string{"status_code"},
+		),
+		cycleDuration: factory.NewHistogram(
+			prometheus.HistogramOpts{
+				Name:    "northwind_poll_cycle_duration_seconds",
+				Help:    "Duration of one NorthWind transfer poll cycle in seconds",
+				Buckets: prometheus.DefBuckets,
+			},
+		),
+		anomalies: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "northwind_poll_anomalies_total",
+				Help: "Total number of NorthWind poll responses quarantined because they could not be mapped, by reason",
+			},
+			[]string{"reason"},
+		),
+		unresolved: factory.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "northwind_poll_anomalies_unresolved",
+				Help: "Number of quarantined NorthWind poll responses awaiting a replay",
+			},
+		),
+	}
+}
+
+func (m *NorthwindPollingMetrics) setBacklog(counts map[string]int64) {
+	if m == nil {
+		return
+	}
+	for _, status := range pollBacklogStatuses {
+		m.backlog.WithLabelValues(status).Set(float64(counts[status]))
+	}
+}
+
+func (m *NorthwindPollingMetrics) recordTransition(from, to string) {
+	if m == nil {
+		return
+	}
+	m.transitions.WithLabelValues(from, to).Inc()
+}
+
+func (m *NorthwindPollingMetrics) recordPollError(err error) {
+	if m == nil {
+		return
+	}
+	code := "network"
+	var apiErr *northwind.APIError
+	switch {
+	case errors.As(err, &apiErr):
+		code = strconv.Itoa(apiErr.StatusCode)
+	case errors.Is(err, northwind.ErrUnexpectedContentType):
+		code = "content_type"
+	}
+	m.pollErrors.WithLabelValues(code).Inc()
+}
+
+func (m *NorthwindPollingMetrics) observeCycle(duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.cycleDuration.Observe(duration.Seconds())
+}
+
+func (m *NorthwindPollingMetrics) recordAnomaly(reason string) {
+	if m == nil {
+		return
+	}
+	m.anomalies.WithLabelValues(reason).Inc()
+}
+
+func (m *NorthwindPollingMetrics) setUnresolvedAnomalies(count int64) {
+	if m == nil {
+		return
+	}
+	m.unresolved.Set(float64(count))
+}
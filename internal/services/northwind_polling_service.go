@@ -10,6 +10,15 @@ import (
 	"github.com/array/banking-api/internal/repositories"
 )
 
+// pollBatchSize bounds how many transfers one poll cycle checks
+const pollBatchSize = 50
+
+// Defaults for the share of each poll batch reserved for transfers users recently viewed
+const (
+	DefaultPollPriorityShare  = 0.3
+	DefaultPollPriorityWindow = 5 * time.Minute
+)
+
 // NorthwindPollingService periodically polls NorthWind for transfer status updates
 type NorthwindPollingService struct {
 	client       *northwind.Client
@@ -17,6 +26,15 @@ type NorthwindPollingService struct {
 	regulatorSvc *RegulatorService
 	pollInterval time.Duration
 	logger       *slog.Logger
+	metrics      *NorthwindPollingMetrics
+	schedule     *NorthwindPollSchedule
+	states       *TransferStateManager
+	anomalies    *PollAnomalyService
+	// pollableStatuses are the statuses of transfers asked about; local-only ones never reach NorthWind
+	pollableStatuses []string
+	// priorityShare of each batch goes to transfers viewed within priorityWindow
+	priorityShare  float64
+	priorityWindow time.Duration
 }
 
 // NewNorthwindPollingService creates a new polling service
@@ -33,6 +51,52 @@ func NewNorthwindPollingService(
 		regulatorSvc: regulatorSvc,
 		pollInterval: pollInterval,
 		logger:       logger,
+		schedule:     NewNorthwindPollSchedule(nil),
+		states:       NewTransferStateManager(transferRepo, regulatorSvc, logger),
+
+		pollableStatuses: models.NWTransferPollableStatusValues(),
+		priorityShare:    DefaultPollPriorityShare,
+		priorityWindow:   DefaultPollPriorityWindow,
+	}
+}
+
+// SetMetrics enables Prometheus instrumentation of poll cycles; nil disables it
+func (s *NorthwindPollingService) SetMetrics(metrics *NorthwindPollingMetrics) {
+	s.metrics = metrics
+}
+
+// SetPollSchedule sets the per-transfer-type schedule deciding when each transfer is next polled.
+// Without one every in-flight transfer is polled on every cycle.
+func (s *NorthwindPollingService) SetPollSchedule(schedule *NorthwindPollSchedule) {
+	if schedule != nil {
+		s.schedule = schedule
+		s.states.SetPollSchedule(schedule)
+	}
+}
+
+// SetTransferStateManager sets the component status changes are applied through. Share one with
+// the webhook receiver so both paths serialize on the same transfer row.
+func (s *NorthwindPollingService) SetTransferStateManager(states *TransferStateManager) {
+	if states != nil {
+		s.states = states
+	}
+}
+
+// SetPollAnomalies sets where responses the status mapping cannot handle are quarantined. Without
+// one they are only logged; either way the transfer is left as it is.
+func (s *NorthwindPollingService) SetPollAnomalies(anomalies *PollAnomalyService) {
+	s.anomalies = anomalies
+}
+
+// SetPollPriority sets the share of each poll batch, from 0 to 1, reserved for transfers viewed
+// within window, so the transfers users are watching update first. A share of 0 polls strictly
+// oldest first; values outside [0, 1] or a non-positive window keep the defaults.
+func (s *NorthwindPollingService) SetPollPriority(share float64, window time.Duration) {
+	if share >= 0 && share <= 1 {
+		s.priorityShare = share
+	}
+	if window > 0 {
+		s.priorityWindow = window
 	}
 }
 
@@ -55,11 +119,41 @@ func (s *NorthwindPollingService) Start(ctx context.Context) {
 
 // PollOnce runs one transfer status poll cycle. Used by the unified worker scheduler.
 func (s *NorthwindPollingService) PollOnce(ctx context.Context) {
+	start := time.Now()
 	s.pollPendingTransfers(ctx)
+	s.refreshBacklog(ctx)
+	s.metrics.observeCycle(time.Since(start))
+}
+
+// refreshBacklog sets the backlog gauge from the transfers still awaiting a terminal status
+func (s *NorthwindPollingService) refreshBacklog(ctx context.Context) {
+	if s.metrics == nil {
+		return
+	}
+	counts, err := s.transferRepo.CountByStatus(ctx, pollBacklogStatuses...)
+	if err != nil {
+		s.logger.Warn("Failed to count NorthWind transfer backlog", "error", err)
+		return
+	}
+	s.metrics.setBacklog(counts)
+
+	if s.anomalies == nil {
+		return
+	}
+	unresolved, err := s.anomalies.CountUnresolved(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to count NorthWind poll anomalies", "error", err)
+		return
+	}
+	s.metrics.setUnresolvedAnomalies(unresolved)
 }
 
 func (s *NorthwindPollingService) pollPendingTransfers(ctx context.Context) {
-	transfers, err := s.transferRepo.GetPendingTransfers(50)
+	priority := models.NorthwindPollPriority{
+		ViewedSince: time.Now().Add(-s.priorityWindow),
+		Slots:       int(s.priorityShare * pollBatchSize),
+	}
+	transfers, err := s.transferRepo.GetPendingTransfers(ctx, s.pollableStatuses, pollBatchSize, priority)
 	if err != nil {
 		s.logger.Error("Failed to fetch pending NorthWind transfers", "error", err)
 		return
@@ -81,62 +175,93 @@ func (s *NorthwindPollingService) pollPendingTransfers(ctx context.Context) {
 	}
 }
 
+// checkTransferStatus polls one transfer by its ExternalRef, NorthWind's ID for it. A transfer
+// without one was never accepted by NorthWind, or predates the ExternalRef backfill, so asking
+// about it would only get a 404; it is logged and rescheduled instead. The context records the
+// poll's response, so the status event or quarantined response it leads to carries NorthWind's
+// request ID.
 func (s *NorthwindPollingService) checkTransferStatus(ctx context.Context, transfer *models.NorthwindTransfer) {
-	resp, err := s.client.GetTransferStatus(ctx, transfer.NorthwindTransferID.String())
+	if transfer.ExternalRef == nil || *transfer.ExternalRef == "" {
+		s.logger.Warn("Skipping poll of NorthWind transfer without an upstream ID",
+			"transfer_id", transfer.ID,
+			"status", transfer.Status,
+		)
+		s.reschedule(ctx, transfer)
+		return
+	}
+
+	ctx, response := northwind.WithResponseMetadata(ctx)
+	resp, raw, err := s.client.GetTransferStatusRaw(ctx, *transfer.ExternalRef)
+	if err != nil && raw != nil {
+		s.quarantine(ctx, transfer, models.PollAnomalyReasonUndecodable, "", raw)
+		return
+	}
 	if err != nil {
+		// Back off like an unchanged transfer so one NorthWind keeps failing on is not asked
+		// again on every cycle
+		s.metrics.recordPollError(err)
 		s.logger.Warn("Failed to get transfer status from NorthWind",
 			"northwind_id", transfer.NorthwindTransferID,
+			"upstream_request_id", response.RequestID,
 			"error", err,
 		)
+		s.reschedule(ctx, transfer)
 		return
 	}
 
-	newStatus := northwind.MapStatus(resp.Status)
-	if newStatus == transfer.Status {
-		return // No change
-	}
-
-	oldStatus := transfer.Status
-	transfer.Status = newStatus
-
-	// Update optional fields from response
-	transfer.ProcessingDate = northwind.ParseRFC3339Optional(resp.ProcessingDate)
-	transfer.CompletedDate = northwind.ParseRFC3339Optional(resp.CompletedDate)
-	transfer.ExpectedCompletionDate = northwind.ParseRFC3339Optional(resp.ExpectedCompletionDate)
-
-	if resp.ErrorCode != "" {
-		transfer.ErrorCode = &resp.ErrorCode
-	}
-	if resp.ErrorMessage != "" {
-		transfer.ErrorMessage = &resp.ErrorMessage
+	if _, ok := northwind.MapStatus(resp.Status); !ok {
+		s.quarantine(ctx, transfer, models.PollAnomalyReasonUnknownStatus, resp.Status, raw)
+		return
 	}
 
-	if err := s.transferRepo.Update(transfer); err != nil {
+	result, err := s.states.Apply(ctx, transfer.ID, models.NWTransferEventSourcePoller, resp)
+	if err != nil {
 		s.logger.Error("Failed to update transfer status",
 			"transfer_id", transfer.ID,
 			"error", err,
 		)
 		return
 	}
+	if result.Applied() {
+		s.metrics.recordTransition(result.Event.FromStatus, result.Event.ToStatus)
+		return
+	}
+
+	// Unchanged, or already moved on by the webhook receiver; only reschedule in-flight transfers
+	if result.Transfer.IsTerminal() {
+		return
+	}
+	s.reschedule(ctx, result.Transfer)
+}
 
-	s.logger.Info("Transfer status updated",
+// quarantine records a response the status mapping cannot handle and leaves the transfer's status
+// alone, polling it again on its normal schedule
+func (s *NorthwindPollingService) quarantine(ctx context.Context, transfer *models.NorthwindTransfer, reason, reportedStatus string, raw []byte) {
+	s.metrics.recordAnomaly(reason)
+	s.logger.Warn("Quarantined NorthWind poll response",
 		"transfer_id", transfer.ID,
 		"northwind_id", transfer.NorthwindTransferID,
-		"old_status", oldStatus,
-		"new_status", newStatus,
+		"reason", reason,
+		"reported_status", reportedStatus,
 	)
-
-	// If terminal state, trigger regulator notification
-	if newStatus == models.NWTransferStatusCompleted || newStatus == models.NWTransferStatusFailed {
-		s.logger.Info("Transfer reached terminal state, creating regulator notification",
-			"transfer_id", transfer.ID,
-			"status", newStatus,
-		)
-		if err := s.regulatorSvc.CreateAndSendNotification(ctx, transfer, newStatus); err != nil {
-			s.logger.Error("Failed to create regulator notification",
+	if s.anomalies != nil {
+		if err := s.anomalies.Quarantine(ctx, transfer, reason, reportedStatus, raw); err != nil {
+			s.logger.Error("Failed to quarantine NorthWind poll response",
 				"transfer_id", transfer.ID,
 				"error", err,
 			)
 		}
 	}
+	s.reschedule(ctx, transfer)
+}
+
+// reschedule sets when an unchanged or unreachable in-flight transfer is next polled
+func (s *NorthwindPollingService) reschedule(ctx context.Context, transfer *models.NorthwindTransfer) {
+	next := s.schedule.NextPollAt(transfer, false, time.Now())
+	if err := s.transferRepo.SetNextPollAt(ctx, transfer.ID, &next); err != nil {
+		s.logger.Warn("Failed to schedule next transfer poll",
+			"transfer_id", transfer.ID,
+			"error", err,
+		)
+	}
 }
@@ -0,0 +1,424 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/config"
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"gorm.io/gorm"
+)
+
+func TestNorthwindPollingService_PollOnce_TerminalTransferNotifiesRegulator(t *testing.T) {
+	db := testfactory.NewDB(t)
+	transferRepo := repositories.NewNorthwindTransferRepository(db)
+	notifRepo := repositories.NewRegulatorNotificationRepository(db)
+	attemptRepo := repositories.NewRegulatorNotificationAttemptRepository(db)
+
+	completed := testfactory.NWTransfer(t, db)
+	unchanged := testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusProcessing))
+	// Already terminal: must not be polled
+	testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusFailed))
+
+	nwServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := "PROCESSING"
+		if strings.HasSuffix(r.URL.Path, completed.NorthwindTransferID.String()) {
+			status = "COMPLETED"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(northwind.TransferStatusResponse{Status: status})
+	}))
+	defer nwServer.Close()
+
+	webhookCalls := 0
+	regulatorServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		webhookCalls++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer regulatorServer.Close()
+
+	regulatorSvc := NewRegulatorService(regulatorServer.URL, 2, 60, notifRepo, attemptRepo, slog.Default(), regulatorServer.Client())
+	regulatorSvc.StartDeliveryWorkers(1, 10)
+	svc := NewNorthwindPollingService(northwind.NewClient(nwServer.URL, "test-key"), transferRepo, regulatorSvc, 0, slog.Default())
+
+	svc.PollOnce(context.Background())
+	regulatorSvc.Shutdown(context.Background())
+
+	got, err := transferRepo.GetByID(context.Background(), completed.ID)
+	if err != nil {
+		t.Fatalf("failed to reload transfer: %v", err)
+	}
+	if got.Status != models.NWTransferStatusCompleted {
+		t.Errorf("expected COMPLETED, got %s", got.Status)
+	}
+	if got, _ := transferRepo.GetByID(context.Background(), unchanged.ID); got.Status != models.NWTransferStatusProcessing {
+		t.Errorf("expected unchanged transfer to stay PROCESSING, got %s", got.Status)
+	}
+
+	exists, err := notifRepo.ExistsForTransferAndStatus(context.Background(), completed.ID, models.NWTransferStatusCompleted)
+	if err != nil {
+		t.Fatalf("failed to query notifications: %v", err)
+	}
+	if !exists {
+		t.Error("expected regulator notification for completed transfer")
+	}
+	if webhookCalls != 1 {
+		t.Errorf("expected 1 webhook call, got %d", webhookCalls)
+	}
+}
+
+// gatheredMetric returns the sample of family name whose labels include labels, or nil if absent
+func gatheredMetric(t *testing.T, reg *prometheus.Registry, name string, labels map[string]string) *dto.Metric {
+	t.Helper()
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, m := range family.GetMetric() {
+			for _, pair := range m.GetLabel() {
+				if want, ok := labels[pair.GetName()]; ok && want != pair.GetValue() {
+					continue metrics
+				}
+			}
+			return m
+		}
+	}
+	return nil
+}
+
+func TestNorthwindPollingService_PollOnce_RecordsMetrics(t *testing.T) {
+	db := testfactory.NewDB(t)
+	transferRepo := repositories.NewNorthwindTransferRepository(db)
+	notifRepo := repositories.NewRegulatorNotificationRepository(db)
+	attemptRepo := repositories.NewRegulatorNotificationAttemptRepository(db)
+
+	completed := testfactory.NWTransfer(t, db)
+	processing := testfactory.NWTransfer(t, db)
+	missing := testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusProcessing))
+	testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusProcessing))
+
+	nwServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := "PROCESSING"
+		switch {
+		case strings.HasSuffix(r.URL.Path, missing.NorthwindTransferID.String()):
+			w.WriteHeader(http.StatusNotFound)
+			return
+		case strings.HasSuffix(r.URL.Path, completed.NorthwindTransferID.String()):
+			status = "COMPLETED"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(northwind.TransferStatusResponse{Status: status})
+	}))
+	defer nwServer.Close()
+
+	regulatorServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer regulatorServer.Close()
+
+	reg := prometheus.NewRegistry()
+	regulatorSvc := NewRegulatorService(regulatorServer.URL, 2, 60, notifRepo, attemptRepo, slog.Default(), regulatorServer.Client())
+	svc := NewNorthwindPollingService(northwind.NewClient(nwServer.URL, "test-key"), transferRepo, regulatorSvc, 0, slog.Default())
+	svc.SetMetrics(NewNorthwindPollingMetrics(reg))
+
+	svc.PollOnce(context.Background())
+
+	counters := []struct {
+		name   string
+		labels map[string]string
+		want   float64
+	}{
+		{"northwind_transfer_status_transitions_total", map[string]string{"from": "PENDING", "to": "COMPLETED"}, 1},
+		{"northwind_transfer_status_transitions_total", map[string]string{"from": "PENDING", "to": "PROCESSING"}, 1},
+		{"northwind_poll_errors_total", map[string]string{"status_code": "404"}, 1},
+	}
+	for _, c := range counters {
+		m := gatheredMetric(t, reg, c.name, c.labels)
+		if m == nil {
+			t.Errorf("expected series %s%v", c.name, c.labels)
+			continue
+		}
+		if got := m.GetCounter().GetValue(); got != c.want {
+			t.Errorf("%s%v = %v, want %v", c.name, c.labels, got, c.want)
+		}
+	}
+
+	// After the cycle: PENDING is empty, three transfers are PROCESSING
+	gauges := map[string]float64{models.NWTransferStatusPending: 0, models.NWTransferStatusProcessing: 3}
+	for status, want := range gauges {
+		m := gatheredMetric(t, reg, "northwind_poll_backlog_transfers", map[string]string{"status": status})
+		if m == nil {
+			t.Errorf("expected backlog series for %s", status)
+			continue
+		}
+		if got := m.GetGauge().GetValue(); got != want {
+			t.Errorf("backlog{status=%s} = %v, want %v", status, got, want)
+		}
+	}
+
+	m := gatheredMetric(t, reg, "northwind_poll_cycle_duration_seconds", nil)
+	if m == nil || m.GetHistogram().GetSampleCount() != 1 {
+		t.Errorf("expected one poll cycle observation, got %v", m)
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetValue() == processing.ID.String() || label.GetValue() == processing.NorthwindTransferID.String() {
+					t.Errorf("%s carries a transfer ID label", family.GetName())
+				}
+			}
+		}
+	}
+}
+
+// blockingRegulator is a regulator webhook that holds every request until release is closed
+func blockingRegulator(t *testing.T) (server *httptest.Server, calls *atomic.Int32, release func()) {
+	t.Helper()
+	calls = &atomic.Int32{}
+	unblock := make(chan struct{})
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+		calls.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	var once sync.Once
+	release = func() { once.Do(func() { close(unblock) }) }
+	t.Cleanup(func() {
+		release()
+		server.Close()
+	})
+	return server, calls, release
+}
+
+// completingNorthwind reports every transfer as COMPLETED
+func completingNorthwind(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(northwind.TransferStatusResponse{Status: "COMPLETED"})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func countDelivered(t *testing.T, db *gorm.DB) int64 {
+	t.Helper()
+	var delivered int64
+	if err := db.Model(&models.RegulatorNotification{}).Where("delivered = ?", true).Count(&delivered).Error; err != nil {
+		t.Fatalf("failed to count delivered notifications: %v", err)
+	}
+	return delivered
+}
+
+func TestNorthwindPollingService_PollOnce_NotBlockedBySlowRegulator(t *testing.T) {
+	db := testfactory.NewDB(t)
+	transferRepo := repositories.NewNorthwindTransferRepository(db)
+	notifRepo := repositories.NewRegulatorNotificationRepository(db)
+	attemptRepo := repositories.NewRegulatorNotificationAttemptRepository(db)
+	for i := 0; i < 3; i++ {
+		testfactory.NWTransfer(t, db)
+	}
+
+	regulatorServer, calls, release := blockingRegulator(t)
+	regulatorSvc := NewRegulatorService(regulatorServer.URL, 2, 60, notifRepo, attemptRepo, slog.Default(), regulatorServer.Client())
+	regulatorSvc.StartDeliveryWorkers(2, 10)
+	svc := NewNorthwindPollingService(northwind.NewClient(completingNorthwind(t).URL, "test-key"), transferRepo, regulatorSvc, 0, slog.Default())
+
+	start := time.Now()
+	svc.PollOnce(context.Background())
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("poll cycle waited on the regulator: took %v", elapsed)
+	}
+
+	counts, err := transferRepo.CountByStatus(context.Background(), models.NWTransferStatusCompleted)
+	if err != nil {
+		t.Fatalf("failed to count transfers: %v", err)
+	}
+	if counts[models.NWTransferStatusCompleted] != 3 {
+		t.Errorf("expected 3 transfers updated while the regulator was stalled, got %d", counts[models.NWTransferStatusCompleted])
+	}
+	if calls.Load() != 0 {
+		t.Errorf("expected no completed webhook calls yet, got %d", calls.Load())
+	}
+
+	// The retry loop must leave queued notifications to the delivery workers
+	regulatorSvc.RetryOnce(context.Background())
+
+	release()
+	regulatorSvc.Shutdown(context.Background())
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 webhook calls after draining, got %d", calls.Load())
+	}
+	if delivered := countDelivered(t, db); delivered != 3 {
+		t.Errorf("expected 3 delivered notifications, got %d", delivered)
+	}
+}
+
+func TestNorthwindPollingService_PollOnce_QueueOverflowFallsBackToRetryLoop(t *testing.T) {
+	db := testfactory.NewDB(t)
+	transferRepo := repositories.NewNorthwindTransferRepository(db)
+	notifRepo := repositories.NewRegulatorNotificationRepository(db)
+	attemptRepo := repositories.NewRegulatorNotificationAttemptRepository(db)
+	const transfers = 5
+	for i := 0; i < transfers; i++ {
+		testfactory.NWTransfer(t, db)
+	}
+
+	regulatorServer, calls, release := blockingRegulator(t)
+	regulatorSvc := NewRegulatorService(regulatorServer.URL, 2, 60, notifRepo, attemptRepo, slog.Default(), regulatorServer.Client())
+	// One stalled worker and a single queue slot: at most two notifications are accepted
+	regulatorSvc.StartDeliveryWorkers(1, 1)
+	svc := NewNorthwindPollingService(northwind.NewClient(completingNorthwind(t).URL, "test-key"), transferRepo, regulatorSvc, 0, slog.Default())
+
+	svc.PollOnce(context.Background())
+
+	overflowed, err := notifRepo.GetPendingNotifications(context.Background(), transfers)
+	if err != nil {
+		t.Fatalf("failed to fetch due notifications: %v", err)
+	}
+	if len(overflowed) < transfers-2 {
+		t.Fatalf("expected at least %d notifications handed to the retry loop, got %d", transfers-2, len(overflowed))
+	}
+
+	release()
+	regulatorSvc.Shutdown(context.Background())
+	regulatorSvc.RetryOnce(context.Background())
+
+	if delivered := countDelivered(t, db); delivered != transfers {
+		t.Errorf("expected all %d notifications delivered, got %d", transfers, delivered)
+	}
+	if calls.Load() != transfers {
+		t.Errorf("expected %d webhook calls, got %d", transfers, calls.Load())
+	}
+}
+
+func testPollSchedule() *NorthwindPollSchedule {
+	return NewNorthwindPollSchedule(map[string]config.PollingProfile{
+		models.NWTransferTypeRTP:  {InitialDelay: 5 * time.Second, MinInterval: 5 * time.Second, MaxInterval: 30 * time.Second},
+		models.NWTransferTypeWire: {InitialDelay: time.Minute, MinInterval: time.Minute, MaxInterval: 15 * time.Minute},
+		models.NWTransferTypeACH:  {InitialDelay: 10 * time.Minute, MinInterval: 10 * time.Minute, MaxInterval: time.Hour},
+	})
+}
+
+func TestNorthwindPollingService_PollOnce_SchedulesNextPollByTransferType(t *testing.T) {
+	db := testfactory.NewDB(t)
+	transferRepo := repositories.NewNorthwindTransferRepository(db)
+
+	byType := map[string]*models.NorthwindTransfer{}
+	for _, transferType := range []string{models.NWTransferTypeRTP, models.NWTransferTypeWire, models.NWTransferTypeACH} {
+		byType[transferType] = testfactory.NWTransfer(t, db,
+			testfactory.WithTransferType(transferType),
+			testfactory.WithStatus(models.NWTransferStatusProcessing))
+	}
+
+	var polls atomic.Int32
+	nwServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(northwind.TransferStatusResponse{Status: "PROCESSING"})
+	}))
+	defer nwServer.Close()
+
+	svc := NewNorthwindPollingService(northwind.NewClient(nwServer.URL, "test-key"), transferRepo, nil, 0, slog.Default())
+	svc.SetPollSchedule(testPollSchedule())
+
+	before := time.Now()
+	svc.PollOnce(context.Background())
+
+	want := map[string]time.Duration{
+		models.NWTransferTypeRTP:  5 * time.Second,
+		models.NWTransferTypeWire: time.Minute,
+		models.NWTransferTypeACH:  10 * time.Minute,
+	}
+	for transferType, transfer := range byType {
+		got, err := transferRepo.GetByID(context.Background(), transfer.ID)
+		if err != nil {
+			t.Fatalf("failed to reload %s transfer: %v", transferType, err)
+		}
+		if got.NextPollAt == nil {
+			t.Errorf("%s: expected next_poll_at to be scheduled", transferType)
+			continue
+		}
+		if delay := got.NextPollAt.Sub(before); delay < want[transferType] || delay > want[transferType]+5*time.Second {
+			t.Errorf("%s: next poll in %v, want about %v", transferType, delay, want[transferType])
+		}
+		if got.Version != transfer.Version {
+			t.Errorf("%s: rescheduling bumped version from %d to %d", transferType, transfer.Version, got.Version)
+		}
+	}
+
+	// Nothing is due again yet, so a second cycle makes no NorthWind calls
+	svc.PollOnce(context.Background())
+	if polls.Load() != 3 {
+		t.Errorf("expected 3 status polls, got %d", polls.Load())
+	}
+}
+
+func TestNorthwindPollingService_PollOnce_SkipsTransfersUnknownToNorthwind(t *testing.T) {
+	db := testfactory.NewDB(t)
+	transferRepo := repositories.NewNorthwindTransferRepository(db)
+
+	polled := testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusProcessing))
+	queued := testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusInitiationPending))
+	unlinked := testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusPending))
+	if err := db.Model(unlinked).UpdateColumn("external_ref", nil).Error; err != nil {
+		t.Fatalf("failed to clear external ref: %v", err)
+	}
+
+	var mu sync.Mutex
+	var paths []string
+	nwServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(northwind.TransferStatusResponse{Status: "PROCESSING"})
+	}))
+	defer nwServer.Close()
+
+	svc := NewNorthwindPollingService(northwind.NewClient(nwServer.URL, "test-key"), transferRepo, nil, 0, slog.Default())
+	svc.SetPollSchedule(testPollSchedule())
+	svc.PollOnce(context.Background())
+
+	if len(paths) != 1 || !strings.HasSuffix(paths[0], *polled.ExternalRef) {
+		t.Fatalf("expected only the transfer NorthWind knows to be polled, got %v", paths)
+	}
+	got, err := transferRepo.GetByID(context.Background(), queued.ID)
+	if err != nil {
+		t.Fatalf("failed to reload queued transfer: %v", err)
+	}
+	if got.Status != models.NWTransferStatusInitiationPending {
+		t.Errorf("expected the queued transfer untouched, got %s", got.Status)
+	}
+	// The unlinked transfer is rescheduled rather than picked up again on every cycle
+	got, err = transferRepo.GetByID(context.Background(), unlinked.ID)
+	if err != nil {
+		t.Fatalf("failed to reload unlinked transfer: %v", err)
+	}
+	if got.NextPollAt == nil || !got.NextPollAt.After(time.Now()) {
+		t.Errorf("expected the unlinked transfer rescheduled, got next poll %v", got.NextPollAt)
+	}
+}
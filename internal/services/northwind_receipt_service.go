@@ -0,0 +1,289 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/pdf"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// ErrNWReceiptUnavailable is returned when a receipt is requested for a transfer that has not settled
+var ErrNWReceiptUnavailable = errors.New("receipts are only available for completed or reversed transfers")
+
+// receiptHashVersion prefixes the canonical field string so the hashed fields can change later
+// without invalidating receipts already issued
+const receiptHashVersion = "v1"
+
+// maxReceiptDescriptionLen keeps the description on a single receipt line
+const maxReceiptDescriptionLen = 60
+
+//go:embed templates/transfer_receipt.tmpl
+var receiptTemplates embed.FS
+
+// Receipt is a rendered transfer receipt
+type Receipt struct {
+	Filename         string
+	PDF              []byte
+	VerificationHash string
+}
+
+// ReceiptVerification is the result of checking a verification hash quoted from a receipt.
+// ReceiptStatus is the status printed on the matching receipt, which differs from CurrentStatus
+// when a transfer was reversed after its receipt was issued.
+type ReceiptVerification struct {
+	TransferID      uuid.UUID `json:"transfer_id"`
+	ReferenceNumber string    `json:"reference_number"`
+	Valid           bool      `json:"valid"`
+	ReceiptStatus   string    `json:"receipt_status,omitempty"`
+	CurrentStatus   string    `json:"current_status"`
+}
+
+// receiptData is the template input; every value is already formatted for display
+type receiptData struct {
+	IssuedAt            string
+	TransferID          string
+	NorthwindTransferID string
+	ReferenceNumber     string
+	Status              string
+	Direction           string
+	TransferType        string
+	Description         string
+	Amount              string
+	Fee                 string
+	Currency            string
+	SourceAccount       string
+	DestinationAccount  string
+	CreatedAt           string
+	InitiatedAt         string
+	CompletedAt         string
+	VerificationHash    string
+}
+
+// ReceiptService renders PDF receipts for settled NorthWind transfers and verifies the HMAC
+// printed on them. Layout lives in templates/transfer_receipt.tmpl.
+type ReceiptService struct {
+	transferRepo repositories.NorthwindTransferRepositoryInterface
+	signingKey   []byte
+	template     *template.Template
+	now          func() time.Time
+}
+
+// NewReceiptService creates a receipt service. signingKey is the HMAC key for verification
+// hashes; rotating it invalidates every receipt issued before.
+func NewReceiptService(transferRepo repositories.NorthwindTransferRepositoryInterface, signingKey []byte) *ReceiptService {
+	return &ReceiptService{
+		transferRepo: transferRepo,
+		signingKey:   signingKey,
+		template:     template.Must(template.ParseFS(receiptTemplates, "templates/transfer_receipt.tmpl")),
+		now:          time.Now,
+	}
+}
+
+// TransferReceipt renders the receipt for one of the user's COMPLETED or REVERSED transfers
+func (s *ReceiptService) TransferReceipt(ctx context.Context, userID, transferID uuid.UUID) (*Receipt, error) {
+	transfer, err := s.getTransfer(ctx, transferID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.UserID == nil || *transfer.UserID != userID {
+		return nil, ErrNWTransferNotFound
+	}
+	if transfer.Status != models.NWTransferStatusCompleted && transfer.Status != models.NWTransferStatusReversed {
+		return nil, ErrNWReceiptUnavailable
+	}
+
+	data := s.receiptData(transfer)
+	var layout bytes.Buffer
+	if err := s.template.Execute(&layout, data); err != nil {
+		return nil, fmt.Errorf("failed to render receipt template: %w", err)
+	}
+	doc, err := renderReceiptLayout(layout.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Receipt{
+		Filename:         "receipt-" + transfer.ReferenceNumber + ".pdf",
+		PDF:              doc.Bytes(),
+		VerificationHash: data.VerificationHash,
+	}, nil
+}
+
+// VerifyReceipt reports whether hash is the verification hash of a receipt issued for the
+// transfer. A receipt issued while the transfer was COMPLETED still verifies after a reversal.
+func (s *ReceiptService) VerifyReceipt(ctx context.Context, transferID uuid.UUID, hash string) (*ReceiptVerification, error) {
+	transfer, err := s.getTransfer(ctx, transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReceiptVerification{
+		TransferID:      transfer.ID,
+		ReferenceNumber: transfer.ReferenceNumber,
+		CurrentStatus:   transfer.Status,
+	}
+	candidates := []string{transfer.Status}
+	if transfer.Status == models.NWTransferStatusReversed {
+		candidates = append(candidates, models.NWTransferStatusCompleted)
+	}
+	given, err := hex.DecodeString(strings.TrimSpace(hash))
+	if err != nil {
+		return result, nil
+	}
+	for _, status := range candidates {
+		if hmac.Equal(given, s.sign(transfer, status)) {
+			result.Valid = true
+			result.ReceiptStatus = status
+			break
+		}
+	}
+	return result, nil
+}
+
+func (s *ReceiptService) getTransfer(ctx context.Context, transferID uuid.UUID) (*models.NorthwindTransfer, error) {
+	transfer, err := s.transferRepo.GetByID(ctx, transferID)
+	if errors.Is(err, repositories.ErrNorthwindTransferNotFound) {
+		return nil, ErrNWTransferNotFound
+	}
+	return transfer, err
+}
+
+func (s *ReceiptService) receiptData(t *models.NorthwindTransfer) receiptData {
+	data := receiptData{
+		IssuedAt:            formatReceiptTime(s.now()),
+		TransferID:          t.ID.String(),
+		NorthwindTransferID: t.NorthwindTransferID.String(),
+		ReferenceNumber:     t.ReferenceNumber,
+		Status:              t.Status,
+		Direction:           t.Direction,
+		TransferType:        t.TransferType,
+		Amount:              t.Amount.StringFixed(2),
+		Fee:                 receiptFee(t).StringFixed(2),
+		Currency:            t.Currency,
+		SourceAccount:       maskAccountNumber(t.SourceAccountNumber),
+		DestinationAccount:  maskAccountNumber(t.DestinationAccountNumber),
+		CreatedAt:           formatReceiptTime(t.CreatedAt),
+		VerificationHash:    hex.EncodeToString(s.sign(t, t.Status)),
+	}
+	if t.Description != nil {
+		data.Description = truncateReceiptText(*t.Description, maxReceiptDescriptionLen)
+	}
+	if t.InitiatedDate != nil {
+		data.InitiatedAt = formatReceiptTime(*t.InitiatedDate)
+	}
+	if t.CompletedDate != nil {
+		data.CompletedAt = formatReceiptTime(*t.CompletedDate)
+	}
+	return data
+}
+
+// sign computes the HMAC-SHA256 of the transfer's canonical receipt fields with the given status
+func (s *ReceiptService) sign(t *models.NorthwindTransfer, status string) []byte {
+	var completedAt string
+	if t.CompletedDate != nil {
+		completedAt = formatReceiptTime(*t.CompletedDate)
+	}
+	fields := []string{
+		receiptHashVersion,
+		t.ID.String(),
+		t.NorthwindTransferID.String(),
+		t.ReferenceNumber,
+		status,
+		t.Direction,
+		t.TransferType,
+		t.Amount.StringFixed(2),
+		receiptFee(t).StringFixed(2),
+		t.Currency,
+		maskAccountNumber(t.SourceAccountNumber),
+		maskAccountNumber(t.DestinationAccountNumber),
+		formatReceiptTime(t.CreatedAt),
+		completedAt,
+	}
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(strings.Join(fields, "\n")))
+	return mac.Sum(nil)
+}
+
+func receiptFee(t *models.NorthwindTransfer) decimal.Decimal {
+	if t.Fee == nil {
+		return decimal.Zero
+	}
+	return *t.Fee
+}
+
+// formatReceiptTime formats to whole seconds so the hash survives database timestamp precision
+func formatReceiptTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func truncateReceiptText(s string, max int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if runes := []rune(s); len(runes) > max {
+		return string(runes[:max-3]) + "..."
+	}
+	return s
+}
+
+// Receipt page geometry, in points
+const (
+	receiptMargin     = 72
+	receiptValueX     = 240
+	receiptLineHeight = 16
+)
+
+// renderReceiptLayout lays out the directive lines produced by the receipt template top-down
+// on a single page
+func renderReceiptLayout(layout string) (*pdf.Document, error) {
+	doc := pdf.New()
+	page := doc.AddPage()
+	y := float64(pdf.PageHeight - receiptMargin)
+
+	scanner := bufio.NewScanner(strings.NewReader(layout))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			y -= receiptLineHeight / 2
+			continue
+		}
+		directive, content, _ := strings.Cut(line, " ")
+		switch directive {
+		case "title":
+			page.Text(pdf.HelveticaBold, 20, receiptMargin, y, content)
+			y -= receiptLineHeight * 2
+		case "heading":
+			page.Text(pdf.HelveticaBold, 12, receiptMargin, y, content)
+			y -= receiptLineHeight
+		case "field":
+			label, value, _ := strings.Cut(content, "|")
+			page.Text(pdf.Helvetica, 10, receiptMargin, y, label)
+			page.Text(pdf.Helvetica, 10, receiptValueX, y, value)
+			y -= receiptLineHeight
+		case "text":
+			page.Text(pdf.Helvetica, 10, receiptMargin, y, content)
+			y -= receiptLineHeight
+		case "mono":
+			page.Text(pdf.Courier, 9, receiptMargin, y, content)
+			y -= receiptLineHeight
+		case "rule":
+			page.Line(0.5, receiptMargin, y+receiptLineHeight/2, pdf.PageWidth-receiptMargin, y+receiptLineHeight/2)
+			y -= receiptLineHeight / 2
+		default:
+			return nil, fmt.Errorf("unknown receipt layout directive %q", directive)
+		}
+	}
+	return doc, nil
+}
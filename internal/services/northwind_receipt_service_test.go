@@ -0,0 +1,162 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestReceiptService_TransferReceipt(t *testing.T) {
+	db := testfactory.NewDB(t)
+	completedAt := time.Date(2026, 3, 2, 15, 4, 5, 0, time.UTC)
+	transfer := testfactory.NWTransfer(t, db,
+		testfactory.WithStatus(models.NWTransferStatusCompleted),
+		func(tr *models.NorthwindTransfer) {
+			fee := decimal.NewFromFloat(1.25)
+			description := "Rent (March)"
+			tr.Fee = &fee
+			tr.Description = &description
+			tr.CompletedDate = &completedAt
+		})
+
+	svc := NewReceiptService(repositories.NewNorthwindTransferRepository(db), []byte("test-signing-key"))
+	receipt, err := svc.TransferReceipt(context.Background(), *transfer.UserID, transfer.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !bytes.HasPrefix(receipt.PDF, []byte("%PDF-")) {
+		t.Fatalf("receipt is not a PDF")
+	}
+	if receipt.Filename != "receipt-"+transfer.ReferenceNumber+".pdf" {
+		t.Errorf("unexpected filename %q", receipt.Filename)
+	}
+	for _, want := range []string{
+		"(Transfer Receipt)",
+		"(" + transfer.ReferenceNumber + ")",
+		"(" + transfer.NorthwindTransferID.String() + ")",
+		"(100.50 USD)",
+		"(1.25 USD)",
+		"(****1111)",
+		"(****2222)",
+		`(Rent \(March\))`,
+		"(2026-03-02T15:04:05Z)",
+		"(" + receipt.VerificationHash + ")",
+	} {
+		if !bytes.Contains(receipt.PDF, []byte(want)) {
+			t.Errorf("receipt content missing %q", want)
+		}
+	}
+	for _, full := range []string{"1111111111", "2222222222"} {
+		if bytes.Contains(receipt.PDF, []byte(full)) {
+			t.Errorf("receipt contains unmasked account number %s", full)
+		}
+	}
+
+	// The hash does not depend on when the receipt was issued
+	svc.now = func() time.Time { return time.Now().Add(time.Hour) }
+	again, err := svc.TransferReceipt(context.Background(), *transfer.UserID, transfer.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again.VerificationHash != receipt.VerificationHash {
+		t.Errorf("verification hash changed between renders")
+	}
+}
+
+func TestReceiptService_TransferReceipt_Rejected(t *testing.T) {
+	db := testfactory.NewDB(t)
+	svc := NewReceiptService(repositories.NewNorthwindTransferRepository(db), []byte("test-signing-key"))
+
+	for _, status := range []string{
+		models.NWTransferStatusPending,
+		models.NWTransferStatusProcessing,
+		models.NWTransferStatusFailed,
+		models.NWTransferStatusCancelled,
+	} {
+		transfer := testfactory.NWTransfer(t, db, testfactory.WithStatus(status))
+		if _, err := svc.TransferReceipt(context.Background(), *transfer.UserID, transfer.ID); !errors.Is(err, ErrNWReceiptUnavailable) {
+			t.Errorf("%s: expected ErrNWReceiptUnavailable, got %v", status, err)
+		}
+	}
+
+	completed := testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusCompleted))
+	if _, err := svc.TransferReceipt(context.Background(), uuid.New(), completed.ID); !errors.Is(err, ErrNWTransferNotFound) {
+		t.Errorf("other user: expected ErrNWTransferNotFound, got %v", err)
+	}
+	if _, err := svc.TransferReceipt(context.Background(), *completed.UserID, uuid.New()); !errors.Is(err, ErrNWTransferNotFound) {
+		t.Errorf("unknown transfer: expected ErrNWTransferNotFound, got %v", err)
+	}
+	ownerless := testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusCompleted), func(tr *models.NorthwindTransfer) {
+		tr.UserID = nil
+	})
+	if _, err := svc.TransferReceipt(context.Background(), uuid.New(), ownerless.ID); !errors.Is(err, ErrNWTransferNotFound) {
+		t.Errorf("ownerless transfer: expected ErrNWTransferNotFound, got %v", err)
+	}
+}
+
+func TestReceiptService_VerifyReceipt(t *testing.T) {
+	db := testfactory.NewDB(t)
+	transferRepo := repositories.NewNorthwindTransferRepository(db)
+	transfer := testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusCompleted))
+
+	svc := NewReceiptService(transferRepo, []byte("test-signing-key"))
+	receipt, err := svc.TransferReceipt(context.Background(), *transfer.UserID, transfer.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := svc.VerifyReceipt(context.Background(), transfer.ID, receipt.VerificationHash)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Valid || result.ReceiptStatus != models.NWTransferStatusCompleted {
+		t.Errorf("expected valid COMPLETED receipt, got %+v", result)
+	}
+
+	for _, hash := range []string{"not-hex", receipt.VerificationHash[:62] + "00"} {
+		result, err := svc.VerifyReceipt(context.Background(), transfer.ID, hash)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Valid {
+			t.Errorf("hash %q verified", hash)
+		}
+	}
+
+	otherKey := NewReceiptService(transferRepo, []byte("another-key"))
+	if result, _ := otherKey.VerifyReceipt(context.Background(), transfer.ID, receipt.VerificationHash); result.Valid {
+		t.Errorf("hash verified under a different signing key")
+	}
+
+	// A receipt issued before a reversal still verifies afterwards
+	transfer.Status = models.NWTransferStatusReversed
+	if err := transferRepo.Update(context.Background(), transfer); err != nil {
+		t.Fatalf("failed to update transfer: %v", err)
+	}
+	result, err = svc.VerifyReceipt(context.Background(), transfer.ID, receipt.VerificationHash)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Valid || result.ReceiptStatus != models.NWTransferStatusCompleted || result.CurrentStatus != models.NWTransferStatusReversed {
+		t.Errorf("expected COMPLETED receipt on REVERSED transfer to verify, got %+v", result)
+	}
+
+	if _, err := svc.VerifyReceipt(context.Background(), uuid.New(), receipt.VerificationHash); !errors.Is(err, ErrNWTransferNotFound) {
+		t.Errorf("expected ErrNWTransferNotFound, got %v", err)
+	}
+}
+
+func TestRenderReceiptLayout_UnknownDirective(t *testing.T) {
+	if _, err := renderReceiptLayout("title Receipt\nbanner oops\n"); err == nil {
+		t.Error("expected an error for an unknown directive")
+	}
+}
@@ -0,0 +1,311 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// resilienceMaxRetries is the NorthWind client's retry setting in the resilience suite; every
+// call may reach NorthWind at most resilienceMaxRetries+1 times
+const resilienceMaxRetries = 2
+
+// resilienceEnv runs transfer creation, status polling and regulator delivery against the fake
+// NorthWind, with a regulator that accepts every notification
+type resilienceEnv struct {
+	db            *gorm.DB
+	api           *fakeNorthwindTransferAPI
+	transfers     *NorthwindTransferService
+	poller        *NorthwindPollingService
+	states        *TransferStateManager
+	regulator     *RegulatorService
+	regulatorHits atomic.Int32
+	userID        uuid.UUID
+	creates       int
+	statusPolls   int
+}
+
+func newResilienceEnv(t *testing.T) *resilienceEnv {
+	t.Helper()
+	env := &resilienceEnv{db: testfactory.NewDB(t), api: &fakeNorthwindTransferAPI{}, userID: uuid.New()}
+	nwServer := httptest.NewServer(env.api)
+	t.Cleanup(nwServer.Close)
+	regulatorServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		env.regulatorHits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(regulatorServer.Close)
+
+	client := northwind.NewClient(nwServer.URL, "test-key", northwind.WithRetry(resilienceMaxRetries, 1))
+	transferRepo := repositories.NewNorthwindTransferRepository(env.db)
+	env.regulator = NewRegulatorService(regulatorServer.URL, 2, 60,
+		repositories.NewRegulatorNotificationRepository(env.db), repositories.NewRegulatorNotificationAttemptRepository(env.db),
+		slog.Default(), regulatorServer.Client())
+	env.regulator.StartDeliveryWorkers(1, 10)
+	t.Cleanup(func() { env.regulator.Shutdown(context.Background()) })
+
+	env.states = NewTransferStateManager(transferRepo, env.regulator, slog.Default())
+	env.transfers = NewNorthwindTransferService(client, transferRepo, nil, nil, slog.Default())
+	env.transfers.SetDuplicateWindow(0)
+	env.poller = NewNorthwindPollingService(client, transferRepo, env.regulator, 0, slog.Default())
+	env.poller.SetTransferStateManager(env.states)
+	return env
+}
+
+// create sends a transfer with reference through CreateTransfer
+func (env *resilienceEnv) create(reference string) error {
+	env.creates++
+	req := newTestTransferRequest(models.NWTransferDirectionOutbound)
+	req.ReferenceNumber = reference
+	_, err := env.transfers.CreateTransfer(context.Background(), env.userID, req)
+	return err
+}
+
+// poll runs one poll cycle over every in-flight transfer, whatever its next poll time
+func (env *resilienceEnv) poll(t *testing.T) {
+	t.Helper()
+	env.makeDue(t)
+	env.poller.PollOnce(context.Background())
+}
+
+// makeDue makes every in-flight transfer due for the next poll cycle
+func (env *resilienceEnv) makeDue(t *testing.T) {
+	t.Helper()
+	var due int64
+	if err := env.db.Model(&models.NorthwindTransfer{}).Where("status IN ?", pollBacklogStatuses).Count(&due).Error; err != nil {
+		t.Fatalf("failed to count in-flight transfers: %v", err)
+	}
+	if err := env.db.Model(&models.NorthwindTransfer{}).Where("1 = 1").Update("next_poll_at", nil).Error; err != nil {
+		t.Fatalf("failed to make transfers due: %v", err)
+	}
+	env.statusPolls += int(due)
+}
+
+// statusRank orders statuses by how far a transfer has progressed; a transition must never lower it
+func statusRank(status string) int {
+	switch status {
+	case models.NWTransferStatusInitiationPending:
+		return 0
+	case models.NWTransferStatusPending:
+		return 1
+	case models.NWTransferStatusProcessing:
+		return 2
+	case models.NWTransferStatusReversed:
+		return 4
+	default:
+		return 3
+	}
+}
+
+// checkInvariants drains regulator delivery and reports each broken invariant prefixed with the
+// component responsible for it
+func (env *resilienceEnv) checkInvariants(t *testing.T) {
+	t.Helper()
+	env.regulator.Shutdown(context.Background())
+
+	// client and transfer service: NorthWind saw each reference initiated at most once, and every
+	// stored transfer is one it initiated
+	initiated := make(map[string]int)
+	for _, reference := range env.api.initiatedReferences() {
+		initiated[reference]++
+	}
+	for reference, n := range initiated {
+		if n > 1 {
+			t.Errorf("[client] reference %s was initiated %d times at NorthWind", reference, n)
+		}
+	}
+	var transfers []models.NorthwindTransfer
+	if err := env.db.Find(&transfers).Error; err != nil {
+		t.Fatalf("failed to load transfers: %v", err)
+	}
+	for _, transfer := range transfers {
+		if initiated[transfer.ReferenceNumber] != 1 {
+			t.Errorf("[transfer service] stored transfer %s was initiated %d times at NorthWind", transfer.ReferenceNumber, initiated[transfer.ReferenceNumber])
+		}
+	}
+
+	// state manager: no persisted transition moves a transfer backwards
+	var events []models.NorthwindTransferEvent
+	if err := env.db.Find(&events).Error; err != nil {
+		t.Fatalf("failed to load transfer events: %v", err)
+	}
+	for _, event := range events {
+		if statusRank(event.ToStatus) <= statusRank(event.FromStatus) {
+			t.Errorf("[state manager] transfer %s went backwards from %s to %s (source %s)", event.TransferID, event.FromStatus, event.ToStatus, event.Source)
+		}
+	}
+
+	// regulator: every reportable status has a notification, and each was delivered
+	var notifications []models.RegulatorNotification
+	if err := env.db.Find(&notifications).Error; err != nil {
+		t.Fatalf("failed to load regulator notifications: %v", err)
+	}
+	notified := make(map[string]bool)
+	for _, notification := range notifications {
+		notified[notification.TransferID.String()+"/"+notification.TerminalStatus] = true
+		if !notification.Delivered {
+			t.Errorf("[regulator] %s notification for transfer %s was not delivered", notification.TerminalStatus, notification.TransferID)
+		}
+	}
+	for _, transfer := range transfers {
+		if regulatorNotifiable(transfer.Status) && !notified[transfer.ID.String()+"/"+transfer.Status] {
+			t.Errorf("[regulator] transfer %s reached %s without a regulator notification", transfer.ID, transfer.Status)
+		}
+	}
+	if hits := int(env.regulatorHits.Load()); hits < len(notifications) {
+		t.Errorf("[regulator] %d notifications but only %d deliveries", len(notifications), hits)
+	}
+
+	// client: retries stay within the budget
+	for _, endpoint := range []string{fakeEndpointValidate, fakeEndpointBalance, fakeEndpointInitiate} {
+		if n, limit := env.api.requests(endpoint), env.creates*(resilienceMaxRetries+1); n > limit {
+			t.Errorf("[client] %d %s requests for %d transfers, over the retry budget of %d", n, endpoint, env.creates, limit)
+		}
+	}
+	if n, limit := env.api.requests(fakeEndpointStatus), env.statusPolls*(resilienceMaxRetries+1); n > limit {
+		t.Errorf("[poller] %d status requests for %d polls, over the retry budget of %d", n, env.statusPolls, limit)
+	}
+}
+
+// requireStatuses fails unless every stored transfer ended in status
+func (env *resilienceEnv) requireStatuses(t *testing.T, want string, count int) {
+	t.Helper()
+	var transfers []models.NorthwindTransfer
+	if err := env.db.Find(&transfers).Error; err != nil {
+		t.Fatalf("failed to load transfers: %v", err)
+	}
+	if len(transfers) != count {
+		t.Errorf("[transfer service] expected %d stored transfers, got %d", count, len(transfers))
+	}
+	for _, transfer := range transfers {
+		if transfer.Status != want {
+			t.Errorf("[poller] transfer %s ended %s, want %s", transfer.ReferenceNumber, transfer.Status, want)
+		}
+	}
+}
+
+func TestNorthwindResilience_FaultPatterns(t *testing.T) {
+	internalError := fakeFault{status: http.StatusInternalServerError}
+	slow := fakeFault{delay: 30 * time.Millisecond}
+	reset := fakeFault{reset: true}
+
+	tests := []struct {
+		name   string
+		faults map[string][]fakeFault
+		script []string
+		// wantStored transfers of the three created reach NorthWind and are stored
+		wantStored int
+	}{
+		{
+			name: "intermittent 500s",
+			faults: map[string][]fakeFault{
+				fakeEndpointValidate: {internalError, internalError, internalError},
+				fakeEndpointBalance:  {internalError},
+				// A failed initiation is retried under the same Idempotency-Key
+				fakeEndpointInitiate: {internalError},
+				// The first poll exhausts its retries; the rest recover on a retry
+				fakeEndpointStatus: {internalError, internalError, internalError, internalError},
+			},
+			script:     []string{"PROCESSING", "COMPLETED"},
+			wantStored: 3,
+		},
+		{
+			name: "slow responses",
+			faults: map[string][]fakeFault{
+				fakeEndpointValidate: {slow, slow},
+				fakeEndpointInitiate: {slow},
+				fakeEndpointStatus:   {slow, slow, slow, slow},
+			},
+			script:     []string{"PENDING", "PROCESSING", "COMPLETED"},
+			wantStored: 3,
+		},
+		{
+			name: "connection resets",
+			faults: map[string][]fakeFault{
+				fakeEndpointBalance: {reset},
+				// NorthWind takes the first initiation but its response is lost; the retry's
+				// Idempotency-Key gets that transfer back instead of a second one
+				fakeEndpointInitiate: {reset},
+				fakeEndpointStatus:   {reset, reset, reset, reset},
+			},
+			script:     []string{"PROCESSING", "COMPLETED"},
+			wantStored: 3,
+		},
+		{
+			name:       "out-of-order status",
+			script:     []string{"PROCESSING", "PENDING", "COMPLETED", "PROCESSING"},
+			wantStored: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newResilienceEnv(t)
+			env.api.statusScript = tt.script
+			for endpoint, faults := range tt.faults {
+				env.api.inject(endpoint, faults...)
+			}
+
+			failed := 0
+			for i := 0; i < 3; i++ {
+				if err := env.create(fmt.Sprintf("CHAOS-%d", i)); err != nil {
+					failed++
+				}
+			}
+			if stored := 3 - failed; stored != tt.wantStored {
+				t.Errorf("[transfer service] expected %d transfers created, got %d", tt.wantStored, stored)
+			}
+			for i := 0; i < len(tt.script)+2; i++ {
+				env.poll(t)
+			}
+
+			env.requireStatuses(t, models.NWTransferStatusCompleted, tt.wantStored)
+			env.checkInvariants(t)
+		})
+	}
+}
+
+// A poll whose response is slow carries a status that may be stale by the time it arrives: here
+// PROCESSING, after the webhook has already completed the transfer
+func TestNorthwindResilience_StaleSlowPollAfterCompletion(t *testing.T) {
+	env := newResilienceEnv(t)
+	env.api.statusScript = []string{"PROCESSING"}
+	if err := env.create("CHAOS-STALE"); err != nil {
+		t.Fatalf("failed to create transfer: %v", err)
+	}
+	var transfer models.NorthwindTransfer
+	if err := env.db.First(&transfer).Error; err != nil {
+		t.Fatalf("failed to load transfer: %v", err)
+	}
+
+	env.api.inject(fakeEndpointStatus, fakeFault{delay: 200 * time.Millisecond})
+	env.makeDue(t)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		env.poller.PollOnce(context.Background())
+	}()
+
+	// The webhook lands while the poll is waiting on NorthWind
+	time.Sleep(50 * time.Millisecond)
+	result, err := env.states.Apply(context.Background(), transfer.ID, models.NWTransferEventSourceWebhook,
+		&northwind.TransferResponse{TransferID: transfer.NorthwindTransferID.String(), Status: "COMPLETED"})
+	if err != nil || !result.Applied() {
+		t.Fatalf("[state manager] expected the webhook to complete the transfer, got %v", err)
+	}
+	<-done
+
+	env.requireStatuses(t, models.NWTransferStatusCompleted, 1)
+	env.checkInvariants(t)
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NorthWind's documented field limits, in characters
+const (
+	MaxNWDescriptionLength       = 140
+	MaxNWAccountHolderNameLength = 100
+	// MaxAccountNicknameLength is our own limit; NorthWind never sees nicknames
+	MaxAccountNicknameLength = 50
+)
+
+var ErrNWInvalidText = errors.New("invalid text field")
+
+// InvalidTextError reports a free-text field NorthWind would reject. Either MaxLength is set
+// because the field is too long, or Characters lists the characters it does not allow. It
+// wraps ErrNWInvalidText.
+type InvalidTextError struct {
+	Field      string
+	MaxLength  int
+	Characters []string
+}
+
+func (e *InvalidTextError) Error() string {
+	if e.MaxLength > 0 {
+		return fmt.Sprintf("%s: %s must be at most %d characters", ErrNWInvalidText, e.Field, e.MaxLength)
+	}
+	return fmt.Sprintf("%s: %s contains characters that are not allowed: %s", ErrNWInvalidText, e.Field, strings.Join(e.Characters, ", "))
+}
+
+func (e *InvalidTextError) Unwrap() error {
+	return ErrNWInvalidText
+}
+
+// sanitizeText drops control and invisible formatting characters (such as bidi overrides) and
+// collapses runs of whitespace, including newlines and tabs, into single spaces
+func sanitizeText(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	space := false
+	for _, r := range s {
+		switch {
+		case r == utf8.RuneError:
+			continue
+		case unicode.IsSpace(r):
+			space = b.Len() > 0
+			continue
+		case unicode.IsControl(r) || unicode.Is(unicode.Cf, r):
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// sanitizeDescription sanitizes a transfer description and enforces NorthWind's length limit
+func sanitizeDescription(field, s string) (string, error) {
+	s = sanitizeText(s)
+	if utf8.RuneCountInString(s) > MaxNWDescriptionLength {
+		return "", &InvalidTextError{Field: field, MaxLength: MaxNWDescriptionLength}
+	}
+	return s, nil
+}
+
+// sanitizeAccountHolderName sanitizes an account holder name and rejects characters NACHA files
+// cannot carry. Names are limited to printable ASCII and the Latin-1 letters used in European
+// names; anything else is reported rather than transliterated, so the name on file is always
+// the one the user typed.
+func sanitizeAccountHolderName(field, s string) (string, error) {
+	s = sanitizeText(s)
+	var offending []string
+	seen := make(map[rune]bool)
+	for _, r := range s {
+		if allowedHolderNameRune(r) || seen[r] {
+			continue
+		}
+		seen[r] = true
+		offending = append(offending, fmt.Sprintf("%q (%U)", r, r))
+	}
+	if len(offending) > 0 {
+		return "", &InvalidTextError{Field: field, Characters: offending}
+	}
+	if utf8.RuneCountInString(s) > MaxNWAccountHolderNameLength {
+		return "", &InvalidTextError{Field: field, MaxLength: MaxNWAccountHolderNameLength}
+	}
+	return s, nil
+}
+
+// sanitizeNickname sanitizes an external account nickname and enforces its length limit
+func sanitizeNickname(field, s string) (string, error) {
+	s = sanitizeText(s)
+	if utf8.RuneCountInString(s) > MaxAccountNicknameLength {
+		return "", &InvalidTextError{Field: field, MaxLength: MaxAccountNicknameLength}
+	}
+	return s, nil
+}
+
+func allowedHolderNameRune(r rune) bool {
+	if r >= 0x20 && r <= 0x7E {
+		return true
+	}
+	// Latin-1 Supplement letters, excluding the multiplication and division signs
+	return r >= 0xC0 && r <= 0xFF && r != 0xD7 && r != 0xF7
+}
+
+// sanitizeTransferText sanitizes the free-text fields of a transfer request in place and
+// normalizes its reference number
+func sanitizeTransferText(req *CreateTransferRequest) error {
+	req.NormalizeReference()
+	var err error
+	if req.Description, err = sanitizeDescription("description", req.Description); err != nil {
+		return err
+	}
+	if req.SourceAccount.AccountHolderName, err = sanitizeAccountHolderName("source_account.account_holder_name", req.SourceAccount.AccountHolderName); err != nil {
+		return err
+	}
+	if req.DestinationAccount.AccountHolderName, err = sanitizeAccountHolderName("destination_account.account_holder_name", req.DestinationAccount.AccountHolderName); err != nil {
+		return err
+	}
+	return nil
+}
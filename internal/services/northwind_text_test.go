@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestSanitizeDescription(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      string
+		maxLength int
+	}{
+		{"plain", "Rent for March", "Rent for March", 0},
+		{"control characters", "Rent\x00 for\x07 March\x1b", "Rent for March", 0},
+		{"newlines and tabs collapse", "  Rent\r\n\tfor   March  ", "Rent for March", 0},
+		{"bidi override stripped", "Invoice \u202e1234", "Invoice 1234", 0},
+		{"zero width space stripped", "Re\u200bnt", "Rent", 0},
+		{"rtl text kept", "שכירות מרץ", "שכירות מרץ", 0},
+		{"emoji kept", "Dinner 🍕", "Dinner 🍕", 0},
+		{"empty", "", "", 0},
+		{"at limit", strings.Repeat("a", MaxNWDescriptionLength), strings.Repeat("a", MaxNWDescriptionLength), 0},
+		{"limit counts characters not bytes", strings.Repeat("é", MaxNWDescriptionLength), strings.Repeat("é", MaxNWDescriptionLength), 0},
+		{"limit applies after collapsing", strings.Repeat("a ", MaxNWDescriptionLength/2) + "    ", strings.TrimSpace(strings.Repeat("a ", MaxNWDescriptionLength/2)), 0},
+		{"over limit", strings.Repeat("a", MaxNWDescriptionLength+1), "", MaxNWDescriptionLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sanitizeDescription("description", tt.input)
+			if tt.maxLength > 0 {
+				var textErr *InvalidTextError
+				if !errors.As(err, &textErr) || textErr.MaxLength != tt.maxLength || textErr.Field != "description" {
+					t.Fatalf("expected a max length %d error for description, got %v", tt.maxLength, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSanitizeAccountHolderName(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		want       string
+		characters []string
+		maxLength  int
+	}{
+		{"plain", "Jane Doe", "Jane Doe", nil, 0},
+		{"punctuation", "O'Brien-Smith, Jr.", "O'Brien-Smith, Jr.", nil, 0},
+		{"latin-1 letters", "José Müller Ørsted", "José Müller Ørsted", nil, 0},
+		{"control characters and whitespace", "\tJane\x00  Doe\n", "Jane Doe", nil, 0},
+		{"emoji", "Jane 😀 Doe", "", []string{`'😀' (U+1F600)`}, 0},
+		{"repeated characters listed once", "Jane 😀😀 Doe ✨", "", []string{`'😀' (U+1F600)`, `'✨' (U+2728)`}, 0},
+		{"rtl text", "יעל כהן", "", []string{`'י' (U+05D9)`, `'ע' (U+05E2)`, `'ל' (U+05DC)`, `'כ' (U+05DB)`, `'ה' (U+05D4)`, `'ן' (U+05DF)`}, 0},
+		{"multiplication sign", "A × B", "", []string{`'×' (U+00D7)`}, 0},
+		{"cyrillic lookalike", "Jаne", "", []string{`'а' (U+0430)`}, 0},
+		{"at limit", strings.Repeat("a", MaxNWAccountHolderNameLength), strings.Repeat("a", MaxNWAccountHolderNameLength), nil, 0},
+		{"over limit", strings.Repeat("a", MaxNWAccountHolderNameLength+1), "", nil, MaxNWAccountHolderNameLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sanitizeAccountHolderName("account_holder_name", tt.input)
+			if tt.characters == nil && tt.maxLength == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if got != tt.want {
+					t.Errorf("expected %q, got %q", tt.want, got)
+				}
+				return
+			}
+
+			var textErr *InvalidTextError
+			if !errors.As(err, &textErr) {
+				t.Fatalf("expected an InvalidTextError, got %v", err)
+			}
+			if !errors.Is(err, ErrNWInvalidText) {
+				t.Errorf("expected error to wrap ErrNWInvalidText")
+			}
+			if textErr.MaxLength != tt.maxLength {
+				t.Errorf("expected max length %d, got %d", tt.maxLength, textErr.MaxLength)
+			}
+			if strings.Join(textErr.Characters, " ") != strings.Join(tt.characters, " ") {
+				t.Errorf("expected offending characters %v, got %v", tt.characters, textErr.Characters)
+			}
+		})
+	}
+}
+
+func TestNorthwindTransferService_CreateTransfer_RejectsInvalidText(t *testing.T) {
+	// The client is nil: sanitization must fail before anything is sent to NorthWind
+	svc := NewNorthwindTransferService(nil, nil, nil, nil, slog.Default())
+	req := CreateTransferRequest{
+		Amount:             10,
+		Currency:           "USD",
+		Direction:          "OUTBOUND",
+		TransferType:       "ACH",
+		SourceAccount:      CreateTransferAccountDetails{AccountHolderName: "Jane Doe", AccountNumber: "1111111111"},
+		DestinationAccount: CreateTransferAccountDetails{AccountHolderName: "Bob 🏦 Smith", AccountNumber: "2222222222"},
+	}
+
+	_, err := svc.CreateTransfer(context.Background(), uuid.New(), req)
+	var textErr *InvalidTextError
+	if !errors.As(err, &textErr) {
+		t.Fatalf("expected an InvalidTextError, got %v", err)
+	}
+	if textErr.Field != "destination_account.account_holder_name" {
+		t.Errorf("expected destination holder name to be reported, got %s", textErr.Field)
+	}
+}
+
+func TestNorthwindAccountService_ValidateAndRegister_RejectsInvalidHolderName(t *testing.T) {
+	svc := NewNorthwindAccountService(nil, nil, slog.Default())
+	req := ValidateAndRegisterRequest{AccountHolderName: "Jane\u202e Doe 💸", AccountNumber: "1111111111", RoutingNumber: "021000021"}
+
+	_, err := svc.ValidateAndRegister(context.Background(), uuid.New(), req)
+	var textErr *InvalidTextError
+	if !errors.As(err, &textErr) {
+		t.Fatalf("expected an InvalidTextError, got %v", err)
+	}
+	if len(textErr.Characters) != 1 || textErr.Characters[0] != `'💸' (U+1F4B8)` {
+		t.Errorf("expected only the emoji to be reported, got %v", textErr.Characters)
+	}
+}
@@ -0,0 +1,386 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNWTransferBatchExists   = errors.New("batch name already used for another batch")
+	ErrNWTransferBatchNotFound = errors.New("transfer batch not found")
+)
+
+// Outcomes reported per transfer by SubmitBatch and RetryFailedBatch
+const (
+	BatchItemOutcomeCreated = "created"
+	BatchItemOutcomeFailed  = "failed"
+)
+
+// batchItemMissingCode is recorded on a batch item NorthWind neither accepted nor rejected
+const batchItemMissingCode = "NO_RESULT"
+
+// SubmitBatchRequest is a named batch of transfers submitted to NorthWind in one call. The name
+// identifies the batch for the user, so its failed items can be retried later; without one a
+// name is generated and returned in the result. Transfers are validated one by one, so an
+// invalid transfer fails alone rather than rejecting the batch.
+type SubmitBatchRequest struct {
+	BatchName string                  `json:"batch_name,omitempty" validate:"omitempty,max=100"`
+	Transfers []CreateTransferRequest `json:"transfers" validate:"required,min=1,max=100"`
+	// Invalid holds, by index, why each transfer that failed validation is rejected; the handler
+	// sets it, it is never bound from the body
+	Invalid map[int]error `json:"-"`
+	// Metadata identifies the client; the handler sets it, it is never bound from the body
+	Metadata RequestMetadata `json:"-"`
+}
+
+// BatchTransferResult is the per-item outcome of a batch submission or retry
+type BatchTransferResult struct {
+	BatchName    string                    `json:"batch_name"`
+	TotalCount   int                       `json:"total_count"`
+	SuccessCount int                       `json:"success_count"`
+	FailedCount  int                       `json:"failed_count"`
+	Items        []BatchTransferItemResult `json:"items"`
+}
+
+// BatchTransferItemResult is the outcome of one transfer in a batch. Index is the transfer's
+// position in the originally submitted batch; Transfer is the stored record, failed or not.
+// LocalErr is set, with no error code, on a transfer that failed validation or the local checks;
+// it was not sent to NorthWind, and a new one is not stored.
+type BatchTransferItemResult struct {
+	Index        int                       `json:"index"`
+	Outcome      string                    `json:"outcome"`
+	Transfer     *models.NorthwindTransfer `json:"transfer"`
+	ErrorCode    string                    `json:"error_code,omitempty"`
+	ErrorMessage string                    `json:"error_message,omitempty"`
+	LocalErr     error                     `json:"-"`
+}
+
+// localRejection is the outcome of a batch transfer that failed validation or the local checks
+func localRejection(index int, err error) BatchTransferItemResult {
+	return BatchTransferItemResult{Index: index, Outcome: BatchItemOutcomeFailed, ErrorMessage: err.Error(), LocalErr: err}
+}
+
+// isLocalRejection reports whether err is a transfer failing CreateTransfer's local checks, as
+// opposed to the checks being unable to run
+func isLocalRejection(err error) bool {
+	var textErr *InvalidTextError
+	var cutoff *SameDayCutoffPassedError
+	return errors.As(err, &textErr) || errors.As(err, &cutoff) ||
+		errors.Is(err, ErrNWTransferPriorityNotAllowed) ||
+		errors.Is(err, ErrNWTransferConsentRequired) ||
+		errors.Is(err, ErrNWTransferUnverifiedAcct) ||
+		errors.Is(err, ErrNWTransferDuplicateRef)
+}
+
+// addLocalRejections adds the outcomes of transfers rejected before the NorthWind call to r,
+// keeping its items in batch order
+func (r *BatchTransferResult) addLocalRejections(rejected []BatchTransferItemResult) {
+	if len(rejected) == 0 {
+		return
+	}
+	r.Items = append(r.Items, rejected...)
+	sort.SliceStable(r.Items, func(i, j int) bool { return r.Items[i].Index < r.Items[j].Index })
+	r.TotalCount += len(rejected)
+	r.FailedCount += len(rejected)
+}
+
+// batchItem is one transfer sent to NorthWind as part of a batch. existing is the stored record
+// of a previously failed item being retried.
+type batchItem struct {
+	index    int
+	req      CreateTransferRequest
+	existing *models.NorthwindTransfer
+}
+
+// SubmitBatch submits a named batch of transfers and stores every item NorthWind answered for:
+// accepted transfers as usual, and transfers NorthWind rejected as FAILED records carrying its
+// error code and message. Each item passes the same local checks as CreateTransfer, except the
+// near-identical transfer check; an item failing them is reported failed and not sent, while the
+// rest of the batch goes ahead.
+func (s *NorthwindTransferService) SubmitBatch(ctx context.Context, userID uuid.UUID, req SubmitBatchRequest) (*BatchTransferResult, error) {
+	if req.BatchName == "" {
+		req.BatchName = "batch-" + uuid.NewString()
+	}
+	existing, err := s.transferRepo.GetByUserIDAndBatch(ctx, userID, req.BatchName)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNWTransferBatchExists, req.BatchName)
+	}
+
+	items := make([]batchItem, 0, len(req.Transfers))
+	var rejected []BatchTransferItemResult
+	references := make(map[string]struct{}, len(req.Transfers))
+	for i, transfer := range req.Transfers {
+		if err, invalid := req.Invalid[i]; invalid {
+			rejected = append(rejected, localRejection(i, err))
+			continue
+		}
+		if req.Metadata != (RequestMetadata{}) {
+			transfer.Metadata = req.Metadata
+		}
+		err := s.preflightBatchItem(ctx, userID, &transfer)
+		if _, dup := references[transfer.ReferenceNumber]; err == nil && dup {
+			err = fmt.Errorf("%w: %s", ErrNWTransferDuplicateRef, transfer.ReferenceNumber)
+		}
+		if err != nil {
+			if !isLocalRejection(err) {
+				return nil, fmt.Errorf("transfers[%d]: %w", i, err)
+			}
+			rejected = append(rejected, localRejection(i, err))
+			continue
+		}
+		references[transfer.ReferenceNumber] = struct{}{}
+		items = append(items, batchItem{index: i, req: transfer})
+	}
+
+	result := &BatchTransferResult{BatchName: req.BatchName, Items: []BatchTransferItemResult{}}
+	if len(items) > 0 {
+		if result, err = s.submitBatchItems(ctx, userID, req.BatchName, items); err != nil {
+			return nil, err
+		}
+	}
+	result.addLocalRejections(rejected)
+	return result, nil
+}
+
+// CreateBatchTransfers submits transfers as one unnamed batch, each keeping its own metadata.
+// The batch is stored under a generated name, returned in the result, by which its failed items
+// can be retried. Transfers are expected to have passed validation already; each still gets the
+// local checks.
+func (s *NorthwindTransferService) CreateBatchTransfers(ctx context.Context, userID uuid.UUID, transfers []CreateTransferRequest) (*BatchTransferResult, error) {
+	return s.SubmitBatch(ctx, userID, SubmitBatchRequest{Transfers: transfers})
+}
+
+// RetryFailedBatch resubmits the items of a named batch that NorthWind rejected, and only those.
+// Each is rebuilt from its stored record and keeps its reference number and batch position; the
+// record is updated in place with the new outcome. An item that can no longer be sent is
+// reported failed and left as it was.
+func (s *NorthwindTransferService) RetryFailedBatch(ctx context.Context, userID uuid.UUID, batchName string, meta RequestMetadata) (*BatchTransferResult, error) {
+	transfers, err := s.transferRepo.GetByUserIDAndBatch(ctx, userID, batchName)
+	if err != nil {
+		return nil, err
+	}
+	if len(transfers) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNWTransferBatchNotFound, batchName)
+	}
+
+	var items []batchItem
+	var rejected []BatchTransferItemResult
+	for i := range transfers {
+		t := &transfers[i]
+		// Only items NorthWind never accepted are retried; a transfer that failed after acceptance has an ExternalRef
+		if t.Status != models.NWTransferStatusFailed || t.ExternalRef != nil || t.BatchIndex == nil {
+			continue
+		}
+		req := requestFromTransfer(t)
+		req.Metadata = meta
+		// A SAME_DAY item can only be resent before the day's cutoff
+		if err := s.checkPriority(req); err != nil {
+			rejection := localRejection(*t.BatchIndex, err)
+			rejection.Transfer = t
+			rejected = append(rejected, rejection)
+			continue
+		}
+		items = append(items, batchItem{index: *t.BatchIndex, req: req, existing: t})
+	}
+
+	result := &BatchTransferResult{BatchName: batchName, Items: []BatchTransferItemResult{}}
+	if len(items) > 0 {
+		if result, err = s.submitBatchItems(ctx, userID, batchName, items); err != nil {
+			return nil, err
+		}
+	}
+	result.addLocalRejections(rejected)
+	return result, nil
+}
+
+// preflightBatchItem runs CreateTransfer's local checks on one batch transfer and assigns its
+// reference number
+func (s *NorthwindTransferService) preflightBatchItem(ctx context.Context, userID uuid.UUID, req *CreateTransferRequest) error {
+	if err := sanitizeTransferText(req); err != nil {
+		return err
+	}
+	if err := s.checkPriority(*req); err != nil {
+		return err
+	}
+	if req.Direction == models.NWTransferDirectionInbound {
+		if err := validateAuthorizationConsent(req.AuthorizationConsent); err != nil {
+			return err
+		}
+		if err := s.requireVerifiedExternalAccount(ctx, userID, req.SourceAccount); err != nil {
+			return err
+		}
+	}
+	referenceNumber, err := s.assignReferenceNumber(ctx, userID, *req)
+	if err != nil {
+		return err
+	}
+	req.ReferenceNumber = referenceNumber
+	return nil
+}
+
+// submitBatchItems sends items to NorthWind in one batch call and stores the outcome of each.
+// Accepted transfers are matched back to their item by reference number, rejections by their
+// position in this call. If the call itself fails nothing is stored.
+func (s *NorthwindTransferService) submitBatchItems(ctx context.Context, userID uuid.UUID, batchName string, items []batchItem) (*BatchTransferResult, error) {
+	nwReq := northwind.BatchTransferRequest{Transfers: make([]northwind.TransferRequest, len(items))}
+	for i, item := range items {
+		nwReq.Transfers[i] = toNWTransferRequest(item.req)
+	}
+
+	batchCtx, response := northwind.WithResponseMetadata(ctx)
+	nwResp, err := s.client.BatchTransfers(batchCtx, nwReq)
+	if err != nil {
+		s.logger.Error("NorthWind batch transfer failed", "batch_name", batchName, "upstream_request_id", response.RequestID, "error", err)
+		return nil, fmt.Errorf("%w: %w", ErrNWTransferInitiateFailed, err)
+	}
+
+	accepted := make(map[string]*northwind.TransferResponse, len(nwResp.Transfers))
+	for i := range nwResp.Transfers {
+		accepted[nwResp.Transfers[i].ReferenceNumber] = &nwResp.Transfers[i]
+	}
+	rejected := make(map[int]northwind.BatchTransferItemError, len(nwResp.Errors))
+	for _, itemErr := range nwResp.Errors {
+		rejected[itemErr.Index] = itemErr
+	}
+
+	// NorthWind has answered for these transfers, so record them even if the caller has gone away
+	storeCtx := context.WithoutCancel(ctx)
+	result := &BatchTransferResult{BatchName: batchName, TotalCount: len(items), Items: make([]BatchTransferItemResult, 0, len(items))}
+	for i, item := range items {
+		itemErr, isRejected := rejected[i]
+		remote, isAccepted := accepted[item.req.ReferenceNumber]
+
+		var transfer *models.NorthwindTransfer
+		if isAccepted && !isRejected {
+			transfer = s.newLocalTransfer(userID, item.req, remote)
+		} else {
+			if !isRejected {
+				itemErr = northwind.BatchTransferItemError{
+					Index:           i,
+					ReferenceNumber: item.req.ReferenceNumber,
+					ErrorCode:       batchItemMissingCode,
+					ErrorMessage:    "NorthWind returned no result for this transfer",
+				}
+			}
+			transfer = s.newRejectedTransfer(userID, item.req, itemErr)
+		}
+		setInitiationRequestID(transfer, response.RequestID)
+		index := item.index
+		transfer.BatchName = &batchName
+		transfer.BatchIndex = &index
+
+		if item.existing != nil {
+			transfer.ID = item.existing.ID
+			transfer.CreatedAt = item.existing.CreatedAt
+			transfer.Version = item.existing.Version
+			transfer.OriginIP = item.existing.OriginIP
+			transfer.OriginUserAgent = item.existing.OriginUserAgent
+			err = s.transferRepo.Update(storeCtx, transfer)
+		} else {
+			err = s.transferRepo.Create(storeCtx, transfer)
+		}
+		if err != nil {
+			s.logger.Error("Failed to store batch transfer",
+				"batch_name", batchName,
+				"batch_index", index,
+				"northwind_id", transfer.NorthwindTransferID,
+				"error", err,
+			)
+			return nil, fmt.Errorf("failed to store batch transfer %d: %w", index, err)
+		}
+
+		transfer.Failure = s.errorCodes.Describe(transfer)
+		itemResult := BatchTransferItemResult{Index: index, Transfer: transfer}
+		if transfer.ExternalRef != nil {
+			itemResult.Outcome = BatchItemOutcomeCreated
+			result.SuccessCount++
+			if item.existing == nil {
+				s.auditTransferCreated(transfer)
+			}
+		} else {
+			itemResult.Outcome = BatchItemOutcomeFailed
+			itemResult.ErrorCode = itemErr.ErrorCode
+			itemResult.ErrorMessage = itemErr.ErrorMessage
+			result.FailedCount++
+		}
+		result.Items = append(result.Items, itemResult)
+	}
+
+	s.logger.Info("Batch transfers submitted and stored",
+		"batch_name", batchName,
+		"total", result.TotalCount,
+		"succeeded", result.SuccessCount,
+		"failed", result.FailedCount,
+	)
+	return result, nil
+}
+
+// newRejectedTransfer builds the FAILED local record of a batch transfer NorthWind did not
+// accept. It has no ExternalRef, and its NorthWind transfer ID is a placeholder.
+func (s *NorthwindTransferService) newRejectedTransfer(userID uuid.UUID, req CreateTransferRequest, itemErr northwind.BatchTransferItemError) *models.NorthwindTransfer {
+	transfer := s.newLocalTransfer(userID, req, &northwind.TransferResponse{
+		Status:       models.NWTransferStatusFailed,
+		ErrorCode:    itemErr.ErrorCode,
+		ErrorMessage: itemErr.ErrorMessage,
+	})
+	if raw, err := json.Marshal(itemErr); err == nil {
+		transfer.RawResponse = string(raw)
+	}
+	return transfer
+}
+
+// requestFromTransfer rebuilds the request for a stored transfer so it can be resubmitted.
+// Institution names are not stored and are not resent.
+func requestFromTransfer(t *models.NorthwindTransfer) CreateTransferRequest {
+	req := CreateTransferRequest{
+		Amount:          t.Amount.InexactFloat64(),
+		Currency:        t.Currency,
+		Direction:       t.Direction,
+		TransferType:    t.TransferType,
+		Priority:        t.Priority,
+		ReferenceNumber: t.ReferenceNumber,
+		SourceAccount: CreateTransferAccountDetails{
+			AccountNumber:     t.SourceAccountNumber,
+			RoutingNumber:     stringValue(t.SourceRoutingNumber),
+			AccountHolderName: stringValue(t.SourceAccountHolderName),
+		},
+		DestinationAccount: CreateTransferAccountDetails{
+			AccountNumber:     t.DestinationAccountNumber,
+			RoutingNumber:     stringValue(t.DestinationRoutingNumber),
+			AccountHolderName: stringValue(t.DestinationAccountHolderName),
+		},
+	}
+	if t.Description != nil {
+		req.Description = *t.Description
+	}
+	if t.ScheduledDate != nil {
+		req.ScheduledDate = t.ScheduledDate.UTC().Format(time.RFC3339)
+	}
+	if t.ConsentTimestamp != nil && t.ConsentIPAddress != nil && t.ConsentMethod != nil {
+		req.AuthorizationConsent = &models.AuthorizationConsent{
+			Timestamp: *t.ConsentTimestamp,
+			IPAddress: *t.ConsentIPAddress,
+			Method:    *t.ConsentMethod,
+		}
+	}
+	return req
+}
+
+// stringValue returns the string s points to, or "" when s is nil
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
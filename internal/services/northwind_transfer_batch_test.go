@@ -0,0 +1,353 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/google/uuid"
+)
+
+// fakeNorthwindBatchAPI serves the batch endpoint, rejecting transfers whose reference is in
+// reject and recording the references submitted in each call
+type fakeNorthwindBatchAPI struct {
+	mu        sync.Mutex
+	reject    map[string]bool
+	submitted [][]string
+}
+
+func (f *fakeNorthwindBatchAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/external/transfers/batch" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	var body northwind.BatchTransferRequest
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	resp := northwind.BatchTransferResponse{TotalCount: len(body.Transfers)}
+	var refs []string
+	for i, transfer := range body.Transfers {
+		refs = append(refs, transfer.ReferenceNumber)
+		if f.reject[transfer.ReferenceNumber] {
+			resp.Errors = append(resp.Errors, northwind.BatchTransferItemError{
+				Index:           i,
+				ReferenceNumber: transfer.ReferenceNumber,
+				ErrorCode:       "INVALID_ACCOUNT",
+				ErrorMessage:    "destination account is closed",
+			})
+			continue
+		}
+		resp.Transfers = append(resp.Transfers, northwind.TransferResponse{
+			TransferID:      uuid.New().String(),
+			ReferenceNumber: transfer.ReferenceNumber,
+			Status:          "PENDING",
+		})
+	}
+	resp.SuccessCount, resp.FailedCount = len(resp.Transfers), len(resp.Errors)
+	f.submitted = append(f.submitted, refs)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func newBatchTestService(t *testing.T, api *fakeNorthwindBatchAPI) (*NorthwindTransferService, repositories.NorthwindTransferRepositoryInterface) {
+	t.Helper()
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+	transferRepo := repositories.NewNorthwindTransferRepository(testfactory.NewDB(t))
+	return NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), transferRepo, nil, nil, slog.Default()), transferRepo
+}
+
+func newTestBatch(name string, references ...string) SubmitBatchRequest {
+	req := SubmitBatchRequest{BatchName: name}
+	for _, ref := range references {
+		transfer := newTestTransferRequest(models.NWTransferDirectionOutbound)
+		transfer.ReferenceNumber = ref
+		req.Transfers = append(req.Transfers, transfer)
+	}
+	return req
+}
+
+func TestNorthwindTransferService_SubmitBatch_MixedOutcomes(t *testing.T) {
+	api := &fakeNorthwindBatchAPI{reject: map[string]bool{"REF-B": true}}
+	svc, transferRepo := newBatchTestService(t, api)
+	userID := uuid.New()
+
+	result, err := svc.SubmitBatch(context.Background(), userID, newTestBatch("payroll", "REF-A", "REF-B", "REF-C"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.TotalCount != 3 || result.SuccessCount != 2 || result.FailedCount != 1 {
+		t.Errorf("expected 3 total, 2 succeeded, 1 failed, got %+v", result)
+	}
+	wantOutcomes := []string{BatchItemOutcomeCreated, BatchItemOutcomeFailed, BatchItemOutcomeCreated}
+	for i, item := range result.Items {
+		if item.Index != i || item.Outcome != wantOutcomes[i] {
+			t.Errorf("item %d: expected index %d outcome %s, got %d %s", i, i, wantOutcomes[i], item.Index, item.Outcome)
+		}
+	}
+	if failed := result.Items[1]; failed.ErrorCode != "INVALID_ACCOUNT" || failed.ErrorMessage != "destination account is closed" {
+		t.Errorf("expected NorthWind's error on the failed item, got %q %q", failed.ErrorCode, failed.ErrorMessage)
+	}
+
+	// Every item is stored, the rejected one as a FAILED transfer NorthWind never accepted
+	stored, err := transferRepo.GetByUserIDAndBatch(context.Background(), userID, "payroll")
+	if err != nil {
+		t.Fatalf("failed to load batch: %v", err)
+	}
+	if len(stored) != 3 {
+		t.Fatalf("expected 3 stored transfers, got %d", len(stored))
+	}
+	rejected := stored[1]
+	if rejected.ReferenceNumber != "REF-B" || rejected.Status != models.NWTransferStatusFailed || rejected.ExternalRef != nil {
+		t.Errorf("expected REF-B stored as FAILED without an external ref, got %s %s %v", rejected.ReferenceNumber, rejected.Status, rejected.ExternalRef)
+	}
+	if rejected.ErrorCode == nil || *rejected.ErrorCode != "INVALID_ACCOUNT" || rejected.ErrorMessage == nil {
+		t.Errorf("expected the rejection to be stored on the transfer, got %v %v", rejected.ErrorCode, rejected.ErrorMessage)
+	}
+	if stored[0].Status != models.NWTransferStatusPending || stored[0].ExternalRef == nil {
+		t.Errorf("expected REF-A stored as an accepted PENDING transfer, got %s", stored[0].Status)
+	}
+
+	if _, err := svc.SubmitBatch(context.Background(), userID, newTestBatch("payroll", "REF-D")); !errors.Is(err, ErrNWTransferBatchExists) {
+		t.Errorf("expected ErrNWTransferBatchExists for a reused batch name, got %v", err)
+	}
+}
+
+func TestNorthwindTransferService_SubmitBatch_GeneratesName(t *testing.T) {
+	api := &fakeNorthwindBatchAPI{reject: map[string]bool{"REF-B": true}}
+	svc, transferRepo := newBatchTestService(t, api)
+	userID := uuid.New()
+	ctx := context.Background()
+
+	result, err := svc.SubmitBatch(ctx, userID, newTestBatch("", "REF-A", "REF-B"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(result.BatchName, "batch-") || result.SuccessCount != 1 || result.FailedCount != 1 {
+		t.Fatalf("expected a generated batch name with one success and one failure, got %+v", result)
+	}
+	stored, err := transferRepo.GetByUserIDAndBatch(ctx, userID, result.BatchName)
+	if err != nil {
+		t.Fatalf("failed to load batch: %v", err)
+	}
+	if len(stored) != 2 {
+		t.Errorf("expected both items stored under the generated name, got %d", len(stored))
+	}
+
+	// The generated name identifies the batch for a retry
+	if _, err := svc.RetryFailedBatch(ctx, userID, result.BatchName, RequestMetadata{}); err != nil {
+		t.Errorf("expected the failed item to be retried by the generated name, got %v", err)
+	}
+	if other, err := svc.SubmitBatch(ctx, userID, newTestBatch("", "REF-C")); err != nil || other.BatchName == result.BatchName {
+		t.Errorf("expected a second unnamed batch under a new name, got %+v, %v", other, err)
+	}
+}
+
+func TestNorthwindTransferService_CreateBatchTransfers(t *testing.T) {
+	api := &fakeNorthwindBatchAPI{reject: map[string]bool{"REF-B": true}}
+	svc, transferRepo := newBatchTestService(t, api)
+	userID := uuid.New()
+	ctx := context.Background()
+
+	transfers := newTestBatch("", "REF-A", "REF-B").Transfers
+	transfers[0].Metadata = RequestMetadata{IPAddress: "203.0.113.7"}
+	result, err := svc.CreateBatchTransfers(ctx, userID, transfers)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(result.BatchName, "batch-") || result.TotalCount != 2 || result.SuccessCount != 1 || result.FailedCount != 1 {
+		t.Fatalf("expected a generated batch name with one success and one failure, got %+v", result)
+	}
+	if ip := result.Items[0].Transfer.OriginIP; ip == nil || *ip != "203.0.113.7" {
+		t.Errorf("expected the item's own metadata kept, got %v", ip)
+	}
+
+	stored, err := transferRepo.GetByUserIDAndBatch(ctx, userID, result.BatchName)
+	if err != nil {
+		t.Fatalf("failed to load batch: %v", err)
+	}
+	if len(stored) != 2 {
+		t.Errorf("expected both items stored under the generated name, got %d", len(stored))
+	}
+}
+
+func TestNorthwindTransferService_SubmitBatch_RejectsInvalidItemLocally(t *testing.T) {
+	api := &fakeNorthwindBatchAPI{reject: map[string]bool{"REF-E": true}}
+	svc, transferRepo := newBatchTestService(t, api)
+	userID := uuid.New()
+	ctx := context.Background()
+
+	batch := newTestBatch("rent", "REF-A", "REF-A", "REF-C", "REF-D", "REF-E")
+	batch.Transfers[2].DestinationAccount.AccountHolderName = "Caf\u20ac"
+	invalid := errors.New("amount failed the required rule")
+	batch.Invalid = map[int]error{3: invalid}
+	result, err := svc.SubmitBatch(ctx, userID, batch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(api.submitted) != 1 || len(api.submitted[0]) != 2 || api.submitted[0][0] != "REF-A" || api.submitted[0][1] != "REF-E" {
+		t.Fatalf("expected only the valid items sent to NorthWind, got %v", api.submitted)
+	}
+	if result.TotalCount != 5 || result.SuccessCount != 1 || result.FailedCount != 4 || len(result.Items) != 5 {
+		t.Fatalf("expected 5 items, 1 succeeded and 4 failed, got %+v", result)
+	}
+	var textErr *InvalidTextError
+	for i, item := range result.Items {
+		if item.Index != i {
+			t.Errorf("expected items in batch order, got index %d at %d", item.Index, i)
+		}
+		local := item.LocalErr != nil
+		if wantLocal := i == 1 || i == 2 || i == 3; local != wantLocal || local && (item.Transfer != nil || item.ErrorMessage == "") {
+			t.Errorf("item %d: unexpected outcome %+v", i, item)
+		}
+	}
+	if !errors.Is(result.Items[1].LocalErr, ErrNWTransferDuplicateRef) ||
+		!errors.As(result.Items[2].LocalErr, &textErr) ||
+		result.Items[3].LocalErr != invalid {
+		t.Errorf("unexpected local errors %v, %v, %v", result.Items[1].LocalErr, result.Items[2].LocalErr, result.Items[3].LocalErr)
+	}
+	if result.Items[4].Outcome != BatchItemOutcomeFailed || result.Items[4].ErrorCode != "INVALID_ACCOUNT" {
+		t.Errorf("expected NorthWind's rejection reported, got %+v", result.Items[4])
+	}
+
+	// Items rejected locally were never sent, so only NorthWind's answers are stored
+	stored, err := transferRepo.GetByUserIDAndBatch(ctx, userID, "rent")
+	if err != nil {
+		t.Fatalf("failed to load batch: %v", err)
+	}
+	if len(stored) != 2 {
+		t.Errorf("expected the two items NorthWind answered for stored, got %d", len(stored))
+	}
+}
+
+func TestNorthwindTransferService_SubmitBatch_AllItemsRejectedLocally(t *testing.T) {
+	api := &fakeNorthwindBatchAPI{}
+	svc, transferRepo := newBatchTestService(t, api)
+	userID := uuid.New()
+	ctx := context.Background()
+
+	batch := newTestBatch("rent", "REF-A")
+	batch.Invalid = map[int]error{0: errors.New("amount failed the required rule")}
+	result, err := svc.SubmitBatch(ctx, userID, batch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.TotalCount != 1 || result.FailedCount != 1 || result.SuccessCount != 0 {
+		t.Errorf("expected the one item reported failed, got %+v", result)
+	}
+	if len(api.submitted) != 0 {
+		t.Errorf("expected nothing sent to NorthWind, got %v", api.submitted)
+	}
+	if stored, _ := transferRepo.GetByUserIDAndBatch(ctx, userID, "rent"); len(stored) != 0 {
+		t.Errorf("expected nothing stored, got %d transfers", len(stored))
+	}
+}
+
+func TestNorthwindTransferService_RetryFailedBatch(t *testing.T) {
+	api := &fakeNorthwindBatchAPI{reject: map[string]bool{"REF-B": true, "REF-D": true}}
+	svc, transferRepo := newBatchTestService(t, api)
+	userID := uuid.New()
+	ctx := context.Background()
+
+	if _, err := svc.SubmitBatch(ctx, userID, newTestBatch("vendors", "REF-A", "REF-B", "REF-C", "REF-D")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	before, _ := transferRepo.GetByUserIDAndBatch(ctx, userID, "vendors")
+
+	// REF-B is fixed upstream, REF-D is still rejected
+	api.mu.Lock()
+	delete(api.reject, "REF-B")
+	api.mu.Unlock()
+
+	result, err := svc.RetryFailedBatch(ctx, userID, "vendors", RequestMetadata{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := api.submitted[len(api.submitted)-1]; len(got) != 2 || got[0] != "REF-B" || got[1] != "REF-D" {
+		t.Errorf("expected only the failed items to be resubmitted, got %v", got)
+	}
+	if result.TotalCount != 2 || result.SuccessCount != 1 || result.FailedCount != 1 {
+		t.Errorf("expected 2 retried, 1 succeeded, 1 failed, got %+v", result)
+	}
+	if result.Items[0].Index != 1 || result.Items[1].Index != 3 {
+		t.Errorf("expected items to keep their batch positions, got %d and %d", result.Items[0].Index, result.Items[1].Index)
+	}
+
+	after, _ := transferRepo.GetByUserIDAndBatch(ctx, userID, "vendors")
+	if len(after) != 4 {
+		t.Fatalf("expected the retry to update the batch in place, got %d transfers", len(after))
+	}
+	if after[1].ID != before[1].ID || after[1].Status != models.NWTransferStatusPending || after[1].ExternalRef == nil {
+		t.Errorf("expected REF-B to be accepted in place, got %s %s", after[1].ID, after[1].Status)
+	}
+	if after[3].Status != models.NWTransferStatusFailed || after[3].ExternalRef != nil {
+		t.Errorf("expected REF-D to stay FAILED, got %s", after[3].Status)
+	}
+	if *after[0].ExternalRef != *before[0].ExternalRef {
+		t.Errorf("expected the already accepted REF-A to be left alone")
+	}
+
+	// Nothing left to retry but REF-D; an unknown batch is reported as such
+	if _, err := svc.RetryFailedBatch(ctx, userID, "no-such-batch", RequestMetadata{}); !errors.Is(err, ErrNWTransferBatchNotFound) {
+		t.Errorf("expected ErrNWTransferBatchNotFound, got %v", err)
+	}
+}
+
+func TestNorthwindTransferService_RetryFailedBatch_SameDayPastCutoff(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	api := &fakeNorthwindBatchAPI{reject: map[string]bool{"REF-A": true, "REF-B": true}}
+	svc, transferRepo := newBatchTestService(t, api)
+	policy := newTestSameDayPolicy(t, time.Date(2025, time.June, 12, 10, 0, 0, 0, newYork))
+	svc.SetSameDayPolicy(policy)
+	userID := uuid.New()
+	ctx := context.Background()
+
+	batch := newTestBatch("payroll", "REF-A", "REF-B")
+	batch.Transfers[0].Priority = models.NWTransferPrioritySameDay
+	if _, err := svc.SubmitBatch(ctx, userID, batch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	before, _ := transferRepo.GetByUserIDAndBatch(ctx, userID, "payroll")
+
+	// Past the cutoff only the STANDARD item can be resent
+	policy.now = func() time.Time { return time.Date(2025, time.June, 12, 16, 0, 0, 0, newYork) }
+	api.mu.Lock()
+	api.reject = nil
+	api.mu.Unlock()
+	result, err := svc.RetryFailedBatch(ctx, userID, "payroll", RequestMetadata{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := api.submitted[len(api.submitted)-1]; len(got) != 1 || got[0] != "REF-B" {
+		t.Errorf("expected only REF-B resent, got %v", got)
+	}
+	var cutoff *SameDayCutoffPassedError
+	if result.SuccessCount != 1 || result.FailedCount != 1 || !errors.As(result.Items[0].LocalErr, &cutoff) {
+		t.Fatalf("expected REF-A reported past the cutoff and REF-B created, got %+v", result)
+	}
+	if result.Items[0].Transfer == nil || result.Items[0].Transfer.ID != before[0].ID {
+		t.Errorf("expected REF-A's stored transfer reported, got %+v", result.Items[0].Transfer)
+	}
+	after, _ := transferRepo.GetByUserIDAndBatch(ctx, userID, "payroll")
+	if after[0].Status != models.NWTransferStatusFailed || after[0].Version != before[0].Version {
+		t.Errorf("expected REF-A left as it was, got %s version %d", after[0].Status, after[0].Version)
+	}
+}
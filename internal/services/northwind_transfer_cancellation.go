@@ -0,0 +1,82 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/array/banking-api/internal/config"
+	"github.com/array/banking-api/internal/models"
+)
+
+var ErrCancellationWindowClosed = errors.New("cancellation window has closed")
+
+// CancellationWindowClosedError is returned by CancelTransfer when the transfer's cancellation
+// window closed before the request, so NorthWind is not asked. It wraps
+// ErrCancellationWindowClosed.
+type CancellationWindowClosedError struct {
+	TransferType string
+	Deadline     time.Time
+}
+
+func (e *CancellationWindowClosedError) Error() string {
+	return fmt.Sprintf("%s: %s transfers can only be cancelled until %s", ErrCancellationWindowClosed, e.TransferType, e.Deadline.UTC().Format(time.RFC3339))
+}
+
+func (e *CancellationWindowClosedError) Unwrap() error {
+	return ErrCancellationWindowClosed
+}
+
+// SetCancellationWindows sets how long transfers of each type stay cancellable; types without a
+// window are left to NorthWind
+func (s *NorthwindTransferService) SetCancellationWindows(windows map[string]config.CancellationWindow) {
+	s.cancelWindows = windows
+}
+
+// cancellationDeadline returns when the transfer's cancellation window closes: its window's
+// duration after initiation (or creation, before NorthWind reported the initiation), or its
+// processing date, falling back to the scheduled date. It is nil for terminal transfers, queued
+// or scheduled transfers that NorthWind has not seen, types without a window, and processing
+// dates not known yet.
+func (s *NorthwindTransferService) cancellationDeadline(transfer *models.NorthwindTransfer) *time.Time {
+	window, ok := s.cancelWindows[transfer.TransferType]
+	if !ok || transfer.IsTerminal() || transfer.AwaitingInitiation() {
+		return nil
+	}
+	if window.UntilProcessingDate {
+		if transfer.ProcessingDate != nil {
+			return transfer.ProcessingDate
+		}
+		return transfer.ScheduledDate
+	}
+	initiated := transfer.CreatedAt
+	if transfer.InitiatedDate != nil {
+		initiated = *transfer.InitiatedDate
+	}
+	deadline := initiated.Add(window.After)
+	return &deadline
+}
+
+// setComputedFields fills in the fields that are not stored, CancellableUntil and Failure, on
+// a transfer about to be returned
+func (s *NorthwindTransferService) setComputedFields(transfer *models.NorthwindTransfer) {
+	transfer.CancellableUntil = s.cancellationDeadline(transfer)
+	transfer.Failure = s.errorCodes.Describe(transfer)
+}
+
+// setPageComputedFields is setComputedFields for a page of transfers
+func (s *NorthwindTransferService) setPageComputedFields(transfers []models.NorthwindTransfer) {
+	for i := range transfers {
+		s.setComputedFields(&transfers[i])
+	}
+}
+
+// checkCancellationWindow returns a CancellationWindowClosedError when the transfer's window
+// closed before now
+func (s *NorthwindTransferService) checkCancellationWindow(transfer *models.NorthwindTransfer, now time.Time) error {
+	deadline := s.cancellationDeadline(transfer)
+	if deadline == nil || !now.After(*deadline) {
+		return nil
+	}
+	return &CancellationWindowClosedError{TransferType: transfer.TransferType, Deadline: *deadline}
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/config"
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/google/uuid"
+)
+
+var testCancellationWindows = map[string]config.CancellationWindow{
+	models.NWTransferTypeACH:  {UntilProcessingDate: true},
+	models.NWTransferTypeWire: {After: 15 * time.Minute},
+}
+
+func initiatedAt(at time.Time) testfactory.TransferOption {
+	return func(tr *models.NorthwindTransfer) {
+		tr.InitiatedDate = &at
+	}
+}
+
+func processingOn(at time.Time) testfactory.TransferOption {
+	return func(tr *models.NorthwindTransfer) {
+		tr.ProcessingDate = &at
+	}
+}
+
+func TestNorthwindTransferService_CancelTransfer_Window(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	tests := []struct {
+		name         string
+		transferType string
+		opt          testfactory.TransferOption
+		wantDeadline *time.Time
+		wantClosed   bool
+	}{
+		{"inside window", models.NWTransferTypeWire, initiatedAt(now.Add(-10 * time.Minute)), ptrTime(now.Add(5 * time.Minute)), false},
+		{"just past window", models.NWTransferTypeWire, initiatedAt(now.Add(-15*time.Minute - time.Second)), ptrTime(now.Add(-time.Second)), true},
+		{"before processing date", models.NWTransferTypeACH, processingOn(now.Add(time.Hour)), ptrTime(now.Add(time.Hour)), false},
+		{"past processing date", models.NWTransferTypeACH, processingOn(now.Add(-time.Second)), ptrTime(now.Add(-time.Second)), true},
+		{"no window", models.NWTransferTypeRTP, initiatedAt(now.Add(-72 * time.Hour)), nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := testfactory.NewDB(t)
+			userID := uuid.New()
+			transfer := testfactory.NWTransfer(t, db, testfactory.WithUser(userID), testfactory.WithTransferType(tt.transferType), tt.opt)
+
+			var cancels atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				cancels.Add(1)
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(northwind.TransferResponse{Status: "CANCELLED"})
+			}))
+			defer server.Close()
+
+			svc := NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), repositories.NewNorthwindTransferRepository(db), nil, nil, slog.Default())
+			svc.SetCancellationWindows(testCancellationWindows)
+
+			got, err := svc.GetTransfer(context.Background(), userID, transfer.ID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !sameDeadline(got.CancellableUntil, tt.wantDeadline) {
+				t.Errorf("expected cancellable until %v, got %v", tt.wantDeadline, got.CancellableUntil)
+			}
+
+			cancelled, err := svc.CancelTransfer(context.Background(), userID, transfer.ID, "changed my mind", models.UserInitiator(userID))
+			if tt.wantClosed {
+				var closed *CancellationWindowClosedError
+				if !errors.As(err, &closed) || !errors.Is(err, ErrCancellationWindowClosed) {
+					t.Fatalf("expected CancellationWindowClosedError, got %v", err)
+				}
+				if !closed.Deadline.Equal(*tt.wantDeadline) {
+					t.Errorf("expected the missed deadline %v, got %v", *tt.wantDeadline, closed.Deadline)
+				}
+				if cancels.Load() != 0 {
+					t.Errorf("expected NorthWind not to be asked, got %d calls", cancels.Load())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cancels.Load() != 1 || cancelled.Status != models.NWTransferStatusCancelled {
+				t.Errorf("expected the transfer cancelled with NorthWind, got %d calls and status %s", cancels.Load(), cancelled.Status)
+			}
+			if cancelled.CancellableUntil != nil {
+				t.Errorf("expected no cancellation deadline once cancelled, got %v", cancelled.CancellableUntil)
+			}
+		})
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
+
+func sameDeadline(got, want *time.Time) bool {
+	if got == nil || want == nil {
+		return got == want
+	}
+	return got.Equal(*want)
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"time"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// TransferFieldDiff compares one field of a local transfer with NorthWind's record. A nil side
+// means the field is unset there.
+type TransferFieldDiff struct {
+	Field  string  `json:"field"`
+	Local  *string `json:"local"`
+	Remote *string `json:"remote"`
+	Match  bool    `json:"match"`
+}
+
+// TransferComparison pairs a local transfer with NorthWind's live record. Remote is nil and
+// RemoteMissing is set when NorthWind has no such transfer. Origin is the initiating client,
+// which the local transfer itself never serializes. InitiationRequestID and RemoteRequestID are
+// NorthWind's request IDs for the call that initiated the transfer and for this comparison's
+// fetch, to quote to NorthWind support.
+type TransferComparison struct {
+	Local               *models.NorthwindTransfer      `json:"local"`
+	Origin              models.NorthwindTransferOrigin `json:"origin"`
+	Remote              *northwind.TransferResponse    `json:"remote"`
+	RemoteMissing       bool                           `json:"remote_missing"`
+	Mismatches          int                            `json:"mismatches"`
+	Fields              []TransferFieldDiff            `json:"fields"`
+	InitiationRequestID *string                        `json:"initiation_request_id,omitempty"`
+	RemoteRequestID     string                         `json:"remote_request_id,omitempty"`
+}
+
+// DiffTransfer compares status, amount, currency, fee and lifecycle dates of the local transfer
+// with NorthWind's record. Upstream statuses are mapped to local ones, amounts compare by value
+// and dates compare as instants to the second.
+func DiffTransfer(local *models.NorthwindTransfer, remote *northwind.TransferResponse) []TransferFieldDiff {
+	remoteAmount := decimal.NewFromFloat(remote.Amount)
+	var remoteFee *decimal.Decimal
+	if remote.Fee != nil {
+		fee := decimal.NewFromFloat(*remote.Fee)
+		remoteFee = &fee
+	}
+
+	// An unknown status is compared as NorthWind sent it, so it always shows as a mismatch
+	remoteStatus, ok := northwind.MapStatus(remote.Status)
+	if !ok {
+		remoteStatus = remote.Status
+	}
+
+	return []TransferFieldDiff{
+		stringFieldDiff("status", local.Status, remoteStatus),
+		decimalFieldDiff("amount", &local.Amount, &remoteAmount),
+		stringFieldDiff("currency", local.Currency, remote.Currency),
+		decimalFieldDiff("fee", local.Fee, remoteFee),
+		dateFieldDiff("initiated_date", local.InitiatedDate, remote.InitiatedDate),
+		dateFieldDiff("processing_date", local.ProcessingDate, remote.ProcessingDate),
+		dateFieldDiff("expected_completion_date", local.ExpectedCompletionDate, remote.ExpectedCompletionDate),
+		dateFieldDiff("completed_date", local.CompletedDate, remote.CompletedDate),
+	}
+}
+
+func stringFieldDiff(field, local, remote string) TransferFieldDiff {
+	return TransferFieldDiff{Field: field, Local: &local, Remote: &remote, Match: local == remote}
+}
+
+func decimalFieldDiff(field string, local, remote *decimal.Decimal) TransferFieldDiff {
+	diff := TransferFieldDiff{Field: field}
+	if local != nil {
+		s := local.String()
+		diff.Local = &s
+	}
+	if remote != nil {
+		s := remote.String()
+		diff.Remote = &s
+	}
+	diff.Match = (local == nil && remote == nil) || (local != nil && remote != nil && local.Equal(*remote))
+	return diff
+}
+
+// dateFieldDiff compares a local timestamp with NorthWind's RFC 3339 string. An unparsable
+// remote value is shown verbatim and never matches.
+func dateFieldDiff(field string, local *time.Time, remote string) TransferFieldDiff {
+	diff := TransferFieldDiff{Field: field}
+	var localValue string
+	if local != nil {
+		localValue = local.UTC().Truncate(time.Second).Format(time.RFC3339)
+		diff.Local = &localValue
+	}
+	if remote == "" {
+		diff.Match = local == nil
+		return diff
+	}
+
+	remoteValue := remote
+	if parsed := northwind.ParseRFC3339Optional(remote); parsed != nil {
+		remoteValue = parsed.UTC().Truncate(time.Second).Format(time.RFC3339)
+		diff.Match = local != nil && localValue == remoteValue
+	}
+	diff.Remote = &remoteValue
+	return diff
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/shopspring/decimal"
+)
+
+func diffByField(diffs []TransferFieldDiff) map[string]TransferFieldDiff {
+	byField := make(map[string]TransferFieldDiff, len(diffs))
+	for _, d := range diffs {
+		byField[d.Field] = d
+	}
+	return byField
+}
+
+func TestDiffTransfer_Matching(t *testing.T) {
+	completed := time.Date(2026, 3, 2, 15, 4, 5, 123000000, time.UTC)
+	fee := decimal.NewFromFloat(1.5)
+	local := testfactory.NewNWTransfer(testfactory.WithStatus(models.NWTransferStatusCompleted), func(tr *models.NorthwindTransfer) {
+		tr.Fee = &fee
+		tr.CompletedDate = &completed
+	})
+	remoteFee := 1.50
+	remote := &northwind.TransferResponse{
+		Status:        "completed",
+		Amount:        100.5,
+		Currency:      "USD",
+		Fee:           &remoteFee,
+		CompletedDate: "2026-03-02T10:04:05-05:00",
+	}
+
+	for _, d := range DiffTransfer(local, remote) {
+		if !d.Match {
+			t.Errorf("expected %s to match, local=%v remote=%v", d.Field, diffValue(d.Local), diffValue(d.Remote))
+		}
+	}
+}
+
+func TestDiffTransfer_Mismatches(t *testing.T) {
+	initiated := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	local := testfactory.NewNWTransfer(func(tr *models.NorthwindTransfer) {
+		tr.InitiatedDate = &initiated
+	})
+	remoteFee := 0.25
+	remote := &northwind.TransferResponse{
+		Status:         "FAILED",
+		Amount:         100.49,
+		Currency:       "USD",
+		Fee:            &remoteFee,
+		InitiatedDate:  "2026-03-01T09:00:01Z",
+		ProcessingDate: "yesterday",
+		CompletedDate:  "2026-03-02T00:00:00Z",
+	}
+
+	diffs := diffByField(DiffTransfer(local, remote))
+
+	tests := []struct {
+		field  string
+		match  bool
+		local  string
+		remote string
+	}{
+		{"status", false, models.NWTransferStatusPending, models.NWTransferStatusFailed},
+		{"amount", false, "100.5", "100.49"},
+		{"currency", true, "USD", "USD"},
+		{"fee", false, "", "0.25"},
+		{"initiated_date", false, "2026-03-01T09:00:00Z", "2026-03-01T09:00:01Z"},
+		{"processing_date", false, "", "yesterday"},
+		{"expected_completion_date", true, "", ""},
+		{"completed_date", false, "", "2026-03-02T00:00:00Z"},
+	}
+	for _, tt := range tests {
+		d, ok := diffs[tt.field]
+		if !ok {
+			t.Errorf("missing field %s", tt.field)
+			continue
+		}
+		if d.Match != tt.match || diffValue(d.Local) != tt.local || diffValue(d.Remote) != tt.remote {
+			t.Errorf("%s: got match=%v local=%q remote=%q, want match=%v local=%q remote=%q",
+				tt.field, d.Match, diffValue(d.Local), diffValue(d.Remote), tt.match, tt.local, tt.remote)
+		}
+	}
+}
+
+func diffValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/google/uuid"
+)
+
+func newCursorTestService(t *testing.T) (*NorthwindTransferService, uuid.UUID, func(time.Time) *models.NorthwindTransfer) {
+	t.Helper()
+	db := testfactory.NewDB(t)
+	userID := uuid.New()
+	create := func(at time.Time) *models.NorthwindTransfer {
+		return testfactory.NWTransfer(t, db, testfactory.WithUser(userID), testfactory.WithCreatedAt(at))
+	}
+	svc := NewNorthwindTransferService(nil, repositories.NewNorthwindTransferRepository(db), nil, nil, slog.Default())
+	svc.SetCursorSigning([]byte("cursor-test-key"), time.Hour)
+	return svc, userID, create
+}
+
+func TestNorthwindTransferService_ListTransfersAfter_PagesForward(t *testing.T) {
+	svc, userID, create := newCursorTestService(t)
+	ctx := context.Background()
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < 7; i++ {
+		create(base.Add(time.Duration(i) * time.Minute))
+	}
+
+	var pages [][]models.NorthwindTransfer
+	cursor := ""
+	for i := 0; i < 3; i++ {
+		transfers, next, err := svc.ListTransfersAfter(ctx, userID, models.NorthwindTransferFilters{}, cursor, 3)
+		if err != nil {
+			t.Fatalf("page %d: unexpected error: %v", i, err)
+		}
+		pages = append(pages, transfers)
+		cursor = next
+		// A transfer created between requests is newer than every listed one and must not
+		// push an already-seen row onto the next page
+		create(time.Now())
+	}
+
+	if len(pages[0]) != 3 || len(pages[1]) != 3 || len(pages[2]) != 1 {
+		t.Fatalf("expected pages of 3, 3 and 1, got %d, %d and %d", len(pages[0]), len(pages[1]), len(pages[2]))
+	}
+	if cursor != "" {
+		t.Errorf("expected no cursor after the last page, got %q", cursor)
+	}
+	seen := make(map[uuid.UUID]bool)
+	var previous time.Time
+	for _, page := range pages {
+		for _, tr := range page {
+			if seen[tr.ID] {
+				t.Errorf("transfer %s returned twice", tr.ID)
+			}
+			seen[tr.ID] = true
+			if !previous.IsZero() && tr.CreatedAt.After(previous) {
+				t.Errorf("transfers are not newest first")
+			}
+			previous = tr.CreatedAt
+		}
+	}
+}
+
+func TestNorthwindTransferService_ListTransfersAfter_RejectsBadCursors(t *testing.T) {
+	svc, userID, create := newCursorTestService(t)
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		create(time.Now().Add(-time.Duration(i) * time.Minute))
+	}
+	pending := models.NorthwindTransferFilters{Status: models.NWTransferStatusPending}
+	_, cursor, err := svc.ListTransfersAfter(ctx, userID, pending, "", 1)
+	if err != nil || cursor == "" {
+		t.Fatalf("expected a next cursor, got %q, %v", cursor, err)
+	}
+
+	payload, mac, _ := strings.Cut(cursor, ".")
+	tampered := payload[:len(payload)-2] + "AA." + mac
+	cases := map[string]struct {
+		cursor  string
+		userID  uuid.UUID
+		filters models.NorthwindTransferFilters
+	}{
+		"garbage":         {"not-a-cursor", userID, pending},
+		"tampered":        {tampered, userID, pending},
+		"other user":      {cursor, uuid.New(), pending},
+		"changed filters": {cursor, userID, models.NorthwindTransferFilters{}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := svc.ListTransfersAfter(ctx, tc.userID, tc.filters, tc.cursor, 1)
+			if !errors.Is(err, ErrInvalidTransferCursor) {
+				t.Errorf("expected ErrInvalidTransferCursor, got %v", err)
+			}
+		})
+	}
+
+	t.Run("expired", func(t *testing.T) {
+		svc.cursors.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+		defer func() { svc.cursors.now = time.Now }()
+		_, _, err := svc.ListTransfersAfter(ctx, userID, pending, cursor, 1)
+		if !errors.Is(err, ErrTransferCursorExpired) {
+			t.Errorf("expected ErrTransferCursorExpired, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// DefaultDisputeWindow is how long after completion a transfer may be disputed unless SetWindow
+// says otherwise
+const DefaultDisputeWindow = 60 * 24 * time.Hour
+
+// Decisions an admin can resolve a dispute with
+const (
+	DisputeDecisionReverse = "reverse"
+	DisputeDecisionReject  = "reject"
+)
+
+var (
+	ErrNWDisputeNotFound     = errors.New("transfer dispute not found")
+	ErrNWDisputeNotCompleted = errors.New("only completed transfers can be disputed")
+	ErrNWDisputeWindowClosed = errors.New("dispute window has closed")
+	ErrNWDisputeAlreadyOpen  = errors.New("transfer already has an unresolved dispute")
+	// ErrNWDisputeTransition is returned when a dispute is not in a status the requested step can
+	// move it from, such as resolving one that is already resolved
+	ErrNWDisputeTransition = errors.New("dispute cannot make that transition")
+)
+
+// OpenDisputeRequest is a customer's dispute of one of their completed transfers
+type OpenDisputeRequest struct {
+	ReasonCategory string `json:"reason_category" validate:"required,oneof=UNAUTHORIZED INCORRECT_AMOUNT DUPLICATE NOT_RECEIVED OTHER"`
+	Description    string `json:"description" validate:"max=1000"`
+}
+
+// ResolveDisputeRequest is an admin's decision on a dispute: reverse upholds it and reverses the
+// transfer with NorthWind, reject leaves the transfer as it is
+type ResolveDisputeRequest struct {
+	Decision string `json:"decision" validate:"required,oneof=reverse reject"`
+	Note     string `json:"note" validate:"max=1000"`
+}
+
+// TransferDisputeService lets customers dispute their completed transfers within the dispute
+// window and admins review and resolve those disputes. Upholding one reverses the transfer
+// through NorthwindTransferService, which records the dispute on the reversal's audit event.
+type TransferDisputeService struct {
+	repo      repositories.TransferDisputeRepositoryInterface
+	transfers *NorthwindTransferService
+	window    time.Duration
+	now       func() time.Time
+	logger    *slog.Logger
+}
+
+// NewTransferDisputeService creates a transfer dispute service
+func NewTransferDisputeService(repo repositories.TransferDisputeRepositoryInterface, transfers *NorthwindTransferService, logger *slog.Logger) *TransferDisputeService {
+	return &TransferDisputeService{
+		repo:      repo,
+		transfers: transfers,
+		window:    DefaultDisputeWindow,
+		now:       time.Now,
+		logger:    logger,
+	}
+}
+
+// SetWindow sets how long after completion a transfer may be disputed; non-positive values keep
+// DefaultDisputeWindow
+func (s *TransferDisputeService) SetWindow(window time.Duration) {
+	if window > 0 {
+		s.window = window
+	}
+}
+
+// Open records userID's dispute of their transfer. The transfer must be COMPLETED, within the
+// window of its completion date, and have no unresolved dispute.
+func (s *TransferDisputeService) Open(ctx context.Context, userID, transferID uuid.UUID, req OpenDisputeRequest) (*models.TransferDispute, error) {
+	transfer, err := s.transfers.GetTransfer(ctx, userID, transferID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.Status != models.NWTransferStatusCompleted {
+		return nil, ErrNWDisputeNotCompleted
+	}
+	if s.now().After(s.deadline(transfer)) {
+		return nil, ErrNWDisputeWindowClosed
+	}
+	// The unique index on unresolved disputes catches a concurrent second one
+	if _, err := s.repo.GetUnresolvedByTransfer(ctx, transfer.ID); err == nil {
+		return nil, ErrNWDisputeAlreadyOpen
+	} else if !errors.Is(err, repositories.ErrTransferDisputeNotFound) {
+		return nil, err
+	}
+
+	dispute := &models.TransferDispute{
+		TransferID:     transfer.ID,
+		UserID:         userID,
+		ReasonCategory: req.ReasonCategory,
+		Description:    sanitizeText(req.Description),
+		Status:         models.DisputeStatusOpen,
+	}
+	if err := s.repo.Create(ctx, dispute); err != nil {
+		if errors.Is(err, repositories.ErrTransferDisputeAlreadyOpen) {
+			return nil, ErrNWDisputeAlreadyOpen
+		}
+		return nil, err
+	}
+
+	s.logger.Info("Transfer disputed",
+		"dispute_id", dispute.ID,
+		"transfer_id", transfer.ID,
+		"reason_category", dispute.ReasonCategory,
+	)
+	return dispute, nil
+}
+
+// deadline measures the window from NorthWind's completion date, or from when we last updated
+// the transfer when NorthWind sent none
+func (s *TransferDisputeService) deadline(transfer *models.NorthwindTransfer) time.Time {
+	completed := transfer.UpdatedAt
+	if transfer.CompletedDate != nil {
+		completed = *transfer.CompletedDate
+	}
+	return completed.Add(s.window)
+}
+
+// ListForTransfer returns the disputes of userID's transfer, newest first
+func (s *TransferDisputeService) ListForTransfer(ctx context.Context, userID, transferID uuid.UUID) ([]models.TransferDispute, error) {
+	if _, err := s.transfers.GetTransfer(ctx, userID, transferID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByTransfer(ctx, transferID)
+}
+
+// List returns a page of disputes in status, or in any status when it is empty, for admins
+func (s *TransferDisputeService) List(ctx context.Context, status string, offset, limit int) ([]models.TransferDispute, int64, error) {
+	return s.repo.List(ctx, status, offset, limit)
+}
+
+// StartReview moves an open dispute under review by adminID
+func (s *TransferDisputeService) StartReview(ctx context.Context, disputeID, adminID uuid.UUID) (*models.TransferDispute, error) {
+	dispute, err := s.get(ctx, disputeID)
+	if err != nil {
+		return nil, err
+	}
+	if dispute.Status != models.DisputeStatusOpen {
+		return nil, ErrNWDisputeTransition
+	}
+
+	dispute.Status = models.DisputeStatusUnderReview
+	dispute.ReviewerID = &adminID
+	if err := s.transition(ctx, dispute, models.DisputeStatusOpen); err != nil {
+		return nil, err
+	}
+	return dispute, nil
+}
+
+// Resolve records adminID's decision on an unresolved dispute. Reversing asks NorthWind to
+// reverse the transfer first and leaves the dispute unresolved when it refuses; the dispute is
+// only marked REVERSED once the reversal went through.
+func (s *TransferDisputeService) Resolve(ctx context.Context, disputeID, adminID uuid.UUID, req ResolveDisputeRequest) (*models.TransferDispute, error) {
+	dispute, err := s.get(ctx, disputeID)
+	if err != nil {
+		return nil, err
+	}
+	if dispute.IsResolved() {
+		return nil, ErrNWDisputeTransition
+	}
+
+	note := sanitizeText(req.Note)
+	resolution := models.DisputeResolutionRejected
+	if req.Decision == DisputeDecisionReverse {
+		// The note can outgrow NorthWind's description limit; the dispute ID ties its record to ours
+		description := "Dispute " + dispute.ID.String()
+		if _, err := s.transfers.ReverseDisputedTransfer(ctx, dispute.TransferID, dispute.ID, dispute.ReasonCategory, description, adminID); err != nil {
+			return nil, err
+		}
+		resolution = models.DisputeResolutionReversed
+	}
+
+	now := s.now()
+	dispute.Status = models.DisputeStatusResolved
+	dispute.Resolution = &resolution
+	if note != "" {
+		dispute.ResolutionNote = &note
+	}
+	dispute.ReviewerID = &adminID
+	dispute.ResolvedAt = &now
+	if err := s.transition(ctx, dispute, models.DisputeStatusOpen, models.DisputeStatusUnderReview); err != nil {
+		if resolution == models.DisputeResolutionReversed {
+			s.logger.Error("Transfer reversed for a dispute that could not be marked resolved",
+				"dispute_id", dispute.ID,
+				"transfer_id", dispute.TransferID,
+				"error", err,
+			)
+		}
+		return nil, err
+	}
+
+	s.logger.Info("Transfer dispute resolved",
+		"dispute_id", dispute.ID,
+		"transfer_id", dispute.TransferID,
+		"resolution", resolution,
+		"reviewer_id", adminID,
+	)
+	return dispute, nil
+}
+
+func (s *TransferDisputeService) get(ctx context.Context, disputeID uuid.UUID) (*models.TransferDispute, error) {
+	dispute, err := s.repo.GetByID(ctx, disputeID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrTransferDisputeNotFound) {
+			return nil, ErrNWDisputeNotFound
+		}
+		return nil, err
+	}
+	return dispute, nil
+}
+
+// transition stores the dispute's new state provided it is still in one of from, which turns a
+// concurrent change by another admin into ErrNWDisputeTransition
+func (s *TransferDisputeService) transition(ctx context.Context, dispute *models.TransferDispute, from ...string) error {
+	if err := s.repo.Transition(ctx, dispute, from); err != nil {
+		if errors.Is(err, repositories.ErrTransferDisputeStatusChanged) {
+			return ErrNWDisputeTransition
+		}
+		return fmt.Errorf("failed to update dispute: %w", err)
+	}
+	return nil
+}
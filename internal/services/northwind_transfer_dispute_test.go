@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// newDisputeTestService returns a dispute service with a 30-day window whose NorthWind answers
+// every reverse request with REVERSED
+func newDisputeTestService(t *testing.T, db *gorm.DB) *TransferDisputeService {
+	t.Helper()
+	svc := NewTransferDisputeService(repositories.NewTransferDisputeRepository(db), newInitiatorTestService(t, db, "REVERSED"), slog.Default())
+	svc.SetWindow(30 * 24 * time.Hour)
+	return svc
+}
+
+func TestTransferDisputeService_Open_Window(t *testing.T) {
+	tests := []struct {
+		name      string
+		completed time.Time
+		want      error
+	}{
+		{"within the window", time.Now().Add(-29 * 24 * time.Hour), nil},
+		{"after the window", time.Now().Add(-31 * 24 * time.Hour), ErrNWDisputeWindowClosed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := testfactory.NewDB(t)
+			userID := uuid.New()
+			transfer := testfactory.NWTransfer(t, db, testfactory.WithUser(userID),
+				testfactory.WithStatus(models.NWTransferStatusCompleted), testfactory.WithCompletedDate(tt.completed))
+			svc := newDisputeTestService(t, db)
+
+			dispute, err := svc.Open(context.Background(), userID, transfer.ID, OpenDisputeRequest{ReasonCategory: models.DisputeReasonUnauthorized})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+			if tt.want == nil && (dispute.Status != models.DisputeStatusOpen || dispute.TransferID != transfer.ID) {
+				t.Errorf("expected an open dispute of the transfer, got %+v", dispute)
+			}
+		})
+	}
+}
+
+func TestTransferDisputeService_Open_Rejections(t *testing.T) {
+	db := testfactory.NewDB(t)
+	userID := uuid.New()
+	svc := newDisputeTestService(t, db)
+	req := OpenDisputeRequest{ReasonCategory: models.DisputeReasonDuplicate}
+
+	pending := testfactory.NWTransfer(t, db, testfactory.WithUser(userID))
+	if _, err := svc.Open(context.Background(), userID, pending.ID, req); !errors.Is(err, ErrNWDisputeNotCompleted) {
+		t.Errorf("expected ErrNWDisputeNotCompleted for a pending transfer, got %v", err)
+	}
+
+	completed := testfactory.NWTransfer(t, db, testfactory.WithUser(userID), testfactory.WithStatus(models.NWTransferStatusCompleted))
+	if _, err := svc.Open(context.Background(), uuid.New(), completed.ID, req); !errors.Is(err, ErrNWTransferNotFound) {
+		t.Errorf("expected ErrNWTransferNotFound for another user's transfer, got %v", err)
+	}
+	if _, err := svc.Open(context.Background(), userID, completed.ID, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.Open(context.Background(), userID, completed.ID, req); !errors.Is(err, ErrNWDisputeAlreadyOpen) {
+		t.Errorf("expected ErrNWDisputeAlreadyOpen for a second dispute, got %v", err)
+	}
+}
+
+func TestTransferDisputeService_Transitions(t *testing.T) {
+	db := testfactory.NewDB(t)
+	userID := uuid.New()
+	adminID := uuid.New()
+	transfer := testfactory.NWTransfer(t, db, testfactory.WithUser(userID), testfactory.WithStatus(models.NWTransferStatusCompleted))
+	svc := newDisputeTestService(t, db)
+	ctx := context.Background()
+
+	dispute, err := svc.Open(ctx, userID, transfer.ID, OpenDisputeRequest{ReasonCategory: models.DisputeReasonIncorrectAmount})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reviewed, err := svc.StartReview(ctx, dispute.ID, adminID)
+	if err != nil || reviewed.Status != models.DisputeStatusUnderReview || !sameUUID(reviewed.ReviewerID, &adminID) {
+		t.Fatalf("expected the dispute under review by the admin, got %+v (%v)", reviewed, err)
+	}
+	if _, err := svc.StartReview(ctx, dispute.ID, adminID); !errors.Is(err, ErrNWDisputeTransition) {
+		t.Errorf("expected ErrNWDisputeTransition reviewing twice, got %v", err)
+	}
+
+	resolved, err := svc.Resolve(ctx, dispute.ID, adminID, ResolveDisputeRequest{Decision: DisputeDecisionReject, Note: "amount matches the invoice"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resolved.Status != models.DisputeStatusResolved || *resolved.Resolution != models.DisputeResolutionRejected || resolved.ResolvedAt == nil {
+		t.Errorf("expected the dispute rejected, got %+v", resolved)
+	}
+	if _, err := svc.Resolve(ctx, dispute.ID, adminID, ResolveDisputeRequest{Decision: DisputeDecisionReject}); !errors.Is(err, ErrNWDisputeTransition) {
+		t.Errorf("expected ErrNWDisputeTransition resolving twice, got %v", err)
+	}
+	if _, err := svc.StartReview(ctx, uuid.New(), adminID); !errors.Is(err, ErrNWDisputeNotFound) {
+		t.Errorf("expected ErrNWDisputeNotFound, got %v", err)
+	}
+
+	stored, err := repositories.NewNorthwindTransferRepository(db).GetByID(ctx, transfer.ID)
+	if err != nil || stored.Status != models.NWTransferStatusCompleted {
+		t.Errorf("expected a rejected dispute to leave the transfer COMPLETED, got %+v (%v)", stored, err)
+	}
+	// Once resolved, the transfer can be disputed again
+	if _, err := svc.Open(ctx, userID, transfer.ID, OpenDisputeRequest{ReasonCategory: models.DisputeReasonOther}); err != nil {
+		t.Errorf("expected a new dispute after the first was resolved, got %v", err)
+	}
+}
+
+func TestTransferDisputeService_Resolve_ReversesTransfer(t *testing.T) {
+	db := testfactory.NewDB(t)
+	userID := uuid.New()
+	adminID := uuid.New()
+	transfer := testfactory.NWTransfer(t, db, testfactory.WithUser(userID), testfactory.WithStatus(models.NWTransferStatusCompleted))
+	svc := newDisputeTestService(t, db)
+	ctx := context.Background()
+
+	dispute, err := svc.Open(ctx, userID, transfer.ID, OpenDisputeRequest{ReasonCategory: models.DisputeReasonUnauthorized, Description: "not me"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resolved, err := svc.Resolve(ctx, dispute.ID, adminID, ResolveDisputeRequest{Decision: DisputeDecisionReverse})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resolved.Status != models.DisputeStatusResolved || *resolved.Resolution != models.DisputeResolutionReversed {
+		t.Errorf("expected the dispute resolved as REVERSED, got %+v", resolved)
+	}
+
+	stored, err := repositories.NewNorthwindTransferRepository(db).GetByID(ctx, transfer.ID)
+	if err != nil || stored.Status != models.NWTransferStatusReversed {
+		t.Fatalf("expected the transfer REVERSED, got %+v (%v)", stored, err)
+	}
+	assertInitiatorRecorded(t, db, transfer.ID, models.AuditActionNorthwindTransferReversed, models.NWTransferEventSourceAdmin, models.AdminInitiator(adminID))
+	var audit models.AuditLog
+	if err := db.Where("action = ? AND resource_id = ?", models.AuditActionNorthwindTransferReversed, transfer.ID.String()).First(&audit).Error; err != nil {
+		t.Fatalf("expected a reversal audit event: %v", err)
+	}
+	if audit.Metadata["dispute_id"] != dispute.ID.String() {
+		t.Errorf("expected the reversal audit event to carry dispute %s, got %v", dispute.ID, audit.Metadata)
+	}
+}
@@ -0,0 +1,281 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// newInitiatorTestService returns a transfer service, with audit events, whose NorthWind answers
+// every cancel and reverse request with status
+func newInitiatorTestService(t *testing.T, db *gorm.DB, status string) *NorthwindTransferService {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(northwind.TransferResponse{Status: status})
+	}))
+	t.Cleanup(server.Close)
+
+	svc := NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), repositories.NewNorthwindTransferRepository(db), nil, nil, slog.Default())
+	svc.SetAuditService(NewAuditService(repositories.NewAuditLogRepository(db)))
+	return svc
+}
+
+// assertInitiatorRecorded checks the transfer's stored initiator, its last status-history event
+// and the audit event for action
+func assertInitiatorRecorded(t *testing.T, db *gorm.DB, transferID uuid.UUID, action, source string, initiator models.TransferInitiator) {
+	t.Helper()
+	stored, err := repositories.NewNorthwindTransferRepository(db).GetByID(context.Background(), transferID)
+	if err != nil {
+		t.Fatalf("failed to load transfer: %v", err)
+	}
+	if stored.CancellationInitiator == nil || *stored.CancellationInitiator != initiator.Initiator || !sameUUID(stored.CancellationInitiatorID, initiator.InitiatorID) {
+		t.Errorf("expected the transfer to record initiator %+v, got %v/%v", initiator, stored.CancellationInitiator, stored.CancellationInitiatorID)
+	}
+
+	events, err := repositories.NewNorthwindTransferRepository(db).ListEvents(context.Background(), transferID)
+	if err != nil || len(events) == 0 {
+		t.Fatalf("expected a status-history event, got %v (%v)", events, err)
+	}
+	if event := events[len(events)-1]; event.Source != source || !sameUUID(event.InitiatorID, initiator.InitiatorID) {
+		t.Errorf("expected a %s event by %v, got %+v", source, initiator.InitiatorID, event)
+	}
+
+	var audit models.AuditLog
+	if err := db.Where("action = ? AND resource_id = ?", action, transferID.String()).First(&audit).Error; err != nil {
+		t.Fatalf("expected a %s audit event: %v", action, err)
+	}
+	if audit.Metadata["initiator"] != initiator.Initiator || !sameUUID(audit.UserID, initiator.InitiatorID) {
+		t.Errorf("expected the audit event attributed to %+v, got user %v and metadata %v", initiator, audit.UserID, audit.Metadata)
+	}
+}
+
+func sameUUID(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func TestNorthwindTransferService_CancelTransfer_RecordsUserInitiator(t *testing.T) {
+	db := testfactory.NewDB(t)
+	userID := uuid.New()
+	transfer := testfactory.NWTransfer(t, db, testfactory.WithUser(userID), testfactory.WithTransferType(models.NWTransferTypeRTP))
+	svc := newInitiatorTestService(t, db, "CANCELLED")
+
+	if _, err := svc.CancelTransfer(context.Background(), userID, transfer.ID, "changed my mind", models.UserInitiator(userID)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertInitiatorRecorded(t, db, transfer.ID, models.AuditActionNorthwindTransferCancelled, models.NWTransferEventSourceUser, models.UserInitiator(userID))
+}
+
+func TestNorthwindTransferService_CancelAllPendingTransfers_RecordsInitiator(t *testing.T) {
+	tests := []struct {
+		name      string
+		initiator models.TransferInitiator
+		source    string
+	}{
+		{"admin", models.AdminInitiator(uuid.New()), models.NWTransferEventSourceAdmin},
+		{"system", models.SystemInitiator(), models.NWTransferEventSourceSystem},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := testfactory.NewDB(t)
+			userID := uuid.New()
+			transfer := testfactory.NWTransfer(t, db, testfactory.WithUser(userID))
+			svc := newInitiatorTestService(t, db, "CANCELLED")
+
+			results, err := svc.CancelAllPendingTransfers(context.Background(), userID, "compromised account", tt.initiator)
+			if err != nil || len(results) != 1 || results[0].Outcome != BulkCancelOutcomeCancelled {
+				t.Fatalf("expected the transfer cancelled, got %+v (%v)", results, err)
+			}
+			assertInitiatorRecorded(t, db, transfer.ID, models.AuditActionNorthwindTransferCancelled, tt.source, tt.initiator)
+		})
+	}
+}
+
+func TestNorthwindTransferService_ReverseTransfer_RecordsUserInitiator(t *testing.T) {
+	db := testfactory.NewDB(t)
+	userID := uuid.New()
+	transfer := testfactory.NWTransfer(t, db, testfactory.WithUser(userID), testfactory.WithStatus(models.NWTransferStatusCompleted))
+	svc := newInitiatorTestService(t, db, "REVERSED")
+
+	reversed, err := svc.ReverseTransfer(context.Background(), userID, transfer.ID, "duplicate", "", models.UserInitiator(userID))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reversed.Status != models.NWTransferStatusReversed {
+		t.Errorf("expected REVERSED, got %s", reversed.Status)
+	}
+	assertInitiatorRecorded(t, db, transfer.ID, models.AuditActionNorthwindTransferReversed, models.NWTransferEventSourceUser, models.UserInitiator(userID))
+}
+
+func TestNorthwindTransferService_ReverseTransfer_NotifiesRegulator(t *testing.T) {
+	db := testfactory.NewDB(t)
+	userID := uuid.New()
+	transfer := testfactory.NWTransfer(t, db, testfactory.WithUser(userID), testfactory.WithStatus(models.NWTransferStatusCompleted))
+	notifRepo := repositories.NewRegulatorNotificationRepository(db)
+	regulatorSvc := NewRegulatorService("http://regulator.invalid/webhook", 2, 60,
+		notifRepo, repositories.NewRegulatorNotificationAttemptRepository(db), slog.Default(), http.DefaultClient)
+	completion, err := regulatorSvc.createNotification(context.Background(), transfer, models.NWTransferStatusCompleted, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	svc := newInitiatorTestService(t, db, "REVERSED")
+	svc.SetRegulatorService(regulatorSvc)
+
+	if _, err := svc.ReverseTransfer(context.Background(), userID, transfer.ID, "duplicate", "", models.UserInitiator(userID)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reversal, err := notifRepo.GetByTransferAndStatus(context.Background(), transfer.ID, models.NWTransferStatusReversed)
+	if err != nil {
+		t.Fatalf("expected a REVERSED notification: %v", err)
+	}
+	if reversal.RelatedNotificationID == nil || *reversal.RelatedNotificationID != completion.ID {
+		t.Errorf("expected the reversal linked to %s, got %v", completion.ID, reversal.RelatedNotificationID)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(reversal.Payload, &payload); err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+	if payload["original_event_id"] != models.EventIDFromPayload(completion.Payload) || payload["cancellation_origin"] != models.NWInitiatorUser {
+		t.Errorf("unexpected reversal payload: %v", payload)
+	}
+}
+
+func TestRegulatorService_PayloadCarriesCancellationOrigin(t *testing.T) {
+	db := testfactory.NewDB(t)
+	regulatorSvc := NewRegulatorService("http://regulator.invalid/webhook", 2, 60,
+		repositories.NewRegulatorNotificationRepository(db), repositories.NewRegulatorNotificationAttemptRepository(db), slog.Default(), http.DefaultClient)
+
+	system := models.NWInitiatorSystem
+	cancelRequested := testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusFailed))
+	cancelRequested.CancellationInitiator = &system
+	untouched := testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusCompleted))
+
+	for transfer, want := range map[*models.NorthwindTransfer]string{cancelRequested: models.NWInitiatorSystem, untouched: ""} {
+		notification, err := regulatorSvc.createNotification(context.Background(), transfer, transfer.Status, time.Now())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var payload map[string]interface{}
+		if err := json.Unmarshal(notification.Payload, &payload); err != nil {
+			t.Fatalf("invalid payload: %v", err)
+		}
+		got, present := payload["cancellation_origin"]
+		if want == "" && present {
+			t.Errorf("expected no cancellation_origin without a cancellation, got %v", got)
+		}
+		if want != "" && got != want {
+			t.Errorf("expected cancellation_origin %q, got %v", want, got)
+		}
+	}
+}
+
+func TestNorthwindTransferService_OpaqueNorthwindID_UsedUpstream(t *testing.T) {
+	const opaqueID = "NWTR_8fK2-000931"
+	var mu sync.Mutex
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		status := "CANCELLED"
+		if strings.HasSuffix(r.URL.Path, "/reverse") {
+			status = "REVERSED"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(northwind.TransferResponse{TransferID: opaqueID, Status: status})
+	}))
+	defer server.Close()
+
+	// newLocalTransfer stores a random placeholder UUID when NorthWind's ID is not a UUID
+	seed := func(db *gorm.DB, userID uuid.UUID, status string) *models.NorthwindTransfer {
+		transfer := testfactory.NWTransfer(t, db, testfactory.WithUser(userID), testfactory.WithStatus(status), testfactory.WithTransferType(models.NWTransferTypeRTP))
+		ref := opaqueID
+		if err := db.Model(transfer).Update("external_ref", &ref).Error; err != nil {
+			t.Fatalf("failed to set external ref: %v", err)
+		}
+		return transfer
+	}
+	newService := func(db *gorm.DB) *NorthwindTransferService {
+		return NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), repositories.NewNorthwindTransferRepository(db), nil, nil, slog.Default())
+	}
+
+	tests := []struct {
+		name   string
+		status string
+		call   func(svc *NorthwindTransferService, userID, transferID uuid.UUID) error
+		want   string
+	}{
+		{"cancel", models.NWTransferStatusPending, func(svc *NorthwindTransferService, userID, transferID uuid.UUID) error {
+			_, err := svc.CancelTransfer(context.Background(), userID, transferID, "changed my mind", models.UserInitiator(userID))
+			return err
+		}, "/external/transfers/" + opaqueID + "/cancel"},
+		{"cancel all", models.NWTransferStatusPending, func(svc *NorthwindTransferService, userID, _ uuid.UUID) error {
+			results, err := svc.CancelAllPendingTransfers(context.Background(), userID, "compromised account", models.SystemInitiator())
+			if err == nil && (len(results) != 1 || results[0].Outcome != BulkCancelOutcomeCancelled) {
+				t.Errorf("expected the transfer cancelled, got %+v", results)
+			}
+			return err
+		}, "/external/transfers/" + opaqueID + "/cancel"},
+		{"reverse", models.NWTransferStatusCompleted, func(svc *NorthwindTransferService, userID, transferID uuid.UUID) error {
+			_, err := svc.ReverseTransfer(context.Background(), userID, transferID, "duplicate", "", models.UserInitiator(userID))
+			return err
+		}, "/external/transfers/" + opaqueID + "/reverse"},
+		{"compare", models.NWTransferStatusPending, func(svc *NorthwindTransferService, _, transferID uuid.UUID) error {
+			comparison, err := svc.CompareTransfer(context.Background(), transferID)
+			if err == nil && comparison.RemoteMissing {
+				t.Errorf("expected NorthWind's record to be found")
+			}
+			return err
+		}, "/external/transfers/" + opaqueID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			paths = nil
+			mu.Unlock()
+			db := testfactory.NewDB(t)
+			userID := uuid.New()
+			transfer := seed(db, userID, tt.status)
+
+			if err := tt.call(newService(db), userID, transfer.ID); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if len(paths) != 1 || paths[0] != tt.want {
+				t.Errorf("expected a request to %s, got %v", tt.want, paths)
+			}
+		})
+	}
+}
+
+func TestNorthwindTransferService_CancelTransfer_WithoutNorthwindID(t *testing.T) {
+	db := testfactory.NewDB(t)
+	userID := uuid.New()
+	transfer := testfactory.NWTransfer(t, db, testfactory.WithUser(userID), testfactory.WithTransferType(models.NWTransferTypeRTP))
+	if err := db.Model(transfer).Update("external_ref", nil).Error; err != nil {
+		t.Fatalf("failed to clear external ref: %v", err)
+	}
+	svc := newInitiatorTestService(t, db, "CANCELLED")
+
+	if _, err := svc.CancelTransfer(context.Background(), userID, transfer.ID, "changed my mind", models.UserInitiator(userID)); !errors.Is(err, ErrNWTransferNotUpstream) {
+		t.Errorf("expected ErrNWTransferNotUpstream, got %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// maxOriginUserAgentLength matches the origin_user_agent column; longer User-Agents are cut
+const maxOriginUserAgentLength = 512
+
+// RequestMetadata identifies the client behind a request, for audit events and fraud forensics
+type RequestMetadata struct {
+	IPAddress string
+	UserAgent string
+}
+
+// SetAuditService records a northwind_transfer_created audit event, with the client's IP and
+// User-Agent, for every transfer created. Without it no audit events are written.
+func (s *NorthwindTransferService) SetAuditService(audit AuditServiceInterface) {
+	s.audit = audit
+}
+
+// recordOrigin stores the initiating client on the transfer. The IP is stored in canonical
+// form, with any IPv6 zone dropped and IPv4-mapped IPv6 addresses unmapped, so it always fits
+// the column; a value that is not an IP address is not stored.
+func recordOrigin(transfer *models.NorthwindTransfer, meta RequestMetadata) {
+	if addr, err := netip.ParseAddr(meta.IPAddress); err == nil {
+		ip := addr.WithZone("").Unmap().String()
+		transfer.OriginIP = &ip
+	}
+	if meta.UserAgent != "" {
+		userAgent := truncateUTF8(meta.UserAgent, maxOriginUserAgentLength)
+		transfer.OriginUserAgent = &userAgent
+	}
+}
+
+// auditTransferCreated writes the transfer's audit event. Failures are logged rather than
+// returned: NorthWind has already accepted the transfer.
+func (s *NorthwindTransferService) auditTransferCreated(transfer *models.NorthwindTransfer) {
+	if s.audit == nil {
+		return
+	}
+	log := &models.AuditLog{
+		UserID:     transfer.UserID,
+		Action:     models.AuditActionNorthwindTransferCreated,
+		Resource:   "northwind_transfer",
+		ResourceID: transfer.ID.String(),
+		Metadata: models.JSONBMap{
+			"northwind_transfer_id": transfer.NorthwindTransferID.String(),
+			"direction":             transfer.Direction,
+			"transfer_type":         transfer.TransferType,
+			"amount":                transfer.Amount.String(),
+			"currency":              transfer.Currency,
+			"internal_test":         transfer.InternalTest,
+		},
+	}
+	if transfer.OriginIP != nil {
+		log.IPAddress = *transfer.OriginIP
+	}
+	if transfer.OriginUserAgent != nil {
+		log.UserAgent = *transfer.OriginUserAgent
+	}
+	if err := s.audit.CreateAuditLog(log); err != nil {
+		s.logger.Error("Failed to write transfer audit event", "local_id", transfer.ID, "error", err)
+	}
+}
+
+// auditTransferStatusRequest writes the audit event for a cancel or reverse request NorthWind
+// accepted, attributed to its initiator; the system's requests have no user. extra is added to
+// the event's metadata. Failures are logged rather than returned: the transfer has already
+// changed.
+func (s *NorthwindTransferService) auditTransferStatusRequest(transfer *models.NorthwindTransfer, action, reason string, initiator models.TransferInitiator, extra models.JSONBMap) {
+	if s.audit == nil {
+		return
+	}
+	log := &models.AuditLog{
+		UserID:     initiator.InitiatorID,
+		Action:     action,
+		Resource:   "northwind_transfer",
+		ResourceID: transfer.ID.String(),
+		Metadata: models.JSONBMap{
+			"northwind_transfer_id": transfer.NorthwindTransferID.String(),
+			"initiator":             initiator.Initiator,
+			"reason":                reason,
+			"status":                transfer.Status,
+		},
+	}
+	if transfer.UserID != nil {
+		log.SetMetadata("owner_id", transfer.UserID.String())
+	}
+	for key, value := range extra {
+		log.SetMetadata(key, value)
+	}
+	if err := s.audit.CreateAuditLog(log); err != nil {
+		s.logger.Error("Failed to write transfer audit event", "local_id", transfer.ID, "error", err)
+	}
+}
+
+// SetInternalTest flags or unflags any user's transfer as an internal test transfer, which is
+// never reported to the regulator, and records the change with the admin who made it. It only
+// affects notifications not yet created.
+func (s *NorthwindTransferService) SetInternalTest(ctx context.Context, transferID uuid.UUID, internalTest bool, adminID uuid.UUID, meta RequestMetadata) (*models.NorthwindTransfer, error) {
+	transfer, err := s.GetAnyTransfer(ctx, transferID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.InternalTest == internalTest {
+		return transfer, nil
+	}
+	if err := s.transferRepo.SetInternalTest(ctx, transferID, internalTest); err != nil {
+		if errors.Is(err, repositories.ErrNorthwindTransferNotFound) {
+			return nil, ErrNWTransferNotFound
+		}
+		return nil, err
+	}
+	transfer.InternalTest = internalTest
+
+	if s.audit != nil {
+		log := &models.AuditLog{
+			UserID:     &adminID,
+			Action:     models.AuditActionNorthwindTransferInternalTestChanged,
+			Resource:   "northwind_transfer",
+			ResourceID: transfer.ID.String(),
+			IPAddress:  meta.IPAddress,
+			UserAgent:  truncateUTF8(meta.UserAgent, maxOriginUserAgentLength),
+			Metadata: models.JSONBMap{
+				"internal_test": internalTest,
+				"status":        transfer.Status,
+			},
+		}
+		if err := s.audit.CreateAuditLog(log); err != nil {
+			s.logger.Error("Failed to write transfer audit event", "local_id", transfer.ID, "error", err)
+		}
+	}
+	s.logger.Info("Transfer internal test flag changed", "transfer_id", transfer.ID, "internal_test", internalTest, "admin_id", adminID)
+	return transfer, nil
+}
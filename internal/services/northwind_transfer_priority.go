@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrNWTransferPriorityNotAllowed = errors.New("SAME_DAY priority is only available for ACH transfers")
+	ErrSameDayCutoffPassed          = errors.New("same-day cutoff has passed")
+)
+
+// SameDayCutoffPassedError is returned for a SAME_DAY transfer submitted after the day's cutoff,
+// or on a weekend, so NorthWind is not asked. NextCutoff is the next time a SAME_DAY transfer
+// can be submitted until. It wraps ErrSameDayCutoffPassed.
+type SameDayCutoffPassedError struct {
+	NextCutoff time.Time
+}
+
+func (e *SameDayCutoffPassedError) Error() string {
+	return fmt.Sprintf("%s: the next same-day cutoff is %s", ErrSameDayCutoffPassed, e.NextCutoff.Format(time.RFC3339))
+}
+
+func (e *SameDayCutoffPassedError) Unwrap() error {
+	return ErrSameDayCutoffPassed
+}
+
+// SameDayPolicy decides when SAME_DAY ACH transfers are accepted: on weekdays, until the daily
+// cutoff in the bank's time zone. Bank holidays are left to NorthWind.
+type SameDayPolicy struct {
+	hour     int
+	minute   int
+	location *time.Location
+	fee      decimal.Decimal
+	now      func() time.Time
+}
+
+// NewSameDayPolicy creates a same-day policy from a cutoff written as HH:MM, the IANA time zone
+// it is in and the expedite fee
+func NewSameDayPolicy(cutoff, timezone string, fee float64) (*SameDayPolicy, error) {
+	parsed, err := time.Parse("15:04", cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("same-day cutoff must be HH:MM, got %q", cutoff)
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bank time zone %q: %w", timezone, err)
+	}
+	if fee < 0 {
+		return nil, fmt.Errorf("same-day fee must not be negative, got %v", fee)
+	}
+	return &SameDayPolicy{
+		hour:     parsed.Hour(),
+		minute:   parsed.Minute(),
+		location: location,
+		fee:      decimal.NewFromFloat(fee),
+		now:      time.Now,
+	}, nil
+}
+
+// Fee returns the expedite fee of a SAME_DAY transfer
+func (p *SameDayPolicy) Fee() decimal.Decimal {
+	return p.fee
+}
+
+// NextCutoff returns the first weekday cutoff after now, in the bank's time zone
+func (p *SameDayPolicy) NextCutoff(now time.Time) time.Time {
+	local := now.In(p.location)
+	for day := 0; ; day++ {
+		year, month, date := local.AddDate(0, 0, day).Date()
+		cutoff := time.Date(year, month, date, p.hour, p.minute, 0, 0, p.location)
+		if weekday := cutoff.Weekday(); weekday == time.Saturday || weekday == time.Sunday {
+			continue
+		}
+		if now.Before(cutoff) {
+			return cutoff
+		}
+	}
+}
+
+// Check returns nil when a SAME_DAY transfer submitted now makes today's cutoff, and a
+// SameDayCutoffPassedError otherwise
+func (p *SameDayPolicy) Check() error {
+	return p.checkAt(p.now())
+}
+
+func (p *SameDayPolicy) checkAt(now time.Time) error {
+	next := p.NextCutoff(now)
+	local := now.In(p.location)
+	if next.Year() == local.Year() && next.YearDay() == local.YearDay() {
+		return nil
+	}
+	return &SameDayCutoffPassedError{NextCutoff: next}
+}
+
+// SetSameDayPolicy sets when SAME_DAY transfers are accepted and what they cost. Without it
+// SAME_DAY is only checked against the transfer type and estimates carry no expedite fee.
+func (s *NorthwindTransferService) SetSameDayPolicy(policy *SameDayPolicy) {
+	s.sameDay = policy
+}
+
+// checkPriority rejects a SAME_DAY request for a transfer type other than ACH, or made after the
+// day's cutoff
+func (s *NorthwindTransferService) checkPriority(req CreateTransferRequest) error {
+	if req.Priority != models.NWTransferPrioritySameDay {
+		return nil
+	}
+	if req.TransferType != models.NWTransferTypeACH {
+		return fmt.Errorf("%w: got %s", ErrNWTransferPriorityNotAllowed, req.TransferType)
+	}
+	if s.sameDay == nil {
+		return nil
+	}
+	return s.sameDay.Check()
+}
+
+// transferPriority returns the priority a request asks for, STANDARD when it names none
+func transferPriority(req CreateTransferRequest) string {
+	if req.Priority == "" {
+		return models.NWTransferPriorityStandard
+	}
+	return req.Priority
+}
+
+// TransferEstimate describes what a transfer of one type and priority would cost and how long it
+// typically takes. For SAME_DAY, ExpediteFee is the fee on top of NorthWind's usual fee,
+// SameDayAvailable whether a transfer submitted now makes today's cutoff, and NextCutoff the
+// cutoff it would make.
+type TransferEstimate struct {
+	TransferType     string                        `json:"transfer_type"`
+	Priority         string                        `json:"priority"`
+	ExpediteFee      *decimal.Decimal              `json:"expedite_fee,omitempty"`
+	SameDayAvailable *bool                         `json:"same_day_available,omitempty"`
+	NextCutoff       *time.Time                    `json:"next_cutoff,omitempty"`
+	ExpectedDuration *models.TransferDurationStats `json:"expected_duration,omitempty"`
+}
+
+// EstimateTransfer estimates a transfer of transferType at priority, STANDARD when empty
+func (s *NorthwindTransferService) EstimateTransfer(ctx context.Context, transferType, priority string) (*TransferEstimate, error) {
+	req := CreateTransferRequest{TransferType: transferType, Priority: priority}
+	estimate := &TransferEstimate{TransferType: transferType, Priority: transferPriority(req)}
+	if estimate.Priority == models.NWTransferPrioritySameDay {
+		if transferType != models.NWTransferTypeACH {
+			return nil, fmt.Errorf("%w: got %s", ErrNWTransferPriorityNotAllowed, transferType)
+		}
+		if s.sameDay != nil {
+			fee := s.sameDay.Fee()
+			now := s.sameDay.now()
+			available := s.sameDay.checkAt(now) == nil
+			next := s.sameDay.NextCutoff(now)
+			estimate.ExpediteFee = &fee
+			estimate.SameDayAvailable = &available
+			estimate.NextCutoff = &next
+		}
+	}
+	if s.durations != nil {
+		if expected, ok := s.durations.ExpectedDuration(ctx, transferType); ok {
+			estimate.ExpectedDuration = expected
+		}
+	}
+	return estimate, nil
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories/repository_mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+func newTestSameDayPolicy(t *testing.T, now time.Time) *SameDayPolicy {
+	t.Helper()
+	policy, err := NewSameDayPolicy("14:45", "America/New_York", 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	policy.now = func() time.Time { return now }
+	return policy
+}
+
+func TestSameDayPolicy_Check(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	at := func(day, hour, minute, second int) time.Time {
+		return time.Date(2025, time.June, day, hour, minute, second, 0, newYork)
+	}
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		wantNext time.Time
+		wantOpen bool
+	}{
+		{"morning", at(11, 9, 0, 0), at(11, 14, 45, 0), true},
+		{"last second before cutoff", at(11, 14, 44, 59), at(11, 14, 45, 0), true},
+		{"at cutoff", at(11, 14, 45, 0), at(12, 14, 45, 0), false},
+		{"evening", at(11, 20, 0, 0), at(12, 14, 45, 0), false},
+		{"friday after cutoff", at(13, 15, 0, 0), at(16, 14, 45, 0), false},
+		{"saturday", at(14, 10, 0, 0), at(16, 14, 45, 0), false},
+		// 18:30 UTC is 14:30 in New York, before the cutoff despite the UTC clock
+		{"utc clock", time.Date(2025, time.June, 11, 18, 30, 0, 0, time.UTC), at(11, 14, 45, 0), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := newTestSameDayPolicy(t, tt.now)
+			err := policy.Check()
+			if tt.wantOpen {
+				if err != nil {
+					t.Fatalf("expected SAME_DAY accepted, got %v", err)
+				}
+				return
+			}
+			var passed *SameDayCutoffPassedError
+			if !errors.As(err, &passed) || !errors.Is(err, ErrSameDayCutoffPassed) {
+				t.Fatalf("expected SameDayCutoffPassedError, got %v", err)
+			}
+			if !passed.NextCutoff.Equal(tt.wantNext) {
+				t.Errorf("expected next cutoff %v, got %v", tt.wantNext, passed.NextCutoff)
+			}
+		})
+	}
+}
+
+func TestNewSameDayPolicy_InvalidSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		cutoff   string
+		timezone string
+		fee      float64
+	}{
+		{"malformed cutoff", "2:45pm", "America/New_York", 5},
+		{"unknown time zone", "14:45", "Mars/Olympus_Mons", 5},
+		{"negative fee", "14:45", "America/New_York", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSameDayPolicy(tt.cutoff, tt.timezone, tt.fee); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestNorthwindTransferService_CreateTransfer_Priority(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	beforeCutoff := time.Date(2025, time.June, 11, 14, 0, 0, 0, newYork)
+	afterCutoff := time.Date(2025, time.June, 11, 15, 0, 0, 0, newYork)
+
+	tests := []struct {
+		name         string
+		transferType string
+		priority     string
+		now          time.Time
+		wantErr      error
+		wantSent     string
+		wantStored   string
+	}{
+		{"same day ACH passed through", models.NWTransferTypeACH, models.NWTransferPrioritySameDay, beforeCutoff, nil, models.NWTransferPrioritySameDay, models.NWTransferPrioritySameDay},
+		{"standard by default", models.NWTransferTypeACH, "", afterCutoff, nil, "", models.NWTransferPriorityStandard},
+		{"standard ACH after cutoff", models.NWTransferTypeACH, models.NWTransferPriorityStandard, afterCutoff, nil, models.NWTransferPriorityStandard, models.NWTransferPriorityStandard},
+		{"standard wire not sent a priority", models.NWTransferTypeWire, models.NWTransferPriorityStandard, beforeCutoff, nil, "", models.NWTransferPriorityStandard},
+		{"same day wire rejected", models.NWTransferTypeWire, models.NWTransferPrioritySameDay, beforeCutoff, ErrNWTransferPriorityNotAllowed, "", ""},
+		{"same day ACH after cutoff rejected", models.NWTransferTypeACH, models.NWTransferPrioritySameDay, afterCutoff, ErrSameDayCutoffPassed, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			api := &fakeNorthwindTransferAPI{}
+			server := httptest.NewServer(api)
+			defer server.Close()
+
+			userID := uuid.New()
+			req := newTestTransferRequest(models.NWTransferDirectionOutbound)
+			req.TransferType = tt.transferType
+			req.Priority = tt.priority
+
+			transferRepo := repository_mocks.NewMockNorthwindTransferRepositoryInterface(ctrl)
+			var stored *models.NorthwindTransfer
+			if tt.wantErr == nil {
+				transferRepo.EXPECT().ReferenceExists(gomock.Any(), userID, gomock.Any()).Return(false, nil)
+				expectNoRecentDuplicate(transferRepo, userID)
+				transferRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, transfer *models.NorthwindTransfer) error {
+					stored = transfer
+					return nil
+				})
+			}
+
+			svc := NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), transferRepo, nil, nil, slog.Default())
+			svc.SetSameDayPolicy(newTestSameDayPolicy(t, tt.now))
+			_, err := svc.CreateTransfer(context.Background(), userID, req)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				if n := api.calls(); n != 0 {
+					t.Errorf("expected no NorthWind calls for a rejected priority, got %d", n)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sent := api.initiatedPriorities(); len(sent) != 1 || sent[0] != tt.wantSent {
+				t.Errorf("expected priority %q sent to NorthWind, got %v", tt.wantSent, sent)
+			}
+			if stored == nil || stored.Priority != tt.wantStored {
+				t.Errorf("expected stored priority %q, got %+v", tt.wantStored, stored)
+			}
+		})
+	}
+}
+
+func TestNorthwindTransferService_EstimateTransfer(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	svc := NewNorthwindTransferService(nil, nil, nil, nil, slog.Default())
+	svc.SetSameDayPolicy(newTestSameDayPolicy(t, time.Date(2025, time.June, 13, 16, 0, 0, 0, newYork)))
+
+	estimate, err := svc.EstimateTransfer(context.Background(), models.NWTransferTypeACH, models.NWTransferPrioritySameDay)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if estimate.ExpediteFee == nil || estimate.ExpediteFee.String() != "5" {
+		t.Errorf("expected expedite fee 5, got %v", estimate.ExpediteFee)
+	}
+	if estimate.SameDayAvailable == nil || *estimate.SameDayAvailable {
+		t.Errorf("expected same day unavailable on Friday after the cutoff, got %v", estimate.SameDayAvailable)
+	}
+	if want := time.Date(2025, time.June, 16, 14, 45, 0, 0, newYork); estimate.NextCutoff == nil || !estimate.NextCutoff.Equal(want) {
+		t.Errorf("expected next cutoff %v, got %v", want, estimate.NextCutoff)
+	}
+
+	standard, err := svc.EstimateTransfer(context.Background(), models.NWTransferTypeWire, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if standard.Priority != models.NWTransferPriorityStandard || standard.ExpediteFee != nil {
+		t.Errorf("expected a STANDARD estimate without an expedite fee, got %+v", standard)
+	}
+
+	if _, err := svc.EstimateTransfer(context.Background(), models.NWTransferTypeWire, models.NWTransferPrioritySameDay); !errors.Is(err, ErrNWTransferPriorityNotAllowed) {
+		t.Errorf("expected ErrNWTransferPriorityNotAllowed, got %v", err)
+	}
+}
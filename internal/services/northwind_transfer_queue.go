@@ -0,0 +1,316 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// ErrNWTransferInitiating is returned when a held transfer is cancelled while a worker is sending
+// it to NorthWind; once NorthWind has answered it can be cancelled like any other
+var ErrNWTransferInitiating = errors.New("transfer is being sent to northwind, try again shortly")
+
+// QueuedInitiationReasonMaintenance is why a transfer created during a maintenance window was queued
+const QueuedInitiationReasonMaintenance = "northwind_maintenance"
+
+// queuedInitiationBatchSize bounds how many queued transfers one drain sends to NorthWind
+const queuedInitiationBatchSize = 50
+
+// initiationClaimStaleAfter is how long a worker's claim on a held transfer keeps others off it.
+// It is well beyond the time sending one transfer takes, retries included, so only a claim left
+// by a worker that died mid-send goes stale and is taken over.
+const initiationClaimStaleAfter = 5 * time.Minute
+
+// Error codes recorded on a queued transfer that failed when it was finally initiated
+const (
+	queuedFailureValidation   = "VALIDATION_FAILED"
+	queuedFailureInsufficient = "INSUFFICIENT_BALANCE"
+	queuedFailureRejected     = "INITIATION_REJECTED"
+)
+
+// QueuedInitiation tells the client its transfer was accepted but not yet sent to NorthWind
+type QueuedInitiation struct {
+	Reason string `json:"reason"`
+	// InitiateAfter is when the transfer becomes due: the end of the maintenance window, or its
+	// scheduled date
+	InitiateAfter time.Time `json:"initiate_after"`
+}
+
+// SetMaintenance registers the NorthWind maintenance window. While it is open CreateTransfer
+// runs its local checks, then queues the transfer as INITIATION_PENDING instead of calling
+// NorthWind; InitiateQueuedTransfers sends it after the window closes. Without it transfers are
+// always initiated immediately.
+func (s *NorthwindTransferService) SetMaintenance(maintenance *NorthwindMaintenance) {
+	s.maintenance = maintenance
+}
+
+// maintenanceWindow returns the maintenance window when one is open now
+func (s *NorthwindTransferService) maintenanceWindow() (MaintenanceWindow, bool) {
+	if s.maintenance == nil {
+		return MaintenanceWindow{}, false
+	}
+	return s.maintenance.Active()
+}
+
+// queueTransfer stores a transfer that passed the local checks as INITIATION_PENDING, keeping
+// the full request to send to NorthWind once the maintenance window closes
+func (s *NorthwindTransferService) queueTransfer(ctx context.Context, userID uuid.UUID, req CreateTransferRequest, window MaintenanceWindow) (*CreateTransferResponse, error) {
+	transfer, err := s.holdTransfer(ctx, userID, req, models.NWTransferStatusInitiationPending)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Transfer queued during NorthWind maintenance",
+		"local_id", transfer.ID,
+		"reference_number", transfer.ReferenceNumber,
+		"initiate_after", window.End,
+	)
+	s.auditTransferCreated(transfer)
+
+	return &CreateTransferResponse{
+		Transfer: transfer,
+		Queued:   &QueuedInitiation{Reason: QueuedInitiationReasonMaintenance, InitiateAfter: window.End},
+	}, nil
+}
+
+// holdTransfer stores a transfer that is not sent to NorthWind yet in status, one of the statuses
+// a transfer awaits initiation in, with the request to send later
+func (s *NorthwindTransferService) holdTransfer(ctx context.Context, userID uuid.UUID, req CreateTransferRequest, status string) (*models.NorthwindTransfer, error) {
+	raw, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode held transfer request: %w", err)
+	}
+
+	transfer := s.newLocalTransfer(userID, req, &northwind.TransferResponse{})
+	transfer.Status = status
+	transfer.NextPollAt = nil
+	transfer.RawResponse = ""
+	transfer.InitiationRequest = string(raw)
+
+	if err := s.transferRepo.Create(ctx, transfer); err != nil {
+		if errors.Is(err, repositories.ErrNorthwindTransferDuplicateReference) {
+			return nil, fmt.Errorf("%w: %s", ErrNWTransferDuplicateRef, transfer.ReferenceNumber)
+		}
+		return nil, fmt.Errorf("failed to store held transfer: %w", err)
+	}
+	return transfer, nil
+}
+
+// InitiateQueuedTransfers sends transfers queued during maintenance to NorthWind once the window
+// has closed, oldest first. A transfer NorthWind rejects is marked FAILED; one that could not be
+// sent stays queued for the next run.
+func (s *NorthwindTransferService) InitiateQueuedTransfers(ctx context.Context) error {
+	if _, ok := s.maintenanceWindow(); ok {
+		return nil
+	}
+
+	queued, err := s.transferRepo.GetByStatus(ctx, models.NWTransferStatusInitiationPending, queuedInitiationBatchSize)
+	if err != nil {
+		return err
+	}
+	return s.initiateHeld(ctx, queued, models.NWTransferEventSourceQueue)
+}
+
+// initiateHeld sends held transfers to NorthWind one at a time on behalf of source. A transfer
+// that could not be sent is logged and left for the next run.
+func (s *NorthwindTransferService) initiateHeld(ctx context.Context, transfers []models.NorthwindTransfer, source string) error {
+	for i := range transfers {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := s.initiateQueued(ctx, transfers[i].ID, transfers[i].Status, source); err != nil {
+			s.logger.Warn("Held transfer not initiated, will retry",
+				"transfer_id", transfers[i].ID,
+				"status", transfers[i].Status,
+				"error", err,
+			)
+		}
+	}
+	return nil
+}
+
+// initiateQueued sends one transfer held in status to NorthWind. The transfer is first claimed
+// in a short update of its own, so the NorthWind calls hold no row lock or transaction open;
+// while the claim is fresh neither another worker nor a cancellation touches the transfer. The
+// outcome is then recorded with ApplyTransition, and a transfer that could not be sent has its
+// claim released for the next run. A scheduled transfer is due by now, so it is sent without its
+// scheduled date and NorthWind initiates it straight away.
+func (s *NorthwindTransferService) initiateQueued(ctx context.Context, transferID uuid.UUID, status, source string) error {
+	claimed, err := s.transferRepo.ClaimForInitiation(ctx, transferID, status, time.Now(), initiationClaimStaleAfter)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		// Cancelled, initiated or being initiated by another worker since it was listed
+		return nil
+	}
+
+	nwResp, requestID, req, sendErr := s.sendHeld(northwind.WithIdempotencyKey(ctx, heldInitiationKey(transferID)), transferID, status)
+	code, rejected := queuedFailureCode(sendErr)
+	if sendErr != nil && !rejected {
+		if err := s.transferRepo.ReleaseInitiationClaim(context.WithoutCancel(ctx), transferID); err != nil {
+			s.logger.Error("Failed to release held transfer claim", "transfer_id", transferID, "error", err)
+		}
+		return sendErr
+	}
+
+	// NorthWind has acted on the transfer, so its answer is stored even if ctx is cancelled now
+	transfer, event, err := s.transferRepo.ApplyTransition(context.WithoutCancel(ctx), transferID, func(t *models.NorthwindTransfer) *models.NorthwindTransferEvent {
+		if t.Status != status {
+			return nil
+		}
+
+		event := &models.NorthwindTransferEvent{FromStatus: t.Status, Source: source}
+		if sendErr != nil {
+			message := sendErr.Error()
+			setInitiationRequestID(t, requestID)
+			t.Status = models.NWTransferStatusFailed
+			t.NextPollAt = nil
+			t.ErrorCode = &code
+			t.ErrorMessage = &message
+			t.InitiationClaimedAt = nil
+			s.errorCodes.Classify(t)
+			event.ToStatus = t.Status
+			return event
+		}
+
+		initiated := s.newLocalTransfer(*t.UserID, req, nwResp)
+		initiated.ID = t.ID
+		initiated.CreatedAt = t.CreatedAt
+		initiated.UpdatedAt = t.UpdatedAt
+		initiated.Version = t.Version
+		initiated.OriginIP = t.OriginIP
+		initiated.OriginUserAgent = t.OriginUserAgent
+		initiated.InitiationRequest = t.InitiationRequest
+		// Set on the held transfer by admins or the user, not derived from the request
+		initiated.InternalTest = t.InternalTest
+		initiated.CancellationInitiator = t.CancellationInitiator
+		initiated.CancellationInitiatorID = t.CancellationInitiatorID
+		initiated.LastViewedAt = t.LastViewedAt
+		initiated.BatchName = t.BatchName
+		initiated.BatchIndex = t.BatchIndex
+		if initiated.ScheduledDate == nil {
+			initiated.ScheduledDate = t.ScheduledDate
+		}
+		setInitiationRequestID(initiated, requestID)
+		*t = *initiated
+		event.ToStatus = t.Status
+		return event
+	})
+	if err != nil {
+		return err
+	}
+	if event == nil {
+		s.logger.Error("Held transfer changed while claimed for initiation",
+			"transfer_id", transferID,
+			"status", transfer.Status,
+		)
+		return nil
+	}
+
+	s.logger.Info("Held transfer initiated",
+		"local_id", transfer.ID,
+		"northwind_id", transfer.NorthwindTransferID,
+		"status", transfer.Status,
+		"source", source,
+	)
+	return nil
+}
+
+// heldInitiationKey is the Idempotency-Key of a held transfer's initiation. It is the same on
+// every attempt, so when an attempt reached NorthWind but its answer was lost, the next drain's
+// attempt returns that transfer instead of initiating it a second time.
+func heldInitiationKey(transferID uuid.UUID) string {
+	return "initiate-" + transferID.String()
+}
+
+// sendHeld loads a claimed transfer held in status and sends its stored request to NorthWind,
+// returning the decoded request along with NorthWind's response and request ID
+func (s *NorthwindTransferService) sendHeld(ctx context.Context, transferID uuid.UUID, status string) (*northwind.TransferResponse, string, CreateTransferRequest, error) {
+	var req CreateTransferRequest
+	transfer, err := s.transferRepo.GetByID(ctx, transferID)
+	if err != nil {
+		return nil, "", req, err
+	}
+	if err := json.Unmarshal([]byte(transfer.InitiationRequest), &req); err != nil {
+		return nil, "", req, fmt.Errorf("failed to decode queued transfer request: %w", err)
+	}
+	if status == models.NWTransferStatusScheduled {
+		req.ScheduledDate = ""
+	}
+
+	nwResp, requestID, err := s.sendQueued(ctx, req, toNWTransferRequest(req))
+	return nwResp, requestID, req, err
+}
+
+// sendQueued runs CreateTransfer's NorthWind checks and initiation for a queued transfer,
+// returning NorthWind's request ID for the initiation
+func (s *NorthwindTransferService) sendQueued(ctx context.Context, req CreateTransferRequest, nwReq northwind.TransferRequest) (*northwind.TransferResponse, string, error) {
+	if err := s.checkWithNorthwind(ctx, req, nwReq); err != nil {
+		return nil, "", err
+	}
+	return s.initiate(ctx, nwReq)
+}
+
+// queuedFailureCode classifies an error from sending a queued transfer. rejected is true when
+// NorthWind refused the transfer itself, so sending it again would not help.
+func queuedFailureCode(err error) (code string, rejected bool) {
+	var apiErr *northwind.APIError
+	switch {
+	case errors.Is(err, ErrNWTransferValidationFailed):
+		return queuedFailureValidation, true
+	case errors.Is(err, ErrNWTransferInsufficientBal):
+		return queuedFailureInsufficient, true
+	case errors.As(err, &apiErr) && apiErr.StatusCode >= http.StatusBadRequest && apiErr.StatusCode < http.StatusInternalServerError:
+		return queuedFailureRejected, true
+	default:
+		return "", false
+	}
+}
+
+// cancelQueued cancels a transfer that is still queued or scheduled on behalf of initiator,
+// without calling NorthWind. It returns false, with transfer refreshed, when a worker initiated
+// the transfer first, and ErrNWTransferInitiating while a worker is sending it.
+func (s *NorthwindTransferService) cancelQueued(ctx context.Context, transfer *models.NorthwindTransfer, reason string, initiator models.TransferInitiator) (bool, error) {
+	var inFlight bool
+	updated, event, err := s.transferRepo.ApplyTransition(ctx, transfer.ID, func(t *models.NorthwindTransfer) *models.NorthwindTransferEvent {
+		if !t.AwaitingInitiation() {
+			return nil
+		}
+		if t.InitiationClaimedAt != nil && time.Since(*t.InitiationClaimedAt) < initiationClaimStaleAfter {
+			inFlight = true
+			return nil
+		}
+		from := t.Status
+		t.Status = models.NWTransferStatusCancelled
+		t.NextPollAt = nil
+		t.CancellationInitiator = &initiator.Initiator
+		t.CancellationInitiatorID = initiator.InitiatorID
+		return initiator.Event(from, t.Status)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel queued transfer: %w", err)
+	}
+	*transfer = *updated
+	if inFlight {
+		return false, ErrNWTransferInitiating
+	}
+	if event == nil {
+		return false, nil
+	}
+
+	s.logger.Info("Held transfer cancelled before initiation",
+		"transfer_id", transfer.ID,
+		"reason", reason,
+		"initiator", initiator.Initiator,
+	)
+	return true, nil
+}
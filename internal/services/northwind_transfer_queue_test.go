@@ -0,0 +1,348 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/google/uuid"
+)
+
+// newQueueTestService returns a transfer service, whose client is built with opts, whose
+// maintenance window is open until the returned maintenance's clock is moved past it
+func newQueueTestService(t *testing.T, api http.Handler, opts ...northwind.ClientOption) (*NorthwindTransferService, repositories.NorthwindTransferRepositoryInterface, *NorthwindMaintenance) {
+	t.Helper()
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	transferRepo := repositories.NewNorthwindTransferRepository(testfactory.NewDB(t))
+	svc := NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key", opts...), transferRepo, nil, nil, slog.Default())
+	now := time.Now()
+	maintenance := NewNorthwindMaintenance(&MaintenanceWindow{Start: now.Add(-time.Minute), End: now.Add(time.Hour)})
+	svc.SetMaintenance(maintenance)
+	return svc, transferRepo, maintenance
+}
+
+// endMaintenance moves the maintenance clock past the end of the window
+func endMaintenance(m *NorthwindMaintenance) {
+	end := m.Window().End
+	m.now = func() time.Time { return end.Add(time.Second) }
+}
+
+func TestNorthwindTransferService_CreateTransfer_QueuedDuringMaintenance(t *testing.T) {
+	api := &fakeNorthwindTransferAPI{}
+	svc, transferRepo, maintenance := newQueueTestService(t, api)
+	ctx := context.Background()
+
+	resp, err := svc.CreateTransfer(ctx, uuid.New(), newTestTransferRequest(models.NWTransferDirectionOutbound))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Queued == nil || resp.Initiation != nil {
+		t.Fatalf("expected a queued response without an initiation, got %+v", resp)
+	}
+	if !resp.Queued.InitiateAfter.Equal(maintenance.Window().End) {
+		t.Errorf("expected initiation after the window ends, got %v", resp.Queued.InitiateAfter)
+	}
+	if api.calls() != 0 {
+		t.Errorf("expected no NorthWind calls during maintenance, got %d", api.calls())
+	}
+
+	stored, err := transferRepo.GetByID(ctx, resp.Transfer.ID)
+	if err != nil {
+		t.Fatalf("failed to reload transfer: %v", err)
+	}
+	if stored.Status != models.NWTransferStatusInitiationPending || stored.ExternalRef != nil || stored.InitiationRequest == "" {
+		t.Errorf("expected a queued transfer holding its request, got status %s, external ref %v", stored.Status, stored.ExternalRef)
+	}
+
+	// Local checks still run: a reused reference is rejected without queueing
+	if _, err := svc.CreateTransfer(ctx, *stored.UserID, newTestTransferRequest(models.NWTransferDirectionOutbound)); err == nil {
+		t.Error("expected the duplicate reference to be rejected during maintenance")
+	}
+
+	// Nothing is drained while the window is open
+	if err := svc.InitiateQueuedTransfers(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if api.calls() != 0 {
+		t.Errorf("expected the queue to wait for the window to close, got %d calls", api.calls())
+	}
+}
+
+func TestNorthwindTransferService_InitiateQueuedTransfers_DrainsAfterWindow(t *testing.T) {
+	api := &fakeNorthwindTransferAPI{}
+	svc, transferRepo, maintenance := newQueueTestService(t, api)
+	ctx := context.Background()
+
+	resp, err := svc.CreateTransfer(ctx, uuid.New(), newTestTransferRequest(models.NWTransferDirectionOutbound))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	endMaintenance(maintenance)
+	if err := svc.InitiateQueuedTransfers(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := transferRepo.GetByID(ctx, resp.Transfer.ID)
+	if err != nil {
+		t.Fatalf("failed to reload transfer: %v", err)
+	}
+	if got.Status != models.NWTransferStatusPending || got.ExternalRef == nil {
+		t.Errorf("expected the queued transfer to be initiated, got status %s, external ref %v", got.Status, got.ExternalRef)
+	}
+	if refs := api.initiatedReferences(); len(refs) != 1 || refs[0] != "REF-1" {
+		t.Errorf("expected REF-1 to be initiated once, got %v", refs)
+	}
+	if len(api.balancePaths()) != 1 {
+		t.Errorf("expected the balance check to run before initiation, got %v", api.balancePaths())
+	}
+	events, _ := transferRepo.ListEvents(ctx, got.ID)
+	if len(events) != 1 || events[0].FromStatus != models.NWTransferStatusInitiationPending || events[0].Source != models.NWTransferEventSourceQueue {
+		t.Errorf("expected one queue initiation event, got %+v", events)
+	}
+
+	// Already drained: another run sends nothing
+	calls := api.calls()
+	if err := svc.InitiateQueuedTransfers(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if api.calls() != calls {
+		t.Errorf("expected no further NorthWind calls, got %d", api.calls()-calls)
+	}
+}
+
+func TestNorthwindTransferService_InitiateQueuedTransfers_UpstreamFailures(t *testing.T) {
+	tests := map[string]struct {
+		initiateStatus int
+		wantStatus     string
+		wantErrorCode  string
+	}{
+		"rejected":    {http.StatusUnprocessableEntity, models.NWTransferStatusFailed, queuedFailureRejected},
+		"unavailable": {http.StatusServiceUnavailable, models.NWTransferStatusInitiationPending, ""},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fallback := &fakeNorthwindTransferAPI{}
+			api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/external/transfers/initiate" {
+					w.WriteHeader(tt.initiateStatus)
+					_, _ = w.Write([]byte(`{"message":"destination account closed"}`))
+					return
+				}
+				fallback.ServeHTTP(w, r)
+			})
+			svc, transferRepo, maintenance := newQueueTestService(t, api)
+			ctx := context.Background()
+
+			resp, err := svc.CreateTransfer(ctx, uuid.New(), newTestTransferRequest(models.NWTransferDirectionOutbound))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			endMaintenance(maintenance)
+			if err := svc.InitiateQueuedTransfers(ctx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got, err := transferRepo.GetByID(ctx, resp.Transfer.ID)
+			if err != nil {
+				t.Fatalf("failed to reload transfer: %v", err)
+			}
+			if got.Status != tt.wantStatus {
+				t.Errorf("expected status %s, got %s", tt.wantStatus, got.Status)
+			}
+			if tt.wantErrorCode != "" && (got.ErrorCode == nil || *got.ErrorCode != tt.wantErrorCode) {
+				t.Errorf("expected error code %s, got %v", tt.wantErrorCode, got.ErrorCode)
+			}
+			if got.InitiationClaimedAt != nil {
+				t.Errorf("expected the claim to be cleared, got %v", got.InitiationClaimedAt)
+			}
+		})
+	}
+}
+
+func TestNorthwindTransferService_InitiateQueuedTransfers_StableIdempotencyKey(t *testing.T) {
+	api := &fakeNorthwindTransferAPI{}
+	svc, transferRepo, maintenance := newQueueTestService(t, api, northwind.WithRetry(0, 1))
+	ctx := context.Background()
+
+	resp, err := svc.CreateTransfer(ctx, uuid.New(), newTestTransferRequest(models.NWTransferDirectionOutbound))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	endMaintenance(maintenance)
+
+	// The first drain's initiation reaches NorthWind but its response is lost. Go's transport
+	// replays a request with an Idempotency-Key on a reused connection, so every attempt is lost.
+	api.inject(fakeEndpointInitiate, fakeFault{reset: true}, fakeFault{reset: true}, fakeFault{reset: true})
+	if err := svc.InitiateQueuedTransfers(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	api.mu.Lock()
+	delete(api.faults, fakeEndpointInitiate)
+	api.mu.Unlock()
+	got, err := transferRepo.GetByID(ctx, resp.Transfer.ID)
+	if err != nil {
+		t.Fatalf("failed to reload transfer: %v", err)
+	}
+	if got.Status != models.NWTransferStatusInitiationPending {
+		t.Fatalf("expected the transfer to stay queued after a lost response, got %s", got.Status)
+	}
+
+	if err := svc.InitiateQueuedTransfers(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err = transferRepo.GetByID(ctx, resp.Transfer.ID)
+	if err != nil {
+		t.Fatalf("failed to reload transfer: %v", err)
+	}
+	if got.Status != models.NWTransferStatusPending {
+		t.Fatalf("expected the second drain to initiate the transfer, got %s", got.Status)
+	}
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	if len(api.references) != 1 {
+		t.Errorf("expected NorthWind to initiate the transfer once, got %v", api.references)
+	}
+	initiated, ok := api.initiations[heldInitiationKey(resp.Transfer.ID)]
+	if len(api.initiations) != 1 || !ok {
+		t.Fatalf("expected both drains to send key %s, got %v", heldInitiationKey(resp.Transfer.ID), api.initiations)
+	}
+	if got.ExternalRef == nil || *got.ExternalRef != initiated.TransferID {
+		t.Errorf("expected the transfer NorthWind first initiated, got %v", got.ExternalRef)
+	}
+}
+
+func TestNorthwindTransferService_InitiateQueuedTransfers_SkipsClaimed(t *testing.T) {
+	api := &fakeNorthwindTransferAPI{}
+	svc, transferRepo, maintenance := newQueueTestService(t, api)
+	ctx := context.Background()
+
+	userID := uuid.New()
+	resp, err := svc.CreateTransfer(ctx, userID, newTestTransferRequest(models.NWTransferDirectionOutbound))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Another worker is sending it
+	claimed, err := transferRepo.ClaimForInitiation(ctx, resp.Transfer.ID, models.NWTransferStatusInitiationPending, time.Now(), initiationClaimStaleAfter)
+	if err != nil || !claimed {
+		t.Fatalf("expected to claim the transfer, got %v (%v)", claimed, err)
+	}
+
+	endMaintenance(maintenance)
+	if err := svc.InitiateQueuedTransfers(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if api.calls() != 0 {
+		t.Errorf("expected a claimed transfer to be left to its worker, got %d NorthWind calls", api.calls())
+	}
+
+	if _, err := svc.CancelTransfer(ctx, userID, resp.Transfer.ID, "changed my mind", models.UserInitiator(userID)); !errors.Is(err, ErrNWTransferInitiating) {
+		t.Errorf("expected ErrNWTransferInitiating while the transfer is being sent, got %v", err)
+	}
+	got, err := transferRepo.GetByID(ctx, resp.Transfer.ID)
+	if err != nil {
+		t.Fatalf("failed to reload transfer: %v", err)
+	}
+	if got.Status != models.NWTransferStatusInitiationPending {
+		t.Errorf("expected the transfer to stay queued, got %s", got.Status)
+	}
+}
+
+func TestNorthwindTransferService_InitiateQueuedTransfers_KeepsInternalTest(t *testing.T) {
+	env := newStateTestEnv(t)
+	server := httptest.NewServer(&fakeNorthwindTransferAPI{})
+	defer server.Close()
+	svc := NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), env.transferRepo, nil, nil, slog.Default())
+	now := time.Now()
+	maintenance := NewNorthwindMaintenance(&MaintenanceWindow{Start: now.Add(-time.Minute), End: now.Add(time.Hour)})
+	svc.SetMaintenance(maintenance)
+	ctx := context.Background()
+
+	var queued []uuid.UUID
+	for _, ref := range []string{"REF-QA", "REF-REAL"} {
+		req := newTestTransferRequest(models.NWTransferDirectionOutbound)
+		req.ReferenceNumber = ref
+		resp, err := svc.CreateTransfer(ctx, uuid.New(), req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		queued = append(queued, resp.Transfer.ID)
+	}
+	internal, regular := queued[0], queued[1]
+	if _, err := svc.SetInternalTest(ctx, internal, true, uuid.New(), RequestMetadata{}); err != nil {
+		t.Fatalf("failed to flag the held transfer: %v", err)
+	}
+
+	endMaintenance(maintenance)
+	if err := svc.InitiateQueuedTransfers(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := env.transferRepo.GetByID(ctx, internal)
+	if err != nil {
+		t.Fatalf("failed to reload transfer: %v", err)
+	}
+	if got.ExternalRef == nil || !got.InternalTest {
+		t.Fatalf("expected the flag kept through initiation, got external ref %v, internal test %v", got.ExternalRef, got.InternalTest)
+	}
+
+	for _, id := range queued {
+		if _, err := env.states.Apply(ctx, id, models.NWTransferEventSourcePoller, &northwind.TransferResponse{Status: "COMPLETED"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	env.regulatorSvc.Shutdown(ctx)
+	for id, want := range map[uuid.UUID]int64{internal: 0, regular: 1} {
+		var notifications int64
+		if err := env.db.Model(&models.RegulatorNotification{}).Where("transfer_id = ?", id).Count(&notifications).Error; err != nil {
+			t.Fatalf("failed to count notifications: %v", err)
+		}
+		if notifications != want {
+			t.Errorf("transfer %s: expected %d regulator notifications, got %d", id, want, notifications)
+		}
+	}
+}
+
+func TestNorthwindTransferService_CancelTransfer_Queued(t *testing.T) {
+	api := &fakeNorthwindTransferAPI{}
+	svc, transferRepo, maintenance := newQueueTestService(t, api)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	resp, err := svc.CreateTransfer(ctx, userID, newTestTransferRequest(models.NWTransferDirectionOutbound))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cancelled, err := svc.CancelTransfer(ctx, userID, resp.Transfer.ID, "changed my mind", models.UserInitiator(userID))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cancelled.Status != models.NWTransferStatusCancelled {
+		t.Errorf("expected CANCELLED, got %s", cancelled.Status)
+	}
+
+	// The cancelled transfer is never sent, during or after the window
+	endMaintenance(maintenance)
+	if err := svc.InitiateQueuedTransfers(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if api.calls() != 0 {
+		t.Errorf("expected no NorthWind calls for a cancelled queued transfer, got %d", api.calls())
+	}
+	events, _ := transferRepo.ListEvents(ctx, resp.Transfer.ID)
+	if len(events) != 1 || events[0].ToStatus != models.NWTransferStatusCancelled || events[0].Source != models.NWTransferEventSourceUser ||
+		events[0].InitiatorID == nil || *events[0].InitiatorID != userID {
+		t.Errorf("expected one user cancellation event, got %+v", events)
+	}
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// SetRiskService registers the risk rules evaluated before a transfer is initiated, for users
+// the transfer_risk_rules flag is on for. Without it no rules are evaluated.
+func (s *NorthwindTransferService) SetRiskService(risk *RiskService) {
+	s.risk = risk
+}
+
+// assessRisk evaluates the risk rules against the request. A request an enforced rule flags is
+// refused with a *RiskBlockedError once its verdicts are recorded; otherwise the assessment is
+// returned so its verdicts can be recorded against the transfer once it exists.
+func (s *NorthwindTransferService) assessRisk(ctx context.Context, userID uuid.UUID, req CreateTransferRequest) (*RiskAssessment, error) {
+	if s.risk == nil || !s.featureEnabled(ctx, FlagTransferRiskRules, userID) {
+		return nil, nil
+	}
+	assessment := s.risk.Evaluate(ctx, RiskInput{
+		UserID:       userID,
+		Amount:       decimal.NewFromFloat(req.Amount),
+		Currency:     req.Currency,
+		Direction:    req.Direction,
+		TransferType: req.TransferType,
+	})
+	if blocking := assessment.Blocking(); len(blocking) > 0 {
+		s.risk.Record(context.WithoutCancel(ctx), assessment, nil)
+		return nil, &RiskBlockedError{Rules: blocking}
+	}
+	return assessment, nil
+}
+
+// recordRisk stores the assessment's verdicts against the transfer created for the request
+func (s *NorthwindTransferService) recordRisk(ctx context.Context, assessment *RiskAssessment, transferID uuid.UUID) {
+	if assessment == nil {
+		return
+	}
+	s.risk.Record(context.WithoutCancel(ctx), assessment, &transferID)
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/google/uuid"
+)
+
+// QueuedInitiationReasonScheduled is why a transfer scheduled for a later date was held
+const QueuedInitiationReasonScheduled = "scheduled"
+
+// scheduleFor decides when req is initiated. A scheduled date after now is returned with
+// scheduled true and the transfer is held until then; a date that has passed is cleared from req
+// so NorthWind initiates the transfer now instead of being asked for a date behind it.
+func scheduleFor(req *CreateTransferRequest, now time.Time) (at time.Time, scheduled bool) {
+	date := northwind.ParseRFC3339Optional(req.ScheduledDate)
+	if date == nil {
+		return time.Time{}, false
+	}
+	if date.After(now) {
+		return *date, true
+	}
+	req.ScheduledDate = ""
+	return time.Time{}, false
+}
+
+// scheduleTransfer stores a transfer that passed the local checks as SCHEDULED, keeping the full
+// request to send to NorthWind once its scheduled date arrives. NorthWind only accepts dates a
+// limited time ahead, so it does not hear of the transfer before then.
+func (s *NorthwindTransferService) scheduleTransfer(ctx context.Context, userID uuid.UUID, req CreateTransferRequest, at time.Time) (*CreateTransferResponse, error) {
+	transfer, err := s.holdTransfer(ctx, userID, req, models.NWTransferStatusScheduled)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Transfer scheduled",
+		"local_id", transfer.ID,
+		"reference_number", transfer.ReferenceNumber,
+		"scheduled_date", at,
+	)
+	s.auditTransferCreated(transfer)
+
+	return &CreateTransferResponse{
+		Transfer: transfer,
+		Queued:   &QueuedInitiation{Reason: QueuedInitiationReasonScheduled, InitiateAfter: at},
+	}, nil
+}
+
+// SubmitDueScheduledTransfers sends SCHEDULED transfers whose date has come to NorthWind,
+// earliest first, waiting while a maintenance window is open. A transfer NorthWind rejects is
+// marked FAILED; one that could not be sent stays scheduled for the next run.
+func (s *NorthwindTransferService) SubmitDueScheduledTransfers(ctx context.Context) error {
+	if _, ok := s.maintenanceWindow(); ok {
+		return nil
+	}
+
+	due, err := s.transferRepo.GetScheduledTransfersDue(ctx, time.Now(), queuedInitiationBatchSize)
+	if err != nil {
+		return err
+	}
+	return s.initiateHeld(ctx, due, models.NWTransferEventSourceSchedule)
+}
@@ -0,0 +1,240 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/google/uuid"
+)
+
+// scheduledDateRecorder serves the fake NorthWind API, recording the scheduled date sent with
+// each initiation
+type scheduledDateRecorder struct {
+	fakeNorthwindTransferAPI
+	mu    sync.Mutex
+	dates []string
+}
+
+func (s *scheduledDateRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/external/transfers/initiate" {
+		raw, _ := io.ReadAll(r.Body)
+		var body northwind.TransferRequest
+		_ = json.Unmarshal(raw, &body)
+		s.mu.Lock()
+		s.dates = append(s.dates, body.ScheduledDate)
+		s.mu.Unlock()
+		r.Body = io.NopCloser(bytes.NewReader(raw))
+	}
+	s.fakeNorthwindTransferAPI.ServeHTTP(w, r)
+}
+
+func (s *scheduledDateRecorder) sentDates() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.dates...)
+}
+
+// newScheduledTestRequest returns a transfer request scheduled for at
+func newScheduledTestRequest(reference string, at time.Time) CreateTransferRequest {
+	req := newTestTransferRequest(models.NWTransferDirectionOutbound)
+	req.ReferenceNumber = reference
+	req.ScheduledDate = at.UTC().Format(time.RFC3339)
+	return req
+}
+
+func TestNorthwindTransferService_CreateTransfer_Scheduled(t *testing.T) {
+	api := &scheduledDateRecorder{}
+	svc, transferRepo, maintenance := newQueueTestService(t, api)
+	endMaintenance(maintenance)
+	ctx := context.Background()
+	at := time.Now().Add(72 * time.Hour).Truncate(time.Second)
+
+	resp, err := svc.CreateTransfer(ctx, uuid.New(), newScheduledTestRequest("REF-1", at))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Queued == nil || resp.Queued.Reason != QueuedInitiationReasonScheduled || !resp.Queued.InitiateAfter.Equal(at) {
+		t.Fatalf("expected the transfer held until %v, got %+v", at, resp.Queued)
+	}
+	if api.calls() != 0 {
+		t.Errorf("expected no NorthWind calls for a scheduled transfer, got %d", api.calls())
+	}
+	stored, err := transferRepo.GetByID(ctx, resp.Transfer.ID)
+	if err != nil {
+		t.Fatalf("failed to reload transfer: %v", err)
+	}
+	if stored.Status != models.NWTransferStatusScheduled || stored.ExternalRef != nil || stored.ScheduledDate == nil || !stored.ScheduledDate.Equal(at) {
+		t.Errorf("expected a SCHEDULED transfer for %v, got status %s, scheduled %v", at, stored.Status, stored.ScheduledDate)
+	}
+
+	// A date that has already passed is initiated straight away, without the date
+	past, err := svc.CreateTransfer(ctx, uuid.New(), newScheduledTestRequest("REF-2", time.Now().Add(-time.Hour)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if past.Queued != nil || past.Transfer.Status != models.NWTransferStatusPending {
+		t.Errorf("expected a past scheduled date to initiate now, got status %s, queued %+v", past.Transfer.Status, past.Queued)
+	}
+	if dates := api.sentDates(); len(dates) != 1 || dates[0] != "" {
+		t.Errorf("expected one initiation without a scheduled date, got %q", dates)
+	}
+}
+
+func TestNorthwindTransferService_SubmitDueScheduledTransfers(t *testing.T) {
+	api := &scheduledDateRecorder{}
+	svc, transferRepo, maintenance := newQueueTestService(t, api)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	due, err := svc.CreateTransfer(ctx, userID, newScheduledTestRequest("REF-DUE", time.Now().Add(time.Hour)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	laterReq := newScheduledTestRequest("REF-LATER", time.Now().Add(72*time.Hour))
+	laterReq.Amount = 400
+	later, err := svc.CreateTransfer(ctx, userID, laterReq)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Move the first transfer's date into the past, as if the hour had gone by
+	stored, err := transferRepo.GetByID(ctx, due.Transfer.ID)
+	if err != nil {
+		t.Fatalf("failed to reload transfer: %v", err)
+	}
+	passed := time.Now().Add(-time.Minute)
+	stored.ScheduledDate = &passed
+	if err := transferRepo.Update(ctx, stored); err != nil {
+		t.Fatalf("failed to move the scheduled date: %v", err)
+	}
+
+	// Nothing is sent while NorthWind is in maintenance
+	if err := svc.SubmitDueScheduledTransfers(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if api.calls() != 0 {
+		t.Errorf("expected scheduled transfers to wait for maintenance to end, got %d calls", api.calls())
+	}
+
+	endMaintenance(maintenance)
+	if err := svc.SubmitDueScheduledTransfers(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if refs := api.initiatedReferences(); len(refs) != 1 || refs[0] != "REF-DUE" {
+		t.Errorf("expected only the due transfer initiated, got %v", refs)
+	}
+	if dates := api.sentDates(); len(dates) != 1 || dates[0] != "" {
+		t.Errorf("expected the due transfer sent without its scheduled date, got %q", dates)
+	}
+
+	got, err := transferRepo.GetByID(ctx, due.Transfer.ID)
+	if err != nil {
+		t.Fatalf("failed to reload transfer: %v", err)
+	}
+	if got.Status != models.NWTransferStatusPending || got.ExternalRef == nil || got.ScheduledDate == nil {
+		t.Errorf("expected the due transfer initiated keeping its date, got status %s, external ref %v, scheduled %v", got.Status, got.ExternalRef, got.ScheduledDate)
+	}
+	// The first event records moving the date
+	events, _ := transferRepo.ListEvents(ctx, got.ID)
+	if len(events) != 2 || events[1].FromStatus != models.NWTransferStatusScheduled || events[1].Source != models.NWTransferEventSourceSchedule {
+		t.Errorf("expected a schedule initiation event, got %+v", events)
+	}
+	if notDue, _ := transferRepo.GetByID(ctx, later.Transfer.ID); notDue == nil || notDue.Status != models.NWTransferStatusScheduled {
+		t.Errorf("expected the later transfer to stay SCHEDULED, got %+v", notDue)
+	}
+
+	// The maintenance queue does not pick up scheduled transfers
+	calls := api.calls()
+	if err := svc.InitiateQueuedTransfers(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if api.calls() != calls {
+		t.Errorf("expected the maintenance queue to leave scheduled transfers alone, got %d calls", api.calls()-calls)
+	}
+}
+
+func TestNorthwindTransferService_CancelTransfer_Scheduled(t *testing.T) {
+	api := &scheduledDateRecorder{}
+	svc, transferRepo, maintenance := newQueueTestService(t, api)
+	endMaintenance(maintenance)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	resp, err := svc.CreateTransfer(ctx, userID, newScheduledTestRequest("REF-1", time.Now().Add(72*time.Hour)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cancelled, err := svc.CancelTransfer(ctx, userID, resp.Transfer.ID, "changed my mind", models.UserInitiator(userID))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cancelled.Status != models.NWTransferStatusCancelled {
+		t.Errorf("expected CANCELLED, got %s", cancelled.Status)
+	}
+	if api.calls() != 0 {
+		t.Errorf("expected no NorthWind calls to cancel a scheduled transfer, got %d", api.calls())
+	}
+	events, _ := transferRepo.ListEvents(ctx, resp.Transfer.ID)
+	if len(events) != 1 || events[0].FromStatus != models.NWTransferStatusScheduled || events[0].ToStatus != models.NWTransferStatusCancelled {
+		t.Errorf("expected one SCHEDULED to CANCELLED event, got %+v", events)
+	}
+}
+
+func TestNorthwindTransferService_SubmitDueScheduledTransfers_StableIdempotencyKey(t *testing.T) {
+	api := &fakeNorthwindTransferAPI{}
+	svc, transferRepo, maintenance := newQueueTestService(t, api, northwind.WithRetry(0, 1))
+	endMaintenance(maintenance)
+	ctx := context.Background()
+
+	resp, err := svc.CreateTransfer(ctx, uuid.New(), newScheduledTestRequest("REF-DUE", time.Now().Add(time.Hour)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stored, err := transferRepo.GetByID(ctx, resp.Transfer.ID)
+	if err != nil {
+		t.Fatalf("failed to reload transfer: %v", err)
+	}
+	passed := time.Now().Add(-time.Minute)
+	stored.ScheduledDate = &passed
+	if err := transferRepo.Update(ctx, stored); err != nil {
+		t.Fatalf("failed to move the scheduled date: %v", err)
+	}
+
+	// The first run's initiation reaches NorthWind but every response to it is lost
+	api.inject(fakeEndpointInitiate, fakeFault{reset: true}, fakeFault{reset: true}, fakeFault{reset: true})
+	if err := svc.SubmitDueScheduledTransfers(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	api.mu.Lock()
+	delete(api.faults, fakeEndpointInitiate)
+	api.mu.Unlock()
+	if got, _ := transferRepo.GetByID(ctx, resp.Transfer.ID); got == nil || got.Status != models.NWTransferStatusScheduled {
+		t.Fatalf("expected the transfer to stay scheduled after a lost response, got %+v", got)
+	}
+
+	if err := svc.SubmitDueScheduledTransfers(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := transferRepo.GetByID(ctx, resp.Transfer.ID)
+	if err != nil {
+		t.Fatalf("failed to reload transfer: %v", err)
+	}
+	if got.Status != models.NWTransferStatusPending {
+		t.Fatalf("expected the second run to submit the transfer, got %s", got.Status)
+	}
+	if refs := api.initiatedReferences(); len(refs) != 1 {
+		t.Errorf("expected NorthWind to initiate the transfer once, got %v", refs)
+	}
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	if _, ok := api.initiations[heldInitiationKey(resp.Transfer.ID)]; len(api.initiations) != 1 || !ok {
+		t.Errorf("expected both runs to send key %s, got %d keys", heldInitiationKey(resp.Transfer.ID), len(api.initiations))
+	}
+}
@@ -2,10 +2,16 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
 
+	"github.com/array/banking-api/internal/config"
 	"github.com/array/banking-api/internal/integrations/northwind"
 	"github.com/array/banking-api/internal/models"
 	"github.com/array/banking-api/internal/repositories"
@@ -18,39 +24,197 @@ var (
 	ErrNWTransferInsufficientBal  = errors.New("insufficient balance in source account")
 	ErrNWTransferInitiateFailed   = errors.New("failed to initiate transfer with northwind")
 	ErrNWTransferNotFound         = errors.New("northwind transfer not found")
+	ErrNWTransferConsentRequired  = errors.New("authorization consent is required for inbound transfers")
+	ErrNWTransferUnverifiedAcct   = errors.New("external source account is not registered or not verified")
+	ErrNWTransferDuplicateRef     = errors.New("reference number already used for another transfer")
+	ErrNWTransferPossibleDup      = errors.New("a near-identical transfer was created recently")
+	ErrNWTransferUnknownStatus    = errors.New("northwind reported a transfer status we do not recognise")
+	ErrNWTransferNotUpstream      = errors.New("transfer has no northwind transfer ID")
 )
 
+// Outcomes reported per transfer by CancelAllPendingTransfers
+const (
+	BulkCancelOutcomeCancelled       = "cancelled"
+	BulkCancelOutcomeAlreadyTerminal = "already_terminal"
+	BulkCancelOutcomeUpstreamError   = "upstream_error"
+)
+
+// maxReferenceAttempts bounds regeneration when a generated reference number collides with an existing one
+const maxReferenceAttempts = 5
+
+// transferViewInterval is the least time between two recorded views of the same transfer
+const transferViewInterval = time.Minute
+
+// bulkCancelConcurrency bounds the number of in-flight NorthWind cancel calls during a bulk cancel
+const bulkCancelConcurrency = 5
+
+// transferWaitPollInterval is how often WaitForTransferChange re-reads the transfer
+const transferWaitPollInterval = time.Second
+
+// DefaultDuplicateWindow is how far back CreateTransfer looks for a near-identical transfer
+const DefaultDuplicateWindow = 2 * time.Minute
+
+// PossibleDuplicateError is returned by CreateTransfer when the user created a transfer with the
+// same amount, currency, direction, and destination within the duplicate window. It wraps
+// ErrNWTransferPossibleDup.
+type PossibleDuplicateError struct {
+	ExistingTransferID uuid.UUID
+	ReferenceNumber    string
+}
+
+func (e *PossibleDuplicateError) Error() string {
+	return fmt.Sprintf("%s: transfer %s (reference %s)", ErrNWTransferPossibleDup, e.ExistingTransferID, e.ReferenceNumber)
+}
+
+func (e *PossibleDuplicateError) Unwrap() error {
+	return ErrNWTransferPossibleDup
+}
+
 // NorthwindTransferService handles external transfer operations
 type NorthwindTransferService struct {
-	client       *northwind.Client
-	transferRepo repositories.NorthwindTransferRepositoryInterface
-	logger       *slog.Logger
+	client           *northwind.Client
+	transferRepo     repositories.NorthwindTransferRepositoryInterface
+	extAcctRepo      repositories.NorthwindExternalAccountRepositoryInterface
+	durations        TransferDurationEstimator
+	logger           *slog.Logger
+	waitPollInterval time.Duration
+	duplicateWindow  time.Duration
+	flags            *FeatureFlagService
+	cursors          transferCursorCodec
+	audit            AuditServiceInterface
+	pollSchedule     *NorthwindPollSchedule
+	maintenance      *NorthwindMaintenance
+	cancelWindows    map[string]config.CancellationWindow
+	risk             *RiskService
+	errorCodes       *NorthwindErrorCatalog
+	regulator        *RegulatorService
+	readOnly         *ReadOnlyService
+	sameDay          *SameDayPolicy
+	initiations      *InitiationGate
+	states           *TransferStateManager
 }
 
-// NewNorthwindTransferService creates a new NorthWind transfer service
+// NewNorthwindTransferService creates a new NorthWind transfer service. durations may be nil, in
+// which case transfer-creation responses carry no expected duration.
 func NewNorthwindTransferService(
 	client *northwind.Client,
 	transferRepo repositories.NorthwindTransferRepositoryInterface,
+	extAcctRepo repositories.NorthwindExternalAccountRepositoryInterface,
+	durations TransferDurationEstimator,
 	logger *slog.Logger,
 ) *NorthwindTransferService {
 	return &NorthwindTransferService{
-		client:       client,
-		transferRepo: transferRepo,
-		logger:       logger,
+		client:           client,
+		transferRepo:     transferRepo,
+		extAcctRepo:      extAcctRepo,
+		durations:        durations,
+		logger:           logger,
+		waitPollInterval: transferWaitPollInterval,
+		duplicateWindow:  DefaultDuplicateWindow,
+		cursors:          newTransferCursorCodec(),
+		errorCodes:       NewNorthwindErrorCatalog(nil, nil, logger),
 	}
 }
 
+// SetDuplicateWindow sets how far back CreateTransfer looks for a near-identical transfer.
+// A zero or negative window disables the check.
+func (s *NorthwindTransferService) SetDuplicateWindow(window time.Duration) {
+	s.duplicateWindow = window
+}
+
+// SetFeatureFlags registers the flags that gate transfer features being rolled out gradually.
+// Without it every flagged feature is off.
+func (s *NorthwindTransferService) SetFeatureFlags(flags *FeatureFlagService) {
+	s.flags = flags
+}
+
+// SetPollSchedule sets the schedule used to delay a new transfer's first status poll by its
+// type's initial delay. Without one new transfers are polled on the next cycle.
+func (s *NorthwindTransferService) SetPollSchedule(schedule *NorthwindPollSchedule) {
+	s.pollSchedule = schedule
+}
+
+// SetErrorCatalog sets the table that explains the error codes of failed transfers. Without
+// it the built-in table is used and unknown codes are not counted.
+func (s *NorthwindTransferService) SetErrorCatalog(catalog *NorthwindErrorCatalog) {
+	if catalog != nil {
+		s.errorCodes = catalog
+	}
+}
+
+// SetRegulatorService makes cancel and reverse requests report the terminal status they reach,
+// such as a reversal, to the regulator, as the TransferStateManager does for polled statuses.
+// Without it they are not reported.
+func (s *NorthwindTransferService) SetRegulatorService(regulator *RegulatorService) {
+	s.regulator = regulator
+}
+
+// SetReadOnly refuses cancellations the system starts on its own while read-only mode pauses
+// them. Cancellations users and admins request are blocked with the rest of the API instead.
+func (s *NorthwindTransferService) SetReadOnly(readOnly *ReadOnlyService) {
+	s.readOnly = readOnly
+}
+
+// SetInitiationGate bounds how many CreateTransfer calls talk to NorthWind at once; the rest
+// queue briefly and are then refused. Without it initiations are not limited.
+func (s *NorthwindTransferService) SetInitiationGate(gate *InitiationGate) {
+	s.initiations = gate
+}
+
+// SetTransferStateManager lets SyncTransferStatus apply the status NorthWind reports through the
+// same state manager the poller uses
+func (s *NorthwindTransferService) SetTransferStateManager(states *TransferStateManager) {
+	s.states = states
+}
+
+// featureEnabled is the decision point for flagged transfer features such as the approval
+// workflow, risk rules and async initiation
+func (s *NorthwindTransferService) featureEnabled(ctx context.Context, flag FeatureFlag, userID uuid.UUID) bool {
+	return s.flags != nil && s.flags.IsEnabled(ctx, flag, userID)
+}
+
 // CreateTransferRequest represents a request to create an external transfer
 type CreateTransferRequest struct {
-	Amount             float64                      `json:"amount" validate:"required,gt=0"`
-	Currency           string                       `json:"currency" validate:"required"`
-	Description        string                       `json:"description,omitempty"`
-	Direction          string                       `json:"direction" validate:"required,oneof=INBOUND OUTBOUND"`
-	TransferType       string                       `json:"transfer_type" validate:"required"`
-	ReferenceNumber    string                       `json:"reference_number" validate:"required"`
+	Amount       float64 `json:"amount" validate:"required,gt=0"`
+	Currency     string  `json:"currency" validate:"required,supported_currency"`
+	Description  string  `json:"description,omitempty"`
+	Direction    string  `json:"direction" validate:"required,nw_transfer_direction"`
+	TransferType string  `json:"transfer_type" validate:"required,nw_transfer_type,nw_transfer_type_allowed"`
+	// Priority is STANDARD when empty; SAME_DAY is for ACH transfers before the daily cutoff
+	Priority string `json:"priority,omitempty" validate:"omitempty,nw_transfer_priority"`
+	// ReferenceNumber is generated when empty; see NormalizeReference
+	ReferenceNumber    string                       `json:"reference_number,omitempty" validate:"omitempty,reference_number"`
 	ScheduledDate      string                       `json:"scheduled_date,omitempty"`
 	SourceAccount      CreateTransferAccountDetails `json:"source_account" validate:"required"`
 	DestinationAccount CreateTransferAccountDetails `json:"destination_account" validate:"required"`
+	// AuthorizationConsent is required for INBOUND transfers (debit of the external source account)
+	AuthorizationConsent *models.AuthorizationConsent `json:"authorization_consent,omitempty"`
+	// Force skips the near-identical transfer check
+	Force bool `json:"force,omitempty"`
+	// Metadata identifies the client; the handler sets it, it is never bound from the body
+	Metadata RequestMetadata `json:"-"`
+	// suppliedReference is ReferenceNumber as the client sent it, before NormalizeReference
+	suppliedReference string
+}
+
+// NormalizeReference normalizes the client-supplied reference number in place, so the normalized
+// form is what is validated, stored and sent to NorthWind. The reference as sent is kept for the
+// duplicate check, which must also find a reference stored before normalization in that form.
+func (r *CreateTransferRequest) NormalizeReference() {
+	if r.suppliedReference == "" {
+		r.suppliedReference = r.ReferenceNumber
+	}
+	r.ReferenceNumber = models.NormalizeTransferReference(r.ReferenceNumber)
+}
+
+// lookupReference returns the reference to look existing transfers up by: the reference as the
+// client sent it, whose lookup forms include the normalized one, or ReferenceNumber when the
+// request was never normalized
+func (r *CreateTransferRequest) lookupReference() string {
+	if r.suppliedReference != "" && models.NormalizeTransferReference(r.suppliedReference) == r.ReferenceNumber {
+		return r.suppliedReference
+	}
+	return r.ReferenceNumber
 }
 
 // CreateTransferAccountDetails represents account details in a transfer request
@@ -61,27 +225,171 @@ type CreateTransferAccountDetails struct {
 	InstitutionName   string `json:"institution_name,omitempty"`
 }
 
-// CreateTransferResponse represents the response from creating a transfer
+// CreateTransferResponse represents the response from creating a transfer. NorthWind's raw
+// initiation response is not part of it; it is persisted on the transfer as RawResponse. A
+// transfer queued during NorthWind maintenance has Queued set instead of Initiation.
 type CreateTransferResponse struct {
-	Transfer          *models.NorthwindTransfer   `json:"transfer"`
-	NorthwindResponse *northwind.TransferResponse `json:"northwind_response,omitempty"`
+	Transfer         *models.NorthwindTransfer     `json:"transfer"`
+	Initiation       *InitiationResult             `json:"initiation,omitempty"`
+	Queued           *QueuedInitiation             `json:"queued,omitempty"`
+	ExpectedDuration *models.TransferDurationStats `json:"expected_duration,omitempty"`
+}
+
+// InitiationResult is the subset of NorthWind's initiation response exposed to clients
+type InitiationResult struct {
+	NorthwindTransferID    string           `json:"northwind_transfer_id"`
+	Status                 string           `json:"status"`
+	ExpectedCompletionDate *time.Time       `json:"expected_completion_date,omitempty"`
+	Fee                    *decimal.Decimal `json:"fee,omitempty"`
 }
 
-// CreateTransfer validates, checks balance, initiates a transfer via NorthWind, and stores it locally
+// CreateTransfer validates, checks balance, initiates a transfer via NorthWind, and stores it locally.
+// INBOUND transfers pull funds from an external source account, so they additionally require a
+// registered and verified source account plus the account holder's authorization consent.
 func (s *NorthwindTransferService) CreateTransfer(ctx context.Context, userID uuid.UUID, req CreateTransferRequest) (*CreateTransferResponse, error) {
-	// Build NorthWind transfer request
-	nwReq := northwind.TransferRequest{
-		Amount:             req.Amount,
-		Currency:           req.Currency,
-		Description:        req.Description,
-		Direction:          req.Direction,
-		TransferType:       req.TransferType,
-		ReferenceNumber:    req.ReferenceNumber,
-		ScheduledDate:      req.ScheduledDate,
-		SourceAccount:      toNWAccountDetails(req.SourceAccount),
-		DestinationAccount: toNWAccountDetails(req.DestinationAccount),
+	if err := sanitizeTransferText(&req); err != nil {
+		return nil, err
+	}
+	if err := s.checkPriority(req); err != nil {
+		return nil, err
+	}
+	inbound := req.Direction == models.NWTransferDirectionInbound
+
+	// Step 0: Direction-specific preflight (INBOUND only)
+	if inbound {
+		if err := validateAuthorizationConsent(req.AuthorizationConsent); err != nil {
+			return nil, err
+		}
+		if err := s.requireVerifiedExternalAccount(ctx, userID, req.SourceAccount); err != nil {
+			return nil, err
+		}
+	}
+
+	referenceNumber, err := s.assignReferenceNumber(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	req.ReferenceNumber = referenceNumber
+
+	if !req.Force {
+		if err := s.checkPossibleDuplicate(ctx, userID, req); err != nil {
+			return nil, err
+		}
+	}
+
+	// Risk rules: enforced rules can refuse the request, shadow rules are only recorded
+	assessment, err := s.assessRisk(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Scheduled for a later date: keep the transfer and send it once it is due
+	if at, ok := scheduleFor(&req, time.Now()); ok {
+		resp, err := s.scheduleTransfer(ctx, userID, req, at)
+		if err == nil {
+			s.recordRisk(ctx, assessment, resp.Transfer.ID)
+		}
+		return resp, err
+	}
+
+	// NorthWind is under maintenance: keep the transfer and send it once the window closes
+	if window, ok := s.maintenanceWindow(); ok {
+		resp, err := s.queueTransfer(ctx, userID, req, window)
+		if err == nil {
+			s.recordRisk(ctx, assessment, resp.Transfer.ID)
+		}
+		return resp, err
 	}
 
+	nwResp, requestID, err := s.initiateWithNorthwind(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Step 4: Store locally
+	transfer := s.newLocalTransfer(userID, req, nwResp)
+	setInitiationRequestID(transfer, requestID)
+
+	// NorthWind has accepted the transfer, so record it even if the caller has gone away
+	if err := s.transferRepo.Create(context.WithoutCancel(ctx), transfer); err != nil {
+		if errors.Is(err, repositories.ErrNorthwindTransferDuplicateReference) {
+			// A concurrent request took the reference after our check; NorthWind already accepted the transfer
+			s.logger.Error("Reference number collided after initiation",
+				"northwind_id", transfer.NorthwindTransferID,
+				"reference_number", transfer.ReferenceNumber,
+			)
+			return nil, fmt.Errorf("%w: %s", ErrNWTransferDuplicateRef, transfer.ReferenceNumber)
+		}
+		s.logger.Error("Failed to store transfer locally", "error", err)
+		return nil, fmt.Errorf("failed to store transfer: %w", err)
+	}
+
+	s.logger.Info("Transfer initiated and stored",
+		"local_id", transfer.ID,
+		"northwind_id", transfer.NorthwindTransferID,
+		"status", transfer.Status,
+	)
+	s.auditTransferCreated(transfer)
+	s.recordRisk(ctx, assessment, transfer.ID)
+
+	s.setComputedFields(transfer)
+	resp := &CreateTransferResponse{
+		Transfer:   transfer,
+		Initiation: newInitiationResult(transfer),
+	}
+	if s.durations != nil {
+		if expected, ok := s.durations.ExpectedDuration(ctx, transfer.TransferType); ok {
+			resp.ExpectedDuration = expected
+		}
+	}
+	return resp, nil
+}
+
+// initiateWithNorthwind makes CreateTransfer's NorthWind calls, steps 1-3, holding a slot of the
+// initiation gate. It also returns NorthWind's request ID for the initiation.
+func (s *NorthwindTransferService) initiateWithNorthwind(ctx context.Context, req CreateTransferRequest) (*northwind.TransferResponse, string, error) {
+	release, err := s.initiations.Acquire(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	defer release()
+
+	// Steps 1-2: Validate with NorthWind and check the funding account's balance
+	nwReq := toNWTransferRequest(req)
+	if err := s.checkWithNorthwind(ctx, req, nwReq); err != nil {
+		return nil, "", err
+	}
+
+	// Step 3: Initiate transfer with NorthWind
+	nwResp, requestID, err := s.initiate(ctx, nwReq)
+	if err != nil {
+		s.logger.Error("NorthWind transfer initiation failed", "upstream_request_id", requestID, "error", err)
+		return nil, "", err
+	}
+	return nwResp, requestID, nil
+}
+
+// initiate sends one transfer to NorthWind, returning NorthWind's request ID for the call whether
+// or not it succeeded
+func (s *NorthwindTransferService) initiate(ctx context.Context, nwReq northwind.TransferRequest) (*northwind.TransferResponse, string, error) {
+	ctx, response := northwind.WithResponseMetadata(ctx)
+	nwResp, err := s.client.InitiateTransfer(ctx, nwReq)
+	if err != nil {
+		return nil, response.RequestID, fmt.Errorf("%w: %w", ErrNWTransferInitiateFailed, err)
+	}
+	return nwResp, response.RequestID, nil
+}
+
+// setInitiationRequestID records on transfer NorthWind's request ID for the call that initiated it
+func setInitiationRequestID(transfer *models.NorthwindTransfer, requestID string) {
+	if requestID != "" {
+		transfer.InitiationRequestID = &requestID
+	}
+}
+
+// checkWithNorthwind validates the transfer with NorthWind and checks the balance of the funding
+// account. Both calls are best effort: only a definite rejection fails the check.
+func (s *NorthwindTransferService) checkWithNorthwind(ctx context.Context, req CreateTransferRequest, nwReq northwind.TransferRequest) error {
 	// Step 1: Validate transfer with NorthWind
 	validationResp, err := s.client.ValidateTransfer(ctx, nwReq)
 	if err != nil {
@@ -91,32 +399,53 @@ func (s *NorthwindTransferService) CreateTransfer(ctx context.Context, userID uu
 		// Check for severity=error issues
 		for _, issue := range validationResp.Issues {
 			if issue.Severity == "error" {
-				return nil, fmt.Errorf("%w: %s", ErrNWTransferValidationFailed, issue.Message)
+				return fmt.Errorf("%w: %s", ErrNWTransferValidationFailed, issue.Message)
 			}
 		}
 	}
 
-	// Step 2: Check balance for source account (best effort)
-	balance, err := s.client.GetAccountBalance(ctx, req.SourceAccount.AccountNumber)
+	// Step 2: Check balance of the funding account (best effort). Funds always leave the source
+	// account: our own account for OUTBOUND, the external account being debited for INBOUND.
+	fundingAccount := req.SourceAccount.AccountNumber
+	balance, err := s.client.GetAccountBalance(ctx, fundingAccount)
 	if err != nil {
-		s.logger.Warn("Balance check failed, proceeding with initiation", "error", err)
+		s.logger.Warn("Balance check failed, proceeding with initiation",
+			"account_number", fundingAccount,
+			"direction", req.Direction,
+			"error", err,
+		)
 	} else if balance != nil && balance.AvailableBalance < req.Amount {
-		return nil, fmt.Errorf("%w: available=%.2f, requested=%.2f",
+		return fmt.Errorf("%w: available=%.2f, requested=%.2f",
 			ErrNWTransferInsufficientBal, balance.AvailableBalance, req.Amount)
 	}
+	return nil
+}
 
-	// Step 3: Initiate transfer with NorthWind
-	nwResp, err := s.client.InitiateTransfer(ctx, nwReq)
-	if err != nil {
-		s.logger.Error("NorthWind transfer initiation failed", "error", err)
-		return nil, fmt.Errorf("%w: %v", ErrNWTransferInitiateFailed, err)
+// mapResponseStatus maps the status NorthWind returned for a call we made, keeping fallback when it
+// is one we do not recognise; the poller quarantines it when it next checks the transfer
+func (s *NorthwindTransferService) mapResponseStatus(resp *northwind.TransferResponse, fallback string) string {
+	status, ok := northwind.MapStatus(resp.Status)
+	if !ok {
+		s.logger.Warn("NorthWind returned an unknown transfer status",
+			"northwind_id", resp.TransferID,
+			"status", resp.Status,
+			"kept_status", fallback,
+		)
+		return fallback
 	}
+	return status
+}
 
-	// Step 4: Store locally
+// newLocalTransfer builds the local record of a transfer from the request that initiated it and
+// NorthWind's response
+func (s *NorthwindTransferService) newLocalTransfer(userID uuid.UUID, req CreateTransferRequest, nwResp *northwind.TransferResponse) *models.NorthwindTransfer {
 	nwTransferID, err := uuid.Parse(nwResp.TransferID)
 	if err != nil {
-		s.logger.Error("Failed to parse northwind transfer ID", "transfer_id", nwResp.TransferID, "error", err)
-		nwTransferID = uuid.New() // fallback
+		// A rejected batch item has no NorthWind ID at all; anything else is unexpected
+		if nwResp.TransferID != "" {
+			s.logger.Error("Failed to parse northwind transfer ID", "transfer_id", nwResp.TransferID, "error", err)
+		}
+		nwTransferID = uuid.New() // fallback; the ID as returned is kept in ExternalRef
 	}
 
 	transfer := &models.NorthwindTransfer{
@@ -124,14 +453,21 @@ func (s *NorthwindTransferService) CreateTransfer(ctx context.Context, userID uu
 		NorthwindTransferID:      nwTransferID,
 		Direction:                req.Direction,
 		TransferType:             req.TransferType,
+		Priority:                 transferPriority(req),
 		Amount:                   decimal.NewFromFloat(req.Amount),
 		Currency:                 req.Currency,
 		ReferenceNumber:          req.ReferenceNumber,
 		SourceAccountNumber:      req.SourceAccount.AccountNumber,
 		DestinationAccountNumber: req.DestinationAccount.AccountNumber,
-		Status:                   northwind.MapStatus(nwResp.Status),
+		Status:                   s.mapResponseStatus(nwResp, models.NWTransferStatusPending),
 	}
 
+	if nwResp.TransferID != "" {
+		transfer.ExternalRef = &nwResp.TransferID
+	}
+	if nwResp.Priority != "" {
+		transfer.Priority = nwResp.Priority
+	}
 	if req.Description != "" {
 		transfer.Description = &req.Description
 	}
@@ -148,121 +484,559 @@ func (s *NorthwindTransferService) CreateTransfer(ctx context.Context, userID uu
 		transfer.DestinationAccountHolderName = &req.DestinationAccount.AccountHolderName
 	}
 
-	transfer.InitiatedDate = northwind.ParseRFC3339Optional(nwResp.InitiatedDate)
-	transfer.ProcessingDate = northwind.ParseRFC3339Optional(nwResp.ProcessingDate)
-	transfer.ExpectedCompletionDate = northwind.ParseRFC3339Optional(nwResp.ExpectedCompletionDate)
-	transfer.CompletedDate = northwind.ParseRFC3339Optional(nwResp.CompletedDate)
+	// NorthWind's scheduled date, when it gives one, replaces the requested one
+	transfer.ScheduledDate = northwind.ParseRFC3339Optional(req.ScheduledDate)
+	ApplySnapshot(transfer, northwind.ToSnapshot(nwResp))
+	s.errorCodes.Classify(transfer)
 
-	if nwResp.ScheduledDate != "" {
-		transfer.ScheduledDate = northwind.ParseRFC3339Optional(nwResp.ScheduledDate)
-	} else if req.ScheduledDate != "" {
-		transfer.ScheduledDate = northwind.ParseRFC3339Optional(req.ScheduledDate)
+	if s.pollSchedule != nil && !transfer.IsTerminal() {
+		firstPoll := s.pollSchedule.InitialPollAt(transfer.TransferType, time.Now())
+		transfer.NextPollAt = &firstPoll
 	}
 
-	if nwResp.Fee != nil {
-		fee := decimal.NewFromFloat(*nwResp.Fee)
-		transfer.Fee = &fee
-	}
-	if nwResp.ExchangeRate != nil {
-		rate := decimal.NewFromFloat(*nwResp.ExchangeRate)
-		transfer.ExchangeRate = &rate
-	}
-	if nwResp.ErrorCode != "" {
-		transfer.ErrorCode = &nwResp.ErrorCode
-	}
-	if nwResp.ErrorMessage != "" {
-		transfer.ErrorMessage = &nwResp.ErrorMessage
+	if raw, err := json.Marshal(nwResp); err == nil {
+		transfer.RawResponse = string(raw)
+	} else {
+		s.logger.Warn("Failed to encode northwind initiation response", "northwind_id", nwTransferID, "error", err)
 	}
 
-	if err := s.transferRepo.Create(transfer); err != nil {
-		s.logger.Error("Failed to store transfer locally", "error", err)
-		return nil, fmt.Errorf("failed to store transfer: %w", err)
+	if req.Direction == models.NWTransferDirectionInbound && req.AuthorizationConsent != nil {
+		consentAt := req.AuthorizationConsent.Timestamp.UTC()
+		transfer.ConsentTimestamp = &consentAt
+		transfer.ConsentIPAddress = &req.AuthorizationConsent.IPAddress
+		transfer.ConsentMethod = &req.AuthorizationConsent.Method
 	}
+	recordOrigin(transfer, req.Metadata)
+	return transfer
+}
 
-	s.logger.Info("Transfer initiated and stored",
-		"local_id", transfer.ID,
-		"northwind_id", nwTransferID,
-		"status", transfer.Status,
-	)
-
-	return &CreateTransferResponse{
-		Transfer:          transfer,
-		NorthwindResponse: nwResp,
-	}, nil
+// newInitiationResult maps the fields taken from NorthWind's initiation response, already parsed
+// onto the stored transfer, into the client-facing result
+func newInitiationResult(t *models.NorthwindTransfer) *InitiationResult {
+	return &InitiationResult{
+		NorthwindTransferID:    t.NorthwindTransferID.String(),
+		Status:                 t.Status,
+		ExpectedCompletionDate: t.ExpectedCompletionDate,
+		Fee:                    t.Fee,
+	}
 }
 
-// GetTransfer retrieves a local NorthWind transfer by ID
+// GetTransfer retrieves one of the user's local NorthWind transfers by ID. Transfers without an
+// owner, such as adopted upstream transfers and canaries, are only reachable by admins.
 func (s *NorthwindTransferService) GetTransfer(ctx context.Context, userID uuid.UUID, transferID uuid.UUID) (*models.NorthwindTransfer, error) {
-	transfer, err := s.transferRepo.GetByID(transferID)
+	transfer, err := s.transferRepo.GetByID(ctx, transferID)
 	if err != nil {
+		if errors.Is(err, repositories.ErrNorthwindTransferNotFound) {
+			return nil, ErrNWTransferNotFound
+		}
 		return nil, err
 	}
 	// Verify ownership
-	if transfer.UserID != nil && *transfer.UserID != userID {
+	if transfer.UserID == nil || *transfer.UserID != userID {
 		return nil, ErrNWTransferNotFound
 	}
+	s.setComputedFields(transfer)
+	return transfer, nil
+}
+
+// ViewTransfer is GetTransfer for a user looking at the transfer, which also marks an in-flight
+// transfer as viewed so the poller checks it ahead of the backlog
+func (s *NorthwindTransferService) ViewTransfer(ctx context.Context, userID uuid.UUID, transferID uuid.UUID) (*models.NorthwindTransfer, error) {
+	transfer, err := s.GetTransfer(ctx, userID, transferID)
+	if err != nil {
+		return nil, err
+	}
+	s.recordView(ctx, transfer)
 	return transfer, nil
 }
 
+// SyncTransferStatus asks NorthWind for the current status of userID's transfer instead of
+// waiting for the poller, and applies the answer the way the poller would: dates and error fields
+// are refreshed and a transfer that just became terminal is reported to the regulator. An
+// unchanged status leaves the stored transfer untouched. A transfer NorthWind does not know yet
+// is returned as it is.
+func (s *NorthwindTransferService) SyncTransferStatus(ctx context.Context, userID uuid.UUID, transferID uuid.UUID) (*models.NorthwindTransfer, error) {
+	transfer, err := s.GetTransfer(ctx, userID, transferID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.ExternalRef == nil || transfer.AwaitingInitiation() {
+		return transfer, nil
+	}
+	if s.states == nil {
+		return nil, errors.New("transfer status sync is not configured")
+	}
+
+	ctx, _ = northwind.WithResponseMetadata(ctx)
+	resp, err := s.client.GetTransferStatus(ctx, *transfer.ExternalRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfer status from northwind: %w", err)
+	}
+	result, err := s.states.Apply(ctx, transfer.ID, models.NWTransferEventSourceSync, resp)
+	if err != nil {
+		return nil, err
+	}
+	if result.Applied() {
+		s.logger.Info("Transfer status synced",
+			"transfer_id", transfer.ID,
+			"from_status", result.Event.FromStatus,
+			"to_status", result.Event.ToStatus,
+		)
+	}
+	s.setComputedFields(result.Transfer)
+	return result.Transfer, nil
+}
+
+// recordView marks an in-flight transfer as viewed. Writes are throttled to one per
+// transferViewInterval, and a failure only costs the priority.
+func (s *NorthwindTransferService) recordView(ctx context.Context, transfer *models.NorthwindTransfer) {
+	if transfer.IsTerminal() || transfer.AwaitingInitiation() {
+		return
+	}
+	if _, err := s.transferRepo.TouchLastViewedAt(ctx, transfer.ID, time.Now(), transferViewInterval); err != nil {
+		s.logger.Warn("Failed to record transfer view", "transfer_id", transfer.ID, "error", err)
+	}
+}
+
+// WaitForTransferChange blocks until the transfer's version moves past sinceVersion, the timeout
+// elapses, or ctx is cancelled. It returns the latest state it read and whether it changed; on
+// timeout or cancellation that is the current state with changed=false.
+func (s *NorthwindTransferService) WaitForTransferChange(ctx context.Context, userID uuid.UUID, transferID uuid.UUID, sinceVersion int, timeout time.Duration) (*models.NorthwindTransfer, bool, error) {
+	transfer, err := s.GetTransfer(ctx, userID, transferID)
+	if err != nil {
+		return nil, false, err
+	}
+	if transfer.Version > sinceVersion {
+		return transfer, true, nil
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(s.waitPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return transfer, false, nil
+		case <-deadline.C:
+			return transfer, false, nil
+		case <-ticker.C:
+			latest, err := s.GetTransfer(ctx, userID, transferID)
+			if err != nil {
+				if ctx.Err() != nil {
+					// Cancelled mid-read: same outcome as cancellation between reads
+					return transfer, false, nil
+				}
+				return nil, false, err
+			}
+			transfer = latest
+			if transfer.Version > sinceVersion {
+				return transfer, true, nil
+			}
+		}
+	}
+}
+
 // ListTransfers lists the user's NorthWind transfers with optional filters
 func (s *NorthwindTransferService) ListTransfers(ctx context.Context, userID uuid.UUID, status, direction, transferType string, offset, limit int) ([]models.NorthwindTransfer, int64, error) {
-	return s.transferRepo.GetByUserIDWithFilters(userID, status, direction, transferType, offset, limit)
+	transfers, total, err := s.transferRepo.GetByUserIDWithFilters(ctx, userID, status, direction, transferType, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	s.setPageComputedFields(transfers)
+	return transfers, total, nil
 }
 
-// CancelTransfer cancels a transfer via NorthWind
-func (s *NorthwindTransferService) CancelTransfer(ctx context.Context, userID uuid.UUID, transferID uuid.UUID, reason string) (*models.NorthwindTransfer, error) {
+// CancelTransfer cancels a transfer via NorthWind on behalf of initiator. A transfer whose
+// cancellation window has closed is refused with a CancellationWindowClosedError without asking
+// NorthWind.
+func (s *NorthwindTransferService) CancelTransfer(ctx context.Context, userID uuid.UUID, transferID uuid.UUID, reason string, initiator models.TransferInitiator) (*models.NorthwindTransfer, error) {
 	transfer, err := s.GetTransfer(ctx, userID, transferID)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.checkCancellationWindow(transfer, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.cancel(ctx, transfer, reason, initiator); err != nil {
+		return nil, err
+	}
+	s.setComputedFields(transfer)
+	return transfer, nil
+}
+
+// BulkCancelResult is the outcome of cancelling one transfer during a bulk cancel
+type BulkCancelResult struct {
+	TransferID          uuid.UUID `json:"transfer_id"`
+	NorthwindTransferID uuid.UUID `json:"northwind_transfer_id"`
+	Outcome             string    `json:"outcome"`
+	Status              string    `json:"status"`
+	Error               string    `json:"error,omitempty"`
+}
 
-	resp, err := s.client.CancelTransfer(ctx, transfer.NorthwindTransferID.String(), reason)
+// CancelAllPendingTransfers cancels every PENDING transfer owned by the user on behalf of
+// initiator, e.g. after a compromised-account report. NorthWind calls run with bounded
+// concurrency and individual failures are reported per transfer rather than aborting the batch.
+// Results follow the order in which the transfers were created.
+func (s *NorthwindTransferService) CancelAllPendingTransfers(ctx context.Context, userID uuid.UUID, reason string, initiator models.TransferInitiator) ([]BulkCancelResult, error) {
+	transfers, err := s.transferRepo.GetByUserIDAndStatus(ctx, userID, models.NWTransferStatusPending)
 	if err != nil {
-		return nil, fmt.Errorf("failed to cancel transfer: %w", err)
+		return nil, err
 	}
 
-	transfer.Status = northwind.MapStatus(resp.Status)
-	if resp.ErrorCode != "" {
-		transfer.ErrorCode = &resp.ErrorCode
+	s.logger.Info("Bulk cancelling pending NorthWind transfers",
+		"user_id", userID,
+		"count", len(transfers),
+		"reason", reason,
+		"initiator", initiator.Initiator,
+	)
+
+	results := make([]BulkCancelResult, len(transfers))
+	sem := make(chan struct{}, bulkCancelConcurrency)
+	var wg sync.WaitGroup
+	for i := range transfers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = s.cancelForBulk(ctx, &transfers[i], reason, initiator)
+		}(i)
 	}
-	if resp.ErrorMessage != "" {
-		transfer.ErrorMessage = &resp.ErrorMessage
+	wg.Wait()
+
+	return results, nil
+}
+
+// cancelForBulk cancels one transfer and classifies the outcome
+func (s *NorthwindTransferService) cancelForBulk(ctx context.Context, transfer *models.NorthwindTransfer, reason string, initiator models.TransferInitiator) BulkCancelResult {
+	result := BulkCancelResult{
+		TransferID:          transfer.ID,
+		NorthwindTransferID: transfer.NorthwindTransferID,
 	}
 
-	if err := s.transferRepo.Update(transfer); err != nil {
-		return nil, fmt.Errorf("failed to update transfer after cancel: %w", err)
+	err := s.cancel(ctx, transfer, reason, initiator)
+	result.Status = transfer.Status
+	switch {
+	case err != nil:
+		var apiErr *northwind.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+			// NorthWind refuses to cancel transfers that already settled or failed
+			result.Outcome = BulkCancelOutcomeAlreadyTerminal
+		} else {
+			result.Outcome = BulkCancelOutcomeUpstreamError
+		}
+		result.Error = err.Error()
+	case transfer.Status == models.NWTransferStatusCancelled:
+		result.Outcome = BulkCancelOutcomeCancelled
+	case transfer.IsTerminal():
+		result.Outcome = BulkCancelOutcomeAlreadyTerminal
+	default:
+		result.Outcome = BulkCancelOutcomeUpstreamError
+		result.Error = fmt.Sprintf("northwind reported status %s after cancel", transfer.Status)
 	}
 
-	return transfer, nil
+	if result.Outcome != BulkCancelOutcomeCancelled {
+		s.logger.Warn("Bulk cancel did not cancel transfer",
+			"transfer_id", transfer.ID,
+			"outcome", result.Outcome,
+			"error", result.Error,
+		)
+	}
+	return result
+}
+
+// cancel asks NorthWind to cancel the transfer and applies the resulting status. A transfer
+// still queued or scheduled never reached NorthWind and is cancelled locally. A cancellation
+// the system starts is refused with ErrReadOnlyMode while read-only mode pauses cancellations.
+func (s *NorthwindTransferService) cancel(ctx context.Context, transfer *models.NorthwindTransfer, reason string, initiator models.TransferInitiator) error {
+	if initiator.Initiator == models.NWInitiatorSystem && s.readOnly != nil && !s.readOnly.WorkerAllowed(ctx, ReadOnlyWorkerCancellations) {
+		return ErrReadOnlyMode
+	}
+	if transfer.AwaitingInitiation() {
+		cancelled, err := s.cancelQueued(ctx, transfer, reason, initiator)
+		if err != nil {
+			return err
+		}
+		if cancelled {
+			s.auditTransferStatusRequest(transfer, models.AuditActionNorthwindTransferCancelled, reason, initiator, nil)
+			return nil
+		}
+		// The queue worker initiated it first: cancel it with NorthWind like any other
+	}
+
+	upstreamID, err := upstreamTransferID(transfer)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.CancelTransfer(ctx, upstreamID, reason)
+	if err != nil {
+		return fmt.Errorf("failed to cancel transfer: %w", err)
+	}
+	if err := s.applyRequestedStatus(ctx, transfer, resp, initiator); err != nil {
+		return fmt.Errorf("failed to update transfer after cancel: %w", err)
+	}
+
+	s.logger.Info("NorthWind transfer cancelled",
+		"transfer_id", transfer.ID,
+		"northwind_transfer_id", transfer.NorthwindTransferID,
+		"status", transfer.Status,
+		"reason", reason,
+		"initiator", initiator.Initiator,
+	)
+	s.auditTransferStatusRequest(transfer, models.AuditActionNorthwindTransferCancelled, reason, initiator, nil)
+	return nil
+}
+
+// upstreamTransferID returns the ID NorthWind knows transfer by: its ExternalRef, kept exactly as
+// NorthWind returned it. NorthwindTransferID is only a placeholder when that ID is not a UUID, so
+// it must never be sent back. A transfer without one was never accepted by NorthWind.
+func upstreamTransferID(transfer *models.NorthwindTransfer) (string, error) {
+	if transfer.ExternalRef == nil || *transfer.ExternalRef == "" {
+		return "", ErrNWTransferNotUpstream
+	}
+	return *transfer.ExternalRef, nil
 }
 
-// ReverseTransfer reverses a transfer via NorthWind
-func (s *NorthwindTransferService) ReverseTransfer(ctx context.Context, userID uuid.UUID, transferID uuid.UUID, reason, description string) (*models.NorthwindTransfer, error) {
+// ReverseTransfer reverses a transfer via NorthWind on behalf of initiator
+func (s *NorthwindTransferService) ReverseTransfer(ctx context.Context, userID uuid.UUID, transferID uuid.UUID, reason, description string, initiator models.TransferInitiator) (*models.NorthwindTransfer, error) {
 	transfer, err := s.GetTransfer(ctx, userID, transferID)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.reverse(ctx, transfer, reason, description, initiator, nil); err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
 
-	resp, err := s.client.ReverseTransfer(ctx, transfer.NorthwindTransferID.String(), reason, description)
+// ReverseDisputedTransfer reverses any user's transfer on behalf of the admin who upheld a
+// dispute of it, noting the dispute on the reversal's audit event
+func (s *NorthwindTransferService) ReverseDisputedTransfer(ctx context.Context, transferID, disputeID uuid.UUID, reason, description string, adminID uuid.UUID) (*models.NorthwindTransfer, error) {
+	transfer, err := s.GetAnyTransfer(ctx, transferID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to reverse transfer: %w", err)
+		return nil, err
 	}
+	if err := s.reverse(ctx, transfer, reason, description, models.AdminInitiator(adminID), models.JSONBMap{"dispute_id": disputeID.String()}); err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
 
-	transfer.Status = northwind.MapStatus(resp.Status)
-	if resp.ErrorCode != "" {
-		transfer.ErrorCode = &resp.ErrorCode
+// reverse asks NorthWind to reverse transfer, stores the status it reports and audits the
+// request with extra added to the event's metadata
+func (s *NorthwindTransferService) reverse(ctx context.Context, transfer *models.NorthwindTransfer, reason, description string, initiator models.TransferInitiator, extra models.JSONBMap) error {
+	upstreamID, err := upstreamTransferID(transfer)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.ReverseTransfer(ctx, upstreamID, reason, description)
+	if err != nil {
+		return fmt.Errorf("failed to reverse transfer: %w", err)
 	}
-	if resp.ErrorMessage != "" {
-		transfer.ErrorMessage = &resp.ErrorMessage
+	if err := s.applyRequestedStatus(ctx, transfer, resp, initiator); err != nil {
+		return fmt.Errorf("failed to update transfer after reverse: %w", err)
 	}
 
-	if err := s.transferRepo.Update(transfer); err != nil {
-		return nil, fmt.Errorf("failed to update transfer after reverse: %w", err)
+	s.auditTransferStatusRequest(transfer, models.AuditActionNorthwindTransferReversed, reason, initiator, extra)
+	return nil
+}
+
+// applyRequestedStatus stores the status NorthWind reported in response to a cancel or reverse
+// request, with the initiator on the status-history event and on the transfer. Like the
+// TransferStateManager it writes under the row lock, changes nothing when the status is the one
+// stored, and never moves a transfer out of a terminal status other than reversing a completed
+// one. The store is detached from ctx cancellation because NorthWind has already acted on the
+// request. A status the regulator is told about is queued for it, unless the transfer is an
+// internal test transfer.
+func (s *NorthwindTransferService) applyRequestedStatus(ctx context.Context, transfer *models.NorthwindTransfer, resp *northwind.TransferResponse, initiator models.TransferInitiator) error {
+	ctx = context.WithoutCancel(ctx)
+	updated, event, err := s.transferRepo.ApplyTransition(ctx, transfer.ID, func(t *models.NorthwindTransfer) *models.NorthwindTransferEvent {
+		newStatus := s.mapResponseStatus(resp, t.Status)
+		if newStatus == t.Status || !canLeaveStatus(t, newStatus) {
+			return nil
+		}
+
+		event := initiator.Event(t.Status, newStatus)
+		t.Status = newStatus
+		ApplySnapshot(t, northwind.ToSnapshot(resp))
+		s.errorCodes.Classify(t)
+		t.CancellationInitiator = &initiator.Initiator
+		t.CancellationInitiatorID = initiator.InitiatorID
+		return event
+	})
+	if err != nil {
+		return err
 	}
+	*transfer = *updated
 
+	if event != nil && s.regulator != nil && regulatorNotifiable(event.ToStatus) && !transfer.InternalTest {
+		if err := s.regulator.CreateAndQueueNotification(ctx, transfer, event.ToStatus); err != nil {
+			s.logger.Error("Failed to create regulator notification",
+				"transfer_id", transfer.ID,
+				"status", event.ToStatus,
+				"error", err,
+			)
+		}
+	}
+	return nil
+}
+
+// GetAnyTransfer loads a transfer regardless of its owner, for admin views
+func (s *NorthwindTransferService) GetAnyTransfer(ctx context.Context, transferID uuid.UUID) (*models.NorthwindTransfer, error) {
+	transfer, err := s.transferRepo.GetByID(ctx, transferID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNorthwindTransferNotFound) {
+			return nil, ErrNWTransferNotFound
+		}
+		return nil, err
+	}
 	return transfer, nil
 }
 
+// CompareTransfer loads a transfer (any user's) and NorthWind's live record of it, with a
+// field-by-field diff. A 404 from NorthWind marks the remote side missing rather than failing.
+func (s *NorthwindTransferService) CompareTransfer(ctx context.Context, transferID uuid.UUID) (*TransferComparison, error) {
+	transfer, err := s.GetAnyTransfer(ctx, transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &TransferComparison{Local: transfer, Origin: transfer.Origin(), InitiationRequestID: transfer.InitiationRequestID}
+	upstreamID, err := upstreamTransferID(transfer)
+	if err != nil {
+		comparison.RemoteMissing = true
+		comparison.Fields = []TransferFieldDiff{}
+		return comparison, nil
+	}
+	fetchCtx, response := northwind.WithResponseMetadata(ctx)
+	remote, err := s.client.GetTransferStatus(fetchCtx, upstreamID)
+	comparison.RemoteRequestID = response.RequestID
+	if err != nil {
+		var apiErr *northwind.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			comparison.RemoteMissing = true
+			comparison.Fields = []TransferFieldDiff{}
+			return comparison, nil
+		}
+		return nil, fmt.Errorf("failed to fetch transfer from northwind: %w", err)
+	}
+
+	comparison.Remote = remote
+	comparison.Fields = DiffTransfer(transfer, remote)
+	for _, field := range comparison.Fields {
+		if !field.Match {
+			comparison.Mismatches++
+		}
+	}
+	return comparison, nil
+}
+
+// assignReferenceNumber returns the reference to use for req. A client-supplied reference,
+// already normalized, is kept but must not have been used by the same user before, in its
+// normalized form or as the client sent it; otherwise a reference is generated, retrying on the
+// (unlikely) collision with an existing one.
+func (s *NorthwindTransferService) assignReferenceNumber(ctx context.Context, userID uuid.UUID, req CreateTransferRequest) (string, error) {
+	if supplied := req.ReferenceNumber; supplied != "" {
+		exists, err := s.transferRepo.ReferenceExists(ctx, userID, req.lookupReference())
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			return "", fmt.Errorf("%w: %s", ErrNWTransferDuplicateRef, supplied)
+		}
+		return supplied, nil
+	}
+
+	for attempt := 1; attempt <= maxReferenceAttempts; attempt++ {
+		reference := models.GenerateTransferReference()
+		exists, err := s.transferRepo.ReferenceExists(ctx, userID, reference)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return reference, nil
+		}
+		s.logger.Warn("Generated reference number collided, regenerating", "attempt", attempt, "reference_number", reference)
+	}
+	return "", fmt.Errorf("failed to generate a unique reference number after %d attempts", maxReferenceAttempts)
+}
+
+// checkPossibleDuplicate rejects a request matching a transfer the user created within the
+// duplicate window, catching double submissions that carry different reference numbers
+func (s *NorthwindTransferService) checkPossibleDuplicate(ctx context.Context, userID uuid.UUID, req CreateTransferRequest) error {
+	if s.duplicateWindow <= 0 {
+		return nil
+	}
+
+	since := time.Now().Add(-s.duplicateWindow)
+	existing, err := s.transferRepo.FindRecentDuplicate(ctx, userID, decimal.NewFromFloat(req.Amount), req.Currency,
+		req.Direction, req.DestinationAccount.AccountNumber, since)
+	if errors.Is(err, repositories.ErrNorthwindTransferNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return &PossibleDuplicateError{ExistingTransferID: existing.ID, ReferenceNumber: existing.ReferenceNumber}
+}
+
+// validateAuthorizationConsent ensures an INBOUND debit carries complete authorization evidence
+func validateAuthorizationConsent(consent *models.AuthorizationConsent) error {
+	if consent == nil {
+		return ErrNWTransferConsentRequired
+	}
+	if consent.Timestamp.IsZero() {
+		return fmt.Errorf("%w: timestamp is required", ErrNWTransferConsentRequired)
+	}
+	if net.ParseIP(consent.IPAddress) == nil {
+		return fmt.Errorf("%w: a valid ip_address is required", ErrNWTransferConsentRequired)
+	}
+	switch consent.Method {
+	case models.ConsentMethodOnline, models.ConsentMethodWritten, models.ConsentMethodTelephone:
+	default:
+		return fmt.Errorf("%w: method must be one of ONLINE, WRITTEN, TELEPHONE", ErrNWTransferConsentRequired)
+	}
+	return nil
+}
+
+// requireVerifiedExternalAccount ensures the external account being debited was registered and validated by the user
+func (s *NorthwindTransferService) requireVerifiedExternalAccount(ctx context.Context, userID uuid.UUID, account CreateTransferAccountDetails) error {
+	extAcct, err := s.extAcctRepo.FindByAccountAndRouting(ctx, userID, account.AccountNumber, account.RoutingNumber)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNorthwindExternalAccountNotFound) {
+			return ErrNWTransferUnverifiedAcct
+		}
+		return fmt.Errorf("failed to look up external account: %w", err)
+	}
+	if !extAcct.Validated {
+		return ErrNWTransferUnverifiedAcct
+	}
+	return nil
+}
+
+// toNWTransferRequest builds NorthWind's request for a transfer. NorthWind only takes a priority
+// on ACH transfers, so it is left out for other types.
+func toNWTransferRequest(req CreateTransferRequest) northwind.TransferRequest {
+	nwReq := northwind.TransferRequest{
+		Amount:             req.Amount,
+		Currency:           req.Currency,
+		Description:        req.Description,
+		Direction:          req.Direction,
+		TransferType:       req.TransferType,
+		ReferenceNumber:    req.ReferenceNumber,
+		ScheduledDate:      req.ScheduledDate,
+		SourceAccount:      toNWAccountDetails(req.SourceAccount),
+		DestinationAccount: toNWAccountDetails(req.DestinationAccount),
+	}
+	if req.TransferType == models.NWTransferTypeACH {
+		nwReq.Priority = req.Priority
+	}
+	return nwReq
+}
+
 func toNWAccountDetails(d CreateTransferAccountDetails) northwind.AccountDetails {
 	return northwind.AccountDetails{
 		AccountHolderName: d.AccountHolderName,
@@ -271,4 +1045,3 @@ func toNWAccountDetails(d CreateTransferAccountDetails) northwind.AccountDetails
 		InstitutionName:   d.InstitutionName,
 	}
 }
-
@@ -0,0 +1,1254 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/repositories/repository_mocks"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Endpoints of fakeNorthwindTransferAPI that faults can be scheduled on
+const (
+	fakeEndpointValidate = "validate"
+	fakeEndpointBalance  = "balance"
+	fakeEndpointInitiate = "initiate"
+	fakeEndpointStatus   = "status"
+)
+
+// fakeFault is what fakeNorthwindTransferAPI does with one request instead of answering it
+// normally. A status fails the request before it is handled; a reset handles it and then drops
+// the connection, so NorthWind acted on a request whose response is lost.
+type fakeFault struct {
+	status int
+	delay  time.Duration
+	reset  bool
+}
+
+// fakeNorthwindTransferAPI serves the validate, balance, initiate, and transfer status endpoints
+// and records the paths hit. Faults scheduled on an endpoint are used up by its next requests, one
+// each. An initiation repeating an Idempotency-Key gets the transfer the key first initiated.
+// Every initiated transfer reports statusScript on successive status polls, repeating the last
+// entry, or PENDING without one.
+type fakeNorthwindTransferAPI struct {
+	mu           sync.Mutex
+	paths        []string
+	references   []string
+	priorities   []string
+	faults       map[string][]fakeFault
+	hits         map[string]int
+	statusScript []string
+	statuses     map[string][]string
+	initiations  map[string]northwind.TransferResponse
+}
+
+func (f *fakeNorthwindTransferAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	endpoint := fakeEndpoint(r)
+	f.mu.Lock()
+	f.paths = append(f.paths, r.URL.Path)
+	if f.hits == nil {
+		f.hits = make(map[string]int)
+	}
+	f.hits[endpoint]++
+	var fault fakeFault
+	if pending := f.faults[endpoint]; len(pending) > 0 {
+		fault, f.faults[endpoint] = pending[0], pending[1:]
+	}
+	f.mu.Unlock()
+
+	time.Sleep(fault.delay)
+	if fault.status != 0 {
+		w.WriteHeader(fault.status)
+		_ = json.NewEncoder(w).Encode(northwind.APIErrorResponse{Message: "injected fault"})
+		return
+	}
+
+	status, body := f.handle(endpoint, r)
+	if fault.reset {
+		resetConnection(w)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if body != nil {
+		_ = json.NewEncoder(w).Encode(body)
+	}
+}
+
+func (f *fakeNorthwindTransferAPI) handle(endpoint string, r *http.Request) (int, interface{}) {
+	switch endpoint {
+	case fakeEndpointValidate:
+		return http.StatusOK, northwind.TransferValidationResponse{Valid: true}
+	case fakeEndpointBalance:
+		return http.StatusOK, northwind.AccountBalance{AvailableBalance: 10000, Currency: "USD"}
+	case fakeEndpointInitiate:
+		var body northwind.TransferRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		key := r.Header.Get(northwind.IdempotencyKeyHeader)
+		transferID := uuid.New().String()
+		f.mu.Lock()
+		if initiated, ok := f.initiations[key]; ok && key != "" {
+			f.mu.Unlock()
+			return http.StatusOK, initiated
+		}
+		f.references = append(f.references, body.ReferenceNumber)
+		f.priorities = append(f.priorities, body.Priority)
+		if f.statuses == nil {
+			f.statuses = make(map[string][]string)
+		}
+		f.statuses[transferID] = append([]string(nil), f.statusScript...)
+		initiated := northwind.TransferResponse{TransferID: transferID, Status: "PENDING"}
+		if f.initiations == nil {
+			f.initiations = make(map[string]northwind.TransferResponse)
+		}
+		f.initiations[key] = initiated
+		f.mu.Unlock()
+		return http.StatusOK, initiated
+	case fakeEndpointStatus:
+		transferID := strings.TrimPrefix(r.URL.Path, "/external/transfers/")
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		script, ok := f.statuses[transferID]
+		if !ok {
+			return http.StatusNotFound, nil
+		}
+		status := "PENDING"
+		if len(script) > 0 {
+			status = script[0]
+		}
+		if len(script) > 1 {
+			f.statuses[transferID] = script[1:]
+		}
+		return http.StatusOK, northwind.TransferStatusResponse{TransferID: transferID, Status: status}
+	default:
+		return http.StatusNotFound, nil
+	}
+}
+
+// fakeEndpoint names the endpoint r is for, or returns the path for any other request
+func fakeEndpoint(r *http.Request) string {
+	switch {
+	case r.URL.Path == "/external/transfers/validate":
+		return fakeEndpointValidate
+	case strings.HasSuffix(r.URL.Path, "/balance"):
+		return fakeEndpointBalance
+	case r.URL.Path == "/external/transfers/initiate":
+		return fakeEndpointInitiate
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/external/transfers/"):
+		return fakeEndpointStatus
+	default:
+		return r.URL.Path
+	}
+}
+
+// resetConnection drops the connection under w without a response, as a TCP reset
+func resetConnection(w http.ResponseWriter) {
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		return
+	}
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		_ = tcp.SetLinger(0)
+	}
+	_ = conn.Close()
+}
+
+// inject schedules faults for the next requests to endpoint
+func (f *fakeNorthwindTransferAPI) inject(endpoint string, faults ...fakeFault) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.faults == nil {
+		f.faults = make(map[string][]fakeFault)
+	}
+	f.faults[endpoint] = append(f.faults[endpoint], faults...)
+}
+
+// requests returns how many requests endpoint received, faulted ones included
+func (f *fakeNorthwindTransferAPI) requests(endpoint string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.hits[endpoint]
+}
+
+func (f *fakeNorthwindTransferAPI) balancePaths() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []string
+	for _, p := range f.paths {
+		if strings.HasSuffix(p, "/balance") {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func (f *fakeNorthwindTransferAPI) initiatedReferences() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.references...)
+}
+
+func (f *fakeNorthwindTransferAPI) initiatedPriorities() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.priorities...)
+}
+
+func (f *fakeNorthwindTransferAPI) calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.paths)
+}
+
+func newTestTransferRequest(direction string) CreateTransferRequest {
+	return CreateTransferRequest{
+		Amount:          250,
+		Currency:        "USD",
+		Direction:       direction,
+		TransferType:    "ACH",
+		ReferenceNumber: "REF-1",
+		SourceAccount: CreateTransferAccountDetails{
+			AccountHolderName: "Source Holder",
+			AccountNumber:     "1111111111",
+			RoutingNumber:     "021000021",
+		},
+		DestinationAccount: CreateTransferAccountDetails{
+			AccountHolderName: "Destination Holder",
+			AccountNumber:     "2222222222",
+			RoutingNumber:     "021000089",
+		},
+	}
+}
+
+// expectNoRecentDuplicate lets CreateTransfer's near-identical transfer check find nothing
+func expectNoRecentDuplicate(repo *repository_mocks.MockNorthwindTransferRepositoryInterface, userID uuid.UUID) {
+	repo.EXPECT().FindRecentDuplicate(gomock.Any(), userID, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, repositories.ErrNorthwindTransferNotFound)
+}
+
+func newTestConsent() *models.AuthorizationConsent {
+	return &models.AuthorizationConsent{
+		Timestamp: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+		IPAddress: "203.0.113.10",
+		Method:    models.ConsentMethodOnline,
+	}
+}
+
+func TestNorthwindTransferService_CreateTransfer_InboundRequiresConsent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := &fakeNorthwindTransferAPI{}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	transferRepo := repository_mocks.NewMockNorthwindTransferRepositoryInterface(ctrl)
+	extAcctRepo := repository_mocks.NewMockNorthwindExternalAccountRepositoryInterface(ctrl)
+	transferRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+	svc := NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), transferRepo, extAcctRepo, nil, slog.Default())
+
+	tests := []struct {
+		name    string
+		consent *models.AuthorizationConsent
+	}{
+		{"missing", nil},
+		{"missing timestamp", &models.AuthorizationConsent{IPAddress: "203.0.113.10", Method: models.ConsentMethodOnline}},
+		{"invalid ip", &models.AuthorizationConsent{Timestamp: time.Now(), IPAddress: "not-an-ip", Method: models.ConsentMethodOnline}},
+		{"unknown method", &models.AuthorizationConsent{Timestamp: time.Now(), IPAddress: "203.0.113.10", Method: "CARRIER_PIGEON"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newTestTransferRequest(models.NWTransferDirectionInbound)
+			req.AuthorizationConsent = tt.consent
+
+			_, err := svc.CreateTransfer(context.Background(), uuid.New(), req)
+			if !errors.Is(err, ErrNWTransferConsentRequired) {
+				t.Fatalf("expected ErrNWTransferConsentRequired, got %v", err)
+			}
+		})
+	}
+
+	if n := api.calls(); n != 0 {
+		t.Errorf("expected no NorthWind calls when consent is missing, got %d", n)
+	}
+}
+
+func TestNorthwindTransferService_CreateTransfer_InboundRequiresVerifiedAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := &fakeNorthwindTransferAPI{}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	userID := uuid.New()
+	req := newTestTransferRequest(models.NWTransferDirectionInbound)
+	req.AuthorizationConsent = newTestConsent()
+
+	transferRepo := repository_mocks.NewMockNorthwindTransferRepositoryInterface(ctrl)
+	extAcctRepo := repository_mocks.NewMockNorthwindExternalAccountRepositoryInterface(ctrl)
+	gomock.InOrder(
+		extAcctRepo.EXPECT().
+			FindByAccountAndRouting(gomock.Any(), userID, req.SourceAccount.AccountNumber, req.SourceAccount.RoutingNumber).
+			Return(nil, repositories.ErrNorthwindExternalAccountNotFound),
+		extAcctRepo.EXPECT().
+			FindByAccountAndRouting(gomock.Any(), userID, req.SourceAccount.AccountNumber, req.SourceAccount.RoutingNumber).
+			Return(testfactory.NewNWExternalAccount(testfactory.WithOwner(userID), testfactory.WithValidated(false)), nil),
+	)
+	transferRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+	svc := NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), transferRepo, extAcctRepo, nil, slog.Default())
+
+	for _, name := range []string{"unregistered", "unverified"} {
+		if _, err := svc.CreateTransfer(context.Background(), userID, req); !errors.Is(err, ErrNWTransferUnverifiedAcct) {
+			t.Errorf("%s: expected ErrNWTransferUnverifiedAcct, got %v", name, err)
+		}
+	}
+	if n := api.calls(); n != 0 {
+		t.Errorf("expected no NorthWind calls for unverified account, got %d", n)
+	}
+}
+
+func TestNorthwindTransferService_CreateTransfer_BalanceCheckTarget(t *testing.T) {
+	tests := []struct {
+		direction   string
+		source      string
+		destination string
+	}{
+		// OUTBOUND debits our own account, which is the source
+		{models.NWTransferDirectionOutbound, "1111111111", "9999999999"},
+		// INBOUND debits the external account, which is the source
+		{models.NWTransferDirectionInbound, "9999999999", "1111111111"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.direction, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			api := &fakeNorthwindTransferAPI{}
+			server := httptest.NewServer(api)
+			defer server.Close()
+
+			userID := uuid.New()
+			req := newTestTransferRequest(tt.direction)
+			req.SourceAccount.AccountNumber = tt.source
+			req.DestinationAccount.AccountNumber = tt.destination
+
+			transferRepo := repository_mocks.NewMockNorthwindTransferRepositoryInterface(ctrl)
+			extAcctRepo := repository_mocks.NewMockNorthwindExternalAccountRepositoryInterface(ctrl)
+			transferRepo.EXPECT().ReferenceExists(gomock.Any(), userID, req.ReferenceNumber).Return(false, nil)
+			expectNoRecentDuplicate(transferRepo, userID)
+			if tt.direction == models.NWTransferDirectionInbound {
+				req.AuthorizationConsent = newTestConsent()
+				extAcctRepo.EXPECT().
+					FindByAccountAndRouting(gomock.Any(), userID, tt.source, req.SourceAccount.RoutingNumber).
+					Return(testfactory.NewNWExternalAccount(
+						testfactory.WithOwner(userID),
+						testfactory.WithAccountNumber(tt.source, req.SourceAccount.RoutingNumber),
+					), nil)
+			}
+
+			var stored *models.NorthwindTransfer
+			transferRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, tr *models.NorthwindTransfer) error {
+				stored = tr
+				return nil
+			})
+
+			svc := NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), transferRepo, extAcctRepo, nil, slog.Default())
+			if _, err := svc.CreateTransfer(context.Background(), userID, req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			paths := api.balancePaths()
+			expected := "/external/accounts/" + tt.source + "/balance"
+			if len(paths) != 1 || paths[0] != expected {
+				t.Errorf("expected balance check on %s, got %v", expected, paths)
+			}
+
+			if tt.direction == models.NWTransferDirectionInbound {
+				consent := stored.AuthorizationConsent()
+				if consent == nil {
+					t.Fatal("expected consent to be stored on inbound transfer")
+				}
+				if *consent != *newTestConsent() {
+					t.Errorf("expected stored consent %+v, got %+v", *newTestConsent(), *consent)
+				}
+			} else if stored.AuthorizationConsent() != nil {
+				t.Error("expected no consent on outbound transfer")
+			}
+		})
+	}
+}
+
+func TestNorthwindTransferService_CancelAllPendingTransfers_MixedOutcomes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	cancelled := testfactory.NewNWTransfer(testfactory.WithUser(userID))
+	settled := testfactory.NewNWTransfer(testfactory.WithUser(userID))
+	conflict := testfactory.NewNWTransfer(testfactory.WithUser(userID))
+	broken := testfactory.NewNWTransfer(testfactory.WithUser(userID))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/external/transfers/" + cancelled.NorthwindTransferID.String() + "/cancel":
+			_ = json.NewEncoder(w).Encode(northwind.TransferResponse{Status: "CANCELLED"})
+		case "/external/transfers/" + settled.NorthwindTransferID.String() + "/cancel":
+			_ = json.NewEncoder(w).Encode(northwind.TransferResponse{Status: "COMPLETED"})
+		case "/external/transfers/" + conflict.NorthwindTransferID.String() + "/cancel":
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"message":"transfer already completed"}`))
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	transferRepo := repository_mocks.NewMockNorthwindTransferRepositoryInterface(ctrl)
+	transferRepo.EXPECT().GetByUserIDAndStatus(gomock.Any(), userID, models.NWTransferStatusPending).
+		Return([]models.NorthwindTransfer{*cancelled, *settled, *conflict, *broken}, nil)
+
+	var mu sync.Mutex
+	updated := map[uuid.UUID]string{}
+	events := map[uuid.UUID]*models.NorthwindTransferEvent{}
+	pending := map[uuid.UUID]models.NorthwindTransfer{cancelled.ID: *cancelled, settled.ID: *settled}
+	transferRepo.EXPECT().ApplyTransition(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, id uuid.UUID, transition func(*models.NorthwindTransfer) *models.NorthwindTransferEvent) (*models.NorthwindTransfer, *models.NorthwindTransferEvent, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			tr := pending[id]
+			event := transition(&tr)
+			updated[id] = tr.Status
+			events[id] = event
+			return &tr, event, nil
+		}).Times(2)
+
+	adminID := uuid.New()
+	svc := NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), transferRepo, nil, nil, slog.Default())
+	results, err := svc.CancelAllPendingTransfers(context.Background(), userID, "compromised account", models.AdminInitiator(adminID))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		id      uuid.UUID
+		outcome string
+	}{
+		{cancelled.ID, BulkCancelOutcomeCancelled},
+		{settled.ID, BulkCancelOutcomeAlreadyTerminal},
+		{conflict.ID, BulkCancelOutcomeAlreadyTerminal},
+		{broken.ID, BulkCancelOutcomeUpstreamError},
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("expected %d results, got %d", len(expected), len(results))
+	}
+	for i, want := range expected {
+		if results[i].TransferID != want.id || results[i].Outcome != want.outcome {
+			t.Errorf("result %d: expected %s/%s, got %s/%s", i, want.id, want.outcome, results[i].TransferID, results[i].Outcome)
+		}
+	}
+	if results[3].Error == "" {
+		t.Error("expected upstream error message on failed cancellation")
+	}
+
+	if updated[cancelled.ID] != models.NWTransferStatusCancelled {
+		t.Errorf("expected cancelled transfer stored as CANCELLED, got %q", updated[cancelled.ID])
+	}
+	if updated[settled.ID] != models.NWTransferStatusCompleted {
+		t.Errorf("expected settled transfer stored as COMPLETED, got %q", updated[settled.ID])
+	}
+	if event := events[cancelled.ID]; event == nil || event.Source != models.NWTransferEventSourceAdmin || event.InitiatorID == nil || *event.InitiatorID != adminID {
+		t.Errorf("expected an admin cancellation event, got %+v", event)
+	}
+}
+
+func TestNorthwindTransferService_CancelAllPendingTransfers_ConcurrencyBound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(30 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(northwind.TransferResponse{Status: "CANCELLED"})
+	}))
+	defer server.Close()
+
+	userID := uuid.New()
+	pending := make([]models.NorthwindTransfer, 3*bulkCancelConcurrency)
+	for i := range pending {
+		pending[i] = *testfactory.NewNWTransfer(testfactory.WithUser(userID))
+	}
+
+	transferRepo := repository_mocks.NewMockNorthwindTransferRepositoryInterface(ctrl)
+	transferRepo.EXPECT().GetByUserIDAndStatus(gomock.Any(), userID, models.NWTransferStatusPending).Return(pending, nil)
+	transferRepo.EXPECT().ApplyTransition(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ uuid.UUID, transition func(*models.NorthwindTransfer) *models.NorthwindTransferEvent) (*models.NorthwindTransfer, *models.NorthwindTransferEvent, error) {
+			tr := testfactory.NewNWTransfer(testfactory.WithUser(userID))
+			return tr, transition(tr), nil
+		}).Times(len(pending))
+
+	svc := NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), transferRepo, nil, nil, slog.Default())
+	results, err := svc.CancelAllPendingTransfers(context.Background(), userID, "compromised account", models.SystemInitiator())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, r := range results {
+		if r.Outcome != BulkCancelOutcomeCancelled {
+			t.Errorf("expected all transfers cancelled, got %s for %s", r.Outcome, r.TransferID)
+		}
+	}
+	if maxInFlight > bulkCancelConcurrency {
+		t.Errorf("expected at most %d concurrent NorthWind calls, observed %d", bulkCancelConcurrency, maxInFlight)
+	}
+	if maxInFlight < 2 {
+		t.Errorf("expected cancellations to run concurrently, observed %d in flight", maxInFlight)
+	}
+}
+
+func TestNorthwindTransferService_CreateTransfer_ReferenceNumber(t *testing.T) {
+	generated := regexp.MustCompile(`^NW-\d{8}-[A-Z2-7]{10}$`)
+
+	t.Run("generated when absent", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		api := &fakeNorthwindTransferAPI{}
+		server := httptest.NewServer(api)
+		defer server.Close()
+
+		userID := uuid.New()
+		req := newTestTransferRequest(models.NWTransferDirectionOutbound)
+		req.ReferenceNumber = ""
+
+		transferRepo := repository_mocks.NewMockNorthwindTransferRepositoryInterface(ctrl)
+		gomock.InOrder(
+			// First generated reference collides, the second one is free
+			transferRepo.EXPECT().ReferenceExists(gomock.Any(), userID, gomock.Any()).Return(true, nil),
+			transferRepo.EXPECT().ReferenceExists(gomock.Any(), userID, gomock.Any()).Return(false, nil),
+			transferRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil),
+		)
+		expectNoRecentDuplicate(transferRepo, userID)
+
+		svc := NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), transferRepo, nil, nil, slog.Default())
+		resp, err := svc.CreateTransfer(context.Background(), userID, req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		ref := resp.Transfer.ReferenceNumber
+		if !generated.MatchString(ref) {
+			t.Errorf("expected generated reference, got %q", ref)
+		}
+		if sent := api.initiatedReferences(); len(sent) != 1 || sent[0] != ref {
+			t.Errorf("expected %q sent to NorthWind, got %v", ref, sent)
+		}
+	})
+
+	t.Run("client supplied is kept", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		api := &fakeNorthwindTransferAPI{}
+		server := httptest.NewServer(api)
+		defer server.Close()
+
+		userID := uuid.New()
+		req := newTestTransferRequest(models.NWTransferDirectionOutbound)
+		req.ReferenceNumber = "INVOICE-42"
+
+		transferRepo := repository_mocks.NewMockNorthwindTransferRepositoryInterface(ctrl)
+		transferRepo.EXPECT().ReferenceExists(gomock.Any(), userID, "INVOICE-42").Return(false, nil)
+		expectNoRecentDuplicate(transferRepo, userID)
+		transferRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+		svc := NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), transferRepo, nil, nil, slog.Default())
+		resp, err := svc.CreateTransfer(context.Background(), userID, req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Transfer.ReferenceNumber != "INVOICE-42" {
+			t.Errorf("expected client reference kept, got %q", resp.Transfer.ReferenceNumber)
+		}
+		if sent := api.initiatedReferences(); len(sent) != 1 || sent[0] != "INVOICE-42" {
+			t.Errorf("expected client reference sent to NorthWind, got %v", sent)
+		}
+	})
+
+	t.Run("client supplied is normalized", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		api := &fakeNorthwindTransferAPI{}
+		server := httptest.NewServer(api)
+		defer server.Close()
+
+		userID := uuid.New()
+		req := newTestTransferRequest(models.NWTransferDirectionOutbound)
+		req.ReferenceNumber = "  invoice \t 42 "
+
+		transferRepo := repository_mocks.NewMockNorthwindTransferRepositoryInterface(ctrl)
+		transferRepo.EXPECT().ReferenceExists(gomock.Any(), userID, "  invoice \t 42 ").Return(false, nil)
+		expectNoRecentDuplicate(transferRepo, userID)
+		transferRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+		svc := NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), transferRepo, nil, nil, slog.Default())
+		resp, err := svc.CreateTransfer(context.Background(), userID, req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Transfer.ReferenceNumber != "INVOICE-42" {
+			t.Errorf("expected the normalized reference stored, got %q", resp.Transfer.ReferenceNumber)
+		}
+		if sent := api.initiatedReferences(); len(sent) != 1 || sent[0] != "INVOICE-42" {
+			t.Errorf("expected the normalized reference sent to NorthWind, got %v", sent)
+		}
+	})
+
+	t.Run("client supplied duplicate is rejected", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		api := &fakeNorthwindTransferAPI{}
+		server := httptest.NewServer(api)
+		defer server.Close()
+
+		userID := uuid.New()
+		req := newTestTransferRequest(models.NWTransferDirectionOutbound)
+		req.ReferenceNumber = "REF001"
+
+		transferRepo := repository_mocks.NewMockNorthwindTransferRepositoryInterface(ctrl)
+		transferRepo.EXPECT().ReferenceExists(gomock.Any(), userID, "REF001").Return(true, nil)
+		transferRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+
+		svc := NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), transferRepo, nil, nil, slog.Default())
+		if _, err := svc.CreateTransfer(context.Background(), userID, req); !errors.Is(err, ErrNWTransferDuplicateRef) {
+			t.Fatalf("expected ErrNWTransferDuplicateRef, got %v", err)
+		}
+		if n := api.calls(); n != 0 {
+			t.Errorf("expected no NorthWind calls for a duplicate reference, got %d", n)
+		}
+	})
+}
+
+func TestNorthwindTransferService_CreateTransfer_LegacyRawReference(t *testing.T) {
+	api := &fakeNorthwindTransferAPI{}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	db := testfactory.NewDB(t)
+	svc := NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), repositories.NewNorthwindTransferRepository(db), nil, nil, slog.Default())
+
+	// Stored before references were normalized, so kept in the client's spelling
+	userID := uuid.New()
+	testfactory.NWTransfer(t, db, testfactory.WithUser(userID), func(tr *models.NorthwindTransfer) {
+		tr.ReferenceNumber = "inv 00042/a"
+	})
+
+	req := newTestTransferRequest(models.NWTransferDirectionOutbound)
+	req.ReferenceNumber = "inv 00042/a"
+	if _, err := svc.CreateTransfer(context.Background(), userID, req); !errors.Is(err, ErrNWTransferDuplicateRef) {
+		t.Fatalf("expected ErrNWTransferDuplicateRef, got %v", err)
+	}
+
+	// The handler normalizes before calling the service
+	req.NormalizeReference()
+	if _, err := svc.CreateTransfer(context.Background(), userID, req); !errors.Is(err, ErrNWTransferDuplicateRef) {
+		t.Fatalf("expected ErrNWTransferDuplicateRef after normalization, got %v", err)
+	}
+	if n := api.calls(); n != 0 {
+		t.Errorf("expected no NorthWind calls for a duplicate reference, got %d", n)
+	}
+}
+
+func newWaitTestService(t *testing.T, versions func(call int) int) (*NorthwindTransferService, uuid.UUID, *models.NorthwindTransfer) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+
+	userID := uuid.New()
+	transfer := testfactory.NewNWTransfer(testfactory.WithUser(userID))
+
+	var mu sync.Mutex
+	calls := 0
+	transferRepo := repository_mocks.NewMockNorthwindTransferRepositoryInterface(ctrl)
+	transferRepo.EXPECT().GetByID(gomock.Any(), transfer.ID).DoAndReturn(func(_ context.Context, id uuid.UUID) (*models.NorthwindTransfer, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		snapshot := *transfer
+		snapshot.Version = versions(calls)
+		return &snapshot, nil
+	}).AnyTimes()
+
+	svc := NewNorthwindTransferService(nil, transferRepo, nil, nil, slog.Default())
+	svc.waitPollInterval = 10 * time.Millisecond
+	return svc, userID, transfer
+}
+
+func TestNorthwindTransferService_WaitForTransferChange(t *testing.T) {
+	t.Run("change detected", func(t *testing.T) {
+		svc, userID, transfer := newWaitTestService(t, func(call int) int {
+			if call < 3 {
+				return 1
+			}
+			return 2
+		})
+
+		got, changed, err := svc.WaitForTransferChange(context.Background(), userID, transfer.ID, 1, 5*time.Second)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !changed || got.Version != 2 {
+			t.Errorf("expected change to version 2, got changed=%v version=%d", changed, got.Version)
+		}
+	})
+
+	t.Run("already past since_version", func(t *testing.T) {
+		svc, userID, transfer := newWaitTestService(t, func(int) int { return 3 })
+
+		start := time.Now()
+		_, changed, err := svc.WaitForTransferChange(context.Background(), userID, transfer.ID, 1, 5*time.Second)
+		if err != nil || !changed {
+			t.Fatalf("expected immediate change, got changed=%v err=%v", changed, err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("expected immediate return, took %v", elapsed)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		svc, userID, transfer := newWaitTestService(t, func(int) int { return 1 })
+
+		start := time.Now()
+		got, changed, err := svc.WaitForTransferChange(context.Background(), userID, transfer.ID, 1, 60*time.Millisecond)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if changed || got == nil || got.Version != 1 {
+			t.Errorf("expected current state unchanged, got changed=%v transfer=%+v", changed, got)
+		}
+		if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+			t.Errorf("returned before timeout after %v", elapsed)
+		}
+	})
+
+	t.Run("cancellation", func(t *testing.T) {
+		svc, userID, transfer := newWaitTestService(t, func(int) int { return 1 })
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(30*time.Millisecond, cancel)
+
+		start := time.Now()
+		_, changed, err := svc.WaitForTransferChange(ctx, userID, transfer.ID, 1, 10*time.Second)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if changed {
+			t.Errorf("expected changed=false on cancellation")
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("cancellation not honoured, took %v", elapsed)
+		}
+	})
+
+	t.Run("cancelled mid-read", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		userID := uuid.New()
+		transfer := testfactory.NewNWTransfer(testfactory.WithUser(userID))
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		calls := 0
+		transferRepo := repository_mocks.NewMockNorthwindTransferRepositoryInterface(ctrl)
+		transferRepo.EXPECT().GetByID(gomock.Any(), transfer.ID).DoAndReturn(func(ctx context.Context, _ uuid.UUID) (*models.NorthwindTransfer, error) {
+			calls++
+			if calls > 1 {
+				cancel()
+				return nil, ctx.Err()
+			}
+			snapshot := *transfer
+			snapshot.Version = 1
+			return &snapshot, nil
+		}).AnyTimes()
+		svc := NewNorthwindTransferService(nil, transferRepo, nil, nil, slog.Default())
+		svc.waitPollInterval = 10 * time.Millisecond
+
+		got, changed, err := svc.WaitForTransferChange(ctx, userID, transfer.ID, 1, 10*time.Second)
+		if err != nil {
+			t.Fatalf("expected cancellation mid-read to be reported as unchanged, got %v", err)
+		}
+		if changed || got == nil || got.Version != 1 {
+			t.Errorf("expected last read state unchanged, got changed=%v transfer=%+v", changed, got)
+		}
+	})
+
+	t.Run("other user's transfer", func(t *testing.T) {
+		svc, _, transfer := newWaitTestService(t, func(int) int { return 1 })
+
+		_, _, err := svc.WaitForTransferChange(context.Background(), uuid.New(), transfer.ID, 0, time.Second)
+		if !errors.Is(err, ErrNWTransferNotFound) {
+			t.Errorf("expected ErrNWTransferNotFound, got %v", err)
+		}
+	})
+}
+
+func TestNorthwindTransferService_CreateTransfer_ExpectedDuration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := &fakeNorthwindTransferAPI{}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	userID := uuid.New()
+	req := newTestTransferRequest(models.NWTransferDirectionOutbound)
+
+	transferRepo := repository_mocks.NewMockNorthwindTransferRepositoryInterface(ctrl)
+	transferRepo.EXPECT().ReferenceExists(gomock.Any(), userID, gomock.Any()).Return(false, nil)
+	expectNoRecentDuplicate(transferRepo, userID)
+	transferRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	transferRepo.EXPECT().GetCompletionDurationStats(gomock.Any(), gomock.Any(), gomock.Any()).Return([]models.TransferDurationStats{
+		{TransferType: req.TransferType, SampleCount: 12, P50Seconds: 172800, P95Seconds: 259200},
+	}, nil)
+
+	stats := NewNorthwindTransferStatsService(transferRepo, nil, slog.Default())
+	svc := NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), transferRepo, nil, stats, slog.Default())
+
+	resp, err := svc.CreateTransfer(context.Background(), userID, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.ExpectedDuration == nil || resp.ExpectedDuration.P50Seconds != 172800 {
+		t.Errorf("expected duration stats on the response, got %+v", resp.ExpectedDuration)
+	}
+}
+
+func TestNorthwindTransferService_CreateTransfer_PossibleDuplicate(t *testing.T) {
+	existing := testfactory.NewNWTransfer()
+
+	tests := []struct {
+		name     string
+		force    bool
+		window   time.Duration
+		found    *models.NorthwindTransfer
+		wantDup  bool
+		wantLook bool
+	}{
+		{name: "recent match is rejected", window: DefaultDuplicateWindow, found: existing, wantDup: true, wantLook: true},
+		{name: "no match proceeds", window: DefaultDuplicateWindow, wantLook: true},
+		{name: "force skips the check", force: true, window: DefaultDuplicateWindow},
+		{name: "disabled window skips the check", window: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			api := &fakeNorthwindTransferAPI{}
+			server := httptest.NewServer(api)
+			defer server.Close()
+
+			userID := uuid.New()
+			req := newTestTransferRequest(models.NWTransferDirectionOutbound)
+			req.Force = tt.force
+
+			transferRepo := repository_mocks.NewMockNorthwindTransferRepositoryInterface(ctrl)
+			transferRepo.EXPECT().ReferenceExists(gomock.Any(), userID, req.ReferenceNumber).Return(false, nil)
+			if tt.wantLook {
+				before := time.Now()
+				transferRepo.EXPECT().
+					FindRecentDuplicate(gomock.Any(), userID, gomock.Any(), "USD", models.NWTransferDirectionOutbound, "2222222222", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ uuid.UUID, amount decimal.Decimal, _, _, _ string, since time.Time) (*models.NorthwindTransfer, error) {
+						if !amount.Equal(decimal.NewFromInt(250)) {
+							t.Errorf("expected amount 250, got %s", amount)
+						}
+						// since must be the window before the request, give or take the test's runtime
+						if lower, upper := before.Add(-tt.window), time.Now().Add(-tt.window); since.Before(lower) || since.After(upper) {
+							t.Errorf("expected since within [%s, %s], got %s", lower, upper, since)
+						}
+						if tt.found == nil {
+							return nil, repositories.ErrNorthwindTransferNotFound
+						}
+						return tt.found, nil
+					})
+			}
+			if !tt.wantDup {
+				transferRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			svc := NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), transferRepo, nil, nil, slog.Default())
+			svc.SetDuplicateWindow(tt.window)
+			_, err := svc.CreateTransfer(context.Background(), userID, req)
+
+			if !tt.wantDup {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var dup *PossibleDuplicateError
+			if !errors.As(err, &dup) || !errors.Is(err, ErrNWTransferPossibleDup) {
+				t.Fatalf("expected PossibleDuplicateError, got %v", err)
+			}
+			if dup.ExistingTransferID != existing.ID {
+				t.Errorf("expected existing transfer ID %s, got %s", existing.ID, dup.ExistingTransferID)
+			}
+			if n := api.calls(); n != 0 {
+				t.Errorf("expected no NorthWind calls for a possible duplicate, got %d", n)
+			}
+		})
+	}
+}
+
+func TestNorthwindTransferService_CreateTransfer_InitiationResult(t *testing.T) {
+	fallback := &fakeNorthwindTransferAPI{}
+	nwID := uuid.New()
+	fee := 1.25
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/external/transfers/initiate" {
+			fallback.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(northwind.TransferResponse{
+			TransferID:             nwID.String(),
+			Status:                 "processing",
+			ExpectedCompletionDate: "2026-03-04T12:00:00Z",
+			Fee:                    &fee,
+			SourceAccount:          northwind.AccountDetails{AccountNumber: "1111111111"},
+			DestinationAccount:     northwind.AccountDetails{AccountNumber: "2222222222"},
+		})
+	}))
+	defer server.Close()
+
+	db := testfactory.NewDB(t)
+	transferRepo := repositories.NewNorthwindTransferRepository(db)
+	svc := NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), transferRepo, nil, nil, slog.Default())
+
+	resp, err := svc.CreateTransfer(context.Background(), uuid.New(), newTestTransferRequest(models.NWTransferDirectionOutbound))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	initiation := resp.Initiation
+	if initiation == nil {
+		t.Fatal("expected an initiation result")
+	}
+	if initiation.NorthwindTransferID != nwID.String() || initiation.Status != models.NWTransferStatusProcessing {
+		t.Errorf("unexpected initiation result %+v", initiation)
+	}
+	if initiation.ExpectedCompletionDate == nil || !initiation.ExpectedCompletionDate.Equal(time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected expected completion date %v", initiation.ExpectedCompletionDate)
+	}
+	if initiation.Fee == nil || !initiation.Fee.Equal(decimal.NewFromFloat(1.25)) {
+		t.Errorf("unexpected fee %v", initiation.Fee)
+	}
+
+	encoded, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("failed to encode response: %v", err)
+	}
+	for _, leaked := range []string{"northwind_response", "raw_response", "source_account\""} {
+		if strings.Contains(string(encoded), leaked) {
+			t.Errorf("response exposes %s: %s", leaked, encoded)
+		}
+	}
+
+	// The raw response is kept on the stored transfer and encrypted at rest
+	var stored string
+	if err := db.Raw("SELECT raw_response FROM northwind_transfers WHERE id = ?", resp.Transfer.ID).Scan(&stored).Error; err != nil {
+		t.Fatalf("failed to read raw response: %v", err)
+	}
+	if stored == "" || strings.Contains(stored, "1111111111") {
+		t.Errorf("raw response not encrypted at rest: %q", stored)
+	}
+	reloaded, err := transferRepo.GetByID(context.Background(), resp.Transfer.ID)
+	if err != nil {
+		t.Fatalf("failed to reload transfer: %v", err)
+	}
+	var raw northwind.TransferResponse
+	if err := json.Unmarshal([]byte(reloaded.RawResponse), &raw); err != nil {
+		t.Fatalf("raw response is not JSON: %v", err)
+	}
+	if raw.TransferID != nwID.String() || raw.Status != "processing" || raw.SourceAccount.AccountNumber != "1111111111" {
+		t.Errorf("unexpected raw response %+v", raw)
+	}
+}
+
+func TestNorthwindTransferService_CreateTransfer_OpaqueNorthwindID(t *testing.T) {
+	const opaqueID = "NWTR_8fK2-000931"
+	fallback := &fakeNorthwindTransferAPI{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/external/transfers/initiate" {
+			fallback.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(northwind.TransferResponse{TransferID: opaqueID, Status: "PENDING"})
+	}))
+	defer server.Close()
+
+	db := testfactory.NewDB(t)
+	transferRepo := repositories.NewNorthwindTransferRepository(db)
+	svc := NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), transferRepo, nil, nil, slog.Default())
+
+	resp, err := svc.CreateTransfer(context.Background(), uuid.New(), newTestTransferRequest(models.NWTransferDirectionOutbound))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Transfer.ExternalRef == nil || *resp.Transfer.ExternalRef != opaqueID {
+		t.Fatalf("expected external ref %q, got %v", opaqueID, resp.Transfer.ExternalRef)
+	}
+	found, err := transferRepo.GetByExternalRef(context.Background(), opaqueID)
+	if err != nil {
+		t.Fatalf("transfer not found by its NorthWind ID: %v", err)
+	}
+	if found.ID != resp.Transfer.ID {
+		t.Errorf("expected transfer %s, got %s", resp.Transfer.ID, found.ID)
+	}
+}
+
+func TestNorthwindTransferService_CreateTransfer_RecordsNorthwindRequestID(t *testing.T) {
+	api := &fakeNorthwindTransferAPI{}
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(northwind.RequestIDHeader, fmt.Sprintf("nw-req-%d", calls.Add(1)))
+		api.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	db := testfactory.NewDB(t)
+	transferRepo := repositories.NewNorthwindTransferRepository(db)
+	svc := NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key", northwind.WithRetry(0, 1)), transferRepo, nil, nil, slog.Default())
+	svc.SetDuplicateWindow(0)
+
+	// Validate, balance, then initiate: the initiation is NorthWind's third request
+	resp, err := svc.CreateTransfer(context.Background(), uuid.New(), newTestTransferRequest(models.NWTransferDirectionOutbound))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stored, err := transferRepo.GetByID(context.Background(), resp.Transfer.ID)
+	if err != nil {
+		t.Fatalf("failed to reload transfer: %v", err)
+	}
+	if stored.InitiationRequestID == nil || *stored.InitiationRequestID != "nw-req-3" {
+		t.Errorf("expected the initiation's request ID on the stored transfer, got %v", stored.InitiationRequestID)
+	}
+
+	comparison, err := svc.CompareTransfer(context.Background(), resp.Transfer.ID)
+	if err != nil {
+		t.Fatalf("unexpected compare error: %v", err)
+	}
+	if comparison.InitiationRequestID == nil || *comparison.InitiationRequestID != "nw-req-3" || comparison.RemoteRequestID != "nw-req-4" {
+		t.Errorf("expected the initiation's and the fetch's request IDs, got %v and %q", comparison.InitiationRequestID, comparison.RemoteRequestID)
+	}
+
+	// A rejected initiation carries NorthWind's request ID on the error
+	api.inject(fakeEndpointInitiate, fakeFault{status: http.StatusBadRequest})
+	_, err = svc.CreateTransfer(context.Background(), uuid.New(), newTestTransferRequest(models.NWTransferDirectionOutbound))
+	var apiErr *northwind.APIError
+	if !errors.Is(err, ErrNWTransferInitiateFailed) || !errors.As(err, &apiErr) {
+		t.Fatalf("expected a failed initiation with NorthWind's error, got %v", err)
+	}
+	if apiErr.RequestID != "nw-req-7" || northwind.RequestID(err) != "nw-req-7" {
+		t.Errorf("expected request ID nw-req-7 on the error, got %q", apiErr.RequestID)
+	}
+}
+
+func TestNorthwindTransferService_GetTransfer_NotFound(t *testing.T) {
+	db := testfactory.NewDB(t)
+	svc := NewNorthwindTransferService(nil, repositories.NewNorthwindTransferRepository(db), nil, nil, slog.Default())
+	other := testfactory.NWTransfer(t, db, testfactory.WithUser(uuid.New()))
+
+	for name, id := range map[string]uuid.UUID{"unknown": uuid.New(), "other user's": other.ID} {
+		if _, err := svc.GetTransfer(context.Background(), uuid.New(), id); !errors.Is(err, ErrNWTransferNotFound) {
+			t.Errorf("%s transfer: expected ErrNWTransferNotFound, got %v", name, err)
+		}
+	}
+}
+
+func TestNorthwindTransferService_CreateTransfer_SchedulesFirstPollByType(t *testing.T) {
+	server := httptest.NewServer(&fakeNorthwindTransferAPI{})
+	defer server.Close()
+
+	db := testfactory.NewDB(t)
+	svc := NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), repositories.NewNorthwindTransferRepository(db), nil, nil, slog.Default())
+	svc.SetPollSchedule(testPollSchedule())
+
+	for transferType, want := range map[string]time.Duration{models.NWTransferTypeRTP: 5 * time.Second, models.NWTransferTypeACH: 10 * time.Minute} {
+		req := newTestTransferRequest(models.NWTransferDirectionOutbound)
+		req.TransferType = transferType
+		req.ReferenceNumber = "REF-" + transferType
+		before := time.Now()
+		resp, err := svc.CreateTransfer(context.Background(), uuid.New(), req)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", transferType, err)
+		}
+		if resp.Transfer.NextPollAt == nil {
+			t.Errorf("%s: expected the first poll to be scheduled", transferType)
+			continue
+		}
+		if delay := resp.Transfer.NextPollAt.Sub(before); delay < want || delay > want+5*time.Second {
+			t.Errorf("%s: first poll in %v, want about %v", transferType, delay, want)
+		}
+	}
+}
+
+func TestNorthwindTransferService_ViewTransfer_RecordsView(t *testing.T) {
+	db := testfactory.NewDB(t)
+	transferRepo := repositories.NewNorthwindTransferRepository(db)
+	svc := NewNorthwindTransferService(nil, transferRepo, nil, nil, slog.Default())
+	ctx := context.Background()
+	userID := uuid.New()
+	pending := testfactory.NWTransfer(t, db, testfactory.WithUser(userID))
+	completed := testfactory.NWTransfer(t, db, testfactory.WithUser(userID), testfactory.WithStatus(models.NWTransferStatusCompleted))
+
+	for _, transfer := range []*models.NorthwindTransfer{pending, completed} {
+		if _, err := svc.ViewTransfer(ctx, userID, transfer.ID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	got, _ := transferRepo.GetByID(ctx, pending.ID)
+	if got.LastViewedAt == nil {
+		t.Error("expected viewing a pending transfer to record the view")
+	}
+	got, _ = transferRepo.GetByID(ctx, completed.ID)
+	if got.LastViewedAt != nil {
+		t.Errorf("expected no view recorded for a completed transfer, got %v", got.LastViewedAt)
+	}
+
+	// Plain reads, such as the long poll's, record nothing
+	other := testfactory.NWTransfer(t, db, testfactory.WithUser(userID))
+	if _, err := svc.GetTransfer(ctx, userID, other.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, _ := transferRepo.GetByID(ctx, other.ID); got.LastViewedAt != nil {
+		t.Errorf("expected GetTransfer not to record a view, got %v", got.LastViewedAt)
+	}
+}
+
+// newSyncTestClient returns a client whose NorthWind reports resp for every transfer and counts
+// the requests it receives
+func newSyncTestClient(t *testing.T, resp northwind.TransferResponse) (*northwind.Client, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return northwind.NewClient(server.URL, "test-key"), &calls
+}
+
+func TestNorthwindTransferService_SyncTransferStatus_Unchanged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	transfer := testfactory.NewNWTransfer(testfactory.WithUser(userID))
+	client, calls := newSyncTestClient(t, northwind.TransferResponse{TransferID: *transfer.ExternalRef, Status: "PENDING"})
+
+	transferRepo := repository_mocks.NewMockNorthwindTransferRepositoryInterface(ctrl)
+	transferRepo.EXPECT().GetByID(gomock.Any(), transfer.ID).Return(transfer, nil)
+	transferRepo.EXPECT().ApplyTransition(gomock.Any(), transfer.ID, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ uuid.UUID, transition func(*models.NorthwindTransfer) *models.NorthwindTransferEvent) (*models.NorthwindTransfer, *models.NorthwindTransferEvent, error) {
+			stored := *transfer
+			if event := transition(&stored); event != nil {
+				t.Errorf("expected no transition for an unchanged status, got %+v", event)
+			}
+			return &stored, nil, nil
+		})
+	transferRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Times(0)
+
+	svc := NewNorthwindTransferService(client, transferRepo, nil, nil, slog.Default())
+	svc.SetTransferStateManager(NewTransferStateManager(transferRepo, nil, slog.Default()))
+
+	got, err := svc.SyncTransferStatus(context.Background(), userID, transfer.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Status != models.NWTransferStatusPending {
+		t.Errorf("expected the transfer to stay PENDING, got %s", got.Status)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("expected one NorthWind call, got %d", n)
+	}
+}
+
+func TestNorthwindTransferService_SyncTransferStatus_PendingToCompleted(t *testing.T) {
+	env := newStateTestEnv(t)
+	ctx := context.Background()
+	userID := uuid.New()
+	transfer := testfactory.NWTransfer(t, env.db, testfactory.WithUser(userID))
+	client, calls := newSyncTestClient(t, northwind.TransferResponse{
+		TransferID:     *transfer.ExternalRef,
+		Status:         "COMPLETED",
+		ProcessingDate: "2025-03-01T10:00:00Z",
+		CompletedDate:  "2025-03-01T16:30:00Z",
+	})
+	svc := NewNorthwindTransferService(client, env.transferRepo, nil, nil, slog.Default())
+	svc.SetTransferStateManager(env.states)
+
+	if _, err := svc.SyncTransferStatus(ctx, uuid.New(), transfer.ID); !errors.Is(err, ErrNWTransferNotFound) {
+		t.Fatalf("expected ErrNWTransferNotFound for another user's transfer, got %v", err)
+	}
+	if n := calls.Load(); n != 0 {
+		t.Fatalf("expected no NorthWind call for another user's transfer, got %d", n)
+	}
+
+	got, err := svc.SyncTransferStatus(ctx, userID, transfer.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Status != models.NWTransferStatusCompleted || got.ProcessingDate == nil || got.CompletedDate == nil {
+		t.Errorf("expected a COMPLETED transfer with NorthWind's dates, got status %s, dates %v/%v", got.Status, got.ProcessingDate, got.CompletedDate)
+	}
+	env.assertOutcome(t, transfer.ID, 1, 1)
+
+	events, err := env.transferRepo.ListEvents(ctx, transfer.ID)
+	if err != nil {
+		t.Fatalf("failed to list events: %v", err)
+	}
+	if events[0].Source != models.NWTransferEventSourceSync {
+		t.Errorf("expected a %s event, got %s", models.NWTransferEventSourceSync, events[0].Source)
+	}
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+)
+
+// ApplySnapshot copies NorthWind's view of a transfer onto the local record; the status is left
+// to the caller, which decides whether the transition is allowed and sets it first. It is the
+// one place responses from initiation, cancellation, reversal, polling and webhooks reach the
+// model, so they all persist the same fields. The rules:
+//   - ExpectedCompletionDate follows NorthWind: an estimate it no longer gives is cleared
+//   - the dates of what already happened (scheduled, initiated, processing, completed), the fee
+//     and the exchange rate are only set when present, so a later response that omits them
+//     never erases them
+//   - the error code and message follow applyUpstreamError
+func ApplySnapshot(transfer *models.NorthwindTransfer, snap northwind.TransferSnapshot) {
+	transfer.ExpectedCompletionDate = snap.ExpectedCompletionDate
+
+	if snap.ScheduledDate != nil {
+		transfer.ScheduledDate = snap.ScheduledDate
+	}
+	if snap.InitiatedDate != nil {
+		transfer.InitiatedDate = snap.InitiatedDate
+	}
+	if snap.ProcessingDate != nil {
+		transfer.ProcessingDate = snap.ProcessingDate
+	}
+	if snap.CompletedDate != nil {
+		transfer.CompletedDate = snap.CompletedDate
+	}
+	if snap.Fee != nil {
+		transfer.Fee = snap.Fee
+	}
+	if snap.ExchangeRate != nil {
+		transfer.ExchangeRate = snap.ExchangeRate
+	}
+	applyUpstreamError(transfer, snap)
+}
+
+// applyUpstreamError keeps the error code and message for statuses NorthWind explains with one,
+// setting whichever the response carries. A transfer in any other status has recovered, as when
+// NorthWind retried a failed transfer to COMPLETED, so its error and the classification of it
+// are cleared rather than shown to support long after they stopped applying.
+func applyUpstreamError(transfer *models.NorthwindTransfer, snap northwind.TransferSnapshot) {
+	if !models.NWTransferStatusKeepsError(transfer.Status) {
+		transfer.ErrorCode = nil
+		transfer.ErrorMessage = nil
+		transfer.FailureCategory = nil
+		transfer.FailureRetryable = nil
+		return
+	}
+	if snap.ErrorCode != nil {
+		transfer.ErrorCode = snap.ErrorCode
+	}
+	if snap.ErrorMessage != nil {
+		transfer.ErrorMessage = snap.ErrorMessage
+	}
+}
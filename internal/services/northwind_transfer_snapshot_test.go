@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestApplySnapshot_ClearsOnlyTheEstimate(t *testing.T) {
+	at := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	fee := decimal.NewFromFloat(1.25)
+	code, message := "R01", "insufficient funds"
+	transfer := testfactory.NewNWTransfer(testfactory.WithStatus(models.NWTransferStatusReversed), func(tr *models.NorthwindTransfer) {
+		tr.InitiatedDate, tr.ProcessingDate, tr.CompletedDate, tr.ExpectedCompletionDate = &at, &at, &at, &at
+		tr.Fee, tr.ErrorCode, tr.ErrorMessage = &fee, &code, &message
+	})
+
+	ApplySnapshot(transfer, northwind.ToSnapshot(&northwind.TransferResponse{Status: "REVERSED"}))
+
+	if transfer.ExpectedCompletionDate != nil {
+		t.Errorf("expected the completion estimate cleared, got %v", transfer.ExpectedCompletionDate)
+	}
+	if transfer.InitiatedDate == nil || transfer.ProcessingDate == nil || transfer.CompletedDate == nil {
+		t.Errorf("expected past dates kept, got %+v", transfer)
+	}
+	if transfer.Fee == nil || transfer.ErrorCode == nil || transfer.ErrorMessage == nil {
+		t.Errorf("expected fee and error kept, got %+v", transfer)
+	}
+}
+
+// persistedFields renders the fields every response path maps from NorthWind
+func persistedFields(t *testing.T, repo repositories.NorthwindTransferRepositoryInterface, id uuid.UUID) string {
+	t.Helper()
+	transfer, err := repo.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("failed to reload transfer: %v", err)
+	}
+	date := func(d *time.Time) string {
+		if d == nil {
+			return "nil"
+		}
+		return d.UTC().Format(time.RFC3339)
+	}
+	str := func(s *string) string {
+		if s == nil {
+			return "nil"
+		}
+		return *s
+	}
+	dec := func(d *decimal.Decimal) string {
+		if d == nil {
+			return "nil"
+		}
+		return d.String()
+	}
+	return fmt.Sprintf("status=%s initiated=%s processing=%s expected=%s completed=%s fee=%s rate=%s code=%s message=%s",
+		transfer.Status, date(transfer.InitiatedDate), date(transfer.ProcessingDate), date(transfer.ExpectedCompletionDate),
+		date(transfer.CompletedDate), dec(transfer.Fee), dec(transfer.ExchangeRate), str(transfer.ErrorCode), str(transfer.ErrorMessage))
+}
+
+func TestApplySnapshot_CreatePollAndWebhookPersistTheSame(t *testing.T) {
+	env := newStateTestEnv(t)
+	fee, rate := 1.25, 0.9175
+	response := func(transferID string) northwind.TransferResponse {
+		return northwind.TransferResponse{
+			TransferID:             transferID,
+			Status:                 "FAILED",
+			InitiatedDate:          "2025-03-01T09:00:00Z",
+			ProcessingDate:         "2025-03-01T10:00:00Z",
+			ExpectedCompletionDate: "2025-03-03T00:00:00Z",
+			CompletedDate:          "2025-03-02T16:30:00Z",
+			Fee:                    &fee,
+			ExchangeRate:           &rate,
+			ErrorCode:              "R01",
+			ErrorMessage:           "insufficient funds",
+		}
+	}
+	polled := testfactory.NWTransfer(t, env.db)
+	hooked := testfactory.NWTransfer(t, env.db)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/external/transfers/validate":
+			_ = json.NewEncoder(w).Encode(northwind.TransferValidationResponse{Valid: true})
+		case strings.HasSuffix(r.URL.Path, "/balance"):
+			_ = json.NewEncoder(w).Encode(northwind.AccountBalance{AvailableBalance: 10000, Currency: "USD"})
+		case r.URL.Path == "/external/transfers/initiate":
+			_ = json.NewEncoder(w).Encode(response(uuid.NewString()))
+		case r.URL.Path == "/external/transfers/"+polled.NorthwindTransferID.String():
+			_ = json.NewEncoder(w).Encode(response(polled.NorthwindTransferID.String()))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := northwind.NewClient(server.URL, "test-key")
+
+	transferSvc := NewNorthwindTransferService(client, env.transferRepo, repositories.NewNorthwindExternalAccountRepository(env.db), nil, slog.Default())
+	created, err := transferSvc.CreateTransfer(context.Background(), uuid.New(), newTestTransferRequest(models.NWTransferDirectionOutbound))
+	if err != nil {
+		t.Fatalf("failed to create transfer: %v", err)
+	}
+
+	poller := NewNorthwindPollingService(client, env.transferRepo, env.regulatorSvc, 0, slog.Default())
+	poller.SetTransferStateManager(env.states)
+	poller.checkTransferStatus(context.Background(), polled)
+
+	webhook := response(*hooked.ExternalRef)
+	if _, err := env.states.ApplyRemote(context.Background(), models.NWTransferEventSourceWebhook, &webhook); err != nil {
+		t.Fatalf("failed to apply webhook: %v", err)
+	}
+	env.regulatorSvc.Shutdown(context.Background())
+
+	want := persistedFields(t, env.transferRepo, created.Transfer.ID)
+	if !strings.Contains(want, "initiated=2025-03-01T09:00:00Z") || !strings.Contains(want, "fee=1.25") {
+		t.Fatalf("expected the created transfer to carry NorthWind's values, got %s", want)
+	}
+	for name, id := range map[string]uuid.UUID{"poller": polled.ID, "webhook": hooked.ID} {
+		if got := persistedFields(t, env.transferRepo, id); got != want {
+			t.Errorf("%s persisted\n  %s\nbut create persisted\n  %s", name, got, want)
+		}
+	}
+}
+
+func TestApplyUpstreamError(t *testing.T) {
+	stale, staleMessage, category, retryable := "R01", "insufficient funds", models.NWFailureCategoryFunds, false
+	tests := []struct {
+		name        string
+		status      string
+		resp        northwind.TransferResponse
+		wantCode    string
+		wantMessage string
+	}{
+		{"failure sets the error", models.NWTransferStatusFailed, northwind.TransferResponse{ErrorCode: "R03", ErrorMessage: "no account"}, "R03", "no account"},
+		{"failure without an error keeps the stored one", models.NWTransferStatusFailed, northwind.TransferResponse{}, stale, staleMessage},
+		{"reversal keeps the stored error", models.NWTransferStatusReversed, northwind.TransferResponse{}, stale, staleMessage},
+		{"completion clears the error", models.NWTransferStatusCompleted, northwind.TransferResponse{}, "", ""},
+		{"completion ignores an error NorthWind still reports", models.NWTransferStatusCompleted, northwind.TransferResponse{ErrorCode: "R01"}, "", ""},
+		{"processing clears the error", models.NWTransferStatusProcessing, northwind.TransferResponse{}, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transfer := testfactory.NewNWTransfer(testfactory.WithStatus(tt.status), func(tr *models.NorthwindTransfer) {
+				code, message, cat, retry := stale, staleMessage, category, retryable
+				tr.ErrorCode, tr.ErrorMessage, tr.FailureCategory, tr.FailureRetryable = &code, &message, &cat, &retry
+			})
+
+			ApplySnapshot(transfer, northwind.ToSnapshot(&tt.resp))
+
+			str := func(s *string) string {
+				if s == nil {
+					return ""
+				}
+				return *s
+			}
+			if str(transfer.ErrorCode) != tt.wantCode || str(transfer.ErrorMessage) != tt.wantMessage {
+				t.Errorf("expected error %q/%q, got %q/%q", tt.wantCode, tt.wantMessage, str(transfer.ErrorCode), str(transfer.ErrorMessage))
+			}
+			if cleared := tt.wantCode == ""; cleared != (transfer.FailureCategory == nil && transfer.FailureRetryable == nil) {
+				t.Errorf("expected the failure classification cleared=%v, got %v/%v", cleared, transfer.FailureCategory, transfer.FailureRetryable)
+			}
+		})
+	}
+}
+
+func TestTransferStateManager_Apply_RecoveryClearsStoredError(t *testing.T) {
+	env := newStateTestEnv(t)
+	ctx := context.Background()
+	transfer := testfactory.NWTransfer(t, env.db, testfactory.WithStatus(models.NWTransferStatusProcessing))
+	code, message, category := "R01", "insufficient funds, retrying", models.NWFailureCategoryFunds
+	transfer.ErrorCode, transfer.ErrorMessage, transfer.FailureCategory = &code, &message, &category
+	if err := env.db.Save(transfer).Error; err != nil {
+		t.Fatalf("failed to store the stale error: %v", err)
+	}
+
+	if _, err := env.states.Apply(ctx, transfer.ID, models.NWTransferEventSourcePoller, &northwind.TransferResponse{Status: "COMPLETED"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	env.regulatorSvc.Shutdown(ctx)
+
+	stored, err := env.transferRepo.GetByID(ctx, transfer.ID)
+	if err != nil {
+		t.Fatalf("failed to reload transfer: %v", err)
+	}
+	if stored.Status != models.NWTransferStatusCompleted || stored.ErrorCode != nil || stored.ErrorMessage != nil || stored.FailureCategory != nil {
+		t.Errorf("expected a COMPLETED transfer without the stale error, got status %s, error %v/%v, category %v",
+			stored.Status, stored.ErrorCode, stored.ErrorMessage, stored.FailureCategory)
+	}
+}
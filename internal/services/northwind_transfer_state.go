@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// TransferTransition is the outcome of applying a NorthWind status observation to a transfer
+type TransferTransition struct {
+	Transfer *models.NorthwindTransfer
+	// Event is the recorded status change; nil when there was nothing to apply
+	Event *models.NorthwindTransferEvent
+}
+
+// Applied reports whether the observation changed the transfer's status
+func (t *TransferTransition) Applied() bool {
+	return t.Event != nil
+}
+
+// TransferStateManager applies NorthWind status observations to transfers. It is the one place
+// status transitions are written, so the poller and the webhook receiver can report the same
+// transition concurrently: the read, check and update run under a row lock, re-applying the
+// current status is a no-op, and each actual transition records exactly one status-history event
+// and at most one regulator notification.
+type TransferStateManager struct {
+	transferRepo repositories.NorthwindTransferRepositoryInterface
+	regulatorSvc *RegulatorService
+	schedule     *NorthwindPollSchedule
+	notifier     *TransferNotifier
+	errorCodes   *NorthwindErrorCatalog
+	logger       *slog.Logger
+}
+
+// NewTransferStateManager creates a new transfer state manager
+func NewTransferStateManager(
+	transferRepo repositories.NorthwindTransferRepositoryInterface,
+	regulatorSvc *RegulatorService,
+	logger *slog.Logger,
+) *TransferStateManager {
+	return &TransferStateManager{
+		transferRepo: transferRepo,
+		regulatorSvc: regulatorSvc,
+		schedule:     NewNorthwindPollSchedule(nil),
+		errorCodes:   NewNorthwindErrorCatalog(nil, nil, logger),
+		logger:       logger,
+	}
+}
+
+// SetPollSchedule sets the schedule used to plan the next poll after a non-terminal transition
+func (m *TransferStateManager) SetPollSchedule(schedule *NorthwindPollSchedule) {
+	if schedule != nil {
+		m.schedule = schedule
+	}
+}
+
+// SetErrorCatalog sets the table that classifies the error codes of transfers that fail
+func (m *TransferStateManager) SetErrorCatalog(catalog *NorthwindErrorCatalog) {
+	if catalog != nil {
+		m.errorCodes = catalog
+	}
+}
+
+// SetNotifier sets the notifier that tells transfer owners about each transition
+func (m *TransferStateManager) SetNotifier(notifier *TransferNotifier) {
+	m.notifier = notifier
+}
+
+// ApplyRemote applies a status observation to the transfer NorthWind identifies by its transfer ID
+func (m *TransferStateManager) ApplyRemote(ctx context.Context, source string, remote *northwind.TransferResponse) (*TransferTransition, error) {
+	transfer, err := m.transferRepo.GetByExternalRef(ctx, remote.TransferID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNorthwindTransferNotFound) {
+			return nil, ErrNWTransferNotFound
+		}
+		return nil, err
+	}
+	return m.Apply(ctx, transfer.ID, source, remote)
+}
+
+// Apply applies NorthWind's view of a transfer, as observed by source. A status equal to the
+// stored one changes nothing, and neither does one that would move a transfer backwards: out of a
+// terminal status, other than a completed transfer being reversed, or from processing to pending.
+// A status we do not recognise is ErrNWTransferUnknownStatus and leaves the transfer untouched.
+// When remote came from a call made with a context from northwind.WithResponseMetadata, the event
+// records NorthWind's request ID.
+func (m *TransferStateManager) Apply(ctx context.Context, transferID uuid.UUID, source string, remote *northwind.TransferResponse) (*TransferTransition, error) {
+	newStatus, ok := northwind.MapStatus(remote.Status)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrNWTransferUnknownStatus, remote.Status)
+	}
+	transfer, event, err := m.transferRepo.ApplyTransition(ctx, transferID, func(transfer *models.NorthwindTransfer) *models.NorthwindTransferEvent {
+		if newStatus == transfer.Status || !canLeaveStatus(transfer, newStatus) {
+			return nil
+		}
+
+		event := &models.NorthwindTransferEvent{FromStatus: transfer.Status, ToStatus: newStatus, Source: source}
+		event.UpstreamRequestID = upstreamRequestID(ctx)
+		transfer.Status = newStatus
+		transfer.NextPollAt = nil
+		if !transfer.IsTerminal() {
+			next := m.schedule.NextPollAt(transfer, true, time.Now())
+			transfer.NextPollAt = &next
+		}
+
+		ApplySnapshot(transfer, northwind.ToSnapshot(remote))
+		m.errorCodes.Classify(transfer)
+		return event
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrNorthwindTransferNotFound) {
+			return nil, ErrNWTransferNotFound
+		}
+		return nil, err
+	}
+
+	result := &TransferTransition{Transfer: transfer, Event: event}
+	if event == nil {
+		if newStatus != transfer.Status {
+			m.logger.Warn("Ignoring stale NorthWind status",
+				"transfer_id", transfer.ID,
+				"status", transfer.Status,
+				"reported_status", newStatus,
+				"source", source,
+			)
+		}
+		return result, nil
+	}
+
+	m.logger.Info("Transfer status updated",
+		"transfer_id", transfer.ID,
+		"northwind_id", transfer.NorthwindTransferID,
+		"old_status", event.FromStatus,
+		"new_status", event.ToStatus,
+		"source", source,
+	)
+
+	// If terminal state, record the regulator notification; delivery happens off the caller's path.
+	// Internal test transfers are never reported.
+	terminal := regulatorNotifiable(event.ToStatus)
+	if terminal && transfer.InternalTest {
+		m.logger.Debug("Internal test transfer reached terminal state, skipping regulator notification",
+			"transfer_id", transfer.ID,
+			"status", event.ToStatus,
+		)
+	} else if terminal {
+		m.logger.Info("Transfer reached terminal state, creating regulator notification",
+			"transfer_id", transfer.ID,
+			"status", event.ToStatus,
+		)
+		if err := m.regulatorSvc.CreateAndQueueNotification(ctx, transfer, event.ToStatus); err != nil {
+			m.logger.Error("Failed to create regulator notification",
+				"transfer_id", transfer.ID,
+				"error", err,
+			)
+		}
+	}
+
+	if m.notifier != nil {
+		if err := m.notifier.NotifyTransferEvent(ctx, transfer, event); err != nil {
+			m.logger.Error("Failed to notify transfer owner",
+				"transfer_id", transfer.ID,
+				"status", event.ToStatus,
+				"error", err,
+			)
+		}
+	}
+	return result, nil
+}
+
+// upstreamRequestID returns NorthWind's request ID for the last response to a call made with ctx,
+// or nil when ctx records none or NorthWind sent no ID
+func upstreamRequestID(ctx context.Context) *string {
+	if meta := northwind.ResponseMetadataFrom(ctx); meta != nil && meta.RequestID != "" {
+		requestID := meta.RequestID
+		return &requestID
+	}
+	return nil
+}
+
+// regulatorNotifiable reports whether a transfer reaching status is reported to the regulator:
+// completions, failures and the reversal of a completed transfer. The notifications are
+// idempotent per status, so a reversal is reported even though the completion already was.
+func regulatorNotifiable(status string) bool {
+	switch status {
+	case models.NWTransferStatusCompleted, models.NWTransferStatusFailed, models.NWTransferStatusReversed:
+		return true
+	}
+	return false
+}
+
+// canLeaveStatus reports whether a transfer may move to newStatus from its current status.
+// Terminal statuses are final except that a completed transfer can still be reversed, and a
+// processing transfer never goes back to pending: that is a stale response arriving late.
+func canLeaveStatus(transfer *models.NorthwindTransfer, newStatus string) bool {
+	if transfer.Status == models.NWTransferStatusProcessing && newStatus == models.NWTransferStatusPending {
+		return false
+	}
+	if !transfer.IsTerminal() {
+		return true
+	}
+	return transfer.Status == models.NWTransferStatusCompleted && newStatus == models.NWTransferStatusReversed
+}
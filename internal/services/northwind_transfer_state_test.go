@@ -0,0 +1,269 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type stateTestEnv struct {
+	db             *gorm.DB
+	transferRepo   repositories.NorthwindTransferRepositoryInterface
+	regulatorSvc   *RegulatorService
+	regulatorCalls *atomic.Int32
+	states         *TransferStateManager
+}
+
+func newStateTestEnv(t *testing.T) *stateTestEnv {
+	t.Helper()
+	db := testfactory.NewDB(t)
+	// Concurrent callers must share the one in-memory database
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	var regulatorCalls atomic.Int32
+	regulatorServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		regulatorCalls.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(regulatorServer.Close)
+
+	transferRepo := repositories.NewNorthwindTransferRepository(db)
+	regulatorSvc := NewRegulatorService(regulatorServer.URL, 2, 60,
+		repositories.NewRegulatorNotificationRepository(db),
+		repositories.NewRegulatorNotificationAttemptRepository(db),
+		slog.Default(), regulatorServer.Client())
+	regulatorSvc.StartDeliveryWorkers(1, 10)
+
+	return &stateTestEnv{
+		db:             db,
+		transferRepo:   transferRepo,
+		regulatorSvc:   regulatorSvc,
+		regulatorCalls: &regulatorCalls,
+		states:         NewTransferStateManager(transferRepo, regulatorSvc, slog.Default()),
+	}
+}
+
+// assertOutcome drains regulator deliveries and checks the transfer's event and notification counts
+func (env *stateTestEnv) assertOutcome(t *testing.T, transferID uuid.UUID, wantEvents, wantNotifications int) {
+	t.Helper()
+	env.regulatorSvc.Shutdown(context.Background())
+
+	events, err := env.transferRepo.ListEvents(context.Background(), transferID)
+	if err != nil {
+		t.Fatalf("failed to list events: %v", err)
+	}
+	if len(events) != wantEvents {
+		t.Errorf("expected %d status events, got %d: %+v", wantEvents, len(events), events)
+	}
+	var notifications int64
+	if err := env.db.Model(&models.RegulatorNotification{}).Where("transfer_id = ?", transferID).Count(&notifications).Error; err != nil {
+		t.Fatalf("failed to count notifications: %v", err)
+	}
+	if notifications != int64(wantNotifications) {
+		t.Errorf("expected %d regulator notifications, got %d", wantNotifications, notifications)
+	}
+	if got := env.regulatorCalls.Load(); got != int32(wantNotifications) {
+		t.Errorf("expected %d regulator deliveries, got %d", wantNotifications, got)
+	}
+}
+
+func TestTransferStateManager_Apply_OneEventPerTransition(t *testing.T) {
+	env := newStateTestEnv(t)
+	transfer := testfactory.NWTransfer(t, env.db)
+	ctx := context.Background()
+
+	steps := []struct {
+		source      string
+		status      string
+		wantApplied bool
+	}{
+		{models.NWTransferEventSourcePoller, "PROCESSING", true},
+		{models.NWTransferEventSourceWebhook, "PROCESSING", false},
+		{models.NWTransferEventSourceWebhook, "COMPLETED", true},
+		{models.NWTransferEventSourcePoller, "COMPLETED", false},
+	}
+	for _, step := range steps {
+		result, err := env.states.Apply(ctx, transfer.ID, step.source, &northwind.TransferResponse{Status: step.status})
+		if err != nil {
+			t.Fatalf("%s %s: unexpected error: %v", step.source, step.status, err)
+		}
+		if result.Applied() != step.wantApplied {
+			t.Errorf("%s %s: expected applied=%v", step.source, step.status, step.wantApplied)
+		}
+		if result.Transfer.Status != step.status {
+			t.Errorf("%s %s: transfer status is %s", step.source, step.status, result.Transfer.Status)
+		}
+	}
+
+	env.assertOutcome(t, transfer.ID, 2, 1)
+	events, _ := env.transferRepo.ListEvents(ctx, transfer.ID)
+	if len(events) == 2 && (events[1].FromStatus != models.NWTransferStatusProcessing || events[1].Source != models.NWTransferEventSourceWebhook) {
+		t.Errorf("unexpected completion event: %+v", events[1])
+	}
+}
+
+func TestTransferStateManager_Apply_TerminalStatusIsFinal(t *testing.T) {
+	env := newStateTestEnv(t)
+	ctx := context.Background()
+	failed := testfactory.NWTransfer(t, env.db, testfactory.WithStatus(models.NWTransferStatusFailed))
+	completed := testfactory.NWTransfer(t, env.db, testfactory.WithStatus(models.NWTransferStatusCompleted))
+
+	// A late, out-of-order delivery must not reopen a finished transfer
+	result, err := env.states.Apply(ctx, failed.ID, models.NWTransferEventSourceWebhook, &northwind.TransferResponse{Status: "PROCESSING"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Applied() || result.Transfer.Status != models.NWTransferStatusFailed {
+		t.Errorf("expected FAILED transfer to stay FAILED, got %s", result.Transfer.Status)
+	}
+
+	result, err = env.states.Apply(ctx, completed.ID, models.NWTransferEventSourcePoller, &northwind.TransferResponse{Status: "REVERSED"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Applied() || result.Transfer.Status != models.NWTransferStatusReversed {
+		t.Errorf("expected completed transfer to be reversed, got %s", result.Transfer.Status)
+	}
+
+	// The reversal is reported; the FAILED transfer is not touched
+	env.assertOutcome(t, completed.ID, 1, 1)
+	if events, _ := env.transferRepo.ListEvents(ctx, failed.ID); len(events) != 0 {
+		t.Errorf("expected no events for the FAILED transfer, got %+v", events)
+	}
+}
+
+func TestTransferStateManager_Apply_ReversalIsLinkedToCompletion(t *testing.T) {
+	env := newStateTestEnv(t)
+	ctx := context.Background()
+	transfer := testfactory.NWTransfer(t, env.db, testfactory.WithStatus(models.NWTransferStatusProcessing))
+
+	for _, status := range []string{"COMPLETED", "REVERSED", "REVERSED"} {
+		if _, err := env.states.Apply(ctx, transfer.ID, models.NWTransferEventSourcePoller, &northwind.TransferResponse{Status: status}); err != nil {
+			t.Fatalf("%s: unexpected error: %v", status, err)
+		}
+	}
+	env.assertOutcome(t, transfer.ID, 2, 2)
+
+	notifRepo := repositories.NewRegulatorNotificationRepository(env.db)
+	completion, err := notifRepo.GetByTransferAndStatus(ctx, transfer.ID, models.NWTransferStatusCompleted)
+	if err != nil {
+		t.Fatalf("failed to load the completion notification: %v", err)
+	}
+	reversal, err := notifRepo.GetByTransferAndStatus(ctx, transfer.ID, models.NWTransferStatusReversed)
+	if err != nil {
+		t.Fatalf("failed to load the reversal notification: %v", err)
+	}
+	if reversal.RelatedNotificationID == nil || *reversal.RelatedNotificationID != completion.ID {
+		t.Errorf("expected the reversal linked to %s, got %v", completion.ID, reversal.RelatedNotificationID)
+	}
+	var payload models.RegulatorWebhookPayload
+	if err := json.Unmarshal(reversal.Payload, &payload); err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+	if payload.Status != models.NWTransferStatusReversed || payload.OriginalEventID == "" || payload.OriginalEventID != models.EventIDFromPayload(completion.Payload) {
+		t.Errorf("unexpected reversal payload: %+v", payload)
+	}
+}
+
+func TestTransferStateManager_Apply_InternalTestTransferIsNotReported(t *testing.T) {
+	env := newStateTestEnv(t)
+	ctx := context.Background()
+	internal := testfactory.NWTransfer(t, env.db, testfactory.WithStatus(models.NWTransferStatusProcessing), testfactory.WithInternalTest())
+	regular := testfactory.NWTransfer(t, env.db, testfactory.WithStatus(models.NWTransferStatusProcessing))
+
+	for _, transfer := range []*models.NorthwindTransfer{internal, regular} {
+		result, err := env.states.Apply(ctx, transfer.ID, models.NWTransferEventSourcePoller, &northwind.TransferResponse{Status: "COMPLETED"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Applied() {
+			t.Errorf("expected the completion applied to %s", transfer.ID)
+		}
+	}
+
+	env.assertOutcome(t, regular.ID, 1, 1)
+	var notifications int64
+	if err := env.db.Model(&models.RegulatorNotification{}).Where("transfer_id = ?", internal.ID).Count(&notifications).Error; err != nil {
+		t.Fatalf("failed to count notifications: %v", err)
+	}
+	if notifications != 0 {
+		t.Errorf("expected no regulator notification for the internal test transfer, got %d", notifications)
+	}
+}
+
+func TestTransferStateManager_ApplyRemote_UnknownTransfer(t *testing.T) {
+	env := newStateTestEnv(t)
+	defer env.regulatorSvc.Shutdown(context.Background())
+
+	_, err := env.states.ApplyRemote(context.Background(), models.NWTransferEventSourceWebhook, &northwind.TransferResponse{TransferID: "unknown", Status: "COMPLETED"})
+	if !errors.Is(err, ErrNWTransferNotFound) {
+		t.Errorf("expected ErrNWTransferNotFound, got %v", err)
+	}
+}
+
+func TestTransferStateManager_PollerAndWebhookApplyCompletionOnce(t *testing.T) {
+	env := newStateTestEnv(t)
+	transfer := testfactory.NWTransfer(t, env.db, testfactory.WithStatus(models.NWTransferStatusProcessing))
+
+	nwServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(northwind.TransferStatusResponse{TransferID: *transfer.ExternalRef, Status: "COMPLETED"})
+	}))
+	defer nwServer.Close()
+	poller := NewNorthwindPollingService(northwind.NewClient(nwServer.URL, "test-key"), env.transferRepo, env.regulatorSvc, 0, slog.Default())
+	poller.SetTransferStateManager(env.states)
+
+	const rounds = 5
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	errs := make(chan error, rounds)
+	for i := 0; i < rounds; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			<-start
+			poller.PollOnce(context.Background())
+		}()
+		go func() {
+			defer wg.Done()
+			<-start
+			_, err := env.states.ApplyRemote(context.Background(), models.NWTransferEventSourceWebhook,
+				&northwind.TransferResponse{TransferID: *transfer.ExternalRef, Status: "COMPLETED"})
+			errs <- err
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("webhook path: unexpected error: %v", err)
+		}
+	}
+
+	got, err := env.transferRepo.GetByID(context.Background(), transfer.ID)
+	if err != nil {
+		t.Fatalf("failed to reload transfer: %v", err)
+	}
+	if got.Status != models.NWTransferStatusCompleted {
+		t.Errorf("expected COMPLETED, got %s", got.Status)
+	}
+	env.assertOutcome(t, transfer.ID, 1, 1)
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+)
+
+const (
+	// durationStatsWindow is how far back completed transfers are sampled for duration stats
+	durationStatsWindow = 90 * 24 * time.Hour
+	// durationStatsCacheTTL is how long computed duration stats are served before recomputing
+	durationStatsCacheTTL = time.Hour
+)
+
+// TransferDurationEstimator reports the typical initiated-to-completed duration of a transfer type
+type TransferDurationEstimator interface {
+	ExpectedDuration(ctx context.Context, transferType string) (*models.TransferDurationStats, bool)
+}
+
+// DurationStatsReport is a cached snapshot of per-type transfer duration percentiles
+type DurationStatsReport struct {
+	WindowStart time.Time                      `json:"window_start"`
+	WindowEnd   time.Time                      `json:"window_end"`
+	GeneratedAt time.Time                      `json:"generated_at"`
+	Stats       []models.TransferDurationStats `json:"stats"`
+}
+
+// NorthwindTransferStatsService computes and caches time-to-terminal statistics for NorthWind transfers
+type NorthwindTransferStatsService struct {
+	transferRepo repositories.NorthwindTransferRepositoryInterface
+	now          func() time.Time
+	logger       *slog.Logger
+
+	mu     sync.Mutex
+	cached *DurationStatsReport
+}
+
+// NewNorthwindTransferStatsService creates a new transfer stats service. now is the clock used for
+// the sampling window and cache expiry; nil means time.Now.
+func NewNorthwindTransferStatsService(
+	transferRepo repositories.NorthwindTransferRepositoryInterface,
+	now func() time.Time,
+	logger *slog.Logger,
+) *NorthwindTransferStatsService {
+	if now == nil {
+		now = time.Now
+	}
+	return &NorthwindTransferStatsService{
+		transferRepo: transferRepo,
+		now:          now,
+		logger:       logger,
+	}
+}
+
+// DurationStats returns p50/p95 durations per transfer type over the last 90 days, recomputed at
+// most once per hour
+func (s *NorthwindTransferStatsService) DurationStats(ctx context.Context) (*DurationStatsReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now().UTC()
+	if s.cached != nil && now.Sub(s.cached.GeneratedAt) < durationStatsCacheTTL {
+		return s.cached, nil
+	}
+
+	from := now.Add(-durationStatsWindow)
+	stats, err := s.transferRepo.GetCompletionDurationStats(ctx, from, now)
+	if err != nil {
+		return nil, err
+	}
+	if stats == nil {
+		stats = []models.TransferDurationStats{}
+	}
+
+	s.cached = &DurationStatsReport{
+		WindowStart: from,
+		WindowEnd:   now,
+		GeneratedAt: now,
+		Stats:       stats,
+	}
+	return s.cached, nil
+}
+
+// ExpectedDuration returns the duration stats for transferType, or false if there is no sample
+// or the stats could not be computed
+func (s *NorthwindTransferStatsService) ExpectedDuration(ctx context.Context, transferType string) (*models.TransferDurationStats, bool) {
+	report, err := s.DurationStats(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to compute transfer duration stats", "error", err)
+		return nil, false
+	}
+	for i := range report.Stats {
+		if report.Stats[i].TransferType == transferType {
+			stats := report.Stats[i]
+			return &stats, true
+		}
+	}
+	return nil, false
+}
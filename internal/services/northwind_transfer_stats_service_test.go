@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories/repository_mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func TestNorthwindTransferStatsService_DurationStatsCachedHourly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	achStats := []models.TransferDurationStats{{TransferType: "ACH", SampleCount: 5, P50Seconds: 172800, P95Seconds: 259200}}
+	transferRepo := repository_mocks.NewMockNorthwindTransferRepositoryInterface(ctrl)
+	transferRepo.EXPECT().GetCompletionDurationStats(gomock.Any(), now.Add(-durationStatsWindow), now).Return(achStats, nil).Times(1)
+
+	svc := NewNorthwindTransferStatsService(transferRepo, clock, slog.Default())
+
+	report, err := svc.DurationStats(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Stats) != 1 || report.Stats[0].P50Seconds != 172800 {
+		t.Errorf("unexpected stats: %+v", report.Stats)
+	}
+	if !report.WindowStart.Equal(now.Add(-durationStatsWindow)) || !report.WindowEnd.Equal(now) {
+		t.Errorf("unexpected window %v - %v", report.WindowStart, report.WindowEnd)
+	}
+
+	// Within the hour: served from cache
+	now = now.Add(59 * time.Minute)
+	if _, err := svc.DurationStats(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// After the hour: recomputed over the shifted window
+	now = now.Add(2 * time.Minute)
+	transferRepo.EXPECT().GetCompletionDurationStats(gomock.Any(), now.Add(-durationStatsWindow), now).Return(nil, nil).Times(1)
+	report, err = svc.DurationStats(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Stats == nil || len(report.Stats) != 0 {
+		t.Errorf("expected empty (non-nil) stats after refresh, got %+v", report.Stats)
+	}
+}
+
+func TestNorthwindTransferStatsService_ExpectedDuration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	transferRepo := repository_mocks.NewMockNorthwindTransferRepositoryInterface(ctrl)
+	transferRepo.EXPECT().GetCompletionDurationStats(gomock.Any(), gomock.Any(), gomock.Any()).Return([]models.TransferDurationStats{
+		{TransferType: "ACH", SampleCount: 5, P50Seconds: 172800, P95Seconds: 259200},
+		{TransferType: "WIRE", SampleCount: 2, P50Seconds: 1200, P95Seconds: 1740},
+	}, nil)
+
+	svc := NewNorthwindTransferStatsService(transferRepo, nil, slog.Default())
+
+	wire, ok := svc.ExpectedDuration(context.Background(), "WIRE")
+	if !ok || wire.P50Seconds != 1200 {
+		t.Errorf("expected WIRE stats, got %+v, %v", wire, ok)
+	}
+	if _, ok := svc.ExpectedDuration(context.Background(), "RTP"); ok {
+		t.Errorf("expected no stats for a type without samples")
+	}
+}
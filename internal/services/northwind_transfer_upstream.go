@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// TransferSourceUpstream labels a transfer record read from NorthWind rather than our database
+const TransferSourceUpstream = "upstream"
+
+// UpstreamTransfer is NorthWind's record of a transfer mapped onto our model. Unless Adopted is
+// set, Transfer is not stored and its ID is not a local ID.
+type UpstreamTransfer struct {
+	Source   string                    `json:"source"`
+	Transfer *models.NorthwindTransfer `json:"transfer"`
+	// LocalID is the local transfer linked to the NorthWind transfer, when there is one
+	LocalID *uuid.UUID `json:"local_id,omitempty"`
+	// Adopted is set when this request created or linked the local transfer
+	Adopted bool `json:"adopted"`
+	// MatchedIntent is set when adoption linked a local transfer that had the same reference
+	// number but had never reached NorthWind, keeping its user
+	MatchedIntent bool `json:"matched_intent"`
+}
+
+// GetUpstreamTransfer reads a transfer straight from NorthWind, for transfers missing from our
+// database. A 404 from NorthWind is ErrNWTransferNotFound.
+func (s *NorthwindTransferService) GetUpstreamTransfer(ctx context.Context, northwindID uuid.UUID) (*UpstreamTransfer, error) {
+	remote, err := s.client.GetTransferStatus(ctx, northwindID.String())
+	if err != nil {
+		var apiErr *northwind.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return nil, ErrNWTransferNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch transfer from northwind: %w", err)
+	}
+
+	upstream := &UpstreamTransfer{Source: TransferSourceUpstream, Transfer: s.newUpstreamTransfer(remote)}
+	local, err := s.transferRepo.GetByNorthwindTransferID(ctx, northwindID)
+	switch {
+	case err == nil:
+		upstream.LocalID = &local.ID
+	case !errors.Is(err, repositories.ErrNorthwindTransferNotFound):
+		return nil, err
+	}
+	return upstream, nil
+}
+
+// AdoptUpstreamTransfer stores NorthWind's record of a transfer we have no local row for. When
+// exactly one local transfer has the same reference number and never reached NorthWind, that
+// transfer is linked and updated in place, so it keeps its user; otherwise a new transfer with no
+// user is created. A transfer that is already local is returned unchanged.
+func (s *NorthwindTransferService) AdoptUpstreamTransfer(ctx context.Context, northwindID uuid.UUID) (*UpstreamTransfer, error) {
+	upstream, err := s.GetUpstreamTransfer(ctx, northwindID)
+	if err != nil {
+		return nil, err
+	}
+	if upstream.LocalID != nil {
+		return upstream, nil
+	}
+
+	intents, err := s.transferRepo.GetUnlinkedByReference(ctx, upstream.Transfer.ReferenceNumber)
+	if err != nil {
+		return nil, err
+	}
+	if len(intents) > 1 {
+		s.logger.Warn("Several local transfers match the adopted reference number, adopting without a user",
+			"northwind_id", northwindID,
+			"reference_number", upstream.Transfer.ReferenceNumber,
+			"matches", len(intents),
+		)
+	}
+
+	if len(intents) == 1 {
+		linked, err := s.linkIntent(ctx, intents[0].ID, upstream.Transfer)
+		if err != nil {
+			return nil, err
+		}
+		if linked != nil {
+			upstream.Transfer = linked
+			upstream.MatchedIntent = true
+		}
+	}
+	if !upstream.MatchedIntent {
+		if err := s.transferRepo.Create(ctx, upstream.Transfer); err != nil {
+			return nil, fmt.Errorf("failed to store adopted transfer: %w", err)
+		}
+	}
+
+	upstream.LocalID = &upstream.Transfer.ID
+	upstream.Adopted = true
+	s.logger.Info("Adopted upstream NorthWind transfer",
+		"local_id", upstream.Transfer.ID,
+		"northwind_id", northwindID,
+		"matched_intent", upstream.MatchedIntent,
+	)
+	return upstream, nil
+}
+
+// linkIntent overwrites an unlinked local transfer with NorthWind's record of it, keeping its
+// identity, owner, origin and batch. It returns nil when the transfer was linked concurrently.
+func (s *NorthwindTransferService) linkIntent(ctx context.Context, intentID uuid.UUID, remote *models.NorthwindTransfer) (*models.NorthwindTransfer, error) {
+	linked := false
+	transfer, _, err := s.transferRepo.ApplyTransition(ctx, intentID, func(t *models.NorthwindTransfer) *models.NorthwindTransferEvent {
+		if t.ExternalRef != nil {
+			return nil
+		}
+		linked = true
+		fromStatus := t.Status
+
+		adopted := *remote
+		adopted.ID = t.ID
+		adopted.UserID = t.UserID
+		adopted.CreatedAt = t.CreatedAt
+		adopted.UpdatedAt = t.UpdatedAt
+		adopted.Version = t.Version
+		adopted.OriginIP = t.OriginIP
+		adopted.OriginUserAgent = t.OriginUserAgent
+		adopted.InitiationRequest = t.InitiationRequest
+		adopted.BatchName = t.BatchName
+		adopted.BatchIndex = t.BatchIndex
+		adopted.ConsentTimestamp = t.ConsentTimestamp
+		adopted.ConsentIPAddress = t.ConsentIPAddress
+		adopted.ConsentMethod = t.ConsentMethod
+		*t = adopted
+
+		// Recorded even when the status is unchanged: ApplyTransition only saves with an event
+		return &models.NorthwindTransferEvent{
+			FromStatus: fromStatus,
+			ToStatus:   t.Status,
+			Source:     models.NWTransferEventSourceAdoption,
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to link adopted transfer: %w", err)
+	}
+	if !linked {
+		return nil, nil
+	}
+	return transfer, nil
+}
+
+// newUpstreamTransfer maps NorthWind's record of a transfer onto an unstored local transfer with
+// no user
+func (s *NorthwindTransferService) newUpstreamTransfer(remote *northwind.TransferResponse) *models.NorthwindTransfer {
+	req := CreateTransferRequest{
+		Amount:             remote.Amount,
+		Currency:           remote.Currency,
+		Description:        remote.Description,
+		Direction:          remote.Direction,
+		TransferType:       remote.TransferType,
+		ReferenceNumber:    remote.ReferenceNumber,
+		SourceAccount:      fromNWAccountDetails(remote.SourceAccount),
+		DestinationAccount: fromNWAccountDetails(remote.DestinationAccount),
+	}
+	transfer := s.newLocalTransfer(uuid.Nil, req, remote)
+	transfer.UserID = nil
+	return transfer
+}
+
+func fromNWAccountDetails(d northwind.AccountDetails) CreateTransferAccountDetails {
+	return CreateTransferAccountDetails{
+		AccountHolderName: d.AccountHolderName,
+		AccountNumber:     d.AccountNumber,
+		RoutingNumber:     d.RoutingNumber,
+		InstitutionName:   d.InstitutionName,
+	}
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// newUpstreamTestService returns a transfer service whose NorthWind only knows the transfer
+// northwindID, reporting it COMPLETED with reference REF-UP-1
+func newUpstreamTestService(t *testing.T, northwindID uuid.UUID) (*NorthwindTransferService, repositories.NorthwindTransferRepositoryInterface, *gorm.DB) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/external/transfers/"+northwindID.String() {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(northwind.APIErrorResponse{Message: "transfer not found"})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(northwind.TransferResponse{
+			TransferID:         northwindID.String(),
+			Status:             "COMPLETED",
+			Amount:             250,
+			Currency:           "USD",
+			Direction:          models.NWTransferDirectionOutbound,
+			TransferType:       models.NWTransferTypeACH,
+			ReferenceNumber:    "REF-UP-1",
+			SourceAccount:      northwind.AccountDetails{AccountHolderName: "Jane Doe", AccountNumber: "1111111111"},
+			DestinationAccount: northwind.AccountDetails{AccountHolderName: "John Roe", AccountNumber: "2222222222"},
+			CompletedDate:      "2026-03-02T15:04:05Z",
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	db := testfactory.NewDB(t)
+	transferRepo := repositories.NewNorthwindTransferRepository(db)
+	svc := NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), transferRepo, nil, nil, slog.Default())
+	return svc, transferRepo, db
+}
+
+// unsentTransfer makes a factory transfer one that never reached NorthWind
+func unsentTransfer(reference string) testfactory.TransferOption {
+	return func(tr *models.NorthwindTransfer) {
+		tr.ReferenceNumber = reference
+		tr.ExternalRef = nil
+		tr.Status = models.NWTransferStatusInitiationPending
+		tr.NextPollAt = nil
+	}
+}
+
+func TestNorthwindTransferService_GetUpstreamTransfer(t *testing.T) {
+	northwindID := uuid.New()
+	svc, transferRepo, _ := newUpstreamTestService(t, northwindID)
+	ctx := context.Background()
+
+	upstream, err := svc.GetUpstreamTransfer(ctx, northwindID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if upstream.Source != TransferSourceUpstream || upstream.Adopted || upstream.LocalID != nil {
+		t.Errorf("expected an unadopted upstream record, got %+v", upstream)
+	}
+	if upstream.Transfer.Status != models.NWTransferStatusCompleted || upstream.Transfer.ReferenceNumber != "REF-UP-1" || upstream.Transfer.UserID != nil {
+		t.Errorf("expected the mapped COMPLETED REF-UP-1 transfer with no user, got %+v", upstream.Transfer)
+	}
+	if _, err := transferRepo.GetByNorthwindTransferID(ctx, northwindID); !errors.Is(err, repositories.ErrNorthwindTransferNotFound) {
+		t.Errorf("expected viewing not to store the transfer, got %v", err)
+	}
+
+	if _, err := svc.GetUpstreamTransfer(ctx, uuid.New()); !errors.Is(err, ErrNWTransferNotFound) {
+		t.Errorf("expected ErrNWTransferNotFound for a transfer NorthWind does not know, got %v", err)
+	}
+}
+
+func TestNorthwindTransferService_AdoptUpstreamTransfer(t *testing.T) {
+	northwindID := uuid.New()
+	svc, transferRepo, _ := newUpstreamTestService(t, northwindID)
+	ctx := context.Background()
+
+	upstream, err := svc.AdoptUpstreamTransfer(ctx, northwindID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !upstream.Adopted || upstream.MatchedIntent || upstream.LocalID == nil {
+		t.Fatalf("expected a new unmatched local transfer, got %+v", upstream)
+	}
+
+	stored, err := transferRepo.GetByNorthwindTransferID(ctx, northwindID)
+	if err != nil {
+		t.Fatalf("expected the adopted transfer to be stored: %v", err)
+	}
+	if stored.ID != *upstream.LocalID || stored.UserID != nil || stored.Status != models.NWTransferStatusCompleted {
+		t.Errorf("expected the stored COMPLETED transfer with no user, got %+v", stored)
+	}
+
+	// Adopting again returns the existing local transfer
+	again, err := svc.AdoptUpstreamTransfer(ctx, northwindID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again.Adopted || again.LocalID == nil || *again.LocalID != stored.ID {
+		t.Errorf("expected the already adopted transfer to be reported, got %+v", again)
+	}
+}
+
+func TestNorthwindTransferService_AdoptUpstreamTransfer_MatchesIntent(t *testing.T) {
+	northwindID := uuid.New()
+	svc, transferRepo, db := newUpstreamTestService(t, northwindID)
+	ctx := context.Background()
+	userID := uuid.New()
+	intent := testfactory.NWTransfer(t, db, testfactory.WithUser(userID), unsentTransfer("REF-UP-1"))
+
+	upstream, err := svc.AdoptUpstreamTransfer(ctx, northwindID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !upstream.Adopted || !upstream.MatchedIntent || upstream.LocalID == nil || *upstream.LocalID != intent.ID {
+		t.Fatalf("expected the intent to be adopted in place, got %+v", upstream)
+	}
+
+	stored, err := transferRepo.GetByID(ctx, intent.ID)
+	if err != nil {
+		t.Fatalf("failed to reload transfer: %v", err)
+	}
+	if stored.UserID == nil || *stored.UserID != userID {
+		t.Errorf("expected the adopted transfer to keep user %s, got %v", userID, stored.UserID)
+	}
+	if stored.ExternalRef == nil || *stored.ExternalRef != northwindID.String() || stored.Status != models.NWTransferStatusCompleted {
+		t.Errorf("expected the intent linked to %s and COMPLETED, got ref %v status %s", northwindID, stored.ExternalRef, stored.Status)
+	}
+	events, _ := transferRepo.ListEvents(ctx, intent.ID)
+	if len(events) != 1 || events[0].Source != models.NWTransferEventSourceAdoption || events[0].FromStatus != models.NWTransferStatusInitiationPending {
+		t.Errorf("expected one adoption event, got %+v", events)
+	}
+}
+
+func TestNorthwindTransferService_AdoptUpstreamTransfer_AmbiguousIntent(t *testing.T) {
+	northwindID := uuid.New()
+	svc, _, db := newUpstreamTestService(t, northwindID)
+	testfactory.NWTransfer(t, db, unsentTransfer("REF-UP-1"))
+	testfactory.NWTransfer(t, db, unsentTransfer("REF-UP-1"))
+
+	upstream, err := svc.AdoptUpstreamTransfer(context.Background(), northwindID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !upstream.Adopted || upstream.MatchedIntent || upstream.Transfer.UserID != nil {
+		t.Errorf("expected two matching intents to adopt without a user, got %+v", upstream)
+	}
+}
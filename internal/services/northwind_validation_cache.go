@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultAccountValidationCacheTTL is how long a successful NorthWind account validation is
+// reused for the same account and routing number
+const DefaultAccountValidationCacheTTL = 10 * time.Minute
+
+type accountValidationKey struct {
+	accountNumber string
+	routingNumber string
+}
+
+func (k accountValidationKey) String() string {
+	return k.routingNumber + "/" + k.accountNumber
+}
+
+type accountValidationEntry struct {
+	resp      northwind.AccountValidationResponse
+	expiresAt time.Time
+}
+
+// accountValidationCache remembers successful account validations for a short TTL and collapses
+// concurrent validations of the same account into one NorthWind call. Failed or invalid results
+// are never cached, so a failure is always re-checked.
+type accountValidationCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[accountValidationKey]accountValidationEntry
+	calls   singleflight.Group
+}
+
+func newAccountValidationCache(ttl time.Duration) *accountValidationCache {
+	return &accountValidationCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[accountValidationKey]accountValidationEntry),
+	}
+}
+
+// get returns a copy of the cached result for key, if there is one that has not expired
+func (c *accountValidationCache) get(key accountValidationKey) (*northwind.AccountValidationResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	resp := entry.resp
+	return &resp, true
+}
+
+// put caches a valid result and drops any entries that have expired
+func (c *accountValidationCache) put(key accountValidationKey, resp *northwind.AccountValidationResponse) {
+	if c.ttl <= 0 || resp == nil || !resp.Valid {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	for k, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = accountValidationEntry{resp: *resp, expiresAt: now.Add(c.ttl)}
+}
+
+// SetValidationCacheTTL sets how long a successful account validation is reused for the same
+// account and routing number. Zero or negative disables the cache; concurrent validations of the
+// same account are still collapsed into one call.
+func (s *NorthwindAccountService) SetValidationCacheTTL(ttl time.Duration) {
+	s.validations = newAccountValidationCache(ttl)
+}
+
+// validateAccount validates an account with NorthWind, serving a recent successful result from
+// the cache and sharing one in-flight call between concurrent callers
+func (s *NorthwindAccountService) validateAccount(ctx context.Context, accountNumber, routingNumber string) (*northwind.AccountValidationResponse, error) {
+	key := accountValidationKey{accountNumber: accountNumber, routingNumber: routingNumber}
+	if resp, ok := s.validations.get(key); ok {
+		s.logger.Info("NorthWind account validation served from cache",
+			"account_number", maskAccountNumber(accountNumber),
+			"routing_number", routingNumber,
+		)
+		return resp, nil
+	}
+
+	// The shared call must not fail for every caller when the one that started it gives up
+	ch := s.validations.calls.DoChan(key.String(), func() (interface{}, error) {
+		if resp, ok := s.validations.get(key); ok {
+			return resp, nil
+		}
+		resp, err := s.client.ValidateAccount(context.WithoutCancel(ctx), northwind.AccountValidationRequest{
+			AccountNumber: accountNumber,
+			RoutingNumber: routingNumber,
+		})
+		if err != nil {
+			return nil, err
+		}
+		s.validations.put(key, resp)
+		return resp, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-ch:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		if result.Shared {
+			s.logger.Info("NorthWind account validation shared with a concurrent request",
+				"account_number", maskAccountNumber(accountNumber),
+				"routing_number", routingNumber,
+			)
+		}
+		resp := *result.Val.(*northwind.AccountValidationResponse)
+		return &resp, nil
+	}
+}
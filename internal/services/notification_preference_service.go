@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// Transfer event types users can be notified about, one per status a transfer can move to
+const (
+	NotificationEventTransferProcessing = "TRANSFER_PROCESSING"
+	NotificationEventTransferCompleted  = "TRANSFER_COMPLETED"
+	NotificationEventTransferFailed     = "TRANSFER_FAILED"
+	NotificationEventTransferCancelled  = "TRANSFER_CANCELLED"
+	NotificationEventTransferReversed   = "TRANSFER_REVERSED"
+)
+
+// Sources of a user's channels for an event type
+const (
+	NotificationPreferenceSourceDefault = "default"
+	NotificationPreferenceSourceUser    = "user"
+)
+
+var ErrUnknownNotificationEvent = errors.New("unknown notification event type")
+
+// NotificationChannels says which channels an event is delivered on
+type NotificationChannels struct {
+	InApp bool `json:"in_app"`
+	Email bool `json:"email"`
+}
+
+// unknownEventChannels apply to event types without a definition: shown in-app, never emailed
+var unknownEventChannels = NotificationChannels{InApp: true}
+
+// NotificationEventDefinition declares an event type users can be notified about and the channels
+// used until a user changes them
+type NotificationEventDefinition struct {
+	EventType   string
+	Description string
+	Defaults    NotificationChannels
+}
+
+var notificationEventDefinitions = []NotificationEventDefinition{
+	{EventType: NotificationEventTransferProcessing, Description: "A transfer is being processed by NorthWind", Defaults: NotificationChannels{InApp: true}},
+	{EventType: NotificationEventTransferCompleted, Description: "A transfer completed", Defaults: NotificationChannels{InApp: true, Email: true}},
+	{EventType: NotificationEventTransferFailed, Description: "A transfer failed", Defaults: NotificationChannels{InApp: true, Email: true}},
+	{EventType: NotificationEventTransferCancelled, Description: "A transfer was cancelled", Defaults: NotificationChannels{InApp: true}},
+	{EventType: NotificationEventTransferReversed, Description: "A completed transfer was reversed", Defaults: NotificationChannels{InApp: true, Email: true}},
+}
+
+// TransferNotificationEvent returns the event type for a transfer moving to status
+func TransferNotificationEvent(status string) string {
+	return "TRANSFER_" + status
+}
+
+// NotificationPreferenceStatus is a user's effective channels for one event type
+type NotificationPreferenceStatus struct {
+	EventType   string `json:"event_type"`
+	Description string `json:"description"`
+	NotificationChannels
+	Source string `json:"source"`
+}
+
+// NotificationPreferenceUpdate changes a user's channels for one event type; a channel left nil
+// keeps its current setting
+type NotificationPreferenceUpdate struct {
+	EventType string `json:"event_type" validate:"required"`
+	InApp     *bool  `json:"in_app"`
+	Email     *bool  `json:"email"`
+}
+
+// NotificationPreferenceService resolves which channels a user is notified on for each event
+// type: the user's stored preference when there is one, otherwise the event type's default.
+type NotificationPreferenceService struct {
+	repo        repositories.NotificationPreferenceRepositoryInterface
+	definitions map[string]NotificationEventDefinition
+}
+
+// NewNotificationPreferenceService creates a new notification preference service
+func NewNotificationPreferenceService(repo repositories.NotificationPreferenceRepositoryInterface) *NotificationPreferenceService {
+	definitions := make(map[string]NotificationEventDefinition, len(notificationEventDefinitions))
+	for _, def := range notificationEventDefinitions {
+		definitions[def.EventType] = def
+	}
+	return &NotificationPreferenceService{repo: repo, definitions: definitions}
+}
+
+// Channels returns the channels the user is notified on for eventType
+func (s *NotificationPreferenceService) Channels(ctx context.Context, userID uuid.UUID, eventType string) (NotificationChannels, error) {
+	preference, err := s.repo.Get(ctx, userID, eventType)
+	switch {
+	case err == nil:
+		return NotificationChannels{InApp: preference.InApp, Email: preference.Email}, nil
+	case errors.Is(err, repositories.ErrNotificationPreferenceNotFound):
+		return s.defaults(eventType), nil
+	default:
+		return NotificationChannels{}, err
+	}
+}
+
+// GetPreferences returns the user's effective channels for every defined event type
+func (s *NotificationPreferenceService) GetPreferences(ctx context.Context, userID uuid.UUID) ([]NotificationPreferenceStatus, error) {
+	stored, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	byEvent := make(map[string]models.NotificationPreference, len(stored))
+	for _, preference := range stored {
+		byEvent[preference.EventType] = preference
+	}
+
+	statuses := make([]NotificationPreferenceStatus, 0, len(notificationEventDefinitions))
+	for _, def := range notificationEventDefinitions {
+		status := NotificationPreferenceStatus{
+			EventType:            def.EventType,
+			Description:          def.Description,
+			NotificationChannels: def.Defaults,
+			Source:               NotificationPreferenceSourceDefault,
+		}
+		if preference, ok := byEvent[def.EventType]; ok {
+			status.NotificationChannels = NotificationChannels{InApp: preference.InApp, Email: preference.Email}
+			status.Source = NotificationPreferenceSourceUser
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// UpdatePreferences applies the updates to the user's preferences and returns the result. Every
+// event type is checked before anything is stored.
+func (s *NotificationPreferenceService) UpdatePreferences(ctx context.Context, userID uuid.UUID, updates []NotificationPreferenceUpdate) ([]NotificationPreferenceStatus, error) {
+	for _, update := range updates {
+		if _, ok := s.definitions[update.EventType]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownNotificationEvent, update.EventType)
+		}
+	}
+
+	for _, update := range updates {
+		channels, err := s.Channels(ctx, userID, update.EventType)
+		if err != nil {
+			return nil, err
+		}
+		if update.InApp != nil {
+			channels.InApp = *update.InApp
+		}
+		if update.Email != nil {
+			channels.Email = *update.Email
+		}
+		if err := s.repo.Upsert(ctx, &models.NotificationPreference{
+			UserID:    userID,
+			EventType: update.EventType,
+			InApp:     channels.InApp,
+			Email:     channels.Email,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return s.GetPreferences(ctx, userID)
+}
+
+// defaults returns the channels used for eventType when the user has not chosen any
+func (s *NotificationPreferenceService) defaults(eventType string) NotificationChannels {
+	if def, ok := s.definitions[eventType]; ok {
+		return def.Defaults
+	}
+	return unknownEventChannels
+}
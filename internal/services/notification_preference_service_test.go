@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func boolPtr(b bool) *bool { return &b }
+
+func newPreferenceTestService(t *testing.T) (*NotificationPreferenceService, *gorm.DB) {
+	t.Helper()
+	db := testfactory.NewDB(t)
+	return NewNotificationPreferenceService(repositories.NewNotificationPreferenceRepository(db)), db
+}
+
+func TestNotificationPreferenceService_Defaults(t *testing.T) {
+	svc, _ := newPreferenceTestService(t)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	statuses, err := svc.GetPreferences(ctx, userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(statuses) != len(notificationEventDefinitions) {
+		t.Fatalf("expected every event type, got %d", len(statuses))
+	}
+	for _, status := range statuses {
+		if status.Source != NotificationPreferenceSourceDefault {
+			t.Errorf("%s: expected the default, got source %s", status.EventType, status.Source)
+		}
+	}
+
+	tests := []struct {
+		eventType string
+		want      NotificationChannels
+	}{
+		{NotificationEventTransferFailed, NotificationChannels{InApp: true, Email: true}},
+		{NotificationEventTransferProcessing, NotificationChannels{InApp: true}},
+		{"TRANSFER_SOMETHING_NEW", NotificationChannels{InApp: true}},
+	}
+	for _, tt := range tests {
+		channels, err := svc.Channels(ctx, userID, tt.eventType)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.eventType, err)
+		}
+		if channels != tt.want {
+			t.Errorf("%s: expected %+v, got %+v", tt.eventType, tt.want, channels)
+		}
+	}
+}
+
+func TestNotificationPreferenceService_UpdatePreferences(t *testing.T) {
+	svc, _ := newPreferenceTestService(t)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	// Email only for failures: the completed email is switched off, in-app keeps its default
+	statuses, err := svc.UpdatePreferences(ctx, userID, []NotificationPreferenceUpdate{
+		{EventType: NotificationEventTransferCompleted, Email: boolPtr(false)},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, status := range statuses {
+		if status.EventType == NotificationEventTransferCompleted &&
+			(status.Source != NotificationPreferenceSourceUser || !status.InApp || status.Email) {
+			t.Errorf("expected the user's in-app only preference, got %+v", status)
+		}
+	}
+
+	channels, _ := svc.Channels(ctx, userID, NotificationEventTransferCompleted)
+	if channels != (NotificationChannels{InApp: true}) {
+		t.Errorf("expected in-app only for completions, got %+v", channels)
+	}
+	if channels, _ := svc.Channels(ctx, userID, NotificationEventTransferFailed); !channels.Email {
+		t.Errorf("expected failures to keep their email default, got %+v", channels)
+	}
+	if channels, _ := svc.Channels(ctx, uuid.New(), NotificationEventTransferCompleted); !channels.Email {
+		t.Errorf("expected another user's completions to keep the default, got %+v", channels)
+	}
+
+	// A second update replaces the stored preference rather than adding one
+	if _, err := svc.UpdatePreferences(ctx, userID, []NotificationPreferenceUpdate{
+		{EventType: NotificationEventTransferCompleted, InApp: boolPtr(false)},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if channels, _ := svc.Channels(ctx, userID, NotificationEventTransferCompleted); channels != (NotificationChannels{}) {
+		t.Errorf("expected completions to be silenced, got %+v", channels)
+	}
+}
+
+func TestNotificationPreferenceService_UpdatePreferences_UnknownEvent(t *testing.T) {
+	svc, _ := newPreferenceTestService(t)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	_, err := svc.UpdatePreferences(ctx, userID, []NotificationPreferenceUpdate{
+		{EventType: NotificationEventTransferFailed, Email: boolPtr(false)},
+		{EventType: "NOT_AN_EVENT", Email: boolPtr(true)},
+	})
+	if !errors.Is(err, ErrUnknownNotificationEvent) {
+		t.Fatalf("expected ErrUnknownNotificationEvent, got %v", err)
+	}
+	if channels, _ := svc.Channels(ctx, userID, NotificationEventTransferFailed); !channels.Email {
+		t.Errorf("expected nothing to be stored when any event type is unknown, got %+v", channels)
+	}
+}
+
+func TestTransferStateManager_Apply_NotifiesOnPreferredChannels(t *testing.T) {
+	env := newStateTestEnv(t)
+	ctx := context.Background()
+
+	user := &models.User{Email: "owner@example.com", FirstName: "Jane", LastName: "Doe", PasswordHash: "x", Role: models.RoleCustomer}
+	if err := env.db.Create(user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	preferences := NewNotificationPreferenceService(repositories.NewNotificationPreferenceRepository(env.db))
+	notifications := repositories.NewUserNotificationRepository(env.db)
+	env.states.SetNotifier(NewTransferNotifier(preferences, notifications, repositories.NewUserRepository(env.db), slog.Default()))
+
+	// Processing: in-app only by default. Completed: email suppressed by the user.
+	if _, err := preferences.UpdatePreferences(ctx, user.ID, []NotificationPreferenceUpdate{
+		{EventType: NotificationEventTransferCompleted, Email: boolPtr(false)},
+		{EventType: NotificationEventTransferFailed, InApp: boolPtr(false)},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	owned := testfactory.NWTransfer(t, env.db, testfactory.WithUser(user.ID))
+	for _, status := range []string{"PROCESSING", "COMPLETED"} {
+		if _, err := env.states.Apply(ctx, owned.ID, models.NWTransferEventSourcePoller, &northwind.TransferResponse{Status: status}); err != nil {
+			t.Fatalf("%s: unexpected error: %v", status, err)
+		}
+	}
+	failed := testfactory.NWTransfer(t, env.db, testfactory.WithUser(user.ID))
+	if _, err := env.states.Apply(ctx, failed.ID, models.NWTransferEventSourcePoller, &northwind.TransferResponse{Status: "FAILED"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	inApp, _ := notifications.ListByUser(ctx, user.ID, models.NotificationChannelInApp)
+	if len(inApp) != 2 {
+		t.Fatalf("expected in-app notifications for processing and completion only, got %+v", inApp)
+	}
+	for _, n := range inApp {
+		if n.EventType == NotificationEventTransferFailed {
+			t.Errorf("expected the suppressed in-app failure notification to be skipped")
+		}
+	}
+	emails, _ := notifications.ListByUser(ctx, user.ID, models.NotificationChannelEmail)
+	if len(emails) != 1 || emails[0].EventType != NotificationEventTransferFailed || emails[0].Recipient != user.Email || emails[0].SentAt != nil {
+		t.Errorf("expected one queued failure email to %s, got %+v", user.Email, emails)
+	}
+}
+
+func TestTransferNotifier_SkipsTransfersWithoutUser(t *testing.T) {
+	db := testfactory.NewDB(t)
+	notifications := repositories.NewUserNotificationRepository(db)
+	notifier := NewTransferNotifier(NewNotificationPreferenceService(repositories.NewNotificationPreferenceRepository(db)),
+		notifications, repositories.NewUserRepository(db), slog.Default())
+
+	transfer := testfactory.NewNWTransfer()
+	transfer.UserID = nil
+	event := &models.NorthwindTransferEvent{FromStatus: models.NWTransferStatusPending, ToStatus: models.NWTransferStatusFailed}
+	if err := notifier.NotifyTransferEvent(context.Background(), transfer, event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var count int64
+	db.Model(&models.UserNotification{}).Count(&count)
+	if count != 0 {
+		t.Errorf("expected no notifications, got %d", count)
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"crypto/rand"
 	"errors"
 	"fmt"
@@ -274,7 +275,7 @@ func (ps *PasswordService) calculateEntropyBonus(password string) int {
 
 // AdminResetPassword resets a customer's password (admin operation)
 // Returns the temporary password that should be sent to the customer
-func (ps *PasswordService) AdminResetPassword(customerID, adminID uuid.UUID) (string, error) {
+func (ps *PasswordService) AdminResetPassword(ctx context.Context, customerID, adminID uuid.UUID) (string, error) {
 	if ps.userRepo == nil {
 		return "", errors.New("user repository not configured for customer operations")
 	}
@@ -287,7 +288,7 @@ func (ps *PasswordService) AdminResetPassword(customerID, adminID uuid.UUID) (st
 		return "", ErrInvalidAdminID
 	}
 
-	user, err := ps.userRepo.GetByIDActive(customerID)
+	user, err := ps.userRepo.GetByIDActive(ctx, customerID)
 	if err != nil {
 		if errors.Is(err, repositories.ErrUserNotFound) {
 			return "", ErrCustomerNotFound
@@ -305,7 +306,7 @@ func (ps *PasswordService) AdminResetPassword(customerID, adminID uuid.UUID) (st
 		return "", fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	if err := ps.userRepo.UpdatePasswordHash(user.ID, hashedPassword); err != nil {
+	if err := ps.userRepo.UpdatePasswordHash(ctx, user.ID, hashedPassword); err != nil {
 		return "", fmt.Errorf("failed to update password: %w", err)
 	}
 
@@ -313,7 +314,7 @@ func (ps *PasswordService) AdminResetPassword(customerID, adminID uuid.UUID) (st
 }
 
 // CustomerUpdatePassword allows a customer to update their own password
-func (ps *PasswordService) CustomerUpdatePassword(customerID uuid.UUID, currentPassword, newPassword string) error {
+func (ps *PasswordService) CustomerUpdatePassword(ctx context.Context, customerID uuid.UUID, currentPassword, newPassword string) error {
 	if ps.userRepo == nil {
 		return errors.New("user repository not configured for customer operations")
 	}
@@ -338,7 +339,7 @@ func (ps *PasswordService) CustomerUpdatePassword(customerID uuid.UUID, currentP
 		return err
 	}
 
-	user, err := ps.userRepo.GetByIDActive(customerID)
+	user, err := ps.userRepo.GetByIDActive(ctx, customerID)
 	if err != nil {
 		if errors.Is(err, repositories.ErrUserNotFound) {
 			return ErrCustomerNotFound
@@ -355,7 +356,7 @@ func (ps *PasswordService) CustomerUpdatePassword(customerID uuid.UUID, currentP
 		return fmt.Errorf("failed to hash new password: %w", err)
 	}
 
-	if err := ps.userRepo.UpdatePasswordHash(user.ID, hashedPassword); err != nil {
+	if err := ps.userRepo.UpdatePasswordHash(ctx, user.ID, hashedPassword); err != nil {
 		return fmt.Errorf("failed to update password: %w", err)
 	}
 
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/google/uuid"
+)
+
+const (
+	// personalAccessTokenPrefix marks a secret as a personal access token, so leaked secrets are
+	// easy to recognise
+	personalAccessTokenPrefix = "pat_"
+
+	// DefaultPersonalAccessTokenDays is the lifetime of a token created without expires_in_days
+	DefaultPersonalAccessTokenDays = 90
+
+	// personalAccessTokenTouchInterval is the least time between two last_used_at writes for a
+	// token, so a busy integration does not write on every request
+	personalAccessTokenTouchInterval = time.Minute
+	personalAccessTokenTouchTimeout  = 5 * time.Second
+)
+
+var (
+	ErrPersonalAccessTokenNotFound = errors.New("personal access token not found")
+	ErrPersonalAccessTokenInvalid  = errors.New("personal access token is invalid")
+	ErrPersonalAccessTokenExpired  = errors.New("personal access token has expired")
+	ErrPersonalAccessTokenRevoked  = errors.New("personal access token has been revoked")
+)
+
+// CreatePersonalAccessTokenRequest creates a token for the caller. A zero ExpiresInDays uses
+// DefaultPersonalAccessTokenDays.
+type CreatePersonalAccessTokenRequest struct {
+	Name          string   `json:"name" validate:"required,max=100"`
+	Scopes        []string `json:"scopes" validate:"required,min=1,dive,oneof=transfers:read transfers:write"`
+	ExpiresInDays int      `json:"expires_in_days" validate:"omitempty,min=1,max=365"`
+}
+
+// CreatedPersonalAccessToken is a new token along with its secret. The secret is only ever
+// returned here; it cannot be read back later.
+type CreatedPersonalAccessToken struct {
+	models.PersonalAccessToken
+	Token string `json:"token"`
+}
+
+// PersonalAccessTokenService manages users' personal access tokens and authenticates requests
+// made with them. Revoked and expired tokens, and tokens of deleted or locked users, are rejected.
+type PersonalAccessTokenService struct {
+	repo     repositories.PersonalAccessTokenRepositoryInterface
+	userRepo repositories.UserRepositoryInterface
+	logger   *slog.Logger
+	now      func() time.Time
+}
+
+// NewPersonalAccessTokenService creates a new personal access token service
+func NewPersonalAccessTokenService(repo repositories.PersonalAccessTokenRepositoryInterface, userRepo repositories.UserRepositoryInterface, logger *slog.Logger) *PersonalAccessTokenService {
+	return &PersonalAccessTokenService{
+		repo:     repo,
+		userRepo: userRepo,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// CreateToken creates a token for the user and returns it with its secret
+func (s *PersonalAccessTokenService) CreateToken(ctx context.Context, userID uuid.UUID, req CreatePersonalAccessTokenRequest) (*CreatedPersonalAccessToken, error) {
+	days := req.ExpiresInDays
+	if days == 0 {
+		days = DefaultPersonalAccessTokenDays
+	}
+
+	secret := newPersonalAccessTokenSecret()
+	token := models.PersonalAccessToken{
+		UserID:    userID,
+		Name:      req.Name,
+		TokenHash: hashToken(secret),
+		Scopes:    uniqueScopes(req.Scopes),
+		ExpiresAt: s.now().AddDate(0, 0, days),
+	}
+	if err := s.repo.Create(ctx, &token); err != nil {
+		return nil, err
+	}
+	return &CreatedPersonalAccessToken{PersonalAccessToken: token, Token: secret}, nil
+}
+
+// ListTokens returns a page of the user's tokens, revoked and expired included, and the total
+// number of them
+func (s *PersonalAccessTokenService) ListTokens(ctx context.Context, userID uuid.UUID, offset, limit int) ([]models.PersonalAccessToken, int64, error) {
+	return s.repo.ListByUser(ctx, userID, offset, limit)
+}
+
+// RevokeToken revokes one of the user's tokens. Tokens of other users are reported as not found.
+func (s *PersonalAccessTokenService) RevokeToken(ctx context.Context, userID, tokenID uuid.UUID) error {
+	token, err := s.repo.GetByID(ctx, tokenID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrPersonalAccessTokenNotFound) {
+			return ErrPersonalAccessTokenNotFound
+		}
+		return err
+	}
+	if token.UserID != userID {
+		return ErrPersonalAccessTokenNotFound
+	}
+	return s.repo.Revoke(ctx, token.ID, s.now())
+}
+
+// Authenticate resolves a token secret to its token and user. It records the use in the
+// background, so a slow write never holds up the request.
+func (s *PersonalAccessTokenService) Authenticate(ctx context.Context, secret string) (*models.PersonalAccessToken, *models.User, error) {
+	token, err := s.repo.GetByTokenHash(ctx, hashToken(secret))
+	if err != nil {
+		if errors.Is(err, repositories.ErrPersonalAccessTokenNotFound) {
+			return nil, nil, ErrPersonalAccessTokenInvalid
+		}
+		return nil, nil, err
+	}
+
+	now := s.now()
+	if token.IsRevoked() {
+		return nil, nil, ErrPersonalAccessTokenRevoked
+	}
+	if token.IsExpiredAt(now) {
+		return nil, nil, ErrPersonalAccessTokenExpired
+	}
+
+	user, err := s.userRepo.GetByIDActive(ctx, token.UserID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return nil, nil, ErrPersonalAccessTokenInvalid
+		}
+		return nil, nil, err
+	}
+	if user.IsLocked() {
+		return nil, nil, ErrAccountLocked
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= personalAccessTokenTouchInterval {
+		go s.touch(token.ID, now)
+	}
+	return token, user, nil
+}
+
+func (s *PersonalAccessTokenService) touch(tokenID uuid.UUID, usedAt time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), personalAccessTokenTouchTimeout)
+	defer cancel()
+	if err := s.repo.TouchLastUsed(ctx, tokenID, usedAt); err != nil {
+		s.logger.Warn("Failed to record personal access token use", "token_id", tokenID, "error", err)
+	}
+}
+
+func newPersonalAccessTokenSecret() string {
+	buf := make([]byte, 32)
+	_, _ = rand.Read(buf) // never returns an error; crashes the program instead
+	return personalAccessTokenPrefix + base64.RawURLEncoding.EncodeToString(buf)
+}
+
+func uniqueScopes(scopes []string) models.TokenScopes {
+	unique := models.TokenScopes{}
+	for _, scope := range scopes {
+		if !unique.Has(scope) {
+			unique = append(unique, scope)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/database"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAccessTokenTestService(t *testing.T) (*PersonalAccessTokenService, *database.DB, *models.User) {
+	t.Helper()
+	db := database.SetupTestDB(t)
+	require.NoError(t, db.DB.AutoMigrate(&models.PersonalAccessToken{}))
+	user := database.CreateTestUser(t, db, "integrations@example.com")
+	svc := NewPersonalAccessTokenService(repositories.NewPersonalAccessTokenRepository(db.DB), repositories.NewUserRepository(db.DB), slog.Default())
+	return svc, db, user
+}
+
+func createAccessToken(t *testing.T, svc *PersonalAccessTokenService, userID uuid.UUID, scopes ...string) *CreatedPersonalAccessToken {
+	t.Helper()
+	created, err := svc.CreateToken(context.Background(), userID, CreatePersonalAccessTokenRequest{Name: "erp", Scopes: scopes})
+	require.NoError(t, err)
+	return created
+}
+
+func TestPersonalAccessTokenService_StoresOnlyTheHash(t *testing.T) {
+	svc, db, user := newAccessTokenTestService(t)
+	created := createAccessToken(t, svc, user.ID, models.TokenScopeTransfersRead, models.TokenScopeTransfersRead)
+
+	assert.True(t, strings.HasPrefix(created.Token, personalAccessTokenPrefix))
+	assert.Equal(t, models.TokenScopes{models.TokenScopeTransfersRead}, created.Scopes)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, DefaultPersonalAccessTokenDays), created.ExpiresAt, time.Minute)
+
+	var stored models.PersonalAccessToken
+	require.NoError(t, db.DB.First(&stored, "id = ?", created.ID).Error)
+	assert.Equal(t, hashToken(created.Token), stored.TokenHash)
+	var matches int64
+	require.NoError(t, db.DB.Raw("SELECT COUNT(*) FROM personal_access_tokens WHERE token_hash = ? OR name = ? OR scopes LIKE ?",
+		created.Token, created.Token, "%"+created.Token+"%").Scan(&matches).Error)
+	assert.Zero(t, matches, "the secret must not be stored")
+
+	listed, total, err := svc.ListTokens(context.Background(), user.ID, 0, 10)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, total)
+	assert.Equal(t, created.ID, listed[0].ID)
+}
+
+func TestPersonalAccessTokenService_Authenticate(t *testing.T) {
+	svc, _, user := newAccessTokenTestService(t)
+	created := createAccessToken(t, svc, user.ID, models.TokenScopeTransfersRead)
+
+	token, resolved, err := svc.Authenticate(context.Background(), created.Token)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, token.ID)
+	assert.Equal(t, user.ID, resolved.ID)
+
+	_, _, err = svc.Authenticate(context.Background(), created.Token+"x")
+	assert.ErrorIs(t, err, ErrPersonalAccessTokenInvalid)
+}
+
+func TestPersonalAccessTokenService_Authenticate_RecordsUseInTheBackground(t *testing.T) {
+	svc, db, user := newAccessTokenTestService(t)
+	created := createAccessToken(t, svc, user.ID, models.TokenScopeTransfersRead)
+
+	_, _, err := svc.Authenticate(context.Background(), created.Token)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		var stored models.PersonalAccessToken
+		return db.DB.First(&stored, "id = ?", created.ID).Error == nil && stored.LastUsedAt != nil
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestPersonalAccessTokenService_Authenticate_FailsClosed(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, svc *PersonalAccessTokenService, db *database.DB, user *models.User, token *CreatedPersonalAccessToken)
+		want  error
+	}{
+		{"expired", func(t *testing.T, svc *PersonalAccessTokenService, _ *database.DB, _ *models.User, token *CreatedPersonalAccessToken) {
+			svc.now = func() time.Time { return token.ExpiresAt }
+		}, ErrPersonalAccessTokenExpired},
+		{"revoked", func(t *testing.T, svc *PersonalAccessTokenService, _ *database.DB, user *models.User, token *CreatedPersonalAccessToken) {
+			require.NoError(t, svc.RevokeToken(context.Background(), user.ID, token.ID))
+		}, ErrPersonalAccessTokenRevoked},
+		{"deleted user", func(t *testing.T, _ *PersonalAccessTokenService, db *database.DB, user *models.User, _ *CreatedPersonalAccessToken) {
+			require.NoError(t, db.DB.Delete(user).Error)
+		}, ErrPersonalAccessTokenInvalid},
+		{"locked user", func(t *testing.T, _ *PersonalAccessTokenService, db *database.DB, user *models.User, _ *CreatedPersonalAccessToken) {
+			require.NoError(t, db.DB.Model(user).Update("locked_at", time.Now()).Error)
+		}, ErrAccountLocked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, db, user := newAccessTokenTestService(t)
+			created := createAccessToken(t, svc, user.ID, models.TokenScopeTransfersWrite)
+			tt.setup(t, svc, db, user, created)
+
+			token, resolved, err := svc.Authenticate(context.Background(), created.Token)
+			assert.True(t, errors.Is(err, tt.want), "expected %v, got %v", tt.want, err)
+			assert.Nil(t, token)
+			assert.Nil(t, resolved)
+		})
+	}
+}
+
+func TestPersonalAccessTokenService_RevokeToken_OtherUsersTokenNotFound(t *testing.T) {
+	svc, _, user := newAccessTokenTestService(t)
+	created := createAccessToken(t, svc, user.ID, models.TokenScopeTransfersRead)
+
+	err := svc.RevokeToken(context.Background(), uuid.New(), created.ID)
+	assert.ErrorIs(t, err, ErrPersonalAccessTokenNotFound)
+	_, _, err = svc.Authenticate(context.Background(), created.Token)
+	assert.NoError(t, err, "a failed revoke must leave the token usable")
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// ReadOnlyWorker names background work that read-only mode can pause
+type ReadOnlyWorker string
+
+// Background work covered by the read-only worker matrix
+const (
+	ReadOnlyWorkerPolling             ReadOnlyWorker = "polling"
+	ReadOnlyWorkerInitiationRetries   ReadOnlyWorker = "initiation_retries"
+	ReadOnlyWorkerCancellations       ReadOnlyWorker = "cancellations"
+	ReadOnlyWorkerRegulatorDeliveries ReadOnlyWorker = "regulator_deliveries"
+)
+
+// Worker settings in READ_ONLY_WORKERS
+const (
+	ReadOnlyWorkerRun   = "run"
+	ReadOnlyWorkerPause = "pause"
+)
+
+// Sources of the effective read-only mode
+const (
+	ReadOnlySourceConfig   = "config"
+	ReadOnlySourceOverride = "override"
+)
+
+// readOnlyRefreshInterval is how long the stored switch is cached, so a toggle on another
+// instance applies within it
+const readOnlyRefreshInterval = 5 * time.Second
+
+var (
+	ErrReadOnlyMode               = errors.New("read-only mode is on")
+	ErrUnknownReadOnlyWorker      = errors.New("unknown read-only worker")
+	errReadOnlyWorkerConfigFormat = errors.New("expected worker=run or worker=pause")
+)
+
+// readOnlyWorkerDefaults says which work keeps running in read-only mode when READ_ONLY_WORKERS
+// does not: status polling and regulator deliveries record what NorthWind already did and what
+// the regulator must be told, while initiation retries and cancellations would start new
+// changes.
+var readOnlyWorkerDefaults = map[ReadOnlyWorker]bool{
+	ReadOnlyWorkerPolling:             true,
+	ReadOnlyWorkerInitiationRetries:   false,
+	ReadOnlyWorkerCancellations:       false,
+	ReadOnlyWorkerRegulatorDeliveries: true,
+}
+
+// ReadOnlyStatus describes read-only mode as admins see it. Workers says whether each kind of
+// background work runs or pauses while the mode is on.
+type ReadOnlyStatus struct {
+	Enabled     bool                      `json:"enabled"`
+	Reason      string                    `json:"reason,omitempty"`
+	ExpectedEnd *time.Time                `json:"expected_end,omitempty"`
+	Source      string                    `json:"source"`
+	UpdatedBy   *uuid.UUID                `json:"updated_by,omitempty"`
+	UpdatedAt   *time.Time                `json:"updated_at,omitempty"`
+	Workers     map[ReadOnlyWorker]string `json:"workers"`
+}
+
+// ReadOnlyService holds the read-only switch that freezes writes during incident response. The
+// mode comes from the switch an admin last set, which is persisted so it survives restarts, or
+// from READ_ONLY_MODE until an admin has set it. Background work checks WorkerAllowed, so each
+// kind keeps running or pauses as the worker matrix says.
+type ReadOnlyService struct {
+	repo       repositories.ReadOnlyModeRepositoryInterface
+	configured bool
+	workers    map[ReadOnlyWorker]bool
+	logger     *slog.Logger
+	now        func() time.Time
+
+	// mu guards the cached switch; stored is nil until an admin has set it
+	mu       sync.Mutex
+	stored   *models.ReadOnlyMode
+	loadedAt time.Time
+}
+
+// NewReadOnlyService creates a read-only service. enabled is the configured mode and workers the
+// matrix from ParseReadOnlyWorkers; a nil matrix uses the defaults.
+func NewReadOnlyService(repo repositories.ReadOnlyModeRepositoryInterface, enabled bool, workers map[ReadOnlyWorker]bool, logger *slog.Logger) *ReadOnlyService {
+	if workers == nil {
+		workers = readOnlyWorkerDefaults
+	}
+	return &ReadOnlyService{
+		repo:       repo,
+		configured: enabled,
+		workers:    workers,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// ParseReadOnlyWorkers parses a comma-separated list of worker=run or worker=pause into the full
+// worker matrix, with the defaults for workers not listed. Unknown workers are rejected so a typo
+// does not silently leave work running.
+func ParseReadOnlyWorkers(raw string) (map[ReadOnlyWorker]bool, error) {
+	workers := make(map[ReadOnlyWorker]bool, len(readOnlyWorkerDefaults))
+	for worker, run := range readOnlyWorkerDefaults {
+		workers[worker] = run
+	}
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, setting, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q: %w", entry, errReadOnlyWorkerConfigFormat)
+		}
+		worker := ReadOnlyWorker(strings.TrimSpace(name))
+		if _, known := readOnlyWorkerDefaults[worker]; !known {
+			return nil, fmt.Errorf("%q: %w", worker, ErrUnknownReadOnlyWorker)
+		}
+		switch strings.ToLower(strings.TrimSpace(setting)) {
+		case ReadOnlyWorkerRun:
+			workers[worker] = true
+		case ReadOnlyWorkerPause:
+			workers[worker] = false
+		default:
+			return nil, fmt.Errorf("%q: %w", entry, errReadOnlyWorkerConfigFormat)
+		}
+	}
+	return workers, nil
+}
+
+// Enabled reports whether read-only mode is on. The stored switch is re-read at most every
+// readOnlyRefreshInterval; while it cannot be read the last known mode applies.
+func (s *ReadOnlyService) Enabled(ctx context.Context) bool {
+	s.mu.Lock()
+	stale := s.loadedAt.IsZero() || s.now().Sub(s.loadedAt) >= readOnlyRefreshInterval
+	if stale {
+		// Claim the refresh so concurrent callers use the cached mode instead of queueing on it
+		s.loadedAt = s.now()
+	}
+	s.mu.Unlock()
+
+	if stale {
+		if _, err := s.load(ctx); err != nil {
+			s.logger.Warn("Failed to load read-only mode; keeping the last known mode", "error", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.effective()
+}
+
+// ExpectedEnd returns when read-only mode is expected to be lifted, if it is on and the admin
+// who turned it on gave a time that has not yet passed
+func (s *ReadOnlyService) ExpectedEnd(ctx context.Context) (time.Time, bool) {
+	if !s.Enabled(ctx) {
+		return time.Time{}, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stored == nil || s.stored.ExpectedEnd == nil || !s.stored.ExpectedEnd.After(s.now()) {
+		return time.Time{}, false
+	}
+	return *s.stored.ExpectedEnd, true
+}
+
+// WorkerAllowed reports whether the background work may run: always outside read-only mode, and
+// in it as the worker matrix says
+func (s *ReadOnlyService) WorkerAllowed(ctx context.Context, worker ReadOnlyWorker) bool {
+	return !s.Enabled(ctx) || s.workers[worker]
+}
+
+// Status returns the current mode, read from the database, with the worker matrix
+func (s *ReadOnlyService) Status(ctx context.Context) (*ReadOnlyStatus, error) {
+	stored, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &ReadOnlyStatus{
+		Enabled: s.configured,
+		Source:  ReadOnlySourceConfig,
+		Workers: make(map[ReadOnlyWorker]string, len(s.workers)),
+	}
+	if stored != nil {
+		updatedAt := stored.UpdatedAt
+		status.Enabled = stored.Enabled
+		status.Reason = stored.Reason
+		status.ExpectedEnd = stored.ExpectedEnd
+		status.Source = ReadOnlySourceOverride
+		status.UpdatedBy = stored.UpdatedBy
+		status.UpdatedAt = &updatedAt
+	}
+	for worker, run := range s.workers {
+		status.Workers[worker] = ReadOnlyWorkerPause
+		if run {
+			status.Workers[worker] = ReadOnlyWorkerRun
+		}
+	}
+	return status, nil
+}
+
+// Set turns read-only mode on or off. The setting is persisted, replaces the configured mode
+// and applies on every instance within readOnlyRefreshInterval. expectedEnd, when turning the
+// mode on, is when it is expected to be lifted; it is dropped when turning the mode off.
+func (s *ReadOnlyService) Set(ctx context.Context, enabled bool, reason string, expectedEnd *time.Time, adminID uuid.UUID) error {
+	if !enabled {
+		expectedEnd = nil
+	}
+	mode := &models.ReadOnlyMode{Enabled: enabled, Reason: reason, ExpectedEnd: expectedEnd, UpdatedBy: &adminID}
+	if err := s.repo.Save(ctx, mode); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.stored = mode
+	s.loadedAt = s.now()
+	s.mu.Unlock()
+
+	s.logger.Warn("Read-only mode changed", "enabled", enabled, "reason", reason, "admin_id", adminID)
+	return nil
+}
+
+// load reads the stored switch into the cache and returns it; nil means it was never set
+func (s *ReadOnlyService) load(ctx context.Context) (*models.ReadOnlyMode, error) {
+	stored, err := s.repo.Get(ctx)
+	if errors.Is(err, repositories.ErrReadOnlyModeNotFound) {
+		stored, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.stored = stored
+	s.loadedAt = s.now()
+	s.mu.Unlock()
+	return stored, nil
+}
+
+// effective is the mode in force; callers hold mu
+func (s *ReadOnlyService) effective() bool {
+	if s.stored != nil {
+		return s.stored.Enabled
+	}
+	return s.configured
+}
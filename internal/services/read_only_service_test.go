@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/database"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newReadOnlyTestRepo(t *testing.T) repositories.ReadOnlyModeRepositoryInterface {
+	t.Helper()
+	db := database.SetupTestDB(t)
+	require.NoError(t, db.DB.AutoMigrate(&models.ReadOnlyMode{}))
+	return repositories.NewReadOnlyModeRepository(db.DB)
+}
+
+func TestReadOnlyService_ToggleSurvivesRestart(t *testing.T) {
+	repo := newReadOnlyTestRepo(t)
+	ctx := context.Background()
+	adminID := uuid.New()
+
+	svc := NewReadOnlyService(repo, false, nil, slog.Default())
+	assert.False(t, svc.Enabled(ctx), "configured default applies until an admin sets the mode")
+	require.NoError(t, svc.Set(ctx, true, "ledger corruption", nil, adminID))
+	assert.True(t, svc.Enabled(ctx))
+
+	restarted := NewReadOnlyService(repo, false, nil, slog.Default())
+	assert.True(t, restarted.Enabled(ctx), "the toggle must survive a restart")
+	status, err := restarted.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, ReadOnlySourceOverride, status.Source)
+	assert.Equal(t, "ledger corruption", status.Reason)
+	require.NotNil(t, status.UpdatedBy)
+	assert.Equal(t, adminID, *status.UpdatedBy)
+
+	// An admin turning the mode off beats READ_ONLY_MODE=true
+	require.NoError(t, restarted.Set(ctx, false, "", nil, adminID))
+	configuredOn := NewReadOnlyService(repo, true, nil, slog.Default())
+	assert.False(t, configuredOn.Enabled(ctx))
+}
+
+func TestReadOnlyService_ExpectedEnd(t *testing.T) {
+	repo := newReadOnlyTestRepo(t)
+	ctx := context.Background()
+	now := time.Date(2025, time.June, 11, 12, 0, 0, 0, time.UTC)
+	svc := NewReadOnlyService(repo, false, nil, slog.Default())
+	svc.now = func() time.Time { return now }
+
+	end := now.Add(30 * time.Minute)
+	require.NoError(t, svc.Set(ctx, true, "schema migration", &end, uuid.New()))
+	got, ok := svc.ExpectedEnd(ctx)
+	require.True(t, ok)
+	assert.True(t, got.Equal(end))
+	status, err := svc.Status(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, status.ExpectedEnd)
+	assert.True(t, status.ExpectedEnd.Equal(end))
+
+	now = end.Add(time.Second)
+	_, ok = svc.ExpectedEnd(ctx)
+	assert.False(t, ok, "a passed expected end is no hint")
+	assert.True(t, svc.Enabled(ctx), "the expected end does not lift the mode")
+
+	require.NoError(t, svc.Set(ctx, false, "", &end, uuid.New()))
+	_, ok = svc.ExpectedEnd(ctx)
+	assert.False(t, ok)
+	status, err = svc.Status(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, status.ExpectedEnd, "turning the mode off drops the expected end")
+}
+
+func TestReadOnlyService_AppliesOtherInstancesToggleAfterRefresh(t *testing.T) {
+	repo := newReadOnlyTestRepo(t)
+	ctx := context.Background()
+	now := time.Now()
+	svc := NewReadOnlyService(repo, false, nil, slog.Default())
+	svc.now = func() time.Time { return now }
+	require.False(t, svc.Enabled(ctx))
+
+	other := NewReadOnlyService(repo, false, nil, slog.Default())
+	require.NoError(t, other.Set(ctx, true, "incident", nil, uuid.New()))
+	assert.False(t, svc.Enabled(ctx), "the cached mode applies within the refresh interval")
+
+	now = now.Add(readOnlyRefreshInterval)
+	assert.True(t, svc.Enabled(ctx))
+}
+
+// failingReadOnlyRepo fails every read, like a database that went away
+type failingReadOnlyRepo struct {
+	repositories.ReadOnlyModeRepositoryInterface
+}
+
+func (failingReadOnlyRepo) Get(context.Context) (*models.ReadOnlyMode, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestReadOnlyService_KeepsLastKnownModeWhenStoreFails(t *testing.T) {
+	repo := newReadOnlyTestRepo(t)
+	ctx := context.Background()
+	now := time.Now()
+	svc := NewReadOnlyService(repo, false, nil, slog.Default())
+	svc.now = func() time.Time { return now }
+	require.NoError(t, svc.Set(ctx, true, "incident", nil, uuid.New()))
+
+	svc.repo = failingReadOnlyRepo{repo}
+	now = now.Add(readOnlyRefreshInterval)
+	assert.True(t, svc.Enabled(ctx))
+}
+
+func TestReadOnlyService_WorkerMatrix(t *testing.T) {
+	ctx := context.Background()
+	workers, err := ParseReadOnlyWorkers("initiation_retries=run, regulator_deliveries=pause")
+	require.NoError(t, err)
+	svc := NewReadOnlyService(newReadOnlyTestRepo(t), true, workers, slog.Default())
+
+	want := map[ReadOnlyWorker]bool{
+		ReadOnlyWorkerPolling:             true,
+		ReadOnlyWorkerInitiationRetries:   true,
+		ReadOnlyWorkerCancellations:       false,
+		ReadOnlyWorkerRegulatorDeliveries: false,
+	}
+	for worker, allowed := range want {
+		assert.Equal(t, allowed, svc.WorkerAllowed(ctx, worker), worker)
+	}
+
+	require.NoError(t, svc.Set(ctx, false, "", nil, uuid.New()))
+	for worker := range want {
+		assert.True(t, svc.WorkerAllowed(ctx, worker), "%s must run outside read-only mode", worker)
+	}
+}
+
+func TestParseReadOnlyWorkers_Invalid(t *testing.T) {
+	for _, raw := range []string{"polling", "polling=stop", "webhooks=pause"} {
+		_, err := ParseReadOnlyWorkers(raw)
+		assert.Error(t, err, raw)
+	}
+	_, err := ParseReadOnlyWorkers("webhooks=pause")
+	assert.ErrorIs(t, err, ErrUnknownReadOnlyWorker)
+}
+
+func TestNorthwindTransferService_SystemCancellationPausedInReadOnlyMode(t *testing.T) {
+	db := testfactory.NewDB(t)
+	userID := uuid.New()
+	transfer := testfactory.NWTransfer(t, db, testfactory.WithUser(userID))
+	svc := newInitiatorTestService(t, db, "CANCELLED")
+	readOnly := NewReadOnlyService(newReadOnlyTestRepo(t), true, nil, slog.Default())
+	svc.SetReadOnly(readOnly)
+
+	results, err := svc.CancelAllPendingTransfers(context.Background(), userID, "compromised account", models.SystemInitiator())
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, BulkCancelOutcomeUpstreamError, results[0].Outcome)
+	assert.Contains(t, results[0].Error, ErrReadOnlyMode.Error())
+
+	// Admins and users are held back by the API guard, not here
+	results, err = svc.CancelAllPendingTransfers(context.Background(), userID, "compromised account", models.AdminInitiator(uuid.New()))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, BulkCancelOutcomeCancelled, results[0].Outcome, transfer.ID)
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// MaxEvidenceTransfers bounds how many transfers one evidence export covers
+const MaxEvidenceTransfers = 500
+
+// EvidenceManifestName is the name of the manifest in an evidence bundle
+const EvidenceManifestName = "manifest.json"
+
+// EvidenceMissingNoNotification is the manifest reason for a transfer with no regulator notification
+const EvidenceMissingNoNotification = "NO_NOTIFICATION"
+
+var ErrEvidenceTooManyTransfers = errors.New("too many transfers for one evidence export")
+
+// NotificationEvidence is one notification of a transfer with every delivery attempt and, once
+// the regulator acknowledged it, the attempt that delivered it
+type NotificationEvidence struct {
+	Notification         models.RegulatorNotification          `json:"notification"`
+	Attempts             []models.RegulatorNotificationAttempt `json:"attempts"`
+	DeliveryConfirmation *models.RegulatorNotificationAttempt  `json:"delivery_confirmation"`
+}
+
+// TransferEvidence is the content of one transfer's file in an evidence bundle. Transfer is the
+// transfer as stored now, nil once retention has removed it.
+type TransferEvidence struct {
+	TransferID    uuid.UUID                 `json:"transfer_id"`
+	Transfer      *models.NorthwindTransfer `json:"transfer"`
+	Notifications []NotificationEvidence    `json:"notifications"`
+}
+
+// EvidenceManifestFile describes one file of an evidence bundle
+type EvidenceManifestFile struct {
+	Name       string    `json:"name"`
+	TransferID uuid.UUID `json:"transfer_id"`
+	SHA256     string    `json:"sha256"`
+	Size       int       `json:"size"`
+}
+
+// EvidenceMissing is a requested transfer the bundle has no evidence for
+type EvidenceMissing struct {
+	TransferID uuid.UUID `json:"transfer_id"`
+	Reason     string    `json:"reason"`
+}
+
+// EvidenceManifest lists the files of an evidence bundle with their SHA-256 hashes and the
+// requested transfers that had nothing to export
+type EvidenceManifest struct {
+	GeneratedAt time.Time              `json:"generated_at"`
+	Requested   int                    `json:"requested"`
+	Files       []EvidenceManifestFile `json:"files"`
+	Missing     []EvidenceMissing      `json:"missing"`
+}
+
+// EvidenceBundle is the evidence collected for an export, ready to be written as a ZIP
+type EvidenceBundle struct {
+	Transfers []TransferEvidence
+	Missing   []EvidenceMissing
+	requested int
+}
+
+// RegulatorEvidenceExporter packages the proof of regulator notification for a sample of
+// transfers, for audits
+type RegulatorEvidenceExporter struct {
+	notifRepo    repositories.RegulatorNotificationRepositoryInterface
+	attemptRepo  repositories.RegulatorNotificationAttemptRepositoryInterface
+	transferRepo repositories.NorthwindTransferRepositoryInterface
+}
+
+// NewRegulatorEvidenceExporter creates an evidence exporter
+func NewRegulatorEvidenceExporter(
+	notifRepo repositories.RegulatorNotificationRepositoryInterface,
+	attemptRepo repositories.RegulatorNotificationAttemptRepositoryInterface,
+	transferRepo repositories.NorthwindTransferRepositoryInterface,
+) *RegulatorEvidenceExporter {
+	return &RegulatorEvidenceExporter{notifRepo: notifRepo, attemptRepo: attemptRepo, transferRepo: transferRepo}
+}
+
+// Collect reads the transfers, notifications and attempts of the given transfers, with a fixed
+// number of queries however many transfers are requested. Duplicate IDs are
+// exported once, in the order first requested; transfers without a notification are reported
+// as missing rather than failing the export.
+func (e *RegulatorEvidenceExporter) Collect(ctx context.Context, transferIDs []uuid.UUID) (*EvidenceBundle, error) {
+	ids := make([]uuid.UUID, 0, len(transferIDs))
+	seen := make(map[uuid.UUID]bool, len(transferIDs))
+	for _, id := range transferIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > MaxEvidenceTransfers {
+		return nil, ErrEvidenceTooManyTransfers
+	}
+
+	notifications, err := e.notifRepo.ListByTransferIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	notificationIDs := make([]uuid.UUID, len(notifications))
+	for i, n := range notifications {
+		notificationIDs[i] = n.ID
+	}
+	attempts, err := e.attemptRepo.ListByNotificationIDs(ctx, notificationIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	attemptsByNotification := make(map[uuid.UUID][]models.RegulatorNotificationAttempt)
+	for _, a := range attempts {
+		attemptsByNotification[a.NotificationID] = append(attemptsByNotification[a.NotificationID], a)
+	}
+	byTransfer := make(map[uuid.UUID][]NotificationEvidence)
+	for _, n := range notifications {
+		evidence := NotificationEvidence{Notification: n, Attempts: attemptsByNotification[n.ID]}
+		if evidence.Attempts == nil {
+			evidence.Attempts = []models.RegulatorNotificationAttempt{}
+		}
+		if n.Delivered {
+			evidence.DeliveryConfirmation = deliveryConfirmation(evidence.Attempts)
+		}
+		byTransfer[n.TransferID] = append(byTransfer[n.TransferID], evidence)
+	}
+
+	transfers, err := e.transferRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &EvidenceBundle{Missing: []EvidenceMissing{}, requested: len(ids)}
+	for _, id := range ids {
+		if evidence, ok := byTransfer[id]; ok {
+			file := TransferEvidence{TransferID: id, Notifications: evidence}
+			if transfer, ok := transfers[id]; ok {
+				file.Transfer = &transfer
+			}
+			bundle.Transfers = append(bundle.Transfers, file)
+		} else {
+			bundle.Missing = append(bundle.Missing, EvidenceMissing{TransferID: id, Reason: EvidenceMissingNoNotification})
+		}
+	}
+	return bundle, nil
+}
+
+// deliveryConfirmation returns the last attempt the regulator acknowledged with a 2xx
+func deliveryConfirmation(attempts []models.RegulatorNotificationAttempt) *models.RegulatorNotificationAttempt {
+	for i := len(attempts) - 1; i >= 0; i-- {
+		if status := attempts[i].HTTPStatus; status != nil && *status >= 200 && *status < 300 {
+			return &attempts[i]
+		}
+	}
+	return nil
+}
+
+// WriteZip writes the bundle to w as a ZIP with one <transfer_id>.json per transfer and a
+// manifest holding each file's SHA-256, written last so it covers every file
+func (b *EvidenceBundle) WriteZip(w io.Writer) error {
+	archive := zip.NewWriter(w)
+	manifest := EvidenceManifest{
+		GeneratedAt: time.Now().UTC(),
+		Requested:   b.requested,
+		Files:       make([]EvidenceManifestFile, 0, len(b.Transfers)),
+		Missing:     b.Missing,
+	}
+
+	for _, transfer := range b.Transfers {
+		name := transfer.TransferID.String() + ".json"
+		content, err := json.MarshalIndent(transfer, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := writeZipFile(archive, name, content); err != nil {
+			return err
+		}
+		sum := sha256.Sum256(content)
+		manifest.Files = append(manifest.Files, EvidenceManifestFile{
+			Name:       name,
+			TransferID: transfer.TransferID,
+			SHA256:     hex.EncodeToString(sum[:]),
+			Size:       len(content),
+		})
+	}
+
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeZipFile(archive, EvidenceManifestName, content); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+func writeZipFile(archive *zip.Writer, name string, content []byte) error {
+	f, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(content)
+	return err
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/google/uuid"
+)
+
+// readZip returns the files of a ZIP by name
+func readZip(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("invalid zip: %v", err)
+	}
+	files := map[string][]byte{}
+	for _, f := range archive.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", f.Name, err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", f.Name, err)
+		}
+		files[f.Name] = content
+	}
+	return files
+}
+
+func TestRegulatorEvidenceExporter_WriteZip(t *testing.T) {
+	db := testfactory.NewDB(t)
+	ctx := context.Background()
+	notifRepo := repositories.NewRegulatorNotificationRepository(db)
+	attemptRepo := repositories.NewRegulatorNotificationAttemptRepository(db)
+
+	transfer := testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusCompleted))
+	notification := testfactory.RegulatorNotification(t, db, testfactory.WithTransfer(transfer), testfactory.WithDelivered(2))
+	failed, delivered := http.StatusInternalServerError, http.StatusOK
+	for i, status := range []*int{&failed, &delivered} {
+		attempt := &models.RegulatorNotificationAttempt{
+			NotificationID: notification.ID,
+			AttemptedAt:    time.Now().Add(time.Duration(i) * time.Second),
+			HTTPStatus:     status,
+			TargetURL:      "http://regulator/webhook",
+		}
+		if err := attemptRepo.Create(ctx, attempt); err != nil {
+			t.Fatalf("failed to create attempt: %v", err)
+		}
+	}
+	unnotified := testfactory.NWTransfer(t, db)
+
+	bundle, err := NewRegulatorEvidenceExporter(notifRepo, attemptRepo, repositories.NewNorthwindTransferRepository(db)).Collect(ctx, []uuid.UUID{transfer.ID, unnotified.ID, transfer.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := bundle.WriteZip(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files := readZip(t, buf.Bytes())
+
+	transferFile := transfer.ID.String() + ".json"
+	if len(files) != 2 || files[transferFile] == nil || files[EvidenceManifestName] == nil {
+		t.Fatalf("expected %s and the manifest, got %d files", transferFile, len(files))
+	}
+
+	var evidence TransferEvidence
+	if err := json.Unmarshal(files[transferFile], &evidence); err != nil {
+		t.Fatalf("invalid transfer file: %v", err)
+	}
+	if len(evidence.Notifications) != 1 || len(evidence.Notifications[0].Attempts) != 2 {
+		t.Fatalf("expected one notification with two attempts, got %+v", evidence.Notifications)
+	}
+	confirmation := evidence.Notifications[0].DeliveryConfirmation
+	if confirmation == nil || confirmation.HTTPStatus == nil || *confirmation.HTTPStatus != http.StatusOK {
+		t.Errorf("expected the 200 attempt as delivery confirmation, got %+v", confirmation)
+	}
+	if string(evidence.Notifications[0].Notification.Payload) == "" {
+		t.Error("expected the notification payload in the evidence")
+	}
+	if evidence.Transfer == nil || evidence.Transfer.ID != transfer.ID || evidence.Transfer.Status != models.NWTransferStatusCompleted {
+		t.Errorf("expected the transfer record in the evidence, got %+v", evidence.Transfer)
+	}
+
+	var manifest EvidenceManifest
+	if err := json.Unmarshal(files[EvidenceManifestName], &manifest); err != nil {
+		t.Fatalf("invalid manifest: %v", err)
+	}
+	if manifest.Requested != 2 {
+		t.Errorf("expected duplicate IDs to count once, got requested=%d", manifest.Requested)
+	}
+	sum := sha256.Sum256(files[transferFile])
+	if len(manifest.Files) != 1 || manifest.Files[0].Name != transferFile || manifest.Files[0].SHA256 != hex.EncodeToString(sum[:]) ||
+		manifest.Files[0].Size != len(files[transferFile]) {
+		t.Errorf("expected the manifest to hash %s, got %+v", transferFile, manifest.Files)
+	}
+	if len(manifest.Missing) != 1 || manifest.Missing[0].TransferID != unnotified.ID || manifest.Missing[0].Reason != EvidenceMissingNoNotification {
+		t.Errorf("expected the unnotified transfer reported missing, got %+v", manifest.Missing)
+	}
+}
+
+func TestRegulatorEvidenceExporter_UndeliveredAndAllMissing(t *testing.T) {
+	db := testfactory.NewDB(t)
+	ctx := context.Background()
+	exporter := NewRegulatorEvidenceExporter(repositories.NewRegulatorNotificationRepository(db), repositories.NewRegulatorNotificationAttemptRepository(db),
+		repositories.NewNorthwindTransferRepository(db))
+
+	transfer := testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusFailed))
+	testfactory.RegulatorNotification(t, db, testfactory.WithTransfer(transfer))
+	bundle, err := exporter.Collect(ctx, []uuid.UUID{transfer.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(bundle.Transfers) != 1 || bundle.Transfers[0].Notifications[0].DeliveryConfirmation != nil {
+		t.Errorf("expected an undelivered notification without confirmation, got %+v", bundle.Transfers)
+	}
+
+	bundle, err = exporter.Collect(ctx, []uuid.UUID{uuid.New(), uuid.New()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := bundle.WriteZip(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	files := readZip(t, buf.Bytes())
+	var manifest EvidenceManifest
+	if err := json.Unmarshal(files[EvidenceManifestName], &manifest); err != nil {
+		t.Fatalf("invalid manifest: %v", err)
+	}
+	if len(files) != 1 || len(manifest.Files) != 0 || len(manifest.Missing) != 2 {
+		t.Errorf("expected only a manifest listing both transfers missing, got %d files and %+v", len(files), manifest)
+	}
+}
+
+func TestRegulatorEvidenceExporter_TooManyTransfers(t *testing.T) {
+	exporter := NewRegulatorEvidenceExporter(nil, nil, nil)
+	ids := make([]uuid.UUID, MaxEvidenceTransfers+1)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+	if _, err := exporter.Collect(context.Background(), ids); !errors.Is(err, ErrEvidenceTooManyTransfers) {
+		t.Errorf("expected ErrEvidenceTooManyTransfers, got %v", err)
+	}
+}
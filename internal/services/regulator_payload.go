@@ -0,0 +1,90 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/array/banking-api/internal/models"
+)
+
+// RegulatorSignatureHeader carries the hex HMAC-SHA256 of the request body, prefixed "sha256=",
+// when a signing secret is set
+const RegulatorSignatureHeader = "X-Regulator-Signature"
+
+const (
+	// RegulatorPayloadFormatFlat sends the stored payload as is
+	RegulatorPayloadFormatFlat = "flat"
+	// RegulatorPayloadFormatEnvelope wraps the stored payload in {"data": ..., "meta": ...}
+	RegulatorPayloadFormatEnvelope = "envelope"
+)
+
+// PayloadTransformer derives the body sent to the regulator from a notification's stored
+// payload. The stored payload stays canonical; only the wire format changes.
+type PayloadTransformer interface {
+	Transform(notification *models.RegulatorNotification) ([]byte, error)
+}
+
+// IdentityTransformer sends the stored payload unchanged
+type IdentityTransformer struct{}
+
+// Transform returns the notification's stored payload
+func (IdentityTransformer) Transform(notification *models.RegulatorNotification) ([]byte, error) {
+	return notification.Payload, nil
+}
+
+// EnvelopeTransformer wraps the stored payload as "data" next to a "meta" object describing
+// the notification and the attempt
+type EnvelopeTransformer struct{}
+
+// regulatorEnvelopeMeta is the "meta" object of an enveloped payload
+type regulatorEnvelopeMeta struct {
+	NotificationID string `json:"notification_id"`
+	EventID        string `json:"event_id,omitempty"`
+	TransferID     string `json:"transfer_id"`
+	TerminalStatus string `json:"terminal_status"`
+	Attempt        int    `json:"attempt"`
+}
+
+// Transform returns {"data": <stored payload>, "meta": {...}}
+func (EnvelopeTransformer) Transform(notification *models.RegulatorNotification) ([]byte, error) {
+	if !json.Valid(notification.Payload) {
+		return nil, fmt.Errorf("stored payload is not valid JSON")
+	}
+	envelope := struct {
+		Data json.RawMessage       `json:"data"`
+		Meta regulatorEnvelopeMeta `json:"meta"`
+	}{
+		Data: notification.Payload,
+		Meta: regulatorEnvelopeMeta{
+			NotificationID: notification.ID.String(),
+			EventID:        models.EventIDFromPayload(notification.Payload),
+			TransferID:     notification.TransferID.String(),
+			TerminalStatus: notification.TerminalStatus,
+			Attempt:        notification.AttemptCount + 1,
+		},
+	}
+	return json.Marshal(envelope)
+}
+
+// PayloadTransformerForFormat returns the transformer for a REGULATOR_PAYLOAD_FORMAT value;
+// an empty format is the flat one
+func PayloadTransformerForFormat(format string) (PayloadTransformer, error) {
+	switch format {
+	case "", RegulatorPayloadFormatFlat:
+		return IdentityTransformer{}, nil
+	case RegulatorPayloadFormatEnvelope:
+		return EnvelopeTransformer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown regulator payload format %q", format)
+	}
+}
+
+// signRegulatorBody returns the signature header value for body under secret
+func signRegulatorBody(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
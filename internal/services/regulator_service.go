@@ -3,20 +3,52 @@ package services
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"math"
 	"math/rand"
+	"net"
 	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
+	"unicode/utf8"
 
 	"github.com/array/banking-api/internal/models"
 	"github.com/array/banking-api/internal/repositories"
 	"github.com/google/uuid"
 )
 
+// maxStoredResponseBodyBytes caps the regulator response body kept on each attempt record
+const maxStoredResponseBodyBytes = 1000
+
+const (
+	// connFailureFixedRetries is how many connection-level failures (DNS, connection refused)
+	// are retried on a fixed delay before falling back to exponential backoff
+	connFailureFixedRetries = 5
+	// connFailureRetryDelay is the fixed delay used for those early connection-level failures
+	connFailureRetryDelay = 5 * time.Second
+	// preflightRetryInterval is the pause between reachability probes during Preflight
+	preflightRetryInterval = time.Second
+)
+
+const (
+	// DefaultDeliveryWorkers is the number of goroutines delivering queued notifications
+	DefaultDeliveryWorkers = 4
+	// DefaultDeliveryQueueSize bounds the notifications waiting for their immediate delivery attempt
+	DefaultDeliveryQueueSize = 100
+	// queuedDeliveryLease keeps a queued notification out of the retry loop so it is not sent twice
+	queuedDeliveryLease = 30 * time.Second
+)
+
 // RegulatorService handles webhook notifications to the regulator
 type RegulatorService struct {
 	webhookURL          string
@@ -26,6 +58,20 @@ type RegulatorService struct {
 	attemptRepo         repositories.RegulatorNotificationAttemptRepositoryInterface
 	httpClient          *http.Client
 	logger              *slog.Logger
+	// jitter spreads each retry backoff; nil means DefaultJitter
+	jitter func(seconds float64) float64
+	// transformer derives the wire body from the stored payload; nil means IdentityTransformer
+	transformer PayloadTransformer
+	// signingSecret signs each wire body in RegulatorSignatureHeader; empty sends no signature
+	signingSecret []byte
+	// readOnly pauses queued deliveries in read-only mode when the worker matrix says so
+	readOnly *ReadOnlyService
+
+	// deliveryQueue is nil until StartDeliveryWorkers and again after Shutdown
+	deliveryMu     sync.RWMutex
+	deliveryQueue  chan *models.RegulatorNotification
+	deliveryWG     sync.WaitGroup
+	cancelDelivery context.CancelFunc
 }
 
 // NewRegulatorService creates a new regulator service. If httpClient is nil, a default client with 10s timeout is used (allows tests to inject httptest server client).
@@ -52,19 +98,143 @@ func NewRegulatorService(
 	}
 }
 
+// SetJitter replaces the jitter applied to retry backoffs, for tests that need exact schedules.
+// jitter takes a backoff in seconds and returns the jittered backoff.
+func (s *RegulatorService) SetJitter(jitter func(seconds float64) float64) {
+	s.jitter = jitter
+}
+
+// SetPayloadTransformer sets how the body sent to the regulator is derived from the stored
+// payload, for regulator endpoints that expect a different shape
+func (s *RegulatorService) SetPayloadTransformer(transformer PayloadTransformer) {
+	s.transformer = transformer
+}
+
+// SetSigningSecret signs every delivery with an HMAC-SHA256 of the body actually sent, in the
+// RegulatorSignatureHeader header. An empty secret sends unsigned deliveries.
+func (s *RegulatorService) SetSigningSecret(secret string) {
+	s.signingSecret = []byte(secret)
+}
+
+// SetReadOnly makes the delivery workers hold queued notifications back while read-only mode
+// pauses regulator deliveries; the retry loop sends them once the mode is lifted
+func (s *RegulatorService) SetReadOnly(readOnly *ReadOnlyService) {
+	s.readOnly = readOnly
+}
+
+// Preflight probes the webhook host (DNS lookup and TCP dial) until it is reachable or maxWait
+// elapses. It never blocks longer than maxWait and only reports the outcome: callers are
+// expected to log and carry on, since notifications are retried by the worker anyway.
+func (s *RegulatorService) Preflight(ctx context.Context, maxWait time.Duration) error {
+	target, err := url.Parse(s.webhookURL)
+	if err != nil || target.Hostname() == "" {
+		return fmt.Errorf("invalid regulator webhook URL %q", s.webhookURL)
+	}
+	port := target.Port()
+	if port == "" {
+		port = "80"
+		if target.Scheme == "https" {
+			port = "443"
+		}
+	}
+	address := net.JoinHostPort(target.Hostname(), port)
+
+	ctx, cancel := context.WithTimeout(ctx, maxWait)
+	defer cancel()
+
+	dialer := &net.Dialer{}
+	for attempt := 1; ; attempt++ {
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err == nil {
+			conn.Close()
+			s.logger.Info("Regulator webhook host reachable", "address", address, "attempts", attempt)
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			s.logger.Warn("Regulator webhook host unreachable, continuing startup",
+				"address", address,
+				"attempts", attempt,
+				"waited", maxWait,
+				"error", err,
+			)
+			return fmt.Errorf("regulator webhook host %s unreachable: %w", address, err)
+		case <-time.After(preflightRetryInterval):
+		}
+	}
+}
+
 // CreateAndSendNotification creates a notification record and immediately attempts delivery
 func (s *RegulatorService) CreateAndSendNotification(ctx context.Context, transfer *models.NorthwindTransfer, terminalStatus string) error {
+	notification, err := s.createNotification(ctx, transfer, terminalStatus, time.Now())
+	if err != nil || notification == nil {
+		return err
+	}
+
+	s.logger.Info("Regulator notification created, attempting immediate delivery",
+		"notification_id", notification.ID,
+		"transfer_id", transfer.ID,
+	)
+
+	// Immediately attempt first delivery (meeting 60-second requirement)
+	s.attemptDelivery(ctx, notification)
+
+	return nil
+}
+
+// CreateAndQueueNotification creates a notification record and hands its first delivery attempt
+// to the delivery workers, so callers never wait on the regulator. When the queue is full or the
+// workers are not running, the notification is left due for the retry loop instead.
+func (s *RegulatorService) CreateAndQueueNotification(ctx context.Context, transfer *models.NorthwindTransfer, terminalStatus string) error {
+	notification, err := s.createNotification(ctx, transfer, terminalStatus, time.Now().Add(queuedDeliveryLease))
+	if err != nil || notification == nil {
+		return err
+	}
+
+	if s.enqueueDelivery(notification) {
+		s.logger.Info("Regulator notification created, queued for immediate delivery",
+			"notification_id", notification.ID,
+			"transfer_id", transfer.ID,
+		)
+		return nil
+	}
+
+	now := time.Now()
+	notification.NextAttemptAt = &now
+	if err := s.notifRepo.Update(ctx, notification); err != nil {
+		return fmt.Errorf("failed to release notification to retry loop: %w", err)
+	}
+	s.logger.Warn("Regulator delivery queue unavailable, deferring notification to retry loop",
+		"notification_id", notification.ID,
+		"transfer_id", transfer.ID,
+	)
+	return nil
+}
+
+// createNotification persists a notification for the transfer's terminal status, first due at
+// nextAttemptAt. It returns nil without error when one already exists or the transfer is an
+// internal test transfer, which is never reported.
+func (s *RegulatorService) createNotification(ctx context.Context, transfer *models.NorthwindTransfer, terminalStatus string, nextAttemptAt time.Time) (*models.RegulatorNotification, error) {
+	if transfer.InternalTest {
+		s.logger.Debug("Skipping regulator notification for internal test transfer",
+			"transfer_id", transfer.ID,
+			"status", terminalStatus,
+		)
+		return nil, nil
+	}
+
 	// Idempotency guard: check if notification already exists for this transfer+status
-	exists, err := s.notifRepo.ExistsForTransferAndStatus(transfer.ID, terminalStatus)
+	exists, err := s.notifRepo.ExistsForTransferAndStatus(ctx, transfer.ID, terminalStatus)
 	if err != nil {
-		return fmt.Errorf("failed to check notification existence: %w", err)
+		return nil, fmt.Errorf("failed to check notification existence: %w", err)
 	}
 	if exists {
 		s.logger.Info("Notification already exists for transfer, skipping",
 			"transfer_id", transfer.ID,
 			"status", terminalStatus,
 		)
-		return nil
+		return nil, nil
 	}
 
 	// Build webhook payload
@@ -80,35 +250,138 @@ func (s *RegulatorService) CreateAndSendNotification(ctx context.Context, transf
 		TransferType:        transfer.TransferType,
 		Timestamp:           time.Now().UTC().Format(time.RFC3339),
 	}
+	if transfer.IsInbound() {
+		payload.AuthorizationConsent = transfer.AuthorizationConsent()
+	}
+	if transfer.CancellationInitiator != nil {
+		payload.CancellationOrigin = *transfer.CancellationInitiator
+	}
+	original, err := s.originalNotification(ctx, transfer, terminalStatus)
+	if err != nil {
+		return nil, err
+	}
+	if original != nil {
+		payload.OriginalEventID = models.EventIDFromPayload(original.Payload)
+	}
 
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("failed to marshal payload: %w", err)
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	now := time.Now()
 	notification := &models.RegulatorNotification{
 		TransferID:     transfer.ID,
 		TerminalStatus: terminalStatus,
 		Delivered:      false,
 		AttemptCount:   0,
-		NextAttemptAt:  &now, // Immediate first attempt
+		NextAttemptAt:  &nextAttemptAt,
 		Payload:        payloadBytes,
 	}
+	if original != nil {
+		notification.RelatedNotificationID = &original.ID
+	}
 
-	if err := s.notifRepo.Create(notification); err != nil {
-		return fmt.Errorf("failed to create notification: %w", err)
+	if err := s.notifRepo.Create(ctx, notification); err != nil {
+		return nil, fmt.Errorf("failed to create notification: %w", err)
 	}
+	return notification, nil
+}
 
-	s.logger.Info("Regulator notification created, attempting immediate delivery",
-		"notification_id", notification.ID,
-		"transfer_id", transfer.ID,
-	)
+// originalNotification returns, for a REVERSED notification, the COMPLETED notification of the
+// same transfer that it follows up. It is nil for other statuses, and for a reversal of a transfer
+// whose completion was never reported.
+func (s *RegulatorService) originalNotification(ctx context.Context, transfer *models.NorthwindTransfer, terminalStatus string) (*models.RegulatorNotification, error) {
+	if terminalStatus != models.NWTransferStatusReversed {
+		return nil, nil
+	}
+	original, err := s.notifRepo.GetByTransferAndStatus(ctx, transfer.ID, models.NWTransferStatusCompleted)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRegulatorNotificationNotFound) {
+			s.logger.Warn("Reversed transfer has no completion notification to follow up",
+				"transfer_id", transfer.ID,
+			)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find the completion notification: %w", err)
+	}
+	return original, nil
+}
 
-	// Immediately attempt first delivery (meeting 60-second requirement)
-	s.attemptDelivery(ctx, notification)
+// StartDeliveryWorkers starts workers goroutines draining a delivery queue of queueSize
+// notifications. Calling it again while workers are running has no effect.
+func (s *RegulatorService) StartDeliveryWorkers(workers, queueSize int) {
+	s.deliveryMu.Lock()
+	defer s.deliveryMu.Unlock()
+	if s.deliveryQueue != nil {
+		return
+	}
 
-	return nil
+	queue := make(chan *models.RegulatorNotification, queueSize)
+	ctx, cancel := context.WithCancel(context.Background())
+	s.deliveryQueue = queue
+	s.cancelDelivery = cancel
+	for i := 0; i < workers; i++ {
+		s.deliveryWG.Add(1)
+		go s.deliveryWorker(ctx, queue)
+	}
+	s.logger.Info("Regulator delivery workers started", "workers", workers, "queue_size", queueSize)
+}
+
+// Shutdown stops accepting queued deliveries and waits for the workers to drain the queue. If ctx
+// ends first, in-flight requests are cancelled and the remaining notifications are left for the
+// retry loop once their lease expires.
+func (s *RegulatorService) Shutdown(ctx context.Context) {
+	s.deliveryMu.Lock()
+	queue, cancel := s.deliveryQueue, s.cancelDelivery
+	s.deliveryQueue, s.cancelDelivery = nil, nil
+	s.deliveryMu.Unlock()
+	if queue == nil {
+		return
+	}
+
+	close(queue)
+	drained := make(chan struct{})
+	go func() {
+		s.deliveryWG.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		s.logger.Info("Regulator delivery queue drained")
+	case <-ctx.Done():
+		s.logger.Warn("Regulator delivery queue not drained before shutdown deadline", "remaining", len(queue))
+		cancel()
+		<-drained
+	}
+	cancel()
+}
+
+func (s *RegulatorService) enqueueDelivery(notification *models.RegulatorNotification) bool {
+	s.deliveryMu.RLock()
+	defer s.deliveryMu.RUnlock()
+	if s.deliveryQueue == nil {
+		return false
+	}
+	select {
+	case s.deliveryQueue <- notification:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *RegulatorService) deliveryWorker(ctx context.Context, queue <-chan *models.RegulatorNotification) {
+	defer s.deliveryWG.Done()
+	for notification := range queue {
+		if ctx.Err() != nil {
+			continue // shutdown deadline passed; the retry loop takes over after the lease
+		}
+		if s.readOnly != nil && !s.readOnly.WorkerAllowed(ctx, ReadOnlyWorkerRegulatorDeliveries) {
+			continue // left for the retry loop after the lease
+		}
+		s.attemptDelivery(ctx, notification)
+	}
 }
 
 // StartRetryLoop runs the background retry loop for undelivered notifications
@@ -134,7 +407,7 @@ func (s *RegulatorService) RetryOnce(ctx context.Context) {
 }
 
 func (s *RegulatorService) retryPendingNotifications(ctx context.Context) {
-	notifications, err := s.notifRepo.GetPendingNotifications(20)
+	notifications, err := s.notifRepo.GetPendingNotifications(ctx, 20)
 	if err != nil {
 		s.logger.Error("Failed to fetch pending regulator notifications", "error", err)
 		return
@@ -152,37 +425,54 @@ func (s *RegulatorService) retryPendingNotifications(ctx context.Context) {
 
 func (s *RegulatorService) attemptDelivery(ctx context.Context, notification *models.RegulatorNotification) {
 	now := time.Now()
+	// ctx bounds the webhook call only; the attempt and its retry schedule are recorded even when
+	// shutdown cancels the request mid-flight
+	storeCtx := context.WithoutCancel(ctx)
+
+	// Derive the wire body; the stored payload is never modified
+	transformer := s.transformer
+	if transformer == nil {
+		transformer = IdentityTransformer{}
+	}
+	body, err := transformer.Transform(notification)
+	if err != nil {
+		s.recordAttempt(storeCtx, notification, nil, 0, nil, fmt.Sprintf("failed to transform payload: %v", err), "")
+		s.scheduleRetry(storeCtx, notification, false)
+		return
+	}
 
 	// Prepare HTTP request
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.webhookURL, bytes.NewReader(notification.Payload))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.webhookURL, bytes.NewReader(body))
 	if err != nil {
-		s.recordAttempt(notification, nil, fmt.Sprintf("failed to create request: %v", err), "")
-		s.scheduleRetry(notification)
+		s.recordAttempt(storeCtx, notification, nil, 0, nil, fmt.Sprintf("failed to create request: %v", err), "")
+		s.scheduleRetry(storeCtx, notification, false)
 		return
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-Event-ID", notification.ID.String())
+	if len(s.signingSecret) > 0 {
+		req.Header.Set(RegulatorSignatureHeader, signRegulatorBody(s.signingSecret, body))
+	}
 
 	// Execute request
 	resp, err := s.httpClient.Do(req)
+	duration := time.Since(now)
 	if err != nil {
 		s.logger.Warn("Regulator webhook delivery failed",
 			"notification_id", notification.ID,
 			"attempt", notification.AttemptCount+1,
+			"duration_ms", duration.Milliseconds(),
 			"error", err,
 		)
-		s.recordAttempt(notification, nil, err.Error(), "")
-		s.scheduleRetry(notification)
+		s.recordAttempt(storeCtx, notification, req, duration, nil, err.Error(), "")
+		s.scheduleRetry(storeCtx, notification, isConnectionError(err))
 		return
 	}
 	defer resp.Body.Close()
 
 	bodyBytes, _ := io.ReadAll(resp.Body)
-	respBody := string(bodyBytes)
 	// Truncate response body for storage
-	if len(respBody) > 1000 {
-		respBody = respBody[:1000]
-	}
+	respBody := truncateUTF8(string(bodyBytes), maxStoredResponseBodyBytes)
 
 	httpStatus := resp.StatusCode
 
@@ -198,16 +488,17 @@ func (s *RegulatorService) attemptDelivery(ctx context.Context, notification *mo
 		notification.NextAttemptAt = nil
 		notification.LastError = nil
 
-		if err := s.notifRepo.Update(notification); err != nil {
+		if err := s.notifRepo.Update(storeCtx, notification); err != nil {
 			s.logger.Error("Failed to update notification after successful delivery", "error", err)
 		}
 
-		s.recordAttempt(notification, &httpStatus, "", respBody)
+		s.recordAttempt(storeCtx, notification, req, duration, &httpStatus, "", respBody)
 
 		s.logger.Info("Regulator notification delivered successfully",
 			"notification_id", notification.ID,
 			"transfer_id", notification.TransferID,
 			"attempts", notification.AttemptCount,
+			"duration_ms", duration.Milliseconds(),
 		)
 		return
 	}
@@ -218,16 +509,25 @@ func (s *RegulatorService) attemptDelivery(ctx context.Context, notification *mo
 		"notification_id", notification.ID,
 		"http_status", httpStatus,
 		"attempt", notification.AttemptCount+1,
+		"duration_ms", duration.Milliseconds(),
 	)
 
-	s.recordAttempt(notification, &httpStatus, errMsg, respBody)
-	s.scheduleRetry(notification)
+	s.recordAttempt(storeCtx, notification, req, duration, &httpStatus, errMsg, respBody)
+	s.scheduleRetry(storeCtx, notification, false)
 }
 
-func (s *RegulatorService) recordAttempt(notification *models.RegulatorNotification, httpStatus *int, errMsg, respBody string) {
+// recordAttempt persists an audit record for a single delivery attempt. req may be nil
+// when the request could not be built; the target URL is still recorded in that case.
+func (s *RegulatorService) recordAttempt(ctx context.Context, notification *models.RegulatorNotification, req *http.Request, duration time.Duration, httpStatus *int, errMsg, respBody string) {
 	attempt := &models.RegulatorNotificationAttempt{
 		NotificationID: notification.ID,
 		HTTPStatus:     httpStatus,
+		DurationMs:     int(duration.Milliseconds()),
+		TargetURL:      s.webhookURL,
+	}
+	if req != nil {
+		attempt.TargetURL = req.URL.String()
+		attempt.RequestHeadersHash = hashRequestHeaders(req.Header)
 	}
 	if errMsg != "" {
 		attempt.Error = &errMsg
@@ -236,12 +536,14 @@ func (s *RegulatorService) recordAttempt(notification *models.RegulatorNotificat
 		attempt.ResponseBody = &respBody
 	}
 
-	if err := s.attemptRepo.Create(attempt); err != nil {
+	if err := s.attemptRepo.Create(ctx, attempt); err != nil {
 		s.logger.Error("Failed to record notification attempt", "error", err)
 	}
 }
 
-func (s *RegulatorService) scheduleRetry(notification *models.RegulatorNotification) {
+// scheduleRetry sets the next attempt time. connectionFailure marks failures where the
+// regulator was never reached (DNS, connection refused), which get a gentler schedule.
+func (s *RegulatorService) scheduleRetry(ctx context.Context, notification *models.RegulatorNotification, connectionFailure bool) {
 	now := time.Now()
 	notification.AttemptCount++
 	notification.LastAttemptAt = &now
@@ -249,12 +551,11 @@ func (s *RegulatorService) scheduleRetry(notification *models.RegulatorNotificat
 		notification.FirstAttemptAt = &now
 	}
 
-	// Exponential backoff with jitter
-	backoff := s.calculateBackoff(notification.AttemptCount)
+	backoff := s.retryDelay(notification.AttemptCount, connectionFailure)
 	nextAttempt := now.Add(backoff)
 	notification.NextAttemptAt = &nextAttempt
 
-	if err := s.notifRepo.Update(notification); err != nil {
+	if err := s.notifRepo.Update(ctx, notification); err != nil {
 		s.logger.Error("Failed to schedule retry", "error", err)
 	}
 
@@ -263,29 +564,82 @@ func (s *RegulatorService) scheduleRetry(notification *models.RegulatorNotificat
 		"attempt", notification.AttemptCount,
 		"next_attempt_at", nextAttempt,
 		"backoff", backoff,
+		"connection_failure", connectionFailure,
 	)
 }
 
-// calculateBackoff returns the backoff duration using exponential backoff with jitter
-func (s *RegulatorService) calculateBackoff(attemptCount int) time.Duration {
-	base := float64(s.retryInitialSeconds)
-	max := float64(s.retryMaxSeconds)
-
-	// Exponential: base * 2^(attempt-1)
-	backoffSeconds := base * math.Pow(2, float64(attemptCount-1))
+// retryDelay picks the backoff for a failed attempt. The first connFailureFixedRetries
+// connection-level failures are infrastructure problems (typically DNS still converging at
+// pod start) and retry on a fixed delay; after that, and for any endpoint error, exponential
+// backoff applies. Connection failures resume the exponential schedule from its first step
+// so the fixed window does not burn through the early backoff steps.
+func (s *RegulatorService) retryDelay(attemptCount int, connectionFailure bool) time.Duration {
+	if !connectionFailure {
+		return s.calculateBackoff(attemptCount)
+	}
+	if attemptCount <= connFailureFixedRetries {
+		return connFailureRetryDelay
+	}
+	return s.calculateBackoff(attemptCount - connFailureFixedRetries)
+}
 
-	// Cap at max
-	if backoffSeconds > max {
-		backoffSeconds = max
+// isConnectionError reports whether err means the regulator host could not be reached at all
+func isConnectionError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
 	}
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// DefaultJitter moves a backoff by up to 20% either way so retries of many notifications spread out
+func DefaultJitter(seconds float64) float64 {
+	return seconds + seconds*0.2*(rand.Float64()*2-1) //nolint:gosec
+}
 
-	// Add jitter: +/- 20%
-	jitter := backoffSeconds * 0.2 * (rand.Float64()*2 - 1) //nolint:gosec
-	backoffSeconds += jitter
+// calculateBackoff returns the backoff for an attempt: base * 2^(attempt-1) with jitter, capped
+// at retryMaxSeconds after the jitter is applied so the cap is a true ceiling, and never below 1s
+func (s *RegulatorService) calculateBackoff(attemptCount int) time.Duration {
+	jitter := s.jitter
+	if jitter == nil {
+		jitter = DefaultJitter
+	}
+	backoffSeconds := jitter(float64(s.retryInitialSeconds) * math.Pow(2, float64(attemptCount-1)))
 
+	if backoffSeconds > float64(s.retryMaxSeconds) {
+		backoffSeconds = float64(s.retryMaxSeconds)
+	}
 	if backoffSeconds < 1 {
 		backoffSeconds = 1
 	}
 
 	return time.Duration(backoffSeconds * float64(time.Second))
 }
+
+// hashRequestHeaders returns a hex SHA-256 of the headers in canonical, sorted form so
+// two attempts can be compared without storing header values verbatim
+func hashRequestHeaders(h http.Header) string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, http.CanonicalHeaderKey(k))
+	}
+	sort.Strings(keys)
+
+	hasher := sha256.New()
+	for _, k := range keys {
+		fmt.Fprintf(hasher, "%s: %s\n", k, strings.Join(h.Values(k), ","))
+	}
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// truncateUTF8 shortens s to at most maxBytes without splitting a multibyte rune
+func truncateUTF8(s string, maxBytes int) string {
+	if len(s) <= maxBytes {
+		return s
+	}
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut]
+}
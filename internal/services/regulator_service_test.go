@@ -2,111 +2,100 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"strings"
+	"syscall"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"github.com/array/banking-api/internal/models"
 	"github.com/array/banking-api/internal/repositories/repository_mocks"
+	"github.com/array/banking-api/internal/testfactory"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
-	"github.com/shopspring/decimal"
 )
 
+// noJitter pins retry backoffs to their exact schedule
+func noJitter(seconds float64) float64 { return seconds }
+
+// maxJitter is DefaultJitter's largest upward move
+func maxJitter(seconds float64) float64 { return seconds * 1.2 }
+
 func TestRegulatorService_CalculateBackoff(t *testing.T) {
 	svc := &RegulatorService{
 		retryInitialSeconds: 2,
 		retryMaxSeconds:     60,
+		jitter:              noJitter,
 	}
 
 	tests := []struct {
-		attempt    int
-		minSeconds float64
-		maxSeconds float64
+		attempt int
+		want    time.Duration
 	}{
-		{1, 1.0, 3.0},    // ~2s base
-		{2, 2.0, 6.0},    // ~4s base
-		{3, 5.0, 10.0},   // ~8s base
-		{4, 10.0, 20.0},  // ~16s base
-		{5, 20.0, 40.0},  // ~32s base
-		{6, 40.0, 73.0},  // ~64s -> capped at 60
-		{10, 40.0, 73.0}, // large attempt -> still capped
+		{1, 2 * time.Second},
+		{2, 4 * time.Second},
+		{3, 8 * time.Second},
+		{4, 16 * time.Second},
+		{5, 32 * time.Second},
+		{6, 60 * time.Second},  // 64s capped at 60
+		{10, 60 * time.Second}, // large attempt still capped
 	}
 
 	for _, tt := range tests {
-		t.Run("", func(t *testing.T) {
-			// Run multiple times due to jitter
-			for i := 0; i < 20; i++ {
-				backoff := svc.calculateBackoff(tt.attempt)
-				seconds := backoff.Seconds()
-				if seconds < tt.minSeconds {
-					t.Errorf("attempt %d: backoff %v (%.2fs) below minimum %.2fs",
-						tt.attempt, backoff, seconds, tt.minSeconds)
-				}
-				if seconds > tt.maxSeconds {
-					t.Errorf("attempt %d: backoff %v (%.2fs) above maximum %.2fs",
-						tt.attempt, backoff, seconds, tt.maxSeconds)
-				}
-			}
-		})
+		if got := svc.calculateBackoff(tt.attempt); got != tt.want {
+			t.Errorf("attempt %d: backoff %v, want %v", tt.attempt, got, tt.want)
+		}
 	}
 }
 
-func TestRegulatorService_BackoffCap(t *testing.T) {
+func TestRegulatorService_BackoffCappedAfterJitter(t *testing.T) {
 	svc := &RegulatorService{
 		retryInitialSeconds: 2,
 		retryMaxSeconds:     60,
+		jitter:              maxJitter,
 	}
 
-	// Even at very high attempt counts, should never exceed max + jitter
-	for attempt := 1; attempt <= 20; attempt++ {
-		backoff := svc.calculateBackoff(attempt)
-		// Max is 60s + 20% jitter = 72s maximum theoretical
-		if backoff > 73*time.Second {
-			t.Errorf("attempt %d: backoff %v exceeds cap", attempt, backoff)
-		}
-		if backoff < 1*time.Second {
-			t.Errorf("attempt %d: backoff %v below minimum 1s", attempt, backoff)
+	// 32s jittered up stays under the cap; 64s jittered to 76.8s is capped at exactly 60s
+	if got := svc.calculateBackoff(5); got != 38400*time.Millisecond {
+		t.Errorf("attempt 5: backoff %v, want 38.4s", got)
+	}
+	for attempt := 6; attempt <= 20; attempt++ {
+		if got := svc.calculateBackoff(attempt); got != 60*time.Second {
+			t.Errorf("attempt %d: backoff %v exceeds the 60s cap", attempt, got)
 		}
 	}
 }
 
-func TestRegulatorService_BackoffIsExponential(t *testing.T) {
+func TestRegulatorService_BackoffFloor(t *testing.T) {
 	svc := &RegulatorService{
-		retryInitialSeconds: 2,
-		retryMaxSeconds:     120, // Higher cap to avoid capping during test
+		retryInitialSeconds: 1,
+		retryMaxSeconds:     60,
+		jitter:              func(seconds float64) float64 { return seconds * 0.8 },
 	}
 
-	// Verify that backoff generally increases
-	var prevMedian float64
-	for attempt := 1; attempt <= 5; attempt++ {
-		var total float64
-		runs := 100
-		for i := 0; i < runs; i++ {
-			total += svc.calculateBackoff(attempt).Seconds()
-		}
-		median := total / float64(runs)
-
-		if attempt > 1 && median <= prevMedian {
-			t.Errorf("attempt %d: median backoff %.2fs should be greater than attempt %d: %.2fs",
-				attempt, median, attempt-1, prevMedian)
-		}
-		prevMedian = median
+	// 1s jittered down to 0.8s is raised to the 1s floor
+	if got := svc.calculateBackoff(1); got != time.Second {
+		t.Errorf("attempt 1: backoff %v, want the 1s floor", got)
+	}
+	if got := svc.calculateBackoff(2); got != 1600*time.Millisecond {
+		t.Errorf("attempt 2: backoff %v, want 1.6s", got)
 	}
 }
 
-func makeTestNorthwindTransfer(t *testing.T) *models.NorthwindTransfer {
-	t.Helper()
-	return &models.NorthwindTransfer{
-		ID:                  uuid.New(),
-		NorthwindTransferID: uuid.New(),
-		Amount:              decimal.NewFromFloat(100.50),
-		Currency:            "USD",
-		Direction:           "outbound",
-		TransferType:        "ach",
-		Status:              models.NWTransferStatusCompleted,
+func TestRegulatorService_DefaultJitterWithinTwentyPercent(t *testing.T) {
+	svc := &RegulatorService{retryInitialSeconds: 2, retryMaxSeconds: 60}
+	for i := 0; i < 20; i++ {
+		if got := svc.calculateBackoff(6); got < 48*time.Second || got > 60*time.Second {
+			t.Errorf("attempt 6: backoff %v outside 48s..60s", got)
+		}
 	}
 }
 
@@ -124,20 +113,20 @@ func TestRegulatorService_CreateAndSendNotification_HTTP200_Success(t *testing.T
 
 	notifRepo := repository_mocks.NewMockRegulatorNotificationRepositoryInterface(ctrl)
 	attemptRepo := repository_mocks.NewMockRegulatorNotificationAttemptRepositoryInterface(ctrl)
-	transfer := makeTestNorthwindTransfer(t)
+	transfer := testfactory.NewNWTransfer(testfactory.WithStatus(models.NWTransferStatusCompleted))
 
-	notifRepo.EXPECT().ExistsForTransferAndStatus(transfer.ID, models.NWTransferStatusCompleted).Return(false, nil)
-	notifRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(n *models.RegulatorNotification) error {
+	notifRepo.EXPECT().ExistsForTransferAndStatus(gomock.Any(), transfer.ID, models.NWTransferStatusCompleted).Return(false, nil)
+	notifRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, n *models.RegulatorNotification) error {
 		n.ID = uuid.New()
 		return nil
 	}).Times(1)
-	notifRepo.EXPECT().Update(gomock.Any()).DoAndReturn(func(n *models.RegulatorNotification) error {
+	notifRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, n *models.RegulatorNotification) error {
 		if !n.Delivered {
 			t.Error("expected Delivered=true after 200")
 		}
 		return nil
 	}).Times(1)
-	attemptRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+	attemptRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 
 	svc := NewRegulatorService(
 		server.URL,
@@ -164,14 +153,14 @@ func TestRegulatorService_CreateAndSendNotification_HTTP500_SchedulesRetry(t *te
 
 	notifRepo := repository_mocks.NewMockRegulatorNotificationRepositoryInterface(ctrl)
 	attemptRepo := repository_mocks.NewMockRegulatorNotificationAttemptRepositoryInterface(ctrl)
-	transfer := makeTestNorthwindTransfer(t)
+	transfer := testfactory.NewNWTransfer(testfactory.WithStatus(models.NWTransferStatusCompleted))
 
-	notifRepo.EXPECT().ExistsForTransferAndStatus(transfer.ID, models.NWTransferStatusFailed).Return(false, nil)
-	notifRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(n *models.RegulatorNotification) error {
+	notifRepo.EXPECT().ExistsForTransferAndStatus(gomock.Any(), transfer.ID, models.NWTransferStatusFailed).Return(false, nil)
+	notifRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, n *models.RegulatorNotification) error {
 		n.ID = uuid.New()
 		return nil
 	}).Times(1)
-	notifRepo.EXPECT().Update(gomock.Any()).DoAndReturn(func(n *models.RegulatorNotification) error {
+	notifRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, n *models.RegulatorNotification) error {
 		if n.Delivered {
 			t.Error("expected Delivered=false after 500")
 		}
@@ -180,7 +169,7 @@ func TestRegulatorService_CreateAndSendNotification_HTTP500_SchedulesRetry(t *te
 		}
 		return nil
 	}).Times(1)
-	attemptRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+	attemptRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 
 	svc := NewRegulatorService(
 		server.URL,
@@ -196,17 +185,35 @@ func TestRegulatorService_CreateAndSendNotification_HTTP500_SchedulesRetry(t *te
 	}
 }
 
+func TestRegulatorService_CreateAndSendNotification_SkipsInternalTestTransfers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// No repository calls and no delivery are expected
+	notifRepo := repository_mocks.NewMockRegulatorNotificationRepositoryInterface(ctrl)
+	attemptRepo := repository_mocks.NewMockRegulatorNotificationAttemptRepositoryInterface(ctrl)
+	svc := NewRegulatorService("http://regulator.invalid/webhook", 2, 60, notifRepo, attemptRepo, slog.Default(), nil)
+
+	transfer := testfactory.NewNWTransfer(testfactory.WithStatus(models.NWTransferStatusCompleted), testfactory.WithInternalTest())
+	if err := svc.CreateAndSendNotification(context.Background(), transfer, models.NWTransferStatusCompleted); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := svc.CreateAndQueueNotification(context.Background(), transfer, models.NWTransferStatusCompleted); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestRegulatorService_CreateAndSendNotification_Idempotency_SkipsIfExists(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	notifRepo := repository_mocks.NewMockRegulatorNotificationRepositoryInterface(ctrl)
 	attemptRepo := repository_mocks.NewMockRegulatorNotificationAttemptRepositoryInterface(ctrl)
-	transfer := makeTestNorthwindTransfer(t)
+	transfer := testfactory.NewNWTransfer(testfactory.WithStatus(models.NWTransferStatusCompleted))
 
-	notifRepo.EXPECT().ExistsForTransferAndStatus(transfer.ID, models.NWTransferStatusCompleted).Return(true, nil)
-	notifRepo.EXPECT().Create(gomock.Any()).Times(0)
-	attemptRepo.EXPECT().Create(gomock.Any()).Times(0)
+	notifRepo.EXPECT().ExistsForTransferAndStatus(gomock.Any(), transfer.ID, models.NWTransferStatusCompleted).Return(true, nil)
+	notifRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
+	attemptRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Times(0)
 
 	svc := NewRegulatorService(
 		"http://localhost:9999/webhook",
@@ -234,26 +241,16 @@ func TestRegulatorService_RetryOnce_DeliversPending(t *testing.T) {
 	notifRepo := repository_mocks.NewMockRegulatorNotificationRepositoryInterface(ctrl)
 	attemptRepo := repository_mocks.NewMockRegulatorNotificationAttemptRepositoryInterface(ctrl)
 
-	payload := []byte(`{"event_id":"e1","transfer_id":"t1","status":"COMPLETED"}`)
-	notif := models.RegulatorNotification{
-		ID:             uuid.New(),
-		TransferID:     uuid.New(),
-		TerminalStatus: models.NWTransferStatusCompleted,
-		Delivered:      false,
-		AttemptCount:   0,
-		Payload:        payload,
-	}
-	now := time.Now()
-	notif.NextAttemptAt = &now
+	notif := *testfactory.NewRegulatorNotification()
 
-	notifRepo.EXPECT().GetPendingNotifications(20).Return([]models.RegulatorNotification{notif}, nil)
-	notifRepo.EXPECT().Update(gomock.Any()).DoAndReturn(func(n *models.RegulatorNotification) error {
+	notifRepo.EXPECT().GetPendingNotifications(gomock.Any(), 20).Return([]models.RegulatorNotification{notif}, nil)
+	notifRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, n *models.RegulatorNotification) error {
 		if !n.Delivered {
 			t.Error("expected Delivered=true after 200")
 		}
 		return nil
 	}).Times(1)
-	attemptRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(1)
+	attemptRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 
 	svc := NewRegulatorService(
 		server.URL,
@@ -265,3 +262,378 @@ func TestRegulatorService_RetryOnce_DeliversPending(t *testing.T) {
 	ctx := context.Background()
 	svc.RetryOnce(ctx)
 }
+
+func TestRegulatorService_AttemptRecordsDurationAndTarget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(25 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifRepo := repository_mocks.NewMockRegulatorNotificationRepositoryInterface(ctrl)
+	attemptRepo := repository_mocks.NewMockRegulatorNotificationAttemptRepositoryInterface(ctrl)
+	transfer := testfactory.NewNWTransfer(testfactory.WithStatus(models.NWTransferStatusCompleted))
+
+	notifRepo.EXPECT().ExistsForTransferAndStatus(gomock.Any(), transfer.ID, models.NWTransferStatusCompleted).Return(false, nil)
+	notifRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, n *models.RegulatorNotification) error {
+		n.ID = uuid.New()
+		return nil
+	})
+	notifRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	var recorded *models.RegulatorNotificationAttempt
+	attemptRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, a *models.RegulatorNotificationAttempt) error {
+		recorded = a
+		return nil
+	})
+
+	svc := NewRegulatorService(server.URL, 2, 60, notifRepo, attemptRepo, slog.Default(), server.Client())
+	if err := svc.CreateAndSendNotification(context.Background(), transfer, models.NWTransferStatusCompleted); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if recorded == nil {
+		t.Fatal("expected attempt to be recorded")
+	}
+	if recorded.DurationMs < 25 {
+		t.Errorf("expected duration >= 25ms, got %dms", recorded.DurationMs)
+	}
+	if recorded.TargetURL != server.URL {
+		t.Errorf("expected target URL %s, got %s", server.URL, recorded.TargetURL)
+	}
+	if len(recorded.RequestHeadersHash) != 64 {
+		t.Errorf("expected 64-char sha256 hex header hash, got %q", recorded.RequestHeadersHash)
+	}
+}
+
+func TestRegulatorService_ResponseBodyTruncationIsUTF8Safe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// 1 ASCII byte followed by 2-byte runes pushes a rune across the 1000-byte boundary
+	body := "x" + strings.Repeat("é", 800)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	notifRepo := repository_mocks.NewMockRegulatorNotificationRepositoryInterface(ctrl)
+	attemptRepo := repository_mocks.NewMockRegulatorNotificationAttemptRepositoryInterface(ctrl)
+
+	notif := *testfactory.NewRegulatorNotification()
+	notifRepo.EXPECT().GetPendingNotifications(gomock.Any(), 20).Return([]models.RegulatorNotification{notif}, nil)
+	notifRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	var recorded *models.RegulatorNotificationAttempt
+	attemptRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, a *models.RegulatorNotificationAttempt) error {
+		recorded = a
+		return nil
+	})
+
+	svc := NewRegulatorService(server.URL, 2, 60, notifRepo, attemptRepo, slog.Default(), server.Client())
+	svc.RetryOnce(context.Background())
+
+	if recorded == nil || recorded.ResponseBody == nil {
+		t.Fatal("expected attempt with response body to be recorded")
+	}
+	stored := *recorded.ResponseBody
+	if len(stored) > maxStoredResponseBodyBytes {
+		t.Errorf("expected at most %d bytes, got %d", maxStoredResponseBodyBytes, len(stored))
+	}
+	if !utf8.ValidString(stored) {
+		t.Error("expected stored response body to be valid UTF-8")
+	}
+	if !strings.HasPrefix(body, stored) {
+		t.Error("expected stored body to be a prefix of the original")
+	}
+}
+
+func TestTruncateUTF8(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		max      int
+		expected string
+	}{
+		{"shorter than max", "abc", 10, "abc"},
+		{"ascii cut", "abcdef", 3, "abc"},
+		{"cut inside 2-byte rune", "aé", 2, "a"},
+		{"cut inside 4-byte rune", "ab😀", 4, "ab"},
+		{"cut on rune boundary", "é€", 2, "é"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateUTF8(tt.in, tt.max)
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("result %q is not valid UTF-8", got)
+			}
+		})
+	}
+}
+
+func TestRegulatorService_InboundPayloadIncludesConsent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var received models.RegulatorWebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifRepo := repository_mocks.NewMockRegulatorNotificationRepositoryInterface(ctrl)
+	attemptRepo := repository_mocks.NewMockRegulatorNotificationAttemptRepositoryInterface(ctrl)
+
+	consent := models.AuthorizationConsent{
+		Timestamp: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+		IPAddress: "203.0.113.10",
+		Method:    models.ConsentMethodWritten,
+	}
+	transfer := testfactory.NewNWTransfer(
+		testfactory.WithStatus(models.NWTransferStatusCompleted),
+		testfactory.WithDirection(models.NWTransferDirectionInbound),
+		testfactory.WithConsent(consent),
+	)
+
+	notifRepo.EXPECT().ExistsForTransferAndStatus(gomock.Any(), transfer.ID, models.NWTransferStatusCompleted).Return(false, nil)
+	notifRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	notifRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+	attemptRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	svc := NewRegulatorService(server.URL, 2, 60, notifRepo, attemptRepo, slog.Default(), server.Client())
+	if err := svc.CreateAndSendNotification(context.Background(), transfer, models.NWTransferStatusCompleted); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if received.AuthorizationConsent == nil {
+		t.Fatal("expected authorization consent in inbound payload")
+	}
+	if *received.AuthorizationConsent != consent {
+		t.Errorf("unexpected consent in payload: %+v", *received.AuthorizationConsent)
+	}
+}
+
+// closedAddress returns a loopback address with nothing listening on it
+func closedAddress(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to reserve port: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+	return addr
+}
+
+func TestRegulatorService_ConnectionFailureScheduling(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	dnsFailingClient := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return nil, &net.OpError{Op: "dial", Net: network, Err: &net.DNSError{Err: "no such host", Name: "regulator", IsNotFound: true}}
+		},
+	}}
+
+	tests := []struct {
+		name          string
+		url           string
+		client        *http.Client
+		priorAttempts int
+		fixed         bool
+	}{
+		{"dns failure uses fixed delay", "http://regulator:9000/webhook", dnsFailingClient, 0, true},
+		{"dns failure within window", "http://regulator:9000/webhook", dnsFailingClient, connFailureFixedRetries - 1, true},
+		{"dns failure after window is exponential", "http://regulator:9000/webhook", dnsFailingClient, connFailureFixedRetries, false},
+		{"connection refused uses fixed delay", "http://" + closedAddress(t) + "/webhook", nil, 0, true},
+		{"http 500 is exponential", server.URL, server.Client(), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			notifRepo := repository_mocks.NewMockRegulatorNotificationRepositoryInterface(ctrl)
+			attemptRepo := repository_mocks.NewMockRegulatorNotificationAttemptRepositoryInterface(ctrl)
+
+			notif := *testfactory.NewRegulatorNotification()
+			notif.AttemptCount = tt.priorAttempts
+
+			var delay time.Duration
+			notifRepo.EXPECT().GetPendingNotifications(gomock.Any(), 20).Return([]models.RegulatorNotification{notif}, nil)
+			notifRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, n *models.RegulatorNotification) error {
+				if n.NextAttemptAt == nil || n.LastAttemptAt == nil {
+					t.Fatal("expected LastAttemptAt and NextAttemptAt to be set")
+				}
+				delay = n.NextAttemptAt.Sub(*n.LastAttemptAt)
+				return nil
+			})
+			attemptRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+			svc := NewRegulatorService(tt.url, 2, 60, notifRepo, attemptRepo, slog.Default(), tt.client)
+			svc.SetJitter(noJitter)
+			svc.RetryOnce(context.Background())
+
+			if tt.fixed {
+				if delay != connFailureRetryDelay {
+					t.Errorf("expected fixed delay %v, got %v", connFailureRetryDelay, delay)
+				}
+				return
+			}
+			if delay != 2*time.Second {
+				t.Errorf("expected the first exponential step of 2s, got %v", delay)
+			}
+		})
+	}
+}
+
+func TestIsConnectionError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"dns", &net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", IsNotFound: true}}, true},
+		{"connection refused", &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, true},
+		{"timeout", context.DeadlineExceeded, false},
+		{"other", errors.New("boom"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isConnectionError(tt.err); got != tt.expected {
+				t.Errorf("isConnectionError(%v) = %v, want %v", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRegulatorService_Preflight(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	reachable := NewRegulatorService(server.URL+"/webhook", 2, 60, nil, nil, slog.Default(), nil)
+	if err := reachable.Preflight(context.Background(), time.Second); err != nil {
+		t.Errorf("expected reachable host to pass preflight, got %v", err)
+	}
+
+	unreachable := NewRegulatorService("http://"+closedAddress(t)+"/webhook", 2, 60, nil, nil, slog.Default(), nil)
+	start := time.Now()
+	if err := unreachable.Preflight(context.Background(), 1500*time.Millisecond); err == nil {
+		t.Error("expected preflight to fail for unreachable host")
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("expected preflight to give up after its bounded wait, took %v", elapsed)
+	}
+}
+
+func TestRegulatorService_PayloadTransformers(t *testing.T) {
+	payload := json.RawMessage(`{"event_id":"evt-1","transfer_id":"t-1","status":"COMPLETED","amount":12.5}`)
+	notificationID := uuid.New()
+	transferID := uuid.New()
+
+	tests := []struct {
+		name        string
+		transformer PayloadTransformer
+		wantBody    string
+	}{
+		{"default", nil, string(payload)},
+		{"flat", IdentityTransformer{}, string(payload)},
+		{"envelope", EnvelopeTransformer{}, `{"data":` + string(payload) + `,"meta":{"notification_id":"` + notificationID.String() +
+			`","event_id":"evt-1","transfer_id":"` + transferID.String() + `","terminal_status":"COMPLETED","attempt":3}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			var body []byte
+			var signature string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ = io.ReadAll(r.Body)
+				signature = r.Header.Get(RegulatorSignatureHeader)
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			notification := &models.RegulatorNotification{
+				ID:             notificationID,
+				TransferID:     transferID,
+				TerminalStatus: models.NWTransferStatusCompleted,
+				AttemptCount:   2,
+				Payload:        append(json.RawMessage(nil), payload...),
+			}
+			notifRepo := repository_mocks.NewMockRegulatorNotificationRepositoryInterface(ctrl)
+			attemptRepo := repository_mocks.NewMockRegulatorNotificationAttemptRepositoryInterface(ctrl)
+			notifRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, n *models.RegulatorNotification) error {
+				if string(n.Payload) != string(payload) {
+					t.Errorf("expected the stored payload untouched, got %s", n.Payload)
+				}
+				return nil
+			})
+			attemptRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+			svc := NewRegulatorService(server.URL, 2, 60, notifRepo, attemptRepo, slog.Default(), server.Client())
+			if tt.transformer != nil {
+				svc.SetPayloadTransformer(tt.transformer)
+			}
+			svc.SetSigningSecret("regulator-secret")
+			svc.attemptDelivery(context.Background(), notification)
+
+			if string(body) != tt.wantBody {
+				t.Errorf("wire body:\n got %s\nwant %s", body, tt.wantBody)
+			}
+			if want := signRegulatorBody([]byte("regulator-secret"), []byte(tt.wantBody)); signature != want {
+				t.Errorf("expected the signature over the wire body %s, got %s", want, signature)
+			}
+			if string(notification.Payload) != string(payload) {
+				t.Errorf("expected the stored payload untouched, got %s", notification.Payload)
+			}
+		})
+	}
+}
+
+func TestRegulatorService_UnsignedWithoutSecret(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	signed := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, signed = r.Header[RegulatorSignatureHeader]
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifRepo := repository_mocks.NewMockRegulatorNotificationRepositoryInterface(ctrl)
+	attemptRepo := repository_mocks.NewMockRegulatorNotificationAttemptRepositoryInterface(ctrl)
+	notifRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+	attemptRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	svc := NewRegulatorService(server.URL, 2, 60, notifRepo, attemptRepo, slog.Default(), server.Client())
+	svc.attemptDelivery(context.Background(), &models.RegulatorNotification{ID: uuid.New(), Payload: json.RawMessage(`{}`)})
+	if signed {
+		t.Error("expected no signature header without a signing secret")
+	}
+}
+
+func TestPayloadTransformerForFormat(t *testing.T) {
+	for format, want := range map[string]PayloadTransformer{
+		"":         IdentityTransformer{},
+		"flat":     IdentityTransformer{},
+		"envelope": EnvelopeTransformer{},
+	} {
+		got, err := PayloadTransformerForFormat(format)
+		if err != nil || got != want {
+			t.Errorf("format %q: got %T, %v", format, got, err)
+		}
+	}
+	if _, err := PayloadTransformerForFormat("xml"); err == nil {
+		t.Error("expected an unknown format to be rejected")
+	}
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/array/banking-api/internal/config"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+)
+
+// Entities the retention policy applies to, in the order they are purged: transfers before the
+// transactions they reference
+const (
+	RetentionEntityNorthwindTransfers = "northwind_transfers"
+	RetentionEntityTransfers          = "transfers"
+	RetentionEntityTransactions       = "transactions"
+	RetentionEntityAuditLogs          = "audit_logs"
+)
+
+const (
+	// retentionBatchPause is slept between purge batches so the purged tables are not starved
+	retentionBatchPause = 50 * time.Millisecond
+
+	defaultRetentionBatchSize   = 1000
+	defaultRetentionMaxDuration = 10 * time.Minute
+)
+
+// retentionPurger is what the retention job needs from an entity's repository
+type retentionPurger interface {
+	CountRetentionExpired(ctx context.Context, cutoff time.Time) (models.RetentionCount, error)
+	PurgeRetentionExpired(ctx context.Context, cutoff time.Time, limit int) (int64, error)
+}
+
+type retentionTarget struct {
+	entity string
+	years  int
+	repo   retentionPurger
+}
+
+// RetentionReport is the retention outcome for one entity. Expired and Held are counted before
+// anything is purged; a dry run purges nothing.
+type RetentionReport struct {
+	Entity         string    `json:"entity"`
+	RetentionYears int       `json:"retention_years"`
+	Cutoff         time.Time `json:"cutoff"`
+	models.RetentionCount
+	Purged int64 `json:"purged"`
+	// Truncated is set when the run hit its time limit before every expired row was purged
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// RetentionService purges records once they are past their retention period: transfers and
+// transactions after TransferYears and TransactionYears, audit logs after AuditLogYears. Rows
+// still referenced by open work are held, whatever their age.
+type RetentionService struct {
+	targets []retentionTarget
+	cfg     config.RetentionConfig
+	logger  *slog.Logger
+	now     func() time.Time
+}
+
+// NewRetentionService creates a retention service for the given repositories. A zero BatchSize
+// or MaxDuration uses the default.
+func NewRetentionService(
+	nwTransfers repositories.NorthwindTransferRepositoryInterface,
+	transfers repositories.TransferRepositoryInterface,
+	transactions repositories.TransactionRepositoryInterface,
+	auditLogs repositories.AuditLogRepositoryInterface,
+	cfg config.RetentionConfig,
+	logger *slog.Logger,
+) *RetentionService {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultRetentionBatchSize
+	}
+	if cfg.MaxDuration <= 0 {
+		cfg.MaxDuration = defaultRetentionMaxDuration
+	}
+	return &RetentionService{
+		targets: []retentionTarget{
+			{entity: RetentionEntityNorthwindTransfers, years: cfg.TransferYears, repo: nwTransfers},
+			{entity: RetentionEntityTransfers, years: cfg.TransferYears, repo: transfers},
+			{entity: RetentionEntityTransactions, years: cfg.TransactionYears, repo: transactions},
+			{entity: RetentionEntityAuditLogs, years: cfg.AuditLogYears, repo: auditLogs},
+		},
+		cfg:    cfg,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// DryRun reports, per entity, how many rows are past retention and how many of those are held,
+// without purging anything
+func (s *RetentionService) DryRun(ctx context.Context) ([]RetentionReport, error) {
+	now := s.now()
+	reports := make([]RetentionReport, 0, len(s.targets))
+	for _, target := range s.targets {
+		report, err := s.count(ctx, target, now)
+		if err != nil {
+			return reports, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// Purge hard-deletes the rows past retention that are not held, entity by entity in batches of
+// BatchSize, logging its progress after each batch. It stops after MaxDuration and marks the
+// entity it stopped in as truncated; the next run carries on.
+func (s *RetentionService) Purge(ctx context.Context) ([]RetentionReport, error) {
+	now := s.now()
+	deadline := time.Now().Add(s.cfg.MaxDuration)
+	reports := make([]RetentionReport, 0, len(s.targets))
+	for _, target := range s.targets {
+		report, err := s.count(ctx, target, now)
+		if err != nil {
+			return reports, err
+		}
+		if report.Expired > 0 {
+			report.Purged, report.Truncated, err = s.purge(ctx, target, report, deadline)
+		}
+		reports = append(reports, report)
+		if err != nil || report.Truncated {
+			return reports, err
+		}
+	}
+	return reports, nil
+}
+
+// Run is the retention worker job: a Purge, or only a DryRun while DryRun is configured
+func (s *RetentionService) Run(ctx context.Context) error {
+	var reports []RetentionReport
+	var err error
+	if s.cfg.DryRun {
+		reports, err = s.DryRun(ctx)
+	} else {
+		reports, err = s.Purge(ctx)
+	}
+	for _, report := range reports {
+		s.logger.Info("Retention run",
+			"entity", report.Entity,
+			"dry_run", s.cfg.DryRun,
+			"cutoff", report.Cutoff,
+			"expired", report.Expired,
+			"held", report.Held,
+			"purged", report.Purged,
+			"truncated", report.Truncated,
+		)
+	}
+	return err
+}
+
+func (s *RetentionService) count(ctx context.Context, target retentionTarget, now time.Time) (RetentionReport, error) {
+	report := RetentionReport{
+		Entity:         target.entity,
+		RetentionYears: target.years,
+		Cutoff:         now.AddDate(-target.years, 0, 0),
+	}
+	count, err := target.repo.CountRetentionExpired(ctx, report.Cutoff)
+	report.RetentionCount = count
+	return report, err
+}
+
+// purge deletes target's purgeable rows until none are left or the deadline passes, and reports
+// the rows deleted and whether it stopped at the deadline
+func (s *RetentionService) purge(ctx context.Context, target retentionTarget, report RetentionReport, deadline time.Time) (int64, bool, error) {
+	var purged int64
+	for {
+		deleted, err := target.repo.PurgeRetentionExpired(ctx, report.Cutoff, s.cfg.BatchSize)
+		purged += deleted
+		if err != nil {
+			return purged, false, err
+		}
+		s.logger.Info("Retention purge progress",
+			"entity", target.entity,
+			"purged", purged,
+			"expired", report.Expired,
+		)
+		if deleted < int64(s.cfg.BatchSize) {
+			return purged, false, nil
+		}
+		if time.Now().Add(retentionBatchPause).After(deadline) {
+			return purged, true, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return purged, false, ctx.Err()
+		case <-time.After(retentionBatchPause):
+		}
+	}
+}
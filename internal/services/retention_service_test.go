@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/config"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+var retentionTestNow = time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func newRetentionTestService(t *testing.T, db *gorm.DB, batchSize int) *RetentionService {
+	t.Helper()
+	service := NewRetentionService(
+		repositories.NewNorthwindTransferRepository(db),
+		repositories.NewTransferRepository(db),
+		repositories.NewTransactionRepository(db),
+		repositories.NewAuditLogRepository(db),
+		config.RetentionConfig{TransferYears: 7, TransactionYears: 7, AuditLogYears: 10, BatchSize: batchSize},
+		slog.Default(),
+	)
+	service.now = func() time.Time { return retentionTestNow }
+	return service
+}
+
+// seedRetentionRows stores, for every entity, count rows that are yearsOld years old
+func seedRetentionRows(t *testing.T, db *gorm.DB, yearsOld, count int) {
+	t.Helper()
+	createdAt := retentionTestNow.AddDate(-yearsOld, 0, -1)
+	for i := 0; i < count; i++ {
+		testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusCompleted), testfactory.WithCreatedAt(createdAt))
+		if err := db.Create(&models.Transaction{
+			AccountID:       uuid.New(),
+			TransactionType: models.TransactionTypeCredit,
+			Amount:          decimal.NewFromFloat(10),
+			BalanceBefore:   decimal.Zero,
+			BalanceAfter:    decimal.NewFromFloat(10),
+			Description:     "deposit",
+			CreatedAt:       createdAt,
+			UpdatedAt:       createdAt,
+		}).Error; err != nil {
+			t.Fatalf("failed to create transaction: %v", err)
+		}
+		if err := db.Create(&models.AuditLog{Action: models.AuditActionLogin, Resource: "user", CreatedAt: createdAt}).Error; err != nil {
+			t.Fatalf("failed to create audit log: %v", err)
+		}
+	}
+}
+
+func retentionReport(t *testing.T, reports []RetentionReport, entity string) RetentionReport {
+	t.Helper()
+	for _, report := range reports {
+		if report.Entity == entity {
+			return report
+		}
+	}
+	t.Fatalf("no report for %s in %+v", entity, reports)
+	return RetentionReport{}
+}
+
+func countRows(t *testing.T, db *gorm.DB, model interface{}) int64 {
+	t.Helper()
+	var count int64
+	if err := db.Model(model).Count(&count).Error; err != nil {
+		t.Fatalf("failed to count rows: %v", err)
+	}
+	return count
+}
+
+func TestRetentionService_DryRunPurgesNothing(t *testing.T) {
+	db := testfactory.NewDB(t)
+	seedRetentionRows(t, db, 8, 2)
+	service := newRetentionTestService(t, db, 0)
+
+	reports, err := service.DryRun(context.Background())
+	if err != nil {
+		t.Fatalf("DryRun: %v", err)
+	}
+	if len(reports) != 4 {
+		t.Fatalf("expected a report per entity, got %+v", reports)
+	}
+	if got := retentionReport(t, reports, RetentionEntityNorthwindTransfers); got.Expired != 2 || got.Purged != 0 {
+		t.Errorf("northwind transfers: expected 2 expired and none purged, got %+v", got)
+	}
+	if got := retentionReport(t, reports, RetentionEntityAuditLogs); got.Expired != 0 {
+		t.Errorf("audit logs are kept for 10 years, got %+v", got)
+	}
+	if got := retentionReport(t, reports, RetentionEntityTransactions).Cutoff; !got.Equal(retentionTestNow.AddDate(-7, 0, 0)) {
+		t.Errorf("unexpected transaction cutoff %v", got)
+	}
+	if n := countRows(t, db, &models.NorthwindTransfer{}); n != 2 {
+		t.Errorf("dry run deleted transfers: %d left", n)
+	}
+}
+
+func TestRetentionService_PurgeInBatchesKeepsRetainedRows(t *testing.T) {
+	db := testfactory.NewDB(t)
+	seedRetentionRows(t, db, 8, 3)
+	seedRetentionRows(t, db, 1, 1)
+	service := newRetentionTestService(t, db, 2)
+
+	reports, err := service.Purge(context.Background())
+	if err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if got := retentionReport(t, reports, RetentionEntityTransactions); got.Purged != 3 || got.Truncated {
+		t.Errorf("transactions: expected 3 purged across batches, got %+v", got)
+	}
+	if n := countRows(t, db, &models.NorthwindTransfer{}); n != 1 {
+		t.Errorf("expected only the recent transfer left, got %d", n)
+	}
+	if n := countRows(t, db, &models.Transaction{}); n != 1 {
+		t.Errorf("expected only the recent transaction left, got %d", n)
+	}
+	if n := countRows(t, db, &models.AuditLog{}); n != 4 {
+		t.Errorf("audit logs within 10 years must be kept, got %d", n)
+	}
+}
+
+func TestRetentionService_RunHonoursDryRun(t *testing.T) {
+	db := testfactory.NewDB(t)
+	seedRetentionRows(t, db, 11, 1)
+	service := newRetentionTestService(t, db, 0)
+	service.cfg.DryRun = true
+
+	if err := service.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if n := countRows(t, db, &models.AuditLog{}); n != 1 {
+		t.Fatalf("dry run must not purge, got %d audit logs", n)
+	}
+
+	service.cfg.DryRun = false
+	if err := service.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if n := countRows(t, db, &models.AuditLog{}); n != 0 {
+		t.Errorf("expected the 11 year old audit log purged, got %d", n)
+	}
+}
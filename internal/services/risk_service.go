@@ -0,0 +1,369 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/array/banking-api/internal/config"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// RiskRule names a rule evaluated against a transfer request before it is initiated
+type RiskRule string
+
+// Risk rules
+const (
+	RiskRuleVelocityCount  RiskRule = "velocity_count"
+	RiskRuleVelocityAmount RiskRule = "velocity_amount"
+	RiskRuleLargeTransfer  RiskRule = "large_transfer"
+)
+
+var (
+	ErrUnknownRiskRule       = errors.New("unknown risk rule")
+	ErrInvalidRiskRuleMode   = errors.New("risk rule mode must be off, shadow or enforce")
+	ErrNWTransferRiskBlocked = errors.New("transfer blocked by risk rules")
+	errRiskRuleConfigFormat  = errors.New("expected rule=off, rule=shadow or rule=enforce")
+)
+
+// RiskRuleDefinition declares a rule in code. DefaultMode applies when neither config nor an
+// override sets the rule's mode; new rules start in shadow mode so they are observed before
+// they can block anyone.
+type RiskRuleDefinition struct {
+	Name        RiskRule
+	Description string
+	DefaultMode string
+}
+
+var riskRuleDefinitions = []RiskRuleDefinition{
+	{Name: RiskRuleVelocityCount, Description: "More transfers within the velocity window than RISK_VELOCITY_MAX_TRANSFERS", DefaultMode: models.RiskRuleModeShadow},
+	{Name: RiskRuleVelocityAmount, Description: "More than RISK_VELOCITY_MAX_AMOUNT transferred in one currency within the velocity window", DefaultMode: models.RiskRuleModeShadow},
+	{Name: RiskRuleLargeTransfer, Description: "A single transfer of at least RISK_LARGE_TRANSFER_AMOUNT", DefaultMode: models.RiskRuleModeShadow},
+}
+
+// RiskRuleStatus describes a rule as admins see it. Source says where Mode comes from, using the
+// same default, config and override precedence as feature flags.
+type RiskRuleStatus struct {
+	Rule        RiskRule `json:"rule"`
+	Description string   `json:"description"`
+	DefaultMode string   `json:"default_mode"`
+	Mode        string   `json:"mode"`
+	Source      string   `json:"source"`
+}
+
+// RiskBlockedError reports the enforced rules that flagged a transfer request
+type RiskBlockedError struct {
+	Rules []RiskRule
+}
+
+func (e *RiskBlockedError) Error() string {
+	names := make([]string, len(e.Rules))
+	for i, rule := range e.Rules {
+		names[i] = string(rule)
+	}
+	return fmt.Sprintf("%s: %s", ErrNWTransferRiskBlocked, strings.Join(names, ", "))
+}
+
+func (e *RiskBlockedError) Unwrap() error {
+	return ErrNWTransferRiskBlocked
+}
+
+// RiskInput describes the transfer request the rules judge
+type RiskInput struct {
+	UserID       uuid.UUID
+	Amount       decimal.Decimal
+	Currency     string
+	Direction    string
+	TransferType string
+}
+
+// RiskAssessment holds the verdicts of every rule that is not off on one transfer request. The
+// verdicts are not stored until Record is called.
+type RiskAssessment struct {
+	Evaluations []models.RiskEvaluation
+}
+
+// Blocking returns the enforced rules that flagged the request
+func (a *RiskAssessment) Blocking() []RiskRule {
+	var rules []RiskRule
+	for _, e := range a.Evaluations {
+		if e.Blocked {
+			rules = append(rules, RiskRule(e.Rule))
+		}
+	}
+	return rules
+}
+
+// RiskService evaluates risk rules against transfer requests. Each rule is off, in shadow mode,
+// where its verdict is recorded but never blocks, or enforced. A rule's mode comes from, in order
+// of precedence, a runtime override stored in the database, the RISK_RULE_MODES config and the
+// code default, so a rule can be moved from shadow to enforce without a deploy.
+type RiskService struct {
+	transfers   repositories.NorthwindTransferRepositoryInterface
+	evaluations repositories.RiskEvaluationRepositoryInterface
+	overrides   repositories.RiskRuleOverrideRepositoryInterface
+	limits      config.RiskConfig
+	definitions map[RiskRule]RiskRuleDefinition
+	configured  map[RiskRule]string
+	logger      *slog.Logger
+	now         func() time.Time
+}
+
+// NewRiskService creates a risk service. configured holds the modes from config, typically
+// produced by ParseRiskRuleModes; it may be nil.
+func NewRiskService(
+	transfers repositories.NorthwindTransferRepositoryInterface,
+	evaluations repositories.RiskEvaluationRepositoryInterface,
+	overrides repositories.RiskRuleOverrideRepositoryInterface,
+	limits config.RiskConfig,
+	configured map[RiskRule]string,
+	logger *slog.Logger,
+) *RiskService {
+	definitions := make(map[RiskRule]RiskRuleDefinition, len(riskRuleDefinitions))
+	for _, def := range riskRuleDefinitions {
+		definitions[def.Name] = def
+	}
+	return &RiskService{
+		transfers:   transfers,
+		evaluations: evaluations,
+		overrides:   overrides,
+		limits:      limits,
+		definitions: definitions,
+		configured:  configured,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// ParseRiskRuleModes parses a comma-separated list of rule=mode pairs, where mode is off, shadow
+// or enforce. Unknown rules are rejected so a typo in the environment does not silently leave a
+// rule in its default mode.
+func ParseRiskRuleModes(raw string) (map[RiskRule]string, error) {
+	configured := make(map[RiskRule]string)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, mode, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q: %w", entry, errRiskRuleConfigFormat)
+		}
+		rule := RiskRule(strings.TrimSpace(name))
+		if !knownRiskRule(rule) {
+			return nil, fmt.Errorf("%q: %w", rule, ErrUnknownRiskRule)
+		}
+		mode = strings.ToLower(strings.TrimSpace(mode))
+		if !validRiskRuleMode(mode) {
+			return nil, fmt.Errorf("%q: %w", entry, errRiskRuleConfigFormat)
+		}
+		configured[rule] = mode
+	}
+	return configured, nil
+}
+
+func knownRiskRule(rule RiskRule) bool {
+	for _, def := range riskRuleDefinitions {
+		if def.Name == rule {
+			return true
+		}
+	}
+	return false
+}
+
+func validRiskRuleMode(mode string) bool {
+	switch mode {
+	case models.RiskRuleModeOff, models.RiskRuleModeShadow, models.RiskRuleModeEnforce:
+		return true
+	}
+	return false
+}
+
+// Evaluate judges the request with every rule that is not off. A rule whose inputs cannot be
+// read is skipped with a warning rather than failing the request.
+func (s *RiskService) Evaluate(ctx context.Context, input RiskInput) *RiskAssessment {
+	modes := s.currentModes(ctx)
+	assessment := &RiskAssessment{}
+	var velocity *models.NorthwindTransferVelocity
+	for _, def := range riskRuleDefinitions {
+		mode := modes[def.Name]
+		if mode == models.RiskRuleModeOff {
+			continue
+		}
+		flagged, inputs, err := s.check(ctx, def.Name, input, &velocity)
+		if err != nil {
+			s.logger.Warn("Risk rule could not be evaluated; skipping it", "rule", def.Name, "user_id", input.UserID, "error", err)
+			continue
+		}
+
+		evaluation := models.RiskEvaluation{
+			UserID:  input.UserID,
+			Rule:    string(def.Name),
+			Mode:    mode,
+			Verdict: models.RiskVerdictPass,
+			Inputs:  inputs,
+		}
+		if flagged {
+			evaluation.Verdict = models.RiskVerdictFlag
+			evaluation.Blocked = mode == models.RiskRuleModeEnforce
+			s.logger.Info("Risk rule flagged transfer request",
+				"rule", def.Name,
+				"mode", mode,
+				"user_id", input.UserID,
+				"blocked", evaluation.Blocked,
+			)
+		}
+		assessment.Evaluations = append(assessment.Evaluations, evaluation)
+	}
+	return assessment
+}
+
+// check applies one rule to the request and returns whether it flags it, with the inputs it
+// looked at. The user's velocity is read once and shared by the velocity rules.
+func (s *RiskService) check(ctx context.Context, rule RiskRule, input RiskInput, velocity **models.NorthwindTransferVelocity) (bool, models.JSONBMap, error) {
+	amount := input.Amount.Round(2)
+	switch rule {
+	case RiskRuleLargeTransfer:
+		threshold := decimal.NewFromFloat(s.limits.LargeTransferAmount).Round(2)
+		return amount.GreaterThanOrEqual(threshold), models.JSONBMap{
+			"amount":    amount.StringFixed(2),
+			"currency":  input.Currency,
+			"threshold": threshold.StringFixed(2),
+		}, nil
+	}
+
+	if *velocity == nil {
+		v, err := s.transfers.GetUserVelocity(ctx, input.UserID, input.Currency, s.now().Add(-s.limits.VelocityWindow))
+		if err != nil {
+			return false, nil, err
+		}
+		*velocity = v
+	}
+	switch rule {
+	case RiskRuleVelocityCount:
+		// The request itself counts towards the limit
+		count := (*velocity).Count + 1
+		return count > int64(s.limits.VelocityMaxTransfers), models.JSONBMap{
+			"window":        s.limits.VelocityWindow.String(),
+			"transfers":     count,
+			"max_transfers": s.limits.VelocityMaxTransfers,
+		}, nil
+	case RiskRuleVelocityAmount:
+		total := (*velocity).Amount.Add(amount)
+		maxAmount := decimal.NewFromFloat(s.limits.VelocityMaxAmount).Round(2)
+		return total.GreaterThan(maxAmount), models.JSONBMap{
+			"window":       s.limits.VelocityWindow.String(),
+			"currency":     input.Currency,
+			"amount":       amount.StringFixed(2),
+			"total_amount": total.StringFixed(2),
+			"max_amount":   maxAmount.StringFixed(2),
+		}, nil
+	}
+	return false, nil, fmt.Errorf("%q: %w", rule, ErrUnknownRiskRule)
+}
+
+// Record stores the assessment's verdicts against the transfer, or without one when the request
+// was blocked. Failing to store them is logged and never fails the transfer.
+func (s *RiskService) Record(ctx context.Context, assessment *RiskAssessment, transferID *uuid.UUID) {
+	if assessment == nil || len(assessment.Evaluations) == 0 {
+		return
+	}
+	for i := range assessment.Evaluations {
+		assessment.Evaluations[i].TransferID = transferID
+	}
+	if err := s.evaluations.CreateBatch(ctx, assessment.Evaluations); err != nil {
+		s.logger.Error("Failed to record risk evaluations", "transfer_id", transferID, "error", err)
+	}
+}
+
+// ListRules returns every defined rule with its effective mode and where that mode comes from
+func (s *RiskService) ListRules(ctx context.Context) ([]RiskRuleStatus, error) {
+	overrides, err := s.overrides.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	overridden := make(map[RiskRule]string, len(overrides))
+	for _, o := range overrides {
+		overridden[RiskRule(o.Rule)] = o.Mode
+	}
+
+	statuses := make([]RiskRuleStatus, 0, len(riskRuleDefinitions))
+	for _, def := range riskRuleDefinitions {
+		status := RiskRuleStatus{Rule: def.Name, Description: def.Description, DefaultMode: def.DefaultMode}
+		status.Mode, status.Source = s.baseMode(def)
+		if mode, ok := overridden[def.Name]; ok {
+			status.Mode, status.Source = mode, FlagSourceOverride
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// SetMode overrides the rule's mode on every instance from its next evaluation
+func (s *RiskService) SetMode(ctx context.Context, rule RiskRule, mode string, adminID uuid.UUID) error {
+	if _, ok := s.definitions[rule]; !ok {
+		return ErrUnknownRiskRule
+	}
+	if !validRiskRuleMode(mode) {
+		return ErrInvalidRiskRuleMode
+	}
+	if err := s.overrides.Upsert(ctx, &models.RiskRuleOverride{Rule: string(rule), Mode: mode, UpdatedBy: &adminID}); err != nil {
+		return err
+	}
+	s.logger.Info("Risk rule mode overridden", "rule", rule, "mode", mode, "admin_id", adminID)
+	return nil
+}
+
+// ClearMode removes the runtime override so config or the default applies again
+func (s *RiskService) ClearMode(ctx context.Context, rule RiskRule) error {
+	if _, ok := s.definitions[rule]; !ok {
+		return ErrUnknownRiskRule
+	}
+	return s.overrides.Delete(ctx, string(rule))
+}
+
+// ListEvaluations returns a page of recorded verdicts matching filters and how many match
+func (s *RiskService) ListEvaluations(ctx context.Context, filters models.RiskEvaluationFilters, offset, limit int) ([]models.RiskEvaluation, int64, error) {
+	return s.evaluations.List(ctx, filters, offset, limit)
+}
+
+// SummarizeEvaluations counts the recorded verdicts matching filters per rule. Filtered on a
+// transfer status, the flagged count of shadow verdicts on COMPLETED transfers approximates the
+// false positives enforcing the rule would have caused.
+func (s *RiskService) SummarizeEvaluations(ctx context.Context, filters models.RiskEvaluationFilters) ([]models.RiskRuleSummary, error) {
+	return s.evaluations.SummarizeByRule(ctx, filters)
+}
+
+// currentModes returns every rule's effective mode. If overrides cannot be loaded the modes come
+// from config and defaults alone.
+func (s *RiskService) currentModes(ctx context.Context) map[RiskRule]string {
+	modes := make(map[RiskRule]string, len(riskRuleDefinitions))
+	for _, def := range riskRuleDefinitions {
+		modes[def.Name], _ = s.baseMode(def)
+	}
+	overrides, err := s.overrides.List(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to load risk rule overrides; using configured modes", "error", err)
+		return modes
+	}
+	for _, o := range overrides {
+		if _, ok := modes[RiskRule(o.Rule)]; ok {
+			modes[RiskRule(o.Rule)] = o.Mode
+		}
+	}
+	return modes
+}
+
+// baseMode is the rule's mode before runtime overrides: config if set, otherwise the default
+func (s *RiskService) baseMode(def RiskRuleDefinition) (string, string) {
+	if mode, ok := s.configured[def.Name]; ok {
+		return mode, FlagSourceConfig
+	}
+	return def.DefaultMode, FlagSourceDefault
+}
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/config"
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type riskTestEnv struct {
+	db        *gorm.DB
+	api       *fakeNorthwindTransferAPI
+	risk      *RiskService
+	transfers *NorthwindTransferService
+}
+
+// newRiskTestEnv wires the risk rules into transfer creation for every user, with only the
+// rules in modes switched on
+func newRiskTestEnv(t *testing.T, modes map[RiskRule]string) *riskTestEnv {
+	t.Helper()
+	env := &riskTestEnv{db: testfactory.NewDB(t), api: &fakeNorthwindTransferAPI{}}
+	server := httptest.NewServer(env.api)
+	t.Cleanup(server.Close)
+
+	configured := map[RiskRule]string{
+		RiskRuleVelocityCount:  models.RiskRuleModeOff,
+		RiskRuleVelocityAmount: models.RiskRuleModeOff,
+		RiskRuleLargeTransfer:  models.RiskRuleModeOff,
+	}
+	for rule, mode := range modes {
+		configured[rule] = mode
+	}
+	transferRepo := repositories.NewNorthwindTransferRepository(env.db)
+	env.risk = NewRiskService(transferRepo, repositories.NewRiskEvaluationRepository(env.db), repositories.NewRiskRuleOverrideRepository(env.db),
+		config.RiskConfig{VelocityWindow: time.Hour, VelocityMaxTransfers: 3, VelocityMaxAmount: 1000, LargeTransferAmount: 200},
+		configured, slog.Default())
+
+	env.transfers = NewNorthwindTransferService(northwind.NewClient(server.URL, "test-key"), transferRepo, nil, nil, slog.Default())
+	env.transfers.SetFeatureFlags(NewFeatureFlagService(repositories.NewFeatureFlagOverrideRepository(env.db),
+		map[FeatureFlag]int{FlagTransferRiskRules: 100}, slog.Default()))
+	env.transfers.SetRiskService(env.risk)
+	env.transfers.SetDuplicateWindow(0)
+	return env
+}
+
+func (env *riskTestEnv) evaluations(t *testing.T) []models.RiskEvaluation {
+	t.Helper()
+	var evaluations []models.RiskEvaluation
+	if err := env.db.Order("rule ASC").Find(&evaluations).Error; err != nil {
+		t.Fatalf("failed to load risk evaluations: %v", err)
+	}
+	return evaluations
+}
+
+func TestRiskService_CreateTransfer_Modes(t *testing.T) {
+	tests := []struct {
+		mode        string
+		wantBlocked bool
+		wantRecords int
+	}{
+		{models.RiskRuleModeOff, false, 0},
+		{models.RiskRuleModeShadow, false, 1},
+		{models.RiskRuleModeEnforce, true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			env := newRiskTestEnv(t, map[RiskRule]string{RiskRuleLargeTransfer: tt.mode})
+			// 250 is above the large transfer threshold of 200
+			resp, err := env.transfers.CreateTransfer(context.Background(), uuid.New(), newTestTransferRequest(models.NWTransferDirectionOutbound))
+
+			var blocked *RiskBlockedError
+			if tt.wantBlocked {
+				if !errors.As(err, &blocked) || !errors.Is(err, ErrNWTransferRiskBlocked) || len(blocked.Rules) != 1 || blocked.Rules[0] != RiskRuleLargeTransfer {
+					t.Fatalf("expected the transfer blocked by %s, got %v", RiskRuleLargeTransfer, err)
+				}
+				if n := env.api.calls(); n != 0 {
+					t.Errorf("expected no NorthWind calls for a blocked transfer, got %d", n)
+				}
+			} else if err != nil {
+				t.Fatalf("expected the transfer created, got %v", err)
+			}
+
+			evaluations := env.evaluations(t)
+			if len(evaluations) != tt.wantRecords {
+				t.Fatalf("expected %d recorded evaluations, got %+v", tt.wantRecords, evaluations)
+			}
+			if tt.wantRecords == 0 {
+				return
+			}
+			e := evaluations[0]
+			if e.Rule != string(RiskRuleLargeTransfer) || e.Mode != tt.mode || e.Verdict != models.RiskVerdictFlag || e.Blocked != tt.wantBlocked {
+				t.Errorf("unexpected evaluation %+v", e)
+			}
+			if e.Inputs["amount"] != "250.00" || e.Inputs["threshold"] != "200.00" {
+				t.Errorf("expected the inputs snapshot recorded, got %+v", e.Inputs)
+			}
+			switch {
+			case tt.wantBlocked && e.TransferID != nil:
+				t.Errorf("expected a blocked evaluation to have no transfer, got %s", e.TransferID)
+			case !tt.wantBlocked && (e.TransferID == nil || *e.TransferID != resp.Transfer.ID):
+				t.Errorf("expected the shadow verdict recorded against transfer %s, got %v", resp.Transfer.ID, e.TransferID)
+			}
+		})
+	}
+}
+
+func TestRiskService_CreateTransfer_FlagOffSkipsRules(t *testing.T) {
+	env := newRiskTestEnv(t, map[RiskRule]string{RiskRuleLargeTransfer: models.RiskRuleModeEnforce})
+	env.transfers.SetFeatureFlags(NewFeatureFlagService(repositories.NewFeatureFlagOverrideRepository(env.db), nil, slog.Default()))
+
+	if _, err := env.transfers.CreateTransfer(context.Background(), uuid.New(), newTestTransferRequest(models.NWTransferDirectionOutbound)); err != nil {
+		t.Fatalf("expected the transfer created without risk rules, got %v", err)
+	}
+	if evaluations := env.evaluations(t); len(evaluations) != 0 {
+		t.Errorf("expected no evaluations while transfer_risk_rules is off, got %+v", evaluations)
+	}
+}
+
+func TestRiskService_SetMode_AppliesWithoutRestart(t *testing.T) {
+	env := newRiskTestEnv(t, map[RiskRule]string{RiskRuleLargeTransfer: models.RiskRuleModeShadow})
+	ctx := context.Background()
+	userID := uuid.New()
+	create := func(reference string) error {
+		req := newTestTransferRequest(models.NWTransferDirectionOutbound)
+		req.ReferenceNumber = reference
+		_, err := env.transfers.CreateTransfer(ctx, userID, req)
+		return err
+	}
+
+	if err := create("REF-1"); err != nil {
+		t.Fatalf("expected the shadow rule not to block, got %v", err)
+	}
+	if err := env.risk.SetMode(ctx, RiskRuleLargeTransfer, models.RiskRuleModeEnforce, uuid.New()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := create("REF-2"); !errors.Is(err, ErrNWTransferRiskBlocked) {
+		t.Fatalf("expected the enforced rule to block, got %v", err)
+	}
+
+	rules, err := env.risk.ListRules(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, rule := range rules {
+		if rule.Rule == RiskRuleLargeTransfer && (rule.Mode != models.RiskRuleModeEnforce || rule.Source != FlagSourceOverride) {
+			t.Errorf("expected the override reported, got %+v", rule)
+		}
+	}
+
+	if err := env.risk.ClearMode(ctx, RiskRuleLargeTransfer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := create("REF-3"); err != nil {
+		t.Fatalf("expected the configured shadow mode to apply again, got %v", err)
+	}
+
+	if err := env.risk.SetMode(ctx, "unknown_rule", models.RiskRuleModeEnforce, uuid.New()); !errors.Is(err, ErrUnknownRiskRule) {
+		t.Errorf("expected ErrUnknownRiskRule, got %v", err)
+	}
+	if err := env.risk.SetMode(ctx, RiskRuleLargeTransfer, "block", uuid.New()); !errors.Is(err, ErrInvalidRiskRuleMode) {
+		t.Errorf("expected ErrInvalidRiskRuleMode, got %v", err)
+	}
+}
+
+func TestRiskService_Evaluate_VelocityRules(t *testing.T) {
+	env := newRiskTestEnv(t, map[RiskRule]string{
+		RiskRuleVelocityCount:  models.RiskRuleModeShadow,
+		RiskRuleVelocityAmount: models.RiskRuleModeShadow,
+	})
+	userID := uuid.New()
+	testfactory.NWTransfer(t, env.db, testfactory.WithUser(userID), testfactory.WithAmount(400))
+	testfactory.NWTransfer(t, env.db, testfactory.WithUser(userID), testfactory.WithAmount(400))
+	// Outside the window, failed, or another user's: not counted
+	testfactory.NWTransfer(t, env.db, testfactory.WithUser(userID), testfactory.WithAmount(900), testfactory.WithCreatedAt(time.Now().Add(-2*time.Hour)))
+	testfactory.NWTransfer(t, env.db, testfactory.WithUser(userID), testfactory.WithAmount(900), testfactory.WithStatus(models.NWTransferStatusFailed))
+	testfactory.NWTransfer(t, env.db, testfactory.WithAmount(900))
+
+	input := RiskInput{UserID: userID, Amount: decimal.NewFromInt(150), Currency: "USD"}
+	assessment := env.risk.Evaluate(context.Background(), input)
+	if len(assessment.Evaluations) != 2 || len(assessment.Blocking()) != 0 {
+		t.Fatalf("expected two shadow verdicts, got %+v", assessment.Evaluations)
+	}
+	verdicts := map[string]models.RiskEvaluation{}
+	for _, e := range assessment.Evaluations {
+		verdicts[e.Rule] = e
+	}
+	// Third transfer within a limit of three; 950 within a limit of 1000
+	if e := verdicts[string(RiskRuleVelocityCount)]; e.Verdict != models.RiskVerdictPass || e.Inputs["transfers"] != int64(3) {
+		t.Errorf("expected velocity_count to pass at 3 transfers, got %+v", e)
+	}
+	if e := verdicts[string(RiskRuleVelocityAmount)]; e.Verdict != models.RiskVerdictPass || e.Inputs["total_amount"] != "950.00" {
+		t.Errorf("expected velocity_amount to pass at 950.00, got %+v", e)
+	}
+
+	testfactory.NWTransfer(t, env.db, testfactory.WithUser(userID), testfactory.WithAmount(100))
+	for _, e := range env.risk.Evaluate(context.Background(), input).Evaluations {
+		if e.Verdict != models.RiskVerdictFlag || e.Blocked {
+			t.Errorf("expected %s flagged in shadow mode, got %+v", e.Rule, e)
+		}
+	}
+}
+
+func TestRiskService_ListEvaluations_FiltersAndSummary(t *testing.T) {
+	env := newRiskTestEnv(t, map[RiskRule]string{
+		RiskRuleLargeTransfer: models.RiskRuleModeShadow,
+		RiskRuleVelocityCount: models.RiskRuleModeShadow,
+	})
+	ctx := context.Background()
+	userID := uuid.New()
+	for _, reference := range []string{"REF-1", "REF-2"} {
+		req := newTestTransferRequest(models.NWTransferDirectionOutbound)
+		req.ReferenceNumber = reference
+		if _, err := env.transfers.CreateTransfer(ctx, userID, req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	var completed models.NorthwindTransfer
+	if err := env.db.Where("reference_number = ?", "REF-1").First(&completed).Error; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := env.db.Model(&completed).Update("status", models.NWTransferStatusCompleted).Error; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	flagged, total, err := env.risk.ListEvaluations(ctx, models.RiskEvaluationFilters{Verdict: models.RiskVerdictFlag}, 0, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 2 || len(flagged) != 2 || flagged[0].Rule != string(RiskRuleLargeTransfer) {
+		t.Errorf("expected the two large transfer flags, got %d: %+v", total, flagged)
+	}
+
+	summary, err := env.risk.SummarizeEvaluations(ctx, models.RiskEvaluationFilters{TransferStatus: models.NWTransferStatusCompleted})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []models.RiskRuleSummary{
+		{Rule: string(RiskRuleLargeTransfer), Evaluated: 1, Flagged: 1},
+		{Rule: string(RiskRuleVelocityCount), Evaluated: 1},
+	}
+	if len(summary) != len(want) || summary[0] != want[0] || summary[1] != want[1] {
+		t.Errorf("expected %+v, got %+v", want, summary)
+	}
+}
+
+func TestParseRiskRuleModes(t *testing.T) {
+	modes, err := ParseRiskRuleModes(" velocity_count=enforce, large_transfer=OFF ,")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(modes) != 2 || modes[RiskRuleVelocityCount] != models.RiskRuleModeEnforce || modes[RiskRuleLargeTransfer] != models.RiskRuleModeOff {
+		t.Errorf("unexpected modes %+v", modes)
+	}
+	for _, raw := range []string{"velocity_count", "velocity_count=block", "velocity_cuont=shadow"} {
+		if _, err := ParseRiskRuleModes(raw); err == nil {
+			t.Errorf("expected %q to be rejected", raw)
+		}
+	}
+}
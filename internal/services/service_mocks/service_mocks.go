@@ -40,47 +40,47 @@ func (m *MockAccountAssociationServiceInterface) EXPECT() *MockAccountAssociatio
 }
 
 // CreateAccountForCustomer mocks base method.
-func (m *MockAccountAssociationServiceInterface) CreateAccountForCustomer(customerID, performedBy uuid.UUID, accountType, ipAddress, userAgent string) (*models.Account, error) {
+func (m *MockAccountAssociationServiceInterface) CreateAccountForCustomer(ctx context.Context, customerID, performedBy uuid.UUID, accountType, ipAddress, userAgent string) (*models.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateAccountForCustomer", customerID, performedBy, accountType, ipAddress, userAgent)
+	ret := m.ctrl.Call(m, "CreateAccountForCustomer", ctx, customerID, performedBy, accountType, ipAddress, userAgent)
 	ret0, _ := ret[0].(*models.Account)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateAccountForCustomer indicates an expected call of CreateAccountForCustomer.
-func (mr *MockAccountAssociationServiceInterfaceMockRecorder) CreateAccountForCustomer(customerID, performedBy, accountType, ipAddress, userAgent interface{}) *gomock.Call {
+func (mr *MockAccountAssociationServiceInterfaceMockRecorder) CreateAccountForCustomer(ctx, customerID, performedBy, accountType, ipAddress, userAgent interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountForCustomer", reflect.TypeOf((*MockAccountAssociationServiceInterface)(nil).CreateAccountForCustomer), customerID, performedBy, accountType, ipAddress, userAgent)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountForCustomer", reflect.TypeOf((*MockAccountAssociationServiceInterface)(nil).CreateAccountForCustomer), ctx, customerID, performedBy, accountType, ipAddress, userAgent)
 }
 
 // GetCustomerAccounts mocks base method.
-func (m *MockAccountAssociationServiceInterface) GetCustomerAccounts(customerID uuid.UUID) ([]*models.Account, error) {
+func (m *MockAccountAssociationServiceInterface) GetCustomerAccounts(ctx context.Context, customerID uuid.UUID) ([]*models.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCustomerAccounts", customerID)
+	ret := m.ctrl.Call(m, "GetCustomerAccounts", ctx, customerID)
 	ret0, _ := ret[0].([]*models.Account)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCustomerAccounts indicates an expected call of GetCustomerAccounts.
-func (mr *MockAccountAssociationServiceInterfaceMockRecorder) GetCustomerAccounts(customerID interface{}) *gomock.Call {
+func (mr *MockAccountAssociationServiceInterfaceMockRecorder) GetCustomerAccounts(ctx, customerID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerAccounts", reflect.TypeOf((*MockAccountAssociationServiceInterface)(nil).GetCustomerAccounts), customerID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerAccounts", reflect.TypeOf((*MockAccountAssociationServiceInterface)(nil).GetCustomerAccounts), ctx, customerID)
 }
 
 // TransferAccountOwnership mocks base method.
-func (m *MockAccountAssociationServiceInterface) TransferAccountOwnership(accountID, fromCustomerID, toCustomerID, performedBy uuid.UUID, ipAddress, userAgent string) error {
+func (m *MockAccountAssociationServiceInterface) TransferAccountOwnership(ctx context.Context, accountID, fromCustomerID, toCustomerID, performedBy uuid.UUID, ipAddress, userAgent string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TransferAccountOwnership", accountID, fromCustomerID, toCustomerID, performedBy, ipAddress, userAgent)
+	ret := m.ctrl.Call(m, "TransferAccountOwnership", ctx, accountID, fromCustomerID, toCustomerID, performedBy, ipAddress, userAgent)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // TransferAccountOwnership indicates an expected call of TransferAccountOwnership.
-func (mr *MockAccountAssociationServiceInterfaceMockRecorder) TransferAccountOwnership(accountID, fromCustomerID, toCustomerID, performedBy, ipAddress, userAgent interface{}) *gomock.Call {
+func (mr *MockAccountAssociationServiceInterfaceMockRecorder) TransferAccountOwnership(ctx, accountID, fromCustomerID, toCustomerID, performedBy, ipAddress, userAgent interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferAccountOwnership", reflect.TypeOf((*MockAccountAssociationServiceInterface)(nil).TransferAccountOwnership), accountID, fromCustomerID, toCustomerID, performedBy, ipAddress, userAgent)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferAccountOwnership", reflect.TypeOf((*MockAccountAssociationServiceInterface)(nil).TransferAccountOwnership), ctx, accountID, fromCustomerID, toCustomerID, performedBy, ipAddress, userAgent)
 }
 
 // MockAccountServiceInterface is a mock of AccountServiceInterface interface.
@@ -107,32 +107,32 @@ func (m *MockAccountServiceInterface) EXPECT() *MockAccountServiceInterfaceMockR
 }
 
 // CloseAccount mocks base method.
-func (m *MockAccountServiceInterface) CloseAccount(accountID, userID uuid.UUID) error {
+func (m *MockAccountServiceInterface) CloseAccount(ctx context.Context, accountID, userID uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CloseAccount", accountID, userID)
+	ret := m.ctrl.Call(m, "CloseAccount", ctx, accountID, userID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CloseAccount indicates an expected call of CloseAccount.
-func (mr *MockAccountServiceInterfaceMockRecorder) CloseAccount(accountID, userID interface{}) *gomock.Call {
+func (mr *MockAccountServiceInterfaceMockRecorder) CloseAccount(ctx, accountID, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAccount", reflect.TypeOf((*MockAccountServiceInterface)(nil).CloseAccount), accountID, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAccount", reflect.TypeOf((*MockAccountServiceInterface)(nil).CloseAccount), ctx, accountID, userID)
 }
 
 // CreateAccount mocks base method.
-func (m *MockAccountServiceInterface) CreateAccount(userID uuid.UUID, accountType string, initialDeposit decimal.Decimal) (*models.Account, error) {
+func (m *MockAccountServiceInterface) CreateAccount(ctx context.Context, userID uuid.UUID, accountType string, initialDeposit decimal.Decimal) (*models.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateAccount", userID, accountType, initialDeposit)
+	ret := m.ctrl.Call(m, "CreateAccount", ctx, userID, accountType, initialDeposit)
 	ret0, _ := ret[0].(*models.Account)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateAccount indicates an expected call of CreateAccount.
-func (mr *MockAccountServiceInterfaceMockRecorder) CreateAccount(userID, accountType, initialDeposit interface{}) *gomock.Call {
+func (mr *MockAccountServiceInterfaceMockRecorder) CreateAccount(ctx, userID, accountType, initialDeposit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockAccountServiceInterface)(nil).CreateAccount), userID, accountType, initialDeposit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockAccountServiceInterface)(nil).CreateAccount), ctx, userID, accountType, initialDeposit)
 }
 
 // CreateAccountsForNewUser mocks base method.
@@ -150,18 +150,18 @@ func (mr *MockAccountServiceInterfaceMockRecorder) CreateAccountsForNewUser(user
 }
 
 // GetAccountByID mocks base method.
-func (m *MockAccountServiceInterface) GetAccountByID(accountID uuid.UUID, userID *uuid.UUID) (*models.Account, error) {
+func (m *MockAccountServiceInterface) GetAccountByID(ctx context.Context, accountID uuid.UUID, userID *uuid.UUID) (*models.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccountByID", accountID, userID)
+	ret := m.ctrl.Call(m, "GetAccountByID", ctx, accountID, userID)
 	ret0, _ := ret[0].(*models.Account)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAccountByID indicates an expected call of GetAccountByID.
-func (mr *MockAccountServiceInterfaceMockRecorder) GetAccountByID(accountID, userID interface{}) *gomock.Call {
+func (mr *MockAccountServiceInterfaceMockRecorder) GetAccountByID(ctx, accountID, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByID", reflect.TypeOf((*MockAccountServiceInterface)(nil).GetAccountByID), accountID, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByID", reflect.TypeOf((*MockAccountServiceInterface)(nil).GetAccountByID), ctx, accountID, userID)
 }
 
 // GetAccountByNumber mocks base method.
@@ -180,9 +180,9 @@ func (mr *MockAccountServiceInterfaceMockRecorder) GetAccountByNumber(accountNum
 }
 
 // GetAccountTransactions mocks base method.
-func (m *MockAccountServiceInterface) GetAccountTransactions(accountID uuid.UUID, userID *uuid.UUID, offset, limit int) ([]models.Transaction, int64, error) {
+func (m *MockAccountServiceInterface) GetAccountTransactions(ctx context.Context, accountID uuid.UUID, userID *uuid.UUID, offset, limit int) ([]models.Transaction, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccountTransactions", accountID, userID, offset, limit)
+	ret := m.ctrl.Call(m, "GetAccountTransactions", ctx, accountID, userID, offset, limit)
 	ret0, _ := ret[0].([]models.Transaction)
 	ret1, _ := ret[1].(int64)
 	ret2, _ := ret[2].(error)
@@ -190,9 +190,9 @@ func (m *MockAccountServiceInterface) GetAccountTransactions(accountID uuid.UUID
 }
 
 // GetAccountTransactions indicates an expected call of GetAccountTransactions.
-func (mr *MockAccountServiceInterfaceMockRecorder) GetAccountTransactions(accountID, userID, offset, limit interface{}) *gomock.Call {
+func (mr *MockAccountServiceInterfaceMockRecorder) GetAccountTransactions(ctx, accountID, userID, offset, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountTransactions", reflect.TypeOf((*MockAccountServiceInterface)(nil).GetAccountTransactions), accountID, userID, offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountTransactions", reflect.TypeOf((*MockAccountServiceInterface)(nil).GetAccountTransactions), ctx, accountID, userID, offset, limit)
 }
 
 // GetAllAccounts mocks base method.
@@ -212,18 +212,18 @@ func (mr *MockAccountServiceInterfaceMockRecorder) GetAllAccounts(filters, offse
 }
 
 // GetRecentTransactions mocks base method.
-func (m *MockAccountServiceInterface) GetRecentTransactions(accountID uuid.UUID, userID *uuid.UUID, limit int) ([]models.Transaction, error) {
+func (m *MockAccountServiceInterface) GetRecentTransactions(ctx context.Context, accountID uuid.UUID, userID *uuid.UUID, limit int) ([]models.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRecentTransactions", accountID, userID, limit)
+	ret := m.ctrl.Call(m, "GetRecentTransactions", ctx, accountID, userID, limit)
 	ret0, _ := ret[0].([]models.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetRecentTransactions indicates an expected call of GetRecentTransactions.
-func (mr *MockAccountServiceInterfaceMockRecorder) GetRecentTransactions(accountID, userID, limit interface{}) *gomock.Call {
+func (mr *MockAccountServiceInterfaceMockRecorder) GetRecentTransactions(ctx, accountID, userID, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecentTransactions", reflect.TypeOf((*MockAccountServiceInterface)(nil).GetRecentTransactions), accountID, userID, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecentTransactions", reflect.TypeOf((*MockAccountServiceInterface)(nil).GetRecentTransactions), ctx, accountID, userID, limit)
 }
 
 // GetUserAccounts mocks base method.
@@ -258,18 +258,18 @@ func (mr *MockAccountServiceInterfaceMockRecorder) GetUserTransfers(userID, filt
 }
 
 // PerformTransaction mocks base method.
-func (m *MockAccountServiceInterface) PerformTransaction(accountID uuid.UUID, amount decimal.Decimal, transactionType, description string, userID *uuid.UUID) (*models.Transaction, error) {
+func (m *MockAccountServiceInterface) PerformTransaction(ctx context.Context, accountID uuid.UUID, amount decimal.Decimal, transactionType, description string, userID *uuid.UUID) (*models.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PerformTransaction", accountID, amount, transactionType, description, userID)
+	ret := m.ctrl.Call(m, "PerformTransaction", ctx, accountID, amount, transactionType, description, userID)
 	ret0, _ := ret[0].(*models.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // PerformTransaction indicates an expected call of PerformTransaction.
-func (mr *MockAccountServiceInterfaceMockRecorder) PerformTransaction(accountID, amount, transactionType, description, userID interface{}) *gomock.Call {
+func (mr *MockAccountServiceInterfaceMockRecorder) PerformTransaction(ctx, accountID, amount, transactionType, description, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PerformTransaction", reflect.TypeOf((*MockAccountServiceInterface)(nil).PerformTransaction), accountID, amount, transactionType, description, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PerformTransaction", reflect.TypeOf((*MockAccountServiceInterface)(nil).PerformTransaction), ctx, accountID, amount, transactionType, description, userID)
 }
 
 // TransferBetweenAccounts mocks base method.
@@ -288,18 +288,18 @@ func (mr *MockAccountServiceInterfaceMockRecorder) TransferBetweenAccounts(fromA
 }
 
 // UpdateAccountStatus mocks base method.
-func (m *MockAccountServiceInterface) UpdateAccountStatus(accountID uuid.UUID, userID *uuid.UUID, status string) (*models.Account, error) {
+func (m *MockAccountServiceInterface) UpdateAccountStatus(ctx context.Context, accountID uuid.UUID, userID *uuid.UUID, status string) (*models.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateAccountStatus", accountID, userID, status)
+	ret := m.ctrl.Call(m, "UpdateAccountStatus", ctx, accountID, userID, status)
 	ret0, _ := ret[0].(*models.Account)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateAccountStatus indicates an expected call of UpdateAccountStatus.
-func (mr *MockAccountServiceInterfaceMockRecorder) UpdateAccountStatus(accountID, userID, status interface{}) *gomock.Call {
+func (mr *MockAccountServiceInterfaceMockRecorder) UpdateAccountStatus(ctx, accountID, userID, status interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountStatus", reflect.TypeOf((*MockAccountServiceInterface)(nil).UpdateAccountStatus), accountID, userID, status)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountStatus", reflect.TypeOf((*MockAccountServiceInterface)(nil).UpdateAccountStatus), ctx, accountID, userID, status)
 }
 
 // MockAccountSummaryServiceInterface is a mock of AccountSummaryServiceInterface interface.
@@ -326,18 +326,18 @@ func (m *MockAccountSummaryServiceInterface) EXPECT() *MockAccountSummaryService
 }
 
 // GetAccountSummary mocks base method.
-func (m *MockAccountSummaryServiceInterface) GetAccountSummary(requestorID uuid.UUID, targetUserID *uuid.UUID, isAdmin bool) (*models.UserAccountSummary, error) {
+func (m *MockAccountSummaryServiceInterface) GetAccountSummary(ctx context.Context, requestorID uuid.UUID, targetUserID *uuid.UUID, isAdmin bool) (*models.UserAccountSummary, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccountSummary", requestorID, targetUserID, isAdmin)
+	ret := m.ctrl.Call(m, "GetAccountSummary", ctx, requestorID, targetUserID, isAdmin)
 	ret0, _ := ret[0].(*models.UserAccountSummary)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAccountSummary indicates an expected call of GetAccountSummary.
-func (mr *MockAccountSummaryServiceInterfaceMockRecorder) GetAccountSummary(requestorID, targetUserID, isAdmin interface{}) *gomock.Call {
+func (mr *MockAccountSummaryServiceInterfaceMockRecorder) GetAccountSummary(ctx, requestorID, targetUserID, isAdmin interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountSummary", reflect.TypeOf((*MockAccountSummaryServiceInterface)(nil).GetAccountSummary), requestorID, targetUserID, isAdmin)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountSummary", reflect.TypeOf((*MockAccountSummaryServiceInterface)(nil).GetAccountSummary), ctx, requestorID, targetUserID, isAdmin)
 }
 
 // MockAuditServiceInterface is a mock of AuditServiceInterface interface.
@@ -681,9 +681,9 @@ func (m *MockCustomerProfileServiceInterface) EXPECT() *MockCustomerProfileServi
 }
 
 // CreateCustomer mocks base method.
-func (m *MockCustomerProfileServiceInterface) CreateCustomer(email, firstName, lastName, role string) (*models.User, string, error) {
+func (m *MockCustomerProfileServiceInterface) CreateCustomer(ctx context.Context, email, firstName, lastName, role string) (*models.User, string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateCustomer", email, firstName, lastName, role)
+	ret := m.ctrl.Call(m, "CreateCustomer", ctx, email, firstName, lastName, role)
 	ret0, _ := ret[0].(*models.User)
 	ret1, _ := ret[1].(string)
 	ret2, _ := ret[2].(error)
@@ -691,66 +691,66 @@ func (m *MockCustomerProfileServiceInterface) CreateCustomer(email, firstName, l
 }
 
 // CreateCustomer indicates an expected call of CreateCustomer.
-func (mr *MockCustomerProfileServiceInterfaceMockRecorder) CreateCustomer(email, firstName, lastName, role interface{}) *gomock.Call {
+func (mr *MockCustomerProfileServiceInterfaceMockRecorder) CreateCustomer(ctx, email, firstName, lastName, role interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCustomer", reflect.TypeOf((*MockCustomerProfileServiceInterface)(nil).CreateCustomer), email, firstName, lastName, role)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCustomer", reflect.TypeOf((*MockCustomerProfileServiceInterface)(nil).CreateCustomer), ctx, email, firstName, lastName, role)
 }
 
 // DeleteCustomer mocks base method.
-func (m *MockCustomerProfileServiceInterface) DeleteCustomer(customerID uuid.UUID, reason string) error {
+func (m *MockCustomerProfileServiceInterface) DeleteCustomer(ctx context.Context, customerID uuid.UUID, reason string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteCustomer", customerID, reason)
+	ret := m.ctrl.Call(m, "DeleteCustomer", ctx, customerID, reason)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteCustomer indicates an expected call of DeleteCustomer.
-func (mr *MockCustomerProfileServiceInterfaceMockRecorder) DeleteCustomer(customerID, reason interface{}) *gomock.Call {
+func (mr *MockCustomerProfileServiceInterfaceMockRecorder) DeleteCustomer(ctx, customerID, reason interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCustomer", reflect.TypeOf((*MockCustomerProfileServiceInterface)(nil).DeleteCustomer), customerID, reason)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCustomer", reflect.TypeOf((*MockCustomerProfileServiceInterface)(nil).DeleteCustomer), ctx, customerID, reason)
 }
 
 // GetCustomerProfile mocks base method.
-func (m *MockCustomerProfileServiceInterface) GetCustomerProfile(customerID uuid.UUID) (*models.User, error) {
+func (m *MockCustomerProfileServiceInterface) GetCustomerProfile(ctx context.Context, customerID uuid.UUID) (*models.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCustomerProfile", customerID)
+	ret := m.ctrl.Call(m, "GetCustomerProfile", ctx, customerID)
 	ret0, _ := ret[0].(*models.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCustomerProfile indicates an expected call of GetCustomerProfile.
-func (mr *MockCustomerProfileServiceInterfaceMockRecorder) GetCustomerProfile(customerID interface{}) *gomock.Call {
+func (mr *MockCustomerProfileServiceInterfaceMockRecorder) GetCustomerProfile(ctx, customerID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerProfile", reflect.TypeOf((*MockCustomerProfileServiceInterface)(nil).GetCustomerProfile), customerID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomerProfile", reflect.TypeOf((*MockCustomerProfileServiceInterface)(nil).GetCustomerProfile), ctx, customerID)
 }
 
 // UpdateCustomerEmail mocks base method.
-func (m *MockCustomerProfileServiceInterface) UpdateCustomerEmail(customerID uuid.UUID, newEmail string) error {
+func (m *MockCustomerProfileServiceInterface) UpdateCustomerEmail(ctx context.Context, customerID uuid.UUID, newEmail string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateCustomerEmail", customerID, newEmail)
+	ret := m.ctrl.Call(m, "UpdateCustomerEmail", ctx, customerID, newEmail)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateCustomerEmail indicates an expected call of UpdateCustomerEmail.
-func (mr *MockCustomerProfileServiceInterfaceMockRecorder) UpdateCustomerEmail(customerID, newEmail interface{}) *gomock.Call {
+func (mr *MockCustomerProfileServiceInterfaceMockRecorder) UpdateCustomerEmail(ctx, customerID, newEmail interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCustomerEmail", reflect.TypeOf((*MockCustomerProfileServiceInterface)(nil).UpdateCustomerEmail), customerID, newEmail)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCustomerEmail", reflect.TypeOf((*MockCustomerProfileServiceInterface)(nil).UpdateCustomerEmail), ctx, customerID, newEmail)
 }
 
 // UpdateCustomerProfile mocks base method.
-func (m *MockCustomerProfileServiceInterface) UpdateCustomerProfile(customerID uuid.UUID, updates map[string]interface{}) error {
+func (m *MockCustomerProfileServiceInterface) UpdateCustomerProfile(ctx context.Context, customerID uuid.UUID, updates map[string]interface{}) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateCustomerProfile", customerID, updates)
+	ret := m.ctrl.Call(m, "UpdateCustomerProfile", ctx, customerID, updates)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateCustomerProfile indicates an expected call of UpdateCustomerProfile.
-func (mr *MockCustomerProfileServiceInterfaceMockRecorder) UpdateCustomerProfile(customerID, updates interface{}) *gomock.Call {
+func (mr *MockCustomerProfileServiceInterfaceMockRecorder) UpdateCustomerProfile(ctx, customerID, updates interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCustomerProfile", reflect.TypeOf((*MockCustomerProfileServiceInterface)(nil).UpdateCustomerProfile), customerID, updates)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCustomerProfile", reflect.TypeOf((*MockCustomerProfileServiceInterface)(nil).UpdateCustomerProfile), ctx, customerID, updates)
 }
 
 // MockCustomerSearchServiceInterface is a mock of CustomerSearchServiceInterface interface.
@@ -777,9 +777,9 @@ func (m *MockCustomerSearchServiceInterface) EXPECT() *MockCustomerSearchService
 }
 
 // SearchCustomers mocks base method.
-func (m *MockCustomerSearchServiceInterface) SearchCustomers(query string, searchType models.SearchType, offset, limit int) ([]*models.CustomerSearchResult, int64, error) {
+func (m *MockCustomerSearchServiceInterface) SearchCustomers(ctx context.Context, query string, searchType models.SearchType, offset, limit int) ([]*models.CustomerSearchResult, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SearchCustomers", query, searchType, offset, limit)
+	ret := m.ctrl.Call(m, "SearchCustomers", ctx, query, searchType, offset, limit)
 	ret0, _ := ret[0].([]*models.CustomerSearchResult)
 	ret1, _ := ret[1].(int64)
 	ret2, _ := ret[2].(error)
@@ -787,9 +787,9 @@ func (m *MockCustomerSearchServiceInterface) SearchCustomers(query string, searc
 }
 
 // SearchCustomers indicates an expected call of SearchCustomers.
-func (mr *MockCustomerSearchServiceInterfaceMockRecorder) SearchCustomers(query, searchType, offset, limit interface{}) *gomock.Call {
+func (mr *MockCustomerSearchServiceInterfaceMockRecorder) SearchCustomers(ctx, query, searchType, offset, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchCustomers", reflect.TypeOf((*MockCustomerSearchServiceInterface)(nil).SearchCustomers), query, searchType, offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchCustomers", reflect.TypeOf((*MockCustomerSearchServiceInterface)(nil).SearchCustomers), ctx, query, searchType, offset, limit)
 }
 
 // MockAccountMetricsServiceInterface is a mock of AccountMetricsServiceInterface interface.
@@ -816,33 +816,33 @@ func (m *MockAccountMetricsServiceInterface) EXPECT() *MockAccountMetricsService
 }
 
 // GetAccountMetrics mocks base method.
-func (m *MockAccountMetricsServiceInterface) GetAccountMetrics(requestorID, accountID uuid.UUID, startDate, endDate *time.Time, isAdmin bool) (*models.AccountMetrics, error) {
+func (m *MockAccountMetricsServiceInterface) GetAccountMetrics(ctx context.Context, requestorID, accountID uuid.UUID, startDate, endDate *time.Time, isAdmin bool) (*models.AccountMetrics, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccountMetrics", requestorID, accountID, startDate, endDate, isAdmin)
+	ret := m.ctrl.Call(m, "GetAccountMetrics", ctx, requestorID, accountID, startDate, endDate, isAdmin)
 	ret0, _ := ret[0].(*models.AccountMetrics)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAccountMetrics indicates an expected call of GetAccountMetrics.
-func (mr *MockAccountMetricsServiceInterfaceMockRecorder) GetAccountMetrics(requestorID, accountID, startDate, endDate, isAdmin interface{}) *gomock.Call {
+func (mr *MockAccountMetricsServiceInterfaceMockRecorder) GetAccountMetrics(ctx, requestorID, accountID, startDate, endDate, isAdmin interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountMetrics", reflect.TypeOf((*MockAccountMetricsServiceInterface)(nil).GetAccountMetrics), requestorID, accountID, startDate, endDate, isAdmin)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountMetrics", reflect.TypeOf((*MockAccountMetricsServiceInterface)(nil).GetAccountMetrics), ctx, requestorID, accountID, startDate, endDate, isAdmin)
 }
 
 // GetUserAggregateMetrics mocks base method.
-func (m *MockAccountMetricsServiceInterface) GetUserAggregateMetrics(requestorID, targetUserID uuid.UUID, startDate, endDate *time.Time, isAdmin bool) (*models.UserAggregateMetrics, error) {
+func (m *MockAccountMetricsServiceInterface) GetUserAggregateMetrics(ctx context.Context, requestorID, targetUserID uuid.UUID, startDate, endDate *time.Time, isAdmin bool) (*models.UserAggregateMetrics, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserAggregateMetrics", requestorID, targetUserID, startDate, endDate, isAdmin)
+	ret := m.ctrl.Call(m, "GetUserAggregateMetrics", ctx, requestorID, targetUserID, startDate, endDate, isAdmin)
 	ret0, _ := ret[0].(*models.UserAggregateMetrics)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUserAggregateMetrics indicates an expected call of GetUserAggregateMetrics.
-func (mr *MockAccountMetricsServiceInterfaceMockRecorder) GetUserAggregateMetrics(requestorID, targetUserID, startDate, endDate, isAdmin interface{}) *gomock.Call {
+func (mr *MockAccountMetricsServiceInterfaceMockRecorder) GetUserAggregateMetrics(ctx, requestorID, targetUserID, startDate, endDate, isAdmin interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserAggregateMetrics", reflect.TypeOf((*MockAccountMetricsServiceInterface)(nil).GetUserAggregateMetrics), requestorID, targetUserID, startDate, endDate, isAdmin)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserAggregateMetrics", reflect.TypeOf((*MockAccountMetricsServiceInterface)(nil).GetUserAggregateMetrics), ctx, requestorID, targetUserID, startDate, endDate, isAdmin)
 }
 
 // MockMetricsRecorderInterface is a mock of MetricsRecorderInterface interface.
@@ -928,18 +928,18 @@ func (m *MockStatementServiceInterface) EXPECT() *MockStatementServiceInterfaceM
 }
 
 // GenerateStatement mocks base method.
-func (m *MockStatementServiceInterface) GenerateStatement(requestorID, accountID uuid.UUID, periodType string, year, period int, isAdmin bool) (*models.AccountStatement, error) {
+func (m *MockStatementServiceInterface) GenerateStatement(ctx context.Context, requestorID, accountID uuid.UUID, periodType string, year, period int, isAdmin bool) (*models.AccountStatement, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GenerateStatement", requestorID, accountID, periodType, year, period, isAdmin)
+	ret := m.ctrl.Call(m, "GenerateStatement", ctx, requestorID, accountID, periodType, year, period, isAdmin)
 	ret0, _ := ret[0].(*models.AccountStatement)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GenerateStatement indicates an expected call of GenerateStatement.
-func (mr *MockStatementServiceInterfaceMockRecorder) GenerateStatement(requestorID, accountID, periodType, year, period, isAdmin interface{}) *gomock.Call {
+func (mr *MockStatementServiceInterfaceMockRecorder) GenerateStatement(ctx, requestorID, accountID, periodType, year, period, isAdmin interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateStatement", reflect.TypeOf((*MockStatementServiceInterface)(nil).GenerateStatement), requestorID, accountID, periodType, year, period, isAdmin)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateStatement", reflect.TypeOf((*MockStatementServiceInterface)(nil).GenerateStatement), ctx, requestorID, accountID, periodType, year, period, isAdmin)
 }
 
 // MockTransactionGeneratorInterface is a mock of TransactionGeneratorInterface interface.
@@ -1130,18 +1130,18 @@ func (m *MockAuthServiceInterface) EXPECT() *MockAuthServiceInterfaceMockRecorde
 }
 
 // Login mocks base method.
-func (m *MockAuthServiceInterface) Login(req *dto.LoginRequest, ipAddress, userAgent string) (*dto.TokenResponse, error) {
+func (m *MockAuthServiceInterface) Login(ctx context.Context, req *dto.LoginRequest, ipAddress, userAgent string) (*dto.TokenResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Login", req, ipAddress, userAgent)
+	ret := m.ctrl.Call(m, "Login", ctx, req, ipAddress, userAgent)
 	ret0, _ := ret[0].(*dto.TokenResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Login indicates an expected call of Login.
-func (mr *MockAuthServiceInterfaceMockRecorder) Login(req, ipAddress, userAgent interface{}) *gomock.Call {
+func (mr *MockAuthServiceInterfaceMockRecorder) Login(ctx, req, ipAddress, userAgent interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuthServiceInterface)(nil).Login), req, ipAddress, userAgent)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuthServiceInterface)(nil).Login), ctx, req, ipAddress, userAgent)
 }
 
 // Logout mocks base method.
@@ -1159,33 +1159,33 @@ func (mr *MockAuthServiceInterfaceMockRecorder) Logout(accessToken, ipAddress, u
 }
 
 // RefreshTokens mocks base method.
-func (m *MockAuthServiceInterface) RefreshTokens(refreshToken, ipAddress, userAgent string) (*dto.TokenResponse, error) {
+func (m *MockAuthServiceInterface) RefreshTokens(ctx context.Context, refreshToken, ipAddress, userAgent string) (*dto.TokenResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RefreshTokens", refreshToken, ipAddress, userAgent)
+	ret := m.ctrl.Call(m, "RefreshTokens", ctx, refreshToken, ipAddress, userAgent)
 	ret0, _ := ret[0].(*dto.TokenResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // RefreshTokens indicates an expected call of RefreshTokens.
-func (mr *MockAuthServiceInterfaceMockRecorder) RefreshTokens(refreshToken, ipAddress, userAgent interface{}) *gomock.Call {
+func (mr *MockAuthServiceInterfaceMockRecorder) RefreshTokens(ctx, refreshToken, ipAddress, userAgent interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshTokens", reflect.TypeOf((*MockAuthServiceInterface)(nil).RefreshTokens), refreshToken, ipAddress, userAgent)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshTokens", reflect.TypeOf((*MockAuthServiceInterface)(nil).RefreshTokens), ctx, refreshToken, ipAddress, userAgent)
 }
 
 // Register mocks base method.
-func (m *MockAuthServiceInterface) Register(req *dto.RegisterRequest, ipAddress, userAgent string) (*models.User, error) {
+func (m *MockAuthServiceInterface) Register(ctx context.Context, req *dto.RegisterRequest, ipAddress, userAgent string) (*models.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Register", req, ipAddress, userAgent)
+	ret := m.ctrl.Call(m, "Register", ctx, req, ipAddress, userAgent)
 	ret0, _ := ret[0].(*models.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Register indicates an expected call of Register.
-func (mr *MockAuthServiceInterfaceMockRecorder) Register(req, ipAddress, userAgent interface{}) *gomock.Call {
+func (mr *MockAuthServiceInterfaceMockRecorder) Register(ctx, req, ipAddress, userAgent interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockAuthServiceInterface)(nil).Register), req, ipAddress, userAgent)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockAuthServiceInterface)(nil).Register), ctx, req, ipAddress, userAgent)
 }
 
 // MockTokenServiceInterface is a mock of TokenServiceInterface interface.
@@ -1288,6 +1288,20 @@ func (mr *MockTokenServiceInterfaceMockRecorder) GetTokenExpiry(tokenString inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTokenExpiry", reflect.TypeOf((*MockTokenServiceInterface)(nil).GetTokenExpiry), tokenString)
 }
 
+// ScopesForRole mocks base method.
+func (m *MockTokenServiceInterface) ScopesForRole(role string) models.TokenScopes {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScopesForRole", role)
+	ret0, _ := ret[0].(models.TokenScopes)
+	return ret0
+}
+
+// ScopesForRole indicates an expected call of ScopesForRole.
+func (mr *MockTokenServiceInterfaceMockRecorder) ScopesForRole(role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScopesForRole", reflect.TypeOf((*MockTokenServiceInterface)(nil).ScopesForRole), role)
+}
+
 // ValidateAccessToken mocks base method.
 func (m *MockTokenServiceInterface) ValidateAccessToken(tokenString string) (*models.CustomClaims, error) {
 	m.ctrl.T.Helper()
@@ -1342,18 +1356,18 @@ func (m *MockPasswordServiceInterface) EXPECT() *MockPasswordServiceInterfaceMoc
 }
 
 // AdminResetPassword mocks base method.
-func (m *MockPasswordServiceInterface) AdminResetPassword(customerID, adminID uuid.UUID) (string, error) {
+func (m *MockPasswordServiceInterface) AdminResetPassword(ctx context.Context, customerID, adminID uuid.UUID) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AdminResetPassword", customerID, adminID)
+	ret := m.ctrl.Call(m, "AdminResetPassword", ctx, customerID, adminID)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AdminResetPassword indicates an expected call of AdminResetPassword.
-func (mr *MockPasswordServiceInterfaceMockRecorder) AdminResetPassword(customerID, adminID interface{}) *gomock.Call {
+func (mr *MockPasswordServiceInterfaceMockRecorder) AdminResetPassword(ctx, customerID, adminID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminResetPassword", reflect.TypeOf((*MockPasswordServiceInterface)(nil).AdminResetPassword), customerID, adminID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminResetPassword", reflect.TypeOf((*MockPasswordServiceInterface)(nil).AdminResetPassword), ctx, customerID, adminID)
 }
 
 // ComparePassword mocks base method.
@@ -1371,17 +1385,17 @@ func (mr *MockPasswordServiceInterfaceMockRecorder) ComparePassword(password, ha
 }
 
 // CustomerUpdatePassword mocks base method.
-func (m *MockPasswordServiceInterface) CustomerUpdatePassword(customerID uuid.UUID, currentPassword, newPassword string) error {
+func (m *MockPasswordServiceInterface) CustomerUpdatePassword(ctx context.Context, customerID uuid.UUID, currentPassword, newPassword string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CustomerUpdatePassword", customerID, currentPassword, newPassword)
+	ret := m.ctrl.Call(m, "CustomerUpdatePassword", ctx, customerID, currentPassword, newPassword)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CustomerUpdatePassword indicates an expected call of CustomerUpdatePassword.
-func (mr *MockPasswordServiceInterfaceMockRecorder) CustomerUpdatePassword(customerID, currentPassword, newPassword interface{}) *gomock.Call {
+func (mr *MockPasswordServiceInterfaceMockRecorder) CustomerUpdatePassword(ctx, customerID, currentPassword, newPassword interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CustomerUpdatePassword", reflect.TypeOf((*MockPasswordServiceInterface)(nil).CustomerUpdatePassword), customerID, currentPassword, newPassword)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CustomerUpdatePassword", reflect.TypeOf((*MockPasswordServiceInterface)(nil).CustomerUpdatePassword), ctx, customerID, currentPassword, newPassword)
 }
 
 // GenerateSecurePassword mocks base method.
@@ -1764,6 +1778,20 @@ func (mr *MockCircuitBreakerInterfaceMockRecorder) Reset() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockCircuitBreakerInterface)(nil).Reset))
 }
 
+// RetryAfter mocks base method.
+func (m *MockCircuitBreakerInterface) RetryAfter() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryAfter")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// RetryAfter indicates an expected call of RetryAfter.
+func (mr *MockCircuitBreakerInterfaceMockRecorder) RetryAfter() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryAfter", reflect.TypeOf((*MockCircuitBreakerInterface)(nil).RetryAfter))
+}
+
 // MockCustomerLoggerInterface is a mock of CustomerLoggerInterface interface.
 type MockCustomerLoggerInterface struct {
 	ctrl     *gomock.Controller
@@ -1967,17 +1995,17 @@ func (m *MockTransactionProcessingServiceInterface) EXPECT() *MockTransactionPro
 }
 
 // EnqueueTransaction mocks base method.
-func (m *MockTransactionProcessingServiceInterface) EnqueueTransaction(transactionID uuid.UUID, operation string, priority int) error {
+func (m *MockTransactionProcessingServiceInterface) EnqueueTransaction(ctx context.Context, transactionID uuid.UUID, operation string, priority int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "EnqueueTransaction", transactionID, operation, priority)
+	ret := m.ctrl.Call(m, "EnqueueTransaction", ctx, transactionID, operation, priority)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // EnqueueTransaction indicates an expected call of EnqueueTransaction.
-func (mr *MockTransactionProcessingServiceInterfaceMockRecorder) EnqueueTransaction(transactionID, operation, priority interface{}) *gomock.Call {
+func (mr *MockTransactionProcessingServiceInterfaceMockRecorder) EnqueueTransaction(ctx, transactionID, operation, priority interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueTransaction", reflect.TypeOf((*MockTransactionProcessingServiceInterface)(nil).EnqueueTransaction), transactionID, operation, priority)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueTransaction", reflect.TypeOf((*MockTransactionProcessingServiceInterface)(nil).EnqueueTransaction), ctx, transactionID, operation, priority)
 }
 
 // GetQueueMetrics mocks base method.
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Startup recovery categories, used as the metric label and in the summary log line
+const (
+	RecoveryCategoryRegulatorLeases   = "regulator_leases"
+	RecoveryCategoryProcessingQueue   = "processing_queue"
+	RecoveryCategoryQueuedInitiations = "queued_initiations"
+)
+
+// processingItemStaleAfter is how long a queue item may stay marked processing before recovery
+// treats its worker as dead, well beyond the time one transaction takes
+const processingItemStaleAfter = 5 * time.Minute
+
+// RecoverySummary counts what one startup recovery pass found, by category. Regulator leases
+// and processing-queue items are the rows this pass released; queued initiations are the
+// INITIATION_PENDING transfers waiting for the first drain, which needs no reset because a claim
+// a dead pod left on one is taken over once it is older than initiationClaimStaleAfter.
+type RecoverySummary map[string]int64
+
+// StartupRecovery releases the work a pod that shut down uncleanly left claimed, so it is picked
+// up on the first scheduler cycle rather than after its lease or backoff runs out. Every release
+// is a conditional UPDATE whose conditions a released row no longer meets, so pods starting
+// together recover each row once.
+type StartupRecovery struct {
+	notificationRepo repositories.RegulatorNotificationRepositoryInterface
+	queueRepo        repositories.ProcessingQueueRepositoryInterface
+	transferRepo     repositories.NorthwindTransferRepositoryInterface
+	recovered        *prometheus.CounterVec
+	logger           *slog.Logger
+	now              func() time.Time
+}
+
+// NewStartupRecovery creates a startup recovery pass. Recovered rows are counted in
+// startup_recovery_items_total, registered with reg.
+func NewStartupRecovery(
+	notificationRepo repositories.RegulatorNotificationRepositoryInterface,
+	queueRepo repositories.ProcessingQueueRepositoryInterface,
+	transferRepo repositories.NorthwindTransferRepositoryInterface,
+	reg prometheus.Registerer,
+	logger *slog.Logger,
+) *StartupRecovery {
+	return &StartupRecovery{
+		notificationRepo: notificationRepo,
+		queueRepo:        queueRepo,
+		transferRepo:     transferRepo,
+		recovered: promauto.With(reg).NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "startup_recovery_items_total",
+				Help: "Rows released by the startup recovery pass, by category",
+			},
+			[]string{"category"},
+		),
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Run makes one recovery pass and logs its summary. A category that fails is logged and left
+// out of the summary while the others still run; the errors are returned joined.
+func (r *StartupRecovery) Run(ctx context.Context) (RecoverySummary, error) {
+	now := r.now()
+	summary := make(RecoverySummary)
+	var errs []error
+
+	released, err := r.notificationRepo.ReleaseStaleLeases(ctx, now.Add(-queuedDeliveryLease), now)
+	if err != nil {
+		errs = append(errs, err)
+	} else {
+		summary[RecoveryCategoryRegulatorLeases] = released
+	}
+
+	reset, err := r.queueRepo.ResetStaleProcessing(now.Add(-processingItemStaleAfter))
+	if err != nil {
+		errs = append(errs, err)
+	} else {
+		summary[RecoveryCategoryProcessingQueue] = reset
+	}
+
+	counts, err := r.transferRepo.CountByStatus(ctx, models.NWTransferStatusInitiationPending)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to count queued initiations: %w", err))
+	} else {
+		summary[RecoveryCategoryQueuedInitiations] = counts[models.NWTransferStatusInitiationPending]
+	}
+
+	for _, category := range []string{RecoveryCategoryRegulatorLeases, RecoveryCategoryProcessingQueue} {
+		if n := summary[category]; n > 0 {
+			r.recovered.WithLabelValues(category).Add(float64(n))
+		}
+	}
+
+	r.logger.Info("Startup recovery finished",
+		RecoveryCategoryRegulatorLeases, summary[RecoveryCategoryRegulatorLeases],
+		RecoveryCategoryProcessingQueue, summary[RecoveryCategoryProcessingQueue],
+		RecoveryCategoryQueuedInitiations, summary[RecoveryCategoryQueuedInitiations])
+	if err := errors.Join(errs...); err != nil {
+		r.logger.Error("Startup recovery incomplete", "error", err)
+		return summary, err
+	}
+	return summary, nil
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gorm.io/gorm"
+)
+
+func newTestStartupRecovery(t *testing.T, db *gorm.DB, now time.Time) *StartupRecovery {
+	t.Helper()
+	recovery := NewStartupRecovery(
+		repositories.NewRegulatorNotificationRepository(db),
+		repositories.NewProcessingQueueRepository(db),
+		repositories.NewNorthwindTransferRepository(db),
+		prometheus.NewRegistry(), slog.Default())
+	recovery.now = func() time.Time { return now }
+	return recovery
+}
+
+// seedStaleWork stores what a pod that died a few minutes ago leaves behind next to live work,
+// and returns the IDs of the rows recovery should release
+func seedStaleWork(t *testing.T, db *gorm.DB, now time.Time) (staleNotifications, staleItems []uuid.UUID) {
+	t.Helper()
+	createdAt := func(at time.Time) testfactory.NotificationOption {
+		return func(n *models.RegulatorNotification) {
+			n.CreatedAt, n.UpdatedAt = at, at
+		}
+	}
+	for i := 0; i < 3; i++ {
+		stale := testfactory.RegulatorNotification(t, db, createdAt(now.Add(-10*time.Minute)), testfactory.WithNextAttemptAt(now.Add(time.Hour)))
+		staleNotifications = append(staleNotifications, stale.ID)
+	}
+	testfactory.RegulatorNotification(t, db, createdAt(now.Add(-5*time.Second)), testfactory.WithNextAttemptAt(now.Add(25*time.Second)))
+
+	item := func(status string, updatedAt time.Time) uuid.UUID {
+		queued := &models.ProcessingQueueItem{
+			TransactionID: uuid.New(),
+			Operation:     models.QueueOperationProcess,
+			Priority:      models.QueuePriorityNormal,
+			Status:        status,
+			MaxRetries:    3,
+			ScheduledAt:   updatedAt,
+			CreatedAt:     updatedAt,
+			UpdatedAt:     updatedAt,
+		}
+		if err := db.Create(queued).Error; err != nil {
+			t.Fatalf("failed to create queue item: %v", err)
+		}
+		return queued.ID
+	}
+	for i := 0; i < 2; i++ {
+		staleItems = append(staleItems, item(models.QueueStatusProcessing, now.Add(-time.Hour)))
+	}
+	item(models.QueueStatusProcessing, now.Add(-time.Second))
+	item(models.QueueStatusCompleted, now.Add(-time.Hour))
+
+	testfactory.NWTransfer(t, db, testfactory.WithStatus(models.NWTransferStatusInitiationPending))
+	return staleNotifications, staleItems
+}
+
+func TestStartupRecovery_Run(t *testing.T) {
+	db := testfactory.NewDB(t)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	staleNotifications, staleItems := seedStaleWork(t, db, now)
+	recovery := newTestStartupRecovery(t, db, now)
+
+	summary, err := recovery.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := RecoverySummary{
+		RecoveryCategoryRegulatorLeases:   int64(len(staleNotifications)),
+		RecoveryCategoryProcessingQueue:   int64(len(staleItems)),
+		RecoveryCategoryQueuedInitiations: 1,
+	}
+	for category, n := range want {
+		if summary[category] != n {
+			t.Errorf("expected %d %s recovered, got %d", n, category, summary[category])
+		}
+	}
+
+	pending, err := repositories.NewRegulatorNotificationRepository(db).GetPendingNotifications(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pending) != len(staleNotifications) {
+		t.Errorf("expected the %d released notifications due, got %d", len(staleNotifications), len(pending))
+	}
+	var items []*models.ProcessingQueueItem
+	if err := db.Where("id IN ?", staleItems).Find(&items).Error; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, item := range items {
+		if item.Status != models.QueueStatusPending || item.ScheduledAt.After(time.Now()) {
+			t.Errorf("expected queue item %s pending and due, got %s at %v", item.ID, item.Status, item.ScheduledAt)
+		}
+	}
+
+	for category, n := range map[string]int{RecoveryCategoryRegulatorLeases: len(staleNotifications), RecoveryCategoryProcessingQueue: len(staleItems)} {
+		if got := testutil.ToFloat64(recovery.recovered.WithLabelValues(category)); got != float64(n) {
+			t.Errorf("expected startup_recovery_items_total{category=%q} %d, got %v", category, n, got)
+		}
+	}
+
+	again, err := recovery.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again[RecoveryCategoryRegulatorLeases] != 0 || again[RecoveryCategoryProcessingQueue] != 0 {
+		t.Errorf("expected a second pass to release nothing, got %v", again)
+	}
+}
+
+func TestStartupRecovery_ConcurrentPods(t *testing.T) {
+	db := testfactory.NewDB(t)
+	// Concurrent callers must share the one in-memory database
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	staleNotifications, staleItems := seedStaleWork(t, db, now)
+
+	const pods = 2
+	summaries := make([]RecoverySummary, pods)
+	var wg sync.WaitGroup
+	for i := 0; i < pods; i++ {
+		recovery := newTestStartupRecovery(t, db, now.Add(time.Duration(i)*time.Millisecond))
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			summary, err := recovery.Run(context.Background())
+			if err != nil {
+				t.Errorf("pod %d: unexpected error: %v", i, err)
+			}
+			summaries[i] = summary
+		}(i)
+	}
+	wg.Wait()
+
+	var leases, items int64
+	for _, summary := range summaries {
+		leases += summary[RecoveryCategoryRegulatorLeases]
+		items += summary[RecoveryCategoryProcessingQueue]
+	}
+	if leases != int64(len(staleNotifications)) {
+		t.Errorf("expected each of %d stale leases released once across pods, got %d releases", len(staleNotifications), leases)
+	}
+	if items != int64(len(staleItems)) {
+		t.Errorf("expected each of %d stale queue items reset once across pods, got %d resets", len(staleItems), items)
+	}
+}
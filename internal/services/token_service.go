@@ -78,9 +78,26 @@ func (ts *TokenService) GenerateRefreshToken(userID uuid.UUID) (string, time.Tim
 	return tokenString, expiresAt, nil
 }
 
-// ValidateAccessToken validates and parses an access token
+// ScopesForRole returns the scopes granted to role: those configured in RoleScopes, or the defaults
+func (ts *TokenService) ScopesForRole(role string) models.TokenScopes {
+	if scopes, ok := ts.RoleScopes[role]; ok {
+		return append(models.TokenScopes{}, scopes...)
+	}
+	return models.ScopesForRole(role)
+}
+
+// ValidateAccessToken validates and parses an access token. A token issued before tokens carried
+// scopes gets the scopes its role is granted now, unless RejectUnscopedTokens is set, when its Scopes
+// stay nil.
 func (ts *TokenService) ValidateAccessToken(tokenString string) (*models.CustomClaims, error) {
-	return ts.validateToken(tokenString, TokenTypeAccess)
+	claims, err := ts.validateToken(tokenString, TokenTypeAccess)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Scopes == nil && !ts.RejectUnscopedTokens {
+		claims.Scopes = ts.ScopesForRole(claims.Role)
+	}
+	return claims, nil
 }
 
 // ValidateRefreshToken validates and parses a refresh token
@@ -144,6 +161,7 @@ func (ts *TokenService) buildAccessTokenClaims(user *models.User, issuedAt, expi
 		Email:     user.Email,
 		Role:      user.Role,
 		TokenType: TokenTypeAccess,
+		Scopes:    ts.ScopesForRole(user.Role),
 	}
 }
 
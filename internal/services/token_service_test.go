@@ -7,6 +7,7 @@ import (
 
 	"github.com/array/banking-api/internal/config"
 	"github.com/array/banking-api/internal/models"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
 )
@@ -306,6 +307,85 @@ func (s *TokenServiceTestSuite) TestGetJTI() {
 	s.NoError(err)
 }
 
+// Test access tokens carry the scopes of the user's role
+func (s *TokenServiceTestSuite) TestGenerateAccessToken_Scopes() {
+	for _, role := range []string{models.RoleCustomer, models.RoleAdmin} {
+		token, _, err := s.service.GenerateAccessToken(&models.User{ID: uuid.New(), Email: "test@example.com", Role: role})
+		s.Require().NoError(err)
+
+		claims, err := s.service.ValidateAccessToken(token)
+		s.Require().NoError(err)
+		s.ElementsMatch(models.ScopesForRole(role), claims.Scopes, role)
+		s.True(claims.Scopes.Grants(models.ClaimScopeTransfersCreate), role)
+		s.Equal(role == models.RoleAdmin, claims.Scopes.Grants(models.ClaimScopeAdmin), role)
+	}
+}
+
+// Test tokens issued before scopes get their role's scopes unless unscoped tokens are rejected
+func (s *TokenServiceTestSuite) TestValidateAccessToken_LegacyUnscoped() {
+	now := time.Now()
+	legacy := jwt.NewWithClaims(jwt.SigningMethodRS256, models.CustomClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.issuer,
+			ID:        uuid.New().String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+		UserID:    uuid.New().String(),
+		Role:      models.RoleCustomer,
+		TokenType: TokenTypeAccess,
+	})
+	token, err := legacy.SignedString(s.privateKey)
+	s.Require().NoError(err)
+
+	claims, err := s.service.ValidateAccessToken(token)
+	s.Require().NoError(err)
+	s.ElementsMatch(models.ScopesForRole(models.RoleCustomer), claims.Scopes)
+
+	strict := NewTokenService(&config.JWTConfig{
+		PrivateKey:           s.privateKey,
+		PublicKey:            s.publicKey,
+		Issuer:               s.issuer,
+		AccessTokenDuration:  s.accessDuration,
+		RefreshTokenDuration: s.refreshDuration,
+		RejectUnscopedTokens: true,
+	})
+	claims, err = strict.ValidateAccessToken(token)
+	s.Require().NoError(err)
+	s.Nil(claims.Scopes)
+}
+
+// Test configured role scopes replace the defaults, and a role without scopes gets none
+func (s *TokenServiceTestSuite) TestGenerateAccessToken_ConfiguredRoleScopes() {
+	service := NewTokenService(&config.JWTConfig{
+		PrivateKey:           s.privateKey,
+		PublicKey:            s.publicKey,
+		Issuer:               s.issuer,
+		AccessTokenDuration:  s.accessDuration,
+		RefreshTokenDuration: s.refreshDuration,
+		RoleScopes:           map[string][]string{models.RoleCustomer: {models.ClaimScopeTransfersCreate}},
+	})
+
+	tests := []struct {
+		role   string
+		scopes models.TokenScopes
+	}{
+		{models.RoleCustomer, models.TokenScopes{models.ClaimScopeTransfersCreate}},
+		{models.RoleAdmin, models.ScopesForRole(models.RoleAdmin)},
+		{"auditor", nil},
+	}
+	for _, tt := range tests {
+		token, _, err := service.GenerateAccessToken(&models.User{ID: uuid.New(), Email: "test@example.com", Role: tt.role})
+		s.Require().NoError(err)
+
+		claims, err := service.ValidateAccessToken(token)
+		s.Require().NoError(err)
+		s.ElementsMatch(tt.scopes, claims.Scopes, tt.role)
+		s.ElementsMatch(tt.scopes, service.ScopesForRole(tt.role), tt.role)
+	}
+	s.False(service.ScopesForRole(models.RoleCustomer).Grants(models.ClaimScopeTransfersReverse))
+}
+
 // Benchmarks
 func BenchmarkTokenService_GenerateAccessToken(b *testing.B) {
 	privateKey, publicKey, err := config.GenerateRSAKeyPair()
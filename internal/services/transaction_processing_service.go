@@ -54,8 +54,8 @@ func NewTransactionProcessingService(
 	}
 }
 
-func (s *TransactionProcessingService) EnqueueTransaction(transactionID uuid.UUID, operation string, priority int) error {
-	_, err := s.transactionRepo.GetByID(transactionID)
+func (s *TransactionProcessingService) EnqueueTransaction(ctx context.Context, transactionID uuid.UUID, operation string, priority int) error {
+	_, err := s.transactionRepo.GetByID(ctx, transactionID)
 	if err != nil {
 		return fmt.Errorf("transaction not found: %w", err)
 	}
@@ -148,7 +148,7 @@ func (s *TransactionProcessingService) validateProcessingPreconditions(ctx conte
 		s.metrics.IncrementCounter("circuit_breaker.open", map[string]string{
 			"service": "database",
 		})
-		return ErrCircuitBreakerOpen
+		return &CircuitBreakerOpenError{RetryAfter: s.circuitBreaker.RetryAfter()}
 	}
 
 	if queueItem.RetryCount >= queueItem.MaxRetries {
@@ -159,14 +159,14 @@ func (s *TransactionProcessingService) validateProcessingPreconditions(ctx conte
 }
 
 func (s *TransactionProcessingService) fetchAndValidateTransaction(ctx context.Context, queueItem *models.ProcessingQueueItem) (*models.Transaction, error) {
-	transaction, err := s.transactionRepo.GetByID(queueItem.TransactionID)
+	transaction, err := s.transactionRepo.GetByID(ctx, queueItem.TransactionID)
 	if err != nil {
 		s.circuitBreaker.RecordFailure()
 		return nil, s.handleProcessingError(ctx, queueItem, err)
 	}
 
 	if transaction.Reference != "" {
-		if isDuplicate, err := s.checkDuplicateReference(transaction); isDuplicate {
+		if isDuplicate, err := s.checkDuplicateReference(ctx, transaction); isDuplicate {
 			s.metrics.IncrementCounter("transaction.duplicate.rejected", map[string]string{
 				"reference": transaction.Reference,
 			})
@@ -180,8 +180,8 @@ func (s *TransactionProcessingService) fetchAndValidateTransaction(ctx context.C
 	return transaction, nil
 }
 
-func (s *TransactionProcessingService) checkDuplicateReference(transaction *models.Transaction) (bool, error) {
-	existingTxn, err := s.transactionRepo.GetByReference(transaction.Reference)
+func (s *TransactionProcessingService) checkDuplicateReference(ctx context.Context, transaction *models.Transaction) (bool, error) {
+	existingTxn, err := s.transactionRepo.GetByReference(ctx, transaction.Reference)
 	if err != nil {
 		return false, err
 	}
@@ -232,7 +232,7 @@ func (s *TransactionProcessingService) processTransaction(ctx context.Context, t
 		return fmt.Errorf("failed to update account balance: %w", err)
 	}
 
-	if err := s.transactionRepo.UpdateWithOptimisticLock(transaction, expectedVersion); err != nil {
+	if err := s.transactionRepo.UpdateWithOptimisticLock(ctx, transaction, expectedVersion); err != nil {
 		if errors.Is(err, models.ErrOptimisticLockConflict) {
 			s.auditLogger.LogOptimisticLockConflict(ctx, "transaction", transaction.ID, expectedVersion, transaction.Version)
 		}
@@ -258,7 +258,7 @@ func (s *TransactionProcessingService) reverseTransaction(ctx context.Context, t
 		return fmt.Errorf("failed to reverse account balance: %w", err)
 	}
 
-	if err := s.transactionRepo.UpdateWithOptimisticLock(transaction, expectedVersion); err != nil {
+	if err := s.transactionRepo.UpdateWithOptimisticLock(ctx, transaction, expectedVersion); err != nil {
 		return err
 	}
 
@@ -324,12 +324,12 @@ func (s *TransactionProcessingService) handleProcessingError(ctx context.Context
 }
 
 func (s *TransactionProcessingService) handleMaxRetriesExceeded(ctx context.Context, queueItem *models.ProcessingQueueItem) error {
-	transaction, err := s.transactionRepo.GetByID(queueItem.TransactionID)
+	transaction, err := s.transactionRepo.GetByID(ctx, queueItem.TransactionID)
 	if err == nil {
 		oldStatus := transaction.Status
 		transaction.Fail()
 		expectedVersion := transaction.Version - 1
-		_ = s.transactionRepo.UpdateWithOptimisticLock(transaction, expectedVersion)
+		_ = s.transactionRepo.UpdateWithOptimisticLock(ctx, transaction, expectedVersion)
 		s.auditLogger.LogTransactionStateChange(ctx, transaction.ID, oldStatus, models.TransactionStatusFailed)
 	}
 
@@ -350,7 +350,7 @@ func (s *TransactionProcessingService) handleMaxRetriesExceeded(ctx context.Cont
 func (s *TransactionProcessingService) handleDuplicateReference(ctx context.Context, queueItem *models.ProcessingQueueItem, transaction *models.Transaction) error {
 	transaction.Fail()
 	expectedVersion := transaction.Version
-	_ = s.transactionRepo.UpdateWithOptimisticLock(transaction, expectedVersion)
+	_ = s.transactionRepo.UpdateWithOptimisticLock(ctx, transaction, expectedVersion)
 
 	if err := s.queueRepo.MarkFailed(queueItem.ID, "duplicate transaction reference"); err != nil {
 		return err
@@ -76,11 +76,11 @@ func (s *TransactionProcessingServiceTestSuite) TestTransactionProcessingService
 	}
 
 	// Mock expectations
-	s.transactionRepo.EXPECT().GetByID(transactionID).Return(transaction, nil).Times(1)
+	s.transactionRepo.EXPECT().GetByID(gomock.Any(), transactionID).Return(transaction, nil).Times(1)
 	s.queueRepo.EXPECT().Enqueue(transactionID, models.QueueOperationProcess, models.QueuePriorityNormal).Return(nil).Times(1)
 	s.metrics.EXPECT().IncrementCounter("queue.enqueued", map[string]string{"operation": models.QueueOperationProcess}).Times(1)
 
-	err := s.processingService.EnqueueTransaction(transactionID, models.QueueOperationProcess, models.QueuePriorityNormal)
+	err := s.processingService.EnqueueTransaction(context.Background(), transactionID, models.QueueOperationProcess, models.QueuePriorityNormal)
 
 	s.NoError(err)
 }
@@ -102,11 +102,11 @@ func (s *TransactionProcessingServiceTestSuite) TestTransactionProcessingService
 	}
 
 	// Mock expectations
-	s.transactionRepo.EXPECT().GetByID(transactionID).Return(transaction, nil).Times(1)
+	s.transactionRepo.EXPECT().GetByID(gomock.Any(), transactionID).Return(transaction, nil).Times(1)
 	s.queueRepo.EXPECT().Enqueue(transactionID, models.QueueOperationProcess, models.QueuePriorityHigh).Return(nil).Times(1)
 	s.metrics.EXPECT().IncrementCounter("queue.enqueued", map[string]string{"operation": models.QueueOperationProcess}).Times(1)
 
-	err := s.processingService.EnqueueTransaction(transactionID, models.QueueOperationProcess, models.QueuePriorityHigh)
+	err := s.processingService.EnqueueTransaction(context.Background(), transactionID, models.QueueOperationProcess, models.QueuePriorityHigh)
 
 	s.NoError(err)
 }
@@ -165,12 +165,12 @@ func (s *TransactionProcessingServiceTestSuite) TestTransactionProcessingService
 			Balance: decimal.NewFromFloat(1000.0),
 		}
 
-		s.transactionRepo.EXPECT().GetByID(item.TransactionID).Return(transaction, nil)
+		s.transactionRepo.EXPECT().GetByID(gomock.Any(), item.TransactionID).Return(transaction, nil)
 		s.accountRepo.EXPECT().GetByID(accountID).Return(account, nil)
 		s.accountRepo.EXPECT().UpdateBalance(accountID, gomock.Any(), gomock.Any()).Return(nil)
 		// Use gomock.Any() for transaction to avoid data race: the service mutates it in
 		// Complete() before calling UpdateWithOptimisticLock; gomock reading it for matching races.
-		s.transactionRepo.EXPECT().UpdateWithOptimisticLock(gomock.Any(), 1).Return(nil)
+		s.transactionRepo.EXPECT().UpdateWithOptimisticLock(gomock.Any(), gomock.Any(), 1).Return(nil)
 		s.queueRepo.EXPECT().MarkCompleted(item.ID).Return(nil)
 	}
 
@@ -221,11 +221,11 @@ func (s *TransactionProcessingServiceTestSuite) TestTransactionProcessingService
 	// Mock expectations
 	s.circuitBreaker.EXPECT().IsOpen().Return(false).Times(1)
 	s.auditLogger.EXPECT().LogTransactionProcessingStarted(gomock.Any(), transactionID, models.QueueOperationProcess).Times(1)
-	s.transactionRepo.EXPECT().GetByID(transactionID).Return(transaction, nil).Times(1)
+	s.transactionRepo.EXPECT().GetByID(gomock.Any(), transactionID).Return(transaction, nil).Times(1)
 	s.accountRepo.EXPECT().GetByID(accountID).Return(account, nil).Times(1)
 	s.auditLogger.EXPECT().LogBalanceUpdate(gomock.Any(), accountID, gomock.Any(), gomock.Any(), transactionID).Times(1)
 	s.accountRepo.EXPECT().UpdateBalance(accountID, gomock.Any(), gomock.Any()).Return(nil).Times(1)
-	s.transactionRepo.EXPECT().UpdateWithOptimisticLock(transaction, 1).Return(models.ErrOptimisticLockConflict).Times(1)
+	s.transactionRepo.EXPECT().UpdateWithOptimisticLock(gomock.Any(), transaction, 1).Return(models.ErrOptimisticLockConflict).Times(1)
 	s.auditLogger.EXPECT().LogOptimisticLockConflict(gomock.Any(), "transaction", transactionID, 1, 1).Times(1)
 	s.circuitBreaker.EXPECT().RecordFailure().Times(1)
 	s.auditLogger.EXPECT().LogRetryAttempt(gomock.Any(), queueItem.ID, transactionID, 1, 3, int64(1000)).Times(1)
@@ -263,8 +263,8 @@ func (s *TransactionProcessingServiceTestSuite) TestTransactionProcessingService
 
 	// Mock expectations
 	s.circuitBreaker.EXPECT().IsOpen().Return(false).Times(1)
-	s.transactionRepo.EXPECT().GetByID(transactionID).Return(transaction, nil).Times(1)
-	s.transactionRepo.EXPECT().UpdateWithOptimisticLock(transaction, 0).Return(nil).Times(1)
+	s.transactionRepo.EXPECT().GetByID(gomock.Any(), transactionID).Return(transaction, nil).Times(1)
+	s.transactionRepo.EXPECT().UpdateWithOptimisticLock(gomock.Any(), transaction, 0).Return(nil).Times(1)
 	s.auditLogger.EXPECT().LogTransactionStateChange(gomock.Any(), transactionID, models.TransactionStatusPending, models.TransactionStatusFailed).Times(1)
 	s.queueRepo.EXPECT().MarkFailed(queueItem.ID, "max retries exceeded").Return(nil).Times(1)
 	s.metrics.EXPECT().IncrementCounter("transaction.processed.failed", map[string]string{"operation": models.QueueOperationProcess, "reason": "max_retries"}).Times(1)
@@ -292,12 +292,17 @@ func (s *TransactionProcessingServiceTestSuite) TestTransactionProcessingService
 
 	// Mock expectations - circuit breaker is open
 	s.circuitBreaker.EXPECT().IsOpen().Return(true).Times(1)
+	s.circuitBreaker.EXPECT().RetryAfter().Return(12 * time.Second).Times(1)
 	s.metrics.EXPECT().IncrementCounter("circuit_breaker.open", map[string]string{"service": "database"}).Times(1)
 
 	err := s.processingService.ProcessQueueItem(s.ctx, queueItem)
 
 	s.Error(err)
 	s.Contains(err.Error(), "circuit breaker is open")
+	var breakerErr *services.CircuitBreakerOpenError
+	s.Require().ErrorAs(err, &breakerErr)
+	s.ErrorIs(err, services.ErrCircuitBreakerOpen)
+	s.Equal(12*time.Second, breakerErr.RetryAfter)
 }
 
 // Test: Circuit Breaker - Service Recovered - Closes Circuit
@@ -335,11 +340,11 @@ func (s *TransactionProcessingServiceTestSuite) TestTransactionProcessingService
 	// Mock expectations
 	s.circuitBreaker.EXPECT().IsOpen().Return(false).Times(1)
 	s.auditLogger.EXPECT().LogTransactionProcessingStarted(gomock.Any(), transactionID, models.QueueOperationProcess).Times(1)
-	s.transactionRepo.EXPECT().GetByID(transactionID).Return(transaction, nil).Times(1)
+	s.transactionRepo.EXPECT().GetByID(gomock.Any(), transactionID).Return(transaction, nil).Times(1)
 	s.accountRepo.EXPECT().GetByID(accountID).Return(account, nil).Times(1)
 	s.auditLogger.EXPECT().LogBalanceUpdate(gomock.Any(), accountID, gomock.Any(), gomock.Any(), transactionID).Times(1)
 	s.accountRepo.EXPECT().UpdateBalance(accountID, gomock.Any(), gomock.Any()).Return(nil).Times(1)
-	s.transactionRepo.EXPECT().UpdateWithOptimisticLock(transaction, 1).Return(nil).Times(1)
+	s.transactionRepo.EXPECT().UpdateWithOptimisticLock(gomock.Any(), transaction, 1).Return(nil).Times(1)
 	s.auditLogger.EXPECT().LogTransactionStateChange(gomock.Any(), transactionID, models.TransactionStatusPending, models.TransactionStatusCompleted).Times(1)
 	s.queueRepo.EXPECT().MarkCompleted(queueItem.ID).Return(nil).Times(1)
 	s.circuitBreaker.EXPECT().RecordSuccess().Times(1)
@@ -388,11 +393,11 @@ func (s *TransactionProcessingServiceTestSuite) TestTransactionProcessingService
 	// Mock expectations
 	s.circuitBreaker.EXPECT().IsOpen().Return(false).Times(1)
 	s.auditLogger.EXPECT().LogTransactionProcessingStarted(gomock.Any(), transactionID, models.QueueOperationProcess).Times(1)
-	s.transactionRepo.EXPECT().GetByID(transactionID).Return(transaction, nil).Times(1)
+	s.transactionRepo.EXPECT().GetByID(gomock.Any(), transactionID).Return(transaction, nil).Times(1)
 	s.accountRepo.EXPECT().GetByID(accountID).Return(account, nil).Times(1)
 	s.auditLogger.EXPECT().LogBalanceUpdate(gomock.Any(), accountID, gomock.Any(), gomock.Any(), transactionID).Times(1)
 	s.accountRepo.EXPECT().UpdateBalance(accountID, gomock.Any(), gomock.Any()).Return(nil).Times(1)
-	s.transactionRepo.EXPECT().UpdateWithOptimisticLock(transaction, 1).Return(nil).Times(1)
+	s.transactionRepo.EXPECT().UpdateWithOptimisticLock(gomock.Any(), transaction, 1).Return(nil).Times(1)
 	s.auditLogger.EXPECT().LogTransactionStateChange(gomock.Any(), transactionID, models.TransactionStatusPending, models.TransactionStatusCompleted).Times(1)
 	s.queueRepo.EXPECT().MarkCompleted(queueItem.ID).Return(nil).Times(1)
 	s.circuitBreaker.EXPECT().RecordSuccess().Times(1)
@@ -441,11 +446,11 @@ func (s *TransactionProcessingServiceTestSuite) TestTransactionProcessingService
 	// Mock expectations
 	s.circuitBreaker.EXPECT().IsOpen().Return(false).Times(1)
 	s.auditLogger.EXPECT().LogTransactionProcessingStarted(gomock.Any(), transactionID, models.QueueOperationProcess).Times(1)
-	s.transactionRepo.EXPECT().GetByID(transactionID).Return(transaction, nil).Times(1)
+	s.transactionRepo.EXPECT().GetByID(gomock.Any(), transactionID).Return(transaction, nil).Times(1)
 	s.accountRepo.EXPECT().GetByID(accountID).Return(account, nil).Times(1)
 	s.auditLogger.EXPECT().LogBalanceUpdate(gomock.Any(), accountID, gomock.Any(), gomock.Any(), transactionID).Times(1)
 	s.accountRepo.EXPECT().UpdateBalance(accountID, gomock.Any(), gomock.Any()).Return(nil).Times(1)
-	s.transactionRepo.EXPECT().UpdateWithOptimisticLock(transaction, 1).Return(nil).Times(1)
+	s.transactionRepo.EXPECT().UpdateWithOptimisticLock(gomock.Any(), transaction, 1).Return(nil).Times(1)
 	s.auditLogger.EXPECT().LogTransactionStateChange(gomock.Any(), transactionID, models.TransactionStatusPending, models.TransactionStatusCompleted).Times(1)
 	s.queueRepo.EXPECT().MarkCompleted(queueItem.ID).Return(nil).Times(1)
 	s.circuitBreaker.EXPECT().RecordSuccess().Times(1)
@@ -518,10 +523,10 @@ func (s *TransactionProcessingServiceTestSuite) TestTransactionProcessingService
 	// Mock expectations
 	s.circuitBreaker.EXPECT().IsOpen().Return(false).Times(1)
 	s.auditLogger.EXPECT().LogTransactionProcessingStarted(gomock.Any(), transactionID, models.QueueOperationProcess).Times(1)
-	s.transactionRepo.EXPECT().GetByID(transactionID).Return(transaction, nil).Times(1)
-	s.transactionRepo.EXPECT().GetByReference(reference).Return(existingTransaction, nil).Times(1)
+	s.transactionRepo.EXPECT().GetByID(gomock.Any(), transactionID).Return(transaction, nil).Times(1)
+	s.transactionRepo.EXPECT().GetByReference(gomock.Any(), reference).Return(existingTransaction, nil).Times(1)
 	s.metrics.EXPECT().IncrementCounter("transaction.duplicate.rejected", map[string]string{"reference": reference}).Times(1)
-	s.transactionRepo.EXPECT().UpdateWithOptimisticLock(transaction, 1).Return(nil).Times(1)
+	s.transactionRepo.EXPECT().UpdateWithOptimisticLock(gomock.Any(), transaction, 1).Return(nil).Times(1)
 	s.queueRepo.EXPECT().MarkFailed(queueItem.ID, "duplicate transaction reference").Return(nil).Times(1)
 	s.auditLogger.EXPECT().LogTransactionStateChange(gomock.Any(), transactionID, models.TransactionStatusPending, models.TransactionStatusFailed).Times(1)
 
@@ -596,11 +601,11 @@ func (s *TransactionProcessingServiceTestSuite) TestTransactionProcessingService
 	// Mock expectations
 	s.circuitBreaker.EXPECT().IsOpen().Return(false).Times(1)
 	s.auditLogger.EXPECT().LogTransactionProcessingStarted(gomock.Any(), transactionID, models.QueueOperationProcess).Times(1)
-	s.transactionRepo.EXPECT().GetByID(transactionID).Return(transaction, nil).Times(1)
+	s.transactionRepo.EXPECT().GetByID(gomock.Any(), transactionID).Return(transaction, nil).Times(1)
 	s.accountRepo.EXPECT().GetByID(accountID).Return(account, nil).Times(1)
 	s.auditLogger.EXPECT().LogBalanceUpdate(gomock.Any(), accountID, gomock.Any(), gomock.Any(), transactionID).Times(1)
 	s.accountRepo.EXPECT().UpdateBalance(accountID, gomock.Any(), gomock.Any()).Return(nil).Times(1)
-	s.transactionRepo.EXPECT().UpdateWithOptimisticLock(transaction, 1).Return(nil).Times(1)
+	s.transactionRepo.EXPECT().UpdateWithOptimisticLock(gomock.Any(), transaction, 1).Return(nil).Times(1)
 	s.auditLogger.EXPECT().LogTransactionStateChange(gomock.Any(), transactionID, models.TransactionStatusPending, models.TransactionStatusCompleted).Times(1)
 	s.queueRepo.EXPECT().MarkCompleted(queueItem.ID).Return(nil).Times(1)
 	s.circuitBreaker.EXPECT().RecordSuccess().Times(1)
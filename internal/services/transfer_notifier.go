@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+)
+
+// TransferNotifier tells a transfer's owner about its status changes, on the channels their
+// notification preferences allow: an in-app notification, a queued email, both or neither.
+type TransferNotifier struct {
+	preferences   *NotificationPreferenceService
+	notifications repositories.UserNotificationRepositoryInterface
+	userRepo      repositories.UserRepositoryInterface
+	logger        *slog.Logger
+}
+
+// NewTransferNotifier creates a new transfer notifier
+func NewTransferNotifier(
+	preferences *NotificationPreferenceService,
+	notifications repositories.UserNotificationRepositoryInterface,
+	userRepo repositories.UserRepositoryInterface,
+	logger *slog.Logger,
+) *TransferNotifier {
+	return &TransferNotifier{
+		preferences:   preferences,
+		notifications: notifications,
+		userRepo:      userRepo,
+		logger:        logger,
+	}
+}
+
+// NotifyTransferEvent notifies the transfer's owner of a status change. Transfers without a user
+// notify no one.
+func (n *TransferNotifier) NotifyTransferEvent(ctx context.Context, transfer *models.NorthwindTransfer, event *models.NorthwindTransferEvent) error {
+	if transfer.UserID == nil {
+		return nil
+	}
+	userID := *transfer.UserID
+	eventType := TransferNotificationEvent(event.ToStatus)
+	channels, err := n.preferences.Channels(ctx, userID, eventType)
+	if err != nil {
+		return fmt.Errorf("failed to load notification preferences: %w", err)
+	}
+
+	title, message := transferNotificationText(transfer, event.ToStatus)
+	notification := models.UserNotification{
+		UserID:     userID,
+		TransferID: &transfer.ID,
+		EventType:  eventType,
+		Title:      title,
+		Message:    message,
+	}
+
+	if channels.InApp {
+		inApp := notification
+		inApp.Channel = models.NotificationChannelInApp
+		if err := n.notifications.Create(ctx, &inApp); err != nil {
+			return fmt.Errorf("failed to create in-app notification: %w", err)
+		}
+	}
+	if channels.Email {
+		user, err := n.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to load notification recipient: %w", err)
+		}
+		email := notification
+		email.Channel = models.NotificationChannelEmail
+		email.Recipient = user.Email
+		if err := n.notifications.Create(ctx, &email); err != nil {
+			return fmt.Errorf("failed to enqueue notification email: %w", err)
+		}
+	}
+
+	n.logger.Debug("Transfer event notification sent",
+		"transfer_id", transfer.ID,
+		"event_type", eventType,
+		"in_app", channels.InApp,
+		"email", channels.Email,
+	)
+	return nil
+}
+
+func transferNotificationText(transfer *models.NorthwindTransfer, status string) (string, string) {
+	state := strings.ToLower(status)
+	title := fmt.Sprintf("Transfer %s", state)
+	message := fmt.Sprintf("Your %s transfer %s of %s %s is now %s.",
+		strings.ToLower(transfer.Direction), transfer.ReferenceNumber, transfer.Amount.StringFixed(2), transfer.Currency, state)
+	return title, message
+}
@@ -0,0 +1,337 @@
+// Package testfactory builds valid NorthWind and regulator model records for tests.
+//
+// Each model has two builders: an in-memory variant (NewNWTransfer, ...) for pure unit
+// tests, and a persisting variant (NWTransfer, ...) that stores the record through the
+// real repository. In-memory records are fully populated so that they look exactly like
+// records that went through the model's BeforeCreate hook.
+package testfactory
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/database"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// NewDB returns an in-memory test database with the NorthWind, regulator, canary, poll anomaly,
+// feature flag, risk and user notification tables migrated
+func NewDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db := database.SetupTestDB(t)
+	if err := db.DB.AutoMigrate(
+		&models.NorthwindExternalAccount{},
+		&models.NorthwindTransfer{},
+		&models.NorthwindTransferEvent{},
+		&models.RegulatorNotification{},
+		&models.RegulatorNotificationAttempt{},
+		&models.CanaryRun{},
+		&models.PollAnomaly{},
+		&models.TransferDispute{},
+		&models.FeatureFlagOverride{},
+		&models.NotificationPreference{},
+		&models.UserNotification{},
+		&models.BalanceAlertRule{},
+		&models.ProcessedWebhookEvent{},
+		&models.RiskEvaluation{},
+		&models.RiskRuleOverride{},
+	); err != nil {
+		t.Fatalf("failed to migrate northwind tables: %v", err)
+	}
+	return db.DB
+}
+
+// TransferOption customizes a NorthwindTransfer built by the factory
+type TransferOption func(*models.NorthwindTransfer)
+
+// WithStatus sets the transfer status
+func WithStatus(status string) TransferOption {
+	return func(tr *models.NorthwindTransfer) {
+		tr.Status = status
+	}
+}
+
+// WithUser sets the owning user
+func WithUser(userID uuid.UUID) TransferOption {
+	return func(tr *models.NorthwindTransfer) {
+		tr.UserID = &userID
+	}
+}
+
+// WithDirection sets the transfer direction (INBOUND or OUTBOUND)
+func WithDirection(direction string) TransferOption {
+	return func(tr *models.NorthwindTransfer) {
+		tr.Direction = direction
+	}
+}
+
+// WithTransferType sets the transfer type (ACH, WIRE or RTP)
+func WithTransferType(transferType string) TransferOption {
+	return func(tr *models.NorthwindTransfer) {
+		tr.TransferType = transferType
+	}
+}
+
+// WithNextPollAt sets when the poller next checks the transfer; nil means it is always due
+func WithNextPollAt(at *time.Time) TransferOption {
+	return func(tr *models.NorthwindTransfer) {
+		tr.NextPollAt = at
+	}
+}
+
+// WithAmount sets the transfer amount
+func WithAmount(amount float64) TransferOption {
+	return func(tr *models.NorthwindTransfer) {
+		tr.Amount = decimal.NewFromFloat(amount)
+	}
+}
+
+// WithNorthwindID sets the NorthWind-side transfer ID
+func WithNorthwindID(nwID uuid.UUID) TransferOption {
+	return func(tr *models.NorthwindTransfer) {
+		tr.NorthwindTransferID = nwID
+		ref := nwID.String()
+		tr.ExternalRef = &ref
+	}
+}
+
+// WithConsent stores an authorization consent on the transfer
+func WithConsent(consent models.AuthorizationConsent) TransferOption {
+	return func(tr *models.NorthwindTransfer) {
+		at := consent.Timestamp.UTC()
+		tr.ConsentTimestamp = &at
+		tr.ConsentIPAddress = &consent.IPAddress
+		tr.ConsentMethod = &consent.Method
+	}
+}
+
+// WithSourceAccount sets the source account number
+func WithSourceAccount(accountNumber string) TransferOption {
+	return func(tr *models.NorthwindTransfer) {
+		tr.SourceAccountNumber = accountNumber
+	}
+}
+
+// WithInternalTest flags the transfer as an internal test transfer
+func WithInternalTest() TransferOption {
+	return func(tr *models.NorthwindTransfer) {
+		tr.InternalTest = true
+	}
+}
+
+// WithCreatedAt sets the creation (and update) timestamp
+func WithCreatedAt(at time.Time) TransferOption {
+	return func(tr *models.NorthwindTransfer) {
+		tr.CreatedAt = at
+		tr.UpdatedAt = at
+	}
+}
+
+// WithCompletedDate sets the completion date NorthWind reported
+func WithCompletedDate(at time.Time) TransferOption {
+	return func(tr *models.NorthwindTransfer) {
+		tr.CompletedDate = &at
+	}
+}
+
+// NewNWTransfer builds an in-memory OUTBOUND ACH transfer in PENDING status
+func NewNWTransfer(opts ...TransferOption) *models.NorthwindTransfer {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	userID := uuid.New()
+	nwID := uuid.New()
+	externalRef := nwID.String()
+	transfer := &models.NorthwindTransfer{
+		ID:                       uuid.New(),
+		UserID:                   &userID,
+		NorthwindTransferID:      nwID,
+		ExternalRef:              &externalRef,
+		Direction:                models.NWTransferDirectionOutbound,
+		TransferType:             models.NWTransferTypeACH,
+		Amount:                   decimal.NewFromFloat(100.50),
+		Currency:                 "USD",
+		ReferenceNumber:          "REF-" + uuid.NewString()[:8],
+		SourceAccountNumber:      "1111111111",
+		DestinationAccountNumber: "2222222222",
+		Status:                   models.NWTransferStatusPending,
+		Version:                  1,
+		NextPollAt:               &now,
+		CreatedAt:                now,
+		UpdatedAt:                now,
+	}
+	for _, opt := range opts {
+		opt(transfer)
+	}
+	return transfer
+}
+
+// NWTransfer builds a transfer and persists it via the NorthWind transfer repository
+func NWTransfer(t *testing.T, db *gorm.DB, opts ...TransferOption) *models.NorthwindTransfer {
+	t.Helper()
+
+	transfer := NewNWTransfer(opts...)
+	if err := repositories.NewNorthwindTransferRepository(db).Create(context.Background(), transfer); err != nil {
+		t.Fatalf("failed to create northwind transfer: %v", err)
+	}
+	return transfer
+}
+
+// NotificationOption customizes a RegulatorNotification built by the factory
+type NotificationOption func(*models.RegulatorNotification)
+
+// WithTransfer links the notification to a transfer and rebuilds the payload from it.
+// A terminal transfer status becomes the notification's terminal status.
+func WithTransfer(transfer *models.NorthwindTransfer) NotificationOption {
+	return func(n *models.RegulatorNotification) {
+		n.TransferID = transfer.ID
+		if transfer.IsTerminal() {
+			n.TerminalStatus = transfer.Status
+		}
+		n.Payload = webhookPayload(transfer, n.TerminalStatus)
+	}
+}
+
+// WithTerminalStatus sets the terminal status the notification reports
+func WithTerminalStatus(status string) NotificationOption {
+	return func(n *models.RegulatorNotification) {
+		n.TerminalStatus = status
+	}
+}
+
+// WithDelivered marks the notification as delivered after the given number of attempts
+func WithDelivered(attempts int) NotificationOption {
+	return func(n *models.RegulatorNotification) {
+		now := time.Now().UTC()
+		n.Delivered = true
+		n.AttemptCount = attempts
+		n.FirstAttemptAt = &now
+		n.LastAttemptAt = &now
+		n.NextAttemptAt = nil
+	}
+}
+
+// WithNextAttemptAt schedules the next delivery attempt
+func WithNextAttemptAt(at time.Time) NotificationOption {
+	return func(n *models.RegulatorNotification) {
+		n.NextAttemptAt = &at
+	}
+}
+
+// NewRegulatorNotification builds an in-memory undelivered notification for a COMPLETED
+// transfer, due for immediate delivery
+func NewRegulatorNotification(opts ...NotificationOption) *models.RegulatorNotification {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	transfer := NewNWTransfer(WithStatus(models.NWTransferStatusCompleted))
+	notification := &models.RegulatorNotification{
+		ID:             uuid.New(),
+		TransferID:     transfer.ID,
+		TerminalStatus: models.NWTransferStatusCompleted,
+		NextAttemptAt:  &now,
+		Payload:        webhookPayload(transfer, models.NWTransferStatusCompleted),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	for _, opt := range opts {
+		opt(notification)
+	}
+	if eventID := models.EventIDFromPayload(notification.Payload); eventID != "" && notification.EventID == nil {
+		notification.EventID = &eventID
+	}
+	return notification
+}
+
+// RegulatorNotification builds a notification and persists it via the regulator notification repository
+func RegulatorNotification(t *testing.T, db *gorm.DB, opts ...NotificationOption) *models.RegulatorNotification {
+	t.Helper()
+
+	notification := NewRegulatorNotification(opts...)
+	if err := repositories.NewRegulatorNotificationRepository(db).Create(context.Background(), notification); err != nil {
+		t.Fatalf("failed to create regulator notification: %v", err)
+	}
+	return notification
+}
+
+// ExternalAccountOption customizes a NorthwindExternalAccount built by the factory
+type ExternalAccountOption func(*models.NorthwindExternalAccount)
+
+// WithOwner sets the user that registered the external account
+func WithOwner(userID uuid.UUID) ExternalAccountOption {
+	return func(a *models.NorthwindExternalAccount) {
+		a.UserID = &userID
+	}
+}
+
+// WithAccountNumber sets the account and routing numbers
+func WithAccountNumber(accountNumber, routingNumber string) ExternalAccountOption {
+	return func(a *models.NorthwindExternalAccount) {
+		a.AccountNumber = accountNumber
+		a.RoutingNumber = routingNumber
+	}
+}
+
+// WithValidated sets whether NorthWind validated the account
+func WithValidated(validated bool) ExternalAccountOption {
+	return func(a *models.NorthwindExternalAccount) {
+		a.Validated = validated
+		if validated {
+			now := time.Now().UTC()
+			a.ValidationTime = &now
+		} else {
+			a.ValidationTime = nil
+		}
+	}
+}
+
+// NewNWExternalAccount builds an in-memory validated external account
+func NewNWExternalAccount(opts ...ExternalAccountOption) *models.NorthwindExternalAccount {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	userID := uuid.New()
+	account := &models.NorthwindExternalAccount{
+		ID:                uuid.New(),
+		UserID:            &userID,
+		AccountHolderName: "Test Holder",
+		AccountNumber:     "3333333333",
+		RoutingNumber:     "021000021",
+		Validated:         true,
+		ValidationTime:    &now,
+		CreatedAt:         now,
+	}
+	for _, opt := range opts {
+		opt(account)
+	}
+	return account
+}
+
+// NWExternalAccount builds an external account and persists it via the external account repository
+func NWExternalAccount(t *testing.T, db *gorm.DB, opts ...ExternalAccountOption) *models.NorthwindExternalAccount {
+	t.Helper()
+
+	account := NewNWExternalAccount(opts...)
+	if err := repositories.NewNorthwindExternalAccountRepository(db).Create(context.Background(), account); err != nil {
+		t.Fatalf("failed to create northwind external account: %v", err)
+	}
+	return account
+}
+
+func webhookPayload(transfer *models.NorthwindTransfer, status string) json.RawMessage {
+	amount, _ := transfer.Amount.Float64()
+	payload, _ := json.Marshal(models.RegulatorWebhookPayload{
+		EventID:              uuid.NewString(),
+		TransferID:           transfer.ID.String(),
+		NorthwindTransferID:  transfer.NorthwindTransferID.String(),
+		Status:               status,
+		Amount:               amount,
+		Currency:             transfer.Currency,
+		Direction:            transfer.Direction,
+		TransferType:         transfer.TransferType,
+		Timestamp:            time.Now().UTC().Format(time.RFC3339),
+		AuthorizationConsent: transfer.AuthorizationConsent(),
+	})
+	return payload
+}
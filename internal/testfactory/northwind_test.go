@@ -0,0 +1,112 @@
+package testfactory
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/google/uuid"
+)
+
+// The in-memory builders must already satisfy every invariant the BeforeCreate hooks
+// enforce; if a hook changes a factory record, the factory has drifted from the model.
+func TestFactories_SatisfyBeforeCreateHooks(t *testing.T) {
+	t.Run("NorthwindTransfer", func(t *testing.T) {
+		built := NewNWTransfer()
+		hooked := *built
+		if err := hooked.BeforeCreate(nil); err != nil {
+			t.Fatalf("BeforeCreate failed: %v", err)
+		}
+		if !reflect.DeepEqual(*built, hooked) {
+			t.Errorf("BeforeCreate modified factory transfer:\nbuilt:  %+v\nhooked: %+v", *built, hooked)
+		}
+	})
+
+	t.Run("RegulatorNotification", func(t *testing.T) {
+		built := NewRegulatorNotification()
+		hooked := *built
+		if err := hooked.BeforeCreate(nil); err != nil {
+			t.Fatalf("BeforeCreate failed: %v", err)
+		}
+		if !reflect.DeepEqual(*built, hooked) {
+			t.Errorf("BeforeCreate modified factory notification:\nbuilt:  %+v\nhooked: %+v", *built, hooked)
+		}
+	})
+
+	t.Run("NorthwindExternalAccount", func(t *testing.T) {
+		built := NewNWExternalAccount()
+		hooked := *built
+		if err := hooked.BeforeCreate(nil); err != nil {
+			t.Fatalf("BeforeCreate failed: %v", err)
+		}
+		if !reflect.DeepEqual(*built, hooked) {
+			t.Errorf("BeforeCreate modified factory external account:\nbuilt:  %+v\nhooked: %+v", *built, hooked)
+		}
+	})
+}
+
+func TestNWTransfer_PersistsWithOptions(t *testing.T) {
+	db := NewDB(t)
+	userID := uuid.New()
+	consent := models.AuthorizationConsent{
+		Timestamp: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+		IPAddress: "203.0.113.10",
+		Method:    models.ConsentMethodOnline,
+	}
+
+	created := NWTransfer(t, db,
+		WithStatus(models.NWTransferStatusProcessing),
+		WithUser(userID),
+		WithDirection(models.NWTransferDirectionInbound),
+		WithConsent(consent),
+	)
+
+	stored, err := repositories.NewNorthwindTransferRepository(db).GetByID(context.Background(), created.ID)
+	if err != nil {
+		t.Fatalf("failed to load transfer: %v", err)
+	}
+	if stored.Status != models.NWTransferStatusProcessing {
+		t.Errorf("expected status PROCESSING, got %s", stored.Status)
+	}
+	if stored.UserID == nil || *stored.UserID != userID {
+		t.Errorf("expected user %s, got %v", userID, stored.UserID)
+	}
+	if got := stored.AuthorizationConsent(); got == nil || got.IPAddress != consent.IPAddress || got.Method != consent.Method {
+		t.Errorf("expected consent %+v, got %+v", consent, got)
+	}
+}
+
+func TestRegulatorNotification_PersistsLinkedToTransfer(t *testing.T) {
+	db := NewDB(t)
+	transfer := NWTransfer(t, db, WithStatus(models.NWTransferStatusFailed))
+
+	created := RegulatorNotification(t, db, WithTransfer(transfer))
+
+	exists, err := repositories.NewRegulatorNotificationRepository(db).
+		ExistsForTransferAndStatus(context.Background(), transfer.ID, models.NWTransferStatusFailed)
+	if err != nil {
+		t.Fatalf("failed to query notification: %v", err)
+	}
+	if !exists {
+		t.Errorf("expected notification %s for transfer %s with status FAILED", created.ID, transfer.ID)
+	}
+}
+
+func TestNWExternalAccount_PersistsWithOptions(t *testing.T) {
+	db := NewDB(t)
+	userID := uuid.New()
+
+	NWExternalAccount(t, db, WithOwner(userID), WithAccountNumber("4444444444", "021000089"), WithValidated(false))
+
+	stored, err := repositories.NewNorthwindExternalAccountRepository(db).
+		FindByAccountAndRouting(context.Background(), userID, "4444444444", "021000089")
+	if err != nil {
+		t.Fatalf("failed to load external account: %v", err)
+	}
+	if stored.Validated {
+		t.Error("expected account to be unvalidated")
+	}
+}
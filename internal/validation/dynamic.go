@@ -0,0 +1,154 @@
+package validation
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/array/banking-api/internal/config"
+	"github.com/array/banking-api/internal/models"
+	"github.com/go-playground/validator/v10"
+)
+
+// Requester describes the caller a request is validated for
+type Requester struct {
+	IsAdmin bool
+}
+
+type requesterKey struct{}
+
+// WithRequester returns ctx carrying the caller, for rules that depend on who is asking
+func WithRequester(ctx context.Context, requester Requester) context.Context {
+	return context.WithValue(ctx, requesterKey{}, requester)
+}
+
+// RequesterFrom returns the caller carried by ctx; without one the caller is not an admin
+func RequesterFrom(ctx context.Context) Requester {
+	requester, _ := ctx.Value(requesterKey{}).(Requester)
+	return requester
+}
+
+// DomainsProvider returns the names of the domains NorthWind currently publishes
+type DomainsProvider func(ctx context.Context) ([]string, error)
+
+// StringSet is a set of case-insensitive values that can be replaced while the process runs
+type StringSet struct {
+	mu     sync.RWMutex
+	values map[string]bool
+}
+
+// NewStringSet creates a set holding values
+func NewStringSet(values []string) *StringSet {
+	s := &StringSet{}
+	s.Set(values)
+	return s
+}
+
+// Set replaces the set's values
+func (s *StringSet) Set(values []string) {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[strings.ToUpper(value)] = true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values = set
+}
+
+// Contains reports whether value is in the set
+func (s *StringSet) Contains(value string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.values[strings.ToUpper(value)]
+}
+
+// Len returns the number of values in the set
+func (s *StringSet) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.values)
+}
+
+// DynamicRules holds the runtime configuration of the validators that depend on config, NorthWind
+// or the caller. Its sets are read on every validation, so replacing their values takes effect
+// without a restart.
+type DynamicRules struct {
+	// Currencies limits supported_currency; empty allows any currency
+	Currencies *StringSet
+	// AdminOnlyTransferTypes fail nw_transfer_type_allowed unless the caller is an admin
+	AdminOnlyTransferTypes *StringSet
+	// Domains, when set and publishing any transfer type, limits nw_transfer_type_allowed to the
+	// published types
+	Domains DomainsProvider
+	// ReferenceNumbers is the format reference_number requires; nil uses
+	// config.DefaultReferenceNumberPattern
+	ReferenceNumbers *regexp.Regexp
+}
+
+// defaultReferenceNumbers is the reference_number format when none is configured
+var defaultReferenceNumbers = regexp.MustCompile(config.DefaultReferenceNumberPattern)
+
+// NewDynamicRules creates the rules configured by cfg, with transfer types checked against the
+// domains NorthWind publishes when domains is not nil. A reference number pattern that does not
+// compile, which Config.Validate reports, falls back to the default.
+func NewDynamicRules(cfg *config.Config, domains DomainsProvider) *DynamicRules {
+	rules := &DynamicRules{
+		Currencies:             NewStringSet(cfg.NorthWind.SupportedCurrencies),
+		AdminOnlyTransferTypes: NewStringSet(cfg.NorthWind.AdminOnlyTransferTypes),
+		Domains:                domains,
+	}
+	if pattern := cfg.NorthWind.ReferenceNumberPattern; pattern != "" {
+		rules.ReferenceNumbers, _ = regexp.Compile(pattern)
+	}
+	return rules
+}
+
+// register adds the dynamic validators to v
+func (r *DynamicRules) register(v *validator.Validate) {
+	_ = v.RegisterValidationCtx("supported_currency", r.validateSupportedCurrency)
+	_ = v.RegisterValidationCtx("nw_transfer_type_allowed", r.validateTransferTypeAllowed)
+	_ = v.RegisterValidation("reference_number", r.validateReferenceNumber)
+}
+
+// validateReferenceNumber validates a reference number, already normalized, against the
+// configured format
+func (r *DynamicRules) validateReferenceNumber(fl validator.FieldLevel) bool {
+	pattern := r.ReferenceNumbers
+	if pattern == nil {
+		pattern = defaultReferenceNumbers
+	}
+	return pattern.MatchString(fl.Field().String())
+}
+
+// validateSupportedCurrency validates a currency against the configured allowlist
+func (r *DynamicRules) validateSupportedCurrency(_ context.Context, fl validator.FieldLevel) bool {
+	if r.Currencies == nil || r.Currencies.Len() == 0 {
+		return true
+	}
+	return r.Currencies.Contains(fl.Field().String())
+}
+
+// validateTransferTypeAllowed validates that the caller may initiate a NorthWind transfer type and
+// that NorthWind publishes it. When the domains cannot be read the type is allowed, leaving
+// NorthWind to reject it.
+func (r *DynamicRules) validateTransferTypeAllowed(ctx context.Context, fl validator.FieldLevel) bool {
+	transferType := fl.Field().String()
+	if r.AdminOnlyTransferTypes != nil && r.AdminOnlyTransferTypes.Contains(transferType) && !RequesterFrom(ctx).IsAdmin {
+		return false
+	}
+	if r.Domains == nil {
+		return true
+	}
+	names, err := r.Domains(ctx)
+	if err != nil {
+		return true
+	}
+	published := map[string]bool{}
+	for _, name := range names {
+		if name = strings.ToUpper(name); models.IsNWTransferType(name) {
+			published[name] = true
+		}
+	}
+	return len(published) == 0 || published[strings.ToUpper(transferType)]
+}
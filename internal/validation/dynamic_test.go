@@ -0,0 +1,109 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/array/banking-api/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type dynamicRequest struct {
+	Currency     string `json:"currency" validate:"required,supported_currency"`
+	TransferType string `json:"transfer_type" validate:"required,nw_transfer_type,nw_transfer_type_allowed"`
+}
+
+func newDynamicValidator(t *testing.T, cfg *config.Config, domains DomainsProvider) (*Validator, *DynamicRules) {
+	t.Helper()
+	rules := NewDynamicRules(cfg, domains)
+	v := NewValidator(WithDynamicRules(rules))
+	require.NotNil(t, v)
+	return v, rules
+}
+
+func TestDynamicRules_AdminOnlyTransferTypeDependsOnRequester(t *testing.T) {
+	cfg := &config.Config{NorthWind: config.NorthWindConfig{AdminOnlyTransferTypes: []string{"wire"}}}
+	v, _ := newDynamicValidator(t, cfg, nil)
+	wire := &dynamicRequest{Currency: "USD", TransferType: "WIRE"}
+
+	assert.Error(t, v.ValidateCtx(context.Background(), wire), "callers are not admins by default")
+	assert.Error(t, v.ValidateCtx(WithRequester(context.Background(), Requester{}), wire))
+	assert.NoError(t, v.ValidateCtx(WithRequester(context.Background(), Requester{IsAdmin: true}), wire))
+	assert.NoError(t, v.ValidateCtx(context.Background(), &dynamicRequest{Currency: "USD", TransferType: "ACH"}))
+}
+
+func TestDynamicRules_CurrencyAllowlistChangesAtRuntime(t *testing.T) {
+	cfg := &config.Config{NorthWind: config.NorthWindConfig{SupportedCurrencies: []string{"USD"}}}
+	v, rules := newDynamicValidator(t, cfg, nil)
+	ctx := context.Background()
+	eur := &dynamicRequest{Currency: "EUR", TransferType: "ACH"}
+
+	assert.NoError(t, v.ValidateCtx(ctx, &dynamicRequest{Currency: "usd", TransferType: "ACH"}))
+	assert.Error(t, v.ValidateCtx(ctx, eur))
+
+	// The same validator picks up the new allowlist
+	rules.Currencies.Set([]string{"USD", "EUR"})
+	assert.NoError(t, v.ValidateCtx(ctx, eur))
+
+	rules.Currencies.Set(nil)
+	assert.NoError(t, v.ValidateCtx(ctx, &dynamicRequest{Currency: "JPY", TransferType: "ACH"}), "an empty allowlist allows any currency")
+}
+
+func TestDynamicRules_TransferTypesFromDomains(t *testing.T) {
+	published := []string{"ach", "payments"}
+	var domainsErr error
+	domains := func(context.Context) ([]string, error) { return published, domainsErr }
+	v, _ := newDynamicValidator(t, &config.Config{}, domains)
+	ctx := context.Background()
+
+	assert.NoError(t, v.ValidateCtx(ctx, &dynamicRequest{Currency: "USD", TransferType: "ACH"}))
+	assert.Error(t, v.ValidateCtx(ctx, &dynamicRequest{Currency: "USD", TransferType: "RTP"}), "RTP is not published")
+
+	published = []string{"payments"}
+	assert.NoError(t, v.ValidateCtx(ctx, &dynamicRequest{Currency: "USD", TransferType: "RTP"}), "no published transfer types leaves all allowed")
+
+	published, domainsErr = []string{"ach"}, errors.New("northwind unavailable")
+	assert.NoError(t, v.ValidateCtx(ctx, &dynamicRequest{Currency: "USD", TransferType: "RTP"}), "unreadable domains leave the type to NorthWind")
+}
+
+func TestEchoValidator_ValidateCtx(t *testing.T) {
+	cfg := &config.Config{NorthWind: config.NorthWindConfig{AdminOnlyTransferTypes: []string{"WIRE"}}}
+	v, _ := newDynamicValidator(t, cfg, nil)
+	ev, ok := v.Echo().(ContextValidator)
+	require.True(t, ok)
+	wire := &dynamicRequest{Currency: "USD", TransferType: "WIRE"}
+
+	assert.NoError(t, ev.ValidateCtx(WithRequester(context.Background(), Requester{IsAdmin: true}), wire))
+	assert.Error(t, ev.Validate(wire), "without a context the caller is not an admin")
+}
+
+func TestNewValidator_DynamicRulesDefaultToAllowAll(t *testing.T) {
+	v := NewValidator()
+	assert.NoError(t, v.ValidateCtx(context.Background(), &dynamicRequest{Currency: "XYZ", TransferType: "WIRE"}))
+	assert.Error(t, v.ValidateCtx(context.Background(), &dynamicRequest{Currency: "USD", TransferType: "CHEQUE"}), "static rules still apply")
+}
+
+func TestDynamicRules_ReferenceNumber(t *testing.T) {
+	type referenceRequest struct {
+		ReferenceNumber string `json:"reference_number" validate:"omitempty,reference_number"`
+	}
+	ctx := context.Background()
+
+	v := NewValidator()
+	for _, ok := range []string{"", "INV-2024-001", "REF001"} {
+		assert.NoError(t, v.ValidateCtx(ctx, &referenceRequest{ReferenceNumber: ok}), ok)
+	}
+	for _, bad := range []string{"REF-001/A", "ref-001", "REF01", "REF 001", strings.Repeat("A", 41)} {
+		assert.Error(t, v.ValidateCtx(ctx, &referenceRequest{ReferenceNumber: bad}), "the default format rejects %q", bad)
+	}
+
+	v, _ = newDynamicValidator(t, &config.Config{NorthWind: config.NorthWindConfig{ReferenceNumberPattern: `^[A-Z0-9/\-]{4,20}$`}}, nil)
+	assert.NoError(t, v.ValidateCtx(ctx, &referenceRequest{ReferenceNumber: "REF-001/A"}), "a configured format replaces the default")
+	assert.Error(t, v.ValidateCtx(ctx, &referenceRequest{ReferenceNumber: "REF-001.A"}))
+
+	v, _ = newDynamicValidator(t, &config.Config{NorthWind: config.NorthWindConfig{ReferenceNumberPattern: `([`}}, nil)
+	assert.Error(t, v.ValidateCtx(ctx, &referenceRequest{ReferenceNumber: "REF-001/A"}), "an invalid format falls back to the default")
+}
@@ -1,11 +1,14 @@
 package validation
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"regexp"
 	"strings"
+	"sync"
 
+	"github.com/array/banking-api/internal/models"
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 )
@@ -20,24 +23,44 @@ func (v *Validator) GetValidate() *validator.Validate {
 	return v.validate
 }
 
-// singleton instance of the validator
-var instance *Validator
+// ValidateCtx validates s, passing ctx to the context-aware validators
+func (v *Validator) ValidateCtx(ctx context.Context, s interface{}) error {
+	return v.validate.StructCtx(ctx, s)
+}
+
+// Echo returns an echo.Validator backed by v that also implements ContextValidator
+func (v *Validator) Echo() echo.Validator {
+	return &echoValidator{validate: v.validate}
+}
 
-// GetValidator returns the singleton validator instance
+// singleton instance of the validator, with the default dynamic rules
+var (
+	instance     *Validator
+	instanceOnce sync.Once
+)
+
+// GetValidator returns the singleton validator instance. Its dynamic rules allow every currency
+// and transfer type; build a validator with NewValidator(WithDynamicRules(...)) to configure them.
 func GetValidator() *Validator {
-	if instance == nil {
+	instanceOnce.Do(func() {
 		instance = NewValidator()
-	}
+	})
 	return instance
 }
 
 // EchoValidator returns an echo.Validator that uses our custom validation rules.
 // Use this to wire validation into Echo: e.Validator = validation.EchoValidator()
 func EchoValidator() echo.Validator {
-	return &echoValidator{validate: GetValidator().GetValidate()}
+	return GetValidator().Echo()
 }
 
-// echoValidator implements echo.Validator interface
+// ContextValidator is an echo.Validator that can also validate with the request's context
+type ContextValidator interface {
+	echo.Validator
+	ValidateCtx(ctx context.Context, i interface{}) error
+}
+
+// echoValidator implements echo.Validator and ContextValidator
 type echoValidator struct {
 	validate *validator.Validate
 }
@@ -47,8 +70,33 @@ func (v *echoValidator) Validate(i interface{}) error {
 	return v.validate.Struct(i)
 }
 
-// NewValidator creates a new validator instance with custom rules and configuration
-func NewValidator() *Validator {
+// ValidateCtx implements ContextValidator
+func (v *echoValidator) ValidateCtx(ctx context.Context, i interface{}) error {
+	return v.validate.StructCtx(ctx, i)
+}
+
+// Option configures a validator built by NewValidator
+type Option func(*options)
+
+type options struct {
+	rules *DynamicRules
+}
+
+// WithDynamicRules sets the configuration of the config-backed and context-aware validators
+func WithDynamicRules(rules *DynamicRules) Option {
+	return func(o *options) {
+		o.rules = rules
+	}
+}
+
+// NewValidator creates a new validator instance with custom rules and configuration. Without
+// WithDynamicRules the dynamic validators allow every value.
+func NewValidator(opts ...Option) *Validator {
+	o := options{rules: &DynamicRules{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	v := validator.New()
 
 	_ = v.RegisterValidation("account_number", validateAccountNumber)
@@ -57,6 +105,10 @@ func NewValidator() *Validator {
 	_ = v.RegisterValidation("customer_id", validateCustomerID)
 	_ = v.RegisterValidation("account_type", validateAccountType)
 	_ = v.RegisterValidation("transaction_type", validateTransactionType)
+	_ = v.RegisterValidation("nw_transfer_direction", validateNWTransferDirection)
+	_ = v.RegisterValidation("nw_transfer_type", validateNWTransferType)
+	_ = v.RegisterValidation("nw_transfer_priority", validateNWTransferPriority)
+	o.rules.register(v)
 
 	v.RegisterTagNameFunc(func(fld reflect.StructField) string {
 		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
@@ -150,3 +202,18 @@ func validateTransactionType(fl validator.FieldLevel) bool {
 	}
 	return validTypes[txType]
 }
+
+// validateNWTransferDirection validates a NorthWind transfer direction against models.NWTransferDirections
+func validateNWTransferDirection(fl validator.FieldLevel) bool {
+	return models.IsNWTransferDirection(fl.Field().String())
+}
+
+// validateNWTransferType validates a NorthWind transfer type against models.NWTransferTypes
+func validateNWTransferType(fl validator.FieldLevel) bool {
+	return models.IsNWTransferType(fl.Field().String())
+}
+
+// validateNWTransferPriority validates a NorthWind transfer priority against models.NWTransferPriorities
+func validateNWTransferPriority(fl validator.FieldLevel) bool {
+	return models.IsNWTransferPriority(fl.Field().String())
+}
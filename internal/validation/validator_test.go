@@ -15,7 +15,6 @@ func TestNewValidator(t *testing.T) {
 }
 
 func TestGetValidator(t *testing.T) {
-	instance = nil // reset singleton for test
 	v1 := GetValidator()
 	v2 := GetValidator()
 	require.NotNil(t, v1)
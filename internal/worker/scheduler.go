@@ -3,6 +3,7 @@ package worker
 import (
 	"context"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/array/banking-api/internal/services"
@@ -15,6 +16,32 @@ type Scheduler struct {
 	regulator *services.RegulatorService
 	interval  time.Duration
 	logger    *slog.Logger
+	readOnly  *services.ReadOnlyService
+	// builtins are polling and regulator retries, tracked like jobs so Status reports them
+	builtins []*scheduledJob
+	jobs     []*scheduledJob
+
+	// mu guards the run history of the jobs, which Status reads from other goroutines
+	mu sync.Mutex
+}
+
+// Job is periodic maintenance work run by the scheduler after polling and retries. Jobs run on
+// the scheduler's goroutine, so Run should bound its own duration.
+type Job struct {
+	Name string
+	// Every is the minimum time between runs; it is rounded up to the scheduler interval
+	Every time.Duration
+	Run   func(ctx context.Context) error
+	// ReadOnlyWorker, when set, pauses the job in read-only mode if the worker matrix says so
+	ReadOnlyWorker services.ReadOnlyWorker
+}
+
+type scheduledJob struct {
+	Job
+	lastRun      time.Time
+	lastDuration time.Duration
+	lastErr      error
+	paused       bool
 }
 
 // NewScheduler creates a unified scheduler for NorthWind polling and regulator retries
@@ -27,16 +54,40 @@ func NewScheduler(
 	if logger == nil {
 		logger = slog.Default()
 	}
-	return &Scheduler{
+	s := &Scheduler{
 		polling:   polling,
 		regulator: regulator,
 		interval:  interval,
 		logger:    logger,
 	}
+	s.builtins = []*scheduledJob{
+		{Job: Job{Name: "northwind_polling", Every: interval, ReadOnlyWorker: services.ReadOnlyWorkerPolling, Run: func(ctx context.Context) error {
+			s.polling.PollOnce(ctx)
+			return nil
+		}}},
+		{Job: Job{Name: "regulator_retry", Every: interval, ReadOnlyWorker: services.ReadOnlyWorkerRegulatorDeliveries, Run: func(ctx context.Context) error {
+			s.regulator.RetryOnce(ctx)
+			return nil
+		}}},
+	}
+	return s
+}
+
+// SetReadOnly pauses the jobs tagged with a ReadOnlyWorker while read-only mode is on and the
+// worker matrix pauses that worker. Without it jobs always run.
+func (s *Scheduler) SetReadOnly(readOnly *services.ReadOnlyService) {
+	s.readOnly = readOnly
+}
+
+// Register adds a job to the scheduler. It must be called before Start. A job first runs on the
+// first tick.
+func (s *Scheduler) Register(job Job) {
+	s.jobs = append(s.jobs, &scheduledJob{Job: job})
 }
 
 // Start runs the scheduler loop until ctx is cancelled.
-// Each tick: (1) poll NorthWind for transfer status updates, (2) retry pending regulator notifications.
+// Each tick: (1) poll NorthWind for transfer status updates, (2) retry pending regulator notifications,
+// (3) run any registered jobs that are due.
 func (s *Scheduler) Start(ctx context.Context) {
 	s.logger.Info("Unified worker scheduler started", "interval", s.interval)
 	ticker := time.NewTicker(s.interval)
@@ -48,8 +99,84 @@ func (s *Scheduler) Start(ctx context.Context) {
 			s.logger.Info("Unified worker scheduler stopping")
 			return
 		case <-ticker.C:
-			s.polling.PollOnce(ctx)
-			s.regulator.RetryOnce(ctx)
+			now := time.Now()
+			for _, job := range s.builtins {
+				s.runJob(ctx, job, now)
+			}
+			s.runDueJobs(ctx, now)
+		}
+	}
+}
+
+func (s *Scheduler) runDueJobs(ctx context.Context, now time.Time) {
+	for _, job := range s.jobs {
+		s.mu.Lock()
+		due := job.lastRun.IsZero() || now.Sub(job.lastRun) >= job.Every
+		s.mu.Unlock()
+		if due {
+			s.runJob(ctx, job, now)
 		}
 	}
 }
+
+// runJob runs job and records the outcome for Status. A job paused by read-only mode is skipped
+// without recording a run, so it runs on the first tick after the mode is lifted.
+func (s *Scheduler) runJob(ctx context.Context, job *scheduledJob, now time.Time) {
+	paused := job.ReadOnlyWorker != "" && s.readOnly != nil && !s.readOnly.WorkerAllowed(ctx, job.ReadOnlyWorker)
+	s.mu.Lock()
+	if job.paused != paused {
+		s.logger.Info("Scheduled job read-only pause changed", "job", job.Name, "paused", paused)
+	}
+	job.paused = paused
+	if !paused {
+		job.lastRun = now
+	}
+	s.mu.Unlock()
+	if paused {
+		return
+	}
+
+	start := time.Now()
+	err := job.Run(ctx)
+	if err != nil {
+		s.logger.Error("Scheduled job failed", "job", job.Name, "error", err)
+	}
+
+	s.mu.Lock()
+	job.lastDuration = time.Since(start)
+	job.lastErr = err
+	s.mu.Unlock()
+}
+
+// Status reports every job the scheduler runs, polling and regulator retries first, with the
+// outcome of its last run. A job that has not run yet has no last or next run time.
+func (s *Scheduler) Status() []services.ScheduledJobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := append(append([]*scheduledJob{}, s.builtins...), s.jobs...)
+	statuses := make([]services.ScheduledJobStatus, 0, len(jobs))
+	for _, job := range jobs {
+		status := services.ScheduledJobStatus{Name: job.Name, EverySeconds: s.period(job).Seconds(), Paused: job.paused}
+		if !job.lastRun.IsZero() {
+			lastRun, nextRun := job.lastRun, job.lastRun.Add(s.period(job))
+			status.LastRunAt = &lastRun
+			status.NextRunAt = &nextRun
+			status.LastDurationMs = job.lastDuration.Milliseconds()
+		}
+		if job.lastErr != nil {
+			status.LastError = job.lastErr.Error()
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// period is the time between runs of job: Every rounded up to a whole number of ticks
+func (s *Scheduler) period(job *scheduledJob) time.Duration {
+	if s.interval <= 0 || job.Every <= s.interval {
+		return s.interval
+	}
+	ticks := (job.Every + s.interval - 1) / s.interval
+	return ticks * s.interval
+}
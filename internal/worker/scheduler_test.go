@@ -19,12 +19,12 @@ func TestNewScheduler_NilLogger(t *testing.T) {
 	defer ctrl.Finish()
 
 	notifRepo := repository_mocks.NewMockRegulatorNotificationRepositoryInterface(ctrl)
-	notifRepo.EXPECT().GetPendingNotifications(20).Return([]models.RegulatorNotification{}, nil).AnyTimes()
+	notifRepo.EXPECT().GetPendingNotifications(gomock.Any(), 20).Return([]models.RegulatorNotification{}, nil).AnyTimes()
 	attemptRepo := repository_mocks.NewMockRegulatorNotificationAttemptRepositoryInterface(ctrl)
 	regulator := services.NewRegulatorService("http://localhost", 2, 60, notifRepo, attemptRepo, nil, nil)
 
 	transferRepo := repository_mocks.NewMockNorthwindTransferRepositoryInterface(ctrl)
-	transferRepo.EXPECT().GetPendingTransfers(50).Return([]models.NorthwindTransfer{}, nil).AnyTimes()
+	transferRepo.EXPECT().GetPendingTransfers(gomock.Any(), models.NWTransferPollableStatusValues(), 50, gomock.Any()).Return([]models.NorthwindTransfer{}, nil).AnyTimes()
 	polling := services.NewNorthwindPollingService(nil, transferRepo, regulator, time.Hour, nil)
 
 	sched := NewScheduler(polling, regulator, time.Second, nil)
@@ -37,12 +37,12 @@ func TestScheduler_Start_StopsOnContextCancel(t *testing.T) {
 	defer ctrl.Finish()
 
 	notifRepo := repository_mocks.NewMockRegulatorNotificationRepositoryInterface(ctrl)
-	notifRepo.EXPECT().GetPendingNotifications(20).Return([]models.RegulatorNotification{}, nil).AnyTimes()
+	notifRepo.EXPECT().GetPendingNotifications(gomock.Any(), 20).Return([]models.RegulatorNotification{}, nil).AnyTimes()
 	attemptRepo := repository_mocks.NewMockRegulatorNotificationAttemptRepositoryInterface(ctrl)
 	regulator := services.NewRegulatorService("http://localhost", 2, 60, notifRepo, attemptRepo, slog.Default(), nil)
 
 	transferRepo := repository_mocks.NewMockNorthwindTransferRepositoryInterface(ctrl)
-	transferRepo.EXPECT().GetPendingTransfers(50).Return([]models.NorthwindTransfer{}, nil).AnyTimes()
+	transferRepo.EXPECT().GetPendingTransfers(gomock.Any(), models.NWTransferPollableStatusValues(), 50, gomock.Any()).Return([]models.NorthwindTransfer{}, nil).AnyTimes()
 	polling := services.NewNorthwindPollingService(nil, transferRepo, regulator, time.Hour, slog.Default())
 
 	sched := NewScheduler(polling, regulator, 10*time.Second, slog.Default())
@@ -68,12 +68,12 @@ func TestScheduler_Start_RunsOneTickThenStops(t *testing.T) {
 	defer ctrl.Finish()
 
 	notifRepo := repository_mocks.NewMockRegulatorNotificationRepositoryInterface(ctrl)
-	notifRepo.EXPECT().GetPendingNotifications(20).Return([]models.RegulatorNotification{}, nil).AnyTimes()
+	notifRepo.EXPECT().GetPendingNotifications(gomock.Any(), 20).Return([]models.RegulatorNotification{}, nil).AnyTimes()
 	attemptRepo := repository_mocks.NewMockRegulatorNotificationAttemptRepositoryInterface(ctrl)
 	regulator := services.NewRegulatorService("http://localhost", 2, 60, notifRepo, attemptRepo, slog.Default(), nil)
 
 	transferRepo := repository_mocks.NewMockNorthwindTransferRepositoryInterface(ctrl)
-	transferRepo.EXPECT().GetPendingTransfers(50).Return([]models.NorthwindTransfer{}, nil).AnyTimes()
+	transferRepo.EXPECT().GetPendingTransfers(gomock.Any(), models.NWTransferPollableStatusValues(), 50, gomock.Any()).Return([]models.NorthwindTransfer{}, nil).AnyTimes()
 	polling := services.NewNorthwindPollingService(nil, transferRepo, regulator, time.Hour, slog.Default())
 
 	sched := NewScheduler(polling, regulator, 5*time.Millisecond, slog.Default())
@@ -95,3 +95,88 @@ func TestScheduler_Start_RunsOneTickThenStops(t *testing.T) {
 		t.Fatal("Start did not return after cancel")
 	}
 }
+
+func TestScheduler_RunDueJobs_RespectsEvery(t *testing.T) {
+	sched := NewScheduler(nil, nil, time.Second, slog.Default())
+	runs := 0
+	sched.Register(Job{Name: "counter", Every: time.Hour, Run: func(ctx context.Context) error {
+		runs++
+		return nil
+	}})
+	sched.Register(Job{Name: "failing", Every: time.Hour, Run: func(ctx context.Context) error {
+		return assert.AnError
+	}})
+
+	start := time.Now()
+	sched.runDueJobs(context.Background(), start)
+	sched.runDueJobs(context.Background(), start.Add(30*time.Minute))
+	assert.Equal(t, 1, runs, "job ran again before Every elapsed")
+
+	sched.runDueJobs(context.Background(), start.Add(time.Hour))
+	assert.Equal(t, 2, runs)
+}
+
+func TestScheduler_Status(t *testing.T) {
+	sched := NewScheduler(nil, nil, time.Second, slog.Default())
+	sched.Register(Job{Name: "hourly", Every: time.Hour, Run: func(ctx context.Context) error { return nil }})
+	sched.Register(Job{Name: "failing", Every: 1500 * time.Millisecond, Run: func(ctx context.Context) error {
+		return assert.AnError
+	}})
+
+	statuses := sched.Status()
+	require.Len(t, statuses, 4)
+	assert.Equal(t, []string{"northwind_polling", "regulator_retry", "hourly", "failing"},
+		[]string{statuses[0].Name, statuses[1].Name, statuses[2].Name, statuses[3].Name})
+	assert.Nil(t, statuses[2].LastRunAt, "a job that has not run has no last run")
+
+	start := time.Now()
+	sched.runDueJobs(context.Background(), start)
+	statuses = sched.Status()
+	require.NotNil(t, statuses[2].LastRunAt)
+	assert.Equal(t, start.Add(time.Hour), *statuses[2].NextRunAt)
+	assert.Empty(t, statuses[2].LastError)
+	assert.Equal(t, assert.AnError.Error(), statuses[3].LastError)
+	assert.Equal(t, 2.0, statuses[3].EverySeconds, "Every is rounded up to whole ticks")
+	assert.Equal(t, start.Add(2*time.Second), *statuses[3].NextRunAt)
+}
+
+func TestScheduler_ReadOnlyWorkerMatrix(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := repository_mocks.NewMockReadOnlyModeRepositoryInterface(ctrl)
+	enabled := true
+	repo.EXPECT().Get(gomock.Any()).DoAndReturn(func(context.Context) (*models.ReadOnlyMode, error) {
+		return &models.ReadOnlyMode{Enabled: enabled}, nil
+	}).AnyTimes()
+	workers, err := services.ParseReadOnlyWorkers("polling=run,initiation_retries=pause")
+	require.NoError(t, err)
+
+	sched := NewScheduler(nil, nil, time.Second, slog.Default())
+	sched.SetReadOnly(services.NewReadOnlyService(repo, false, workers, slog.Default()))
+	runs := map[string]int{}
+	for _, job := range []Job{
+		{Name: "polling", ReadOnlyWorker: services.ReadOnlyWorkerPolling},
+		{Name: "initiations", ReadOnlyWorker: services.ReadOnlyWorkerInitiationRetries},
+		{Name: "cleanup"},
+	} {
+		name := job.Name
+		job.Run = func(ctx context.Context) error {
+			runs[name]++
+			return nil
+		}
+		sched.Register(job)
+	}
+
+	start := time.Now()
+	sched.runDueJobs(context.Background(), start)
+	assert.Equal(t, map[string]int{"polling": 1, "cleanup": 1}, runs)
+	statuses := sched.Status()
+	assert.True(t, statuses[3].Paused)
+	assert.Nil(t, statuses[3].LastRunAt, "a paused job records no run")
+
+	// Once the mode is lifted, the paused job runs on the next tick
+	enabled = false
+	sched.SetReadOnly(services.NewReadOnlyService(repo, false, workers, slog.Default()))
+	sched.runDueJobs(context.Background(), start.Add(time.Second))
+	assert.Equal(t, 1, runs["initiations"])
+	assert.False(t, sched.Status()[3].Paused)
+}
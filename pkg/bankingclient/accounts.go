@@ -0,0 +1,35 @@
+package bankingclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// RegisterExternalAccount validates an account at another bank with NorthWind and registers it
+// for transfers. When NorthWind reports the account invalid it returns ErrAccountValidationFailed
+// together with the response, whose Validation explains why.
+func (c *Client) RegisterExternalAccount(ctx context.Context, req RegisterExternalAccountRequest) (*RegisterExternalAccountResponse, error) {
+	env, err := c.do(ctx, request{
+		method: http.MethodPost,
+		path:   "/northwind/external-accounts/validate-and-register",
+		body:   req,
+	})
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity && apiErr.Code == "" && env != nil && len(env.Data) > 0 {
+		var resp RegisterExternalAccountResponse
+		if decodeErr := decodeData(env, &resp); decodeErr != nil {
+			return nil, decodeErr
+		}
+		return &resp, fmt.Errorf("%w: %s", ErrAccountValidationFailed, env.Message)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var resp RegisterExternalAccountResponse
+	if err := decodeData(env, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,241 @@
+// Package bankingclient is a Go client for the banking API's NorthWind transfer and external
+// account endpoints. It only depends on the standard library so services outside this module
+// can import it; its types mirror the API's JSON and are checked against the real handlers by
+// the package's contract tests.
+package bankingclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTimeout bounds each HTTP request made by the client
+	DefaultTimeout = 30 * time.Second
+	// DefaultMaxRetries is how many times a request that is safe to repeat is retried after a
+	// transport error or a 502, 503 or 504
+	DefaultMaxRetries = 2
+
+	idempotencyKeyHeader = "Idempotency-Key"
+	retryBackoff         = 200 * time.Millisecond
+)
+
+// ErrInvalidBaseURL is returned by New when the base URL is not an absolute http(s) URL
+var ErrInvalidBaseURL = errors.New("bankingclient: base URL must be an absolute http or https URL")
+
+// TokenSource returns the access token sent with each request. It is called per request, so it
+// can refresh tokens that are about to expire.
+type TokenSource func(ctx context.Context) (string, error)
+
+// Client calls the banking API. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	token      TokenSource
+	userAgent  string
+	maxRetries int
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithToken sends a fixed access token with every request
+func WithToken(token string) Option {
+	return WithTokenSource(func(context.Context) (string, error) { return token, nil })
+}
+
+// WithTokenSource fetches the access token for every request from source
+func WithTokenSource(source TokenSource) Option {
+	return func(c *Client) {
+		c.token = source
+	}
+}
+
+// WithHTTPClient replaces the default HTTP client, e.g. to add tracing or change the timeout
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// WithUserAgent sets the User-Agent header, so the API's logs show which service is calling
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithMaxRetries sets how many times a request that is safe to repeat is retried; zero disables
+// retries
+func WithMaxRetries(maxRetries int) Option {
+	return func(c *Client) {
+		if maxRetries >= 0 {
+			c.maxRetries = maxRetries
+		}
+	}
+}
+
+// New creates a client for the API served at baseURL, e.g. "https://bank.internal". The
+// /api/v1 prefix is added by the client.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, ErrInvalidBaseURL
+	}
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/") + "/api/v1",
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		maxRetries: DefaultMaxRetries,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// NewIdempotencyKey returns a random key for a request that creates something. Reuse the same
+// key when retrying the request so the API does not act on it twice.
+func NewIdempotencyKey() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// envelope is the API's success response: the payload, a message and optional paging metadata
+type envelope struct {
+	Data    json.RawMessage `json:"data"`
+	Message string          `json:"message"`
+	Meta    json.RawMessage `json:"meta"`
+}
+
+// request describes one API call
+type request struct {
+	method         string
+	path           string
+	query          url.Values
+	body           interface{}
+	idempotencyKey string
+}
+
+// retryable reports whether repeating the request cannot act on it twice
+func (r request) retryable() bool {
+	return r.method == http.MethodGet || r.idempotencyKey != ""
+}
+
+// do sends the request and returns the decoded envelope of a 2xx response. Other responses are
+// returned as *APIError alongside the envelope, which some endpoints fill on failure too.
+func (c *Client) do(ctx context.Context, r request) (*envelope, error) {
+	var body []byte
+	if r.body != nil {
+		var err error
+		if body, err = json.Marshal(r.body); err != nil {
+			return nil, fmt.Errorf("bankingclient: failed to encode request: %w", err)
+		}
+	}
+	target := c.baseURL + r.path
+	if len(r.query) > 0 {
+		target += "?" + r.query.Encode()
+	}
+
+	attempts := 1
+	if r.retryable() {
+		attempts += c.maxRetries
+	}
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(retryBackoff * time.Duration(attempt)):
+			}
+		}
+
+		status, respBody, err := c.send(ctx, r, target, body)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			lastErr = err
+			continue
+		}
+		if status == http.StatusBadGateway || status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout {
+			lastErr = newAPIError(status, respBody)
+			continue
+		}
+		return decodeResponse(status, respBody)
+	}
+	return nil, lastErr
+}
+
+func (c *Client) send(ctx context.Context, r request, target string, body []byte) (int, []byte, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, r.method, target, reader)
+	if err != nil {
+		return 0, nil, fmt.Errorf("bankingclient: failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if r.idempotencyKey != "" {
+		req.Header.Set(idempotencyKeyHeader, r.idempotencyKey)
+	}
+	if c.token != nil {
+		token, err := c.token(ctx)
+		if err != nil {
+			return 0, nil, fmt.Errorf("bankingclient: failed to get access token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("bankingclient: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("bankingclient: failed to read response: %w", err)
+	}
+	return resp.StatusCode, respBody, nil
+}
+
+func decodeResponse(status int, body []byte) (*envelope, error) {
+	var env envelope
+	if status >= 200 && status < 300 {
+		if err := json.Unmarshal(body, &env); err != nil {
+			return nil, fmt.Errorf("bankingclient: failed to decode response: %w", err)
+		}
+		return &env, nil
+	}
+	// Ignore decode errors: error bodies are not always envelopes
+	_ = json.Unmarshal(body, &env)
+	return &env, newAPIError(status, body)
+}
+
+// decodeData decodes the envelope's data into out
+func decodeData(env *envelope, out interface{}) error {
+	if err := json.Unmarshal(env.Data, out); err != nil {
+		return fmt.Errorf("bankingclient: failed to decode response data: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,345 @@
+package bankingclient_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/array/banking-api/internal/config"
+	"github.com/array/banking-api/internal/handlers"
+	"github.com/array/banking-api/internal/idempotency"
+	"github.com/array/banking-api/internal/integrations/northwind"
+	"github.com/array/banking-api/internal/middleware"
+	"github.com/array/banking-api/internal/models"
+	"github.com/array/banking-api/internal/repositories"
+	"github.com/array/banking-api/internal/services"
+	"github.com/array/banking-api/internal/testfactory"
+	"github.com/array/banking-api/internal/validation"
+	"github.com/array/banking-api/pkg/bankingclient"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// contractEnv serves the real NorthWind handlers, backed by an in-memory database and a
+// NorthWind stub, so the client is exercised against the API's actual JSON
+type contractEnv struct {
+	db     *gorm.DB
+	server *httptest.Server
+	userID uuid.UUID
+	token  string
+	// validAccounts decides the stub's answer to account validation
+	validAccounts bool
+	// raw holds the last response body the API returned, for strict decoding
+	raw []byte
+}
+
+func newContractEnv(t *testing.T) *contractEnv {
+	t.Helper()
+	env := &contractEnv{db: testfactory.NewDB(t), userID: uuid.New(), validAccounts: true}
+
+	nwServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/external/transfers/validate":
+			_ = json.NewEncoder(w).Encode(northwind.TransferValidationResponse{Valid: true})
+		case strings.HasSuffix(r.URL.Path, "/balance"):
+			_ = json.NewEncoder(w).Encode(northwind.AccountBalance{AvailableBalance: 10000, Currency: "USD"})
+		case r.URL.Path == "/external/transfers/initiate":
+			fee := 0.5
+			_ = json.NewEncoder(w).Encode(northwind.TransferResponse{
+				TransferID:             uuid.New().String(),
+				Status:                 "PENDING",
+				Amount:                 250,
+				ExpectedCompletionDate: "2026-03-04T12:00:00Z",
+				Fee:                    &fee,
+			})
+		case r.URL.Path == "/external/accounts/validate":
+			var req northwind.AccountValidationRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			resp := northwind.AccountValidationResponse{Valid: env.validAccounts, AccountNumber: req.AccountNumber, RoutingNumber: req.RoutingNumber}
+			if !env.validAccounts {
+				resp.Message = "Account not found"
+			}
+			_ = json.NewEncoder(w).Encode(resp)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(nwServer.Close)
+
+	privateKey, publicKey, err := config.GenerateRSAKeyPair()
+	require.NoError(t, err)
+	tokenSvc := services.NewTokenService(&config.JWTConfig{
+		PrivateKey:          privateKey,
+		PublicKey:           publicKey,
+		Issuer:              "banking-api",
+		AccessTokenDuration: time.Hour,
+	})
+	env.token, _, err = tokenSvc.(*services.TokenService).GenerateAccessToken(&models.User{
+		ID:    env.userID,
+		Email: "sdk@example.com",
+		Role:  models.RoleCustomer,
+	})
+	require.NoError(t, err)
+
+	nwClient := northwind.NewClient(nwServer.URL, "test-key")
+	transferSvc := services.NewNorthwindTransferService(nwClient, repositories.NewNorthwindTransferRepository(env.db), nil, nil, slog.Default())
+	accountSvc := services.NewNorthwindAccountService(nwClient, repositories.NewNorthwindExternalAccountRepository(env.db), slog.Default())
+	handler := handlers.NewNorthwindHandler(nwClient, accountSvc, transferSvc, nil, nil,
+		&config.Config{Server: config.ServerConfig{Environment: "testing"}})
+
+	e := echo.New()
+	e.Validator = validation.EchoValidator()
+	e.HTTPErrorHandler = middleware.CustomHTTPErrorHandler
+	nw := e.Group("/api/v1/northwind", middleware.RequireAuth(tokenSvc, repositories.NewBlacklistedTokenRepository(env.db)))
+	nw.POST("/external-accounts/validate-and-register", handler.ValidateAndRegister)
+	nw.POST("/transfers", handler.CreateTransfer, middleware.Idempotency(idempotency.NewMemoryStore(), time.Minute, time.Hour))
+	nw.GET("/transfers", handler.ListTransfers)
+	nw.GET("/transfers/:id", handler.GetTransfer)
+
+	env.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, r)
+		env.raw = rec.Body.Bytes()
+		for k, v := range rec.Header() {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(rec.Code)
+		_, _ = w.Write(rec.Body.Bytes())
+	}))
+	t.Cleanup(env.server.Close)
+	return env
+}
+
+func (env *contractEnv) client(t *testing.T, opts ...bankingclient.Option) *bankingclient.Client {
+	t.Helper()
+	client, err := bankingclient.New(env.server.URL, append([]bankingclient.Option{bankingclient.WithToken(env.token)}, opts...)...)
+	require.NoError(t, err)
+	return client
+}
+
+// decodeStrict decodes the data of the last API response into out, failing on any field the
+// client's type does not know about, so new API fields show up as contract drift
+func (env *contractEnv) decodeStrict(t *testing.T, out interface{}) {
+	t.Helper()
+	var resp struct {
+		Data json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(env.raw, &resp))
+	dec := json.NewDecoder(bytes.NewReader(resp.Data))
+	dec.DisallowUnknownFields()
+	require.NoError(t, dec.Decode(out), "API response has fields the client does not model: %s", resp.Data)
+}
+
+func outboundRequest() bankingclient.CreateTransferRequest {
+	return bankingclient.CreateTransferRequest{
+		Amount:             250,
+		Currency:           "USD",
+		Description:        "Invoice 42",
+		Direction:          bankingclient.DirectionOutbound,
+		TransferType:       bankingclient.TransferTypeACH,
+		SourceAccount:      bankingclient.AccountDetails{AccountHolderName: "Source", AccountNumber: "1111111111"},
+		DestinationAccount: bankingclient.AccountDetails{AccountHolderName: "Destination", AccountNumber: "2222222222", RoutingNumber: "021000021"},
+	}
+}
+
+func TestContract_CreateTransferRequest_MatchesAPI(t *testing.T) {
+	consent := &bankingclient.AuthorizationConsent{Timestamp: time.Now().UTC(), IPAddress: "203.0.113.7", Method: "web"}
+	req := outboundRequest()
+	req.ReferenceNumber = "REF-1"
+	req.ScheduledDate = "2026-03-04"
+	req.AuthorizationConsent = consent
+	req.Force = true
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	var apiReq services.CreateTransferRequest
+	require.NoError(t, dec.Decode(&apiReq), "client sends fields the API does not accept: %s", body)
+	assert.Equal(t, "REF-1", apiReq.ReferenceNumber)
+	assert.True(t, apiReq.Force)
+	require.NotNil(t, apiReq.AuthorizationConsent)
+	assert.Equal(t, "203.0.113.7", apiReq.AuthorizationConsent.IPAddress)
+}
+
+func TestContract_CreateGetAndListTransfers(t *testing.T) {
+	env := newContractEnv(t)
+	client := env.client(t)
+	ctx := context.Background()
+
+	created, err := client.CreateTransfer(ctx, outboundRequest(), "")
+	require.NoError(t, err)
+	var strictCreate bankingclient.CreateTransferResponse
+	env.decodeStrict(t, &strictCreate)
+	require.NotNil(t, created.Transfer)
+	require.NotNil(t, created.Initiation)
+	assert.Equal(t, env.userID.String(), created.Transfer.UserID)
+	assert.Equal(t, "250", created.Transfer.Amount)
+	assert.Equal(t, bankingclient.StatusPending, created.Transfer.Status)
+	assert.Equal(t, "Invoice 42", created.Transfer.Description)
+	assert.Equal(t, created.Transfer.NorthwindTransferID, created.Initiation.NorthwindTransferID)
+	assert.Equal(t, "0.5", created.Initiation.Fee)
+
+	got, err := client.GetTransfer(ctx, created.Transfer.ID)
+	require.NoError(t, err)
+	var strictGet bankingclient.Transfer
+	env.decodeStrict(t, &strictGet)
+	assert.Equal(t, created.Transfer.ID, got.ID)
+	assert.Equal(t, created.Transfer.ReferenceNumber, got.ReferenceNumber)
+
+	page, err := client.ListTransfers(ctx, bankingclient.ListTransfersOptions{Status: bankingclient.StatusPending}, "")
+	require.NoError(t, err)
+	var strictList []bankingclient.Transfer
+	env.decodeStrict(t, &strictList)
+	require.Len(t, page.Transfers, 1)
+	assert.Equal(t, created.Transfer.ID, page.Transfers[0].ID)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestContract_CreateTransfer_IdempotencyKeyReplays(t *testing.T) {
+	env := newContractEnv(t)
+	client := env.client(t)
+	ctx := context.Background()
+	key := bankingclient.NewIdempotencyKey()
+
+	first, err := client.CreateTransfer(ctx, outboundRequest(), key)
+	require.NoError(t, err)
+	second, err := client.CreateTransfer(ctx, outboundRequest(), key)
+	require.NoError(t, err)
+	assert.Equal(t, first.Transfer.ID, second.Transfer.ID)
+
+	var count int64
+	require.NoError(t, env.db.Model(&models.NorthwindTransfer{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestContract_TransfersIterator_WalksEveryPage(t *testing.T) {
+	env := newContractEnv(t)
+	client := env.client(t)
+	base := time.Now().Add(-time.Hour)
+	want := map[string]bool{}
+	for i := 0; i < 5; i++ {
+		transfer := testfactory.NWTransfer(t, env.db, testfactory.WithUser(env.userID), testfactory.WithCreatedAt(base.Add(time.Duration(i)*time.Minute)))
+		want[transfer.ID.String()] = true
+	}
+	testfactory.NWTransfer(t, env.db, testfactory.WithUser(uuid.New()))
+
+	it := client.Transfers(bankingclient.ListTransfersOptions{Limit: 2})
+	var got []string
+	for it.Next(context.Background()) {
+		got = append(got, it.Transfer().ID)
+	}
+	require.NoError(t, it.Err())
+	require.Len(t, got, len(want))
+	for _, id := range got {
+		assert.True(t, want[id], "unexpected transfer %s", id)
+	}
+}
+
+func TestContract_ErrorsMapToSentinels(t *testing.T) {
+	env := newContractEnv(t)
+	ctx := context.Background()
+
+	anonymous, err := bankingclient.New(env.server.URL)
+	require.NoError(t, err)
+	_, err = anonymous.GetTransfer(ctx, uuid.NewString())
+	assert.ErrorIs(t, err, bankingclient.ErrUnauthorized)
+
+	client := env.client(t)
+	_, err = client.GetTransfer(ctx, uuid.NewString())
+	assert.ErrorIs(t, err, bankingclient.ErrNotFound)
+	var apiErr *bankingclient.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "NORTHWIND_TRANSFER_001", apiErr.Code)
+	assert.NotEmpty(t, apiErr.Message)
+
+	req := outboundRequest()
+	req.Direction = "SIDEWAYS"
+	_, err = client.CreateTransfer(ctx, req, "")
+	assert.ErrorIs(t, err, bankingclient.ErrValidation)
+
+	_, err = client.ListTransfers(ctx, bankingclient.ListTransfersOptions{}, "not-a-cursor")
+	assert.ErrorIs(t, err, bankingclient.ErrValidation)
+}
+
+func TestContract_RegisterExternalAccount(t *testing.T) {
+	env := newContractEnv(t)
+	client := env.client(t)
+	req := bankingclient.RegisterExternalAccountRequest{
+		AccountHolderName: "Jane Doe",
+		AccountNumber:     "3333333333",
+		RoutingNumber:     "021000021",
+		InstitutionName:   "Other Bank",
+	}
+
+	resp, err := client.RegisterExternalAccount(context.Background(), req)
+	require.NoError(t, err)
+	var strict bankingclient.RegisterExternalAccountResponse
+	env.decodeStrict(t, &strict)
+	require.NotNil(t, resp.Account)
+	assert.True(t, resp.Account.Validated)
+	assert.Equal(t, "3333333333", resp.Account.AccountNumber)
+	assert.True(t, resp.Validation.Valid)
+
+	env.validAccounts = false
+	req.AccountNumber = "4444444444"
+	resp, err = client.RegisterExternalAccount(context.Background(), req)
+	assert.ErrorIs(t, err, bankingclient.ErrAccountValidationFailed)
+	require.NotNil(t, resp)
+	assert.Nil(t, resp.Account)
+	assert.False(t, resp.Validation.Valid)
+	assert.Equal(t, "Account not found", resp.Validation.Message)
+}
+
+func TestClient_RetriesSafeRequestsOnUnavailable(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"abc","status":"PENDING"},"message":"ok"}`))
+	}))
+	defer server.Close()
+
+	client, err := bankingclient.New(server.URL)
+	require.NoError(t, err)
+	transfer, err := client.GetTransfer(context.Background(), "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "abc", transfer.ID)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestClient_DoesNotRetryUnsafeRequests(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, err := bankingclient.New(server.URL)
+	require.NoError(t, err)
+	_, err = client.RegisterExternalAccount(context.Background(), bankingclient.RegisterExternalAccountRequest{})
+	assert.ErrorIs(t, err, bankingclient.ErrServer)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestNew_RejectsInvalidBaseURL(t *testing.T) {
+	for _, baseURL := range []string{"", "bank.internal", "ftp://bank.internal", "http://"} {
+		_, err := bankingclient.New(baseURL)
+		assert.ErrorIs(t, err, bankingclient.ErrInvalidBaseURL, baseURL)
+	}
+}
@@ -0,0 +1,114 @@
+package bankingclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Errors matched by errors.Is against an *APIError, by HTTP status class or API error code
+var (
+	ErrUnauthorized  = errors.New("bankingclient: unauthorized")
+	ErrForbidden     = errors.New("bankingclient: forbidden")
+	ErrNotFound      = errors.New("bankingclient: not found")
+	ErrValidation    = errors.New("bankingclient: request rejected by validation")
+	ErrConflict      = errors.New("bankingclient: conflict")
+	ErrRateLimited   = errors.New("bankingclient: rate limited")
+	ErrServer        = errors.New("bankingclient: server error")
+	ErrCursorExpired = errors.New("bankingclient: list cursor has expired")
+	// ErrPossibleDuplicate is returned by CreateTransfer when a near-identical transfer was
+	// created recently; resend with Force set to create it anyway
+	ErrPossibleDuplicate = errors.New("bankingclient: possible duplicate transfer")
+	// ErrAccountValidationFailed is returned by RegisterExternalAccount when NorthWind reports
+	// the account as invalid; the response still carries NorthWind's validation result
+	ErrAccountValidationFailed = errors.New("bankingclient: external account validation failed")
+	// ErrAccountNameMismatch is returned by RegisterExternalAccount when the holder name is too
+	// different from the name NorthWind has on file
+	ErrAccountNameMismatch = errors.New("bankingclient: account holder name does not match")
+)
+
+// Error codes the API returns that callers commonly branch on
+const (
+	CodePossibleDuplicate     = "POSSIBLE_DUPLICATE"
+	CodeInsufficientBalance   = "NORTHWIND_TRANSFER_004"
+	CodeConsentRequired       = "NORTHWIND_TRANSFER_007"
+	CodeUnverifiedAccount     = "NORTHWIND_TRANSFER_008"
+	CodeDuplicateReference    = "NORTHWIND_TRANSFER_009"
+	CodeTransferCursorExpired = "NORTHWIND_TRANSFER_011"
+	CodeAccountNameMismatch   = "NORTHWIND_ACCOUNT_004"
+)
+
+// APIError is a non-2xx response from the API. Code, Message, Details and TraceID come from the
+// API's standard error envelope and are empty when the body was not one. RetryAfter is how long
+// the API asked to wait before retrying a 429 or 503, zero when it did not say.
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Details    []string
+	TraceID    string
+	RetryAfter time.Duration
+	Body       string
+}
+
+func newAPIError(status int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: status, Body: string(body)}
+	var parsed struct {
+		Error struct {
+			Code              string   `json:"code"`
+			Message           string   `json:"message"`
+			Details           []string `json:"details"`
+			TraceID           string   `json:"trace_id"`
+			RetryAfterSeconds int      `json:"retry_after_seconds"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &parsed) == nil {
+		apiErr.Code = parsed.Error.Code
+		apiErr.Message = parsed.Error.Message
+		apiErr.Details = parsed.Error.Details
+		apiErr.TraceID = parsed.Error.TraceID
+		apiErr.RetryAfter = time.Duration(parsed.Error.RetryAfterSeconds) * time.Second
+	}
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("bankingclient: HTTP %d: %s", e.StatusCode, e.Body)
+	}
+	msg := fmt.Sprintf("bankingclient: HTTP %d %s: %s", e.StatusCode, e.Code, e.Message)
+	if len(e.Details) > 0 {
+		msg += " (" + strings.Join(e.Details, "; ") + ")"
+	}
+	return msg
+}
+
+// Is maps the error to the package's sentinel errors, so callers can use errors.Is
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrPossibleDuplicate:
+		return e.Code == CodePossibleDuplicate
+	case ErrCursorExpired:
+		return e.Code == CodeTransferCursorExpired
+	case ErrAccountNameMismatch:
+		return e.Code == CodeAccountNameMismatch
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package bankingclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// CreateTransfer initiates a transfer. The idempotency key makes the call safe to retry: the
+// API replays the first response for a repeated key instead of initiating again. Pass the same
+// key when retrying after an error; an empty key gets a fresh one from NewIdempotencyKey, which
+// still covers the client's own retries.
+func (c *Client) CreateTransfer(ctx context.Context, req CreateTransferRequest, idempotencyKey string) (*CreateTransferResponse, error) {
+	if idempotencyKey == "" {
+		idempotencyKey = NewIdempotencyKey()
+	}
+	env, err := c.do(ctx, request{
+		method:         http.MethodPost,
+		path:           "/northwind/transfers",
+		body:           req,
+		idempotencyKey: idempotencyKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var resp CreateTransferResponse
+	if err := decodeData(env, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetTransfer fetches one of the caller's transfers by its ID
+func (c *Client) GetTransfer(ctx context.Context, id string) (*Transfer, error) {
+	env, err := c.do(ctx, request{method: http.MethodGet, path: "/northwind/transfers/" + url.PathEscape(id)})
+	if err != nil {
+		return nil, err
+	}
+	var transfer Transfer
+	if err := decodeData(env, &transfer); err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// ListTransfers fetches one page of the caller's transfers, newest first. Pass an empty cursor
+// for the first page and the returned NextCursor for the next. Use Transfers to iterate over
+// every page instead.
+func (c *Client) ListTransfers(ctx context.Context, opts ListTransfersOptions, cursor string) (*TransferPage, error) {
+	query := url.Values{"cursor": {cursor}}
+	if opts.Status != "" {
+		query.Set("status", opts.Status)
+	}
+	if opts.Direction != "" {
+		query.Set("direction", opts.Direction)
+	}
+	if opts.TransferType != "" {
+		query.Set("transfer_type", opts.TransferType)
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+
+	env, err := c.do(ctx, request{method: http.MethodGet, path: "/northwind/transfers", query: query})
+	if err != nil {
+		return nil, err
+	}
+	page := &TransferPage{}
+	if err := decodeData(env, &page.Transfers); err != nil {
+		return nil, err
+	}
+	var meta struct {
+		NextCursor *string `json:"next_cursor"`
+	}
+	if err := json.Unmarshal(env.Meta, &meta); err != nil {
+		return nil, fmt.Errorf("bankingclient: failed to decode paging metadata: %w", err)
+	}
+	if meta.NextCursor != nil {
+		page.NextCursor = *meta.NextCursor
+	}
+	return page, nil
+}
+
+// TransferIterator walks every transfer matching a listing, fetching pages as needed
+type TransferIterator struct {
+	client  *Client
+	opts    ListTransfersOptions
+	page    []Transfer
+	cursor  string
+	started bool
+	current Transfer
+	err     error
+}
+
+// Transfers returns an iterator over all of the caller's transfers matching opts, newest first.
+//
+//	it := client.Transfers(bankingclient.ListTransfersOptions{Status: bankingclient.StatusPending})
+//	for it.Next(ctx) {
+//		t := it.Transfer()
+//	}
+//	if err := it.Err(); err != nil { ... }
+func (c *Client) Transfers(opts ListTransfersOptions) *TransferIterator {
+	return &TransferIterator{client: c, opts: opts}
+}
+
+// Next advances to the next transfer, fetching the next page when the current one is used up.
+// It returns false when there are no more transfers or a page could not be fetched.
+func (it *TransferIterator) Next(ctx context.Context) bool {
+	for len(it.page) == 0 {
+		if it.err != nil || (it.started && it.cursor == "") {
+			return false
+		}
+		page, err := it.client.ListTransfers(ctx, it.opts, it.cursor)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.started = true
+		it.page, it.cursor = page.Transfers, page.NextCursor
+	}
+	it.current, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Transfer returns the transfer Next advanced to
+func (it *TransferIterator) Transfer() Transfer {
+	return it.current
+}
+
+// Err returns the error that stopped iteration, if any
+func (it *TransferIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,207 @@
+package bankingclient
+
+import "time"
+
+// Transfer directions
+const (
+	DirectionInbound  = "INBOUND"
+	DirectionOutbound = "OUTBOUND"
+)
+
+// Transfer types
+const (
+	TransferTypeACH  = "ACH"
+	TransferTypeWire = "WIRE"
+	TransferTypeRTP  = "RTP"
+)
+
+// Transfer priorities; SAME_DAY is only accepted for ACH transfers before the daily cutoff
+const (
+	PriorityStandard = "STANDARD"
+	PrioritySameDay  = "SAME_DAY"
+)
+
+// Transfer statuses
+const (
+	StatusPending    = "PENDING"
+	StatusProcessing = "PROCESSING"
+	StatusCompleted  = "COMPLETED"
+	StatusFailed     = "FAILED"
+	StatusCancelled  = "CANCELLED"
+	StatusReversed   = "REVERSED"
+)
+
+// Transfer is an external transfer made through NorthWind. Amounts, fees and exchange rates are
+// decimal strings, e.g. "100.5", so no precision is lost in transit.
+type Transfer struct {
+	ID                           string     `json:"id"`
+	UserID                       string     `json:"user_id,omitempty"`
+	NorthwindTransferID          string     `json:"northwind_transfer_id"`
+	ExternalRef                  string     `json:"external_ref,omitempty"`
+	Direction                    string     `json:"direction"`
+	TransferType                 string     `json:"transfer_type"`
+	Priority                     string     `json:"priority,omitempty"`
+	Amount                       string     `json:"amount"`
+	Currency                     string     `json:"currency"`
+	Description                  string     `json:"description,omitempty"`
+	ReferenceNumber              string     `json:"reference_number"`
+	ScheduledDate                *time.Time `json:"scheduled_date,omitempty"`
+	SourceAccountNumber          string     `json:"source_account_number"`
+	SourceRoutingNumber          string     `json:"source_routing_number,omitempty"`
+	SourceAccountHolderName      string     `json:"source_account_holder_name,omitempty"`
+	DestinationAccountNumber     string     `json:"destination_account_number"`
+	DestinationRoutingNumber     string     `json:"destination_routing_number,omitempty"`
+	DestinationAccountHolderName string     `json:"destination_account_holder_name,omitempty"`
+	Status                       string     `json:"status"`
+	ErrorCode                    string     `json:"error_code,omitempty"`
+	ErrorMessage                 string     `json:"error_message,omitempty"`
+	Failure                      *Failure   `json:"failure,omitempty"`
+	InitiatedDate                *time.Time `json:"initiated_date,omitempty"`
+	ProcessingDate               *time.Time `json:"processing_date,omitempty"`
+	ExpectedCompletionDate       *time.Time `json:"expected_completion_date,omitempty"`
+	CompletedDate                *time.Time `json:"completed_date,omitempty"`
+	Fee                          string     `json:"fee,omitempty"`
+	ExchangeRate                 string     `json:"exchange_rate,omitempty"`
+	ConsentTimestamp             *time.Time `json:"consent_timestamp,omitempty"`
+	ConsentIPAddress             string     `json:"consent_ip_address,omitempty"`
+	ConsentMethod                string     `json:"consent_method,omitempty"`
+	Version                      int        `json:"version"`
+	NextPollAt                   *time.Time `json:"next_poll_at,omitempty"`
+	CreatedAt                    time.Time  `json:"created_at"`
+	UpdatedAt                    time.Time  `json:"updated_at"`
+}
+
+// Failure explains why a FAILED transfer failed. Message is meant for the account holder; offer
+// to try the transfer again only when Retryable is true.
+type Failure struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Category  string `json:"category"`
+	Retryable bool   `json:"retryable"`
+}
+
+// AccountDetails identifies one side of a transfer
+type AccountDetails struct {
+	AccountHolderName string `json:"account_holder_name"`
+	AccountNumber     string `json:"account_number"`
+	RoutingNumber     string `json:"routing_number,omitempty"`
+	InstitutionName   string `json:"institution_name,omitempty"`
+}
+
+// AuthorizationConsent records the account holder's consent to debit their account; INBOUND
+// transfers require it
+type AuthorizationConsent struct {
+	Timestamp time.Time `json:"timestamp"`
+	IPAddress string    `json:"ip_address"`
+	Method    string    `json:"method"`
+}
+
+// CreateTransferRequest is the body of a create transfer call
+type CreateTransferRequest struct {
+	Amount             float64        `json:"amount"`
+	Currency           string         `json:"currency"`
+	Description        string         `json:"description,omitempty"`
+	Direction          string         `json:"direction"`
+	TransferType       string         `json:"transfer_type"`
+	Priority           string         `json:"priority,omitempty"`
+	ReferenceNumber    string         `json:"reference_number,omitempty"`
+	ScheduledDate      string         `json:"scheduled_date,omitempty"`
+	SourceAccount      AccountDetails `json:"source_account"`
+	DestinationAccount AccountDetails `json:"destination_account"`
+	// AuthorizationConsent is required for INBOUND transfers
+	AuthorizationConsent *AuthorizationConsent `json:"authorization_consent,omitempty"`
+	// Force creates the transfer even if a near-identical one was created recently
+	Force bool `json:"force,omitempty"`
+}
+
+// CreateTransferResponse is the created transfer and NorthWind's answer to initiating it
+type CreateTransferResponse struct {
+	Transfer         *Transfer      `json:"transfer"`
+	Initiation       *Initiation    `json:"initiation"`
+	ExpectedDuration *DurationStats `json:"expected_duration,omitempty"`
+}
+
+// Initiation is NorthWind's response to initiating a transfer
+type Initiation struct {
+	NorthwindTransferID    string     `json:"northwind_transfer_id"`
+	Status                 string     `json:"status"`
+	ExpectedCompletionDate *time.Time `json:"expected_completion_date,omitempty"`
+	Fee                    string     `json:"fee,omitempty"`
+}
+
+// DurationStats are historical initiated-to-completed durations for a transfer type
+type DurationStats struct {
+	TransferType string  `json:"transfer_type"`
+	SampleCount  int64   `json:"sample_count"`
+	P50Seconds   float64 `json:"p50_seconds"`
+	P95Seconds   float64 `json:"p95_seconds"`
+}
+
+// ListTransfersOptions filters a transfer listing; empty fields do not filter
+type ListTransfersOptions struct {
+	Status       string
+	Direction    string
+	TransferType string
+	// Limit is the page size; the API's default applies when zero
+	Limit int
+}
+
+// TransferPage is one page of a transfer listing. NextCursor fetches the next page and is empty
+// on the last one.
+type TransferPage struct {
+	Transfers  []Transfer
+	NextCursor string
+}
+
+// RegisterExternalAccountRequest is the body of a validate-and-register call
+type RegisterExternalAccountRequest struct {
+	AccountHolderName string `json:"account_holder_name"`
+	AccountNumber     string `json:"account_number"`
+	RoutingNumber     string `json:"routing_number"`
+	InstitutionName   string `json:"institution_name,omitempty"`
+	// OverrideNameMismatch registers the account despite a major holder name mismatch; admin only
+	OverrideNameMismatch bool `json:"override_name_mismatch,omitempty"`
+}
+
+// Outcomes of comparing the typed account holder name with the name NorthWind has on file
+const (
+	NameMatchMatch         = "match"
+	NameMatchMinorMismatch = "minor_mismatch"
+	NameMatchMajorMismatch = "major_mismatch"
+)
+
+// RegisterExternalAccountResponse is the registered account, absent when validation failed,
+// and NorthWind's validation result
+type RegisterExternalAccountResponse struct {
+	Account    *ExternalAccount   `json:"account,omitempty"`
+	Validation *AccountValidation `json:"validation"`
+	NameMatch  string             `json:"name_match,omitempty"`
+}
+
+// ExternalAccount is an account at another bank registered for transfers
+type ExternalAccount struct {
+	ID                string     `json:"id"`
+	UserID            string     `json:"user_id,omitempty"`
+	AccountHolderName string     `json:"account_holder_name"`
+	AccountNumber     string     `json:"account_number"`
+	RoutingNumber     string     `json:"routing_number"`
+	InstitutionName   string     `json:"institution_name,omitempty"`
+	Validated         bool       `json:"validated"`
+	ValidationTime    *time.Time `json:"validation_time,omitempty"`
+	NameMatch         string     `json:"name_match,omitempty"`
+	// ValidatedHolderName is NorthWind's name for the account when it differs from the typed one
+	ValidatedHolderName string    `json:"validated_holder_name,omitempty"`
+	NeedsReview         bool      `json:"needs_review"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+// AccountValidation is NorthWind's verdict on an external account
+type AccountValidation struct {
+	Valid             bool   `json:"valid"`
+	AccountNumber     string `json:"account_number,omitempty"`
+	RoutingNumber     string `json:"routing_number,omitempty"`
+	AccountHolderName string `json:"account_holder_name,omitempty"`
+	InstitutionName   string `json:"institution_name,omitempty"`
+	AccountType       string `json:"account_type,omitempty"`
+	Message           string `json:"message,omitempty"`
+}